@@ -0,0 +1,134 @@
+package accounts
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrAccountNotFound is returned if an account with the given ID
+	// doesn't exist.
+	ErrAccountNotFound = errors.New("account not found")
+
+	// ErrInsufficientBalance is returned if the balance of an account
+	// doesn't cover a payment or debit.
+	ErrInsufficientBalance = errors.New("insufficient account balance")
+
+	// ErrUnknownHash is returned if a payment hash doesn't belong to an
+	// invoice or payment of any account.
+	ErrUnknownHash = errors.New("payment hash doesn't belong to an " +
+		"account")
+
+	// ErrHashInUse is returned if a payment hash already belongs to an
+	// invoice or payment of an account.
+	ErrHashInUse = errors.New("payment hash already belongs to an " +
+		"account")
+)
+
+// AccountID is the unique identifier of an account.
+type AccountID [8]byte
+
+// NewAccountID returns a new random account ID.
+func NewAccountID() (AccountID, error) {
+	var id AccountID
+	if _, err := rand.Read(id[:]); err != nil {
+		return id, err
+	}
+
+	return id, nil
+}
+
+// ParseAccountID parses the hex encoded account ID.
+func ParseAccountID(s string) (AccountID, error) {
+	var id AccountID
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return id, fmt.Errorf("invalid account ID: %w", err)
+	}
+	if len(b) != len(id) {
+		return id, fmt.Errorf("invalid account ID length: %d", len(b))
+	}
+	copy(id[:], b)
+
+	return id, nil
+}
+
+// String returns the hex encoding of the account ID.
+func (a AccountID) String() string {
+	return hex.EncodeToString(a[:])
+}
+
+// Account is an off-chain balance bucket of a node that is shared by several
+// applications. An application that uses a macaroon bound to an account can
+// only spend the balance of its account, and receives the amounts paid to
+// its invoices into it.
+type Account struct {
+	// ID is the unique identifier of the account.
+	ID AccountID
+
+	// Label is a human readable description of the account.
+	Label string
+
+	// Balance is the current balance of the account.
+	Balance lnwire.MilliSatoshi
+
+	// CreatedAt is the time the account was created at.
+	CreatedAt time.Time
+}
+
+// EntryKind is the kind of an entry in the ledger of an account.
+type EntryKind uint8
+
+const (
+	// EntryAdjustment is a manual change of the balance by the node
+	// operator, which includes the initial balance of an account.
+	EntryAdjustment EntryKind = 0
+
+	// EntryInvoice is an invoice of the account that was paid.
+	EntryInvoice EntryKind = 1
+
+	// EntryPayment is a payment of the account that succeeded, including
+	// its routing fees.
+	EntryPayment EntryKind = 2
+)
+
+// String returns a human readable version of the entry kind.
+func (k EntryKind) String() string {
+	switch k {
+	case EntryAdjustment:
+		return "adjustment"
+
+	case EntryInvoice:
+		return "invoice"
+
+	case EntryPayment:
+		return "payment"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(k))
+	}
+}
+
+// Entry is a change of the balance of an account.
+type Entry struct {
+	// Kind is the kind of the balance change.
+	Kind EntryKind
+
+	// Timestamp is the time the balance changed.
+	Timestamp time.Time
+
+	// Amount is the change of the balance in millisatoshis, which is
+	// negative for debits.
+	Amount int64
+
+	// Hash is the payment hash of the invoice or payment that changed the
+	// balance. It is empty for adjustments.
+	Hash lntypes.Hash
+}
@@ -0,0 +1,599 @@
+package accounts
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"gopkg.in/macaroon.v2"
+)
+
+const (
+	// CaveatName is the name of the custom macaroon caveat that binds a
+	// macaroon to an account. Its condition is the hex encoded account
+	// ID.
+	CaveatName = "account"
+
+	// reservationTimeout is the time after which the balance reserved for
+	// a payment is released if the payment wasn't started by then, for
+	// example because the payment request was rejected.
+	reservationTimeout = time.Minute
+)
+
+var (
+	// ErrMethodNotAllowed is returned if a macaroon that is bound to an
+	// account is used to call a method that isn't account aware.
+	ErrMethodNotAllowed = errors.New("method not allowed for account " +
+		"macaroons")
+
+	// allowedMethods are the methods that can be called with a macaroon
+	// that is bound to an account, as they either don't reveal data of
+	// other accounts or are accounted for by the service.
+	allowedMethods = map[string]struct{}{
+		"/lnrpc.Lightning/GetInfo":         {},
+		"/lnrpc.Lightning/DecodePayReq":    {},
+		"/lnrpc.Lightning/AddInvoice":      {},
+		"/lnrpc.Lightning/SendPaymentSync": {},
+		"/routerrpc.Router/SendPaymentV2":  {},
+	}
+)
+
+// AllowedMethods returns the methods that can be called with a macaroon that
+// is bound to an account.
+func AllowedMethods() []string {
+	methods := make([]string, 0, len(allowedMethods))
+	for method := range allowedMethods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	return methods
+}
+
+// Constraint returns a macaroon constraint that binds a macaroon to the
+// account.
+func Constraint(id AccountID) func(*macaroon.Macaroon) error {
+	return macaroons.CustomConstraint(CaveatName, id.String())
+}
+
+// InvoiceSubscriber subscribes to the invoices of the node.
+type InvoiceSubscriber interface {
+	// SubscribeNotifications returns an invoice subscription that starts
+	// after the given add and settle indexes.
+	SubscribeNotifications(ctx context.Context, addIndex,
+		settleIndex uint64) (*invoices.InvoiceSubscription, error)
+}
+
+// Config holds the dependencies of the account service.
+type Config struct {
+	// Store persists the accounts.
+	Store *Store
+
+	// Invoices notifies the service about settled invoices.
+	Invoices InvoiceSubscriber
+
+	// Payments notifies the service about the state of payments.
+	Payments routing.ControlTower
+
+	// ChainParams are the parameters of the chain that payment requests
+	// are decoded for.
+	ChainParams *chaincfg.Params
+
+	// Clock is the clock of the reservation timeouts.
+	Clock clock.Clock
+}
+
+// reservation is the balance of an account that is held back for a payment
+// until the payment is resolved.
+type reservation struct {
+	account   AccountID
+	amount    lnwire.MilliSatoshi
+	createdAt time.Time
+	inFlight  bool
+}
+
+// Service enforces the balances of the accounts. It validates requests made
+// with macaroons that are bound to an account, and keeps the balances in
+// line with the invoices and payments of the accounts.
+type Service struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// reservations are the balances held back for the pending payments of
+	// the accounts, keyed by the payment hash.
+	reservations map[lntypes.Hash]*reservation
+	mu           sync.Mutex
+
+	cancel func()
+	wg     sync.WaitGroup
+}
+
+// NewService returns a new account service.
+func NewService(cfg *Config) *Service {
+	return &Service{
+		cfg:          cfg,
+		reservations: make(map[lntypes.Hash]*reservation),
+	}
+}
+
+// Start subscribes to the invoices and payments of the node.
+func (s *Service) Start() error {
+	var startErr error
+	s.started.Do(func() {
+		log.Info("Account service starting")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		s.cancel = cancel
+
+		invoiceSub, err := s.cfg.Invoices.SubscribeNotifications(
+			ctx, 0, 0,
+		)
+		if err != nil {
+			startErr = err
+			return
+		}
+
+		paymentSub, err := s.cfg.Payments.SubscribeAllPayments()
+		if err != nil {
+			invoiceSub.Cancel()
+			startErr = err
+
+			return
+		}
+
+		s.wg.Add(2)
+		go s.handleInvoices(ctx, invoiceSub)
+		go s.handlePayments(ctx, paymentSub)
+	})
+
+	return startErr
+}
+
+// Stop stops the account service.
+func (s *Service) Stop() error {
+	s.stopped.Do(func() {
+		log.Info("Account service shutting down...")
+		defer log.Debug("Account service shutdown complete")
+
+		if s.cancel != nil {
+			s.cancel()
+		}
+		s.wg.Wait()
+	})
+
+	return nil
+}
+
+// NewAccount creates an account with the given label and initial balance.
+func (s *Service) NewAccount(label string,
+	balance lnwire.MilliSatoshi) (*Account, error) {
+
+	return s.cfg.Store.NewAccount(label, balance)
+}
+
+// Account returns the account with the given ID.
+func (s *Service) Account(id AccountID) (*Account, error) {
+	return s.cfg.Store.Account(id)
+}
+
+// Accounts returns all accounts.
+func (s *Service) Accounts() ([]*Account, error) {
+	return s.cfg.Store.Accounts()
+}
+
+// AdjustBalance changes the balance of the account by the given amount in
+// millisatoshis, which is negative for debits.
+func (s *Service) AdjustBalance(id AccountID, amount int64) error {
+	return s.cfg.Store.AdjustBalance(id, amount)
+}
+
+// Ledger returns the balance changes of the account in order.
+func (s *Service) Ledger(id AccountID) ([]*Entry, error) {
+	return s.cfg.Store.Ledger(id)
+}
+
+// RemoveAccount removes the account. Macaroons that are bound to the account
+// are rejected afterwards.
+func (s *Service) RemoveAccount(id AccountID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for hash, r := range s.reservations {
+		if r.account == id {
+			delete(s.reservations, hash)
+		}
+	}
+
+	return s.cfg.Store.RemoveAccount(id)
+}
+
+// ValidateCaveat validates a request that is made with a macaroon that is
+// bound to the account in the condition.
+//
+// NOTE: This is part of the rpcperms.CaveatValidator interface.
+func (s *Service) ValidateCaveat(_ context.Context, fullMethod,
+	condition string, req interface{}) error {
+
+	if _, ok := allowedMethods[fullMethod]; !ok {
+		return ErrMethodNotAllowed
+	}
+
+	id, err := ParseAccountID(condition)
+	if err != nil {
+		return err
+	}
+
+	switch r := req.(type) {
+	case *lnrpc.Invoice:
+		return s.addInvoice(id, r)
+
+	case *lnrpc.SendRequest:
+		amt, err := lnrpc.UnmarshallAmt(r.Amt, r.AmtMsat)
+		if err != nil {
+			return err
+		}
+
+		return s.reservePayment(
+			id, r.PaymentRequest, r.PaymentHash,
+			r.DestCustomRecords, amt, func(
+				amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+				return lnrpc.CalculateFeeLimit(r.FeeLimit, amt)
+			},
+		)
+
+	case *routerrpc.SendPaymentRequest:
+		if r.Amp {
+			return errors.New("AMP payments are not supported " +
+				"for accounts")
+		}
+
+		amt, err := lnrpc.UnmarshallAmt(r.Amt, r.AmtMsat)
+		if err != nil {
+			return err
+		}
+		feeLimit, err := lnrpc.UnmarshallAmt(
+			r.FeeLimitSat, r.FeeLimitMsat,
+		)
+		if err != nil {
+			return err
+		}
+
+		return s.reservePayment(
+			id, r.PaymentRequest, r.PaymentHash,
+			r.DestCustomRecords, amt,
+			func(lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+				return feeLimit
+			},
+		)
+
+	default:
+		// Requests of read-only methods, and stream establishments
+		// without a request, only require the account to exist.
+		_, err := s.cfg.Store.Account(id)
+
+		return err
+	}
+}
+
+// ReleaseCaveat releases the balance reserved for a payment request that was
+// rejected after it was validated, before its payment was started.
+//
+// NOTE: This is part of the rpcperms.CaveatReleaser interface.
+func (s *Service) ReleaseCaveat(_ context.Context, _, condition string,
+	req interface{}) {
+
+	id, err := ParseAccountID(condition)
+	if err != nil {
+		return
+	}
+
+	var hash lntypes.Hash
+	switch r := req.(type) {
+	case *lnrpc.SendRequest:
+		hash, _, err = s.decodePayment(
+			r.PaymentRequest, r.PaymentHash, r.DestCustomRecords, 0,
+		)
+
+	case *routerrpc.SendPaymentRequest:
+		hash, _, err = s.decodePayment(
+			r.PaymentRequest, r.PaymentHash, r.DestCustomRecords, 0,
+		)
+
+	default:
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.reservations[hash]
+	if ok && r.account == id && !r.inFlight {
+		delete(s.reservations, hash)
+	}
+}
+
+// addInvoice binds the invoice of the request to the account. A preimage is
+// chosen for the invoice if none was requested, so that its payment hash is
+// known before the invoice is created.
+func (s *Service) addInvoice(id AccountID, req *lnrpc.Invoice) error {
+	if req.IsAmp {
+		return errors.New("AMP invoices are not supported for " +
+			"accounts")
+	}
+
+	if len(req.RPreimage) == 0 {
+		var preimage lntypes.Preimage
+		if _, err := rand.Read(preimage[:]); err != nil {
+			return err
+		}
+		req.RPreimage = preimage[:]
+	}
+
+	preimage, err := lntypes.MakePreimage(req.RPreimage)
+	if err != nil {
+		return err
+	}
+
+	return s.cfg.Store.AddInvoice(id, preimage.Hash())
+}
+
+// reservePayment holds back the amount and the fee limit of a payment from
+// the balance of the account, and binds the payment to the account.
+func (s *Service) reservePayment(id AccountID, payReq string,
+	paymentHash []byte, customRecords map[uint64][]byte,
+	amt lnwire.MilliSatoshi,
+	feeLimit func(lnwire.MilliSatoshi) lnwire.MilliSatoshi) error {
+
+	hash, amt, err := s.decodePayment(
+		payReq, paymentHash, customRecords, amt,
+	)
+	if err != nil {
+		return err
+	}
+
+	total := amt + feeLimit(amt)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.reservations[hash]; ok && !s.expired(existing) {
+		return fmt.Errorf("payment %v is already pending", hash)
+	}
+
+	// The balance is read while holding the mutex, so that it's in line
+	// with the reservations of the payments that were resolved meanwhile.
+	account, err := s.cfg.Store.Account(id)
+	if err != nil {
+		return err
+	}
+
+	reserved := s.reserved(id)
+	if reserved+total > account.Balance {
+		return fmt.Errorf("%w: payment of %v exceeds balance of %v "+
+			"with %v reserved", ErrInsufficientBalance, total,
+			account.Balance, reserved)
+	}
+
+	// The payment hash may already be bound to the account if the payment
+	// is retried, but it must not be bound to another account.
+	err = s.cfg.Store.AddPayment(id, hash)
+	if errors.Is(err, ErrHashInUse) {
+		var owner AccountID
+		owner, err = s.cfg.Store.PaymentAccount(hash)
+		if err == nil && owner != id {
+			err = fmt.Errorf("%w: payment %v is bound to another "+
+				"account", ErrHashInUse, hash)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	s.reservations[hash] = &reservation{
+		account:   id,
+		amount:    total,
+		createdAt: s.cfg.Clock.Now(),
+	}
+
+	return nil
+}
+
+// decodePayment returns the payment hash and the amount of a payment, which
+// is taken from the payment request if it has one.
+func (s *Service) decodePayment(payReq string, paymentHash []byte,
+	customRecords map[uint64][]byte,
+	amt lnwire.MilliSatoshi) (lntypes.Hash, lnwire.MilliSatoshi, error) {
+
+	switch {
+	case payReq != "":
+		invoice, err := zpay32.Decode(payReq, s.cfg.ChainParams)
+		if err != nil {
+			return lntypes.Hash{}, 0, err
+		}
+		if invoice.PaymentHash == nil {
+			return lntypes.Hash{}, 0, errors.New("payment " +
+				"request has no payment hash")
+		}
+
+		if invoice.MilliSat != nil {
+			amt = *invoice.MilliSat
+		}
+
+		return *invoice.PaymentHash, amt, nil
+
+	case len(paymentHash) > 0:
+		hash, err := lntypes.MakeHash(paymentHash)
+		if err != nil {
+			return lntypes.Hash{}, 0, err
+		}
+
+		return hash, amt, nil
+
+	case len(customRecords[record.KeySendType]) > 0:
+		return sha256.Sum256(customRecords[record.KeySendType]), amt,
+			nil
+
+	default:
+		return lntypes.Hash{}, 0, errors.New("payments of accounts " +
+			"require a payment request or payment hash")
+	}
+}
+
+// reserved returns the total balance of the account that is held back for
+// pending payments. Expired reservations are released.
+//
+// NOTE: The mutex must be held when calling this method.
+func (s *Service) reserved(id AccountID) lnwire.MilliSatoshi {
+	var total lnwire.MilliSatoshi
+	for hash, r := range s.reservations {
+		if s.expired(r) {
+			delete(s.reservations, hash)
+			continue
+		}
+
+		if r.account == id {
+			total += r.amount
+		}
+	}
+
+	return total
+}
+
+// expired returns true if the payment of the reservation wasn't started in
+// time.
+func (s *Service) expired(r *reservation) bool {
+	return !r.inFlight &&
+		s.cfg.Clock.Now().Sub(r.createdAt) > reservationTimeout
+}
+
+// handleInvoices credits settled invoices to their accounts.
+//
+// NOTE: This must be run as a goroutine.
+func (s *Service) handleInvoices(ctx context.Context,
+	sub *invoices.InvoiceSubscription) {
+
+	defer s.wg.Done()
+	defer sub.Cancel()
+
+	for {
+		select {
+		case invoice := <-sub.SettledInvoices:
+			preimage := invoice.Terms.PaymentPreimage
+			if preimage == nil {
+				continue
+			}
+
+			id, err := s.cfg.Store.SettleInvoice(
+				preimage.Hash(), invoice.AmtPaid,
+			)
+			switch {
+			case errors.Is(err, ErrUnknownHash):
+
+			case err != nil:
+				log.Errorf("Unable to credit invoice %v: %v",
+					preimage.Hash(), err)
+
+			default:
+				log.Debugf("Credited %v to account %v",
+					invoice.AmtPaid, id)
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handlePayments debits succeeded payments from their accounts and releases
+// the reserved balances of resolved payments.
+//
+// NOTE: This must be run as a goroutine.
+func (s *Service) handlePayments(ctx context.Context,
+	sub routing.ControlTowerSubscriber) {
+
+	defer s.wg.Done()
+	defer sub.Close()
+
+	for {
+		select {
+		case item, ok := <-sub.Updates():
+			if !ok {
+				log.Errorf("Payment subscription terminated")
+				return
+			}
+
+			payment, ok := item.(*channeldb.MPPayment)
+			if !ok {
+				continue
+			}
+
+			s.handlePayment(payment)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handlePayment applies the state of a payment to its account.
+func (s *Service) handlePayment(payment *channeldb.MPPayment) {
+	hash := payment.Info.PaymentIdentifier
+
+	switch payment.Status {
+	case channeldb.StatusInitiated, channeldb.StatusInFlight:
+		s.mu.Lock()
+		if r, ok := s.reservations[hash]; ok {
+			r.inFlight = true
+		}
+		s.mu.Unlock()
+
+	case channeldb.StatusSucceeded:
+		sent, fees := payment.SentAmt()
+		id, err := s.cfg.Store.SettlePayment(hash, sent+fees)
+		switch {
+		case errors.Is(err, ErrUnknownHash):
+
+		case err != nil:
+			log.Errorf("Unable to debit payment %v: %v", hash, err)
+
+		default:
+			log.Debugf("Debited %v from account %v", sent+fees, id)
+		}
+
+		s.release(hash)
+
+	case channeldb.StatusFailed:
+		s.release(hash)
+	}
+}
+
+// release releases the balance reserved for the payment.
+func (s *Service) release(hash lntypes.Hash) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reservations, hash)
+}
@@ -0,0 +1,100 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	getInfoMethod     = "/lnrpc.Lightning/GetInfo"
+	addInvoiceMethod  = "/lnrpc.Lightning/AddInvoice"
+	sendPaymentMethod = "/routerrpc.Router/SendPaymentV2"
+)
+
+// TestValidateCaveat tests that requests made with a macaroon that is bound
+// to an account are limited by the balance of the account.
+func TestValidateCaveat(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Unix(1000, 0))
+	store := newTestStore(t, testClock)
+
+	s := NewService(&Config{
+		Store:       store,
+		ChainParams: &chaincfg.RegressionNetParams,
+		Clock:       testClock,
+	})
+
+	account, err := store.NewAccount("app", 10_000)
+	require.NoError(t, err)
+	condition := account.ID.String()
+
+	ctx := context.Background()
+	validate := func(method string, req interface{}) error {
+		return s.ValidateCaveat(ctx, method, condition, req)
+	}
+
+	require.NoError(t, validate(getInfoMethod, nil))
+	require.ErrorIs(
+		t, validate("/lnrpc.Lightning/ListInvoices", nil),
+		ErrMethodNotAllowed,
+	)
+
+	// Invoices get a preimage, so that they can be bound to the account
+	// before they are created.
+	invoice := &lnrpc.Invoice{Value: 1}
+	require.NoError(t, validate(addInvoiceMethod, invoice))
+	require.Len(t, invoice.RPreimage, lntypes.PreimageSize)
+
+	// A payment reserves its amount and fee limit.
+	pay := func(hash byte, amt int64) error {
+		req := &routerrpc.SendPaymentRequest{
+			PaymentHash:  []byte{31: hash},
+			AmtMsat:      amt,
+			FeeLimitMsat: 100,
+		}
+
+		return validate(sendPaymentMethod, req)
+	}
+	require.NoError(t, pay(1, 6_000))
+	require.ErrorIs(t, pay(2, 6_000), ErrInsufficientBalance)
+
+	// Once the first payment failed, its reservation is released.
+	s.release(lntypes.Hash{31: 1})
+	require.NoError(t, pay(2, 6_000))
+
+	// Reservations of payments that weren't started expire.
+	testClock.SetTime(testClock.Now().Add(2 * reservationTimeout))
+	require.NoError(t, pay(3, 6_000))
+
+	// A payment that is rejected after it was validated is released.
+	require.NoError(t, pay(4, 3_000))
+	require.ErrorIs(t, pay(5, 3_000), ErrInsufficientBalance)
+	s.ReleaseCaveat(ctx, sendPaymentMethod, condition,
+		&routerrpc.SendPaymentRequest{PaymentHash: []byte{31: 4}})
+	require.NoError(t, pay(5, 3_000))
+
+	// The payment hashes of an account can't be used by another account.
+	other, err := store.NewAccount("other", 10_000)
+	require.NoError(t, err)
+	err = s.ValidateCaveat(
+		ctx, sendPaymentMethod, other.ID.String(),
+		&routerrpc.SendPaymentRequest{
+			PaymentHash: []byte{31: 2},
+			AmtMsat:     1_000,
+		},
+	)
+	require.ErrorIs(t, err, ErrHashInUse)
+
+	// Macaroons of removed accounts are rejected.
+	require.NoError(t, s.RemoveAccount(account.ID))
+	require.ErrorIs(t, validate(getInfoMethod, nil), ErrAccountNotFound)
+}
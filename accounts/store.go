@@ -0,0 +1,590 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// accountsBucket is the top level bucket of the accounts. It holds a
+	// sub bucket per account, keyed by the account ID, and the hash
+	// indexes.
+	//
+	// accounts
+	//   |-- <account-id>
+	//   |     |-- info: <account>
+	//   |     |-- ledger
+	//   |           |-- <seq>: <entry>
+	//   |-- invoice-index
+	//   |     |-- <hash>: <account-id><settled>
+	//   |-- payment-index
+	//         |-- <hash>: <account-id><settled>
+	accountsBucket = []byte("accounts")
+
+	// accountInfoKey is the key of the account info within the bucket of
+	// an account.
+	accountInfoKey = []byte("info")
+
+	// ledgerBucket is the bucket within the bucket of an account that
+	// holds its ledger entries in order.
+	ledgerBucket = []byte("ledger")
+
+	// invoiceIndexBucket maps the payment hashes of the invoices of all
+	// accounts to their account.
+	invoiceIndexBucket = []byte("invoice-index")
+
+	// paymentIndexBucket maps the payment hashes of the payments of all
+	// accounts to their account.
+	paymentIndexBucket = []byte("payment-index")
+
+	// byteOrder is the byte order of the serialized accounts.
+	byteOrder = binary.BigEndian
+)
+
+// Store persists the accounts, the hashes of their invoices and payments and
+// their ledgers.
+type Store struct {
+	db    kvdb.Backend
+	clock clock.Clock
+}
+
+// NewStore returns a new account store that is backed by the database.
+func NewStore(db kvdb.Backend, clock clock.Clock) (*Store, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		accounts, err := tx.CreateTopLevelBucket(accountsBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = accounts.CreateBucketIfNotExists(invoiceIndexBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = accounts.CreateBucketIfNotExists(paymentIndexBucket)
+
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		db:    db,
+		clock: clock,
+	}, nil
+}
+
+// NewAccount creates an account with the given label and initial balance.
+func (s *Store) NewAccount(label string,
+	balance lnwire.MilliSatoshi) (*Account, error) {
+
+	if len(label) > math.MaxUint16 {
+		return nil, errors.New("account label too long")
+	}
+
+	id, err := NewAccountID()
+	if err != nil {
+		return nil, err
+	}
+
+	account := &Account{
+		ID:        id,
+		Label:     label,
+		Balance:   balance,
+		CreatedAt: s.clock.Now(),
+	}
+
+	err = kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		accounts := tx.ReadWriteBucket(accountsBucket)
+		if accounts.NestedReadWriteBucket(id[:]) != nil {
+			return fmt.Errorf("account %v already exists", id)
+		}
+
+		bucket, err := accounts.CreateBucket(id[:])
+		if err != nil {
+			return err
+		}
+		if _, err := bucket.CreateBucket(ledgerBucket); err != nil {
+			return err
+		}
+
+		if err := putAccount(bucket, account); err != nil {
+			return err
+		}
+
+		return addEntry(bucket, &Entry{
+			Kind:      EntryAdjustment,
+			Timestamp: account.CreatedAt,
+			Amount:    int64(balance),
+		})
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// Account returns the account with the given ID.
+func (s *Store) Account(id AccountID) (*Account, error) {
+	var account *Account
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(accountsBucket).NestedReadBucket(id[:])
+		if bucket == nil {
+			return ErrAccountNotFound
+		}
+
+		var err error
+		account, err = getAccount(id, bucket)
+
+		return err
+	}, func() {
+		account = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// Accounts returns all accounts.
+func (s *Store) Accounts() ([]*Account, error) {
+	var accounts []*Account
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(accountsBucket)
+
+		return bucket.ForEach(func(k, v []byte) error {
+			// Skip the hash indexes and any values.
+			if v != nil || len(k) != len(AccountID{}) {
+				return nil
+			}
+
+			var id AccountID
+			copy(id[:], k)
+
+			account, err := getAccount(
+				id, bucket.NestedReadBucket(k),
+			)
+			if err != nil {
+				return err
+			}
+			accounts = append(accounts, account)
+
+			return nil
+		})
+	}, func() {
+		accounts = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+// RemoveAccount removes the account along with its ledger. The invoices and
+// payments of the account are no longer accounted for afterwards.
+func (s *Store) RemoveAccount(id AccountID) error {
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		accounts := tx.ReadWriteBucket(accountsBucket)
+		if accounts.NestedReadWriteBucket(id[:]) == nil {
+			return ErrAccountNotFound
+		}
+
+		for _, indexKey := range [][]byte{
+			invoiceIndexBucket, paymentIndexBucket,
+		} {
+			err := removeIndexEntries(
+				accounts.NestedReadWriteBucket(indexKey), id,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return accounts.DeleteNestedBucket(id[:])
+	}, func() {})
+}
+
+// AdjustBalance changes the balance of the account by the given amount in
+// millisatoshis, which is negative for debits. ErrInsufficientBalance is
+// returned if the balance would become negative.
+func (s *Store) AdjustBalance(id AccountID, amount int64) error {
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		accounts := tx.ReadWriteBucket(accountsBucket)
+
+		return s.changeBalance(accounts, id, &Entry{
+			Kind:      EntryAdjustment,
+			Timestamp: s.clock.Now(),
+			Amount:    amount,
+		}, false)
+	}, func() {})
+}
+
+// AddInvoice binds the invoice with the given payment hash to the account,
+// which receives the paid amount once the invoice is settled.
+func (s *Store) AddInvoice(id AccountID, hash lntypes.Hash) error {
+	return s.addHash(invoiceIndexBucket, id, hash)
+}
+
+// AddPayment binds the payment with the given payment hash to the account,
+// which is debited once the payment succeeded.
+func (s *Store) AddPayment(id AccountID, hash lntypes.Hash) error {
+	return s.addHash(paymentIndexBucket, id, hash)
+}
+
+// PaymentAccount returns the account that the payment with the given payment
+// hash is bound to. ErrUnknownHash is returned if the payment doesn't belong
+// to an account.
+func (s *Store) PaymentAccount(hash lntypes.Hash) (AccountID, error) {
+	var id AccountID
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		index := tx.ReadBucket(accountsBucket).NestedReadBucket(
+			paymentIndexBucket,
+		)
+
+		value := index.Get(hash[:])
+		if len(value) != len(id)+1 {
+			return ErrUnknownHash
+		}
+		copy(id[:], value)
+
+		return nil
+	}, func() {
+		id = AccountID{}
+	})
+	if err != nil {
+		return AccountID{}, err
+	}
+
+	return id, nil
+}
+
+// SettleInvoice credits the paid amount of the invoice with the given payment
+// hash to its account. ErrUnknownHash is returned if the invoice doesn't
+// belong to an account. An invoice is only credited once.
+func (s *Store) SettleInvoice(hash lntypes.Hash,
+	amtPaid lnwire.MilliSatoshi) (AccountID, error) {
+
+	return s.settleHash(invoiceIndexBucket, hash, &Entry{
+		Kind:   EntryInvoice,
+		Amount: int64(amtPaid),
+	})
+}
+
+// SettlePayment debits the sent amount including fees of the payment with
+// the given payment hash from its account. ErrUnknownHash is returned if the
+// payment doesn't belong to an account. A payment is only debited once. As
+// the payment already succeeded, the balance is debited even if it doesn't
+// cover the payment, in which case it's set to zero.
+func (s *Store) SettlePayment(hash lntypes.Hash,
+	amtSent lnwire.MilliSatoshi) (AccountID, error) {
+
+	return s.settleHash(paymentIndexBucket, hash, &Entry{
+		Kind:   EntryPayment,
+		Amount: -int64(amtSent),
+	})
+}
+
+// Ledger returns the balance changes of the account in order.
+func (s *Store) Ledger(id AccountID) ([]*Entry, error) {
+	var entries []*Entry
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(accountsBucket).NestedReadBucket(id[:])
+		if bucket == nil {
+			return ErrAccountNotFound
+		}
+
+		ledger := bucket.NestedReadBucket(ledgerBucket)
+
+		return ledger.ForEach(func(_, v []byte) error {
+			entry, err := deserializeEntry(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+
+			return nil
+		})
+	}, func() {
+		entries = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// addHash binds the payment hash to the account in the given index.
+func (s *Store) addHash(indexKey []byte, id AccountID,
+	hash lntypes.Hash) error {
+
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		accounts := tx.ReadWriteBucket(accountsBucket)
+		if accounts.NestedReadWriteBucket(id[:]) == nil {
+			return ErrAccountNotFound
+		}
+
+		index := accounts.NestedReadWriteBucket(indexKey)
+		if index.Get(hash[:]) != nil {
+			return ErrHashInUse
+		}
+
+		value := make([]byte, len(id)+1)
+		copy(value, id[:])
+
+		return index.Put(hash[:], value)
+	}, func() {})
+}
+
+// settleHash applies the ledger entry to the account that the payment hash
+// is bound to in the given index, unless it was already applied before.
+func (s *Store) settleHash(indexKey []byte, hash lntypes.Hash,
+	entry *Entry) (AccountID, error) {
+
+	var id AccountID
+	err := kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		accounts := tx.ReadWriteBucket(accountsBucket)
+		index := accounts.NestedReadWriteBucket(indexKey)
+
+		value := index.Get(hash[:])
+		if len(value) != len(id)+1 {
+			return ErrUnknownHash
+		}
+		copy(id[:], value)
+
+		// The hash was settled before, so there's nothing left to do.
+		if value[len(id)] != 0 {
+			return nil
+		}
+
+		settled := make([]byte, len(value))
+		copy(settled, value)
+		settled[len(id)] = 1
+		if err := index.Put(hash[:], settled); err != nil {
+			return err
+		}
+
+		entry.Timestamp = s.clock.Now()
+		entry.Hash = hash
+
+		return s.changeBalance(accounts, id, entry, true)
+	}, func() {
+		id = AccountID{}
+	})
+	if err != nil {
+		return AccountID{}, err
+	}
+
+	return id, nil
+}
+
+// changeBalance applies the ledger entry to the balance of the account. If
+// the balance doesn't cover a debit, either ErrInsufficientBalance is
+// returned or the balance is set to zero if saturate is true.
+func (s *Store) changeBalance(accounts kvdb.RwBucket, id AccountID,
+	entry *Entry, saturate bool) error {
+
+	bucket := accounts.NestedReadWriteBucket(id[:])
+	if bucket == nil {
+		return ErrAccountNotFound
+	}
+
+	account, err := getAccount(id, bucket)
+	if err != nil {
+		return err
+	}
+
+	balance := int64(account.Balance) + entry.Amount
+	switch {
+	case balance < 0 && saturate:
+		log.Warnf("Balance of account %v doesn't cover debit of %v "+
+			"mSAT, setting it to zero", id, -entry.Amount)
+
+		balance = 0
+
+	case balance < 0:
+		return ErrInsufficientBalance
+	}
+	account.Balance = lnwire.MilliSatoshi(balance)
+
+	if err := putAccount(bucket, account); err != nil {
+		return err
+	}
+
+	return addEntry(bucket, entry)
+}
+
+// removeIndexEntries removes all payment hashes of the account from the hash
+// index.
+func removeIndexEntries(index kvdb.RwBucket, id AccountID) error {
+	var hashes [][]byte
+	err := index.ForEach(func(k, v []byte) error {
+		if bytes.HasPrefix(v, id[:]) {
+			hashes = append(hashes, k)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range hashes {
+		if err := index.Delete(hash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// putAccount stores the account info in the bucket of the account.
+func putAccount(bucket kvdb.RwBucket, account *Account) error {
+	var b bytes.Buffer
+	if err := serializeAccount(&b, account); err != nil {
+		return err
+	}
+
+	return bucket.Put(accountInfoKey, b.Bytes())
+}
+
+// getAccount reads the account info from the bucket of the account.
+func getAccount(id AccountID, bucket kvdb.RBucket) (*Account, error) {
+	info := bucket.Get(accountInfoKey)
+	if info == nil {
+		return nil, fmt.Errorf("account %v has no info", id)
+	}
+
+	account, err := deserializeAccount(bytes.NewReader(info))
+	if err != nil {
+		return nil, err
+	}
+	account.ID = id
+
+	return account, nil
+}
+
+// addEntry appends the entry to the ledger of the account.
+func addEntry(bucket kvdb.RwBucket, entry *Entry) error {
+	ledger := bucket.NestedReadWriteBucket(ledgerBucket)
+
+	seq, err := ledger.NextSequence()
+	if err != nil {
+		return err
+	}
+
+	var key [8]byte
+	byteOrder.PutUint64(key[:], seq)
+
+	var b bytes.Buffer
+	if err := serializeEntry(&b, entry); err != nil {
+		return err
+	}
+
+	return ledger.Put(key[:], b.Bytes())
+}
+
+// serializeAccount writes the account info, without its ID, to the writer.
+func serializeAccount(w io.Writer, account *Account) error {
+	err := binary.Write(w, byteOrder, uint16(len(account.Label)))
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte(account.Label)); err != nil {
+		return err
+	}
+
+	err = binary.Write(w, byteOrder, uint64(account.Balance))
+	if err != nil {
+		return err
+	}
+
+	return binary.Write(w, byteOrder, account.CreatedAt.UnixNano())
+}
+
+// deserializeAccount reads the account info, without its ID, from the reader.
+func deserializeAccount(r io.Reader) (*Account, error) {
+	var labelLen uint16
+	if err := binary.Read(r, byteOrder, &labelLen); err != nil {
+		return nil, err
+	}
+
+	label := make([]byte, labelLen)
+	if _, err := io.ReadFull(r, label); err != nil {
+		return nil, err
+	}
+
+	var (
+		balance   uint64
+		createdAt int64
+	)
+	if err := binary.Read(r, byteOrder, &balance); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, byteOrder, &createdAt); err != nil {
+		return nil, err
+	}
+
+	return &Account{
+		Label:     string(label),
+		Balance:   lnwire.MilliSatoshi(balance),
+		CreatedAt: time.Unix(0, createdAt),
+	}, nil
+}
+
+// serializeEntry writes the ledger entry to the writer.
+func serializeEntry(w io.Writer, entry *Entry) error {
+	if err := binary.Write(w, byteOrder, entry.Kind); err != nil {
+		return err
+	}
+
+	err := binary.Write(w, byteOrder, entry.Timestamp.UnixNano())
+	if err != nil {
+		return err
+	}
+
+	if err := binary.Write(w, byteOrder, entry.Amount); err != nil {
+		return err
+	}
+
+	_, err = w.Write(entry.Hash[:])
+
+	return err
+}
+
+// deserializeEntry reads a ledger entry from the reader.
+func deserializeEntry(r io.Reader) (*Entry, error) {
+	var (
+		entry     Entry
+		timestamp int64
+	)
+	if err := binary.Read(r, byteOrder, &entry.Kind); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, byteOrder, &timestamp); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, byteOrder, &entry.Amount); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(r, entry.Hash[:]); err != nil {
+		return nil, err
+	}
+	entry.Timestamp = time.Unix(0, timestamp)
+
+	return &entry, nil
+}
@@ -0,0 +1,117 @@
+package accounts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore creates a new account store for the tests.
+func newTestStore(t *testing.T, clock clock.Clock) *Store {
+	t.Helper()
+
+	db, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "accounts")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	store, err := NewStore(db, clock)
+	require.NoError(t, err)
+
+	return store
+}
+
+// TestStore tests that the store keeps the balances and ledgers of the
+// accounts in line with their invoices and payments.
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Unix(1000, 0))
+	store := newTestStore(t, testClock)
+
+	account, err := store.NewAccount("app", 10_000)
+	require.NoError(t, err)
+
+	other, err := store.NewAccount("other", 0)
+	require.NoError(t, err)
+
+	accounts, err := store.Accounts()
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+
+	// An invoice is credited once, and only to its own account.
+	invoiceHash := lntypes.Hash{1}
+	require.NoError(t, store.AddInvoice(account.ID, invoiceHash))
+	require.ErrorIs(
+		t, store.AddInvoice(other.ID, invoiceHash), ErrHashInUse,
+	)
+
+	id, err := store.SettleInvoice(invoiceHash, 5_000)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, id)
+
+	_, err = store.SettleInvoice(invoiceHash, 5_000)
+	require.NoError(t, err)
+
+	_, err = store.SettleInvoice(lntypes.Hash{2}, 5_000)
+	require.ErrorIs(t, err, ErrUnknownHash)
+
+	// A payment is debited once.
+	paymentHash := lntypes.Hash{3}
+	require.NoError(t, store.AddPayment(account.ID, paymentHash))
+
+	_, err = store.SettlePayment(paymentHash, 4_000)
+	require.NoError(t, err)
+	_, err = store.SettlePayment(paymentHash, 4_000)
+	require.NoError(t, err)
+
+	account, err = store.Account(account.ID)
+	require.NoError(t, err)
+	require.EqualValues(t, 11_000, account.Balance)
+	require.Equal(t, "app", account.Label)
+
+	// Manual debits can't exceed the balance.
+	require.ErrorIs(
+		t, store.AdjustBalance(account.ID, -12_000),
+		ErrInsufficientBalance,
+	)
+	require.NoError(t, store.AdjustBalance(account.ID, -1_000))
+
+	ledger, err := store.Ledger(account.ID)
+	require.NoError(t, err)
+	require.Equal(t, []*Entry{
+		{
+			Kind:      EntryAdjustment,
+			Timestamp: testClock.Now(),
+			Amount:    10_000,
+		},
+		{
+			Kind:      EntryInvoice,
+			Timestamp: testClock.Now(),
+			Amount:    5_000,
+			Hash:      invoiceHash,
+		},
+		{
+			Kind:      EntryPayment,
+			Timestamp: testClock.Now(),
+			Amount:    -4_000,
+			Hash:      paymentHash,
+		},
+		{
+			Kind:      EntryAdjustment,
+			Timestamp: testClock.Now(),
+			Amount:    -1_000,
+		},
+	}, ledger)
+
+	// Removing an account also removes its hashes.
+	require.NoError(t, store.RemoveAccount(account.ID))
+
+	_, err = store.Account(account.ID)
+	require.ErrorIs(t, err, ErrAccountNotFound)
+
+	require.NoError(t, store.AddInvoice(other.ID, invoiceHash))
+}
@@ -0,0 +1,256 @@
+package lnd
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+
+	"github.com/lightningnetwork/lnd/accounts"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+// errAccountsInactive is returned by the account RPCs if accounts aren't
+// active.
+var errAccountsInactive = status.Error(
+	codes.Unavailable, "accounts are not active, set accounts.active to "+
+		"enable them",
+)
+
+// accountRPCError maps the errors of the account service to gRPC status
+// errors.
+func accountRPCError(err error) error {
+	switch {
+	case errors.Is(err, accounts.ErrAccountNotFound):
+		return status.Error(codes.NotFound, err.Error())
+
+	case errors.Is(err, accounts.ErrInsufficientBalance):
+		return status.Error(codes.FailedPrecondition, err.Error())
+
+	default:
+		return err
+	}
+}
+
+// marshallAccount converts an account into its RPC representation.
+func marshallAccount(account *accounts.Account) *lnrpc.Account {
+	return &lnrpc.Account{
+		Id:          account.ID.String(),
+		Label:       account.Label,
+		BalanceMsat: uint64(account.Balance),
+		CreatedAt:   account.CreatedAt.Unix(),
+	}
+}
+
+// parseAccountID parses the account ID of a request.
+func parseAccountID(id string) (accounts.AccountID, error) {
+	accountID, err := accounts.ParseAccountID(id)
+	if err != nil {
+		return accountID, status.Error(
+			codes.InvalidArgument, err.Error(),
+		)
+	}
+
+	return accountID, nil
+}
+
+// bakeAccountMacaroon returns a hex encoded macaroon that is bound to the
+// account and grants the permissions of the methods that are accounted for.
+func (r *rpcServer) bakeAccountMacaroon(ctx context.Context,
+	id accounts.AccountID) (string, error) {
+
+	if r.macService == nil {
+		return "", errMacaroonDisabled
+	}
+
+	var (
+		permissions   = r.interceptorChain.Permissions()
+		seen          = make(map[bakery.Op]struct{})
+		requiredPerms []bakery.Op
+	)
+	for _, method := range accounts.AllowedMethods() {
+		for _, op := range permissions[method] {
+			if _, ok := seen[op]; ok {
+				continue
+			}
+			seen[op] = struct{}{}
+
+			requiredPerms = append(requiredPerms, op)
+		}
+	}
+
+	newMac, err := r.macService.NewMacaroon(
+		ctx, macaroons.DefaultRootKeyID, requiredPerms...,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	mac, err := macaroons.AddConstraints(
+		newMac.M(), accounts.Constraint(id),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	macBytes, err := mac.MarshalBinary()
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(macBytes), nil
+}
+
+// CreateAccount creates an off-chain account with the given initial balance
+// and returns a macaroon that is bound to it.
+func (r *rpcServer) CreateAccount(ctx context.Context,
+	req *lnrpc.CreateAccountRequest) (*lnrpc.CreateAccountResponse,
+	error) {
+
+	if r.server.accounts == nil {
+		return nil, errAccountsInactive
+	}
+
+	account, err := r.server.accounts.NewAccount(
+		req.Label, lnwire.MilliSatoshi(req.BalanceMsat),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// An account without a macaroon can't be used, so we remove it again
+	// if the macaroon can't be baked.
+	mac, err := r.bakeAccountMacaroon(ctx, account.ID)
+	if err != nil {
+		removeErr := r.server.accounts.RemoveAccount(account.ID)
+		if removeErr != nil {
+			rpcsLog.Errorf("Unable to remove account %v: %v",
+				account.ID, removeErr)
+		}
+
+		return nil, err
+	}
+
+	rpcsLog.Infof("Created account %v with a balance of %v", account.ID,
+		account.Balance)
+
+	return &lnrpc.CreateAccountResponse{
+		Account:  marshallAccount(account),
+		Macaroon: mac,
+	}, nil
+}
+
+// ListAccounts returns all off-chain accounts.
+func (r *rpcServer) ListAccounts(_ context.Context,
+	_ *lnrpc.ListAccountsRequest) (*lnrpc.ListAccountsResponse, error) {
+
+	if r.server.accounts == nil {
+		return nil, errAccountsInactive
+	}
+
+	allAccounts, err := r.server.accounts.Accounts()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListAccountsResponse{}
+	for _, account := range allAccounts {
+		resp.Accounts = append(resp.Accounts, marshallAccount(account))
+	}
+
+	return resp, nil
+}
+
+// AdjustAccountBalance credits or debits the balance of an account.
+func (r *rpcServer) AdjustAccountBalance(_ context.Context,
+	req *lnrpc.AdjustAccountBalanceRequest) (
+	*lnrpc.AdjustAccountBalanceResponse, error) {
+
+	if r.server.accounts == nil {
+		return nil, errAccountsInactive
+	}
+
+	id, err := parseAccountID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.server.accounts.AdjustBalance(id, req.AmountMsat)
+	if err != nil {
+		return nil, accountRPCError(err)
+	}
+
+	account, err := r.server.accounts.Account(id)
+	if err != nil {
+		return nil, accountRPCError(err)
+	}
+
+	rpcsLog.Infof("Adjusted balance of account %v by %v msat to %v", id,
+		req.AmountMsat, account.Balance)
+
+	return &lnrpc.AdjustAccountBalanceResponse{
+		Account: marshallAccount(account),
+	}, nil
+}
+
+// AccountLedger returns the balance changes of an account in order.
+func (r *rpcServer) AccountLedger(_ context.Context,
+	req *lnrpc.AccountLedgerRequest) (*lnrpc.AccountLedgerResponse, error) {
+
+	if r.server.accounts == nil {
+		return nil, errAccountsInactive
+	}
+
+	id, err := parseAccountID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.server.accounts.Ledger(id)
+	if err != nil {
+		return nil, accountRPCError(err)
+	}
+
+	resp := &lnrpc.AccountLedgerResponse{}
+	for _, entry := range entries {
+		rpcEntry := &lnrpc.AccountLedgerEntry{
+			Kind:       entry.Kind.String(),
+			Timestamp:  entry.Timestamp.Unix(),
+			AmountMsat: entry.Amount,
+		}
+		if entry.Kind != accounts.EntryAdjustment {
+			rpcEntry.PaymentHash = entry.Hash[:]
+		}
+
+		resp.Entries = append(resp.Entries, rpcEntry)
+	}
+
+	return resp, nil
+}
+
+// RemoveAccount removes an account. Macaroons that are bound to the account
+// are rejected afterwards.
+func (r *rpcServer) RemoveAccount(_ context.Context,
+	req *lnrpc.RemoveAccountRequest) (*lnrpc.RemoveAccountResponse, error) {
+
+	if r.server.accounts == nil {
+		return nil, errAccountsInactive
+	}
+
+	id, err := parseAccountID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.server.accounts.RemoveAccount(id); err != nil {
+		return nil, accountRPCError(err)
+	}
+
+	rpcsLog.Infof("Removed account %v", id)
+
+	return &lnrpc.RemoveAccountResponse{}, nil
+}
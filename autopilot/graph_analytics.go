@@ -0,0 +1,426 @@
+package autopilot
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/graph"
+)
+
+const (
+	// DefaultAnalyticsBatchDelay is the default delay between the first
+	// topology change that alters the structure of the graph and the
+	// recomputation of the analytics. Changes that arrive in the meantime
+	// are covered by the same recomputation.
+	DefaultAnalyticsBatchDelay = time.Minute
+)
+
+var (
+	// ErrAnalyticsNotReady is returned when the graph analytics are
+	// queried before they have been computed for the first time.
+	ErrAnalyticsNotReady = errors.New("graph analytics not yet computed")
+
+	// ErrUnknownNode is returned when analytics are requested for a node
+	// that isn't part of the analyzed graph.
+	ErrUnknownNode = errors.New("node not found in analyzed graph")
+)
+
+// Bridge is a channel whose removal would split the graph into two
+// disconnected components. If there are multiple channels between the same
+// pair of nodes, none of them is a bridge.
+type Bridge struct {
+	// Node1 is the first node of the bridge.
+	Node1 NodeID
+
+	// Node2 is the second node of the bridge.
+	Node2 NodeID
+}
+
+// GraphStats is a snapshot of the analytics computed over the channel graph.
+type GraphStats struct {
+	// Timestamp is the time at which the analytics were computed.
+	Timestamp time.Time
+
+	// NumNodes is the number of nodes with at least one channel.
+	NumNodes int
+
+	// NumChannels is the number of channels in the graph.
+	NumChannels int
+
+	// Diameter is the approximated diameter, in hops, of the component of
+	// the graph that contains the node with the highest degree.
+	Diameter uint32
+
+	// Centrality is the betweenness centrality of each node.
+	Centrality map[NodeID]float64
+
+	// NormalizedCentrality is the betweenness centrality of each node,
+	// normalized to the range [0, 1].
+	NormalizedCentrality map[NodeID]float64
+
+	// ComponentSizes holds the number of nodes in each of the connected
+	// components of the graph, largest first.
+	ComponentSizes []int
+
+	// Bridges is the set of channels whose removal would disconnect the
+	// graph.
+	Bridges []Bridge
+
+	// componentOf maps each node to the size of its connected component.
+	componentOf map[NodeID]int
+
+	// chanIDs is the set of channels the analytics were computed over.
+	chanIDs map[uint64]struct{}
+}
+
+// Reachable returns the number of nodes that can be reached from the given
+// node.
+func (s *GraphStats) Reachable(node NodeID) (int, error) {
+	size, ok := s.componentOf[node]
+	if !ok {
+		return 0, ErrUnknownNode
+	}
+
+	// The node itself is part of its component.
+	return size - 1, nil
+}
+
+// Reachability returns the number of nodes that can be reached from each node.
+func (s *GraphStats) Reachability() map[NodeID]int {
+	return reachability(s.componentOf)
+}
+
+// reachability converts the component size of each node into the number of
+// nodes that can be reached from it.
+func reachability(componentOf map[NodeID]int) map[NodeID]int {
+	reachable := make(map[NodeID]int, len(componentOf))
+	for node, size := range componentOf {
+		reachable[node] = size - 1
+	}
+
+	return reachable
+}
+
+// componentSizes returns the size of the connected component of each node of
+// the given graph, given the component each node is labeled with.
+func componentSizes(simpleGraph *SimpleGraph,
+	components []int) map[NodeID]int {
+
+	sizes := make(map[int]int)
+	for _, component := range components {
+		sizes[component]++
+	}
+
+	componentOf := make(map[NodeID]int, len(components))
+	for node, component := range components {
+		componentOf[simpleGraph.Nodes[node]] = sizes[component]
+	}
+
+	return componentOf
+}
+
+// Reachability returns the number of nodes that can be reached from each node
+// of the given channel graph.
+func Reachability(g ChannelGraph) (map[NodeID]int, error) {
+	simpleGraph, err := NewSimpleGraph(g)
+	if err != nil {
+		return nil, err
+	}
+
+	componentOf := componentSizes(simpleGraph, simpleGraph.components())
+
+	return reachability(componentOf), nil
+}
+
+// channelIDs returns the set of channels of the given channel graph.
+func channelIDs(g ChannelGraph) (map[uint64]struct{}, error) {
+	chanIDs := make(map[uint64]struct{})
+	err := g.ForEachNode(func(node Node) error {
+		return node.ForEachChannel(func(edge ChannelEdge) error {
+			chanIDs[edge.ChanID.ToUint64()] = struct{}{}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chanIDs, nil
+}
+
+// changesStructure returns true if the given topology change adds a channel
+// to or removes a channel from the analyzed graph. Policy updates of known
+// channels don't affect the analytics.
+func (s *GraphStats) changesStructure(change *graph.TopologyChange) bool {
+	for _, edgeUpdate := range change.ChannelEdgeUpdates {
+		if _, ok := s.chanIDs[edgeUpdate.ChanID]; !ok {
+			return true
+		}
+	}
+
+	for _, closedChan := range change.ClosedChannels {
+		if _, ok := s.chanIDs[closedChan.ChanID]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GraphAnalyticsCfg houses the values and methods the GraphAnalytics needs to
+// analyze the channel graph.
+type GraphAnalyticsCfg struct {
+	// Graph is the channel graph to analyze.
+	Graph ChannelGraph
+
+	// SubscribeTopology is used to get a subscription for topology changes
+	// on the network.
+	SubscribeTopology func() (*graph.TopologyClient, error)
+
+	// Workers is the number of workers used to compute the betweenness
+	// centrality.
+	Workers int
+
+	// BatchDelay is the delay between the first topology change that adds
+	// or removes a channel and the recomputation of the analytics. This
+	// batches the changes that arrive through gossip, as computing the
+	// analytics is expensive for large graphs.
+	BatchDelay time.Duration
+
+	// Clock is the clock used to time the recomputations.
+	Clock clock.Clock
+}
+
+// GraphAnalytics computes analytics such as betweenness centrality,
+// reachability and bridges over the channel graph, and keeps them up to date
+// as gossip arrives. This allows the analytics to be queried without having to
+// walk the full graph on every request.
+type GraphAnalytics struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *GraphAnalyticsCfg
+
+	// centrality is used to compute the betweenness centrality of the
+	// graph. It is guarded by refreshMtx.
+	centrality *BetweennessCentrality
+
+	// refreshMtx ensures that only one refresh runs at a time.
+	refreshMtx sync.Mutex
+
+	// stats is the most recently computed snapshot of the analytics.
+	stats    *GraphStats
+	statsMtx sync.RWMutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewGraphAnalytics creates a new GraphAnalytics instance from the passed
+// config.
+func NewGraphAnalytics(cfg *GraphAnalyticsCfg) (*GraphAnalytics, error) {
+	if cfg.Graph == nil {
+		return nil, errors.New("graph must be set")
+	}
+
+	centrality, err := NewBetweennessCentralityMetric(cfg.Workers)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.BatchDelay == 0 {
+		cfg.BatchDelay = DefaultAnalyticsBatchDelay
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = clock.NewDefaultClock()
+	}
+
+	return &GraphAnalytics{
+		cfg:        cfg,
+		centrality: centrality,
+		quit:       make(chan struct{}),
+	}, nil
+}
+
+// Start computes the initial analytics in the background and subscribes to
+// topology changes to keep them up to date.
+func (g *GraphAnalytics) Start() error {
+	var startErr error
+	g.started.Do(func() {
+		log.Info("Graph analytics starting")
+
+		var topology *graph.TopologyClient
+		if g.cfg.SubscribeTopology != nil {
+			var err error
+			topology, err = g.cfg.SubscribeTopology()
+			if err != nil {
+				startErr = fmt.Errorf("unable to subscribe to "+
+					"topology changes: %w", err)
+
+				return
+			}
+		}
+
+		g.wg.Add(1)
+		go g.analyticsLoop(topology)
+	})
+
+	return startErr
+}
+
+// Stop stops the GraphAnalytics.
+func (g *GraphAnalytics) Stop() error {
+	g.stopped.Do(func() {
+		log.Info("Graph analytics shutting down...")
+		defer log.Debug("Graph analytics shutdown complete")
+
+		close(g.quit)
+		g.wg.Wait()
+	})
+
+	return nil
+}
+
+// Stats returns the most recently computed snapshot of the graph analytics.
+func (g *GraphAnalytics) Stats() (*GraphStats, error) {
+	g.statsMtx.RLock()
+	defer g.statsMtx.RUnlock()
+
+	if g.stats == nil {
+		return nil, ErrAnalyticsNotReady
+	}
+
+	return g.stats, nil
+}
+
+// analyticsLoop computes the analytics once at startup, and then recomputes
+// them whenever the topology notifications report that a channel was added to
+// or removed from the graph. The recomputation is delayed by the batch delay,
+// so that it covers all changes that arrive in the meantime.
+//
+// NOTE: This MUST be run as a goroutine.
+func (g *GraphAnalytics) analyticsLoop(topology *graph.TopologyClient) {
+	defer g.wg.Done()
+
+	var topologyChanges <-chan *graph.TopologyChange
+	if topology != nil {
+		defer topology.Cancel()
+		topologyChanges = topology.TopologyChanges
+	}
+
+	refresh := func() {
+		if err := g.Refresh(); err != nil {
+			log.Errorf("Unable to compute graph analytics: %v",
+				err)
+		}
+	}
+
+	refresh()
+
+	// batchTimer is set while a recomputation is pending.
+	var batchTimer <-chan time.Time
+	for {
+		select {
+		case change, ok := <-topologyChanges:
+			if !ok {
+				topologyChanges = nil
+				continue
+			}
+
+			if batchTimer != nil {
+				continue
+			}
+
+			// If the analytics were never computed, any change is
+			// worth a retry.
+			stats, err := g.Stats()
+			if err == nil && !stats.changesStructure(change) {
+				continue
+			}
+
+			batchTimer = g.cfg.Clock.TickAfter(g.cfg.BatchDelay)
+
+		case <-batchTimer:
+			batchTimer = nil
+			refresh()
+
+		case <-g.quit:
+			return
+		}
+	}
+}
+
+// Refresh recomputes the analytics over the current channel graph.
+func (g *GraphAnalytics) Refresh() error {
+	g.refreshMtx.Lock()
+	defer g.refreshMtx.Unlock()
+
+	start := g.cfg.Clock.Now()
+
+	simpleGraph, err := NewSimpleGraph(g.cfg.Graph)
+	if err != nil {
+		return err
+	}
+
+	chanIDs, err := channelIDs(g.cfg.Graph)
+	if err != nil {
+		return err
+	}
+
+	stats := &GraphStats{
+		Timestamp: start,
+		NumNodes:  len(simpleGraph.Nodes),
+		chanIDs:   chanIDs,
+	}
+
+	for _, neighbors := range simpleGraph.Adj {
+		stats.NumChannels += len(neighbors)
+	}
+
+	// Each channel is part of the adjacency list of both of its nodes.
+	stats.NumChannels /= 2
+
+	if len(simpleGraph.Nodes) > 0 {
+		stats.Diameter = simpleGraph.DiameterRadialCutoff()
+	}
+
+	g.centrality.refresh(simpleGraph)
+	stats.Centrality = g.centrality.GetMetric(false)
+	stats.NormalizedCentrality = g.centrality.GetMetric(true)
+
+	components := simpleGraph.components()
+	sizes := make(map[int]int)
+	for _, component := range components {
+		sizes[component]++
+	}
+	for _, size := range sizes {
+		stats.ComponentSizes = append(stats.ComponentSizes, size)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(stats.ComponentSizes)))
+
+	stats.componentOf = componentSizes(simpleGraph, components)
+
+	for _, bridge := range simpleGraph.bridges() {
+		stats.Bridges = append(stats.Bridges, Bridge{
+			Node1: simpleGraph.Nodes[bridge[0]],
+			Node2: simpleGraph.Nodes[bridge[1]],
+		})
+	}
+
+	log.Debugf("Computed graph analytics in %v: nodes=%d, channels=%d, "+
+		"diameter=%d, components=%d, bridges=%d",
+		g.cfg.Clock.Now().Sub(start),
+		stats.NumNodes, stats.NumChannels, stats.Diameter,
+		len(stats.ComponentSizes), len(stats.Bridges))
+
+	g.statsMtx.Lock()
+	g.stats = stats
+	g.statsMtx.Unlock()
+
+	return nil
+}
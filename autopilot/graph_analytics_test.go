@@ -0,0 +1,219 @@
+package autopilot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/graph"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSimpleGraphBridges asserts that bridges are correctly detected, taking
+// parallel channels into account.
+func TestSimpleGraphBridges(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		graph   testGraphDesc
+		bridges [][2]int
+	}{
+		{
+			name:    "centrality test graph",
+			graph:   centralityTestGraph,
+			bridges: [][2]int{{6, 8}},
+		},
+		{
+			name: "chain",
+			graph: testGraphDesc{
+				nodes: 3,
+				edges: map[int][]int{
+					0: {1},
+					1: {2},
+				},
+			},
+			bridges: [][2]int{{1, 2}, {0, 1}},
+		},
+		{
+			name: "parallel channels",
+			graph: testGraphDesc{
+				nodes: 2,
+				edges: map[int][]int{
+					0: {1, 1},
+				},
+			},
+		},
+		{
+			name: "cycle",
+			graph: testGraphDesc{
+				nodes: 3,
+				edges: map[int][]int{
+					0: {1},
+					1: {2},
+					2: {0},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			simpleGraph := NewTestSimpleGraph(tc.graph)
+			require.Equal(t, tc.bridges, simpleGraph.bridges())
+		})
+	}
+}
+
+// TestSimpleGraphComponents asserts that the nodes are labeled with their
+// connected component.
+func TestSimpleGraphComponents(t *testing.T) {
+	t.Parallel()
+
+	simpleGraph := NewTestSimpleGraph(testGraphDesc{
+		nodes: 5,
+		edges: map[int][]int{
+			0: {1},
+			2: {3, 4},
+		},
+	})
+
+	require.Equal(t, []int{0, 0, 1, 1, 1}, simpleGraph.components())
+}
+
+// TestGraphAnalytics asserts that the graph analytics are computed on startup
+// and recomputed once the topology notifications report that a channel was
+// added.
+func TestGraphAnalytics(t *testing.T) {
+	t.Parallel()
+
+	chanGraph, err := newMemChanGraph(t)
+	require.NoError(t, err)
+
+	nodes := buildTestGraph(t, chanGraph, centralityTestGraph)
+
+	const batchDelay = time.Minute
+
+	topologyChanges := make(chan *graph.TopologyChange)
+	tickSignal := make(chan time.Duration, 1)
+	testClock := clock.NewTestClockWithTickSignal(
+		time.Unix(1, 0), tickSignal,
+	)
+
+	analytics, err := NewGraphAnalytics(&GraphAnalyticsCfg{
+		Graph: chanGraph,
+		SubscribeTopology: func() (*graph.TopologyClient, error) {
+			return &graph.TopologyClient{
+				TopologyChanges: topologyChanges,
+				Cancel:          func() {},
+			}, nil
+		},
+		Workers:    2,
+		BatchDelay: batchDelay,
+		Clock:      testClock,
+	})
+	require.NoError(t, err)
+
+	_, err = analytics.Stats()
+	require.ErrorIs(t, err, ErrAnalyticsNotReady)
+
+	require.NoError(t, analytics.Start())
+	t.Cleanup(func() {
+		require.NoError(t, analytics.Stop())
+	})
+
+	var stats *GraphStats
+	require.Eventually(t, func() bool {
+		stats, err = analytics.Stats()
+		return err == nil
+	}, time.Second*5, time.Millisecond*50)
+
+	require.Equal(t, 9, stats.NumNodes)
+	require.Equal(t, 14, stats.NumChannels)
+	require.EqualValues(t, 5, stats.Diameter)
+	require.Equal(t, []int{9}, stats.ComponentSizes)
+	require.Len(t, stats.Bridges, 1)
+	require.ElementsMatch(
+		t, []NodeID{NewNodeID(nodes[6]), NewNodeID(nodes[8])},
+		[]NodeID{stats.Bridges[0].Node1, stats.Bridges[0].Node2},
+	)
+	centrality := stats.Centrality[NewNodeID(nodes[3])]
+	require.Equal(t, testGraphCentrality[3], centrality)
+
+	reachable, err := stats.Reachable(NewNodeID(nodes[0]))
+	require.NoError(t, err)
+	require.Equal(t, 8, reachable)
+	require.Equal(t, 8, stats.Reachability()[NewNodeID(nodes[0])])
+
+	var knownChanID uint64
+	for chanID := range stats.chanIDs {
+		knownChanID = chanID
+		break
+	}
+
+	// Add a disconnected pair of nodes to the graph.
+	node1, err := chanGraph.addRandNode()
+	require.NoError(t, err)
+	node2, err := chanGraph.addRandNode()
+	require.NoError(t, err)
+	edge, _, err := chanGraph.addRandChannel(
+		node1, node2, btcutil.SatoshiPerBitcoin,
+	)
+	require.NoError(t, err)
+
+	// Policy updates of known channels and the closure of unknown
+	// channels don't change the structure of the graph, so they don't
+	// schedule a refresh. As the topology channel is unbuffered, the first
+	// change has been fully handled once the second one is received.
+	topologyChanges <- &graph.TopologyChange{
+		ChannelEdgeUpdates: []*graph.ChannelEdgeUpdate{{
+			ChanID: knownChanID,
+		}},
+		ClosedChannels: []*graph.ClosedChanSummary{{
+			ChanID: knownChanID + 1_000_000,
+		}},
+	}
+	topologyChanges <- &graph.TopologyChange{}
+	require.Empty(t, tickSignal)
+
+	// An update of the new channel schedules a refresh after the batch
+	// delay.
+	topologyChanges <- &graph.TopologyChange{
+		ChannelEdgeUpdates: []*graph.ChannelEdgeUpdate{{
+			ChanID: edge.ChanID.ToUint64(),
+		}},
+	}
+	require.Equal(t, batchDelay, <-tickSignal)
+
+	unchanged, err := analytics.Stats()
+	require.NoError(t, err)
+	require.Same(t, stats, unchanged)
+
+	testClock.SetTime(testClock.Now().Add(batchDelay))
+
+	require.Eventually(t, func() bool {
+		stats, err = analytics.Stats()
+		require.NoError(t, err)
+
+		return stats.NumNodes == 11
+	}, time.Second*5, time.Millisecond*50)
+
+	require.Equal(t, []int{9, 2}, stats.ComponentSizes)
+
+	reachable, err = stats.Reachable(NewNodeID(node1))
+	require.NoError(t, err)
+	require.Equal(t, 1, reachable)
+
+	// Closing the new channel schedules another refresh.
+	topologyChanges <- &graph.TopologyChange{
+		ClosedChannels: []*graph.ClosedChanSummary{{
+			ChanID: edge.ChanID.ToUint64(),
+		}},
+	}
+	require.Equal(t, batchDelay, <-tickSignal)
+}
@@ -233,3 +233,132 @@ func (graph *SimpleGraph) DiameterRadialCutoff() uint32 {
 	eccentricities := graph.nodeEccentricities(nodes)
 	return maxVal(eccentricities)
 }
+
+// components labels each node with the index of the connected component it
+// is part of.
+func (graph *SimpleGraph) components() []int {
+	components := make([]int, len(graph.Adj))
+	for node := range components {
+		components[node] = -1
+	}
+
+	var (
+		component int
+		queue     []int
+	)
+	for root := range graph.Adj {
+		if components[root] >= 0 {
+			continue
+		}
+
+		components[root] = component
+		queue = append(queue[:0], root)
+		for len(queue) > 0 {
+			node := queue[0]
+			queue = queue[1:]
+
+			for _, neighbor := range graph.Adj[node] {
+				if components[neighbor] >= 0 {
+					continue
+				}
+
+				components[neighbor] = component
+				queue = append(queue, neighbor)
+			}
+		}
+
+		component++
+	}
+
+	return components
+}
+
+// bridges returns the edges of the graph whose removal would increase the
+// number of connected components, using Tarjan's bridge-finding algorithm.
+// The depth-first search is iterative, as the depth of the search may be as
+// large as the number of nodes in the graph.
+func (graph *SimpleGraph) bridges() [][2]int {
+	// disc holds the discovery time of each node, starting at one such
+	// that zero means the node hasn't been visited yet. low holds the
+	// lowest discovery time that is reachable from the subtree of each
+	// node through a single back edge.
+	disc := make([]int, len(graph.Adj))
+	low := make([]int, len(graph.Adj))
+
+	// frame is the state of a node on the depth-first search stack.
+	type frame struct {
+		node   int
+		parent int
+
+		// next is the index of the next neighbor to visit.
+		next int
+
+		// skippedParent is set once the edge to the parent has been
+		// skipped. Any further edges to the parent are parallel
+		// channels, which means the edge to the parent isn't a bridge.
+		skippedParent bool
+	}
+
+	var (
+		bridges [][2]int
+		time    int
+		stack   []frame
+	)
+	for root := range graph.Adj {
+		if disc[root] != 0 {
+			continue
+		}
+
+		time++
+		disc[root], low[root] = time, time
+		stack = append(stack[:0], frame{node: root, parent: -1})
+
+		for len(stack) > 0 {
+			top := &stack[len(stack)-1]
+
+			if top.next < len(graph.Adj[top.node]) {
+				neighbor := graph.Adj[top.node][top.next]
+				top.next++
+
+				isParent := neighbor == top.parent
+				switch {
+				case isParent && !top.skippedParent:
+					top.skippedParent = true
+
+				case disc[neighbor] == 0:
+					time++
+					disc[neighbor] = time
+					low[neighbor] = time
+
+					stack = append(stack, frame{
+						node:   neighbor,
+						parent: top.node,
+					})
+
+				default:
+					low[top.node] = min(
+						low[top.node], disc[neighbor],
+					)
+				}
+
+				continue
+			}
+
+			// All neighbors of the node have been visited, so we
+			// can propagate its low value to its parent.
+			node, parent := top.node, top.parent
+			stack = stack[:len(stack)-1]
+
+			if parent < 0 {
+				continue
+			}
+
+			low[parent] = min(low[parent], low[node])
+			if low[node] > disc[parent] {
+				bridges = append(bridges, [2]int{parent, node})
+			}
+		}
+	}
+
+	return bridges
+}
@@ -0,0 +1,105 @@
+package lnd
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/funding"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// BatchAllowPartialMetadataKey is the gRPC metadata key a client sets
+	// to "true" to allow individual channels of a BatchOpenChannel call to
+	// fail without aborting the whole batch. The failed channels are
+	// removed and the funding transaction is re-computed for the remaining
+	// ones.
+	BatchAllowPartialMetadataKey = "batch-allow-partial"
+
+	// BatchOutcomesMetadataKey is the gRPC header key under which the JSON
+	// encoded outcome of each channel of a BatchOpenChannel call is sent
+	// back to the client.
+	BatchOutcomesMetadataKey = "batch-outcomes"
+)
+
+// BatchChannelOutcome is the outcome of a single channel of a batch channel
+// open.
+type BatchChannelOutcome struct {
+	// Index is the position of the channel in the batch request.
+	Index int `json:"index"`
+
+	// NodePubkey is the hex encoded public key of the peer.
+	NodePubkey string `json:"node_pubkey"`
+
+	// PendingChanID is the hex encoded pending channel ID of the channel.
+	PendingChanID string `json:"pending_chan_id"`
+
+	// ChannelPoint is the funding outpoint of the channel if it was
+	// opened.
+	ChannelPoint string `json:"channel_point,omitempty"`
+
+	// Error is the reason the channel wasn't opened.
+	Error string `json:"error,omitempty"`
+}
+
+// batchAllowPartialFromContext returns true if the client allowed partial
+// failures of a batch channel open through the request metadata.
+func batchAllowPartialFromContext(ctx context.Context) (bool, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false, nil
+	}
+
+	values := md.Get(BatchAllowPartialMetadataKey)
+	if len(values) == 0 {
+		return false, nil
+	}
+
+	allowPartial, err := strconv.ParseBool(values[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid %v metadata: %w",
+			BatchAllowPartialMetadataKey, err)
+	}
+
+	return allowPartial, nil
+}
+
+// sendBatchOutcomes sends the outcome of each channel of a batch channel open
+// back to the client as a response header.
+func sendBatchOutcomes(ctx context.Context,
+	outcomes []*funding.ChannelOutcome) error {
+
+	rpcOutcomes := make([]*BatchChannelOutcome, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		rpcOutcome := &BatchChannelOutcome{
+			Index: outcome.Index,
+			NodePubkey: hex.EncodeToString(
+				outcome.NodePubkey.SerializeCompressed(),
+			),
+			PendingChanID: hex.EncodeToString(
+				outcome.PendingChanID[:],
+			),
+		}
+		if outcome.ChanPoint != nil {
+			rpcOutcome.ChannelPoint = outcome.ChanPoint.String()
+		}
+		if outcome.Err != nil {
+			rpcOutcome.Error = outcome.Err.Error()
+		}
+
+		rpcOutcomes = append(rpcOutcomes, rpcOutcome)
+	}
+
+	encoded, err := json.Marshal(rpcOutcomes)
+	if err != nil {
+		return err
+	}
+
+	return grpc.SetHeader(
+		ctx, metadata.Pairs(BatchOutcomesMetadataKey, string(encoded)),
+	)
+}
@@ -0,0 +1,75 @@
+// Package caveats implements the custom macaroon caveats that lnd enforces
+// itself, so that least-privilege macaroons can be baked without running an
+// RPC middleware.
+package caveats
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"gopkg.in/macaroon.v2"
+)
+
+const (
+	// MaxAmountPerDayName is the name of the custom caveat that limits
+	// the amount a macaroon can spend within a day. Its condition is the
+	// maximum amount in satoshis.
+	MaxAmountPerDayName = "max-amount-per-day"
+
+	// ChannelAllowlistName is the name of the custom caveat that restricts
+	// a macaroon to a set of channels. Its condition is a comma separated
+	// list of short channel IDs.
+	ChannelAllowlistName = "channel-allowlist"
+)
+
+// MaxAmountPerDayConstraint returns a macaroon constraint that limits the
+// amount the macaroon can spend within a day.
+func MaxAmountPerDayConstraint(
+	amt btcutil.Amount) func(*macaroon.Macaroon) error {
+
+	return macaroons.CustomConstraint(
+		MaxAmountPerDayName, strconv.FormatInt(int64(amt), 10),
+	)
+}
+
+// ChannelAllowlistConstraint returns a macaroon constraint that restricts
+// the macaroon to the given channels.
+func ChannelAllowlistConstraint(
+	chanIDs ...lnwire.ShortChannelID) func(*macaroon.Macaroon) error {
+
+	ids := make([]string, 0, len(chanIDs))
+	for _, chanID := range chanIDs {
+		ids = append(ids, strconv.FormatUint(chanID.ToUint64(), 10))
+	}
+
+	return macaroons.CustomConstraint(
+		ChannelAllowlistName, strings.Join(ids, ","),
+	)
+}
+
+// macaroonID returns the ID of the macaroon in the request context. Macaroons
+// that are derived from the same macaroon share its ID.
+func macaroonID(ctx context.Context) ([]byte, error) {
+	macHex, err := macaroons.RawMacaroonFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	macBytes, err := hex.DecodeString(macHex)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := &macaroon.Macaroon{}
+	if err := mac.UnmarshalBinary(macBytes); err != nil {
+		return nil, fmt.Errorf("unable to decode macaroon: %w", err)
+	}
+
+	return mac.Id(), nil
+}
@@ -0,0 +1,193 @@
+package caveats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrChannelNotAllowed is returned if a request made with a macaroon that has
+// a channel allowlist acts on a channel that isn't on the list.
+var ErrChannelNotAllowed = errors.New("channel not allowed")
+
+// ChannelAllowlistValidator restricts macaroons to the channels in their
+// allowlist. Payments must be restricted to outgoing channels of the list,
+// and channels can only be rebalanced, closed, abandoned or updated if they're
+// on the list.
+type ChannelAllowlistValidator struct {
+	// shortChanID returns the short channel ID of the channel with the
+	// given funding outpoint.
+	shortChanID func(wire.OutPoint) (lnwire.ShortChannelID, error)
+}
+
+// NewChannelAllowlistValidator returns a new validator that looks up the
+// short channel IDs of channel points with the given function.
+func NewChannelAllowlistValidator(
+	shortChanID func(wire.OutPoint) (lnwire.ShortChannelID,
+		error)) *ChannelAllowlistValidator {
+
+	return &ChannelAllowlistValidator{
+		shortChanID: shortChanID,
+	}
+}
+
+// ValidateCaveat rejects requests that act on channels which aren't in the
+// allowlist of the condition.
+//
+// NOTE: This is part of the rpcperms.CaveatValidator interface.
+func (v *ChannelAllowlistValidator) ValidateCaveat(_ context.Context,
+	_ string, condition string, req interface{}) error {
+
+	allowlist, err := parseAllowlist(condition)
+	if err != nil {
+		return err
+	}
+
+	switch r := req.(type) {
+	case *lnrpc.SendRequest:
+		return allowlist.checkOutgoing([]uint64{r.OutgoingChanId})
+
+	case *routerrpc.SendPaymentRequest:
+		chanIDs := r.OutgoingChanIds
+		if r.OutgoingChanId != 0 {
+			chanIDs = append(chanIDs, r.OutgoingChanId)
+		}
+
+		return allowlist.checkOutgoing(chanIDs)
+
+	case *lnrpc.SendToRouteRequest:
+		return allowlist.checkRoute(r.Route)
+
+	case *routerrpc.SendToRouteRequest:
+		return allowlist.checkRoute(r.Route)
+
+	case *routerrpc.RebalanceRequest:
+		if err := allowlist.check(r.OutgoingChanId); err != nil {
+			return err
+		}
+
+		return allowlist.check(r.IncomingChanId)
+
+	case *lnrpc.CloseChannelRequest:
+		return v.checkChanPoint(allowlist, r.ChannelPoint)
+
+	case *lnrpc.AbandonChannelRequest:
+		return v.checkChanPoint(allowlist, r.ChannelPoint)
+
+	case *lnrpc.PolicyUpdateRequest:
+		if r.GetGlobal() {
+			return fmt.Errorf("%w: updating the policy of all "+
+				"channels", ErrChannelNotAllowed)
+		}
+
+		return v.checkChanPoint(allowlist, r.GetChanPoint())
+
+	case *routerrpc.UpdateChanStatusRequest:
+		return v.checkChanPoint(allowlist, r.ChanPoint)
+
+	default:
+		return nil
+	}
+}
+
+// checkChanPoint returns an error if the channel with the funding outpoint
+// isn't in the allowlist.
+func (v *ChannelAllowlistValidator) checkChanPoint(allowlist channelAllowlist,
+	chanPoint *lnrpc.ChannelPoint) error {
+
+	if chanPoint == nil {
+		return fmt.Errorf("%w: no channel point", ErrChannelNotAllowed)
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
+	if err != nil {
+		return err
+	}
+	op := wire.OutPoint{
+		Hash:  *txid,
+		Index: chanPoint.OutputIndex,
+	}
+
+	chanID, err := v.shortChanID(op)
+	if err != nil {
+		return fmt.Errorf("%w: unable to look up channel %v: %v",
+			ErrChannelNotAllowed, op, err)
+	}
+
+	return allowlist.check(chanID.ToUint64())
+}
+
+// channelAllowlist is the set of short channel IDs a macaroon is restricted
+// to.
+type channelAllowlist map[uint64]struct{}
+
+// parseAllowlist parses the comma separated short channel IDs of a caveat
+// condition.
+func parseAllowlist(condition string) (channelAllowlist, error) {
+	allowlist := make(channelAllowlist)
+	for _, field := range strings.Split(condition, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		chanID, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel ID '%s' in "+
+				"allowlist", field)
+		}
+		allowlist[chanID] = struct{}{}
+	}
+
+	return allowlist, nil
+}
+
+// check returns an error if the channel isn't in the allowlist.
+func (a channelAllowlist) check(chanID uint64) error {
+	if _, ok := a[chanID]; !ok {
+		return fmt.Errorf("%w: %v", ErrChannelNotAllowed,
+			lnwire.NewShortChanIDFromInt(chanID))
+	}
+
+	return nil
+}
+
+// checkOutgoing returns an error unless the payment is restricted to
+// outgoing channels of the allowlist.
+func (a channelAllowlist) checkOutgoing(chanIDs []uint64) error {
+	restricted := false
+	for _, chanID := range chanIDs {
+		if chanID == 0 {
+			continue
+		}
+
+		if err := a.check(chanID); err != nil {
+			return err
+		}
+		restricted = true
+	}
+
+	if !restricted {
+		return fmt.Errorf("%w: payments must be restricted to "+
+			"allowed outgoing channels", ErrChannelNotAllowed)
+	}
+
+	return nil
+}
+
+// checkRoute returns an error unless the first hop of the route uses a
+// channel of the allowlist.
+func (a channelAllowlist) checkRoute(route *lnrpc.Route) error {
+	if len(route.GetHops()) == 0 {
+		return fmt.Errorf("%w: route has no hops", ErrChannelNotAllowed)
+	}
+
+	return a.check(route.Hops[0].ChanId)
+}
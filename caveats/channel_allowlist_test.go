@@ -0,0 +1,159 @@
+package caveats
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelAllowlistValidator tests that requests are restricted to the
+// channels of the allowlist.
+func TestChannelAllowlistValidator(t *testing.T) {
+	t.Parallel()
+
+	allowedPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 0}
+	otherPoint := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+	chanIDs := map[wire.OutPoint]lnwire.ShortChannelID{
+		allowedPoint: lnwire.NewShortChanIDFromInt(100),
+		otherPoint:   lnwire.NewShortChanIDFromInt(200),
+	}
+
+	validator := NewChannelAllowlistValidator(
+		func(op wire.OutPoint) (lnwire.ShortChannelID, error) {
+			chanID, ok := chanIDs[op]
+			if !ok {
+				return lnwire.ShortChannelID{},
+					errors.New("unknown channel")
+			}
+
+			return chanID, nil
+		},
+	)
+
+	rpcPoint := func(op wire.OutPoint) *lnrpc.ChannelPoint {
+		return &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: op.Hash[:],
+			},
+			OutputIndex: op.Index,
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		req     interface{}
+		allowed bool
+	}{{
+		name:    "stream establishment",
+		allowed: true,
+	}, {
+		name:    "unrelated request",
+		req:     &lnrpc.GetInfoRequest{},
+		allowed: true,
+	}, {
+		name:    "payment without outgoing channel",
+		req:     &lnrpc.SendRequest{},
+		allowed: false,
+	}, {
+		name:    "payment over allowed channel",
+		req:     &lnrpc.SendRequest{OutgoingChanId: 100},
+		allowed: true,
+	}, {
+		name: "payment over other channel",
+		req: &routerrpc.SendPaymentRequest{
+			OutgoingChanIds: []uint64{100, 200},
+		},
+		allowed: false,
+	}, {
+		name: "route over allowed channel",
+		req: &routerrpc.SendToRouteRequest{
+			Route: &lnrpc.Route{
+				Hops: []*lnrpc.Hop{{ChanId: 100}, {ChanId: 200}},
+			},
+		},
+		allowed: true,
+	}, {
+		name: "route over other channel",
+		req: &lnrpc.SendToRouteRequest{
+			Route: &lnrpc.Route{Hops: []*lnrpc.Hop{{ChanId: 200}}},
+		},
+		allowed: false,
+	}, {
+		name: "rebalance into other channel",
+		req: &routerrpc.RebalanceRequest{
+			OutgoingChanId: 100,
+			IncomingChanId: 200,
+		},
+		allowed: false,
+	}, {
+		name: "close allowed channel",
+		req: &lnrpc.CloseChannelRequest{
+			ChannelPoint: rpcPoint(allowedPoint),
+		},
+		allowed: true,
+	}, {
+		name: "close other channel",
+		req: &lnrpc.CloseChannelRequest{
+			ChannelPoint: rpcPoint(otherPoint),
+		},
+		allowed: false,
+	}, {
+		name: "update policy of all channels",
+		req: &lnrpc.PolicyUpdateRequest{
+			Scope: &lnrpc.PolicyUpdateRequest_Global{Global: true},
+		},
+		allowed: false,
+	}, {
+		name: "disable unknown channel",
+		req: &routerrpc.UpdateChanStatusRequest{
+			ChanPoint: rpcPoint(wire.OutPoint{Index: 5}),
+		},
+		allowed: false,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validator.ValidateCaveat(
+				context.Background(), "", "100, 300", tc.req,
+			)
+			if tc.allowed {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, ErrChannelNotAllowed)
+			}
+		})
+	}
+
+	// Invalid allowlists are rejected.
+	err := validator.ValidateCaveat(
+		context.Background(), "", "100,chan", nil,
+	)
+	require.ErrorContains(t, err, "invalid channel ID")
+}
+
+// TestChannelAllowlistConstraint tests that the condition of the constraint
+// is parsed back into the allowlist.
+func TestChannelAllowlistConstraint(t *testing.T) {
+	t.Parallel()
+
+	mac := newTestMacaroon(t, "id")
+	err := ChannelAllowlistConstraint(
+		lnwire.NewShortChanIDFromInt(100),
+		lnwire.NewShortChanIDFromInt(300),
+	)(mac)
+	require.NoError(t, err)
+
+	allowlist, err := parseAllowlist(
+		macaroons.GetCustomCaveatCondition(mac, ChannelAllowlistName),
+	)
+	require.NoError(t, err)
+	require.Equal(t, channelAllowlist{100: {}, 300: {}}, allowlist)
+}
@@ -0,0 +1,376 @@
+package caveats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+// spendWindow is the period over which the amounts spent with a macaroon are
+// summed up.
+const spendWindow = 24 * time.Hour
+
+var (
+	// ErrMethodNotMetered is returned if a macaroon with a maximum amount
+	// per day is used to call a method that spends funds which can't be
+	// metered.
+	ErrMethodNotMetered = errors.New("method spends funds that can't be " +
+		"metered")
+
+	// unmeteredMethods are the methods that spend funds without the
+	// amount being known from the request, such as methods that sign or
+	// publish arbitrary transactions.
+	unmeteredMethods = map[string]struct{}{
+		"/lnrpc.Lightning/FundingStateStep":       {},
+		"/routerrpc.Router/SendPayment":           {},
+		"/routerrpc.Router/SendToRoute":           {},
+		"/walletrpc.WalletKit/FundPsbt":           {},
+		"/walletrpc.WalletKit/SignPsbt":           {},
+		"/walletrpc.WalletKit/FinalizePsbt":       {},
+		"/walletrpc.WalletKit/PublishTransaction": {},
+		"/walletrpc.WalletKit/BumpFee":            {},
+		"/walletrpc.WalletKit/BumpForceCloseFee":  {},
+		"/walletrpc.WalletKit/SweepNow":           {},
+		"/signrpc.Signer/SignOutputRaw":           {},
+		"/signrpc.Signer/ComputeInputScript":      {},
+		"/signrpc.Signer/MuSig2Sign":              {},
+	}
+
+	// meteredMethods are the methods that spend funds whose amount is
+	// known from their requests.
+	meteredMethods = map[string]struct{}{
+		"/lnrpc.Lightning/SendPayment":      {},
+		"/lnrpc.Lightning/SendPaymentSync":  {},
+		"/lnrpc.Lightning/SendToRoute":      {},
+		"/lnrpc.Lightning/SendToRouteSync":  {},
+		"/lnrpc.Lightning/SendCoins":        {},
+		"/lnrpc.Lightning/SendMany":         {},
+		"/lnrpc.Lightning/OpenChannel":      {},
+		"/lnrpc.Lightning/OpenChannelSync":  {},
+		"/lnrpc.Lightning/BatchOpenChannel": {},
+		"/lnrpc.Lightning/CloseChannel":     {},
+		"/routerrpc.Router/SendPaymentV2":   {},
+		"/routerrpc.Router/SendPayments":    {},
+		"/routerrpc.Router/SendToRouteV2":   {},
+		"/routerrpc.Router/Rebalance":       {},
+		"/walletrpc.WalletKit/SendOutputs":  {},
+	}
+
+	// nonSpendingMethods are the methods that require the permission to
+	// write off-chain or on-chain state without spending any funds. Any
+	// other method with such a permission that isn't metered is rejected,
+	// so that a new method can't spend funds unnoticed.
+	nonSpendingMethods = map[string]struct{}{
+		"/autopilotrpc.Autopilot/SetScores":                    {},
+		"/lnrpc.Lightning/AbandonChannel":                      {},
+		"/lnrpc.Lightning/CancelJob":                           {},
+		"/lnrpc.Lightning/ChannelAcceptor":                     {},
+		"/lnrpc.Lightning/DeleteAllPayments":                   {},
+		"/lnrpc.Lightning/DeleteChannelTemplate":               {},
+		"/lnrpc.Lightning/DeletePayment":                       {},
+		"/lnrpc.Lightning/ForgetAbortedPsbtFlow":               {},
+		"/lnrpc.Lightning/ReleaseUtxoReservation":              {},
+		"/lnrpc.Lightning/RestoreChannelBackups":               {},
+		"/lnrpc.Lightning/SendCustomMessage":                   {},
+		"/lnrpc.Lightning/SetChannelTemplate":                  {},
+		"/lnrpc.Lightning/SetForwardingRules":                  {},
+		"/lnrpc.Lightning/UpdateChannelAcceptPolicies":         {},
+		"/lnrpc.Lightning/UpdateChannelPolicy":                 {},
+		"/routerrpc.Router/ReprocessForwardingPackage":         {},
+		"/routerrpc.Router/ResetMissionControl":                {},
+		"/routerrpc.Router/SetMissionControlConfig":            {},
+		"/routerrpc.Router/UpdateChanStatus":                   {},
+		"/routerrpc.Router/UpdateMaxFeeExposure":               {},
+		"/routerrpc.Router/XAddLocalChanAliases":               {},
+		"/routerrpc.Router/XDeleteLocalChanAliases":            {},
+		"/routerrpc.Router/XImportMissionControl":              {},
+		"/walletrpc.WalletKit/ImportAccount":                   {},
+		"/walletrpc.WalletKit/ImportPublicKey":                 {},
+		"/walletrpc.WalletKit/ImportTapscript":                 {},
+		"/walletrpc.WalletKit/LabelTransaction":                {},
+		"/walletrpc.WalletKit/LeaseOutput":                     {},
+		"/walletrpc.WalletKit/ReleaseOutput":                   {},
+		"/walletrpc.WalletKit/RemoveTransaction":               {},
+		"/walletrpc.WalletKit/SignMessageWithAddr":             {},
+		"/walletrpc.WalletKit/VerifyMessageWithAddr":           {},
+		"/wtclientrpc.WatchtowerClient/AddTower":               {},
+		"/wtclientrpc.WatchtowerClient/DeactivateTower":        {},
+		"/wtclientrpc.WatchtowerClient/ExcludeChannel":         {},
+		"/wtclientrpc.WatchtowerClient/RemoveTower":            {},
+		"/wtclientrpc.WatchtowerClient/SetChannelSweepFeeRate": {},
+		"/wtclientrpc.WatchtowerClient/TerminateSession":       {},
+	}
+)
+
+// MaxAmountValidator enforces the maximum amount per day of macaroons. The
+// amounts of the payments, on-chain sends and channel openings made with a
+// macaroon, including the fee limits of payments, are summed up over the
+// last 24 hours. An amount counts as spent once its request is accepted,
+// whether or not the payment or transaction succeeds.
+type MaxAmountValidator struct {
+	store *spendStore
+
+	chainParams *chaincfg.Params
+
+	permissions map[string][]bakery.Op
+}
+
+// NewMaxAmountValidator returns a new validator that persists the spent
+// amounts in the database. The permissions are those required by the methods
+// of the RPC server, which are used to reject methods that may spend funds
+// but aren't metered.
+func NewMaxAmountValidator(db kvdb.Backend, chainParams *chaincfg.Params,
+	clock clock.Clock,
+	permissions map[string][]bakery.Op) (*MaxAmountValidator, error) {
+
+	store, err := newSpendStore(db, clock)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MaxAmountValidator{
+		store:       store,
+		chainParams: chainParams,
+		permissions: permissions,
+	}, nil
+}
+
+// ValidateCaveat rejects requests that would spend more than the maximum
+// amount per day in the condition.
+//
+// NOTE: This is part of the rpcperms.CaveatValidator interface.
+func (v *MaxAmountValidator) ValidateCaveat(ctx context.Context, fullMethod,
+	condition string, req interface{}) error {
+
+	if _, ok := unmeteredMethods[fullMethod]; ok {
+		return ErrMethodNotMetered
+	}
+	if v.maySpendFunds(fullMethod) {
+		return fmt.Errorf("%w: method %v isn't metered",
+			ErrMethodNotMetered, fullMethod)
+	}
+
+	maxAmt, err := strconv.ParseInt(condition, 10, 64)
+	if err != nil || maxAmt < 0 {
+		return fmt.Errorf("invalid maximum amount '%s'", condition)
+	}
+	limit := lnwire.NewMSatFromSatoshis(btcutil.Amount(maxAmt))
+
+	amt, err := v.spentAmount(req)
+	if err != nil {
+		return err
+	}
+	if amt == 0 {
+		return nil
+	}
+
+	macID, err := macaroonID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return v.store.spend(macID, amt, limit, spendWindow)
+}
+
+// maySpendFunds returns true if the method requires the permission to write
+// off-chain or on-chain state, but is neither metered nor known not to spend
+// any funds.
+func (v *MaxAmountValidator) maySpendFunds(fullMethod string) bool {
+	if _, ok := meteredMethods[fullMethod]; ok {
+		return false
+	}
+	if _, ok := nonSpendingMethods[fullMethod]; ok {
+		return false
+	}
+
+	for _, op := range v.permissions[fullMethod] {
+		if op.Action != "write" {
+			continue
+		}
+
+		if op.Entity == "offchain" || op.Entity == "onchain" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// spentAmount returns the amount the request spends. Requests that don't
+// spend funds, and stream establishments without a request, spend nothing.
+func (v *MaxAmountValidator) spentAmount(
+	req interface{}) (lnwire.MilliSatoshi, error) {
+
+	switch r := req.(type) {
+	case *lnrpc.SendRequest:
+		amt, err := lnrpc.UnmarshallAmt(r.Amt, r.AmtMsat)
+		if err != nil {
+			return 0, err
+		}
+		amt, err = v.paymentAmount(r.PaymentRequest, amt)
+		if err != nil {
+			return 0, err
+		}
+
+		return amt + lnrpc.CalculateFeeLimit(r.FeeLimit, amt), nil
+
+	case *routerrpc.SendPaymentRequest:
+		return v.sendPaymentAmount(r)
+
+	case *routerrpc.SendPaymentsRequest:
+		var total lnwire.MilliSatoshi
+		for _, payment := range r.Payments {
+			amt, err := v.sendPaymentAmount(payment)
+			if err != nil {
+				return 0, err
+			}
+			total += amt
+		}
+
+		return total, nil
+
+	case *lnrpc.SendToRouteRequest:
+		return lnwire.MilliSatoshi(r.GetRoute().GetTotalAmtMsat()), nil
+
+	case *routerrpc.SendToRouteRequest:
+		return lnwire.MilliSatoshi(r.GetRoute().GetTotalAmtMsat()), nil
+
+	// Only the fee of a rebalance leaves the node, the amount comes back
+	// to one of our channels.
+	case *routerrpc.RebalanceRequest:
+		if r.QueryOnly {
+			return 0, nil
+		}
+
+		return satoshis(r.FeeLimitSat)
+
+	case *lnrpc.SendCoinsRequest:
+		if r.SendAll {
+			return 0, fmt.Errorf("%w: sending all coins",
+				ErrMethodNotMetered)
+		}
+
+		return satoshis(r.Amount)
+
+	case *lnrpc.SendManyRequest:
+		var total lnwire.MilliSatoshi
+		for _, amt := range r.AddrToAmount {
+			msat, err := satoshis(amt)
+			if err != nil {
+				return 0, err
+			}
+			total += msat
+		}
+
+		return total, nil
+
+	case *lnrpc.OpenChannelRequest:
+		if r.FundMax {
+			return 0, fmt.Errorf("%w: funding a channel with the "+
+				"maximum amount", ErrMethodNotMetered)
+		}
+
+		return satoshis(r.LocalFundingAmount)
+
+	case *lnrpc.BatchOpenChannelRequest:
+		var total lnwire.MilliSatoshi
+		for _, channel := range r.Channels {
+			msat, err := satoshis(channel.LocalFundingAmount)
+			if err != nil {
+				return 0, err
+			}
+			total += msat
+		}
+
+		return total, nil
+
+	// The balance of a cooperative close to an external delivery leaves
+	// the node, but it is only known once the close is negotiated.
+	case *lnrpc.CloseChannelRequest:
+		if r.DeliveryAddress != "" || len(r.DeliveryPsbt) > 0 {
+			return 0, fmt.Errorf("%w: closing a channel to an "+
+				"external delivery", ErrMethodNotMetered)
+		}
+
+		return 0, nil
+
+	case *walletrpc.SendOutputsRequest:
+		var total lnwire.MilliSatoshi
+		for _, output := range r.Outputs {
+			msat, err := satoshis(output.Value)
+			if err != nil {
+				return 0, err
+			}
+			total += msat
+		}
+
+		return total, nil
+
+	default:
+		return 0, nil
+	}
+}
+
+// sendPaymentAmount returns the amount of a payment sent with the router,
+// including its fee limit.
+func (v *MaxAmountValidator) sendPaymentAmount(
+	r *routerrpc.SendPaymentRequest) (lnwire.MilliSatoshi, error) {
+
+	amt, err := lnrpc.UnmarshallAmt(r.Amt, r.AmtMsat)
+	if err != nil {
+		return 0, err
+	}
+	amt, err = v.paymentAmount(r.PaymentRequest, amt)
+	if err != nil {
+		return 0, err
+	}
+	feeLimit, err := lnrpc.UnmarshallAmt(r.FeeLimitSat, r.FeeLimitMsat)
+	if err != nil {
+		return 0, err
+	}
+
+	return amt + feeLimit, nil
+}
+
+// paymentAmount returns the amount of the payment request if it has one, and
+// the amount of the request otherwise.
+func (v *MaxAmountValidator) paymentAmount(payReq string,
+	amt lnwire.MilliSatoshi) (lnwire.MilliSatoshi, error) {
+
+	if payReq == "" {
+		return amt, nil
+	}
+
+	invoice, err := zpay32.Decode(payReq, v.chainParams)
+	if err != nil {
+		return 0, err
+	}
+	if invoice.MilliSat != nil {
+		return *invoice.MilliSat, nil
+	}
+
+	return amt, nil
+}
+
+// satoshis converts an amount of a request to millisatoshis, rejecting
+// negative amounts.
+func satoshis(amt int64) (lnwire.MilliSatoshi, error) {
+	if amt < 0 {
+		return 0, fmt.Errorf("invalid negative amount %d", amt)
+	}
+
+	return lnwire.NewMSatFromSatoshis(btcutil.Amount(amt)), nil
+}
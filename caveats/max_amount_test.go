@@ -0,0 +1,274 @@
+package caveats
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+	"gopkg.in/macaroon.v2"
+)
+
+// testPermissions are the permissions of the methods used by the tests.
+var testPermissions = map[string][]bakery.Op{
+	"/lnrpc.Lightning/GetInfo": {{
+		Entity: "info",
+		Action: "read",
+	}},
+	"/lnrpc.Lightning/CloseChannel": {{
+		Entity: "onchain",
+		Action: "write",
+	}, {
+		Entity: "offchain",
+		Action: "write",
+	}},
+	"/lnrpc.Lightning/UpdateChannelPolicy": {{
+		Entity: "offchain",
+		Action: "write",
+	}},
+	"/routerrpc.Router/SendPayments": {{
+		Entity: "offchain",
+		Action: "write",
+	}},
+	"/routerrpc.Router/HtlcInterceptor": {{
+		Entity: "offchain",
+		Action: "read",
+	}, {
+		Entity: "offchain",
+		Action: "write",
+	}},
+}
+
+// newTestMacaroon returns a new macaroon with the given ID.
+func newTestMacaroon(t *testing.T, id string) *macaroon.Macaroon {
+	t.Helper()
+
+	mac, err := macaroon.New(
+		[]byte("root-key"), []byte(id), "lnd", macaroon.LatestVersion,
+	)
+	require.NoError(t, err)
+
+	return mac
+}
+
+// macaroonContext returns an incoming request context that carries a new
+// macaroon with the given ID.
+func macaroonContext(t *testing.T, id string) context.Context {
+	t.Helper()
+
+	mac := newTestMacaroon(t, id)
+
+	macBytes, err := mac.MarshalBinary()
+	require.NoError(t, err)
+
+	md := metadata.Pairs("macaroon", hex.EncodeToString(macBytes))
+
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+// TestMaxAmountValidator tests that the amounts spent with a macaroon are
+// limited to the maximum amount within a day.
+func TestMaxAmountValidator(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "caveats")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	testClock := clock.NewTestClock(time.Unix(1000, 0))
+	validator, err := NewMaxAmountValidator(
+		db, &chaincfg.RegressionNetParams, testClock, testPermissions,
+	)
+	require.NoError(t, err)
+
+	ctx := macaroonContext(t, "mac")
+	validate := func(method string, req interface{}) error {
+		return validator.ValidateCaveat(ctx, method, "10000", req)
+	}
+
+	// Stream establishments and requests that don't spend funds are
+	// accepted.
+	require.NoError(t, validate("/routerrpc.Router/SendPaymentV2", nil))
+	require.NoError(t, validate(
+		"/lnrpc.Lightning/GetInfo", &lnrpc.GetInfoRequest{},
+	))
+
+	// Methods that can't be metered are rejected.
+	require.ErrorIs(t, validate(
+		"/walletrpc.WalletKit/PublishTransaction", nil,
+	), ErrMethodNotMetered)
+	require.ErrorIs(t, validate(
+		"/lnrpc.Lightning/SendCoins",
+		&lnrpc.SendCoinsRequest{SendAll: true},
+	), ErrMethodNotMetered)
+
+	// A payment counts with its fee limit.
+	require.NoError(t, validate(
+		"/routerrpc.Router/SendPaymentV2",
+		&routerrpc.SendPaymentRequest{Amt: 4_000, FeeLimitSat: 1_000},
+	))
+	require.NoError(t, validate(
+		"/lnrpc.Lightning/SendCoins",
+		&lnrpc.SendCoinsRequest{Amount: 3_000},
+	))
+
+	// The limit is exceeded by a channel opening that would spend more
+	// than what is left of it.
+	openReq := &lnrpc.OpenChannelRequest{LocalFundingAmount: 2_001}
+	require.ErrorIs(t, validate(
+		"/lnrpc.Lightning/OpenChannelSync", openReq,
+	), ErrMaxAmountExceeded)
+
+	// The limit is per macaroon.
+	otherCtx := macaroonContext(t, "other")
+	require.NoError(t, validator.ValidateCaveat(
+		otherCtx, "/lnrpc.Lightning/OpenChannelSync", "10000", openReq,
+	))
+
+	// What is left of the limit can still be spent.
+	openReq.LocalFundingAmount = 2_000
+	require.NoError(t, validate(
+		"/lnrpc.Lightning/OpenChannelSync", openReq,
+	))
+	require.ErrorIs(t, validate(
+		"/lnrpc.Lightning/SendCoins",
+		&lnrpc.SendCoinsRequest{Amount: 1},
+	), ErrMaxAmountExceeded)
+
+	// Only the fee limit of a rebalance is spent, and querying its route
+	// spends nothing.
+	rebalanceReq := &routerrpc.RebalanceRequest{
+		AmtSat:      5_000,
+		FeeLimitSat: 1,
+		QueryOnly:   true,
+	}
+	require.NoError(t, validate(
+		"/routerrpc.Router/Rebalance", rebalanceReq,
+	))
+	rebalanceReq.QueryOnly = false
+	require.ErrorIs(t, validate(
+		"/routerrpc.Router/Rebalance", rebalanceReq,
+	), ErrMaxAmountExceeded)
+
+	// Spends are forgotten once they're older than a day, also across
+	// restarts.
+	testClock.SetTime(time.Unix(1000, 0).Add(spendWindow + time.Second))
+	validator, err = NewMaxAmountValidator(
+		db, &chaincfg.RegressionNetParams, testClock, testPermissions,
+	)
+	require.NoError(t, err)
+	require.NoError(t, validate(
+		"/lnrpc.Lightning/SendMany", &lnrpc.SendManyRequest{
+			AddrToAmount: map[string]int64{"a": 6_000, "b": 4_000},
+		},
+	))
+	require.ErrorIs(t, validate(
+		"/lnrpc.Lightning/SendToRouteSync", &lnrpc.SendToRouteRequest{
+			Route: &lnrpc.Route{TotalAmtMsat: 1},
+		},
+	), ErrMaxAmountExceeded)
+
+	// Invalid conditions are rejected.
+	require.Error(t, validator.ValidateCaveat(
+		ctx, "/lnrpc.Lightning/GetInfo", "-1", nil,
+	))
+}
+
+// TestMaxAmountValidatorMethods tests that batched payments and channel
+// closes are metered, and that methods that may spend funds without being
+// metered are rejected.
+func TestMaxAmountValidatorMethods(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "caveats")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	validator, err := NewMaxAmountValidator(
+		db, &chaincfg.RegressionNetParams,
+		clock.NewTestClock(time.Unix(1000, 0)), testPermissions,
+	)
+	require.NoError(t, err)
+
+	ctx := macaroonContext(t, "mac")
+	validate := func(method string, req interface{}) error {
+		return validator.ValidateCaveat(ctx, method, "10000", req)
+	}
+
+	// Methods that change off-chain state without spending funds are
+	// accepted.
+	require.NoError(t, validate(
+		"/lnrpc.Lightning/UpdateChannelPolicy",
+		&lnrpc.PolicyUpdateRequest{},
+	))
+
+	// Methods that may spend funds but aren't metered are rejected, also
+	// when establishing a stream.
+	require.ErrorIs(t, validate(
+		"/routerrpc.Router/HtlcInterceptor", nil,
+	), ErrMethodNotMetered)
+	require.ErrorIs(t, validate(
+		"/routerrpc.Router/HtlcInterceptor",
+		&routerrpc.ForwardHtlcInterceptResponse{},
+	), ErrMethodNotMetered)
+
+	// A cooperative close to our wallet spends nothing, while a close to
+	// an external delivery can't be metered.
+	require.NoError(t, validate(
+		"/lnrpc.Lightning/CloseChannel", &lnrpc.CloseChannelRequest{},
+	))
+	require.ErrorIs(t, validate(
+		"/lnrpc.Lightning/CloseChannel", &lnrpc.CloseChannelRequest{
+			DeliveryAddress: "bcrt1qexternal",
+		},
+	), ErrMethodNotMetered)
+	require.ErrorIs(t, validate(
+		"/lnrpc.Lightning/CloseChannel", &lnrpc.CloseChannelRequest{
+			DeliveryPsbt: []byte{1},
+		},
+	), ErrMethodNotMetered)
+
+	// Every payment of a batch counts with its fee limit.
+	batchReq := &routerrpc.SendPaymentsRequest{
+		Payments: []*routerrpc.SendPaymentRequest{{
+			Amt:         3_000,
+			FeeLimitSat: 500,
+		}, {
+			Amt:         5_000,
+			FeeLimitSat: 500,
+		}},
+	}
+	require.NoError(t, validate("/routerrpc.Router/SendPayments", batchReq))
+	require.ErrorIs(t, validate(
+		"/routerrpc.Router/SendPayments", batchReq,
+	), ErrMaxAmountExceeded)
+
+	// The batch above left 1000 sat of the limit.
+	batchReq.Payments = batchReq.Payments[:1]
+	batchReq.Payments[0].Amt = 500
+	require.NoError(t, validate("/routerrpc.Router/SendPayments", batchReq))
+	require.ErrorIs(t, validate(
+		"/routerrpc.Router/SendPayments", batchReq,
+	), ErrMaxAmountExceeded)
+}
+
+// TestMaxAmountPerDayConstraint tests that the constraint adds the caveat
+// with the maximum amount to the macaroon.
+func TestMaxAmountPerDayConstraint(t *testing.T) {
+	t.Parallel()
+
+	mac := newTestMacaroon(t, "id")
+	require.NoError(t, MaxAmountPerDayConstraint(5_000)(mac))
+	require.Equal(t, "5000", macaroons.GetCustomCaveatCondition(
+		mac, MaxAmountPerDayName,
+	))
+}
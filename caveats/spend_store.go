@@ -0,0 +1,124 @@
+package caveats
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// spendsBucket is the top level bucket of the amounts spent with
+	// macaroons that have a maximum amount per day. It holds a sub bucket
+	// per macaroon, keyed by the macaroon ID.
+	//
+	// caveat-spends
+	//   |-- <macaroon-id>
+	//         |-- <seq>: <timestamp><amount>
+	spendsBucket = []byte("caveat-spends")
+
+	// byteOrder is the byte order of the serialized spends.
+	byteOrder = binary.BigEndian
+
+	// ErrMaxAmountExceeded is returned if a request would spend more than
+	// the maximum amount per day of a macaroon.
+	ErrMaxAmountExceeded = errors.New("maximum amount per day exceeded")
+)
+
+// spendStore persists the amounts spent with macaroons that have a maximum
+// amount per day, so that the limit holds across restarts.
+type spendStore struct {
+	db    kvdb.Backend
+	clock clock.Clock
+}
+
+// newSpendStore returns a new spend store that is backed by the database.
+func newSpendStore(db kvdb.Backend, clock clock.Clock) (*spendStore, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(spendsBucket)
+
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &spendStore{
+		db:    db,
+		clock: clock,
+	}, nil
+}
+
+// spend records the amount as spent with the macaroon, unless the amounts
+// spent with it within the window would exceed the limit. Spends that are
+// older than the window are removed.
+func (s *spendStore) spend(macID []byte, amt, limit lnwire.MilliSatoshi,
+	window time.Duration) error {
+
+	now := s.clock.Now()
+	cutoff := now.Add(-window)
+
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		spends := tx.ReadWriteBucket(spendsBucket)
+		bucket, err := spends.CreateBucketIfNotExists(macID)
+		if err != nil {
+			return err
+		}
+
+		var (
+			spent lnwire.MilliSatoshi
+			stale [][]byte
+		)
+		err = bucket.ForEach(func(k, v []byte) error {
+			if len(v) != 16 {
+				return fmt.Errorf("invalid spend of length %d",
+					len(v))
+			}
+
+			timestamp := time.Unix(
+				0, int64(byteOrder.Uint64(v[:8])),
+			)
+			if timestamp.Before(cutoff) {
+				stale = append(stale, k)
+				return nil
+			}
+
+			spent += lnwire.MilliSatoshi(byteOrder.Uint64(v[8:]))
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, k := range stale {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		if amt > limit || spent > limit-amt {
+			return fmt.Errorf("%w: spending %v would exceed %v with "+
+				"%v spent", ErrMaxAmountExceeded, amt, limit,
+				spent)
+		}
+
+		seq, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var k [8]byte
+		byteOrder.PutUint64(k[:], seq)
+
+		var v [16]byte
+		byteOrder.PutUint64(v[:8], uint64(now.UnixNano()))
+		byteOrder.PutUint64(v[8:], uint64(amt))
+
+		return bucket.Put(k[:], v[:])
+	}, func() {})
+}
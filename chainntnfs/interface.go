@@ -856,3 +856,43 @@ type MempoolWatcher interface {
 	// found.
 	LookupInputMempoolSpend(op wire.OutPoint) fn.Option[wire.MsgTx]
 }
+
+// MempoolEntry describes an unconfirmed tx in the mempool together with the
+// aggregated sizes and fees of its in-mempool ancestors and descendants. The
+// aggregates include the tx itself.
+type MempoolEntry struct {
+	// Txid is the hash of the tx.
+	Txid chainhash.Hash
+
+	// VSize is the virtual size of the tx in vbytes.
+	VSize int64
+
+	// Fee is the fee paid by the tx.
+	Fee btcutil.Amount
+
+	// AncestorVSize is the virtual size of the tx and all its in-mempool
+	// ancestors.
+	AncestorVSize int64
+
+	// AncestorFee is the fee paid by the tx and all its in-mempool
+	// ancestors.
+	AncestorFee btcutil.Amount
+
+	// DescendantVSize is the virtual size of the tx and all its in-mempool
+	// descendants.
+	DescendantVSize int64
+
+	// DescendantFee is the fee paid by the tx and all its in-mempool
+	// descendants.
+	DescendantFee btcutil.Amount
+}
+
+// MempoolInspector defines an interface that allows the caller to query the
+// sizes and fees of the txns in the mempool and of the clusters they belong
+// to.
+type MempoolInspector interface {
+	// LookupMempoolEntry returns the mempool entry of the given tx. A
+	// fn.None is returned if the tx isn't in the mempool.
+	LookupMempoolEntry(txid chainhash.Hash) (fn.Option[MempoolEntry],
+		error)
+}
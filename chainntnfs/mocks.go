@@ -121,3 +121,22 @@ func (m *MockChainNotifier) Stop() error {
 
 	return args.Error(0)
 }
+
+// MockMempoolInspector is a mock implementation of the MempoolInspector
+// interface.
+type MockMempoolInspector struct {
+	mock.Mock
+}
+
+// Compile-time check to ensure MockMempoolInspector implements
+// MempoolInspector.
+var _ MempoolInspector = (*MockMempoolInspector)(nil)
+
+// LookupMempoolEntry returns the mempool entry of the given tx.
+func (m *MockMempoolInspector) LookupMempoolEntry(
+	txid chainhash.Hash) (fn.Option[MempoolEntry], error) {
+
+	args := m.Called(txid)
+
+	return args.Get(0).(fn.Option[MempoolEntry]), args.Error(1)
+}
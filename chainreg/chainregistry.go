@@ -179,6 +179,14 @@ type PartialChainControl struct {
 	// notifications for received funds, etc.
 	ChainSource chain.Interface
 
+	// PackageSubmitter is used to broadcast transactions as packages, if
+	// the chain backend supports package relay.
+	PackageSubmitter fn.Option[lnwallet.PackageSubmitter]
+
+	// MempoolInspector is used to query the fees of the txns in the
+	// mempool, if the chain backend supports it.
+	MempoolInspector fn.Option[chainntnfs.MempoolInspector]
+
 	// RoutingPolicy is the routing policy we have decided to use.
 	RoutingPolicy models.ForwardingPolicy
 
@@ -518,6 +526,25 @@ func NewPartialChainControl(cfg *Config) (*PartialChainControl, func(), error) {
 			}
 		}
 
+		// Starting with version 28.0, bitcoind relays packages of a
+		// parent and a child, which allows us to fee bump commitment
+		// transactions that pay too little fees to enter the mempool
+		// on their own.
+		if ver >= minPackageRelayVersion {
+			log.Infof("Using package relay of bitcoind")
+
+			cc.PackageSubmitter = fn.Some(
+				newBitcoindPackageSubmitter(chainConn),
+			)
+		}
+
+		// The mempool entries of bitcoind expose the fees of the
+		// ancestors and descendants of a tx, which the sweeper uses to
+		// outbid conflicting txns and to lift unconfirmed parents.
+		cc.MempoolInspector = fn.Some(
+			newBitcoindMempoolInspector(chainConn),
+		)
+
 		cc.HealthCheck = func() error {
 			_, err := chainConn.RawRequest(cmd, nil)
 			if err != nil {
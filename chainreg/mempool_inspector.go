@@ -0,0 +1,101 @@
+package chainreg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/fn"
+)
+
+// bitcoindMempoolInspector queries the mempool entries of bitcoind using the
+// getmempoolentry RPC.
+type bitcoindMempoolInspector struct {
+	rpc *rpcclient.Client
+}
+
+// newBitcoindMempoolInspector returns a mempool inspector that uses the given
+// bitcoind RPC connection.
+func newBitcoindMempoolInspector(
+	rpc *rpcclient.Client) chainntnfs.MempoolInspector {
+
+	return &bitcoindMempoolInspector{
+		rpc: rpc,
+	}
+}
+
+// LookupMempoolEntry returns the mempool entry of the given tx. A fn.None is
+// returned if the tx isn't in the mempool of bitcoind.
+//
+// NOTE: Part of the chainntnfs.MempoolInspector interface.
+func (b *bitcoindMempoolInspector) LookupMempoolEntry(
+	txid chainhash.Hash) (fn.Option[chainntnfs.MempoolEntry], error) {
+
+	none := fn.None[chainntnfs.MempoolEntry]()
+
+	param, err := json.Marshal(txid.String())
+	if err != nil {
+		return none, err
+	}
+
+	resp, err := b.rpc.RawRequest(
+		"getmempoolentry", []json.RawMessage{param},
+	)
+
+	// bitcoind returns an invalid address or key error if the tx isn't
+	// in its mempool.
+	var rpcErr *btcjson.RPCError
+	if errors.As(err, &rpcErr) &&
+		rpcErr.Code == btcjson.ErrRPCInvalidAddressOrKey {
+
+		return none, nil
+	}
+	if err != nil {
+		return none, fmt.Errorf("getmempoolentry failed: %w", err)
+	}
+
+	result := struct {
+		VSize          int64 `json:"vsize"`
+		AncestorSize   int64 `json:"ancestorsize"`
+		DescendantSize int64 `json:"descendantsize"`
+		Fees           struct {
+			Base       float64 `json:"base"`
+			Ancestor   float64 `json:"ancestor"`
+			Descendant float64 `json:"descendant"`
+		} `json:"fees"`
+	}{}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return none, fmt.Errorf("unable to decode getmempoolentry "+
+			"resp: %w", err)
+	}
+
+	fee, err := btcutil.NewAmount(result.Fees.Base)
+	if err != nil {
+		return none, err
+	}
+
+	ancestorFee, err := btcutil.NewAmount(result.Fees.Ancestor)
+	if err != nil {
+		return none, err
+	}
+
+	descendantFee, err := btcutil.NewAmount(result.Fees.Descendant)
+	if err != nil {
+		return none, err
+	}
+
+	return fn.Some(chainntnfs.MempoolEntry{
+		Txid:            txid,
+		VSize:           result.VSize,
+		Fee:             fee,
+		AncestorVSize:   result.AncestorSize,
+		AncestorFee:     ancestorFee,
+		DescendantVSize: result.DescendantSize,
+		DescendantFee:   descendantFee,
+	}), nil
+}
@@ -0,0 +1,109 @@
+package chainreg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/btcsuite/btcd/rpcclient"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+const (
+	// minPackageRelayVersion is the first bitcoind version that relays
+	// packages of one parent and one child, and accepts a parent below
+	// the minimum mempool fee rate if the child pays for it. This is the
+	// version 28.0 in the format returned by getnetworkinfo.
+	minPackageRelayVersion = 280000
+
+	// packageSuccessMsg is the package message that bitcoind returns if
+	// all transactions of a package were accepted.
+	packageSuccessMsg = "success"
+)
+
+// bitcoindPackageSubmitter submits packages of transactions to bitcoind using
+// the submitpackage RPC.
+type bitcoindPackageSubmitter struct {
+	rpc *rpcclient.Client
+}
+
+// newBitcoindPackageSubmitter returns a package submitter that uses the given
+// bitcoind RPC connection.
+func newBitcoindPackageSubmitter(
+	rpc *rpcclient.Client) lnwallet.PackageSubmitter {
+
+	return &bitcoindPackageSubmitter{
+		rpc: rpc,
+	}
+}
+
+// SubmitPackage submits the given parent transactions together with a child
+// that spends from them to the mempool of bitcoind.
+//
+// NOTE: Part of the lnwallet.PackageSubmitter interface.
+func (b *bitcoindPackageSubmitter) SubmitPackage(parents []*wire.MsgTx,
+	child *wire.MsgTx) error {
+
+	// The package must be sorted topologically, with the child last.
+	txs := make([]*wire.MsgTx, 0, len(parents)+1)
+	txs = append(txs, parents...)
+	txs = append(txs, child)
+
+	rawTxs := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			return err
+		}
+
+		rawTxs = append(rawTxs, hex.EncodeToString(buf.Bytes()))
+	}
+
+	param, err := json.Marshal(rawTxs)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.rpc.RawRequest(
+		"submitpackage", []json.RawMessage{param},
+	)
+	if err != nil {
+		return fmt.Errorf("submitpackage failed: %w", err)
+	}
+
+	result := struct {
+		PackageMsg string `json:"package_msg"`
+		TxResults  map[string]struct {
+			TxID  string `json:"txid"`
+			Error string `json:"error"`
+		} `json:"tx-results"`
+	}{}
+	if err := json.Unmarshal(resp, &result); err != nil {
+		return fmt.Errorf("unable to decode submitpackage resp: %w",
+			err)
+	}
+
+	if result.PackageMsg == packageSuccessMsg {
+		return nil
+	}
+
+	// Collect the reasons the individual transactions were rejected for,
+	// if any, in a stable order.
+	var reasons []string
+	for _, txResult := range result.TxResults {
+		if txResult.Error == "" {
+			continue
+		}
+
+		reasons = append(reasons, fmt.Sprintf("%s: %s", txResult.TxID,
+			txResult.Error))
+	}
+	sort.Strings(reasons)
+
+	return fmt.Errorf("package rejected: %s (%s)", result.PackageMsg,
+		strings.Join(reasons, ", "))
+}
@@ -0,0 +1,210 @@
+package lnd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// newAcceptPolicyChain creates the chain of accept policies for inbound
+// channels from the config. The given acceptor, which multiplexes the
+// acceptors registered over RPC, is evaluated as the rpc policy.
+func (s *server) newAcceptPolicyChain(cfg *Config,
+	rpcAcceptor chanacceptor.ChannelAcceptor) (*chanacceptor.PolicyChain,
+	error) {
+
+	policyCfg := cfg.AcceptPolicy
+
+	chain, err := chanacceptor.NewPolicyChain(
+		policyCfg.MaxDecisions,
+		&peerPolicyAcceptor{
+			policies:      s.peerPolicies,
+			fetchChannels: s.chanStateDB.FetchOpenChannels,
+		},
+		&chanacceptor.MinSizePolicy{
+			MinSize: btcutil.Amount(policyCfg.MinChanSize),
+		},
+		&chanacceptor.PeerReputationPolicy{
+			MinReputation: policyCfg.MinPeerReputation,
+			Reputation:    s.peerReputation,
+		},
+		&chanacceptor.FeeRatePolicy{
+			MaxFeeRate: chainfee.SatPerKVByte(
+				policyCfg.MaxFeeRate * 1000,
+			).FeePerKWeight(),
+			FeeEstimator: s.cc.FeeEstimator,
+		},
+		&chanacceptor.RPCPolicy{
+			ChannelAcceptor: rpcAcceptor,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := chain.SetOrder(policyCfg.Order); err != nil {
+		return nil, err
+	}
+
+	// The built-in policies without parameters start disabled, and can be
+	// enabled at runtime once they're given some.
+	disabled := map[string]bool{
+		chanacceptor.PolicyMinSize:        policyCfg.MinChanSize == 0,
+		chanacceptor.PolicyPeerReputation: !policyCfg.PeerReputation,
+		chanacceptor.PolicyFeeRate:        policyCfg.MaxFeeRate == 0,
+	}
+	for name, disable := range disabled {
+		if !disable {
+			continue
+		}
+
+		if err := chain.SetEnabled(name, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return chain, nil
+}
+
+// peerReputation returns the sum of the forwarding reputations of the open
+// channels with the given peer, and false if we have no channels with it.
+func (s *server) peerReputation(node *btcec.PublicKey) (int64, bool, error) {
+	channels, err := s.chanStateDB.FetchOpenChannels(node)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var reputation int64
+	for _, channel := range channels {
+		reputation += s.htlcSwitch.ChannelReputation(
+			channel.ShortChanID(),
+		).Reputation
+	}
+
+	return reputation, len(channels) > 0, nil
+}
+
+// ListChannelAcceptPolicies returns the channel accept policies in the order
+// they're evaluated.
+func (r *rpcServer) ListChannelAcceptPolicies(_ context.Context,
+	_ *lnrpc.ListChannelAcceptPoliciesRequest) (
+	*lnrpc.ListChannelAcceptPoliciesResponse, error) {
+
+	resp := &lnrpc.ListChannelAcceptPoliciesResponse{}
+	for _, policy := range r.server.acceptPolicies.Policies() {
+		resp.Policies = append(
+			resp.Policies, &lnrpc.ChannelAcceptPolicy{
+				Name:    policy.Name,
+				Enabled: policy.Enabled,
+			},
+		)
+	}
+
+	return resp, nil
+}
+
+// UpdateChannelAcceptPolicies changes the channel accept policies at runtime.
+// Changing the parameters of a policy doesn't enable it. The changes are lost
+// on restart.
+func (r *rpcServer) UpdateChannelAcceptPolicies(_ context.Context,
+	req *lnrpc.UpdateChannelAcceptPoliciesRequest) (
+	*lnrpc.UpdateChannelAcceptPoliciesResponse, error) {
+
+	chain := r.server.acceptPolicies
+
+	if req.MinChanSizeSat != nil {
+		err := chain.SetPolicy(&chanacceptor.MinSizePolicy{
+			MinSize: btcutil.Amount(*req.MinChanSizeSat),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.MinPeerReputationMsat != nil {
+		err := chain.SetPolicy(&chanacceptor.PeerReputationPolicy{
+			MinReputation: *req.MinPeerReputationMsat,
+			Reputation:    r.server.peerReputation,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.MaxFeeRateSatPerVbyte != nil {
+		maxFeeRate := chainfee.SatPerKVByte(
+			*req.MaxFeeRateSatPerVbyte * 1000,
+		)
+		err := chain.SetPolicy(&chanacceptor.FeeRatePolicy{
+			MaxFeeRate:   maxFeeRate.FeePerKWeight(),
+			FeeEstimator: r.server.cc.FeeEstimator,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(req.Order) > 0 {
+		if err := chain.SetOrder(req.Order); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range req.Enable {
+		if err := chain.SetEnabled(name, true); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range req.Disable {
+		if err := chain.SetEnabled(name, false); err != nil {
+			return nil, err
+		}
+	}
+
+	return &lnrpc.UpdateChannelAcceptPoliciesResponse{}, nil
+}
+
+// ListChannelAcceptDecisions returns the most recent decisions of the channel
+// accept policies, oldest first.
+func (r *rpcServer) ListChannelAcceptDecisions(_ context.Context,
+	_ *lnrpc.ListChannelAcceptDecisionsRequest) (
+	*lnrpc.ListChannelAcceptDecisionsResponse, error) {
+
+	decisions := r.server.acceptPolicies.Decisions()
+	if len(decisions) == 0 && r.cfg.AcceptPolicy.MaxDecisions == 0 {
+		return nil, errors.New("no decisions kept, set " +
+			"acceptpolicy.maxdecisions to keep them")
+	}
+
+	resp := &lnrpc.ListChannelAcceptDecisionsResponse{}
+	for _, decision := range decisions {
+		resp.Decisions = append(
+			resp.Decisions, marshallAcceptDecision(decision),
+		)
+	}
+
+	return resp, nil
+}
+
+// marshallAcceptDecision converts a decision of the channel accept policies
+// to its RPC representation.
+func marshallAcceptDecision(
+	decision chanacceptor.PolicyDecision) *lnrpc.ChannelAcceptDecision {
+
+	return &lnrpc.ChannelAcceptDecision{
+		Timestamp:     decision.Timestamp.Unix(),
+		PendingChanId: decision.PendingChanID[:],
+		NodePubkey:    decision.Node[:],
+		FundingAmt:    uint64(decision.FundingAmt),
+		Evaluated:     decision.Evaluated,
+		Accepted:      decision.Accepted,
+		RejectedBy:    decision.RejectedBy,
+		Reason:        decision.Reason,
+	}
+}
@@ -0,0 +1,96 @@
+package lnd
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateChannelAcceptPolicies tests that the channel accept policies can
+// be changed over RPC, and that the decisions of the changed policies are
+// listed.
+func TestUpdateChannelAcceptPolicies(t *testing.T) {
+	t.Parallel()
+
+	chain, err := chanacceptor.NewPolicyChain(
+		5, &chanacceptor.MinSizePolicy{}, &chanacceptor.FeeRatePolicy{},
+	)
+	require.NoError(t, err)
+	require.NoError(t, chain.SetEnabled(chanacceptor.PolicyFeeRate, false))
+
+	r := &rpcServer{
+		cfg: &Config{
+			AcceptPolicy: &lncfg.AcceptPolicy{MaxDecisions: 5},
+		},
+		server: &server{
+			acceptPolicies: chain,
+		},
+	}
+
+	// An order that doesn't name all policies is rejected.
+	_, err = r.UpdateChannelAcceptPolicies(
+		nil, &lnrpc.UpdateChannelAcceptPoliciesRequest{
+			Order: []string{chanacceptor.PolicyFeeRate},
+		},
+	)
+	require.Error(t, err)
+
+	minChanSize := uint64(100_000)
+	_, err = r.UpdateChannelAcceptPolicies(
+		nil, &lnrpc.UpdateChannelAcceptPoliciesRequest{
+			Order: []string{
+				chanacceptor.PolicyFeeRate,
+				chanacceptor.PolicyMinSize,
+			},
+			MinChanSizeSat: &minChanSize,
+		},
+	)
+	require.NoError(t, err)
+
+	policies, err := r.ListChannelAcceptPolicies(
+		nil, &lnrpc.ListChannelAcceptPoliciesRequest{},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []*lnrpc.ChannelAcceptPolicy{{
+		Name: chanacceptor.PolicyFeeRate,
+	}, {
+		Name:    chanacceptor.PolicyMinSize,
+		Enabled: true,
+	}}, policies.Policies)
+
+	// A channel below the new minimum size is rejected by the minsize
+	// policy, which is the only one evaluated.
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	resp := chain.Accept(&chanacceptor.ChannelAcceptRequest{
+		Node: priv.PubKey(),
+		OpenChanMsg: &lnwire.OpenChannel{
+			PendingChannelID: [32]byte{1},
+			FundingAmount:    50_000,
+		},
+	})
+	require.True(t, resp.RejectChannel())
+
+	decisions, err := r.ListChannelAcceptDecisions(
+		nil, &lnrpc.ListChannelAcceptDecisionsRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, decisions.Decisions, 1)
+
+	decision := decisions.Decisions[0]
+	require.False(t, decision.Accepted)
+	require.Equal(t, chanacceptor.PolicyMinSize, decision.RejectedBy)
+	require.Equal(
+		t, []string{chanacceptor.PolicyMinSize}, decision.Evaluated,
+	)
+	require.EqualValues(t, 50_000, decision.FundingAmt)
+	require.Equal(
+		t, priv.PubKey().SerializeCompressed(), decision.NodePubkey,
+	)
+}
@@ -0,0 +1,174 @@
+package chanacceptor
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+const (
+	// PolicyMinSize is the name of the MinSizePolicy.
+	PolicyMinSize = "minsize"
+
+	// PolicyPeerReputation is the name of the PeerReputationPolicy.
+	PolicyPeerReputation = "reputation"
+
+	// PolicyFeeRate is the name of the FeeRatePolicy.
+	PolicyFeeRate = "feerate"
+
+	// PolicyRPC is the name of the policy that asks the acceptors
+	// registered over RPC.
+	PolicyRPC = "rpc"
+
+	// feeRateConfTarget is the conf target used to estimate the on-chain
+	// fee rate for the FeeRatePolicy.
+	feeRateConfTarget = 6
+)
+
+// acceptResponse returns a response that accepts the channel without setting
+// any of its parameters.
+func acceptResponse() *ChannelAcceptResponse {
+	return NewChannelAcceptResponse(
+		true, nil, nil, 0, 0, 0, 0, 0, 0, false,
+	)
+}
+
+// rejectResponse returns a response that rejects the channel with the given
+// error.
+func rejectResponse(err error) *ChannelAcceptResponse {
+	return NewChannelAcceptResponse(
+		false, err, nil, 0, 0, 0, 0, 0, 0, false,
+	)
+}
+
+// MinSizePolicy rejects channels smaller than a minimum size.
+type MinSizePolicy struct {
+	// MinSize is the smallest channel size that's accepted.
+	MinSize btcutil.Amount
+}
+
+// Name returns the name of the policy.
+//
+// NOTE: Part of the Policy interface.
+func (m *MinSizePolicy) Name() string {
+	return PolicyMinSize
+}
+
+// Accept rejects the channel if it's smaller than the minimum size.
+//
+// NOTE: Part of the ChannelAcceptor interface.
+func (m *MinSizePolicy) Accept(
+	req *ChannelAcceptRequest) *ChannelAcceptResponse {
+
+	if req.OpenChanMsg.FundingAmount < m.MinSize {
+		return rejectResponse(fmt.Errorf("channel size %v below "+
+			"minimum %v", req.OpenChanMsg.FundingAmount,
+			m.MinSize))
+	}
+
+	return acceptResponse()
+}
+
+// PeerReputationPolicy rejects channels of peers whose existing channels have
+// a poor forwarding reputation. Peers without any channels are accepted.
+type PeerReputationPolicy struct {
+	// MinReputation is the lowest reputation in milli-satoshis that's
+	// accepted.
+	MinReputation int64
+
+	// Reputation returns the reputation of the given peer in
+	// milli-satoshis, and false if the peer has no reputation yet.
+	Reputation func(node *btcec.PublicKey) (int64, bool, error)
+}
+
+// Name returns the name of the policy.
+//
+// NOTE: Part of the Policy interface.
+func (r *PeerReputationPolicy) Name() string {
+	return PolicyPeerReputation
+}
+
+// Accept rejects the channel if the peer's reputation is below the minimum.
+//
+// NOTE: Part of the ChannelAcceptor interface.
+func (r *PeerReputationPolicy) Accept(
+	req *ChannelAcceptRequest) *ChannelAcceptResponse {
+
+	reputation, ok, err := r.Reputation(req.Node)
+	if err != nil {
+		log.Errorf("Unable to fetch reputation of %x: %v",
+			req.Node.SerializeCompressed(), err)
+
+		return rejectResponse(nil)
+	}
+
+	if ok && reputation < r.MinReputation {
+		return rejectResponse(fmt.Errorf("peer reputation %d below "+
+			"minimum %d", reputation, r.MinReputation))
+	}
+
+	return acceptResponse()
+}
+
+// FeeRatePolicy rejects channels while the on-chain fee rate is above a
+// maximum, as closing them would be costly.
+type FeeRatePolicy struct {
+	// MaxFeeRate is the highest on-chain fee rate at which channels are
+	// accepted.
+	MaxFeeRate chainfee.SatPerKWeight
+
+	// FeeEstimator estimates the current on-chain fee rate.
+	FeeEstimator chainfee.Estimator
+}
+
+// Name returns the name of the policy.
+//
+// NOTE: Part of the Policy interface.
+func (f *FeeRatePolicy) Name() string {
+	return PolicyFeeRate
+}
+
+// Accept rejects the channel if the on-chain fee rate is above the maximum.
+//
+// NOTE: Part of the ChannelAcceptor interface.
+func (f *FeeRatePolicy) Accept(
+	req *ChannelAcceptRequest) *ChannelAcceptResponse {
+
+	feeRate, err := f.FeeEstimator.EstimateFeePerKW(feeRateConfTarget)
+	if err != nil {
+		log.Errorf("Unable to estimate fee rate: %v", err)
+
+		return rejectResponse(nil)
+	}
+
+	if feeRate > f.MaxFeeRate {
+		return rejectResponse(fmt.Errorf("on-chain fee rate %v above "+
+			"maximum %v", feeRate, f.MaxFeeRate))
+	}
+
+	return acceptResponse()
+}
+
+// RPCPolicy asks the acceptors that are registered over RPC, which are
+// multiplexed by the wrapped acceptor.
+type RPCPolicy struct {
+	ChannelAcceptor
+}
+
+// Name returns the name of the policy.
+//
+// NOTE: Part of the Policy interface.
+func (r *RPCPolicy) Name() string {
+	return PolicyRPC
+}
+
+// A compile-time constraint to ensure the built-in policies implement the
+// Policy interface.
+var (
+	_ Policy = (*MinSizePolicy)(nil)
+	_ Policy = (*PeerReputationPolicy)(nil)
+	_ Policy = (*FeeRatePolicy)(nil)
+	_ Policy = (*RPCPolicy)(nil)
+)
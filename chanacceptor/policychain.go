@@ -0,0 +1,314 @@
+package chanacceptor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// DefaultMaxPolicyDecisions is the default number of decisions a PolicyChain
+// keeps for inspection.
+const DefaultMaxPolicyDecisions = 100
+
+// Policy is a named ChannelAcceptor that can be evaluated as part of a
+// PolicyChain.
+type Policy interface {
+	ChannelAcceptor
+
+	// Name returns the name of the policy, which is unique within its
+	// chain.
+	Name() string
+}
+
+// PolicyStatus describes a policy of a PolicyChain.
+type PolicyStatus struct {
+	// Name is the name of the policy.
+	Name string
+
+	// Enabled is true if the policy is evaluated.
+	Enabled bool
+}
+
+// PolicyDecision records the outcome of a channel open request that was
+// evaluated by a PolicyChain.
+type PolicyDecision struct {
+	// Timestamp is the time the request was evaluated.
+	Timestamp time.Time
+
+	// PendingChanID is the pending channel ID of the request.
+	PendingChanID [32]byte
+
+	// Node is the compressed public key of the node requesting the
+	// channel.
+	Node [33]byte
+
+	// FundingAmt is the size of the requested channel.
+	FundingAmt btcutil.Amount
+
+	// Evaluated are the names of the policies that were evaluated, in
+	// order.
+	Evaluated []string
+
+	// Accepted is true if the channel was accepted.
+	Accepted bool
+
+	// RejectedBy is the name of the policy that rejected the channel.
+	RejectedBy string
+
+	// Reason is the reason the channel was rejected.
+	Reason string
+}
+
+// PolicyChain is a channel acceptor that evaluates an ordered chain of
+// policies. The first policy that rejects a channel decides, while the
+// responses of the accepting policies are merged like the ones of the
+// ChainedAcceptor. The policies can be reordered, replaced, enabled and
+// disabled at runtime, and the most recent decisions are kept for inspection.
+type PolicyChain struct {
+	mu sync.RWMutex
+
+	// policies are the policies of the chain in the order they're
+	// evaluated.
+	policies []Policy
+
+	// disabled holds the names of the policies that are skipped.
+	disabled map[string]struct{}
+
+	// decisions are the most recent decisions, oldest first.
+	decisions    []*PolicyDecision
+	maxDecisions int
+}
+
+// NewPolicyChain creates a PolicyChain that evaluates the given policies in
+// order and keeps the given number of decisions.
+func NewPolicyChain(maxDecisions int,
+	policies ...Policy) (*PolicyChain, error) {
+
+	names := make(map[string]struct{}, len(policies))
+	for _, policy := range policies {
+		if _, ok := names[policy.Name()]; ok {
+			return nil, fmt.Errorf("duplicate policy %v",
+				policy.Name())
+		}
+		names[policy.Name()] = struct{}{}
+	}
+
+	return &PolicyChain{
+		policies:     policies,
+		disabled:     make(map[string]struct{}),
+		maxDecisions: maxDecisions,
+	}, nil
+}
+
+// Accept evaluates the enabled policies in order, and rejects the channel if
+// any of them does.
+//
+// NOTE: Part of the ChannelAcceptor interface.
+func (p *PolicyChain) Accept(req *ChannelAcceptRequest) *ChannelAcceptResponse {
+	p.mu.RLock()
+	policies := make([]Policy, 0, len(p.policies))
+	for _, policy := range p.policies {
+		if _, ok := p.disabled[policy.Name()]; !ok {
+			policies = append(policies, policy)
+		}
+	}
+	p.mu.RUnlock()
+
+	decision := &PolicyDecision{
+		Timestamp:     time.Now(),
+		PendingChanID: req.OpenChanMsg.PendingChannelID,
+		FundingAmt:    req.OpenChanMsg.FundingAmount,
+	}
+	if req.Node != nil {
+		copy(decision.Node[:], req.Node.SerializeCompressed())
+	}
+
+	resp := p.evaluate(req, policies, decision)
+	p.recordDecision(decision)
+
+	return resp
+}
+
+// evaluate evaluates the given policies in order and fills in the decision.
+func (p *PolicyChain) evaluate(req *ChannelAcceptRequest, policies []Policy,
+	decision *PolicyDecision) *ChannelAcceptResponse {
+
+	var finalResp ChannelAcceptResponse
+	for _, policy := range policies {
+		decision.Evaluated = append(decision.Evaluated, policy.Name())
+
+		resp := policy.Accept(req)
+		if resp.RejectChannel() {
+			decision.RejectedBy = policy.Name()
+			decision.Reason = resp.Error()
+
+			log.Infof("Policy %v rejected channel %x from %x: %v",
+				policy.Name(), decision.PendingChanID[:],
+				decision.Node[:], resp.Error())
+
+			return resp
+		}
+
+		log.Debugf("Policy %v accepted channel %x from %x",
+			policy.Name(), decision.PendingChanID[:],
+			decision.Node[:])
+
+		var err error
+		finalResp, err = mergeResponse(finalResp, *resp)
+		if err != nil {
+			log.Errorf("response of policy %v for %x has "+
+				"inconsistent values: %v", policy.Name(),
+				decision.PendingChanID[:], err)
+
+			decision.RejectedBy = policy.Name()
+			decision.Reason = err.Error()
+
+			return NewChannelAcceptResponse(
+				false, errChannelRejected, nil, 0, 0,
+				0, 0, 0, 0, false,
+			)
+		}
+	}
+
+	decision.Accepted = true
+
+	log.Infof("Accepted channel %x of %v from %x", decision.PendingChanID[:],
+		decision.FundingAmt, decision.Node[:])
+
+	return &finalResp
+}
+
+// recordDecision keeps the given decision, dropping the oldest one if the
+// chain keeps too many.
+func (p *PolicyChain) recordDecision(decision *PolicyDecision) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.maxDecisions <= 0 {
+		return
+	}
+
+	p.decisions = append(p.decisions, decision)
+	if len(p.decisions) > p.maxDecisions {
+		p.decisions = p.decisions[len(p.decisions)-p.maxDecisions:]
+	}
+}
+
+// Decisions returns the most recent decisions of the chain, oldest first.
+func (p *PolicyChain) Decisions() []PolicyDecision {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	decisions := make([]PolicyDecision, 0, len(p.decisions))
+	for _, decision := range p.decisions {
+		decisions = append(decisions, *decision)
+	}
+
+	return decisions
+}
+
+// Policies returns the policies of the chain in the order they're evaluated.
+func (p *PolicyChain) Policies() []PolicyStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	statuses := make([]PolicyStatus, 0, len(p.policies))
+	for _, policy := range p.policies {
+		_, disabled := p.disabled[policy.Name()]
+		statuses = append(statuses, PolicyStatus{
+			Name:    policy.Name(),
+			Enabled: !disabled,
+		})
+	}
+
+	return statuses
+}
+
+// SetOrder reorders the policies of the chain. The given names must name all
+// policies of the chain exactly once.
+func (p *PolicyChain) SetOrder(names []string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(names) != len(p.policies) {
+		return fmt.Errorf("expected %d policies, got %d",
+			len(p.policies), len(names))
+	}
+
+	byName := make(map[string]Policy, len(p.policies))
+	for _, policy := range p.policies {
+		byName[policy.Name()] = policy
+	}
+
+	policies := make([]Policy, 0, len(names))
+	for _, name := range names {
+		policy, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("unknown or duplicate policy %v",
+				name)
+		}
+		delete(byName, name)
+
+		policies = append(policies, policy)
+	}
+	p.policies = policies
+
+	log.Infof("Channel accept policy order set to %v", names)
+
+	return nil
+}
+
+// SetEnabled enables or disables the policy with the given name.
+func (p *PolicyChain) SetEnabled(name string, enabled bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.index(name) < 0 {
+		return fmt.Errorf("unknown policy %v", name)
+	}
+
+	if enabled {
+		delete(p.disabled, name)
+	} else {
+		p.disabled[name] = struct{}{}
+	}
+
+	log.Infof("Channel accept policy %v enabled=%v", name, enabled)
+
+	return nil
+}
+
+// SetPolicy replaces the policy of the same name, keeping its position in the
+// chain. This allows the parameters of a policy to be changed at runtime.
+func (p *PolicyChain) SetPolicy(policy Policy) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := p.index(policy.Name())
+	if i < 0 {
+		return fmt.Errorf("unknown policy %v", policy.Name())
+	}
+	p.policies[i] = policy
+
+	log.Infof("Channel accept policy %v updated", policy.Name())
+
+	return nil
+}
+
+// index returns the position of the policy with the given name in the chain,
+// or -1 if there's none. The caller must hold the mutex.
+func (p *PolicyChain) index(name string) int {
+	for i, policy := range p.policies {
+		if policy.Name() == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// A compile-time constraint to ensure PolicyChain implements the
+// ChannelAcceptor interface.
+var _ ChannelAcceptor = (*PolicyChain)(nil)
@@ -0,0 +1,174 @@
+package chanacceptor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPolicy is a Policy that counts its evaluations and returns a canned
+// response.
+type countingPolicy struct {
+	name  string
+	resp  *ChannelAcceptResponse
+	calls int
+}
+
+func (c *countingPolicy) Name() string {
+	return c.name
+}
+
+func (c *countingPolicy) Accept(
+	req *ChannelAcceptRequest) *ChannelAcceptResponse {
+
+	c.calls++
+
+	return c.resp
+}
+
+// TestPolicyChain asserts that the policy chain evaluates its enabled policies
+// in order, stops at the first rejection, and records its decisions.
+func TestPolicyChain(t *testing.T) {
+	t.Parallel()
+
+	first := &countingPolicy{
+		name: "first",
+		resp: NewChannelAcceptResponse(
+			true, nil, nil, 144, 0, 0, 0, 0, 0, false,
+		),
+	}
+	second := &countingPolicy{
+		name: "second",
+		resp: rejectResponse(errors.New("too small")),
+	}
+
+	_, err := NewPolicyChain(10, first, first)
+	require.Error(t, err)
+
+	chain, err := NewPolicyChain(2, first, second)
+	require.NoError(t, err)
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	req := &ChannelAcceptRequest{
+		Node: priv.PubKey(),
+		OpenChanMsg: &lnwire.OpenChannel{
+			PendingChannelID: [32]byte{1},
+			FundingAmount:    100_000,
+		},
+	}
+
+	// The second policy rejects the channel after the first accepted it.
+	resp := chain.Accept(req)
+	require.True(t, resp.RejectChannel())
+	require.Equal(t, 1, first.calls)
+	require.Equal(t, 1, second.calls)
+
+	// Once the rejecting policy comes first, the other one isn't asked.
+	require.Error(t, chain.SetOrder([]string{"second"}))
+	require.Error(t, chain.SetOrder([]string{"second", "second"}))
+	require.NoError(t, chain.SetOrder([]string{"second", "first"}))
+
+	resp = chain.Accept(req)
+	require.True(t, resp.RejectChannel())
+	require.Equal(t, 1, first.calls)
+	require.Equal(t, 2, second.calls)
+
+	// Disabled policies are skipped, and the responses of the accepting
+	// policies are merged.
+	require.Error(t, chain.SetEnabled("unknown", false))
+	require.NoError(t, chain.SetEnabled("second", false))
+	require.Equal(t, []PolicyStatus{
+		{Name: "second", Enabled: false},
+		{Name: "first", Enabled: true},
+	}, chain.Policies())
+
+	resp = chain.Accept(req)
+	require.False(t, resp.RejectChannel())
+	require.EqualValues(t, 144, resp.CSVDelay)
+	require.Equal(t, 2, first.calls)
+	require.Equal(t, 2, second.calls)
+
+	// Only the most recent decisions are kept.
+	decisions := chain.Decisions()
+	require.Len(t, decisions, 2)
+
+	require.False(t, decisions[0].Accepted)
+	require.Equal(t, "second", decisions[0].RejectedBy)
+	require.Equal(t, "too small", decisions[0].Reason)
+	require.Equal(t, []string{"second"}, decisions[0].Evaluated)
+
+	require.True(t, decisions[1].Accepted)
+	require.Empty(t, decisions[1].RejectedBy)
+	require.Equal(t, []string{"first"}, decisions[1].Evaluated)
+	require.Equal(t, req.OpenChanMsg.PendingChannelID,
+		decisions[1].PendingChanID)
+	require.Equal(t, req.OpenChanMsg.FundingAmount,
+		decisions[1].FundingAmt)
+
+	// A policy can be replaced at runtime, keeping its position.
+	require.Error(t, chain.SetPolicy(&MinSizePolicy{}))
+	require.NoError(t, chain.SetPolicy(&countingPolicy{
+		name: "first",
+		resp: rejectResponse(nil),
+	}))
+	require.True(t, chain.Accept(req).RejectChannel())
+	require.Equal(t, 2, first.calls)
+}
+
+// TestBuiltinPolicies asserts that the built-in policies reject channels by
+// their size, the reputation of the peer and the on-chain fee rate.
+func TestBuiltinPolicies(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	req := &ChannelAcceptRequest{
+		Node: priv.PubKey(),
+		OpenChanMsg: &lnwire.OpenChannel{
+			FundingAmount: 100_000,
+		},
+	}
+
+	minSize := &MinSizePolicy{MinSize: 100_000}
+	require.False(t, minSize.Accept(req).RejectChannel())
+
+	minSize.MinSize = 100_001
+	require.True(t, minSize.Accept(req).RejectChannel())
+
+	var (
+		reputation int64
+		known      bool
+	)
+	peerReputation := &PeerReputationPolicy{
+		MinReputation: 0,
+		Reputation: func(*btcec.PublicKey) (int64, bool, error) {
+			return reputation, known, nil
+		},
+	}
+
+	// Peers without reputation are accepted.
+	reputation = -1
+	require.False(t, peerReputation.Accept(req).RejectChannel())
+
+	known = true
+	require.True(t, peerReputation.Accept(req).RejectChannel())
+
+	reputation = 0
+	require.False(t, peerReputation.Accept(req).RejectChannel())
+
+	feeRate := &FeeRatePolicy{
+		MaxFeeRate:   2500,
+		FeeEstimator: chainfee.NewStaticEstimator(2500, 0),
+	}
+	require.False(t, feeRate.Accept(req).RejectChannel())
+
+	feeRate.MaxFeeRate = 2499
+	require.True(t, feeRate.Accept(req).RejectChannel())
+}
@@ -250,6 +250,37 @@ func (p *peerLog) channelUptime(channelPoint wire.OutPoint) (time.Duration,
 	return now.Sub(channel.openedAt), uptime, nil
 }
 
+// channelUptimeSince returns the amount of time the channel has been monitored
+// for since the given time, and the uptime of its peer over this period.
+func (p *peerLog) channelUptimeSince(channelPoint wire.OutPoint,
+	since time.Time) (time.Duration, time.Duration, error) {
+
+	channel, ok := p.channels[channelPoint]
+	if !ok {
+		return 0, 0, ErrChannelNotFound
+	}
+
+	now := p.clock.Now()
+
+	start := since
+	if channel.openedAt.After(start) {
+		start = channel.openedAt
+	}
+
+	// The channel may have been opened in the future relative to our
+	// clock, in which case it hasn't been monitored at all.
+	if now.Before(start) {
+		return 0, 0, nil
+	}
+
+	uptime, err := p.uptime(start, now)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return now.Sub(start), uptime, nil
+}
+
 // getFlapCount returns the peer's flap count and the timestamp that we last
 // recorded a flap.
 func (p *peerLog) getFlapCount() (int, *time.Time) {
@@ -21,6 +21,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/subscribe"
@@ -64,6 +65,12 @@ type ChannelEventStore struct {
 	// peerRequests serves requests for information about a peer.
 	peerRequests chan peerRequest
 
+	// htlcs aggregates the htlc events of our channels into metrics.
+	htlcs *htlcTracker
+
+	// metricsRequests serves requests for the metrics of a channel.
+	metricsRequests chan metricsRequest
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -99,6 +106,15 @@ type Config struct {
 	// FlapCountTicker is a ticker which controls how often we flush our
 	// peer's flap count to disk.
 	FlapCountTicker ticker.Ticker
+
+	// SubscribeHtlcEvents provides a subscription client which provides a
+	// stream of htlc events. It is optional, if it is nil no htlc metrics
+	// are collected.
+	SubscribeHtlcEvents func() (subscribe.Subscription, error)
+
+	// HtlcMetricsRetention is the period over which the htlc metrics of
+	// channels are retained. If it is zero, the default retention is used.
+	HtlcMetricsRetention time.Duration
 }
 
 // peerFlapCountMap is the map used to map peers to flap counts, declared here
@@ -127,6 +143,19 @@ type peerResponse struct {
 	err       error
 }
 
+type metricsRequest struct {
+	peer         route.Vertex
+	channelPoint wire.OutPoint
+	chanID       lnwire.ShortChannelID
+	window       time.Duration
+	responseChan chan metricsResponse
+}
+
+type metricsResponse struct {
+	metrics *ChannelMetrics
+	err     error
+}
+
 // NewChannelEventStore initializes an event store with the config provided.
 // Note that this function does not start the main event loop, Start() must be
 // called.
@@ -136,6 +165,8 @@ func NewChannelEventStore(config *Config) *ChannelEventStore {
 		peers:            make(map[route.Vertex]peerMonitor),
 		chanInfoRequests: make(chan channelInfoRequest),
 		peerRequests:     make(chan peerRequest),
+		htlcs:            newHtlcTracker(config.HtlcMetricsRetention),
+		metricsRequests:  make(chan metricsRequest),
 		quit:             make(chan struct{}),
 	}
 
@@ -167,11 +198,34 @@ func (c *ChannelEventStore) Start() error {
 		return err
 	}
 
+	// Create a subscription to htlc events if htlc metrics are collected.
+	var htlcClient subscribe.Subscription
+	if c.cfg.SubscribeHtlcEvents != nil {
+		htlcClient, err = c.cfg.SubscribeHtlcEvents()
+		if err != nil {
+			channelClient.Cancel()
+			peerClient.Cancel()
+
+			return err
+		}
+	}
+
 	// cancel should be called to cancel all subscriptions if an error
 	// occurs.
 	cancel := func() {
 		channelClient.Cancel()
 		peerClient.Cancel()
+
+		if htlcClient != nil {
+			htlcClient.Cancel()
+		}
+	}
+
+	// A nil channel blocks forever, so no htlc events are consumed if we
+	// don't collect htlc metrics.
+	var htlcUpdates <-chan interface{}
+	if htlcClient != nil {
+		htlcUpdates = htlcClient.Updates()
 	}
 
 	// Add the existing set of channels to the event store. This is required
@@ -204,6 +258,7 @@ func (c *ChannelEventStore) Start() error {
 	go c.consume(&subscriptions{
 		channelUpdates: channelClient.Updates(),
 		peerUpdates:    peerClient.Updates(),
+		htlcUpdates:    htlcUpdates,
 		cancel:         cancel,
 	})
 
@@ -329,6 +384,7 @@ func (c *ChannelEventStore) peerEvent(peer route.Vertex, online bool) {
 type subscriptions struct {
 	channelUpdates <-chan interface{}
 	peerUpdates    <-chan interface{}
+	htlcUpdates    <-chan interface{}
 	cancel         func()
 }
 
@@ -391,8 +447,15 @@ func (c *ChannelEventStore) consume(subscriptions *subscriptions) {
 				c.closeChannel(
 					event.CloseSummary.ChanPoint, peerKey,
 				)
+				c.htlcs.removeChannel(
+					event.CloseSummary.ShortChanID,
+				)
 			}
 
+		// Process htlc events to update the htlc metrics.
+		case e := <-subscriptions.htlcUpdates:
+			c.htlcs.handleEvent(e)
+
 		// Process peer online and offline events.
 		case e := <-subscriptions.peerUpdates:
 			switch event := e.(type) {
@@ -416,6 +479,13 @@ func (c *ChannelEventStore) consume(subscriptions *subscriptions) {
 			resp.info, resp.err = c.getChanInfo(req)
 			req.responseChan <- resp
 
+		// Serve all requests for channel metrics.
+		case req := <-c.metricsRequests:
+			var resp metricsResponse
+
+			resp.metrics, resp.err = c.getChannelMetrics(req)
+			req.responseChan <- resp
+
 		// Serve all requests for information about our peer.
 		case req := <-c.peerRequests:
 			var resp peerResponse
@@ -501,6 +571,93 @@ func (c *ChannelEventStore) getChanInfo(req channelInfoRequest) (*ChannelInfo,
 	}, nil
 }
 
+// ChannelMetrics provides the performance metrics of a channel over a window.
+type ChannelMetrics struct {
+	// Monitored is the amount of time within the window that we have
+	// monitored the channel for.
+	Monitored time.Duration
+
+	// Uptime is the amount of time within the monitored period that the
+	// channel peer has been observed as online.
+	Uptime time.Duration
+
+	// Incoming aggregates the forwards that arrived on the channel within
+	// the window.
+	Incoming *HtlcStats
+
+	// Outgoing aggregates the forwards that left on the channel within
+	// the window.
+	Outgoing *HtlcStats
+}
+
+// Downtime returns the amount of time within the monitored period that the
+// channel peer has been offline.
+func (m *ChannelMetrics) Downtime() time.Duration {
+	return m.Monitored - m.Uptime
+}
+
+// GetChannelMetrics returns the metrics of a channel over the given window,
+// ending now. The forwarding metrics are aggregated per hour, so the window
+// is extended to full hours for them.
+func (c *ChannelEventStore) GetChannelMetrics(channelPoint wire.OutPoint,
+	peer route.Vertex, chanID lnwire.ShortChannelID,
+	window time.Duration) (*ChannelMetrics, error) {
+
+	request := metricsRequest{
+		peer:         peer,
+		channelPoint: channelPoint,
+		chanID:       chanID,
+		window:       window,
+		responseChan: make(chan metricsResponse),
+	}
+
+	select {
+	case c.metricsRequests <- request:
+	case <-c.quit:
+		return nil, errShuttingDown
+	}
+
+	select {
+	case resp := <-request.responseChan:
+		return resp.metrics, resp.err
+
+	case <-c.quit:
+		return nil, errShuttingDown
+	}
+}
+
+// getChannelMetrics collects the metrics of a channel over a window.
+func (c *ChannelEventStore) getChannelMetrics(
+	req metricsRequest) (*ChannelMetrics, error) {
+
+	if req.window <= 0 {
+		return nil, fmt.Errorf("invalid metrics window: %v", req.window)
+	}
+
+	peerMonitor, ok := c.peers[req.peer]
+	if !ok {
+		return nil, ErrPeerNotFound
+	}
+
+	since := c.cfg.Clock.Now().Add(-req.window)
+
+	monitored, uptime, err := peerMonitor.channelUptimeSince(
+		req.channelPoint, since,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	incoming, outgoing := c.htlcs.stats(req.chanID, since)
+
+	return &ChannelMetrics{
+		Monitored: monitored,
+		Uptime:    uptime,
+		Incoming:  incoming,
+		Outgoing:  outgoing,
+	}, nil
+}
+
 // FlapCount returns the flap count we have for a peer and the timestamp of its
 // last flap. If we do not have any flaps recorded for the peer, the last flap
 // timestamp will be nil.
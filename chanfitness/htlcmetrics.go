@@ -0,0 +1,262 @@
+package chanfitness
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultHtlcMetricsRetention is the default period over which the
+	// htlc metrics of channels are retained.
+	DefaultHtlcMetricsRetention = 30 * 24 * time.Hour
+
+	// htlcBucketWidth is the period that the htlc metrics of a channel are
+	// aggregated over. Metrics windows are rounded to this granularity.
+	htlcBucketWidth = time.Hour
+
+	// maxPendingForwards is the maximum number of forwards that we track
+	// the hold time of. Forwards beyond this limit are still counted once
+	// they are resolved, but don't contribute to the average hold time.
+	maxPendingForwards = 100_000
+)
+
+// HtlcStats aggregates the forwards of a channel in one direction.
+type HtlcStats struct {
+	// NumSettled is the number of forwards that were settled.
+	NumSettled uint64
+
+	// NumFailed is the number of forwards that failed.
+	NumFailed uint64
+
+	// SettledVolume is the total amount of the settled forwards.
+	SettledVolume lnwire.MilliSatoshi
+
+	// totalHoldTime is the sum of the hold times of the forwards whose
+	// hold time is known.
+	totalHoldTime time.Duration
+
+	// numTimed is the number of forwards whose hold time is known.
+	numTimed uint64
+}
+
+// SuccessRate returns the share of forwards that were settled, or zero if
+// there were no forwards.
+func (s *HtlcStats) SuccessRate() float64 {
+	total := s.NumSettled + s.NumFailed
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.NumSettled) / float64(total)
+}
+
+// AverageHoldTime returns the average time forwards were held until they
+// were resolved, or zero if it isn't known for any forward.
+func (s *HtlcStats) AverageHoldTime() time.Duration {
+	if s.numTimed == 0 {
+		return 0
+	}
+
+	return s.totalHoldTime / time.Duration(s.numTimed)
+}
+
+// Merge adds the forwards of the other stats to the stats.
+func (s *HtlcStats) Merge(other *HtlcStats) {
+	s.NumSettled += other.NumSettled
+	s.NumFailed += other.NumFailed
+	s.SettledVolume += other.SettledVolume
+	s.totalHoldTime += other.totalHoldTime
+	s.numTimed += other.numTimed
+}
+
+// record adds a resolved forward to the stats. A negative hold time indicates
+// that the hold time isn't known.
+func (s *HtlcStats) record(settled bool, amt lnwire.MilliSatoshi,
+	holdTime time.Duration) {
+
+	if settled {
+		s.NumSettled++
+		s.SettledVolume += amt
+	} else {
+		s.NumFailed++
+	}
+
+	if holdTime >= 0 {
+		s.totalHoldTime += holdTime
+		s.numTimed++
+	}
+}
+
+// htlcBucket aggregates the forwards of a channel over one bucket period.
+type htlcBucket struct {
+	// start is the start of the bucket period.
+	start time.Time
+
+	// incoming aggregates the forwards that arrived on the channel.
+	incoming HtlcStats
+
+	// outgoing aggregates the forwards that left on the channel.
+	outgoing HtlcStats
+}
+
+// pendingForward is a forward that hasn't been resolved yet.
+type pendingForward struct {
+	forwardedAt time.Time
+	info        htlcswitch.HtlcInfo
+}
+
+// htlcTracker aggregates htlc events into per-channel metrics.
+type htlcTracker struct {
+	retention time.Duration
+
+	// pending tracks the forwards that haven't been resolved yet, so that
+	// their hold time can be determined.
+	pending map[htlcswitch.HtlcKey]pendingForward
+
+	// buckets holds the metrics of every channel, ordered by bucket start
+	// time.
+	buckets map[lnwire.ShortChannelID][]*htlcBucket
+}
+
+// newHtlcTracker creates a tracker that retains metrics over the given period.
+func newHtlcTracker(retention time.Duration) *htlcTracker {
+	if retention <= 0 {
+		retention = DefaultHtlcMetricsRetention
+	}
+
+	return &htlcTracker{
+		retention: retention,
+		pending:   make(map[htlcswitch.HtlcKey]pendingForward),
+		buckets:   make(map[lnwire.ShortChannelID][]*htlcBucket),
+	}
+}
+
+// handleEvent updates the metrics with a htlc event. Only forwards are
+// tracked, local sends and receives are ignored.
+func (h *htlcTracker) handleEvent(e interface{}) {
+	switch event := e.(type) {
+	case *htlcswitch.ForwardingEvent:
+		if event.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		if len(h.pending) >= maxPendingForwards {
+			return
+		}
+
+		h.pending[event.HtlcKey] = pendingForward{
+			forwardedAt: event.Timestamp,
+			info:        event.HtlcInfo,
+		}
+
+	case *htlcswitch.SettleEvent:
+		if event.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		h.resolve(event.HtlcKey, event.HtlcInfo, true, event.Timestamp)
+
+	case *htlcswitch.ForwardingFailEvent:
+		if event.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		h.resolve(event.HtlcKey, event.HtlcInfo, false, event.Timestamp)
+
+	// Forwards that were failed by one of our links never reached the
+	// next hop, and count as failures for both channels.
+	case *htlcswitch.LinkFailEvent:
+		if event.HtlcEventType != htlcswitch.HtlcEventTypeForward {
+			return
+		}
+
+		h.resolve(event.HtlcKey, event.HtlcInfo, false, event.Timestamp)
+	}
+}
+
+// resolve records a resolved forward for its incoming and outgoing channel.
+func (h *htlcTracker) resolve(key htlcswitch.HtlcKey,
+	info htlcswitch.HtlcInfo, settled bool, ts time.Time) {
+
+	holdTime := time.Duration(-1)
+	if fwd, ok := h.pending[key]; ok {
+		delete(h.pending, key)
+
+		if !ts.Before(fwd.forwardedAt) {
+			holdTime = ts.Sub(fwd.forwardedAt)
+		}
+
+		// Settle and fail events don't reliably carry the amounts, so
+		// we fall back to the ones of the forward.
+		if info.IncomingAmt == 0 && info.OutgoingAmt == 0 {
+			info = fwd.info
+		}
+	}
+
+	// The outgoing channel isn't known for forwards that failed before
+	// the outgoing link was selected.
+	var unknownChan lnwire.ShortChannelID
+	if key.IncomingCircuit.ChanID != unknownChan {
+		incoming := h.bucket(key.IncomingCircuit.ChanID, ts)
+		incoming.incoming.record(settled, info.IncomingAmt, holdTime)
+	}
+
+	if key.OutgoingCircuit.ChanID != unknownChan {
+		outgoing := h.bucket(key.OutgoingCircuit.ChanID, ts)
+		outgoing.outgoing.record(settled, info.OutgoingAmt, holdTime)
+	}
+}
+
+// bucket returns the bucket of the channel that covers the given time, and
+// prunes the buckets that are past the retention period.
+func (h *htlcTracker) bucket(chanID lnwire.ShortChannelID,
+	ts time.Time) *htlcBucket {
+
+	start := ts.Truncate(htlcBucketWidth)
+
+	buckets := h.buckets[chanID]
+
+	// Events are delivered in order, so the bucket is either the last
+	// one, or a new one has to be appended.
+	if len(buckets) > 0 {
+		last := buckets[len(buckets)-1]
+		if !start.After(last.start) {
+			return last
+		}
+	}
+
+	cutoff := ts.Add(-h.retention)
+	for len(buckets) > 0 && buckets[0].start.Before(cutoff) {
+		buckets = buckets[1:]
+	}
+
+	bucket := &htlcBucket{start: start}
+	h.buckets[chanID] = append(buckets, bucket)
+
+	return bucket
+}
+
+// stats returns the incoming and outgoing stats of the channel for all
+// buckets that overlap with the period since the given time.
+func (h *htlcTracker) stats(chanID lnwire.ShortChannelID,
+	since time.Time) (*HtlcStats, *HtlcStats) {
+
+	var incoming, outgoing HtlcStats
+	for _, bucket := range h.buckets[chanID] {
+		if bucket.start.Add(htlcBucketWidth).Before(since) {
+			continue
+		}
+
+		incoming.Merge(&bucket.incoming)
+		outgoing.Merge(&bucket.outgoing)
+	}
+
+	return &incoming, &outgoing
+}
+
+// removeChannel drops the metrics of a channel.
+func (h *htlcTracker) removeChannel(chanID lnwire.ShortChannelID) {
+	delete(h.buckets, chanID)
+}
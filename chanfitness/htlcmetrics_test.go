@@ -0,0 +1,143 @@
+package chanfitness
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHtlcTracker tests the aggregation of htlc events into channel metrics.
+func TestHtlcTracker(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chanIn  = lnwire.NewShortChanIDFromInt(1)
+		chanOut = lnwire.NewShortChanIDFromInt(2)
+		start   = testNow.Truncate(htlcBucketWidth)
+	)
+
+	htlcKey := func(id uint64) htlcswitch.HtlcKey {
+		return htlcswitch.HtlcKey{
+			IncomingCircuit: models.CircuitKey{
+				ChanID: chanIn,
+				HtlcID: id,
+			},
+			OutgoingCircuit: models.CircuitKey{
+				ChanID: chanOut,
+				HtlcID: id,
+			},
+		}
+	}
+
+	info := htlcswitch.HtlcInfo{
+		IncomingAmt: 1010,
+		OutgoingAmt: 1000,
+	}
+
+	tracker := newHtlcTracker(24 * time.Hour)
+
+	// Forward two htlcs, one of which is settled after ten seconds and the
+	// other fails after thirty seconds.
+	for id := uint64(0); id < 2; id++ {
+		tracker.handleEvent(&htlcswitch.ForwardingEvent{
+			HtlcKey:       htlcKey(id),
+			HtlcInfo:      info,
+			HtlcEventType: htlcswitch.HtlcEventTypeForward,
+			Timestamp:     start,
+		})
+	}
+	tracker.handleEvent(&htlcswitch.SettleEvent{
+		HtlcKey:       htlcKey(0),
+		HtlcEventType: htlcswitch.HtlcEventTypeForward,
+		Timestamp:     start.Add(10 * time.Second),
+	})
+	tracker.handleEvent(&htlcswitch.ForwardingFailEvent{
+		HtlcKey:       htlcKey(1),
+		HtlcEventType: htlcswitch.HtlcEventTypeForward,
+		Timestamp:     start.Add(30 * time.Second),
+	})
+
+	// Local sends aren't tracked.
+	tracker.handleEvent(&htlcswitch.SettleEvent{
+		HtlcKey:       htlcKey(2),
+		HtlcEventType: htlcswitch.HtlcEventTypeSend,
+		Timestamp:     start,
+	})
+
+	require.Empty(t, tracker.pending)
+
+	incoming, outgoing := tracker.stats(chanIn, start)
+	require.EqualValues(t, 1, incoming.NumSettled)
+	require.EqualValues(t, 1, incoming.NumFailed)
+	require.EqualValues(t, 1010, incoming.SettledVolume)
+	require.Equal(t, 0.5, incoming.SuccessRate())
+	require.Equal(t, 20*time.Second, incoming.AverageHoldTime())
+	require.Zero(t, outgoing.NumSettled+outgoing.NumFailed)
+
+	incoming, outgoing = tracker.stats(chanOut, start)
+	require.EqualValues(t, 1000, outgoing.SettledVolume)
+	require.Zero(t, incoming.NumSettled+incoming.NumFailed)
+
+	// A link failure a day later is recorded in a new bucket, and prunes
+	// the first one that is now past the retention period.
+	later := start.Add(25 * time.Hour)
+	tracker.handleEvent(&htlcswitch.LinkFailEvent{
+		HtlcKey:       htlcKey(3),
+		HtlcInfo:      info,
+		HtlcEventType: htlcswitch.HtlcEventTypeForward,
+		Timestamp:     later,
+	})
+
+	incoming, _ = tracker.stats(chanIn, start)
+	require.Zero(t, incoming.NumSettled)
+	require.EqualValues(t, 1, incoming.NumFailed)
+	require.Zero(t, incoming.AverageHoldTime())
+	require.Len(t, tracker.buckets[chanIn], 1)
+
+	tracker.removeChannel(chanIn)
+	incoming, _ = tracker.stats(chanIn, start)
+	require.Zero(t, incoming.NumFailed)
+}
+
+// TestChannelUptimeSince tests that the uptime of a channel is limited to the
+// requested window.
+func TestChannelUptimeSince(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(testNow)
+	peers := newPeerLog(testClock, 0, nil)
+
+	// Open a channel while our peer is online, and take it offline after
+	// an hour.
+	chanPoint := wire.OutPoint{Index: 1}
+	peers.onlineEvent(true)
+	require.NoError(t, peers.addChannel(chanPoint))
+
+	testClock.SetTime(testNow.Add(time.Hour))
+	peers.onlineEvent(false)
+
+	testClock.SetTime(testNow.Add(2 * time.Hour))
+
+	monitored, uptime, err := peers.channelUptimeSince(
+		chanPoint, testNow.Add(-time.Hour),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Hour, monitored)
+	require.Equal(t, time.Hour, uptime)
+
+	monitored, uptime, err = peers.channelUptimeSince(
+		chanPoint, testNow.Add(90*time.Minute),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, monitored)
+	require.Zero(t, uptime)
+
+	_, _, err = peers.channelUptimeSince(wire.OutPoint{}, testNow)
+	require.ErrorIs(t, err, ErrChannelNotFound)
+}
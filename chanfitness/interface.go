@@ -27,6 +27,12 @@ type peerMonitor interface {
 	channelUptime(channelPoint wire.OutPoint) (time.Duration,
 		time.Duration, error)
 
+	// channelUptimeSince looks up a channel and returns the amount of time
+	// that the channel has been monitored for since the given time, and its
+	// uptime over this period.
+	channelUptimeSince(channelPoint wire.OutPoint,
+		since time.Time) (time.Duration, time.Duration, error)
+
 	// getFlapCount returns the peer's flap count and the timestamp that we
 	// last recorded a flap, which may be nil if we have never recorded a
 	// flap for this peer.
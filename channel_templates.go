@@ -0,0 +1,203 @@
+package lnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// maxChannelTemplateName is the maximum length of the name of a channel
+// template.
+const maxChannelTemplateName = 64
+
+// fetchChannelTemplate returns the channel template of the given name, or nil
+// if no name is given.
+func (r *rpcServer) fetchChannelTemplate(
+	name string) (*channeldb.ChannelTemplate, error) {
+
+	if name == "" {
+		return nil, nil
+	}
+
+	tmpl, err := r.server.chanStateDB.FetchChannelTemplate(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch channel template %q: "+
+			"%w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// applyChannelTemplate sets the parameters of the template that aren't set in
+// the open channel request.
+func applyChannelTemplate(in *lnrpc.OpenChannelRequest,
+	tmpl *channeldb.ChannelTemplate) {
+
+	if tmpl == nil {
+		return
+	}
+
+	if in.CommitmentType == lnrpc.CommitmentType_UNKNOWN_COMMITMENT_TYPE {
+		in.CommitmentType = lnrpc.CommitmentType(tmpl.CommitmentType)
+	}
+
+	in.Private = in.Private || tmpl.Private
+	in.ZeroConf = in.ZeroConf || tmpl.ZeroConf
+	in.ScidAlias = in.ScidAlias || tmpl.ScidAlias
+
+	if !in.UseBaseFee && tmpl.UseBaseFee {
+		in.UseBaseFee = true
+		in.BaseFee = tmpl.BaseFee
+	}
+	if !in.UseFeeRate && tmpl.UseFeeRate {
+		in.UseFeeRate = true
+		in.FeeRate = tmpl.FeeRate
+	}
+
+	if in.RemoteCsvDelay == 0 {
+		in.RemoteCsvDelay = tmpl.RemoteCsvDelay
+	}
+	if in.MaxLocalCsv == 0 {
+		in.MaxLocalCsv = tmpl.MaxLocalCsv
+	}
+	if in.RemoteChanReserveSat == 0 {
+		in.RemoteChanReserveSat = tmpl.RemoteChanReserveSat
+	}
+	if in.RemoteMaxHtlcs == 0 {
+		in.RemoteMaxHtlcs = tmpl.RemoteMaxHtlcs
+	}
+	if in.MinHtlcMsat == 0 {
+		in.MinHtlcMsat = int64(tmpl.MinHtlcMsat)
+	}
+	if in.RemoteMaxValueInFlightMsat == 0 {
+		in.RemoteMaxValueInFlightMsat = tmpl.RemoteMaxValueInFlightMsat
+	}
+}
+
+// marshallChannelTemplate converts a channel template to its RPC
+// representation.
+func marshallChannelTemplate(
+	tmpl *channeldb.ChannelTemplate) *lnrpc.ChannelTemplate {
+
+	commitType := lnrpc.CommitmentType(tmpl.CommitmentType)
+
+	return &lnrpc.ChannelTemplate{
+		Name:                       tmpl.Name,
+		CommitmentType:             commitType,
+		Private:                    tmpl.Private,
+		ZeroConf:                   tmpl.ZeroConf,
+		ScidAlias:                  tmpl.ScidAlias,
+		UseBaseFee:                 tmpl.UseBaseFee,
+		BaseFee:                    tmpl.BaseFee,
+		UseFeeRate:                 tmpl.UseFeeRate,
+		FeeRate:                    tmpl.FeeRate,
+		RemoteCsvDelay:             tmpl.RemoteCsvDelay,
+		MaxLocalCsv:                tmpl.MaxLocalCsv,
+		RemoteChanReserveSat:       tmpl.RemoteChanReserveSat,
+		RemoteMaxHtlcs:             tmpl.RemoteMaxHtlcs,
+		MinHtlcMsat:                tmpl.MinHtlcMsat,
+		RemoteMaxValueInFlightMsat: tmpl.RemoteMaxValueInFlightMsat,
+	}
+}
+
+// unmarshallChannelTemplate converts a channel template from its RPC
+// representation, validating its name and commitment type.
+func unmarshallChannelTemplate(
+	tmpl *lnrpc.ChannelTemplate) (*channeldb.ChannelTemplate, error) {
+
+	switch {
+	case tmpl == nil:
+		return nil, errors.New("channel template must be set")
+
+	case tmpl.Name == "":
+		return nil, errors.New("channel template name must be set")
+
+	case len(tmpl.Name) > maxChannelTemplateName:
+		return nil, fmt.Errorf("channel template name longer than %d "+
+			"characters", maxChannelTemplateName)
+	}
+
+	commitType := int32(tmpl.CommitmentType)
+	if _, ok := lnrpc.CommitmentType_name[commitType]; !ok {
+		return nil, fmt.Errorf("unknown commitment type %d", commitType)
+	}
+
+	return &channeldb.ChannelTemplate{
+		Name:                       tmpl.Name,
+		CommitmentType:             uint32(tmpl.CommitmentType),
+		Private:                    tmpl.Private,
+		ZeroConf:                   tmpl.ZeroConf,
+		ScidAlias:                  tmpl.ScidAlias,
+		UseBaseFee:                 tmpl.UseBaseFee,
+		BaseFee:                    tmpl.BaseFee,
+		UseFeeRate:                 tmpl.UseFeeRate,
+		FeeRate:                    tmpl.FeeRate,
+		RemoteCsvDelay:             tmpl.RemoteCsvDelay,
+		MaxLocalCsv:                tmpl.MaxLocalCsv,
+		RemoteChanReserveSat:       tmpl.RemoteChanReserveSat,
+		RemoteMaxHtlcs:             tmpl.RemoteMaxHtlcs,
+		MinHtlcMsat:                tmpl.MinHtlcMsat,
+		RemoteMaxValueInFlightMsat: tmpl.RemoteMaxValueInFlightMsat,
+	}, nil
+}
+
+// ListChannelTemplates returns the channel templates, ordered by name.
+func (r *rpcServer) ListChannelTemplates(_ context.Context,
+	_ *lnrpc.ListChannelTemplatesRequest) (
+	*lnrpc.ListChannelTemplatesResponse, error) {
+
+	tmpls, err := r.server.chanStateDB.FetchChannelTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListChannelTemplatesResponse{
+		Templates: make([]*lnrpc.ChannelTemplate, 0, len(tmpls)),
+	}
+	for _, tmpl := range tmpls {
+		resp.Templates = append(
+			resp.Templates, marshallChannelTemplate(tmpl),
+		)
+	}
+
+	return resp, nil
+}
+
+// SetChannelTemplate stores a channel template under its name, replacing the
+// one stored before. Channels that were opened with the template before are
+// not changed.
+func (r *rpcServer) SetChannelTemplate(_ context.Context,
+	in *lnrpc.SetChannelTemplateRequest) (*lnrpc.SetChannelTemplateResponse,
+	error) {
+
+	tmpl, err := unmarshallChannelTemplate(in.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.server.chanStateDB.PutChannelTemplate(tmpl); err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("Channel template %q updated", tmpl.Name)
+
+	return &lnrpc.SetChannelTemplateResponse{}, nil
+}
+
+// DeleteChannelTemplate removes the channel template of the given name.
+func (r *rpcServer) DeleteChannelTemplate(_ context.Context,
+	in *lnrpc.DeleteChannelTemplateRequest) (
+	*lnrpc.DeleteChannelTemplateResponse, error) {
+
+	err := r.server.chanStateDB.DeleteChannelTemplate(in.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("Channel template %q deleted", in.Name)
+
+	return &lnrpc.DeleteChannelTemplateResponse{}, nil
+}
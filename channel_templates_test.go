@@ -0,0 +1,101 @@
+package lnd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApplyChannelTemplate asserts that the parameters of a channel template
+// are used for those that aren't set in the open channel request.
+func TestApplyChannelTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &channeldb.ChannelTemplate{
+		Name: "routing",
+		CommitmentType: uint32(
+			lnrpc.CommitmentType_SIMPLE_TAPROOT,
+		),
+		Private:              true,
+		UseBaseFee:           true,
+		BaseFee:              0,
+		UseFeeRate:           true,
+		FeeRate:              500,
+		RemoteCsvDelay:       288,
+		MaxLocalCsv:          1008,
+		RemoteChanReserveSat: 20_000,
+		RemoteMaxHtlcs:       200,
+		MinHtlcMsat:          1_000,
+	}
+
+	req := &lnrpc.OpenChannelRequest{
+		LocalFundingAmount: 1_000_000,
+		UseFeeRate:         true,
+		FeeRate:            100,
+		RemoteCsvDelay:     144,
+	}
+	applyChannelTemplate(req, tmpl)
+
+	require.Equal(t, &lnrpc.OpenChannelRequest{
+		LocalFundingAmount:   1_000_000,
+		CommitmentType:       lnrpc.CommitmentType_SIMPLE_TAPROOT,
+		Private:              true,
+		UseBaseFee:           true,
+		UseFeeRate:           true,
+		FeeRate:              100,
+		RemoteCsvDelay:       144,
+		MaxLocalCsv:          1008,
+		RemoteChanReserveSat: 20_000,
+		RemoteMaxHtlcs:       200,
+		MinHtlcMsat:          1_000,
+	}, req)
+
+	// Without a template, the request isn't changed.
+	applyChannelTemplate(req, nil)
+	require.EqualValues(t, 100, req.FeeRate)
+}
+
+// TestChannelTemplateRPCConversion asserts that a channel template survives
+// the conversion to and from its RPC representation, and that templates with
+// an invalid name or commitment type are rejected.
+func TestChannelTemplateRPCConversion(t *testing.T) {
+	t.Parallel()
+
+	tmpl := &channeldb.ChannelTemplate{
+		Name:                       "routing",
+		CommitmentType:             uint32(lnrpc.CommitmentType_ANCHORS),
+		ZeroConf:                   true,
+		ScidAlias:                  true,
+		UseBaseFee:                 true,
+		BaseFee:                    1_000,
+		RemoteCsvDelay:             288,
+		RemoteMaxValueInFlightMsat: 500_000_000,
+	}
+
+	rpcTmpl := marshallChannelTemplate(tmpl)
+	require.Equal(t, lnrpc.CommitmentType_ANCHORS, rpcTmpl.CommitmentType)
+
+	parsed, err := unmarshallChannelTemplate(rpcTmpl)
+	require.NoError(t, err)
+	require.Equal(t, tmpl, parsed)
+
+	_, err = unmarshallChannelTemplate(nil)
+	require.ErrorContains(t, err, "must be set")
+
+	_, err = unmarshallChannelTemplate(&lnrpc.ChannelTemplate{})
+	require.ErrorContains(t, err, "name must be set")
+
+	_, err = unmarshallChannelTemplate(&lnrpc.ChannelTemplate{
+		Name: strings.Repeat("a", maxChannelTemplateName+1),
+	})
+	require.ErrorContains(t, err, "longer than")
+
+	_, err = unmarshallChannelTemplate(&lnrpc.ChannelTemplate{
+		Name:           "unknown",
+		CommitmentType: 1_000,
+	})
+	require.ErrorContains(t, err, "unknown commitment type")
+}
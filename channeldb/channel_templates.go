@@ -0,0 +1,252 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// channelTemplateBucket is the name of the top level bucket that stores
+	// the named templates of the parameters of channels we open, keyed by
+	// their name.
+	//
+	// channel-template-bucket
+	//      |
+	//      |-- <name>: <flags><commitment type><fees><csvs><reserve>
+	//                  <max htlcs><min htlc><max in flight>
+	channelTemplateBucket = []byte("channel-template-bucket")
+)
+
+var (
+	// ErrChannelTemplateNotFound is returned when no channel template is
+	// stored under a name.
+	ErrChannelTemplateNotFound = errors.New("channel template not found")
+)
+
+const (
+	// channelTemplatePrivate is set if channels of the template aren't
+	// announced.
+	channelTemplatePrivate uint8 = 1 << iota
+
+	// channelTemplateZeroConf is set if channels of the template are
+	// zero-conf channels.
+	channelTemplateZeroConf
+
+	// channelTemplateScidAlias is set if channels of the template
+	// negotiate the scid-alias channel type.
+	channelTemplateScidAlias
+
+	// channelTemplateUseBaseFee is set if the template sets the base fee.
+	channelTemplateUseBaseFee
+
+	// channelTemplateUseFeeRate is set if the template sets the fee rate.
+	channelTemplateUseFeeRate
+)
+
+// ChannelTemplate is a named set of parameters of channels we open. Values
+// that are zero or unset are left to the defaults of the open request.
+type ChannelTemplate struct {
+	// Name is the name the template is referenced by.
+	Name string
+
+	// CommitmentType is the lnrpc commitment type of the channels.
+	CommitmentType uint32
+
+	// Private indicates that the channels aren't announced.
+	Private bool
+
+	// ZeroConf indicates that the channels are zero-conf channels.
+	ZeroConf bool
+
+	// ScidAlias indicates that the channels negotiate the scid-alias
+	// channel type.
+	ScidAlias bool
+
+	// UseBaseFee indicates that BaseFee is set.
+	UseBaseFee bool
+
+	// BaseFee is the base fee in milli-satoshis of the channels.
+	BaseFee uint64
+
+	// UseFeeRate indicates that FeeRate is set.
+	UseFeeRate bool
+
+	// FeeRate is the fee rate in parts per million of the channels.
+	FeeRate uint64
+
+	// RemoteCsvDelay is the CSV delay we require for the funds of the
+	// peer.
+	RemoteCsvDelay uint32
+
+	// MaxLocalCsv is the maximum CSV delay we accept for our funds.
+	MaxLocalCsv uint32
+
+	// RemoteChanReserveSat is the reserve the peer has to keep.
+	RemoteChanReserveSat uint64
+
+	// RemoteMaxHtlcs is the maximum number of htlcs the peer may offer.
+	RemoteMaxHtlcs uint32
+
+	// MinHtlcMsat is the smallest htlc we accept.
+	MinHtlcMsat uint64
+
+	// RemoteMaxValueInFlightMsat is the maximum value of the htlcs the
+	// peer may have in flight.
+	RemoteMaxValueInFlightMsat uint64
+}
+
+// serializeChannelTemplate writes the channel template, without its name, to
+// the writer.
+func serializeChannelTemplate(w io.Writer, tmpl *ChannelTemplate) error {
+	var flags uint8
+	if tmpl.Private {
+		flags |= channelTemplatePrivate
+	}
+	if tmpl.ZeroConf {
+		flags |= channelTemplateZeroConf
+	}
+	if tmpl.ScidAlias {
+		flags |= channelTemplateScidAlias
+	}
+	if tmpl.UseBaseFee {
+		flags |= channelTemplateUseBaseFee
+	}
+	if tmpl.UseFeeRate {
+		flags |= channelTemplateUseFeeRate
+	}
+
+	return WriteElements(
+		w, flags, tmpl.CommitmentType, tmpl.BaseFee, tmpl.FeeRate,
+		tmpl.RemoteCsvDelay, tmpl.MaxLocalCsv,
+		tmpl.RemoteChanReserveSat, tmpl.RemoteMaxHtlcs,
+		tmpl.MinHtlcMsat, tmpl.RemoteMaxValueInFlightMsat,
+	)
+}
+
+// deserializeChannelTemplate reads a channel template from the reader.
+func deserializeChannelTemplate(name string,
+	r io.Reader) (*ChannelTemplate, error) {
+
+	var (
+		tmpl  = ChannelTemplate{Name: name}
+		flags uint8
+	)
+	err := ReadElements(
+		r, &flags, &tmpl.CommitmentType, &tmpl.BaseFee, &tmpl.FeeRate,
+		&tmpl.RemoteCsvDelay, &tmpl.MaxLocalCsv,
+		&tmpl.RemoteChanReserveSat, &tmpl.RemoteMaxHtlcs,
+		&tmpl.MinHtlcMsat, &tmpl.RemoteMaxValueInFlightMsat,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl.Private = flags&channelTemplatePrivate != 0
+	tmpl.ZeroConf = flags&channelTemplateZeroConf != 0
+	tmpl.ScidAlias = flags&channelTemplateScidAlias != 0
+	tmpl.UseBaseFee = flags&channelTemplateUseBaseFee != 0
+	tmpl.UseFeeRate = flags&channelTemplateUseFeeRate != 0
+
+	return &tmpl, nil
+}
+
+// PutChannelTemplate stores the channel template under its name, replacing
+// the one stored before.
+func (c *ChannelStateDB) PutChannelTemplate(tmpl *ChannelTemplate) error {
+	if tmpl.Name == "" {
+		return errors.New("channel template without name")
+	}
+
+	var b bytes.Buffer
+	if err := serializeChannelTemplate(&b, tmpl); err != nil {
+		return err
+	}
+
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(channelTemplateBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(tmpl.Name), b.Bytes())
+	}, func() {})
+}
+
+// DeleteChannelTemplate removes the channel template of the given name, or
+// returns ErrChannelTemplateNotFound if none is stored.
+func (c *ChannelStateDB) DeleteChannelTemplate(name string) error {
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(channelTemplateBucket)
+		if bucket == nil || bucket.Get([]byte(name)) == nil {
+			return ErrChannelTemplateNotFound
+		}
+
+		return bucket.Delete([]byte(name))
+	}, func() {})
+}
+
+// FetchChannelTemplate returns the channel template of the given name, or
+// ErrChannelTemplateNotFound if none is stored.
+func (c *ChannelStateDB) FetchChannelTemplate(name string) (*ChannelTemplate,
+	error) {
+
+	var tmpl *ChannelTemplate
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(channelTemplateBucket)
+		if bucket == nil {
+			return ErrChannelTemplateNotFound
+		}
+
+		v := bucket.Get([]byte(name))
+		if v == nil {
+			return ErrChannelTemplateNotFound
+		}
+
+		var err error
+		tmpl, err = deserializeChannelTemplate(
+			name, bytes.NewReader(v),
+		)
+
+		return err
+	}, func() {
+		tmpl = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
+
+// FetchChannelTemplates returns all channel templates, ordered by name.
+func (c *ChannelStateDB) FetchChannelTemplates() ([]*ChannelTemplate, error) {
+	var templates []*ChannelTemplate
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(channelTemplateBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			tmpl, err := deserializeChannelTemplate(
+				string(k), bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			templates = append(templates, tmpl)
+
+			return nil
+		})
+	}, func() {
+		templates = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,73 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelTemplates tests storing, fetching and deleting channel
+// templates.
+func TestChannelTemplates(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	cdb := fullDB.ChannelStateDB()
+
+	// Without any stored template, we get an empty list.
+	templates, err := cdb.FetchChannelTemplates()
+	require.NoError(t, err)
+	require.Empty(t, templates)
+
+	_, err = cdb.FetchChannelTemplate("routing")
+	require.ErrorIs(t, err, ErrChannelTemplateNotFound)
+
+	require.Error(t, cdb.PutChannelTemplate(&ChannelTemplate{}))
+
+	tmpl1 := &ChannelTemplate{
+		Name:                       "routing",
+		CommitmentType:             4,
+		UseBaseFee:                 true,
+		BaseFee:                    0,
+		UseFeeRate:                 true,
+		FeeRate:                    500,
+		RemoteCsvDelay:             288,
+		MaxLocalCsv:                1008,
+		RemoteChanReserveSat:       20_000,
+		RemoteMaxHtlcs:             200,
+		MinHtlcMsat:                1_000,
+		RemoteMaxValueInFlightMsat: 500_000_000,
+	}
+	tmpl2 := &ChannelTemplate{
+		Name:      "lsp",
+		Private:   true,
+		ZeroConf:  true,
+		ScidAlias: true,
+	}
+
+	require.NoError(t, cdb.PutChannelTemplate(tmpl1))
+	require.NoError(t, cdb.PutChannelTemplate(tmpl2))
+
+	templates, err = cdb.FetchChannelTemplates()
+	require.NoError(t, err)
+	require.Equal(t, []*ChannelTemplate{tmpl2, tmpl1}, templates)
+
+	// Overwriting a template replaces it.
+	tmpl1.FeeRate = 1_000
+	require.NoError(t, cdb.PutChannelTemplate(tmpl1))
+
+	fetched, err := cdb.FetchChannelTemplate("routing")
+	require.NoError(t, err)
+	require.Equal(t, tmpl1, fetched)
+
+	require.NoError(t, cdb.DeleteChannelTemplate("lsp"))
+	require.ErrorIs(
+		t, cdb.DeleteChannelTemplate("lsp"), ErrChannelTemplateNotFound,
+	)
+
+	templates, err = cdb.FetchChannelTemplates()
+	require.NoError(t, err)
+	require.Equal(t, []*ChannelTemplate{tmpl1}, templates)
+}
@@ -457,6 +457,7 @@ var dbTopLevelBuckets = [][]byte{
 	outpointBucket,
 	chanIDBucket,
 	historicalChannelBucket,
+	subscriptionsBucket,
 }
 
 // Wipe completely deletes all saved state within all used buckets within the
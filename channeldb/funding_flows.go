@@ -0,0 +1,253 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// fundingFlowBucket is the name of the top level bucket that stores
+	// the state of the externally funded channel opens we initiated whose
+	// funding flow is in progress, keyed by their pending channel ID.
+	//
+	// funding-flow-bucket
+	//      |
+	//      |-- <pending chan id>: <step><flags><updated><peer><capacity>
+	//                             <pk script><witness script><local key>
+	//                             <remote key><outpoint>[funding tx]
+	fundingFlowBucket = []byte("funding-flow-bucket")
+)
+
+var (
+	// ErrFundingFlowNotFound is returned when no funding flow is stored
+	// under a pending channel ID.
+	ErrFundingFlowNotFound = errors.New("funding flow not found")
+)
+
+// FundingFlowStep is the last step an externally funded channel open reached
+// before its state was stored.
+type FundingFlowStep uint8
+
+const (
+	// FundingFlowAwaitingPsbt means that the funding output is known and
+	// we are waiting for the PSBT that funds it.
+	FundingFlowAwaitingPsbt FundingFlowStep = 0
+
+	// FundingFlowPsbtFinalized means that the funding transaction was
+	// finalized and we sent, or are about to send, our signature for the
+	// commitment transaction of the peer.
+	FundingFlowPsbtFinalized FundingFlowStep = 1
+)
+
+// String returns a human readable version of the step.
+func (s FundingFlowStep) String() string {
+	switch s {
+	case FundingFlowAwaitingPsbt:
+		return "AwaitingPsbt"
+
+	case FundingFlowPsbtFinalized:
+		return "PsbtFinalized"
+
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(s))
+	}
+}
+
+const (
+	// fundingFlowAborted is set if the funding flow was aborted because
+	// lnd restarted while it was in progress.
+	fundingFlowAborted uint8 = 1 << iota
+
+	// fundingFlowHasTx is set if the finalized funding transaction is
+	// stored.
+	fundingFlowHasTx
+)
+
+// PendingFundingFlow is the state of an externally funded channel open we
+// initiated. It contains everything needed to recover the funds sent to the
+// funding output if the funding flow can't be completed.
+type PendingFundingFlow struct {
+	// PendingChanID is the pending channel ID of the funding flow.
+	PendingChanID [32]byte
+
+	// Step is the last step the funding flow reached.
+	Step FundingFlowStep
+
+	// Aborted indicates that the funding flow was aborted because lnd
+	// restarted while it was in progress.
+	Aborted bool
+
+	// UpdatedAt is the time the funding flow was last stored.
+	UpdatedAt time.Time
+
+	// Peer is the identity public key of the peer.
+	Peer *btcec.PublicKey
+
+	// Capacity is the value of the funding output.
+	Capacity btcutil.Amount
+
+	// FundingPkScript is the pk script of the funding output.
+	FundingPkScript []byte
+
+	// FundingWitnessScript is the witness script of the funding output.
+	// It is empty for taproot channels.
+	FundingWitnessScript []byte
+
+	// LocalKey is our multisig key of the funding output.
+	LocalKey keychain.KeyDescriptor
+
+	// RemoteKey is the multisig key of the peer of the funding output.
+	RemoteKey *btcec.PublicKey
+
+	// FundingOutpoint is the funding outpoint. It is only set once the
+	// funding transaction was finalized.
+	FundingOutpoint wire.OutPoint
+
+	// FundingTx is the finalized funding transaction, if it is known.
+	FundingTx *wire.MsgTx
+}
+
+// serializeFundingFlow writes the funding flow, without its pending channel
+// ID, to the writer.
+func serializeFundingFlow(w io.Writer, flow *PendingFundingFlow) error {
+	var flags uint8
+	if flow.Aborted {
+		flags |= fundingFlowAborted
+	}
+
+	if flow.FundingTx != nil {
+		flags |= fundingFlowHasTx
+	}
+
+	err := WriteElements(
+		w, uint8(flow.Step), flags, uint64(flow.UpdatedAt.Unix()),
+		flow.Peer, flow.Capacity, flow.FundingPkScript,
+		flow.FundingWitnessScript, flow.LocalKey, flow.RemoteKey,
+		flow.FundingOutpoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	if flow.FundingTx == nil {
+		return nil
+	}
+
+	return WriteElement(w, flow.FundingTx)
+}
+
+// deserializeFundingFlow reads a funding flow from the reader.
+func deserializeFundingFlow(pendingChanID []byte,
+	r io.Reader) (*PendingFundingFlow, error) {
+
+	var (
+		flow      PendingFundingFlow
+		step      uint8
+		flags     uint8
+		updatedAt uint64
+	)
+	copy(flow.PendingChanID[:], pendingChanID)
+
+	err := ReadElements(
+		r, &step, &flags, &updatedAt, &flow.Peer, &flow.Capacity,
+		&flow.FundingPkScript, &flow.FundingWitnessScript,
+		&flow.LocalKey, &flow.RemoteKey, &flow.FundingOutpoint,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	flow.Step = FundingFlowStep(step)
+	flow.Aborted = flags&fundingFlowAborted != 0
+	flow.UpdatedAt = time.Unix(int64(updatedAt), 0)
+
+	// The witness script is empty for taproot channels.
+	if len(flow.FundingWitnessScript) == 0 {
+		flow.FundingWitnessScript = nil
+	}
+
+	if flags&fundingFlowHasTx != 0 {
+		if err := ReadElement(r, &flow.FundingTx); err != nil {
+			return nil, err
+		}
+	}
+
+	return &flow, nil
+}
+
+// PutPendingFundingFlow stores the funding flow under its pending channel ID,
+// replacing the state stored before.
+func (c *ChannelStateDB) PutPendingFundingFlow(
+	flow *PendingFundingFlow) error {
+
+	var b bytes.Buffer
+	if err := serializeFundingFlow(&b, flow); err != nil {
+		return err
+	}
+
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(fundingFlowBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(flow.PendingChanID[:], b.Bytes())
+	}, func() {})
+}
+
+// DeletePendingFundingFlow removes the funding flow of the given pending
+// channel ID, or returns ErrFundingFlowNotFound if none is stored.
+func (c *ChannelStateDB) DeletePendingFundingFlow(
+	pendingChanID [32]byte) error {
+
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(fundingFlowBucket)
+		if bucket == nil || bucket.Get(pendingChanID[:]) == nil {
+			return ErrFundingFlowNotFound
+		}
+
+		return bucket.Delete(pendingChanID[:])
+	}, func() {})
+}
+
+// FetchPendingFundingFlows returns all stored funding flows, ordered by their
+// pending channel ID.
+func (c *ChannelStateDB) FetchPendingFundingFlows() ([]*PendingFundingFlow,
+	error) {
+
+	var flows []*PendingFundingFlow
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(fundingFlowBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			flow, err := deserializeFundingFlow(
+				k, bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			flows = append(flows, flow)
+
+			return nil
+		})
+	}, func() {
+		flows = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return flows, nil
+}
@@ -0,0 +1,104 @@
+package channeldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPendingFundingFlows tests storing, fetching and deleting the state of
+// funding flows.
+func TestPendingFundingFlows(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	cdb := fullDB.ChannelStateDB()
+
+	// Without any stored funding flow, we get an empty list.
+	flows, err := cdb.FetchPendingFundingFlows()
+	require.NoError(t, err)
+	require.Empty(t, flows)
+
+	flow1 := &PendingFundingFlow{
+		PendingChanID:        [32]byte{1},
+		Step:                 FundingFlowAwaitingPsbt,
+		UpdatedAt:            time.Unix(1_700_000_000, 0),
+		Peer:                 pubKey,
+		Capacity:             1_000_000,
+		FundingPkScript:      []byte{0x00, 0x20, 0x01},
+		FundingWitnessScript: []byte{0x52, 0x21, 0x02},
+		LocalKey: keychain.KeyDescriptor{
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamilyMultiSig,
+				Index:  7,
+			},
+			PubKey: pubKey,
+		},
+		RemoteKey: pubKey,
+	}
+	flow2 := &PendingFundingFlow{
+		PendingChanID:   [32]byte{2},
+		Step:            FundingFlowAwaitingPsbt,
+		UpdatedAt:       time.Unix(1_700_000_100, 0),
+		Peer:            pubKey,
+		Capacity:        500_000,
+		FundingPkScript: []byte{0x51, 0x20, 0x02},
+		LocalKey: keychain.KeyDescriptor{
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamilyMultiSig,
+				Index:  8,
+			},
+		},
+		RemoteKey: pubKey,
+	}
+
+	require.NoError(t, cdb.PutPendingFundingFlow(flow1))
+	require.NoError(t, cdb.PutPendingFundingFlow(flow2))
+
+	flows, err = cdb.FetchPendingFundingFlows()
+	require.NoError(t, err)
+	require.Equal(t, []*PendingFundingFlow{flow1, flow2}, flows)
+
+	// Advancing a funding flow replaces its state.
+	fundingTx := wire.NewMsgTx(2)
+	fundingTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 3},
+	})
+	fundingTx.AddTxOut(&wire.TxOut{
+		Value:    int64(flow1.Capacity),
+		PkScript: flow1.FundingPkScript,
+	})
+
+	flow1.Step = FundingFlowPsbtFinalized
+	flow1.Aborted = true
+	flow1.FundingOutpoint = wire.OutPoint{Hash: fundingTx.TxHash()}
+	flow1.FundingTx = fundingTx
+	require.NoError(t, cdb.PutPendingFundingFlow(flow1))
+
+	flows, err = cdb.FetchPendingFundingFlows()
+	require.NoError(t, err)
+	require.Len(t, flows, 2)
+
+	fetched := flows[0]
+	require.Equal(t, FundingFlowPsbtFinalized, fetched.Step)
+	require.True(t, fetched.Aborted)
+	require.Equal(t, flow1.FundingOutpoint, fetched.FundingOutpoint)
+	require.NotNil(t, fetched.FundingTx)
+	require.Equal(t, fundingTx.TxHash(), fetched.FundingTx.TxHash())
+
+	require.NoError(t, cdb.DeletePendingFundingFlow(flow2.PendingChanID))
+	require.ErrorIs(
+		t, cdb.DeletePendingFundingFlow(flow2.PendingChanID),
+		ErrFundingFlowNotFound,
+	)
+
+	flows, err = cdb.FetchPendingFundingFlows()
+	require.NoError(t, err)
+	require.Len(t, flows, 1)
+	require.Equal(t, flow1.PendingChanID, flows[0].PendingChanID)
+}
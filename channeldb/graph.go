@@ -752,6 +752,140 @@ func (c *ChannelGraph) ForEachNodeCacheable(cb func(kvdb.RTx,
 	return kvdb.View(c.db, traversal, func() {})
 }
 
+// ForEachNodePage iterates through at most limit nodes of the graph in the
+// order of their public keys, starting after the given public key. A nil
+// public key starts the iteration at the first node. The limit is capped at
+// MaxPageSize. True is returned if there are further nodes after the last
+// visited one.
+func (c *ChannelGraph) ForEachNodePage(after []byte, limit uint64,
+	cb func(*LightningNode) error) (bool, error) {
+
+	if limit == 0 || limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	var more bool
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		nodes := tx.ReadBucket(nodeBucket)
+		if nodes == nil {
+			return ErrGraphNotFound
+		}
+
+		cursor := nodes.ReadCursor()
+		pubKey, nodeBytes := cursor.First()
+		if after != nil {
+			pubKey, nodeBytes = cursor.Seek(after)
+			if bytes.Equal(pubKey, after) {
+				pubKey, nodeBytes = cursor.Next()
+			}
+		}
+
+		var numNodes uint64
+		for ; pubKey != nil; pubKey, nodeBytes = cursor.Next() {
+			// Skip the source key and the nested buckets, which
+			// don't hold raw node information.
+			if len(pubKey) != 33 || nodeBytes == nil {
+				continue
+			}
+
+			if numNodes == limit {
+				more = true
+				return nil
+			}
+
+			node, err := deserializeLightningNode(
+				bytes.NewReader(nodeBytes),
+			)
+			if err != nil {
+				return err
+			}
+
+			if err := cb(&node); err != nil {
+				return err
+			}
+			numNodes++
+		}
+
+		return nil
+	}, func() {
+		more = false
+	})
+
+	return more, err
+}
+
+// ForEachChannelPage iterates through at most limit channels of the graph in
+// the order of their channel IDs, starting after the given channel ID. The
+// limit is capped at MaxPageSize. True is returned if there are further
+// channels after the last visited one.
+//
+// NOTE: If an edge can't be found, or wasn't advertised, then a nil pointer
+// for that particular channel edge routing policy will be passed into the
+// callback.
+func (c *ChannelGraph) ForEachChannelPage(afterChanID, limit uint64,
+	cb func(*models.ChannelEdgeInfo, *models.ChannelEdgePolicy,
+		*models.ChannelEdgePolicy) error) (bool, error) {
+
+	if limit == 0 || limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	var more bool
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		edges := tx.ReadBucket(edgeBucket)
+		if edges == nil {
+			return ErrGraphNoEdgesFound
+		}
+
+		edgeIndex := edges.NestedReadBucket(edgeIndexBucket)
+		if edgeIndex == nil {
+			return ErrGraphNoEdgesFound
+		}
+
+		var start [8]byte
+		byteOrder.PutUint64(start[:], afterChanID)
+
+		cursor := edgeIndex.ReadCursor()
+		chanID, edgeInfoBytes := cursor.Seek(start[:])
+		if bytes.Equal(chanID, start[:]) {
+			chanID, edgeInfoBytes = cursor.Next()
+		}
+
+		var numChannels uint64
+		for ; chanID != nil; chanID, edgeInfoBytes = cursor.Next() {
+			if numChannels == limit {
+				more = true
+				return nil
+			}
+
+			info, err := deserializeChanEdgeInfo(
+				bytes.NewReader(edgeInfoBytes),
+			)
+			if err != nil {
+				return err
+			}
+
+			policy1, policy2, err := fetchChanEdgePolicies(
+				edgeIndex, edges, chanID,
+			)
+			if err != nil {
+				return err
+			}
+
+			if err := cb(&info, policy1, policy2); err != nil {
+				return err
+			}
+			numChannels++
+		}
+
+		return nil
+	}, func() {
+		more = false
+	})
+
+	return more, err
+}
+
 // SourceNode returns the source node of the graph. The source node is treated
 // as the center node within a star-graph. This method may be used to kick off
 // a path finding algorithm in order to explore the reachability of another
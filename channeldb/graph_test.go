@@ -1211,6 +1211,82 @@ func TestGraphCacheTraversal(t *testing.T) {
 	require.Equal(t, numChannels*2*(numNodes-1), numNodeChans)
 }
 
+// TestGraphPagination tests that the nodes and channels of the graph can be
+// iterated in pages.
+func TestGraphPagination(t *testing.T) {
+	t.Parallel()
+
+	graph, err := MakeTestGraph(t)
+	require.NoError(t, err)
+
+	const (
+		numNodes    = 10
+		numChannels = 3
+		pageSize    = 4
+	)
+	chanIndex, _ := fillTestGraph(t, graph, numNodes, numChannels)
+
+	// Page through the nodes, which are returned in the order of their
+	// public keys.
+	var (
+		nodes     [][]byte
+		afterNode []byte
+		numPages  int
+	)
+	for {
+		more, err := graph.ForEachNodePage(
+			afterNode, pageSize, func(node *LightningNode) error {
+				nodes = append(nodes, node.PubKeyBytes[:])
+				return nil
+			},
+		)
+		require.NoError(t, err)
+		numPages++
+
+		afterNode = nodes[len(nodes)-1]
+		if !more {
+			break
+		}
+	}
+	require.Len(t, nodes, numNodes)
+	require.Equal(t, 3, numPages)
+	for i := 1; i < len(nodes); i++ {
+		require.Equal(t, -1, bytes.Compare(nodes[i-1], nodes[i]))
+	}
+
+	// Page through the channels, which are returned in the order of their
+	// channel IDs.
+	var (
+		chanIDs     []uint64
+		afterChanID uint64
+	)
+	cb := func(info *models.ChannelEdgeInfo,
+		p1, p2 *models.ChannelEdgePolicy) error {
+
+		require.NotNil(t, p1)
+		require.NotNil(t, p2)
+		chanIDs = append(chanIDs, info.ChannelID)
+
+		return nil
+	}
+	for {
+		more, err := graph.ForEachChannelPage(afterChanID, pageSize, cb)
+		require.NoError(t, err)
+
+		afterChanID = chanIDs[len(chanIDs)-1]
+		if !more {
+			break
+		}
+	}
+	require.Len(t, chanIDs, len(chanIndex))
+	for i, chanID := range chanIDs {
+		require.Contains(t, chanIndex, chanID)
+		if i > 0 {
+			require.Less(t, chanIDs[i-1], chanID)
+		}
+	}
+}
+
 func fillTestGraph(t require.TestingT, graph *ChannelGraph, numNodes,
 	numChannels int) (map[uint64]struct{}, []*LightningNode) {
 
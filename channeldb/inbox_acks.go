@@ -0,0 +1,61 @@
+package channeldb
+
+import (
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// inboxAckBucket is the name of the top level bucket that stores the
+	// IDs of the acknowledged keysend inbox messages. The keys are the
+	// big endian IDs, the values are empty.
+	inboxAckBucket = []byte("inbox-ack-bucket")
+)
+
+// PutInboxAcks records that the keysend inbox messages with the given IDs
+// have been acknowledged.
+func (d *DB) PutInboxAcks(ids []uint64) error {
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(inboxAckBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			var key [8]byte
+			byteOrder.PutUint64(key[:], id)
+
+			if err := bucket.Put(key[:], nil); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+}
+
+// FetchInboxAcks returns the IDs of the acknowledged keysend inbox messages.
+func (d *DB) FetchInboxAcks() (map[uint64]struct{}, error) {
+	var acks map[uint64]struct{}
+	err := kvdb.View(d, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(inboxAckBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, _ []byte) error {
+			if len(k) != 8 {
+				return nil
+			}
+			acks[byteOrder.Uint64(k)] = struct{}{}
+
+			return nil
+		})
+	}, func() {
+		acks = make(map[uint64]struct{})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return acks, nil
+}
@@ -103,3 +103,43 @@ func TestEncodeDecodeAmpInvoiceState(t *testing.T) {
 	// The two states should match.
 	require.Equal(t, ampState, ampState2)
 }
+
+// TestSerializeInvoiceFiatQuote asserts that the fiat quote of an invoice
+// survives a serialization round trip, and that invoices without a quote are
+// decoded without one.
+func TestSerializeInvoiceFiatQuote(t *testing.T) {
+	t.Parallel()
+
+	invoice := &invpkg.Invoice{
+		CreationDate: testNow,
+		Terms: invpkg.ContractTerm{
+			Value:    1000,
+			Features: lnwire.EmptyFeatureVector(),
+		},
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, serializeInvoice(&b, invoice))
+
+	decoded, err := deserializeInvoice(&b)
+	require.NoError(t, err)
+	require.Nil(t, decoded.FiatQuote)
+
+	invoice.FiatQuote = &invpkg.FiatQuote{
+		Currency:   "EUR",
+		Amount:     4200,
+		RateSource: "exchange",
+		QuoteTime:  testNow,
+	}
+
+	b.Reset()
+	require.NoError(t, serializeInvoice(&b, invoice))
+
+	decoded, err = deserializeInvoice(&b)
+	require.NoError(t, err)
+	require.NotNil(t, decoded.FiatQuote)
+	require.Equal(t, "EUR", decoded.FiatQuote.Currency)
+	require.EqualValues(t, 4200, decoded.FiatQuote.Amount)
+	require.Equal(t, "exchange", decoded.FiatQuote.RateSource)
+	require.True(t, testNow.Equal(decoded.FiatQuote.QuoteTime))
+}
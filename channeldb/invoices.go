@@ -16,6 +16,7 @@ import (
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/tlv"
 )
 
@@ -126,6 +127,25 @@ const (
 	hodlInvoiceType     tlv.Type = 14
 	invoiceAmpStateType tlv.Type = 15
 
+	// The partial payment policy uses odd types, so that a node that is
+	// rolled back ignores it and requires invoices to be paid in full.
+	partialToleranceType tlv.Type = 17
+	partialMinSettleType tlv.Type = 19
+
+	// The htlc constraints use odd types as well, a node that is rolled
+	// back ignores them and accepts any htlc.
+	htlcMaxCountType        tlv.Type = 21
+	htlcMinAmtType          tlv.Type = 23
+	htlcAllowedChannelsType tlv.Type = 25
+	htlcAllowedPeersType    tlv.Type = 27
+
+	// The fiat quote is stored with odd types too, as it is only
+	// informational.
+	fiatCurrencyType   tlv.Type = 29
+	fiatAmountType     tlv.Type = 31
+	fiatRateSourceType tlv.Type = 33
+	fiatQuoteTimeType  tlv.Type = 35
+
 	// A set of tlv type definitions used to serialize the invoice AMP
 	// state along-side the main invoice body.
 	ampStateSetIDType       tlv.Type = 0
@@ -1204,6 +1224,64 @@ func ampRecordSize(a *invpkg.AMPInvoiceState) func() uint64 {
 	}
 }
 
+// serializeAllowedChannels encodes the allowed channels of an invoice's htlc
+// constraints as a concatenation of their short channel ids.
+func serializeAllowedChannels(channels []lnwire.ShortChannelID) []byte {
+	b := make([]byte, 0, len(channels)*8)
+	for _, channel := range channels {
+		b = binary.BigEndian.AppendUint64(b, channel.ToUint64())
+	}
+
+	return b
+}
+
+// deserializeAllowedChannels decodes the allowed channels of an invoice's
+// htlc constraints.
+func deserializeAllowedChannels(b []byte) ([]lnwire.ShortChannelID, error) {
+	if len(b)%8 != 0 {
+		return nil, fmt.Errorf("invalid allowed channels length: %v",
+			len(b))
+	}
+
+	var channels []lnwire.ShortChannelID
+	for ; len(b) > 0; b = b[8:] {
+		channels = append(channels, lnwire.NewShortChanIDFromInt(
+			binary.BigEndian.Uint64(b[:8]),
+		))
+	}
+
+	return channels, nil
+}
+
+// serializeAllowedPeers encodes the allowed peers of an invoice's htlc
+// constraints as a concatenation of their public keys.
+func serializeAllowedPeers(peers []route.Vertex) []byte {
+	b := make([]byte, 0, len(peers)*route.VertexSize)
+	for _, peer := range peers {
+		b = append(b, peer[:]...)
+	}
+
+	return b
+}
+
+// deserializeAllowedPeers decodes the allowed peers of an invoice's htlc
+// constraints.
+func deserializeAllowedPeers(b []byte) ([]route.Vertex, error) {
+	if len(b)%route.VertexSize != 0 {
+		return nil, fmt.Errorf("invalid allowed peers length: %v",
+			len(b))
+	}
+
+	var peers []route.Vertex
+	for ; len(b) > 0; b = b[route.VertexSize:] {
+		var peer route.Vertex
+		copy(peer[:], b)
+		peers = append(peers, peer)
+	}
+
+	return peers, nil
+}
+
 // serializeInvoice serializes an invoice to a writer.
 //
 // Note: this function is in use for a migration. Before making changes that
@@ -1246,6 +1324,30 @@ func serializeInvoice(w io.Writer, i *invpkg.Invoice) error {
 		hodlInvoice = 1
 	}
 
+	partialTolerance := uint64(i.Terms.PartialPayment.Tolerance)
+	partialMinSettle := uint64(i.Terms.PartialPayment.MinSettleAmt)
+
+	constraints := i.Terms.HtlcConstraints
+	htlcMaxCount := constraints.MaxHtlcs
+	htlcMinAmt := uint64(constraints.MinHtlcAmt)
+	allowedChannels := serializeAllowedChannels(constraints.AllowedChannels)
+	allowedPeers := serializeAllowedPeers(constraints.AllowedPeers)
+
+	var (
+		fiatCurrency, fiatRateSource, fiatQuoteTime []byte
+		fiatAmount                                  uint64
+	)
+	if i.FiatQuote != nil {
+		fiatCurrency = []byte(i.FiatQuote.Currency)
+		fiatAmount = i.FiatQuote.Amount
+		fiatRateSource = []byte(i.FiatQuote.RateSource)
+
+		fiatQuoteTime, err = i.FiatQuote.QuoteTime.MarshalBinary()
+		if err != nil {
+			return err
+		}
+	}
+
 	tlvStream, err := tlv.NewStream(
 		// Memo and payreq.
 		tlv.MakePrimitiveRecord(memoType, &i.Memo),
@@ -1277,6 +1379,28 @@ func serializeInvoice(w io.Writer, i *invpkg.Invoice) error {
 			ampRecordSize(&i.AMPState),
 			ampStateEncoder, ampStateDecoder,
 		),
+
+		// Partial payment policy.
+		tlv.MakePrimitiveRecord(
+			partialToleranceType, &partialTolerance,
+		),
+		tlv.MakePrimitiveRecord(
+			partialMinSettleType, &partialMinSettle,
+		),
+
+		// Htlc constraints.
+		tlv.MakePrimitiveRecord(htlcMaxCountType, &htlcMaxCount),
+		tlv.MakePrimitiveRecord(htlcMinAmtType, &htlcMinAmt),
+		tlv.MakePrimitiveRecord(
+			htlcAllowedChannelsType, &allowedChannels,
+		),
+		tlv.MakePrimitiveRecord(htlcAllowedPeersType, &allowedPeers),
+
+		// Fiat quote.
+		tlv.MakePrimitiveRecord(fiatCurrencyType, &fiatCurrency),
+		tlv.MakePrimitiveRecord(fiatAmountType, &fiatAmount),
+		tlv.MakePrimitiveRecord(fiatRateSourceType, &fiatRateSource),
+		tlv.MakePrimitiveRecord(fiatQuoteTimeType, &fiatQuoteTime),
 	)
 	if err != nil {
 		return err
@@ -1637,6 +1761,19 @@ func deserializeInvoice(r io.Reader) (invpkg.Invoice, error) {
 		state         uint8
 		hodlInvoice   uint8
 
+		partialTolerance uint64
+		partialMinSettle uint64
+
+		htlcMaxCount    uint32
+		htlcMinAmt      uint64
+		allowedChannels []byte
+		allowedPeers    []byte
+
+		fiatCurrency   []byte
+		fiatAmount     uint64
+		fiatRateSource []byte
+		fiatQuoteTime  []byte
+
 		creationDateBytes []byte
 		settleDateBytes   []byte
 		featureBytes      []byte
@@ -1674,6 +1811,28 @@ func deserializeInvoice(r io.Reader) (invpkg.Invoice, error) {
 			invoiceAmpStateType, &i.AMPState, nil,
 			ampStateEncoder, ampStateDecoder,
 		),
+
+		// Partial payment policy.
+		tlv.MakePrimitiveRecord(
+			partialToleranceType, &partialTolerance,
+		),
+		tlv.MakePrimitiveRecord(
+			partialMinSettleType, &partialMinSettle,
+		),
+
+		// Htlc constraints.
+		tlv.MakePrimitiveRecord(htlcMaxCountType, &htlcMaxCount),
+		tlv.MakePrimitiveRecord(htlcMinAmtType, &htlcMinAmt),
+		tlv.MakePrimitiveRecord(
+			htlcAllowedChannelsType, &allowedChannels,
+		),
+		tlv.MakePrimitiveRecord(htlcAllowedPeersType, &allowedPeers),
+
+		// Fiat quote.
+		tlv.MakePrimitiveRecord(fiatCurrencyType, &fiatCurrency),
+		tlv.MakePrimitiveRecord(fiatAmountType, &fiatAmount),
+		tlv.MakePrimitiveRecord(fiatRateSourceType, &fiatRateSource),
+		tlv.MakePrimitiveRecord(fiatQuoteTimeType, &fiatQuoteTime),
 	)
 	if err != nil {
 		return i, err
@@ -1705,6 +1864,41 @@ func deserializeInvoice(r io.Reader) (invpkg.Invoice, error) {
 		i.HodlInvoice = true
 	}
 
+	i.Terms.PartialPayment = invpkg.PartialPaymentPolicy{
+		Tolerance:    lnwire.MilliSatoshi(partialTolerance),
+		MinSettleAmt: lnwire.MilliSatoshi(partialMinSettle),
+	}
+
+	channels, err := deserializeAllowedChannels(allowedChannels)
+	if err != nil {
+		return i, err
+	}
+
+	peers, err := deserializeAllowedPeers(allowedPeers)
+	if err != nil {
+		return i, err
+	}
+
+	i.Terms.HtlcConstraints = invpkg.HtlcConstraints{
+		MaxHtlcs:        htlcMaxCount,
+		MinHtlcAmt:      lnwire.MilliSatoshi(htlcMinAmt),
+		AllowedChannels: channels,
+		AllowedPeers:    peers,
+	}
+
+	if len(fiatCurrency) > 0 {
+		i.FiatQuote = &invpkg.FiatQuote{
+			Currency:   string(fiatCurrency),
+			Amount:     fiatAmount,
+			RateSource: string(fiatRateSource),
+		}
+
+		err = i.FiatQuote.QuoteTime.UnmarshalBinary(fiatQuoteTime)
+		if err != nil {
+			return i, err
+		}
+	}
+
 	err = i.CreationDate.UnmarshalBinary(creationDateBytes)
 	if err != nil {
 		return i, err
@@ -13,6 +13,13 @@ import (
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// htlcFailHoldTimesType is the TLV type of the hold times that are
+	// stored with the failure info of an htlc.
+	htlcFailHoldTimesType tlv.Type = 1
 )
 
 // HTLCAttemptInfo contains static information about a specific HTLC attempt
@@ -148,6 +155,12 @@ type HTLCFailInfo struct {
 	// field will be populated when the failure reason is either
 	// HTLCFailMessage or HTLCFailUnknown.
 	FailureSourceIndex uint32
+
+	// HoldTimes are the times that the hops of the route held the HTLC
+	// before failing it back, as reported through attribution data. The
+	// hold times are in route order and only include the hops whose
+	// attribution data could be authenticated.
+	HoldTimes []time.Duration
 }
 
 // MPPaymentState wraps a series of info needed for a given payment, which is
@@ -558,7 +571,33 @@ func serializeHTLCFailInfo(w io.Writer, f *HTLCFailInfo) error {
 		return err
 	}
 
-	return WriteElements(w, byte(f.Reason), f.FailureSourceIndex)
+	err := WriteElements(w, byte(f.Reason), f.FailureSourceIndex)
+	if err != nil {
+		return err
+	}
+
+	// The hold times are written as an optional trailing TLV stream, so
+	// that failures without hold times keep their original encoding.
+	if len(f.HoldTimes) == 0 {
+		return nil
+	}
+
+	holdTimes := make([]byte, 0, len(f.HoldTimes)*4)
+	for _, holdTime := range f.HoldTimes {
+		holdTimes = byteOrder.AppendUint32(
+			holdTimes, uint32(holdTime.Milliseconds()),
+		)
+	}
+
+	holdTimesRecord := tlv.MakePrimitiveRecord(
+		htlcFailHoldTimesType, &holdTimes,
+	)
+	tlvStream, err := tlv.NewStream(holdTimesRecord)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
 }
 
 // deserializeHTLCFailInfo deserializes the details of a failed htlc including
@@ -594,6 +633,41 @@ func deserializeHTLCFailInfo(r io.Reader) (*HTLCFailInfo, error) {
 	}
 	f.Reason = HTLCFailReason(reason)
 
+	// Read the optional trailing hold times, if any.
+	extraData, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(extraData) == 0 {
+		return f, nil
+	}
+
+	var holdTimes []byte
+	holdTimesRecord := tlv.MakePrimitiveRecord(
+		htlcFailHoldTimesType, &holdTimes,
+	)
+	tlvStream, err := tlv.NewStream(holdTimesRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tlvStream.Decode(bytes.NewReader(extraData))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(holdTimes)%4 != 0 {
+		return nil, fmt.Errorf("invalid hold times length: %d",
+			len(holdTimes))
+	}
+
+	for i := 0; i < len(holdTimes); i += 4 {
+		holdTime := byteOrder.Uint32(holdTimes[i : i+4])
+		f.HoldTimes = append(
+			f.HoldTimes, time.Duration(holdTime)*time.Millisecond,
+		)
+	}
+
 	return f, nil
 }
 
@@ -2,6 +2,11 @@ package channeldb
 
 import "github.com/lightningnetwork/lnd/kvdb"
 
+// MaxPageSize is the maximum number of items that a single paginated query
+// returns, regardless of the number of items requested. Callers that need
+// more items have to continue the query from the last returned index.
+const MaxPageSize = 10000
+
 type paginator struct {
 	// cursor is the cursor which we are using to iterate through a bucket.
 	cursor kvdb.RCursor
@@ -17,10 +22,14 @@ type paginator struct {
 }
 
 // newPaginator returns a struct which can be used to query an indexed bucket
-// in pages.
+// in pages. The number of items is capped at MaxPageSize.
 func newPaginator(c kvdb.RCursor, reversed bool,
 	indexOffset, totalItems uint64) paginator {
 
+	if totalItems > MaxPageSize {
+		totalItems = MaxPageSize
+	}
+
 	return paginator{
 		cursor:      c,
 		reversed:    reversed,
@@ -217,6 +217,57 @@ func testSerializeRoute(t *testing.T, route route.Route) {
 	reflect.DeepEqual(route, route2)
 }
 
+// TestHTLCFailInfoSerialization tests that the failure info of an htlc is
+// serialized with and without hold times.
+func TestHTLCFailInfoSerialization(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		failInfo *HTLCFailInfo
+	}{
+		{
+			name: "no hold times",
+			failInfo: &HTLCFailInfo{
+				FailTime: time.Unix(1000, 0),
+				Message:  &lnwire.FailTemporaryNodeFailure{},
+				Reason:   HTLCFailMessage,
+
+				FailureSourceIndex: 2,
+			},
+		},
+		{
+			name: "hold times",
+			failInfo: &HTLCFailInfo{
+				FailTime:           time.Unix(1000, 0),
+				Reason:             HTLCFailUnreadable,
+				FailureSourceIndex: 0,
+				HoldTimes: []time.Duration{
+					300 * time.Millisecond,
+					0,
+					2 * time.Second,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var b bytes.Buffer
+			err := serializeHTLCFailInfo(&b, tc.failInfo)
+			require.NoError(t, err)
+
+			failInfo, err := readHtlcFailInfo(b.Bytes())
+			require.NoError(t, err)
+			require.Equal(t, tc.failInfo, failInfo)
+		})
+	}
+}
+
 // deletePayment removes a payment with paymentHash from the payments database.
 func deletePayment(t *testing.T, db *DB, paymentHash lntypes.Hash, seqNr uint64) {
 	t.Helper()
@@ -0,0 +1,286 @@
+package channeldb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+var (
+	// peerPolicyBucket is the name of the top level bucket that stores the
+	// connection policies the user set for individual peers, keyed by
+	// their public key.
+	//
+	// peer-policy-bucket
+	//      |
+	//      |-- <peer-pubkey>: <flags><max channels><num addrs><addrs>
+	peerPolicyBucket = []byte("peer-policy-bucket")
+
+	// inboundAccessBucket is the name of the top level bucket that stores
+	// the inbound connection allow and deny lists, keyed by the public key
+	// of the peer.
+	//
+	// inbound-access-bucket
+	//      |
+	//      |-- <peer-pubkey>: <access>
+	inboundAccessBucket = []byte("inbound-access-bucket")
+)
+
+var (
+	// ErrPeerPolicyNotFound is returned when no connection policy is
+	// stored for a peer.
+	ErrPeerPolicyNotFound = errors.New("peer policy not found")
+)
+
+const (
+	// peerPolicyPersistent is set if we keep a connection to the peer even
+	// if we have no channel with it.
+	peerPolicyPersistent uint8 = 1 << iota
+
+	// peerPolicyTorOnly is set if we only connect to the peer over Tor.
+	peerPolicyTorOnly
+
+	// peerPolicyRejectInbound is set if we don't accept inbound
+	// connections from the peer. We store the negation, so the zero value
+	// of the flags allows inbound connections like for any other peer.
+	peerPolicyRejectInbound
+)
+
+// PeerPolicy is the connection policy the user set for a peer.
+type PeerPolicy struct {
+	// Persistent indicates that we keep a connection to the peer, even if
+	// we have no channel with it.
+	Persistent bool
+
+	// TorOnly indicates that we only connect to the peer over Tor.
+	TorOnly bool
+
+	// AllowInbound indicates that we accept inbound connections from the
+	// peer.
+	AllowInbound bool
+
+	// MaxChannels is the maximum number of channels the peer may open
+	// with us, including pending ones. Zero means no limit.
+	MaxChannels uint32
+
+	// Addresses are the addresses we connect to if the peer is
+	// persistent.
+	Addresses []net.Addr
+}
+
+// InboundAccess is the entry of a peer in the inbound connection access
+// lists.
+type InboundAccess uint8
+
+const (
+	// InboundAccessNone means the peer is on neither list.
+	InboundAccessNone InboundAccess = 0
+
+	// InboundAccessAllow means the peer is on the allow list. Once the
+	// allow list isn't empty, only peers on it may connect to us.
+	InboundAccessAllow InboundAccess = 1
+
+	// InboundAccessDeny means the peer is on the deny list and may never
+	// connect to us.
+	InboundAccessDeny InboundAccess = 2
+)
+
+// String returns a human readable version of the inbound access.
+func (a InboundAccess) String() string {
+	switch a {
+	case InboundAccessNone:
+		return "none"
+
+	case InboundAccessAllow:
+		return "allow"
+
+	case InboundAccessDeny:
+		return "deny"
+
+	default:
+		return "unknown"
+	}
+}
+
+// serializePeerPolicy writes the peer policy to the writer.
+func serializePeerPolicy(w io.Writer, policy *PeerPolicy) error {
+	var flags uint8
+	if policy.Persistent {
+		flags |= peerPolicyPersistent
+	}
+	if policy.TorOnly {
+		flags |= peerPolicyTorOnly
+	}
+	if !policy.AllowInbound {
+		flags |= peerPolicyRejectInbound
+	}
+
+	numAddrs := uint16(len(policy.Addresses))
+	err := WriteElements(w, flags, policy.MaxChannels, numAddrs)
+	if err != nil {
+		return err
+	}
+
+	for _, addr := range policy.Addresses {
+		if err := serializeAddr(w, addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializePeerPolicy reads a peer policy from the reader.
+func deserializePeerPolicy(r io.Reader) (*PeerPolicy, error) {
+	var (
+		policy   PeerPolicy
+		flags    uint8
+		numAddrs uint16
+	)
+	err := ReadElements(r, &flags, &policy.MaxChannels, &numAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	policy.Persistent = flags&peerPolicyPersistent != 0
+	policy.TorOnly = flags&peerPolicyTorOnly != 0
+	policy.AllowInbound = flags&peerPolicyRejectInbound == 0
+
+	for i := uint16(0); i < numAddrs; i++ {
+		addr, err := deserializeAddr(r)
+		if err != nil {
+			return nil, err
+		}
+		policy.Addresses = append(policy.Addresses, addr)
+	}
+
+	return &policy, nil
+}
+
+// PutPeerPolicy stores the connection policy of a peer, replacing the one
+// stored before.
+func (c *ChannelStateDB) PutPeerPolicy(pubKey route.Vertex,
+	policy *PeerPolicy) error {
+
+	var b bytes.Buffer
+	if err := serializePeerPolicy(&b, policy); err != nil {
+		return err
+	}
+
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(peerPolicyBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(pubKey[:], b.Bytes())
+	}, func() {})
+}
+
+// DeletePeerPolicy removes the connection policy of a peer, or returns
+// ErrPeerPolicyNotFound if none is stored.
+func (c *ChannelStateDB) DeletePeerPolicy(pubKey route.Vertex) error {
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(peerPolicyBucket)
+		if bucket == nil || bucket.Get(pubKey[:]) == nil {
+			return ErrPeerPolicyNotFound
+		}
+
+		return bucket.Delete(pubKey[:])
+	}, func() {})
+}
+
+// FetchPeerPolicies returns the connection policies of all peers.
+func (c *ChannelStateDB) FetchPeerPolicies() (map[route.Vertex]*PeerPolicy,
+	error) {
+
+	var policies map[route.Vertex]*PeerPolicy
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(peerPolicyBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			pubKey, err := route.NewVertexFromBytes(k)
+			if err != nil {
+				return err
+			}
+
+			policy, err := deserializePeerPolicy(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			policies[pubKey] = policy
+
+			return nil
+		})
+	}, func() {
+		policies = make(map[route.Vertex]*PeerPolicy)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// PutInboundAccess puts a peer on the inbound connection allow or deny list.
+// InboundAccessNone removes the peer from both lists.
+func (c *ChannelStateDB) PutInboundAccess(pubKey route.Vertex,
+	access InboundAccess) error {
+
+	return kvdb.Update(c.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(inboundAccessBucket)
+		if err != nil {
+			return err
+		}
+
+		if access == InboundAccessNone {
+			return bucket.Delete(pubKey[:])
+		}
+
+		return bucket.Put(pubKey[:], []byte{byte(access)})
+	}, func() {})
+}
+
+// FetchInboundAccess returns the entries of the inbound connection allow and
+// deny lists.
+func (c *ChannelStateDB) FetchInboundAccess() (
+	map[route.Vertex]InboundAccess, error) {
+
+	var access map[route.Vertex]InboundAccess
+	err := kvdb.View(c.backend, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(inboundAccessBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			pubKey, err := route.NewVertexFromBytes(k)
+			if err != nil {
+				return err
+			}
+
+			if len(v) != 1 {
+				return fmt.Errorf("invalid inbound access "+
+					"entry of %v", pubKey)
+			}
+			access[pubKey] = InboundAccess(v[0])
+
+			return nil
+		})
+	}, func() {
+		access = make(map[route.Vertex]InboundAccess)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return access, nil
+}
@@ -0,0 +1,94 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPeerPolicies tests storing, fetching and deleting peer connection
+// policies.
+func TestPeerPolicies(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	cdb := fullDB.ChannelStateDB()
+
+	// Without any stored policy, we get an empty map.
+	policies, err := cdb.FetchPeerPolicies()
+	require.NoError(t, err)
+	require.Empty(t, policies)
+
+	testPub2 := route.Vertex{2, 2, 2}
+	policy1 := &PeerPolicy{
+		Persistent:   true,
+		AllowInbound: true,
+		MaxChannels:  3,
+		Addresses:    testAddrs,
+	}
+	policy2 := &PeerPolicy{
+		TorOnly: true,
+	}
+
+	require.NoError(t, cdb.PutPeerPolicy(testPub, policy1))
+	require.NoError(t, cdb.PutPeerPolicy(testPub2, policy2))
+
+	policies, err = cdb.FetchPeerPolicies()
+	require.NoError(t, err)
+	require.Equal(t, map[route.Vertex]*PeerPolicy{
+		testPub:  policy1,
+		testPub2: policy2,
+	}, policies)
+
+	// Overwriting a policy replaces it.
+	policy2.AllowInbound = true
+	require.NoError(t, cdb.PutPeerPolicy(testPub2, policy2))
+
+	require.NoError(t, cdb.DeletePeerPolicy(testPub))
+	require.ErrorIs(
+		t, cdb.DeletePeerPolicy(testPub), ErrPeerPolicyNotFound,
+	)
+
+	policies, err = cdb.FetchPeerPolicies()
+	require.NoError(t, err)
+	require.Equal(t, map[route.Vertex]*PeerPolicy{
+		testPub2: policy2,
+	}, policies)
+}
+
+// TestInboundAccess tests that peers can be put on and removed from the
+// inbound connection access lists.
+func TestInboundAccess(t *testing.T) {
+	t.Parallel()
+
+	fullDB, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	cdb := fullDB.ChannelStateDB()
+
+	access, err := cdb.FetchInboundAccess()
+	require.NoError(t, err)
+	require.Empty(t, access)
+
+	testPub2 := route.Vertex{2, 2, 2}
+	require.NoError(t, cdb.PutInboundAccess(testPub, InboundAccessAllow))
+	require.NoError(t, cdb.PutInboundAccess(testPub2, InboundAccessDeny))
+
+	access, err = cdb.FetchInboundAccess()
+	require.NoError(t, err)
+	require.Equal(t, map[route.Vertex]InboundAccess{
+		testPub:  InboundAccessAllow,
+		testPub2: InboundAccessDeny,
+	}, access)
+
+	require.NoError(t, cdb.PutInboundAccess(testPub, InboundAccessNone))
+
+	access, err = cdb.FetchInboundAccess()
+	require.NoError(t, err)
+	require.Equal(t, map[route.Vertex]InboundAccess{
+		testPub2: InboundAccessDeny,
+	}, access)
+}
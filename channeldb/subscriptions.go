@@ -0,0 +1,167 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// subscriptionsBucket is the name of a top level bucket in which we
+	// store the recurring invoice subscriptions, keyed by their id.
+	//
+	// subscriptions-bucket
+	//      |
+	//      |-- <subscription-id>: <serialized subscription>
+	//      |
+	//      |-- <subscription-id>: <serialized subscription>
+	subscriptionsBucket = []byte("subscriptions-bucket")
+)
+
+// A compile-time check to ensure that DB implements the subscription store.
+var _ invoices.SubscriptionStore = (*DB)(nil)
+
+// PutSubscription adds the subscription to the database, or overwrites it if
+// it exists already.
+//
+// NOTE: This is part of the invoices.SubscriptionStore interface.
+func (d *DB) PutSubscription(sub *invoices.Subscription) error {
+	var b bytes.Buffer
+	if err := serializeSubscription(&b, sub); err != nil {
+		return err
+	}
+
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		// The bucket is created lazily, so that nodes that don't use
+		// subscriptions don't need a migration.
+		subs, err := tx.CreateTopLevelBucket(subscriptionsBucket)
+		if err != nil {
+			return err
+		}
+
+		return subs.Put(sub.ID[:], b.Bytes())
+	}, func() {})
+}
+
+// FetchSubscriptions returns all subscriptions stored in the database.
+//
+// NOTE: This is part of the invoices.SubscriptionStore interface.
+func (d *DB) FetchSubscriptions() ([]*invoices.Subscription, error) {
+	var subs []*invoices.Subscription
+
+	err := kvdb.View(d, func(tx kvdb.RTx) error {
+		subsBucket := tx.ReadBucket(subscriptionsBucket)
+		if subsBucket == nil {
+			return nil
+		}
+
+		return subsBucket.ForEach(func(_, v []byte) error {
+			sub, err := deserializeSubscription(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			subs = append(subs, sub)
+
+			return nil
+		})
+	}, func() {
+		subs = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
+
+// serializeSubscription writes the subscription to the given writer.
+func serializeSubscription(w io.Writer, sub *invoices.Subscription) error {
+	tmpl := &sub.Template
+
+	err := WriteElements(
+		w, [32]byte(sub.ID), sub.Secret, uint8(sub.State),
+		[]byte(tmpl.Memo), tmpl.Value, int64(tmpl.Period),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := serializeTime(w, tmpl.StartTime); err != nil {
+		return err
+	}
+
+	err = WriteElements(
+		w, tmpl.NumPeriods, int64(tmpl.Expiry), tmpl.CltvExpiry,
+		tmpl.Private, uint32(len(sub.Invoices)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, inv := range sub.Invoices {
+		err := WriteElements(w, inv.Period, [32]byte(inv.PaymentHash))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeSubscription reads a subscription from the given reader.
+func deserializeSubscription(r io.Reader) (*invoices.Subscription, error) {
+	var (
+		sub            invoices.Subscription
+		tmpl           = &sub.Template
+		id             [32]byte
+		state          uint8
+		memo           []byte
+		value          lnwire.MilliSatoshi
+		period, expiry int64
+		numInvoices    uint32
+	)
+
+	err := ReadElements(r, &id, &sub.Secret, &state, &memo, &value, &period)
+	if err != nil {
+		return nil, err
+	}
+
+	sub.ID = invoices.SubscriptionID(id)
+	sub.State = invoices.SubscriptionState(state)
+	tmpl.Memo = string(memo)
+	tmpl.Value = value
+	tmpl.Period = time.Duration(period)
+
+	tmpl.StartTime, err = deserializeTime(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ReadElements(
+		r, &tmpl.NumPeriods, &expiry, &tmpl.CltvExpiry, &tmpl.Private,
+		&numInvoices,
+	)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.Expiry = time.Duration(expiry)
+
+	sub.Invoices = make([]invoices.SubscriptionInvoice, numInvoices)
+	for i := range sub.Invoices {
+		var hash [32]byte
+		err := ReadElements(r, &sub.Invoices[i].Period, &hash)
+		if err != nil {
+			return nil, err
+		}
+
+		sub.Invoices[i].PaymentHash = lntypes.Hash(hash)
+	}
+
+	return &sub, nil
+}
@@ -0,0 +1,55 @@
+package channeldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSubscriptions tests writing and reading subscriptions to and from disk.
+func TestSubscriptions(t *testing.T) {
+	db, err := MakeTestDB(t)
+	require.NoError(t, err)
+
+	// Without any subscriptions, we expect an empty result.
+	subs, err := db.FetchSubscriptions()
+	require.NoError(t, err)
+	require.Empty(t, subs)
+
+	sub := &invoices.Subscription{
+		ID:     invoices.SubscriptionID{1},
+		Secret: [32]byte{2},
+		Template: invoices.SubscriptionTemplate{
+			Memo:       "monthly",
+			Value:      10_000,
+			Period:     time.Hour,
+			StartTime:  time.Unix(100, 23),
+			NumPeriods: 12,
+			Expiry:     time.Minute,
+			CltvExpiry: 80,
+			Private:    true,
+		},
+		State: invoices.SubscriptionActive,
+		Invoices: []invoices.SubscriptionInvoice{
+			{Period: 0, PaymentHash: lntypes.Hash{3}},
+			{Period: 2, PaymentHash: lntypes.Hash{4}},
+		},
+	}
+	require.NoError(t, db.PutSubscription(sub))
+
+	subs, err = db.FetchSubscriptions()
+	require.NoError(t, err)
+	require.Equal(t, []*invoices.Subscription{sub}, subs)
+
+	// Updating the subscription should overwrite the stored one.
+	sub.State = invoices.SubscriptionCanceled
+	sub.Invoices = sub.Invoices[:1]
+	require.NoError(t, db.PutSubscription(sub))
+
+	subs, err = db.FetchSubscriptions()
+	require.NoError(t, err)
+	require.Equal(t, []*invoices.Subscription{sub}, subs)
+}
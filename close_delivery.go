@@ -0,0 +1,107 @@
+package lnd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrMultipleDeliveryOutputs is returned if a delivery PSBT holds more than
+// one output. The cooperative close transaction pays each party to a single
+// output, so further outputs can't be negotiated with the remote party.
+var ErrMultipleDeliveryOutputs = errors.New("delivery PSBT must hold " +
+	"exactly one output")
+
+// CloseDelivery describes where our funds are paid to in a cooperative
+// close. At most one of its fields may be set. If neither is set, the upfront
+// shutdown script or a new wallet address is used.
+type CloseDelivery struct {
+	// Address is the address to pay our funds to.
+	Address string
+
+	// Psbt is a binary serialized PSBT without inputs. Its single output
+	// holds the script to pay our funds to, which may be a custom script
+	// that has no address encoding. The value of the output is ignored.
+	Psbt []byte
+}
+
+// deliveryScript returns the script to pay our funds to, or nil if the
+// default delivery script should be used. Whether the script matches the
+// upfront shutdown script of the channel is checked once the close starts.
+func (d *CloseDelivery) deliveryScript(
+	params *chaincfg.Params) (lnwire.DeliveryAddress, error) {
+
+	switch {
+	case len(d.Address) > 0 && len(d.Psbt) > 0:
+		return nil, errors.New("either a delivery address or a " +
+			"delivery PSBT can be set, but not both")
+
+	case len(d.Address) > 0:
+		addr, err := btcutil.DecodeAddress(d.Address, params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid delivery address: %w",
+				err)
+		}
+
+		if !addr.IsForNet(params) {
+			return nil, fmt.Errorf("delivery address is not for %s",
+				params.Name)
+		}
+
+		return txscript.PayToAddrScript(addr)
+
+	case len(d.Psbt) > 0:
+		packet, err := psbt.NewFromRawBytes(
+			bytes.NewReader(d.Psbt), false,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing delivery PSBT: "+
+				"%w", err)
+		}
+
+		if len(packet.UnsignedTx.TxIn) != 0 {
+			return nil, errors.New("delivery PSBT must not have " +
+				"inputs")
+		}
+		if len(packet.UnsignedTx.TxOut) != 1 {
+			return nil, ErrMultipleDeliveryOutputs
+		}
+
+		script := packet.UnsignedTx.TxOut[0].PkScript
+		if !isValidDeliveryScript(script) {
+			return nil, fmt.Errorf("delivery script %x is not a "+
+				"valid shutdown script", script)
+		}
+
+		return script, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// isValidDeliveryScript returns true if the script may be used as the
+// shutdown script of a cooperative close, which BOLT #2 limits to the
+// standard script types and future segwit versions.
+func isValidDeliveryScript(script []byte) bool {
+	switch txscript.GetScriptClass(script) {
+	case txscript.PubKeyHashTy, txscript.ScriptHashTy,
+		txscript.WitnessV0PubKeyHashTy, txscript.WitnessV0ScriptHashTy,
+		txscript.WitnessV1TaprootTy:
+
+		return true
+	}
+
+	version, _, err := txscript.ExtractWitnessProgramInfo(script)
+	if err != nil {
+		return false
+	}
+
+	return version >= 1 && version <= 16
+}
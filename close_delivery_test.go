@@ -0,0 +1,157 @@
+package lnd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/peer"
+	"github.com/stretchr/testify/require"
+)
+
+// deliveryPsbt returns a serialized PSBT with the given inputs and output
+// scripts.
+func deliveryPsbt(t *testing.T, inputs []*wire.OutPoint,
+	scripts ...[]byte) []byte {
+
+	outputs := make([]*wire.TxOut, 0, len(scripts))
+	for _, script := range scripts {
+		outputs = append(outputs, wire.NewTxOut(1000, script))
+	}
+	sequences := make([]uint32, len(inputs))
+
+	packet, err := psbt.New(inputs, outputs, 2, 0, sequences)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, packet.Serialize(&b))
+
+	return b.Bytes()
+}
+
+// TestCloseDeliveryScript tests the parsing of the delivery of a cooperative
+// close.
+func TestCloseDeliveryScript(t *testing.T) {
+	t.Parallel()
+
+	const (
+		regtestAddr = "bcrt1qqyqszqgpqyqszqgpqyqszqgpqyqszqgpvxat9t"
+		mainnetAddr = "bc1qqyqszqgpqyqszqgpqyqszqgpqyqszqgpyfl4f3"
+	)
+
+	params := &chaincfg.RegressionNetParams
+
+	p2wkh := append([]byte{txscript.OP_0, txscript.OP_DATA_20},
+		bytes.Repeat([]byte{1}, 20)...)
+
+	// A segwit v2 program is valid under option_shutdown_anysegwit even
+	// though it has no standard script class yet.
+	segwitV2 := append([]byte{txscript.OP_2, txscript.OP_DATA_32},
+		bytes.Repeat([]byte{2}, 32)...)
+
+	bareMultiSig := []byte{
+		txscript.OP_1, txscript.OP_1, txscript.OP_CHECKMULTISIG,
+	}
+
+	testCases := []struct {
+		name     string
+		delivery *CloseDelivery
+		script   []byte
+		err      bool
+	}{
+		{
+			name:     "default",
+			delivery: &CloseDelivery{},
+		},
+		{
+			name: "address",
+			delivery: &CloseDelivery{
+				Address: regtestAddr,
+			},
+			script: p2wkh,
+		},
+		{
+			name: "address for other network",
+			delivery: &CloseDelivery{
+				Address: mainnetAddr,
+			},
+			err: true,
+		},
+		{
+			name: "psbt",
+			delivery: &CloseDelivery{
+				Psbt: deliveryPsbt(t, nil, p2wkh),
+			},
+			script: p2wkh,
+		},
+		{
+			name: "psbt with future segwit version",
+			delivery: &CloseDelivery{
+				Psbt: deliveryPsbt(t, nil, segwitV2),
+			},
+			script: segwitV2,
+		},
+		{
+			name: "psbt with non-standard script",
+			delivery: &CloseDelivery{
+				Psbt: deliveryPsbt(t, nil, bareMultiSig),
+			},
+			err: true,
+		},
+		{
+			name: "psbt with multiple outputs",
+			delivery: &CloseDelivery{
+				Psbt: deliveryPsbt(t, nil, p2wkh, segwitV2),
+			},
+			err: true,
+		},
+		{
+			name: "psbt with inputs",
+			delivery: &CloseDelivery{
+				Psbt: deliveryPsbt(
+					t, []*wire.OutPoint{{Index: 1}}, p2wkh,
+				),
+			},
+			err: true,
+		},
+		{
+			name: "address and psbt",
+			delivery: &CloseDelivery{
+				Address: regtestAddr,
+				Psbt:    deliveryPsbt(t, nil, p2wkh),
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		script, err := tc.delivery.deliveryScript(params)
+		if tc.err {
+			require.Error(t, err, tc.name)
+			continue
+		}
+
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.script, []byte(script), tc.name)
+	}
+}
+
+// TestCloseNegotiationRPCUpdate asserts that the rounds of the closing fee
+// negotiation are streamed to the caller of CloseChannel.
+func TestCloseNegotiationRPCUpdate(t *testing.T) {
+	t.Parallel()
+
+	update, err := createRPCCloseUpdate(&peer.CloseNegotiationUpdate{
+		LocalFee:  1_000,
+		RemoteFee: 1_200,
+	})
+	require.NoError(t, err)
+
+	feeUpdate := update.GetFeeUpdate()
+	require.NotNil(t, feeUpdate)
+	require.EqualValues(t, 1_000, feeUpdate.LocalFeeSat)
+	require.EqualValues(t, 1_200, feeUpdate.RemoteFeeSat)
+}
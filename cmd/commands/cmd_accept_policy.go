@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var acceptPolicyCommand = cli.Command{
+	Name:     "acceptpolicy",
+	Category: "Channels",
+	Usage:    "Manage the policies inbound channels are evaluated by.",
+	Subcommands: []cli.Command{
+		listAcceptPoliciesCommand,
+		updateAcceptPoliciesCommand,
+		listAcceptDecisionsCommand,
+	},
+}
+
+var listAcceptPoliciesCommand = cli.Command{
+	Name:   "list",
+	Usage:  "List the channel accept policies in evaluation order.",
+	Action: actionDecorator(listAcceptPolicies),
+}
+
+func listAcceptPolicies(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListChannelAcceptPolicies(
+		ctxc, &lnrpc.ListChannelAcceptPoliciesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var updateAcceptPoliciesCommand = cli.Command{
+	Name:  "update",
+	Usage: "Change the channel accept policies.",
+	Description: `
+	Reorder, change, enable and disable the policies inbound channels are
+	evaluated by. Changing the parameters of a policy doesn't enable it. The
+	changes are lost on restart; use the acceptpolicy options to make them
+	permanent.
+	`,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name: "order",
+			Usage: "(optional) the new evaluation order, " +
+				"which must name all policies exactly " +
+				"once; specify the flag once per policy",
+		},
+		cli.StringSliceFlag{
+			Name: "enable",
+			Usage: "(optional) the name of a policy to " +
+				"enable, can be specified multiple times",
+		},
+		cli.StringSliceFlag{
+			Name: "disable",
+			Usage: "(optional) the name of a policy to disable, " +
+				"can be specified multiple times",
+		},
+		cli.Uint64Flag{
+			Name: "min_chan_size",
+			Usage: "(optional) the new minimum channel size in " +
+				"satoshis of the minsize policy",
+		},
+		cli.Int64Flag{
+			Name: "min_peer_reputation_msat",
+			Usage: "(optional) the new minimum forwarding " +
+				"reputation in milli-satoshis of the " +
+				"reputation policy",
+		},
+		cli.Uint64Flag{
+			Name: "max_fee_rate",
+			Usage: "(optional) the new maximum on-chain fee rate " +
+				"in sat/vbyte of the feerate policy",
+		},
+	},
+	Action: actionDecorator(updateAcceptPolicies),
+}
+
+func updateAcceptPolicies(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NumFlags() == 0 {
+		return cli.ShowCommandHelp(ctx, "update")
+	}
+
+	req := &lnrpc.UpdateChannelAcceptPoliciesRequest{
+		Order:   ctx.StringSlice("order"),
+		Enable:  ctx.StringSlice("enable"),
+		Disable: ctx.StringSlice("disable"),
+	}
+
+	if ctx.IsSet("min_chan_size") {
+		minChanSize := ctx.Uint64("min_chan_size")
+		req.MinChanSizeSat = &minChanSize
+	}
+
+	if ctx.IsSet("min_peer_reputation_msat") {
+		minReputation := ctx.Int64("min_peer_reputation_msat")
+		req.MinPeerReputationMsat = &minReputation
+	}
+
+	if ctx.IsSet("max_fee_rate") {
+		maxFeeRate := ctx.Uint64("max_fee_rate")
+		req.MaxFeeRateSatPerVbyte = &maxFeeRate
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.UpdateChannelAcceptPolicies(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listAcceptDecisionsCommand = cli.Command{
+	Name:  "decisions",
+	Usage: "List the most recent decisions of the accept policies.",
+	Description: `
+	List the most recent decisions of the channel accept policies, oldest
+	first. The number of decisions kept is set by the
+	acceptpolicy.maxdecisions option.
+	`,
+	Action: actionDecorator(listAcceptDecisions),
+}
+
+func listAcceptDecisions(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListChannelAcceptDecisions(
+		ctxc, &lnrpc.ListChannelAcceptDecisionsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
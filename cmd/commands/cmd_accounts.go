@@ -0,0 +1,227 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var offChainAccountsCommand = cli.Command{
+	Name:     "accounts",
+	Category: "Accounts",
+	Usage:    "Manage the off-chain accounts of the node.",
+	Description: `
+	Manage the off-chain accounts that split the balance of the node between
+	several applications. Requires lnd to be started with accounts.active.
+	`,
+	Subcommands: []cli.Command{
+		createAccountCommand,
+		listOffChainAccountsCommand,
+		adjustAccountCommand,
+		accountLedgerCommand,
+		removeAccountCommand,
+	},
+}
+
+var createAccountCommand = cli.Command{
+	Name:  "create",
+	Usage: "Create an account and a macaroon that is bound to it.",
+	Description: `
+	Create an off-chain account with the given initial balance. The
+	returned macaroon is bound to the account, so it can only spend the
+	balance of the account and is restricted to the RPCs that are
+	accounted for.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "label",
+			Usage: "a human readable description of the account",
+		},
+		cli.Uint64Flag{
+			Name:  "balance",
+			Usage: "the initial balance of the account in satoshis",
+		},
+		cli.Uint64Flag{
+			Name: "balance_msat",
+			Usage: "the initial balance of the account in " +
+				"millisatoshis",
+		},
+	},
+	Action: actionDecorator(createAccount),
+}
+
+func createAccount(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.IsSet("balance") && ctx.IsSet("balance_msat") {
+		return fmt.Errorf("only one of balance and balance_msat can " +
+			"be set")
+	}
+
+	balance := ctx.Uint64("balance_msat")
+	if ctx.IsSet("balance") {
+		balance = ctx.Uint64("balance") * 1000
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.CreateAccount(ctxc, &lnrpc.CreateAccountRequest{
+		Label:       ctx.String("label"),
+		BalanceMsat: balance,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listOffChainAccountsCommand = cli.Command{
+	Name:   "list",
+	Usage:  "List all accounts.",
+	Action: actionDecorator(listOffChainAccounts),
+}
+
+func listOffChainAccounts(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListAccounts(ctxc, &lnrpc.ListAccountsRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var adjustAccountCommand = cli.Command{
+	Name:      "adjust",
+	Usage:     "Credit or debit the balance of an account.",
+	ArgsUsage: "account_id",
+	Description: `
+	Change the balance of the account with the given id by the given
+	amount, which is negative for debits. A debit must not exceed the
+	balance of the account.
+	`,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "the amount in satoshis to change the " +
+				"balance by",
+		},
+		cli.Int64Flag{
+			Name: "amt_msat",
+			Usage: "the amount in millisatoshis to change the " +
+				"balance by",
+		},
+	},
+	Action: actionDecorator(adjustAccount),
+}
+
+func adjustAccount(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "adjust")
+	}
+
+	if ctx.IsSet("amt") == ctx.IsSet("amt_msat") {
+		return fmt.Errorf("exactly one of amt and amt_msat must be set")
+	}
+
+	amt := ctx.Int64("amt_msat")
+	if ctx.IsSet("amt") {
+		amt = ctx.Int64("amt") * 1000
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.AdjustAccountBalance(
+		ctxc, &lnrpc.AdjustAccountBalanceRequest{
+			Id:         ctx.Args().First(),
+			AmountMsat: amt,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var accountLedgerCommand = cli.Command{
+	Name:      "ledger",
+	Usage:     "List the balance changes of an account.",
+	ArgsUsage: "account_id",
+	Description: `
+	List the balance changes of the account with the given id, oldest
+	first. Besides manual adjustments, the balance changes when invoices
+	of the account are paid and payments of the account succeed.
+	`,
+	Action: actionDecorator(accountLedger),
+}
+
+func accountLedger(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "ledger")
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.AccountLedger(ctxc, &lnrpc.AccountLedgerRequest{
+		Id: ctx.Args().First(),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var removeAccountCommand = cli.Command{
+	Name:      "remove",
+	Usage:     "Remove an account.",
+	ArgsUsage: "account_id",
+	Description: `
+	Remove the account with the given id. Macaroons that are bound to the
+	account are rejected afterwards.
+	`,
+	Action: actionDecorator(removeAccount),
+}
+
+func removeAccount(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "remove")
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.RemoveAccount(ctxc, &lnrpc.RemoveAccountRequest{
+		Id: ctx.Args().First(),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var channelTemplateCommand = cli.Command{
+	Name:     "channeltemplate",
+	Category: "Channels",
+	Usage: "Manage the channel templates that channels can be opened " +
+		"with.",
+	Subcommands: []cli.Command{
+		listChannelTemplatesCommand,
+		setChannelTemplateCommand,
+		deleteChannelTemplateCommand,
+	},
+}
+
+var listChannelTemplatesCommand = cli.Command{
+	Name:   "list",
+	Usage:  "List the channel templates stored by the node.",
+	Action: actionDecorator(listChannelTemplates),
+}
+
+func listChannelTemplates(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListChannelTemplates(
+		ctxc, &lnrpc.ListChannelTemplatesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var setChannelTemplateCommand = cli.Command{
+	Name:      "set",
+	Usage:     "Store a channel template.",
+	ArgsUsage: "name",
+	Description: `
+	Store a channel template under the given name, replacing the template
+	stored under it before. The parameters of the template are used when
+	opening a channel with the --template flag of openchannel or
+	batchopenchannel, unless they're set explicitly. Channels that were
+	opened with the template before aren't changed.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "channel_type",
+			Usage: fmt.Sprintf("(optional) the type of channel to "+
+				"propose to the remote peer (%q, %q, %q)",
+				channelTypeTweakless, channelTypeAnchors,
+				channelTypeSimpleTaproot),
+		},
+		cli.BoolFlag{
+			Name:  "private",
+			Usage: "make the channels private",
+		},
+		cli.BoolFlag{
+			Name:  "zero_conf",
+			Usage: "attempt zero-conf channel opens",
+		},
+		cli.BoolFlag{
+			Name:  "scid_alias",
+			Usage: "negotiate the scid-alias channel type",
+		},
+		cli.Uint64Flag{
+			Name: "base_fee_msat",
+			Usage: "(optional) the base fee in milli-satoshis of " +
+				"the channels",
+		},
+		cli.Uint64Flag{
+			Name: "fee_rate_ppm",
+			Usage: "(optional) the fee rate ppm (parts per " +
+				"million) of the channels",
+		},
+		cli.Uint64Flag{
+			Name: "remote_csv_delay",
+			Usage: "(optional) the number of blocks we will " +
+				"require our channel counterparty to wait " +
+				"before accessing its funds in case of " +
+				"unilateral close",
+		},
+		cli.Uint64Flag{
+			Name: "max_local_csv",
+			Usage: "(optional) the maximum number of blocks that " +
+				"we will allow the remote peer to require we " +
+				"wait before accessing our funds in the case " +
+				"of a unilateral close",
+		},
+		cli.Uint64Flag{
+			Name: "remote_reserve_sats",
+			Usage: "(optional) the minimum number of satoshis we " +
+				"require the remote node to keep as a direct " +
+				"payment",
+		},
+		cli.Uint64Flag{
+			Name: "remote_max_htlcs",
+			Usage: "(optional) the maximum number of htlcs the " +
+				"remote node may offer us",
+		},
+		cli.Uint64Flag{
+			Name: "min_htlc_msat",
+			Usage: "(optional) the minimum value we will require " +
+				"for incoming HTLCs on the channels",
+		},
+		cli.Uint64Flag{
+			Name: "remote_max_value_in_flight_msat",
+			Usage: "(optional) the maximum value in msat that " +
+				"can be pending within the channels at any " +
+				"given time",
+		},
+	},
+	Action: actionDecorator(setChannelTemplate),
+}
+
+func setChannelTemplate(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "set")
+	}
+
+	tmpl := &lnrpc.ChannelTemplate{
+		Name:                 ctx.Args().First(),
+		Private:              ctx.Bool("private"),
+		ZeroConf:             ctx.Bool("zero_conf"),
+		ScidAlias:            ctx.Bool("scid_alias"),
+		UseBaseFee:           ctx.IsSet("base_fee_msat"),
+		BaseFee:              ctx.Uint64("base_fee_msat"),
+		UseFeeRate:           ctx.IsSet("fee_rate_ppm"),
+		FeeRate:              ctx.Uint64("fee_rate_ppm"),
+		RemoteCsvDelay:       uint32(ctx.Uint64("remote_csv_delay")),
+		MaxLocalCsv:          uint32(ctx.Uint64("max_local_csv")),
+		RemoteChanReserveSat: ctx.Uint64("remote_reserve_sats"),
+		RemoteMaxHtlcs:       uint32(ctx.Uint64("remote_max_htlcs")),
+		MinHtlcMsat:          ctx.Uint64("min_htlc_msat"),
+		RemoteMaxValueInFlightMsat: ctx.Uint64(
+			"remote_max_value_in_flight_msat",
+		),
+	}
+
+	channelType := ctx.String("channel_type")
+	switch channelType {
+	case "":
+		break
+	case channelTypeTweakless:
+		tmpl.CommitmentType = lnrpc.CommitmentType_STATIC_REMOTE_KEY
+	case channelTypeAnchors:
+		tmpl.CommitmentType = lnrpc.CommitmentType_ANCHORS
+	case channelTypeSimpleTaproot:
+		tmpl.CommitmentType = lnrpc.CommitmentType_SIMPLE_TAPROOT
+	default:
+		return fmt.Errorf("unsupported channel type %v", channelType)
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.SetChannelTemplate(
+		ctxc, &lnrpc.SetChannelTemplateRequest{Template: tmpl},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var deleteChannelTemplateCommand = cli.Command{
+	Name:      "delete",
+	Usage:     "Delete a channel template.",
+	ArgsUsage: "name",
+	Action:    actionDecorator(deleteChannelTemplate),
+}
+
+func deleteChannelTemplate(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "delete")
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.DeleteChannelTemplate(
+		ctxc, &lnrpc.DeleteChannelTemplateRequest{
+			Name: ctx.Args().First(),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var dustExposureCommand = cli.Command{
+	Name:     "dustexposure",
+	Category: "Channels",
+	Usage:    "Show the dust exposure of our channels.",
+	Description: `
+	Show the dust exposure of the channels that have an active link, or of
+	a single channel if its id is given. The exposure is reported for both
+	commitments, and includes the dust htlcs that are still waiting to be
+	added to the channel.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "chan_id",
+			Usage: "the short channel id of the channel to show " +
+				"the dust exposure of",
+		},
+	},
+	Action: actionDecorator(dustExposure),
+}
+
+func dustExposure(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.DustExposureRequest{
+		ChanId: ctx.Uint64("chan_id"),
+	}
+	resp, err := client.DustExposure(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var updateMaxFeeExposureCommand = cli.Command{
+	Name:     "updatemaxfeeexposure",
+	Category: "Channels",
+	Usage:    "Update the threshold after which dust htlcs are rejected.",
+	Description: `
+	Update the threshold after which new dust htlcs are rejected on our
+	channels, which is initially set by dust-threshold. The new threshold
+	applies until lnd is restarted. Htlcs that are already committed are
+	not affected.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "max_fee_exposure_msat",
+			Usage: "the threshold after which new dust htlcs are " +
+				"rejected in msat",
+		},
+	},
+	Action: actionDecorator(updateMaxFeeExposure),
+}
+
+func updateMaxFeeExposure(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	threshold := ctx.Uint64("max_fee_exposure_msat")
+	if threshold == 0 {
+		return errors.New("max_fee_exposure_msat must be set")
+	}
+
+	req := &routerrpc.UpdateMaxFeeExposureRequest{
+		MaxFeeExposureMsat: threshold,
+	}
+	resp, err := client.UpdateMaxFeeExposure(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
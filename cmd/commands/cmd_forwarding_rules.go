@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var forwardingRulesCommand = cli.Command{
+	Name:     "forwardingrules",
+	Category: "Channels",
+	Usage:    "Manage the rules that restrict forwarded HTLCs.",
+	Subcommands: []cli.Command{
+		listForwardingRulesCommand,
+		setForwardingRulesCommand,
+	},
+}
+
+var listForwardingRulesCommand = cli.Command{
+	Name:   "list",
+	Usage:  "List the active forwarding rules.",
+	Action: actionDecorator(listForwardingRules),
+}
+
+func listForwardingRules(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListForwardingRules(
+		ctxc, &lnrpc.ListForwardingRulesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var setForwardingRulesCommand = cli.Command{
+	Name:      "set",
+	Usage:     "Replace the active forwarding rules.",
+	ArgsUsage: "rules_file",
+	Description: `
+	Replace the active forwarding rules with the rules of the given JSON
+	file, which has the same format as the file of the
+	htlcswitch.forwardingrulesfile option, e.g.:
+
+	{
+		"rules": [{
+			"chan_id": "123456789",
+			"deny": true
+		}, {
+			"peer": "03abc...",
+			"max_forwards": 10,
+			"rate_interval_seconds": 60
+		}]
+	}
+
+	Rate limits start afresh. Use --clear instead of a file to remove all
+	rules.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "clear",
+			Usage: "remove all forwarding rules",
+		},
+	},
+	Action: actionDecorator(setForwardingRules),
+}
+
+func setForwardingRules(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	req := &lnrpc.SetForwardingRulesRequest{}
+	switch {
+	case ctx.Bool("clear") && ctx.NArg() == 0:
+
+	case !ctx.Bool("clear") && ctx.NArg() == 1:
+		rulesJSON, err := os.ReadFile(ctx.Args().First())
+		if err != nil {
+			return fmt.Errorf("unable to read rules file: %w", err)
+		}
+
+		err = lnrpc.ProtoJSONUnmarshalOpts.Unmarshal(rulesJSON, req)
+		if err != nil {
+			return fmt.Errorf("unable to parse rules file: %w", err)
+		}
+
+	default:
+		return cli.ShowCommandHelp(ctx, "set")
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.SetForwardingRules(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
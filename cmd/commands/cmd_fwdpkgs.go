@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var fwdPkgsCommand = cli.Command{
+	Name:     "fwdpkgs",
+	Category: "Channels",
+	Usage:    "List the forwarding packages of a channel.",
+	Description: `
+	List the forwarding packages of a channel that haven't been removed
+	yet. A forwarding package holds the updates that the remote party
+	locked in at a given height, and tracks which of them have been
+	forwarded and acked. Packages that remain in the same state for long
+	may indicate that the channel is wedged.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the channel to list the forwarding packages " +
+				"of, in the form of txid:output_index",
+		},
+	},
+	Action: actionDecorator(fwdPkgs),
+}
+
+func fwdPkgs(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if !ctx.IsSet("chan_point") {
+		return errors.New("chan_point must be set")
+	}
+
+	chanPoint, err := parseChanPoint(ctx.String("chan_point"))
+	if err != nil {
+		return fmt.Errorf("unable to parse chan_point: %w", err)
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.ForwardingPackagesRequest{
+		ChanPoint: chanPoint,
+	}
+	resp, err := client.ForwardingPackages(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var reprocessFwdPkgCommand = cli.Command{
+	Name:     "reprocessfwdpkg",
+	Category: "Channels",
+	Usage:    "Replay a forwarding package of a channel.",
+	Description: `
+	Replay the forwarding package at the given height of a channel, just
+	like the channel does for all of its packages when it is started. This
+	is safe, since duplicate updates are filtered out, and can be used to
+	unblock a wedged forwarding package without restarting lnd. Completed
+	packages can't be replayed, and neither can packages of a quiescent
+	channel.`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "chan_point",
+			Usage: "the channel whose forwarding package should " +
+				"be replayed, in the form of txid:output_index",
+		},
+		cli.Uint64Flag{
+			Name:  "height",
+			Usage: "the height of the forwarding package to replay",
+		},
+	},
+	Action: actionDecorator(reprocessFwdPkg),
+}
+
+func reprocessFwdPkg(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if !ctx.IsSet("chan_point") || !ctx.IsSet("height") {
+		return errors.New("chan_point and height must be set")
+	}
+
+	chanPoint, err := parseChanPoint(ctx.String("chan_point"))
+	if err != nil {
+		return fmt.Errorf("unable to parse chan_point: %w", err)
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.ReprocessForwardingPackageRequest{
+		ChanPoint: chanPoint,
+		Height:    ctx.Uint64("height"),
+	}
+	resp, err := client.ReprocessForwardingPackage(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
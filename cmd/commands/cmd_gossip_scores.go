@@ -0,0 +1,36 @@
+package commands
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var gossipScoresCommand = cli.Command{
+	Name:     "gossipscores",
+	Category: "Peers",
+	Usage:    "List the gossip reputation of our peers.",
+	Description: `
+	List the gossip reputation of the peers we received gossip from,
+	ordered from the lowest to the highest score. Each entry holds the rate
+	of gossip messages the peer is currently allowed to send, and the number
+	of its messages that were accepted, stale, invalid or throttled.
+	`,
+	Action: actionDecorator(gossipScores),
+}
+
+func gossipScores(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListGossipPeerScores(
+		ctxc, &lnrpc.ListGossipPeerScoresRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/lightningnetwork/lnd"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var exportGraphSnapshotCommand = cli.Command{
+	Name:      "exportgraphsnapshot",
+	Category:  "Graph",
+	Usage:     "Export a signed snapshot of the channel graph.",
+	ArgsUsage: "output_file",
+	Description: `
+	Export a snapshot of the public channel graph, signed with the node key,
+	to the given file. Other nodes can bootstrap their graph from the file
+	by setting it as their gossip.snapshot-source and trusting this node
+	with gossip.snapshot-signer.
+	`,
+	Action: actionDecorator(exportGraphSnapshot),
+}
+
+func exportGraphSnapshot(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "exportgraphsnapshot")
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ExportGraphSnapshot(
+		ctxc, &lnrpc.ExportGraphSnapshotRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	fileName := lnd.CleanAndExpandPath(ctx.Args().First())
+
+	return os.WriteFile(fileName, resp.Snapshot, 0600)
+}
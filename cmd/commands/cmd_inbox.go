@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var inboxCommand = cli.Command{
+	Name:     "inbox",
+	Category: "Invoices",
+	Usage:    "Read the messages received with keysend payments.",
+	Description: `
+	Read the messages received with keysend payments. Requires the
+	invoices.keysendinbox option to be set.
+	`,
+	Subcommands: []cli.Command{
+		listInboxCommand,
+		ackInboxCommand,
+		subscribeInboxCommand,
+	},
+}
+
+var listInboxCommand = cli.Command{
+	Name:  "list",
+	Usage: "List the messages of the keysend inbox.",
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "index_offset",
+			Usage: "(optional) the id of the last message that " +
+				"was seen, only messages with a higher id are " +
+				"returned",
+		},
+		cli.Uint64Flag{
+			Name: "max_messages",
+			Usage: "(optional) the maximum number of messages to " +
+				"return",
+		},
+		cli.BoolFlag{
+			Name: "unacked_only",
+			Usage: "only return the messages that haven't been " +
+				"acknowledged",
+		},
+	},
+	Action: actionDecorator(listInbox),
+}
+
+func listInbox(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListInboxMessages(
+		ctxc, &lnrpc.ListInboxMessagesRequest{
+			IndexOffset: ctx.Uint64("index_offset"),
+			MaxMessages: uint32(ctx.Uint64("max_messages")),
+			UnackedOnly: ctx.Bool("unacked_only"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var ackInboxCommand = cli.Command{
+	Name:      "ack",
+	Usage:     "Acknowledge messages of the keysend inbox.",
+	ArgsUsage: "id [id...]",
+	Action:    actionDecorator(ackInbox),
+}
+
+func ackInbox(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() == 0 {
+		return cli.ShowCommandHelp(ctx, "ack")
+	}
+
+	ids := make([]uint64, 0, ctx.NArg())
+	for _, arg := range ctx.Args() {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid message id %v: %w", arg,
+				err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.AckInboxMessages(
+		ctxc, &lnrpc.AckInboxMessagesRequest{Ids: ids},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var subscribeInboxCommand = cli.Command{
+	Name:   "subscribe",
+	Usage:  "Print new messages of the keysend inbox as they arrive.",
+	Action: actionDecorator(subscribeInbox),
+}
+
+func subscribeInbox(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeInbox(
+		ctxc, &lnrpc.SubscribeInboxRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(msg)
+	}
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"strconv"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -116,6 +117,57 @@ var AddInvoiceCommand = cli.Command{
 				"use on a blinded path. The flag may be " +
 				"specified multiple times.",
 		},
+		cli.Uint64Flag{
+			Name: "partial_tolerance_msat",
+			Usage: "allow an MPP payment to settle the " +
+				"invoice if it falls short of the invoice " +
+				"amount by at most this many millisatoshis",
+		},
+		cli.Uint64Flag{
+			Name: "partial_min_settle_msat",
+			Usage: "allow an MPP payment to settle the invoice " +
+				"once at least this many millisatoshis have " +
+				"been received",
+		},
+		cli.Uint64Flag{
+			Name: "max_htlcs",
+			Usage: "the maximum number of htlcs that may pay the " +
+				"invoice",
+		},
+		cli.Uint64Flag{
+			Name: "min_htlc_msat",
+			Usage: "the minimum amount in millisatoshis of every " +
+				"htlc that pays the invoice",
+		},
+		cli.StringSliceFlag{
+			Name: "allowed_chan_id",
+			Usage: "the short channel id of an incoming channel " +
+				"that htlcs paying the invoice may arrive " +
+				"on. The flag may be specified multiple times.",
+		},
+		cli.StringSliceFlag{
+			Name: "allowed_peer",
+			Usage: "the pub key (in hex) of a peer that htlcs " +
+				"paying the invoice may be received from. " +
+				"The flag may be specified multiple times.",
+		},
+		cli.StringFlag{
+			Name: "fiat_currency",
+			Usage: "the ISO 4217 code of the currency to quote " +
+				"the fiat price of the invoice in. Unless " +
+				"--fiat_amount is set, the price is quoted " +
+				"by the configured fiat rate provider",
+		},
+		cli.Uint64Flag{
+			Name: "fiat_amount",
+			Usage: "the quoted fiat price of the invoice in the " +
+				"minor unit of --fiat_currency, e.g. cents",
+		},
+		cli.StringFlag{
+			Name: "fiat_rate_source",
+			Usage: "the source of the exchange rate " +
+				"--fiat_amount is based on",
+		},
 	},
 	Action: actionDecorator(addInvoice),
 }
@@ -187,6 +239,35 @@ func addInvoice(ctx *cli.Context) error {
 		BlindedPathConfig: blindedPathCfg,
 	}
 
+	if ctx.IsSet("partial_tolerance_msat") ||
+		ctx.IsSet("partial_min_settle_msat") {
+
+		invoice.PartialPaymentPolicy = &lnrpc.PartialPaymentPolicy{
+			ToleranceMsat: ctx.Uint64("partial_tolerance_msat"),
+			MinSettleMsat: ctx.Uint64("partial_min_settle_msat"),
+		}
+	}
+
+	switch {
+	case ctx.IsSet("fiat_amount"):
+		invoice.FiatQuote = &lnrpc.FiatQuote{
+			Currency:   ctx.String("fiat_currency"),
+			Amount:     ctx.Uint64("fiat_amount"),
+			RateSource: ctx.String("fiat_rate_source"),
+		}
+
+	case ctx.IsSet("fiat_rate_source"):
+		return fmt.Errorf("fiat_rate_source requires fiat_amount")
+
+	default:
+		invoice.FiatCurrency = ctx.String("fiat_currency")
+	}
+
+	invoice.HtlcConstraints, err = parseInvoiceHtlcConstraints(ctx)
+	if err != nil {
+		return fmt.Errorf("could not parse htlc constraints: %w", err)
+	}
+
 	resp, err := client.AddInvoice(ctxc, invoice)
 	if err != nil {
 		return err
@@ -197,6 +278,54 @@ func addInvoice(ctx *cli.Context) error {
 	return nil
 }
 
+// parseInvoiceHtlcConstraints parses the htlc constraints of an invoice from
+// the command line flags. It returns nil if no constraint is set.
+func parseInvoiceHtlcConstraints(
+	ctx *cli.Context) (*lnrpc.InvoiceHtlcConstraints, error) {
+
+	if !ctx.IsSet("max_htlcs") && !ctx.IsSet("min_htlc_msat") &&
+		!ctx.IsSet("allowed_chan_id") && !ctx.IsSet("allowed_peer") {
+
+		return nil, nil
+	}
+
+	maxHtlcs := ctx.Uint64("max_htlcs")
+	if maxHtlcs > math.MaxUint32 {
+		return nil, fmt.Errorf("max_htlcs %d is too large", maxHtlcs)
+	}
+
+	constraints := &lnrpc.InvoiceHtlcConstraints{
+		MaxHtlcs:    uint32(maxHtlcs),
+		MinHtlcMsat: ctx.Uint64("min_htlc_msat"),
+	}
+
+	for _, chanIDStr := range ctx.StringSlice("allowed_chan_id") {
+		chanID, err := strconv.ParseUint(chanIDStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid channel id %v: %w",
+				chanIDStr, err)
+		}
+
+		constraints.AllowedChanIds = append(
+			constraints.AllowedChanIds, chanID,
+		)
+	}
+
+	for _, peerStr := range ctx.StringSlice("allowed_peer") {
+		peer, err := hex.DecodeString(peerStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer %v: %w", peerStr,
+				err)
+		}
+
+		constraints.AllowedPeers = append(
+			constraints.AllowedPeers, peer,
+		)
+	}
+
+	return constraints, nil
+}
+
 func parseBlindedPathCfg(ctx *cli.Context) (*lnrpc.BlindedPathConfig, error) {
 	if !ctx.Bool("blind") {
 		if ctx.IsSet("min_real_blinded_hops") ||
@@ -348,6 +477,8 @@ var listInvoicesCommand = cli.Command{
 				"invoices with creation date less than or " +
 				"equal to it",
 		},
+		pagedFlag,
+		pageCursorFlag,
 	},
 	Action: actionDecorator(listInvoices),
 }
@@ -364,6 +495,8 @@ func listInvoices(ctx *cli.Context) error {
 		Reversed:          !ctx.Bool("paginate-forwards"),
 		CreationDateStart: ctx.Uint64("creation_date_start"),
 		CreationDateEnd:   ctx.Uint64("creation_date_end"),
+		Paginate:          isPaged(ctx),
+		PageCursor:        ctx.String(pageCursorFlag.Name),
 	}
 
 	invoices, err := client.ListInvoices(ctxc, req)
@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var jobsCommand = cli.Command{
+	Name:     "jobs",
+	Category: "Jobs",
+	Usage:    "Manage the jobs of long-running operations.",
+	Subcommands: []cli.Command{
+		listJobsCommand,
+		subscribeJobCommand,
+		cancelJobCommand,
+	},
+}
+
+var listJobsCommand = cli.Command{
+	Name:  "list",
+	Usage: "List the jobs of long-running operations.",
+	Description: `
+	List the jobs of long-running operations, e.g. channel closes that were
+	run with --as_job, ordered by their id. Jobs are persisted, so jobs of
+	previous runs of lnd are listed as well.
+	`,
+	Action: actionDecorator(listJobs),
+}
+
+func listJobs(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListJobs(ctxc, &lnrpc.ListJobsRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var subscribeJobCommand = cli.Command{
+	Name:      "subscribe",
+	Usage:     "Print the events of a job.",
+	ArgsUsage: "job_id",
+	Description: `
+	Print the events of the job with the given id, starting at the event
+	with the given index. Events that were already emitted are printed
+	first. The command returns once the job reached a final state and all
+	its events were printed. The events of a closechannel job hold binary
+	serialized CloseStatusUpdate messages.
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name:  "from_index",
+			Usage: "(optional) the index of the first event to print",
+		},
+	},
+	Action: actionDecorator(subscribeJob),
+}
+
+func subscribeJob(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "subscribe")
+	}
+
+	jobID, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unable to parse job id: %w", err)
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeJobEvents(
+		ctxc, &lnrpc.SubscribeJobEventsRequest{
+			JobId:     jobID,
+			FromIndex: ctx.Uint64("from_index"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(event)
+	}
+}
+
+var cancelJobCommand = cli.Command{
+	Name:      "cancel",
+	Usage:     "Cancel a running job.",
+	ArgsUsage: "job_id",
+	Description: `
+	Cancel the operation of the running job with the given id. Canceling a
+	closechannel job stops the tracking of the close, but doesn't abort a
+	close that was already initiated.
+	`,
+	Action: actionDecorator(cancelJob),
+}
+
+func cancelJob(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "cancel")
+	}
+
+	jobID, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unable to parse job id: %w", err)
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.CancelJob(ctxc, &lnrpc.CancelJobRequest{
+		JobId: jobID,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
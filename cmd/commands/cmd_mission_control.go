@@ -294,6 +294,36 @@ func queryMissionControl(ctx *cli.Context) error {
 	return nil
 }
 
+var probeHistoryCommand = cli.Command{
+	Name:     "probehistory",
+	Category: "Mission Control",
+	Usage:    "List the results of the most recent background probes.",
+	Description: `
+	List the results of the most recent probes of the background prober
+	that is enabled with routing.probing.enable, oldest first. The probes
+	aren't stored as payments, so they don't show up in listpayments.
+	`,
+	Action: actionDecorator(probeHistory),
+}
+
+func probeHistory(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.ProbeHistoryRequest{}
+	resp, err := client.ProbeHistory(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var queryProbCommand = cli.Command{
 	Name:      "queryprob",
 	Category:  "Mission Control",
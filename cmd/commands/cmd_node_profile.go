@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lightningnetwork/lnd"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var nodeProfileCommand = cli.Command{
+	Name:     "nodeprofile",
+	Category: "Meta",
+	Usage:    "Export or import the operator-tunable state of the node.",
+	Subcommands: []cli.Command{
+		exportNodeProfileCommand,
+		importNodeProfileCommand,
+	},
+}
+
+var exportNodeProfileCommand = cli.Command{
+	Name:      "export",
+	Usage:     "Export a signed node profile bundle.",
+	ArgsUsage: "[--output_file F]",
+	Description: `
+	Export the default and per-channel fee policies, the persistent peers
+	and mission control of the node as a bundle that is signed by the node.
+	The bundle is printed, or written to the given file.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "output_file",
+			Usage: "(optional) the file to write the bundle to " +
+				"instead of printing it",
+		},
+	},
+	Action: actionDecorator(exportNodeProfile),
+}
+
+func exportNodeProfile(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ExportNodeProfile(
+		ctxc, &lnrpc.ExportNodeProfileRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	if ctx.IsSet("output_file") {
+		fileName := lnd.CleanAndExpandPath(ctx.String("output_file"))
+
+		return os.WriteFile(fileName, resp.Bundle, 0600)
+	}
+
+	fmt.Println(string(resp.Bundle))
+
+	return nil
+}
+
+var importNodeProfileCommand = cli.Command{
+	Name:      "import",
+	Usage:     "Apply a node profile bundle to the node.",
+	ArgsUsage: "bundle_file",
+	Description: `
+	Apply the node profile bundle in the given file, as written by
+	'lncli nodeprofile export', to the node. Channel policies are only
+	applied to channels that exist on this node, and the mission control
+	results are merged with the current ones.
+
+	Bundles that were signed by another node are rejected unless
+	--allow_foreign is set.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "allow_foreign",
+			Usage: "allow importing a bundle that was signed by " +
+				"another node",
+		},
+		cli.BoolFlag{
+			Name: "force_mc",
+			Usage: "overwrite mission control results that are " +
+				"more recent than the imported ones",
+		},
+	},
+	Action: actionDecorator(importNodeProfile),
+}
+
+func importNodeProfile(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "import")
+	}
+
+	fileName := lnd.CleanAndExpandPath(ctx.Args().First())
+	bundle, err := os.ReadFile(fileName)
+	if err != nil {
+		return fmt.Errorf("unable to read file '%s': %w", fileName,
+			err)
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ImportNodeProfile(
+		ctxc, &lnrpc.ImportNodeProfileRequest{
+			Bundle:              bundle,
+			AllowForeign:        ctx.Bool("allow_foreign"),
+			ForceMissionControl: ctx.Bool("force_mc"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
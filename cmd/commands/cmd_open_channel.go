@@ -21,6 +21,15 @@ import (
 	"github.com/urfave/cli"
 )
 
+// templateFlag is the flag that references a channel template stored by the
+// node, whose parameters are used for those not set by other flags.
+var templateFlag = cli.StringFlag{
+	Name: "template",
+	Usage: "(optional) the name of a channel template stored by the " +
+		"node, whose parameters are used for the channel unless " +
+		"they're set explicitly",
+}
+
 const (
 	userMsgFund = `PSBT funding initiated with peer %x.
 Please create a PSBT that sends %v (%d satoshi) to the funding address %s.
@@ -282,6 +291,8 @@ var openChannelCommand = cli.Command{
 				has no bearing on the channel's operation. Max
 				allowed length is 500 characters`,
 		},
+		templateFlag,
+		dryRunFlag,
 	},
 	Action: actionDecorator(openChannel),
 }
@@ -326,6 +337,8 @@ func openChannel(ctx *cli.Context) error {
 		RemoteChanReserveSat:       ctx.Uint64("remote_reserve_sats"),
 		FundMax:                    ctx.Bool("fundmax"),
 		Memo:                       ctx.String("memo"),
+		DryRun:                     ctx.Bool(dryRunFlag.Name),
+		Template:                   ctx.String(templateFlag.Name),
 	}
 
 	switch {
@@ -486,6 +499,11 @@ func openChannel(ctx *cli.Context) error {
 
 		case *lnrpc.OpenStatusUpdate_ChanOpen:
 			return printChanOpen(update)
+
+		case *lnrpc.OpenStatusUpdate_DryRunPlan:
+			printRespJSON(update.DryRunPlan)
+
+			return nil
 		}
 	}
 }
@@ -817,6 +835,8 @@ var batchOpenChannelCommand = cli.Command{
 				"wallet after publishing it",
 		},
 		coinSelectionStrategyFlag,
+		templateFlag,
+		dryRunFlag,
 	},
 	Action: actionDecorator(batchOpenChannel),
 }
@@ -858,6 +878,8 @@ func batchOpenChannel(ctx *cli.Context) error {
 		SpendUnconfirmed:      minConfs == 0,
 		Label:                 ctx.String("label"),
 		CoinSelectionStrategy: coinSelectionStrategy,
+		DryRun:                ctx.Bool(dryRunFlag.Name),
+		Template:              ctx.String(templateFlag.Name),
 	}
 
 	// Let's try and parse the JSON part of the CLI now. Fortunately we can
@@ -900,6 +922,12 @@ func batchOpenChannel(ctx *cli.Context) error {
 		return err
 	}
 
+	if resp.DryRunPlan != nil {
+		printRespJSON(resp.DryRunPlan)
+
+		return nil
+	}
+
 	for _, pending := range resp.PendingChannels {
 		txid, err := chainhash.NewHash(pending.Txid)
 		if err != nil {
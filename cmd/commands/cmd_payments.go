@@ -151,6 +151,25 @@ var (
 			"Canceling will only prevent further attempts from " +
 			"being sent",
 	}
+
+	trampolineFlag = cli.StringFlag{
+		Name: "trampoline",
+		Usage: "(optional) the compressed identity pubkey of a " +
+			"trampoline node that the route to the destination " +
+			"is delegated to",
+	}
+
+	trampolineFeeFlag = cli.Uint64Flag{
+		Name: "trampoline_fee_msat",
+		Usage: "(trampoline) the fee in millisatoshis allotted to " +
+			"the trampoline node, deducted from the fee limit",
+	}
+
+	trampolineCltvFlag = cli.UintFlag{
+		Name: "trampoline_cltv_delta",
+		Usage: "(trampoline) the cltv delta allotted to the " +
+			"trampoline node for the route to the destination",
+	}
 )
 
 // PaymentFlags returns common flags for sendpayment and payinvoice.
@@ -199,7 +218,8 @@ func PaymentFlags() []cli.Flag {
 		},
 		dataFlag, inflightUpdatesFlag, maxPartsFlag, jsonFlag,
 		maxShardSizeSatFlag, maxShardSizeMsatFlag, ampFlag,
-		timePrefFlag,
+		timePrefFlag, trampolineFlag, trampolineFeeFlag,
+		trampolineCltvFlag,
 	}
 }
 
@@ -514,6 +534,20 @@ func SendPaymentRequest(ctx *cli.Context, req *routerrpc.SendPaymentRequest,
 		req.LastHopPubkey = lastHop[:]
 	}
 
+	if ctx.IsSet(trampolineFlag.Name) {
+		trampoline, err := route.NewVertexFromStr(
+			ctx.String(trampolineFlag.Name),
+		)
+		if err != nil {
+			return err
+		}
+		req.Trampoline = &routerrpc.TrampolineOptions{
+			Node:      trampoline[:],
+			FeeMsat:   ctx.Uint64(trampolineFeeFlag.Name),
+			CltvDelta: uint32(ctx.Uint(trampolineCltvFlag.Name)),
+		}
+	}
+
 	req.CltvLimit = int32(ctx.Int(cltvLimitFlag.Name))
 
 	pmtTimeout := ctx.Duration("timeout")
@@ -930,6 +964,112 @@ func payInvoice(ctx *cli.Context) error {
 	return SendPaymentRequest(ctx, req, conn, conn, routerRPCSendPayment)
 }
 
+var sendPaymentsCommand = cli.Command{
+	Name:     "sendpayments",
+	Category: "Payments",
+	Usage:    "Pay a batch of invoices over lightning.",
+	Description: `
+	Pay all given invoices as a single batch. The status updates of all
+	payments are printed as they arrive, followed by a summary of the
+	outcome of the batch once all payments reached a final state. A
+	failing payment doesn't abort the other payments of the batch.
+	`,
+	ArgsUsage: "pay_req [pay_req...]",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "maximum fee allowed in satoshis when " +
+				"sending each payment",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "percentage of the amount of each payment " +
+				"used as the maximum fee allowed when " +
+				"sending the payment",
+		},
+		cli.DurationFlag{
+			Name: "timeout",
+			Usage: "the maximum amount of time we should spend " +
+				"trying to fulfill each payment, failing " +
+				"after the timeout has elapsed",
+			Value: paymentTimeout,
+		},
+		cancelableFlag, inflightUpdatesFlag, maxPartsFlag,
+	},
+	Action: actionDecorator(sendPayments),
+}
+
+func sendPayments(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	args := ctx.Args()
+	if !args.Present() {
+		return fmt.Errorf("pay_req argument missing")
+	}
+
+	pmtTimeout := ctx.Duration("timeout")
+	if pmtTimeout <= 0 {
+		return errors.New("payment timeout must be greater than zero")
+	}
+
+	lnClient := lnrpc.NewLightningClient(conn)
+	req := &routerrpc.SendPaymentsRequest{}
+	for _, arg := range args {
+		payReq := StripPrefix(arg)
+
+		// Decode the payment request to base the fee limit on its
+		// amount.
+		decodeResp, err := lnClient.DecodePayReq(
+			ctxc, &lnrpc.PayReqString{PayReq: payReq},
+		)
+		if err != nil {
+			return err
+		}
+
+		feeLimit, err := retrieveFeeLimit(
+			ctx, decodeResp.GetNumSatoshis(),
+		)
+		if err != nil {
+			return err
+		}
+
+		req.Payments = append(req.Payments,
+			&routerrpc.SendPaymentRequest{
+				PaymentRequest: payReq,
+				FeeLimitSat:    feeLimit,
+				TimeoutSeconds: int32(pmtTimeout.Seconds()),
+				MaxParts: uint32(
+					ctx.Uint(maxPartsFlag.Name),
+				),
+				Cancelable: ctx.Bool(cancelableFlag.Name),
+				NoInflightUpdates: !ctx.Bool(
+					inflightUpdatesFlag.Name,
+				),
+			},
+		)
+	}
+
+	routerClient := routerrpc.NewRouterClient(conn)
+	stream, err := routerClient.SendPayments(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(resp)
+	}
+}
+
 var sendToRouteCommand = cli.Command{
 	Name:     "sendtoroute",
 	Category: "Payments",
@@ -1145,6 +1285,17 @@ var queryRoutesCommand = cli.Command{
 				"multiple times if multiple node pairs are " +
 				"to be ignored",
 		},
+		cli.UintFlag{
+			Name: "num_routes",
+			Usage: "(optional) the number of diverse routes to " +
+				"return, in order of increasing cost",
+		},
+		cli.UintFlag{
+			Name: "max_shared_edges",
+			Usage: "(optional) the maximum number of edges each " +
+				"route shares with each of the routes before " +
+				"it, only used if num_routes is greater than 1",
+		},
 		timePrefFlag,
 		cltvLimitFlag,
 		introductionNodeFlag,
@@ -1245,6 +1396,8 @@ func queryRoutes(ctx *cli.Context) error {
 		TimePref:            ctx.Float64(timePrefFlag.Name),
 		IgnoredPairs:        ignoredPairs,
 		BlindedPaymentPaths: blindedRoutes,
+		NumRoutes:           uint32(ctx.Uint("num_routes")),
+		MaxSharedEdges:      uint32(ctx.Uint("max_shared_edges")),
 	}
 
 	route, err := client.QueryRoutes(ctxc, req)
@@ -1440,6 +1593,8 @@ var listPaymentsCommand = cli.Command{
 				"payments with creation date less than or " +
 				"equal to it",
 		},
+		pagedFlag,
+		pageCursorFlag,
 	},
 	Action: actionDecorator(listPayments),
 }
@@ -1457,6 +1612,8 @@ func listPayments(ctx *cli.Context) error {
 		CountTotalPayments: ctx.Bool("count_total_payments"),
 		CreationDateStart:  ctx.Uint64("creation_date_start"),
 		CreationDateEnd:    ctx.Uint64("creation_date_end"),
+		Paginate:           isPaged(ctx),
+		PageCursor:         ctx.String(pageCursorFlag.Name),
 	}
 
 	payments, err := client.ListPayments(ctxc, req)
@@ -1515,6 +1672,8 @@ var forwardingHistoryCommand = cli.Command{
 			Usage: "skip the peer alias lookup per forwarding " +
 				"event in order to improve performance",
 		},
+		pagedFlag,
+		pageCursorFlag,
 	},
 	Action: actionDecorator(forwardingHistory),
 }
@@ -1594,6 +1753,8 @@ func forwardingHistory(ctx *cli.Context) error {
 		IndexOffset:     indexOffset,
 		NumMaxEvents:    maxEvents,
 		PeerAliasLookup: lookupPeerAlias,
+		Paginate:        isPaged(ctx),
+		PageCursor:      ctx.String(pageCursorFlag.Name),
 	}
 	resp, err := client.ForwardingHistory(ctxc, req)
 	if err != nil {
@@ -1883,6 +2044,12 @@ var estimateRouteFeeCommand = cli.Command{
 				"applicable if pay_req is specified.",
 			Value: paymentTimeout,
 		},
+		cli.BoolFlag{
+			Name: "simulate",
+			Usage: "simulate the payment with mission control " +
+				"and multi-part splitting instead of probing, " +
+				"which also returns its success probability",
+		},
 	},
 }
 
@@ -1931,6 +2098,8 @@ func estimateRouteFee(ctx *cli.Context) error {
 		return fmt.Errorf("fee estimation arguments missing")
 	}
 
+	req.Simulate = ctx.Bool("simulate")
+
 	resp, err := client.EstimateRouteFee(ctxc, req)
 	if err != nil {
 		return err
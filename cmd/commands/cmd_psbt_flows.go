@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var psbtFlowsCommand = cli.Command{
+	Name:     "psbtflows",
+	Category: "Channels",
+	Usage: "Manage the PSBT funding flows that were aborted by a " +
+		"restart.",
+	Subcommands: []cli.Command{
+		listAbortedPsbtFlowsCommand,
+		forgetAbortedPsbtFlowCommand,
+	},
+}
+
+var listAbortedPsbtFlowsCommand = cli.Command{
+	Name:  "list",
+	Usage: "List the aborted PSBT funding flows.",
+	Description: `
+	List the PSBT funding flows that were aborted because lnd restarted
+	while they were in progress. Each flow holds the funding output script,
+	the multisig keys and, if known, the finalized funding transaction, so
+	that funds sent to the funding output can be recovered together with
+	the peer.
+	`,
+	Action: actionDecorator(listAbortedPsbtFlows),
+}
+
+func listAbortedPsbtFlows(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListAbortedPsbtFlows(
+		ctxc, &lnrpc.ListAbortedPsbtFlowsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var forgetAbortedPsbtFlowCommand = cli.Command{
+	Name:      "forget",
+	Usage:     "Forget an aborted PSBT funding flow.",
+	ArgsUsage: "pending_chan_id",
+	Description: `
+	Remove the aborted PSBT funding flow with the given hex encoded pending
+	channel ID. Only do this once no funds are left in its funding output,
+	as the keys and scripts needed to recover them are removed too.
+	`,
+	Action: actionDecorator(forgetAbortedPsbtFlow),
+}
+
+func forgetAbortedPsbtFlow(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "forget")
+	}
+
+	pendingChanID, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode pending channel ID: %w",
+			err)
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ForgetAbortedPsbtFlow(
+		ctxc, &lnrpc.ForgetAbortedPsbtFlowRequest{
+			PendingChanId: pendingChanID,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var rebalanceCommand = cli.Command{
+	Name:     "rebalance",
+	Category: "Payments",
+	Usage:    "Move liquidity between two of our channels.",
+	Description: `
+	Rebalance two of our channels by paying ourselves in a circle. The
+	payment leaves through the outgoing channel and comes back through the
+	peer of the incoming channel, which moves local balance from the
+	outgoing to the incoming channel.
+
+	The circular route is computed first and shown together with its fee.
+	Unless --force is set, the payment is only sent after confirmation.
+	The local balances of both channels before and after the rebalance are
+	reported once the payment is settled.
+
+	Note that if there are several channels with the peer of the incoming
+	channel, the peer may choose any of them to forward the payment to us.
+	`,
+	ArgsUsage: "--from=F --to=T --amt=A",
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "from",
+			Usage: "short channel id of the channel to move " +
+				"local balance out of",
+		},
+		cli.Uint64Flag{
+			Name: "to",
+			Usage: "short channel id of the channel to move " +
+				"local balance into",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "number of satoshis to move",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit",
+			Usage: "maximum fee allowed in satoshis when " +
+				"rebalancing",
+		},
+		cli.Int64Flag{
+			Name: "fee_limit_percent",
+			Usage: "percentage of the amount used as the maximum " +
+				"fee allowed when rebalancing",
+		},
+		cltvLimitFlag,
+		cli.BoolFlag{
+			Name:  "force, f",
+			Usage: "will skip the route confirmation",
+		},
+	},
+	Action: actionDecorator(rebalance),
+}
+
+func rebalance(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	if ctx.NumFlags() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "rebalance")
+		return nil
+	}
+
+	var (
+		from = ctx.Uint64("from")
+		to   = ctx.Uint64("to")
+		amt  = ctx.Int64("amt")
+	)
+	switch {
+	case from == 0 || to == 0:
+		return errors.New("both --from and --to must be set")
+
+	case from == to:
+		return errors.New("--from and --to must be different channels")
+
+	case amt <= 0:
+		return errors.New("amount must be greater than zero")
+	}
+
+	feeLimit, err := retrieveFeeLimit(ctx, amt)
+	if err != nil {
+		return err
+	}
+	if feeLimit < 0 {
+		return errors.New("fee limit must not be negative")
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.RebalanceRequest{
+		OutgoingChanId: from,
+		IncomingChanId: to,
+		AmtSat:         amt,
+		FeeLimitSat:    feeLimit,
+		CltvLimit:      uint32(ctx.Uint(cltvLimitFlag.Name)),
+	}
+
+	if !ctx.Bool("force") {
+		// Query the circular route first, so that it can be
+		// confirmed before the payment is sent.
+		req.QueryOnly = true
+		resp, err := client.Rebalance(ctxc, req)
+		if err != nil {
+			return err
+		}
+		req.QueryOnly = false
+
+		rt := resp.Route
+		fmt.Printf("Amount (in satoshis): %v\n", amt)
+		fmt.Printf("Fee (in millisatoshis): %v\n", rt.TotalFeesMsat)
+		fmt.Printf("Route: %v", from)
+		for _, hop := range rt.Hops[1:] {
+			fmt.Printf(" -> %v", hop.ChanId)
+		}
+		fmt.Println()
+
+		confirm := promptForConfirmation("Confirm rebalance (yes/no): ")
+		if !confirm {
+			return errors.New("rebalance not confirmed")
+		}
+	}
+
+	resp, err := client.Rebalance(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
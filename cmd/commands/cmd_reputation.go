@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var reputationCommand = cli.Command{
+	Name:     "reputation",
+	Category: "Channels",
+	Usage: "Show the reputation of our incoming channels for htlc " +
+		"endorsement.",
+	Description: `
+	Show the reputation of our incoming channels in the experimental htlc
+	endorsement scheme, or of a single channel if its id is given. A
+	channel earns reputation with the fees of the htlcs it forwards
+	through us, and an outgoing htlc is only endorsed if the reputation of
+	its incoming channel covers the fees of all of its endorsed htlcs in
+	flight.`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "chan_id",
+			Usage: "the short channel id of the incoming channel " +
+				"to show the reputation of",
+		},
+	},
+	Action: actionDecorator(reputation),
+}
+
+func reputation(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.ChannelReputationRequest{
+		ChanId: ctx.Uint64("chan_id"),
+	}
+	resp, err := client.ChannelReputation(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
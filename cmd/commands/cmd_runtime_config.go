@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var runtimeConfigCommand = cli.Command{
+	Name:     "runtimeconfig",
+	Category: "Meta",
+	Usage: "Show or change the options that can be changed without a " +
+		"restart.",
+	Subcommands: []cli.Command{
+		getRuntimeConfigCommand,
+		setRuntimeConfigCommand,
+	},
+}
+
+var getRuntimeConfigCommand = cli.Command{
+	Name:   "get",
+	Usage:  "Show the current value of the runtime options.",
+	Action: actionDecorator(getRuntimeConfig),
+}
+
+func getRuntimeConfig(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetRuntimeConfig(
+		ctxc, &lnrpc.GetRuntimeConfigRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var setRuntimeConfigCommand = cli.Command{
+	Name:      "set",
+	Usage:     "Change runtime options.",
+	ArgsUsage: "name=value [name=value...]",
+	Description: `
+	Change the given options of the running node. The options are named as
+	in the config file, for example bitcoin.basefee=1000. The request is
+	rejected if any of the options can't be changed at runtime. The changed
+	options are written to runtime-overrides.conf in the lnd directory and
+	are kept on the next start.
+	`,
+	Action: actionDecorator(setRuntimeConfig),
+}
+
+func setRuntimeConfig(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() == 0 {
+		return cli.ShowCommandHelp(ctx, "set")
+	}
+
+	options := make(map[string]string, ctx.NArg())
+	for _, arg := range ctx.Args() {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid option %q, expected "+
+				"name=value", arg)
+		}
+
+		options[name] = value
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.UpdateRuntimeConfig(
+		ctxc, &lnrpc.UpdateRuntimeConfigRequest{Options: options},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var stuckHtlcsCommand = cli.Command{
+	Name:     "stuckhtlcs",
+	Category: "Events",
+	Usage:    "Subscribe to the htlcs that are stuck on our channels.",
+	Description: `
+	Print the htlcs that are currently stuck, followed by every htlc that
+	becomes stuck, until the command is interrupted. An htlc is stuck once
+	it has been pending on one of our outgoing channels for longer than
+	htlcswitch.stuckhtlcthreshold, which needs to be set to enable the
+	detection of stuck htlcs.
+	`,
+	Action: actionDecorator(stuckHtlcs),
+}
+
+func stuckHtlcs(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	client := routerrpc.NewRouterClient(conn)
+
+	req := &routerrpc.SubscribeStuckHtlcsRequest{}
+	stream, err := client.SubscribeStuckHtlcs(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		htlc, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(htlc)
+	}
+}
@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/urfave/cli"
+)
+
+var subscribeAllCommand = cli.Command{
+	Name:     "subscribeall",
+	Category: "Events",
+	Usage: "Subscribe to the channel, peer, htlc, invoice, payment and " +
+		"block events of the node.",
+	Description: `
+	Print the channel, peer, htlc, invoice, payment and block events of the
+	node as they happen, until the command is interrupted. The events can
+	be restricted to certain types, and the events that relate to channels
+	or peers to those of certain channels or peers. Events that don't
+	relate to a channel or peer, such as new blocks, are always printed if
+	their type is subscribed to.
+	`,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name: "type",
+			Usage: "(optional) the type of events to subscribe " +
+				"to (channel, peer, htlc, invoice, payment " +
+				"or block), can be specified multiple times; " +
+				"all types are subscribed to if not set",
+		},
+		cli.StringSliceFlag{
+			Name: "chan_point",
+			Usage: "(optional) only print the events that relate " +
+				"to the channel of the given channel point, " +
+				"can be specified multiple times",
+		},
+		cli.StringSliceFlag{
+			Name: "peer",
+			Usage: "(optional) only print the events that relate " +
+				"to the peer of the given public key, can be " +
+				"specified multiple times",
+		},
+	},
+	Action: actionDecorator(subscribeAll),
+}
+
+func subscribeAll(ctx *cli.Context) error {
+	ctxc := getContext()
+	conn := getClientConn(ctx, false)
+	defer conn.Close()
+
+	req := &routerrpc.SubscribeAllRequest{}
+
+	eventTypes := routerrpc.SubscribeAllEvent_EventType_value
+
+	for _, t := range ctx.StringSlice("type") {
+		eventType, ok := eventTypes[strings.ToUpper(t)]
+		if !ok {
+			return fmt.Errorf("unknown event type %v", t)
+		}
+
+		req.Types = append(
+			req.Types, routerrpc.SubscribeAllEvent_EventType(
+				eventType,
+			),
+		)
+	}
+
+	for _, c := range ctx.StringSlice("chan_point") {
+		chanPoint, err := parseChanPoint(c)
+		if err != nil {
+			return err
+		}
+
+		req.ChannelPoints = append(req.ChannelPoints, chanPoint)
+	}
+
+	for _, p := range ctx.StringSlice("peer") {
+		peer, err := hex.DecodeString(p)
+		if err != nil {
+			return fmt.Errorf("unable to decode peer public key: "+
+				"%w", err)
+		}
+
+		req.Peers = append(req.Peers, peer)
+	}
+
+	client := routerrpc.NewRouterClient(conn)
+	stream, err := client.SubscribeAll(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(event)
+	}
+}
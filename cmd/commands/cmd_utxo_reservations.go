@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var utxoReservationsCommand = cli.Command{
+	Name:     "utxoreservations",
+	Category: "On-chain",
+	Usage: "Manage the wallet outputs locked for the funding of " +
+		"pending channels.",
+	Subcommands: []cli.Command{
+		listUtxoReservationsCommand,
+		releaseUtxoReservationCommand,
+	},
+}
+
+var listUtxoReservationsCommand = cli.Command{
+	Name:  "list",
+	Usage: "List the wallet outputs locked for pending channels.",
+	Description: `
+	List the wallet outputs that are locked for the funding transactions of
+	pending channels, grouped by funding flow and ordered by the time their
+	lock expires. Reservations whose lock expired are released
+	automatically.
+	`,
+	Action: actionDecorator(listUtxoReservations),
+}
+
+func listUtxoReservations(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListUtxoReservations(
+		ctxc, &lnrpc.ListUtxoReservationsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var releaseUtxoReservationCommand = cli.Command{
+	Name:      "release",
+	Usage:     "Release the wallet outputs locked for a pending channel.",
+	ArgsUsage: "pending_chan_id",
+	Description: `
+	Release the wallet outputs locked for the funding transaction of the
+	pending channel with the given hex encoded pending channel ID. If its
+	funding flow is still in progress, it is failed.
+	`,
+	Action: actionDecorator(releaseUtxoReservation),
+}
+
+func releaseUtxoReservation(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "release")
+	}
+
+	pendingChanID, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode pending channel ID: %w",
+			err)
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ReleaseUtxoReservation(
+		ctxc, &lnrpc.ReleaseUtxoReservationRequest{
+			PendingChanId: pendingChanID,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
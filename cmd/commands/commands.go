@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -15,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
@@ -311,6 +313,40 @@ var txLabelFlag = cli.StringFlag{
 	Usage: "(optional) a label for the transaction",
 }
 
+var dryRunFlag = cli.BoolFlag{
+	Name: "dry_run",
+	Usage: "(optional) if set, the transaction is only planned and " +
+		"printed, without broadcasting anything or changing any " +
+		"state",
+}
+
+// pagedFlag lists the items of a listing page by page. The response holds
+// the cursor the next page is listed with.
+var pagedFlag = cli.BoolFlag{
+	Name: "paged",
+	Usage: "(optional) if set, only a page of the items is listed, " +
+		"along with the cursor of the next page",
+}
+
+// pageCursorFlag continues a listing with the page of the given cursor.
+var pageCursorFlag = cli.StringFlag{
+	Name: "page_cursor",
+	Usage: "(optional) the next_page_cursor of the previous page to " +
+		"list the next page of, implies --paged",
+}
+
+// pageSizeFlag is the maximum number of items of a page of a listing.
+var pageSizeFlag = cli.UintFlag{
+	Name: "page_size",
+	Usage: "(optional) the maximum number of items of a page if " +
+		"--paged is set, defaults to 100 and is capped to 1000",
+}
+
+// isPaged returns true if a listing was requested page by page.
+func isPaged(ctx *cli.Context) bool {
+	return ctx.Bool(pagedFlag.Name) || ctx.IsSet(pageCursorFlag.Name)
+}
+
 var sendCoinsCommand = cli.Command{
 	Name:      "sendcoins",
 	Category:  "On-chain",
@@ -387,6 +423,7 @@ var sendCoinsCommand = cli.Command{
 				"the amt flag",
 		},
 		txLabelFlag,
+		dryRunFlag,
 	},
 	Action: actionDecorator(sendCoins),
 }
@@ -486,7 +523,9 @@ func sendCoins(ctx *cli.Context) error {
 	// Ask for confirmation if we're on an actual terminal and the output is
 	// not being redirected to another command. This prevents existing shell
 	// scripts from breaking.
-	if !ctx.Bool("force") && term.IsTerminal(int(os.Stdout.Fd())) {
+	if !ctx.Bool("force") && !ctx.Bool(dryRunFlag.Name) &&
+		term.IsTerminal(int(os.Stdout.Fd())) {
+
 		fmt.Printf("Amount: %d\n", displayAmt)
 		fmt.Printf("Destination address: %v\n", addr)
 
@@ -507,6 +546,7 @@ func sendCoins(ctx *cli.Context) error {
 		SpendUnconfirmed:      minConfs == 0,
 		CoinSelectionStrategy: coinSelectionStrategy,
 		Outpoints:             outpoints,
+		DryRun:                ctx.Bool(dryRunFlag.Name),
 	}
 	txid, err := client.SendCoins(ctxc, req)
 	if err != nil {
@@ -920,6 +960,14 @@ var closeChannelCommand = cli.Command{
 				"be used if an upfront shutdown address is not " +
 				"already set",
 		},
+		cli.StringFlag{
+			Name: "delivery_psbt",
+			Usage: "(optional) a base64 encoded PSBT without " +
+				"inputs whose single output holds the script " +
+				"to deliver funds to upon cooperative channel " +
+				"closing, which may be a custom script that " +
+				"has no address encoding",
+		},
 		cli.Uint64Flag{
 			Name: "max_fee_rate",
 			Usage: "(optional) maximum fee rate in sat/vbyte " +
@@ -928,6 +976,15 @@ var closeChannelCommand = cli.Command{
 				"success pobability of the negotiation if " +
 				"set higher",
 		},
+		dryRunFlag,
+		cli.BoolFlag{
+			Name: "as_job",
+			Usage: "(optional) if set, the close is run as a job " +
+				"that survives the disconnection of lncli; " +
+				"only the id of the job is printed, its " +
+				"updates can be followed with `lncli jobs " +
+				"subscribe`",
+		},
 	},
 	Action: actionDecorator(closeChannel),
 }
@@ -957,6 +1014,17 @@ func closeChannel(ctx *cli.Context) error {
 		return err
 	}
 
+	var deliveryPsbt []byte
+	if ctx.IsSet("delivery_psbt") {
+		deliveryPsbt, err = base64.StdEncoding.DecodeString(
+			ctx.String("delivery_psbt"),
+		)
+		if err != nil {
+			return fmt.Errorf("unable to decode delivery PSBT: %w",
+				err)
+		}
+	}
+
 	// TODO(roasbeef): implement time deadline within server
 	req := &lnrpc.CloseChannelRequest{
 		ChannelPoint:    channelPoint,
@@ -964,7 +1032,20 @@ func closeChannel(ctx *cli.Context) error {
 		TargetConf:      int32(ctx.Int64("conf_target")),
 		SatPerVbyte:     ctx.Uint64(feeRateFlag),
 		DeliveryAddress: ctx.String("delivery_addr"),
+		DeliveryPsbt:    deliveryPsbt,
 		MaxFeePerVbyte:  ctx.Uint64("max_fee_rate"),
+		DryRun:          ctx.Bool(dryRunFlag.Name),
+		AsJob:           ctx.Bool("as_job"),
+	}
+
+	// A dry run only sends back the plan of the closing transaction.
+	if req.DryRun {
+		return planChannelClose(ctxc, client, req)
+	}
+
+	// A close that is run as a job only sends back the id of the job.
+	if req.AsJob {
+		return submitChannelCloseJob(ctxc, client, req)
 	}
 
 	// After parsing the request, we'll spin up a goroutine that will
@@ -1042,6 +1123,54 @@ func executeChannelClose(ctxc context.Context, client lnrpc.LightningClient,
 	}
 }
 
+// submitChannelCloseJob prints the id of the job a channel close is run as.
+func submitChannelCloseJob(ctxc context.Context,
+	client lnrpc.LightningClient, req *lnrpc.CloseChannelRequest) error {
+
+	stream, err := client.CloseChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	job := resp.GetJob()
+	if job == nil {
+		return fmt.Errorf("unexpected close update: %v", resp)
+	}
+
+	printRespJSON(job)
+
+	return nil
+}
+
+// planChannelClose prints the plan of the dry run of a channel close.
+func planChannelClose(ctxc context.Context, client lnrpc.LightningClient,
+	req *lnrpc.CloseChannelRequest) error {
+
+	stream, err := client.CloseChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	resp, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	plan := resp.GetDryRunPlan()
+	if plan == nil {
+		return fmt.Errorf("unexpected close update: %v", resp)
+	}
+
+	printRespJSON(plan)
+
+	return nil
+}
+
 var closeAllChannelsCommand = cli.Command{
 	Name:     "closeallchannels",
 	Category: "Channels",
@@ -1651,6 +1780,9 @@ var ListChannelsCommand = cli.Command{
 			Usage: "skip the peer alias lookup per channel in " +
 				"order to improve performance",
 		},
+		pagedFlag,
+		pageCursorFlag,
+		pageSizeFlag,
 	},
 	Action: actionDecorator(ListChannels),
 }
@@ -1710,6 +1842,9 @@ func ListChannels(ctx *cli.Context) error {
 		PrivateOnly:     ctx.Bool("private_only"),
 		Peer:            peerKey,
 		PeerAliasLookup: lookupPeerAlias,
+		Paginate:        isPaged(ctx),
+		PageCursor:      ctx.String(pageCursorFlag.Name),
+		PageSize:        uint32(ctx.Uint(pageSizeFlag.Name)),
 	}
 
 	resp, err := client.ListChannels(ctxc, req)
@@ -1722,6 +1857,38 @@ func ListChannels(ctx *cli.Context) error {
 	return nil
 }
 
+var htlcExpiryRiskCommand = cli.Command{
+	Name:     "htlcexpiryrisk",
+	Category: "Channels",
+	Usage: "Display the heights at which outstanding HTLCs force " +
+		"channels on-chain.",
+	Description: `
+	This command returns, for every active channel, the heights at which
+	each outstanding HTLC forces the channel to be closed on-chain unless
+	it's resolved off-chain before. Channels with a low
+	blocks_until_force_close are closed unless their peer comes back
+	online in time.
+	`,
+	Action: actionDecorator(htlcExpiryRisk),
+}
+
+func htlcExpiryRisk(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.HtlcExpiryRisks(
+		ctxc, &lnrpc.HtlcExpiryRisksRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
 var closedChannelsCommand = cli.Command{
 	Name:     "closedchannels",
 	Category: "Channels",
@@ -1797,6 +1964,9 @@ var describeGraphCommand = cli.Command{
 				"graph. Unannounced channels are both private channels, and " +
 				"public channels that are not yet announced to the network.",
 		},
+		pagedFlag,
+		pageCursorFlag,
+		pageSizeFlag,
 	},
 	Action: actionDecorator(describeGraph),
 }
@@ -1808,6 +1978,9 @@ func describeGraph(ctx *cli.Context) error {
 
 	req := &lnrpc.ChannelGraphRequest{
 		IncludeUnannounced: ctx.Bool("include_unannounced"),
+		Paginate:           isPaged(ctx),
+		PageCursor:         ctx.String(pageCursorFlag.Name),
+		PageSize:           uint32(ctx.Uint(pageSizeFlag.Name)),
 	}
 
 	graph, err := client.DescribeGraph(ctxc, req)
@@ -1824,7 +1997,14 @@ var getNodeMetricsCommand = cli.Command{
 	Category:    "Graph",
 	Description: "Prints out node metrics calculated from the current graph",
 	Usage:       "Get node metrics.",
-	Action:      actionDecorator(getNodeMetrics),
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "reachability",
+			Usage: "also include the number of nodes that can be " +
+				"reached from each node",
+		},
+	},
+	Action: actionDecorator(getNodeMetrics),
 }
 
 func getNodeMetrics(ctx *cli.Context) error {
@@ -1835,6 +2015,11 @@ func getNodeMetrics(ctx *cli.Context) error {
 	req := &lnrpc.NodeMetricsRequest{
 		Types: []lnrpc.NodeMetricType{lnrpc.NodeMetricType_BETWEENNESS_CENTRALITY},
 	}
+	if ctx.Bool("reachability") {
+		req.Types = append(
+			req.Types, lnrpc.NodeMetricType_REACHABILITY,
+		)
+	}
 
 	nodeMetrics, err := client.GetNodeMetrics(ctxc, req)
 	if err != nil {
@@ -1845,6 +2030,93 @@ func getNodeMetrics(ctx *cli.Context) error {
 	return nil
 }
 
+var getGraphMetricsCommand = cli.Command{
+	Name:     "getgraphmetrics",
+	Category: "Graph",
+	Usage:    "Get the analytics of the channel graph.",
+	Description: `
+	Prints out the most recent analytics of the channel graph: its size,
+	diameter, connected components and bridge channels. Requires lnd to be
+	started with routing.graph-analytics.
+	`,
+	Action: actionDecorator(getGraphMetrics),
+}
+
+func getGraphMetrics(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	graphMetrics, err := client.GetGraphMetrics(
+		ctxc, &lnrpc.GraphMetricsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(graphMetrics)
+	return nil
+}
+
+var getNodeMetricsV2Command = cli.Command{
+	Name:     "getnodemetricsv2",
+	Category: "Channels",
+	Usage: "Get the historical forwarding performance of channels and " +
+		"peers.",
+	Description: `
+	Prints the forwarding volume, success rate and average hold time of
+	incoming and outgoing forwards, and the peer downtime, of the open
+	channels and their peers over a window ending now. The forwarding
+	metrics are retained for 30 days.`,
+	Flags: []cli.Flag{
+		cli.DurationFlag{
+			Name:  "window",
+			Usage: "the window to aggregate the metrics over",
+			Value: 24 * time.Hour,
+		},
+		cli.StringSliceFlag{
+			Name: "chan_id",
+			Usage: "the short channel id of a channel to return " +
+				"the metrics of. The flag may be specified " +
+				"multiple times. If not set, the metrics of " +
+				"all open channels are returned.",
+		},
+	},
+	Action: actionDecorator(getNodeMetricsV2),
+}
+
+func getNodeMetricsV2(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	window := ctx.Duration("window")
+	if window < time.Second {
+		return fmt.Errorf("window must be at least one second")
+	}
+
+	req := &lnrpc.NodeMetricsV2Request{
+		WindowSeconds: uint64(window.Seconds()),
+	}
+	for _, chanIDStr := range ctx.StringSlice("chan_id") {
+		chanID, err := strconv.ParseUint(chanIDStr, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid channel id %v: %w",
+				chanIDStr, err)
+		}
+
+		req.ChanIds = append(req.ChanIds, chanID)
+	}
+
+	metrics, err := client.GetNodeMetricsV2(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(metrics)
+	return nil
+}
+
 var getChanInfoCommand = cli.Command{
 	Name:     "getchaninfo",
 	Category: "Graph",
@@ -2724,6 +2996,63 @@ func exportChanBackup(ctx *cli.Context) error {
 	return nil
 }
 
+var exportEmergencyBundleCommand = cli.Command{
+	Name:      "exportemergencybundle",
+	Category:  "Channels",
+	Usage:     "Export the latest force close txns of all channels.",
+	ArgsUsage: "[--output_file]",
+	Description: `
+	This command exports the latest signed force close transaction of every
+	active channel, together with unsigned PSBTs that sweep the anchor and
+	to_local outputs of segwit v0 channels. The bundle is encrypted with a
+	key derived from the seed of the user, and allows funds to be recovered
+	with cold storage tooling after a catastrophic node loss.
+
+	NOTE: the bundle is only valid until the next channel update.
+	Broadcasting an outdated force close transaction is treated as a breach
+	by the remote party, which can then claim all funds of the channel.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "output_file",
+			Usage: "if specified, then rather than printing a " +
+				"JSON output of the bundle, the encrypted " +
+				"bundle will be written to the target file",
+		},
+	},
+	Action: actionDecorator(exportEmergencyBundle),
+}
+
+func exportEmergencyBundle(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments.
+	if ctx.NArg() != 0 {
+		return cli.ShowCommandHelp(ctx, "exportemergencybundle")
+	}
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	bundle, err := client.ExportEmergencyCloseBundle(
+		ctxc, &lnrpc.EmergencyCloseBundleRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	if ctx.IsSet("output_file") {
+		return os.WriteFile(
+			ctx.String("output_file"), bundle.Bundle, 0600,
+		)
+	}
+
+	printRespJSON(bundle)
+
+	return nil
+}
+
 var verifyChanBackupCommand = cli.Command{
 	Name:      "verifychanbackup",
 	Category:  "Channels",
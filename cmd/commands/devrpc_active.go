@@ -24,9 +24,37 @@ func devCommands() []cli.Command {
 			ArgsUsage:   "graph-json-file",
 			Action:      actionDecorator(importGraph),
 		},
+		fsmCommand,
 	}
 }
 
+var fsmCommand = cli.Command{
+	Name:     "fsm",
+	Category: "Development",
+	Usage:    "Inspect the protocol state machines.",
+	Subcommands: []cli.Command{
+		{
+			Name:   "list",
+			Usage:  "List the active state machines.",
+			Action: actionDecorator(listStateMachines),
+		},
+		{
+			Name: "show",
+			Usage: "Show the current state and recent " +
+				"transitions of a state machine.",
+			ArgsUsage: "name",
+			Action:    actionDecorator(showStateMachine),
+		},
+		{
+			Name: "inject",
+			Usage: "Send an event to a state machine (regtest " +
+				"and simnet only).",
+			ArgsUsage: "name event",
+			Action:    actionDecorator(injectStateMachineEvent),
+		},
+	},
+}
+
 func getDevClient(ctx *cli.Context) (devrpc.DevClient, func()) {
 	conn := getClientConn(ctx, false)
 	cleanUp := func() {
@@ -60,3 +88,62 @@ func importGraph(ctx *cli.Context) error {
 	printRespJSON(res)
 	return nil
 }
+
+func listStateMachines(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getDevClient(ctx)
+	defer cleanUp()
+
+	res, err := client.ListStateMachines(
+		ctxc, &devrpc.ListStateMachinesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+	return nil
+}
+
+func showStateMachine(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getDevClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "show")
+	}
+
+	res, err := client.GetStateMachine(ctxc, &devrpc.GetStateMachineRequest{
+		Name: ctx.Args().First(),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+	return nil
+}
+
+func injectStateMachineEvent(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getDevClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 2 {
+		return cli.ShowCommandHelp(ctx, "inject")
+	}
+
+	res, err := client.InjectStateMachineEvent(
+		ctxc, &devrpc.InjectStateMachineEventRequest{
+			Name:  ctx.Args().Get(0),
+			Event: ctx.Args().Get(1),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(res)
+	return nil
+}
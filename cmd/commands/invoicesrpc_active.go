@@ -5,7 +5,9 @@ package commands
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 
 	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
@@ -18,6 +20,12 @@ func invoicesCommands() []cli.Command {
 		cancelInvoiceCommand,
 		addHoldInvoiceCommand,
 		settleInvoiceCommand,
+		webhookDeadLettersCommand,
+		subscriptionCommand,
+		addStatelessInvoiceCommand,
+		verifyStatelessSettlementCommand,
+		ampSetCommand,
+		reissueInvoiceCommand,
 	}
 }
 
@@ -262,3 +270,633 @@ func addHoldInvoice(ctx *cli.Context) error {
 
 	return nil
 }
+
+var webhookDeadLettersCommand = cli.Command{
+	Name:     "webhookdeadletters",
+	Category: "Invoices",
+	Usage: "Manage the invoice events that couldn't be delivered to " +
+		"the webhook.",
+	Subcommands: []cli.Command{
+		listWebhookDeadLettersCommand,
+		retryWebhookDeadLettersCommand,
+	},
+}
+
+var listWebhookDeadLettersCommand = cli.Command{
+	Name:  "list",
+	Usage: "List the invoice events in the dead letter queue.",
+	Description: `
+	List the invoice events that couldn't be delivered to the webhook set
+	with invoices.webhookurl once invoices.webhookmaxattempts was
+	exhausted, oldest first.
+	`,
+	Action: actionDecorator(listWebhookDeadLetters),
+}
+
+func listWebhookDeadLetters(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListWebhookDeadLetters(
+		ctxc, &invoicesrpc.ListWebhookDeadLettersRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var retryWebhookDeadLettersCommand = cli.Command{
+	Name:  "retry",
+	Usage: "Deliver the invoice events in the dead letter queue again.",
+	Description: `
+	Remove all invoice events from the dead letter queue and queue them for
+	delivery to the webhook again.
+	`,
+	Action: actionDecorator(retryWebhookDeadLetters),
+}
+
+func retryWebhookDeadLetters(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.RetryWebhookDeadLetters(
+		ctxc, &invoicesrpc.RetryWebhookDeadLettersRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var subscriptionCommand = cli.Command{
+	Name:     "subscription",
+	Category: "Invoices",
+	Usage:    "Manage recurring subscription invoices.",
+	Subcommands: []cli.Command{
+		addSubscriptionCommand,
+		cancelSubscriptionCommand,
+		listSubscriptionsCommand,
+	},
+}
+
+var addSubscriptionCommand = cli.Command{
+	Name:  "add",
+	Usage: "Create a recurring subscription from an invoice template.",
+	Description: `
+	Create a recurring subscription. A new invoice for amt_msat is
+	generated at the start of each period, until the subscription is
+	canceled or num_periods invoices were generated. If the first period
+	already started, its invoice is generated right away.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "memo",
+			Usage: "the memo of the generated invoices, to which " +
+				"the index of the period is appended",
+		},
+		cli.Uint64Flag{
+			Name:  "amt_msat",
+			Usage: "the amount that is due each period in msat",
+		},
+		cli.DurationFlag{
+			Name: "period",
+			Usage: "the duration of a single period, at least " +
+				"one minute (e.g. 720h)",
+		},
+		cli.Int64Flag{
+			Name: "start_time",
+			Usage: "the unix timestamp of the start of the first " +
+				"period; if not set, the subscription starts " +
+				"now",
+		},
+		cli.Uint64Flag{
+			Name: "num_periods",
+			Usage: "the number of periods after which the " +
+				"subscription completes; if not set, it " +
+				"continues until it is canceled",
+		},
+		cli.DurationFlag{
+			Name: "expiry",
+			Usage: "the expiry of the generated invoices; if not " +
+				"set, they expire at the end of their period",
+		},
+		cli.Uint64Flag{
+			Name:  "cltv_expiry",
+			Usage: "the final cltv delta of the generated invoices",
+		},
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "include route hints for private channels in " +
+				"the generated invoices",
+		},
+	},
+	Action: actionDecorator(addSubscription),
+}
+
+func addSubscription(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("amt_msat") || !ctx.IsSet("period") {
+		return fmt.Errorf("amt_msat and period must be set")
+	}
+
+	resp, err := client.AddSubscription(
+		ctxc, &invoicesrpc.AddSubscriptionRequest{
+			Memo:          ctx.String("memo"),
+			ValueMsat:     ctx.Uint64("amt_msat"),
+			PeriodSeconds: uint64(ctx.Duration("period").Seconds()),
+			StartTime:     ctx.Int64("start_time"),
+			NumPeriods:    uint32(ctx.Uint64("num_periods")),
+			Expiry:        int64(ctx.Duration("expiry").Seconds()),
+			CltvExpiry:    ctx.Uint64("cltv_expiry"),
+			Private:       ctx.Bool("private"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var cancelSubscriptionCommand = cli.Command{
+	Name:      "cancel",
+	Usage:     "Stop generating invoices for a subscription.",
+	ArgsUsage: "id",
+	Description: `
+	Cancel the subscription with the given hex encoded id. Invoices that
+	were generated already are not canceled.
+	`,
+	Action: actionDecorator(cancelSubscription),
+}
+
+func cancelSubscription(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "cancel")
+	}
+
+	id, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode id: %w", err)
+	}
+
+	resp, err := client.CancelSubscription(
+		ctxc, &invoicesrpc.CancelSubscriptionRequest{
+			Id: id,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listSubscriptionsCommand = cli.Command{
+	Name:  "list",
+	Usage: "List the subscriptions and the payment status of each period.",
+	Description: `
+	List all subscriptions, or a single subscription if its hex encoded id
+	is given, along with the payment status of each period an invoice was
+	generated for.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "id",
+			Usage: "the id of the subscription to list",
+		},
+	},
+	Action: actionDecorator(listSubscriptions),
+}
+
+func listSubscriptions(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	id, err := hex.DecodeString(ctx.String("id"))
+	if err != nil {
+		return fmt.Errorf("unable to decode id: %w", err)
+	}
+
+	resp, err := client.ListSubscriptions(
+		ctxc, &invoicesrpc.ListSubscriptionsRequest{
+			Id: id,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var addStatelessInvoiceCommand = cli.Command{
+	Name:     "addstatelessinvoice",
+	Category: "Invoices",
+	Usage:    "Add a new stateless invoice.",
+	Description: `
+	Add a new stateless invoice, which isn't stored until it is paid. Its
+	payment address and preimage are derived from the stateless invoice
+	secret. Stateless invoices must specify an amount and require
+	invoices.stateless to be set.`,
+	ArgsUsage: "amt",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "memo",
+			Usage: "a description of the payment to set in the " +
+				"encoded invoice (default=\"\")",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the amt of satoshis in this invoice",
+		},
+		cli.Int64Flag{
+			Name:  "amt_msat",
+			Usage: "the amt of millisatoshis in this invoice",
+		},
+		cli.StringFlag{
+			Name: "description_hash",
+			Usage: "SHA-256 hash of the description of the " +
+				"payment. Used if the purpose of payment " +
+				"cannot naturally fit within the memo. If " +
+				"provided this will be used instead of the " +
+				"description(memo) field in the encoded " +
+				"invoice.",
+		},
+		cli.StringFlag{
+			Name: "fallback_addr",
+			Usage: "fallback on-chain address that can be " +
+				"used in case the lightning payment fails",
+		},
+		cli.Int64Flag{
+			Name: "expiry",
+			Usage: "the invoice's expiry time in seconds. If not " +
+				"specified, an expiry of " +
+				"86400 seconds (24 hours) is implied.",
+		},
+		cli.Uint64Flag{
+			Name: "cltv_expiry_delta",
+			Usage: "The minimum CLTV delta to use for the final " +
+				"hop. If this is set to 0, the default value " +
+				"is used. The default value for " +
+				"cltv_expiry_delta is configured by the " +
+				"'bitcoin.timelockdelta' option.",
+		},
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "encode routing hints in the invoice with " +
+				"private channels in order to assist the " +
+				"payer in reaching you",
+		},
+	},
+	Action: actionDecorator(addStatelessInvoice),
+}
+
+func addStatelessInvoice(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	var (
+		args    = ctx.Args()
+		amt     = ctx.Int64("amt")
+		amtMsat = ctx.Int64("amt_msat")
+		err     error
+	)
+	if !ctx.IsSet("amt") && !ctx.IsSet("amt_msat") && args.Present() {
+		amt, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt argument: %w",
+				err)
+		}
+	}
+
+	descHash, err := hex.DecodeString(ctx.String("description_hash"))
+	if err != nil {
+		return fmt.Errorf("unable to parse description_hash: %w", err)
+	}
+
+	resp, err := client.AddStatelessInvoice(
+		ctxc, &invoicesrpc.AddStatelessInvoiceRequest{
+			Memo:            ctx.String("memo"),
+			Value:           amt,
+			ValueMsat:       amtMsat,
+			DescriptionHash: descHash,
+			FallbackAddr:    ctx.String("fallback_addr"),
+			Expiry:          ctx.Int64("expiry"),
+			CltvExpiry:      ctx.Uint64("cltv_expiry_delta"),
+			Private:         ctx.Bool("private"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var verifyStatelessSettlementCommand = cli.Command{
+	Name:     "verifystatelesssettlement",
+	Category: "Invoices",
+	Usage:    "Verify the settlement of a stateless invoice.",
+	Description: `
+	Verify that a payment address was issued as a stateless invoice for the
+	given amount, and report the preimage derived from it along with the
+	settlement state of the invoice.`,
+	ArgsUsage: "payment_addr amt_msat",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "payment_addr",
+			Usage: "the hex-encoded payment address of the invoice",
+		},
+		cli.Uint64Flag{
+			Name: "amt_msat",
+			Usage: "the amt of millisatoshis the invoice was " +
+				"issued for",
+		},
+	},
+	Action: actionDecorator(verifyStatelessSettlement),
+}
+
+func verifyStatelessSettlement(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	payAddrStr := ctx.String("payment_addr")
+	if !ctx.IsSet("payment_addr") {
+		if !args.Present() {
+			return cli.ShowCommandHelp(
+				ctx, "verifystatelesssettlement",
+			)
+		}
+
+		payAddrStr = args.First()
+		args = args.Tail()
+	}
+
+	payAddr, err := hex.DecodeString(payAddrStr)
+	if err != nil {
+		return fmt.Errorf("unable to parse payment_addr: %w", err)
+	}
+
+	amtMsat := ctx.Uint64("amt_msat")
+	if !ctx.IsSet("amt_msat") && args.Present() {
+		amtMsat, err = strconv.ParseUint(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt_msat "+
+				"argument: %w", err)
+		}
+	}
+
+	resp, err := client.VerifyStatelessSettlement(
+		ctxc, &invoicesrpc.VerifyStatelessSettlementRequest{
+			PaymentAddr: payAddr,
+			ValueMsat:   amtMsat,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var ampSetCommand = cli.Command{
+	Name:     "ampset",
+	Category: "Invoices",
+	Usage:    "Manage the sets of AMP invoices.",
+	Subcommands: []cli.Command{
+		subscribeAMPSetCommand,
+		cancelAMPSetCommand,
+		listAMPSetsCommand,
+	},
+}
+
+var subscribeAMPSetCommand = cli.Command{
+	Name:      "subscribe",
+	Usage:     "Print state changes of an AMP set as they happen.",
+	ArgsUsage: "set_id",
+	Description: `
+	Print the AMP invoice the hex encoded set id belongs to, with only the
+	htlcs of the set populated, every time the state of the set changes.
+	The command returns once the set is settled or canceled.
+	`,
+	Action: actionDecorator(subscribeAMPSet),
+}
+
+func subscribeAMPSet(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "subscribe")
+	}
+
+	setID, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode set_id: %w", err)
+	}
+
+	stream, err := client.SubscribeAMPSet(
+		ctxc, &invoicesrpc.SubscribeAMPSetRequest{
+			SetId: setID,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		invoice, err := stream.Recv()
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+
+		case err != nil:
+			return err
+		}
+
+		printRespJSON(invoice)
+	}
+}
+
+var cancelAMPSetCommand = cli.Command{
+	Name:      "cancel",
+	Usage:     "Cancel the accepted htlcs of a pending AMP set.",
+	ArgsUsage: "set_id",
+	Description: `
+	Cancel the accepted htlcs of the pending AMP set with the given hex
+	encoded set id. The AMP invoice itself and its other sets are not
+	affected.
+	`,
+	Action: actionDecorator(cancelAMPSet),
+}
+
+func cancelAMPSet(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "cancel")
+	}
+
+	setID, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode set_id: %w", err)
+	}
+
+	resp, err := client.CancelAMPSet(
+		ctxc, &invoicesrpc.CancelAMPSetRequest{
+			SetId: setID,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listAMPSetsCommand = cli.Command{
+	Name:      "list",
+	Usage:     "List the pending and settled sets of an AMP invoice.",
+	ArgsUsage: "payment_hash",
+	Description: `
+	List the pending sets of the AMP invoice with the given hex encoded
+	payment hash, along with a page of its settled sets in settle index
+	order.
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "index_offset",
+			Usage: "the settle index to start the page of " +
+				"settled sets at",
+		},
+		cli.Uint64Flag{
+			Name:  "max_sets",
+			Usage: "the max number of settled sets to return",
+		},
+		cli.BoolFlag{
+			Name: "reversed",
+			Usage: "if set, the settled sets returned start " +
+				"from the index offset and go backwards",
+		},
+	},
+	Action: actionDecorator(listAMPSets),
+}
+
+func listAMPSets(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "list")
+	}
+
+	hash, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode payment_hash: %w", err)
+	}
+
+	resp, err := client.ListAMPSets(
+		ctxc, &invoicesrpc.ListAMPSetsRequest{
+			PaymentHash: hash,
+			IndexOffset: ctx.Uint64("index_offset"),
+			NumMaxSets:  ctx.Uint64("max_sets"),
+			Reversed:    ctx.Bool("reversed"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var reissueInvoiceCommand = cli.Command{
+	Name:      "reissueinvoice",
+	Category:  "Invoices",
+	Usage:     "Re-issue the payment request of an invoice.",
+	ArgsUsage: "paymenthash",
+	Description: `
+	Regenerate the hop hints of an open, unexpired invoice and print a new
+	payment request for the same payment hash and payment address. All
+	other fields, including the timestamp and expiry, are carried over from
+	the original payment request, so both remain payable. Invoices with
+	blinded paths can't be re-issued.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "private",
+			Usage: "include hop hints for our private channels in " +
+				"the re-issued payment request",
+		},
+	},
+	Action: actionDecorator(reissueInvoice),
+}
+
+func reissueInvoice(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "reissueinvoice")
+	}
+
+	paymentHash, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to parse payment hash: %w", err)
+	}
+
+	client, cleanUp := getInvoicesClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ReissueInvoice(
+		ctxc, &invoicesrpc.ReissueInvoiceRequest{
+			PaymentHash: paymentHash,
+			Private:     ctx.Bool("private"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
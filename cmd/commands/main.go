@@ -26,6 +26,7 @@ import (
 	"golang.org/x/term"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -230,6 +231,14 @@ func getClientConn(ctx *cli.Context, skipMacaroons bool) *grpc.ClientConn {
 
 	opts = append(opts, grpc.WithDefaultCallOptions(maxMsgRecvSize))
 
+	// Large responses like the one of describegraph shrink considerably
+	// if they are compressed.
+	if ctx.GlobalBool("compress") {
+		opts = append(opts, grpc.WithDefaultCallOptions(
+			grpc.UseCompressor(gzip.Name),
+		))
+	}
+
 	conn, err := grpc.Dial(profile.RPCServer, opts...)
 	if err != nil {
 		fatal(fmt.Errorf("unable to connect to RPC server: %w", err))
@@ -438,6 +447,12 @@ func Main() {
 				"to lnd. This flag may be specified multiple " +
 				"times. The format is: \"key:value\".",
 		},
+		cli.BoolFlag{
+			Name: "compress",
+			Usage: "Request gzip compressed responses, which " +
+				"reduces the size of large responses like " +
+				"the one of describegraph.",
+		},
 		cli.BoolFlag{
 			Name: "insecure",
 			Usage: "Connect to the rpc server without TLS " +
@@ -459,10 +474,15 @@ func Main() {
 		disconnectCommand,
 		openChannelCommand,
 		batchOpenChannelCommand,
+		channelTemplateCommand,
+		acceptPolicyCommand,
+		psbtFlowsCommand,
+		utxoReservationsCommand,
 		closeChannelCommand,
 		closeAllChannelsCommand,
 		abandonChannelCommand,
 		listPeersCommand,
+		gossipScoresCommand,
 		walletBalanceCommand,
 		ChannelBalanceCommand,
 		getInfoCommand,
@@ -471,22 +491,32 @@ func Main() {
 		decryptDebugPackageCommand,
 		getRecoveryInfoCommand,
 		pendingChannelsCommand,
+		htlcExpiryRiskCommand,
 		SendPaymentCommand,
 		payInvoiceCommand,
+		sendPaymentsCommand,
 		sendToRouteCommand,
 		AddInvoiceCommand,
 		lookupInvoiceCommand,
 		listInvoicesCommand,
+		inboxCommand,
 		ListChannelsCommand,
 		closedChannelsCommand,
 		listPaymentsCommand,
 		describeGraphCommand,
+		exportGraphSnapshotCommand,
+		jobsCommand,
+		offChainAccountsCommand,
 		getNodeMetricsCommand,
+		getGraphMetricsCommand,
+		getNodeMetricsV2Command,
 		getChanInfoCommand,
 		getNodeInfoCommand,
 		queryRoutesCommand,
 		getNetworkInfoCommand,
 		debugLevelCommand,
+		runtimeConfigCommand,
+		nodeProfileCommand,
 		decodePayReqCommand,
 		listChainTxnsCommand,
 		stopCommand,
@@ -495,7 +525,9 @@ func Main() {
 		feeReportCommand,
 		updateChannelPolicyCommand,
 		forwardingHistoryCommand,
+		forwardingRulesCommand,
 		exportChanBackupCommand,
+		exportEmergencyBundleCommand,
 		verifyChanBackupCommand,
 		restoreChanBackupCommand,
 		bakeMacaroonCommand,
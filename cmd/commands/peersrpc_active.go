@@ -4,6 +4,7 @@
 package commands
 
 import (
+	"encoding/hex"
 	"fmt"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -22,6 +23,11 @@ func peersCommands() []cli.Command {
 				"network",
 			Subcommands: []cli.Command{
 				updateNodeAnnouncementCommand,
+				setPeerPolicyCommand,
+				deletePeerPolicyCommand,
+				listPeerPoliciesCommand,
+				setInboundAccessCommand,
+				listInboundAccessCommand,
 			},
 		},
 	}
@@ -160,3 +166,218 @@ func updateNodeAnnouncement(ctx *cli.Context) error {
 
 	return nil
 }
+
+var setPeerPolicyCommand = cli.Command{
+	Name:      "setpolicy",
+	Category:  "Peers",
+	Usage:     "Store the connection policy of a peer.",
+	ArgsUsage: "pubkey",
+	Description: `
+	Store the connection policy of the peer with the given public key,
+	replacing its previous policy. A persistent peer is connected right
+	away, the other settings apply to new connections and channels.`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "persistent",
+			Usage: "keep a connection to the peer, even if we " +
+				"have no channel with it",
+		},
+		cli.BoolFlag{
+			Name:  "tor_only",
+			Usage: "only connect to and from the peer over Tor",
+		},
+		cli.BoolFlag{
+			Name:  "allow_inbound",
+			Usage: "allow the peer to connect to us",
+		},
+		cli.Uint64Flag{
+			Name: "max_channels",
+			Usage: "(optional) the maximum number of channels " +
+				"the peer may open with us, including " +
+				"pending ones",
+		},
+		cli.StringSliceFlag{
+			Name: "address",
+			Usage: "(optional) a host:port address to connect to " +
+				"if the peer is persistent. Can be set " +
+				"multiple times in the same command",
+		},
+	},
+	Action: actionDecorator(setPeerPolicy),
+}
+
+func setPeerPolicy(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "setpolicy")
+	}
+
+	pubKey, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode peer public key: %w", err)
+	}
+
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.SetPeerPolicy(ctxc, &peersrpc.SetPeerPolicyRequest{
+		Policy: &peersrpc.PeerPolicy{
+			PubKey:       pubKey,
+			Persistent:   ctx.Bool("persistent"),
+			TorOnly:      ctx.Bool("tor_only"),
+			AllowInbound: ctx.Bool("allow_inbound"),
+			MaxChannels:  uint32(ctx.Uint64("max_channels")),
+			Addresses:    ctx.StringSlice("address"),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var deletePeerPolicyCommand = cli.Command{
+	Name:      "deletepolicy",
+	Category:  "Peers",
+	Usage:     "Remove the connection policy of a peer.",
+	ArgsUsage: "pubkey",
+	Action:    actionDecorator(deletePeerPolicy),
+}
+
+func deletePeerPolicy(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "deletepolicy")
+	}
+
+	pubKey, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode peer public key: %w", err)
+	}
+
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.DeletePeerPolicy(
+		ctxc, &peersrpc.DeletePeerPolicyRequest{PubKey: pubKey},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listPeerPoliciesCommand = cli.Command{
+	Name:     "listpolicies",
+	Category: "Peers",
+	Usage:    "List the connection policies of all peers.",
+	Action:   actionDecorator(listPeerPolicies),
+}
+
+func listPeerPolicies(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListPeerPolicies(
+		ctxc, &peersrpc.ListPeerPoliciesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var setInboundAccessCommand = cli.Command{
+	Name:      "setinboundaccess",
+	Category:  "Peers",
+	Usage:     "Put a peer on the inbound connection allow or deny list.",
+	ArgsUsage: "pubkey allow|deny|none",
+	Description: `
+	Put the peer with the given public key on the inbound connection allow
+	or deny list, or remove it from both with none. Once the allow list
+	isn't empty, only the peers on it may connect to us. The lists apply to
+	new connections.`,
+	Action: actionDecorator(setInboundAccess),
+}
+
+func setInboundAccess(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 2 {
+		return cli.ShowCommandHelp(ctx, "setinboundaccess")
+	}
+
+	pubKey, err := hex.DecodeString(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode peer public key: %w", err)
+	}
+
+	var access peersrpc.InboundAccess
+	switch ctx.Args().Get(1) {
+	case "allow":
+		access = peersrpc.InboundAccess_ACCESS_ALLOW
+
+	case "deny":
+		access = peersrpc.InboundAccess_ACCESS_DENY
+
+	case "none":
+		access = peersrpc.InboundAccess_ACCESS_NONE
+
+	default:
+		return fmt.Errorf("unknown inbound access %v, expected "+
+			"allow, deny or none", ctx.Args().Get(1))
+	}
+
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.SetInboundAccess(
+		ctxc, &peersrpc.SetInboundAccessRequest{
+			PubKey: pubKey,
+			Access: access,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var listInboundAccessCommand = cli.Command{
+	Name:     "listinboundaccess",
+	Category: "Peers",
+	Usage:    "List the inbound connection allow and deny lists.",
+	Action:   actionDecorator(listInboundAccess),
+}
+
+func listInboundAccess(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getPeersClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListInboundAccess(
+		ctxc, &peersrpc.ListInboundAccessRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
@@ -8,10 +8,19 @@ func routerCommands() []cli.Command {
 		queryMissionControlCommand,
 		importMissionControlCommand,
 		queryProbCommand,
+		probeHistoryCommand,
 		resetMissionControlCommand,
 		buildRouteCommand,
 		getCfgCommand,
 		setCfgCommand,
 		updateChanStatusCommand,
+		rebalanceCommand,
+		subscribeAllCommand,
+		stuckHtlcsCommand,
+		dustExposureCommand,
+		updateMaxFeeExposureCommand,
+		reputationCommand,
+		fwdPkgsCommand,
+		reprocessFwdPkgCommand,
 	}
 }
@@ -36,6 +36,8 @@ var (
 		Subcommands: []cli.Command{
 			fundPsbtCommand,
 			fundTemplatePsbtCommand,
+			exportPsbtCommand,
+			signPsbtCommand,
 			finalizePsbtCommand,
 		},
 	}
@@ -64,6 +66,18 @@ var (
 		},
 	}
 
+	// anchorBumpsCommand is a wallet subcommand that is responsible for
+	// the anchor fee bumps that are delegated to an external service.
+	anchorBumpsCommand = cli.Command{
+		Name: "anchorbumps",
+		Usage: "Interact with the anchor fee bumps delegated to an " +
+			"external service.",
+		Subcommands: []cli.Command{
+			listAnchorBumpsCommand,
+			subscribeAnchorBumpsCommand,
+		},
+	}
+
 	p2TrChangeType = walletrpc.ChangeAddressType_CHANGE_ADDRESS_TYPE_P2TR
 )
 
@@ -94,6 +108,11 @@ func walletCommands() []cli.Command {
 				accountsCommand,
 				requiredReserveCommand,
 				addressesCommand,
+				anchorBumpsCommand,
+				spendClaimsCommand,
+				sweepLedgerCommand,
+				sweepNowCommand,
+				overrideSweepCommand,
 			},
 		},
 	}
@@ -1440,6 +1459,7 @@ var finalizePsbtCommand = cli.Command{
 			Name:  "funded_psbt",
 			Usage: "the base64 encoded PSBT to finalize",
 		},
+		inFileFlag,
 		cli.StringFlag{
 			Name: "account",
 			Usage: "(optional) the name of the account to " +
@@ -1454,24 +1474,11 @@ func finalizePsbt(ctx *cli.Context) error {
 
 	// Display the command's help message if we do not have the expected
 	// number of arguments/flags.
-	if ctx.NArg() > 1 || ctx.NumFlags() > 2 {
+	if ctx.NArg() > 1 || ctx.NumFlags() > 3 {
 		return cli.ShowCommandHelp(ctx, "finalize")
 	}
 
-	var (
-		args       = ctx.Args()
-		psbtBase64 string
-	)
-	switch {
-	case ctx.IsSet("funded_psbt"):
-		psbtBase64 = ctx.String("funded_psbt")
-	case args.Present():
-		psbtBase64 = args.First()
-	default:
-		return fmt.Errorf("funded_psbt argument missing")
-	}
-
-	psbtBytes, err := base64.StdEncoding.DecodeString(psbtBase64)
+	psbtBytes, err := readPsbtFlag(ctx, "funded_psbt")
 	if err != nil {
 		return err
 	}
@@ -2162,3 +2169,290 @@ func importPubKey(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+var listAnchorBumpsCommand = cli.Command{
+	Name:  "list",
+	Usage: "List the pending delegated anchor fee bumps.",
+	Description: `
+	List the fee bumps of the force closed commitments that are delegated to
+	an external service and are still pending. This requires the
+	forceclose.delegateanchorbumps option to be set.
+	`,
+	Action: actionDecorator(listAnchorBumps),
+}
+
+func listAnchorBumps(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() != 0 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "list")
+	}
+
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListDelegatedAnchorBumps(
+		ctxc, &walletrpc.ListDelegatedAnchorBumpsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var subscribeAnchorBumpsCommand = cli.Command{
+	Name:  "subscribe",
+	Usage: "Subscribe to new delegated anchor fee bumps.",
+	Description: `
+	Print the fee bumps of force closed commitments as they are delegated to
+	an external service, until the command is interrupted.
+	`,
+	Action: actionDecorator(subscribeAnchorBumps),
+}
+
+func subscribeAnchorBumps(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() != 0 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "subscribe")
+	}
+
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeDelegatedAnchorBumps(
+		ctxc, &walletrpc.SubscribeDelegatedAnchorBumpsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		bump, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(bump)
+	}
+}
+
+var spendClaimsCommand = cli.Command{
+	Name:  "spendclaims",
+	Usage: "List the outpoints claimed by the subsystems of lnd.",
+	Description: `
+	List the outpoints that are currently spent by one of the subsystems
+	that broadcast transactions (the sweeper, the contract court and the
+	user), together with the most recent broadcasts that were rejected
+	because they spent an outpoint claimed by another subsystem.
+	`,
+	Action: actionDecorator(listSpendClaims),
+}
+
+func listSpendClaims(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() != 0 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "spendclaims")
+	}
+
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListSpendClaims(
+		ctxc, &walletrpc.ListSpendClaimsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var sweepLedgerCommand = cli.Command{
+	Name:  "sweepledger",
+	Usage: "List the history of all sweeps published by the sweeper.",
+	Description: `
+	List every sweep published by the sweeper, including its inputs, each
+	RBF attempt with its fee rate and fee, and whether it confirmed or
+	failed.
+
+	With --csv the ledger is printed in CSV format instead, so that the
+	fees that went to the chain can be audited in a spreadsheet.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "csv",
+			Usage: "print the ledger in CSV format",
+		},
+	},
+	Action: actionDecorator(listSweepLedger),
+}
+
+func listSweepLedger(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments.
+	if ctx.NArg() != 0 {
+		return cli.ShowCommandHelp(ctx, "sweepledger")
+	}
+
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListSweepLedger(
+		ctxc, &walletrpc.ListSweepLedgerRequest{
+			Csv: ctx.Bool("csv"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("csv") {
+		fmt.Print(string(resp.Csv))
+
+		return nil
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var sweepNowCommand = cli.Command{
+	Name:      "sweepnow",
+	Usage:     "Sweep the given inputs right away.",
+	ArgsUsage: "outpoint [outpoint...]",
+	Description: `
+	Sweep the given inputs right away, bypassing the batch windows of the
+	sweeper and without waiting for the next block. The inputs must be
+	pending in the sweeper. They are swept in transactions of their own,
+	and inputs that were already published are swept again, which allows
+	operators to respond to pinning attacks on sweeping transactions.
+
+	The outpoints are expected in the format txid:output_index.
+	`,
+	Action: actionDecorator(sweepNow),
+}
+
+func sweepNow(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments.
+	if ctx.NArg() == 0 {
+		return cli.ShowCommandHelp(ctx, "sweepnow")
+	}
+
+	outpoints, err := UtxosToOutpoints(ctx.Args())
+	if err != nil {
+		return fmt.Errorf("unable to decode outpoints: %w", err)
+	}
+
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.SweepNow(ctxc, &walletrpc.SweepNowRequest{
+		Outpoints: outpoints,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var overrideSweepCommand = cli.Command{
+	Name:      "overridesweep",
+	Usage:     "Override the sweep parameters of a pending input.",
+	ArgsUsage: "outpoint",
+	Description: `
+	Override the sweep parameters of an input that is pending in the
+	sweeper. Only the given parameters are changed, the others are left as
+	they are. The input is swept again with the new parameters, replacing
+	its current sweeping transaction.
+
+	The state of the fee function of the sweeping transaction, including
+	the fee of its next bump, is shown by 'lncli wallet pendingsweeps'.
+
+	The outpoint is expected in the format txid:output_index.
+	`,
+	Flags: []cli.Flag{
+		cli.Uint64Flag{
+			Name: "budget",
+			Usage: "the max amount in sats that can be used as " +
+				"the fees, which also disables the escalation " +
+				"of the budget",
+		},
+		cli.Uint64Flag{
+			Name:  "deadline_height",
+			Usage: "the height by which the input should be swept",
+		},
+		cli.Uint64Flag{
+			Name:  "exclusive_group",
+			Usage: "the exclusive group to move the input into",
+		},
+		cli.BoolFlag{
+			Name: "remove_exclusive_group",
+			Usage: "remove the input from its exclusive group so " +
+				"it can be batched with any other input",
+		},
+		cli.BoolFlag{
+			Name: "immediate",
+			Usage: "whether the input will be swept immediately " +
+				"without waiting for the next batch",
+		},
+	},
+	Action: actionDecorator(overrideSweep),
+}
+
+func overrideSweep(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments.
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "overridesweep")
+	}
+
+	outpoint, err := NewProtoOutPoint(ctx.Args().First())
+	if err != nil {
+		return fmt.Errorf("unable to decode outpoint: %w", err)
+	}
+
+	client, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.OverrideSweepParams(
+		ctxc, &walletrpc.OverrideSweepParamsRequest{
+			Outpoint:             outpoint,
+			Budget:               ctx.Uint64("budget"),
+			DeadlineHeight:       uint32(ctx.Uint64("deadline_height")),
+			ExclusiveGroup:       ctx.Uint64("exclusive_group"),
+			RemoveExclusiveGroup: ctx.Bool("remove_exclusive_group"),
+			Immediate:            ctx.Bool("immediate"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
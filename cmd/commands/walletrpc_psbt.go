@@ -0,0 +1,305 @@
+//go:build walletrpc
+// +build walletrpc
+
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/urfave/cli"
+)
+
+var (
+	// inFileFlag is the flag to read a PSBT from a file, which is how
+	// PSBTs are exchanged with air-gapped signers.
+	inFileFlag = cli.StringFlag{
+		Name: "in_file",
+		Usage: "the path of a file that holds the binary or base64 " +
+			"encoded PSBT",
+	}
+
+	// outFileFlag is the flag to write the resulting PSBT to a file.
+	outFileFlag = cli.StringFlag{
+		Name: "out_file",
+		Usage: "(optional) the path of a file to write the binary " +
+			"PSBT to, which can be carried to the next signer",
+	}
+)
+
+// readPsbtFlag returns the binary PSBT that was passed as base64 string in the
+// given flag or as the first argument, or through the file of the in_file
+// flag.
+func readPsbtFlag(ctx *cli.Context, flagName string) ([]byte, error) {
+	switch {
+	case ctx.IsSet(flagName) && ctx.IsSet(inFileFlag.Name):
+		return nil, fmt.Errorf("either %s or %s can be set, but not "+
+			"both", flagName, inFileFlag.Name)
+
+	case ctx.IsSet(flagName):
+		return base64.StdEncoding.DecodeString(ctx.String(flagName))
+
+	case ctx.IsSet(inFileFlag.Name):
+		fileName := lncfg.CleanAndExpandPath(
+			ctx.String(inFileFlag.Name),
+		)
+		stat, err := os.Stat(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		// Even very large PSBTs are only a few hundred kilobytes, so we
+		// refuse to read anything bigger.
+		if stat.Size() > psbtMaxFileSize {
+			return nil, fmt.Errorf("size of %d bytes exceeds max "+
+				"PSBT file size of %d", stat.Size(),
+				psbtMaxFileSize)
+		}
+
+		content, err := os.ReadFile(fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		return decodePsbt(string(content))
+
+	case ctx.Args().Present():
+		return base64.StdEncoding.DecodeString(ctx.Args().First())
+
+	default:
+		return nil, fmt.Errorf("%s argument missing", flagName)
+	}
+}
+
+// writePsbtFile writes the binary PSBT to the file of the out_file flag, if
+// it is set.
+func writePsbtFile(ctx *cli.Context, psbtBytes []byte) error {
+	if !ctx.IsSet(outFileFlag.Name) {
+		return nil
+	}
+
+	fileName := lncfg.CleanAndExpandPath(ctx.String(outFileFlag.Name))
+	if err := os.WriteFile(fileName, psbtBytes, 0600); err != nil {
+		return fmt.Errorf("unable to write PSBT file: %w", err)
+	}
+
+	return nil
+}
+
+// checkOfflineInputs makes sure the PSBT holds everything a signer needs to
+// sign the inputs without access to the chain or the wallet of the watch-only
+// node: the UTXO of each input and the derivation path of its key.
+func checkOfflineInputs(packet *psbt.Packet) error {
+	for idx, in := range packet.Inputs {
+		if in.WitnessUtxo == nil && in.NonWitnessUtxo == nil {
+			return fmt.Errorf("input %d has no UTXO information, "+
+				"which an offline signer can't look up", idx)
+		}
+
+		if len(in.Bip32Derivation) == 0 &&
+			len(in.TaprootBip32Derivation) == 0 {
+
+			return fmt.Errorf("input %d has no derivation path, "+
+				"so the signer can't find its key", idx)
+		}
+	}
+
+	return nil
+}
+
+// psbtSummary is a short description of a PSBT for the operator to check
+// before it is carried to a signer.
+type psbtSummary struct {
+	Txid        string `json:"txid"`
+	NumInputs   int    `json:"num_inputs"`
+	NumOutputs  int    `json:"num_outputs"`
+	TotalOutSat int64  `json:"total_out_sat"`
+	FeeSat      int64  `json:"fee_sat,omitempty"`
+	OutFile     string `json:"out_file,omitempty"`
+	Psbt        string `json:"psbt"`
+}
+
+// newPsbtSummary summarizes the PSBT. The fee is only known if all inputs
+// carry their UTXO.
+func newPsbtSummary(packet *psbt.Packet, psbtBytes []byte) *psbtSummary {
+	summary := &psbtSummary{
+		Txid:       packet.UnsignedTx.TxHash().String(),
+		NumInputs:  len(packet.UnsignedTx.TxIn),
+		NumOutputs: len(packet.UnsignedTx.TxOut),
+		Psbt:       base64.StdEncoding.EncodeToString(psbtBytes),
+	}
+
+	for _, out := range packet.UnsignedTx.TxOut {
+		summary.TotalOutSat += out.Value
+	}
+
+	inputSum, err := psbt.SumUtxoInputValues(packet)
+	if err == nil {
+		summary.FeeSat = inputSum - summary.TotalOutSat
+	}
+
+	return summary
+}
+
+var exportPsbtCommand = cli.Command{
+	Name:      "export",
+	Usage:     "Prepare a PSBT for an offline signer.",
+	ArgsUsage: "funded_psbt",
+	Description: `
+	The export command checks that a funded PSBT can be signed by an
+	offline signer and writes it to a binary PSBT file, which is the format
+	air-gapped signers and hardware wallet tools exchange. It is run on the
+	watch-only node after funding the PSBT with 'lncli wallet psbt fund'.
+
+	Every input must carry its UTXO and the derivation path of its key,
+	since an offline signer can neither look up the chain nor the wallet
+	of the watch-only node. The txid of the summary doesn't change when
+	the PSBT is signed, so it identifies the transaction on both sides of
+	the exchange.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funded_psbt",
+			Usage: "the base64 encoded PSBT to export",
+		},
+		inFileFlag,
+		outFileFlag,
+	},
+	Action: actionDecorator(exportPsbt),
+}
+
+func exportPsbt(ctx *cli.Context) error {
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() > 1 || ctx.NumFlags() > 3 {
+		return cli.ShowCommandHelp(ctx, "export")
+	}
+
+	psbtBytes, err := readPsbtFlag(ctx, "funded_psbt")
+	if err != nil {
+		return err
+	}
+
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader(psbtBytes), false)
+	if err != nil {
+		return fmt.Errorf("error parsing PSBT: %w", err)
+	}
+
+	if err := checkOfflineInputs(packet); err != nil {
+		return err
+	}
+
+	if err := writePsbtFile(ctx, psbtBytes); err != nil {
+		return err
+	}
+
+	summary := newPsbtSummary(packet, psbtBytes)
+	summary.OutFile = ctx.String(outFileFlag.Name)
+
+	printJSON(summary)
+
+	return nil
+}
+
+// signPsbtResponse is a struct that contains JSON annotations for nice result
+// serialization.
+type signPsbtResponse struct {
+	Txid         string   `json:"txid"`
+	Psbt         string   `json:"psbt"`
+	SignedInputs []uint32 `json:"signed_inputs"`
+	OutFile      string   `json:"out_file,omitempty"`
+}
+
+var signPsbtCommand = cli.Command{
+	Name:      "sign",
+	Usage:     "Sign the inputs of a PSBT that belong to the wallet.",
+	ArgsUsage: "funded_psbt",
+	Description: `
+	The sign command signs all inputs of a funded PSBT that belong to the
+	wallet of the node, without finalizing them. In a remote signer setup
+	it is run against the signer node.
+
+	With --offline, the PSBT is expected to come from an air-gapped
+	exchange: every input must carry its UTXO and the derivation path of
+	its key, since the signer can't look them up. The signed PSBT should
+	be written to a file with --out_file and carried back to the
+	watch-only node, where 'lncli wallet psbt finalize --in_file' finalizes
+	it and 'lncli wallet publishtx' publishes it.
+	`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funded_psbt",
+			Usage: "the base64 encoded PSBT to sign",
+		},
+		inFileFlag,
+		outFileFlag,
+		cli.BoolFlag{
+			Name: "offline",
+			Usage: "require all information to sign the PSBT " +
+				"to be part of it, as an air-gapped signer " +
+				"can't look anything up",
+		},
+	},
+	Action: actionDecorator(signPsbt),
+}
+
+func signPsbt(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if we do not have the expected
+	// number of arguments/flags.
+	if ctx.NArg() > 1 || ctx.NumFlags() > 4 {
+		return cli.ShowCommandHelp(ctx, "sign")
+	}
+
+	psbtBytes, err := readPsbtFlag(ctx, "funded_psbt")
+	if err != nil {
+		return err
+	}
+
+	packet, err := psbt.NewFromRawBytes(bytes.NewReader(psbtBytes), false)
+	if err != nil {
+		return fmt.Errorf("error parsing PSBT: %w", err)
+	}
+
+	if ctx.Bool("offline") {
+		if err := checkOfflineInputs(packet); err != nil {
+			return err
+		}
+	}
+
+	walletClient, cleanUp := getWalletClient(ctx)
+	defer cleanUp()
+
+	response, err := walletClient.SignPsbt(ctxc, &walletrpc.SignPsbtRequest{
+		FundedPsbt: psbtBytes,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(response.SignedInputs) == 0 {
+		return errors.New("no input of the PSBT belongs to the wallet")
+	}
+
+	if err := writePsbtFile(ctx, response.SignedPsbt); err != nil {
+		return err
+	}
+
+	printJSON(&signPsbtResponse{
+		Txid: packet.UnsignedTx.TxHash().String(),
+		Psbt: base64.StdEncoding.EncodeToString(
+			response.SignedPsbt,
+		),
+		SignedInputs: response.SignedInputs,
+		OutFile:      ctx.String(outFileFlag.Name),
+	})
+
+	return nil
+}
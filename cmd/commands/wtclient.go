@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/lightningnetwork/lnd/lnrpc/wtclientrpc"
@@ -25,8 +26,11 @@ func wtclientCommands() []cli.Command {
 				listTowersCommand,
 				getTowerCommand,
 				statsCommand,
+				dbStatsCommand,
 				policyCommand,
 				sessionCommands,
+				channelCommands,
+				justiceReportCommands,
 			},
 		},
 	}
@@ -314,6 +318,34 @@ func stats(ctx *cli.Context) error {
 	return nil
 }
 
+var dbStatsCommand = cli.Command{
+	Name:   "dbstats",
+	Usage:  "Display the storage stats of the watchtower client database.",
+	Action: actionDecorator(dbStats),
+}
+
+func dbStats(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if the number of arguments/flags
+	// is not what we expect.
+	if ctx.NArg() > 0 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "dbstats")
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	req := &wtclientrpc.DBStatsRequest{}
+	resp, err := client.DBStats(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 var policyCommand = cli.Command{
 	Name:   "policy",
 	Usage:  "Display the active watchtower client policy configuration.",
@@ -418,3 +450,215 @@ func terminateSession(ctx *cli.Context) error {
 
 	return nil
 }
+
+var channelCommands = cli.Command{
+	Name:  "channel",
+	Usage: "Manage the backups of individual channels.",
+	Subcommands: []cli.Command{
+		excludeChannelCommand,
+		channelSweepFeeRateCommand,
+		channelCoverageCommand,
+	},
+}
+
+var excludeChannelCommand = cli.Command{
+	Name:      "exclude",
+	Usage:     "Exclude a channel from watchtower backups.",
+	ArgsUsage: "chan_point",
+	Description: `
+	Exclude the channel with the given funding outpoint from being backed up
+	to the watchtowers. States of the channel that are revoked while it is
+	excluded are never backed up. Use --include to back the channel up
+	again.
+	`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "include",
+			Usage: "include the channel in backups again",
+		},
+	},
+	Action: actionDecorator(excludeChannel),
+}
+
+func excludeChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "exclude")
+	}
+
+	chanPoint, err := parseChanPoint(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ExcludeChannel(
+		ctxc, &wtclientrpc.ExcludeChannelRequest{
+			ChanPoint: chanPoint,
+			Exclude:   !ctx.Bool("include"),
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var channelSweepFeeRateCommand = cli.Command{
+	Name:      "sweepfeerate",
+	Usage:     "Require a minimum sweep fee rate for a channel's backups.",
+	ArgsUsage: "chan_point sat_per_vbyte",
+	Description: `
+	Set the minimum fee rate in sat/vbyte that the justice transactions of
+	the channel with the given funding outpoint must pay. States of the
+	channel are only backed up while the client's sweep fee rate is at least
+	this high. A fee rate of zero removes the minimum.
+	`,
+	Action: actionDecorator(channelSweepFeeRate),
+}
+
+func channelSweepFeeRate(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	if ctx.NArg() != 2 {
+		return cli.ShowCommandHelp(ctx, "sweepfeerate")
+	}
+
+	chanPoint, err := parseChanPoint(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+
+	satPerVByte, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid fee rate: %w", err)
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	resp, err := client.SetChannelSweepFeeRate(
+		ctxc, &wtclientrpc.SetChannelSweepFeeRateRequest{
+			ChanPoint:   chanPoint,
+			SatPerVbyte: satPerVByte,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var channelCoverageCommand = cli.Command{
+	Name: "coverage",
+	Usage: "Display the backup settings and protected commitment " +
+		"heights of channels.",
+	ArgsUsage: "[chan_point...]",
+	Description: `
+	Display the backup settings and the commitment heights that are
+	protected by the watchtowers for the channels with the given funding
+	outpoints, or for all registered channels if none are given.
+	`,
+	Action: actionDecorator(channelCoverage),
+}
+
+func channelCoverage(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	req := &wtclientrpc.ChannelCoverageRequest{}
+	for _, arg := range ctx.Args() {
+		chanPoint, err := parseChanPoint(arg)
+		if err != nil {
+			return err
+		}
+
+		req.ChanPoints = append(req.ChanPoints, chanPoint)
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ChannelCoverage(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var justiceReportCommands = cli.Command{
+	Name: "justicereports",
+	Usage: "Display the justice transactions the watchtowers " +
+		"published.",
+	Subcommands: []cli.Command{
+		listJusticeReportsCommand,
+		subscribeJusticeReportsCommand,
+	},
+}
+
+var listJusticeReportsCommand = cli.Command{
+	Name: "list",
+	Usage: "List the justice transactions the watchtowers reported to " +
+		"have published.",
+	Description: `
+	List the justice transactions the watchtowers reported to have
+	published. Each of them indicates that one of our channels was
+	breached, possibly while the node was offline.
+	`,
+	Action: actionDecorator(listJusticeReports),
+}
+
+func listJusticeReports(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	resp, err := client.ListJusticeReports(
+		ctxc, &wtclientrpc.ListJusticeReportsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var subscribeJusticeReportsCommand = cli.Command{
+	Name: "subscribe",
+	Usage: "Print the justice transactions the watchtowers report " +
+		"as they arrive.",
+	Action: actionDecorator(subscribeJusticeReports),
+}
+
+func subscribeJusticeReports(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeJusticeReports(
+		ctxc, &wtclientrpc.SubscribeJusticeReportsRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		report, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(report)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/chanacceptor"
 	"github.com/lightningnetwork/lnd/chanbackup"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/discovery"
@@ -57,6 +58,7 @@ const (
 	defaultAdminMacFilename   = "admin.macaroon"
 	defaultReadMacFilename    = "readonly.macaroon"
 	defaultInvoiceMacFilename = "invoice.macaroon"
+	defaultLockedRPCTokenName = "lockedrpc.token"
 	defaultLogLevel           = "info"
 	defaultLogDirname         = "logs"
 	defaultLogFilename        = "lnd.log"
@@ -410,6 +412,8 @@ type Config struct {
 
 	ChannelCommitBatchSize uint32 `long:"channel-commit-batch-size" description:"The maximum number of channel state updates that is accumulated before signing a new commitment."`
 
+	ChannelSettleBatchWindow time.Duration `long:"channel-settle-batch-window" description:"The maximum time that settles and fails of HTLCs are held before signing a new commitment that includes them. Holding them allows multiple resolutions to be signed in a single commitment, which improves the throughput of busy routing nodes at the cost of latency. The batch is signed early once channel-commit-batch-size updates are pending. Setting this to 0 signs a new commitment right away. Must not exceed channel-commit-interval."`
+
 	KeepFailedPaymentAttempts bool `long:"keep-failed-payment-attempts" description:"Keeps persistent record of all failed payment attempts for successfully settled payments."`
 
 	StoreFinalHtlcResolutions bool `long:"store-final-htlc-resolutions" description:"Persistently store the final resolution of incoming htlcs."`
@@ -431,6 +435,10 @@ type Config struct {
 	// registered regardless of whether the RPC is called or not.
 	RequireInterceptor bool `long:"requireinterceptor" description:"Whether to always intercept HTLCs, even if no stream is attached"`
 
+	// CltvInterceptDelta is the number of blocks before the expiry of an
+	// HTLC where it isn't offered to the HTLC interceptor anymore.
+	CltvInterceptDelta uint32 `long:"cltvinterceptdelta" description:"The number of blocks before the expiry of an HTLC where it isn't offered to the HTLC interceptor anymore. The interceptor has until the HTLC is within 13 blocks of its expiry to resolve it, after which it is failed back. Must be greater than 13."`
+
 	StaggerInitialReconnect bool `long:"stagger-initial-reconnect" description:"If true, will apply a randomized staggering between 0s and 30s when reconnecting to persistent peers on startup. The first 10 reconnections will be attempted instantly, regardless of the flag's value"`
 
 	MaxOutgoingCltvExpiry uint32 `long:"max-cltv-expiry" description:"The maximum number of blocks funds could be locked up for when forwarding payments."`
@@ -487,12 +495,28 @@ type Config struct {
 
 	RPCMiddleware *lncfg.RPCMiddleware `group:"rpcmiddleware" namespace:"rpcmiddleware"`
 
+	RPCRateLimit *lncfg.RPCRateLimit `group:"rpcratelimit" namespace:"rpcratelimit"`
+
+	LockedRPC *lncfg.LockedRPC `group:"lockedrpc" namespace:"lockedrpc"`
+
+	Accounts *lncfg.Accounts `group:"accounts" namespace:"accounts"`
+
+	Caveats *lncfg.Caveats `group:"caveats" namespace:"caveats"`
+
 	RemoteSigner *lncfg.RemoteSigner `group:"remotesigner" namespace:"remotesigner"`
 
 	Sweeper *lncfg.Sweeper `group:"sweeper" namespace:"sweeper"`
 
 	Htlcswitch *lncfg.Htlcswitch `group:"htlcswitch" namespace:"htlcswitch"`
 
+	ForceClose *lncfg.ForceClose `group:"forceclose" namespace:"forceclose"`
+
+	AcceptPolicy *lncfg.AcceptPolicy `group:"acceptpolicy" namespace:"acceptpolicy"`
+
+	ZeroConf *lncfg.ZeroConf `group:"zeroconf" namespace:"zeroconf"`
+
+	InboundPolicy *lncfg.InboundPolicy `group:"inboundpolicy" namespace:"inboundpolicy"`
+
 	GRPC *GRPCConfig `group:"grpc" namespace:"grpc"`
 
 	// SubLogMgr is the root logger that all the daemon's subloggers are
@@ -506,6 +530,10 @@ type Config struct {
 	// network.
 	networkDir string
 
+	// runtimeOverridesPath is the path to the file that holds the options
+	// that were changed through the runtime config RPC.
+	runtimeOverridesPath string
+
 	// ActiveNetParams contains parameters of the target chain.
 	ActiveNetParams chainreg.BitcoinNetParams
 
@@ -699,12 +727,17 @@ func DefaultConfig() Config {
 		Gossip: &lncfg.Gossip{
 			MaxChannelUpdateBurst: discovery.DefaultMaxChannelUpdateBurst,
 			ChannelUpdateInterval: discovery.DefaultChannelUpdateInterval,
+			PeerMsgRate:           discovery.DefaultPeerMsgRate,
+			PeerMsgBurst:          discovery.DefaultPeerMsgBurst,
 			SubBatchDelay:         discovery.DefaultSubBatchDelay,
 		},
 		Invoices: &lncfg.Invoices{
-			HoldExpiryDelta: lncfg.DefaultHoldInvoiceExpiryDelta,
+			HoldExpiryDelta:    lncfg.DefaultHoldInvoiceExpiryDelta,
+			HoldWarningDelta:   lncfg.DefaultHoldWarningDelta,
+			WebhookMaxAttempts: lncfg.DefaultWebhookMaxAttempts,
 		},
 		Routing: &lncfg.Routing{
+			GraphAnalyticsBatchDelay: autopilot.DefaultAnalyticsBatchDelay,
 			BlindedPaths: lncfg.BlindedPaths{
 				MinNumRealHops:           lncfg.DefaultMinNumRealBlindedPathHops,
 				NumHops:                  lncfg.DefaultNumBlindedPathHops,
@@ -712,7 +745,14 @@ func DefaultConfig() Config {
 				PolicyIncreaseMultiplier: lncfg.DefaultBlindedPathPolicyIncreaseMultiplier,
 				PolicyDecreaseMultiplier: lncfg.DefaultBlindedPathPolicyDecreaseMultiplier,
 			},
+			Probing: &lncfg.Probing{
+				Interval: routing.DefaultProbeInterval,
+				Amount:   uint64(routing.DefaultProbeAmount.ToSatoshis()),
+				FeeLimit: uint64(routing.DefaultProbeFeeLimit.ToSatoshis()),
+				Timeout:  routing.DefaultProbeTimeout,
+			},
 		},
+		CltvInterceptDelta:        lncfg.DefaultCltvInterceptDelta,
 		MaxOutgoingCltvExpiry:     htlcswitch.DefaultMaxOutgoingCltvExpiry,
 		MaxChannelFeeAllocation:   htlcswitch.DefaultMaxLinkFeeAllocation,
 		MaxCommitFeeRateAnchors:   lnwallet.DefaultAnchorsCommitMaxFeeRateSatPerVByte,
@@ -721,6 +761,10 @@ func DefaultConfig() Config {
 		DB:                        lncfg.DefaultDB(),
 		Cluster:                   lncfg.DefaultCluster(),
 		RPCMiddleware:             lncfg.DefaultRPCMiddleware(),
+		RPCRateLimit:              lncfg.DefaultRPCRateLimit(),
+		LockedRPC:                 lncfg.DefaultLockedRPC(),
+		Accounts:                  lncfg.DefaultAccounts(),
+		Caveats:                   lncfg.DefaultCaveats(),
 		ActiveNetParams:           chainreg.BitcoinTestNetParams,
 		ChannelCommitInterval:     defaultChannelCommitInterval,
 		PendingCommitInterval:     defaultPendingCommitInterval,
@@ -733,7 +777,17 @@ func DefaultConfig() Config {
 		Sweeper: lncfg.DefaultSweeperConfig(),
 		Htlcswitch: &lncfg.Htlcswitch{
 			MailboxDeliveryTimeout: htlcswitch.DefaultMailboxDeliveryTimeout,
+			MailboxOverflowPolicy:  "fail",
+		},
+		ForceClose: &lncfg.ForceClose{},
+		AcceptPolicy: &lncfg.AcceptPolicy{
+			Order: append(
+				[]string(nil), lncfg.DefaultAcceptPolicyOrder...,
+			),
+			MaxDecisions: chanacceptor.DefaultMaxPolicyDecisions,
 		},
+		ZeroConf:      &lncfg.ZeroConf{},
+		InboundPolicy: &lncfg.InboundPolicy{},
 		GRPC: &GRPCConfig{
 			ServerPingTime:    defaultGrpcServerPingTime,
 			ServerPingTimeout: defaultGrpcServerPingTimeout,
@@ -814,6 +868,21 @@ func LoadConfig(interceptor signal.Interceptor) (*Config, error) {
 		configFileError = err
 	}
 
+	// The options that were changed at runtime are stored next to the
+	// config file and take precedence over it.
+	cfg.runtimeOverridesPath = filepath.Join(
+		configFileDir, runtimeOverridesFilename,
+	)
+	if lnrpc.FileExists(cfg.runtimeOverridesPath) {
+		err := flags.NewIniParser(fileParser).ParseFile(
+			cfg.runtimeOverridesPath,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse runtime "+
+				"overrides: %w", err)
+		}
+	}
+
 	// Finally, parse the remaining command line options again to ensure
 	// they take precedence.
 	flagParser := flags.NewParser(&cfg, flags.Default)
@@ -963,6 +1032,10 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 	cfg.AdminMacPath = CleanAndExpandPath(cfg.AdminMacPath)
 	cfg.ReadMacPath = CleanAndExpandPath(cfg.ReadMacPath)
 	cfg.InvoiceMacPath = CleanAndExpandPath(cfg.InvoiceMacPath)
+	cfg.LockedRPC.TokenPath = CleanAndExpandPath(cfg.LockedRPC.TokenPath)
+	cfg.Htlcswitch.ForwardingRulesFile = CleanAndExpandPath(
+		cfg.Htlcswitch.ForwardingRulesFile,
+	)
 	cfg.LogDir = CleanAndExpandPath(cfg.LogDir)
 	cfg.BtcdMode.Dir = CleanAndExpandPath(cfg.BtcdMode.Dir)
 	cfg.BitcoindMode.Dir = CleanAndExpandPath(cfg.BitcoindMode.Dir)
@@ -1065,6 +1138,13 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			"if the watchtower client is active")
 	}
 
+	// Towers can only be restricted to onion addresses if we're able to
+	// reach them over Tor.
+	if cfg.WtClient.DiscoveryTorOnly && !cfg.Tor.Active {
+		return nil, mkErr("wtclient.discovery-tor-only requires " +
+			"tor.active")
+	}
+
 	// Ensure a valid max channel fee allocation was set.
 	if cfg.MaxChannelFeeAllocation <= 0 || cfg.MaxChannelFeeAllocation > 1 {
 		return nil, mkErr("invalid max channel fee allocation: %v, "+
@@ -1365,6 +1445,11 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			cfg.networkDir, defaultInvoiceMacFilename,
 		)
 	}
+	if cfg.LockedRPC.TokenPath == "" {
+		cfg.LockedRPC.TokenPath = filepath.Join(
+			cfg.networkDir, defaultLockedRPCTokenName,
+		)
+	}
 
 	towerDir := filepath.Join(
 		cfg.Watchtower.TowerDir, BitcoinChainName,
@@ -1662,6 +1747,16 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 			maxChannelCommitInterval)
 	}
 
+	// Since pending updates are signed at the latest once the commit
+	// interval passes, a larger settle batch window has no effect.
+	if cfg.ChannelSettleBatchWindow < 0 ||
+		cfg.ChannelSettleBatchWindow > cfg.ChannelCommitInterval {
+
+		return nil, mkErr("channel-settle-batch-window (%v) must be "+
+			"between 0 and channel-commit-interval (%v)",
+			cfg.ChannelSettleBatchWindow, cfg.ChannelCommitInterval)
+	}
+
 	// Limit PendingCommitInterval so we don't wait too long for the remote
 	// party to send back a revoke.
 	if cfg.PendingCommitInterval > maxPendingCommitInterval {
@@ -1726,9 +1821,15 @@ func ValidateConfig(cfg Config, interceptor signal.Interceptor, fileParser,
 		cfg.Cluster,
 		cfg.HealthChecks,
 		cfg.RPCMiddleware,
+		cfg.RPCRateLimit,
+		cfg.LockedRPC,
 		cfg.RemoteSigner,
 		cfg.Sweeper,
 		cfg.Htlcswitch,
+		cfg.ForceClose,
+		cfg.AcceptPolicy,
+		cfg.ZeroConf,
+		cfg.InboundPolicy,
 		cfg.Invoices,
 		cfg.Routing,
 		cfg.Pprof,
@@ -154,6 +154,12 @@ type ImplementationCfg struct {
 	// AuxComponents is a set of auxiliary components that can be used by
 	// lnd for certain custom channel types.
 	AuxComponents
+
+	// CaveatValidators is a set of in-process validators for custom
+	// macaroon caveats, keyed by the custom caveat name they enforce.
+	// Macaroons carrying one of these caveats are accepted without an RPC
+	// middleware being registered for it.
+	CaveatValidators map[string]rpcperms.CaveatValidator
 }
 
 // AuxComponents is a set of auxiliary components that can be used by lnd for
@@ -196,6 +202,10 @@ type AuxComponents struct {
 	// AuxContractResolver is an optional interface that can be used to
 	// modify the way contracts are resolved.
 	AuxContractResolver fn.Option[lnwallet.AuxContractResolver]
+
+	// FiatRateProvider is an optional provider that is used to quote the
+	// fiat price of invoices that are created with a fiat currency.
+	FiatRateProvider fn.Option[invoices.FiatRateProvider]
 }
 
 // DefaultWalletImpl is the default implementation of our normal, btcwallet
@@ -1132,7 +1142,7 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 
 	// Wrap the watchtower client DB and make sure we clean up.
 	if cfg.WtClient.Active {
-		dbs.TowerClientDB, err = wtdb.OpenClientDB(
+		kvTowerClientDB, err := wtdb.OpenClientDB(
 			databaseBackends.TowerClientDB,
 		)
 		if err != nil {
@@ -1143,11 +1153,39 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 			d.logger.Error(err)
 			return nil, nil, err
 		}
+
+		dbs.TowerClientDB = kvTowerClientDB
+
+		// With native SQL enabled, the client's sessions are moved to
+		// the SQL store. The kv database is left untouched so that it
+		// can still be used if native SQL is disabled again.
+		if d.cfg.DB.UseNativeSQL {
+			executor := sqldb.NewTransactionExecutor(
+				dbs.NativeSQLStore,
+				func(tx *sql.Tx) wtdb.SQLClientQueries {
+					return dbs.NativeSQLStore.WithTx(tx)
+				},
+			)
+
+			sqlTowerClientDB := wtdb.NewSQLClientDB(executor)
+			err = sqlTowerClientDB.MigrateFromKV(kvTowerClientDB)
+			if err != nil {
+				cleanUp()
+
+				err := fmt.Errorf("unable to migrate %s "+
+					"database to native SQL: %w",
+					lncfg.NSTowerClientDB, err)
+				d.logger.Error(err)
+				return nil, nil, err
+			}
+
+			dbs.TowerClientDB = sqlTowerClientDB
+		}
 	}
 
 	// Wrap the watchtower server DB and make sure we clean up.
 	if cfg.Watchtower.Active {
-		dbs.TowerServerDB, err = wtdb.OpenTowerDB(
+		kvTowerServerDB, err := wtdb.OpenTowerDB(
 			databaseBackends.TowerServerDB,
 		)
 		if err != nil {
@@ -1158,6 +1196,39 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 			d.logger.Error(err)
 			return nil, nil, err
 		}
+
+		dbs.TowerServerDB = kvTowerServerDB
+
+		// With shards configured, the tower's sessions are spread over
+		// the shards. The tower database of the node is left untouched
+		// after it was migrated into them.
+		if len(cfg.Watchtower.ShardDSNs) > 0 {
+			shardedTowerDB, err := openShardedTowerDB(
+				ctx, cfg, databaseBackends.CloseFuncs,
+			)
+			if err != nil {
+				cleanUp()
+
+				err := fmt.Errorf("unable to open %s "+
+					"shards: %w", lncfg.NSTowerServerDB,
+					err)
+				d.logger.Error(err)
+				return nil, nil, err
+			}
+
+			err = shardedTowerDB.MigrateFromTowerDB(kvTowerServerDB)
+			if err != nil {
+				cleanUp()
+
+				err := fmt.Errorf("unable to migrate %s "+
+					"database to shards: %w",
+					lncfg.NSTowerServerDB, err)
+				d.logger.Error(err)
+				return nil, nil, err
+			}
+
+			dbs.TowerServerDB = shardedTowerDB
+		}
 	}
 
 	openTime := time.Since(startOpenTime)
@@ -1166,6 +1237,37 @@ func (d *DefaultDatabaseBuilder) BuildDatabase(
 	return dbs, cleanUp, nil
 }
 
+// openShardedTowerDB opens the configured shards of the watchtower database.
+// The close functions of the shards are added to the given map, so that they
+// are closed together with the other databases.
+func openShardedTowerDB(ctx context.Context, cfg *Config,
+	closeFuncs map[string]func() error) (*wtdb.ShardedTowerDB, error) {
+
+	backends, shardCloseFuncs, err := cfg.Watchtower.GetShardBackends(
+		ctx, cfg.DB.Postgres,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, closeFunc := range shardCloseFuncs {
+		closeFuncs[name] = closeFunc
+	}
+
+	shards := make([]*wtdb.TowerDB, 0, len(backends))
+	for i, backend := range backends {
+		shard, err := wtdb.OpenTowerDB(backend)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open shard %d: %w", i,
+				err)
+		}
+
+		shards = append(shards, shard)
+	}
+
+	return wtdb.NewShardedTowerDB(shards...)
+}
+
 // waitForWalletPassword blocks until a password is provided by the user to
 // this RPC server.
 func waitForWalletPassword(cfg *Config,
@@ -0,0 +1,174 @@
+package contractcourt
+
+import (
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+// anchorTemplateSequence is the sequence of the anchor input of the spend
+// template, which signals replaceability so the service can bump its child.
+const anchorTemplateSequence = wire.MaxTxInSequenceNum - 2
+
+// AnchorBumpRequest asks an external service to fee bump one of our broadcast
+// commitment txns by spending its anchor output in a CPFP child.
+type AnchorBumpRequest struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// CommitTxid is the hash of the commitment tx that's fee bumped.
+	CommitTxid chainhash.Hash
+
+	// CommitTx is the fully signed commitment tx. It's only known for our
+	// own commitment, and allows it to be broadcast in a package together
+	// with the child.
+	CommitTx *wire.MsgTx
+
+	// CommitFee is the fee paid by the commitment tx.
+	CommitFee btcutil.Amount
+
+	// CommitWeight is the weight of the commitment tx.
+	CommitWeight lntypes.WeightUnit
+
+	// AnchorOutPoint is our anchor output on the commitment tx.
+	AnchorOutPoint wire.OutPoint
+
+	// AnchorOutput is the output that's spent by the anchor input.
+	AnchorOutput *wire.TxOut
+
+	// SpendTemplate is a tx with a single input that spends the anchor
+	// output. Our signature of the input only commits to the input itself
+	// (SIGHASH_NONE|SIGHASH_ANYONECANPAY), so the service can add its own
+	// inputs and outputs to fund the child, as long as it keeps the
+	// version and lock time of the template. Anyone who sees the template
+	// may spend the anchor output, which is of no concern given its value.
+	SpendTemplate *wire.MsgTx
+
+	// DeadlineHeight is the height by which the commitment tx should be
+	// confirmed, if any HTLCs are at stake.
+	DeadlineHeight fn.Option[int32]
+
+	// Budget is the maximum amount we'd pay to get the commitment tx
+	// confirmed, as the sweeper would have.
+	Budget btcutil.Amount
+}
+
+// AnchorBumpDelegate is an external service that fee bumps our commitment
+// txns, in place of the sweeper. This allows nodes whose wallets are
+// intentionally unfunded to get their commitments confirmed in time.
+type AnchorBumpDelegate interface {
+	// DelegateAnchorBump hands the fee bump of a commitment tx to the
+	// service.
+	DelegateAnchorBump(req *AnchorBumpRequest) error
+}
+
+// A compile time assertion to ensure ChainArbitrator meets the
+// AnchorBumpDelegate interface.
+var _ AnchorBumpDelegate = (*ChainArbitrator)(nil)
+
+// newAnchorBumpRequest creates the request to fee bump the commitment of the
+// given anchor, and signs the anchor input of its spend template.
+func newAnchorBumpRequest(signer input.Signer, chanPoint wire.OutPoint,
+	anchor *lnwallet.AnchorResolution, witnessType input.StandardWitnessType,
+	deadlineHeight fn.Option[int32],
+	budget btcutil.Amount) (*AnchorBumpRequest, error) {
+
+	anchorOutput := anchor.AnchorSignDescriptor.Output
+
+	template := wire.NewMsgTx(2)
+	template.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: anchor.CommitAnchor,
+		Sequence:         anchorTemplateSequence,
+	})
+
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		anchorOutput.PkScript, anchorOutput.Value,
+	)
+
+	signDesc := anchor.AnchorSignDescriptor
+	signDesc.HashType = txscript.SigHashNone | txscript.SigHashAnyOneCanPay
+	signDesc.PrevOutputFetcher = prevOutFetcher
+
+	witness, err := witnessType.WitnessGenerator(signer, &signDesc)(
+		template, txscript.NewTxSigHashes(template, prevOutFetcher), 0,
+	)
+	if err != nil {
+		return nil, err
+	}
+	template.TxIn[0].Witness = witness.Witness
+
+	return &AnchorBumpRequest{
+		ChanPoint:      chanPoint,
+		CommitTxid:     anchor.CommitAnchor.Hash,
+		CommitTx:       anchor.CommitTx,
+		CommitFee:      anchor.CommitFee,
+		CommitWeight:   anchor.CommitWeight,
+		AnchorOutPoint: anchor.CommitAnchor,
+		AnchorOutput:   anchorOutput,
+		SpendTemplate:  template,
+		DeadlineHeight: deadlineHeight,
+		Budget:         budget,
+	}, nil
+}
+
+// DelegateAnchorBump hands the given anchor bump to the subscribers of the
+// anchor bumps. The request is kept until the closing tx of its channel
+// confirmed, so subscribers that connect later can still pick it up.
+//
+// NOTE: Part of the AnchorBumpDelegate interface.
+func (c *ChainArbitrator) DelegateAnchorBump(req *AnchorBumpRequest) error {
+	c.anchorBumpsMtx.Lock()
+	c.pendingAnchorBumps[req.AnchorOutPoint] = req
+	c.anchorBumpsMtx.Unlock()
+
+	log.Infof("Delegating fee bump of commitment %v of ChannelPoint(%v) "+
+		"with budget=%v", req.CommitTxid, req.ChanPoint, req.Budget)
+
+	return c.anchorBumpNtfns.SendUpdate(req)
+}
+
+// SubscribeAnchorBumps returns a subscription that receives an
+// AnchorBumpRequest for every commitment tx whose fee bump is delegated.
+func (c *ChainArbitrator) SubscribeAnchorBumps() (*subscribe.Client, error) {
+	return c.anchorBumpNtfns.Subscribe()
+}
+
+// PendingAnchorBumps returns the delegated anchor bumps of the commitment txns
+// that haven't confirmed yet.
+func (c *ChainArbitrator) PendingAnchorBumps() []*AnchorBumpRequest {
+	c.anchorBumpsMtx.Lock()
+	defer c.anchorBumpsMtx.Unlock()
+
+	reqs := make([]*AnchorBumpRequest, 0, len(c.pendingAnchorBumps))
+	for _, req := range c.pendingAnchorBumps {
+		reqs = append(reqs, req)
+	}
+
+	sort.Slice(reqs, func(i, j int) bool {
+		return reqs[i].AnchorOutPoint.String() <
+			reqs[j].AnchorOutPoint.String()
+	})
+
+	return reqs
+}
+
+// clearAnchorBumps removes the delegated anchor bumps of the given channel
+// once its closing tx confirmed.
+func (c *ChainArbitrator) clearAnchorBumps(chanPoint wire.OutPoint) {
+	c.anchorBumpsMtx.Lock()
+	defer c.anchorBumpsMtx.Unlock()
+
+	for op, req := range c.pendingAnchorBumps {
+		if req.ChanPoint == chanPoint {
+			delete(c.pendingAnchorBumps, op)
+		}
+	}
+}
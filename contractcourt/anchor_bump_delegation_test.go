@@ -0,0 +1,96 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnchorBumpDelegation asserts that the spend template of a delegated
+// anchor bump stays valid once the service adds its own inputs and outputs,
+// and that the chain arbitrator hands the delegated bumps to its subscribers
+// until the closing tx of their channel confirmed.
+func TestAnchorBumpDelegation(t *testing.T) {
+	t.Parallel()
+
+	chanType := channeldb.SingleFunderTweaklessBit |
+		channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit
+
+	alice, _, err := lnwallet.CreateTestChannels(t, chanType)
+	require.NoError(t, err, "unable to create test channels")
+
+	aliceClose, err := alice.ForceClose()
+	require.NoError(t, err, "unable to force close alice's channel")
+
+	anchor := aliceClose.AnchorResolution
+	require.NotNil(t, anchor)
+
+	chanPoint := alice.State().FundingOutpoint
+	req, err := newAnchorBumpRequest(
+		alice.Signer, chanPoint, anchor, input.CommitmentAnchor,
+		fn.Some(int32(100)), 10_000,
+	)
+	require.NoError(t, err)
+	require.Equal(t, aliceClose.CloseTx.TxHash(), req.CommitTxid)
+	require.Equal(t, anchor.CommitAnchor, req.AnchorOutPoint)
+
+	// The service funds the child with its own input and output.
+	child := req.SpendTemplate.Copy()
+	fundingOp := wire.OutPoint{Index: 7}
+	child.AddTxIn(&wire.TxIn{PreviousOutPoint: fundingOp})
+	child.AddTxOut(&wire.TxOut{
+		PkScript: []byte{txscript.OP_TRUE},
+		Value:    50_000,
+	})
+
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	prevOuts.AddPrevOut(req.AnchorOutPoint, req.AnchorOutput)
+	prevOuts.AddPrevOut(fundingOp, &wire.TxOut{
+		PkScript: []byte{txscript.OP_TRUE},
+		Value:    60_000,
+	})
+
+	vm, err := txscript.NewEngine(
+		req.AnchorOutput.PkScript, child, 0,
+		txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(child, prevOuts),
+		req.AnchorOutput.Value, prevOuts,
+	)
+	require.NoError(t, err)
+	require.NoError(t, vm.Execute(), "invalid anchor spend")
+
+	// The delegated bump is sent to the subscribers, and kept for the
+	// ones that connect later.
+	chainArb := NewChainArbitrator(ChainArbitratorConfig{
+		DelegateAnchorBumps: true,
+	}, alice.State().Db.GetParentDB())
+	require.Equal(t, chainArb, chainArb.cfg.AnchorBumpDelegate)
+
+	require.NoError(t, chainArb.anchorBumpNtfns.Start())
+	t.Cleanup(func() {
+		require.NoError(t, chainArb.anchorBumpNtfns.Stop())
+	})
+
+	client, err := chainArb.SubscribeAnchorBumps()
+	require.NoError(t, err)
+	defer client.Cancel()
+
+	require.NoError(t, chainArb.DelegateAnchorBump(req))
+	require.Equal(t, req, receiveResolutionEvent(t, client))
+	require.Equal(
+		t, []*AnchorBumpRequest{req}, chainArb.PendingAnchorBumps(),
+	)
+
+	// Once the closing tx confirmed, the bump is no longer pending.
+	chainArb.clearAnchorBumps(wire.OutPoint{Index: 1})
+	require.Len(t, chainArb.PendingAnchorBumps(), 1)
+
+	chainArb.clearAnchorBumps(chanPoint)
+	require.Empty(t, chainArb.PendingAnchorBumps())
+}
@@ -179,6 +179,10 @@ type BreachConfig struct {
 	// AuxSweeper is an optional interface that can be used to modify the
 	// way sweep transaction are generated.
 	AuxSweeper fn.Option[sweep.AuxSweeper]
+
+	// Budget is the configured budget used to cap the fees paid by the
+	// justice transactions.
+	Budget *BudgetConfig
 }
 
 // BreachArbitrator is a special subsystem which is responsible for watching and
@@ -702,13 +706,15 @@ func (b *BreachArbitrator) exactRetribution(
 	defer b.wg.Done()
 
 	// TODO(roasbeef): state needs to be checkpointed here
+	var currentHeight int32
 	select {
-	case _, ok := <-confChan.Confirmed:
+	case conf, ok := <-confChan.Confirmed:
 		// If the second value is !ok, then the channel has been closed
 		// signifying a daemon shutdown, so we exit.
 		if !ok {
 			return
 		}
+		currentHeight = int32(conf.BlockHeight)
 
 		// Otherwise, if this is a real confirmation notification, then
 		// we fall through to complete our duty.
@@ -732,40 +738,21 @@ justiceTxBroadcast:
 	// With the breach transaction confirmed, we now create the
 	// justice tx which will claim ALL the funds within the
 	// channel.
-	justiceTxs, err := b.createJusticeTx(breachInfo.breachedOutputs)
+	blocksLeft := b.blocksLeft(breachInfo, currentHeight)
+	justiceTxs, err := b.createJusticeTx(
+		breachInfo.breachedOutputs, blocksLeft,
+	)
 	if err != nil {
 		brarLog.Errorf("Unable to create justice tx: %v", err)
 		return
 	}
-	finalTx := justiceTxs.spendAll
-
-	brarLog.Debugf("Broadcasting justice tx: %v", lnutils.SpewLogClosure(
-		finalTx))
-
-	// As we're about to broadcast our breach transaction, we'll notify the
-	// aux sweeper of our broadcast attempt first.
-	err = fn.MapOptionZ(b.cfg.AuxSweeper, func(aux sweep.AuxSweeper) error {
-		bumpReq := sweep.BumpRequest{
-			Inputs:          finalTx.inputs,
-			DeliveryAddress: finalTx.sweepAddr,
-			ExtraTxOut:      finalTx.extraTxOut,
-		}
-
-		return aux.NotifyBroadcast(
-			&bumpReq, finalTx.justiceTx, finalTx.fee,
-		)
-	})
-	if err != nil {
-		brarLog.Errorf("unable to notify broadcast: %w", err)
-		return
-	}
 
 	// We'll now attempt to broadcast the transaction which finalized the
 	// channel's retribution against the cheating counter party.
-	label := labels.MakeLabel(labels.LabelTypeJusticeTransaction, nil)
-	err = b.cfg.PublishTransaction(finalTx.justiceTx, label)
+	err = b.publishJusticeTx(justiceTxs.spendAll)
 	if err != nil {
-		brarLog.Errorf("Unable to broadcast justice tx: %v", err)
+		brarLog.Errorf("Unable to publish justice tx: %v", err)
+		return
 	}
 
 	// Regardless of publication succeeded or not, we now wait for any of
@@ -850,6 +837,15 @@ Loop:
 			if !ok {
 				return
 			}
+			currentHeight = epoch.Height
+
+			// If the deadline of our justice txs is close, we'll
+			// recreate them using the escalated budget, and replace
+			// the previous ones.
+			blocksLeft = b.blocksLeft(breachInfo, currentHeight)
+			justiceTxs = b.escalateJusticeTx(
+				breachInfo, justiceTxs, blocksLeft,
+			)
 
 			// If less than four blocks have passed since the
 			// breach confirmed, we'll continue waiting. It was
@@ -943,6 +939,98 @@ Loop:
 	wg.Wait()
 }
 
+// blocksLeft returns the number of blocks left at the given height until the
+// deadline of the justice txs of the given breach. None is returned if the
+// deadline is unknown or the budget escalation is disabled, in which case the
+// fees of the justice txs aren't escalated.
+func (b *BreachArbitrator) blocksLeft(breachInfo *retributionInfo,
+	height int32) fn.Option[int32] {
+
+	if b.cfg.Budget.EscalationRatio == 0 {
+		return fn.None[int32]()
+	}
+
+	return breachInfo.blocksLeft(height)
+}
+
+// publishJusticeTx notifies the aux sweeper about the given justice tx and
+// broadcasts it. Only an error from the aux sweeper is returned, as a failure
+// to broadcast is retried with the next attempt.
+func (b *BreachArbitrator) publishJusticeTx(finalTx *justiceTxCtx) error {
+	brarLog.Debugf("Broadcasting justice tx: %v", lnutils.SpewLogClosure(
+		finalTx))
+
+	// As we're about to broadcast our breach transaction, we'll notify the
+	// aux sweeper of our broadcast attempt first.
+	notify := func(aux sweep.AuxSweeper) error {
+		bumpReq := sweep.BumpRequest{
+			Inputs:          finalTx.inputs,
+			DeliveryAddress: finalTx.sweepAddr,
+			ExtraTxOut:      finalTx.extraTxOut,
+		}
+
+		return aux.NotifyBroadcast(
+			&bumpReq, finalTx.justiceTx, finalTx.fee,
+		)
+	}
+	err := fn.MapOptionZ(b.cfg.AuxSweeper, notify)
+	if err != nil {
+		return fmt.Errorf("unable to notify broadcast: %w", err)
+	}
+
+	label := labels.MakeLabel(labels.LabelTypeJusticeTransaction, nil)
+	err = b.cfg.PublishTransaction(finalTx.justiceTx, label)
+	if err != nil {
+		brarLog.Errorf("Unable to broadcast justice tx: %v", err)
+	}
+
+	return nil
+}
+
+// escalateJusticeTx recreates the justice txs using the budget escalated for
+// the given number of blocks left until the deadline, and publishes the one
+// spending all outputs to replace the previous one. If the deadline isn't close
+// yet or the justice txs can't be recreated, the previous ones are returned.
+func (b *BreachArbitrator) escalateJusticeTx(breachInfo *retributionInfo,
+	prevTxs *justiceTxVariants,
+	blocksLeft fn.Option[int32]) *justiceTxVariants {
+
+	// Exit early if the deadline is unknown or still far away.
+	escalate := fn.MapOptionZ(blocksLeft, func(left int32) bool {
+		return left < sweep.DefaultBudgetEscalationDelta
+	})
+	if !escalate {
+		return prevTxs
+	}
+
+	justiceTxs, err := b.createJusticeTx(
+		breachInfo.breachedOutputs, blocksLeft,
+	)
+	if err != nil {
+		brarLog.Errorf("Unable to create escalated justice tx: %v", err)
+		return prevTxs
+	}
+
+	// Exit early if the fee didn't change, as there's nothing to replace.
+	if justiceTxs.spendAll.fee <= prevTxs.spendAll.fee {
+		return prevTxs
+	}
+
+	brarLog.Infof("Escalating fee of justice tx for ChannelPoint(%v) "+
+		"from %v to %v, blocks_left=%v", breachInfo.chanPoint,
+		prevTxs.spendAll.fee, justiceTxs.spendAll.fee,
+		blocksLeft.UnwrapOr(0))
+
+	if err := b.publishJusticeTx(justiceTxs.spendAll); err != nil {
+		brarLog.Errorf("Unable to publish escalated justice tx: %v",
+			err)
+
+		return prevTxs
+	}
+
+	return justiceTxs
+}
+
 // cleanupBreach marks the given channel point as fully resolved and removes the
 // retribution for that the channel from the retribution store.
 func (b *BreachArbitrator) cleanupBreach(chanPoint *wire.OutPoint) error {
@@ -1231,6 +1319,25 @@ type retributionInfo struct {
 	breachHeight uint32
 
 	breachedOutputs []breachedOutput
+
+	// csvDelay is the CSV delay of the revoked to_local output of the
+	// breaching party. Once it expires, the breaching party can sweep the
+	// output itself, so it determines the deadline of the justice txs. It
+	// is zero for retributions that were stored without it.
+	csvDelay uint32
+}
+
+// blocksLeft returns the number of blocks left at the given height until the
+// deadline of the justice txs, which is derived from the CSV delay of the
+// revoked to_local output. None is returned if the CSV delay is unknown.
+func (ret *retributionInfo) blocksLeft(height int32) fn.Option[int32] {
+	if ret.csvDelay == 0 {
+		return fn.None[int32]()
+	}
+
+	deadline := int32(ret.breachHeight + ret.csvDelay)
+
+	return fn.Some(deadline - height)
 }
 
 // newRetributionInfo constructs a retributionInfo containing all the
@@ -1251,16 +1358,27 @@ func newRetributionInfo(chanPoint *wire.OutPoint,
 	// the nil-ness of their sign descriptors.
 	breachedOutputs := make([]breachedOutput, 0, nHtlcs+2)
 
+	// Both commitment outputs may be dust, in which case we determine the
+	// channel type from the breached HTLC outputs.
 	isTaproot := func() bool {
-		if breachInfo.LocalOutputSignDesc != nil {
-			return txscript.IsPayToTaproot(
-				breachInfo.LocalOutputSignDesc.Output.PkScript,
-			)
+		signDescs := []*input.SignDescriptor{
+			breachInfo.LocalOutputSignDesc,
+			breachInfo.RemoteOutputSignDesc,
+		}
+		for i := range breachInfo.HtlcRetributions {
+			htlc := &breachInfo.HtlcRetributions[i]
+			signDescs = append(signDescs, &htlc.SignDesc)
 		}
 
-		return txscript.IsPayToTaproot(
-			breachInfo.RemoteOutputSignDesc.Output.PkScript,
-		)
+		for _, signDesc := range signDescs {
+			if signDesc == nil {
+				continue
+			}
+
+			return txscript.IsPayToTaproot(signDesc.Output.PkScript)
+		}
+
+		return false
 	}()
 
 	// First, record the breach information for the local channel point if
@@ -1378,6 +1496,7 @@ func newRetributionInfo(chanPoint *wire.OutPoint,
 		chanPoint:       *chanPoint,
 		breachedOutputs: breachedOutputs,
 		breachHeight:    breachInfo.BreachHeight,
+		csvDelay:        breachInfo.RemoteDelay,
 	}
 }
 
@@ -1411,8 +1530,8 @@ type justiceTxVariants struct {
 // the funds within the channel which we are now entitled to due to a breach of
 // the channel's contract by the counterparty. This function returns a *fully*
 // signed transaction with the witness for each input fully in place.
-func (b *BreachArbitrator) createJusticeTx(
-	breachedOutputs []breachedOutput) (*justiceTxVariants, error) {
+func (b *BreachArbitrator) createJusticeTx(breachedOutputs []breachedOutput,
+	blocksLeft fn.Option[int32]) (*justiceTxVariants, error) {
 
 	var (
 		allInputs         []input.Input
@@ -1451,18 +1570,20 @@ func (b *BreachArbitrator) createJusticeTx(
 	)
 
 	// For each group of inputs, create a tx that spends them.
-	txs.spendAll, err = b.createSweepTx(allInputs...)
+	txs.spendAll, err = b.createSweepTx(blocksLeft, allInputs...)
 	if err != nil {
 		return nil, err
 	}
 
-	txs.spendCommitOuts, err = b.createSweepTx(commitInputs...)
+	txs.spendCommitOuts, err = b.createSweepTx(
+		blocksLeft, commitInputs...,
+	)
 	if err != nil {
 		brarLog.Errorf("could not create sweep tx for commitment "+
 			"outputs: %v", err)
 	}
 
-	txs.spendHTLCs, err = b.createSweepTx(htlcInputs...)
+	txs.spendHTLCs, err = b.createSweepTx(blocksLeft, htlcInputs...)
 	if err != nil {
 		brarLog.Errorf("could not create sweep tx for HTLC outputs: %v",
 			err)
@@ -1472,7 +1593,7 @@ func (b *BreachArbitrator) createJusticeTx(
 
 	secondLevelSweeps := make([]*justiceTxCtx, 0, len(secondLevelInputs))
 	for _, input := range secondLevelInputs {
-		sweepTx, err := b.createSweepTx(input)
+		sweepTx, err := b.createSweepTx(blocksLeft, input)
 		if err != nil {
 			brarLog.Errorf("could not create sweep tx for "+
 				"second-level HTLC output: %v", err)
@@ -1502,7 +1623,8 @@ type justiceTxCtx struct {
 }
 
 // createSweepTx creates a tx that sweeps the passed inputs back to our wallet.
-func (b *BreachArbitrator) createSweepTx(
+// The blocks left until the deadline of the tx are used to escalate its fee.
+func (b *BreachArbitrator) createSweepTx(blocksLeft fn.Option[int32],
 	inputs ...input.Input) (*justiceTxCtx, error) {
 
 	if len(inputs) == 0 {
@@ -1565,13 +1687,59 @@ func (b *BreachArbitrator) createSweepTx(
 
 	txWeight := weightEstimate.Weight()
 
-	return b.sweepSpendableOutputsTxn(txWeight, spendableOutputs...)
+	return b.sweepSpendableOutputsTxn(
+		txWeight, blocksLeft, spendableOutputs...,
+	)
+}
+
+// justiceTxFee returns the fee to pay for a justice tx of the given weight that
+// sweeps the given amount. The fee is capped by the configured budget. Once the
+// deadline of the tx is close, the budget is escalated, and the fee is raised
+// towards it so that the whole budget is used when the deadline is reached.
+func (b *BreachArbitrator) justiceTxFee(txWeight lntypes.WeightUnit,
+	totalAmt btcutil.Amount,
+	blocksLeft fn.Option[int32]) (btcutil.Amount, error) {
+
+	// We'll actually attempt to target inclusion within the next two
+	// blocks as we'd like to sweep these funds back into our wallet ASAP.
+	feePerKw, err := b.cfg.Estimator.EstimateFeePerKW(justiceTxConfTarget)
+	if err != nil {
+		return 0, err
+	}
+	txFee := feePerKw.FeeForWeight(txWeight)
+
+	budget := calculateBudget(
+		totalAmt, b.cfg.Budget.BreachRatio, b.cfg.Budget.Breach,
+	)
+
+	blocksLeft.WhenSome(func(left int32) {
+		budget = sweep.EscalateBudget(
+			budget, b.cfg.Budget.maxBudget(totalAmt), left,
+		)
+
+		// Raise the fee linearly over the escalation window, so the
+		// whole budget is used at the deadline.
+		minFee := sweep.EscalateBudget(0, budget, left)
+		if txFee < minFee {
+			txFee = minFee
+		}
+	})
+
+	if txFee > budget {
+		brarLog.Debugf("Justice tx fee %v is capped at budget %v",
+			txFee, budget)
+
+		txFee = budget
+	}
+
+	return txFee, nil
 }
 
 // sweepSpendableOutputsTxn creates a signed transaction from a sequence of
 // spendable outputs by sweeping the funds into a single p2wkh output.
 func (b *BreachArbitrator) sweepSpendableOutputsTxn(txWeight lntypes.WeightUnit,
-	inputs ...input.Input) (*justiceTxCtx, error) {
+	blocksLeft fn.Option[int32], inputs ...input.Input) (*justiceTxCtx,
+	error) {
 
 	// First, we obtain a new public key script from the wallet which we'll
 	// sweep the funds to.
@@ -1588,13 +1756,10 @@ func (b *BreachArbitrator) sweepSpendableOutputsTxn(txWeight lntypes.WeightUnit,
 		totalAmt += btcutil.Amount(inp.SignDesc().Output.Value)
 	}
 
-	// We'll actually attempt to target inclusion within the next two
-	// blocks as we'd like to sweep these funds back into our wallet ASAP.
-	feePerKw, err := b.cfg.Estimator.EstimateFeePerKW(justiceTxConfTarget)
+	txFee, err := b.justiceTxFee(txWeight, totalAmt, blocksLeft)
 	if err != nil {
 		return nil, err
 	}
-	txFee := feePerKw.FeeForWeight(txWeight)
 
 	// At this point, we'll check to see if we have any extra outputs to
 	// add from the aux sweeper.
@@ -2036,6 +2201,11 @@ func (ret *retributionInfo) Encode(w io.Writer) error {
 		}
 	}
 
+	binary.BigEndian.PutUint32(scratch[:], ret.csvDelay)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -2083,6 +2253,18 @@ func (ret *retributionInfo) Decode(r io.Reader) error {
 		}
 	}
 
+	// The CSV delay was added later on, so retributions stored before
+	// don't have it.
+	_, err = io.ReadFull(r, scratch[:4])
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil
+
+	case err != nil:
+		return err
+	}
+	ret.csvDelay = binary.BigEndian.Uint32(scratch[:4])
+
 	return nil
 }
 
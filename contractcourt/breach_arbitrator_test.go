@@ -28,6 +28,7 @@ import (
 	"github.com/lightningnetwork/lnd/lntest/channels"
 	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -289,6 +290,7 @@ var (
 			breachHeight: 337,
 			// Set to breachedOutputs 0 and 1 in init()
 			breachedOutputs: []breachedOutput{{}, {}},
+			csvDelay:        144,
 		},
 		{
 			commitHash: [chainhash.HashSize]byte{
@@ -495,6 +497,95 @@ func TestRetributionSerialization(t *testing.T) {
 	}
 }
 
+// TestRetributionLegacyDeserialization asserts that retributions stored
+// without a CSV delay can still be decoded.
+func TestRetributionLegacyDeserialization(t *testing.T) {
+	t.Parallel()
+
+	ret := retributions[0]
+
+	var buf bytes.Buffer
+	require.NoError(t, ret.Encode(&buf))
+
+	// Strip the trailing CSV delay to get the legacy encoding.
+	legacy := buf.Bytes()[:buf.Len()-4]
+
+	desRet := &retributionInfo{}
+	require.NoError(t, desRet.Decode(bytes.NewReader(legacy)))
+
+	ret.csvDelay = 0
+	require.Equal(t, &ret, desRet)
+	require.True(t, desRet.blocksLeft(int32(ret.breachHeight)).IsNone())
+}
+
+// TestJusticeTxFee asserts that the fee of a justice tx is capped by the
+// configured budget, and is escalated as the deadline approaches.
+func TestJusticeTxFee(t *testing.T) {
+	t.Parallel()
+
+	brar := &BreachArbitrator{
+		cfg: &BreachConfig{
+			Estimator: chainfee.NewStaticEstimator(12_500, 0),
+			Budget: &BudgetConfig{
+				BreachRatio:     0.5,
+				EscalationRatio: 0.9,
+			},
+		},
+	}
+
+	const weight = lntypes.WeightUnit(1_000)
+
+	testCases := []struct {
+		name       string
+		amt        btcutil.Amount
+		blocksLeft fn.Option[int32]
+		expected   btcutil.Amount
+	}{
+		{
+			name:       "no deadline",
+			amt:        100_000,
+			blocksLeft: fn.None[int32](),
+			expected:   12_500,
+		},
+		{
+			name:       "capped by budget",
+			amt:        20_000,
+			blocksLeft: fn.None[int32](),
+			expected:   10_000,
+		},
+		{
+			name:       "deadline far away",
+			amt:        100_000,
+			blocksLeft: fn.Some(int32(100)),
+			expected:   12_500,
+		},
+		{
+			name:       "half way into escalation",
+			amt:        100_000,
+			blocksLeft: fn.Some(int32(6)),
+			expected:   35_000,
+		},
+		{
+			name:       "deadline reached",
+			amt:        100_000,
+			blocksLeft: fn.Some(int32(0)),
+			expected:   90_000,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			fee, err := brar.justiceTxFee(
+				weight, tc.amt, tc.blocksLeft,
+			)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fee)
+		})
+	}
+}
+
 // copyRetInfo creates a complete copy of the given retributionInfo.
 func copyRetInfo(retInfo *retributionInfo) *retributionInfo {
 	nOutputs := len(retInfo.breachedOutputs)
@@ -505,6 +596,7 @@ func copyRetInfo(retInfo *retributionInfo) *retributionInfo {
 		chanPoint:       retInfo.chanPoint,
 		breachHeight:    retInfo.breachHeight,
 		breachedOutputs: make([]breachedOutput, nOutputs),
+		csvDelay:        retInfo.csvDelay,
 	}
 
 	for i := range retInfo.breachedOutputs {
@@ -1224,7 +1316,9 @@ func TestBreachCreateJusticeTx(t *testing.T) {
 	}
 
 	// Create the justice transactions.
-	justiceTxs, err := brar.createJusticeTx(breachedOutputs)
+	justiceTxs, err := brar.createJusticeTx(
+		breachedOutputs, fn.None[int32](),
+	)
 	require.NoError(t, err)
 	require.NotNil(t, justiceTxs)
 
@@ -1246,6 +1340,215 @@ func TestBreachCreateJusticeTx(t *testing.T) {
 	require.Len(t, justiceTxs.spendSecondLevelHTLCs, 1)
 }
 
+// TestBreachTaprootJusticeTx asserts that the breach arbiter creates valid
+// justice transactions for a breached simple taproot channel, spending the
+// revoked outputs through their revocation paths, and that our anchor on the
+// breached commitment can be swept.
+func TestBreachTaprootJusticeTx(t *testing.T) {
+	chanType := channeldb.SingleFunderTweaklessBit |
+		channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit |
+		channeldb.SimpleTaprootFeatureBit
+
+	alice, bob, err := lnwallet.CreateTestChannels(t, chanType)
+	require.NoError(t, err, "unable to create test channels")
+
+	brar, err := createTestArbiter(
+		t, make(chan *ContractBreachEvent),
+		alice.State().Db.GetParentDB(),
+	)
+	require.NoError(t, err, "unable to initialize test breach arbiter")
+
+	// The justice txns are signed with the keys of Alice's channel.
+	brar.cfg.Signer = alice.Signer
+
+	// Add an HTLC in each direction, so the breached commitment has both
+	// an offered and an accepted HTLC output.
+	htlcAmount := lnwire.NewMSatFromSatoshis(20000)
+	aliceHtlc, _ := createHTLC(0, htlcAmount)
+	_, err = alice.AddHTLC(aliceHtlc, nil)
+	require.NoError(t, err)
+	_, err = bob.ReceiveHTLC(aliceHtlc)
+	require.NoError(t, err)
+
+	bobHtlc, _ := createHTLC(0, htlcAmount)
+	bobHtlc.PaymentHash = sha256.Sum256([]byte("bob"))
+	_, err = bob.AddHTLC(bobHtlc, nil)
+	require.NoError(t, err)
+	_, err = alice.ReceiveHTLC(bobHtlc)
+	require.NoError(t, err)
+
+	// Bob's HTLC is only locked into both commitments once Bob signed
+	// for it as well.
+	require.NoError(t, lnwallet.ForceStateTransition(alice, bob))
+	require.NoError(t, lnwallet.ForceStateTransition(bob, alice))
+
+	// This is the state Bob will broadcast after it has been revoked by
+	// the next state transition.
+	bobClose, err := bob.ForceClose()
+	require.NoError(t, err, "unable to force close bob's channel")
+
+	htlc2, _ := createHTLC(1, htlcAmount)
+	_, err = alice.AddHTLC(htlc2, nil)
+	require.NoError(t, err)
+	_, err = bob.ReceiveHTLC(htlc2)
+	require.NoError(t, err)
+	require.NoError(t, lnwallet.ForceStateTransition(alice, bob))
+
+	breachTx := bobClose.CloseTx
+	retribution, err := lnwallet.NewBreachRetribution(
+		alice.State(), bobClose.ChanSnapshot.CommitHeight, 1, breachTx,
+		fn.Some[lnwallet.AuxLeafStore](&lnwallet.MockAuxLeafStore{}),
+		fn.Some[lnwallet.AuxContractResolver](
+			&lnwallet.MockAuxContractResolver{},
+		),
+	)
+	require.NoError(t, err, "unable to create breach retribution")
+
+	chanPoint := alice.ChannelPoint()
+	retInfo := newRetributionInfo(&chanPoint, retribution)
+
+	// All the breached outputs must be spent using the taproot witness
+	// types.
+	witnessTypes := fn.NewSet[input.WitnessType]()
+	for _, bo := range retInfo.breachedOutputs {
+		witnessTypes.Add(bo.WitnessType())
+	}
+	require.Equal(t, fn.NewSet[input.WitnessType](
+		input.TaprootRemoteCommitSpend, input.TaprootCommitmentRevoke,
+		input.TaprootHtlcAcceptedRevoke, input.TaprootHtlcOfferedRevoke,
+	), witnessTypes)
+
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for i, txOut := range breachTx.TxOut {
+		prevOuts.AddPrevOut(wire.OutPoint{
+			Hash:  breachTx.TxHash(),
+			Index: uint32(i),
+		}, txOut)
+	}
+
+	// assertJusticeTxs asserts that the justice txns created from the
+	// given breached outputs are valid spends of the breached commitment.
+	assertJusticeTxs := func(breachedOutputs []breachedOutput) {
+		justiceTxs, err := brar.createJusticeTx(
+			breachedOutputs, fn.None[int32](),
+		)
+		require.NoError(t, err)
+
+		spendAll := justiceTxs.spendAll.justiceTx
+		require.Len(t, spendAll.TxIn, len(breachedOutputs))
+		assertValidSpend(t, spendAll, prevOuts)
+
+		assertValidSpend(
+			t, justiceTxs.spendCommitOuts.justiceTx, prevOuts,
+		)
+		assertValidSpend(t, justiceTxs.spendHTLCs.justiceTx, prevOuts)
+	}
+
+	assertJusticeTxs(retInfo.breachedOutputs)
+
+	// The justice txns must still be valid after the retribution info was
+	// persisted and loaded again, which requires the taproot specific
+	// info to be restored.
+	require.NoError(t, brar.cfg.Store.Add(retInfo))
+
+	var loaded *retributionInfo
+	err = brar.cfg.Store.ForAll(func(ret *retributionInfo) error {
+		loaded = ret
+		return nil
+	}, func() {
+		loaded = nil
+	})
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+
+	assertJusticeTxs(loaded.breachedOutputs)
+
+	// Finally, our anchor on the breached commitment is swept by the
+	// channel arbitrator, so make sure it can be spent as well.
+	anchorRes, err := lnwallet.NewAnchorResolution(
+		alice.State(), breachTx, retribution.KeyRing, lntypes.Remote,
+	)
+	require.NoError(t, err)
+	require.NotNil(t, anchorRes)
+
+	anchorInput := input.NewBaseInput(
+		&anchorRes.CommitAnchor, input.TaprootAnchorSweepSpend,
+		&anchorRes.AnchorSignDescriptor, 0,
+	)
+
+	anchorSweep := wire.NewMsgTx(2)
+	anchorSweep.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: anchorRes.CommitAnchor,
+	})
+	anchorSweep.AddTxOut(&wire.TxOut{
+		Value:    int64(lnwallet.AnchorSize) - 200,
+		PkScript: []byte{txscript.OP_TRUE},
+	})
+
+	script, err := anchorInput.CraftInputScript(
+		alice.Signer, anchorSweep,
+		txscript.NewTxSigHashes(anchorSweep, prevOuts), prevOuts, 0,
+	)
+	require.NoError(t, err)
+	anchorSweep.TxIn[0].Witness = script.Witness
+
+	assertValidSpend(t, anchorSweep, prevOuts)
+}
+
+// TestNewRetributionInfoDustCommitOutputs asserts that the retribution info
+// of a breached taproot commitment with only HTLC outputs uses the taproot
+// witness types.
+func TestNewRetributionInfoDustCommitOutputs(t *testing.T) {
+	t.Parallel()
+
+	internalKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pkScript, err := input.PayToTaprootScript(internalKey.PubKey())
+	require.NoError(t, err)
+
+	// Both commitment outputs are dust, so their sign descriptors are nil.
+	breachInfo := &lnwallet.BreachRetribution{
+		HtlcRetributions: []lnwallet.HtlcRetribution{{
+			SignDesc: input.SignDescriptor{
+				Output: &wire.TxOut{
+					Value:    1000,
+					PkScript: pkScript,
+				},
+			},
+			IsIncoming: true,
+		}},
+	}
+	retInfo := newRetributionInfo(&wire.OutPoint{}, breachInfo)
+
+	require.Len(t, retInfo.breachedOutputs, 1)
+	require.Equal(
+		t, input.TaprootHtlcAcceptedRevoke,
+		retInfo.breachedOutputs[0].WitnessType(),
+	)
+}
+
+// assertValidSpend asserts that the inputs of the given tx validly spend the
+// given previous outputs.
+func assertValidSpend(t *testing.T, tx *wire.MsgTx,
+	prevOuts *txscript.MultiPrevOutFetcher) {
+
+	t.Helper()
+
+	hashCache := txscript.NewTxSigHashes(tx, prevOuts)
+	for i, txIn := range tx.TxIn {
+		prevOut := prevOuts.FetchPrevOutput(txIn.PreviousOutPoint)
+		require.NotNil(t, prevOut)
+
+		vm, err := txscript.NewEngine(
+			prevOut.PkScript, tx, i, txscript.StandardVerifyFlags,
+			nil, hashCache, prevOut.Value, prevOuts,
+		)
+		require.NoError(t, err)
+		require.NoError(t, vm.Execute(), "invalid spend of input %d", i)
+	}
+}
+
 type publAssertion func(*testing.T, map[wire.OutPoint]struct{},
 	chan *wire.MsgTx, chainhash.Hash) *wire.MsgTx
 
@@ -2151,6 +2454,12 @@ func createTestArbiter(t *testing.T, contractBreaches chan *ContractBreachEvent,
 			return nil
 		},
 		Store: store,
+
+		// Disable the budget escalation, so the justice txs are only
+		// replaced when their inputs are spent.
+		Budget: &BudgetConfig{
+			BreachRatio: DefaultBudgetRatio,
+		},
 	})
 
 	if err := ba.Start(); err != nil {
@@ -648,7 +648,7 @@ func (b *boltArbitratorLog) FetchUnresolvedContracts() ([]ContractResolver, erro
 func (b *boltArbitratorLog) InsertUnresolvedContracts(reports []*channeldb.ResolverReport,
 	resolvers ...ContractResolver) error {
 
-	return kvdb.Batch(b.db, func(tx kvdb.RwTx) error {
+	err := kvdb.Batch(b.db, func(tx kvdb.RwTx) error {
 		contractBucket, err := fetchContractWriteBucket(tx, b.scopeKey[:])
 		if err != nil {
 			return err
@@ -671,6 +671,13 @@ func (b *boltArbitratorLog) InsertUnresolvedContracts(reports []*channeldb.Resol
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	b.notifyReports(reports)
+
+	return nil
 }
 
 // SwapContract performs an atomic swap of the old contract for the new
@@ -1166,7 +1173,7 @@ func (b *boltArbitratorLog) WipeHistory() error {
 func (b *boltArbitratorLog) checkpointContract(c ContractResolver,
 	reports ...*channeldb.ResolverReport) error {
 
-	return kvdb.Update(b.db, func(tx kvdb.RwTx) error {
+	err := kvdb.Update(b.db, func(tx kvdb.RwTx) error {
 		contractBucket, err := fetchContractWriteBucket(tx, b.scopeKey[:])
 		if err != nil {
 			return err
@@ -1184,6 +1191,27 @@ func (b *boltArbitratorLog) checkpointContract(c ContractResolver,
 
 		return nil
 	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	b.notifyReports(reports)
+
+	return nil
+}
+
+// notifyReports passes the given resolver reports, which have been committed
+// to the database, to the report notification function if set.
+func (b *boltArbitratorLog) notifyReports(
+	reports []*channeldb.ResolverReport) {
+
+	if b.cfg.NotifyResolverReport == nil {
+		return
+	}
+
+	for _, report := range reports {
+		b.cfg.NotifyResolverReport(report)
+	}
 }
 
 // encodeSignDetails encodes the given SignDetails struct to the writer.
@@ -22,6 +22,8 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/protofsm"
+	"github.com/lightningnetwork/lnd/subscribe"
 )
 
 // ErrChainArbExiting signals that the chain arbitrator is shutting down.
@@ -182,6 +184,41 @@ type ChainArbitratorConfig struct {
 	// has timed out.
 	PaymentsExpirationGracePeriod time.Duration
 
+	// ForceClosePolicy configures which expiring HTLCs make us force
+	// close their channel.
+	ForceClosePolicy ForceClosePolicy
+
+	// CoopHtlcSweep enables the cooperative sweep of expired HTLCs. When
+	// set, we ask the peer to co-sign a direct sweep of the expired HTLC
+	// outputs of our confirmed commitment, and co-sign such sweeps for
+	// the peer in turn.
+	CoopHtlcSweep bool
+
+	// SendCustomMessage sends a custom message to the peer with the given
+	// public key. It's used by the cooperative HTLC sweep protocol.
+	SendCustomMessage func(peer [33]byte, msgType lnwire.MessageType,
+		data []byte) error
+
+	// PrioritizeClaims enables the prioritization of the on-chain claims
+	// of all channels. When set, the wallet funds that pay the fees of
+	// the claims are handed to the claims with the most value at risk
+	// first, and claims that cost more to sweep than they're worth are
+	// deferred.
+	PrioritizeClaims bool
+
+	// WalletBalance returns the confirmed balance of the wallet. It's
+	// used to prioritize the claims.
+	WalletBalance func() (btcutil.Amount, error)
+
+	// AnchorBumpDelegate, if set, fee bumps our broadcast commitment txns
+	// in place of the sweeper.
+	AnchorBumpDelegate AnchorBumpDelegate
+
+	// DelegateAnchorBumps delegates the fee bumps of our commitment txns
+	// to the subscribers of the anchor bumps, unless an
+	// AnchorBumpDelegate is set.
+	DelegateAnchorBumps bool
+
 	// IsForwardedHTLC checks for a given htlc, identified by channel id and
 	// htlcIndex, if it is a forwarded one.
 	IsForwardedHTLC func(chanID lnwire.ShortChannelID, htlcIndex uint64) bool
@@ -190,6 +227,11 @@ type ChainArbitratorConfig struct {
 	// It is useful for testing.
 	Clock clock.Clock
 
+	// FSMRegistry is the registry the state machines of the channel
+	// arbitrators are added to while they run, so that they can be
+	// inspected. It is optional.
+	FSMRegistry *protofsm.Registry
+
 	// SubscribeBreachComplete is used by the breachResolver to register a
 	// subscription that notifies when the breach resolution process is
 	// complete.
@@ -262,6 +304,27 @@ type ChainArbitrator struct {
 	// active channels that it must still watch over.
 	chanSource *channeldb.DB
 
+	// resolutionNtfns sends the resolution events of closed channels to
+	// their subscribers.
+	resolutionNtfns *subscribe.Server
+
+	// coopSweeps holds the state of the cooperative HTLC sweeps.
+	coopSweeps *coopSweepState
+
+	// claimScheduler prioritizes the claims of all channels before they
+	// are offered to the sweeper, if enabled.
+	claimScheduler *claimScheduler
+
+	// anchorBumpNtfns sends the delegated anchor bumps to their
+	// subscribers.
+	anchorBumpNtfns *subscribe.Server
+
+	// pendingAnchorBumps holds the delegated anchor bumps of the
+	// commitments that haven't confirmed yet, keyed by the anchor
+	// outpoint.
+	pendingAnchorBumps map[wire.OutPoint]*AnchorBumpRequest
+	anchorBumpsMtx     sync.Mutex
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -272,13 +335,39 @@ type ChainArbitrator struct {
 func NewChainArbitrator(cfg ChainArbitratorConfig,
 	db *channeldb.DB) *ChainArbitrator {
 
-	return &ChainArbitrator{
-		cfg:            cfg,
-		activeChannels: make(map[wire.OutPoint]*ChannelArbitrator),
-		activeWatchers: make(map[wire.OutPoint]*chainWatcher),
-		chanSource:     db,
-		quit:           make(chan struct{}),
+	c := &ChainArbitrator{
+		cfg:             cfg,
+		activeChannels:  make(map[wire.OutPoint]*ChannelArbitrator),
+		activeWatchers:  make(map[wire.OutPoint]*chainWatcher),
+		chanSource:      db,
+		resolutionNtfns: subscribe.NewServer(),
+		coopSweeps:      newCoopSweepState(),
+		anchorBumpNtfns: subscribe.NewServer(),
+		pendingAnchorBumps: make(
+			map[wire.OutPoint]*AnchorBumpRequest,
+		),
+		quit: make(chan struct{}),
 	}
+
+	// If enabled, the anchor bumps are handed to their subscribers rather
+	// than the sweeper.
+	if cfg.DelegateAnchorBumps && cfg.AnchorBumpDelegate == nil {
+		c.cfg.AnchorBumpDelegate = c
+	}
+
+	// If enabled, the claims of all channels go through the claim
+	// scheduler before they're offered to the sweeper.
+	if cfg.PrioritizeClaims {
+		c.claimScheduler = newClaimScheduler(claimSchedulerConfig{
+			Sweeper:       cfg.Sweeper,
+			FeeEstimator:  cfg.FeeEstimator,
+			WalletBalance: cfg.WalletBalance,
+			Notifier:      cfg.Notifier,
+		})
+		c.cfg.Sweeper = c.claimScheduler
+	}
+
+	return c
 }
 
 // arbChannel is a wrapper around an open channel that channel arbitrators
@@ -329,7 +418,32 @@ func (a *arbChannel) NewAnchorResolutions() (*lnwallet.AnchorResolutions,
 		return nil, err
 	}
 
-	return chanMachine.NewAnchorResolutions()
+	anchors, err := chanMachine.NewAnchorResolutions()
+	if err != nil {
+		return nil, err
+	}
+
+	// If we broadcast our own commitment, we attach it to its anchor so
+	// that the sweeper can broadcast both as a package. This allows the
+	// commitment to enter the mempool even if its fee is too low.
+	if anchors.Local == nil {
+		return anchors, nil
+	}
+
+	commitTx, err := channel.BroadcastedCommitment()
+	switch {
+	case errors.Is(err, channeldb.ErrNoCloseTx):
+		return anchors, nil
+
+	case err != nil:
+		return nil, err
+	}
+
+	if commitTx.TxHash() == anchors.Local.CommitAnchor.Hash {
+		anchors.Local.CommitTx = commitTx
+	}
+
+	return anchors, nil
 }
 
 // ForceCloseChan should force close the contract that this attendant is
@@ -417,6 +531,9 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 				return err
 			}
 			c.cfg.NotifyClosedChannel(summary.ChanPoint)
+			c.notifyCommitConfirmed(summary)
+			c.clearAnchorBumps(summary.ChanPoint)
+
 			return nil
 		},
 		IsPendingClose:        false,
@@ -425,9 +542,20 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 		PutResolverReport: func(tx kvdb.RwTx,
 			report *channeldb.ResolverReport) error {
 
-			return c.chanSource.PutResolverReport(
-				tx, c.cfg.ChainHash, &chanPoint, report,
-			)
+			return c.putResolverReport(tx, chanPoint, report)
+		},
+		NotifyResolverReport: func(report *channeldb.ResolverReport) {
+			c.notifyResolverReport(chanPoint, report)
+		},
+		ReleaseExpiredHtlc: func(op wire.OutPoint,
+			signDesc *input.SignDescriptor) {
+
+			c.releaseExpiredHtlc(chanPoint, op, signDesc)
+		},
+		RequestCoopSweepSig: func(
+			sweepTx *wire.MsgTx) (input.Signature, error) {
+
+			return c.requestCoopSweepSig(chanPoint, sweepTx)
 		},
 		FetchHistoricalChannel: func() (*channeldb.OpenChannel, error) {
 			chanStateDB := c.chanSource.ChannelStateDB()
@@ -459,6 +587,7 @@ func newActiveChannelArbitrator(channel *channeldb.OpenChannel,
 		if c.cfg.NotifyFullyResolvedChannel != nil {
 			c.cfg.NotifyFullyResolvedChannel(chanPoint)
 		}
+		c.notifyFullyResolved(chanPoint)
 
 		return c.ResolveContract(chanPoint)
 	}
@@ -559,6 +688,20 @@ func (c *ChainArbitrator) Start() error {
 	log.Infof("ChainArbitrator starting with config: budget=[%v]",
 		&c.cfg.Budget)
 
+	if err := c.resolutionNtfns.Start(); err != nil {
+		return err
+	}
+
+	if err := c.anchorBumpNtfns.Start(); err != nil {
+		return err
+	}
+
+	if c.claimScheduler != nil {
+		if err := c.claimScheduler.Start(); err != nil {
+			return err
+		}
+	}
+
 	// First, we'll fetch all the channels that are still open, in order to
 	// collect them within our set of active contracts.
 	openChannels, err := c.chanSource.ChannelStateDB().FetchAllChannels()
@@ -652,8 +795,25 @@ func (c *ChainArbitrator) Start() error {
 			PutResolverReport: func(tx kvdb.RwTx,
 				report *channeldb.ResolverReport) error {
 
-				return c.chanSource.PutResolverReport(
-					tx, c.cfg.ChainHash, &chanPoint, report,
+				return c.putResolverReport(
+					tx, chanPoint, report,
+				)
+			},
+			NotifyResolverReport: func(
+				report *channeldb.ResolverReport) {
+
+				c.notifyResolverReport(chanPoint, report)
+			},
+			ReleaseExpiredHtlc: func(op wire.OutPoint,
+				signDesc *input.SignDescriptor) {
+
+				c.releaseExpiredHtlc(chanPoint, op, signDesc)
+			},
+			RequestCoopSweepSig: func(
+				sweepTx *wire.MsgTx) (input.Signature, error) {
+
+				return c.requestCoopSweepSig(
+					chanPoint, sweepTx,
 				)
 			},
 			FetchHistoricalChannel: func() (*channeldb.OpenChannel, error) {
@@ -678,6 +838,7 @@ func (c *ChainArbitrator) Start() error {
 			if c.cfg.NotifyFullyResolvedChannel != nil {
 				c.cfg.NotifyFullyResolvedChannel(chanPoint)
 			}
+			c.notifyFullyResolved(chanPoint)
 
 			return c.ResolveContract(chanPoint)
 		}
@@ -1033,7 +1194,15 @@ func (c *ChainArbitrator) Stop() error {
 
 	c.wg.Wait()
 
-	return nil
+	if c.claimScheduler != nil {
+		c.claimScheduler.Stop()
+	}
+
+	if err := c.anchorBumpNtfns.Stop(); err != nil {
+		return err
+	}
+
+	return c.resolutionNtfns.Stop()
 }
 
 // ContractUpdate is a message packages the latest set of active HTLCs on a
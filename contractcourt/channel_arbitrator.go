@@ -166,6 +166,22 @@ type ChannelArbitratorConfig struct {
 	PutResolverReport func(tx kvdb.RwTx,
 		report *channeldb.ResolverReport) error
 
+	// NotifyResolverReport is called with the resolver reports that were
+	// recorded within a transaction of the arbitrator log, once the
+	// transaction is committed. It may be nil.
+	NotifyResolverReport func(report *channeldb.ResolverReport)
+
+	// ReleaseExpiredHtlc is called with an expired HTLC output on the
+	// remote commitment that we gave up on, so we co-sign a direct sweep
+	// of it when the peer asks for one. It may be nil.
+	ReleaseExpiredHtlc func(op wire.OutPoint,
+		signDesc *input.SignDescriptor)
+
+	// RequestCoopSweepSig asks the peer to sign the passed tx that sweeps
+	// an expired HTLC output of our commitment. It may be nil.
+	RequestCoopSweepSig func(sweepTx *wire.MsgTx) (input.Signature,
+		error)
+
 	// FetchHistoricalChannel retrieves the historical state of a channel.
 	// This is mostly used to supplement the ContractResolvers with
 	// additional information required for proper contract resolution.
@@ -378,9 +394,14 @@ type ChannelArbitrator struct {
 	// contract will be sent over.
 	forceCloseReqs chan *forceCloseReq
 
-	// state is the current state of the arbitrator. This state is examined
-	// upon start up to decide which actions to take.
-	state ArbitratorState
+	// fsm is the state machine that holds the current state of the
+	// arbitrator. This state is examined upon start up to decide which
+	// actions to take. It is created once the arbitrator is started.
+	fsm *arbStateMachine
+
+	// fsmRegistered is true if the state machine was added to the
+	// registry of the config.
+	fsmRegistered bool
 
 	wg   sync.WaitGroup
 	quit chan struct{}
@@ -474,8 +495,17 @@ func (c *ChannelArbitrator) Start(state *chanArbStartState) error {
 		c.cfg.ChanPoint, lnutils.SpewLogClosure(c.activeHTLCs),
 		state.currentState)
 
-	// Set our state from our starting state.
-	c.state = state.currentState
+	// Set our state from our starting state, and make the state machine
+	// available for inspection.
+	c.fsm = c.newArbStateMachine(state.currentState)
+	if c.cfg.FSMRegistry != nil {
+		if err := c.cfg.FSMRegistry.Register(c.fsm); err != nil {
+			log.Warnf("ChannelArbitrator(%v): unable to register "+
+				"state machine: %v", c.cfg.ChanPoint, err)
+		} else {
+			c.fsmRegistered = true
+		}
+	}
 
 	_, bestHeight, err := c.cfg.ChainIO.GetBestBlock()
 	if err != nil {
@@ -490,7 +520,7 @@ func (c *ChannelArbitrator) Start(state *chanArbStartState) error {
 	trigger := chainTrigger
 	triggerHeight := uint32(bestHeight)
 	if c.cfg.IsPendingClose {
-		switch c.state {
+		switch c.currentState() {
 		case StateDefault:
 			fallthrough
 		case StateBroadcastCommit:
@@ -513,19 +543,19 @@ func (c *ChannelArbitrator) Start(state *chanArbStartState) error {
 
 			log.Warnf("ChannelArbitrator(%v): detected stalled "+
 				"state=%v for closed channel",
-				c.cfg.ChanPoint, c.state)
+				c.cfg.ChanPoint, c.currentState())
 		}
 
 		triggerHeight = c.cfg.ClosingHeight
 	}
 
 	log.Infof("ChannelArbitrator(%v): starting state=%v, trigger=%v, "+
-		"triggerHeight=%v", c.cfg.ChanPoint, c.state, trigger,
+		"triggerHeight=%v", c.cfg.ChanPoint, c.currentState(), trigger,
 		triggerHeight)
 
 	// We'll now attempt to advance our state forward based on the current
 	// on-chain state, and our set of active contracts.
-	startingState := c.state
+	startingState := c.currentState()
 	nextState, _, err := c.advanceState(
 		triggerHeight, trigger, state.commitSet,
 	)
@@ -840,6 +870,12 @@ func (c *ChannelArbitrator) Stop() error {
 	close(c.quit)
 	c.wg.Wait()
 
+	if c.fsmRegistered {
+		c.cfg.FSMRegistry.Unregister(c.fsm.Name())
+	}
+
+	reportBlocksUntilForceClose(c.cfg.ChanPoint, fn.None[int32]())
+
 	return nil
 }
 
@@ -909,7 +945,7 @@ func (t transitionTrigger) String() string {
 // the appropriate state transition if necessary. The next state we transition
 // to is returned, Additionally, if the next transition results in a commitment
 // broadcast, the commitment transaction itself is returned.
-func (c *ChannelArbitrator) stateStep(
+func (c *ChannelArbitrator) stateStep(state ArbitratorState,
 	triggerHeight uint32, trigger transitionTrigger,
 	confCommitSet *CommitSet) (ArbitratorState, *wire.MsgTx, error) {
 
@@ -917,7 +953,7 @@ func (c *ChannelArbitrator) stateStep(
 		nextState ArbitratorState
 		closeTx   *wire.MsgTx
 	)
-	switch c.state {
+	switch state {
 
 	// If we're in the default state, then we'll check our set of actions
 	// to see if while we were down, conditions have changed.
@@ -1177,7 +1213,7 @@ func (c *ChannelArbitrator) stateStep(
 		}
 
 		log.Infof("ChannelArbitrator(%v): trigger %v moving from "+
-			"state %v to %v", c.cfg.ChanPoint, trigger, c.state,
+			"state %v to %v", c.cfg.ChanPoint, trigger, state,
 			nextState)
 
 	// If we're in this state, then the contract has been fully closed to
@@ -1349,6 +1385,7 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 			&input.TxInfo{
 				Fee:    anchor.CommitFee,
 				Weight: anchor.CommitWeight,
+				Tx:     anchor.CommitTx,
 			},
 		)
 
@@ -1372,6 +1409,25 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 			c.cfg.Budget.AnchorCPFP,
 		) + AnchorOutputValue
 
+		// If the anchor bumps are delegated, the external service
+		// fee bumps the commitment instead of the sweeper.
+		if c.cfg.AnchorBumpDelegate != nil {
+			log.Infof("ChannelArbitrator(%v): delegating anchor "+
+				"from %s commitment %v with deadline=%v, "+
+				"budget=%v", c.cfg.ChanPoint, anchorPath,
+				anchor.CommitAnchor, deadlineDesc, budget)
+
+			req, err := newAnchorBumpRequest(
+				c.cfg.Signer, c.cfg.ChanPoint, anchor,
+				witnessType, deadlineHeight, budget,
+			)
+			if err != nil {
+				return err
+			}
+
+			return c.cfg.AnchorBumpDelegate.DelegateAnchorBump(req)
+		}
+
 		log.Infof("ChannelArbitrator(%v): offering anchor from %s "+
 			"commitment %v to sweeper with deadline=%v, budget=%v",
 			c.cfg.ChanPoint, anchorPath, anchor.CommitAnchor,
@@ -1589,62 +1645,33 @@ func (c *ChannelArbitrator) launchResolvers(resolvers []ContractResolver,
 	}
 }
 
-// advanceState is the main driver of our state machine. This method is an
-// iterative function which repeatedly attempts to advance the internal state
-// of the channel arbitrator. The state will be advanced until we reach a
-// redundant transition, meaning that the state transition is a noop. The final
-// param is a callback that allows the caller to execute an arbitrary action
-// after each state transition.
+// advanceState is the main driver of our state machine. It sends the trigger
+// to the state machine of the arbitrator, which repeatedly attempts to advance
+// the internal state of the channel arbitrator. The state will be advanced
+// until we reach a redundant transition, meaning that the state transition is
+// a noop. Each state is committed to the arbitrator log before it is entered,
+// which ensures that we will re-execute the prior state if anything fails.
 func (c *ChannelArbitrator) advanceState(
 	triggerHeight uint32, trigger transitionTrigger,
 	confCommitSet *CommitSet) (ArbitratorState, *wire.MsgTx, error) {
 
-	var (
-		priorState   ArbitratorState
-		forceCloseTx *wire.MsgTx
-	)
-
-	// We'll continue to advance our state forward until the state we
-	// transition to is that same state that we started at.
-	for {
-		priorState = c.state
-		log.Debugf("ChannelArbitrator(%v): attempting state step with "+
-			"trigger=%v from state=%v", c.cfg.ChanPoint, trigger,
-			priorState)
-
-		nextState, closeTx, err := c.stateStep(
-			triggerHeight, trigger, confCommitSet,
-		)
-		if err != nil {
-			log.Errorf("ChannelArbitrator(%v): unable to advance "+
-				"state: %v", c.cfg.ChanPoint, err)
-			return priorState, nil, err
-		}
-
-		if forceCloseTx == nil && closeTx != nil {
-			forceCloseTx = closeTx
-		}
+	event := &arbEvent{
+		triggerHeight: triggerHeight,
+		trigger:       trigger,
+		confCommitSet: confCommitSet,
+	}
+	if err := c.fsm.SendEvent(event); err != nil {
+		log.Errorf("ChannelArbitrator(%v): unable to advance state: %v",
+			c.cfg.ChanPoint, err)
 
-		// Our termination transition is a noop transition. If we get
-		// our prior state back as the next state, then we'll
-		// terminate.
-		if nextState == priorState {
-			log.Debugf("ChannelArbitrator(%v): terminating at "+
-				"state=%v", c.cfg.ChanPoint, nextState)
-			return nextState, forceCloseTx, nil
+		if event.err != nil {
+			err = event.err
 		}
 
-		// As the prior state was successfully executed, we can now
-		// commit the next state. This ensures that we will re-execute
-		// the prior state if anything fails.
-		if err := c.log.CommitState(nextState); err != nil {
-			log.Errorf("ChannelArbitrator(%v): unable to commit "+
-				"next state(%v): %v", c.cfg.ChanPoint,
-				nextState, err)
-			return priorState, nil, err
-		}
-		c.state = nextState
+		return c.currentState(), nil, err
 	}
+
+	return c.currentState(), event.closeTx, nil
 }
 
 // ChainAction is an enum that encompasses all possible on-chain actions
@@ -1811,7 +1838,7 @@ func (c *ChannelArbitrator) checkCommitChainActions(height uint32,
 		// incoming HTLC that will time out, which means as long as we
 		// can learn the preimage, we can settle the invoice (before it
 		// expires?).
-		toChain := c.shouldGoOnChain(
+		toChain := c.triggersForceClose(
 			htlc, c.cfg.OutgoingBroadcastDelta, height,
 		)
 
@@ -1846,7 +1873,7 @@ func (c *ChannelArbitrator) checkCommitChainActions(height uint32,
 			continue
 		}
 
-		toChain := c.shouldGoOnChain(
+		toChain := c.triggersForceClose(
 			htlc, c.cfg.IncomingBroadcastDelta, height,
 		)
 
@@ -2797,10 +2824,14 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 			// If we're not in the default state, then we can
 			// ignore this signal as we're waiting for contract
 			// resolution.
-			if c.state != StateDefault {
+			if c.currentState() != StateDefault {
 				continue
 			}
 
+			// Report how close the channel is to being force
+			// closed due to its HTLCs.
+			c.reportExpiryRisk(uint32(bestHeight))
+
 			// Now that a new block has arrived, we'll attempt to
 			// advance our state forward.
 			nextState, _, err := c.advanceState(
@@ -2864,7 +2895,7 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 			log.Infof("ChannelArbitrator(%v): local on-chain "+
 				"channel close", c.cfg.ChanPoint)
 
-			if c.state != StateCommitmentBroadcasted {
+			if c.currentState() != StateCommitmentBroadcasted {
 				log.Errorf("ChannelArbitrator(%v): unexpected "+
 					"local on-chain channel close",
 					c.cfg.ChanPoint)
@@ -3090,7 +3121,7 @@ func (c *ChannelArbitrator) channelAttendant(bestHeight int32) {
 			log.Infof("ChannelArbitrator(%v): received force "+
 				"close request", c.cfg.ChanPoint)
 
-			if c.state != StateDefault {
+			if c.currentState() != StateDefault {
 				select {
 				case closeReq.closeTx <- nil:
 				case <-c.quit:
@@ -0,0 +1,121 @@
+package contractcourt
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/protofsm"
+)
+
+// arbStateMachine is the state machine of a channel arbitrator. Its states
+// are the ArbitratorStates and its environment is the arbitrator itself.
+type arbStateMachine = protofsm.StateMachine[*arbEvent, *ChannelArbitrator]
+
+// arbStateTransition is a transition of the state machine of a channel
+// arbitrator.
+type arbStateTransition = protofsm.StateTransition[
+	*arbEvent, *ChannelArbitrator,
+]
+
+// arbEvent is the event of the state machine of a channel arbitrator. It asks
+// the current state to take a step for the given trigger.
+type arbEvent struct {
+	// triggerHeight is the height of the trigger.
+	triggerHeight uint32
+
+	// trigger is the reason for the step.
+	trigger transitionTrigger
+
+	// confCommitSet is the set of HTLCs of the commitment that confirmed
+	// on chain, if any.
+	confCommitSet *CommitSet
+
+	// closeTx is set to the commitment transaction that was broadcast by
+	// the steps of the event, if any.
+	closeTx *wire.MsgTx
+
+	// err is set to the error of the step that failed, if any. It is kept
+	// unwrapped, so that callers can match it.
+	err error
+}
+
+// String returns the trigger of the event.
+func (e *arbEvent) String() string {
+	return e.trigger.String()
+}
+
+// A compile time check to ensure ArbitratorState implements the State
+// interface of the arbitrator's state machine.
+var _ protofsm.State[*arbEvent, *ChannelArbitrator] = StateDefault
+
+// ProcessEvent takes a step in the state for the trigger of the event. If the
+// step leads to another state, the event is emitted again so that the next
+// state takes a step for the same trigger. A step that returns the state it
+// was taken in is a noop, which ends the advancement of the state.
+//
+// NOTE: This is part of the protofsm.State interface.
+func (a ArbitratorState) ProcessEvent(event *arbEvent,
+	c *ChannelArbitrator) (*arbStateTransition, error) {
+
+	log.Debugf("ChannelArbitrator(%v): attempting state step with "+
+		"trigger=%v from state=%v", c.cfg.ChanPoint, event.trigger, a)
+
+	nextState, closeTx, err := c.stateStep(
+		a, event.triggerHeight, event.trigger, event.confCommitSet,
+	)
+	if err != nil {
+		event.err = err
+		return nil, err
+	}
+
+	if event.closeTx == nil && closeTx != nil {
+		event.closeTx = closeTx
+	}
+
+	if nextState == a {
+		log.Debugf("ChannelArbitrator(%v): terminating at "+
+			"state=%v", c.cfg.ChanPoint, nextState)
+		return nil, nil
+	}
+
+	return &arbStateTransition{
+		NextState: nextState,
+		NewEvents: []*arbEvent{event},
+	}, nil
+}
+
+// IsTerminal returns false. Even StateFullyResolved takes a step for every
+// trigger, so that a channel that was resolved before a restart is marked as
+// resolved again.
+//
+// NOTE: This is part of the protofsm.State interface.
+func (a ArbitratorState) IsTerminal() bool {
+	return false
+}
+
+// newArbStateMachine creates the state machine of the arbitrator, starting in
+// the given state. Every transition is committed to the arbitrator log before
+// it takes effect, so the state machine resumes in the last committed state
+// after a restart.
+func (c *ChannelArbitrator) newArbStateMachine(
+	state ArbitratorState) *arbStateMachine {
+
+	return protofsm.NewStateMachine(protofsm.Config[
+		*arbEvent, *ChannelArbitrator,
+	]{
+		Name:         fmt.Sprintf("chanarb-%v", c.cfg.ChanPoint),
+		InitialState: state,
+		Env:          c,
+		Checkpoint: func(
+			next protofsm.State[*arbEvent, *ChannelArbitrator]) error {
+
+			return c.log.CommitState(next.(ArbitratorState))
+		},
+		Clock: c.cfg.Clock,
+	})
+}
+
+// currentState returns the current state of the arbitrator.
+func (c *ChannelArbitrator) currentState() ArbitratorState {
+	return c.fsm.CurrentState().(ArbitratorState)
+}
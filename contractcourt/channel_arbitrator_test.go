@@ -21,9 +21,11 @@ import (
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lntest/wait"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/protofsm"
 	"github.com/stretchr/testify/require"
 )
 
@@ -214,6 +216,10 @@ type chanArbTestCtx struct {
 	breachResolutionChan chan struct{}
 
 	finalHtlcs map[uint64]bool
+
+	// fsmTransitions is the number of transitions of the arbitrator's
+	// state machine that were asserted.
+	fsmTransitions int
 }
 
 func (c *chanArbTestCtx) CleanUp() {
@@ -254,15 +260,58 @@ func (c *chanArbTestCtx) AssertStateTransitions(expectedStates ...ArbitratorStat
 		if state != exp {
 			c.t.Fatalf("expected new state %v, got %v", exp, state)
 		}
+
+		c.assertFSMTransition(exp)
+	}
+}
+
+// assertFSMTransition asserts that the next transition recorded by the state
+// machine of the arbitrator leads into the given state, and that it starts in
+// the state the previous transition led into.
+func (c *chanArbTestCtx) assertFSMTransition(expected ArbitratorState) {
+	c.t.Helper()
+
+	// The transition is recorded once its state was committed to the log,
+	// so we may have to wait for it.
+	var transitions []protofsm.Transition
+	err := wait.NoError(func() error {
+		transitions = c.chanArb.fsm.Transitions()
+		if len(transitions) <= c.fsmTransitions {
+			return fmt.Errorf("transition to %v not recorded",
+				expected)
+		}
+
+		return nil
+	}, defaultTimeout)
+	require.NoError(c.t, err)
+
+	transition := transitions[c.fsmTransitions]
+	require.Equal(c.t, expected.String(), transition.To)
+	if c.fsmTransitions > 0 {
+		prev := transitions[c.fsmTransitions-1]
+		require.Equal(c.t, prev.To, transition.From)
 	}
+
+	c.fsmTransitions++
 }
 
 // AssertState checks that the ChannelArbitrator is in the state we expect it
 // to be.
 func (c *chanArbTestCtx) AssertState(expected ArbitratorState) {
-	if c.chanArb.state != expected {
-		c.t.Fatalf("expected state %v, was %v", expected, c.chanArb.state)
+	if c.chanArb.currentState() != expected {
+		c.t.Fatalf("expected state %v, was %v", expected, c.chanArb.currentState())
+	}
+
+	// While the arbitrator runs, its state machine is registered and
+	// reports the same state.
+	machine, err := c.chanArb.cfg.FSMRegistry.Machine(
+		c.chanArb.fsm.Name(),
+	)
+	if errors.Is(err, protofsm.ErrMachineNotFound) {
+		return
 	}
+	require.NoError(c.t, err)
+	require.Equal(c.t, expected.String(), machine.StateName())
 }
 
 // Restart simulates a clean restart of the channel arbitrator, forcing it to
@@ -386,6 +435,7 @@ func createTestChannelArbitrator(t *testing.T, log ArbitratorLog,
 		Clock:        clock.NewDefaultClock(),
 		Sweeper:      mockSweeper,
 		HtlcNotifier: &mockHTLCNotifier{},
+		FSMRegistry:  protofsm.NewRegistry(),
 		PutFinalHtlcOutcome: func(chanId lnwire.ShortChannelID,
 			htlcId uint64, settled bool) error {
 
@@ -557,6 +607,60 @@ func TestChannelArbitratorCooperativeClose(t *testing.T) {
 	}
 }
 
+// TestChannelArbitratorStateMachine tests that the state machine of the
+// ChannelArbitrator is registered while it runs, and that it records the
+// transitions of the arbitrator.
+func TestChannelArbitratorStateMachine(t *testing.T) {
+	log := &mockArbitratorLog{
+		state:     StateDefault,
+		newStates: make(chan ArbitratorState, 5),
+	}
+
+	chanArbCtx, err := createTestChannelArbitrator(t, log)
+	require.NoError(t, err, "unable to create ChannelArbitrator")
+
+	chanArb := chanArbCtx.chanArb
+	registry := protofsm.NewRegistry()
+	chanArb.cfg.FSMRegistry = registry
+
+	require.NoError(t, chanArb.Start(nil))
+	t.Cleanup(func() {
+		require.NoError(t, chanArb.Stop())
+	})
+
+	name := fmt.Sprintf("chanarb-%v", chanArb.cfg.ChanPoint)
+	machine, err := registry.Machine(name)
+	require.NoError(t, err)
+	require.Equal(t, StateDefault.String(), machine.StateName())
+	require.False(t, machine.IsTerminal())
+
+	// A cooperative close moves the arbitrator straight to the fully
+	// resolved state.
+	chanArb.cfg.ChainEvents.CooperativeClosure <- &CooperativeCloseInfo{
+		&channeldb.ChannelCloseSummary{},
+	}
+
+	select {
+	case <-chanArbCtx.resolvedChan:
+	case <-time.After(defaultTimeout):
+		t.Fatalf("contract was not resolved")
+	}
+
+	chanArbCtx.AssertStateTransitions(StateFullyResolved)
+	require.Equal(t, StateFullyResolved.String(), machine.StateName())
+
+	transitions := machine.Transitions()
+	require.Len(t, transitions, 1)
+	require.Equal(t, StateDefault.String(), transitions[0].From)
+	require.Equal(t, StateFullyResolved.String(), transitions[0].To)
+	require.Equal(t, coopCloseTrigger.String(), transitions[0].Event)
+
+	// Once stopped, the state machine is removed from the registry.
+	require.NoError(t, chanArb.Stop())
+	_, err = registry.Machine(name)
+	require.ErrorIs(t, err, protofsm.ErrMachineNotFound)
+}
+
 // TestChannelArbitratorRemoteForceClose checks that the ChannelArbitrator goes
 // through the expected states if a remote force close is observed in the
 // chain.
@@ -638,7 +742,7 @@ func TestChannelArbitratorLocalForceClose(t *testing.T) {
 		// When the force close tx is being broadcasted, check that the
 		// state is correct at that point.
 		select {
-		case stateChan <- chanArb.state:
+		case stateChan <- chanArb.currentState():
 		case <-chanArb.quit:
 			return fmt.Errorf("exiting")
 		}
@@ -1144,7 +1248,7 @@ func TestChannelArbitratorLocalForceCloseRemoteConfirmed(t *testing.T) {
 		// When the force close tx is being broadcasted, check that the
 		// state is correct at that point.
 		select {
-		case stateChan <- chanArb.state:
+		case stateChan <- chanArb.currentState():
 		case <-chanArb.quit:
 			return fmt.Errorf("exiting")
 		}
@@ -1250,7 +1354,7 @@ func TestChannelArbitratorLocalForceDoubleSpend(t *testing.T) {
 		// When the force close tx is being broadcasted, check that the
 		// state is correct at that point.
 		select {
-		case stateChan <- chanArb.state:
+		case stateChan <- chanArb.currentState():
 		case <-chanArb.quit:
 			return fmt.Errorf("exiting")
 		}
@@ -1475,7 +1579,7 @@ func TestChannelArbitratorForceCloseBreachedChannel(t *testing.T) {
 		// When the force close tx is being broadcasted, check that the
 		// state is correct at that point.
 		select {
-		case stateChan <- chanArb.state:
+		case stateChan <- chanArb.currentState():
 		case <-chanArb.quit:
 			return fmt.Errorf("exiting")
 		}
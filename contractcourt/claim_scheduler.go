@@ -0,0 +1,369 @@
+package contractcourt
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+// claimConfTarget is the conf target used to estimate the fee rate that
+// decides whether a claim is worth sweeping.
+const claimConfTarget = 6
+
+// claimSchedulerConfig holds the dependencies of the claimScheduler.
+type claimSchedulerConfig struct {
+	// Sweeper is the sweeper the claims are offered to.
+	Sweeper UtxoSweeper
+
+	// FeeEstimator estimates the fee rate of the claims.
+	FeeEstimator chainfee.Estimator
+
+	// WalletBalance returns the confirmed balance of the wallet, which
+	// is available to pay the fees of the claims that can't pay for
+	// themselves.
+	WalletBalance func() (btcutil.Amount, error)
+
+	// Notifier is used to re-evaluate the deferred claims every block.
+	Notifier chainntnfs.ChainNotifier
+}
+
+// scheduledClaim is a claim that was offered to the claimScheduler.
+type scheduledClaim struct {
+	// inp is the input that's claimed.
+	inp input.Input
+
+	// params are the sweep parameters of the claim.
+	params sweep.Params
+
+	// resultChan receives the result of the sweep of the claim.
+	resultChan chan sweep.Result
+
+	// offered is true once the claim was offered to the sweeper.
+	offered bool
+}
+
+// valueAtRisk returns the value that's lost if the claim isn't swept. For
+// anchors, this is the value of the HTLCs they protect, which is reflected by
+// their budget.
+func (c *scheduledClaim) valueAtRisk() btcutil.Amount {
+	switch c.inp.WitnessType() {
+	case input.CommitmentAnchor, input.TaprootAnchorSweepSpend:
+		return c.params.Budget
+
+	default:
+		return btcutil.Amount(c.inp.SignDesc().Output.Value)
+	}
+}
+
+// walletFunds returns the amount the claim may spend from the wallet to pay
+// its fees, which is the part of its budget its own value can't cover.
+func (c *scheduledClaim) walletFunds() btcutil.Amount {
+	value := btcutil.Amount(c.inp.SignDesc().Output.Value)
+	if c.params.Budget <= value {
+		return 0
+	}
+
+	return c.params.Budget - value
+}
+
+// isDust returns true if sweeping the claim at the given fee rate costs more
+// than its value. Anchors are never dust, as their value lies in the HTLCs
+// they protect.
+func (c *scheduledClaim) isDust(feeRate chainfee.SatPerKWeight) bool {
+	switch c.inp.WitnessType() {
+	case input.CommitmentAnchor, input.TaprootAnchorSweepSpend:
+		return false
+	}
+
+	witnessSize, _, err := c.inp.WitnessType().SizeUpperBound()
+	if err != nil {
+		return false
+	}
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(witnessSize)
+	fee := feeRate.FeeForWeight(weightEstimate.Weight())
+
+	return c.valueAtRisk() <= fee
+}
+
+// claimScheduler sits between the resolvers of all channels and the sweeper,
+// and decides in which order their claims are offered to the sweeper. When
+// many channels go on-chain at the same time, the wallet funds that are
+// available to pay the fees of the claims that can't pay for themselves are
+// handed to the claims with the most value at risk first, and the claims that
+// cost more to sweep than they're worth are deferred until the fees drop.
+// Deferred claims are re-evaluated every block and whenever an offered claim
+// is resolved.
+type claimScheduler struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg claimSchedulerConfig
+
+	mu sync.Mutex
+
+	// claims are the claims that are deferred or offered to the sweeper
+	// and not resolved yet, keyed by their outpoint.
+	claims map[wire.OutPoint]*scheduledClaim
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// A compile time assertion to ensure claimScheduler meets the UtxoSweeper
+// interface.
+var _ UtxoSweeper = (*claimScheduler)(nil)
+
+// newClaimScheduler creates a new claimScheduler.
+func newClaimScheduler(cfg claimSchedulerConfig) *claimScheduler {
+	return &claimScheduler{
+		cfg:    cfg,
+		claims: make(map[wire.OutPoint]*scheduledClaim),
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start starts the re-evaluation of the deferred claims on every block.
+func (s *claimScheduler) Start() error {
+	var err error
+	s.started.Do(func() {
+		var blockEpochs *chainntnfs.BlockEpochEvent
+		blockEpochs, err = s.cfg.Notifier.RegisterBlockEpochNtfn(nil)
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go s.blockLoop(blockEpochs)
+	})
+
+	return err
+}
+
+// Stop stops the claimScheduler.
+func (s *claimScheduler) Stop() {
+	s.stopped.Do(func() {
+		close(s.quit)
+		s.wg.Wait()
+	})
+}
+
+// blockLoop re-evaluates the deferred claims on every block.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *claimScheduler) blockLoop(blockEpochs *chainntnfs.BlockEpochEvent) {
+	defer s.wg.Done()
+	defer blockEpochs.Cancel()
+
+	for {
+		select {
+		case _, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return
+			}
+
+			s.mu.Lock()
+			s.schedule()
+			s.mu.Unlock()
+
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// SweepInput schedules the claim of the given input. The claim is offered to
+// the sweeper right away, unless the wallet can't fund it along with the more
+// valuable claims, or it isn't worth sweeping at the current fee rate.
+//
+// NOTE: Part of the UtxoSweeper interface.
+func (s *claimScheduler) SweepInput(inp input.Input,
+	params sweep.Params) (chan sweep.Result, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op := inp.OutPoint()
+
+	claim, ok := s.claims[op]
+	switch {
+	// If the claim is offered already, the sweeper takes care of the
+	// duplicate.
+	case ok && claim.offered:
+		return s.cfg.Sweeper.SweepInput(inp, params)
+
+	// A deferred claim is updated with the latest parameters.
+	case ok:
+		claim.inp = inp
+		claim.params = params
+
+	default:
+		claim = &scheduledClaim{
+			inp:        inp,
+			params:     params,
+			resultChan: make(chan sweep.Result, 1),
+		}
+		s.claims[op] = claim
+	}
+
+	s.schedule()
+
+	return claim.resultChan, nil
+}
+
+// RelayFeePerKW returns the minimum fee rate required for transactions to be
+// relayed.
+//
+// NOTE: Part of the UtxoSweeper interface.
+func (s *claimScheduler) RelayFeePerKW() chainfee.SatPerKWeight {
+	return s.cfg.Sweeper.RelayFeePerKW()
+}
+
+// UpdateParams updates the sweep parameters of a scheduled claim.
+//
+// NOTE: Part of the UtxoSweeper interface.
+func (s *claimScheduler) UpdateParams(op wire.OutPoint,
+	params sweep.Params) (chan sweep.Result, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	claim, ok := s.claims[op]
+	if !ok {
+		return s.cfg.Sweeper.UpdateParams(op, params)
+	}
+
+	claim.params = params
+	if claim.offered {
+		return s.cfg.Sweeper.UpdateParams(op, params)
+	}
+
+	s.schedule()
+
+	return claim.resultChan, nil
+}
+
+// schedule offers the deferred claims to the sweeper, most valuable first, as
+// long as the wallet can fund them and they're worth sweeping.
+//
+// NOTE: The caller MUST hold the mutex.
+func (s *claimScheduler) schedule() {
+	// If we can't estimate the fee rate or the wallet balance, we don't
+	// hold back any claims.
+	feeRate, err := s.cfg.FeeEstimator.EstimateFeePerKW(claimConfTarget)
+	if err != nil {
+		log.Errorf("Unable to estimate fee rate of claims: %v", err)
+		feeRate = 0
+	}
+
+	limited := true
+	available, err := s.cfg.WalletBalance()
+	if err != nil {
+		log.Errorf("Unable to fetch wallet balance for claims: %v", err)
+		limited = false
+	}
+
+	var deferred []*scheduledClaim
+	for _, claim := range s.claims {
+		if claim.offered {
+			available -= claim.walletFunds()
+			continue
+		}
+
+		deferred = append(deferred, claim)
+	}
+
+	// The claims with the most value at risk are funded first. Claims of
+	// equal value are funded by their deadline.
+	sort.Slice(deferred, func(i, j int) bool {
+		vi, vj := deferred[i].valueAtRisk(), deferred[j].valueAtRisk()
+		if vi != vj {
+			return vi > vj
+		}
+
+		di := deferred[i].params.DeadlineHeight.UnwrapOr(-1)
+		dj := deferred[j].params.DeadlineHeight.UnwrapOr(-1)
+
+		return di < dj
+	})
+
+	for _, claim := range deferred {
+		op := claim.inp.OutPoint()
+
+		if claim.isDust(feeRate) {
+			log.Debugf("Deferring claim of %v: value %v doesn't "+
+				"cover fee at %v", op, claim.valueAtRisk(),
+				feeRate)
+
+			continue
+		}
+
+		funds := claim.walletFunds()
+		if limited && funds > available {
+			log.Infof("Deferring claim of %v with value %v: needs "+
+				"%v of wallet funds, %v available", op,
+				claim.valueAtRisk(), funds, available)
+
+			continue
+		}
+
+		s.offer(claim)
+		available -= funds
+	}
+}
+
+// offer offers the given claim to the sweeper.
+//
+// NOTE: The caller MUST hold the mutex.
+func (s *claimScheduler) offer(claim *scheduledClaim) {
+	op := claim.inp.OutPoint()
+
+	log.Debugf("Offering claim of %v with value %v to sweeper: %v", op,
+		claim.valueAtRisk(), claim.params)
+
+	resultChan, err := s.cfg.Sweeper.SweepInput(claim.inp, claim.params)
+	if err != nil {
+		delete(s.claims, op)
+		claim.resultChan <- sweep.Result{Err: err}
+
+		return
+	}
+	claim.offered = true
+
+	s.wg.Add(1)
+	go s.forwardResult(op, claim, resultChan)
+}
+
+// forwardResult forwards the result of the sweep of an offered claim, and
+// schedules the deferred claims once the claim is resolved, as it no longer
+// needs wallet funds.
+//
+// NOTE: This MUST be run as a goroutine.
+func (s *claimScheduler) forwardResult(op wire.OutPoint,
+	claim *scheduledClaim, resultChan chan sweep.Result) {
+
+	defer s.wg.Done()
+
+	select {
+	case result := <-resultChan:
+		claim.resultChan <- result
+
+	case <-s.quit:
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.claims[op] == claim {
+		delete(s.claims, op)
+	}
+	s.schedule()
+}
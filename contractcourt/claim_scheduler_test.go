@@ -0,0 +1,192 @@
+package contractcourt
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/stretchr/testify/require"
+)
+
+// claimFeeEstimator is a fee estimator with a fee rate that can be changed
+// while it's in use.
+type claimFeeEstimator struct {
+	feeRate atomic.Int64
+}
+
+func (e *claimFeeEstimator) EstimateFeePerKW(
+	uint32) (chainfee.SatPerKWeight, error) {
+
+	return chainfee.SatPerKWeight(e.feeRate.Load()), nil
+}
+
+func (e *claimFeeEstimator) Start() error {
+	return nil
+}
+
+func (e *claimFeeEstimator) Stop() error {
+	return nil
+}
+
+func (e *claimFeeEstimator) RelayFeePerKW() chainfee.SatPerKWeight {
+	return chainfee.FeePerKwFloor
+}
+
+// claimSweeper is a sweeper that records the offered inputs and lets the test
+// decide when their sweeps are resolved.
+type claimSweeper struct {
+	offered chan wire.OutPoint
+	results chan chan sweep.Result
+}
+
+func (s *claimSweeper) SweepInput(inp input.Input,
+	_ sweep.Params) (chan sweep.Result, error) {
+
+	result := make(chan sweep.Result, 1)
+	s.offered <- inp.OutPoint()
+	s.results <- result
+
+	return result, nil
+}
+
+func (s *claimSweeper) RelayFeePerKW() chainfee.SatPerKWeight {
+	return chainfee.FeePerKwFloor
+}
+
+func (s *claimSweeper) UpdateParams(wire.OutPoint,
+	sweep.Params) (chan sweep.Result, error) {
+
+	return make(chan sweep.Result, 1), nil
+}
+
+// assertOffered asserts that the given claim is offered to the sweeper next,
+// and returns the channel that resolves its sweep.
+func (s *claimSweeper) assertOffered(t *testing.T,
+	op wire.OutPoint) chan sweep.Result {
+
+	t.Helper()
+
+	select {
+	case offered := <-s.offered:
+		require.Equal(t, op, offered)
+
+	case <-time.After(defaultTimeout):
+		t.Fatalf("claim %v not offered", op)
+	}
+
+	return <-s.results
+}
+
+// assertNotOffered asserts that no claim is offered to the sweeper.
+func (s *claimSweeper) assertNotOffered(t *testing.T) {
+	t.Helper()
+
+	select {
+	case offered := <-s.offered:
+		t.Fatalf("unexpected claim %v offered", offered)
+
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// newClaimInput creates an input of the given value.
+func newClaimInput(index uint32, witnessType input.WitnessType,
+	value btcutil.Amount) input.Input {
+
+	return input.NewBaseInput(
+		&wire.OutPoint{Index: index}, witnessType,
+		&input.SignDescriptor{
+			Output: &wire.TxOut{Value: int64(value)},
+		}, 0,
+	)
+}
+
+// TestClaimScheduler asserts that the claim scheduler hands the wallet funds
+// to the most valuable claims first, and defers the claims that aren't worth
+// sweeping until the fees drop.
+func TestClaimScheduler(t *testing.T) {
+	t.Parallel()
+
+	estimator := &claimFeeEstimator{}
+	estimator.feeRate.Store(12_500)
+
+	sweeper := &claimSweeper{
+		offered: make(chan wire.OutPoint, 10),
+		results: make(chan chan sweep.Result, 10),
+	}
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+	}
+
+	scheduler := newClaimScheduler(claimSchedulerConfig{
+		Sweeper:      sweeper,
+		FeeEstimator: estimator,
+		WalletBalance: func() (btcutil.Amount, error) {
+			return 100_000, nil
+		},
+		Notifier: notifier,
+	})
+	require.NoError(t, scheduler.Start())
+	t.Cleanup(scheduler.Stop)
+
+	// A claim that needs 80k of wallet funds is offered right away.
+	first := newClaimInput(0, input.HtlcOfferedRemoteTimeout, 80_000)
+	resultChan, err := scheduler.SweepInput(first, sweep.Params{
+		Budget: 160_000,
+	})
+	require.NoError(t, err)
+	firstResult := sweeper.assertOffered(t, first.OutPoint())
+
+	// The claims that need more than the remaining 20k of wallet funds
+	// are deferred.
+	small := newClaimInput(1, input.HtlcOfferedRemoteTimeout, 50_000)
+	_, err = scheduler.SweepInput(small, sweep.Params{Budget: 100_000})
+	require.NoError(t, err)
+
+	big := newClaimInput(2, input.HtlcOfferedRemoteTimeout, 60_000)
+	_, err = scheduler.SweepInput(big, sweep.Params{Budget: 120_000})
+	require.NoError(t, err)
+
+	// A claim that costs more to sweep than it's worth is deferred, even
+	// though it doesn't need any wallet funds.
+	dust := newClaimInput(3, input.CommitmentTimeLock, 1_000)
+	_, err = scheduler.SweepInput(dust, sweep.Params{Budget: 1_000})
+	require.NoError(t, err)
+
+	// A claim that pays for itself is offered right away.
+	commit := newClaimInput(4, input.CommitmentTimeLock, 500_000)
+	_, err = scheduler.SweepInput(commit, sweep.Params{Budget: 10_000})
+	require.NoError(t, err)
+	sweeper.assertOffered(t, commit.OutPoint())
+	sweeper.assertNotOffered(t)
+
+	// Once the first claim is resolved, its result is forwarded, and its
+	// wallet funds go to the most valuable deferred claim.
+	firstResult <- sweep.Result{Tx: &wire.MsgTx{}}
+
+	select {
+	case result := <-resultChan:
+		require.NoError(t, result.Err)
+
+	case <-time.After(defaultTimeout):
+		t.Fatalf("result not forwarded")
+	}
+
+	sweeper.assertOffered(t, big.OutPoint())
+	sweeper.assertNotOffered(t)
+
+	// Once the fees drop, the dust claim is worth sweeping on the next
+	// block.
+	estimator.feeRate.Store(int64(chainfee.FeePerKwFloor))
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{Height: 1}
+
+	sweeper.assertOffered(t, dust.OutPoint())
+	sweeper.assertNotOffered(t)
+}
@@ -23,6 +23,11 @@ const (
 	// sweeping inputs. This is a large value, which is fine as the final
 	// fee rate is capped at the max fee rate configured.
 	DefaultBudgetRatio = 0.5
+
+	// DefaultEscalationRatio defines a default ratio of the value of a
+	// time-sensitive output up to which its budget is raised as its
+	// deadline approaches.
+	DefaultEscalationRatio = 0.9
 )
 
 // BudgetConfig is a struct that holds the configuration when offering outputs
@@ -41,6 +46,11 @@ type BudgetConfig struct {
 
 	NoDeadlineHTLC      btcutil.Amount `long:"nodeadlinehtlc" description:"The amount in satoshis to allocate as the budget to pay fees when sweeping a non-time-sensitive (second-level) HTLC. If set, the budget calculated using the ratio (if set) will be capped at this value."`
 	NoDeadlineHTLCRatio float64        `long:"nodeadlinehtlcratio" description:"The ratio of the value in a non-time-sensitive (second-level) HTLC to allocate as the budget to pay fees when sweeping it."`
+
+	Breach      btcutil.Amount `long:"breach" description:"The amount in satoshis to allocate as the budget to pay fees when sweeping the outputs of a breached channel. If set, the budget calculated using the ratio (if set) will be capped at this value."`
+	BreachRatio float64        `long:"breachratio" description:"The ratio of the value in the outputs of a breached channel to allocate as the budget to pay fees when sweeping them."`
+
+	EscalationRatio float64 `long:"escalationratio" description:"The ratio of the value in a time-sensitive output up to which its budget is raised as its deadline approaches. The budget is raised over the last blocks before the deadline, ignoring the fixed budget caps. Set to 0 to disable the escalation."`
 }
 
 // Validate checks the budget configuration for any invalid values.
@@ -89,6 +99,21 @@ func (b *BudgetConfig) Validate() error {
 			MinBudgetRatio)
 	}
 
+	if b.Breach != 0 && b.Breach < MinBudgetValue {
+		return fmt.Errorf("breach must be at least %v", MinBudgetValue)
+	}
+	if b.BreachRatio != 0 && b.BreachRatio < MinBudgetRatio {
+		return fmt.Errorf("breachratio must be at least %v",
+			MinBudgetRatio)
+	}
+
+	if b.EscalationRatio != 0 && (b.EscalationRatio < MinBudgetRatio ||
+		b.EscalationRatio > 1) {
+
+		return fmt.Errorf("escalationratio must be between %v and 1",
+			MinBudgetRatio)
+	}
+
 	return nil
 }
 
@@ -96,10 +121,12 @@ func (b *BudgetConfig) Validate() error {
 func (b *BudgetConfig) String() string {
 	return fmt.Sprintf("tolocal=%v tolocalratio=%v anchorcpfp=%v "+
 		"anchorcpfpratio=%v deadlinehtlc=%v deadlinehtlcratio=%v "+
-		"nodeadlinehtlc=%v nodeadlinehtlcratio=%v",
+		"nodeadlinehtlc=%v nodeadlinehtlcratio=%v breach=%v "+
+		"breachratio=%v escalationratio=%v",
 		b.ToLocal, b.ToLocalRatio, b.AnchorCPFP, b.AnchorCPFPRatio,
 		b.DeadlineHTLC, b.DeadlineHTLCRatio, b.NoDeadlineHTLC,
-		b.NoDeadlineHTLCRatio)
+		b.NoDeadlineHTLCRatio, b.Breach, b.BreachRatio,
+		b.EscalationRatio)
 }
 
 // DefaultSweeperConfig returns the default configuration for the sweeper.
@@ -109,6 +136,8 @@ func DefaultBudgetConfig() *BudgetConfig {
 		AnchorCPFPRatio:     DefaultBudgetRatio,
 		DeadlineHTLCRatio:   DefaultBudgetRatio,
 		NoDeadlineHTLCRatio: DefaultBudgetRatio,
+		BreachRatio:         DefaultBudgetRatio,
+		EscalationRatio:     DefaultEscalationRatio,
 	}
 }
 
@@ -135,3 +164,10 @@ func calculateBudget(value btcutil.Amount, ratio float64,
 
 	return budget
 }
+
+// maxBudget returns the budget that a time-sensitive output of the given value
+// may spend once its deadline is reached. If the escalation is disabled, zero
+// is returned.
+func (b *BudgetConfig) maxBudget(value btcutil.Amount) btcutil.Amount {
+	return value.MulF64(b.EscalationRatio)
+}
@@ -67,6 +67,23 @@ func TestBudgetConfigValidate(t *testing.T) {
 			cfg:            &BudgetConfig{NoDeadlineHTLCRatio: -1},
 			expectedErrStr: "nodeadlinehtlcratio",
 		},
+
+		{
+			name:           "invalid breach",
+			cfg:            &BudgetConfig{Breach: -1},
+			expectedErrStr: "breach",
+		},
+		{
+			name:           "invalid breachratio",
+			cfg:            &BudgetConfig{BreachRatio: -1},
+			expectedErrStr: "breachratio",
+		},
+
+		{
+			name:           "invalid escalationratio",
+			cfg:            &BudgetConfig{EscalationRatio: 1.5},
+			expectedErrStr: "escalationratio",
+		},
 	}
 
 	for _, tc := range testCases {
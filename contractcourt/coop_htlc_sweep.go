@@ -0,0 +1,658 @@
+package contractcourt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// MsgCoopHtlcSweepRequest is the custom message type of a request to
+	// the peer to co-sign a tx that sweeps an expired HTLC output from our
+	// confirmed commitment directly into our wallet.
+	MsgCoopHtlcSweepRequest = lnwire.CustomTypeStart + 600
+
+	// MsgCoopHtlcSweepResponse is the custom message type of the response
+	// to a MsgCoopHtlcSweepRequest, which carries the signature of the
+	// peer, or no signature if the peer refused to sign.
+	MsgCoopHtlcSweepResponse = lnwire.CustomTypeStart + 601
+
+	// coopSweepTimeout is the time we wait for the peer to respond to a
+	// cooperative sweep request before falling back to the second-level
+	// timeout tx.
+	coopSweepTimeout = time.Minute
+
+	// coopSweepConfTarget is the conf target used to estimate the fee
+	// rate of a cooperative HTLC sweep.
+	coopSweepConfTarget = 6
+
+	// maxReleasedHtlcs is the max number of expired HTLCs of the remote
+	// commitments that we remember to co-sign sweeps for.
+	maxReleasedHtlcs = 1000
+
+	// maxCoopSweepTxSize is the max size of a serialized sweep tx we
+	// accept in a cooperative sweep request.
+	maxCoopSweepTxSize = 1000
+
+	// maxCoopSweepSigSize is the max size of a DER encoded signature.
+	maxCoopSweepSigSize = 73
+)
+
+var (
+	// errCoopSweepRejected is returned when the peer refused to co-sign a
+	// cooperative HTLC sweep.
+	errCoopSweepRejected = errors.New("peer rejected cooperative htlc " +
+		"sweep")
+
+	// errCoopSweepTimeout is returned when the peer didn't respond to a
+	// cooperative HTLC sweep request in time.
+	errCoopSweepTimeout = errors.New("cooperative htlc sweep request " +
+		"timed out")
+)
+
+// coopSweepRequest is a request to the peer to co-sign the direct sweep of
+// an expired HTLC output of our commitment.
+type coopSweepRequest struct {
+	// ChanPoint is the funding outpoint of the closed channel.
+	ChanPoint wire.OutPoint
+
+	// SweepTx is the sweep tx that spends the HTLC output as its only
+	// input.
+	SweepTx *wire.MsgTx
+}
+
+// Encode serializes the request to the passed writer.
+func (r *coopSweepRequest) Encode(w io.Writer) error {
+	if err := channeldb.WriteElements(w, r.ChanPoint); err != nil {
+		return err
+	}
+
+	var txBuf bytes.Buffer
+	if err := r.SweepTx.Serialize(&txBuf); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, txBuf.Bytes())
+}
+
+// Decode deserializes the request from the passed reader.
+func (r *coopSweepRequest) Decode(rd io.Reader) error {
+	if err := channeldb.ReadElements(rd, &r.ChanPoint); err != nil {
+		return err
+	}
+
+	txBytes, err := wire.ReadVarBytes(
+		rd, 0, maxCoopSweepTxSize, "sweep tx",
+	)
+	if err != nil {
+		return err
+	}
+
+	r.SweepTx = &wire.MsgTx{}
+
+	return r.SweepTx.Deserialize(bytes.NewReader(txBytes))
+}
+
+// coopSweepResponse is the response of the peer to a coopSweepRequest.
+type coopSweepResponse struct {
+	// HtlcOutPoint is the HTLC output the request was for.
+	HtlcOutPoint wire.OutPoint
+
+	// Sig is the DER encoded signature of the peer for the sweep tx,
+	// which is empty if the peer refused to sign.
+	Sig []byte
+}
+
+// Encode serializes the response to the passed writer.
+func (r *coopSweepResponse) Encode(w io.Writer) error {
+	if err := channeldb.WriteElements(w, r.HtlcOutPoint); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, r.Sig)
+}
+
+// Decode deserializes the response from the passed reader.
+func (r *coopSweepResponse) Decode(rd io.Reader) error {
+	if err := channeldb.ReadElements(rd, &r.HtlcOutPoint); err != nil {
+		return err
+	}
+
+	var err error
+	r.Sig, err = wire.ReadVarBytes(rd, 0, maxCoopSweepSigSize, "sig")
+
+	return err
+}
+
+// releasedHtlc is an expired HTLC of a remote commitment that we gave up on,
+// which we're willing to co-sign a direct sweep for.
+type releasedHtlc struct {
+	// chanPoint is the funding outpoint of the channel.
+	chanPoint wire.OutPoint
+
+	// signDesc describes how we sign for the HTLC output.
+	signDesc input.SignDescriptor
+}
+
+// coopSweepState holds the state of the cooperative HTLC sweeps of the
+// chain arbitrator.
+type coopSweepState struct {
+	sync.Mutex
+
+	// released are the expired HTLCs that we're willing to co-sign a
+	// sweep for, keyed by their outpoint.
+	released map[wire.OutPoint]*releasedHtlc
+
+	// releasedOrder holds the outpoints of the released HTLCs, oldest
+	// first, so the oldest can be forgotten once there are too many.
+	releasedOrder []wire.OutPoint
+
+	// pending holds the requests that we're waiting for a response to,
+	// keyed by the outpoint of the HTLC.
+	pending map[wire.OutPoint]*pendingCoopSweep
+}
+
+// pendingCoopSweep is a cooperative sweep request that we're waiting for a
+// response to.
+type pendingCoopSweep struct {
+	// peer is the peer the request was sent to.
+	peer [33]byte
+
+	// respChan receives the response of the peer.
+	respChan chan *coopSweepResponse
+}
+
+// newCoopSweepState creates a new, empty coopSweepState.
+func newCoopSweepState() *coopSweepState {
+	return &coopSweepState{
+		released: make(map[wire.OutPoint]*releasedHtlc),
+		pending:  make(map[wire.OutPoint]*pendingCoopSweep),
+	}
+}
+
+// HandleCustomMessage handles the custom messages of the cooperative HTLC
+// sweep protocol. It returns false if the message isn't part of the protocol
+// or cooperative HTLC sweeps are disabled, in which case the message should
+// be handled elsewhere.
+func (c *ChainArbitrator) HandleCustomMessage(peer [33]byte,
+	msg *lnwire.Custom) bool {
+
+	if !c.cfg.CoopHtlcSweep {
+		return false
+	}
+
+	switch msg.Type {
+	case MsgCoopHtlcSweepRequest:
+		var req coopSweepRequest
+		err := req.Decode(bytes.NewReader(msg.Data))
+		if err != nil {
+			log.Warnf("Unable to decode cooperative htlc sweep "+
+				"request from %x: %v", peer, err)
+
+			return true
+		}
+
+		// Signing and replying may take a while, so we don't block
+		// the read handler of the peer.
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+
+			c.handleCoopSweepRequest(peer, &req)
+		}()
+
+	case MsgCoopHtlcSweepResponse:
+		var resp coopSweepResponse
+		err := resp.Decode(bytes.NewReader(msg.Data))
+		if err != nil {
+			log.Warnf("Unable to decode cooperative htlc sweep "+
+				"response from %x: %v", peer, err)
+
+			return true
+		}
+
+		c.coopSweeps.Lock()
+		pending, ok := c.coopSweeps.pending[resp.HtlcOutPoint]
+		c.coopSweeps.Unlock()
+
+		if !ok || pending.peer != peer {
+			log.Debugf("Ignoring unexpected cooperative htlc "+
+				"sweep response for %v", resp.HtlcOutPoint)
+
+			return true
+		}
+
+		select {
+		case pending.respChan <- &resp:
+		default:
+		}
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// handleCoopSweepRequest signs the sweep tx of the passed request if it
+// spends an expired HTLC of the peer that we gave up on, and sends the
+// response back to the peer.
+func (c *ChainArbitrator) handleCoopSweepRequest(peer [33]byte,
+	req *coopSweepRequest) {
+
+	var resp coopSweepResponse
+	if len(req.SweepTx.TxIn) > 0 {
+		resp.HtlcOutPoint = req.SweepTx.TxIn[0].PreviousOutPoint
+	}
+
+	sig, err := c.signCoopSweep(peer, req)
+	if err != nil {
+		log.Infof("Refusing to co-sign sweep of htlc %v of "+
+			"ChannelPoint(%v) for %x: %v", resp.HtlcOutPoint,
+			req.ChanPoint, peer, err)
+	} else {
+		log.Infof("Co-signed sweep tx %v of htlc %v of "+
+			"ChannelPoint(%v) for %x", req.SweepTx.TxHash(),
+			resp.HtlcOutPoint, req.ChanPoint, peer)
+
+		resp.Sig = sig.Serialize()
+	}
+
+	var b bytes.Buffer
+	if err := resp.Encode(&b); err != nil {
+		log.Errorf("Unable to encode cooperative htlc sweep "+
+			"response: %v", err)
+
+		return
+	}
+
+	err = c.cfg.SendCustomMessage(peer, MsgCoopHtlcSweepResponse, b.Bytes())
+	if err != nil {
+		log.Warnf("Unable to send cooperative htlc sweep response to "+
+			"%x: %v", peer, err)
+	}
+}
+
+// signCoopSweep returns our signature for the sweep tx of the passed request.
+// We only sign sweeps of HTLCs that we released, which are the expired HTLCs
+// on the remote commitment that we can no longer claim, and only for the peer
+// of the channel.
+func (c *ChainArbitrator) signCoopSweep(peer [33]byte,
+	req *coopSweepRequest) (input.Signature, error) {
+
+	if len(req.SweepTx.TxIn) != 1 {
+		return nil, fmt.Errorf("sweep tx has %d inputs, expected 1",
+			len(req.SweepTx.TxIn))
+	}
+	op := req.SweepTx.TxIn[0].PreviousOutPoint
+
+	c.coopSweeps.Lock()
+	htlc, ok := c.coopSweeps.released[op]
+	c.coopSweeps.Unlock()
+
+	if !ok || htlc.chanPoint != req.ChanPoint {
+		return nil, fmt.Errorf("htlc %v is not released", op)
+	}
+
+	chanStateDB := c.chanSource.ChannelStateDB()
+	channel, err := chanStateDB.FetchHistoricalChannel(&req.ChanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var chanPeer [33]byte
+	copy(chanPeer[:], channel.IdentityPub.SerializeCompressed())
+	if chanPeer != peer {
+		return nil, fmt.Errorf("channel is not with peer")
+	}
+
+	signDesc := coopSweepSignDesc(&htlc.signDesc, req.SweepTx)
+
+	return c.cfg.Signer.SignOutputRaw(req.SweepTx, signDesc)
+}
+
+// releaseExpiredHtlc records an expired HTLC on the remote commitment of the
+// given channel that we gave up on, so we co-sign a direct sweep of it when
+// the peer asks for one.
+func (c *ChainArbitrator) releaseExpiredHtlc(chanPoint wire.OutPoint,
+	op wire.OutPoint, signDesc *input.SignDescriptor) {
+
+	if !c.cfg.CoopHtlcSweep {
+		return
+	}
+
+	c.coopSweeps.Lock()
+	defer c.coopSweeps.Unlock()
+
+	if _, ok := c.coopSweeps.released[op]; ok {
+		return
+	}
+
+	c.coopSweeps.released[op] = &releasedHtlc{
+		chanPoint: chanPoint,
+		signDesc:  *signDesc,
+	}
+	c.coopSweeps.releasedOrder = append(c.coopSweeps.releasedOrder, op)
+
+	if len(c.coopSweeps.releasedOrder) > maxReleasedHtlcs {
+		delete(c.coopSweeps.released, c.coopSweeps.releasedOrder[0])
+		c.coopSweeps.releasedOrder = c.coopSweeps.releasedOrder[1:]
+	}
+}
+
+// requestCoopSweepSig asks the peer of the given closed channel to sign the
+// passed sweep tx, which spends an expired HTLC output of our commitment.
+func (c *ChainArbitrator) requestCoopSweepSig(chanPoint wire.OutPoint,
+	sweepTx *wire.MsgTx) (input.Signature, error) {
+
+	chanStateDB := c.chanSource.ChannelStateDB()
+	channel, err := chanStateDB.FetchHistoricalChannel(&chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var peer [33]byte
+	copy(peer[:], channel.IdentityPub.SerializeCompressed())
+
+	op := sweepTx.TxIn[0].PreviousOutPoint
+	respChan := make(chan *coopSweepResponse, 1)
+
+	c.coopSweeps.Lock()
+	c.coopSweeps.pending[op] = &pendingCoopSweep{
+		peer:     peer,
+		respChan: respChan,
+	}
+	c.coopSweeps.Unlock()
+
+	defer func() {
+		c.coopSweeps.Lock()
+		delete(c.coopSweeps.pending, op)
+		c.coopSweeps.Unlock()
+	}()
+
+	req := coopSweepRequest{
+		ChanPoint: chanPoint,
+		SweepTx:   sweepTx,
+	}
+
+	var b bytes.Buffer
+	if err := req.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	err = c.cfg.SendCustomMessage(peer, MsgCoopHtlcSweepRequest, b.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-respChan:
+		if len(resp.Sig) == 0 {
+			return nil, errCoopSweepRejected
+		}
+
+		return ecdsa.ParseDERSignature(resp.Sig)
+
+	case <-time.After(coopSweepTimeout):
+		return nil, errCoopSweepTimeout
+
+	case <-c.quit:
+		return nil, ErrChainArbExiting
+	}
+}
+
+// coopSweepSignDesc returns a copy of the passed sign descriptor of an HTLC
+// output that signs the given single input sweep tx with SIGHASH_ALL.
+func coopSweepSignDesc(signDesc *input.SignDescriptor,
+	sweepTx *wire.MsgTx) *input.SignDescriptor {
+
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		signDesc.Output.PkScript, signDesc.Output.Value,
+	)
+
+	desc := *signDesc
+	desc.HashType = txscript.SigHashAll
+	desc.InputIndex = 0
+	desc.PrevOutputFetcher = prevOutFetcher
+	desc.SigHashes = txscript.NewTxSigHashes(sweepTx, prevOutFetcher)
+
+	return &desc
+}
+
+// releaseExpiredHtlc hands the HTLC output to the chain arbitrator once we
+// gave up on an expired HTLC on the remote commitment, so the remote party
+// can sweep it directly with our help rather than via its second-level
+// timeout tx.
+func (h *htlcIncomingContestResolver) releaseExpiredHtlc() {
+	// Only HTLCs on the remote commitment are swept via the second-level
+	// by the remote party, and we only support the segwit v0 scripts.
+	if !h.CoopHtlcSweep || h.ReleaseExpiredHtlc == nil ||
+		h.htlcResolution.SignedSuccessTx != nil ||
+		txscript.IsPayToTaproot(
+			h.htlcResolution.SweepSignDesc.Output.PkScript,
+		) {
+
+		return
+	}
+
+	h.ReleaseExpiredHtlc(
+		h.htlcResolution.ClaimOutpoint, &h.htlcResolution.SweepSignDesc,
+	)
+}
+
+// coopSweepHtlcOutput attempts to sweep the HTLC output of our commitment
+// directly into our wallet once it expired, using the signature of the peer
+// for the multisig timeout path instead of the second-level timeout tx. This
+// saves the fees of the second-level tx and the CSV delay of its output. It
+// returns true if the sweep tx was published or the output was already spent,
+// otherwise the HTLC should be resolved via the second-level timeout tx.
+func (h *htlcTimeoutResolver) coopSweepHtlcOutput() (bool, error) {
+	if !h.CoopHtlcSweep || h.RequestCoopSweepSig == nil ||
+		h.htlcResolution.SignDetails == nil || h.isTaproot() {
+
+		return false, nil
+	}
+
+	// The peer only co-signs once the HTLC expired, as it could still
+	// claim it with the preimage before that. We give the peer one more
+	// block to give up on the HTLC.
+	spent, err := h.waitForExpiryOrSpend(h.htlcResolution.Expiry + 1)
+	if err != nil || spent {
+		return spent, err
+	}
+
+	sweepTx, signDesc, err := h.createCoopSweepTx()
+	if err != nil {
+		log.Warnf("%T(%v): unable to create cooperative sweep tx: %v",
+			h, h.htlcResolution.ClaimOutpoint, err)
+
+		return false, nil
+	}
+
+	log.Infof("%T(%v): requesting peer signature for cooperative sweep "+
+		"tx %v", h, h.htlcResolution.ClaimOutpoint, sweepTx.TxHash())
+
+	peerSig, err := h.RequestCoopSweepSig(sweepTx)
+	if err != nil {
+		log.Infof("%T(%v): cooperative sweep failed, using "+
+			"second-level timeout tx: %v", h,
+			h.htlcResolution.ClaimOutpoint, err)
+
+		return false, nil
+	}
+
+	witness, err := input.SenderHtlcSpendTimeout(
+		peerSig, txscript.SigHashAll, h.Signer, signDesc, sweepTx,
+	)
+	if err != nil {
+		return false, err
+	}
+	sweepTx.TxIn[0].Witness = witness
+
+	// Make sure the peer signed for the right key before we publish.
+	vm, err := txscript.NewEngine(
+		signDesc.Output.PkScript, sweepTx, 0,
+		txscript.StandardVerifyFlags, nil, signDesc.SigHashes,
+		signDesc.Output.Value, signDesc.PrevOutputFetcher,
+	)
+	if err == nil {
+		err = vm.Execute()
+	}
+	if err != nil {
+		log.Warnf("%T(%v): invalid cooperative sweep tx, using "+
+			"second-level timeout tx: %v", h,
+			h.htlcResolution.ClaimOutpoint, err)
+
+		return false, nil
+	}
+
+	label := labels.MakeLabel(
+		labels.LabelTypeSweepTransaction, &h.ShortChanID,
+	)
+	if err := h.PublishTx(sweepTx, label); err != nil {
+		log.Warnf("%T(%v): unable to publish cooperative sweep tx, "+
+			"using second-level timeout tx: %v", h,
+			h.htlcResolution.ClaimOutpoint, err)
+
+		return false, nil
+	}
+
+	log.Infof("%T(%v): published cooperative sweep tx %v", h,
+		h.htlcResolution.ClaimOutpoint, sweepTx.TxHash())
+
+	return true, nil
+}
+
+// waitForExpiryOrSpend waits until the given height is reached, or the HTLC
+// output is spent, which is the case if the peer claimed it with the preimage
+// in the meantime. It returns true if the output was spent.
+func (h *htlcTimeoutResolver) waitForExpiryOrSpend(height uint32) (bool,
+	error) {
+
+	op, pkScript, err := h.chainDetailsToWatch()
+	if err != nil {
+		return false, err
+	}
+
+	spendNtfn, err := h.Notifier.RegisterSpendNtfn(
+		op, pkScript, h.broadcastHeight,
+	)
+	if err != nil {
+		return false, err
+	}
+	defer spendNtfn.Cancel()
+
+	blockEpochs, err := h.Notifier.RegisterBlockEpochNtfn(nil)
+	if err != nil {
+		return false, err
+	}
+	defer blockEpochs.Cancel()
+
+	for {
+		select {
+		case _, ok := <-spendNtfn.Spend:
+			if !ok {
+				return false, errResolverShuttingDown
+			}
+
+			return true, nil
+
+		case newBlock, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return false, errResolverShuttingDown
+			}
+
+			if uint32(newBlock.Height) >= height {
+				return false, nil
+			}
+
+		case <-h.quit:
+			return false, errResolverShuttingDown
+		}
+	}
+}
+
+// createCoopSweepTx creates the unsigned tx that sweeps the HTLC output of our
+// commitment directly into our wallet, paying the fees from the output. It
+// also returns the sign descriptor we sign the tx with.
+func (h *htlcTimeoutResolver) createCoopSweepTx() (*wire.MsgTx,
+	*input.SignDescriptor, error) {
+
+	htlcSignDesc := &h.htlcResolution.SignDetails.SignDesc
+	timeoutTx := h.htlcResolution.SignedTimeoutTx
+
+	pkScript, err := h.NewSweepAddr()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	feeRate, err := h.FeeEstimator.EstimateFeePerKW(coopSweepConfTarget)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(
+		input.OfferedHtlcTimeoutWitnessSizeConfirmed,
+	)
+	weightEstimate.AddOutput(pkScript)
+
+	fee := feeRate.FeeForWeight(weightEstimate.Weight())
+	value := btcutil.Amount(htlcSignDesc.Output.Value) - fee
+	if value < lnwallet.DustLimitForSize(len(pkScript)) {
+		return nil, nil, fmt.Errorf("htlc output of %v can't pay fee "+
+			"of %v", btcutil.Amount(htlcSignDesc.Output.Value), fee)
+	}
+
+	// The HTLC output requires the same sequence as the second-level
+	// timeout tx that spends it.
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: timeoutTx.TxIn[0].PreviousOutPoint,
+		Sequence:         timeoutTx.TxIn[0].Sequence,
+	})
+	sweepTx.AddTxOut(&wire.TxOut{
+		PkScript: pkScript,
+		Value:    int64(value),
+	})
+
+	return sweepTx, coopSweepSignDesc(htlcSignDesc, sweepTx), nil
+}
+
+// isCoopSweep returns true if the passed spend of the HTLC output of our
+// commitment is a cooperative sweep rather than the second-level timeout tx,
+// which pays to the second-level output script. Only resolvers that may
+// attempt a cooperative sweep can see one.
+func (h *htlcTimeoutResolver) isCoopSweep(
+	spend *chainntnfs.SpendDetail) bool {
+
+	if !h.CoopHtlcSweep || h.htlcResolution.SignDetails == nil ||
+		h.isTaproot() {
+
+		return false
+	}
+
+	index := spend.SpenderInputIndex
+	if int(index) >= len(spend.SpendingTx.TxOut) {
+		return true
+	}
+
+	return !bytes.Equal(
+		spend.SpendingTx.TxOut[index].PkScript,
+		h.htlcResolution.SweepSignDesc.Output.PkScript,
+	)
+}
@@ -0,0 +1,242 @@
+package contractcourt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCoopSweepMessageEncoding asserts that the messages of the cooperative
+// HTLC sweep protocol survive an encoding roundtrip.
+func TestCoopSweepMessageEncoding(t *testing.T) {
+	t.Parallel()
+
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 2},
+		SignatureScript:  []byte{},
+		Sequence:         1,
+	})
+	sweepTx.AddTxOut(&wire.TxOut{
+		PkScript: bytes.Repeat([]byte{1}, 22),
+		Value:    1000,
+	})
+
+	req := coopSweepRequest{
+		ChanPoint: wire.OutPoint{Index: 1},
+		SweepTx:   sweepTx,
+	}
+
+	var b bytes.Buffer
+	require.NoError(t, req.Encode(&b))
+
+	var decodedReq coopSweepRequest
+	require.NoError(t, decodedReq.Decode(&b))
+	require.Equal(t, req, decodedReq)
+
+	resp := coopSweepResponse{
+		HtlcOutPoint: wire.OutPoint{Index: 2},
+		Sig:          bytes.Repeat([]byte{2}, 71),
+	}
+
+	b.Reset()
+	require.NoError(t, resp.Encode(&b))
+
+	var decodedResp coopSweepResponse
+	require.NoError(t, decodedResp.Decode(&b))
+	require.Equal(t, resp, decodedResp)
+}
+
+// TestCoopHtlcSweep asserts that an expired HTLC output of a local force
+// close can be swept directly into our wallet with the signature of the peer,
+// and that the peer only co-signs the sweeps of released HTLCs.
+func TestCoopHtlcSweep(t *testing.T) {
+	t.Parallel()
+
+	chanType := channeldb.SingleFunderTweaklessBit |
+		channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit
+
+	alice, bob, err := lnwallet.CreateTestChannels(t, chanType)
+	require.NoError(t, err, "unable to create test channels")
+
+	htlcAmount := lnwire.NewMSatFromSatoshis(100_000)
+	htlc, _ := createHTLC(0, htlcAmount)
+	_, err = alice.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bob.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, lnwallet.ForceStateTransition(alice, bob))
+
+	// Alice force closes, so the HTLC she offered can only be timed out
+	// via the second-level timeout tx.
+	aliceClose, err := alice.ForceClose()
+	require.NoError(t, err, "unable to force close alice's channel")
+	require.Len(t, aliceClose.HtlcResolutions.OutgoingHTLCs, 1)
+
+	outRes := aliceClose.HtlcResolutions.OutgoingHTLCs[0]
+	require.NotNil(t, outRes.SignDetails)
+
+	closeTx := aliceClose.CloseTx
+	closeTxid := closeTx.TxHash()
+	bobClose, err := lnwallet.NewUnilateralCloseSummary(
+		bob.State(), bob.Signer, &chainntnfs.SpendDetail{
+			SpendingTx:    closeTx,
+			SpenderTxHash: &closeTxid,
+		},
+		bob.State().RemoteCommitment,
+		bob.State().RemoteCurrentRevocation,
+		fn.Some[lnwallet.AuxLeafStore](&lnwallet.MockAuxLeafStore{}),
+		fn.Some[lnwallet.AuxContractResolver](
+			&lnwallet.MockAuxContractResolver{},
+		),
+	)
+	require.NoError(t, err, "unable to create bob's close summary")
+	require.Len(t, bobClose.HtlcResolutions.IncomingHTLCs, 1)
+
+	inRes := bobClose.HtlcResolutions.IncomingHTLCs[0]
+	require.Equal(t, outRes.HtlcPoint(), inRes.ClaimOutpoint)
+
+	// Bob only signs for closed channels with Alice.
+	state := bob.State()
+	err = state.CloseChannel(&channeldb.ChannelCloseSummary{
+		ChanPoint:   state.FundingOutpoint,
+		ChainHash:   state.ChainHash,
+		RemotePub:   state.IdentityPub,
+		CloseType:   channeldb.RemoteForceClose,
+		Capacity:    state.Capacity,
+		IsPending:   true,
+		ShortChanID: state.ShortChanID(),
+	})
+	require.NoError(t, err, "unable to close bob's channel")
+
+	bobArb := NewChainArbitrator(ChainArbitratorConfig{
+		CoopHtlcSweep: true,
+		Signer:        bob.Signer,
+	}, state.Db.GetParentDB())
+
+	var alicePub [33]byte
+	copy(alicePub[:], state.IdentityPub.SerializeCompressed())
+
+	chanPoint := state.FundingOutpoint
+	sweepScript := append(
+		[]byte{txscript.OP_0, txscript.OP_DATA_20},
+		bytes.Repeat([]byte{1}, 20)...,
+	)
+
+	var published []*wire.MsgTx
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch, 1),
+		SpendChan: make(chan *chainntnfs.SpendDetail, 1),
+	}
+	resCfg := ResolverConfig{
+		ChannelArbitratorConfig: ChannelArbitratorConfig{
+			ChanPoint: chanPoint,
+			ChainArbitratorConfig: ChainArbitratorConfig{
+				CoopHtlcSweep: true,
+				Notifier:      notifier,
+				Signer:        alice.Signer,
+				FeeEstimator: chainfee.NewStaticEstimator(
+					12_500, 0,
+				),
+				NewSweepAddr: func() ([]byte, error) {
+					return sweepScript, nil
+				},
+				PublishTx: func(tx *wire.MsgTx,
+					_ string) error {
+
+					published = append(published, tx)
+					return nil
+				},
+			},
+			RequestCoopSweepSig: func(
+				tx *wire.MsgTx) (input.Signature, error) {
+
+				return bobArb.signCoopSweep(
+					alicePub, &coopSweepRequest{
+						ChanPoint: chanPoint,
+						SweepTx:   tx,
+					},
+				)
+			},
+		},
+	}
+	resolver := newTimeoutResolver(
+		outRes, 0, channeldb.HTLC{
+			RHash:         htlc.PaymentHash,
+			Amt:           htlcAmount,
+			RefundTimeout: outRes.Expiry,
+		}, resCfg,
+	)
+
+	// If the HTLC output is spent before it expires, we don't attempt a
+	// cooperative sweep.
+	notifier.SpendChan <- &chainntnfs.SpendDetail{}
+	swept, err := resolver.coopSweepHtlcOutput()
+	require.NoError(t, err)
+	require.True(t, swept)
+	require.Empty(t, published)
+
+	// Once the HTLC expired, we ask Bob to co-sign. He refuses as he
+	// didn't give up on the HTLC yet, so we fall back to the second-level
+	// timeout tx.
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{
+		Height: int32(outRes.Expiry + 1),
+	}
+	swept, err = resolver.coopSweepHtlcOutput()
+	require.NoError(t, err)
+	require.False(t, swept)
+	require.Empty(t, published)
+
+	// Once Bob released the HTLC, he co-signs the sweep, which is valid
+	// and pays directly into our wallet.
+	bobArb.releaseExpiredHtlc(
+		chanPoint, inRes.ClaimOutpoint, &inRes.SweepSignDesc,
+	)
+
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{
+		Height: int32(outRes.Expiry + 1),
+	}
+	swept, err = resolver.coopSweepHtlcOutput()
+	require.NoError(t, err)
+	require.True(t, swept)
+	require.Len(t, published, 1)
+
+	sweepTx := published[0]
+	require.Len(t, sweepTx.TxIn, 1)
+	require.Len(t, sweepTx.TxOut, 1)
+	require.Equal(t, sweepScript, sweepTx.TxOut[0].PkScript)
+
+	htlcOutput := outRes.SignDetails.SignDesc.Output
+	require.Less(t, sweepTx.TxOut[0].Value, htlcOutput.Value)
+
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	prevOuts.AddPrevOut(outRes.HtlcPoint(), htlcOutput)
+	assertValidSpend(t, sweepTx, prevOuts)
+
+	// The spend is recognized as a cooperative sweep rather than the
+	// second-level timeout tx.
+	require.True(t, resolver.isCoopSweep(&chainntnfs.SpendDetail{
+		SpendingTx: sweepTx,
+	}))
+	require.False(t, resolver.isCoopSweep(&chainntnfs.SpendDetail{
+		SpendingTx: outRes.SignedTimeoutTx,
+	}))
+
+	// Bob doesn't co-sign for anyone but Alice.
+	_, err = bobArb.signCoopSweep([33]byte{2}, &coopSweepRequest{
+		ChanPoint: chanPoint,
+		SweepTx:   sweepTx,
+	})
+	require.Error(t, err)
+}
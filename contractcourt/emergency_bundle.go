@@ -0,0 +1,411 @@
+package contractcourt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnencrypt"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+)
+
+const (
+	// emergencyBundleVersion is the current version of the serialized
+	// emergency close bundle.
+	emergencyBundleVersion = 0
+
+	// maxEmergencyBundleItems is the max number of channels and PSBTs we
+	// accept when decoding a bundle, to guard against corrupted data.
+	maxEmergencyBundleItems = 100_000
+
+	// maxEmergencyBundleItemSize is the max size of a serialized tx or
+	// PSBT we accept when decoding a bundle.
+	maxEmergencyBundleItemSize = 1_000_000
+)
+
+// EmergencyCloseEntry holds the latest force close tx of a channel together
+// with the PSBTs that sweep its outputs to us.
+type EmergencyCloseEntry struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// CommitHeight is the height of the commitment the close tx was
+	// created from.
+	CommitHeight uint64
+
+	// CloseTx is our fully signed latest commitment tx.
+	CloseTx *wire.MsgTx
+
+	// SweepPsbts are the unsigned PSBTs that spend our outputs of the
+	// close tx, such as the anchor and the to_local output. They don't
+	// have any outputs yet, which are added by the signing tool together
+	// with the fees. PSBTs are only created for the outputs of segwit v0
+	// channels, and not for HTLC outputs, which need second level txns.
+	SweepPsbts []*psbt.Packet
+}
+
+// EmergencyCloseBundle holds the latest force close txns of all active
+// channels, so the funds can be recovered from cold storage after a
+// catastrophic loss of the node.
+//
+// NOTE: the close txns are only valid until the channels are updated.
+// Broadcasting an outdated close tx allows the remote party to claim all the
+// funds of the channel, so the bundle must be exported again after each
+// channel update it's meant to cover.
+type EmergencyCloseBundle struct {
+	// Entries are the close txns of the channels.
+	Entries []*EmergencyCloseEntry
+}
+
+// EmergencyCloseBundle creates the emergency close bundle of all active
+// channels. The BIP32 derivation paths of the sweep PSBTs use the given HD
+// coin type.
+func (c *ChainArbitrator) EmergencyCloseBundle(
+	hdCoinType uint32) (*EmergencyCloseBundle, error) {
+
+	channels, err := c.chanSource.ChannelStateDB().FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &EmergencyCloseBundle{}
+	for _, channel := range channels {
+		// Skip the channels that are pending, closing or otherwise not
+		// in a state that can be force closed by us.
+		if channel.IsPending ||
+			channel.ChanStatus() != channeldb.ChanStatusDefault {
+
+			continue
+		}
+
+		entry, err := c.emergencyCloseEntry(channel, hdCoinType)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create close tx for "+
+				"ChannelPoint(%v): %w", channel.FundingOutpoint,
+				err)
+		}
+
+		bundle.Entries = append(bundle.Entries, entry)
+	}
+
+	return bundle, nil
+}
+
+// emergencyCloseEntry creates the emergency close entry of the given channel.
+// The channel state isn't modified.
+func (c *ChainArbitrator) emergencyCloseEntry(channel *channeldb.OpenChannel,
+	hdCoinType uint32) (*EmergencyCloseEntry, error) {
+
+	var chanOpts []lnwallet.ChannelOpt
+	c.cfg.AuxLeafStore.WhenSome(func(s lnwallet.AuxLeafStore) {
+		chanOpts = append(chanOpts, lnwallet.WithLeafStore(s))
+	})
+	c.cfg.AuxSigner.WhenSome(func(s lnwallet.AuxSigner) {
+		chanOpts = append(chanOpts, lnwallet.WithAuxSigner(s))
+	})
+	c.cfg.AuxResolver.WhenSome(func(s lnwallet.AuxContractResolver) {
+		chanOpts = append(chanOpts, lnwallet.WithAuxResolver(s))
+	})
+
+	// We use a fresh channel machine, so the closed flag set by the force
+	// close only applies to this instance.
+	chanMachine, err := lnwallet.NewLightningChannel(
+		c.cfg.Signer, channel, nil, chanOpts...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := chanMachine.ForceClose()
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &EmergencyCloseEntry{
+		ChanPoint:    channel.FundingOutpoint,
+		CommitHeight: channel.LocalCommitment.CommitHeight,
+		CloseTx:      summary.CloseTx,
+	}
+
+	// Taproot channels need script path data the signing tools can't
+	// reconstruct from a PSBT yet, so we only export the close tx.
+	if channel.ChanType.IsTaproot() {
+		return entry, nil
+	}
+
+	if anchor := summary.AnchorResolution; anchor != nil {
+		packet, err := emergencySweepPsbt(
+			anchor.CommitAnchor, &anchor.AnchorSignDescriptor,
+			wire.MaxTxInSequenceNum-2, 0, hdCoinType,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.SweepPsbts = append(entry.SweepPsbts, packet)
+	}
+
+	if commit := summary.CommitResolution; commit != nil {
+		var lockTime uint32
+		if channel.ChanType.HasLeaseExpiration() {
+			lockTime = channel.ThawHeight
+		}
+
+		packet, err := emergencySweepPsbt(
+			commit.SelfOutPoint, &commit.SelfOutputSignDesc,
+			commit.MaturityDelay, lockTime, hdCoinType,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.SweepPsbts = append(entry.SweepPsbts, packet)
+	}
+
+	return entry, nil
+}
+
+// emergencySweepPsbt creates an unsigned PSBT without outputs that spends the
+// given outpoint using the given sign descriptor. The key tweaks of the sign
+// descriptor are added using the custom PSBT keys understood by lnd.
+func emergencySweepPsbt(op wire.OutPoint, signDesc *input.SignDescriptor,
+	sequence, lockTime, hdCoinType uint32) (*psbt.Packet, error) {
+
+	packet, err := psbt.New(
+		[]*wire.OutPoint{&op}, nil, 2, lockTime, []uint32{sequence},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &packet.Inputs[0]
+	in.WitnessUtxo = signDesc.Output
+	in.WitnessScript = signDesc.WitnessScript
+	in.SighashType = signDesc.HashType
+	in.Bip32Derivation = []*psbt.Bip32Derivation{{
+		Bip32Path: []uint32{
+			keychain.BIP0043Purpose + hdkeychain.HardenedKeyStart,
+			hdCoinType + hdkeychain.HardenedKeyStart,
+			uint32(signDesc.KeyDesc.Family) +
+				hdkeychain.HardenedKeyStart,
+			0,
+			signDesc.KeyDesc.Index,
+		},
+		PubKey: signDesc.KeyDesc.PubKey.SerializeCompressed(),
+	}}
+
+	if len(signDesc.SingleTweak) > 0 {
+		in.Unknowns = append(in.Unknowns, &psbt.Unknown{
+			Key:   btcwallet.PsbtKeyTypeInputSignatureTweakSingle,
+			Value: signDesc.SingleTweak,
+		})
+	}
+	if signDesc.DoubleTweak != nil {
+		in.Unknowns = append(in.Unknowns, &psbt.Unknown{
+			Key:   btcwallet.PsbtKeyTypeInputSignatureTweakDouble,
+			Value: signDesc.DoubleTweak.Serialize(),
+		})
+	}
+
+	return packet, nil
+}
+
+// Encode serializes the bundle and writes it to the given writer encrypted
+// with the channel backup key of the given key ring, so it can be decrypted
+// with the seed of the node.
+func (b *EmergencyCloseBundle) Encode(w io.Writer,
+	keyRing keychain.KeyRing) error {
+
+	var buf bytes.Buffer
+	if err := b.serialize(&buf); err != nil {
+		return err
+	}
+
+	e, err := lnencrypt.KeyRingEncrypter(keyRing)
+	if err != nil {
+		return fmt.Errorf("unable to create encrypter: %w", err)
+	}
+
+	return e.EncryptPayloadToWriter(buf.Bytes(), w)
+}
+
+// DecodeEmergencyCloseBundle reads an encrypted bundle from the given reader
+// and decrypts it with the channel backup key of the given key ring.
+func DecodeEmergencyCloseBundle(r io.Reader,
+	keyRing keychain.KeyRing) (*EmergencyCloseBundle, error) {
+
+	e, err := lnencrypt.KeyRingEncrypter(keyRing)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create encrypter: %w", err)
+	}
+
+	plaintext, err := e.DecryptPayloadFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return deserializeEmergencyBundle(bytes.NewReader(plaintext))
+}
+
+// serialize writes the plaintext bundle to the given writer.
+func (b *EmergencyCloseBundle) serialize(w io.Writer) error {
+	if _, err := w.Write([]byte{emergencyBundleVersion}); err != nil {
+		return err
+	}
+
+	if err := wire.WriteVarInt(w, 0, uint64(len(b.Entries))); err != nil {
+		return err
+	}
+
+	for _, entry := range b.Entries {
+		if err := entry.serialize(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serialize writes the entry to the given writer.
+func (e *EmergencyCloseEntry) serialize(w io.Writer) error {
+	if err := channeldb.WriteElements(w, e.ChanPoint); err != nil {
+		return err
+	}
+
+	err := binary.Write(w, binary.BigEndian, e.CommitHeight)
+	if err != nil {
+		return err
+	}
+
+	var txBuf bytes.Buffer
+	if err := e.CloseTx.Serialize(&txBuf); err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, txBuf.Bytes()); err != nil {
+		return err
+	}
+
+	err = wire.WriteVarInt(w, 0, uint64(len(e.SweepPsbts)))
+	if err != nil {
+		return err
+	}
+
+	for _, packet := range e.SweepPsbts {
+		var psbtBuf bytes.Buffer
+		if err := packet.Serialize(&psbtBuf); err != nil {
+			return err
+		}
+
+		err := wire.WriteVarBytes(w, 0, psbtBuf.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deserializeEmergencyBundle reads a plaintext bundle from the given reader.
+func deserializeEmergencyBundle(r io.Reader) (*EmergencyCloseBundle, error) {
+	var version [1]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return nil, err
+	}
+	if version[0] != emergencyBundleVersion {
+		return nil, fmt.Errorf("unknown emergency bundle version: %v",
+			version[0])
+	}
+
+	numEntries, err := readBundleCount(r)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &EmergencyCloseBundle{
+		Entries: make([]*EmergencyCloseEntry, 0, numEntries),
+	}
+	for i := uint64(0); i < numEntries; i++ {
+		entry, err := deserializeEmergencyEntry(r)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle.Entries = append(bundle.Entries, entry)
+	}
+
+	return bundle, nil
+}
+
+// deserializeEmergencyEntry reads an entry from the given reader.
+func deserializeEmergencyEntry(r io.Reader) (*EmergencyCloseEntry, error) {
+	entry := &EmergencyCloseEntry{}
+	if err := channeldb.ReadElements(r, &entry.ChanPoint); err != nil {
+		return nil, err
+	}
+
+	err := binary.Read(r, binary.BigEndian, &entry.CommitHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	txBytes, err := wire.ReadVarBytes(
+		r, 0, maxEmergencyBundleItemSize, "close tx",
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.CloseTx = &wire.MsgTx{}
+	err = entry.CloseTx.Deserialize(bytes.NewReader(txBytes))
+	if err != nil {
+		return nil, err
+	}
+
+	numPsbts, err := readBundleCount(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := uint64(0); i < numPsbts; i++ {
+		psbtBytes, err := wire.ReadVarBytes(
+			r, 0, maxEmergencyBundleItemSize, "sweep psbt",
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		packet, err := psbt.NewFromRawBytes(
+			bytes.NewReader(psbtBytes), false,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.SweepPsbts = append(entry.SweepPsbts, packet)
+	}
+
+	return entry, nil
+}
+
+// readBundleCount reads a count of bundle items from the given reader.
+func readBundleCount(r io.Reader) (uint64, error) {
+	count, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	if count > maxEmergencyBundleItems {
+		return 0, fmt.Errorf("too many items in emergency bundle: %v",
+			count)
+	}
+
+	return count, nil
+}
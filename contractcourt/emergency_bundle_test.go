@@ -0,0 +1,127 @@
+package contractcourt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnencrypt"
+	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmergencySweepPsbt asserts that the sweep PSBTs of the emergency close
+// bundle carry the data needed to sign them.
+func TestEmergencySweepPsbt(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	op := wire.OutPoint{Index: 1}
+	signDesc := &input.SignDescriptor{
+		KeyDesc: keychain.KeyDescriptor{
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamilyDelayBase,
+				Index:  7,
+			},
+			PubKey: privKey.PubKey(),
+		},
+		SingleTweak:   []byte{1, 2, 3},
+		WitnessScript: []byte{txscript.OP_TRUE},
+		Output:        &wire.TxOut{Value: 1000, PkScript: []byte{0}},
+		HashType:      txscript.SigHashAll,
+	}
+
+	packet, err := emergencySweepPsbt(op, signDesc, 144, 10, 1)
+	require.NoError(t, err)
+
+	require.Len(t, packet.UnsignedTx.TxIn, 1)
+	require.Empty(t, packet.UnsignedTx.TxOut)
+	require.Equal(t, op, packet.UnsignedTx.TxIn[0].PreviousOutPoint)
+	require.EqualValues(t, 144, packet.UnsignedTx.TxIn[0].Sequence)
+	require.EqualValues(t, 10, packet.UnsignedTx.LockTime)
+
+	in := packet.Inputs[0]
+	require.Equal(t, signDesc.Output, in.WitnessUtxo)
+	require.Equal(t, signDesc.WitnessScript, in.WitnessScript)
+	require.Len(t, in.Bip32Derivation, 1)
+	require.Equal(t, []uint32{
+		keychain.BIP0043Purpose + hdkeychain.HardenedKeyStart,
+		1 + hdkeychain.HardenedKeyStart,
+		uint32(keychain.KeyFamilyDelayBase) +
+			hdkeychain.HardenedKeyStart,
+		0, 7,
+	}, in.Bip32Derivation[0].Bip32Path)
+	require.Equal(t, []*psbt.Unknown{{
+		Key:   btcwallet.PsbtKeyTypeInputSignatureTweakSingle,
+		Value: signDesc.SingleTweak,
+	}}, in.Unknowns)
+}
+
+// TestEmergencyCloseBundleEncoding asserts that an emergency close bundle can
+// be encrypted and decrypted again.
+func TestEmergencyCloseBundleEncoding(t *testing.T) {
+	t.Parallel()
+
+	closeTx := wire.NewMsgTx(2)
+	closeTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 2},
+		SignatureScript:  []byte{},
+	})
+	closeTx.AddTxOut(&wire.TxOut{Value: 1000, PkScript: []byte{0}})
+
+	packet, err := emergencySweepPsbt(
+		wire.OutPoint{Hash: closeTx.TxHash()}, &input.SignDescriptor{
+			KeyDesc: keychain.KeyDescriptor{
+				PubKey: testSignDesc.KeyDesc.PubKey,
+			},
+			Output: closeTx.TxOut[0],
+		}, 0, 0, 0,
+	)
+	require.NoError(t, err)
+
+	bundle := &EmergencyCloseBundle{
+		Entries: []*EmergencyCloseEntry{{
+			ChanPoint:    wire.OutPoint{Index: 3},
+			CommitHeight: 42,
+			CloseTx:      closeTx,
+			SweepPsbts:   []*psbt.Packet{packet},
+		}, {
+			ChanPoint: wire.OutPoint{Index: 4},
+			CloseTx:   closeTx,
+		}},
+	}
+
+	keyRing := &lnencrypt.MockKeyRing{}
+
+	var b bytes.Buffer
+	require.NoError(t, bundle.Encode(&b, keyRing))
+
+	decoded, err := DecodeEmergencyCloseBundle(&b, keyRing)
+	require.NoError(t, err)
+	require.Len(t, decoded.Entries, len(bundle.Entries))
+
+	for i, entry := range bundle.Entries {
+		decodedEntry := decoded.Entries[i]
+		require.Equal(t, entry.ChanPoint, decodedEntry.ChanPoint)
+		require.Equal(t, entry.CommitHeight, decodedEntry.CommitHeight)
+		require.Equal(t, entry.CloseTx, decodedEntry.CloseTx)
+		require.Len(t, decodedEntry.SweepPsbts, len(entry.SweepPsbts))
+
+		for j, packet := range entry.SweepPsbts {
+			decodedPacket := decodedEntry.SweepPsbts[j]
+
+			var expected, actual bytes.Buffer
+			require.NoError(t, packet.Serialize(&expected))
+			require.NoError(t, decodedPacket.Serialize(&actual))
+			require.Equal(t, expected.Bytes(), actual.Bytes())
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !monitoring
+// +build !monitoring
+
+package contractcourt
+
+import (
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+)
+
+// reportBlocksUntilForceClose is a no-op if lnd is built without the
+// monitoring tag.
+func reportBlocksUntilForceClose(_ wire.OutPoint, _ fn.Option[int32]) {}
@@ -0,0 +1,41 @@
+//go:build monitoring
+// +build monitoring
+
+package contractcourt
+
+import (
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// blocksUntilForceClose tracks the number of blocks until each channel is
+// force closed due to its outstanding HTLCs.
+var blocksUntilForceClose = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Namespace: "lnd",
+		Subsystem: "contractcourt",
+		Name:      "blocks_until_force_close",
+		Help: "Number of blocks until a channel is force closed " +
+			"unless its htlcs are resolved off-chain",
+	}, []string{"chan_point"},
+)
+
+func init() {
+	prometheus.MustRegister(blocksUntilForceClose)
+}
+
+// reportBlocksUntilForceClose updates the force close gauge of the given
+// channel. The gauge of the channel is removed if it has no HTLCs that force
+// it to be closed.
+func reportBlocksUntilForceClose(chanPoint wire.OutPoint,
+	blocks fn.Option[int32]) {
+
+	label := chanPoint.String()
+	blocks.WhenSome(func(b int32) {
+		blocksUntilForceClose.WithLabelValues(label).Set(float64(b))
+	})
+	if blocks.IsNone() {
+		blocksUntilForceClose.DeleteLabelValues(label)
+	}
+}
@@ -0,0 +1,65 @@
+package contractcourt
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ForceClosePolicy configures which expiring HTLCs make the channel
+// arbitrator go on-chain. The policy only decides whether a channel is force
+// closed, once it's closed all of its HTLCs are resolved on-chain as usual.
+type ForceClosePolicy struct {
+	// MinHtlcAmt is the min amount of an expiring HTLC to trigger a force
+	// close. Smaller HTLCs are only resolved on-chain if the channel is
+	// force closed for another reason.
+	MinHtlcAmt lnwire.MilliSatoshi
+
+	// DustHtlcDelay is the number of blocks by which the force close is
+	// delayed for expiring HTLCs that are dust on the commitment. These
+	// HTLCs can't be resolved on-chain, so going on-chain only serves to
+	// fail back their incoming HTLC.
+	DustHtlcDelay uint32
+}
+
+// forceCloseDelay returns the number of blocks by which the force close for
+// the given HTLC is delayed past its broadcast cutoff, or None if the HTLC
+// never triggers a force close.
+func (p *ForceClosePolicy) forceCloseDelay(
+	htlc channeldb.HTLC) fn.Option[uint32] {
+
+	if htlc.Amt < p.MinHtlcAmt {
+		return fn.None[uint32]()
+	}
+
+	// We know the HTLC is dust if its OutputIndex is negative.
+	if htlc.OutputIndex < 0 {
+		return fn.Some(p.DustHtlcDelay)
+	}
+
+	return fn.Some[uint32](0)
+}
+
+// triggersForceClose returns true if the given HTLC requires us to go
+// on-chain at the given height, taking into account the force close policy.
+func (c *ChannelArbitrator) triggersForceClose(htlc channeldb.HTLC,
+	broadcastDelta, height uint32) bool {
+
+	delayOpt := c.cfg.ForceClosePolicy.forceCloseDelay(htlc)
+	if delayOpt.IsNone() {
+		log.Tracef("ChannelArbitrator(%v): htlc=%x with amount=%v "+
+			"doesn't trigger a force close", c.cfg.ChanPoint,
+			htlc.RHash[:], htlc.Amt)
+
+		return false
+	}
+
+	// Delaying the force close is the same as evaluating the HTLC at an
+	// earlier height.
+	delay := delayOpt.UnwrapOr(0)
+	if height < delay {
+		return false
+	}
+
+	return c.shouldGoOnChain(htlc, broadcastDelta, height-delay)
+}
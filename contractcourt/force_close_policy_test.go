@@ -0,0 +1,120 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTriggersForceClose asserts that the force close policy decides which
+// expiring HTLCs make the channel arbitrator go on-chain.
+func TestTriggersForceClose(t *testing.T) {
+	t.Parallel()
+
+	const (
+		broadcastDelta = 10
+		refundTimeout  = 100
+		cutoff         = refundTimeout - broadcastDelta
+	)
+
+	testCases := []struct {
+		name     string
+		policy   ForceClosePolicy
+		htlc     channeldb.HTLC
+		height   uint32
+		expected bool
+	}{
+		{
+			name: "default policy before cutoff",
+			htlc: channeldb.HTLC{
+				Amt: 1000,
+			},
+			height:   cutoff - 1,
+			expected: false,
+		},
+		{
+			name: "default policy at cutoff",
+			htlc: channeldb.HTLC{
+				Amt: 1000,
+			},
+			height:   cutoff,
+			expected: true,
+		},
+		{
+			name: "htlc below min amount",
+			policy: ForceClosePolicy{
+				MinHtlcAmt: 1001,
+			},
+			htlc: channeldb.HTLC{
+				Amt: 1000,
+			},
+			height:   refundTimeout,
+			expected: false,
+		},
+		{
+			name: "htlc at min amount",
+			policy: ForceClosePolicy{
+				MinHtlcAmt: 1000,
+			},
+			htlc: channeldb.HTLC{
+				Amt: 1000,
+			},
+			height:   cutoff,
+			expected: true,
+		},
+		{
+			name: "dust htlc delayed",
+			policy: ForceClosePolicy{
+				DustHtlcDelay: 5,
+			},
+			htlc: channeldb.HTLC{
+				Amt:         1000,
+				OutputIndex: -1,
+			},
+			height:   cutoff + 4,
+			expected: false,
+		},
+		{
+			name: "dust htlc after delay",
+			policy: ForceClosePolicy{
+				DustHtlcDelay: 5,
+			},
+			htlc: channeldb.HTLC{
+				Amt:         1000,
+				OutputIndex: -1,
+			},
+			height:   cutoff + 5,
+			expected: true,
+		},
+		{
+			name: "non-dust htlc not delayed",
+			policy: ForceClosePolicy{
+				DustHtlcDelay: 5,
+			},
+			htlc: channeldb.HTLC{
+				Amt: 1000,
+			},
+			height:   cutoff,
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			chanArb := &ChannelArbitrator{}
+			chanArb.cfg.ForceClosePolicy = tc.policy
+
+			htlc := tc.htlc
+			htlc.Incoming = true
+			htlc.RefundTimeout = refundTimeout
+
+			toChain := chanArb.triggersForceClose(
+				htlc, broadcastDelta, tc.height,
+			)
+			require.Equal(t, tc.expected, toChain)
+		})
+	}
+}
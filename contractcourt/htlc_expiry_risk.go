@@ -0,0 +1,204 @@
+package contractcourt
+
+import (
+	"sort"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// HtlcExpiryRisk describes the height at which an outstanding HTLC forces us
+// to close its channel on-chain.
+type HtlcExpiryRisk struct {
+	// HtlcIndex is the index of the HTLC in the channel.
+	HtlcIndex uint64
+
+	// Incoming is true if the HTLC was offered to us.
+	Incoming bool
+
+	// RHash is the payment hash of the HTLC.
+	RHash lntypes.Hash
+
+	// Amt is the amount of the HTLC.
+	Amt lnwire.MilliSatoshi
+
+	// RefundTimeout is the absolute expiry height of the HTLC.
+	RefundTimeout uint32
+
+	// BroadcastDelta is the number of blocks before the expiry of the HTLC
+	// at which we go on-chain.
+	BroadcastDelta uint32
+
+	// ForceCloseHeight is the height at which we go on-chain for the
+	// HTLC, unless it's resolved off-chain before.
+	ForceCloseHeight uint32
+
+	// TriggersForceClose is false for incoming HTLCs of which we don't
+	// know the preimage, as we don't go on-chain to claim those, and for
+	// HTLCs that are excluded by the force close policy.
+	TriggersForceClose bool
+}
+
+// ChannelExpiryRisk holds the expiry risk of all outstanding HTLCs of a
+// channel.
+type ChannelExpiryRisk struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// Htlcs are the outstanding HTLCs of the channel, ordered by their
+	// force close height.
+	Htlcs []HtlcExpiryRisk
+}
+
+// ForceCloseHeight returns the earliest height at which one of the HTLCs
+// forces the channel to be closed on-chain, if any.
+func (r *ChannelExpiryRisk) ForceCloseHeight() fn.Option[uint32] {
+	for _, htlc := range r.Htlcs {
+		if htlc.TriggersForceClose {
+			return fn.Some(htlc.ForceCloseHeight)
+		}
+	}
+
+	return fn.None[uint32]()
+}
+
+// BlocksUntilForceClose returns the number of blocks after the given height
+// at which the channel is force closed, unless its HTLCs are resolved
+// off-chain before. A negative value means the channel is past its force
+// close height, which happens if the channel is waiting for a grace period
+// or is in the process of being closed.
+func (r *ChannelExpiryRisk) BlocksUntilForceClose(
+	height uint32) fn.Option[int32] {
+
+	return fn.MapOption(func(closeHeight uint32) int32 {
+		return int32(closeHeight) - int32(height)
+	})(r.ForceCloseHeight())
+}
+
+// HtlcExpiryRisks returns the expiry risk of the outstanding HTLCs of all
+// active channels.
+func (c *ChainArbitrator) HtlcExpiryRisks() ([]*ChannelExpiryRisk, error) {
+	c.Lock()
+	arbs := make([]*ChannelArbitrator, 0, len(c.activeChannels))
+	for _, arb := range c.activeChannels {
+		arbs = append(arbs, arb)
+	}
+	c.Unlock()
+
+	risks := make([]*ChannelExpiryRisk, 0, len(arbs))
+	for _, arb := range arbs {
+		risk, err := arb.htlcExpiryRisk()
+		if err != nil {
+			return nil, err
+		}
+
+		risks = append(risks, risk)
+	}
+
+	sort.Slice(risks, func(i, j int) bool {
+		return risks[i].ChanPoint.String() < risks[j].ChanPoint.String()
+	})
+
+	return risks, nil
+}
+
+// htlcExpiryRisk computes the expiry risk of the HTLCs on all valid
+// commitments of the channel, using the same deltas as the channel arbitrator
+// uses to decide whether to go on-chain.
+func (c *ChannelArbitrator) htlcExpiryRisk() (*ChannelExpiryRisk, error) {
+	type htlcKey struct {
+		incoming bool
+		index    uint64
+	}
+
+	c.unmergedMtx.RLock()
+	htlcs := make(map[htlcKey]channeldb.HTLC)
+	for _, set := range c.unmergedSet {
+		for index, htlc := range set.incomingHTLCs {
+			htlcs[htlcKey{incoming: true, index: index}] = htlc
+		}
+		for index, htlc := range set.outgoingHTLCs {
+			htlcs[htlcKey{index: index}] = htlc
+		}
+	}
+	c.unmergedMtx.RUnlock()
+
+	risk := &ChannelExpiryRisk{
+		ChanPoint: c.cfg.ChanPoint,
+		Htlcs:     make([]HtlcExpiryRisk, 0, len(htlcs)),
+	}
+	for key, htlc := range htlcs {
+		// Outgoing HTLCs that were initiated by us may be given a grace
+		// period, but we report the earliest height we may go
+		// on-chain.
+		delta := c.cfg.OutgoingBroadcastDelta
+		triggers := true
+		if key.incoming {
+			delta = c.cfg.IncomingBroadcastDelta
+
+			var err error
+			triggers, err = c.isPreimageAvailable(htlc.RHash)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// The force close policy may exclude the HTLC or delay its
+		// force close.
+		policy := c.cfg.ForceClosePolicy
+		delayOpt := policy.forceCloseDelay(htlc)
+		if delayOpt.IsNone() {
+			triggers = false
+		}
+
+		var closeHeight uint32
+		if htlc.RefundTimeout > delta {
+			closeHeight = htlc.RefundTimeout - delta
+		}
+		closeHeight += delayOpt.UnwrapOr(0)
+
+		risk.Htlcs = append(risk.Htlcs, HtlcExpiryRisk{
+			HtlcIndex:          key.index,
+			Incoming:           key.incoming,
+			RHash:              htlc.RHash,
+			Amt:                htlc.Amt,
+			RefundTimeout:      htlc.RefundTimeout,
+			BroadcastDelta:     delta,
+			ForceCloseHeight:   closeHeight,
+			TriggersForceClose: triggers,
+		})
+	}
+
+	sort.Slice(risk.Htlcs, func(i, j int) bool {
+		a, b := risk.Htlcs[i], risk.Htlcs[j]
+		if a.ForceCloseHeight != b.ForceCloseHeight {
+			return a.ForceCloseHeight < b.ForceCloseHeight
+		}
+		if a.Incoming != b.Incoming {
+			return !a.Incoming
+		}
+
+		return a.HtlcIndex < b.HtlcIndex
+	})
+
+	return risk, nil
+}
+
+// reportExpiryRisk updates the force close metric of the channel for the
+// given height.
+func (c *ChannelArbitrator) reportExpiryRisk(height uint32) {
+	risk, err := c.htlcExpiryRisk()
+	if err != nil {
+		log.Errorf("ChannelArbitrator(%v): unable to compute htlc "+
+			"expiry risk: %v", c.cfg.ChanPoint, err)
+
+		return
+	}
+
+	reportBlocksUntilForceClose(
+		c.cfg.ChanPoint, risk.BlocksUntilForceClose(height),
+	)
+}
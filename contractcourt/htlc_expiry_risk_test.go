@@ -0,0 +1,86 @@
+package contractcourt
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHtlcExpiryRisk asserts that the expiry risk of a channel reports the
+// heights at which its HTLCs force the channel to be closed.
+func TestHtlcExpiryRisk(t *testing.T) {
+	t.Parallel()
+
+	knownHash := lntypes.Hash{1}
+	unknownHash := lntypes.Hash{2}
+
+	preimageDB := newMockWitnessBeacon()
+	preimageDB.lookupPreimage[knownHash] = lntypes.Preimage{1}
+
+	outgoing := channeldb.HTLC{
+		HtlcIndex:     0,
+		RefundTimeout: 150,
+	}
+	incomingKnown := channeldb.HTLC{
+		HtlcIndex:     1,
+		Incoming:      true,
+		RHash:         knownHash,
+		RefundTimeout: 140,
+	}
+	incomingUnknown := channeldb.HTLC{
+		HtlcIndex:     2,
+		Incoming:      true,
+		RHash:         unknownHash,
+		RefundTimeout: 100,
+	}
+
+	chanArb := &ChannelArbitrator{
+		cfg: ChannelArbitratorConfig{
+			ChainArbitratorConfig: ChainArbitratorConfig{
+				OutgoingBroadcastDelta: 10,
+				IncomingBroadcastDelta: 20,
+				PreimageDB:             preimageDB,
+				Registry:               &mockRegistry{},
+			},
+		},
+		unmergedSet: map[HtlcSetKey]htlcSet{
+			LocalHtlcSet: newHtlcSet([]channeldb.HTLC{
+				outgoing, incomingKnown, incomingUnknown,
+			}),
+			// The same HTLC on the remote commitment must only be
+			// reported once.
+			RemoteHtlcSet: newHtlcSet([]channeldb.HTLC{
+				outgoing,
+			}),
+		},
+	}
+
+	risk, err := chanArb.htlcExpiryRisk()
+	require.NoError(t, err)
+	require.Len(t, risk.Htlcs, 3)
+
+	// The incoming HTLC without preimage expires first but doesn't
+	// trigger a force close.
+	require.EqualValues(t, 2, risk.Htlcs[0].HtlcIndex)
+	require.EqualValues(t, 80, risk.Htlcs[0].ForceCloseHeight)
+	require.False(t, risk.Htlcs[0].TriggersForceClose)
+
+	require.EqualValues(t, 1, risk.Htlcs[1].HtlcIndex)
+	require.EqualValues(t, 120, risk.Htlcs[1].ForceCloseHeight)
+	require.True(t, risk.Htlcs[1].TriggersForceClose)
+
+	require.EqualValues(t, 0, risk.Htlcs[2].HtlcIndex)
+	require.EqualValues(t, 140, risk.Htlcs[2].ForceCloseHeight)
+	require.True(t, risk.Htlcs[2].TriggersForceClose)
+
+	require.Equal(t, fn.Some(uint32(120)), risk.ForceCloseHeight())
+	require.Equal(t, fn.Some(int32(14)), risk.BlocksUntilForceClose(106))
+	require.Equal(t, fn.Some(int32(-1)), risk.BlocksUntilForceClose(121))
+
+	// A channel without HTLCs is never force closed.
+	empty := &ChannelExpiryRisk{}
+	require.True(t, empty.BlocksUntilForceClose(100).IsNone())
+}
@@ -180,6 +180,7 @@ func (h *htlcIncomingContestResolver) Resolve(
 		if err := h.processFinalHtlcFail(); err != nil {
 			return nil, err
 		}
+		h.releaseExpiredHtlc()
 
 		// Finally, get our report and checkpoint our resolver with a
 		// timeout outcome report.
@@ -433,6 +434,7 @@ func (h *htlcIncomingContestResolver) Resolve(
 				if err := h.processFinalHtlcFail(); err != nil {
 					return nil, err
 				}
+				h.releaseExpiredHtlc()
 
 				report := h.report().resolverReport(
 					nil,
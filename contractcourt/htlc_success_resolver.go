@@ -294,6 +294,7 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx(immediate bool) (
 			&secondLevelInput,
 			sweep.Params{
 				Budget:         budget,
+				MaxBudget:      h.Budget.maxBudget(value),
 				DeadlineHeight: deadline,
 				Immediate:      immediate,
 			},
@@ -498,7 +499,10 @@ func (h *htlcSuccessResolver) resolveRemoteCommitOutput(immediate bool) (
 	_, err := h.Sweeper.SweepInput(
 		inp,
 		sweep.Params{
-			Budget:         budget,
+			Budget: budget,
+			MaxBudget: h.Budget.maxBudget(
+				btcutil.Amount(inp.SignDesc().Output.Value),
+			),
 			DeadlineHeight: deadline,
 			Immediate:      immediate,
 		},
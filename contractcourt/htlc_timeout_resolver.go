@@ -639,6 +639,17 @@ func (h *htlcTimeoutResolver) spendHtlcOutput(
 	// (the case for anchor type channels). In this case we can re-sign it
 	// and attach fees at will. We let the sweeper handle this job.
 	case h.htlcResolution.SignDetails != nil && !h.outputIncubating:
+		// If enabled, we first try to sweep the output directly with
+		// the help of the peer once it expired, and only fall back to
+		// the second-level timeout tx if that fails.
+		swept, err := h.coopSweepHtlcOutput()
+		if err != nil {
+			return nil, err
+		}
+		if swept {
+			break
+		}
+
 		if err := h.sweepSecondLevelTx(immediate); err != nil {
 			log.Errorf("Sending timeout tx to sweeper: %v", err)
 
@@ -759,6 +770,11 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 		// accordingly.
 		spendTxID = commitSpend.SpenderTxHash
 
+		// claimAmt is the value of the claimOutpoint.
+		claimAmt = btcutil.Amount(
+			h.htlcResolution.SweepSignDesc.Output.Value,
+		)
+
 		reports []*channeldb.ResolverReport
 	)
 
@@ -770,6 +786,14 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 	case h.htlcResolution.SignedTimeoutTx == nil:
 		break
 
+	// If we swept the HTLC directly off our commitment transaction with
+	// the help of the peer, there's no second level sweep to do either.
+	case h.isCoopSweep(commitSpend):
+		signDesc := h.htlcResolution.SignDetails.SignDesc
+
+		claimOutpoint = h.HtlcPoint()
+		claimAmt = btcutil.Amount(signDesc.Output.Value)
+
 	// If the sweeper is handling the second level transaction, wait for
 	// the CSV and possible CLTV lock to expire, before sweeping the output
 	// on the second-level.
@@ -918,10 +942,9 @@ func (h *htlcTimeoutResolver) handleCommitSpend(
 	h.currentReport.LimboBalance = 0
 	h.reportLock.Unlock()
 
-	amt := btcutil.Amount(h.htlcResolution.SweepSignDesc.Output.Value)
 	reports = append(reports, &channeldb.ResolverReport{
 		OutPoint:        claimOutpoint,
-		Amount:          amt,
+		Amount:          claimAmt,
 		ResolverType:    channeldb.ResolverTypeOutgoingHtlc,
 		ResolverOutcome: channeldb.ResolverOutcomeTimeout,
 		SpendTxID:       spendTxID,
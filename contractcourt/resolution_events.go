@@ -0,0 +1,162 @@
+package contractcourt
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+// ResolutionCommitConfirmed is sent to the resolution event subscribers when
+// the closing tx of a channel confirmed.
+type ResolutionCommitConfirmed struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// ClosingTxid is the hash of the confirmed closing tx.
+	ClosingTxid chainhash.Hash
+
+	// CloseType is the way the channel was closed.
+	CloseType channeldb.ClosureType
+
+	// CloseHeight is the height at which the closing tx confirmed.
+	CloseHeight uint32
+
+	// SettledBalance is our balance that was paid out by the closing tx
+	// without a time lock.
+	SettledBalance btcutil.Amount
+
+	// TimeLockedBalance is our balance that is still time locked in the
+	// closing tx, which is claimed once the time lock expires.
+	TimeLockedBalance btcutil.Amount
+}
+
+// ResolutionOutputResolved is sent to the resolution event subscribers when
+// an output of a closed channel reaches a resolution stage, such as an HTLC
+// being claimed via its timeout or success path, or a sweep of one of our
+// outputs confirming.
+type ResolutionOutputResolved struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// Report describes the resolution of the output.
+	Report channeldb.ResolverReport
+}
+
+// ResolutionFullyResolved is sent to the resolution event subscribers when
+// all the contracts of a closed channel are resolved.
+type ResolutionFullyResolved struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// ClaimedBalance is the total amount we claimed on-chain by resolving
+	// the outputs of the channel.
+	ClaimedBalance btcutil.Amount
+}
+
+// SubscribeResolutionEvents returns a subscription that receives the
+// resolution events of all closed channels, which is a
+// ResolutionCommitConfirmed once the closing tx of a channel confirmed, a
+// ResolutionOutputResolved for every resolved output and finally a
+// ResolutionFullyResolved.
+func (c *ChainArbitrator) SubscribeResolutionEvents() (*subscribe.Client,
+	error) {
+
+	return c.resolutionNtfns.Subscribe()
+}
+
+// notifyResolutionEvent sends the given event to the resolution event
+// subscribers.
+func (c *ChainArbitrator) notifyResolutionEvent(event interface{}) {
+	if err := c.resolutionNtfns.SendUpdate(event); err != nil {
+		log.Warnf("Unable to send resolution event %T: %v", event, err)
+	}
+}
+
+// putResolverReport records the given resolver report of a channel. Reports
+// that are written in a new transaction are sent to the resolution event
+// subscribers right away, while the arbitrator log sends the others once their
+// transaction is committed.
+func (c *ChainArbitrator) putResolverReport(tx kvdb.RwTx,
+	chanPoint wire.OutPoint, report *channeldb.ResolverReport) error {
+
+	err := c.chanSource.PutResolverReport(
+		tx, c.cfg.ChainHash, &chanPoint, report,
+	)
+	if err != nil {
+		return err
+	}
+
+	if tx == nil {
+		c.notifyResolverReport(chanPoint, report)
+	}
+
+	return nil
+}
+
+// notifyResolverReport notifies the resolution event subscribers of the given
+// committed resolver report of a channel.
+func (c *ChainArbitrator) notifyResolverReport(chanPoint wire.OutPoint,
+	report *channeldb.ResolverReport) {
+
+	c.notifyResolutionEvent(&ResolutionOutputResolved{
+		ChanPoint: chanPoint,
+		Report:    *report,
+	})
+}
+
+// notifyCommitConfirmed notifies the resolution event subscribers of the
+// confirmed closing tx described by the given summary.
+func (c *ChainArbitrator) notifyCommitConfirmed(
+	summary *channeldb.ChannelCloseSummary) {
+
+	c.notifyResolutionEvent(&ResolutionCommitConfirmed{
+		ChanPoint:         summary.ChanPoint,
+		ClosingTxid:       summary.ClosingTXID,
+		CloseType:         summary.CloseType,
+		CloseHeight:       summary.CloseHeight,
+		SettledBalance:    summary.SettledBalance,
+		TimeLockedBalance: summary.TimeLockedBalance,
+	})
+}
+
+// notifyFullyResolved notifies the resolution event subscribers that the
+// given channel is fully resolved, along with the amount we claimed on-chain.
+func (c *ChainArbitrator) notifyFullyResolved(chanPoint wire.OutPoint) {
+	reports, err := c.chanSource.FetchChannelReports(
+		c.cfg.ChainHash, &chanPoint,
+	)
+
+	// Channels without any outputs to resolve, such as cooperatively
+	// closed ones, don't have any reports.
+	if err != nil && !errors.Is(err, channeldb.ErrNoChainHashBucket) &&
+		!errors.Is(err, channeldb.ErrNoChannelSummaries) {
+
+		log.Errorf("Unable to fetch resolver reports of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+	}
+
+	// Besides the outputs we claimed, the outgoing HTLCs that timed out
+	// on-chain were paid back to us as well.
+	var claimed btcutil.Amount
+	for _, report := range reports {
+		switch {
+		case report.ResolverOutcome == channeldb.ResolverOutcomeClaimed:
+			claimed += report.Amount
+
+		case report.ResolverType == channeldb.ResolverTypeOutgoingHtlc &&
+			report.ResolverOutcome == channeldb.ResolverOutcomeTimeout:
+
+			claimed += report.Amount
+		}
+	}
+
+	c.notifyResolutionEvent(&ResolutionFullyResolved{
+		ChanPoint:      chanPoint,
+		ClaimedBalance: claimed,
+	})
+}
@@ -0,0 +1,133 @@
+package contractcourt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/stretchr/testify/require"
+)
+
+// receiveResolutionEvent waits for the next resolution event of the given
+// client.
+func receiveResolutionEvent(t *testing.T,
+	client *subscribe.Client) interface{} {
+
+	t.Helper()
+
+	select {
+	case event := <-client.Updates():
+		return event
+
+	case <-time.After(defaultTimeout):
+		t.Fatalf("no resolution event received")
+		return nil
+	}
+}
+
+// TestResolutionEvents asserts that the resolution event subscribers are
+// notified of the confirmed closing tx, the resolved outputs and the final
+// resolution of a channel, along with the balance claimed on-chain.
+func TestResolutionEvents(t *testing.T) {
+	t.Parallel()
+
+	db, err := makeTestChannelDB(t)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	chainArb := NewChainArbitrator(ChainArbitratorConfig{
+		ChainHash: chainhash.Hash{1},
+	}, db)
+	require.NoError(t, chainArb.resolutionNtfns.Start())
+	t.Cleanup(func() {
+		require.NoError(t, chainArb.resolutionNtfns.Stop())
+	})
+
+	client, err := chainArb.SubscribeResolutionEvents()
+	require.NoError(t, err)
+	defer client.Cancel()
+
+	chanPoint := wire.OutPoint{Index: 1}
+	summary := &channeldb.ChannelCloseSummary{
+		ChanPoint:         chanPoint,
+		ClosingTXID:       chainhash.Hash{2},
+		CloseType:         channeldb.LocalForceClose,
+		CloseHeight:       100,
+		SettledBalance:    1000,
+		TimeLockedBalance: 2000,
+	}
+	chainArb.notifyCommitConfirmed(summary)
+
+	event := receiveResolutionEvent(t, client)
+	require.Equal(t, &ResolutionCommitConfirmed{
+		ChanPoint:         chanPoint,
+		ClosingTxid:       summary.ClosingTXID,
+		CloseType:         summary.CloseType,
+		CloseHeight:       summary.CloseHeight,
+		SettledBalance:    summary.SettledBalance,
+		TimeLockedBalance: summary.TimeLockedBalance,
+	}, event)
+
+	// Only our claimed outputs and the outgoing HTLCs that timed out
+	// count towards the claimed balance.
+	reports := []*channeldb.ResolverReport{
+		{
+			OutPoint:        wire.OutPoint{Index: 2},
+			Amount:          2000,
+			ResolverType:    channeldb.ResolverTypeCommit,
+			ResolverOutcome: channeldb.ResolverOutcomeClaimed,
+		},
+		{
+			OutPoint:        wire.OutPoint{Index: 3},
+			Amount:          300,
+			ResolverType:    channeldb.ResolverTypeOutgoingHtlc,
+			ResolverOutcome: channeldb.ResolverOutcomeTimeout,
+		},
+		{
+			OutPoint:        wire.OutPoint{Index: 4},
+			Amount:          400,
+			ResolverType:    channeldb.ResolverTypeIncomingHtlc,
+			ResolverOutcome: channeldb.ResolverOutcomeTimeout,
+		},
+		{
+			OutPoint:        wire.OutPoint{Index: 5},
+			Amount:          330,
+			ResolverType:    channeldb.ResolverTypeAnchor,
+			ResolverOutcome: channeldb.ResolverOutcomeUnclaimed,
+		},
+	}
+	for _, report := range reports {
+		err := chainArb.putResolverReport(nil, chanPoint, report)
+		require.NoError(t, err)
+
+		event := receiveResolutionEvent(t, client)
+		require.Equal(t, &ResolutionOutputResolved{
+			ChanPoint: chanPoint,
+			Report:    *report,
+		}, event)
+	}
+
+	chainArb.notifyFullyResolved(chanPoint)
+
+	event = receiveResolutionEvent(t, client)
+	require.Equal(t, &ResolutionFullyResolved{
+		ChanPoint:      chanPoint,
+		ClaimedBalance: btcutil.Amount(2300),
+	}, event)
+
+	// A channel without any resolver reports, such as a cooperatively
+	// closed one, is resolved without any claimed balance.
+	otherChanPoint := wire.OutPoint{Index: 6}
+	chainArb.notifyFullyResolved(otherChanPoint)
+
+	event = receiveResolutionEvent(t, client)
+	require.Equal(t, &ResolutionFullyResolved{
+		ChanPoint: otherChanPoint,
+	}, event)
+}
@@ -823,30 +823,49 @@ func (u *UtxoNursery) graduateClass(classHeight uint32) error {
 	return nil
 }
 
-// decideDeadlineAndBudget returns the deadline and budget for a given output.
-func (u *UtxoNursery) decideDeadlineAndBudget(k kidOutput) (fn.Option[int32],
-	btcutil.Amount) {
+// decideSweepParams returns the deadline and budget for a given output. Only
+// outputs with a deadline have their budget escalated as the deadline
+// approaches.
+func (u *UtxoNursery) decideSweepParams(k kidOutput) sweep.Params {
+	switch k.WitnessType() {
+	// This is the output of a second-level HTLC transaction, which isn't
+	// time-sensitive, so we use a None deadline.
+	case input.HtlcOfferedTimeoutSecondLevel,
+		input.TaprootHtlcOfferedTimeoutSecondLevel:
+
+		return sweep.Params{
+			Budget: calculateBudget(
+				k.amt, u.cfg.Budget.NoDeadlineHTLCRatio,
+				u.cfg.Budget.NoDeadlineHTLC,
+			),
+		}
+	}
 
 	// Assume this is a to_local output and use a None deadline.
-	deadline := fn.None[int32]()
-
-	// Exit early if this is not HTLC.
 	if !k.isHtlc {
-		budget := calculateBudget(
-			k.amt, u.cfg.Budget.ToLocalRatio, u.cfg.Budget.ToLocal,
-		)
-
-		return deadline, budget
+		return sweep.Params{
+			Budget: calculateBudget(
+				k.amt, u.cfg.Budget.ToLocalRatio,
+				u.cfg.Budget.ToLocal,
+			),
+		}
 	}
 
 	// Otherwise it's the first-level HTLC output, we'll use the
-	// time-sensitive settings for it.
-	budget := calculateBudget(
-		k.amt, u.cfg.Budget.DeadlineHTLCRatio,
-		u.cfg.Budget.DeadlineHTLC,
-	)
+	// time-sensitive settings for it. Its deadline is derived from the
+	// CLTV expiry of the HTLC.
+	params := sweep.Params{
+		DeadlineHeight: k.deadlineHeight,
+		Budget: calculateBudget(
+			k.amt, u.cfg.Budget.DeadlineHTLCRatio,
+			u.cfg.Budget.DeadlineHTLC,
+		),
+	}
+	if k.deadlineHeight.IsSome() {
+		params.MaxBudget = u.cfg.Budget.maxBudget(k.amt)
+	}
 
-	return k.deadlineHeight, budget
+	return params
 }
 
 // sweepMatureOutputs generates and broadcasts the transaction that transfers
@@ -865,12 +884,9 @@ func (u *UtxoNursery) sweepMatureOutputs(classHeight uint32,
 		local := output
 
 		// Calculate the deadline height and budget for this output.
-		deadline, budget := u.decideDeadlineAndBudget(local)
+		params := u.decideSweepParams(local)
 
-		resultChan, err := u.cfg.SweepInput(&local, sweep.Params{
-			DeadlineHeight: deadline,
-			Budget:         budget,
-		})
+		resultChan, err := u.cfg.SweepInput(&local, params)
 		if err != nil {
 			return err
 		}
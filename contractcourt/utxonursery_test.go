@@ -397,6 +397,75 @@ func TestBabyOutputSerialization(t *testing.T) {
 	}
 }
 
+// TestDecideSweepParams checks that the nursery offers its outputs to the
+// sweeper using the budget and deadline matching their type.
+func TestDecideSweepParams(t *testing.T) {
+	t.Parallel()
+
+	u := &UtxoNursery{
+		cfg: &NurseryConfig{
+			Budget: &BudgetConfig{
+				ToLocalRatio:        0.1,
+				DeadlineHTLCRatio:   0.2,
+				NoDeadlineHTLCRatio: 0.3,
+				EscalationRatio:     0.8,
+			},
+		},
+	}
+
+	const amt = btcutil.Amount(10_000)
+	signDesc := &input.SignDescriptor{
+		Output: &wire.TxOut{Value: int64(amt)},
+	}
+	deadline := fn.Some(int32(800_000))
+
+	testCases := []struct {
+		name        string
+		witnessType input.StandardWitnessType
+		deadline    fn.Option[int32]
+		expected    sweep.Params
+	}{
+		{
+			name:        "to_local",
+			witnessType: input.CommitmentTimeLock,
+			expected:    sweep.Params{Budget: 1_000},
+		},
+		{
+			name:        "second-level htlc",
+			witnessType: input.HtlcOfferedTimeoutSecondLevel,
+			expected:    sweep.Params{Budget: 3_000},
+		},
+		{
+			name:        "first-level htlc with deadline",
+			witnessType: input.HtlcOfferedRemoteTimeout,
+			deadline:    deadline,
+			expected: sweep.Params{
+				DeadlineHeight: deadline,
+				Budget:         2_000,
+				MaxBudget:      8_000,
+			},
+		},
+		{
+			name:        "first-level htlc without deadline",
+			witnessType: input.HtlcOfferedRemoteTimeout,
+			expected:    sweep.Params{Budget: 2_000},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			kid := makeKidOutput(
+				&outPoints[0], &outPoints[1], 0, tc.witnessType,
+				signDesc, 0, tc.deadline,
+			)
+
+			require.Equal(t, tc.expected, u.decideSweepParams(kid))
+		})
+	}
+}
+
 type nurseryTestContext struct {
 	nursery     *UtxoNursery
 	notifier    *sweep.MockNotifier
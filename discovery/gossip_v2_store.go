@@ -0,0 +1,676 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// gossipV2StoreBucket is a key used to create a top level bucket in
+	// the gossiper database, used for storing the taproot channel gossip
+	// messages (gossip 1.75) that were validated. The channel graph can't
+	// hold these messages yet, so they are kept here in order to relay
+	// them and to sync them with our peers.
+	//
+	// maps:
+	//   shortChanID (8 bytes) + msgType (2 bytes) + direction (1 byte)
+	//     -> msg
+	gossipV2StoreBucket = []byte("gossip-v2-store")
+
+	// gossipV2EdgePointBucket is a key used to create a top level bucket
+	// in the gossiper database, used for storing the funding outputs of
+	// the taproot channels in the gossip store. They are watched for
+	// spends, so that closed channels can be pruned from the store.
+	//
+	// maps:
+	//   shortChanID (8 bytes) -> outpoint (36 bytes) + pkScript
+	gossipV2EdgePointBucket = []byte("gossip-v2-edge-points")
+
+	// gossipV2NodeBucket is a key used to create a top level bucket in the
+	// gossiper database, used for storing the latest node announcement of
+	// each node that has a taproot channel in the gossip store.
+	//
+	// maps:
+	//   nodeID (33 bytes) -> msg
+	gossipV2NodeBucket = []byte("gossip-v2-nodes")
+
+	// gossipV2NodeChanBucket is a key used to create a top level bucket in
+	// the gossiper database, used for indexing the taproot channels in the
+	// gossip store by their nodes.
+	//
+	// maps:
+	//   nodeID (33 bytes) + shortChanID (8 bytes) -> nil
+	gossipV2NodeChanBucket = []byte("gossip-v2-node-chans")
+
+	// ErrGossipV2ChannelNotFound is returned when the channel announcement
+	// of a taproot channel isn't found in the store.
+	ErrGossipV2ChannelNotFound = errors.New("taproot channel " +
+		"announcement not found")
+)
+
+// GossipV2Store is a store for the validated taproot channel gossip messages.
+// Only the latest channel update of each direction of a channel is kept.
+type GossipV2Store struct {
+	db kvdb.Backend
+}
+
+// NewGossipV2Store creates a new taproot channel gossip store backed by the
+// given database.
+func NewGossipV2Store(db kvdb.Backend) (*GossipV2Store, error) {
+	err := kvdb.Batch(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(gossipV2StoreBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(gossipV2EdgePointBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(gossipV2NodeBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(gossipV2NodeChanBucket)
+
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create required buckets: %w",
+			err)
+	}
+
+	return &GossipV2Store{db: db}, nil
+}
+
+// gossipV2Key constructs the database key of a message of the given type for
+// the given channel. The direction is only relevant for channel updates.
+func gossipV2Key(scid lnwire.ShortChannelID, msgType lnwire.MessageType,
+	isNode1 bool) []byte {
+
+	var k [8 + 2 + 1]byte
+	binary.BigEndian.PutUint64(k[:8], scid.ToUint64())
+	binary.BigEndian.PutUint16(k[8:10], uint16(msgType))
+	if !isNode1 {
+		k[10] = 1
+	}
+
+	return k[:]
+}
+
+// gossipV2MsgKey constructs the database key of the given message.
+func gossipV2MsgKey(msg lnwire.Message) ([]byte, error) {
+	switch msg := msg.(type) {
+	case *lnwire.ChannelAnnouncement2:
+		return gossipV2Key(msg.SCID(), msg.MsgType(), true), nil
+
+	case *lnwire.ChannelUpdate2:
+		return gossipV2Key(msg.SCID(), msg.MsgType(), msg.IsNode1()), nil
+
+	default:
+		return nil, ErrUnsupportedMessage
+	}
+}
+
+// AddMessage stores the given channel announcement or channel update,
+// replacing the one previously stored for the same channel and direction.
+func (s *GossipV2Store) AddMessage(msg lnwire.Message) error {
+	msgKey, err := gossipV2MsgKey(msg)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, msg, 0); err != nil {
+		return err
+	}
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		store := tx.ReadWriteBucket(gossipV2StoreBucket)
+		if store == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		return store.Put(msgKey, b.Bytes())
+	})
+}
+
+// AddChannel stores the given channel announcement along with the funding
+// output of the channel.
+func (s *GossipV2Store) AddChannel(ann *lnwire.ChannelAnnouncement2,
+	edgePoint *channeldb.EdgePoint) error {
+
+	msgKey, err := gossipV2MsgKey(ann)
+	if err != nil {
+		return err
+	}
+
+	var msg bytes.Buffer
+	if _, err := lnwire.WriteMessage(&msg, ann, 0); err != nil {
+		return err
+	}
+
+	var point bytes.Buffer
+	if err := writeGossipV2EdgePoint(&point, edgePoint); err != nil {
+		return err
+	}
+
+	scid := ann.SCID()
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		store := tx.ReadWriteBucket(gossipV2StoreBucket)
+		if store == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		points := tx.ReadWriteBucket(gossipV2EdgePointBucket)
+		if points == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		nodeChans := tx.ReadWriteBucket(gossipV2NodeChanBucket)
+		if nodeChans == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		if err := store.Put(msgKey, msg.Bytes()); err != nil {
+			return err
+		}
+
+		for _, nodeID := range [][33]byte{
+			ann.NodeID1.Val, ann.NodeID2.Val,
+		} {
+			nodeChanKey := gossipV2NodeChanKey(nodeID, scid)
+			if err := nodeChans.Put(nodeChanKey, nil); err != nil {
+				return err
+			}
+		}
+
+		return points.Put(gossipV2ChanKey(scid), point.Bytes())
+	})
+}
+
+// DeleteChannel removes the announcement, the updates and the funding output
+// of the given channel from the store. The announcements of the channel's
+// nodes are removed as well if they have no other channels left.
+func (s *GossipV2Store) DeleteChannel(scid lnwire.ShortChannelID) error {
+	annKey := gossipV2Key(scid, lnwire.MsgChannelAnnouncement2, true)
+	msgKeys := [][]byte{
+		annKey,
+		gossipV2Key(scid, lnwire.MsgChannelUpdate2, true),
+		gossipV2Key(scid, lnwire.MsgChannelUpdate2, false),
+	}
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		store := tx.ReadWriteBucket(gossipV2StoreBucket)
+		if store == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		points := tx.ReadWriteBucket(gossipV2EdgePointBucket)
+		if points == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		nodes := tx.ReadWriteBucket(gossipV2NodeBucket)
+		if nodes == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		nodeChans := tx.ReadWriteBucket(gossipV2NodeChanBucket)
+		if nodeChans == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		// The announcement tells us which nodes the channel belongs
+		// to, so we read it before removing it.
+		var nodeIDs [][33]byte
+		if v := store.Get(annKey); v != nil {
+			msg, err := lnwire.ReadMessage(bytes.NewReader(v), 0)
+			if err != nil {
+				return err
+			}
+
+			ann, ok := msg.(*lnwire.ChannelAnnouncement2)
+			if !ok {
+				return ErrCorruptedMessageStore
+			}
+			nodeIDs = [][33]byte{ann.NodeID1.Val, ann.NodeID2.Val}
+		}
+
+		for _, msgKey := range msgKeys {
+			if err := store.Delete(msgKey); err != nil {
+				return err
+			}
+		}
+
+		for _, nodeID := range nodeIDs {
+			nodeChanKey := gossipV2NodeChanKey(nodeID, scid)
+			if err := nodeChans.Delete(nodeChanKey); err != nil {
+				return err
+			}
+
+			if hasGossipV2NodeChans(nodeChans, nodeID) {
+				continue
+			}
+
+			if err := nodes.Delete(nodeID[:]); err != nil {
+				return err
+			}
+		}
+
+		return points.Delete(gossipV2ChanKey(scid))
+	})
+}
+
+// AddNodeAnnouncement stores the given node announcement, replacing the one
+// previously stored for the same node.
+func (s *GossipV2Store) AddNodeAnnouncement(
+	ann *lnwire.NodeAnnouncement2) error {
+
+	var b bytes.Buffer
+	if _, err := lnwire.WriteMessage(&b, ann, 0); err != nil {
+		return err
+	}
+
+	return kvdb.Batch(s.db, func(tx kvdb.RwTx) error {
+		nodes := tx.ReadWriteBucket(gossipV2NodeBucket)
+		if nodes == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		return nodes.Put(ann.NodeID.Val[:], b.Bytes())
+	})
+}
+
+// NodeAnnouncement returns the stored announcement of the given node. Nil is
+// returned if no announcement is known.
+func (s *GossipV2Store) NodeAnnouncement(
+	nodeID [33]byte) (*lnwire.NodeAnnouncement2, error) {
+
+	var ann *lnwire.NodeAnnouncement2
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		nodes := tx.ReadBucket(gossipV2NodeBucket)
+		if nodes == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		v := nodes.Get(nodeID[:])
+		if v == nil {
+			return nil
+		}
+
+		var err error
+		ann, err = readGossipV2NodeAnn(v)
+
+		return err
+	}, func() {
+		ann = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ann, nil
+}
+
+// NodeAnnouncements returns the stored announcements of all nodes.
+func (s *GossipV2Store) NodeAnnouncements() ([]*lnwire.NodeAnnouncement2,
+	error) {
+
+	var anns []*lnwire.NodeAnnouncement2
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		nodes := tx.ReadBucket(gossipV2NodeBucket)
+		if nodes == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		return nodes.ForEach(func(_, v []byte) error {
+			ann, err := readGossipV2NodeAnn(v)
+			if err != nil {
+				return err
+			}
+
+			anns = append(anns, ann)
+
+			return nil
+		})
+	}, func() {
+		anns = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return anns, nil
+}
+
+// HasNodeChannels returns true if the given node has any taproot channel in
+// the store.
+func (s *GossipV2Store) HasNodeChannels(nodeID [33]byte) (bool, error) {
+	var hasChans bool
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		nodeChans := tx.ReadBucket(gossipV2NodeChanBucket)
+		if nodeChans == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		hasChans = hasGossipV2NodeChans(nodeChans, nodeID)
+
+		return nil
+	}, func() {
+		hasChans = false
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return hasChans, nil
+}
+
+// gossipV2NodeChanKey constructs the database key of the index entry of the
+// given channel of the given node.
+func gossipV2NodeChanKey(nodeID [33]byte, scid lnwire.ShortChannelID) []byte {
+	var k [33 + 8]byte
+	copy(k[:33], nodeID[:])
+	binary.BigEndian.PutUint64(k[33:], scid.ToUint64())
+
+	return k[:]
+}
+
+// hasGossipV2NodeChans returns true if the given node channel index holds any
+// channel of the given node.
+func hasGossipV2NodeChans(nodeChans kvdb.RBucket, nodeID [33]byte) bool {
+	k, _ := nodeChans.ReadCursor().Seek(nodeID[:])
+
+	return bytes.HasPrefix(k, nodeID[:])
+}
+
+// readGossipV2NodeAnn deserializes a stored node announcement.
+func readGossipV2NodeAnn(v []byte) (*lnwire.NodeAnnouncement2, error) {
+	msg, err := lnwire.ReadMessage(bytes.NewReader(v), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ann, ok := msg.(*lnwire.NodeAnnouncement2)
+	if !ok {
+		return nil, fmt.Errorf("expected *lnwire.NodeAnnouncement2, "+
+			"got: %T", msg)
+	}
+
+	return ann, nil
+}
+
+// EdgePoints returns the funding outputs of all channels in the store.
+func (s *GossipV2Store) EdgePoints() (
+	map[lnwire.ShortChannelID]*channeldb.EdgePoint, error) {
+
+	var edgePoints map[lnwire.ShortChannelID]*channeldb.EdgePoint
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		points := tx.ReadBucket(gossipV2EdgePointBucket)
+		if points == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		return points.ForEach(func(k, v []byte) error {
+			scid := lnwire.NewShortChanIDFromInt(
+				binary.BigEndian.Uint64(k),
+			)
+
+			edgePoint, err := readGossipV2EdgePoint(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+
+			edgePoints[scid] = edgePoint
+
+			return nil
+		})
+	}, func() {
+		edgePoints = make(
+			map[lnwire.ShortChannelID]*channeldb.EdgePoint,
+		)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return edgePoints, nil
+}
+
+// ChannelsInRange returns the IDs of the channels in the store that were
+// confirmed within the given range of block heights, in ascending order.
+func (s *GossipV2Store) ChannelsInRange(startHeight,
+	endHeight uint32) ([]lnwire.ShortChannelID, error) {
+
+	startKey := gossipV2ChanKey(lnwire.ShortChannelID{
+		BlockHeight: startHeight,
+	})
+
+	var scids []lnwire.ShortChannelID
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		points := tx.ReadBucket(gossipV2EdgePointBucket)
+		if points == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		cursor := points.ReadCursor()
+		k, _ := cursor.Seek(startKey)
+		for ; k != nil; k, _ = cursor.Next() {
+			scid := lnwire.NewShortChanIDFromInt(
+				binary.BigEndian.Uint64(k),
+			)
+			if scid.BlockHeight > endHeight {
+				break
+			}
+
+			scids = append(scids, scid)
+		}
+
+		return nil
+	}, func() {
+		scids = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return scids, nil
+}
+
+// gossipV2ChanKey constructs the database key of the funding output of the
+// given channel.
+func gossipV2ChanKey(scid lnwire.ShortChannelID) []byte {
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], scid.ToUint64())
+
+	return k[:]
+}
+
+// writeGossipV2EdgePoint serializes the funding output of a channel.
+func writeGossipV2EdgePoint(w io.Writer, edgePoint *channeldb.EdgePoint) error {
+	if _, err := w.Write(edgePoint.OutPoint.Hash[:]); err != nil {
+		return err
+	}
+
+	var index [4]byte
+	binary.BigEndian.PutUint32(index[:], edgePoint.OutPoint.Index)
+	if _, err := w.Write(index[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(edgePoint.FundingPkScript)
+
+	return err
+}
+
+// readGossipV2EdgePoint deserializes the funding output of a channel.
+func readGossipV2EdgePoint(r io.Reader) (*channeldb.EdgePoint, error) {
+	var edgePoint channeldb.EdgePoint
+	if _, err := io.ReadFull(r, edgePoint.OutPoint.Hash[:]); err != nil {
+		return nil, err
+	}
+
+	var index [4]byte
+	if _, err := io.ReadFull(r, index[:]); err != nil {
+		return nil, err
+	}
+	edgePoint.OutPoint.Index = binary.BigEndian.Uint32(index[:])
+
+	pkScript, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	edgePoint.FundingPkScript = pkScript
+
+	return &edgePoint, nil
+}
+
+// ChannelAnnouncement returns the stored announcement of the given channel.
+// ErrGossipV2ChannelNotFound is returned if the channel isn't known.
+func (s *GossipV2Store) ChannelAnnouncement(
+	scid lnwire.ShortChannelID) (*lnwire.ChannelAnnouncement2, error) {
+
+	msgKey := gossipV2Key(scid, lnwire.MsgChannelAnnouncement2, true)
+	msg, err := s.fetch(msgKey)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, ErrGossipV2ChannelNotFound
+	}
+
+	ann, ok := msg.(*lnwire.ChannelAnnouncement2)
+	if !ok {
+		return nil, fmt.Errorf("expected *lnwire.ChannelAnnouncement2, "+
+			"got: %T", msg)
+	}
+
+	return ann, nil
+}
+
+// ChannelUpdate returns the stored update of the given direction of the given
+// channel. Nil is returned if no update is known.
+func (s *GossipV2Store) ChannelUpdate(scid lnwire.ShortChannelID,
+	isNode1 bool) (*lnwire.ChannelUpdate2, error) {
+
+	msgKey := gossipV2Key(scid, lnwire.MsgChannelUpdate2, isNode1)
+	msg, err := s.fetch(msgKey)
+	if err != nil || msg == nil {
+		return nil, err
+	}
+
+	update, ok := msg.(*lnwire.ChannelUpdate2)
+	if !ok {
+		return nil, fmt.Errorf("expected *lnwire.ChannelUpdate2, "+
+			"got: %T", msg)
+	}
+
+	return update, nil
+}
+
+// fetch reads the message stored under the given key, if any.
+func (s *GossipV2Store) fetch(msgKey []byte) (lnwire.Message, error) {
+	var msg lnwire.Message
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		store := tx.ReadBucket(gossipV2StoreBucket)
+		if store == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		v := store.Get(msgKey)
+		if v == nil {
+			return nil
+		}
+
+		var err error
+		msg, err = lnwire.ReadMessage(bytes.NewReader(v), 0)
+
+		return err
+	}, func() {
+		msg = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// ChannelMessages returns the stored announcement and updates of the given
+// channel, with the announcement preceding the updates.
+func (s *GossipV2Store) ChannelMessages(
+	scid lnwire.ShortChannelID) ([]lnwire.Message, error) {
+
+	prefix := gossipV2ChanKey(scid)
+
+	var msgs []lnwire.Message
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		store := tx.ReadBucket(gossipV2StoreBucket)
+		if store == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		cursor := store.ReadCursor()
+		k, v := cursor.Seek(prefix)
+		for ; bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			msg, err := lnwire.ReadMessage(bytes.NewReader(v), 0)
+			if err != nil {
+				return err
+			}
+
+			msgs = append(msgs, msg)
+		}
+
+		return nil
+	}, func() {
+		msgs = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+// Messages returns all stored messages, ordered by channel, with the channel
+// announcement of each channel preceding its updates.
+func (s *GossipV2Store) Messages() ([]lnwire.Message, error) {
+	var msgs []lnwire.Message
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		store := tx.ReadBucket(gossipV2StoreBucket)
+		if store == nil {
+			return ErrCorruptedMessageStore
+		}
+
+		return store.ForEach(func(_, v []byte) error {
+			msg, err := lnwire.ReadMessage(bytes.NewReader(v), 0)
+			if err != nil {
+				return err
+			}
+
+			msgs = append(msgs, msg)
+
+			return nil
+		})
+	}, func() {
+		msgs = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,244 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestGossipV2Store(t *testing.T) *GossipV2Store {
+	t.Helper()
+
+	db, err := channeldb.Open(t.TempDir())
+	require.NoError(t, err, "unable to open db")
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	store, err := NewGossipV2Store(db)
+	require.NoError(t, err, "unable to initialize gossip v2 store")
+
+	return store
+}
+
+func testChanUpdate2(scid lnwire.ShortChannelID, blockHeight uint32,
+	isNode1 bool) *lnwire.ChannelUpdate2 {
+
+	upd := &lnwire.ChannelUpdate2{}
+	upd.ShortChannelID.Val = scid
+	upd.BlockHeight.Val = blockHeight
+	if !isNode1 {
+		upd.SecondPeer = tlv.SomeRecordT(
+			tlv.ZeroRecordT[tlv.TlvType8, lnwire.TrueBoolean](),
+		)
+	}
+
+	return upd
+}
+
+// TestGossipV2Store ensures that the taproot channel gossip store keeps the
+// announcement and the latest update of each direction of a channel.
+func TestGossipV2Store(t *testing.T) {
+	t.Parallel()
+
+	store := createTestGossipV2Store(t)
+	scid := lnwire.NewShortChanIDFromInt(1 << 40)
+
+	// Nothing is known about the channel yet.
+	_, err := store.ChannelAnnouncement(scid)
+	require.ErrorIs(t, err, ErrGossipV2ChannelNotFound)
+
+	upd, err := store.ChannelUpdate(scid, true)
+	require.NoError(t, err)
+	require.Nil(t, upd)
+
+	ann := &lnwire.ChannelAnnouncement2{}
+	ann.ShortChannelID.Val = scid
+	ann.Capacity.Val = 100_000
+	require.NoError(t, store.AddMessage(ann))
+
+	storedAnn, err := store.ChannelAnnouncement(scid)
+	require.NoError(t, err)
+	require.Equal(t, scid, storedAnn.SCID())
+	require.EqualValues(t, 100_000, storedAnn.Capacity.Val)
+
+	// Add an update for each direction, then replace the first one with a
+	// newer update.
+	require.NoError(t, store.AddMessage(testChanUpdate2(scid, 10, true)))
+	require.NoError(t, store.AddMessage(testChanUpdate2(scid, 11, false)))
+	require.NoError(t, store.AddMessage(testChanUpdate2(scid, 12, true)))
+
+	upd, err = store.ChannelUpdate(scid, true)
+	require.NoError(t, err)
+	require.True(t, upd.IsNode1())
+	require.EqualValues(t, 12, upd.BlockHeight.Val)
+
+	upd, err = store.ChannelUpdate(scid, false)
+	require.NoError(t, err)
+	require.False(t, upd.IsNode1())
+	require.EqualValues(t, 11, upd.BlockHeight.Val)
+
+	// The announcement must precede the updates of the channel.
+	msgs, err := store.Messages()
+	require.NoError(t, err)
+	require.Len(t, msgs, 3)
+	require.IsType(t, &lnwire.ChannelAnnouncement2{}, msgs[0])
+	require.IsType(t, &lnwire.ChannelUpdate2{}, msgs[1])
+	require.IsType(t, &lnwire.ChannelUpdate2{}, msgs[2])
+
+	// Messages that aren't taproot channel gossip can't be stored.
+	err = store.AddMessage(&lnwire.ChannelUpdate1{})
+	require.ErrorIs(t, err, ErrUnsupportedMessage)
+}
+
+// TestGossipV2StoreDeleteChannel ensures that the funding output of a channel
+// is stored along with its announcement, and that deleting the channel
+// removes all of its messages.
+func TestGossipV2StoreDeleteChannel(t *testing.T) {
+	t.Parallel()
+
+	store := createTestGossipV2Store(t)
+	scid := lnwire.NewShortChanIDFromInt(1 << 40)
+
+	ann := &lnwire.ChannelAnnouncement2{}
+	ann.ShortChannelID.Val = scid
+	edgePoint := &channeldb.EdgePoint{
+		FundingPkScript: []byte{0x51, 0x20, 0x01},
+		OutPoint:        wire.OutPoint{Hash: [32]byte{1}, Index: 2},
+	}
+	require.NoError(t, store.AddChannel(ann, edgePoint))
+	require.NoError(t, store.AddMessage(testChanUpdate2(scid, 10, true)))
+	require.NoError(t, store.AddMessage(testChanUpdate2(scid, 11, false)))
+
+	edgePoints, err := store.EdgePoints()
+	require.NoError(t, err)
+	require.Equal(t, map[lnwire.ShortChannelID]*channeldb.EdgePoint{
+		scid: edgePoint,
+	}, edgePoints)
+
+	require.NoError(t, store.DeleteChannel(scid))
+
+	_, err = store.ChannelAnnouncement(scid)
+	require.ErrorIs(t, err, ErrGossipV2ChannelNotFound)
+
+	msgs, err := store.Messages()
+	require.NoError(t, err)
+	require.Empty(t, msgs)
+
+	edgePoints, err = store.EdgePoints()
+	require.NoError(t, err)
+	require.Empty(t, edgePoints)
+}
+
+// TestGossipV2StoreChannelsInRange ensures that the channels of the store can
+// be listed by block height, and that the messages of a single channel can be
+// fetched.
+func TestGossipV2StoreChannelsInRange(t *testing.T) {
+	t.Parallel()
+
+	store := createTestGossipV2Store(t)
+
+	scids := []lnwire.ShortChannelID{
+		{BlockHeight: 100},
+		{BlockHeight: 200},
+		{BlockHeight: 200, TxIndex: 1},
+		{BlockHeight: 300},
+	}
+	for _, scid := range scids {
+		ann := &lnwire.ChannelAnnouncement2{}
+		ann.ShortChannelID.Val = scid
+		require.NoError(t, store.AddChannel(ann, &channeldb.EdgePoint{}))
+		require.NoError(t, store.AddMessage(
+			testChanUpdate2(scid, scid.BlockHeight, true),
+		))
+	}
+
+	inRange, err := store.ChannelsInRange(150, 250)
+	require.NoError(t, err)
+	require.Equal(t, scids[1:3], inRange)
+
+	inRange, err = store.ChannelsInRange(0, 1000)
+	require.NoError(t, err)
+	require.Equal(t, scids, inRange)
+
+	// Only the messages of the requested channel are returned, with the
+	// announcement preceding the update.
+	msgs, err := store.ChannelMessages(scids[1])
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	require.IsType(t, &lnwire.ChannelAnnouncement2{}, msgs[0])
+	require.Equal(t, scids[1], msgs[1].(*lnwire.ChannelUpdate2).SCID())
+
+	msgs, err = store.ChannelMessages(lnwire.ShortChannelID{})
+	require.NoError(t, err)
+	require.Empty(t, msgs)
+}
+
+// TestGossipV2StoreNodeAnnouncement ensures that node announcements are kept
+// for as long as their node has a taproot channel in the store.
+func TestGossipV2StoreNodeAnnouncement(t *testing.T) {
+	t.Parallel()
+
+	store := createTestGossipV2Store(t)
+	node1, node2, node3 := [33]byte{1}, [33]byte{2}, [33]byte{3}
+
+	addChannel := func(scid lnwire.ShortChannelID, n1, n2 [33]byte) {
+		ann := &lnwire.ChannelAnnouncement2{}
+		ann.ShortChannelID.Val = scid
+		ann.NodeID1.Val = n1
+		ann.NodeID2.Val = n2
+		require.NoError(t, store.AddChannel(ann, &channeldb.EdgePoint{}))
+	}
+	chan1 := lnwire.ShortChannelID{BlockHeight: 100}
+	chan2 := lnwire.ShortChannelID{BlockHeight: 200}
+	addChannel(chan1, node1, node2)
+	addChannel(chan2, node1, node3)
+
+	for _, nodeID := range [][33]byte{node1, node2, node3} {
+		hasChans, err := store.HasNodeChannels(nodeID)
+		require.NoError(t, err)
+		require.True(t, hasChans)
+
+		nodeAnn := &lnwire.NodeAnnouncement2{}
+		nodeAnn.NodeID.Val = nodeID
+		nodeAnn.BlockHeight.Val = 150
+		require.NoError(t, store.AddNodeAnnouncement(nodeAnn))
+	}
+
+	hasChans, err := store.HasNodeChannels([33]byte{4})
+	require.NoError(t, err)
+	require.False(t, hasChans)
+
+	nodeAnn, err := store.NodeAnnouncement([33]byte{4})
+	require.NoError(t, err)
+	require.Nil(t, nodeAnn)
+
+	nodeAnns, err := store.NodeAnnouncements()
+	require.NoError(t, err)
+	require.Len(t, nodeAnns, 3)
+
+	// Once the first channel is deleted, only the announcement of the
+	// node without any other channel is removed.
+	require.NoError(t, store.DeleteChannel(chan1))
+
+	nodeAnn, err = store.NodeAnnouncement(node1)
+	require.NoError(t, err)
+	require.EqualValues(t, 150, nodeAnn.BlockHeight.Val)
+
+	nodeAnn, err = store.NodeAnnouncement(node2)
+	require.NoError(t, err)
+	require.Nil(t, nodeAnn)
+
+	hasChans, err = store.HasNodeChannels(node2)
+	require.NoError(t, err)
+	require.False(t, hasChans)
+
+	nodeAnns, err = store.NodeAnnouncements()
+	require.NoError(t, err)
+	require.Len(t, nodeAnns, 2)
+}
@@ -261,6 +261,10 @@ type Config struct {
 	// use to determine which messages need to be resent for a given peer.
 	MessageStore GossipMessageStore
 
+	// GossipV2Store is a persistent storage of the validated taproot
+	// channel gossip messages. If it is nil, these messages are ignored.
+	GossipV2Store *GossipV2Store
+
 	// AnnSigner is an instance of the MessageSigner interface which will
 	// be used to manually sign any outgoing channel updates. The signer
 	// implementation should be backed by the public key of the backing
@@ -330,6 +334,17 @@ type Config struct {
 	// direction.
 	ChannelUpdateInterval time.Duration
 
+	// PeerMsgRate is the number of gossip messages per second we'll
+	// process from a peer with a neutral reputation. Peers that send
+	// useful gossip are allowed a higher rate, while peers that send
+	// invalid or excess gossip are throttled further. A zero rate disables
+	// the per-peer rate limit.
+	PeerMsgRate float64
+
+	// PeerMsgBurst is the number of gossip messages a peer can send at
+	// once before it is rate limited.
+	PeerMsgBurst int
+
 	// IsAlias returns true if a given ShortChannelID is an alias for
 	// option_scid_alias channels.
 	IsAlias func(scid lnwire.ShortChannelID) bool
@@ -456,6 +471,10 @@ type AuthenticatedGossiper struct {
 	// banman tracks our peer's ban status.
 	banman *banman
 
+	// reputation scores our peers by the gossip they send us, and limits
+	// the rate of gossip messages we process from them accordingly.
+	reputation *reputationManager
+
 	// networkMsgs is a channel that carries new network broadcasted
 	// message from outside the gossiper service to be processed by the
 	// networkHandler.
@@ -535,6 +554,9 @@ func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper
 		),
 		chanUpdateRateLimiter: make(map[uint64][2]*rate.Limiter),
 		banman:                newBanman(),
+		reputation: newReputationManager(
+			cfg.PeerMsgRate, cfg.PeerMsgBurst,
+		),
 	}
 
 	gossiper.syncMgr = newSyncManager(&SyncManagerCfg{
@@ -548,6 +570,7 @@ func New(cfg Config, selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper
 		BestHeight:              gossiper.latestHeight,
 		PinnedSyncers:           cfg.PinnedSyncers,
 		IsStillZombieChannel:    cfg.IsStillZombieChannel,
+		GossipV2Store:           cfg.GossipV2Store,
 	})
 
 	gossiper.reliableSender = newReliableSender(&reliableSenderCfg{
@@ -593,6 +616,49 @@ func (d *AuthenticatedGossiper) PropagateChanPolicyUpdate(
 	}
 }
 
+// ChannelUpdateRateLimit returns the interval and the maximum burst of the
+// rate limit of remote channel updates.
+func (d *AuthenticatedGossiper) ChannelUpdateRateLimit() (time.Duration,
+	int) {
+
+	d.Lock()
+	defer d.Unlock()
+
+	return d.cfg.ChannelUpdateInterval, d.cfg.MaxChannelUpdateBurst
+}
+
+// SetChannelUpdateRateLimit changes the rate limit of remote channel updates.
+// The new limit applies to the rate limiters of all channels, including the
+// ones that were already created.
+func (d *AuthenticatedGossiper) SetChannelUpdateRateLimit(
+	interval time.Duration, burst int) error {
+
+	if interval <= 0 {
+		return fmt.Errorf("channel update interval must be positive, "+
+			"got %v", interval)
+	}
+	if burst <= 0 {
+		return fmt.Errorf("max channel update burst must be positive, "+
+			"got %d", burst)
+	}
+
+	d.Lock()
+	defer d.Unlock()
+
+	d.cfg.ChannelUpdateInterval = interval
+	d.cfg.MaxChannelUpdateBurst = burst
+
+	r := rate.Every(interval)
+	for _, rls := range d.chanUpdateRateLimiter {
+		for _, rl := range rls {
+			rl.SetLimit(r)
+			rl.SetBurst(burst)
+		}
+	}
+
+	return nil
+}
+
 // Start spawns network messages handler goroutine and registers on new block
 // notifications in order to properly handle the premature announcements.
 func (d *AuthenticatedGossiper) Start() error {
@@ -627,6 +693,14 @@ func (d *AuthenticatedGossiper) start() error {
 		return err
 	}
 
+	// Closed taproot channels are pruned from the gossip store once their
+	// funding output is spent.
+	if d.cfg.GossipV2Store != nil {
+		if err := d.watchGossipV2Channels(); err != nil {
+			return err
+		}
+	}
+
 	d.syncMgr.Start()
 
 	d.banman.start()
@@ -860,6 +934,16 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 		errChan <- nil
 		return errChan
 
+	// The taproot channel gossip messages are only processed if their
+	// handling was enabled.
+	case *lnwire.ChannelAnnouncement2, *lnwire.ChannelUpdate2,
+		*lnwire.NodeAnnouncement2:
+
+		if d.cfg.GossipV2Store == nil {
+			errChan <- nil
+			return errChan
+		}
+
 	// To avoid inserting edges in the graph for our own channels that we
 	// have already closed, we ignore such channel announcements coming
 	// from the remote.
@@ -877,6 +961,22 @@ func (d *AuthenticatedGossiper) ProcessRemoteAnnouncement(msg lnwire.Message,
 		}
 	}
 
+	// Once our graph is synced, we'll only process as many gossip
+	// messages from the peer as its reputation allows. During the initial
+	// historical sync, the messages were requested by us, and the same
+	// holds for the messages of a graph snapshot we import.
+	if d.syncMgr.IsGraphSynced() && !isSnapshotPeer(peer) &&
+		!d.reputation.allow(peer.PubKey()) {
+
+		log.Tracef("Rate limiting %v from peer=%x", msg.MsgType(),
+			peer.PubKey())
+
+		d.disconnectIfAbusive(peer)
+
+		errChan <- nil
+		return errChan
+	}
+
 	nMsg := &networkMsg{
 		msg:      msg,
 		isRemote: true,
@@ -987,6 +1087,18 @@ type deDupedAnnouncements struct {
 	// nodeAnnouncements are identified by the Vertex field.
 	nodeAnnouncements map[route.Vertex]msgWithSenders
 
+	// channelAnnouncements2 are the taproot channel announcements,
+	// identified by the short channel id field.
+	channelAnnouncements2 map[lnwire.ShortChannelID]msgWithSenders
+
+	// channelUpdates2 are the taproot channel updates, identified by the
+	// short channel id and the direction of the update.
+	channelUpdates2 map[channelUpdateID]msgWithSenders
+
+	// nodeAnnouncements2 are the announcements of nodes with taproot
+	// channels, identified by the Vertex field.
+	nodeAnnouncements2 map[route.Vertex]msgWithSenders
+
 	sync.Mutex
 }
 
@@ -1008,6 +1120,11 @@ func (d *deDupedAnnouncements) reset() {
 	d.channelAnnouncements = make(map[lnwire.ShortChannelID]msgWithSenders)
 	d.channelUpdates = make(map[channelUpdateID]msgWithSenders)
 	d.nodeAnnouncements = make(map[route.Vertex]msgWithSenders)
+	d.channelAnnouncements2 = make(
+		map[lnwire.ShortChannelID]msgWithSenders,
+	)
+	d.channelUpdates2 = make(map[channelUpdateID]msgWithSenders)
+	d.nodeAnnouncements2 = make(map[route.Vertex]msgWithSenders)
 }
 
 // addMsg adds a new message to the current batch. If the message is already
@@ -1145,6 +1262,103 @@ func (d *deDupedAnnouncements) addMsg(message networkMsg) {
 		mws.msg = msg
 		mws.senders[sender] = struct{}{}
 		d.nodeAnnouncements[deDupKey] = mws
+
+	// Taproot channel announcements are identified by the short channel
+	// id field, like their legacy counterpart.
+	case *lnwire.ChannelAnnouncement2:
+		deDupKey := msg.SCID()
+		sender := route.NewVertex(message.source)
+
+		mws, ok := d.channelAnnouncements2[deDupKey]
+		if !ok {
+			mws = msgWithSenders{
+				msg:     msg,
+				isLocal: !message.isRemote,
+				senders: make(map[route.Vertex]struct{}),
+			}
+		}
+
+		mws.msg = msg
+		mws.senders[sender] = struct{}{}
+		d.channelAnnouncements2[deDupKey] = mws
+
+	// Taproot channel updates are ordered by block height rather than by
+	// timestamp.
+	case *lnwire.ChannelUpdate2:
+		var flags lnwire.ChanUpdateChanFlags
+		if !msg.IsNode1() {
+			flags = lnwire.ChanUpdateDirection
+		}
+		deDupKey := channelUpdateID{msg.SCID(), flags}
+		sender := route.NewVertex(message.source)
+
+		mws, ok := d.channelUpdates2[deDupKey]
+		if ok {
+			age, err := msg.CmpAge(mws.msg.(*lnwire.ChannelUpdate2))
+			if err != nil {
+				log.Errorf("Unable to compare updates: %v",
+					err)
+
+				return
+			}
+
+			// Discard the message if it's older than the one we
+			// have.
+			if age == lnwire.LessThan {
+				return
+			}
+
+			// Add the sender if it's the same as we had.
+			if age == lnwire.EqualTo {
+				mws.msg = msg
+				mws.senders[sender] = struct{}{}
+				d.channelUpdates2[deDupKey] = mws
+
+				return
+			}
+		}
+
+		mws = msgWithSenders{
+			msg:     msg,
+			isLocal: !message.isRemote,
+			senders: make(map[route.Vertex]struct{}),
+		}
+		mws.senders[sender] = struct{}{}
+		d.channelUpdates2[deDupKey] = mws
+
+	// Node announcements of nodes with taproot channels are ordered by
+	// block height rather than by timestamp.
+	case *lnwire.NodeAnnouncement2:
+		deDupKey := route.Vertex(msg.NodeID.Val)
+		sender := route.NewVertex(message.source)
+
+		mws, ok := d.nodeAnnouncements2[deDupKey]
+		if ok {
+			prev := mws.msg.(*lnwire.NodeAnnouncement2)
+
+			// Discard the message if it's older than the one we
+			// have.
+			if msg.BlockHeight.Val < prev.BlockHeight.Val {
+				return
+			}
+
+			// Add the sender if it's the same as we had.
+			if msg.BlockHeight.Val == prev.BlockHeight.Val {
+				mws.msg = msg
+				mws.senders[sender] = struct{}{}
+				d.nodeAnnouncements2[deDupKey] = mws
+
+				return
+			}
+		}
+
+		mws = msgWithSenders{
+			msg:     msg,
+			isLocal: !message.isRemote,
+			senders: make(map[route.Vertex]struct{}),
+		}
+		mws.senders[sender] = struct{}{}
+		d.nodeAnnouncements2[deDupKey] = mws
 	}
 }
 
@@ -1202,7 +1416,8 @@ func (d *deDupedAnnouncements) Emit() msgsToBroadcast {
 
 	// Get the total number of announcements.
 	numAnnouncements := len(d.channelAnnouncements) + len(d.channelUpdates) +
-		len(d.nodeAnnouncements)
+		len(d.nodeAnnouncements) + len(d.channelAnnouncements2) +
+		len(d.channelUpdates2) + len(d.nodeAnnouncements2)
 
 	// Create an empty array of lnwire.Messages with a length equal to
 	// the total number of announcements.
@@ -1215,16 +1430,25 @@ func (d *deDupedAnnouncements) Emit() msgsToBroadcast {
 	for _, message := range d.channelAnnouncements {
 		msgs.addMsg(message)
 	}
+	for _, message := range d.channelAnnouncements2 {
+		msgs.addMsg(message)
+	}
 
 	// Then add the channel updates.
 	for _, message := range d.channelUpdates {
 		msgs.addMsg(message)
 	}
+	for _, message := range d.channelUpdates2 {
+		msgs.addMsg(message)
+	}
 
 	// Finally add the node announcements.
 	for _, message := range d.nodeAnnouncements {
 		msgs.addMsg(message)
 	}
+	for _, message := range d.nodeAnnouncements2 {
+		msgs.addMsg(message)
+	}
 
 	d.reset()
 
@@ -1568,6 +1792,10 @@ func (d *AuthenticatedGossiper) handleNetworkMessages(nMsg *networkMsg,
 	// continue.
 	vb.SignalDependants(nMsg.msg, allow)
 
+	if nMsg.isRemote && !isSnapshotPeer(nMsg.peer) {
+		d.updateReputation(nMsg, newAnns)
+	}
+
 	// If the announcement was accepted, then add the emitted announcements
 	// to our announce batch to be broadcast once the trickle timer ticks
 	// gain.
@@ -1610,6 +1838,12 @@ func (d *AuthenticatedGossiper) isRecentlyRejectedMsg(msg lnwire.Message,
 	case *lnwire.ChannelAnnouncement1:
 		scid = m.ShortChannelID.ToUint64()
 
+	case *lnwire.ChannelUpdate2:
+		scid = m.SCID().ToUint64()
+
+	case *lnwire.ChannelAnnouncement2:
+		scid = m.SCID().ToUint64()
+
 	default:
 		return false
 	}
@@ -2073,6 +2307,17 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 	case *lnwire.AnnounceSignatures1:
 		return d.handleAnnSig(nMsg, msg)
 
+	// A new taproot channel announcement or update has arrived. These are
+	// only stored in the gossip store until the graph can hold them.
+	case *lnwire.ChannelAnnouncement2:
+		return d.handleChanAnnouncement2(nMsg, msg)
+
+	case *lnwire.ChannelUpdate2:
+		return d.handleChanUpdate2(nMsg, msg)
+
+	case *lnwire.NodeAnnouncement2:
+		return d.handleNodeAnnouncement2(nMsg, msg)
+
 	default:
 		err := errors.New("wrong type of the announcement")
 		nMsg.err <- err
@@ -3549,6 +3794,55 @@ func (d *AuthenticatedGossiper) handleAnnSig(nMsg *networkMsg,
 	return announcements, true
 }
 
+// updateReputation updates the gossip reputation of the peer that sent the
+// given message based on the outcome of its processing. Messages that were
+// added to the reject cache count as invalid.
+func (d *AuthenticatedGossiper) updateReputation(nMsg *networkMsg,
+	newAnns []networkMsg) {
+
+	pubkey := nMsg.peer.PubKey()
+	switch {
+	case newAnns != nil:
+		d.reputation.recordAccepted(pubkey)
+
+	case d.isRecentlyRejectedMsg(nMsg.msg, pubkey):
+		d.reputation.recordInvalid(pubkey)
+		d.disconnectIfAbusive(nMsg.peer)
+
+	default:
+		d.reputation.recordStale(pubkey)
+	}
+}
+
+// disconnectIfAbusive disconnects the given peer if its gossip reputation
+// dropped to the abusive score and we don't have a channel with it.
+func (d *AuthenticatedGossiper) disconnectIfAbusive(peer lnpeer.Peer) {
+	if !d.reputation.isAbusive(peer.PubKey()) {
+		return
+	}
+
+	shouldDc, err := d.ShouldDisconnect(peer.IdentityKey())
+	if err != nil {
+		log.Errorf("failed to check if we should disconnect peer: %v",
+			err)
+
+		return
+	}
+
+	if shouldDc {
+		log.Warnf("Disconnecting peer=%x due to its gossip reputation",
+			peer.PubKey())
+
+		peer.Disconnect(ErrPeerAbusive)
+	}
+}
+
+// PeerReputations returns the gossip reputation of the peers we received
+// gossip from.
+func (d *AuthenticatedGossiper) PeerReputations() []PeerReputation {
+	return d.reputation.reputations()
+}
+
 // isBanned returns true if the peer identified by pubkey is banned for sending
 // invalid channel announcements.
 func (d *AuthenticatedGossiper) isBanned(pubkey [33]byte) bool {
@@ -3565,9 +3859,9 @@ func (d *AuthenticatedGossiper) ShouldDisconnect(pubkey *btcec.PublicKey) (
 	var pubkeyBytes [33]byte
 	copy(pubkeyBytes[:], pubkeySer)
 
-	// If the public key is banned, check whether or not this is a channel
-	// peer.
-	if d.isBanned(pubkeyBytes) {
+	// If the public key is banned or its gossip reputation is abusive,
+	// check whether or not this is a channel peer.
+	if d.isBanned(pubkeyBytes) || d.reputation.isAbusive(pubkeyBytes) {
 		isChanPeer, err := d.cfg.ScidCloser.IsChannelPeer(pubkey)
 		if err != nil {
 			return false, err
@@ -0,0 +1,513 @@
+package discovery
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chanvalidate"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// rejectV2 adds the given taproot channel gossip message to the reject cache
+// of its sender, and sends the error back to the caller.
+func (d *AuthenticatedGossiper) rejectV2(nMsg *networkMsg,
+	scid lnwire.ShortChannelID, err error) {
+
+	log.Debugf("Rejecting %v for short_chan_id=%v from peer=%v: %v",
+		nMsg.msg.MsgType(), scid, nMsg.peer, err)
+
+	if nMsg.isRemote {
+		key := newRejectCacheKey(
+			scid.ToUint64(), sourceToPub(nMsg.source),
+		)
+		_, _ = d.recentRejects.Put(key, &cachedReject{})
+	}
+
+	nMsg.err <- err
+}
+
+// verifyTaprootFundingOutput checks that the given on-chain output script of
+// a taproot channel announcement is a taproot output, and that it commits to
+// the announced bitcoin keys if they are present.
+func verifyTaprootFundingOutput(ann *lnwire.ChannelAnnouncement2,
+	pkScript []byte) error {
+
+	scid := ann.SCID()
+	if !txscript.IsPayToTaproot(pkScript) {
+		return fmt.Errorf("funding output of short_chan_id=%v is not "+
+			"a taproot output", scid)
+	}
+
+	// Without the bitcoin keys, the output key is part of the signature,
+	// which is verified separately.
+	if ann.BitcoinKey1.IsNone() || ann.BitcoinKey2.IsNone() {
+		return nil
+	}
+
+	var (
+		btcKey1 tlv.RecordT[tlv.TlvType12, [33]byte]
+		btcKey2 tlv.RecordT[tlv.TlvType14, [33]byte]
+	)
+	btcKey1 = ann.BitcoinKey1.UnwrapOr(btcKey1)
+	btcKey2 = ann.BitcoinKey2.UnwrapOr(btcKey2)
+
+	bitcoinKey1, err := btcec.ParsePubKey(btcKey1.Val[:])
+	if err != nil {
+		return err
+	}
+	bitcoinKey2, err := btcec.ParsePubKey(btcKey2.Val[:])
+	if err != nil {
+		return err
+	}
+
+	tapscriptRoot := fn.None[chainhash.Hash]()
+	ann.MerkleRootHash.WhenSomeV(func(r [32]byte) {
+		tapscriptRoot = fn.Some(chainhash.Hash(r))
+	})
+
+	fundingScript, _, err := input.GenTaprootFundingScript(
+		bitcoinKey1, bitcoinKey2, int64(ann.Capacity.Val),
+		tapscriptRoot,
+	)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(fundingScript, pkScript) {
+		return fmt.Errorf("funding output of short_chan_id=%v doesn't "+
+			"match the announced bitcoin keys", scid)
+	}
+
+	return nil
+}
+
+// verifyTaprootFundingUtxo checks that the unspent funding output of a taproot
+// channel announcement carries the announced capacity.
+func verifyTaprootFundingUtxo(ann *lnwire.ChannelAnnouncement2,
+	utxo *wire.TxOut) error {
+
+	capacity := btcutil.Amount(ann.Capacity.Val)
+	if btcutil.Amount(utxo.Value) != capacity {
+		return fmt.Errorf("funding output of short_chan_id=%v has "+
+			"value %v, announced capacity is %v", ann.SCID(),
+			btcutil.Amount(utxo.Value), capacity)
+	}
+
+	return nil
+}
+
+// fetchTaprootFundingOutput fetches the funding output of the given taproot
+// channel announcement from the chain. The output is only returned if it
+// commits to the announced keys, is still unspent and carries the announced
+// capacity.
+func (d *AuthenticatedGossiper) fetchTaprootFundingOutput(
+	ann *lnwire.ChannelAnnouncement2) (*channeldb.EdgePoint, error) {
+
+	scid := ann.SCID()
+	fundingTx, err := lnwallet.FetchFundingTxWrapper(
+		d.cfg.ChainIO, &scid, d.quit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch funding tx of "+
+			"short_chan_id=%v: %w", scid, err)
+	}
+
+	locator := chanvalidate.ShortChanIDChanLocator{ID: scid}
+	fundingOut, fundingPoint, err := locator.Locate(fundingTx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to locate funding output of "+
+			"short_chan_id=%v: %w", scid, err)
+	}
+
+	err = verifyTaprootFundingOutput(ann, fundingOut.PkScript)
+	if err != nil {
+		return nil, err
+	}
+
+	// A channel whose funding output was spent has been closed, so there
+	// is no point in announcing it.
+	utxo, err := d.cfg.ChainIO.GetUtxo(
+		fundingPoint, fundingOut.PkScript, scid.BlockHeight, d.quit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch utxo of "+
+			"short_chan_id=%v, chan_point=%v: %w", scid,
+			fundingPoint, err)
+	}
+
+	if err := verifyTaprootFundingUtxo(ann, utxo); err != nil {
+		return nil, err
+	}
+
+	return &channeldb.EdgePoint{
+		FundingPkScript: fundingOut.PkScript,
+		OutPoint:        *fundingPoint,
+	}, nil
+}
+
+// watchGossipV2Channels watches the funding outputs of all channels in the
+// taproot channel gossip store for spends.
+func (d *AuthenticatedGossiper) watchGossipV2Channels() error {
+	edgePoints, err := d.cfg.GossipV2Store.EdgePoints()
+	if err != nil {
+		return err
+	}
+
+	for scid, edgePoint := range edgePoints {
+		if err := d.watchGossipV2Channel(scid, edgePoint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// watchGossipV2Channel registers for the spend of the funding output of the
+// given taproot channel, so that the channel is pruned from the gossip store
+// once it's closed.
+func (d *AuthenticatedGossiper) watchGossipV2Channel(
+	scid lnwire.ShortChannelID, edgePoint *channeldb.EdgePoint) error {
+
+	spendEvent, err := d.cfg.Notifier.RegisterSpendNtfn(
+		&edgePoint.OutPoint, edgePoint.FundingPkScript,
+		scid.BlockHeight,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to register spend of "+
+			"short_chan_id=%v: %w", scid, err)
+	}
+
+	d.wg.Add(1)
+	go d.pruneGossipV2Channel(scid, spendEvent)
+
+	return nil
+}
+
+// pruneGossipV2Channel waits for the funding output of the given taproot
+// channel to be spent, after which the channel is removed from the gossip
+// store.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *AuthenticatedGossiper) pruneGossipV2Channel(
+	scid lnwire.ShortChannelID, spendEvent *chainntnfs.SpendEvent) {
+
+	defer d.wg.Done()
+	defer spendEvent.Cancel()
+
+	select {
+	case spend, ok := <-spendEvent.Spend:
+		if !ok {
+			return
+		}
+
+		log.Debugf("Funding output of short_chan_id=%v spent by %v, "+
+			"pruning channel from gossip store", scid,
+			spend.SpenderTxHash)
+
+		err := d.cfg.GossipV2Store.DeleteChannel(scid)
+		if err != nil {
+			log.Errorf("Unable to prune short_chan_id=%v from "+
+				"gossip store: %v", scid, err)
+		}
+
+	case <-d.quit:
+	}
+}
+
+// handleChanAnnouncement2 processes a new taproot channel announcement. The
+// announcement is validated against the chain and stored, after which it is
+// relayed to the rest of the network.
+func (d *AuthenticatedGossiper) handleChanAnnouncement2(nMsg *networkMsg,
+	ann *lnwire.ChannelAnnouncement2) ([]networkMsg, bool) {
+
+	scid := ann.SCID()
+
+	log.Debugf("Processing ChannelAnnouncement2: peer=%v, short_chan_id=%v",
+		nMsg.peer, scid.ToUint64())
+
+	if ann.ChainHash.Val != d.cfg.ChainHash {
+		err := fmt.Errorf("ignoring ChannelAnnouncement2 from "+
+			"chain=%v, gossiper on chain=%v", ann.ChainHash.Val,
+			d.cfg.ChainHash)
+		d.rejectV2(nMsg, scid, err)
+
+		return nil, false
+	}
+
+	// If we already know the channel, there's nothing left to do, but its
+	// updates can be processed.
+	_, err := d.cfg.GossipV2Store.ChannelAnnouncement(scid)
+	switch {
+	case err == nil:
+		nMsg.err <- nil
+		return nil, true
+
+	case !errors.Is(err, ErrGossipV2ChannelNotFound):
+		nMsg.err <- err
+		return nil, false
+	}
+
+	// If the advertised inclusionary block is beyond our knowledge of the
+	// chain tip, then we'll ignore it for now.
+	d.Lock()
+	if nMsg.isRemote && d.isPremature(scid, 0, nMsg) {
+		log.Warnf("Announcement for chan_id=(%v), is premature: "+
+			"advertises height %v, only height %v is known",
+			scid.ToUint64(), scid.BlockHeight, d.bestHeight)
+		d.Unlock()
+		nMsg.err <- nil
+		return nil, false
+	}
+	d.Unlock()
+
+	edgePoint, err := d.fetchTaprootFundingOutput(ann)
+	if err != nil {
+		d.rejectV2(nMsg, scid, err)
+		return nil, false
+	}
+
+	err = netann.ValidateChannelAnn(
+		ann, func(*lnwire.ShortChannelID) ([]byte, error) {
+			return edgePoint.FundingPkScript, nil
+		},
+	)
+	if err != nil {
+		err := fmt.Errorf("unable to validate ChannelAnnouncement2 "+
+			"for short_chan_id=%v: %w", scid, err)
+		d.rejectV2(nMsg, scid, err)
+
+		return nil, false
+	}
+
+	err = d.cfg.GossipV2Store.AddChannel(ann, edgePoint)
+	if err != nil {
+		nMsg.err <- err
+		return nil, false
+	}
+
+	// Once the channel is closed, it's pruned from the store again.
+	if err := d.watchGossipV2Channel(scid, edgePoint); err != nil {
+		nMsg.err <- err
+		return nil, false
+	}
+
+	log.Debugf("Stored ChannelAnnouncement2 for short_chan_id=%v",
+		scid.ToUint64())
+
+	nMsg.err <- nil
+
+	return []networkMsg{{
+		msg:      ann,
+		isRemote: nMsg.isRemote,
+		peer:     nMsg.peer,
+		source:   nMsg.source,
+	}}, true
+}
+
+// handleChanUpdate2 processes a new update of a taproot channel. The update
+// is validated against the stored channel announcement and stored if it is
+// newer than the one we know, after which it is relayed to the rest of the
+// network.
+func (d *AuthenticatedGossiper) handleChanUpdate2(nMsg *networkMsg,
+	upd *lnwire.ChannelUpdate2) ([]networkMsg, bool) {
+
+	scid := upd.SCID()
+
+	log.Debugf("Processing ChannelUpdate2: peer=%v, short_chan_id=%v",
+		nMsg.peer, scid.ToUint64())
+
+	if upd.ChainHash.Val != d.cfg.ChainHash {
+		err := fmt.Errorf("ignoring ChannelUpdate2 from chain=%v, "+
+			"gossiper on chain=%v", upd.ChainHash.Val,
+			d.cfg.ChainHash)
+		d.rejectV2(nMsg, scid, err)
+
+		return nil, false
+	}
+
+	// The block height of an update orders it, so it can't be older than
+	// the channel. Updates from beyond the chain tip we know are ignored
+	// for now, as the peer may just be ahead of us.
+	blockHeight := upd.BlockHeight.Val
+	if blockHeight < scid.BlockHeight {
+		err := fmt.Errorf("ChannelUpdate2 for short_chan_id=%v has "+
+			"block height %v below the funding height", scid,
+			blockHeight)
+		d.rejectV2(nMsg, scid, err)
+
+		return nil, false
+	}
+
+	d.Lock()
+	bestHeight := d.bestHeight
+	d.Unlock()
+
+	if blockHeight > bestHeight {
+		log.Debugf("Ignoring ChannelUpdate2 for short_chan_id=%v at "+
+			"height %v, only height %v is known", scid,
+			blockHeight, bestHeight)
+		nMsg.err <- nil
+
+		return nil, false
+	}
+
+	// Updates of channels we don't know aren't added to the reject cache,
+	// so that the channel can still be announced by the same peer.
+	ann, err := d.cfg.GossipV2Store.ChannelAnnouncement(scid)
+	if err != nil {
+		nMsg.err <- fmt.Errorf("unable to fetch ChannelAnnouncement2 "+
+			"for short_chan_id=%v: %w", scid, err)
+
+		return nil, false
+	}
+
+	prev, err := d.cfg.GossipV2Store.ChannelUpdate(scid, upd.IsNode1())
+	if err != nil {
+		nMsg.err <- err
+		return nil, false
+	}
+	if prev != nil {
+		age, err := upd.CmpAge(prev)
+		if err != nil {
+			nMsg.err <- err
+			return nil, false
+		}
+
+		if age != lnwire.GreaterThan {
+			log.Debugf("Ignored stale ChannelUpdate2 for "+
+				"short_chan_id=%v at height %v", scid,
+				blockHeight)
+			nMsg.err <- nil
+
+			return nil, true
+		}
+	}
+
+	nodeKey := ann.NodeID1.Val
+	if !upd.IsNode1() {
+		nodeKey = ann.NodeID2.Val
+	}
+	pubKey, err := btcec.ParsePubKey(nodeKey[:])
+	if err != nil {
+		nMsg.err <- err
+		return nil, false
+	}
+
+	capacity := btcutil.Amount(ann.Capacity.Val)
+	err = netann.ValidateChannelUpdateAnn(pubKey, capacity, upd)
+	if err != nil {
+		err := fmt.Errorf("unable to validate ChannelUpdate2 for "+
+			"short_chan_id=%v: %w", scid, err)
+		d.rejectV2(nMsg, scid, err)
+
+		return nil, false
+	}
+
+	if err := d.cfg.GossipV2Store.AddMessage(upd); err != nil {
+		nMsg.err <- err
+		return nil, false
+	}
+
+	nMsg.err <- nil
+
+	return []networkMsg{{
+		msg:      upd,
+		isRemote: nMsg.isRemote,
+		peer:     nMsg.peer,
+		source:   nMsg.source,
+	}}, true
+}
+
+// handleNodeAnnouncement2 processes a new announcement of a node with taproot
+// channels. The announcement is only stored if the node has a taproot channel
+// in the gossip store and the announcement is newer than the one we know,
+// after which it is relayed to the rest of the network.
+func (d *AuthenticatedGossiper) handleNodeAnnouncement2(nMsg *networkMsg,
+	ann *lnwire.NodeAnnouncement2) ([]networkMsg, bool) {
+
+	nodeID := ann.NodeID.Val
+
+	log.Debugf("Processing NodeAnnouncement2: peer=%v, node=%x",
+		nMsg.peer, nodeID)
+
+	// Announcements from beyond the chain tip we know are ignored for
+	// now, as the peer may just be ahead of us.
+	d.Lock()
+	bestHeight := d.bestHeight
+	d.Unlock()
+
+	blockHeight := ann.BlockHeight.Val
+	if blockHeight > bestHeight {
+		log.Debugf("Ignoring NodeAnnouncement2 for node=%x at height "+
+			"%v, only height %v is known", nodeID, blockHeight,
+			bestHeight)
+		nMsg.err <- nil
+
+		return nil, false
+	}
+
+	// Like the legacy node announcements, the announcements of nodes
+	// without any channels we know of are ignored.
+	hasChans, err := d.cfg.GossipV2Store.HasNodeChannels(nodeID)
+	if err != nil {
+		nMsg.err <- err
+		return nil, false
+	}
+	if !hasChans {
+		log.Debugf("Ignoring NodeAnnouncement2 for node=%x without "+
+			"taproot channels", nodeID)
+		nMsg.err <- nil
+
+		return nil, false
+	}
+
+	prev, err := d.cfg.GossipV2Store.NodeAnnouncement(nodeID)
+	if err != nil {
+		nMsg.err <- err
+		return nil, false
+	}
+	if prev != nil && blockHeight <= prev.BlockHeight.Val {
+		log.Debugf("Ignored stale NodeAnnouncement2 for node=%x at "+
+			"height %v", nodeID, blockHeight)
+		nMsg.err <- nil
+
+		return nil, true
+	}
+
+	if err := netann.ValidateNodeAnn2(ann); err != nil {
+		err := fmt.Errorf("unable to validate NodeAnnouncement2 for "+
+			"node=%x: %w", nodeID, err)
+		log.Debugf("Rejecting NodeAnnouncement2 from peer=%v: %v",
+			nMsg.peer, err)
+		nMsg.err <- err
+
+		return nil, false
+	}
+
+	if err := d.cfg.GossipV2Store.AddNodeAnnouncement(ann); err != nil {
+		nMsg.err <- err
+		return nil, false
+	}
+
+	nMsg.err <- nil
+
+	return []networkMsg{{
+		msg:      ann,
+		isRemote: nMsg.isRemote,
+		peer:     nMsg.peer,
+		source:   nMsg.source,
+	}}, true
+}
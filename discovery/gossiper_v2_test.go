@@ -0,0 +1,251 @@
+package discovery
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// mockV2Chain is a chain backend that holds a single block with the funding
+// transaction of a taproot channel.
+type mockV2Chain struct {
+	mock.ChainIO
+
+	block *wire.MsgBlock
+	spent bool
+}
+
+func (c *mockV2Chain) GetBlockHash(int64) (*chainhash.Hash, error) {
+	hash := c.block.BlockHash()
+	return &hash, nil
+}
+
+func (c *mockV2Chain) GetBlock(*chainhash.Hash) (*wire.MsgBlock, error) {
+	return c.block, nil
+}
+
+func (c *mockV2Chain) GetUtxo(op *wire.OutPoint, _ []byte, _ uint32,
+	_ <-chan struct{}) (*wire.TxOut, error) {
+
+	if c.spent {
+		return nil, errors.New("output spent")
+	}
+
+	return c.block.Transactions[0].TxOut[op.Index], nil
+}
+
+// TestFetchTaprootFundingOutput asserts that the funding output of a taproot
+// channel announcement is only accepted if it commits to the announced keys,
+// is unspent and carries the announced capacity.
+func TestFetchTaprootFundingOutput(t *testing.T) {
+	t.Parallel()
+
+	key1, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	key2, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	const capacity = 1_000_000
+	_, fundingOut, err := input.GenTaprootFundingScript(
+		key1.PubKey(), key2.PubKey(), capacity, fn.None[chainhash.Hash](),
+	)
+	require.NoError(t, err)
+
+	fundingTx := wire.NewMsgTx(2)
+	fundingTx.AddTxOut(fundingOut)
+	chain := &mockV2Chain{
+		block: &wire.MsgBlock{Transactions: []*wire.MsgTx{fundingTx}},
+	}
+
+	gossiper := &AuthenticatedGossiper{
+		cfg:  &Config{ChainIO: chain},
+		quit: make(chan struct{}),
+	}
+
+	newAnn := func(capacity uint64,
+		pub1, pub2 *btcec.PublicKey) *lnwire.ChannelAnnouncement2 {
+
+		ann := &lnwire.ChannelAnnouncement2{}
+		ann.ShortChannelID.Val = lnwire.ShortChannelID{BlockHeight: 1}
+		ann.Capacity.Val = capacity
+
+		var btcKey1, btcKey2 [33]byte
+		copy(btcKey1[:], pub1.SerializeCompressed())
+		copy(btcKey2[:], pub2.SerializeCompressed())
+		ann.BitcoinKey1 = tlv.SomeRecordT(
+			tlv.NewPrimitiveRecord[tlv.TlvType12](btcKey1),
+		)
+		ann.BitcoinKey2 = tlv.SomeRecordT(
+			tlv.NewPrimitiveRecord[tlv.TlvType14](btcKey2),
+		)
+
+		return ann
+	}
+
+	// An unspent output that matches the announcement is accepted.
+	ann := newAnn(capacity, key1.PubKey(), key2.PubKey())
+	edgePoint, err := gossiper.fetchTaprootFundingOutput(ann)
+	require.NoError(t, err)
+	require.Equal(t, fundingOut.PkScript, edgePoint.FundingPkScript)
+	require.Equal(t, fundingTx.TxHash(), edgePoint.OutPoint.Hash)
+
+	// The capacity must match the value of the output.
+	ann = newAnn(capacity+1, key1.PubKey(), key2.PubKey())
+	_, err = gossiper.fetchTaprootFundingOutput(ann)
+	require.ErrorContains(t, err, "announced capacity")
+
+	// Keys that the output doesn't commit to are rejected.
+	ann = newAnn(capacity, key1.PubKey(), key1.PubKey())
+	_, err = gossiper.fetchTaprootFundingOutput(ann)
+	require.ErrorContains(t, err, "doesn't match")
+
+	// A spent output is rejected.
+	chain.spent = true
+	ann = newAnn(capacity, key1.PubKey(), key2.PubKey())
+	_, err = gossiper.fetchTaprootFundingOutput(ann)
+	require.ErrorContains(t, err, "output spent")
+}
+
+// TestPruneGossipV2Channel asserts that a taproot channel is pruned from the
+// gossip store once its funding output is spent.
+func TestPruneGossipV2Channel(t *testing.T) {
+	t.Parallel()
+
+	store := createTestGossipV2Store(t)
+	scid := lnwire.NewShortChanIDFromInt(1 << 40)
+
+	ann := &lnwire.ChannelAnnouncement2{}
+	ann.ShortChannelID.Val = scid
+	edgePoint := &channeldb.EdgePoint{
+		OutPoint: wire.OutPoint{Hash: [32]byte{1}},
+	}
+	require.NoError(t, store.AddChannel(ann, edgePoint))
+
+	notifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail, 1),
+	}
+	gossiper := &AuthenticatedGossiper{
+		cfg: &Config{
+			GossipV2Store: store,
+			Notifier:      notifier,
+		},
+		quit: make(chan struct{}),
+	}
+	t.Cleanup(func() {
+		close(gossiper.quit)
+		gossiper.wg.Wait()
+	})
+
+	// The channels of the store are watched on startup.
+	require.NoError(t, gossiper.watchGossipV2Channels())
+
+	notifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpentOutPoint: &edgePoint.OutPoint,
+	}
+
+	require.Eventually(t, func() bool {
+		_, err := store.ChannelAnnouncement(scid)
+		return errors.Is(err, ErrGossipV2ChannelNotFound)
+	}, time.Second, 10*time.Millisecond)
+}
+
+// TestHandleNodeAnnouncement2 asserts that the announcement of a node is only
+// stored if the node has a taproot channel, the announcement is newer than the
+// one we know and it's signed by the node.
+func TestHandleNodeAnnouncement2(t *testing.T) {
+	t.Parallel()
+
+	nodeKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	var nodeID [33]byte
+	copy(nodeID[:], nodeKey.PubKey().SerializeCompressed())
+
+	store := createTestGossipV2Store(t)
+	gossiper := &AuthenticatedGossiper{
+		cfg:        &Config{GossipV2Store: store},
+		bestHeight: 1000,
+	}
+
+	newNodeAnn := func(height uint32) *lnwire.NodeAnnouncement2 {
+		ann := &lnwire.NodeAnnouncement2{}
+		ann.Features.Val = *lnwire.NewRawFeatureVector()
+		ann.NodeID.Val = nodeID
+		ann.BlockHeight.Val = height
+
+		digest, err := netann.NodeAnn2DigestToSign(ann)
+		require.NoError(t, err)
+
+		sig, err := schnorr.Sign(nodeKey, digest[:])
+		require.NoError(t, err)
+
+		ann.Signature, err = lnwire.NewSigFromSignature(sig)
+		require.NoError(t, err)
+
+		return ann
+	}
+
+	// process hands the announcement to the gossiper and returns whether
+	// it's relayed along with the error sent back to the peer.
+	process := func(ann *lnwire.NodeAnnouncement2) (bool, error) {
+		nMsg := &networkMsg{
+			msg:      ann,
+			isRemote: true,
+			err:      make(chan error, 1),
+		}
+		msgs, _ := gossiper.handleNodeAnnouncement2(nMsg, ann)
+
+		return len(msgs) == 1, <-nMsg.err
+	}
+
+	// The announcement of a node without taproot channels is ignored.
+	relayed, err := process(newNodeAnn(900))
+	require.NoError(t, err)
+	require.False(t, relayed)
+
+	chanAnn := &lnwire.ChannelAnnouncement2{}
+	chanAnn.ShortChannelID.Val = lnwire.ShortChannelID{BlockHeight: 800}
+	chanAnn.NodeID1.Val = nodeID
+	require.NoError(t, store.AddChannel(chanAnn, &channeldb.EdgePoint{}))
+
+	// Now the announcement is stored and relayed.
+	relayed, err = process(newNodeAnn(900))
+	require.NoError(t, err)
+	require.True(t, relayed)
+
+	stored, err := store.NodeAnnouncement(nodeID)
+	require.NoError(t, err)
+	require.EqualValues(t, 900, stored.BlockHeight.Val)
+
+	// Announcements that aren't newer, or are beyond the chain tip, are
+	// ignored.
+	for _, height := range []uint32{900, 1001} {
+		relayed, err = process(newNodeAnn(height))
+		require.NoError(t, err)
+		require.False(t, relayed)
+	}
+
+	// An announcement with an invalid signature is rejected.
+	ann := newNodeAnn(950)
+	ann.BlockHeight.Val = 960
+	_, err = process(ann)
+	require.ErrorContains(t, err, "invalid signature")
+
+	stored, err = store.NodeAnnouncement(nodeID)
+	require.NoError(t, err)
+	require.EqualValues(t, 900, stored.BlockHeight.Val)
+}
@@ -0,0 +1,300 @@
+package discovery
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lightninglabs/neutrino/cache"
+	"github.com/lightninglabs/neutrino/cache/lru"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultPeerMsgRate is the default number of gossip messages per
+	// second that we'll process from a peer with a neutral reputation.
+	DefaultPeerMsgRate = 10
+
+	// DefaultPeerMsgBurst is the default number of gossip messages that a
+	// peer can send at once before it is rate limited. This allows a peer
+	// to relay the announcement batch of a full trickle interval at once.
+	DefaultPeerMsgBurst = 1_000
+
+	// maxReputationPeers limits the maximum number of peers whose
+	// reputation we'll track.
+	maxReputationPeers = 10_000
+
+	// maxReputationScore is the score at which a peer is allowed to send
+	// twice the neutral rate of gossip messages.
+	maxReputationScore = 100
+
+	// minReputationScore is the score at which a peer is only allowed to
+	// send a tenth of the neutral rate of gossip messages.
+	minReputationScore = -100
+
+	// abusiveScore is the score at or below which a peer is considered
+	// abusive. Abusive peers are disconnected unless we have a channel
+	// with them. As scores decay over time, a peer that dropped to the
+	// minimum score won't be allowed to reconnect for a half-life.
+	abusiveScore = -50
+
+	// minRateFactor is the fraction of the neutral rate of gossip messages
+	// a peer with the minimum score is allowed to send.
+	minRateFactor = 0.1
+
+	// acceptedScore is added to the score of a peer for each of its
+	// gossip messages that was new to us.
+	acceptedScore = 1
+
+	// invalidScore is added to the score of a peer for each of its gossip
+	// messages that we rejected.
+	invalidScore = -5
+
+	// throttledScore is added to the score of a peer for each of its
+	// gossip messages that was dropped because it exceeded its rate.
+	throttledScore = -1
+
+	// reputationHalfLife is the time after which the score of a peer has
+	// decayed to half of its value, such that a peer can't build up a
+	// reputation once and misbehave with it forever, and a throttled peer
+	// is forgiven eventually.
+	reputationHalfLife = 30 * time.Minute
+)
+
+// ErrPeerAbusive is the error a peer is disconnected with when its gossip
+// reputation dropped to the abusive score.
+var ErrPeerAbusive = errors.New("peer sent too much invalid or excess " +
+	"gossip - disconnecting")
+
+// PeerReputation is a snapshot of the gossip reputation of a peer.
+type PeerReputation struct {
+	// Peer is the public key of the peer.
+	Peer [33]byte
+
+	// Score is the current score of the peer, ranging from -100 for peers
+	// that send invalid or excess gossip to 100 for peers that send useful
+	// gossip. Peers with a score of -50 or below are disconnected.
+	Score float64
+
+	// MsgRate is the number of gossip messages per second the peer is
+	// currently allowed to send. It is zero if the rate isn't limited.
+	MsgRate float64
+
+	// Accepted is the number of gossip messages of the peer that were new
+	// to us.
+	Accepted uint64
+
+	// Stale is the number of gossip messages of the peer that we already
+	// knew about or couldn't process yet.
+	Stale uint64
+
+	// Invalid is the number of gossip messages of the peer that we
+	// rejected.
+	Invalid uint64
+
+	// Throttled is the number of gossip messages of the peer that were
+	// dropped because the peer exceeded its rate.
+	Throttled uint64
+
+	// LastUpdate is the time the score of the peer was last updated.
+	LastUpdate time.Time
+}
+
+// peerReputation tracks the gossip reputation of a single peer.
+type peerReputation struct {
+	PeerReputation
+
+	// limiter limits the rate of gossip messages we'll process from the
+	// peer. Its limit is adjusted whenever the score of the peer changes.
+	limiter *rate.Limiter
+}
+
+// Size returns the "size" of an entry.
+func (p *peerReputation) Size() (uint64, error) {
+	return 1, nil
+}
+
+// reputationManager scores our peers by the usefulness and validity of the
+// gossip they send us. The score of a peer determines the rate of gossip
+// messages we process from it, and peers whose score drops to the abusive
+// score are disconnected. Like the banman, the reputations are in-memory
+// only and reset upon restart of lnd.
+type reputationManager struct {
+	// msgRate is the number of gossip messages per second we'll process
+	// from a peer with a neutral score. A zero rate disables the rate
+	// limit, while the scores are still tracked.
+	msgRate float64
+
+	// msgBurst is the number of gossip messages a peer can send at once
+	// before it is rate limited.
+	msgBurst int
+
+	// peers tracks the reputation of our peers. It uses an LRU cache to
+	// bound the memory used in case there are many peers.
+	peers *lru.Cache[[33]byte, *peerReputation]
+
+	// mu serializes the updates of the cached reputations.
+	mu sync.Mutex
+}
+
+// newReputationManager creates a new reputationManager that processes up to
+// msgRate gossip messages per second with a burst of msgBurst from peers with
+// a neutral score.
+func newReputationManager(msgRate float64, msgBurst int) *reputationManager {
+	return &reputationManager{
+		msgRate:  msgRate,
+		msgBurst: msgBurst,
+		peers: lru.NewCache[[33]byte, *peerReputation](
+			maxReputationPeers,
+		),
+	}
+}
+
+// rateFactor returns the factor the neutral rate of gossip messages is scaled
+// with for a peer with the given score.
+func rateFactor(score float64) float64 {
+	if score >= 0 {
+		return 1 + score/maxReputationScore
+	}
+
+	return 1 - (1-minRateFactor)*score/minReputationScore
+}
+
+// fetch returns the reputation of the given peer, creating a neutral one if
+// the peer isn't known yet. The score of the peer is decayed up to now.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (r *reputationManager) fetch(pubkey [33]byte,
+	now time.Time) *peerReputation {
+
+	rep, err := r.peers.Get(pubkey)
+	if errors.Is(err, cache.ErrElementNotFound) {
+		rep = &peerReputation{
+			PeerReputation: PeerReputation{
+				Peer:       pubkey,
+				MsgRate:    r.msgRate,
+				LastUpdate: now,
+			},
+			limiter: rate.NewLimiter(
+				rate.Limit(r.msgRate), r.msgBurst,
+			),
+		}
+		_, _ = r.peers.Put(pubkey, rep)
+
+		return rep
+	}
+
+	elapsed := now.Sub(rep.LastUpdate)
+	if elapsed > 0 {
+		halvings := float64(elapsed) / float64(reputationHalfLife)
+		r.setScore(rep, rep.Score*math.Pow(0.5, halvings), now)
+	}
+
+	return rep
+}
+
+// setScore sets the score of the given peer, bounded by the minimum and
+// maximum score, and adjusts its rate limit accordingly.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (r *reputationManager) setScore(rep *peerReputation, score float64,
+	now time.Time) {
+
+	score = math.Max(score, minReputationScore)
+	score = math.Min(score, maxReputationScore)
+
+	rep.Score = score
+	rep.LastUpdate = now
+	rep.MsgRate = r.msgRate * rateFactor(score)
+	rep.limiter.SetLimitAt(now, rate.Limit(rep.MsgRate))
+}
+
+// allow returns true if a gossip message of the given peer should be
+// processed, and false if the peer exceeded its rate.
+func (r *reputationManager) allow(pubkey [33]byte) bool {
+	if r.msgRate == 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	rep := r.fetch(pubkey, now)
+	if rep.limiter.AllowN(now, 1) {
+		return true
+	}
+
+	rep.Throttled++
+	r.setScore(rep, rep.Score+throttledScore, now)
+
+	return false
+}
+
+// recordAccepted records that a gossip message of the given peer was new to
+// us.
+func (r *reputationManager) recordAccepted(pubkey [33]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	rep := r.fetch(pubkey, now)
+	rep.Accepted++
+	r.setScore(rep, rep.Score+acceptedScore, now)
+}
+
+// recordStale records that the given peer sent a gossip message we already
+// knew about or couldn't process yet. As any peer relays messages we already
+// received from others, this doesn't affect the score of the peer.
+func (r *reputationManager) recordStale(pubkey [33]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rep := r.fetch(pubkey, time.Now())
+	rep.Stale++
+}
+
+// recordInvalid records that we rejected a gossip message of the given peer.
+func (r *reputationManager) recordInvalid(pubkey [33]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	rep := r.fetch(pubkey, now)
+	rep.Invalid++
+	r.setScore(rep, rep.Score+invalidScore, now)
+}
+
+// isAbusive returns true if the score of the given peer dropped to the
+// abusive score.
+func (r *reputationManager) isAbusive(pubkey [33]byte) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.peers.Get(pubkey); err != nil {
+		return false
+	}
+
+	return r.fetch(pubkey, time.Now()).Score <= abusiveScore
+}
+
+// reputations returns a snapshot of the reputation of all tracked peers.
+func (r *reputationManager) reputations() []PeerReputation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pubkeys [][33]byte
+	r.peers.Range(func(pubkey [33]byte, _ *peerReputation) bool {
+		pubkeys = append(pubkeys, pubkey)
+		return true
+	})
+
+	now := time.Now()
+	reps := make([]PeerReputation, 0, len(pubkeys))
+	for _, pubkey := range pubkeys {
+		reps = append(reps, r.fetch(pubkey, now).PeerReputation)
+	}
+
+	return reps
+}
@@ -0,0 +1,79 @@
+package discovery
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestReputationManager tests that the gossip reputation of a peer adjusts its
+// rate limit, and that peers sending invalid gossip become abusive.
+func TestReputationManager(t *testing.T) {
+	t.Parallel()
+
+	r := newReputationManager(DefaultPeerMsgRate, 2)
+
+	goodPeer := [33]byte{0x01}
+	badPeer := [33]byte{0x02}
+
+	// Useful gossip raises the rate of a peer up to twice the neutral
+	// rate.
+	for i := 0; i < 2*maxReputationScore; i++ {
+		r.recordAccepted(goodPeer)
+	}
+
+	// Stale gossip doesn't change the score of a peer.
+	r.recordStale(goodPeer)
+
+	// Invalid gossip lowers the rate of a peer until it is abusive. As the
+	// score decays in between, one more invalid message than the exact
+	// amount is needed.
+	numInvalid := abusiveScore/invalidScore + 1
+	for i := 0; i < numInvalid; i++ {
+		require.False(t, r.isAbusive(badPeer))
+		r.recordInvalid(badPeer)
+	}
+	require.True(t, r.isAbusive(badPeer))
+	require.False(t, r.isAbusive(goodPeer))
+
+	reps := make(map[[33]byte]PeerReputation)
+	for _, rep := range r.reputations() {
+		reps[rep.Peer] = rep
+	}
+	require.Len(t, reps, 2)
+
+	good := reps[goodPeer]
+	require.InDelta(t, maxReputationScore, good.Score, 0.1)
+	require.InDelta(t, 2*DefaultPeerMsgRate, good.MsgRate, 0.1)
+	require.EqualValues(t, 2*maxReputationScore, good.Accepted)
+	require.EqualValues(t, 1, good.Stale)
+
+	bad := reps[badPeer]
+	require.Less(t, bad.MsgRate, float64(DefaultPeerMsgRate))
+	require.EqualValues(t, numInvalid, bad.Invalid)
+
+	// A peer exceeding its burst is throttled, which lowers its score.
+	throttledPeer := [33]byte{0x03}
+	require.True(t, r.allow(throttledPeer))
+	require.True(t, r.allow(throttledPeer))
+	require.False(t, r.allow(throttledPeer))
+
+	rep, err := r.peers.Get(throttledPeer)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, rep.Throttled)
+	require.Less(t, rep.Score, 0.0)
+
+	// The score of a peer decays over time, so an abusive peer is
+	// eventually forgiven.
+	rep, err = r.peers.Get(badPeer)
+	require.NoError(t, err)
+	rep.LastUpdate = time.Now().Add(-2 * reputationHalfLife)
+	require.False(t, r.isAbusive(badPeer))
+
+	// A zero rate disables the rate limit.
+	r = newReputationManager(0, 0)
+	for i := 0; i < 10; i++ {
+		require.True(t, r.allow(throttledPeer))
+	}
+}
@@ -0,0 +1,446 @@
+package discovery
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnpeer"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// graphSnapshotVersion is the version of the graph snapshot encoding.
+	graphSnapshotVersion = 1
+
+	// maxGraphSnapshotSize is the maximum size of a graph snapshot we'll
+	// read, in order to bound the memory used when reading one from an
+	// untrusted source.
+	maxGraphSnapshotSize = 512 * 1024 * 1024
+)
+
+var (
+	// graphSnapshotMagic are the bytes each graph snapshot starts with.
+	graphSnapshotMagic = [8]byte{'l', 'n', 'd', 'g', 'r', 'a', 'p', 'h'}
+
+	// ErrInvalidGraphSnapshot is returned when a graph snapshot can't be
+	// decoded.
+	ErrInvalidGraphSnapshot = errors.New("invalid graph snapshot")
+
+	// ErrUntrustedGraphSnapshot is returned when a graph snapshot isn't
+	// signed by any of the trusted signers.
+	ErrUntrustedGraphSnapshot = errors.New("graph snapshot not signed by " +
+		"a trusted signer")
+)
+
+// GraphSnapshot is a signed export of the public channel graph of a node. It
+// holds the channel announcements, channel updates and node announcements
+// known to the node, such that a fresh node can bootstrap its graph from it
+// instead of syncing the whole graph through gossip.
+//
+// The snapshot is encoded as:
+//
+//	magic (8 bytes) || version (1 byte) || chain hash (32 bytes) ||
+//	timestamp (8 bytes) || num msgs (4 bytes) ||
+//	[msg len (2 bytes) || msg]... ||
+//	signer (33 bytes) || sig || sig len (1 byte)
+//
+// The signature commits to the double SHA-256 of everything preceding the
+// signer.
+type GraphSnapshot struct {
+	// ChainHash is the hash of the genesis block of the chain the graph
+	// belongs to.
+	ChainHash chainhash.Hash
+
+	// Timestamp is the time the snapshot was created.
+	Timestamp time.Time
+
+	// Messages are the gossip messages of the graph, where each channel
+	// announcement precedes its channel updates, and the node
+	// announcements follow the channels.
+	Messages []lnwire.Message
+
+	// Signer is the public key of the node that signed the snapshot.
+	Signer *btcec.PublicKey
+}
+
+// encodePayload encodes the signed part of the snapshot.
+func (s *GraphSnapshot) encodePayload() ([]byte, error) {
+	var b bytes.Buffer
+	b.Write(graphSnapshotMagic[:])
+	b.WriteByte(graphSnapshotVersion)
+	b.Write(s.ChainHash[:])
+
+	var scratch [8]byte
+	binary.BigEndian.PutUint64(scratch[:], uint64(s.Timestamp.Unix()))
+	b.Write(scratch[:])
+
+	binary.BigEndian.PutUint32(scratch[:4], uint32(len(s.Messages)))
+	b.Write(scratch[:4])
+
+	var msgBuf bytes.Buffer
+	for _, msg := range s.Messages {
+		msgBuf.Reset()
+		if _, err := lnwire.WriteMessage(&msgBuf, msg, 0); err != nil {
+			return nil, err
+		}
+
+		binary.BigEndian.PutUint16(scratch[:2], uint16(msgBuf.Len()))
+		b.Write(scratch[:2])
+		b.Write(msgBuf.Bytes())
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeGraphSnapshotPayload decodes the signed part of a snapshot.
+func decodeGraphSnapshotPayload(payload []byte) (*GraphSnapshot, error) {
+	r := bytes.NewReader(payload)
+
+	var (
+		magic   [8]byte
+		version uint8
+		snap    GraphSnapshot
+		unixTs  uint64
+		numMsgs uint32
+	)
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, err
+	}
+	if magic != graphSnapshotMagic {
+		return nil, fmt.Errorf("%w: unknown magic bytes",
+			ErrInvalidGraphSnapshot)
+	}
+
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != graphSnapshotVersion {
+		return nil, fmt.Errorf("%w: unknown version %d",
+			ErrInvalidGraphSnapshot, version)
+	}
+
+	if _, err := io.ReadFull(r, snap.ChainHash[:]); err != nil {
+		return nil, err
+	}
+	if err := binary.Read(r, binary.BigEndian, &unixTs); err != nil {
+		return nil, err
+	}
+	snap.Timestamp = time.Unix(int64(unixTs), 0)
+
+	if err := binary.Read(r, binary.BigEndian, &numMsgs); err != nil {
+		return nil, err
+	}
+
+	for i := uint32(0); i < numMsgs; i++ {
+		var msgLen uint16
+		err := binary.Read(r, binary.BigEndian, &msgLen)
+		if err != nil {
+			return nil, err
+		}
+
+		msgBytes := make([]byte, msgLen)
+		if _, err := io.ReadFull(r, msgBytes); err != nil {
+			return nil, err
+		}
+
+		msg, err := lnwire.ReadMessage(bytes.NewReader(msgBytes), 0)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to decode message "+
+				"%d: %v", ErrInvalidGraphSnapshot, i, err)
+		}
+
+		switch msg.(type) {
+		case *lnwire.ChannelAnnouncement1, *lnwire.ChannelUpdate1,
+			*lnwire.NodeAnnouncement:
+
+		default:
+			return nil, fmt.Errorf("%w: unexpected message %v",
+				ErrInvalidGraphSnapshot, msg.MsgType())
+		}
+
+		snap.Messages = append(snap.Messages, msg)
+	}
+
+	return &snap, nil
+}
+
+// WriteGraphSnapshot writes the given signed snapshot to w.
+func WriteGraphSnapshot(w io.Writer, snap *GraphSnapshot,
+	sig *ecdsa.Signature) error {
+
+	payload, err := snap.encodePayload()
+	if err != nil {
+		return err
+	}
+
+	sigBytes := sig.Serialize()
+
+	var b bytes.Buffer
+	b.Write(payload)
+	b.Write(snap.Signer.SerializeCompressed())
+	b.Write(sigBytes)
+	b.WriteByte(uint8(len(sigBytes)))
+
+	_, err = w.Write(b.Bytes())
+
+	return err
+}
+
+// ReadGraphSnapshot reads a signed snapshot from r. The snapshot is only
+// returned if it belongs to the given chain and is signed by one of the given
+// trusted signers.
+func ReadGraphSnapshot(r io.Reader, chainHash chainhash.Hash,
+	trustedSigners []*btcec.PublicKey) (*GraphSnapshot, error) {
+
+	data, err := io.ReadAll(io.LimitReader(r, maxGraphSnapshotSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxGraphSnapshotSize {
+		return nil, fmt.Errorf("%w: exceeds %d bytes",
+			ErrInvalidGraphSnapshot, maxGraphSnapshotSize)
+	}
+
+	// The snapshot ends with the signer, the signature and the length of
+	// the signature, which we'll use to locate the end of the payload.
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidGraphSnapshot)
+	}
+	const signerLen = btcec.PubKeyBytesLenCompressed
+	sigLen := int(data[len(data)-1])
+	payloadLen := len(data) - 1 - sigLen - signerLen
+	if payloadLen < 0 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidGraphSnapshot)
+	}
+
+	payload := data[:payloadLen]
+	signerBytes := data[payloadLen : payloadLen+signerLen]
+	sigBytes := data[payloadLen+signerLen : len(data)-1]
+
+	signer, err := btcec.ParsePubKey(signerBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGraphSnapshot, err)
+	}
+
+	sig, err := ecdsa.ParseDERSignature(sigBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGraphSnapshot, err)
+	}
+
+	if !sig.Verify(chainhash.DoubleHashB(payload), signer) {
+		return nil, fmt.Errorf("%w: invalid signature",
+			ErrInvalidGraphSnapshot)
+	}
+
+	trusted := false
+	for _, key := range trustedSigners {
+		if key.IsEqual(signer) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return nil, fmt.Errorf("%w: signer %x",
+			ErrUntrustedGraphSnapshot, signerBytes)
+	}
+
+	snap, err := decodeGraphSnapshotPayload(payload)
+	if err != nil {
+		return nil, err
+	}
+	snap.Signer = signer
+
+	if snap.ChainHash != chainHash {
+		return nil, fmt.Errorf("graph snapshot is for chain %v, "+
+			"expected %v", snap.ChainHash, chainHash)
+	}
+
+	return snap, nil
+}
+
+// ExportGraphSnapshot writes a snapshot of our public channel graph, signed
+// with our node key, to w.
+func (d *AuthenticatedGossiper) ExportGraphSnapshot(w io.Writer) error {
+	now := time.Now()
+	msgs, err := d.cfg.ChanSeries.UpdatesInHorizon(
+		d.cfg.ChainHash, time.Unix(0, 0), now,
+	)
+	if err != nil {
+		return err
+	}
+
+	snap := &GraphSnapshot{
+		ChainHash: d.cfg.ChainHash,
+		Timestamp: now,
+		Messages:  msgs,
+		Signer:    d.selfKey,
+	}
+
+	payload, err := snap.encodePayload()
+	if err != nil {
+		return err
+	}
+
+	sig, err := d.cfg.AnnSigner.SignMessage(d.selfKeyLoc, payload, true)
+	if err != nil {
+		return fmt.Errorf("unable to sign graph snapshot: %w", err)
+	}
+
+	log.Infof("Exporting graph snapshot with %d messages", len(msgs))
+
+	return WriteGraphSnapshot(w, snap, sig)
+}
+
+// ImportGraphSnapshot processes the messages of the given snapshot as if they
+// were received from its signer, such that they are validated and added to
+// our graph like any other gossip. Unlike gossip of a connected peer, the
+// messages aren't limited by the gossip reputation of the signer, so a
+// snapshot can also be imported once our graph is synced. The number of
+// messages that were accepted is returned.
+//
+// NOTE: The gossiper MUST be started before calling this method.
+func (d *AuthenticatedGossiper) ImportGraphSnapshot(
+	snap *GraphSnapshot) (int, error) {
+
+	if snap.ChainHash != d.cfg.ChainHash {
+		return 0, fmt.Errorf("graph snapshot is for chain %v, "+
+			"gossiper on chain=%v", snap.ChainHash,
+			d.cfg.ChainHash)
+	}
+
+	log.Infof("Importing graph snapshot of %v with %d messages signed "+
+		"by %x", snap.Timestamp, len(snap.Messages),
+		snap.Signer.SerializeCompressed())
+
+	// We'll first hand all messages to the gossiper, such that they are
+	// validated in parallel while their dependencies are respected, and
+	// then wait for the result of each of them.
+	peer := &snapshotPeer{signer: snap.Signer, quit: d.quit}
+	errChans := make([]chan error, 0, len(snap.Messages))
+	for _, msg := range snap.Messages {
+		errChans = append(
+			errChans, d.ProcessRemoteAnnouncement(msg, peer),
+		)
+	}
+
+	var accepted int
+	for _, errChan := range errChans {
+		select {
+		case err := <-errChan:
+			if err != nil {
+				log.Debugf("Graph snapshot message "+
+					"rejected: %v", err)
+				continue
+			}
+			accepted++
+
+		case <-d.quit:
+			return accepted, ErrGossiperShuttingDown
+		}
+	}
+
+	log.Infof("Imported %d of %d graph snapshot messages", accepted,
+		len(snap.Messages))
+
+	return accepted, nil
+}
+
+// snapshotPeer is the peer the messages of a graph snapshot are processed as
+// received from. It represents the signer of the snapshot, without a
+// connection to it.
+type snapshotPeer struct {
+	signer *btcec.PublicKey
+	quit   chan struct{}
+}
+
+// isSnapshotPeer returns true if the given peer is the signer of a graph
+// snapshot we import. The messages of a snapshot are neither rate limited nor
+// do they affect the gossip reputation of its signer, as we asked for them.
+func isSnapshotPeer(peer lnpeer.Peer) bool {
+	_, ok := peer.(*snapshotPeer)
+	return ok
+}
+
+// A compile-time constraint to ensure snapshotPeer implements lnpeer.Peer.
+var _ lnpeer.Peer = (*snapshotPeer)(nil)
+
+// SendMessage drops the given messages, as there is no connection to the
+// signer of the snapshot.
+func (p *snapshotPeer) SendMessage(bool, ...lnwire.Message) error {
+	return nil
+}
+
+// SendMessageLazy drops the given messages, as there is no connection to the
+// signer of the snapshot.
+func (p *snapshotPeer) SendMessageLazy(bool, ...lnwire.Message) error {
+	return nil
+}
+
+// AddNewChannel is not supported for the signer of a snapshot.
+func (p *snapshotPeer) AddNewChannel(*lnpeer.NewChannel,
+	<-chan struct{}) error {
+
+	return errors.New("not a connected peer")
+}
+
+// AddPendingChannel is not supported for the signer of a snapshot.
+func (p *snapshotPeer) AddPendingChannel(lnwire.ChannelID,
+	<-chan struct{}) error {
+
+	return errors.New("not a connected peer")
+}
+
+// RemovePendingChannel is not supported for the signer of a snapshot.
+func (p *snapshotPeer) RemovePendingChannel(lnwire.ChannelID) error {
+	return errors.New("not a connected peer")
+}
+
+// WipeChannel is a no-op for the signer of a snapshot.
+func (p *snapshotPeer) WipeChannel(*wire.OutPoint) {}
+
+// PubKey returns the serialized public key of the signer of the snapshot.
+func (p *snapshotPeer) PubKey() [33]byte {
+	var pubKey [33]byte
+	copy(pubKey[:], p.signer.SerializeCompressed())
+
+	return pubKey
+}
+
+// IdentityKey returns the public key of the signer of the snapshot.
+func (p *snapshotPeer) IdentityKey() *btcec.PublicKey {
+	return p.signer
+}
+
+// Address returns nil, as there is no connection to the signer of the
+// snapshot.
+func (p *snapshotPeer) Address() net.Addr {
+	return nil
+}
+
+// QuitSignal returns the quit channel of the gossiper.
+func (p *snapshotPeer) QuitSignal() <-chan struct{} {
+	return p.quit
+}
+
+// LocalFeatures returns an empty feature vector.
+func (p *snapshotPeer) LocalFeatures() *lnwire.FeatureVector {
+	return lnwire.EmptyFeatureVector()
+}
+
+// RemoteFeatures returns an empty feature vector.
+func (p *snapshotPeer) RemoteFeatures() *lnwire.FeatureVector {
+	return lnwire.EmptyFeatureVector()
+}
+
+// Disconnect is a no-op, as there is no connection to the signer of the
+// snapshot.
+func (p *snapshotPeer) Disconnect(error) {}
@@ -0,0 +1,124 @@
+package discovery
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGraphSnapshotEncoding tests that a signed graph snapshot can be read
+// back, and that it is only accepted from a trusted signer of the expected
+// chain.
+func TestGraphSnapshotEncoding(t *testing.T) {
+	t.Parallel()
+
+	signerPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	chanAnn, err := createRemoteChannelAnnouncement(1)
+	require.NoError(t, err)
+	chanUpdate := randChannelUpdate()
+
+	snap := &GraphSnapshot{
+		ChainHash: chainhash.Hash{1},
+		Timestamp: time.Unix(1_700_000_000, 0),
+		Messages:  []lnwire.Message{chanAnn, chanUpdate},
+		Signer:    signerPriv.PubKey(),
+	}
+
+	payload, err := snap.encodePayload()
+	require.NoError(t, err)
+	sig := ecdsa.Sign(signerPriv, chainhash.DoubleHashB(payload))
+
+	var b bytes.Buffer
+	require.NoError(t, WriteGraphSnapshot(&b, snap, sig))
+	encoded := b.Bytes()
+
+	trusted := []*btcec.PublicKey{randPubKey(t), signerPriv.PubKey()}
+	readSnap, err := ReadGraphSnapshot(
+		bytes.NewReader(encoded), snap.ChainHash, trusted,
+	)
+	require.NoError(t, err)
+	require.Equal(t, snap.ChainHash, readSnap.ChainHash)
+	require.True(t, snap.Timestamp.Equal(readSnap.Timestamp))
+	require.True(t, readSnap.Signer.IsEqual(signerPriv.PubKey()))
+	require.Len(t, readSnap.Messages, 2)
+
+	readAnn, ok := readSnap.Messages[0].(*lnwire.ChannelAnnouncement1)
+	require.True(t, ok)
+	require.Equal(t, chanAnn.ShortChannelID, readAnn.ShortChannelID)
+
+	readUpdate, ok := readSnap.Messages[1].(*lnwire.ChannelUpdate1)
+	require.True(t, ok)
+	require.Equal(t, chanUpdate.ShortChannelID, readUpdate.ShortChannelID)
+
+	// A snapshot of another chain is rejected.
+	_, err = ReadGraphSnapshot(
+		bytes.NewReader(encoded), chainhash.Hash{2}, trusted,
+	)
+	require.Error(t, err)
+
+	// A snapshot of a signer that isn't trusted is rejected.
+	_, err = ReadGraphSnapshot(
+		bytes.NewReader(encoded), snap.ChainHash,
+		[]*btcec.PublicKey{randPubKey(t)},
+	)
+	require.ErrorIs(t, err, ErrUntrustedGraphSnapshot)
+
+	// A snapshot that was modified after signing is rejected.
+	tampered := bytes.Clone(encoded)
+	tampered[len(graphSnapshotMagic)+1] ^= 0xff
+	_, err = ReadGraphSnapshot(
+		bytes.NewReader(tampered), snap.ChainHash, trusted,
+	)
+	require.ErrorIs(t, err, ErrInvalidGraphSnapshot)
+}
+
+// TestImportGraphSnapshotSynced tests that a graph snapshot can be imported
+// once our graph is synced, without the messages of the snapshot being rate
+// limited by the gossip reputation of its signer.
+func TestImportGraphSnapshotSynced(t *testing.T) {
+	t.Parallel()
+
+	ctx, err := createTestCtx(t, 0, false)
+	require.NoError(t, err, "can't create context")
+
+	// The test context marks our graph as synced. We'll only allow a
+	// single gossip message per peer, such that the messages of the
+	// snapshot would be dropped if they were rate limited.
+	require.True(t, ctx.gossiper.syncMgr.IsGraphSynced())
+	ctx.gossiper.reputation = newReputationManager(0.001, 1)
+
+	batch, err := createRemoteAnnouncements(0)
+	require.NoError(t, err)
+
+	snap := &GraphSnapshot{
+		ChainHash: ctx.gossiper.cfg.ChainHash,
+		Timestamp: time.Now(),
+		Messages: []lnwire.Message{
+			batch.chanAnn, batch.chanUpdAnn1, batch.chanUpdAnn2,
+			batch.nodeAnn1, batch.nodeAnn2,
+		},
+		Signer: randPubKey(t),
+	}
+
+	accepted, err := ctx.gossiper.ImportGraphSnapshot(snap)
+	require.NoError(t, err)
+	require.Equal(t, len(snap.Messages), accepted)
+
+	// The channel of the snapshot was added to our graph along with the
+	// policies of both its directions.
+	require.Len(t, ctx.router.infos, 1)
+	require.Len(t, ctx.router.edges[batch.chanAnn.ShortChannelID.ToUint64()],
+		2)
+	require.Len(t, ctx.router.nodes, 2)
+
+	// The signer of the snapshot didn't pick up a reputation from it.
+	require.Empty(t, ctx.gossiper.PeerReputations())
+}
@@ -110,6 +110,10 @@ type SyncManagerCfg struct {
 	// updates for a channel and returns true if the channel should be
 	// considered a zombie based on these timestamps.
 	IsStillZombieChannel func(time.Time, time.Time) bool
+
+	// GossipV2Store holds the validated taproot channel gossip messages.
+	// It is nil if their handling isn't enabled.
+	GossipV2Store *GossipV2Store
 }
 
 // SyncManager is a subsystem of the gossiper that manages the gossip syncers
@@ -521,6 +525,7 @@ func (m *SyncManager) createGossipSyncer(peer lnpeer.Peer) *GossipSyncer {
 		maxQueryChanRangeReplies:  maxQueryChanRangeReplies,
 		noTimestampQueryOption:    m.cfg.NoTimestampQueries,
 		isStillZombieChannel:      m.cfg.IsStillZombieChannel,
+		gossipV2Store:             m.cfg.GossipV2Store,
 	}, m.gossipFilterSema)
 
 	// Gossip syncers are initialized by default in a PassiveSync type
@@ -299,6 +299,10 @@ type gossipSyncerCfg struct {
 	// updates for a channel and returns true if the channel should be
 	// considered a zombie based on these timestamps.
 	isStillZombieChannel func(time.Time, time.Time) bool
+
+	// gossipV2Store holds the validated taproot channel gossip messages.
+	// It is nil if their handling isn't enabled.
+	gossipV2Store *GossipV2Store
 }
 
 // GossipSyncer is a struct that handles synchronizing the channel graph state
@@ -932,6 +936,13 @@ func (g *GossipSyncer) processChanRangeReply(msg *lnwire.ReplyChannelRange) erro
 		return fmt.Errorf("unable to filter chan ids: %w", err)
 	}
 
+	// The taproot channels aren't part of the channel graph yet, so the
+	// ones we already have in the gossip store are filtered out as well.
+	newChans, err = g.filterKnownGossipV2Chans(newChans)
+	if err != nil {
+		return fmt.Errorf("unable to filter taproot chan ids: %w", err)
+	}
+
 	// As we've received the entirety of the reply, we no longer need to
 	// hold on to the set of buffered replies or the original query that
 	// prompted the replies, so we'll let that be garbage collected now.
@@ -1108,6 +1119,16 @@ func (g *GossipSyncer) replyChanRangeQuery(query *lnwire.QueryChannelRange) erro
 		return err
 	}
 
+	// The taproot channels aren't part of the channel graph yet, so
+	// they're added from the gossip store.
+	gossipV2Ranges, err := g.gossipV2ChannelRanges(
+		startBlock, endBlock, withTimestamps,
+	)
+	if err != nil {
+		return err
+	}
+	channelRanges = mergeChannelRanges(channelRanges, gossipV2Ranges)
+
 	// TODO(roasbeef): means can't send max uint above?
 	//  * or make internal 64
 
@@ -1281,6 +1302,15 @@ func (g *GossipSyncer) replyShortChanIDs(query *lnwire.QueryShortChanIDs) error
 			query.ShortChanIDs[0].ToUint64(), err)
 	}
 
+	// The messages of the queried taproot channels are taken from the
+	// gossip store.
+	gossipV2Msgs, err := g.gossipV2ChanMsgs(query.ShortChanIDs)
+	if err != nil {
+		return fmt.Errorf("unable to fetch taproot chan anns for "+
+			"%v..., %w", query.ShortChanIDs[0].ToUint64(), err)
+	}
+	replyMsgs = append(replyMsgs, gossipV2Msgs...)
+
 	// Reply with any messages related to those channel ID's, we'll write
 	// each one individually and synchronously to throttle the sends and
 	// perform buffering of responses in the syncer as opposed to the peer.
@@ -1342,6 +1372,15 @@ func (g *GossipSyncer) ApplyGossipFilter(filter *lnwire.GossipTimestampRange) er
 		return err
 	}
 
+	// The stored taproot channel gossip within the filter is sent along
+	// with the rest of the backlog.
+	gossipV2ToSend, err := g.gossipV2InHorizon(startTime, endTime)
+	if err != nil {
+		returnSema()
+		return err
+	}
+	newUpdatestoSend = append(newUpdatestoSend, gossipV2ToSend...)
+
 	log.Infof("GossipSyncer(%x): applying new update horizon: start=%v, "+
 		"end=%v, backlog_size=%v", g.cfg.peerPub[:], startTime, endTime,
 		len(newUpdatestoSend))
@@ -1409,15 +1448,23 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 	chanUpdateIndex := make(
 		map[lnwire.ShortChannelID][]*lnwire.ChannelUpdate1,
 	)
+	chanUpdate2Index := make(
+		map[lnwire.ShortChannelID][]*lnwire.ChannelUpdate2,
+	)
 	for _, msg := range msgs {
-		chanUpdate, ok := msg.msg.(*lnwire.ChannelUpdate1)
-		if !ok {
-			continue
-		}
+		switch chanUpdate := msg.msg.(type) {
+		case *lnwire.ChannelUpdate1:
+			chanUpdateIndex[chanUpdate.ShortChannelID] = append(
+				chanUpdateIndex[chanUpdate.ShortChannelID],
+				chanUpdate,
+			)
 
-		chanUpdateIndex[chanUpdate.ShortChannelID] = append(
-			chanUpdateIndex[chanUpdate.ShortChannelID], chanUpdate,
-		)
+		case *lnwire.ChannelUpdate2:
+			scid := chanUpdate.SCID()
+			chanUpdate2Index[scid] = append(
+				chanUpdate2Index[scid], chanUpdate,
+			)
+		}
 	}
 
 	// We'll construct a helper function that we'll us below to determine
@@ -1435,6 +1482,10 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 			(t.After(startTime) && t.Before(endTime))
 	}
 
+	// The taproot channel gossip is ordered by block height rather than
+	// by timestamp, so we map the time range to a range of block heights.
+	passesHeightFilter := g.gossipV2HeightFilter(startTime, endTime)
+
 	msgsToSend := make([]lnwire.Message, 0, len(msgs))
 	for _, msg := range msgs {
 		// If the target peer is the peer that sent us this message,
@@ -1491,6 +1542,32 @@ func (g *GossipSyncer) FilterGossipMsgs(msgs ...msgWithSenders) {
 			if passesFilter(msg.Timestamp) {
 				msgsToSend = append(msgsToSend, msg)
 			}
+
+		// The taproot channel announcements are sent like the
+		// legacy ones, if any of their updates is within the range
+		// of block heights that corresponds to the time range.
+		case *lnwire.ChannelAnnouncement2:
+			if g.chanAnn2PassesFilter(
+				msg, chanUpdate2Index, passesHeightFilter,
+			) {
+
+				msgsToSend = append(msgsToSend, msg)
+			}
+
+		// The taproot channel updates don't carry a timestamp, so
+		// they are only sent if their block height is within the
+		// range of block heights that corresponds to the time range.
+		case *lnwire.ChannelUpdate2:
+			if passesHeightFilter(msg.BlockHeight.Val) {
+				msgsToSend = append(msgsToSend, msg)
+			}
+
+		// The same goes for the node announcements of nodes with
+		// taproot channels.
+		case *lnwire.NodeAnnouncement2:
+			if passesHeightFilter(msg.BlockHeight.Val) {
+				msgsToSend = append(msgsToSend, msg)
+			}
 		}
 	}
 
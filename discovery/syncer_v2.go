@@ -0,0 +1,409 @@
+package discovery
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// gossipV2BlockInterval is the average time between two blocks, which
+	// is used to map the time range of a gossip filter to the block
+	// heights the taproot channel gossip is ordered by.
+	gossipV2BlockInterval = 10 * time.Minute
+
+	// gossipV2HeightSlack is the number of blocks the block height range
+	// of a gossip filter is widened by, as the block heights only
+	// approximate the time at which a message was created.
+	gossipV2HeightSlack = 6
+)
+
+// gossipV2HeightHorizon maps the given time range of a gossip filter to the
+// range of block heights that were mined within it, based on the current best
+// height. A range that reaches into the future has no upper bound.
+func gossipV2HeightHorizon(bestHeight uint32, now, start,
+	end time.Time) (uint32, uint32) {
+
+	heightAt := func(t time.Time) uint32 {
+		if !t.Before(now) {
+			return bestHeight
+		}
+
+		blocksAgo := now.Sub(t) / gossipV2BlockInterval
+		if blocksAgo >= time.Duration(bestHeight) {
+			return 0
+		}
+
+		return bestHeight - uint32(blocksAgo)
+	}
+
+	startHeight := heightAt(start)
+	if startHeight > gossipV2HeightSlack {
+		startHeight -= gossipV2HeightSlack
+	} else {
+		startHeight = 0
+	}
+
+	endHeight := uint32(math.MaxUint32)
+	if end.Before(now) {
+		endHeight = heightAt(end) + gossipV2HeightSlack
+	}
+
+	return startHeight, endHeight
+}
+
+// gossipV2HeightTime returns the approximate time at which the block of the
+// given height was mined, based on the current best height.
+func gossipV2HeightTime(bestHeight uint32, now time.Time,
+	height uint32) time.Time {
+
+	if height >= bestHeight {
+		return now
+	}
+
+	blocksAgo := time.Duration(bestHeight - height)
+
+	return now.Add(-blocksAgo * gossipV2BlockInterval)
+}
+
+// gossipV2HeightFilter returns a function that reports whether a taproot
+// channel gossip message created at the given block height is within the given
+// time range of a gossip filter.
+func (g *GossipSyncer) gossipV2HeightFilter(startTime,
+	endTime time.Time) func(uint32) bool {
+
+	// Without a store no taproot channel gossip is handled, so there's
+	// nothing to filter.
+	if g.cfg.gossipV2Store == nil {
+		return func(uint32) bool {
+			return false
+		}
+	}
+
+	startHeight, endHeight := gossipV2HeightHorizon(
+		g.cfg.bestHeight(), time.Now(), startTime, endTime,
+	)
+
+	return func(height uint32) bool {
+		return height >= startHeight && height <= endHeight
+	}
+}
+
+// gossipV2InHorizon returns the stored taproot channel gossip messages that
+// are within the given time range of a gossip filter. A channel announcement
+// is returned if any of its updates is, or if the channel has no updates.
+func (g *GossipSyncer) gossipV2InHorizon(startTime,
+	endTime time.Time) ([]lnwire.Message, error) {
+
+	if g.cfg.gossipV2Store == nil {
+		return nil, nil
+	}
+
+	storedMsgs, err := g.cfg.gossipV2Store.Messages()
+	if err != nil {
+		return nil, err
+	}
+
+	chanUpdates := make(map[lnwire.ShortChannelID][]*lnwire.ChannelUpdate2)
+	for _, msg := range storedMsgs {
+		if upd, ok := msg.(*lnwire.ChannelUpdate2); ok {
+			chanUpdates[upd.SCID()] = append(
+				chanUpdates[upd.SCID()], upd,
+			)
+		}
+	}
+
+	passesFilter := g.gossipV2HeightFilter(startTime, endTime)
+
+	var msgs []lnwire.Message
+	for _, msg := range storedMsgs {
+		switch msg := msg.(type) {
+		case *lnwire.ChannelAnnouncement2:
+			upds := chanUpdates[msg.SCID()]
+			if len(upds) == 0 {
+				msgs = append(msgs, msg)
+				continue
+			}
+
+			for _, upd := range upds {
+				if passesFilter(upd.BlockHeight.Val) {
+					msgs = append(msgs, msg)
+					break
+				}
+			}
+
+		case *lnwire.ChannelUpdate2:
+			if passesFilter(msg.BlockHeight.Val) {
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+
+	// The node announcements follow the channels they belong to.
+	nodeAnns, err := g.cfg.gossipV2Store.NodeAnnouncements()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nodeAnn := range nodeAnns {
+		if passesFilter(nodeAnn.BlockHeight.Val) {
+			msgs = append(msgs, nodeAnn)
+		}
+	}
+
+	return msgs, nil
+}
+
+// gossipV2ChannelRanges returns the taproot channels in the store that were
+// confirmed within the given range of block heights, grouped by block height.
+// If requested, the timestamps of the channel updates are approximated by the
+// block heights they were created at.
+func (g *GossipSyncer) gossipV2ChannelRanges(startHeight, endHeight uint32,
+	withTimestamps bool) ([]channeldb.BlockChannelRange, error) {
+
+	if g.cfg.gossipV2Store == nil {
+		return nil, nil
+	}
+
+	scids, err := g.cfg.gossipV2Store.ChannelsInRange(
+		startHeight, endHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		bestHeight    uint32
+		now           = time.Now()
+		channelRanges []channeldb.BlockChannelRange
+	)
+	if withTimestamps {
+		bestHeight = g.cfg.bestHeight()
+	}
+
+	// updateTime returns the approximate time of the latest update of the
+	// given direction of a channel, or the zero time if there's none.
+	updateTime := func(scid lnwire.ShortChannelID,
+		isNode1 bool) (time.Time, error) {
+
+		upd, err := g.cfg.gossipV2Store.ChannelUpdate(scid, isNode1)
+		if err != nil || upd == nil {
+			return time.Time{}, err
+		}
+
+		return gossipV2HeightTime(
+			bestHeight, now, upd.BlockHeight.Val,
+		), nil
+	}
+
+	for _, scid := range scids {
+		info := channeldb.NewChannelUpdateInfo(
+			scid, time.Time{}, time.Time{},
+		)
+
+		if withTimestamps {
+			info.Node1UpdateTimestamp, err = updateTime(scid, true)
+			if err != nil {
+				return nil, err
+			}
+
+			info.Node2UpdateTimestamp, err = updateTime(scid, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// The channels are in ascending order, so a new block range
+		// is started whenever the block height changes.
+		numRanges := len(channelRanges)
+		if numRanges == 0 ||
+			channelRanges[numRanges-1].Height != scid.BlockHeight {
+
+			channelRanges = append(
+				channelRanges, channeldb.BlockChannelRange{
+					Height: scid.BlockHeight,
+				},
+			)
+			numRanges++
+		}
+
+		channelRanges[numRanges-1].Channels = append(
+			channelRanges[numRanges-1].Channels, info,
+		)
+	}
+
+	return channelRanges, nil
+}
+
+// mergeChannelRanges merges two lists of channels grouped by block height,
+// keeping both the block ranges and the channels within them in ascending
+// order.
+func mergeChannelRanges(a,
+	b []channeldb.BlockChannelRange) []channeldb.BlockChannelRange {
+
+	if len(b) == 0 {
+		return a
+	}
+
+	rangeIndex := make(map[uint32]int)
+	merged := make([]channeldb.BlockChannelRange, 0, len(a)+len(b))
+	for _, channelRange := range append(a, b...) {
+		i, ok := rangeIndex[channelRange.Height]
+		if !ok {
+			rangeIndex[channelRange.Height] = len(merged)
+			merged = append(merged, channeldb.BlockChannelRange{
+				Height: channelRange.Height,
+				Channels: append(
+					[]channeldb.ChannelUpdateInfo(nil),
+					channelRange.Channels...,
+				),
+			})
+
+			continue
+		}
+
+		merged[i].Channels = append(
+			merged[i].Channels, channelRange.Channels...,
+		)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Height < merged[j].Height
+	})
+	for _, channelRange := range merged {
+		channels := channelRange.Channels
+		sort.Slice(channels, func(i, j int) bool {
+			id1 := channels[i].ShortChannelID.ToUint64()
+			id2 := channels[j].ShortChannelID.ToUint64()
+
+			return id1 < id2
+		})
+	}
+
+	return merged
+}
+
+// filterKnownGossipV2Chans returns the given channels without the taproot
+// channels that are already in the store.
+func (g *GossipSyncer) filterKnownGossipV2Chans(
+	scids []lnwire.ShortChannelID) ([]lnwire.ShortChannelID, error) {
+
+	if g.cfg.gossipV2Store == nil {
+		return scids, nil
+	}
+
+	newChans := make([]lnwire.ShortChannelID, 0, len(scids))
+	for _, scid := range scids {
+		_, err := g.cfg.gossipV2Store.ChannelAnnouncement(scid)
+		switch {
+		case errors.Is(err, ErrGossipV2ChannelNotFound):
+			newChans = append(newChans, scid)
+
+		case err != nil:
+			return nil, err
+		}
+	}
+
+	return newChans, nil
+}
+
+// gossipV2ChanMsgs returns the stored taproot channel gossip messages of the
+// given channels, followed by the announcements of their nodes.
+func (g *GossipSyncer) gossipV2ChanMsgs(
+	scids []lnwire.ShortChannelID) ([]lnwire.Message, error) {
+
+	if g.cfg.gossipV2Store == nil {
+		return nil, nil
+	}
+
+	var (
+		msgs      []lnwire.Message
+		nodeIDs   [][33]byte
+		seenNodes = make(map[[33]byte]struct{})
+	)
+	for _, scid := range scids {
+		chanMsgs, err := g.cfg.gossipV2Store.ChannelMessages(scid)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, msg := range chanMsgs {
+			ann, ok := msg.(*lnwire.ChannelAnnouncement2)
+			if !ok {
+				continue
+			}
+
+			for _, nodeID := range [][33]byte{
+				ann.NodeID1.Val, ann.NodeID2.Val,
+			} {
+				if _, ok := seenNodes[nodeID]; ok {
+					continue
+				}
+
+				seenNodes[nodeID] = struct{}{}
+				nodeIDs = append(nodeIDs, nodeID)
+			}
+		}
+
+		msgs = append(msgs, chanMsgs...)
+	}
+
+	for _, nodeID := range nodeIDs {
+		nodeAnn, err := g.cfg.gossipV2Store.NodeAnnouncement(nodeID)
+		if err != nil {
+			return nil, err
+		}
+
+		if nodeAnn != nil {
+			msgs = append(msgs, nodeAnn)
+		}
+	}
+
+	return msgs, nil
+}
+
+// chanAnn2PassesFilter returns true if the given taproot channel announcement
+// should be sent to a peer, which is the case if any of the channel's updates
+// passes the peer's filter. The updates are taken from the current batch if
+// there are any, otherwise from the gossip store. A channel without updates
+// is always sent.
+func (g *GossipSyncer) chanAnn2PassesFilter(ann *lnwire.ChannelAnnouncement2,
+	batchUpdates map[lnwire.ShortChannelID][]*lnwire.ChannelUpdate2,
+	passesFilter func(height uint32) bool) bool {
+
+	scid := ann.SCID()
+	chanUpdates, ok := batchUpdates[scid]
+	if !ok {
+		for _, isNode1 := range []bool{true, false} {
+			upd, err := g.cfg.gossipV2Store.ChannelUpdate(
+				scid, isNode1,
+			)
+			if err != nil {
+				log.Warnf("Unable to fetch ChannelUpdate2 "+
+					"for short_chan_id=%v: %v", scid, err)
+
+				return false
+			}
+
+			if upd != nil {
+				chanUpdates = append(chanUpdates, upd)
+			}
+		}
+	}
+
+	if len(chanUpdates) == 0 {
+		return true
+	}
+
+	for _, chanUpdate := range chanUpdates {
+		if passesFilter(chanUpdate.BlockHeight.Val) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,327 @@
+package discovery
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGossipV2HeightHorizon asserts that the time range of a gossip filter is
+// mapped to the expected range of block heights.
+func TestGossipV2HeightHorizon(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_700_000_000, 0)
+	blocksAgo := func(n int) time.Time {
+		return now.Add(-time.Duration(n) * gossipV2BlockInterval)
+	}
+
+	testCases := []struct {
+		name        string
+		start       time.Time
+		end         time.Time
+		startHeight uint32
+		endHeight   uint32
+	}{
+		{
+			name:        "new updates only",
+			start:       now,
+			end:         now.Add(time.Hour),
+			startHeight: 1000 - gossipV2HeightSlack,
+			endHeight:   math.MaxUint32,
+		},
+		{
+			name:        "recent backlog",
+			start:       blocksAgo(100),
+			end:         now.Add(time.Hour),
+			startHeight: 900 - gossipV2HeightSlack,
+			endHeight:   math.MaxUint32,
+		},
+		{
+			name:        "past range",
+			start:       blocksAgo(100),
+			end:         blocksAgo(50),
+			startHeight: 900 - gossipV2HeightSlack,
+			endHeight:   950 + gossipV2HeightSlack,
+		},
+		{
+			name:        "before genesis",
+			start:       blocksAgo(2000),
+			end:         now,
+			startHeight: 0,
+			endHeight:   math.MaxUint32,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			startHeight, endHeight := gossipV2HeightHorizon(
+				1000, now, tc.start, tc.end,
+			)
+			require.Equal(t, tc.startHeight, startHeight)
+			require.Equal(t, tc.endHeight, endHeight)
+		})
+	}
+}
+
+// TestGossipSyncerFilterGossipMsgsV2 asserts that the taproot channel gossip is
+// filtered by the block heights that correspond to the update horizon of the
+// remote peer.
+func TestGossipSyncerFilterGossipMsgsV2(t *testing.T) {
+	t.Parallel()
+
+	msgChan, syncer, _ := newTestSyncer(
+		lnwire.NewShortChanIDFromInt(10), defaultEncoding,
+		defaultChunkSize,
+	)
+	store := createTestGossipV2Store(t)
+	syncer.cfg.gossipV2Store = store
+
+	// The horizon of the peer starts 10 blocks ago, so updates below
+	// that height, minus the slack, are filtered out.
+	syncer.remoteUpdateHorizon = &lnwire.GossipTimestampRange{
+		FirstTimestamp: uint32(
+			time.Now().Add(-10 * gossipV2BlockInterval).Unix(),
+		),
+		TimestampRange: math.MaxUint32,
+	}
+	const (
+		aboveHorizon = latestKnownHeight - 5
+		belowHorizon = latestKnownHeight - 100
+	)
+
+	newAnn := func(scid uint64) *lnwire.ChannelAnnouncement2 {
+		ann := &lnwire.ChannelAnnouncement2{}
+		ann.ShortChannelID.Val = lnwire.NewShortChanIDFromInt(scid)
+
+		return ann
+	}
+	newUpdate := func(scid uint64,
+		height uint32) *lnwire.ChannelUpdate2 {
+
+		return testChanUpdate2(
+			lnwire.NewShortChanIDFromInt(scid), height, true,
+		)
+	}
+
+	// The channels 3 and 5 only have their updates in the store.
+	require.NoError(t, store.AddMessage(newUpdate(3, aboveHorizon)))
+	require.NoError(t, store.AddMessage(newUpdate(5, belowHorizon)))
+
+	newNodeAnn := func(height uint32) *lnwire.NodeAnnouncement2 {
+		nodeAnn := &lnwire.NodeAnnouncement2{}
+		nodeAnn.BlockHeight.Val = height
+
+		return nodeAnn
+	}
+
+	var (
+		ann1     = newAnn(1)
+		upd1     = newUpdate(1, aboveHorizon)
+		ann2     = newAnn(2)
+		upd2     = newUpdate(2, belowHorizon)
+		ann3     = newAnn(3)
+		ann4     = newAnn(4)
+		ann5     = newAnn(5)
+		upd6     = newUpdate(6, aboveHorizon)
+		upd7     = newUpdate(7, belowHorizon)
+		nodeAnn1 = newNodeAnn(aboveHorizon)
+		nodeAnn2 = newNodeAnn(belowHorizon)
+		toSend   = []lnwire.Message{
+			ann1, upd1, ann3, ann4, upd6, nodeAnn1,
+		}
+	)
+
+	var msgs []msgWithSenders
+	for _, msg := range []lnwire.Message{
+		ann1, upd1, ann2, upd2, ann3, ann4, ann5, upd6, upd7,
+		nodeAnn1, nodeAnn2,
+	} {
+		msgs = append(msgs, msgWithSenders{msg: msg})
+	}
+	syncer.FilterGossipMsgs(msgs...)
+
+	select {
+	case sent := <-msgChan:
+		require.Equal(t, toSend, sent)
+
+	case <-time.After(time.Second):
+		t.Fatalf("no messages sent")
+	}
+}
+
+// newGossipV2TestSyncer creates a test syncer with a taproot channel gossip
+// store that holds a channel for each of the given IDs, with an update of the
+// first node created at the given block height.
+func newGossipV2TestSyncer(t *testing.T,
+	channels map[lnwire.ShortChannelID]uint32) (chan []lnwire.Message,
+	*GossipSyncer, *mockChannelGraphTimeSeries) {
+
+	msgChan, syncer, chanSeries := newTestSyncer(
+		lnwire.NewShortChanIDFromInt(10), defaultEncoding,
+		defaultChunkSize,
+	)
+	store := createTestGossipV2Store(t)
+	syncer.cfg.gossipV2Store = store
+
+	for scid, height := range channels {
+		ann := &lnwire.ChannelAnnouncement2{}
+		ann.ShortChannelID.Val = scid
+		require.NoError(t, store.AddChannel(ann, &channeldb.EdgePoint{}))
+		require.NoError(t, store.AddMessage(
+			testChanUpdate2(scid, height, true),
+		))
+	}
+
+	return msgChan, syncer, chanSeries
+}
+
+// TestGossipSyncerApplyGossipFilterV2 asserts that the stored taproot channel
+// gossip within the filter of the remote peer is sent along with the backlog.
+func TestGossipSyncerApplyGossipFilterV2(t *testing.T) {
+	t.Parallel()
+
+	recentChan := lnwire.ShortChannelID{BlockHeight: 1000}
+	msgChan, syncer, chanSeries := newGossipV2TestSyncer(
+		t, map[lnwire.ShortChannelID]uint32{
+			recentChan:                     latestKnownHeight - 5,
+			{BlockHeight: 900, TxIndex: 1}: latestKnownHeight - 100,
+		},
+	)
+
+	// There's no legacy gossip within the filter.
+	chanSeries.horizonResp <- nil
+
+	err := syncer.ApplyGossipFilter(&lnwire.GossipTimestampRange{
+		FirstTimestamp: uint32(
+			time.Now().Add(-10 * gossipV2BlockInterval).Unix(),
+		),
+		TimestampRange: math.MaxUint32,
+	})
+	require.NoError(t, err)
+
+	// Only the channel with the recent update is sent.
+	for _, msgType := range []lnwire.MessageType{
+		lnwire.MsgChannelAnnouncement2, lnwire.MsgChannelUpdate2,
+	} {
+		select {
+		case msgs := <-msgChan:
+			require.Len(t, msgs, 1)
+			require.Equal(t, msgType, msgs[0].MsgType())
+
+			scid := msgs[0].(interface {
+				SCID() lnwire.ShortChannelID
+			}).SCID()
+			require.Equal(t, recentChan, scid)
+
+		case <-time.After(time.Second):
+			t.Fatalf("no messages sent")
+		}
+	}
+
+	select {
+	case msgs := <-msgChan:
+		t.Fatalf("unexpected messages sent: %v", msgs)
+
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// TestGossipSyncerReplyChanRangeQueryV2 asserts that the taproot channels of
+// the gossip store are included in the reply to a channel range query.
+func TestGossipSyncerReplyChanRangeQueryV2(t *testing.T) {
+	t.Parallel()
+
+	msgChan, syncer, chanSeries := newGossipV2TestSyncer(
+		t, map[lnwire.ShortChannelID]uint32{
+			{BlockHeight: 100, TxIndex: 1}: 100,
+			{BlockHeight: 200}:             200,
+			{BlockHeight: 2000}:            2000,
+		},
+	)
+
+	chanSeries.filterRangeResp <- []lnwire.ShortChannelID{
+		{BlockHeight: 100},
+		{BlockHeight: 300},
+	}
+
+	err := syncer.replyChanRangeQuery(&lnwire.QueryChannelRange{
+		FirstBlockHeight: 0,
+		NumBlocks:        1000,
+	})
+	require.NoError(t, err)
+
+	select {
+	case msgs := <-msgChan:
+		require.Len(t, msgs, 1)
+		reply, ok := msgs[0].(*lnwire.ReplyChannelRange)
+		require.True(t, ok)
+		require.Equal(t, []lnwire.ShortChannelID{
+			{BlockHeight: 100},
+			{BlockHeight: 100, TxIndex: 1},
+			{BlockHeight: 200},
+			{BlockHeight: 300},
+		}, reply.ShortChanIDs)
+
+	case <-time.After(time.Second):
+		t.Fatalf("no reply sent")
+	}
+}
+
+// TestGossipSyncerReplyShortChanIDsV2 asserts that the messages of queried
+// taproot channels are taken from the gossip store, and that known taproot
+// channels aren't queried.
+func TestGossipSyncerReplyShortChanIDsV2(t *testing.T) {
+	t.Parallel()
+
+	legacyChan := lnwire.ShortChannelID{BlockHeight: 100}
+	taprootChan := lnwire.ShortChannelID{BlockHeight: 200}
+	msgChan, syncer, chanSeries := newGossipV2TestSyncer(
+		t, map[lnwire.ShortChannelID]uint32{
+			taprootChan: 200,
+		},
+	)
+
+	// The announcement of the node of the taproot channel is sent along
+	// with the channel.
+	nodeAnn := &lnwire.NodeAnnouncement2{}
+	require.NoError(
+		t, syncer.cfg.gossipV2Store.AddNodeAnnouncement(nodeAnn),
+	)
+
+	legacyAnn := &lnwire.ChannelAnnouncement1{
+		ShortChannelID: legacyChan,
+	}
+	chanSeries.annResp <- []lnwire.Message{legacyAnn}
+
+	err := syncer.replyShortChanIDs(&lnwire.QueryShortChanIDs{
+		ShortChanIDs: []lnwire.ShortChannelID{legacyChan, taprootChan},
+	})
+	require.NoError(t, err)
+
+	for _, msgType := range []lnwire.MessageType{
+		lnwire.MsgChannelAnnouncement, lnwire.MsgChannelAnnouncement2,
+		lnwire.MsgChannelUpdate2, lnwire.MsgNodeAnnouncement2,
+		lnwire.MsgReplyShortChanIDsEnd,
+	} {
+		select {
+		case msgs := <-msgChan:
+			require.Len(t, msgs, 1)
+			require.Equal(t, msgType, msgs[0].MsgType())
+
+		case <-time.After(time.Second):
+			t.Fatalf("no messages sent")
+		}
+	}
+
+	// The taproot channel is known, so it isn't queried.
+	newChans, err := syncer.filterKnownGossipV2Chans(
+		[]lnwire.ShortChannelID{legacyChan, taprootChan},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []lnwire.ShortChannelID{legacyChan}, newChans)
+}
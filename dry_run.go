@@ -0,0 +1,253 @@
+package lnd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+const (
+	dryRunSendCoins   = "send_coins"
+	dryRunOpenChannel = "open_channel"
+	dryRunBatchOpen   = "batch_open_channel"
+	dryRunForceClose  = "force_close_channel"
+	dryRunCoopClose   = "coop_close_channel"
+)
+
+// newDryRunPlan returns the plan of the given transaction. The output at the
+// change index, if any, is marked as change.
+func newDryRunPlan(action string, tx *wire.MsgTx, totalInput btcutil.Amount,
+	changeIndex int, feeRate chainfee.SatPerKWeight,
+	final bool) *lnrpc.DryRunPlan {
+
+	plan := &lnrpc.DryRunPlan{
+		Action:      action,
+		SatPerVbyte: uint64(feeRate.FeePerVByte()),
+	}
+	if final {
+		plan.Txid = tx.TxHash().String()
+	}
+
+	for _, txIn := range tx.TxIn {
+		plan.Inputs = append(
+			plan.Inputs, txIn.PreviousOutPoint.String(),
+		)
+	}
+
+	var totalOutput btcutil.Amount
+	for idx, txOut := range tx.TxOut {
+		plan.Outputs = append(plan.Outputs, &lnrpc.DryRunOutput{
+			PkScript:  hex.EncodeToString(txOut.PkScript),
+			AmountSat: txOut.Value,
+			IsChange:  idx == changeIndex,
+		})
+		totalOutput += btcutil.Amount(txOut.Value)
+	}
+	plan.FeeSat = int64(totalInput - totalOutput)
+
+	return plan
+}
+
+// sendCloseDryRunPlan sends the plan of a channel close as the only update of
+// its stream.
+func sendCloseDryRunPlan(stream lnrpc.Lightning_CloseChannelServer,
+	plan *lnrpc.DryRunPlan) error {
+
+	return stream.Send(&lnrpc.CloseStatusUpdate{
+		Update: &lnrpc.CloseStatusUpdate_DryRunPlan{
+			DryRunPlan: plan,
+		},
+	})
+}
+
+// planWalletTx plans a transaction of the wallet that pays to the given
+// outputs, without signing or publishing it. The transaction is only fully
+// determined if the coins are selected deterministically.
+func (r *rpcServer) planWalletTx(action string, outputs []*wire.TxOut,
+	feeRate chainfee.SatPerKWeight, minConfs int32,
+	strategy wallet.CoinSelectionStrategy,
+	selectedUtxos fn.Set[wire.OutPoint]) (*lnrpc.DryRunPlan, error) {
+
+	var plan *lnrpc.DryRunPlan
+	err := r.server.cc.Wallet.WithCoinSelectLock(func() error {
+		authoredTx, err := r.authorWalletTx(
+			outputs, feeRate, minConfs, strategy, selectedUtxos,
+		)
+		if err != nil {
+			return err
+		}
+
+		plan = newDryRunPlan(
+			action, authoredTx.Tx, authoredTx.TotalInput,
+			authoredTx.ChangeIndex, feeRate,
+			strategy != wallet.CoinSelectionRandom,
+		)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// planFundingTx plans the transaction that funds channels of the given
+// amounts. The funding output scripts are only known once the peers sent us
+// their keys, so outputs of the same size are used in their place.
+func (r *rpcServer) planFundingTx(action string, amts []btcutil.Amount,
+	feeRate chainfee.SatPerKWeight, minConfs int32,
+	strategy wallet.CoinSelectionStrategy,
+	selectedUtxos fn.Set[wire.OutPoint]) (*lnrpc.DryRunPlan, error) {
+
+	// Both P2WSH and P2TR funding outputs have a 34 byte script, so a
+	// P2WSH output has the same weight as the real funding output.
+	fundingScript, err := input.WitnessScriptHash(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := make([]*wire.TxOut, 0, len(amts))
+	for _, amt := range amts {
+		outputs = append(
+			outputs, wire.NewTxOut(int64(amt), fundingScript),
+		)
+	}
+
+	plan, err := r.planWalletTx(
+		action, outputs, feeRate, minConfs, strategy, selectedUtxos,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Txid = ""
+	for _, output := range plan.Outputs {
+		if !output.IsChange {
+			output.PkScript = ""
+		}
+	}
+
+	return plan, nil
+}
+
+// planSweepAllTx plans the given transaction that sweeps the coins of the
+// wallet. If the reserved value is sent back to the wallet, changeAddr is the
+// address it's sent to. Its txid is only final without a change output, as a
+// dry run doesn't derive the change address that would be used.
+func (r *rpcServer) planSweepAllTx(tx *wire.MsgTx, changeAddr btcutil.Address,
+	feeRate chainfee.SatPerKWeight) (*lnrpc.DryRunPlan, error) {
+
+	var totalInput btcutil.Amount
+	for _, txIn := range tx.TxIn {
+		utxo, err := r.server.cc.Wallet.FetchOutpointInfo(
+			&txIn.PreviousOutPoint,
+		)
+		if err != nil {
+			return nil, err
+		}
+		totalInput += utxo.Value
+	}
+
+	changeIndex := -1
+	if changeAddr != nil {
+		changeScript, err := txscript.PayToAddrScript(changeAddr)
+		if err != nil {
+			return nil, err
+		}
+
+		for idx, txOut := range tx.TxOut {
+			if bytes.Equal(txOut.PkScript, changeScript) {
+				changeIndex = idx
+			}
+		}
+	}
+
+	return newDryRunPlan(
+		dryRunSendCoins, tx, totalInput, changeIndex, feeRate,
+		changeAddr == nil,
+	), nil
+}
+
+// planOpenChannel plans the funding transaction of the channel open request.
+func (r *rpcServer) planOpenChannel(in *lnrpc.OpenChannelRequest,
+	req *funding.InitFundingMsg) (*lnrpc.DryRunPlan, error) {
+
+	// Transactions funded externally or with the maximum amount of the
+	// wallet are assembled by the funding flow itself, so they can't be
+	// planned upfront.
+	switch {
+	case in.FundingShim != nil:
+		return nil, errors.New("dry run not supported with funding " +
+			"shims")
+
+	case in.FundMax:
+		return nil, errors.New("dry run not supported with fund max")
+	}
+
+	if _, err := r.server.FindPeer(req.TargetPubkey); err != nil {
+		return nil, err
+	}
+
+	var selectedUtxos fn.Set[wire.OutPoint]
+	if len(req.Outpoints) != 0 {
+		selectedUtxos = fn.NewSet(req.Outpoints...)
+	}
+
+	return r.planFundingTx(
+		dryRunOpenChannel, []btcutil.Amount{req.LocalFundingAmt},
+		req.FundingFeePerKw, req.MinConfs,
+		r.server.cc.Wallet.Cfg.CoinSelectionStrategy, selectedUtxos,
+	)
+}
+
+// planBatchOpenChannel plans the funding transaction of the batch channel
+// open request.
+func (r *rpcServer) planBatchOpenChannel(in *lnrpc.BatchOpenChannelRequest,
+	batcher *funding.Batcher) (*lnrpc.DryRunPlan, error) {
+
+	fundingReqs, err := batcher.ParseRequests(in)
+	if err != nil {
+		return nil, err
+	}
+
+	amts := make([]btcutil.Amount, 0, len(fundingReqs))
+	for idx, req := range fundingReqs {
+		if _, err := r.server.FindPeer(req.TargetPubkey); err != nil {
+			return nil, fmt.Errorf("channel %d: %w", idx, err)
+		}
+
+		amts = append(amts, req.LocalFundingAmt)
+	}
+
+	strategy, err := lnrpc.UnmarshallCoinSelectionStrategy(
+		in.CoinSelectionStrategy,
+		r.server.cc.Wallet.Cfg.CoinSelectionStrategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like the batch funding itself, we take the fee rate and min conf
+	// settings from the first request, as all of them are equal. The
+	// batch is funded with a fee rate in sat/vbyte, so the rate is
+	// rounded the same way.
+	firstReq := fundingReqs[0]
+	feeRate := firstReq.FundingFeePerKw.FeePerVByte().FeePerKWeight()
+
+	return r.planFundingTx(
+		dryRunBatchOpen, amts, feeRate, firstReq.MinConfs, strategy,
+		nil,
+	)
+}
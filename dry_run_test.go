@@ -0,0 +1,42 @@
+package lnd
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewDryRunPlan tests that the plan of a transaction reports its inputs,
+// outputs and fee.
+func TestNewDryRunPlan(t *testing.T) {
+	t.Parallel()
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 1},
+	})
+	tx.AddTxOut(wire.NewTxOut(60_000, []byte{0x51}))
+	tx.AddTxOut(wire.NewTxOut(39_000, []byte{0x52}))
+
+	feeRate := chainfee.SatPerKWeight(2500)
+	plan := newDryRunPlan(dryRunSendCoins, tx, 100_000, 1, feeRate, true)
+	require.Equal(t, &lnrpc.DryRunPlan{
+		Action: dryRunSendCoins,
+		Txid:   tx.TxHash().String(),
+		Inputs: []string{tx.TxIn[0].PreviousOutPoint.String()},
+		Outputs: []*lnrpc.DryRunOutput{
+			{PkScript: "51", AmountSat: 60_000},
+			{PkScript: "52", AmountSat: 39_000, IsChange: true},
+		},
+		FeeSat:      1_000,
+		SatPerVbyte: 10,
+	}, plan)
+
+	// A plan of a transaction that isn't fully determined has no txid.
+	plan = newDryRunPlan(dryRunSendCoins, tx, 100_000, -1, feeRate, false)
+	require.Empty(t, plan.Txid)
+	require.False(t, plan.Outputs[1].IsChange)
+}
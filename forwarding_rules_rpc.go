@@ -0,0 +1,149 @@
+package lnd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// unmarshallForwardingRule converts a forwarding rule of the RPC into the rule
+// evaluated by the links.
+func unmarshallForwardingRule(
+	rpcRule *lnrpc.ForwardingRule) (htlcswitch.ForwardingRule, error) {
+
+	rule := htlcswitch.ForwardingRule{
+		Deny:        rpcRule.Deny,
+		MaxExposure: lnwire.MilliSatoshi(rpcRule.MaxExposureMsat),
+		MaxForwards: rpcRule.MaxForwards,
+		RateInterval: time.Duration(rpcRule.RateIntervalSeconds) *
+			time.Second,
+		WindowStart: time.Duration(rpcRule.WindowStartSeconds) *
+			time.Second,
+		WindowEnd: time.Duration(rpcRule.WindowEndSeconds) *
+			time.Second,
+	}
+
+	if rpcRule.Peer != "" {
+		peer, err := hex.DecodeString(rpcRule.Peer)
+		if err != nil {
+			return rule, fmt.Errorf("invalid peer: %w", err)
+		}
+		if len(peer) != 33 {
+			return rule, fmt.Errorf("invalid peer length: %d",
+				len(peer))
+		}
+
+		var peerKey [33]byte
+		copy(peerKey[:], peer)
+		rule.Peer = fn.Some(peerKey)
+	}
+
+	if rpcRule.ChanId != 0 {
+		rule.ChanID = fn.Some(
+			lnwire.NewShortChanIDFromInt(rpcRule.ChanId),
+		)
+	}
+
+	return rule, nil
+}
+
+// unmarshallForwardingRules converts the forwarding rules of the RPC into the
+// rules evaluated by the links.
+func unmarshallForwardingRules(
+	rpcRules []*lnrpc.ForwardingRule) ([]htlcswitch.ForwardingRule, error) {
+
+	rules := make([]htlcswitch.ForwardingRule, 0, len(rpcRules))
+	for i, rpcRule := range rpcRules {
+		rule, err := unmarshallForwardingRule(rpcRule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule %d: %w", i, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// marshallForwardingRule converts a forwarding rule into its RPC
+// representation.
+func marshallForwardingRule(
+	rule *htlcswitch.ForwardingRule) *lnrpc.ForwardingRule {
+
+	rpcRule := &lnrpc.ForwardingRule{
+		Deny:                rule.Deny,
+		MaxExposureMsat:     uint64(rule.MaxExposure),
+		MaxForwards:         rule.MaxForwards,
+		RateIntervalSeconds: uint32(rule.RateInterval / time.Second),
+		WindowStartSeconds:  uint32(rule.WindowStart / time.Second),
+		WindowEndSeconds:    uint32(rule.WindowEnd / time.Second),
+	}
+
+	rule.Peer.WhenSome(func(peer [33]byte) {
+		rpcRule.Peer = hex.EncodeToString(peer[:])
+	})
+	rule.ChanID.WhenSome(func(chanID lnwire.ShortChannelID) {
+		rpcRule.ChanId = chanID.ToUint64()
+	})
+
+	return rpcRule
+}
+
+// loadForwardingRules reads the forwarding rules from a JSON file in the
+// format of the SetForwardingRules request.
+func loadForwardingRules(path string) ([]htlcswitch.ForwardingRule, error) {
+	rulesJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var req lnrpc.SetForwardingRulesRequest
+	if err := protojson.Unmarshal(rulesJSON, &req); err != nil {
+		return nil, fmt.Errorf("unable to parse forwarding rules "+
+			"file %v: %w", path, err)
+	}
+
+	return unmarshallForwardingRules(req.Rules)
+}
+
+// SetForwardingRules replaces the forwarding rules that are evaluated by the
+// outgoing links.
+func (r *rpcServer) SetForwardingRules(_ context.Context,
+	req *lnrpc.SetForwardingRulesRequest) (
+	*lnrpc.SetForwardingRulesResponse, error) {
+
+	rules, err := unmarshallForwardingRules(req.Rules)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	err = r.server.forwardingRules.SetRules(rules)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return &lnrpc.SetForwardingRulesResponse{}, nil
+}
+
+// ListForwardingRules returns the active forwarding rules.
+func (r *rpcServer) ListForwardingRules(_ context.Context,
+	_ *lnrpc.ListForwardingRulesRequest) (
+	*lnrpc.ListForwardingRulesResponse, error) {
+
+	resp := &lnrpc.ListForwardingRulesResponse{}
+	for _, rule := range r.server.forwardingRules.Rules() {
+		resp.Rules = append(resp.Rules, marshallForwardingRule(&rule))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,112 @@
+package lnd
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestForwardingRulesFile tests that the forwarding rules are loaded from a
+// JSON file in the format of the SetForwardingRules request.
+func TestForwardingRulesFile(t *testing.T) {
+	t.Parallel()
+
+	peer := [33]byte{2, 1}
+	rulesJSON := `{
+		"rules": [{
+			"chan_id": "1",
+			"deny": true
+		}, {
+			"peer": "` + hex.EncodeToString(peer[:]) + `",
+			"max_exposure_msat": "2000",
+			"max_forwards": 10,
+			"rate_interval_seconds": 60,
+			"window_start_seconds": 79200,
+			"window_end_seconds": 7200
+		}]
+	}`
+	path := filepath.Join(t.TempDir(), "rules.json")
+	require.NoError(t, os.WriteFile(path, []byte(rulesJSON), 0600))
+
+	rules, err := loadForwardingRules(path)
+	require.NoError(t, err)
+	require.Equal(t, []htlcswitch.ForwardingRule{{
+		ChanID: fn.Some(lnwire.NewShortChanIDFromInt(1)),
+		Deny:   true,
+	}, {
+		Peer:         fn.Some(peer),
+		MaxExposure:  2000,
+		MaxForwards:  10,
+		RateInterval: time.Minute,
+		WindowStart:  22 * time.Hour,
+		WindowEnd:    2 * time.Hour,
+	}}, rules)
+
+	// A file with an invalid peer is rejected.
+	rulesJSON = `{"rules": [{"peer": "02", "deny": true}]}`
+	require.NoError(t, os.WriteFile(path, []byte(rulesJSON), 0600))
+	_, err = loadForwardingRules(path)
+	require.ErrorContains(t, err, "invalid peer length")
+}
+
+// TestForwardingRulesRPC tests that the forwarding rules are replaced and
+// listed through the RPC server.
+func TestForwardingRulesRPC(t *testing.T) {
+	t.Parallel()
+
+	rules := htlcswitch.NewForwardingRules(clock.NewDefaultClock())
+	r := &rpcServer{
+		server: &server{
+			forwardingRules: rules,
+		},
+	}
+	ctx := context.Background()
+
+	peer := [33]byte{3, 7}
+	rpcRules := []*lnrpc.ForwardingRule{{
+		ChanId: lnwire.NewShortChanIDFromInt(5).ToUint64(),
+		Deny:   true,
+	}, {
+		Peer:                hex.EncodeToString(peer[:]),
+		MaxForwards:         2,
+		RateIntervalSeconds: 3600,
+	}}
+	_, err := r.SetForwardingRules(ctx, &lnrpc.SetForwardingRulesRequest{
+		Rules: rpcRules,
+	})
+	require.NoError(t, err)
+	require.Len(t, rules.Rules(), 2)
+
+	resp, err := r.ListForwardingRules(
+		ctx, &lnrpc.ListForwardingRulesRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Rules, len(rpcRules))
+	for i := range rpcRules {
+		require.Equal(t, rpcRules[i].String(), resp.Rules[i].String())
+	}
+
+	// Malformed rules are rejected and the active rules are kept.
+	_, err = r.SetForwardingRules(ctx, &lnrpc.SetForwardingRulesRequest{
+		Rules: []*lnrpc.ForwardingRule{{MaxForwards: 1}},
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+	require.Len(t, rules.Rules(), 2)
+
+	// An empty list removes all rules.
+	_, err = r.SetForwardingRules(ctx, &lnrpc.SetForwardingRulesRequest{})
+	require.NoError(t, err)
+	require.Empty(t, rules.Rules())
+}
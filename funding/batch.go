@@ -7,7 +7,9 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"sync"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -17,7 +19,6 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
-	"golang.org/x/sync/errgroup"
 )
 
 var (
@@ -28,6 +29,10 @@ var (
 
 	// emptyChannelID is a channel ID that consists of all zeros.
 	emptyChannelID = [32]byte{}
+
+	// errBatchAborted is the outcome of the channels that didn't fail
+	// themselves, but were aborted along with the whole batch.
+	errBatchAborted = errors.New("batch aborted")
 )
 
 // batchChannel is a struct that keeps track of a single channel's state within
@@ -40,6 +45,36 @@ type batchChannel struct {
 	fundingAddr   string
 	chanPoint     *wire.OutPoint
 	isPending     bool
+
+	// fixedChanID is true if the user specified the pending channel ID,
+	// which is then kept across funding attempts.
+	fixedChanID bool
+
+	// err is the error the channel failed with. Failed channels are
+	// removed from the batch if partial failures are allowed.
+	err error
+}
+
+// reset prepares the channel for another funding attempt of the batch, after
+// the previous attempt was undone.
+func (c *batchChannel) reset(netParams *chaincfg.Params) error {
+	if !c.fixedChanID {
+		if _, err := rand.Read(c.pendingChanID[:]); err != nil {
+			return fmt.Errorf("error making temp chan ID: %w", err)
+		}
+	}
+
+	c.fundingReq.PendingChanID = c.pendingChanID
+	c.fundingReq.ChanFunder = chanfunding.NewPsbtAssembler(
+		c.fundingReq.LocalFundingAmt, nil, netParams, false,
+	)
+	c.updateChan = nil
+	c.errChan = nil
+	c.fundingAddr = ""
+	c.chanPoint = nil
+	c.isPending = false
+
+	return nil
 }
 
 // processPsbtUpdate processes the first channel update message that is sent
@@ -172,6 +207,31 @@ type BatchConfig struct {
 	// Quit is the channel that is selected on to recognize if the main
 	// server is shutting down.
 	Quit chan struct{}
+
+	// AllowPartial allows individual channels of the batch to fail, for
+	// example because their peer is offline, without aborting the whole
+	// batch. The failed channels are removed and the funding transaction
+	// is re-computed for the remaining ones.
+	AllowPartial bool
+}
+
+// ChannelOutcome is the outcome of a single channel of a batch.
+type ChannelOutcome struct {
+	// Index is the position of the channel in the batch request.
+	Index int
+
+	// PendingChanID is the pending channel ID of the last funding attempt
+	// of the channel.
+	PendingChanID [32]byte
+
+	// NodePubkey is the public key of the peer of the channel.
+	NodePubkey *btcec.PublicKey
+
+	// ChanPoint is the funding outpoint of the channel if it was opened.
+	ChanPoint *wire.OutPoint
+
+	// Err is the error the channel failed with if it wasn't opened.
+	Err error
 }
 
 // Batcher is a type that can be used to perform an atomic funding of multiple
@@ -204,114 +264,97 @@ func (b *Batcher) BatchFund(ctx context.Context,
 	}
 
 	// Parse and validate each individual channel.
-	b.channels = make([]*batchChannel, 0, len(req.Channels))
-	for idx, rpcChannel := range req.Channels {
-		// If the user specifies a channel ID, it must be exactly 32
-		// bytes long.
-		if len(rpcChannel.PendingChanId) > 0 &&
-			len(rpcChannel.PendingChanId) != 32 {
+	if err := b.parseChannels(req); err != nil {
+		return nil, err
+	}
 
-			return nil, fmt.Errorf("invalid temp chan ID %x",
-				rpcChannel.PendingChanId)
+	// From this point on we can fail for any of the channels and for any
+	// number of reasons. This deferred function makes sure that the full
+	// operation is actually atomic: We either succeed and publish a
+	// transaction for the full batch or we clean up everything. If
+	// partial failures are allowed, the batch is only made of the channels
+	// that didn't fail.
+	attempt := b.active()
+	defer func() {
+		b.cleanup(ctx, attempt)
+	}()
+
+	for {
+		failed, err := b.fundAttempt(ctx, req, label)
+		if err == nil {
+			break
 		}
 
-		var pendingChanID [32]byte
-		if len(rpcChannel.PendingChanId) == 32 {
-			copy(pendingChanID[:], rpcChannel.PendingChanId)
+		// Unless partial failures are allowed and the error can be
+		// attributed to individual channels, the whole batch is
+		// aborted.
+		if !b.cfg.AllowPartial || len(failed) == 0 {
+			return nil, err
+		}
 
-			// Don't allow the user to be clever by just setting an
-			// all zero channel ID, we need a "real" value here.
-			if pendingChanID == emptyChannelID {
-				return nil, fmt.Errorf("invalid empty temp " +
-					"chan ID")
-			}
-		} else if _, err := rand.Read(pendingChanID[:]); err != nil {
-			return nil, fmt.Errorf("error making temp chan ID: %w",
-				err)
+		// Otherwise we undo the attempt, remove the failed channels
+		// and try again with the remaining ones, which re-computes
+		// the funding transaction without the outputs of the failed
+		// channels.
+		b.cleanup(ctx, attempt)
+		for _, channel := range failed {
+			log.Infof("[batchopenchannel] removing channel to "+
+				"NodeKey(%x) from batch: %v",
+				channel.fundingReq.TargetPubkey.
+					SerializeCompressed(),
+				channel.err)
 		}
 
-		//nolint:lll
-		fundingReq, err := b.cfg.RequestParser(&lnrpc.OpenChannelRequest{
-			SatPerVbyte:                uint64(req.SatPerVbyte),
-			TargetConf:                 req.TargetConf,
-			MinConfs:                   req.MinConfs,
-			SpendUnconfirmed:           req.SpendUnconfirmed,
-			NodePubkey:                 rpcChannel.NodePubkey,
-			LocalFundingAmount:         rpcChannel.LocalFundingAmount,
-			PushSat:                    rpcChannel.PushSat,
-			Private:                    rpcChannel.Private,
-			MinHtlcMsat:                rpcChannel.MinHtlcMsat,
-			RemoteCsvDelay:             rpcChannel.RemoteCsvDelay,
-			CloseAddress:               rpcChannel.CloseAddress,
-			RemoteMaxValueInFlightMsat: rpcChannel.RemoteMaxValueInFlightMsat,
-			RemoteMaxHtlcs:             rpcChannel.RemoteMaxHtlcs,
-			MaxLocalCsv:                rpcChannel.MaxLocalCsv,
-			CommitmentType:             rpcChannel.CommitmentType,
-			ZeroConf:                   rpcChannel.ZeroConf,
-			ScidAlias:                  rpcChannel.ScidAlias,
-			BaseFee:                    rpcChannel.BaseFee,
-			FeeRate:                    rpcChannel.FeeRate,
-			UseBaseFee:                 rpcChannel.UseBaseFee,
-			UseFeeRate:                 rpcChannel.UseFeeRate,
-			RemoteChanReserveSat:       rpcChannel.RemoteChanReserveSat,
-			Memo:                       rpcChannel.Memo,
-			FundingShim: &lnrpc.FundingShim{
-				Shim: &lnrpc.FundingShim_PsbtShim{
-					PsbtShim: &lnrpc.PsbtShim{
-						PendingChanId: pendingChanID[:],
-						NoPublish:     true,
-					},
-				},
-			},
-		})
-		if err != nil {
-			return nil, fmt.Errorf("error parsing channel %d: %w",
-				idx, err)
+		attempt = b.active()
+		if len(attempt) == 0 {
+			return nil, fmt.Errorf("all channels of the batch "+
+				"failed: %w", err)
 		}
 
-		// Prepare the stuff that we'll need for the internal PSBT
-		// funding.
-		fundingReq.PendingChanID = pendingChanID
-		fundingReq.ChanFunder = chanfunding.NewPsbtAssembler(
-			btcutil.Amount(rpcChannel.LocalFundingAmount), nil,
-			b.cfg.NetParams, false,
-		)
+		for _, channel := range attempt {
+			if err := channel.reset(b.cfg.NetParams); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-		b.channels = append(b.channels, &batchChannel{
-			pendingChanID: pendingChanID,
-			fundingReq:    fundingReq,
-		})
+	rpcPoints := make([]*lnrpc.PendingUpdate, len(attempt))
+	for idx, channel := range attempt {
+		rpcPoints[idx] = &lnrpc.PendingUpdate{
+			Txid:        channel.chanPoint.Hash.CloneBytes(),
+			OutputIndex: channel.chanPoint.Index,
+		}
 	}
 
-	// From this point on we can fail for any of the channels and for any
-	// number of reasons. This deferred function makes sure that the full
-	// operation is actually atomic: We either succeed and publish a
-	// transaction for the full batch or we clean up everything.
-	defer b.cleanup(ctx)
+	return rpcPoints, nil
+}
+
+// fundAttempt negotiates the channels of the batch that didn't fail yet with
+// their peers, and funds and publishes the batch transaction for them. If
+// the attempt fails because of individual channels, they are marked as failed
+// and returned along with the error.
+func (b *Batcher) fundAttempt(ctx context.Context,
+	req *lnrpc.BatchOpenChannelRequest,
+	label string) ([]*batchChannel, error) {
+
+	channels := b.active()
 
 	// Now that we know the user input is sane, we need to kick off the
 	// channel funding negotiation with the peers. Because we specified a
 	// PSBT assembler, we'll get a special response in the channel once the
 	// funding output script is known (which we need to craft the TX).
-	eg := &errgroup.Group{}
-	for _, channel := range b.channels {
+	for _, channel := range channels {
 		channel.updateChan, channel.errChan = b.cfg.ChannelOpener(
 			channel.fundingReq,
 		)
-
-		// Launch a goroutine that waits for the initial response on
-		// either the update or error chan.
-		channel := channel
-		eg.Go(func() error {
-			return b.waitForUpdate(channel, true)
-		})
 	}
 
-	// Wait for all goroutines to report back. Any error at this stage means
+	// Wait for all channels to report back. Any error at this stage means
 	// we need to abort.
-	if err := eg.Wait(); err != nil {
-		return nil, fmt.Errorf("error batch opening channel, initial "+
-			"negotiation failed: %v", err)
+	failed, err := b.waitForUpdates(channels, true)
+	if err != nil {
+		return failed, fmt.Errorf("error batch opening channel, "+
+			"initial negotiation failed: %w", err)
 	}
 
 	// We can now assemble all outputs that we're going to give to the PSBT
@@ -319,7 +362,7 @@ func (b *Batcher) BatchFund(ctx context.Context,
 	txTemplate := &walletrpc.TxTemplate{
 		Outputs: make(map[string]uint64),
 	}
-	for _, channel := range b.channels {
+	for _, channel := range channels {
 		txTemplate.Outputs[channel.fundingAddr] = uint64(
 			channel.fundingReq.LocalFundingAmt,
 		)
@@ -330,7 +373,7 @@ func (b *Batcher) BatchFund(ctx context.Context,
 	// and can craft our PSBT now. We take the fee rate and min conf
 	// settings from the first request as all of them should be equal
 	// anyway.
-	firstReq := b.channels[0].fundingReq
+	firstReq := channels[0].fundingReq
 	feeRateSatPerVByte := firstReq.FundingFeePerKw.FeePerVByte()
 	changeType := walletrpc.ChangeAddressType_CHANGE_ADDRESS_TYPE_P2TR
 	fundPsbtReq := &walletrpc.FundPsbtRequest{
@@ -369,12 +412,15 @@ func (b *Batcher) BatchFund(ctx context.Context,
 
 	// With the funded PSBT we can now advance the funding state machine of
 	// each of the channels.
-	for _, channel := range b.channels {
+	for _, channel := range channels {
 		err = b.cfg.Wallet.PsbtFundingVerify(
 			channel.pendingChanID, unsignedPacket, false,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("error verifying PSBT: %w", err)
+			channel.err = fmt.Errorf("error verifying PSBT: %w",
+				err)
+
+			return []*batchChannel{channel}, channel.err
 		}
 	}
 
@@ -399,32 +445,25 @@ func (b *Batcher) BatchFund(ctx context.Context,
 
 	// Advance the funding state machine of each of the channels a last time
 	// to complete the negotiation with the now signed funding TX.
-	for _, channel := range b.channels {
+	for _, channel := range channels {
 		err = b.cfg.Wallet.PsbtFundingFinalize(
 			channel.pendingChanID, nil, finalTx,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("error finalizing PSBT: %w", err)
+			channel.err = fmt.Errorf("error finalizing PSBT: %w",
+				err)
+
+			return []*batchChannel{channel}, channel.err
 		}
 	}
 
 	// Now every channel should be ready for the funding transaction to be
 	// broadcast. Let's wait for the updates that actually confirm this
-	// state.
-	eg = &errgroup.Group{}
-	for _, channel := range b.channels {
-		// Launch another goroutine that waits for the channel pending
-		// response on the update chan.
-		channel := channel
-		eg.Go(func() error {
-			return b.waitForUpdate(channel, false)
-		})
-	}
-
-	// Wait for all updates and make sure we're still good to proceed.
-	if err := eg.Wait(); err != nil {
-		return nil, fmt.Errorf("error batch opening channel, final "+
-			"negotiation failed: %v", err)
+	// state, and make sure we're still good to proceed.
+	failed, err = b.waitForUpdates(channels, false)
+	if err != nil {
+		return failed, fmt.Errorf("error batch opening channel, final "+
+			"negotiation failed: %w", err)
 	}
 
 	// Great, we're now finally ready to publish the transaction.
@@ -435,15 +474,203 @@ func (b *Batcher) BatchFund(ctx context.Context,
 	}
 	b.didPublish = true
 
-	rpcPoints := make([]*lnrpc.PendingUpdate, len(b.channels))
+	return nil, nil
+}
+
+// Outcomes returns the outcome of each channel of the batch, in the order of
+// the batch request. It should only be called once BatchFund returned.
+func (b *Batcher) Outcomes() []*ChannelOutcome {
+	outcomes := make([]*ChannelOutcome, 0, len(b.channels))
 	for idx, channel := range b.channels {
-		rpcPoints[idx] = &lnrpc.PendingUpdate{
-			Txid:        channel.chanPoint.Hash.CloneBytes(),
-			OutputIndex: channel.chanPoint.Index,
+		outcome := &ChannelOutcome{
+			Index:         idx,
+			PendingChanID: channel.pendingChanID,
+			NodePubkey:    channel.fundingReq.TargetPubkey,
+			Err:           channel.err,
 		}
+
+		switch {
+		case channel.err != nil:
+
+		case b.didPublish:
+			outcome.ChanPoint = channel.chanPoint
+
+		default:
+			outcome.Err = errBatchAborted
+		}
+
+		outcomes = append(outcomes, outcome)
 	}
 
-	return rpcPoints, nil
+	return outcomes
+}
+
+// active returns the channels of the batch that didn't fail.
+func (b *Batcher) active() []*batchChannel {
+	channels := make([]*batchChannel, 0, len(b.channels))
+	for _, channel := range b.channels {
+		if channel.err == nil {
+			channels = append(channels, channel)
+		}
+	}
+
+	return channels
+}
+
+// ParseRequests parses and validates each channel of the batch request and
+// returns their funding initialization messages, without starting the funding
+// negotiation with any of the peers. This allows a batch to be planned without
+// opening any channels.
+//
+// NOTE: This method should only be called once per instance and not in
+// combination with BatchFund.
+func (b *Batcher) ParseRequests(
+	req *lnrpc.BatchOpenChannelRequest) ([]*InitFundingMsg, error) {
+
+	if _, err := labels.ValidateAPI(req.Label); err != nil {
+		return nil, err
+	}
+
+	if err := b.parseChannels(req); err != nil {
+		return nil, err
+	}
+
+	fundingReqs := make([]*InitFundingMsg, 0, len(b.channels))
+	for _, channel := range b.channels {
+		fundingReqs = append(fundingReqs, channel.fundingReq)
+	}
+
+	return fundingReqs, nil
+}
+
+// parseChannels parses and validates each individual channel of the batch
+// request.
+func (b *Batcher) parseChannels(req *lnrpc.BatchOpenChannelRequest) error {
+	b.channels = make([]*batchChannel, 0, len(req.Channels))
+	for idx, rpcChannel := range req.Channels {
+		// If the user specifies a channel ID, it must be exactly 32
+		// bytes long.
+		if len(rpcChannel.PendingChanId) > 0 &&
+			len(rpcChannel.PendingChanId) != 32 {
+
+			return fmt.Errorf("invalid temp chan ID %x",
+				rpcChannel.PendingChanId)
+		}
+
+		var pendingChanID [32]byte
+		if len(rpcChannel.PendingChanId) == 32 {
+			copy(pendingChanID[:], rpcChannel.PendingChanId)
+
+			// Don't allow the user to be clever by just setting an
+			// all zero channel ID, we need a "real" value here.
+			if pendingChanID == emptyChannelID {
+				return fmt.Errorf("invalid empty temp " +
+					"chan ID")
+			}
+		} else if _, err := rand.Read(pendingChanID[:]); err != nil {
+			return fmt.Errorf("error making temp chan ID: %w",
+				err)
+		}
+
+		//nolint:lll
+		fundingReq, err := b.cfg.RequestParser(&lnrpc.OpenChannelRequest{
+			SatPerVbyte:                uint64(req.SatPerVbyte),
+			TargetConf:                 req.TargetConf,
+			MinConfs:                   req.MinConfs,
+			SpendUnconfirmed:           req.SpendUnconfirmed,
+			NodePubkey:                 rpcChannel.NodePubkey,
+			LocalFundingAmount:         rpcChannel.LocalFundingAmount,
+			PushSat:                    rpcChannel.PushSat,
+			Private:                    rpcChannel.Private,
+			MinHtlcMsat:                rpcChannel.MinHtlcMsat,
+			RemoteCsvDelay:             rpcChannel.RemoteCsvDelay,
+			CloseAddress:               rpcChannel.CloseAddress,
+			RemoteMaxValueInFlightMsat: rpcChannel.RemoteMaxValueInFlightMsat,
+			RemoteMaxHtlcs:             rpcChannel.RemoteMaxHtlcs,
+			MaxLocalCsv:                rpcChannel.MaxLocalCsv,
+			CommitmentType:             rpcChannel.CommitmentType,
+			ZeroConf:                   rpcChannel.ZeroConf,
+			ScidAlias:                  rpcChannel.ScidAlias,
+			BaseFee:                    rpcChannel.BaseFee,
+			FeeRate:                    rpcChannel.FeeRate,
+			UseBaseFee:                 rpcChannel.UseBaseFee,
+			UseFeeRate:                 rpcChannel.UseFeeRate,
+			RemoteChanReserveSat:       rpcChannel.RemoteChanReserveSat,
+			Memo:                       rpcChannel.Memo,
+			FundingShim: &lnrpc.FundingShim{
+				Shim: &lnrpc.FundingShim_PsbtShim{
+					PsbtShim: &lnrpc.PsbtShim{
+						PendingChanId: pendingChanID[:],
+						NoPublish:     true,
+					},
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("error parsing channel %d: %w",
+				idx, err)
+		}
+
+		// Prepare the stuff that we'll need for the internal PSBT
+		// funding.
+		fundingReq.PendingChanID = pendingChanID
+		fundingReq.ChanFunder = chanfunding.NewPsbtAssembler(
+			btcutil.Amount(rpcChannel.LocalFundingAmount), nil,
+			b.cfg.NetParams, false,
+		)
+
+		b.channels = append(b.channels, &batchChannel{
+			pendingChanID: pendingChanID,
+			fundingReq:    fundingReq,
+			fixedChanID:   len(rpcChannel.PendingChanId) == 32,
+		})
+	}
+
+	return nil
+}
+
+// waitForUpdates waits for the next update of each of the given channels.
+// The channels that failed are marked as such and returned along with the
+// first of their errors.
+func (b *Batcher) waitForUpdates(channels []*batchChannel,
+	firstUpdate bool) ([]*batchChannel, error) {
+
+	errs := make([]error, len(channels))
+
+	var wg sync.WaitGroup
+	for idx, channel := range channels {
+		wg.Add(1)
+		go func(idx int, channel *batchChannel) {
+			defer wg.Done()
+
+			errs[idx] = b.waitForUpdate(channel, firstUpdate)
+		}(idx, channel)
+	}
+	wg.Wait()
+
+	var (
+		failed   []*batchChannel
+		firstErr error
+	)
+	for idx, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		// A shutdown can't be attributed to the channel.
+		if errors.Is(err, errShuttingDown) {
+			return nil, err
+		}
+
+		channels[idx].err = err
+		failed = append(failed, channels[idx])
+
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return failed, firstErr
 }
 
 // waitForUpdate waits for an incoming channel update (or error) for a single
@@ -481,9 +708,10 @@ func (b *Batcher) waitForUpdate(channel *batchChannel, firstUpdate bool) error {
 	}
 }
 
-// cleanup tries to remove any pending state or UTXO locks in case we had to
-// abort before finalizing and publishing the funding transaction.
-func (b *Batcher) cleanup(ctx context.Context) {
+// cleanup tries to remove any pending state or UTXO locks of the given
+// channels in case we had to abort a funding attempt before finalizing and
+// publishing the funding transaction.
+func (b *Batcher) cleanup(ctx context.Context, channels []*batchChannel) {
 	// Did we publish a transaction? Then there's nothing to clean up since
 	// we succeeded.
 	if b.didPublish {
@@ -516,10 +744,11 @@ func (b *Batcher) cleanup(ctx context.Context) {
 				lockedUTXO.Outpoint.String(), err)
 		}
 	}
+	b.lockedUTXOs = nil
 
 	// Then go through all channels that ever got into a pending state and
 	// remove the pending channel by abandoning them.
-	for _, channel := range b.channels {
+	for _, channel := range channels {
 		if !channel.isPending {
 			continue
 		}
@@ -533,7 +762,7 @@ func (b *Batcher) cleanup(ctx context.Context) {
 
 	// And finally clean up the funding shim for each channel that didn't
 	// make it into a pending state.
-	for _, channel := range b.channels {
+	for _, channel := range channels {
 		if channel.isPending {
 			continue
 		}
@@ -420,3 +420,53 @@ func TestBatchFund(t *testing.T) {
 		})
 	}
 }
+
+// TestBatchFundPartial tests that a channel that fails its negotiation is
+// removed from the batch if partial failures are allowed, and that the batch
+// is funded again for the remaining channels.
+func TestBatchFundPartial(t *testing.T) {
+	t.Parallel()
+
+	h := newTestHarness(t, false, true, false)
+	h.batcher.cfg.AllowPartial = true
+
+	req := &lnrpc.BatchOpenChannelRequest{
+		Channels: []*lnrpc.BatchOpenChannel{{
+			NodePubkey:         testPubKey1Bytes,
+			LocalFundingAmount: 1234,
+		}, {
+			NodePubkey:         testPubKey2Bytes,
+			LocalFundingAmount: 4321,
+		}},
+		SatPerVbyte: 5,
+		MinConfs:    1,
+	}
+	updates, err := h.batcher.BatchFund(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	require.True(t, h.txPublished)
+
+	// The first attempt was undone, and the first channel was opened again
+	// under a new pending channel ID.
+	require.Len(t, h.releasedUTXOs, 1)
+	require.Len(t, h.intentsCreated, 3)
+	require.Len(t, h.intentsCanceled, 1)
+	require.Len(t, h.abandonedChannels, 1)
+
+	outcomes := h.batcher.Outcomes()
+	require.Len(t, outcomes, 2)
+
+	require.NoError(t, outcomes[0].Err)
+	require.NotNil(t, outcomes[0].ChanPoint)
+	require.EqualValues(t, 3, outcomes[0].ChanPoint.Index)
+	require.Equal(t, updates[0].Txid, outcomes[0].ChanPoint.Hash[:])
+	require.Contains(t, h.intentsCreated, outcomes[0].PendingChanID)
+
+	require.ErrorIs(t, outcomes[1].Err, errFundingFailed)
+	require.Nil(t, outcomes[1].ChanPoint)
+	require.Contains(t, h.intentsCanceled, outcomes[1].PendingChanID)
+	require.Equal(
+		t, testPubKey2Bytes,
+		outcomes[1].NodePubkey.SerializeCompressed(),
+	)
+}
@@ -0,0 +1,65 @@
+package funding
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// commitmentFormat returns the commitment format of an inbound channel policy
+// that stands for the given commitment type.
+func commitmentFormat(
+	commitType lnwallet.CommitmentType) lnwire.CommitmentFormat {
+
+	switch commitType {
+	case lnwallet.CommitmentTypeTweakless:
+		return lnwire.CommitmentFormatStaticRemoteKey
+
+	case lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx:
+		return lnwire.CommitmentFormatAnchors
+
+	case lnwallet.CommitmentTypeScriptEnforcedLease:
+		return lnwire.CommitmentFormatScriptEnforcedLease
+
+	case lnwallet.CommitmentTypeSimpleTaproot:
+		return lnwire.CommitmentFormatSimpleTaproot
+
+	case lnwallet.CommitmentTypeSimpleTaprootOverlay:
+		return lnwire.CommitmentFormatSimpleTaprootOverlay
+
+	default:
+		return lnwire.CommitmentFormatLegacy
+	}
+}
+
+// checkInboundPolicy returns an error if a channel of the given capacity and
+// commitment type, opened by a peer that signals the given features, violates
+// the inbound channel policy. The capacity isn't checked if it is zero, as
+// the initiator doesn't always know it before the funding flow starts.
+func checkInboundPolicy(policy *lnwire.InboundChanPolicy,
+	capacity btcutil.Amount, commitType lnwallet.CommitmentType,
+	features *lnwire.FeatureVector) error {
+
+	if policy == nil {
+		return nil
+	}
+
+	if capacity != 0 && capacity < policy.MinChanSize {
+		return lnwallet.ErrChanTooSmall(capacity, policy.MinChanSize)
+	}
+
+	format := commitmentFormat(commitType)
+	if !policy.AllowsCommitmentFormat(format) {
+		return lnwallet.ErrCommitFormatNotAllowed(format)
+	}
+
+	var rawFeatures *lnwire.RawFeatureVector
+	if features != nil {
+		rawFeatures = features.RawFeatureVector
+	}
+	if bit, missing := policy.MissingFeature(rawFeatures); missing {
+		return lnwallet.ErrRequiredFeatureMissing(bit)
+	}
+
+	return nil
+}
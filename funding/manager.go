@@ -562,6 +562,21 @@ type Config struct {
 	// AuxResolver is an optional interface that can be used to modify the
 	// way contracts are resolved.
 	AuxResolver fn.Option[lnwallet.AuxContractResolver]
+
+	// ZeroConfRisk is an optional set of exposure limits for the inbound
+	// zero-conf channels that didn't confirm yet.
+	ZeroConfRisk *ZeroConfRisk
+
+	// InboundPolicy is an optional set of requirements for the channels
+	// that peers open to us. Channels that violate it are rejected before
+	// the channel acceptor is queried and any reservation is made.
+	InboundPolicy *lnwire.InboundChanPolicy
+
+	// FetchInboundPolicy returns the inbound channel policy the given node
+	// published in its node announcement, or nil if it doesn't publish
+	// one. It is used to skip channel opens that the peer would reject.
+	FetchInboundPolicy func(*btcec.PublicKey) (*lnwire.InboundChanPolicy,
+		error)
 }
 
 // Manager acts as an orchestrator/bridge between the wallet's
@@ -589,6 +604,10 @@ type Manager struct {
 	// reservation created.
 	chanIDNonce atomic.Uint64
 
+	// policyMtx is a mutex that guards the default routing policy of the
+	// config, which can be changed while the manager is running.
+	policyMtx sync.RWMutex
+
 	// nonceMtx is a mutex that guards the pendingMusigNonces.
 	nonceMtx sync.RWMutex
 
@@ -724,6 +743,12 @@ func (f *Manager) start() error {
 		return err
 	}
 
+	// PSBT funding flows that were in progress can't be resumed, as their
+	// reservations were in memory only, so we abort them now.
+	if err := f.abortPsbtFlows(allChannels); err != nil {
+		return err
+	}
+
 	for _, channel := range allChannels {
 		chanID := lnwire.NewChanIDFromOutPoint(channel.FundingOutpoint)
 
@@ -955,6 +980,10 @@ func (f *Manager) failFundingFlow(peer lnpeer.Peer, cid *chanIdentifier,
 		log.Errorf("unable to cancel reservation: %v", err)
 	}
 
+	// The flow may fail before the reservation context is created, so the
+	// zero-conf reservation is released separately.
+	f.releaseZeroConf(peer.IdentityKey(), cid.tempChanID)
+
 	// In case the case where the reservation existed, send the funding
 	// error on the error channel.
 	if ctx != nil {
@@ -1054,6 +1083,7 @@ func (f *Manager) reservationCoordinator() {
 
 		case <-zombieSweepTicker.C:
 			f.pruneZombieReservations()
+			f.pruneExpiredUtxoReservations()
 
 		case <-f.quit:
 			return
@@ -1523,6 +1553,34 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		return
 	}
 
+	// Before we query the channel acceptor, we'll check to see what
+	// commitment format we can use with this peer. This is dependent on
+	// *both* us and the remote peer are signaling the proper feature bit
+	// if we're using implicit negotiation, and simply the channel type
+	// sent over if we're using explicit negotiation.
+	chanType, commitType, err := negotiateCommitmentType(
+		msg.ChannelType, peer.LocalFeatures(), peer.RemoteFeatures(),
+	)
+	if err != nil {
+		// TODO(roasbeef): should be using soft errors
+		log.Errorf("channel type negotiation failed: %v", err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
+	// Reject channels that violate our inbound channel policy before any
+	// further work is done for them.
+	err = checkInboundPolicy(
+		f.cfg.InboundPolicy, amt, commitType, peer.RemoteFeatures(),
+	)
+	if err != nil {
+		log.Infof("Rejecting channel from peer(%x) violating the "+
+			"inbound channel policy: %v",
+			peer.IdentityKey().SerializeCompressed(), err)
+		f.failFundingFlow(peer, cid, err)
+		return
+	}
+
 	// Send the OpenChannel request to the ChannelAcceptor to determine
 	// whether this node will accept the channel.
 	chanReq := &chanacceptor.ChannelAcceptRequest{
@@ -1543,27 +1601,6 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		msg.CsvDelay, msg.PendingChannelID,
 		peer.IdentityKey().SerializeCompressed())
 
-	// Attempt to initialize a reservation within the wallet. If the wallet
-	// has insufficient resources to create the channel, then the
-	// reservation attempt may be rejected. Note that since we're on the
-	// responding side of a single funder workflow, we don't commit any
-	// funds to the channel ourselves.
-	//
-	// Before we init the channel, we'll also check to see what commitment
-	// format we can use with this peer. This is dependent on *both* us and
-	// the remote peer are signaling the proper feature bit if we're using
-	// implicit negotiation, and simply the channel type sent over if we're
-	// using explicit negotiation.
-	chanType, commitType, err := negotiateCommitmentType(
-		msg.ChannelType, peer.LocalFeatures(), peer.RemoteFeatures(),
-	)
-	if err != nil {
-		// TODO(roasbeef): should be using soft errors
-		log.Errorf("channel type negotiation failed: %v", err)
-		f.failFundingFlow(peer, cid, err)
-		return
-	}
-
 	var scidFeatureVal bool
 	if hasFeatures(
 		peer.LocalFeatures(), peer.RemoteFeatures(),
@@ -1645,6 +1682,23 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		return
 	}
 
+	// The peer can double spend the funding transaction of a zero-conf
+	// channel until it confirms, so we make sure the unconfirmed inbound
+	// zero-conf channels stay within the configured exposure limits. The
+	// reservation is released when the funding flow completes or fails.
+	if zeroConf && f.cfg.ZeroConfRisk != nil {
+		err := f.cfg.ZeroConfRisk.Reserve(
+			peer.IdentityKey(), msg.PendingChannelID, amt,
+		)
+		if err != nil {
+			log.Errorf("Cancelling funding flow for zero-conf "+
+				"channel %v: %v", cid, err)
+			f.failFundingFlow(peer, cid, err)
+
+			return
+		}
+	}
+
 	// At this point, if we have an AuxFundingController active, we'll
 	// check to see if we have a special tapscript root to use in our
 	// MuSig funding output.
@@ -1662,6 +1716,11 @@ func (f *Manager) fundeeProcessOpenChannel(peer lnpeer.Peer,
 		return
 	}
 
+	// Attempt to initialize a reservation within the wallet. If the wallet
+	// has insufficient resources to create the channel, then the
+	// reservation attempt may be rejected. Note that since we're on the
+	// responding side of a single funder workflow, we don't commit any
+	// funds to the channel ourselves.
 	req := &lnwallet.InitFundingReserveMsg{
 		ChainHash:        &msg.ChainHash,
 		PendingChanID:    msg.PendingChannelID,
@@ -2205,6 +2264,21 @@ func (f *Manager) funderProcessAcceptChannel(peer lnpeer.Peer,
 			f.failFundingFlow(peer, cid, err)
 			return
 		}
+
+		// Before handing out the funding address, we store the state of
+		// the flow so funds sent to it can be recovered if we restart
+		// before the channel is stored.
+		err = f.persistPsbtFlow(
+			resCtx, pendingChanID, psbtErr.Intent,
+			channeldb.FundingFlowAwaitingPsbt,
+		)
+		if err != nil {
+			log.Errorf("Unable to store PSBT funding flow for "+
+				"contribution from %x: %v", peerKeyBytes, err)
+			f.failFundingFlow(peer, cid, err)
+			return
+		}
+
 		resCtx.updates <- &lnrpc.OpenStatusUpdate{
 			PendingChanId: pendingChanID[:],
 			Update: &lnrpc.OpenStatusUpdate_PsbtFund{
@@ -2337,6 +2411,15 @@ func (f *Manager) waitForPsbt(intent *chanfunding.PsbtIntent,
 			return
 		}
 
+		err = f.persistPsbtFlow(
+			resCtx, cid.tempChanID, intent,
+			channeldb.FundingFlowPsbtFinalized,
+		)
+		if err != nil {
+			failFlow("error storing PSBT flow", err)
+			return
+		}
+
 		// We are now ready to continue the funding flow.
 		f.continueFundingAccept(resCtx, cid)
 
@@ -3514,8 +3597,9 @@ func (f *Manager) extractAnnounceParams(c *channeldb.OpenChannel) (
 
 	// We don't necessarily want to go as low as the remote party allows.
 	// Check it against our default forwarding policy.
-	if fwdMinHTLC < f.cfg.DefaultRoutingPolicy.MinHTLCOut {
-		fwdMinHTLC = f.cfg.DefaultRoutingPolicy.MinHTLCOut
+	defaultPolicy := f.DefaultRoutingPolicy()
+	if fwdMinHTLC < defaultPolicy.MinHTLCOut {
+		fwdMinHTLC = defaultPolicy.MinHTLCOut
 	}
 
 	// We'll obtain the max HTLC value we can forward in our direction, as
@@ -4386,6 +4470,7 @@ func (f *Manager) newChanAnnouncement(localPubKey,
 
 	// We announce the channel with the default values. Some of
 	// these values can later be changed by crafting a new ChannelUpdate.
+	defaultPolicy := f.DefaultRoutingPolicy()
 	chanUpdateAnn := &lnwire.ChannelUpdate1{
 		ShortChannelID: shortChanID,
 		ChainHash:      chainHash,
@@ -4393,7 +4478,7 @@ func (f *Manager) newChanAnnouncement(localPubKey,
 		MessageFlags:   msgFlags,
 		ChannelFlags:   chanFlags,
 		TimeLockDelta: uint16(
-			f.cfg.DefaultRoutingPolicy.TimeLockDelta,
+			defaultPolicy.TimeLockDelta,
 		),
 		HtlcMinimumMsat: fwdMinHTLC,
 		HtlcMaximumMsat: fwdMaxHTLC,
@@ -4431,12 +4516,8 @@ func (f *Manager) newChanAnnouncement(localPubKey,
 		log.Infof("No channel forwarding policy specified for channel "+
 			"announcement of ChannelID(%v). "+
 			"Assuming default fee parameters.", chanID)
-		chanUpdateAnn.BaseFee = uint32(
-			f.cfg.DefaultRoutingPolicy.BaseFee,
-		)
-		chanUpdateAnn.FeeRate = uint32(
-			f.cfg.DefaultRoutingPolicy.FeeRate,
-		)
+		chanUpdateAnn.BaseFee = uint32(defaultPolicy.BaseFee)
+		chanUpdateAnn.FeeRate = uint32(defaultPolicy.FeeRate)
 	}
 
 	// With the channel update announcement constructed, we'll generate a
@@ -4730,6 +4811,36 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		return
 	}
 
+	// Skip channel opens the peer would reject according to the inbound
+	// channel policy it published. The channel size is only known up
+	// front if the wallet doesn't have to determine it.
+	if f.cfg.FetchInboundPolicy != nil {
+		policy, err := f.cfg.FetchInboundPolicy(peerKey)
+		if err != nil {
+			log.Debugf("Unable to fetch inbound channel policy of "+
+				"peer %x: %v", peerKey.SerializeCompressed(),
+				err)
+		}
+
+		var capacity btcutil.Amount
+		if !msg.SubtractFees && msg.FundUpToMaxAmt == 0 {
+			capacity = localAmt
+		}
+
+		err = checkInboundPolicy(
+			policy, capacity, commitType,
+			msg.Peer.LocalFeatures(),
+		)
+		if err != nil {
+			err = fmt.Errorf("peer would reject channel according "+
+				"to its inbound channel policy: %w", err)
+			log.Error(err)
+			msg.Err <- err
+
+			return
+		}
+	}
+
 	var (
 		zeroConf bool
 		scid     bool
@@ -5164,7 +5275,12 @@ func (f *Manager) cancelReservationCtx(peerKey *btcec.PublicKey,
 			err)
 	}
 
+	if ctx.reservation.IsPsbt() {
+		f.deletePsbtFlow(pendingChanID)
+	}
+
 	delete(nodeReservations, pendingChanID)
+	f.releaseZeroConf(peerKey, pendingChanID)
 
 	// If this was the last active reservation for this peer, delete the
 	// peer's entry altogether.
@@ -5188,7 +5304,16 @@ func (f *Manager) deleteReservationCtx(peerKey *btcec.PublicKey,
 		// No reservations for this node.
 		return
 	}
+
+	// Once the channel is stored, the state of its PSBT funding flow is
+	// no longer needed.
+	ctx, ok := nodeReservations[pendingChanID]
+	if ok && ctx.reservation.IsPsbt() {
+		f.deletePsbtFlow(pendingChanID)
+	}
+
 	delete(nodeReservations, pendingChanID)
+	f.releaseZeroConf(peerKey, pendingChanID)
 
 	// If this was the last active reservation for this peer, delete the
 	// peer's entry altogether.
@@ -5197,6 +5322,16 @@ func (f *Manager) deleteReservationCtx(peerKey *btcec.PublicKey,
 	}
 }
 
+// releaseZeroConf releases the zero-conf exposure reserved for the given
+// pending channel, if any.
+func (f *Manager) releaseZeroConf(peerKey *btcec.PublicKey,
+	pendingChanID PendingChanID) {
+
+	if f.cfg.ZeroConfRisk != nil {
+		f.cfg.ZeroConfRisk.Release(peerKey, pendingChanID)
+	}
+}
+
 // getReservationCtx returns the reservation context for a particular pending
 // channel ID for a target peer.
 func (f *Manager) getReservationCtx(peerKey *btcec.PublicKey,
@@ -5243,15 +5378,36 @@ func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
 func (f *Manager) defaultForwardingPolicy(
 	bounds channeldb.ChannelStateBounds) *models.ForwardingPolicy {
 
+	defaultPolicy := f.DefaultRoutingPolicy()
+
 	return &models.ForwardingPolicy{
 		MinHTLCOut:    bounds.MinHTLC,
 		MaxHTLC:       bounds.MaxPendingAmount,
-		BaseFee:       f.cfg.DefaultRoutingPolicy.BaseFee,
-		FeeRate:       f.cfg.DefaultRoutingPolicy.FeeRate,
-		TimeLockDelta: f.cfg.DefaultRoutingPolicy.TimeLockDelta,
+		BaseFee:       defaultPolicy.BaseFee,
+		FeeRate:       defaultPolicy.FeeRate,
+		TimeLockDelta: defaultPolicy.TimeLockDelta,
 	}
 }
 
+// DefaultRoutingPolicy returns the routing policy that is used for channels
+// that are opened without an explicit policy.
+func (f *Manager) DefaultRoutingPolicy() models.ForwardingPolicy {
+	f.policyMtx.RLock()
+	defer f.policyMtx.RUnlock()
+
+	return f.cfg.DefaultRoutingPolicy
+}
+
+// SetDefaultRoutingPolicy replaces the routing policy that is used for
+// channels that are opened without an explicit policy. Channels that are
+// already open keep their current policy.
+func (f *Manager) SetDefaultRoutingPolicy(policy models.ForwardingPolicy) {
+	f.policyMtx.Lock()
+	defer f.policyMtx.Unlock()
+
+	f.cfg.DefaultRoutingPolicy = policy
+}
+
 // saveInitialForwardingPolicy saves the forwarding policy for the provided
 // chanPoint in the channelOpeningStateBucket.
 func (f *Manager) saveInitialForwardingPolicy(chanID lnwire.ChannelID,
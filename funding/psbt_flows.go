@@ -0,0 +1,171 @@
+package funding
+
+import (
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+)
+
+// persistPsbtFlow stores the state of the PSBT funding flow of the given
+// reservation, so that it can be aborted cleanly with the information needed
+// to recover its funds if lnd restarts before the channel is stored. Nothing
+// is stored if the reservation was canceled in the meantime.
+func (f *Manager) persistPsbtFlow(resCtx *reservationWithCtx,
+	pendingChanID PendingChanID, intent *chanfunding.PsbtIntent,
+	step channeldb.FundingFlowStep) error {
+
+	witnessScript, fundingOut, err := intent.FundingOutput()
+	if err != nil {
+		return err
+	}
+
+	var (
+		reservation = resCtx.reservation
+		ours        = reservation.OurContribution()
+		theirs      = reservation.TheirContribution()
+	)
+	flow := &channeldb.PendingFundingFlow{
+		PendingChanID:        pendingChanID,
+		Step:                 step,
+		UpdatedAt:            time.Now(),
+		Peer:                 resCtx.peer.IdentityKey(),
+		Capacity:             reservation.Capacity(),
+		FundingPkScript:      fundingOut.PkScript,
+		FundingWitnessScript: witnessScript,
+		LocalKey:             ours.MultiSigKey,
+		RemoteKey:            theirs.MultiSigKey.PubKey,
+	}
+
+	// Taproot channels have no witness script for the funding output.
+	if reservation.IsTaproot() {
+		flow.FundingWitnessScript = nil
+	}
+
+	if step == channeldb.FundingFlowPsbtFinalized {
+		flow.FundingOutpoint = *reservation.FundingOutpoint()
+		flow.FundingTx = reservation.FinalFundingTx()
+	}
+
+	// The reservation mutex is held while writing, so that the state
+	// isn't stored again after the reservation was canceled and its state
+	// deleted.
+	peerIDKey := newSerializedKey(flow.Peer)
+	f.resMtx.Lock()
+	defer f.resMtx.Unlock()
+
+	if _, ok := f.activeReservations[peerIDKey][pendingChanID]; !ok {
+		return nil
+	}
+
+	return f.cfg.ChannelDB.PutPendingFundingFlow(flow)
+}
+
+// deletePsbtFlow removes the stored state of the PSBT funding flow of the
+// given pending channel, if any.
+func (f *Manager) deletePsbtFlow(pendingChanID PendingChanID) {
+	err := f.cfg.ChannelDB.DeletePendingFundingFlow(pendingChanID)
+	if err != nil && !errors.Is(err, channeldb.ErrFundingFlowNotFound) {
+		log.Errorf("Unable to delete PSBT funding flow state of "+
+			"pending_chan_id=%x: %v", pendingChanID[:], err)
+	}
+}
+
+// abortPsbtFlows aborts the PSBT funding flows that were in progress when lnd
+// last went down. The reservations of these flows were in memory only, so
+// they can't be resumed. Their state is kept, marked as aborted, until the
+// user acknowledges it, as funds may have been sent to their funding output.
+// Flows whose channel was stored before the shutdown are simply forgotten.
+func (f *Manager) abortPsbtFlows(channels []*channeldb.OpenChannel) error {
+	flows, err := f.cfg.ChannelDB.FetchPendingFundingFlows()
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[wire.OutPoint]struct{}, len(channels))
+	for _, channel := range channels {
+		stored[channel.FundingOutpoint] = struct{}{}
+	}
+
+	for _, flow := range flows {
+		if flow.Step == channeldb.FundingFlowPsbtFinalized {
+			if _, ok := stored[flow.FundingOutpoint]; ok {
+				f.deletePsbtFlow(flow.PendingChanID)
+				continue
+			}
+		}
+
+		if flow.Aborted {
+			continue
+		}
+
+		flow.Aborted = true
+		err := f.cfg.ChannelDB.PutPendingFundingFlow(flow)
+		if err != nil {
+			return err
+		}
+
+		log.Warnf("Aborted PSBT funding flow pending_chan_id=%x "+
+			"with peer %x at step %v after restart: "+
+			"funding_pk_script=%x, capacity=%v, "+
+			"local_key_family=%d, local_key_index=%d, "+
+			"remote_key=%x", flow.PendingChanID[:],
+			flow.Peer.SerializeCompressed(), flow.Step,
+			flow.FundingPkScript, flow.Capacity,
+			flow.LocalKey.Family, flow.LocalKey.Index,
+			flow.RemoteKey.SerializeCompressed())
+
+		if flow.FundingTx != nil {
+			log.Warnf("Funding transaction %v of aborted PSBT "+
+				"funding flow pending_chan_id=%x must not be "+
+				"published, its funds can only be recovered "+
+				"together with the peer if it was",
+				flow.FundingTx.TxHash(), flow.PendingChanID[:])
+		}
+	}
+
+	return nil
+}
+
+// AbortedPsbtFlows returns the PSBT funding flows that were aborted because
+// lnd restarted while they were in progress. Funds that were sent to the
+// funding output of such a flow can only be recovered together with the
+// peer, using the stored keys and scripts.
+func (f *Manager) AbortedPsbtFlows() ([]*channeldb.PendingFundingFlow,
+	error) {
+
+	flows, err := f.cfg.ChannelDB.FetchPendingFundingFlows()
+	if err != nil {
+		return nil, err
+	}
+
+	aborted := make([]*channeldb.PendingFundingFlow, 0, len(flows))
+	for _, flow := range flows {
+		if flow.Aborted {
+			aborted = append(aborted, flow)
+		}
+	}
+
+	return aborted, nil
+}
+
+// ForgetAbortedPsbtFlow removes the stored state of an aborted PSBT funding
+// flow once the user handled it.
+func (f *Manager) ForgetAbortedPsbtFlow(pendingChanID PendingChanID) error {
+	flows, err := f.AbortedPsbtFlows()
+	if err != nil {
+		return err
+	}
+
+	for _, flow := range flows {
+		if flow.PendingChanID != pendingChanID {
+			continue
+		}
+
+		return f.cfg.ChannelDB.DeletePendingFundingFlow(pendingChanID)
+	}
+
+	return channeldb.ErrFundingFlowNotFound
+}
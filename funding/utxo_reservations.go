@@ -0,0 +1,147 @@
+package funding
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// errUtxoReservationExpired is the error a funding flow is failed with when
+// the lock of the wallet outputs reserved for its funding transaction
+// expired.
+var errUtxoReservationExpired = errors.New("reservation of funding inputs " +
+	"expired")
+
+// errUtxoReservationReleased is the error a funding flow is failed with when
+// the user released the wallet outputs reserved for its funding transaction.
+var errUtxoReservationReleased = errors.New("reservation of funding inputs " +
+	"released by user")
+
+// UtxoReservation is the set of wallet outputs that are locked for the
+// funding transaction of a pending channel.
+type UtxoReservation struct {
+	// PendingChanID is the pending channel ID of the funding flow the
+	// outputs are locked for.
+	PendingChanID PendingChanID
+
+	// Peer is the peer of the funding flow. It is nil if the funding
+	// manager no longer tracks the flow, in which case the outputs are
+	// stranded until the reservation is released or expires.
+	Peer *btcec.PublicKey
+
+	// OutPoints are the locked wallet outputs.
+	OutPoints []wire.OutPoint
+
+	// Expiry is the time at which the lock of the outputs expires.
+	Expiry time.Time
+}
+
+// UtxoReservations returns the wallet outputs that are currently locked for
+// the funding transactions of pending channels, grouped by funding flow and
+// ordered by their expiry.
+func (f *Manager) UtxoReservations() []*UtxoReservation {
+	byChanID := make(map[PendingChanID]*UtxoReservation)
+	for _, utxo := range f.cfg.Wallet.ReservedUtxos() {
+		res, ok := byChanID[utxo.PendingChanID]
+		if !ok {
+			res = &UtxoReservation{
+				PendingChanID: utxo.PendingChanID,
+				Expiry:        utxo.Expiry,
+			}
+			byChanID[utxo.PendingChanID] = res
+		}
+
+		res.OutPoints = append(res.OutPoints, utxo.OutPoint)
+		if utxo.Expiry.Before(res.Expiry) {
+			res.Expiry = utxo.Expiry
+		}
+	}
+
+	f.resMtx.RLock()
+	for _, pendingReservations := range f.activeReservations {
+		for pendingChanID, resCtx := range pendingReservations {
+			if res, ok := byChanID[pendingChanID]; ok {
+				res.Peer = resCtx.peer.IdentityKey()
+			}
+		}
+	}
+	f.resMtx.RUnlock()
+
+	reservations := make([]*UtxoReservation, 0, len(byChanID))
+	for _, res := range byChanID {
+		reservations = append(reservations, res)
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].Expiry.Before(reservations[j].Expiry)
+	})
+
+	return reservations
+}
+
+// ReleaseUtxoReservation releases the wallet outputs locked for the funding
+// transaction of the given pending channel. If the funding flow is still in
+// progress, it is failed.
+func (f *Manager) ReleaseUtxoReservation(pendingChanID PendingChanID) error {
+	for _, res := range f.UtxoReservations() {
+		if res.PendingChanID != pendingChanID {
+			continue
+		}
+
+		return f.releaseUtxoReservation(res, errUtxoReservationReleased)
+	}
+
+	return fmt.Errorf("no reserved funding inputs for pending channel %x",
+		pendingChanID[:])
+}
+
+// releaseUtxoReservation releases the given reservation of wallet outputs.
+// The funding flow it belongs to is failed with the given reason if the
+// funding manager still tracks it, otherwise the wallet reservation is
+// canceled directly.
+func (f *Manager) releaseUtxoReservation(res *UtxoReservation,
+	reason error) error {
+
+	if res.Peer != nil {
+		resCtx, err := f.getReservationCtx(res.Peer, res.PendingChanID)
+		if err == nil {
+			cid := newChanIdentifier(res.PendingChanID)
+			cid.setChanID(lnwire.NewChanIDFromOutPoint(
+				*resCtx.reservation.FundingOutpoint(),
+			))
+
+			f.failFundingFlow(resCtx.peer, cid, reason)
+
+			return nil
+		}
+	}
+
+	return f.cfg.Wallet.CancelReservation(res.PendingChanID)
+}
+
+// pruneExpiredUtxoReservations releases the reservations of wallet outputs
+// whose lock expired, so that a funding flow that was aborted without
+// canceling its reservation can't strand the outputs until the next restart.
+func (f *Manager) pruneExpiredUtxoReservations() {
+	now := time.Now()
+	for _, res := range f.UtxoReservations() {
+		if now.Before(res.Expiry) {
+			continue
+		}
+
+		log.Warnf("Releasing %d funding input(s) of pending_chan_id=%x "+
+			"with expired reservation", len(res.OutPoints),
+			res.PendingChanID[:])
+
+		err := f.releaseUtxoReservation(res, errUtxoReservationExpired)
+		if err != nil {
+			log.Errorf("Unable to release funding inputs of "+
+				"pending_chan_id=%x: %v", res.PendingChanID[:],
+				err)
+		}
+	}
+}
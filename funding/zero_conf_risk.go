@@ -0,0 +1,169 @@
+package funding
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// ErrZeroConfExposure is returned when an inbound zero-conf channel would
+// exceed the exposure limits of the unconfirmed zero-conf channels.
+var ErrZeroConfExposure = errors.New("zero-conf exposure limit exceeded")
+
+// ZeroConfLimits are the exposure limits of the zero-conf channels that were
+// funded by the peer and didn't confirm yet. A value of zero disables a
+// limit.
+type ZeroConfLimits struct {
+	// MaxUnconfirmed is the maximum total capacity of the unconfirmed
+	// inbound zero-conf channels.
+	MaxUnconfirmed btcutil.Amount
+
+	// MaxPeerUnconfirmed is the maximum total capacity of the unconfirmed
+	// inbound zero-conf channels with a single peer.
+	MaxPeerUnconfirmed btcutil.Amount
+}
+
+// ZeroConfExposure is the capacity of the unconfirmed inbound zero-conf
+// channels.
+type ZeroConfExposure struct {
+	// Total is the total capacity of the channels.
+	Total btcutil.Amount
+
+	// PerPeer is the capacity of the channels by the compressed public
+	// key of their peer.
+	PerPeer map[[33]byte]btcutil.Amount
+}
+
+// zeroConfReservation identifies an inbound zero-conf channel whose funding
+// flow is in progress.
+type zeroConfReservation struct {
+	peer          [33]byte
+	pendingChanID PendingChanID
+}
+
+// ZeroConfRisk enforces the exposure limits of the zero-conf channels that
+// were funded by the peer. Until the funding transaction of such a channel
+// confirms, the peer can double spend it, so the value we take on through
+// these channels is capped.
+type ZeroConfRisk struct {
+	limits ZeroConfLimits
+
+	// fetchChannels returns all pending and open channels.
+	fetchChannels func() ([]*channeldb.OpenChannel, error)
+
+	mu sync.Mutex
+
+	// reserved is the capacity of the inbound zero-conf channels whose
+	// funding flow is in progress, which aren't stored yet.
+	reserved map[zeroConfReservation]btcutil.Amount
+}
+
+// NewZeroConfRisk creates a ZeroConfRisk that enforces the given limits on
+// the channels returned by fetchChannels.
+func NewZeroConfRisk(limits ZeroConfLimits,
+	fetchChannels func() ([]*channeldb.OpenChannel, error)) *ZeroConfRisk {
+
+	return &ZeroConfRisk{
+		limits:        limits,
+		fetchChannels: fetchChannels,
+		reserved:      make(map[zeroConfReservation]btcutil.Amount),
+	}
+}
+
+// Exposure returns the capacity of the unconfirmed inbound zero-conf
+// channels, including the ones whose funding flow is in progress.
+func (z *ZeroConfRisk) Exposure() (*ZeroConfExposure, error) {
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	return z.exposure()
+}
+
+// exposure returns the current exposure. The caller must hold the mutex.
+func (z *ZeroConfRisk) exposure() (*ZeroConfExposure, error) {
+	channels, err := z.fetchChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	exposure := &ZeroConfExposure{
+		PerPeer: make(map[[33]byte]btcutil.Amount),
+	}
+	add := func(peer [33]byte, amt btcutil.Amount) {
+		exposure.Total += amt
+		exposure.PerPeer[peer] += amt
+	}
+
+	for _, channel := range channels {
+		if !channel.IsZeroConf() || channel.IsInitiator ||
+			channel.ZeroConfConfirmed() {
+
+			continue
+		}
+
+		var peer [33]byte
+		copy(peer[:], channel.IdentityPub.SerializeCompressed())
+		add(peer, channel.Capacity)
+	}
+
+	for res, amt := range z.reserved {
+		add(res.peer, amt)
+	}
+
+	return exposure, nil
+}
+
+// Reserve adds an inbound zero-conf channel of the given capacity whose
+// funding flow is starting, unless it would exceed the exposure limits. The
+// reservation must be released once the funding flow completed or failed.
+func (z *ZeroConfRisk) Reserve(peer *btcec.PublicKey,
+	pendingChanID PendingChanID, capacity btcutil.Amount) error {
+
+	z.mu.Lock()
+	defer z.mu.Unlock()
+
+	exposure, err := z.exposure()
+	if err != nil {
+		return err
+	}
+
+	res := zeroConfReservation{pendingChanID: pendingChanID}
+	copy(res.peer[:], peer.SerializeCompressed())
+
+	total := exposure.Total + capacity
+	if z.limits.MaxUnconfirmed != 0 && total > z.limits.MaxUnconfirmed {
+		return fmt.Errorf("%w: total unconfirmed capacity %v above "+
+			"maximum %v", ErrZeroConfExposure, total,
+			z.limits.MaxUnconfirmed)
+	}
+
+	peerTotal := exposure.PerPeer[res.peer] + capacity
+	if z.limits.MaxPeerUnconfirmed != 0 &&
+		peerTotal > z.limits.MaxPeerUnconfirmed {
+
+		return fmt.Errorf("%w: unconfirmed capacity %v with peer "+
+			"above maximum %v", ErrZeroConfExposure, peerTotal,
+			z.limits.MaxPeerUnconfirmed)
+	}
+
+	z.reserved[res] = capacity
+
+	return nil
+}
+
+// Release removes the reservation of the given inbound zero-conf channel, if
+// there is one.
+func (z *ZeroConfRisk) Release(peer *btcec.PublicKey,
+	pendingChanID PendingChanID) {
+
+	res := zeroConfReservation{pendingChanID: pendingChanID}
+	copy(res.peer[:], peer.SerializeCompressed())
+
+	z.mu.Lock()
+	delete(z.reserved, res)
+	z.mu.Unlock()
+}
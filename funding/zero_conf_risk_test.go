@@ -0,0 +1,69 @@
+package funding
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestZeroConfRisk asserts that the capacity of the unconfirmed inbound
+// zero-conf channels, stored or in progress, is kept within the total and
+// per-peer limits.
+func TestZeroConfRisk(t *testing.T) {
+	t.Parallel()
+
+	priv1, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	priv2, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	peer1, peer2 := priv1.PubKey(), priv2.PubKey()
+
+	// Only the zero-conf channel funded by the peer counts.
+	channels := []*channeldb.OpenChannel{{
+		ChanType:    channeldb.ZeroConfBit,
+		IdentityPub: peer1,
+		Capacity:    100_000,
+	}, {
+		ChanType:    channeldb.ZeroConfBit,
+		IsInitiator: true,
+		IdentityPub: peer1,
+		Capacity:    100_000,
+	}, {
+		IdentityPub: peer2,
+		Capacity:    100_000,
+	}}
+
+	risk := NewZeroConfRisk(ZeroConfLimits{
+		MaxUnconfirmed:     300_000,
+		MaxPeerUnconfirmed: 150_000,
+	}, func() ([]*channeldb.OpenChannel, error) {
+		return channels, nil
+	})
+
+	exposure, err := risk.Exposure()
+	require.NoError(t, err)
+	require.EqualValues(t, 100_000, exposure.Total)
+
+	// The first peer is at its limit, the second one isn't.
+	err = risk.Reserve(peer1, PendingChanID{1}, 60_000)
+	require.ErrorIs(t, err, ErrZeroConfExposure)
+
+	require.NoError(t, risk.Reserve(peer2, PendingChanID{1}, 150_000))
+
+	// The reservation of the second peer counts towards the total limit.
+	err = risk.Reserve(peer1, PendingChanID{2}, 50_001)
+	require.ErrorIs(t, err, ErrZeroConfExposure)
+
+	require.NoError(t, risk.Reserve(peer1, PendingChanID{2}, 50_000))
+
+	exposure, err = risk.Exposure()
+	require.NoError(t, err)
+	require.EqualValues(t, 300_000, exposure.Total)
+
+	// Once released, the capacity is available again.
+	risk.Release(peer2, PendingChanID{1})
+	require.NoError(t, risk.Reserve(peer2, PendingChanID{3}, 100_000))
+}
@@ -206,6 +206,11 @@ replace github.com/gogo/protobuf => github.com/gogo/protobuf v1.3.2
 // allows us to specify that as an option.
 replace google.golang.org/protobuf => github.com/lightninglabs/protobuf-go-hex-display v1.30.0-hex-display
 
+// TODO: Remove this replace and bump the sqldb require once the sqldb module
+// with the wtclient and invoice migrations 000005 to 000010 is tagged. Until
+// then no published sqldb version contains the schema that lnd expects.
+replace github.com/lightningnetwork/lnd/sqldb => ./sqldb
+
 // If you change this please also update .github/pull_request_template.md,
 // docs/INSTALL.md and GO_IMAGE in lnrpc/gen_protos_docker.sh.
 go 1.22.6
@@ -0,0 +1,50 @@
+package lnd
+
+import (
+	"context"
+	"sort"
+
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// ListGossipPeerScores returns the gossip reputation of the peers we received
+// gossip from, ordered from the lowest to the highest score. Each entry holds
+// the rate of gossip messages the peer is currently allowed to send, and the
+// number of its messages that were accepted, stale, invalid or throttled.
+func (r *rpcServer) ListGossipPeerScores(_ context.Context,
+	_ *lnrpc.ListGossipPeerScoresRequest) (
+	*lnrpc.ListGossipPeerScoresResponse, error) {
+
+	reps := r.server.authGossiper.PeerReputations()
+
+	return &lnrpc.ListGossipPeerScoresResponse{
+		Scores: marshallGossipPeerScores(reps),
+	}, nil
+}
+
+// marshallGossipPeerScores converts the gossip reputations of our peers to
+// their RPC representation, ordered from the lowest to the highest score.
+func marshallGossipPeerScores(
+	reps []discovery.PeerReputation) []*lnrpc.GossipPeerScore {
+
+	sort.Slice(reps, func(i, j int) bool {
+		return reps[i].Score < reps[j].Score
+	})
+
+	scores := make([]*lnrpc.GossipPeerScore, 0, len(reps))
+	for _, rep := range reps {
+		scores = append(scores, &lnrpc.GossipPeerScore{
+			PubKey:     rep.Peer[:],
+			Score:      rep.Score,
+			MsgRate:    rep.MsgRate,
+			Accepted:   rep.Accepted,
+			Stale:      rep.Stale,
+			Invalid:    rep.Invalid,
+			Throttled:  rep.Throttled,
+			LastUpdate: rep.LastUpdate.Unix(),
+		})
+	}
+
+	return scores
+}
@@ -0,0 +1,41 @@
+package lnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshallGossipPeerScores tests that the gossip reputations of our peers
+// are returned from the lowest to the highest score.
+func TestMarshallGossipPeerScores(t *testing.T) {
+	t.Parallel()
+
+	reps := []discovery.PeerReputation{{
+		Peer:       [33]byte{1},
+		Score:      20,
+		Accepted:   10,
+		LastUpdate: time.Unix(1_700_000_000, 0),
+	}, {
+		Peer:      [33]byte{2},
+		Score:     -40,
+		MsgRate:   0.5,
+		Invalid:   3,
+		Throttled: 7,
+	}}
+
+	scores := marshallGossipPeerScores(reps)
+	require.Len(t, scores, 2)
+
+	require.Equal(t, byte(2), scores[0].PubKey[0])
+	require.EqualValues(t, -40, scores[0].Score)
+	require.EqualValues(t, 0.5, scores[0].MsgRate)
+	require.EqualValues(t, 3, scores[0].Invalid)
+	require.EqualValues(t, 7, scores[0].Throttled)
+
+	require.Equal(t, byte(1), scores[1].PubKey[0])
+	require.EqualValues(t, 10, scores[1].Accepted)
+	require.EqualValues(t, 1_700_000_000, scores[1].LastUpdate)
+}
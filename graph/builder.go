@@ -148,6 +148,10 @@ type Builder struct {
 	// existing client.
 	ntfnClientUpdates chan *topologyClientUpdate
 
+	// topologyJournal numbers the topology changes and keeps the latest
+	// ones for clients that resume their subscription.
+	topologyJournal *topologyJournal
+
 	// channelEdgeMtx is a mutex we use to make sure we process only one
 	// ChannelEdgePolicy at a time for a given channelID, to ensure
 	// consistency between the various database accesses.
@@ -171,11 +175,19 @@ var _ ChannelGraphSource = (*Builder)(nil)
 
 // NewBuilder constructs a new Builder.
 func NewBuilder(cfg *Config) (*Builder, error) {
+	// We start the sequence numbers of the topology changes at the current
+	// time, so they keep increasing across restarts and a client can't
+	// resume from a sequence number of an earlier run by accident.
+	journal := newTopologyJournal(
+		uint64(time.Now().UnixNano()), DefaultTopologyJournalSize,
+	)
+
 	return &Builder{
 		cfg:               cfg,
 		networkUpdates:    make(chan *routingMsg),
 		topologyClients:   &lnutils.SyncMap[uint64, *topologyClient]{},
 		ntfnClientUpdates: make(chan *topologyClientUpdate),
+		topologyJournal:   journal,
 		channelEdgeMtx:    multimutex.NewMutex[uint64](),
 		statTicker:        ticker.New(defaultStatInterval),
 		stats:             new(routerStats),
@@ -897,6 +909,7 @@ func (b *Builder) networkHandler() {
 				ntfnChan: ntfnUpdate.ntfnChan,
 				exit:     make(chan struct{}),
 			})
+			close(ntfnUpdate.registered)
 
 		// The graph prune ticker has ticked, so we'll examine the
 		// state of the known graph to filter out any zombie channels
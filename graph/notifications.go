@@ -30,6 +30,17 @@ type TopologyClient struct {
 	// wishes to cancel their notification intent. Doing so allows the
 	// ChannelRouter to free up resources.
 	Cancel func()
+
+	// err is the reason a resumed subscription ended. It is set before
+	// TopologyChanges is closed.
+	err error
+}
+
+// Err returns the reason the subscription ended, or nil if it was canceled or
+// the router is shutting down. It must only be called once TopologyChanges
+// was closed.
+func (t *TopologyClient) Err() error {
+	return t.err
 }
 
 // topologyClientUpdate is a message sent to the channel router to either
@@ -48,6 +59,9 @@ type topologyClientUpdate struct {
 	// ntfnChan is a *send-only* channel in which notifications should be
 	// sent over from router -> client.
 	ntfnChan chan<- *TopologyChange
+
+	// registered is closed once a new client was registered.
+	registered chan struct{}
 }
 
 // SubscribeTopology returns a new topology client which can be used by the
@@ -70,17 +84,27 @@ func (b *Builder) SubscribeTopology() (*TopologyClient, error) {
 		clientID)
 
 	ntfnChan := make(chan *TopologyChange, 10)
+	registered := make(chan struct{})
 
 	select {
 	case b.ntfnClientUpdates <- &topologyClientUpdate{
-		cancel:   false,
-		clientID: clientID,
-		ntfnChan: ntfnChan,
+		cancel:     false,
+		clientID:   clientID,
+		ntfnChan:   ntfnChan,
+		registered: registered,
 	}:
 	case <-b.quit:
 		return nil, errors.New("ChannelRouter shutting down")
 	}
 
+	// We wait until the client is registered, so it receives all changes
+	// that are sent after this method returns.
+	select {
+	case <-registered:
+	case <-b.quit:
+		return nil, errors.New("ChannelRouter shutting down")
+	}
+
 	return &TopologyClient{
 		TopologyChanges: ntfnChan,
 		Cancel: func() {
@@ -96,6 +120,138 @@ func (b *Builder) SubscribeTopology() (*TopologyClient, error) {
 	}, nil
 }
 
+// SubscribeTopologyFrom returns a new topology client like SubscribeTopology,
+// which first receives all changes with a sequence number greater than the
+// given one. All changes are delivered in the order of their sequence
+// numbers without a gap. If the changes after the sequence number aren't
+// known anymore, ErrTopologySeqUnavailable is returned. The subscription ends
+// with the same error if the client falls behind further than the changes
+// that are kept in memory.
+func (b *Builder) SubscribeTopologyFrom(seqNum uint64) (*TopologyClient,
+	error) {
+
+	// We register the underlying client before reading the journal, so
+	// no change can slip through between the two. Changes that are in
+	// both are only sent once.
+	client, err := b.SubscribeTopology()
+	if err != nil {
+		return nil, err
+	}
+
+	replay, err := b.topologyJournal.since(seqNum)
+	if err != nil {
+		client.Cancel()
+		return nil, err
+	}
+
+	var (
+		ntfnChan = make(chan *TopologyChange, 10)
+		quit     = make(chan struct{})
+		once     sync.Once
+	)
+
+	resumed := &TopologyClient{
+		TopologyChanges: ntfnChan,
+		Cancel: func() {
+			once.Do(func() {
+				close(quit)
+				client.Cancel()
+			})
+		},
+	}
+
+	go b.forwardTopologyChanges(
+		client, resumed, ntfnChan, quit, seqNum+1, replay,
+	)
+
+	return resumed, nil
+}
+
+// forwardTopologyChanges sends the replayed changes and then the changes of
+// the client to the resumed client in the order of their sequence numbers,
+// starting at the given one.
+//
+// NOTE: This MUST be run as a goroutine.
+func (b *Builder) forwardTopologyChanges(client, resumed *TopologyClient,
+	ntfnChan chan<- *TopologyChange, quit <-chan struct{}, next uint64,
+	replay []*TopologyChange) {
+
+	defer close(ntfnChan)
+
+	send := func(change *TopologyChange) bool {
+		select {
+		case ntfnChan <- change:
+			next = change.SeqNum + 1
+			return true
+
+		case <-quit:
+			return false
+
+		case <-b.quit:
+			return false
+		}
+	}
+
+	for _, change := range replay {
+		if !send(change) {
+			return
+		}
+	}
+
+	for {
+		var change *TopologyChange
+		select {
+		case c, ok := <-client.TopologyChanges:
+			if !ok {
+				return
+			}
+			change = c
+
+		case <-quit:
+			return
+		}
+
+		// Changes are dispatched concurrently, so we may receive them
+		// out of order. Changes we already sent are skipped, and
+		// missing ones are taken from the journal.
+		if change.SeqNum < next {
+			continue
+		}
+
+		if change.SeqNum > next {
+			missing, err := b.topologyJournal.since(next - 1)
+			if err != nil {
+				log.Warnf("Topology client fell behind: %v",
+					err)
+
+				resumed.err = err
+				client.Cancel()
+
+				return
+			}
+
+			for _, m := range missing {
+				if m.SeqNum >= change.SeqNum {
+					break
+				}
+				if !send(m) {
+					return
+				}
+			}
+		}
+
+		if !send(change) {
+			return
+		}
+	}
+}
+
+// LastTopologySeqNum returns the sequence number of the latest topology
+// change.
+func (b *Builder) LastTopologySeqNum() uint64 {
+	return b.topologyJournal.lastSeqNum()
+}
+
 // topologyClient is a data-structure use by the channel router to couple the
 // client's notification channel along with a special "exit" channel that can
 // be used to cancel all lingering goroutines blocked on a send to the
@@ -116,6 +272,10 @@ type topologyClient struct {
 // notifyTopologyChange notifies all registered clients of a new change in
 // graph topology in a non-blocking.
 func (b *Builder) notifyTopologyChange(topologyDiff *TopologyChange) {
+	// We number the change before sending it off, so all clients see the
+	// same sequence number.
+	b.topologyJournal.add(topologyDiff)
+
 	// notifyClient is a helper closure that will send topology updates to
 	// the given client.
 	notifyClient := func(clientID uint64, client *topologyClient) bool {
@@ -162,6 +322,11 @@ func (b *Builder) notifyTopologyChange(topologyDiff *TopologyChange) {
 // Topology changes will be dispatched in real-time as the ChannelGraph
 // validates and process modifications to the authenticated channel graph.
 type TopologyChange struct {
+	// SeqNum is the sequence number of the change. Each change has a
+	// sequence number that is one greater than the one of the previous
+	// change.
+	SeqNum uint64
+
 	// NodeUpdates is a slice of nodes which are either new to the channel
 	// graph, or have had their attributes updated in an authenticated
 	// manner.
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"errors"
+	"sync"
+)
+
+const (
+	// DefaultTopologyJournalSize is the default number of topology changes
+	// that are kept in memory for clients that resume a subscription.
+	DefaultTopologyJournalSize = 1000
+)
+
+// ErrTopologySeqUnavailable is returned if a topology subscription can't be
+// resumed from the requested sequence number, because the changes after it
+// are no longer known. The client needs to start over from a snapshot.
+var ErrTopologySeqUnavailable = errors.New("topology changes after the " +
+	"requested sequence number are no longer available")
+
+// topologyJournal assigns sequence numbers to topology changes and keeps the
+// most recent ones, so a subscription can be resumed without a gap.
+type topologyJournal struct {
+	mu sync.Mutex
+
+	// seqNum is the sequence number of the latest topology change.
+	seqNum uint64
+
+	// changes holds the latest topology changes ordered by their sequence
+	// number.
+	changes []*TopologyChange
+
+	// size is the maximum number of changes that are kept.
+	size int
+}
+
+// newTopologyJournal creates a journal that keeps the given number of changes.
+// The sequence numbers continue after the given one.
+func newTopologyJournal(seqNum uint64, size int) *topologyJournal {
+	return &topologyJournal{
+		seqNum: seqNum,
+		size:   size,
+	}
+}
+
+// add assigns the next sequence number to the change and stores it.
+func (j *topologyJournal) add(change *TopologyChange) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.seqNum++
+	change.SeqNum = j.seqNum
+
+	j.changes = append(j.changes, change)
+	if len(j.changes) > j.size {
+		j.changes = j.changes[len(j.changes)-j.size:]
+	}
+}
+
+// lastSeqNum returns the sequence number of the latest topology change.
+func (j *topologyJournal) lastSeqNum() uint64 {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.seqNum
+}
+
+// since returns all changes with a sequence number greater than the given
+// one. ErrTopologySeqUnavailable is returned if some of them were already
+// evicted, or if the sequence number wasn't assigned yet.
+func (j *topologyJournal) since(seqNum uint64) ([]*TopologyChange, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	switch {
+	case seqNum > j.seqNum:
+		return nil, ErrTopologySeqUnavailable
+
+	case seqNum == j.seqNum:
+		return nil, nil
+	}
+
+	// The journal needs to hold the change right after the requested one.
+	if len(j.changes) == 0 || j.changes[0].SeqNum > seqNum+1 {
+		return nil, ErrTopologySeqUnavailable
+	}
+
+	start := int(seqNum + 1 - j.changes[0].SeqNum)
+
+	return append([]*TopologyChange(nil), j.changes[start:]...), nil
+}
@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestTopologyJournal tests that the topology journal numbers the changes and
+// returns the ones after a sequence number as long as they are kept.
+func TestTopologyJournal(t *testing.T) {
+	t.Parallel()
+
+	const start = 100
+	journal := newTopologyJournal(start, 3)
+
+	// An empty journal can only be resumed from its current sequence
+	// number.
+	changes, err := journal.since(start)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	_, err = journal.since(start - 1)
+	require.ErrorIs(t, err, ErrTopologySeqUnavailable)
+
+	for i := 0; i < 5; i++ {
+		change := &TopologyChange{}
+		journal.add(change)
+		require.EqualValues(t, start+i+1, change.SeqNum)
+	}
+	require.EqualValues(t, start+5, journal.lastSeqNum())
+
+	seqNums := func(changes []*TopologyChange) []uint64 {
+		nums := make([]uint64, 0, len(changes))
+		for _, change := range changes {
+			nums = append(nums, change.SeqNum)
+		}
+
+		return nums
+	}
+
+	// Only the last three changes are kept.
+	changes, err = journal.since(start + 2)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{start + 3, start + 4, start + 5},
+		seqNums(changes))
+
+	changes, err = journal.since(start + 4)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{start + 5}, seqNums(changes))
+
+	changes, err = journal.since(start + 5)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	// The changes after an evicted one can't be replayed without a gap.
+	_, err = journal.since(start + 1)
+	require.ErrorIs(t, err, ErrTopologySeqUnavailable)
+
+	// Neither can the changes after a sequence number from the future.
+	_, err = journal.since(start + 6)
+	require.ErrorIs(t, err, ErrTopologySeqUnavailable)
+}
@@ -126,6 +126,26 @@ func (v *ValidationBarrier) InitJobDependencies(job interface{}) {
 			v.nodeAnnDependencies[route.Vertex(msg.NodeID1)] = signals
 			v.nodeAnnDependencies[route.Vertex(msg.NodeID2)] = signals
 		}
+
+	// Taproot channel announcements are a dependency of the channel
+	// updates of the same channel and of the announcements of its nodes.
+	case *lnwire.ChannelAnnouncement2:
+		scid := msg.SCID()
+		if _, ok := v.chanAnnFinSignal[scid]; !ok {
+			signals := &validationSignals{
+				allow: make(chan struct{}),
+				deny:  make(chan struct{}),
+			}
+
+			v.chanAnnFinSignal[scid] = signals
+			v.chanEdgeDependencies[scid] = signals
+
+			node1 := route.Vertex(msg.NodeID1.Val)
+			node2 := route.Vertex(msg.NodeID2.Val)
+			v.nodeAnnDependencies[node1] = signals
+			v.nodeAnnDependencies[node2] = signals
+		}
+
 	case *models.ChannelEdgeInfo:
 
 		shortID := lnwire.NewShortChanIDFromInt(msg.ChannelID)
@@ -148,9 +168,13 @@ func (v *ValidationBarrier) InitJobDependencies(job interface{}) {
 		return
 	case *lnwire.ChannelUpdate1:
 		return
+	case *lnwire.ChannelUpdate2:
+		return
 	case *lnwire.NodeAnnouncement:
 		// TODO(roasbeef): node ann needs to wait on existing channel updates
 		return
+	case *lnwire.NodeAnnouncement2:
+		return
 	case *channeldb.LightningNode:
 		return
 	case *lnwire.AnnounceSignatures1:
@@ -208,18 +232,31 @@ func (v *ValidationBarrier) WaitForDependants(job interface{}) error {
 		jobDesc = fmt.Sprintf("job=lnwire.ChannelUpdate, scid=%v",
 			msg.ShortChannelID.ToUint64())
 
+	case *lnwire.ChannelUpdate2:
+		signals, ok = v.chanEdgeDependencies[msg.SCID()]
+
+		jobDesc = fmt.Sprintf("job=lnwire.ChannelUpdate2, scid=%v",
+			msg.SCID().ToUint64())
+
 	case *lnwire.NodeAnnouncement:
 		vertex := route.Vertex(msg.NodeID)
 		signals, ok = v.nodeAnnDependencies[vertex]
 		jobDesc = fmt.Sprintf("job=lnwire.NodeAnnouncement, pub=%s",
 			vertex)
 
+	case *lnwire.NodeAnnouncement2:
+		vertex := route.Vertex(msg.NodeID.Val)
+		signals, ok = v.nodeAnnDependencies[vertex]
+		jobDesc = fmt.Sprintf("job=lnwire.NodeAnnouncement2, pub=%s",
+			vertex)
+
 	// Other types of jobs can be executed immediately, so we'll just
 	// return directly.
 	case *lnwire.AnnounceSignatures1:
 		// TODO(roasbeef): need to wait on chan ann?
 	case *models.ChannelEdgeInfo:
 	case *lnwire.ChannelAnnouncement1:
+	case *lnwire.ChannelAnnouncement2:
 	}
 
 	// Release the lock once the above read is finished.
@@ -288,6 +325,20 @@ func (v *ValidationBarrier) SignalDependants(job interface{}, allow bool) {
 
 		delete(v.chanEdgeDependencies, msg.ShortChannelID)
 
+	case *lnwire.ChannelAnnouncement2:
+		scid := msg.SCID()
+		finSignals, ok := v.chanAnnFinSignal[scid]
+		if ok {
+			if allow {
+				close(finSignals.allow)
+			} else {
+				close(finSignals.deny)
+			}
+			delete(v.chanAnnFinSignal, scid)
+		}
+
+		delete(v.chanEdgeDependencies, scid)
+
 	// For all other job types, we'll delete the tracking entries from the
 	// map, as if we reach this point, then all dependants have already
 	// finished executing and we can proceed.
@@ -295,8 +346,12 @@ func (v *ValidationBarrier) SignalDependants(job interface{}, allow bool) {
 		delete(v.nodeAnnDependencies, route.Vertex(msg.PubKeyBytes))
 	case *lnwire.NodeAnnouncement:
 		delete(v.nodeAnnDependencies, route.Vertex(msg.NodeID))
+	case *lnwire.NodeAnnouncement2:
+		delete(v.nodeAnnDependencies, route.Vertex(msg.NodeID.Val))
 	case *lnwire.ChannelUpdate1:
 		delete(v.chanEdgeDependencies, msg.ShortChannelID)
+	case *lnwire.ChannelUpdate2:
+		delete(v.chanEdgeDependencies, msg.SCID())
 	case *models.ChannelEdgePolicy:
 		shortID := lnwire.NewShortChanIDFromInt(msg.ChannelID)
 		delete(v.chanEdgeDependencies, shortID)
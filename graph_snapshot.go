@@ -0,0 +1,137 @@
+package lnd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/discovery"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+const (
+	// graphSnapshotFetchTimeout is the time after which we give up on
+	// fetching a graph snapshot from a URL, including the time to read its
+	// body. It is generous as snapshots of the mainnet graph are tens of
+	// megabytes and may be fetched over Tor.
+	graphSnapshotFetchTimeout = 10 * time.Minute
+
+	// graphSnapshotHeaderTimeout is the time we'll wait for the server
+	// of a graph snapshot to respond once our request was sent.
+	graphSnapshotHeaderTimeout = time.Minute
+)
+
+// openGraphSnapshot opens the graph snapshot at the given source, which is
+// either a http(s) URL or a file path. URLs are fetched through the
+// configured network, such that they're fetched over Tor if it is enabled.
+func (s *server) openGraphSnapshot(source string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(source, "http://") &&
+		!strings.HasPrefix(source, "https://") {
+
+		return os.Open(lncfg.CleanAndExpandPath(source))
+	}
+
+	client := &http.Client{
+		Timeout: graphSnapshotFetchTimeout,
+		Transport: &http.Transport{
+			ResponseHeaderTimeout: graphSnapshotHeaderTimeout,
+			DialContext: func(_ context.Context, network,
+				addr string) (net.Conn, error) {
+
+				return s.cfg.net.Dial(
+					network, addr, s.cfg.ConnectionTimeout,
+				)
+			},
+		},
+	}
+
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+
+		return nil, fmt.Errorf("unable to fetch graph snapshot: %v",
+			resp.Status)
+	}
+
+	return resp.Body, nil
+}
+
+// bootstrapGraphSnapshot bootstraps our channel graph from the configured
+// graph snapshot if the graph has no channels yet. The messages of the
+// snapshot are validated like any other gossip. A failure is logged rather
+// than returned, as we can still sync the graph through gossip.
+//
+// NOTE: The gossiper MUST be started before calling this method.
+func (s *server) bootstrapGraphSnapshot() {
+	source := s.cfg.Gossip.SnapshotSource
+
+	highestChanID, err := s.graphDB.HighestChanID()
+	if err != nil {
+		srvrLog.Errorf("Unable to check channel graph before "+
+			"bootstrapping from graph snapshot: %v", err)
+		return
+	}
+	if highestChanID != 0 {
+		srvrLog.Debugf("Channel graph isn't empty, skipping graph " +
+			"snapshot bootstrap")
+		return
+	}
+
+	srvrLog.Infof("Bootstrapping channel graph from snapshot %v", source)
+
+	r, err := s.openGraphSnapshot(source)
+	if err != nil {
+		srvrLog.Errorf("Unable to open graph snapshot %v: %v", source,
+			err)
+		return
+	}
+	defer r.Close()
+
+	snap, err := discovery.ReadGraphSnapshot(
+		r, *s.cfg.ActiveNetParams.GenesisHash,
+		s.cfg.Gossip.SnapshotSigners,
+	)
+	if err != nil {
+		srvrLog.Errorf("Unable to read graph snapshot %v: %v", source,
+			err)
+		return
+	}
+
+	accepted, err := s.authGossiper.ImportGraphSnapshot(snap)
+	if err != nil {
+		srvrLog.Errorf("Unable to import graph snapshot %v: %v", source,
+			err)
+		return
+	}
+
+	srvrLog.Infof("Bootstrapped channel graph from snapshot of %v with "+
+		"%d of %d messages", snap.Timestamp, accepted,
+		len(snap.Messages))
+}
+
+// ExportGraphSnapshot returns a snapshot of our public channel graph, signed
+// with our node key, which other nodes can bootstrap their graph from by
+// trusting our node as a snapshot signer.
+func (r *rpcServer) ExportGraphSnapshot(_ context.Context,
+	_ *lnrpc.ExportGraphSnapshotRequest) (
+	*lnrpc.ExportGraphSnapshotResponse, error) {
+
+	var b bytes.Buffer
+	if err := r.server.authGossiper.ExportGraphSnapshot(&b); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ExportGraphSnapshotResponse{
+		Snapshot: b.Bytes(),
+	}, nil
+}
@@ -0,0 +1,88 @@
+package lnd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// subscribeChannelGraph streams the numbered changes of the channel graph, so
+// a client can resume the subscription after a reconnect without missing a
+// change. A client that has no state yet requests a snapshot of the graph
+// first. Changes that happen while the snapshot is taken may be part of the
+// snapshot and also be sent as update afterwards, which is harmless as
+// updates describe the full state of a node or channel.
+//
+// If the changes after ResumeFrom are no longer known, for example because
+// lnd restarted in the meantime, graph.ErrTopologySeqUnavailable is returned
+// and the client needs to start over with a snapshot.
+func (r *rpcServer) subscribeChannelGraph(ctx context.Context,
+	req *lnrpc.GraphTopologySubscription,
+	send func(*lnrpc.GraphTopologyUpdate) error) error {
+
+	if req.Snapshot && req.ResumeFrom != 0 {
+		return errors.New("a subscription either starts with a " +
+			"snapshot or is resumed, but not both")
+	}
+
+	// Without a sequence number to resume from, the subscription starts
+	// with the changes after the latest one.
+	seqNum := req.ResumeFrom
+	if seqNum == 0 {
+		seqNum = r.server.graphBuilder.LastTopologySeqNum()
+	}
+
+	client, err := r.server.graphBuilder.SubscribeTopologyFrom(seqNum)
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	// We take the snapshot after subscribing, so every change that isn't
+	// part of it is sent afterwards. We bypass the graph cache of
+	// DescribeGraph, as it may hold an older state.
+	if req.Snapshot {
+		snapshot, err := describeGraph(r.server.graphDB, false)
+		if err != nil {
+			return err
+		}
+
+		err = send(&lnrpc.GraphTopologyUpdate{
+			SeqNum:   seqNum,
+			Snapshot: snapshot,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case topChange, ok := <-client.TopologyChanges:
+			if !ok {
+				if err := client.Err(); err != nil {
+					return err
+				}
+
+				return errors.New("server shutting down")
+			}
+
+			update := marshallTopologyChange(topChange)
+			update.SeqNum = topChange.SeqNum
+
+			if err := send(update); err != nil {
+				return err
+			}
+
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return ctx.Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package lnd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSubscribeChannelGraphSnapshotAndResume tests that a graph subscription
+// can't both start with a snapshot and be resumed.
+func TestSubscribeChannelGraphSnapshotAndResume(t *testing.T) {
+	t.Parallel()
+
+	r := &rpcServer{}
+	err := r.subscribeChannelGraph(
+		context.Background(), &lnrpc.GraphTopologySubscription{
+			Snapshot:   true,
+			ResumeFrom: 1,
+		}, func(*lnrpc.GraphTopologyUpdate) error {
+			return nil
+		},
+	)
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package htlcswitch
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"sync"
 
@@ -73,6 +74,10 @@ type CircuitLookup interface {
 	// LookupOpenCircuit queries the circuit map for a circuit identified
 	// by its outgoing circuit key.
 	LookupOpenCircuit(outKey CircuitKey) *PaymentCircuit
+
+	// OpenKeystones returns the keystones of all circuits that are
+	// currently open, without loading the circuits themselves.
+	OpenKeystones() []Keystone
 }
 
 // CircuitFwdActions represents the forwarding decision made by the circuit
@@ -170,19 +175,39 @@ var (
 // determine where to forward returning HTLC update messages. Circuits are
 // always identifiable by their incoming CircuitKey, in addition to their
 // outgoing CircuitKey if the circuit is fully-opened.
+//
+// Only the keystones are loaded on startup. The circuits of an incoming channel
+// are loaded from disk the first time they are accessed, which usually happens
+// when the links of the channel and its outgoing channels start. This keeps
+// the startup time independent of the number of persisted circuits, whose
+// decoding requires deriving a shared secret for every forwarded htlc.
 type circuitMap struct {
 	cfg *CircuitMapConfig
 
 	mtx sync.RWMutex
 
-	// pending is an in-memory mapping of all half payment circuits, and is
-	// kept in sync with the on-disk contents of the circuit map.
+	// pending is an in-memory mapping of all half payment circuits of the
+	// loaded channels, and is kept in sync with the on-disk contents of
+	// the circuit map.
 	pending map[CircuitKey]*PaymentCircuit
 
-	// opened is an in-memory mapping of all full payment circuits, which
-	// is also synchronized with the persistent state of the circuit map.
+	// opened is an in-memory mapping of all full payment circuits of the
+	// loaded channels, which is also synchronized with the persistent
+	// state of the circuit map.
 	opened map[CircuitKey]*PaymentCircuit
 
+	// keystones maps the outgoing circuit key of every persisted keystone
+	// to its incoming circuit key, regardless of whether the circuit has
+	// been loaded.
+	keystones map[CircuitKey]CircuitKey
+
+	// inKeystones is the reverse index of keystones.
+	inKeystones map[CircuitKey]CircuitKey
+
+	// loaded is the set of incoming channels whose circuits have been
+	// loaded into memory.
+	loaded map[lnwire.ShortChannelID]struct{}
+
 	// closed is an in-memory set of circuits for which the switch has
 	// received a settle or fail. This precedes the actual deletion of a
 	// circuit from disk.
@@ -230,12 +255,9 @@ func NewCircuitMap(cfg *CircuitMapConfig) (CircuitMap, error) {
 		return nil, err
 	}
 
-	// Delete old circuits and keystones of closed channels.
-	if err := cm.cleanClosedChannels(); err != nil {
-		return nil, err
-	}
-
-	// Load any previously persisted circuit into back into memory.
+	// Load the keystones of any previously persisted circuits into
+	// memory, compacting the circuits and keystones of closed channels
+	// along the way. The circuits themselves are loaded on demand.
 	if err := cm.restoreMemState(); err != nil {
 		return nil, err
 	}
@@ -266,112 +288,95 @@ func (cm *circuitMap) initBuckets() error {
 	}, func() {})
 }
 
-// cleanClosedChannels deletes all circuits and keystones related to closed
-// channels. It first reads all the closed channels and caches the ShortChanIDs
-// into a map for fast lookup. Then it iterates the circuit bucket and keystone
-// bucket and deletes items whose ChanID matches the ShortChanID.
-//
-// NOTE: this operation can also be built into restoreMemState since the latter
-// already opens and iterates the two root buckets, circuitAddKey and
-// circuitKeystoneKey. Depending on the size of the buckets, this marginal gain
-// may be worth investigating. Atm, for clarity, this operation is wrapped into
-// its own function.
-func (cm *circuitMap) cleanClosedChannels() error {
-	log.Infof("Cleaning circuits from disk for closed channels")
-
-	// closedChanIDSet stores the short channel IDs for closed channels.
-	closedChanIDSet := make(map[lnwire.ShortChannelID]struct{})
+// fetchClosedChanIDs returns the short channel IDs of all channels whose
+// close has been confirmed.
+func (cm *circuitMap) fetchClosedChanIDs() (
+	map[lnwire.ShortChannelID]struct{}, error) {
 
-	// circuitKeySet stores the incoming circuit keys of the payment
-	// circuits that need to be deleted.
-	circuitKeySet := make(map[CircuitKey]struct{})
+	closedChannels, err := cm.cfg.FetchClosedChannels(false)
+	if err != nil {
+		return nil, err
+	}
 
-	// keystoneKeySet stores the outgoing keys of the keystones that need
-	// to be deleted.
-	keystoneKeySet := make(map[CircuitKey]struct{})
+	closedChanIDs := make(map[lnwire.ShortChannelID]struct{})
+	for _, closedChannel := range closedChannels {
+		// Skip if the channel close is pending.
+		if closedChannel.IsPending {
+			continue
+		}
 
-	// isClosedChannel is a helper closure that returns a bool indicating
-	// the chanID belongs to a closed channel.
-	isClosedChannel := func(chanID lnwire.ShortChannelID) bool {
 		// Skip if the channel ID is zero value. This has the effect
 		// that a zero value incoming or outgoing key will never be
 		// matched and its corresponding circuits or keystones are not
 		// deleted.
-		if chanID.ToUint64() == 0 {
-			return false
+		if closedChannel.ShortChanID.ToUint64() == 0 {
+			continue
 		}
 
-		_, ok := closedChanIDSet[chanID]
-		return ok
+		closedChanIDs[closedChannel.ShortChanID] = struct{}{}
 	}
 
-	// Find closed channels and cache their ShortChannelIDs into a map.
-	// This map will be used for looking up relative circuits and keystones.
-	closedChannels, err := cm.cfg.FetchClosedChannels(false)
+	return closedChanIDs, nil
+}
+
+// restoreMemState loads the keystones from disk and initializes the in-memory
+// representation of the circuit map, to which the circuits are added once
+// their channels are loaded. This method will also remove any stray keystones,
+// which are those that appear fully-opened, but have no pending circuit
+// related to the intended incoming link.
+//
+// The circuits and keystones of closed channels are compacted in the same
+// pass. Keystones are matched against the closed channels while they are
+// being loaded, and the circuits of closed incoming channels are found by
+// seeking to their channel ID, so that the circuit bucket is never scanned
+// in full.
+func (cm *circuitMap) restoreMemState() error {
+	log.Infof("Restoring in-memory circuit state from disk")
+
+	closedChanIDs, err := cm.fetchClosedChanIDs()
 	if err != nil {
 		return err
 	}
 
-	for _, closedChannel := range closedChannels {
-		// Skip if the channel close is pending.
-		if closedChannel.IsPending {
-			continue
-		}
+	log.Debugf("Found %v closed channels", len(closedChanIDs))
 
-		closedChanIDSet[closedChannel.ShortChanID] = struct{}{}
+	// isClosedChannel is a helper closure that returns a bool indicating
+	// the chanID belongs to a closed channel.
+	isClosedChannel := func(chanID lnwire.ShortChannelID) bool {
+		_, ok := closedChanIDs[chanID]
+		return ok
 	}
 
-	log.Debugf("Found %v closed channels", len(closedChanIDSet))
+	var (
+		keystones   map[CircuitKey]CircuitKey
+		inKeystones map[CircuitKey]CircuitKey
 
-	// Exit early if there are no closed channels.
-	if len(closedChanIDSet) == 0 {
-		log.Infof("Finished cleaning: no closed channels found, " +
-			"no actions taken.",
-		)
-		return nil
-	}
+		numCircuitsDeleted  int
+		numKeystonesDeleted int
+	)
 
-	// Find the payment circuits and keystones that need to be deleted.
-	if err := kvdb.View(cm.cfg.DB, func(tx kvdb.RTx) error {
-		circuitBkt := tx.ReadBucket(circuitAddKey)
+	if err := kvdb.Update(cm.cfg.DB, func(tx kvdb.RwTx) error {
+		circuitBkt := tx.ReadWriteBucket(circuitAddKey)
 		if circuitBkt == nil {
 			return ErrCorruptedCircuitMap
 		}
-		keystoneBkt := tx.ReadBucket(circuitKeystoneKey)
+
+		// Load the keystone bucket and resurrect the keystones used in
+		// any open circuits.
+		keystoneBkt := tx.ReadWriteBucket(circuitKeystoneKey)
 		if keystoneBkt == nil {
 			return ErrCorruptedCircuitMap
 		}
 
-		// If a circuit's incoming/outgoing key prefix matches the
-		// ShortChanID, it will be deleted. However, if the ShortChanID
-		// of the incoming key is zero, the circuit will be kept as it
-		// indicates a locally initiated payment.
-		if err := circuitBkt.ForEach(func(_, v []byte) error {
-			circuit, err := cm.decodeCircuit(v)
-			if err != nil {
-				return err
-			}
-
-			// Check if the incoming channel ID can be found in the
-			// closed channel ID map.
-			if !isClosedChannel(circuit.Incoming.ChanID) {
-				return nil
-			}
-
-			circuitKeySet[circuit.Incoming] = struct{}{}
+		// closedCircuits stores the incoming keys of the payment
+		// circuits that need to be deleted, and closedOutKeys the
+		// outgoing keys of the keystones that need to be deleted.
+		closedCircuits := make(map[CircuitKey]struct{})
+		var closedOutKeys []CircuitKey
 
-			return nil
-		}); err != nil {
-			return err
-		}
-
-		// If a keystone's InKey or OutKey matches the short channel id
-		// in the closed channel ID map, it will be deleted.
-		err := keystoneBkt.ForEach(func(k, v []byte) error {
-			var (
-				inKey  CircuitKey
-				outKey CircuitKey
-			)
+		var strayKeystones []Keystone
+		if err := keystoneBkt.ForEach(func(k, v []byte) error {
+			var inKey, outKey CircuitKey
 
 			// Decode the incoming and outgoing circuit keys.
 			if err := inKey.SetBytes(v); err != nil {
@@ -381,80 +386,76 @@ func (cm *circuitMap) cleanClosedChannels() error {
 				return err
 			}
 
-			// Check if the incoming channel ID can be found in the
-			// closed channel ID map.
+			// If the incoming channel is closed, the keystone and
+			// its circuit are deleted regardless of the state of
+			// the outgoing channel.
 			if isClosedChannel(inKey.ChanID) {
-				// If the incoming channel is closed, we can
-				// skip checking on outgoing channel ID because
-				// this keystone will be deleted.
-				keystoneKeySet[outKey] = struct{}{}
-
-				// Technically the incoming keys found in
-				// keystone bucket should be a subset of
-				// circuit bucket. So a previous loop should
-				// have this inKey put inside circuitAddKey map
-				// already. We do this again to be sure the
-				// circuits are properly cleaned. Even this
-				// inKey doesn't exist in circuit bucket, we
-				// are fine as db deletion is a noop.
-				circuitKeySet[inKey] = struct{}{}
+				closedOutKeys = append(closedOutKeys, outKey)
+				closedCircuits[inKey] = struct{}{}
+
 				return nil
 			}
 
-			// Check if the outgoing channel ID can be found in the
-			// closed channel ID map. Notice that we need to store
-			// the outgoing key because it's used for db query.
-			//
-			// NOTE: We skip this if a resolution message can be
-			// found under the outKey. This means that there is an
-			// existing resolution message(s) that need to get to
-			// the incoming links.
-			if isClosedChannel(outKey.ChanID) {
-				// Check the resolution message store. A return
-				// value of nil means we need to skip deleting
-				// these circuits.
-				if cm.cfg.CheckResolutionMsg(&outKey) == nil {
-					return nil
-				}
+			// If the outgoing channel is closed, the keystone and
+			// its circuit are deleted as well, unless there are
+			// resolution messages under the outKey that still
+			// need to get to the incoming link.
+			if isClosedChannel(outKey.ChanID) &&
+				cm.cfg.CheckResolutionMsg(&outKey) != nil {
 
-				keystoneKeySet[outKey] = struct{}{}
+				closedOutKeys = append(closedOutKeys, outKey)
+				closedCircuits[inKey] = struct{}{}
 
-				// Also update circuitKeySet to mark the
-				// payment circuit needs to be deleted.
-				circuitKeySet[inKey] = struct{}{}
+				return nil
 			}
 
-			return nil
-		})
-		return err
-	}, func() {
-		// Reset the sets.
-		circuitKeySet = make(map[CircuitKey]struct{})
-		keystoneKeySet = make(map[CircuitKey]struct{})
-	}); err != nil {
-		return err
-	}
+			// Only keystones of persisted circuits are indexed,
+			// the others are stray.
+			if circuitBkt.Get(v) == nil {
+				strayKeystones = append(strayKeystones, Keystone{
+					InKey:  inKey,
+					OutKey: outKey,
+				})
 
-	log.Debugf("To be deleted: num_circuits=%v, num_keystones=%v",
-		len(circuitKeySet), len(keystoneKeySet),
-	)
+				return nil
+			}
 
-	numCircuitsDeleted := 0
-	numKeystonesDeleted := 0
+			keystones[outKey] = inKey
+			inKeystones[inKey] = outKey
 
-	// Delete all the circuits and keystones for closed channels.
-	if err := kvdb.Update(cm.cfg.DB, func(tx kvdb.RwTx) error {
-		circuitBkt := tx.ReadWriteBucket(circuitAddKey)
-		if circuitBkt == nil {
-			return ErrCorruptedCircuitMap
+			return nil
+		}); err != nil {
+			return err
 		}
-		keystoneBkt := tx.ReadWriteBucket(circuitKeystoneKey)
-		if keystoneBkt == nil {
-			return ErrCorruptedCircuitMap
+
+		// Circuits are stored under their incoming key, so the
+		// remaining circuits of closed incoming channels can be found
+		// without decoding or scanning the circuits of other channels.
+		// If the ShortChanID of the incoming key is zero, the circuit
+		// will be kept as it indicates a locally initiated payment.
+		for chanID := range closedChanIDs {
+			var prefix [8]byte
+			binary.BigEndian.PutUint64(prefix[:], chanID.ToUint64())
+
+			cursor := circuitBkt.ReadCursor()
+			k, _ := cursor.Seek(prefix[:])
+			for bytes.HasPrefix(k, prefix[:]) {
+				var inKey CircuitKey
+				if err := inKey.SetBytes(k); err != nil {
+					return err
+				}
+
+				closedCircuits[inKey] = struct{}{}
+				k, _ = cursor.Next()
+			}
 		}
 
-		// Delete the circuit.
-		for inKey := range circuitKeySet {
+		log.Debugf("To be deleted: num_circuits=%v, num_keystones=%v",
+			len(closedCircuits), len(closedOutKeys))
+
+		// Delete all the circuits and keystones for closed channels.
+		// Deleting a circuit that doesn't exist is a noop.
+		for inKey := range closedCircuits {
 			if err := circuitBkt.Delete(inKey.Bytes()); err != nil {
 				return err
 			}
@@ -462,8 +463,7 @@ func (cm *circuitMap) cleanClosedChannels() error {
 			numCircuitsDeleted++
 		}
 
-		// Delete the keystone using the outgoing key.
-		for outKey := range keystoneKeySet {
+		for _, outKey := range closedOutKeys {
 			err := keystoneBkt.Delete(outKey.Bytes())
 			if err != nil {
 				return err
@@ -472,97 +472,6 @@ func (cm *circuitMap) cleanClosedChannels() error {
 			numKeystonesDeleted++
 		}
 
-		return nil
-	}, func() {}); err != nil {
-		numCircuitsDeleted = 0
-		numKeystonesDeleted = 0
-		return err
-	}
-
-	log.Infof("Finished cleaning: num_closed_channel=%v, "+
-		"num_circuits=%v, num_keystone=%v",
-		len(closedChannels), numCircuitsDeleted, numKeystonesDeleted,
-	)
-
-	return nil
-}
-
-// restoreMemState loads the contents of the half circuit and full circuit
-// buckets from disk and reconstructs the in-memory representation of the
-// circuit map. Afterwards, the state of the hash index is reconstructed using
-// the recovered set of full circuits. This method will also remove any stray
-// keystones, which are those that appear fully-opened, but have no pending
-// circuit related to the intended incoming link.
-func (cm *circuitMap) restoreMemState() error {
-	log.Infof("Restoring in-memory circuit state from disk")
-
-	var (
-		opened  map[CircuitKey]*PaymentCircuit
-		pending map[CircuitKey]*PaymentCircuit
-	)
-
-	if err := kvdb.Update(cm.cfg.DB, func(tx kvdb.RwTx) error {
-		// Restore any of the circuits persisted in the circuit bucket
-		// back into memory.
-		circuitBkt := tx.ReadWriteBucket(circuitAddKey)
-		if circuitBkt == nil {
-			return ErrCorruptedCircuitMap
-		}
-
-		if err := circuitBkt.ForEach(func(_, v []byte) error {
-			circuit, err := cm.decodeCircuit(v)
-			if err != nil {
-				return err
-			}
-
-			circuit.LoadedFromDisk = true
-			pending[circuit.Incoming] = circuit
-
-			return nil
-		}); err != nil {
-			return err
-		}
-
-		// Furthermore, load the keystone bucket and resurrect the
-		// keystones used in any open circuits.
-		keystoneBkt := tx.ReadWriteBucket(circuitKeystoneKey)
-		if keystoneBkt == nil {
-			return ErrCorruptedCircuitMap
-		}
-
-		var strayKeystones []Keystone
-		if err := keystoneBkt.ForEach(func(k, v []byte) error {
-			var (
-				inKey  CircuitKey
-				outKey = &CircuitKey{}
-			)
-
-			// Decode the incoming and outgoing circuit keys.
-			if err := inKey.SetBytes(v); err != nil {
-				return err
-			}
-			if err := outKey.SetBytes(k); err != nil {
-				return err
-			}
-
-			// Retrieve the pending circuit, set its keystone, then
-			// add it to the opened map.
-			circuit, ok := pending[inKey]
-			if ok {
-				circuit.Outgoing = outKey
-				opened[*outKey] = circuit
-			} else {
-				strayKeystones = append(strayKeystones, Keystone{
-					InKey:  inKey,
-					OutKey: *outKey,
-				})
-			}
-
-			return nil
-		}); err != nil {
-			return err
-		}
-
 		// If any stray keystones were found, we'll proceed to prune
 		// them from the circuit map's persistent storage. This may
 		// manifest on older nodes that had updated channels before
@@ -589,29 +498,131 @@ func (cm *circuitMap) restoreMemState() error {
 		return nil
 
 	}, func() {
-		opened = make(map[CircuitKey]*PaymentCircuit)
-		pending = make(map[CircuitKey]*PaymentCircuit)
+		keystones = make(map[CircuitKey]CircuitKey)
+		inKeystones = make(map[CircuitKey]CircuitKey)
+		numCircuitsDeleted = 0
+		numKeystonesDeleted = 0
 	}); err != nil {
 		return err
 	}
 
-	cm.pending = pending
-	cm.opened = opened
+	log.Infof("Compacted closed channels: num_closed_channel=%v, "+
+		"num_circuits=%v, num_keystone=%v", len(closedChanIDs),
+		numCircuitsDeleted, numKeystonesDeleted)
+
+	cm.pending = make(map[CircuitKey]*PaymentCircuit)
+	cm.opened = make(map[CircuitKey]*PaymentCircuit)
 	cm.closed = make(map[CircuitKey]struct{})
+	cm.hashIndex = make(map[[32]byte]map[CircuitKey]struct{})
+	cm.keystones = keystones
+	cm.inKeystones = inKeystones
+	cm.loaded = make(map[lnwire.ShortChannelID]struct{})
 
-	log.Infof("Payment circuits loaded: num_pending=%v, num_open=%v",
-		len(pending), len(opened))
+	log.Infof("Payment circuit keystones loaded: num_open=%v",
+		len(keystones))
 
-	// Finally, reconstruct the hash index by running through our set of
-	// open circuits.
-	cm.hashIndex = make(map[[32]byte]map[CircuitKey]struct{})
-	for _, circuit := range opened {
-		cm.addCircuitToHashIndex(circuit)
+	return nil
+}
+
+// ensureLoaded loads the circuits of the given incoming channels into memory,
+// if they haven't been loaded yet.
+func (cm *circuitMap) ensureLoaded(chanIDs ...lnwire.ShortChannelID) error {
+	cm.mtx.RLock()
+	missing := !cm.isLoaded(chanIDs...)
+	cm.mtx.RUnlock()
+
+	if !missing {
+		return nil
+	}
+
+	cm.mtx.Lock()
+	defer cm.mtx.Unlock()
+
+	return cm.loadChannels(chanIDs...)
+}
+
+// isLoaded returns whether the circuits of all given incoming channels are
+// loaded. The caller must hold the mutex.
+func (cm *circuitMap) isLoaded(chanIDs ...lnwire.ShortChannelID) bool {
+	for _, chanID := range chanIDs {
+		if _, ok := cm.loaded[chanID]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// loadChannels loads the circuits of the given incoming channels into memory,
+// skipping the channels that have been loaded already. The caller must hold
+// the write lock.
+func (cm *circuitMap) loadChannels(chanIDs ...lnwire.ShortChannelID) error {
+	for _, chanID := range chanIDs {
+		if cm.isLoaded(chanID) {
+			continue
+		}
+
+		var circuits []*PaymentCircuit
+		err := kvdb.View(cm.cfg.DB, func(tx kvdb.RTx) error {
+			circuitBkt := tx.ReadBucket(circuitAddKey)
+			if circuitBkt == nil {
+				return ErrCorruptedCircuitMap
+			}
+
+			// Circuits are stored under their incoming key, which
+			// is prefixed with the incoming channel id.
+			var prefix [8]byte
+			binary.BigEndian.PutUint64(prefix[:], chanID.ToUint64())
+
+			cursor := circuitBkt.ReadCursor()
+			k, v := cursor.Seek(prefix[:])
+			for bytes.HasPrefix(k, prefix[:]) {
+				circuit, err := cm.decodeCircuit(v)
+				if err != nil {
+					return err
+				}
+
+				circuits = append(circuits, circuit)
+				k, v = cursor.Next()
+			}
+
+			return nil
+		}, func() {
+			circuits = nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, circuit := range circuits {
+			cm.addLoadedCircuit(circuit)
+		}
+		cm.loaded[chanID] = struct{}{}
+
+		log.Debugf("Loaded %v payment circuits for chan_id=%v",
+			len(circuits), chanID)
 	}
 
 	return nil
 }
 
+// addLoadedCircuit adds a circuit that was loaded from disk to the in-memory
+// state, opening it if a keystone exists for it. The caller must hold the
+// write lock.
+func (cm *circuitMap) addLoadedCircuit(circuit *PaymentCircuit) {
+	circuit.LoadedFromDisk = true
+	cm.pending[circuit.Incoming] = circuit
+
+	outKey, ok := cm.inKeystones[circuit.Incoming]
+	if !ok {
+		return
+	}
+
+	circuit.Outgoing = &outKey
+	cm.opened[outKey] = circuit
+	cm.addCircuitToHashIndex(circuit)
+}
+
 // decodeCircuit reconstructs an in-memory payment circuit from a byte slice.
 // The byte slice is assumed to have been generated by the circuit's Encode
 // method. If the decoding is successful, the onion obfuscator will be
@@ -706,6 +717,8 @@ func (cm *circuitMap) TrimOpenCircuits(chanID lnwire.ShortChannelID,
 	// Scan forward from the last unacked htlc id, stopping as soon as we
 	// don't find any more. Outgoing htlc id's must be assigned in order,
 	// so there should never be disjoint segments of keystones to trim.
+	// The keystone index is used, so that circuits that haven't been
+	// loaded yet don't need to be loaded.
 	cm.mtx.Lock()
 	for i := start; ; i++ {
 		outKey := CircuitKey{
@@ -713,15 +726,23 @@ func (cm *circuitMap) TrimOpenCircuits(chanID lnwire.ShortChannelID,
 			HtlcID: i,
 		}
 
-		circuit, ok := cm.opened[outKey]
+		inKey, ok := cm.keystones[outKey]
 		if !ok {
 			break
 		}
 
-		circuit.Outgoing = nil
-		delete(cm.opened, outKey)
+		delete(cm.keystones, outKey)
+		delete(cm.inKeystones, inKey)
 		trimmedOutKeys = append(trimmedOutKeys, outKey)
+
+		circuit, ok := cm.opened[outKey]
+		if !ok {
+			continue
+		}
+
 		cm.removeCircuitFromHashIndex(circuit)
+		circuit.Outgoing = nil
+		delete(cm.opened, outKey)
 	}
 	cm.mtx.Unlock()
 
@@ -749,6 +770,13 @@ func (cm *circuitMap) TrimOpenCircuits(chanID lnwire.ShortChannelID,
 // LookupCircuit queries the circuit map for the circuit identified by its
 // incoming circuit key. Returns nil if there is no such circuit.
 func (cm *circuitMap) LookupCircuit(inKey CircuitKey) *PaymentCircuit {
+	if err := cm.ensureLoaded(inKey.ChanID); err != nil {
+		log.Errorf("Unable to load circuits of chan_id=%v: %v",
+			inKey.ChanID, err)
+
+		return nil
+	}
+
 	cm.mtx.RLock()
 	defer cm.mtx.RUnlock()
 
@@ -758,15 +786,61 @@ func (cm *circuitMap) LookupCircuit(inKey CircuitKey) *PaymentCircuit {
 // LookupOpenCircuit searches for the circuit identified by its outgoing circuit
 // key.
 func (cm *circuitMap) LookupOpenCircuit(outKey CircuitKey) *PaymentCircuit {
+	cm.mtx.RLock()
+	inKey, ok := cm.keystones[outKey]
+	cm.mtx.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	if err := cm.ensureLoaded(inKey.ChanID); err != nil {
+		log.Errorf("Unable to load circuits of chan_id=%v: %v",
+			inKey.ChanID, err)
+
+		return nil
+	}
+
 	cm.mtx.RLock()
 	defer cm.mtx.RUnlock()
 
 	return cm.opened[outKey]
 }
 
+// OpenKeystones returns the keystones of all circuits that are currently
+// open. The keystones are always kept in memory, so this doesn't load any
+// circuits.
+func (cm *circuitMap) OpenKeystones() []Keystone {
+	cm.mtx.RLock()
+	defer cm.mtx.RUnlock()
+
+	keystones := make([]Keystone, 0, len(cm.keystones))
+	for outKey, inKey := range cm.keystones {
+		keystones = append(keystones, Keystone{
+			InKey:  inKey,
+			OutKey: outKey,
+		})
+	}
+
+	return keystones
+}
+
 // LookupByPaymentHash looks up and returns any payment circuits with a given
-// payment hash.
+// payment hash. Only open circuits are indexed by their payment hash, so only
+// the incoming channels that have open circuits are loaded.
 func (cm *circuitMap) LookupByPaymentHash(hash [32]byte) []*PaymentCircuit {
+	cm.mtx.RLock()
+	chanIDs := make([]lnwire.ShortChannelID, 0, len(cm.inKeystones))
+	for inKey := range cm.inKeystones {
+		chanIDs = append(chanIDs, inKey.ChanID)
+	}
+	cm.mtx.RUnlock()
+
+	if err := cm.ensureLoaded(chanIDs...); err != nil {
+		log.Errorf("Unable to load circuits: %v", err)
+		return nil
+	}
+
 	cm.mtx.RLock()
 	defer cm.mtx.RUnlock()
 
@@ -820,6 +894,21 @@ func (cm *circuitMap) CommitCircuits(circuits ...*PaymentCircuit) (
 	// to fail back all packets that weren't dropped if we encounter an
 	// error when committing the circuits.
 	cm.mtx.Lock()
+
+	// Load the circuits of the incoming channels, so that duplicates of
+	// persisted circuits are detected. If that fails, we drop all circuits,
+	// which leaves the htlcs with their incoming links.
+	chanIDs := make([]lnwire.ShortChannelID, 0, len(circuits))
+	for _, circuit := range circuits {
+		chanIDs = append(chanIDs, circuit.Incoming.ChanID)
+	}
+	if err := cm.loadChannels(chanIDs...); err != nil {
+		cm.mtx.Unlock()
+
+		actions.Drops = circuits
+		return actions, err
+	}
+
 	var adds, drops, fails, addFails []*PaymentCircuit
 	for _, circuit := range circuits {
 		inKey := circuit.InKey()
@@ -950,12 +1039,20 @@ func (cm *circuitMap) OpenCircuits(keystones ...Keystone) error {
 	log.Tracef("Opening finalized circuits: %v", lnutils.SpewLogClosure(
 		keystones))
 
+	chanIDs := make([]lnwire.ShortChannelID, 0, len(keystones))
+	for _, ks := range keystones {
+		chanIDs = append(chanIDs, ks.InKey.ChanID)
+	}
+	if err := cm.ensureLoaded(chanIDs...); err != nil {
+		return err
+	}
+
 	// Check that all keystones correspond to committed-but-unopened
 	// circuits.
 	cm.mtx.RLock()
 	openedCircuits := make([]*PaymentCircuit, 0, len(keystones))
 	for _, ks := range keystones {
-		if _, ok := cm.opened[ks.OutKey]; ok {
+		if _, ok := cm.keystones[ks.OutKey]; ok {
 			cm.mtx.RUnlock()
 			return ErrDuplicateKeystone
 		}
@@ -1007,6 +1104,8 @@ func (cm *circuitMap) OpenCircuits(keystones ...Keystone) error {
 		*circuit.Outgoing = ks.OutKey
 
 		cm.opened[ks.OutKey] = circuit
+		cm.keystones[ks.OutKey] = ks.InKey
+		cm.inKeystones[ks.InKey] = ks.OutKey
 		cm.addCircuitToHashIndex(circuit)
 	}
 	cm.mtx.Unlock()
@@ -1030,6 +1129,10 @@ func (cm *circuitMap) FailCircuit(inKey CircuitKey) (*PaymentCircuit, error) {
 	cm.mtx.Lock()
 	defer cm.mtx.Unlock()
 
+	if err := cm.loadChannels(inKey.ChanID); err != nil {
+		return nil, err
+	}
+
 	circuit, ok := cm.pending[inKey]
 	if !ok {
 		return nil, ErrUnknownCircuit
@@ -1053,6 +1156,15 @@ func (cm *circuitMap) CloseCircuit(outKey CircuitKey) (*PaymentCircuit, error) {
 	cm.mtx.Lock()
 	defer cm.mtx.Unlock()
 
+	inKey, ok := cm.keystones[outKey]
+	if !ok {
+		return nil, ErrUnknownCircuit
+	}
+
+	if err := cm.loadChannels(inKey.ChanID); err != nil {
+		return nil, err
+	}
+
 	circuit, ok := cm.opened[outKey]
 	if !ok {
 		return nil, ErrUnknownCircuit
@@ -1084,7 +1196,17 @@ func (cm *circuitMap) DeleteCircuits(inKeys ...CircuitKey) error {
 		removedCircuits = make(map[CircuitKey]*PaymentCircuit)
 	)
 
+	chanIDs := make([]lnwire.ShortChannelID, 0, len(inKeys))
+	for _, inKey := range inKeys {
+		chanIDs = append(chanIDs, inKey.ChanID)
+	}
+
 	cm.mtx.Lock()
+	if err := cm.loadChannels(chanIDs...); err != nil {
+		cm.mtx.Unlock()
+		return err
+	}
+
 	// Remove any references to the circuits from memory, keeping track of
 	// which circuits were removed, and which ones had been marked closed.
 	// This can be used to restore these entries later if the persistent
@@ -1103,6 +1225,8 @@ func (cm *circuitMap) DeleteCircuits(inKeys ...CircuitKey) error {
 
 		if circuit.HasKeystone() {
 			delete(cm.opened, circuit.OutKey())
+			delete(cm.keystones, circuit.OutKey())
+			delete(cm.inKeystones, inKey)
 			cm.removeCircuitFromHashIndex(circuit)
 		}
 
@@ -1162,6 +1286,8 @@ func (cm *circuitMap) DeleteCircuits(inKeys ...CircuitKey) error {
 
 		if circuit.HasKeystone() {
 			cm.opened[circuit.OutKey()] = circuit
+			cm.keystones[circuit.OutKey()] = inKey
+			cm.inKeystones[inKey] = circuit.OutKey()
 			cm.addCircuitToHashIndex(circuit)
 		}
 	}
@@ -1191,11 +1317,39 @@ func (cm *circuitMap) removeCircuitFromHashIndex(c *PaymentCircuit) {
 }
 
 // NumPending returns the number of active circuits added to the circuit map.
+// The circuits of channels that haven't been loaded yet are counted by their
+// keys, without decoding or loading them.
 func (cm *circuitMap) NumPending() int {
 	cm.mtx.RLock()
 	defer cm.mtx.RUnlock()
 
-	return len(cm.pending)
+	var numUnloaded int
+	err := kvdb.View(cm.cfg.DB, func(tx kvdb.RTx) error {
+		circuitBkt := tx.ReadBucket(circuitAddKey)
+		if circuitBkt == nil {
+			return ErrCorruptedCircuitMap
+		}
+
+		return circuitBkt.ForEach(func(k, _ []byte) error {
+			var inKey CircuitKey
+			if err := inKey.SetBytes(k); err != nil {
+				return err
+			}
+
+			if !cm.isLoaded(inKey.ChanID) {
+				numUnloaded++
+			}
+
+			return nil
+		})
+	}, func() {
+		numUnloaded = 0
+	})
+	if err != nil {
+		log.Errorf("Unable to count circuits: %v", err)
+	}
+
+	return len(cm.pending) + numUnloaded
 }
 
 // NumOpen returns the number of circuits that have been opened by way of
@@ -1205,5 +1359,5 @@ func (cm *circuitMap) NumOpen() int {
 	cm.mtx.RLock()
 	defer cm.mtx.RUnlock()
 
-	return len(cm.opened)
+	return len(cm.keystones)
 }
@@ -83,7 +83,7 @@ func initTestExtracter() {
 
 // newOnionProcessor creates starts a new htlcswitch.OnionProcessor using a temp
 // db and no garbage collection.
-func newOnionProcessor(t *testing.T) *hop.OnionProcessor {
+func newOnionProcessor(t testing.TB) *hop.OnionProcessor {
 	sphinxRouter := sphinx.NewRouter(
 		&keychain.PrivKeyECDH{PrivKey: sphinxPrivKey},
 		sphinx.NewMemoryReplayLog(),
@@ -99,7 +99,7 @@ func newOnionProcessor(t *testing.T) *hop.OnionProcessor {
 // newCircuitMap creates a new htlcswitch.CircuitMap using a temp db and a
 // fresh sphinx router. When resMsg is set to true, CheckResolutionMsg will
 // always return nil. Otherwise it will always return an error.
-func newCircuitMap(t *testing.T, resMsg bool) (*htlcswitch.CircuitMapConfig,
+func newCircuitMap(t testing.TB, resMsg bool) (*htlcswitch.CircuitMapConfig,
 	htlcswitch.CircuitMap) {
 
 	onionProcessor := newOnionProcessor(t)
@@ -620,7 +620,7 @@ func equalIgnoreLFD(c, c2 *htlcswitch.PaymentCircuit) bool {
 // makeCircuitDB initializes a new test channeldb for testing the persistence of
 // the circuit map. If an empty string is provided as a path, a temp directory
 // will be created.
-func makeCircuitDB(t *testing.T, path string) *channeldb.DB {
+func makeCircuitDB(t testing.TB, path string) *channeldb.DB {
 	if path == "" {
 		path = t.TempDir()
 	}
@@ -634,7 +634,7 @@ func makeCircuitDB(t *testing.T, path string) *channeldb.DB {
 
 // Creates a new circuit map, backed by a freshly opened channeldb. The existing
 // channeldb is closed in order to simulate a complete restart.
-func restartCircuitMap(t *testing.T, cfg *htlcswitch.CircuitMapConfig) (
+func restartCircuitMap(t testing.TB, cfg *htlcswitch.CircuitMapConfig) (
 	*htlcswitch.CircuitMapConfig, htlcswitch.CircuitMap) {
 
 	// Record the current temp path and close current db. We know we have
@@ -1360,3 +1360,162 @@ func TestCircuitMapDeleteOpenCircuit(t *testing.T) {
 			circuit2, nil)
 	}
 }
+
+// TestCircuitMapLazyLoading asserts that circuits that are loaded on demand
+// after a restart behave the same as if they had been loaded on startup.
+func TestCircuitMapLazyLoading(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chan1 = lnwire.NewShortChanIDFromInt(1)
+		chan2 = lnwire.NewShortChanIDFromInt(2)
+		chan3 = lnwire.NewShortChanIDFromInt(3)
+	)
+
+	cfg, circuitMap := newCircuitMap(t, false)
+
+	// Add a forwarded circuit from chan1 to chan3, and one from chan2 that
+	// isn't opened.
+	circuit1 := &htlcswitch.PaymentCircuit{
+		Incoming: htlcswitch.CircuitKey{
+			ChanID: chan1,
+			HtlcID: 1,
+		},
+		PaymentHash:    hash1,
+		ErrorEncrypter: testExtracter,
+	}
+	circuit2 := &htlcswitch.PaymentCircuit{
+		Incoming: htlcswitch.CircuitKey{
+			ChanID: chan2,
+			HtlcID: 1,
+		},
+		PaymentHash:    hash2,
+		ErrorEncrypter: testExtracter,
+	}
+	_, err := circuitMap.CommitCircuits(circuit1, circuit2)
+	require.NoError(t, err)
+
+	keystone := htlcswitch.Keystone{
+		InKey: circuit1.Incoming,
+		OutKey: htlcswitch.CircuitKey{
+			ChanID: chan3,
+			HtlcID: 0,
+		},
+	}
+	require.NoError(t, circuitMap.OpenCircuits(keystone))
+
+	cfg, circuitMap = restartCircuitMap(t, cfg)
+
+	// The keystones are known without loading any circuits, and the
+	// pending circuits are counted without loading them.
+	require.Equal(t, 1, circuitMap.NumOpen())
+	require.Equal(
+		t, []htlcswitch.Keystone{keystone}, circuitMap.OpenKeystones(),
+	)
+	require.Equal(t, 2, circuitMap.NumPending())
+
+	// Opening the same keystone again is caught before chan1 is loaded.
+	require.ErrorIs(
+		t, circuitMap.OpenCircuits(keystone),
+		htlcswitch.ErrDuplicateKeystone,
+	)
+
+	// Closing the circuit by its outgoing key loads the circuits of the
+	// incoming channel.
+	closed, err := circuitMap.CloseCircuit(keystone.OutKey)
+	require.NoError(t, err)
+	require.True(t, closed.LoadedFromDisk)
+	require.Equal(t, keystone.OutKey, *closed.Outgoing)
+
+	// A duplicate of the unopened circuit is failed back, as it was lost
+	// in the restart.
+	actions, err := circuitMap.CommitCircuits(&htlcswitch.PaymentCircuit{
+		Incoming:    circuit2.Incoming,
+		PaymentHash: hash2,
+	})
+	require.NoError(t, err)
+	require.Len(t, actions.Fails, 1)
+
+	// Deleting the forwarded circuit also removes its keystone.
+	require.NoError(t, circuitMap.DeleteCircuits(circuit1.Incoming))
+	require.Zero(t, circuitMap.NumOpen())
+	require.Nil(t, circuitMap.LookupOpenCircuit(keystone.OutKey))
+	require.Equal(t, 1, circuitMap.NumPending())
+
+	_, circuitMap = restartCircuitMap(t, cfg)
+	require.Nil(t, circuitMap.LookupCircuit(circuit1.Incoming))
+	require.NotNil(t, circuitMap.LookupCircuit(circuit2.Incoming))
+}
+
+// populateCircuitMap adds numChans*numHtlcs opened circuits to the circuit
+// map, which are forwarded from numChans incoming channels to a single
+// outgoing channel.
+func populateCircuitMap(b *testing.B, circuitMap htlcswitch.CircuitMap,
+	numChans, numHtlcs int) {
+
+	var outHtlcID uint64
+	for i := 0; i < numChans; i++ {
+		chanID := lnwire.NewShortChanIDFromInt(uint64(i + 1))
+
+		circuits := make([]*htlcswitch.PaymentCircuit, 0, numHtlcs)
+		keystones := make([]htlcswitch.Keystone, 0, numHtlcs)
+		for j := 0; j < numHtlcs; j++ {
+			circuit := &htlcswitch.PaymentCircuit{
+				Incoming: htlcswitch.CircuitKey{
+					ChanID: chanID,
+					HtlcID: uint64(j),
+				},
+				ErrorEncrypter: testExtracter,
+			}
+			circuits = append(circuits, circuit)
+
+			keystones = append(keystones, htlcswitch.Keystone{
+				InKey: circuit.Incoming,
+				OutKey: htlcswitch.CircuitKey{
+					ChanID: lnwire.NewShortChanIDFromInt(
+						uint64(numChans + 1),
+					),
+					HtlcID: outHtlcID,
+				},
+			})
+			outHtlcID++
+		}
+
+		_, err := circuitMap.CommitCircuits(circuits...)
+		require.NoError(b, err)
+		require.NoError(b, circuitMap.OpenCircuits(keystones...))
+	}
+}
+
+// BenchmarkCircuitMapRestart measures the time it takes to restart the
+// circuit map and to look up a circuit of a single channel afterwards.
+func BenchmarkCircuitMapRestart(b *testing.B) {
+	cfg, circuitMap := newCircuitMap(b, false)
+	populateCircuitMap(b, circuitMap, 100, 100)
+
+	inKey := htlcswitch.CircuitKey{
+		ChanID: lnwire.NewShortChanIDFromInt(1),
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cfg, circuitMap = restartCircuitMap(b, cfg)
+		require.NotNil(b, circuitMap.LookupCircuit(inKey))
+	}
+}
+
+// BenchmarkCircuitMapRestartLoadAll measures the time it takes to restart the
+// circuit map and to load the circuits of all channels afterwards, which
+// corresponds to loading all circuits on startup. As all circuits are open, a
+// lookup by payment hash loads all of them.
+func BenchmarkCircuitMapRestartLoadAll(b *testing.B) {
+	cfg, circuitMap := newCircuitMap(b, false)
+	populateCircuitMap(b, circuitMap, 100, 100)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cfg, circuitMap = restartCircuitMap(b, cfg)
+		circuits := circuitMap.LookupByPaymentHash([32]byte{})
+		require.Len(b, circuits, 100*100)
+	}
+}
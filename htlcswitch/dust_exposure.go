@@ -0,0 +1,139 @@
+package htlcswitch
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrInvalidMaxFeeExposure is returned when the fee exposure threshold is
+// updated to zero, which would fail every dust htlc.
+var ErrInvalidMaxFeeExposure = errors.New("max fee exposure must be " +
+	"positive")
+
+// DustExposure is a snapshot of the dust exposure of a channel. The dust of
+// both commitments is tracked separately, since the dust limits of the two
+// parties may differ.
+type DustExposure struct {
+	// ChanID is the channel id of the channel.
+	ChanID lnwire.ChannelID
+
+	// ShortChanID is the short channel id of the channel.
+	ShortChanID lnwire.ShortChannelID
+
+	// CommitDust is the sum of the dust htlcs on each party's
+	// commitment.
+	CommitDust lntypes.Dual[lnwire.MilliSatoshi]
+
+	// MailboxDust is the sum of the dust htlcs that are waiting in the
+	// channel's mailbox to be added to each party's commitment.
+	MailboxDust lntypes.Dual[lnwire.MilliSatoshi]
+
+	// MaxFeeExposure is the threshold after which new dust htlcs are
+	// rejected on the channel.
+	MaxFeeExposure lnwire.MilliSatoshi
+}
+
+// Exposure returns the total dust exposure on the commitment of the given
+// party, including the dust htlcs that are waiting in the mailbox.
+func (d DustExposure) Exposure(
+	whoseCommit lntypes.ChannelParty) lnwire.MilliSatoshi {
+
+	return d.CommitDust.GetForParty(whoseCommit) +
+		d.MailboxDust.GetForParty(whoseCommit)
+}
+
+// Exceeded returns whether the dust exposure on either commitment exceeds the
+// threshold.
+func (d DustExposure) Exceeded() bool {
+	return d.Exposure(lntypes.Local) > d.MaxFeeExposure ||
+		d.Exposure(lntypes.Remote) > d.MaxFeeExposure
+}
+
+// dustExposure returns a snapshot of the current dust exposure of the given
+// link.
+func (s *Switch) dustExposure(link ChannelLink) DustExposure {
+	mailbox := s.mailOrchestrator.GetOrCreateMailBox(
+		link.ChanID(), link.ShortChanID(),
+	)
+	localMailDust, remoteMailDust := mailbox.DustPackets()
+
+	noFee := fn.None[chainfee.SatPerKWeight]()
+
+	return DustExposure{
+		ChanID:      link.ChanID(),
+		ShortChanID: link.ShortChanID(),
+		CommitDust: lntypes.Dual[lnwire.MilliSatoshi]{
+			Local:  link.getDustSum(lntypes.Local, noFee),
+			Remote: link.getDustSum(lntypes.Remote, noFee),
+		},
+		MailboxDust: lntypes.Dual[lnwire.MilliSatoshi]{
+			Local:  localMailDust,
+			Remote: remoteMailDust,
+		},
+		MaxFeeExposure: link.getMaxFeeExposure(),
+	}
+}
+
+// DustExposure returns a snapshot of the current dust exposure of the channel
+// with the given channel id.
+func (s *Switch) DustExposure(chanID lnwire.ChannelID) (DustExposure, error) {
+	s.indexMtx.RLock()
+	defer s.indexMtx.RUnlock()
+
+	link, err := s.getLink(chanID)
+	if err != nil {
+		return DustExposure{}, err
+	}
+
+	return s.dustExposure(link), nil
+}
+
+// DustExposures returns a snapshot of the current dust exposure of all
+// channels that have an active link.
+func (s *Switch) DustExposures() []DustExposure {
+	s.indexMtx.RLock()
+	defer s.indexMtx.RUnlock()
+
+	exposures := make([]DustExposure, 0, len(s.linkIndex))
+	for _, link := range s.linkIndex {
+		exposures = append(exposures, s.dustExposure(link))
+	}
+
+	return exposures
+}
+
+// maxFeeExposure returns the current threshold after which the switch rejects
+// new dust htlcs.
+func (s *Switch) maxFeeExposure() lnwire.MilliSatoshi {
+	return lnwire.MilliSatoshi(s.feeExposureLimit.Load())
+}
+
+// UpdateMaxFeeExposure updates the threshold after which new dust htlcs are
+// rejected at runtime. The new threshold is applied to all active links, and
+// to all links that are added afterwards. Htlcs that are already committed
+// are not affected.
+func (s *Switch) UpdateMaxFeeExposure(threshold lnwire.MilliSatoshi) error {
+	if threshold == 0 {
+		return ErrInvalidMaxFeeExposure
+	}
+
+	s.indexMtx.RLock()
+	defer s.indexMtx.RUnlock()
+
+	s.feeExposureLimit.Store(uint64(threshold))
+
+	for _, link := range s.linkIndex {
+		link.setMaxFeeExposure(threshold)
+	}
+	for _, link := range s.pendingLinkIndex {
+		link.setMaxFeeExposure(threshold)
+	}
+
+	log.Infof("Updated max fee exposure to %v", threshold)
+
+	return nil
+}
@@ -54,6 +54,10 @@ const (
 	// OutgoingFailureForwardsDisabled is returned when the switch is
 	// configured to disallow forwards.
 	OutgoingFailureForwardsDisabled
+
+	// OutgoingFailureForwardingRule is returned when a forward is rejected
+	// by one of the operator's forwarding rules.
+	OutgoingFailureForwardingRule
 )
 
 // FailureString returns the string representation of a failure detail.
@@ -91,6 +95,9 @@ func (fd OutgoingFailure) FailureString() string {
 	case OutgoingFailureForwardsDisabled:
 		return "node configured to disallow forwards"
 
+	case OutgoingFailureForwardingRule:
+		return "forward rejected by forwarding rule"
+
 	default:
 		return "unknown failure detail"
 	}
@@ -0,0 +1,331 @@
+package htlcswitch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"golang.org/x/time/rate"
+)
+
+var (
+	// ErrForwardDenied is returned when a forward matches a rule that
+	// denies it.
+	ErrForwardDenied = errors.New("forward denied")
+
+	// ErrForwardExposureExceeded is returned when a forward would exceed
+	// the maximum htlc exposure of the outgoing peer.
+	ErrForwardExposureExceeded = errors.New("maximum htlc exposure " +
+		"exceeded")
+
+	// ErrForwardRateExceeded is returned when a forward exceeds the rate
+	// limit of a rule.
+	ErrForwardRateExceeded = errors.New("forwarding rate exceeded")
+
+	// ErrForwardOutsideWindow is returned when a forward is attempted
+	// outside of the time of day window of a rule.
+	ErrForwardOutsideWindow = errors.New("forward outside of allowed " +
+		"time window")
+)
+
+// ForwardingRule is a rule that restricts the htlcs that we forward. A rule
+// applies to all forwards that match its peer and channel, if those are set.
+type ForwardingRule struct {
+	// Peer restricts the rule to forwards to the given peer.
+	Peer fn.Option[[33]byte]
+
+	// ChanID restricts the rule to forwards over the given outgoing
+	// channel.
+	ChanID fn.Option[lnwire.ShortChannelID]
+
+	// Deny rejects all matching forwards.
+	Deny bool
+
+	// MaxExposure is the maximum total amount of outgoing htlcs that may
+	// be pending with the outgoing peer. A value of zero disables the
+	// limit.
+	MaxExposure lnwire.MilliSatoshi
+
+	// MaxForwards is the maximum number of forwards per RateInterval. The
+	// limit is applied to the peer or channel that the rule is restricted
+	// to, and globally otherwise. A value of zero disables the limit.
+	MaxForwards uint32
+
+	// RateInterval is the interval that MaxForwards applies to.
+	RateInterval time.Duration
+
+	// WindowStart and WindowEnd define the time of day window, as offset
+	// from midnight UTC, during which matching forwards are allowed. The
+	// window may wrap around midnight. If both are zero, forwards are
+	// allowed at any time.
+	WindowStart time.Duration
+
+	// WindowEnd is the end of the time of day window.
+	WindowEnd time.Duration
+}
+
+// matches returns whether the rule applies to a forward over the given
+// channel to the given peer.
+func (r *ForwardingRule) matches(peer [33]byte,
+	chanID lnwire.ShortChannelID) bool {
+
+	peerMismatch := fn.MapOptionZ(r.Peer, func(p [33]byte) bool {
+		return p != peer
+	})
+	chanMismatch := fn.MapOptionZ(
+		r.ChanID, func(c lnwire.ShortChannelID) bool {
+			return c != chanID
+		},
+	)
+
+	return !peerMismatch && !chanMismatch
+}
+
+// inWindow returns whether the given time falls into the time of day window
+// of the rule.
+func (r *ForwardingRule) inWindow(now time.Time) bool {
+	if r.WindowStart == 0 && r.WindowEnd == 0 {
+		return true
+	}
+
+	now = now.UTC()
+	midnight := time.Date(
+		now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC,
+	)
+	offset := now.Sub(midnight)
+
+	if r.WindowStart <= r.WindowEnd {
+		return offset >= r.WindowStart && offset < r.WindowEnd
+	}
+
+	return offset >= r.WindowStart || offset < r.WindowEnd
+}
+
+// validate checks that the rule is well formed.
+func (r *ForwardingRule) validate() error {
+	const day = 24 * time.Hour
+
+	switch {
+	case r.MaxForwards > 0 && r.RateInterval <= 0:
+		return errors.New("rate interval must be positive")
+
+	case r.WindowStart < 0 || r.WindowStart >= day:
+		return fmt.Errorf("invalid window start %v", r.WindowStart)
+
+	case r.WindowEnd < 0 || r.WindowEnd >= day:
+		return fmt.Errorf("invalid window end %v", r.WindowEnd)
+	}
+
+	return nil
+}
+
+// exposureFunc returns the total amount of the pending outgoing htlcs of a
+// channel.
+type exposureFunc func() lnwire.MilliSatoshi
+
+// rateKey identifies the scope of a rate limit.
+type rateKey struct {
+	// rule is the index of the rule that defines the limit.
+	rule int
+
+	// peer is the peer that the limit applies to, if the rule is
+	// restricted to one.
+	peer [33]byte
+
+	// chanID is the channel that the limit applies to, if the rule is
+	// restricted to one.
+	chanID lnwire.ShortChannelID
+}
+
+// ForwardingRules is a policy layer that is evaluated by the outgoing link
+// before accepting an htlc for forwarding, on top of the channel's forwarding
+// policy. The rules can be replaced at runtime.
+type ForwardingRules struct {
+	clock clock.Clock
+
+	mu sync.Mutex
+
+	// rules are the currently active rules.
+	rules []ForwardingRule
+
+	// limiters are the rate limiters of the rules that define one. They
+	// are reset whenever the rules are replaced.
+	limiters map[rateKey]*rate.Limiter
+
+	// exposures holds the function that returns the pending outgoing htlc
+	// amount of every active channel, indexed by peer.
+	exposures map[[33]byte]map[lnwire.ChannelID]exposureFunc
+}
+
+// NewForwardingRules creates a forwarding rule engine without any rules.
+func NewForwardingRules(clock clock.Clock) *ForwardingRules {
+	return &ForwardingRules{
+		clock:    clock,
+		limiters: make(map[rateKey]*rate.Limiter),
+		exposures: make(
+			map[[33]byte]map[lnwire.ChannelID]exposureFunc,
+		),
+	}
+}
+
+// SetRules replaces the active rules. Rate limits start afresh.
+func (f *ForwardingRules) SetRules(rules []ForwardingRule) error {
+	for i := range rules {
+		if err := rules[i].validate(); err != nil {
+			return fmt.Errorf("invalid rule %d: %w", i, err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.rules = append([]ForwardingRule(nil), rules...)
+	f.limiters = make(map[rateKey]*rate.Limiter)
+
+	log.Infof("Applied %d forwarding rules", len(rules))
+
+	return nil
+}
+
+// Rules returns the active rules.
+func (f *ForwardingRules) Rules() []ForwardingRule {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]ForwardingRule(nil), f.rules...)
+}
+
+// registerChannel adds an active channel to a peer, whose pending outgoing
+// htlc amount is returned by the given function.
+func (f *ForwardingRules) registerChannel(peer [33]byte,
+	chanID lnwire.ChannelID, exposure exposureFunc) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	channels, ok := f.exposures[peer]
+	if !ok {
+		channels = make(map[lnwire.ChannelID]exposureFunc)
+		f.exposures[peer] = channels
+	}
+
+	channels[chanID] = exposure
+}
+
+// unregisterChannel removes a channel that is no longer active.
+func (f *ForwardingRules) unregisterChannel(peer [33]byte,
+	chanID lnwire.ChannelID) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.exposures[peer], chanID)
+	if len(f.exposures[peer]) == 0 {
+		delete(f.exposures, peer)
+	}
+}
+
+// peerExposure returns the total pending outgoing htlc amount with the peer.
+// The caller must hold the mutex.
+func (f *ForwardingRules) peerExposure(peer [33]byte) lnwire.MilliSatoshi {
+	var total lnwire.MilliSatoshi
+	for _, exposure := range f.exposures[peer] {
+		total += exposure()
+	}
+
+	return total
+}
+
+// checkForward evaluates the rules for a forward of the given amount over the
+// given channel to the given peer. A nil error is returned if the forward is
+// allowed. Checking a forward doesn't count against the rate limits, as the
+// switch may check several channels to the same peer for a single forward.
+func (f *ForwardingRules) checkForward(peer [33]byte,
+	chanID lnwire.ShortChannelID, amt lnwire.MilliSatoshi) error {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.clock.Now()
+	for i := range f.rules {
+		rule := &f.rules[i]
+		if !rule.matches(peer, chanID) {
+			continue
+		}
+
+		if rule.Deny {
+			return ErrForwardDenied
+		}
+
+		if !rule.inWindow(now) {
+			return ErrForwardOutsideWindow
+		}
+
+		if rule.MaxExposure > 0 &&
+			f.peerExposure(peer)+amt > rule.MaxExposure {
+
+			return ErrForwardExposureExceeded
+		}
+
+		if rule.MaxForwards == 0 {
+			continue
+		}
+
+		limiter := f.limiter(i, rule, peer, chanID)
+		if limiter.TokensAt(now) < 1 {
+			return ErrForwardRateExceeded
+		}
+	}
+
+	return nil
+}
+
+// recordForward counts a forward over the given channel to the given peer
+// against the rate limits of the matching rules.
+func (f *ForwardingRules) recordForward(peer [33]byte,
+	chanID lnwire.ShortChannelID) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.clock.Now()
+	for i := range f.rules {
+		rule := &f.rules[i]
+		if rule.MaxForwards == 0 || !rule.matches(peer, chanID) {
+			continue
+		}
+
+		f.limiter(i, rule, peer, chanID).AllowN(now, 1)
+	}
+}
+
+// limiter returns the rate limiter of the rule for a forward over the given
+// channel to the given peer. The caller must hold the mutex.
+func (f *ForwardingRules) limiter(idx int, rule *ForwardingRule,
+	peer [33]byte, chanID lnwire.ShortChannelID) *rate.Limiter {
+
+	key := rateKey{rule: idx}
+	if rule.Peer.IsSome() {
+		key.peer = peer
+	}
+	if rule.ChanID.IsSome() {
+		key.chanID = chanID
+	}
+
+	limiter, ok := f.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(
+			rate.Every(rule.RateInterval/time.Duration(
+				rule.MaxForwards,
+			)),
+			int(rule.MaxForwards),
+		)
+		f.limiters[key] = limiter
+	}
+
+	return limiter
+}
@@ -0,0 +1,121 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestForwardingRules tests the evaluation of the forwarding rules.
+func TestForwardingRules(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice   = [33]byte{1}
+		bob     = [33]byte{2}
+		aliceID = lnwire.ChannelID{1}
+		chan1   = lnwire.NewShortChanIDFromInt(1)
+		chan2   = lnwire.NewShortChanIDFromInt(2)
+	)
+
+	// Start at 10:00 UTC.
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	testClock := clock.NewTestClock(now)
+	rules := NewForwardingRules(testClock)
+
+	// Without any rules, all forwards are allowed.
+	require.NoError(t, rules.checkForward(alice, chan1, 1000))
+
+	// Malformed rules are rejected.
+	require.Error(t, rules.SetRules([]ForwardingRule{{
+		MaxForwards: 1,
+	}}))
+	require.Error(t, rules.SetRules([]ForwardingRule{{
+		WindowStart: 25 * time.Hour,
+	}}))
+
+	// Deny forwards over a single channel.
+	require.NoError(t, rules.SetRules([]ForwardingRule{{
+		ChanID: fn.Some(chan2),
+		Deny:   true,
+	}}))
+	require.NoError(t, rules.checkForward(alice, chan1, 1000))
+	require.ErrorIs(
+		t, rules.checkForward(alice, chan2, 1000), ErrForwardDenied,
+	)
+
+	// Only allow forwards to alice between 08:00 and 09:00 UTC.
+	require.NoError(t, rules.SetRules([]ForwardingRule{{
+		Peer:        fn.Some(alice),
+		WindowStart: 8 * time.Hour,
+		WindowEnd:   9 * time.Hour,
+	}}))
+	require.ErrorIs(
+		t, rules.checkForward(alice, chan1, 1000),
+		ErrForwardOutsideWindow,
+	)
+	require.NoError(t, rules.checkForward(bob, chan2, 1000))
+
+	testClock.SetTime(now.Add(-90 * time.Minute))
+	require.NoError(t, rules.checkForward(alice, chan1, 1000))
+
+	// A window that wraps around midnight.
+	require.NoError(t, rules.SetRules([]ForwardingRule{{
+		WindowStart: 22 * time.Hour,
+		WindowEnd:   2 * time.Hour,
+	}}))
+	require.ErrorIs(
+		t, rules.checkForward(alice, chan1, 1000),
+		ErrForwardOutsideWindow,
+	)
+	testClock.SetTime(now.Add(13 * time.Hour))
+	require.NoError(t, rules.checkForward(alice, chan1, 1000))
+	testClock.SetTime(now)
+
+	// Limit the pending htlc amount with alice.
+	exposure := lnwire.MilliSatoshi(1500)
+	rules.registerChannel(alice, aliceID, func() lnwire.MilliSatoshi {
+		return exposure
+	})
+	require.NoError(t, rules.SetRules([]ForwardingRule{{
+		Peer:        fn.Some(alice),
+		MaxExposure: 2000,
+	}}))
+	require.NoError(t, rules.checkForward(alice, chan1, 500))
+	require.ErrorIs(
+		t, rules.checkForward(alice, chan1, 501),
+		ErrForwardExposureExceeded,
+	)
+
+	// Once the channel is gone, its htlcs no longer count.
+	rules.unregisterChannel(alice, aliceID)
+	require.NoError(t, rules.checkForward(alice, chan1, 2000))
+
+	// Allow two forwards per minute to alice. Checking a forward
+	// doesn't consume the limit, only recording it does.
+	require.NoError(t, rules.SetRules([]ForwardingRule{{
+		Peer:         fn.Some(alice),
+		MaxForwards:  2,
+		RateInterval: time.Minute,
+	}}))
+	for i := 0; i < 2; i++ {
+		require.NoError(t, rules.checkForward(alice, chan1, 1000))
+		require.NoError(t, rules.checkForward(alice, chan1, 1000))
+		rules.recordForward(alice, chan1)
+	}
+	require.ErrorIs(
+		t, rules.checkForward(alice, chan1, 1000),
+		ErrForwardRateExceeded,
+	)
+
+	// Forwards to other peers aren't limited.
+	require.NoError(t, rules.checkForward(bob, chan2, 1000))
+
+	// After half a minute, another forward is allowed.
+	testClock.SetTime(now.Add(30 * time.Second))
+	require.NoError(t, rules.checkForward(alice, chan1, 1000))
+}
@@ -0,0 +1,221 @@
+package htlcswitch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrFwdPkgNotFound is returned when a forwarding package that should
+	// be reprocessed doesn't exist.
+	ErrFwdPkgNotFound = errors.New("forwarding package not found")
+
+	// ErrFwdPkgCompleted is returned when a forwarding package that should
+	// be reprocessed is already completed, so there's nothing left to do.
+	ErrFwdPkgCompleted = errors.New("forwarding package already completed")
+
+	// ErrFwdPkgQuiescent is returned when a forwarding package should be
+	// reprocessed while the channel is quiescent, which forbids sending
+	// any updates.
+	ErrFwdPkgQuiescent = errors.New("cannot reprocess forwarding " +
+		"package while channel is quiescent")
+)
+
+// FwdPkgInfo is a snapshot of the state of a forwarding package. A forwarding
+// package holds the updates the remote party locked in at a given height of
+// our local commitment chain, and tracks which of them have been forwarded to
+// the switch and acked.
+type FwdPkgInfo struct {
+	// Source is the short channel id of the channel the updates were
+	// received on.
+	Source lnwire.ShortChannelID
+
+	// Height is the height of the remote commitment chain at which the
+	// updates were locked in.
+	Height uint64
+
+	// State is the processing state of the package.
+	State channeldb.FwdState
+
+	// NumAdds is the number of adds in the package.
+	NumAdds int
+
+	// NumForwardedAdds is the number of adds that were forwarded to the
+	// switch, as opposed to being failed back or settled by us.
+	NumForwardedAdds int
+
+	// NumAckedAdds is the number of adds that have been fully resolved.
+	NumAckedAdds int
+
+	// NumSettleFails is the number of settles and fails in the package.
+	NumSettleFails int
+
+	// NumAckedSettleFails is the number of settles and fails that have
+	// been propagated to the incoming channel.
+	NumAckedSettleFails int
+}
+
+// countSet returns the number of elements that are set in the filter.
+func countSet(filter *channeldb.PkgFilter) int {
+	var n int
+	for i := uint16(0); i < filter.Count(); i++ {
+		if filter.Contains(i) {
+			n++
+		}
+	}
+
+	return n
+}
+
+// newFwdPkgInfo creates a snapshot of the given forwarding package.
+func newFwdPkgInfo(pkg *channeldb.FwdPkg) FwdPkgInfo {
+	return FwdPkgInfo{
+		Source:              pkg.Source,
+		Height:              pkg.Height,
+		State:               pkg.State,
+		NumAdds:             len(pkg.Adds),
+		NumForwardedAdds:    countSet(pkg.FwdFilter),
+		NumAckedAdds:        countSet(pkg.AckFilter),
+		NumSettleFails:      len(pkg.SettleFails),
+		NumAckedSettleFails: countSet(pkg.SettleFailFilter),
+	}
+}
+
+// fwdPkgReq is a request to reprocess the forwarding package at the given
+// height.
+type fwdPkgReq struct {
+	height uint64
+	resp   chan error
+}
+
+// ForwardingPackages returns a snapshot of all forwarding packages of the
+// channel that haven't been removed yet.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) ForwardingPackages() ([]FwdPkgInfo, error) {
+	pkgs, err := l.channel.LoadFwdPkgs()
+	if err != nil {
+		return nil, err
+	}
+
+	return fn.Map(newFwdPkgInfo, pkgs), nil
+}
+
+// ReprocessFwdPkg replays the forwarding package at the given height, as is
+// done for all forwarding packages when the link starts. The adds of the
+// package are forwarded to the switch again, and its settles and fails are
+// propagated again. Replaying a package is safe, since the switch and the
+// circuit map filter out any duplicates. This allows recovering a package
+// that is wedged without restarting the link.
+//
+// NOTE: Part of the ChannelLink interface.
+func (l *channelLink) ReprocessFwdPkg(ctx context.Context,
+	height uint64) error {
+
+	// The request is buffered, as it is resolved by the htlcManager while
+	// we might have given up on it already.
+	req := fwdPkgReq{
+		height: height,
+		resp:   make(chan error, 1),
+	}
+
+	select {
+	case l.fwdPkgReqs <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.Quit:
+		return ErrLinkShuttingDown
+	}
+
+	select {
+	case err := <-req.resp:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.Quit:
+		return ErrLinkShuttingDown
+	}
+}
+
+// handleFwdPkgReq reprocesses the requested forwarding package. This MUST be
+// called from the htlcManager goroutine.
+func (l *channelLink) handleFwdPkgReq(req fwdPkgReq) error {
+	if !l.quiescer.canSendUpdates() {
+		return ErrFwdPkgQuiescent
+	}
+
+	pkgs, err := l.channel.LoadFwdPkgs()
+	if err != nil {
+		return fmt.Errorf("unable to load forwarding packages: %w", err)
+	}
+
+	var pkg *channeldb.FwdPkg
+	for _, p := range pkgs {
+		if p.Height == req.height {
+			pkg = p
+			break
+		}
+	}
+
+	switch {
+	case pkg == nil:
+		return ErrFwdPkgNotFound
+
+	// A completed package will be removed by the garbage collector, and
+	// must not be replayed.
+	case pkg.State == channeldb.FwdStateCompleted:
+		return ErrFwdPkgCompleted
+	}
+
+	l.log.Infof("Reprocessing forwarding package %v", pkg)
+
+	if err := l.resolveFwdPkg(pkg); err != nil {
+		return err
+	}
+
+	// If replaying the package resulted in any updates, we initiate a
+	// state transition to capture them.
+	numUpdates := l.channel.NumPendingUpdates(lntypes.Local, lntypes.Remote)
+	if numUpdates > 0 && !l.updateCommitTxOrFail() {
+		return errors.New("unable to update commitment")
+	}
+
+	return nil
+}
+
+// ForwardingPackages returns a snapshot of all forwarding packages of the
+// channel with the given channel id.
+func (s *Switch) ForwardingPackages(
+	chanID lnwire.ChannelID) ([]FwdPkgInfo, error) {
+
+	s.indexMtx.RLock()
+	link, err := s.getLink(chanID)
+	s.indexMtx.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return link.ForwardingPackages()
+}
+
+// ReprocessForwardingPackage replays the forwarding package at the given
+// height of the channel with the given channel id. It blocks until the
+// package has been replayed.
+func (s *Switch) ReprocessForwardingPackage(ctx context.Context,
+	chanID lnwire.ChannelID, height uint64) error {
+
+	s.indexMtx.RLock()
+	link, err := s.getLink(chanID)
+	s.indexMtx.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	return link.ReprocessFwdPkg(ctx, height)
+}
@@ -56,6 +56,13 @@ import (
 // have a zero outgoing circuit key because the htlc terminates at our
 // node, and sends from our node will have a zero incoming circuit key because
 // the send originates at our node.
+//
+// Every event also carries a correlation id which is shared by all events of
+// a single htlc, from its forward through to its settle or failure and, for
+// htlcs that we received, its final resolution. For forwards and receives
+// this is the incoming circuit key of the htlc. Sends use the source hop and
+// the attempt id of the payment, so that the events of failed local sends can
+// be told apart even if they never got an outgoing htlc id.
 type HtlcNotifier struct {
 	started sync.Once
 	stopped sync.Once
@@ -219,6 +226,9 @@ type ForwardingEvent struct {
 	// forwarding event with subsequent settle/fail events.
 	HtlcKey
 
+	// CorrelationID is shared by all events of the htlc.
+	CorrelationID CircuitKey
+
 	// HtlcInfo contains details about the htlc.
 	HtlcInfo
 
@@ -240,6 +250,9 @@ type LinkFailEvent struct {
 	// HtlcKey uniquely identifies the htlc.
 	HtlcKey
 
+	// CorrelationID is shared by all events of the htlc.
+	CorrelationID CircuitKey
+
 	// HtlcInfo contains details about the htlc.
 	HtlcInfo
 
@@ -260,15 +273,25 @@ type LinkFailEvent struct {
 
 // ForwardingFailEvent represents a htlc failure which occurred down the line
 // after we forwarded a htlc onwards. An error is not included in this event
-// because errors returned down the route are encrypted. HtlcInfo is not
-// reliably available for forwarding failures, so it is omitted. These events
-// should be matched with their corresponding forward event to obtain this
-// information.
+// because errors returned down the route are encrypted. Only the amounts of
+// the htlc are reliably available for forwarding failures, the remaining
+// details should be obtained from the corresponding forward event.
 type ForwardingFailEvent struct {
 	// HtlcKey uniquely identifies the htlc, and can be used to match the
 	// htlc with its corresponding forwarding event.
 	HtlcKey
 
+	// CorrelationID is shared by all events of the htlc.
+	CorrelationID CircuitKey
+
+	// HtlcInfo contains the amounts of the htlc.
+	HtlcInfo
+
+	// Resolution is true if the htlc was failed back because its
+	// outgoing htlc was resolved on chain, rather than by a failure that
+	// our peer sent us.
+	Resolution bool
+
 	// HtlcEventType classifies the event as part of a local send or
 	// receive, or as part of a forward.
 	HtlcEventType
@@ -277,15 +300,21 @@ type ForwardingFailEvent struct {
 	Timestamp time.Time
 }
 
-// SettleEvent represents a htlc that was settled. HtlcInfo is not reliably
-// available for forwarding failures, so it is omitted. These events should
-// be matched with corresponding forward events or invoices (for receives)
-// to obtain additional information about the htlc.
+// SettleEvent represents a htlc that was settled. Only the amounts of the
+// htlc are reliably available for settles of forwards and sends, these events
+// should be matched with corresponding forward events to obtain additional
+// information about the htlc.
 type SettleEvent struct {
 	// HtlcKey uniquely identifies the htlc, and can be used to match
 	// forwards with their corresponding forwarding event.
 	HtlcKey
 
+	// CorrelationID is shared by all events of the htlc.
+	CorrelationID CircuitKey
+
+	// HtlcInfo contains the amounts of the htlc.
+	HtlcInfo
+
 	// Preimage that was released for settling the htlc.
 	Preimage lntypes.Preimage
 
@@ -297,9 +326,17 @@ type SettleEvent struct {
 	Timestamp time.Time
 }
 
+// FinalHtlcEvent represents the final resolution of an htlc that we received,
+// either off-chain or on-chain.
 type FinalHtlcEvent struct {
+	// CircuitKey identifies the incoming htlc.
 	CircuitKey
 
+	// CorrelationID is shared by all events of the htlc. For final
+	// events, this is always the incoming circuit key.
+	CorrelationID CircuitKey
+
+	// Settled is true if the htlc was settled, and false if it failed.
 	Settled bool
 
 	// Offchain is indicating whether the htlc was resolved off-chain.
@@ -313,11 +350,12 @@ type FinalHtlcEvent struct {
 // forwarded.
 //
 // Note this is part of the htlcNotifier interface.
-func (h *HtlcNotifier) NotifyForwardingEvent(key HtlcKey, info HtlcInfo,
-	eventType HtlcEventType) {
+func (h *HtlcNotifier) NotifyForwardingEvent(id CircuitKey, key HtlcKey,
+	info HtlcInfo, eventType HtlcEventType) {
 
 	event := &ForwardingEvent{
 		HtlcKey:       key,
+		CorrelationID: id,
 		HtlcInfo:      info,
 		HtlcEventType: eventType,
 		Timestamp:     h.now(),
@@ -335,11 +373,13 @@ func (h *HtlcNotifier) NotifyForwardingEvent(key HtlcKey, info HtlcInfo,
 // or outgoing link.
 //
 // Note this is part of the htlcNotifier interface.
-func (h *HtlcNotifier) NotifyLinkFailEvent(key HtlcKey, info HtlcInfo,
-	eventType HtlcEventType, linkErr *LinkError, incoming bool) {
+func (h *HtlcNotifier) NotifyLinkFailEvent(id CircuitKey, key HtlcKey,
+	info HtlcInfo, eventType HtlcEventType, linkErr *LinkError,
+	incoming bool) {
 
 	event := &LinkFailEvent{
 		HtlcKey:       key,
+		CorrelationID: id,
 		HtlcInfo:      info,
 		HtlcEventType: eventType,
 		LinkError:     linkErr,
@@ -359,11 +399,14 @@ func (h *HtlcNotifier) NotifyLinkFailEvent(key HtlcKey, info HtlcInfo,
 // forwarded has failed down the line.
 //
 // Note this is part of the htlcNotifier interface.
-func (h *HtlcNotifier) NotifyForwardingFailEvent(key HtlcKey,
-	eventType HtlcEventType) {
+func (h *HtlcNotifier) NotifyForwardingFailEvent(id CircuitKey, key HtlcKey,
+	info HtlcInfo, eventType HtlcEventType, resolution bool) {
 
 	event := &ForwardingFailEvent{
 		HtlcKey:       key,
+		CorrelationID: id,
+		HtlcInfo:      info,
+		Resolution:    resolution,
 		HtlcEventType: eventType,
 		Timestamp:     h.now(),
 	}
@@ -380,11 +423,13 @@ func (h *HtlcNotifier) NotifyForwardingFailEvent(key HtlcKey,
 // to as part of a forward or a receive to our node has been settled.
 //
 // Note this is part of the htlcNotifier interface.
-func (h *HtlcNotifier) NotifySettleEvent(key HtlcKey,
-	preimage lntypes.Preimage, eventType HtlcEventType) {
+func (h *HtlcNotifier) NotifySettleEvent(id CircuitKey, key HtlcKey,
+	info HtlcInfo, preimage lntypes.Preimage, eventType HtlcEventType) {
 
 	event := &SettleEvent{
 		HtlcKey:       key,
+		CorrelationID: id,
+		HtlcInfo:      info,
 		Preimage:      preimage,
 		HtlcEventType: eventType,
 		Timestamp:     h.now(),
@@ -405,10 +450,11 @@ func (h *HtlcNotifier) NotifyFinalHtlcEvent(key models.CircuitKey,
 	info channeldb.FinalHtlcInfo) {
 
 	event := &FinalHtlcEvent{
-		CircuitKey: key,
-		Settled:    info.Settled,
-		Offchain:   info.Offchain,
-		Timestamp:  h.now(),
+		CircuitKey:    key,
+		CorrelationID: key,
+		Settled:       info.Settled,
+		Offchain:      info.Offchain,
+		Timestamp:     h.now(),
 	}
 
 	log.Tracef("Notifying final settle event: %v", key)
@@ -447,6 +493,13 @@ func newHtlcKey(pkt *htlcPacket) HtlcKey {
 	return htlcKey
 }
 
+// newCorrelationID returns the correlation id of the htlc that the packet
+// belongs to. This is the incoming circuit key of the packet, which holds the
+// attempt id in the incoming htlc id for our own sends.
+func newCorrelationID(pkt *htlcPacket) CircuitKey {
+	return pkt.inKey()
+}
+
 // newHtlcInfo returns HtlcInfo for the packet provided.
 func newHtlcInfo(pkt *htlcPacket) HtlcInfo {
 	return HtlcInfo{
@@ -457,6 +510,21 @@ func newHtlcInfo(pkt *htlcPacket) HtlcInfo {
 	}
 }
 
+// newResolvedHtlcInfo returns HtlcInfo for a settle or fail packet. The
+// amounts of the htlc are taken from its circuit if the packet has one,
+// because settle and fail packets don't carry the amounts of the add that
+// they resolve.
+func newResolvedHtlcInfo(pkt *htlcPacket) HtlcInfo {
+	if pkt.circuit == nil {
+		return newHtlcInfo(pkt)
+	}
+
+	return HtlcInfo{
+		IncomingAmt: pkt.circuit.IncomingAmount,
+		OutgoingAmt: pkt.circuit.OutgoingAmount,
+	}
+}
+
 // getEventType returns the htlc type based on the fields set in the htlc
 // packet. Sends that originate at our node have the source (zero) incoming
 // channel ID. Receives to our node have the exit (zero) outgoing channel ID
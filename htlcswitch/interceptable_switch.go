@@ -66,6 +66,17 @@ type InterceptableSwitch struct {
 	// heldHtlcSet keeps track of outstanding intercepted forwards.
 	heldHtlcSet *heldHtlcSet
 
+	// zeroConfHoldThreshold is the amount above which htlcs that come in
+	// over an unconfirmed zero-conf channel are held.
+	zeroConfHoldThreshold lnwire.MilliSatoshi
+
+	// zeroConfHeld keeps track of the htlcs that are held until their
+	// incoming zero-conf channel confirms.
+	zeroConfHeld *heldHtlcSet
+
+	// zeroConfConfirmed is signaled when a zero-conf channel confirmed.
+	zeroConfConfirmed chan struct{}
+
 	// cltvRejectDelta defines the number of blocks before the expiry of the
 	// htlc where we no longer intercept it and instead cancel it back.
 	cltvRejectDelta uint32
@@ -74,8 +85,8 @@ type InterceptableSwitch struct {
 	// the htlc where we don't intercept anymore. This value must be greater
 	// than CltvRejectDelta, because we don't want to offer htlcs to the
 	// interceptor client for which there is no time left to resolve them
-	// anymore.
-	cltvInterceptDelta uint32
+	// anymore. It can be changed at runtime, so it is accessed atomically.
+	cltvInterceptDelta atomic.Uint32
 
 	// notifier is an instance of a chain notifier that we'll use to signal
 	// the switch when a new block has arrived.
@@ -137,6 +148,11 @@ type FwdResolution struct {
 	// Action is FwdActionResumeModified.
 	OutAmountMsat fn.Option[lnwire.MilliSatoshi]
 
+	// OutExpiry is the absolute expiry height that is to be used for the
+	// outgoing htlc if Action is FwdActionResumeModified. It must be below
+	// the expiry of the incoming htlc.
+	OutExpiry fn.Option[uint32]
+
 	// OutWireCustomRecords is the custom records that are to be used for
 	// forwarding if Action is FwdActionResumeModified.
 	OutWireCustomRecords fn.Option[lnwire.CustomRecords]
@@ -151,8 +167,8 @@ type FwdResolution struct {
 }
 
 type fwdResolution struct {
-	resolution *FwdResolution
-	errChan    chan error
+	resolutions []*FwdResolution
+	errChan     chan []error
 }
 
 // InterceptableSwitchConfig contains the configuration of InterceptableSwitch.
@@ -180,6 +196,11 @@ type InterceptableSwitchConfig struct {
 	// RequireInterceptor indicates whether processing should block if no
 	// interceptor is connected.
 	RequireInterceptor bool
+
+	// ZeroConfHoldThreshold is the amount above which htlcs that come in
+	// over a zero-conf channel funded by the peer are held until the
+	// channel confirms. A value of zero disables the holds.
+	ZeroConfHoldThreshold lnwire.MilliSatoshi
 }
 
 // NewInterceptableSwitch returns an instance of InterceptableSwitch.
@@ -192,20 +213,47 @@ func NewInterceptableSwitch(cfg *InterceptableSwitchConfig) (
 			cfg.CltvInterceptDelta, cfg.CltvRejectDelta)
 	}
 
-	return &InterceptableSwitch{
+	s := &InterceptableSwitch{
 		htlcSwitch:              cfg.Switch,
 		intercepted:             make(chan *interceptedPackets),
 		onchainIntercepted:      make(chan InterceptedForward),
 		interceptorRegistration: make(chan ForwardInterceptor),
 		heldHtlcSet:             newHeldHtlcSet(),
+		zeroConfHoldThreshold:   cfg.ZeroConfHoldThreshold,
+		zeroConfHeld:            newHeldHtlcSet(),
+		zeroConfConfirmed:       make(chan struct{}, 1),
 		resolutionChan:          make(chan *fwdResolution),
 		requireInterceptor:      cfg.RequireInterceptor,
 		cltvRejectDelta:         cfg.CltvRejectDelta,
-		cltvInterceptDelta:      cfg.CltvInterceptDelta,
 		notifier:                cfg.Notifier,
 
 		quit: make(chan struct{}),
-	}, nil
+	}
+	s.cltvInterceptDelta.Store(cfg.CltvInterceptDelta)
+
+	return s, nil
+}
+
+// CltvInterceptDelta returns the number of blocks before the expiry of an htlc
+// where we don't intercept it anymore.
+func (s *InterceptableSwitch) CltvInterceptDelta() uint32 {
+	return s.cltvInterceptDelta.Load()
+}
+
+// SetCltvInterceptDelta changes the number of blocks before the expiry of an
+// htlc where we don't intercept it anymore. This gives the interceptor client
+// more or less time to resolve the htlcs it is offered. Htlcs that are already
+// held aren't affected, as they are failed back once they reach the cltv
+// reject delta.
+func (s *InterceptableSwitch) SetCltvInterceptDelta(delta uint32) error {
+	if delta <= s.cltvRejectDelta {
+		return fmt.Errorf("cltv intercept delta %v not greater than "+
+			"cltv reject delta %v", delta, s.cltvRejectDelta)
+	}
+
+	s.cltvInterceptDelta.Store(delta)
+
+	return nil
 }
 
 // SetInterceptor sets the ForwardInterceptor to be used. A nil argument
@@ -330,7 +378,11 @@ func (s *InterceptableSwitch) run() error {
 			}
 
 		case res := <-s.resolutionChan:
-			res.errChan <- s.resolve(res.resolution)
+			errs := make([]error, len(res.resolutions))
+			for i, resolution := range res.resolutions {
+				errs[i] = s.resolve(resolution)
+			}
+			res.errChan <- errs
 
 		case currentBlock, ok := <-s.blockEpochStream.Epochs:
 			if !ok {
@@ -343,6 +395,13 @@ func (s *InterceptableSwitch) run() error {
 			// expire at this height to prevent channel force-close.
 			s.failExpiredHtlcs()
 
+			// The block may have confirmed the zero-conf channels
+			// of held htlcs.
+			s.releaseZeroConfHolds()
+
+		case <-s.zeroConfConfirmed:
+			s.releaseZeroConfHolds()
+
 		case <-s.quit:
 			return nil
 		}
@@ -350,17 +409,15 @@ func (s *InterceptableSwitch) run() error {
 }
 
 func (s *InterceptableSwitch) failExpiredHtlcs() {
-	s.heldHtlcSet.popAutoFails(
-		uint32(s.currentHeight),
-		func(fwd InterceptedForward) {
-			err := fwd.FailWithCode(
-				lnwire.CodeTemporaryChannelFailure,
-			)
-			if err != nil {
-				log.Errorf("Cannot fail packet: %v", err)
-			}
-		},
-	)
+	failHtlc := func(fwd InterceptedForward) {
+		err := fwd.FailWithCode(lnwire.CodeTemporaryChannelFailure)
+		if err != nil {
+			log.Errorf("Cannot fail packet: %v", err)
+		}
+	}
+
+	s.heldHtlcSet.popAutoFails(uint32(s.currentHeight), failHtlc)
+	s.zeroConfHeld.popAutoFails(uint32(s.currentHeight), failHtlc)
 }
 
 func (s *InterceptableSwitch) sendForward(fwd InterceptedForward) {
@@ -419,7 +476,7 @@ func (s *InterceptableSwitch) resolve(res *FwdResolution) error {
 
 	case FwdActionResumeModified:
 		return intercepted.ResumeModified(
-			res.InAmountMsat, res.OutAmountMsat,
+			res.InAmountMsat, res.OutAmountMsat, res.OutExpiry,
 			res.OutWireCustomRecords,
 		)
 
@@ -440,24 +497,39 @@ func (s *InterceptableSwitch) resolve(res *FwdResolution) error {
 
 // Resolve resolves an intercepted packet.
 func (s *InterceptableSwitch) Resolve(res *FwdResolution) error {
+	errs, err := s.ResolveBatch([]*FwdResolution{res})
+	if err != nil {
+		return err
+	}
+
+	return errs[0]
+}
+
+// ResolveBatch resolves a batch of intercepted packets in a single round trip
+// to the main loop. The returned slice holds the result of every resolution,
+// in the order they were passed in. The error is only set if the batch
+// couldn't be processed at all.
+func (s *InterceptableSwitch) ResolveBatch(res []*FwdResolution) ([]error,
+	error) {
+
 	internalRes := &fwdResolution{
-		resolution: res,
-		errChan:    make(chan error, 1),
+		resolutions: res,
+		errChan:     make(chan []error, 1),
 	}
 
 	select {
 	case s.resolutionChan <- internalRes:
 
 	case <-s.quit:
-		return errors.New("switch shutting down")
+		return nil, errors.New("switch shutting down")
 	}
 
 	select {
-	case err := <-internalRes.errChan:
-		return err
+	case errs := <-internalRes.errChan:
+		return errs, nil
 
 	case <-s.quit:
-		return errors.New("switch shutting down")
+		return nil, errors.New("switch shutting down")
 	}
 }
 
@@ -542,6 +614,13 @@ func (s *InterceptableSwitch) interceptForward(packet *htlcPacket,
 			return true, nil
 		}
 
+		// Hold large htlcs that come in over a zero-conf channel
+		// until it confirms.
+		held, err := s.holdZeroConf(intercepted)
+		if err != nil || held {
+			return held, err
+		}
+
 		return s.forward(intercepted, isReplay)
 
 	default:
@@ -609,7 +688,7 @@ func (s *InterceptableSwitch) handleExpired(fwd *interceptedForward) (
 	bool, error) {
 
 	height := uint32(s.currentHeight)
-	if fwd.packet.incomingTimeout >= height+s.cltvInterceptDelta {
+	if fwd.packet.incomingTimeout >= height+s.cltvInterceptDelta.Load() {
 		return false, nil
 	}
 
@@ -669,13 +748,30 @@ func (f *interceptedForward) Resume() error {
 // ResumeModified resumes the default behavior with field modifications. The
 // input amount (if provided) specifies that the value of the inbound HTLC
 // should be interpreted differently from the on-chain amount during further
-// validation. The presence of an output amount and/or custom records indicates
-// that those values should be modified on the outgoing HTLC.
+// validation. The presence of an output amount, expiry and/or custom records
+// indicates that those values should be modified on the outgoing HTLC.
 func (f *interceptedForward) ResumeModified(
 	inAmountMsat fn.Option[lnwire.MilliSatoshi],
 	outAmountMsat fn.Option[lnwire.MilliSatoshi],
+	outExpiry fn.Option[uint32],
 	outWireCustomRecords fn.Option[lnwire.CustomRecords]) error {
 
+	// The outgoing htlc must expire before the incoming one, otherwise we
+	// could be forced to pay out downstream without being able to claim
+	// the incoming htlc in time.
+	err := fn.MapOptionZ(outExpiry, func(expiry uint32) error {
+		if expiry >= f.packet.incomingTimeout {
+			return fmt.Errorf("outgoing expiry %v not below "+
+				"incoming expiry %v", expiry,
+				f.packet.incomingTimeout)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
 	// Convert the optional custom records to the correct type and validate
 	// them.
 	validatedRecords, err := fn.MapOptionZ(
@@ -715,6 +811,11 @@ func (f *interceptedForward) ResumeModified(
 			htlc.Amount = amount
 		})
 
+		outExpiry.WhenSome(func(expiry uint32) {
+			f.packet.outgoingTimeout = expiry
+			htlc.Expiry = expiry
+		})
+
 		if len(validatedRecords) > 0 {
 			htlc.CustomRecords = validatedRecords
 		}
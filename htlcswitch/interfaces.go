@@ -78,6 +78,14 @@ type dustHandler interface {
 	// getCommitFee returns the commitment fee in satoshis from either the
 	// local or remote commitment. This does not include dust.
 	getCommitFee(remote bool) btcutil.Amount
+
+	// getMaxFeeExposure returns the threshold after which dust htlcs and
+	// fee updates are restricted.
+	getMaxFeeExposure() lnwire.MilliSatoshi
+
+	// setMaxFeeExposure updates the threshold after which dust htlcs and
+	// fee updates are restricted.
+	setMaxFeeExposure(threshold lnwire.MilliSatoshi)
 }
 
 // scidAliasHandler is an interface that the ChannelLink implements so it can
@@ -108,6 +116,9 @@ type scidAliasHandler interface {
 	// zeroConfConfirmed returns whether or not the zero-conf channel has
 	// confirmed.
 	zeroConfConfirmed() bool
+
+	// isInitiator returns whether or not we funded the channel.
+	isInitiator() bool
 }
 
 // ChannelUpdateHandler is an interface that provides methods that allow
@@ -170,6 +181,16 @@ type ChannelUpdateHandler interface {
 	// will only ever be called once. If no CommitSig is owed in the
 	// argument's LinkDirection, then we will call this hook immediately.
 	OnCommitOnce(LinkDirection, func())
+
+	// Quiesce requests the channel to be quiesced using the stfu
+	// protocol. It blocks until the channel is quiescent, and returns the
+	// initiator of the quiescence session.
+	Quiesce(ctx context.Context) (lntypes.ChannelParty, error)
+
+	// Resume ends the quiescence session of the channel, allowing both
+	// parties to send updates again. It must be called once the protocol
+	// that required the channel to be quiescent is done.
+	Resume(ctx context.Context) error
 }
 
 // CommitHookID is a value that is used to uniquely identify hooks in the
@@ -290,6 +311,14 @@ type ChannelLink interface {
 	// commitment of the channel that this link is associated with.
 	CommitmentCustomBlob() fn.Option[tlv.Blob]
 
+	// ForwardingPackages returns a snapshot of all forwarding packages of
+	// the channel that haven't been removed yet.
+	ForwardingPackages() ([]FwdPkgInfo, error)
+
+	// ReprocessFwdPkg replays the forwarding package at the given height.
+	// It blocks until the package has been replayed.
+	ReprocessFwdPkg(ctx context.Context, height uint64) error
+
 	// Start/Stop are used to initiate the start/stop of the channel link
 	// functioning.
 	Start() error
@@ -332,6 +361,10 @@ type InterceptableHtlcForwarder interface {
 
 	// Resolve resolves an intercepted packet.
 	Resolve(res *FwdResolution) error
+
+	// ResolveBatch resolves a batch of intercepted packets and returns
+	// the result of every resolution in the order they were passed in.
+	ResolveBatch(res []*FwdResolution) ([]error, error)
 }
 
 // ForwardInterceptor is a function that is invoked from the switch for every
@@ -403,6 +436,7 @@ type InterceptedForward interface {
 	// forward with modified fields.
 	ResumeModified(inAmountMsat,
 		outAmountMsat fn.Option[lnwire.MilliSatoshi],
+		outExpiry fn.Option[uint32],
 		outWireCustomRecords fn.Option[lnwire.CustomRecords]) error
 
 	// Settle notifies the intention to settle an existing hold
@@ -424,25 +458,26 @@ type InterceptedForward interface {
 // it is not needed outside of the htlcSwitch package.
 type htlcNotifier interface {
 	// NotifyForwardingEvent notifies the HtlcNotifier than a htlc has been
-	// forwarded.
-	NotifyForwardingEvent(key HtlcKey, info HtlcInfo,
+	// forwarded. The id correlates all events of the htlc.
+	NotifyForwardingEvent(id CircuitKey, key HtlcKey, info HtlcInfo,
 		eventType HtlcEventType)
 
 	// NotifyIncomingLinkFailEvent notifies that a htlc has failed on our
 	// incoming link. It takes an isReceive bool to differentiate between
 	// our node's receives and forwards.
-	NotifyLinkFailEvent(key HtlcKey, info HtlcInfo,
+	NotifyLinkFailEvent(id CircuitKey, key HtlcKey, info HtlcInfo,
 		eventType HtlcEventType, linkErr *LinkError, incoming bool)
 
 	// NotifyForwardingFailEvent notifies the HtlcNotifier that a htlc we
 	// forwarded has failed down the line.
-	NotifyForwardingFailEvent(key HtlcKey, eventType HtlcEventType)
+	NotifyForwardingFailEvent(id CircuitKey, key HtlcKey, info HtlcInfo,
+		eventType HtlcEventType, resolution bool)
 
 	// NotifySettleEvent notifies the HtlcNotifier that a htlc that we
 	// committed to as part of a forward or a receive to our node has been
 	// settled.
-	NotifySettleEvent(key HtlcKey, preimage lntypes.Preimage,
-		eventType HtlcEventType)
+	NotifySettleEvent(id CircuitKey, key HtlcKey, info HtlcInfo,
+		preimage lntypes.Preimage, eventType HtlcEventType)
 
 	// NotifyFinalHtlcEvent notifies the HtlcNotifier that the final outcome
 	// for an htlc has been determined.
@@ -2,6 +2,7 @@ package htlcswitch
 
 import (
 	"bytes"
+	"context"
 	crand "crypto/rand"
 	"crypto/sha256"
 	"errors"
@@ -199,6 +200,13 @@ type ChannelLinkConfig struct {
 	// before we do a state update.
 	BatchSize uint32
 
+	// SettleBatchWindow is the maximum duration that settles and fails are
+	// held before signing a new commitment that includes them. This allows
+	// resolutions that arrive in quick succession to be coalesced into a
+	// single signature round. The batch is signed early once BatchSize
+	// updates are pending. If zero, a new commitment is signed right away.
+	SettleBatchWindow time.Duration
+
 	// UnsafeReplay will cause a link to replay the adds in its latest
 	// commitment txn after the link is restarted. This should only be used
 	// in testing, it is here to ensure the sphinx replay detection on the
@@ -285,6 +293,15 @@ type ChannelLinkConfig struct {
 	// MaxFeeExposure is the threshold in milli-satoshis after which we'll
 	// restrict the flow of HTLCs and fee updates.
 	MaxFeeExposure lnwire.MilliSatoshi
+
+	// QuiescenceTimeout is the maximum duration of a quiescence session,
+	// after which we disconnect from the peer to unlock the channel.
+	QuiescenceTimeout time.Duration
+
+	// ForwardingRules is an optional set of operator defined rules that
+	// htlcs forwarded over this link must satisfy in addition to the
+	// forwarding policy.
+	ForwardingRules *ForwardingRules
 }
 
 // channelLink is the service which drives a channel's commitment update
@@ -348,6 +365,10 @@ type channelLink struct {
 	// commitment fee every time it fires.
 	updateFeeTimer *time.Timer
 
+	// settleBatchTimeout fires once the settles and fails that are held
+	// for batching need to be signed. It is nil if no batch is pending.
+	settleBatchTimeout <-chan time.Time
+
 	// uncommittedPreimages stores a list of all preimages that have been
 	// learned since receiving the last CommitSig from the remote peer. The
 	// batch will be flushed just before accepting the subsequent CommitSig
@@ -387,6 +408,30 @@ type channelLink struct {
 	// our next CommitSig.
 	incomingCommitHooks hookMap
 
+	// quiescer is the state machine that tracks our progress in the
+	// quiescence protocol.
+	quiescer *quiescer
+
+	// quiescenceReqs is a queue of requests to quiesce this link. The
+	// request is resolved once the channel is quiescent.
+	quiescenceReqs chan stfuReq
+
+	// resumeReqs is a queue of requests to end the quiescence session of
+	// this link.
+	resumeReqs chan chan error
+
+	// quiescenceTimeout is signaled once a quiescence session exceeds
+	// its timeout.
+	quiescenceTimeout chan struct{}
+
+	// fwdPkgReqs is a queue of requests to reprocess a forwarding package.
+	fwdPkgReqs chan fwdPkgReq
+
+	// feeExposureLimit is the threshold in milli-satoshis after which
+	// we'll restrict the flow of HTLCs and fee updates. It is initialized
+	// from the config, and can be updated by the switch at runtime.
+	feeExposureLimit atomic.Uint64
+
 	// ContextGuard is a helper that encapsulates a wait group and quit
 	// channel and allows contexts that either block or cancel on those
 	// depending on the use case.
@@ -462,7 +507,39 @@ func NewChannelLink(cfg ChannelLinkConfig,
 		cfg.MaxFeeExposure = DefaultMaxFeeExposure
 	}
 
-	return &channelLink{
+	// If the quiescence timeout isn't set, use the default.
+	if cfg.QuiescenceTimeout == 0 {
+		cfg.QuiescenceTimeout = DefaultQuiescenceTimeout
+	}
+
+	channelInitiator := lntypes.Remote
+	if channel.IsInitiator() {
+		channelInitiator = lntypes.Local
+	}
+
+	// The timeout is handled by the htlcManager, so the signal is
+	// buffered to not block the timer.
+	quiescenceTimeout := make(chan struct{}, 1)
+
+	qsm := newQuiescer(QuiescerCfg{
+		chanID: lnwire.NewChanIDFromOutPoint(
+			channel.ChannelPoint(),
+		),
+		channelInitiator:  channelInitiator,
+		numPendingUpdates: channel.NumPendingUpdates,
+		sendMsg: func(stfu lnwire.Stfu) error {
+			return cfg.Peer.SendMessage(false, &stfu)
+		},
+		timeout: cfg.QuiescenceTimeout,
+		onTimeout: func() {
+			select {
+			case quiescenceTimeout <- struct{}{}:
+			default:
+			}
+		},
+	})
+
+	l := &channelLink{
 		cfg:                 cfg,
 		channel:             channel,
 		hodlMap:             make(map[models.CircuitKey]hodlHtlc),
@@ -471,8 +548,16 @@ func NewChannelLink(cfg ChannelLinkConfig,
 		flushHooks:          newHookMap(),
 		outgoingCommitHooks: newHookMap(),
 		incomingCommitHooks: newHookMap(),
+		quiescer:            qsm,
+		quiescenceReqs:      make(chan stfuReq),
+		resumeReqs:          make(chan chan error),
+		quiescenceTimeout:   quiescenceTimeout,
+		fwdPkgReqs:          make(chan fwdPkgReq),
 		ContextGuard:        fn.NewContextGuard(),
 	}
+	l.feeExposureLimit.Store(uint64(cfg.MaxFeeExposure))
+
+	return l
 }
 
 // A compile time check to ensure channelLink implements the ChannelLink
@@ -503,6 +588,12 @@ func (l *channelLink) Start() error {
 		}
 	}
 
+	if l.cfg.ForwardingRules != nil {
+		l.cfg.ForwardingRules.registerChannel(
+			l.PeerPubKey(), l.ChanID(), l.outgoingHtlcAmount,
+		)
+	}
+
 	l.mailBox.ResetMessages()
 	l.hodlQueue.Start()
 
@@ -590,6 +681,12 @@ func (l *channelLink) Stop() {
 		l.hodlQueue.Stop()
 	}
 
+	if l.cfg.ForwardingRules != nil {
+		l.cfg.ForwardingRules.unregisterChannel(
+			l.PeerPubKey(), l.ChanID(),
+		)
+	}
+
 	close(l.Quit)
 	l.Wg.Wait()
 
@@ -622,10 +719,11 @@ func (l *channelLink) WaitForShutdown() {
 // EligibleToForward returns a bool indicating if the channel is able to
 // actively accept requests to forward HTLC's. We're able to forward HTLC's if
 // we are eligible to update AND the channel isn't currently flushing the
-// outgoing half of the channel.
+// outgoing half of the channel AND the channel isn't being quiesced.
 func (l *channelLink) EligibleToForward() bool {
 	return l.EligibleToUpdate() &&
-		!l.IsFlushing(Outgoing)
+		!l.IsFlushing(Outgoing) &&
+		l.quiescer.canSendUpdates()
 }
 
 // EligibleToUpdate returns a bool indicating if the channel is able to update
@@ -700,6 +798,96 @@ func (l *channelLink) OnCommitOnce(direction LinkDirection, hook func()) {
 	}
 }
 
+// Quiesce requests the channel to be quiesced, meaning that neither party may
+// send any further updates. It blocks until the channel is quiescent, and
+// returns the initiator of the quiescence session, which is the party that
+// gets to lead the protocol that follows, like a splice. The channel remains
+// quiescent until Resume is called or the connection with the peer is
+// reestablished. If the session exceeds the quiescence timeout, we disconnect
+// from the peer.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) Quiesce(ctx context.Context) (lntypes.ChannelParty,
+	error) {
+
+	if !l.quiescenceNegotiated() {
+		return 0, ErrQuiescenceNotNegotiated
+	}
+
+	// The request is buffered, as it is resolved by the htlcManager while
+	// we might have given up on it already.
+	req := make(chan fn.Result[lntypes.ChannelParty], 1)
+	select {
+	case l.quiescenceReqs <- req:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-l.Quit:
+		return 0, ErrLinkShuttingDown
+	}
+
+	select {
+	case res := <-req:
+		return res.Unpack()
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-l.Quit:
+		return 0, ErrLinkShuttingDown
+	}
+}
+
+// Resume ends the quiescence session of the channel once the protocol that
+// required it is done, allowing both parties to send updates again.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) Resume(ctx context.Context) error {
+	// The request is buffered, as it is resolved by the htlcManager while
+	// we might have given up on it already.
+	req := make(chan error, 1)
+	select {
+	case l.resumeReqs <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.Quit:
+		return ErrLinkShuttingDown
+	}
+
+	select {
+	case err := <-req:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-l.Quit:
+		return ErrLinkShuttingDown
+	}
+}
+
+// quiescenceNegotiated returns whether both we and our peer signaled support
+// for the quiescence protocol.
+func (l *channelLink) quiescenceNegotiated() bool {
+	local := l.cfg.Peer.LocalFeatures()
+	remote := l.cfg.Peer.RemoteFeatures()
+	if local == nil || remote == nil {
+		return false
+	}
+
+	return local.HasFeature(lnwire.QuiescenceOptional) &&
+		remote.HasFeature(lnwire.QuiescenceOptional)
+}
+
+// handleStfu processes an stfu message received from our peer, and responds
+// with our own stfu once all of our updates are committed.
+func (l *channelLink) handleStfu(stfu *lnwire.Stfu) error {
+	if !l.quiescenceNegotiated() {
+		return ErrQuiescenceNotNegotiated
+	}
+
+	if err := l.quiescer.recvStfu(*stfu); err != nil {
+		return err
+	}
+
+	return l.quiescer.sendOwedStfu()
+}
+
 // isReestablished returns true if the link has successfully completed the
 // channel reestablishment dance.
 func (l *channelLink) isReestablished() bool {
@@ -1273,6 +1461,26 @@ func (l *channelLink) htlcManager() {
 			return
 		}
 
+		// If quiescence was requested, send our stfu as soon as all of
+		// our updates are committed.
+		if err := l.quiescer.sendOwedStfu(); err != nil {
+			l.failf(LinkFailureError{code: ErrInternalError},
+				"unable to send stfu: %v", err)
+			return
+		}
+
+		// While quiescence is being negotiated, we must not send any
+		// updates, so the packets from the switch and the htlc
+		// resolutions are left in their queues.
+		var (
+			downstream <-chan *htlcPacket
+			hodlQueue  <-chan interface{}
+		)
+		if l.quiescer.canSendUpdates() {
+			downstream = l.downstream
+			hodlQueue = l.hodlQueue.ChanOut()
+		}
+
 		// If the previous event resulted in a non-empty batch, resume
 		// the batch ticker so that it can be cleared. Otherwise pause
 		// the ticker to prevent waking up the htlcManager while the
@@ -1326,8 +1534,12 @@ func (l *channelLink) htlcManager() {
 			l.updateFeeTimer.Reset(l.randomFeeUpdateTimeout())
 
 			// If we're not the initiator of the channel, don't we
-			// don't control the fees, so we can ignore this.
-			if !l.channel.IsInitiator() {
+			// don't control the fees, so we can ignore this. We
+			// also can't update the fee while quiescence is being
+			// negotiated.
+			if !l.channel.IsInitiator() ||
+				!l.quiescer.canSendUpdates() {
+
 				continue
 			}
 
@@ -1395,6 +1607,17 @@ func (l *channelLink) htlcManager() {
 				return
 			}
 
+		// The window to batch settles and fails has passed, so we'll
+		// sign a new commitment that includes them.
+		case <-l.settleBatchTimeout:
+			l.settleBatchTimeout = nil
+
+			if l.channel.OweCommitment() &&
+				!l.updateCommitTxOrFail() {
+
+				return
+			}
+
 		case <-l.cfg.PendingCommitTicker.Ticks():
 			l.failf(
 				LinkFailureError{
@@ -1408,7 +1631,7 @@ func (l *channelLink) htlcManager() {
 		// A message from the switch was just received. This indicates
 		// that the link is an intermediate hop in a multi-hop HTLC
 		// circuit.
-		case pkt := <-l.downstream:
+		case pkt := <-downstream:
 			l.handleDownstreamPkt(pkt)
 
 		// A message from the connected peer was just received. This
@@ -1419,7 +1642,7 @@ func (l *channelLink) htlcManager() {
 
 		// A htlc resolution is received. This means that we now have a
 		// resolution for a previously accepted htlc.
-		case hodlItem := <-l.hodlQueue.ChanOut():
+		case hodlItem := <-hodlQueue:
 			htlcResolution := hodlItem.(invoices.HtlcResolution)
 			err := l.processHodlQueue(htlcResolution)
 			switch err {
@@ -1445,6 +1668,37 @@ func (l *channelLink) htlcManager() {
 				)
 			}
 
+		// We were asked to quiesce the channel.
+		case req := <-l.quiescenceReqs:
+			l.quiescer.initStfu(req)
+
+		// We were asked to end the quiescence session.
+		case req := <-l.resumeReqs:
+			req <- l.quiescer.resume()
+
+		// The quiescence session took too long, so we disconnect to
+		// unlock the channel. The session may have been resumed since
+		// the timer fired, in which case there's nothing to do.
+		case <-l.quiescenceTimeout:
+			if !l.quiescer.isQuiescing() {
+				continue
+			}
+
+			l.failf(
+				LinkFailureError{
+					code:          ErrQuiescenceTimeout,
+					FailureAction: LinkFailureDisconnect,
+				},
+				"quiescence exceeded timeout of %v",
+				l.cfg.QuiescenceTimeout,
+			)
+
+			return
+
+		// We were asked to reprocess a forwarding package.
+		case req := <-l.fwdPkgReqs:
+			req.resp <- l.handleFwdPkgReq(req)
+
 		case <-l.Quit:
 			return
 		}
@@ -1485,7 +1739,12 @@ loop:
 		}
 	}
 
-	// Update the commitment tx.
+	// Update the commitment tx, unless the resolutions are held to be
+	// batched with the following updates.
+	if l.holdSettleBatch() {
+		return nil
+	}
+
 	if err := l.updateCommitTx(); err != nil {
 		return err
 	}
@@ -1510,9 +1769,7 @@ func (l *channelLink) processHtlcResolution(resolution invoices.HtlcResolution,
 		l.log.Debugf("received settle resolution for %v "+
 			"with outcome: %v", circuitKey, res.Outcome)
 
-		return l.settleHTLC(
-			res.Preimage, htlc.add.ID, htlc.sourceRef,
-		)
+		return l.settleHTLC(res.Preimage, htlc)
 
 	// For htlc failures, we get the relevant failure message based
 	// on the failure resolution and then fail the htlc.
@@ -1656,6 +1913,14 @@ func (l *channelLink) handleDownstreamUpdateAdd(pkt *htlcPacket) error {
 	pkt.outgoingHTLCID = index
 	htlc.ID = index
 
+	// Forwards count against the rate limits of the forwarding rules once
+	// they have been added to the channel.
+	if l.cfg.ForwardingRules != nil && pkt.incomingChanID != hop.Source {
+		l.cfg.ForwardingRules.recordForward(
+			l.PeerPubKey(), pkt.outgoingChanID,
+		)
+	}
+
 	l.log.Debugf("queueing keystone of ADD open circuit: %s->%s",
 		pkt.inKey(), pkt.outKey())
 
@@ -1666,7 +1931,7 @@ func (l *channelLink) handleDownstreamUpdateAdd(pkt *htlcPacket) error {
 
 	// Send a forward event notification to htlcNotifier.
 	l.cfg.HtlcNotifier.NotifyForwardingEvent(
-		newHtlcKey(pkt),
+		newCorrelationID(pkt), newHtlcKey(pkt),
 		HtlcInfo{
 			IncomingTimeLock: pkt.incomingTimeout,
 			IncomingAmt:      pkt.incomingAmount,
@@ -1753,13 +2018,18 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 
 		// Send a settle event notification to htlcNotifier.
 		l.cfg.HtlcNotifier.NotifySettleEvent(
+			newCorrelationID(pkt),
 			newHtlcKey(pkt),
+			newResolvedHtlcInfo(pkt),
 			htlc.PaymentPreimage,
 			getEventType(pkt),
 		)
 
-		// Immediately update the commitment tx to minimize latency.
-		l.updateCommitTxOrFail()
+		// Update the commitment tx to minimize latency, unless the
+		// update is held to be batched with the following ones.
+		if !l.holdSettleBatch() {
+			l.updateCommitTxOrFail()
+		}
 
 	case *lnwire.UpdateFailHTLC:
 		// If hodl.FailOutgoing mode is active, we exit early to
@@ -1836,6 +2106,7 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 		// node.
 		if pkt.linkFailure != nil {
 			l.cfg.HtlcNotifier.NotifyLinkFailEvent(
+				newCorrelationID(pkt),
 				newHtlcKey(pkt),
 				newHtlcInfo(pkt),
 				getEventType(pkt),
@@ -1844,15 +2115,45 @@ func (l *channelLink) handleDownstreamPkt(pkt *htlcPacket) {
 			)
 		} else {
 			l.cfg.HtlcNotifier.NotifyForwardingFailEvent(
-				newHtlcKey(pkt), getEventType(pkt),
+				newCorrelationID(pkt),
+				newHtlcKey(pkt),
+				newResolvedHtlcInfo(pkt),
+				getEventType(pkt),
+				pkt.isResolution,
 			)
 		}
 
-		// Immediately update the commitment tx to minimize latency.
-		l.updateCommitTxOrFail()
+		// Update the commitment tx to minimize latency, unless the
+		// update is held to be batched with the following ones.
+		if !l.holdSettleBatch() {
+			l.updateCommitTxOrFail()
+		}
 	}
 }
 
+// holdSettleBatch returns whether the settles and fails that were just sent to
+// the remote party should be held to be signed together with the following
+// updates. If so, the batch timeout is started unless it is running already.
+func (l *channelLink) holdSettleBatch() bool {
+	if l.cfg.SettleBatchWindow == 0 {
+		return false
+	}
+
+	// Sign right away if the batch is full.
+	pending := l.channel.NumPendingUpdates(lntypes.Local, lntypes.Remote)
+	if pending >= uint64(l.cfg.BatchSize) {
+		return false
+	}
+
+	if l.settleBatchTimeout == nil {
+		l.settleBatchTimeout = time.After(l.cfg.SettleBatchWindow)
+		l.log.Tracef("Holding settle batch for up to %v, "+
+			"pending_updates=%v", l.cfg.SettleBatchWindow, pending)
+	}
+
+	return true
+}
+
 // tryBatchUpdateCommitTx updates the commitment transaction if the batch is
 // full.
 func (l *channelLink) tryBatchUpdateCommitTx() {
@@ -1932,6 +2233,28 @@ func (l *channelLink) cleanupSpuriousResponse(pkt *htlcPacket) {
 // updates from the upstream peer. The upstream peer is the peer whom we have a
 // direct channel with, updating our respective commitment chains.
 func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
+	// Our peer must not send any updates after it sent stfu. Like an add
+	// received while flushing, this is best dealt with by dropping the
+	// connection.
+	switch msg.(type) {
+	case *lnwire.UpdateAddHTLC, *lnwire.UpdateFulfillHTLC,
+		*lnwire.UpdateFailHTLC, *lnwire.UpdateFailMalformedHTLC,
+		*lnwire.UpdateFee:
+
+		if !l.quiescer.canRecvUpdates() {
+			l.failf(
+				LinkFailureError{
+					code:          ErrInvalidUpdate,
+					FailureAction: LinkFailureDisconnect,
+					Warning:       true,
+				},
+				"received %T after stfu", msg,
+			)
+
+			return
+		}
+	}
+
 	switch msg := msg.(type) {
 	case *lnwire.UpdateAddHTLC:
 		if l.IsFlushing(Incoming) {
@@ -2453,6 +2776,18 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// Update the mailbox's feerate as well.
 		l.mailBox.SetFeeRate(fee)
 
+	case *lnwire.Stfu:
+		if err := l.handleStfu(msg); err != nil {
+			l.failf(
+				LinkFailureError{
+					code:          ErrInvalidUpdate,
+					FailureAction: LinkFailureDisconnect,
+					Warning:       true,
+				},
+				"unable to handle stfu: %v", err,
+			)
+		}
+
 	// In the case where we receive a warning message from our peer, just
 	// log it and move on. We choose not to disconnect from our peer,
 	// although we "MAY" do so according to the specification.
@@ -2613,6 +2948,10 @@ func (l *channelLink) updateCommitTx() error {
 		return err
 	}
 
+	// The new commitment includes all held settles and fails, so there's
+	// no batch pending anymore.
+	l.settleBatchTimeout = nil
+
 	if err := l.ackDownStreamPackets(); err != nil {
 		return err
 	}
@@ -2769,6 +3108,22 @@ func (l *channelLink) getDustClosure() dustClosure {
 	return dustHelper(chanType, localDustLimit, remoteDustLimit)
 }
 
+// getMaxFeeExposure returns the threshold after which the link restricts the
+// flow of HTLCs and fee updates.
+//
+// NOTE: Part of the dustHandler interface.
+func (l *channelLink) getMaxFeeExposure() lnwire.MilliSatoshi {
+	return lnwire.MilliSatoshi(l.feeExposureLimit.Load())
+}
+
+// setMaxFeeExposure updates the threshold after which the link restricts the
+// flow of HTLCs and fee updates.
+//
+// NOTE: Part of the dustHandler interface.
+func (l *channelLink) setMaxFeeExposure(threshold lnwire.MilliSatoshi) {
+	l.feeExposureLimit.Store(uint64(threshold))
+}
+
 // getCommitFee returns either the local or remote CommitFee in satoshis. This
 // is used so that the Switch can have access to the commitment fee without
 // needing to have a *LightningChannel. This doesn't include dust.
@@ -2813,7 +3168,7 @@ func (l *channelLink) exceedsFeeExposureLimit(
 	// Finally, check whether the max fee exposure was exceeded on either
 	// future commitment transaction with the fee-rate.
 	totalLocalDust := localDustSum + lnwire.NewMSatFromSatoshis(localFee)
-	if totalLocalDust > l.cfg.MaxFeeExposure {
+	if totalLocalDust > l.getMaxFeeExposure() {
 		return true, nil
 	}
 
@@ -2821,7 +3176,7 @@ func (l *channelLink) exceedsFeeExposureLimit(
 		remoteFee,
 	)
 
-	return totalRemoteDust > l.cfg.MaxFeeExposure, nil
+	return totalRemoteDust > l.getMaxFeeExposure(), nil
 }
 
 // isOverexposedWithHtlc calculates whether the proposed HTLC will make the
@@ -2879,7 +3234,7 @@ func (l *channelLink) isOverexposedWithHtlc(htlc *lnwire.UpdateAddHTLC,
 		localDustSum += additional
 	}
 
-	if localDustSum > l.cfg.MaxFeeExposure {
+	if localDustSum > l.getMaxFeeExposure() {
 		// The max fee exposure was exceeded.
 		return true
 	}
@@ -2894,7 +3249,7 @@ func (l *channelLink) isOverexposedWithHtlc(htlc *lnwire.UpdateAddHTLC,
 		remoteDustSum += additional
 	}
 
-	return remoteDustSum > l.cfg.MaxFeeExposure
+	return remoteDustSum > l.getMaxFeeExposure()
 }
 
 // dustClosure is a function that evaluates whether an HTLC is dust. It returns
@@ -2936,6 +3291,13 @@ func (l *channelLink) zeroConfConfirmed() bool {
 	return l.channel.State().ZeroConfConfirmed()
 }
 
+// isInitiator returns whether or not we funded the channel.
+//
+// Part of the scidAliasHandler interface.
+func (l *channelLink) isInitiator() bool {
+	return l.channel.State().IsInitiator
+}
+
 // confirmedScid returns the confirmed SCID for a zero-conf channel. This
 // should not be called for non-zero-conf channels.
 //
@@ -3102,9 +3464,47 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 		return NewLinkError(failure)
 	}
 
+	// Lastly, the forward must be allowed by the operator's forwarding
+	// rules, if any.
+	if l.cfg.ForwardingRules != nil {
+		err := l.cfg.ForwardingRules.checkForward(
+			l.PeerPubKey(), l.ShortChanID(), amtToForward,
+		)
+		if err != nil {
+			l.log.Warnf("outgoing htlc(%x) rejected by forwarding "+
+				"rules: %v", payHash[:], err)
+
+			cb := func(
+				upd *lnwire.ChannelUpdate1) lnwire.FailureMessage {
+
+				return lnwire.NewTemporaryChannelFailure(upd)
+			}
+			failure := l.createFailureWithUpdate(
+				false, originalScid, cb,
+			)
+
+			return NewDetailedLinkError(
+				failure, OutgoingFailureForwardingRule,
+			)
+		}
+	}
+
 	return nil
 }
 
+// outgoingHtlcAmount returns the total amount of the outgoing htlcs that are
+// locked in on the channel.
+func (l *channelLink) outgoingHtlcAmount() lnwire.MilliSatoshi {
+	var total lnwire.MilliSatoshi
+	for _, htlc := range l.channel.ActiveHtlcs() {
+		if !htlc.Incoming {
+			total += htlc.Amt
+		}
+	}
+
+	return total
+}
+
 // CheckHtlcTransit should return a nil error if the passed HTLC details
 // satisfy the current channel policy.  Otherwise, a LinkError with a
 // valid protocol failure message should be returned in order to signal
@@ -3879,14 +4279,16 @@ func (l *channelLink) processExitHop(add lnwire.UpdateAddHTLC,
 
 // settleHTLC settles the HTLC on the channel.
 func (l *channelLink) settleHTLC(preimage lntypes.Preimage,
-	htlcIndex uint64, sourceRef channeldb.AddRef) error {
+	htlc hodlHtlc) error {
+
+	htlcIndex := htlc.add.ID
 
 	hash := preimage.Hash()
 
 	l.log.Infof("settling htlc %v as exit hop", hash)
 
 	err := l.channel.SettleHTLC(
-		preimage, htlcIndex, &sourceRef, nil, nil,
+		preimage, htlcIndex, &htlc.sourceRef, nil, nil,
 	)
 	if err != nil {
 		return fmt.Errorf("unable to settle htlc: %w", err)
@@ -3909,12 +4311,18 @@ func (l *channelLink) settleHTLC(preimage lntypes.Preimage,
 	})
 
 	// Once we have successfully settled the htlc, notify a settle event.
+	circuitKey := models.CircuitKey{
+		ChanID: l.ShortChanID(),
+		HtlcID: htlcIndex,
+	}
 	l.cfg.HtlcNotifier.NotifySettleEvent(
+		circuitKey,
 		HtlcKey{
-			IncomingCircuit: models.CircuitKey{
-				ChanID: l.ShortChanID(),
-				HtlcID: htlcIndex,
-			},
+			IncomingCircuit: circuitKey,
+		},
+		HtlcInfo{
+			IncomingTimeLock: htlc.add.Expiry,
+			IncomingAmt:      htlc.add.Amount,
 		},
 		preimage,
 		HtlcEventTypeReceive,
@@ -3983,12 +4391,14 @@ func (l *channelLink) sendHTLCError(add lnwire.UpdateAddHTLC,
 		eventType = HtlcEventTypeForward
 	}
 
+	circuitKey := models.CircuitKey{
+		ChanID: l.ShortChanID(),
+		HtlcID: add.ID,
+	}
 	l.cfg.HtlcNotifier.NotifyLinkFailEvent(
+		circuitKey,
 		HtlcKey{
-			IncomingCircuit: models.CircuitKey{
-				ChanID: l.ShortChanID(),
-				HtlcID: add.ID,
-			},
+			IncomingCircuit: circuitKey,
 		},
 		HtlcInfo{
 			IncomingTimeLock: add.Expiry,
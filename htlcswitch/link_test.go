@@ -4497,13 +4497,13 @@ func TestChannelLinkUpdateCommitFee(t *testing.T) {
 		2 * btcutil.SatoshiPerBitcoin,
 	)
 	const maxFeeRate chainfee.SatPerKWeight = 207180182
-	n.aliceChannelLink.cfg.MaxFeeExposure = highFeeExposure
-	n.firstBobChannelLink.cfg.MaxFeeExposure = highFeeExposure
+	n.aliceChannelLink.setMaxFeeExposure(highFeeExposure)
+	n.firstBobChannelLink.setMaxFeeExposure(highFeeExposure)
 	triggerFeeUpdate(maxFeeRate+1, minRelayFee, maxFeeRate, true)
 
 	// Decrease the max fee exposure back to normal.
-	n.aliceChannelLink.cfg.MaxFeeExposure = DefaultMaxFeeExposure
-	n.firstBobChannelLink.cfg.MaxFeeExposure = DefaultMaxFeeExposure
+	n.aliceChannelLink.setMaxFeeExposure(DefaultMaxFeeExposure)
+	n.firstBobChannelLink.setMaxFeeExposure(DefaultMaxFeeExposure)
 
 	// Triggering the link to update the fee of the channel with a fee rate
 	// that is below the current min relay fee rate should result in a fee
@@ -6651,6 +6651,179 @@ func TestChannelLinkHoldInvoiceRestart(t *testing.T) {
 	}
 }
 
+// TestChannelLinkSettleBatchWindow asserts that settles are held for the
+// configured batch window, so that they are signed in a single commitment.
+func TestChannelLinkSettleBatchWindow(t *testing.T) {
+	t.Parallel()
+
+	const (
+		chanAmt     = btcutil.SatoshiPerBitcoin * 5
+		batchWindow = time.Second
+	)
+
+	harness, err := newSingleLinkTestHarness(t, chanAmt, 0)
+	require.NoError(t, err, "unable to create link")
+
+	var (
+		//nolint:forcetypeassert
+		coreLink  = harness.aliceLink.(*channelLink)
+		registry  = coreLink.cfg.Registry.(*mockInvoiceRegistry)
+		aliceMsgs = coreLink.cfg.Peer.(*mockPeer).sentMsgs
+	)
+
+	// Hold settles for a while before signing them.
+	coreLink.cfg.SettleBatchWindow = batchWindow
+
+	require.NoError(t, harness.start(), "unable to start test harness")
+	t.Cleanup(harness.aliceLink.Stop)
+
+	ctx := linkTestContext{
+		t:           t,
+		aliceSwitch: harness.aliceSwitch,
+		aliceLink:   harness.aliceLink,
+		aliceMsgs:   aliceMsgs,
+		bobChannel:  harness.bobChannel,
+	}
+
+	registry.settleChan = make(chan lntypes.Hash)
+
+	// Add two hodl invoices, so that we control when they are settled.
+	htlc1, invoice1 := generateHtlcAndInvoice(t, 0)
+	htlc2, invoice2 := generateHtlcAndInvoice(t, 1)
+
+	preimage1 := invoice1.Terms.PaymentPreimage
+	preimage2 := invoice2.Terms.PaymentPreimage
+	for _, invoice := range []*invpkg.Invoice{invoice1, invoice2} {
+		invoice.Terms.PaymentPreimage = nil
+		invoice.HodlInvoice = true
+	}
+
+	ctxb := context.Background()
+	err = registry.AddInvoice(ctxb, *invoice1, htlc1.PaymentHash)
+	require.NoError(t, err, "unable to add invoice to registry")
+	err = registry.AddInvoice(ctxb, *invoice2, htlc2.PaymentHash)
+	require.NoError(t, err, "unable to add invoice to registry")
+
+	// Lock in both htlcs.
+	ctx.sendHtlcBobToAlice(htlc1)
+	ctx.sendHtlcBobToAlice(htlc2)
+	ctx.sendCommitSigBobToAlice(2)
+	ctx.receiveRevAndAckAliceToBob()
+	ctx.receiveCommitSigAliceToBob(2)
+	ctx.sendRevAndAckBobToAlice()
+
+	<-registry.settleChan
+	<-registry.settleChan
+
+	// Settle the first invoice. Alice should send the settle, but hold
+	// off signing a new commitment.
+	err = registry.SettleHodlInvoice(ctxb, *preimage1)
+	require.NoError(t, err, "settle hodl invoice")
+
+	ctx.receiveSettleAliceToBob()
+	ctx.assertNoMsgFromAlice(batchWindow / 4)
+
+	// Settle the second invoice within the window. Both settles should
+	// be signed in a single commitment once the window has passed.
+	err = registry.SettleHodlInvoice(ctxb, *preimage2)
+	require.NoError(t, err, "settle hodl invoice")
+
+	ctx.receiveSettleAliceToBob()
+	ctx.receiveCommitSigAliceToBob(0)
+	ctx.assertNoMsgFromAlice(batchWindow / 4)
+}
+
+// TestChannelLinkReprocessFwdPkg asserts that the forwarding packages of a
+// link are reported, and that a package can be replayed on demand.
+func TestChannelLinkReprocessFwdPkg(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+
+	harness, err := newSingleLinkTestHarness(t, chanAmt, 0)
+	require.NoError(t, err, "unable to create link")
+
+	require.NoError(t, harness.start(), "unable to start test harness")
+	t.Cleanup(harness.aliceLink.Stop)
+
+	var (
+		//nolint:forcetypeassert
+		coreLink  = harness.aliceLink.(*channelLink)
+		registry  = coreLink.cfg.Registry.(*mockInvoiceRegistry)
+		aliceMsgs = coreLink.cfg.Peer.(*mockPeer).sentMsgs
+	)
+
+	ctx := linkTestContext{
+		t:           t,
+		aliceSwitch: harness.aliceSwitch,
+		aliceLink:   harness.aliceLink,
+		aliceMsgs:   aliceMsgs,
+		bobChannel:  harness.bobChannel,
+	}
+
+	registry.settleChan = make(chan lntypes.Hash)
+
+	// Lock in an htlc paying a hodl invoice, so that it stays in its
+	// forwarding package without being acked.
+	htlc, invoice := generateHtlcAndInvoice(t, 0)
+	invoice.Terms.PaymentPreimage = nil
+	invoice.HodlInvoice = true
+
+	err = registry.AddInvoice(
+		context.Background(), *invoice, htlc.PaymentHash,
+	)
+	require.NoError(t, err, "unable to add invoice to registry")
+
+	ctx.sendHtlcBobToAlice(htlc)
+	ctx.sendCommitSigBobToAlice(1)
+	ctx.receiveRevAndAckAliceToBob()
+	ctx.receiveCommitSigAliceToBob(1)
+	ctx.sendRevAndAckBobToAlice()
+
+	<-registry.settleChan
+
+	// The package holding the add is processed, but not acked yet.
+	pkgs, err := harness.aliceSwitch.ForwardingPackages(
+		coreLink.ChanID(),
+	)
+	require.NoError(t, err)
+
+	var pkg *FwdPkgInfo
+	for i := range pkgs {
+		if pkgs[i].NumAdds == 1 {
+			pkg = &pkgs[i]
+		}
+	}
+	require.NotNil(t, pkg, "forwarding package not found")
+	require.Equal(t, channeldb.FwdStateProcessed, pkg.State)
+	require.Zero(t, pkg.NumForwardedAdds)
+	require.Zero(t, pkg.NumAckedAdds)
+
+	// Replaying the package notifies the registry of the htlc again.
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- harness.aliceSwitch.ReprocessForwardingPackage(
+			context.Background(), coreLink.ChanID(), pkg.Height,
+		)
+	}()
+
+	select {
+	case <-registry.settleChan:
+	case <-time.After(5 * time.Second):
+		t.Fatal("htlc not reprocessed")
+	}
+	require.NoError(t, <-errChan)
+
+	// Since nothing changed, no updates are sent to the remote party.
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+
+	// Replaying an unknown package fails.
+	err = harness.aliceSwitch.ReprocessForwardingPackage(
+		context.Background(), coreLink.ChanID(), pkg.Height+100,
+	)
+	require.ErrorIs(t, err, ErrFwdPkgNotFound)
+}
+
 // TestChannelLinkRevocationWindowRegular asserts that htlcs paying to a regular
 // invoice are settled even if the revocation window gets exhausted.
 func TestChannelLinkRevocationWindowRegular(t *testing.T) {
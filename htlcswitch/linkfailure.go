@@ -51,6 +51,10 @@ const (
 	// circuit map. This is non-fatal and will resolve itself (usually
 	// within several minutes).
 	ErrCircuitError
+
+	// ErrQuiescenceTimeout indicates that the channel remained quiesced
+	// for longer than allowed.
+	ErrQuiescenceTimeout
 )
 
 // LinkFailureAction is an enum-like type that describes the action that should
@@ -122,6 +126,8 @@ func (e LinkFailureError) Error() string {
 		return "unable to resume channel, recovery required"
 	case ErrCircuitError:
 		return "non-fatal circuit map error"
+	case ErrQuiescenceTimeout:
+		return "quiescence timeout"
 	default:
 		return "unknown error"
 	}
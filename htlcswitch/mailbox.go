@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -22,8 +23,41 @@ var (
 	// ErrPacketAlreadyExists signals that an attempt to add a packet failed
 	// because it already exists in the mailbox.
 	ErrPacketAlreadyExists = errors.New("mailbox already has packet")
+
+	// ErrMailBoxFull signals that an add could not be queued because the
+	// mailbox already holds the maximum number of adds.
+	ErrMailBoxFull = errors.New("mailbox is full")
+)
+
+// MailboxOverflowPolicy determines how a mailbox handles a new add once it
+// already holds the maximum number of adds.
+type MailboxOverflowPolicy uint8
+
+const (
+	// MailboxOverflowFail rejects the new add, so that it is failed back
+	// by the switch.
+	MailboxOverflowFail MailboxOverflowPolicy = iota
+
+	// MailboxOverflowDrop drops the oldest add that hasn't been delivered
+	// to the link yet to make room for the new one. The dropped add is
+	// failed back.
+	MailboxOverflowDrop
 )
 
+// String returns a human readable representation of the policy.
+func (p MailboxOverflowPolicy) String() string {
+	switch p {
+	case MailboxOverflowFail:
+		return "fail"
+
+	case MailboxOverflowDrop:
+		return "drop"
+
+	default:
+		return "unknown"
+	}
+}
+
 // MailBox is an interface which represents a concurrent-safe, in-order
 // delivery queue for messages from the network and also from the main switch.
 // This struct serves as a buffer between incoming messages, and messages to
@@ -109,6 +143,22 @@ type mailBoxConfig struct {
 	// correct SCID if the underlying channel uses aliases.
 	failMailboxUpdate func(outScid,
 		mailboxScid lnwire.ShortChannelID) lnwire.FailureMessage
+
+	// maxAdds is the maximum number of adds the mailbox holds. Adds of
+	// our own payments are always accepted. A value of zero means that
+	// the number of adds isn't limited.
+	maxAdds int
+
+	// overflowPolicy determines how new adds are handled once the
+	// mailbox holds maxAdds adds.
+	overflowPolicy MailboxOverflowPolicy
+
+	// scheduler is an optional scheduler that hands out the slots to
+	// deliver adds to the link fairly across all mailboxes.
+	scheduler *mailboxScheduler
+
+	// weight is the weight of the mailbox in the scheduler.
+	weight uint32
 }
 
 // memoryMailBox is an implementation of the MailBox struct backed by purely
@@ -158,6 +208,17 @@ type memoryMailBox struct {
 	// the outstanding dust in the memoryMailBox given the current set
 	// feeRate.
 	isDust dustClosure
+
+	// sched is the handle of the mailbox to the scheduler, if any.
+	sched *schedClient
+
+	// overflow is the number of adds that were queued beyond the maximum
+	// under the drop policy. The courier drops as many of the oldest adds
+	// that haven't been delivered yet.
+	overflow int
+
+	// overflowSignal wakes up the courier to drop adds.
+	overflowSignal chan struct{}
 }
 
 // newMemoryMailBox creates a new instance of the memoryMailBox.
@@ -176,10 +237,16 @@ func newMemoryMailBox(cfg *mailBoxConfig) *memoryMailBox {
 		wireShutdown:  make(chan struct{}),
 		pktShutdown:   make(chan struct{}),
 		quit:          make(chan struct{}),
+
+		overflowSignal: make(chan struct{}, 1),
 	}
 	box.wireCond = sync.NewCond(&box.wireMtx)
 	box.pktCond = sync.NewCond(&box.pktMtx)
 
+	if cfg.scheduler != nil {
+		box.sched = cfg.scheduler.register(cfg.weight)
+	}
+
 	return box
 }
 
@@ -278,6 +345,7 @@ func (m *memoryMailBox) AckPacket(inKey CircuitKey) bool {
 		}
 		m.repPkts.Remove(entry)
 		delete(m.repIndex, inKey)
+		m.reportDepth()
 
 		return true
 	}
@@ -298,8 +366,7 @@ func (m *memoryMailBox) AckPacket(inKey CircuitKey) bool {
 			m.addHead = entry.Next()
 		}
 
-		m.addPkts.Remove(entry)
-		delete(m.addIndex, inKey)
+		m.removeAdd(entry)
 
 		return true
 	}
@@ -307,6 +374,49 @@ func (m *memoryMailBox) AckPacket(inKey CircuitKey) bool {
 	return false
 }
 
+// removeAdd removes the given add from the mailbox, returning its delivery
+// slot to the scheduler. The caller must hold the packet mutex.
+func (m *memoryMailBox) removeAdd(entry *list.Element) {
+	//nolint:forcetypeassert
+	add := entry.Value.(*pktWithExpiry)
+
+	m.addPkts.Remove(entry)
+	delete(m.addIndex, add.pkt.inKey())
+
+	if add.hasSlot {
+		m.sched.release(1)
+	}
+
+	m.reportDepth()
+}
+
+// releaseSlots returns the delivery slots of all adds to the scheduler. This
+// is used when the packets are reset, since the adds will be delivered again.
+// The caller must hold the packet mutex.
+func (m *memoryMailBox) releaseSlots() {
+	if m.sched == nil {
+		return
+	}
+
+	var released int
+	for e := m.addPkts.Front(); e != nil; e = e.Next() {
+		//nolint:forcetypeassert
+		add := e.Value.(*pktWithExpiry)
+		if add.hasSlot {
+			add.hasSlot = false
+			released++
+		}
+	}
+
+	m.sched.release(released)
+}
+
+// reportDepth updates the queue depth gauges of the mailbox. The caller must
+// hold the packet mutex.
+func (m *memoryMailBox) reportDepth() {
+	reportMailboxDepth(m.cfg.shortChanID, len(m.addIndex), len(m.repIndex))
+}
+
 // HasPacket queries the packets for a circuit key, this is used to drop packets
 // bound for the switch that already have a queued response.
 func (m *memoryMailBox) HasPacket(inKey CircuitKey) bool {
@@ -326,6 +436,15 @@ func (m *memoryMailBox) Stop() {
 
 		m.signalUntilShutdown(wireCourier)
 		m.signalUntilShutdown(pktCourier)
+
+		// Return all delivery slots held by this mailbox, so that
+		// the other mailboxes can use them.
+		if m.sched != nil {
+			m.pktCond.L.Lock()
+			m.releaseSlots()
+			m.sched.unregister(0)
+			m.pktCond.L.Unlock()
+		}
 	})
 }
 
@@ -362,6 +481,10 @@ func (m *memoryMailBox) signalUntilShutdown(cType courierType) {
 type pktWithExpiry struct {
 	pkt    *htlcPacket
 	expiry time.Time
+
+	// hasSlot indicates whether the add holds a delivery slot of the
+	// scheduler.
+	hasSlot bool
 }
 
 func (p *pktWithExpiry) deadline(clock clock.Clock) <-chan time.Time {
@@ -429,7 +552,13 @@ func (m *memoryMailBox) pktMailCourier() {
 		// First, we'll check our condition. If our mailbox is empty,
 		// then we'll wait until a new item is added.
 		m.pktCond.L.Lock()
-		for m.repHead == nil && m.addHead == nil {
+		for m.repHead == nil && m.addHead == nil && m.overflow == 0 {
+			// We don't need a delivery slot while there's nothing
+			// to deliver.
+			if m.sched != nil {
+				m.sched.cancel()
+			}
+
 			m.pktCond.Wait()
 
 			select {
@@ -439,6 +568,7 @@ func (m *memoryMailBox) pktMailCourier() {
 			case pktDone := <-m.pktReset:
 				m.repHead = m.repPkts.Front()
 				m.addHead = m.addPkts.Front()
+				m.releaseSlots()
 
 				close(pktDone)
 
@@ -449,6 +579,19 @@ func (m *memoryMailBox) pktMailCourier() {
 			}
 		}
 
+		// Drop the oldest adds to make room for the ones that were
+		// queued beyond the maximum before delivering anything else.
+		if m.overflow > 0 {
+			dropped := m.dropOldestAdds()
+			m.pktCond.L.Unlock()
+
+			for _, pkt := range dropped {
+				m.failAdd(pkt)
+			}
+
+			continue
+		}
+
 		var (
 			nextRep   *htlcPacket
 			nextRepEl *list.Element
@@ -476,6 +619,26 @@ func (m *memoryMailBox) pktMailCourier() {
 			nextAddEl = m.addHead
 		}
 
+		// If the next packet to deliver is an add, it needs a delivery
+		// slot from the scheduler. If none is available, we'll wait
+		// for one to be granted, unless the add expires first.
+		var slotGranted <-chan struct{}
+		if m.sched != nil {
+			needSlot := nextRep == nil && nextAdd != nil &&
+				!nextAdd.hasSlot
+
+			switch {
+			case !needSlot:
+				m.sched.cancel()
+
+			case m.sched.tryAcquire():
+				nextAdd.hasSlot = true
+
+			default:
+				slotGranted = m.sched.granted
+			}
+		}
+
 		// Now that we're done with the condition, we can unlock it to
 		// allow any callers to append to the end of our target queue.
 		m.pktCond.L.Unlock()
@@ -497,9 +660,11 @@ func (m *memoryMailBox) pktMailCourier() {
 		// channel, but we can control which is delivered by exclusively
 		// making one nil and the other non-nil. We know from our loop
 		// condition that at least one nextRep and nextAdd are non-nil.
-		if nextRep != nil {
+		switch {
+		case nextRep != nil:
 			pktOutbox = m.pktOutbox
-		} else {
+
+		case slotGranted == nil:
 			addOutbox = m.pktOutbox
 		}
 
@@ -541,10 +706,19 @@ func (m *memoryMailBox) pktMailCourier() {
 				"keystone=%v", add.keystone())
 			m.FailAdd(add)
 
+		// A delivery slot was granted, we'll pick it up on the next
+		// iteration.
+		case <-slotGranted:
+
+		// Adds were queued beyond the maximum, we'll drop the oldest
+		// ones on the next iteration.
+		case <-m.overflowSignal:
+
 		case pktDone := <-m.pktReset:
 			m.pktCond.L.Lock()
 			m.repHead = m.repPkts.Front()
 			m.addHead = m.addPkts.Front()
+			m.releaseSlots()
 			m.pktCond.L.Unlock()
 
 			close(pktDone)
@@ -600,6 +774,24 @@ func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
 			return ErrPacketAlreadyExists
 		}
 
+		// Make sure there's room for the add. Our own payments are
+		// always accepted, since the router already limits them.
+		full := m.cfg.maxAdds > 0 && len(m.addIndex) >= m.cfg.maxAdds
+		if full && pkt.incomingChanID != hop.Source {
+			if m.cfg.overflowPolicy != MailboxOverflowDrop {
+				m.pktCond.L.Unlock()
+				return ErrMailBoxFull
+			}
+
+			// Leave it to the courier to drop the oldest add, as
+			// it may be delivering it right now.
+			m.overflow++
+			select {
+			case m.overflowSignal <- struct{}{}:
+			default:
+			}
+		}
+
 		entry := m.addPkts.PushBack(&pktWithExpiry{
 			pkt:    pkt,
 			expiry: m.cfg.clock.Now().Add(m.cfg.expiry),
@@ -613,6 +805,7 @@ func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
 		m.pktCond.L.Unlock()
 		return fmt.Errorf("unknown htlc type: %T", htlc)
 	}
+	m.reportDepth()
 	m.pktCond.L.Unlock()
 
 	// With the packet added, we signal to the mailCourier that there are
@@ -622,6 +815,33 @@ func (m *memoryMailBox) AddPacket(pkt *htlcPacket) error {
 	return nil
 }
 
+// dropOldestAdds removes the oldest adds that haven't been delivered to the
+// link yet from the mailbox, to make room for the adds that were queued beyond
+// the maximum. The dropped adds are returned so that they can be failed back.
+// The caller must hold the packet mutex.
+func (m *memoryMailBox) dropOldestAdds() []*htlcPacket {
+	var dropped []*htlcPacket
+	for ; m.overflow > 0 && m.addHead != nil; m.overflow-- {
+		entry := m.addHead
+		m.addHead = entry.Next()
+
+		//nolint:forcetypeassert
+		oldest := entry.Value.(*pktWithExpiry).pkt
+		m.removeAdd(entry)
+
+		log.Debugf("Dropping add htlc with keystone=%v from full "+
+			"mailbox", oldest.keystone())
+
+		dropped = append(dropped, oldest)
+	}
+
+	// If all remaining adds have been delivered already, there's nothing
+	// left to drop.
+	m.overflow = 0
+
+	return dropped
+}
+
 // SetFeeRate sets the memoryMailBox's feerate for use in DustPackets.
 func (m *memoryMailBox) SetFeeRate(feeRate chainfee.SatPerKWeight) {
 	m.pktCond.L.Lock()
@@ -694,6 +914,12 @@ func (m *memoryMailBox) FailAdd(pkt *htlcPacket) {
 		return
 	}
 
+	m.failAdd(pkt)
+}
+
+// failAdd sends a failure for the given add, which must have been removed from
+// the mailbox already, back through the switch.
+func (m *memoryMailBox) failAdd(pkt *htlcPacket) {
 	var (
 		localFailure = false
 		reason       lnwire.OpaqueReason
@@ -818,6 +1044,22 @@ type mailOrchConfig struct {
 	// correct SCID if the underlying channel uses aliases.
 	failMailboxUpdate func(outScid,
 		mailboxScid lnwire.ShortChannelID) lnwire.FailureMessage
+
+	// maxAdds is the maximum number of adds each mailbox holds. A value
+	// of zero means that the number of adds isn't limited.
+	maxAdds int
+
+	// overflowPolicy determines how new adds are handled once a mailbox
+	// holds maxAdds adds.
+	overflowPolicy MailboxOverflowPolicy
+
+	// scheduler is an optional scheduler that hands out the slots to
+	// deliver adds to the links fairly across all mailboxes.
+	scheduler *mailboxScheduler
+
+	// weight returns the weight of the mailbox of the given channel in
+	// the scheduler. If nil, all mailboxes have the same weight.
+	weight func(lnwire.ChannelID) uint32
 }
 
 // newMailOrchestrator initializes a fresh mailOrchestrator.
@@ -870,12 +1112,21 @@ func (mo *mailOrchestrator) exclusiveGetOrCreateMailBox(
 
 	mailbox, ok := mo.mailboxes[chanID]
 	if !ok {
+		var weight uint32 = 1
+		if mo.cfg.scheduler != nil && mo.cfg.weight != nil {
+			weight = mo.cfg.weight(chanID)
+		}
+
 		mailbox = newMemoryMailBox(&mailBoxConfig{
 			shortChanID:       shortChanID,
 			forwardPackets:    mo.cfg.forwardPackets,
 			clock:             mo.cfg.clock,
 			expiry:            mo.cfg.expiry,
 			failMailboxUpdate: mo.cfg.failMailboxUpdate,
+			maxAdds:           mo.cfg.maxAdds,
+			overflowPolicy:    mo.cfg.overflowPolicy,
+			scheduler:         mo.cfg.scheduler,
+			weight:            weight,
 		})
 		mailbox.Start()
 		mo.mailboxes[chanID] = mailbox
@@ -0,0 +1,13 @@
+//go:build !monitoring
+// +build !monitoring
+
+package htlcswitch
+
+import "github.com/lightningnetwork/lnd/lnwire"
+
+// reportMailboxDepth is a no-op if lnd is built without the monitoring tag.
+func reportMailboxDepth(_ lnwire.ShortChannelID, _, _ int) {}
+
+// reportMailboxInFlight is a no-op if lnd is built without the monitoring
+// tag.
+func reportMailboxInFlight(_ int) {}
@@ -0,0 +1,66 @@
+//go:build monitoring
+// +build monitoring
+
+package htlcswitch
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// mailboxQueuedAdds tracks the number of adds queued in the mailbox
+	// of each channel.
+	mailboxQueuedAdds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "lnd",
+			Subsystem: "htlcswitch",
+			Name:      "mailbox_queued_adds",
+			Help:      "Number of htlc adds queued in a mailbox",
+		}, []string{"chan_id"},
+	)
+
+	// mailboxQueuedReplies tracks the number of settles and fails queued
+	// in the mailbox of each channel.
+	mailboxQueuedReplies = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "lnd",
+			Subsystem: "htlcswitch",
+			Name:      "mailbox_queued_replies",
+			Help: "Number of htlc settles and fails queued in a " +
+				"mailbox",
+		}, []string{"chan_id"},
+	)
+
+	// mailboxInFlightAdds tracks the number of adds that are in flight
+	// across all mailboxes.
+	mailboxInFlightAdds = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "lnd",
+			Subsystem: "htlcswitch",
+			Name:      "mailbox_inflight_adds",
+			Help: "Number of htlc adds delivered to links that " +
+				"haven't been acked yet",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(
+		mailboxQueuedAdds, mailboxQueuedReplies, mailboxInFlightAdds,
+	)
+}
+
+// reportMailboxDepth updates the queue depth gauges of the mailbox of the
+// given channel.
+func reportMailboxDepth(sid lnwire.ShortChannelID, adds, replies int) {
+	label := sid.String()
+	mailboxQueuedAdds.WithLabelValues(label).Set(float64(adds))
+	mailboxQueuedReplies.WithLabelValues(label).Set(float64(replies))
+}
+
+// reportMailboxInFlight updates the gauge of the number of adds that are in
+// flight across all mailboxes.
+func reportMailboxInFlight(inFlight int) {
+	mailboxInFlightAdds.Set(float64(inFlight))
+}
@@ -0,0 +1,219 @@
+package htlcswitch
+
+import (
+	"container/list"
+	"sync"
+)
+
+// mailboxScheduler limits the number of adds that are delivered to links, but
+// not yet acked, across all mailboxes of the switch. Once the limit is
+// reached, the remaining delivery slots are handed out to the mailboxes that
+// wait for one using deficit round robin, so that a few busy channels can't
+// starve the others. A mailbox with weight w receives up to w slots in a row
+// before the next waiting mailbox is served.
+type mailboxScheduler struct {
+	// maxInFlight is the maximum number of adds that may be in flight
+	// across all mailboxes.
+	maxInFlight int
+
+	mu sync.Mutex
+
+	// inFlight is the number of slots that are currently handed out,
+	// including the ones that were granted but not used yet.
+	inFlight int
+
+	// clients holds all registered mailboxes in round robin order.
+	clients *list.List
+
+	// cursor points to the client that is served next.
+	cursor *list.Element
+}
+
+// newMailboxScheduler creates a new scheduler that allows up to maxInFlight
+// adds to be in flight across all mailboxes.
+func newMailboxScheduler(maxInFlight int) *mailboxScheduler {
+	return &mailboxScheduler{
+		maxInFlight: maxInFlight,
+		clients:     list.New(),
+	}
+}
+
+// schedClient is the handle of a single mailbox to the scheduler.
+type schedClient struct {
+	sched *mailboxScheduler
+
+	// weight is the number of slots the client may receive in a row.
+	weight uint32
+
+	// deficit is the number of slots the client may still receive in the
+	// current round.
+	deficit uint32
+
+	// waiting indicates whether the client requested a slot that hasn't
+	// been granted yet.
+	waiting bool
+
+	// credits is the number of slots that were granted to the client, but
+	// not yet used.
+	credits int
+
+	// granted is signaled each time a slot is granted to the client.
+	granted chan struct{}
+
+	elem *list.Element
+}
+
+// register adds a new client with the given weight to the scheduler.
+func (s *mailboxScheduler) register(weight uint32) *schedClient {
+	if weight == 0 {
+		weight = 1
+	}
+
+	c := &schedClient{
+		sched:   s,
+		weight:  weight,
+		granted: make(chan struct{}, 1),
+	}
+
+	s.mu.Lock()
+	c.elem = s.clients.PushBack(c)
+	s.mu.Unlock()
+
+	return c
+}
+
+// numInFlight returns the number of slots that are currently handed out.
+func (s *mailboxScheduler) numInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inFlight
+}
+
+// dispatch grants free slots to waiting clients in round robin order. The
+// caller must hold the mutex.
+func (s *mailboxScheduler) dispatch() {
+	defer func() {
+		reportMailboxInFlight(s.inFlight)
+	}()
+
+	// Each pass over the clients either grants a slot or skips a client
+	// that isn't waiting, so we can stop once we've visited every client
+	// without granting anything.
+	idle := 0
+	for s.inFlight < s.maxInFlight && idle < s.clients.Len() {
+		if s.cursor == nil {
+			s.cursor = s.clients.Front()
+		}
+
+		//nolint:forcetypeassert
+		c := s.cursor.Value.(*schedClient)
+		if !c.waiting {
+			// A client that isn't waiting loses the remainder of
+			// its round.
+			c.deficit = 0
+			s.cursor = s.cursor.Next()
+			idle++
+
+			continue
+		}
+
+		if c.deficit == 0 {
+			c.deficit = c.weight
+		}
+
+		c.deficit--
+		c.waiting = false
+		c.credits++
+		s.inFlight++
+		idle = 0
+
+		select {
+		case c.granted <- struct{}{}:
+		default:
+		}
+
+		// Move on to the next client once this one used up its
+		// round.
+		if c.deficit == 0 {
+			s.cursor = s.cursor.Next()
+		}
+	}
+}
+
+// tryAcquire uses a granted slot if one is available. Otherwise, a slot is
+// requested and false is returned. The client is signaled on its granted
+// channel once the request has been granted.
+func (c *schedClient) tryAcquire() bool {
+	s := c.sched
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c.credits > 0 {
+		c.credits--
+		return true
+	}
+
+	c.waiting = true
+	s.dispatch()
+
+	if c.credits > 0 {
+		c.credits--
+		return true
+	}
+
+	return false
+}
+
+// cancel withdraws a pending request of the client and returns the slots that
+// were granted to it, but not used.
+func (c *schedClient) cancel() {
+	s := c.sched
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !c.waiting && c.credits == 0 {
+		return
+	}
+
+	c.waiting = false
+	s.inFlight -= c.credits
+	c.credits = 0
+	s.dispatch()
+}
+
+// release returns the given number of used slots to the scheduler.
+func (c *schedClient) release(n int) {
+	if n == 0 {
+		return
+	}
+
+	s := c.sched
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inFlight -= n
+	s.dispatch()
+}
+
+// unregister removes the client from the scheduler, returning all of its
+// slots.
+func (c *schedClient) unregister(used int) {
+	s := c.sched
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cursor == c.elem {
+		s.cursor = c.elem.Next()
+	}
+	s.clients.Remove(c.elem)
+
+	s.inFlight -= used + c.credits
+	c.credits = 0
+	c.waiting = false
+	s.dispatch()
+}
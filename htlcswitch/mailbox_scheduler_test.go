@@ -0,0 +1,83 @@
+package htlcswitch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestMailboxSchedulerWeights asserts that the scheduler hands out delivery
+// slots in round robin order, granting each client as many slots in a row as
+// its weight allows.
+func TestMailboxSchedulerWeights(t *testing.T) {
+	t.Parallel()
+
+	sched := newMailboxScheduler(1)
+	a := sched.register(2)
+	b := sched.register(1)
+
+	// The first slot is free, so a gets it right away.
+	require.True(t, a.tryAcquire())
+	require.Equal(t, 1, sched.numInFlight())
+
+	// Both clients now wait for the next slot.
+	require.False(t, b.tryAcquire())
+	require.False(t, a.tryAcquire())
+
+	// assertGranted asserts that the given client was granted a slot and
+	// uses it.
+	assertGranted := func(c *schedClient) {
+		t.Helper()
+
+		select {
+		case <-c.granted:
+		default:
+			t.Fatalf("slot not granted")
+		}
+		require.True(t, c.tryAcquire())
+	}
+
+	// Since a has a weight of two, it receives the next slot as well.
+	a.release(1)
+	assertGranted(a)
+
+	// a used up its round, so b is served next, even though a requests
+	// another slot.
+	require.False(t, a.tryAcquire())
+	a.release(1)
+	assertGranted(b)
+
+	// Afterwards, it's a's turn again.
+	b.release(1)
+	assertGranted(a)
+
+	// Once a withdraws its client, all of its slots are returned.
+	a.unregister(1)
+	require.Zero(t, sched.numInFlight())
+
+	require.True(t, b.tryAcquire())
+	b.unregister(1)
+	require.Zero(t, sched.numInFlight())
+}
+
+// TestMailboxSchedulerCancel asserts that cancelling a request returns the
+// slots that were granted, but not used, to the other clients.
+func TestMailboxSchedulerCancel(t *testing.T) {
+	t.Parallel()
+
+	sched := newMailboxScheduler(1)
+	a := sched.register(1)
+	b := sched.register(1)
+
+	require.True(t, a.tryAcquire())
+	require.False(t, b.tryAcquire())
+
+	// The slot of a is granted to b, but b no longer needs it.
+	a.release(1)
+	b.cancel()
+	require.Zero(t, sched.numInFlight())
+
+	// The slot is free again, so a can take it right away.
+	require.True(t, a.tryAcquire())
+	require.Equal(t, 1, sched.numInFlight())
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lnmock"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -828,3 +829,80 @@ func TestMailOrchestrator(t *testing.T) {
 			spew.Sdump(sentPackets), spew.Sdump(recvdPackets))
 	}
 }
+
+// newLimitedMailboxContext creates a new mailbox context, whose mailbox holds
+// at most maxAdds adds and handles overflows using the given policy.
+func newLimitedMailboxContext(t *testing.T, maxAdds int,
+	policy MailboxOverflowPolicy) *mailboxContext {
+
+	ctx := &mailboxContext{
+		t:        t,
+		clock:    clock.NewTestClock(time.Now()),
+		forwards: make(chan *htlcPacket, 1),
+	}
+
+	failMailboxUpdate := func(outScid,
+		mboxScid lnwire.ShortChannelID) lnwire.FailureMessage {
+
+		return &lnwire.FailTemporaryNodeFailure{}
+	}
+
+	ctx.mailbox = newMemoryMailBox(&mailBoxConfig{
+		failMailboxUpdate: failMailboxUpdate,
+		forwardPackets:    ctx.forward,
+		clock:             ctx.clock,
+		expiry:            testExpiry,
+		maxAdds:           maxAdds,
+		overflowPolicy:    policy,
+	})
+	ctx.mailbox.Start()
+	t.Cleanup(ctx.mailbox.Stop)
+
+	return ctx
+}
+
+// TestMailBoxOverflowFail asserts that a full mailbox rejects new forwarded
+// adds when using the fail policy, while local adds are still accepted.
+func TestMailBoxOverflowFail(t *testing.T) {
+	t.Parallel()
+
+	const maxAdds = 2
+	ctx := newLimitedMailboxContext(t, maxAdds, MailboxOverflowFail)
+
+	adds := ctx.sendAdds(0, maxAdds)
+
+	// The mailbox is full, so the next forwarded add is rejected.
+	err := ctx.mailbox.AddPacket(&htlcPacket{
+		incomingChanID: lnwire.NewShortChanIDFromInt(1),
+		incomingHTLCID: maxAdds,
+		htlc:           &lnwire.UpdateAddHTLC{},
+	})
+	require.ErrorIs(t, err, ErrMailBoxFull)
+
+	// Adds of local payments aren't limited.
+	localAdd := &htlcPacket{
+		incomingChanID: hop.Source,
+		incomingHTLCID: maxAdds + 1,
+		htlc:           &lnwire.UpdateAddHTLC{},
+	}
+	require.NoError(t, ctx.mailbox.AddPacket(localAdd))
+
+	// All accepted adds are delivered, and nothing is failed back.
+	ctx.receivePkts(append(adds, localAdd))
+	ctx.checkFails(nil)
+}
+
+// TestMailBoxOverflowDrop asserts that a full mailbox fails back its oldest
+// undelivered add to make room for a new one when using the drop policy.
+func TestMailBoxOverflowDrop(t *testing.T) {
+	t.Parallel()
+
+	const maxAdds = 2
+	ctx := newLimitedMailboxContext(t, maxAdds, MailboxOverflowDrop)
+
+	adds := ctx.sendAdds(0, maxAdds+1)
+
+	// The first add is failed back, and the others are delivered.
+	ctx.checkFails(adds[:1])
+	ctx.receivePkts(adds[1:])
+}
@@ -531,7 +531,7 @@ func (p *mockIteratorDecoder) DecodeHopIterators(id []byte,
 	p.mu.RLock()
 	if resps, ok := p.responses[idHash]; ok {
 		p.mu.RUnlock()
-		return resps, nil
+		return copyMockHopIterators(resps), nil
 	}
 	p.mu.RUnlock()
 
@@ -558,7 +558,28 @@ func (p *mockIteratorDecoder) DecodeHopIterators(id []byte,
 	p.responses[idHash] = resps
 	p.mu.Unlock()
 
-	return resps, nil
+	return copyMockHopIterators(resps), nil
+}
+
+// copyMockHopIterators returns a copy of the given responses with fresh hop
+// iterators, as the mock iterators are consumed when their payload is read,
+// while replayed batches must be decoded to the same payloads again.
+func copyMockHopIterators(
+	resps []hop.DecodeHopIteratorResponse) []hop.DecodeHopIteratorResponse {
+
+	respsCopy := make([]hop.DecodeHopIteratorResponse, len(resps))
+	for i, resp := range resps {
+		respsCopy[i] = resp
+
+		iterator, ok := resp.HopIterator.(*mockHopIterator)
+		if ok {
+			respsCopy[i].HopIterator = &mockHopIterator{
+				hops: iterator.hops,
+			}
+		}
+	}
+
+	return respsCopy
 }
 
 func decodeFwdInfo(r io.Reader, f *hop.ForwardingInfo) error {
@@ -734,10 +755,14 @@ type mockChannelLink struct {
 
 	checkHtlcForwardResult *LinkError
 
+	maxFeeExposure lnwire.MilliSatoshi
+
 	failAliasUpdate func(sid lnwire.ShortChannelID,
 		incoming bool) *lnwire.ChannelUpdate1
 
 	confirmedZC bool
+
+	initiator bool
 }
 
 // completeCircuit is a helper method for adding the finalized payment circuit
@@ -838,6 +863,14 @@ func (f *mockChannelLink) getCommitFee(remote bool) btcutil.Amount {
 	return 0
 }
 
+func (f *mockChannelLink) getMaxFeeExposure() lnwire.MilliSatoshi {
+	return f.maxFeeExposure
+}
+
+func (f *mockChannelLink) setMaxFeeExposure(threshold lnwire.MilliSatoshi) {
+	f.maxFeeExposure = threshold
+}
+
 func (f *mockChannelLink) HandleChannelUpdate(lnwire.Message) {
 }
 
@@ -895,6 +928,10 @@ func (f *mockChannelLink) zeroConfConfirmed() bool {
 	return f.confirmedZC
 }
 
+func (f *mockChannelLink) isInitiator() bool {
+	return f.initiator
+}
+
 func (f *mockChannelLink) Start() error {
 	f.mailBox.ResetMessages()
 	f.mailBox.ResetPackets()
@@ -950,6 +987,14 @@ func (f *mockChannelLink) OnFlushedOnce(func()) {
 func (f *mockChannelLink) OnCommitOnce(LinkDirection, func()) {
 	// TODO(proofofkeags): Implement
 }
+func (f *mockChannelLink) Quiesce(context.Context) (lntypes.ChannelParty,
+	error) {
+
+	return 0, ErrQuiescenceNotNegotiated
+}
+func (f *mockChannelLink) Resume(context.Context) error {
+	return ErrNotQuiescent
+}
 
 func (f *mockChannelLink) FundingCustomBlob() fn.Option[tlv.Blob] {
 	return fn.None[tlv.Blob]()
@@ -959,6 +1004,14 @@ func (f *mockChannelLink) CommitmentCustomBlob() fn.Option[tlv.Blob] {
 	return fn.None[tlv.Blob]()
 }
 
+func (f *mockChannelLink) ForwardingPackages() ([]FwdPkgInfo, error) {
+	return nil, nil
+}
+
+func (f *mockChannelLink) ReprocessFwdPkg(context.Context, uint64) error {
+	return ErrFwdPkgNotFound
+}
+
 var _ ChannelLink = (*mockChannelLink)(nil)
 
 func newDB() (*channeldb.DB, func(), error) {
@@ -1018,7 +1071,7 @@ func newMockRegistry(minDelta uint32) *mockInvoiceRegistry {
 	registry := invoices.NewRegistry(
 		cdb,
 		invoices.NewInvoiceExpiryWatcher(
-			clock.NewDefaultClock(), 0, 0, nil,
+			clock.NewDefaultClock(), 0, 0, 0, nil,
 			&mockChainNotifier{},
 		),
 		&invoices.RegistryConfig{
@@ -1130,6 +1183,10 @@ func (m *mockCircuitMap) LookupOpenCircuit(outKey CircuitKey) *PaymentCircuit {
 	return nil
 }
 
+func (m *mockCircuitMap) OpenKeystones() []Keystone {
+	return nil
+}
+
 func (m *mockCircuitMap) LookupByPaymentHash(hash [32]byte) []*PaymentCircuit {
 	return nil
 }
@@ -1163,24 +1220,25 @@ type mockHTLCNotifier struct {
 	htlcNotifier //nolint:unused
 }
 
-func (h *mockHTLCNotifier) NotifyForwardingEvent(key HtlcKey, info HtlcInfo,
-	eventType HtlcEventType) {
+func (h *mockHTLCNotifier) NotifyForwardingEvent(id CircuitKey, key HtlcKey,
+	info HtlcInfo, eventType HtlcEventType) {
 
 }
 
-func (h *mockHTLCNotifier) NotifyLinkFailEvent(key HtlcKey, info HtlcInfo,
-	eventType HtlcEventType, linkErr *LinkError,
+func (h *mockHTLCNotifier) NotifyLinkFailEvent(id CircuitKey, key HtlcKey,
+	info HtlcInfo, eventType HtlcEventType, linkErr *LinkError,
 	incoming bool) {
 
 }
 
-func (h *mockHTLCNotifier) NotifyForwardingFailEvent(key HtlcKey,
-	eventType HtlcEventType) {
+func (h *mockHTLCNotifier) NotifyForwardingFailEvent(id CircuitKey,
+	key HtlcKey, info HtlcInfo, eventType HtlcEventType,
+	resolution bool) {
 
 }
 
-func (h *mockHTLCNotifier) NotifySettleEvent(key HtlcKey,
-	preimage lntypes.Preimage, eventType HtlcEventType) {
+func (h *mockHTLCNotifier) NotifySettleEvent(id CircuitKey, key HtlcKey,
+	info HtlcInfo, preimage lntypes.Preimage, eventType HtlcEventType) {
 
 }
 
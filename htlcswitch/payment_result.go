@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/multimutex"
@@ -39,6 +40,22 @@ type PaymentResult struct {
 	// irrevocably canceled. If the payment failed during forwarding, this
 	// error will be a *ForwardingError.
 	Error error
+
+	// Attribution holds the data needed to attribute a failure that
+	// occurred further along the route to a specific hop. It is only set
+	// for failures that have been received from the remote party and that
+	// carry attribution data.
+	Attribution fn.Option[FailureAttribution]
+}
+
+// FailureAttribution holds the raw failure as received from the first hop,
+// along with the attribution data that was attached to it.
+type FailureAttribution struct {
+	// Reason is the onion encrypted failure reason.
+	Reason lnwire.OpaqueReason
+
+	// Data is the attribution data that was attached to the failure.
+	Data lnwire.AttributionData
 }
 
 // networkResult is the raw result received from the network after a payment
@@ -0,0 +1,355 @@
+package htlcswitch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrStfuAlreadyRcvd is returned when our peer sends us an stfu
+	// message more than once during a single quiescence session.
+	ErrStfuAlreadyRcvd = errors.New("stfu already received")
+
+	// ErrPendingRemoteUpdates is returned when our peer sends us an stfu
+	// message while some of its updates aren't committed to both
+	// commitments yet.
+	ErrPendingRemoteUpdates = errors.New("stfu received with pending " +
+		"remote updates")
+
+	// ErrQuiescenceInProgress is returned when quiescence is requested
+	// while an earlier request is still pending.
+	ErrQuiescenceInProgress = errors.New("quiescence already requested")
+
+	// ErrQuiescenceNotNegotiated is returned when quiescence is requested
+	// for a channel with a peer that doesn't support it.
+	ErrQuiescenceNotNegotiated = errors.New("quiescence not negotiated " +
+		"with peer")
+
+	// ErrNoQuiescenceInitiator is returned when the initiator of the
+	// quiescence session is queried before the channel is quiescent.
+	ErrNoQuiescenceInitiator = errors.New("no quiescence initiator, " +
+		"channel is not quiescent")
+
+	// ErrNotQuiescent is returned when the quiescence session is resumed
+	// while the channel isn't quiescent.
+	ErrNotQuiescent = errors.New("channel is not quiescent")
+)
+
+const (
+	// DefaultQuiescenceTimeout is the default maximum duration of a
+	// quiescence session, after which we disconnect from our peer to
+	// unlock the channel.
+	DefaultQuiescenceTimeout = time.Minute
+)
+
+// stfuReq is a local request to quiesce a channel. It is resolved with the
+// initiator of the quiescence session once the channel is quiescent.
+type stfuReq = chan<- fn.Result[lntypes.ChannelParty]
+
+// QuiescerCfg holds the configuration of a quiescer.
+type QuiescerCfg struct {
+	// chanID is the channel that the quiescer manages.
+	chanID lnwire.ChannelID
+
+	// channelInitiator is the party that opened the channel. It becomes
+	// the initiator of the quiescence session if both parties request
+	// quiescence at the same time.
+	channelInitiator lntypes.ChannelParty
+
+	// numPendingUpdates returns the number of updates originated by
+	// whoseUpdates that haven't been committed to the tip of whoseCommit's
+	// commitment chain.
+	numPendingUpdates func(whoseUpdates,
+		whoseCommit lntypes.ChannelParty) uint64
+
+	// sendMsg sends the given stfu message to our peer.
+	sendMsg func(lnwire.Stfu) error
+
+	// timeout is the maximum duration of a quiescence session, measured
+	// from the first stfu that is requested, sent or received until the
+	// session is resumed. No timeout is enforced if it is zero.
+	timeout time.Duration
+
+	// onTimeout is called once a quiescence session exceeds the timeout.
+	// It is called from a separate goroutine and must not block.
+	onTimeout func()
+}
+
+// quiescer implements the state machine of the quiescence protocol, in which
+// both parties of a channel agree to stop sending updates by exchanging stfu
+// messages. A channel is quiescent once both parties have sent stfu. The
+// quiescer doesn't send or receive any messages on its own, it is driven by
+// the link.
+type quiescer struct {
+	cfg QuiescerCfg
+
+	// localInit indicates whether we requested quiescence.
+	localInit bool
+
+	// remoteInit indicates whether our peer requested quiescence.
+	remoteInit bool
+
+	// sent indicates whether we sent stfu.
+	sent bool
+
+	// received indicates whether we received stfu.
+	received bool
+
+	// activeQuiescenceReq is the pending local request to quiesce the
+	// channel.
+	activeQuiescenceReq fn.Option[stfuReq]
+
+	// timer fires once the current quiescence session exceeds its
+	// timeout. It is nil outside of a session.
+	timer *time.Timer
+
+	sync.RWMutex
+}
+
+// newQuiescer creates a new quiescer for the given channel.
+func newQuiescer(cfg QuiescerCfg) *quiescer {
+	return &quiescer{
+		cfg: cfg,
+	}
+}
+
+// hasPendingUpdates returns whether any of the given party's updates haven't
+// been committed to both commitments yet.
+func (q *quiescer) hasPendingUpdates(party lntypes.ChannelParty) bool {
+	return q.cfg.numPendingUpdates(party, lntypes.Local) > 0 ||
+		q.cfg.numPendingUpdates(party, lntypes.Remote) > 0
+}
+
+// recvStfu processes an stfu message received from our peer.
+func (q *quiescer) recvStfu(msg lnwire.Stfu) error {
+	q.Lock()
+	defer q.Unlock()
+
+	if msg.ChanID != q.cfg.chanID {
+		return fmt.Errorf("stfu for wrong channel %v", msg.ChanID)
+	}
+
+	if q.received {
+		return ErrStfuAlreadyRcvd
+	}
+
+	// Our peer must not send stfu while any of its updates are pending.
+	if q.hasPendingUpdates(lntypes.Remote) {
+		return ErrPendingRemoteUpdates
+	}
+
+	q.received = true
+	q.remoteInit = msg.Initiator
+	q.startTimeoutLocked()
+
+	// If we had sent stfu already, the channel is now quiescent.
+	q.tryResolveReq()
+
+	return nil
+}
+
+// canSendStfu returns whether we are allowed to send stfu now. The caller
+// must hold the mutex.
+func (q *quiescer) canSendStfu() bool {
+	return !q.sent && !q.hasPendingUpdates(lntypes.Local)
+}
+
+// oweStfu returns whether we need to send stfu, either because we requested
+// quiescence or because our peer did. The caller must hold the mutex.
+func (q *quiescer) oweStfu() bool {
+	return !q.sent && (q.localInit || q.received)
+}
+
+// sendOwedStfu sends stfu to our peer if we owe one and all of our updates
+// have been committed. It is a no-op otherwise.
+func (q *quiescer) sendOwedStfu() error {
+	q.Lock()
+	defer q.Unlock()
+
+	if !q.oweStfu() || !q.canSendStfu() {
+		return nil
+	}
+
+	err := q.cfg.sendMsg(lnwire.Stfu{
+		ChanID:    q.cfg.chanID,
+		Initiator: q.localInit,
+	})
+	if err != nil {
+		return err
+	}
+
+	q.sent = true
+	q.startTimeoutLocked()
+
+	// If we had received stfu already, the channel is now quiescent.
+	q.tryResolveReq()
+
+	return nil
+}
+
+// initStfu starts a local request to quiesce the channel. The request is
+// resolved with the initiator of the quiescence session once the channel is
+// quiescent, which may be our peer if it requested quiescence at the same
+// time.
+func (q *quiescer) initStfu(req stfuReq) {
+	q.Lock()
+	defer q.Unlock()
+
+	if q.activeQuiescenceReq.IsSome() {
+		req <- fn.Err[lntypes.ChannelParty](ErrQuiescenceInProgress)
+		return
+	}
+
+	// If the channel is quiescent already, because our peer requested it,
+	// there's nothing left to do.
+	if q.isQuiescentLocked() {
+		req <- q.initiatorLocked()
+		return
+	}
+
+	// If our peer already requested quiescence, our stfu will be a
+	// response to theirs.
+	if !q.received {
+		q.localInit = true
+	}
+	q.activeQuiescenceReq = fn.Some(req)
+	q.startTimeoutLocked()
+}
+
+// resume ends the quiescence session once the protocol that required the
+// channel to be quiescent is done, allowing both parties to send updates
+// again. Both parties resume on their own, there is no message to signal it.
+func (q *quiescer) resume() error {
+	q.Lock()
+	defer q.Unlock()
+
+	if !q.isQuiescentLocked() {
+		return ErrNotQuiescent
+	}
+
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+
+	q.localInit = false
+	q.remoteInit = false
+	q.sent = false
+	q.received = false
+
+	return nil
+}
+
+// startTimeoutLocked starts the timeout of the quiescence session, unless it
+// is running already. The caller must hold the mutex.
+func (q *quiescer) startTimeoutLocked() {
+	if q.timer != nil || q.cfg.timeout == 0 || q.cfg.onTimeout == nil {
+		return
+	}
+
+	// The timer is only assigned once we release the mutex, so the
+	// callback can tell whether it still belongs to the current session.
+	var timer *time.Timer
+	timer = time.AfterFunc(q.cfg.timeout, func() {
+		q.RLock()
+		current := q.timer == timer
+		q.RUnlock()
+
+		if current {
+			q.cfg.onTimeout()
+		}
+	})
+	q.timer = timer
+}
+
+// isQuiescing returns whether a quiescence session is in progress, which is
+// the case from the moment either party requests quiescence until the session
+// is resumed.
+func (q *quiescer) isQuiescing() bool {
+	q.RLock()
+	defer q.RUnlock()
+
+	return q.localInit || q.sent || q.received
+}
+
+// tryResolveReq resolves the pending local quiescence request if the channel
+// is quiescent. The caller must hold the mutex.
+func (q *quiescer) tryResolveReq() {
+	if !q.isQuiescentLocked() {
+		return
+	}
+
+	q.activeQuiescenceReq.WhenSome(func(req stfuReq) {
+		req <- q.initiatorLocked()
+	})
+	q.activeQuiescenceReq = fn.None[stfuReq]()
+}
+
+// isQuiescent returns whether both parties have sent stfu.
+func (q *quiescer) isQuiescent() bool {
+	q.RLock()
+	defer q.RUnlock()
+
+	return q.isQuiescentLocked()
+}
+
+// isQuiescentLocked returns whether both parties have sent stfu. The caller
+// must hold the mutex.
+func (q *quiescer) isQuiescentLocked() bool {
+	return q.sent && q.received
+}
+
+// quiescenceInitiator returns the initiator of the quiescence session.
+func (q *quiescer) quiescenceInitiator() fn.Result[lntypes.ChannelParty] {
+	q.RLock()
+	defer q.RUnlock()
+
+	return q.initiatorLocked()
+}
+
+// initiatorLocked returns the initiator of the quiescence session.
+// If both parties requested quiescence, the party that opened the channel is
+// the initiator. The caller must hold the mutex.
+func (q *quiescer) initiatorLocked() fn.Result[lntypes.ChannelParty] {
+	switch {
+	case !q.isQuiescentLocked():
+		return fn.Err[lntypes.ChannelParty](ErrNoQuiescenceInitiator)
+
+	case q.localInit && q.remoteInit:
+		return fn.Ok(q.cfg.channelInitiator)
+
+	case q.localInit:
+		return fn.Ok(lntypes.Local)
+
+	case q.remoteInit:
+		return fn.Ok(lntypes.Remote)
+	}
+
+	// Both parties sent stfu without requesting quiescence, which can't
+	// happen with a well-behaved peer.
+	return fn.Err[lntypes.ChannelParty](ErrNoQuiescenceInitiator)
+}
+
+// canSendUpdates returns whether we may send updates to our peer. Once either
+// party requested quiescence, we must not send updates anymore.
+func (q *quiescer) canSendUpdates() bool {
+	q.RLock()
+	defer q.RUnlock()
+
+	return !q.sent && !q.localInit && !q.received
+}
+
+// canRecvUpdates returns whether our peer may send us updates. Once our peer
+// sent stfu, it must not send updates anymore.
+func (q *quiescer) canRecvUpdates() bool {
+	q.RLock()
+	defer q.RUnlock()
+
+	return !q.received
+}
@@ -0,0 +1,250 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// quiescerTestHarness wraps a quiescer with a controllable number of pending
+// updates and records the stfu messages it sends.
+type quiescerTestHarness struct {
+	*quiescer
+
+	pending lntypes.Dual[uint64]
+	sent    []lnwire.Stfu
+}
+
+func newQuiescerTestHarness(
+	channelInitiator lntypes.ChannelParty) *quiescerTestHarness {
+
+	h := &quiescerTestHarness{}
+	h.quiescer = newQuiescer(QuiescerCfg{
+		chanID:           lnwire.ChannelID{1},
+		channelInitiator: channelInitiator,
+		numPendingUpdates: func(whoseUpdates,
+			_ lntypes.ChannelParty) uint64 {
+
+			return h.pending.GetForParty(whoseUpdates)
+		},
+		sendMsg: func(stfu lnwire.Stfu) error {
+			h.sent = append(h.sent, stfu)
+			return nil
+		},
+	})
+
+	return h
+}
+
+// TestQuiescerLocalInit tests that we only send stfu once our updates are
+// committed, and that the channel is quiescent once our peer responds.
+func TestQuiescerLocalInit(t *testing.T) {
+	t.Parallel()
+
+	h := newQuiescerTestHarness(lntypes.Remote)
+	h.pending.Local = 1
+
+	req := make(chan fn.Result[lntypes.ChannelParty], 1)
+	h.initStfu(req)
+	require.False(t, h.canSendUpdates())
+	require.True(t, h.canRecvUpdates())
+
+	// We can't send stfu while our updates are pending.
+	require.NoError(t, h.sendOwedStfu())
+	require.Empty(t, h.sent)
+
+	h.pending.Local = 0
+	require.NoError(t, h.sendOwedStfu())
+	require.Equal(t, []lnwire.Stfu{{
+		ChanID:    lnwire.ChannelID{1},
+		Initiator: true,
+	}}, h.sent)
+
+	// We only send stfu once.
+	require.NoError(t, h.sendOwedStfu())
+	require.Len(t, h.sent, 1)
+	require.False(t, h.isQuiescent())
+
+	// Once our peer responds, the channel is quiescent and we are the
+	// initiator.
+	require.NoError(t, h.recvStfu(lnwire.Stfu{
+		ChanID: lnwire.ChannelID{1},
+	}))
+	require.True(t, h.isQuiescent())
+	require.False(t, h.canRecvUpdates())
+
+	initiator, err := (<-req).Unpack()
+	require.NoError(t, err)
+	require.Equal(t, lntypes.Local, initiator)
+
+	// A second stfu is a protocol violation.
+	require.ErrorIs(t, h.recvStfu(lnwire.Stfu{
+		ChanID: lnwire.ChannelID{1},
+	}), ErrStfuAlreadyRcvd)
+}
+
+// TestQuiescerRemoteInit tests that we respond to the stfu of our peer, and
+// that stfu is rejected while our peer has pending updates.
+func TestQuiescerRemoteInit(t *testing.T) {
+	t.Parallel()
+
+	h := newQuiescerTestHarness(lntypes.Local)
+
+	stfu := lnwire.Stfu{
+		ChanID:    lnwire.ChannelID{1},
+		Initiator: true,
+	}
+
+	h.pending.Remote = 1
+	require.ErrorIs(t, h.recvStfu(stfu), ErrPendingRemoteUpdates)
+
+	h.pending.Remote = 0
+	require.NoError(t, h.recvStfu(stfu))
+	require.False(t, h.canSendUpdates())
+	require.False(t, h.canRecvUpdates())
+
+	// The initiator is only known once the channel is quiescent.
+	require.ErrorIs(
+		t, h.quiescenceInitiator().Err(), ErrNoQuiescenceInitiator,
+	)
+
+	// A local request while our response is owed doesn't make us the
+	// initiator.
+	req := make(chan fn.Result[lntypes.ChannelParty], 1)
+	h.initStfu(req)
+
+	require.NoError(t, h.sendOwedStfu())
+	require.Equal(t, []lnwire.Stfu{{
+		ChanID: lnwire.ChannelID{1},
+	}}, h.sent)
+	require.True(t, h.isQuiescent())
+
+	initiator, err := (<-req).Unpack()
+	require.NoError(t, err)
+	require.Equal(t, lntypes.Remote, initiator)
+
+	// Requests for a quiescent channel are resolved immediately.
+	h.initStfu(req)
+	initiator, err = (<-req).Unpack()
+	require.NoError(t, err)
+	require.Equal(t, lntypes.Remote, initiator)
+}
+
+// TestQuiescerTieBreak tests that the party that opened the channel is the
+// initiator if both parties request quiescence at the same time.
+func TestQuiescerTieBreak(t *testing.T) {
+	t.Parallel()
+
+	for _, opener := range []lntypes.ChannelParty{
+		lntypes.Local, lntypes.Remote,
+	} {
+		h := newQuiescerTestHarness(opener)
+
+		req := make(chan fn.Result[lntypes.ChannelParty], 1)
+		h.initStfu(req)
+
+		// Only one request may be active at a time.
+		req2 := make(chan fn.Result[lntypes.ChannelParty], 1)
+		h.initStfu(req2)
+		require.ErrorIs(t, (<-req2).Err(), ErrQuiescenceInProgress)
+
+		require.NoError(t, h.sendOwedStfu())
+		require.NoError(t, h.recvStfu(lnwire.Stfu{
+			ChanID:    lnwire.ChannelID{1},
+			Initiator: true,
+		}))
+
+		initiator, err := (<-req).Unpack()
+		require.NoError(t, err)
+		require.Equal(t, opener, initiator)
+	}
+}
+
+// TestQuiescerResume tests that a quiescent channel can be resumed, after
+// which both parties may send updates and a new session can be started.
+func TestQuiescerResume(t *testing.T) {
+	t.Parallel()
+
+	h := newQuiescerTestHarness(lntypes.Local)
+
+	// A channel that isn't quiescent can't be resumed.
+	require.ErrorIs(t, h.resume(), ErrNotQuiescent)
+
+	req := make(chan fn.Result[lntypes.ChannelParty], 1)
+	h.initStfu(req)
+	require.NoError(t, h.sendOwedStfu())
+	require.ErrorIs(t, h.resume(), ErrNotQuiescent)
+
+	require.NoError(t, h.recvStfu(lnwire.Stfu{
+		ChanID: lnwire.ChannelID{1},
+	}))
+	require.NoError(t, (<-req).Err())
+
+	require.NoError(t, h.resume())
+	require.False(t, h.isQuiescent())
+	require.False(t, h.isQuiescing())
+	require.True(t, h.canSendUpdates())
+	require.True(t, h.canRecvUpdates())
+
+	// Our peer may start a new session, which we respond to.
+	require.NoError(t, h.recvStfu(lnwire.Stfu{
+		ChanID:    lnwire.ChannelID{1},
+		Initiator: true,
+	}))
+	require.NoError(t, h.sendOwedStfu())
+	require.Len(t, h.sent, 2)
+	require.True(t, h.isQuiescent())
+
+	initiator, err := h.quiescenceInitiator().Unpack()
+	require.NoError(t, err)
+	require.Equal(t, lntypes.Remote, initiator)
+}
+
+// TestQuiescerTimeout tests that a quiescence session that isn't resumed in
+// time times out, and that resumed sessions don't.
+func TestQuiescerTimeout(t *testing.T) {
+	t.Parallel()
+
+	h := newQuiescerTestHarness(lntypes.Local)
+
+	timeouts := make(chan struct{}, 1)
+	h.cfg.timeout = 50 * time.Millisecond
+	h.cfg.onTimeout = func() {
+		timeouts <- struct{}{}
+	}
+
+	// A session that is resumed in time doesn't time out.
+	require.NoError(t, h.recvStfu(lnwire.Stfu{
+		ChanID:    lnwire.ChannelID{1},
+		Initiator: true,
+	}))
+	require.NoError(t, h.sendOwedStfu())
+	require.NoError(t, h.resume())
+
+	select {
+	case <-timeouts:
+		t.Fatal("resumed session timed out")
+	case <-time.After(2 * h.cfg.timeout):
+	}
+
+	// A single stfu of our peer starts the timeout, even if we never
+	// respond because our updates remain pending.
+	h.pending.Local = 1
+	require.NoError(t, h.recvStfu(lnwire.Stfu{
+		ChanID:    lnwire.ChannelID{1},
+		Initiator: true,
+	}))
+	require.NoError(t, h.sendOwedStfu())
+	require.Len(t, h.sent, 1)
+
+	select {
+	case <-timeouts:
+	case <-time.After(time.Second):
+		t.Fatal("session didn't time out")
+	}
+	require.True(t, h.isQuiescing())
+}
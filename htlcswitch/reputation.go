@@ -0,0 +1,320 @@
+package htlcswitch
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// DefaultReputationDecayWindow is the default period over which the
+	// reputation of a channel decays.
+	DefaultReputationDecayWindow = 14 * 24 * time.Hour
+
+	// DefaultResolutionPeriod is the default time in which we expect an
+	// htlc to be resolved. Endorsed htlcs that are held longer reduce the
+	// reputation of their incoming channel.
+	DefaultResolutionPeriod = 90 * time.Second
+)
+
+// ReputationConfig houses the parameters of the reputation tracker.
+type ReputationConfig struct {
+	// DecayWindow is the period over which the reputation of a channel
+	// decays, so that recent behavior of the peer weighs more than its
+	// past behavior.
+	DecayWindow time.Duration
+
+	// ResolutionPeriod is the time in which we expect an htlc to be
+	// resolved. For each period an endorsed htlc is held, the fee it
+	// offered is deducted from the reputation of its incoming channel.
+	ResolutionPeriod time.Duration
+
+	// Clock is the time source of the tracker.
+	Clock clock.Clock
+}
+
+// ChannelReputation is a snapshot of the reputation of an incoming channel.
+type ChannelReputation struct {
+	// ShortChanID is the short channel id of the incoming channel.
+	ShortChanID lnwire.ShortChannelID
+
+	// Reputation is the decayed sum of the fees that the htlcs from the
+	// channel have paid us, less the penalties for endorsed htlcs that
+	// were held beyond the resolution period, in milli-satoshis. It may
+	// be negative.
+	Reputation int64
+
+	// InFlightRisk is the sum of the fees offered by the endorsed htlcs
+	// from the channel that are currently in flight.
+	InFlightRisk lnwire.MilliSatoshi
+
+	// NumInFlight is the number of htlcs from the channel that are
+	// currently in flight.
+	NumInFlight int
+}
+
+// GoodReputation returns whether the channel has earned enough reputation to
+// have an htlc that offers the given fee endorsed, on top of the endorsed
+// htlcs it already has in flight.
+func (c ChannelReputation) GoodReputation(fee lnwire.MilliSatoshi) bool {
+	return c.Reputation >= int64(c.InFlightRisk+fee)
+}
+
+// chanReputation holds the reputation state of a single incoming channel.
+type chanReputation struct {
+	// value is the decayed reputation of the channel as of lastUpdate.
+	value float64
+
+	// lastUpdate is the time the value was last decayed.
+	lastUpdate time.Time
+
+	// inFlightRisk is the sum of the fees offered by the endorsed htlcs
+	// from the channel that are currently in flight.
+	inFlightRisk lnwire.MilliSatoshi
+
+	// numInFlight is the number of htlcs from the channel that are
+	// currently in flight.
+	numInFlight int
+}
+
+// trackedHtlc is an htlc that is forwarded and not yet resolved.
+type trackedHtlc struct {
+	fee      lnwire.MilliSatoshi
+	endorsed bool
+	addedAt  time.Time
+}
+
+// ReputationTracker tracks the reputation of our incoming channels for the
+// experimental htlc endorsement scheme. A channel earns reputation with the
+// fees of the htlcs it forwards through us, and loses it when it endorses
+// htlcs that are held for long, since those lock up our liquidity. We only
+// endorse an outgoing htlc if its incoming channel's reputation covers the
+// fees of all of its endorsed htlcs in flight.
+type ReputationTracker struct {
+	cfg ReputationConfig
+
+	mu sync.Mutex
+
+	// channels holds the reputation of each incoming channel.
+	channels map[lnwire.ShortChannelID]*chanReputation
+
+	// htlcs holds the forwarded htlcs that are in flight, keyed by their
+	// incoming circuit key.
+	htlcs map[CircuitKey]*trackedHtlc
+}
+
+// NewReputationTracker creates a new reputation tracker.
+func NewReputationTracker(cfg ReputationConfig) *ReputationTracker {
+	return &ReputationTracker{
+		cfg:      cfg,
+		channels: make(map[lnwire.ShortChannelID]*chanReputation),
+		htlcs:    make(map[CircuitKey]*trackedHtlc),
+	}
+}
+
+// channel returns the reputation state of the given channel, with its value
+// decayed to the current time. The caller must hold the mutex.
+func (r *ReputationTracker) channel(
+	scid lnwire.ShortChannelID) *chanReputation {
+
+	now := r.cfg.Clock.Now()
+
+	c, ok := r.channels[scid]
+	if !ok {
+		c = &chanReputation{
+			lastUpdate: now,
+		}
+		r.channels[scid] = c
+
+		return c
+	}
+
+	elapsed := now.Sub(c.lastUpdate)
+	if elapsed > 0 {
+		c.value *= math.Exp(
+			-float64(elapsed) / float64(r.cfg.DecayWindow),
+		)
+		c.lastUpdate = now
+	}
+
+	return c
+}
+
+// snapshot returns the reputation of the given channel. The caller must hold
+// the mutex.
+func (r *ReputationTracker) snapshot(
+	scid lnwire.ShortChannelID) ChannelReputation {
+
+	c := r.channel(scid)
+
+	return ChannelReputation{
+		ShortChanID:  scid,
+		Reputation:   int64(c.value),
+		InFlightRisk: c.inFlightRisk,
+		NumInFlight:  c.numInFlight,
+	}
+}
+
+// shouldEndorse returns whether an endorsed htlc that offers the given fee
+// and arrived on the given channel should be endorsed on the outgoing
+// channel.
+func (r *ReputationTracker) shouldEndorse(scid lnwire.ShortChannelID,
+	fee lnwire.MilliSatoshi) bool {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.snapshot(scid).GoodReputation(fee)
+}
+
+// addHtlc starts tracking a forwarded htlc. Htlcs that are already tracked,
+// for example because they are forwarded again after a restart, are ignored.
+func (r *ReputationTracker) addHtlc(key CircuitKey, fee lnwire.MilliSatoshi,
+	endorsed bool) {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.htlcs[key]; ok {
+		return
+	}
+
+	c := r.channel(key.ChanID)
+	c.numInFlight++
+	if endorsed {
+		c.inFlightRisk += fee
+	}
+
+	r.htlcs[key] = &trackedHtlc{
+		fee:      fee,
+		endorsed: endorsed,
+		addedAt:  r.cfg.Clock.Now(),
+	}
+}
+
+// resolveHtlc stops tracking a forwarded htlc and updates the reputation of
+// its incoming channel. Settled htlcs add the fee they paid us, while endorsed
+// htlcs that were held beyond the resolution period have their fee deducted
+// for each period they were held.
+func (r *ReputationTracker) resolveHtlc(key CircuitKey, settled bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	htlc, ok := r.htlcs[key]
+	if !ok {
+		return
+	}
+	delete(r.htlcs, key)
+
+	c := r.channel(key.ChanID)
+	c.numInFlight--
+	if htlc.endorsed {
+		c.inFlightRisk -= htlc.fee
+	}
+
+	var effectiveFee float64
+	if settled {
+		effectiveFee = float64(htlc.fee)
+	}
+
+	holdTime := r.cfg.Clock.Now().Sub(htlc.addedAt)
+	if htlc.endorsed && holdTime > r.cfg.ResolutionPeriod {
+		periods := math.Floor(
+			float64(holdTime) / float64(r.cfg.ResolutionPeriod),
+		)
+		effectiveFee -= periods * float64(htlc.fee)
+	}
+
+	c.value += effectiveFee
+}
+
+// Reputation returns the reputation of the given incoming channel.
+func (r *ReputationTracker) Reputation(
+	scid lnwire.ShortChannelID) ChannelReputation {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.snapshot(scid)
+}
+
+// Reputations returns the reputation of all incoming channels that we've
+// tracked htlcs for.
+func (r *ReputationTracker) Reputations() []ChannelReputation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reputations := make([]ChannelReputation, 0, len(r.channels))
+	for scid := range r.channels {
+		reputations = append(reputations, r.snapshot(scid))
+	}
+
+	return reputations
+}
+
+// incomingEndorsed returns whether the incoming htlc of the packet carries an
+// experimental endorsement signal.
+func incomingEndorsed(packet *htlcPacket) bool {
+	records := packet.inWireCustomRecords
+
+	value, ok := records[lnwire.ExperimentalEndorsementType]
+	if !ok || len(value) == 0 {
+		return false
+	}
+
+	// Any value other than endorsed, including invalid ones that use more
+	// than three bits, is treated as unendorsed.
+	return value[0] == lnwire.ExperimentalEndorsed
+}
+
+// setOutgoingEndorsement sets the experimental endorsement signal of the
+// outgoing htlc of the packet. The outgoing htlc is only endorsed if the
+// incoming htlc was endorsed, and the incoming channel has a good enough
+// reputation to cover the fees it puts at risk. It returns whether the
+// incoming htlc was endorsed.
+func (s *Switch) setOutgoingEndorsement(packet *htlcPacket,
+	htlc *lnwire.UpdateAddHTLC) bool {
+
+	endorsed := incomingEndorsed(packet)
+
+	value := byte(lnwire.ExperimentalUnendorsed)
+	fee := packet.incomingAmount - packet.amount
+	if endorsed && s.reputation.shouldEndorse(packet.incomingChanID, fee) {
+		value = lnwire.ExperimentalEndorsed
+	}
+
+	// Copy the records, so that we don't modify a map that is shared
+	// with the interceptor.
+	records := make(lnwire.CustomRecords, len(htlc.CustomRecords)+1)
+	for k, v := range htlc.CustomRecords {
+		records[k] = v
+	}
+	records[lnwire.ExperimentalEndorsementType] = []byte{value}
+	htlc.CustomRecords = records
+
+	return endorsed
+}
+
+// fwdExpEndorsement returns whether we currently forward experimental
+// endorsement signals.
+func (s *Switch) fwdExpEndorsement() bool {
+	return s.cfg.ShouldFwdExpEndorsement != nil &&
+		s.cfg.ShouldFwdExpEndorsement()
+}
+
+// ChannelReputation returns the reputation of the given incoming channel in
+// the experimental htlc endorsement scheme.
+func (s *Switch) ChannelReputation(
+	scid lnwire.ShortChannelID) ChannelReputation {
+
+	return s.reputation.Reputation(scid)
+}
+
+// ChannelReputations returns the reputation of all incoming channels that
+// forwarded htlcs while experimental endorsement was enabled.
+func (s *Switch) ChannelReputations() []ChannelReputation {
+	return s.reputation.Reputations()
+}
@@ -0,0 +1,121 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReputationTracker asserts that the reputation of an incoming channel
+// grows with the fees of settled htlcs, and shrinks when endorsed htlcs are
+// held beyond the resolution period.
+func TestReputationTracker(t *testing.T) {
+	t.Parallel()
+
+	const (
+		fee              = lnwire.MilliSatoshi(1000)
+		resolutionPeriod = time.Minute
+	)
+
+	testClock := clock.NewTestClock(time.Unix(1, 0))
+	tracker := NewReputationTracker(ReputationConfig{
+		DecayWindow:      DefaultReputationDecayWindow,
+		ResolutionPeriod: resolutionPeriod,
+		Clock:            testClock,
+	})
+
+	scid := lnwire.NewShortChanIDFromInt(1)
+	key := func(id uint64) CircuitKey {
+		return CircuitKey{ChanID: scid, HtlcID: id}
+	}
+
+	// A channel without any history has no reputation, so its endorsed
+	// htlcs aren't endorsed onwards.
+	require.False(t, tracker.shouldEndorse(scid, fee))
+
+	// Settle two unendorsed htlcs quickly. The channel earns their fees.
+	tracker.addHtlc(key(0), fee, false)
+	tracker.addHtlc(key(1), fee, false)
+	require.Equal(t, 2, tracker.Reputation(scid).NumInFlight)
+
+	tracker.resolveHtlc(key(0), true)
+	tracker.resolveHtlc(key(1), true)
+
+	reputation := tracker.Reputation(scid)
+	require.EqualValues(t, 2*fee, reputation.Reputation)
+	require.Zero(t, reputation.NumInFlight)
+
+	// Resolving an unknown htlc doesn't change the reputation.
+	tracker.resolveHtlc(key(1), true)
+	require.EqualValues(t, 2*fee, tracker.Reputation(scid).Reputation)
+
+	// The channel's reputation now covers one endorsed htlc, but not two
+	// of them.
+	require.True(t, tracker.shouldEndorse(scid, fee))
+	tracker.addHtlc(key(2), fee, true)
+	require.EqualValues(t, fee, tracker.Reputation(scid).InFlightRisk)
+	require.True(t, tracker.shouldEndorse(scid, fee))
+	tracker.addHtlc(key(3), fee, true)
+	require.False(t, tracker.shouldEndorse(scid, fee))
+
+	// Fail the first endorsed htlc after holding it for three resolution
+	// periods, which costs the channel three times its fee. Since the
+	// decay window is much larger, the decay is negligible.
+	testClock.SetTime(testClock.Now().Add(3 * resolutionPeriod))
+	tracker.resolveHtlc(key(2), false)
+
+	reputation = tracker.Reputation(scid)
+	require.InDelta(t, -int64(fee), reputation.Reputation, 1)
+	require.EqualValues(t, fee, reputation.InFlightRisk)
+	require.False(t, tracker.shouldEndorse(scid, fee))
+
+	// The second endorsed htlc was held just as long, so settling it
+	// still costs the channel two times its fee.
+	tracker.resolveHtlc(key(3), true)
+	reputation = tracker.Reputation(scid)
+	require.InDelta(t, -3*int64(fee), reputation.Reputation, 1)
+	require.Zero(t, reputation.InFlightRisk)
+	require.False(t, tracker.shouldEndorse(scid, fee))
+	require.Len(t, tracker.Reputations(), 1)
+
+	// Finally, the reputation decays over time.
+	tracker.addHtlc(key(4), 10*fee, false)
+	tracker.resolveHtlc(key(4), true)
+	before := tracker.Reputation(scid).Reputation
+
+	testClock.SetTime(testClock.Now().Add(DefaultReputationDecayWindow))
+	after := tracker.Reputation(scid).Reputation
+	require.Less(t, after, before)
+	require.Positive(t, after)
+}
+
+// TestIncomingEndorsed asserts that only the endorsed value of the
+// experimental endorsement record is interpreted as endorsed.
+func TestIncomingEndorsed(t *testing.T) {
+	t.Parallel()
+
+	packet := func(value []byte) *htlcPacket {
+		if value == nil {
+			return &htlcPacket{}
+		}
+
+		return &htlcPacket{
+			inWireCustomRecords: lnwire.CustomRecords{
+				lnwire.ExperimentalEndorsementType: value,
+			},
+		}
+	}
+
+	require.False(t, incomingEndorsed(packet(nil)))
+	require.False(t, incomingEndorsed(packet([]byte{})))
+	require.False(t, incomingEndorsed(packet(
+		[]byte{lnwire.ExperimentalUnendorsed},
+	)))
+	require.False(t, incomingEndorsed(packet([]byte{0xff})))
+	require.True(t, incomingEndorsed(packet(
+		[]byte{lnwire.ExperimentalEndorsed},
+	)))
+}
@@ -0,0 +1,273 @@
+package htlcswitch
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+// StuckHtlc describes an htlc that has been pending on one of our outgoing
+// channels for longer than the configured threshold.
+type StuckHtlc struct {
+	// Incoming is the incoming circuit key of the htlc. For locally
+	// initiated payments, the incoming channel is hop.Source.
+	Incoming CircuitKey
+
+	// Outgoing is the outgoing circuit key of the htlc.
+	Outgoing CircuitKey
+
+	// PaymentHash is the payment hash of the htlc.
+	PaymentHash [32]byte
+
+	// IncomingAmount is the amount of the incoming htlc.
+	IncomingAmount lnwire.MilliSatoshi
+
+	// OutgoingAmount is the amount of the outgoing htlc.
+	OutgoingAmount lnwire.MilliSatoshi
+
+	// FirstSeen is the time at which the detector first saw the htlc.
+	// Htlcs that were pending when the detector started are assumed to be
+	// added at that time.
+	FirstSeen time.Time
+
+	// Remediated indicates whether a remediation was attempted for the
+	// htlc.
+	Remediated bool
+
+	// reported indicates whether the htlc was reported as stuck already.
+	reported bool
+}
+
+// StuckHtlcDetectorConfig holds the configuration of the StuckHtlcDetector.
+type StuckHtlcDetectorConfig struct {
+	// Circuits gives access to the circuits of the htlcs that are pending
+	// on our outgoing channels. Only the keystones of the open circuits are
+	// scanned, a circuit is only looked up once its htlc becomes stuck.
+	Circuits CircuitLookup
+
+	// FetchLink returns the link of the given outgoing channel.
+	FetchLink func(lnwire.ShortChannelID) (ChannelLink, error)
+
+	// Threshold is the duration after which a pending htlc is considered
+	// stuck.
+	Threshold time.Duration
+
+	// ReconnectPeer is an optional hook that disconnects the given peer,
+	// so that the channels with it are reestablished on reconnection. This
+	// resolves htlcs that are stuck due to a desynchronized channel state.
+	ReconnectPeer func(peer [33]byte) error
+
+	// Ticker is used to periodically scan the circuits.
+	Ticker ticker.Ticker
+
+	// Clock is used to determine how long htlcs have been pending.
+	Clock clock.Clock
+}
+
+// StuckHtlcDetector periodically scans the htlcs that are pending on our
+// outgoing channels, and reports those that have been pending for longer than
+// a threshold to its subscribers.
+//
+// Note that failing back a stuck htlc upstream is never safe while the
+// outgoing htlc can still be settled by our peer, which is why the detector
+// doesn't attempt to do so. Remediation is limited to reconnecting the peer.
+type StuckHtlcDetector struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *StuckHtlcDetectorConfig
+
+	ntfnServer *subscribe.Server
+
+	mu sync.Mutex
+
+	// seen holds all htlcs that were pending on the last scan, indexed by
+	// their outgoing circuit key.
+	seen map[CircuitKey]*StuckHtlc
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewStuckHtlcDetector creates a new stuck htlc detector.
+func NewStuckHtlcDetector(cfg *StuckHtlcDetectorConfig) (*StuckHtlcDetector,
+	error) {
+
+	if cfg.Threshold <= 0 {
+		return nil, errors.New("stuck htlc threshold must be positive")
+	}
+
+	return &StuckHtlcDetector{
+		cfg:        cfg,
+		ntfnServer: subscribe.NewServer(),
+		seen:       make(map[CircuitKey]*StuckHtlc),
+		quit:       make(chan struct{}),
+	}, nil
+}
+
+// Start starts the detector.
+func (d *StuckHtlcDetector) Start() error {
+	var err error
+	d.started.Do(func() {
+		log.Infof("Stuck htlc detector starting with threshold %v",
+			d.cfg.Threshold)
+
+		if err = d.ntfnServer.Start(); err != nil {
+			return
+		}
+
+		d.cfg.Ticker.Resume()
+
+		d.wg.Add(1)
+		go d.detectLoop()
+	})
+
+	return err
+}
+
+// Stop stops the detector.
+func (d *StuckHtlcDetector) Stop() error {
+	var err error
+	d.stopped.Do(func() {
+		log.Info("Stuck htlc detector shutting down...")
+		defer log.Debug("Stuck htlc detector shutdown complete")
+
+		close(d.quit)
+		d.wg.Wait()
+
+		d.cfg.Ticker.Stop()
+		err = d.ntfnServer.Stop()
+	})
+
+	return err
+}
+
+// SubscribeStuckHtlcs returns a subscription that receives a StuckHtlc for
+// every htlc that becomes stuck.
+func (d *StuckHtlcDetector) SubscribeStuckHtlcs() (*subscribe.Client, error) {
+	return d.ntfnServer.Subscribe()
+}
+
+// StuckHtlcs returns the htlcs that are currently stuck.
+func (d *StuckHtlcDetector) StuckHtlcs() []StuckHtlc {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var stuck []StuckHtlc
+	for _, htlc := range d.seen {
+		if htlc.reported {
+			stuck = append(stuck, *htlc)
+		}
+	}
+
+	return stuck
+}
+
+// detectLoop scans the pending htlcs on every tick.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *StuckHtlcDetector) detectLoop() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case <-d.cfg.Ticker.Ticks():
+			d.scan()
+
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// scan updates the set of pending htlcs, and reports and remediates those
+// that became stuck since the last scan.
+func (d *StuckHtlcDetector) scan() {
+	now := d.cfg.Clock.Now()
+
+	d.mu.Lock()
+
+	pending := make(map[CircuitKey]*StuckHtlc)
+	var newlyStuck []*StuckHtlc
+	for _, keystone := range d.cfg.Circuits.OpenKeystones() {
+		htlc, ok := d.seen[keystone.OutKey]
+		if !ok {
+			htlc = &StuckHtlc{
+				Incoming:  keystone.InKey,
+				Outgoing:  keystone.OutKey,
+				FirstSeen: now,
+			}
+		}
+
+		// Only report htlcs once, when they cross the threshold.
+		if htlc.reported || now.Sub(htlc.FirstSeen) < d.cfg.Threshold {
+			pending[htlc.Outgoing] = htlc
+			continue
+		}
+
+		// The circuit is only looked up once the htlc is stuck, so
+		// that the scan doesn't require the circuits of all channels
+		// to be loaded. If it has been resolved in the meantime, the
+		// htlc is no longer pending.
+		circuit := d.cfg.Circuits.LookupOpenCircuit(htlc.Outgoing)
+		if circuit == nil {
+			continue
+		}
+
+		htlc.PaymentHash = circuit.PaymentHash
+		htlc.IncomingAmount = circuit.IncomingAmount
+		htlc.OutgoingAmount = circuit.OutgoingAmount
+		htlc.reported = true
+
+		pending[htlc.Outgoing] = htlc
+		newlyStuck = append(newlyStuck, htlc)
+	}
+	d.seen = pending
+
+	d.mu.Unlock()
+
+	for _, htlc := range newlyStuck {
+		log.Warnf("Htlc %v -> %v with payment hash %x pending since %v",
+			htlc.Incoming, htlc.Outgoing, htlc.PaymentHash,
+			htlc.FirstSeen)
+
+		d.remediate(htlc)
+
+		if err := d.ntfnServer.SendUpdate(*htlc); err != nil {
+			log.Errorf("Unable to send stuck htlc update: %v", err)
+		}
+	}
+}
+
+// remediate attempts to resolve a stuck htlc by reconnecting the peer of its
+// outgoing channel.
+func (d *StuckHtlcDetector) remediate(htlc *StuckHtlc) {
+	if d.cfg.ReconnectPeer == nil {
+		return
+	}
+
+	link, err := d.cfg.FetchLink(htlc.Outgoing.ChanID)
+	if err != nil {
+		log.Debugf("Unable to fetch link of stuck htlc %v: %v",
+			htlc.Outgoing, err)
+
+		return
+	}
+
+	peer := link.PeerPubKey()
+	log.Infof("Reconnecting peer %x to resolve stuck htlc %v", peer,
+		htlc.Outgoing)
+
+	d.mu.Lock()
+	htlc.Remediated = true
+	d.mu.Unlock()
+
+	if err := d.cfg.ReconnectPeer(peer); err != nil {
+		log.Errorf("Unable to reconnect peer %x: %v", peer, err)
+	}
+}
@@ -0,0 +1,153 @@
+package htlcswitch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// mockCircuitLookup is a CircuitLookup that returns a fixed set of open
+// circuits.
+type mockCircuitLookup struct {
+	circuits []*PaymentCircuit
+}
+
+func (m *mockCircuitLookup) LookupCircuit(CircuitKey) *PaymentCircuit {
+	return nil
+}
+
+func (m *mockCircuitLookup) LookupOpenCircuit(
+	outKey CircuitKey) *PaymentCircuit {
+
+	for _, circuit := range m.circuits {
+		if circuit.HasKeystone() && circuit.OutKey() == outKey {
+			return circuit
+		}
+	}
+
+	return nil
+}
+
+func (m *mockCircuitLookup) OpenKeystones() []Keystone {
+	var keystones []Keystone
+	for _, circuit := range m.circuits {
+		if circuit.HasKeystone() {
+			keystones = append(keystones, Keystone{
+				InKey:  circuit.Incoming,
+				OutKey: circuit.OutKey(),
+			})
+		}
+	}
+
+	return keystones
+}
+
+// TestStuckHtlcDetector tests that htlcs that are pending for longer than the
+// threshold are reported and remediated once.
+func TestStuckHtlcDetector(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	chanID := lnwire.NewShortChanIDFromInt(1)
+	link := newMockChannelLink(
+		nil, lnwire.ChannelID{1}, chanID, hop.Source, alicePeer, true,
+		false, false, false,
+	)
+
+	outKey := CircuitKey{ChanID: chanID, HtlcID: 2}
+	circuits := &mockCircuitLookup{
+		circuits: []*PaymentCircuit{
+			{
+				Incoming: CircuitKey{
+					ChanID: lnwire.NewShortChanIDFromInt(2),
+					HtlcID: 1,
+				},
+				Outgoing:       &outKey,
+				PaymentHash:    [32]byte{1},
+				IncomingAmount: 1100,
+				OutgoingAmount: 1000,
+			},
+
+			// A half-open circuit is ignored.
+			{
+				Incoming: CircuitKey{
+					ChanID: lnwire.NewShortChanIDFromInt(2),
+					HtlcID: 2,
+				},
+			},
+		},
+	}
+
+	var reconnected [][33]byte
+	now := time.Unix(1000, 0)
+	testClock := clock.NewTestClock(now)
+	detector, err := NewStuckHtlcDetector(&StuckHtlcDetectorConfig{
+		Circuits: circuits,
+		FetchLink: func(lnwire.ShortChannelID) (ChannelLink, error) {
+			return link, nil
+		},
+		Threshold: time.Minute,
+		ReconnectPeer: func(peer [33]byte) error {
+			reconnected = append(reconnected, peer)
+			return nil
+		},
+		Ticker: ticker.NewForce(time.Hour),
+		Clock:  testClock,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, detector.Start())
+	t.Cleanup(func() {
+		require.NoError(t, detector.Stop())
+	})
+
+	client, err := detector.SubscribeStuckHtlcs()
+	require.NoError(t, err)
+	defer client.Cancel()
+
+	// The ticker is never forced, so that we can scan synchronously. The
+	// htlc isn't stuck when it is first seen.
+	detector.scan()
+	require.Empty(t, detector.StuckHtlcs())
+
+	// Once the threshold has passed, the htlc is reported and its peer is
+	// reconnected.
+	testClock.SetTime(now.Add(time.Minute))
+	detector.scan()
+
+	select {
+	case update := <-client.Updates():
+		htlc, ok := update.(StuckHtlc)
+		require.True(t, ok)
+		require.Equal(t, outKey, htlc.Outgoing)
+		require.Equal(t, [32]byte{1}, htlc.PaymentHash)
+		require.EqualValues(t, 1000, htlc.OutgoingAmount)
+		require.Equal(t, now, htlc.FirstSeen)
+		require.True(t, htlc.Remediated)
+
+	case <-time.After(time.Second):
+		t.Fatal("stuck htlc not reported")
+	}
+	require.Equal(t, [][33]byte{alicePeer.PubKey()}, reconnected)
+	require.Len(t, detector.StuckHtlcs(), 1)
+
+	// The htlc is only reported once.
+	testClock.SetTime(now.Add(2 * time.Minute))
+	detector.scan()
+	require.Len(t, reconnected, 1)
+	require.Len(t, detector.StuckHtlcs(), 1)
+
+	// Once the htlc is resolved, it is no longer stuck.
+	circuits.circuits = nil
+	detector.scan()
+	require.Empty(t, detector.StuckHtlcs())
+}
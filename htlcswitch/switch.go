@@ -213,6 +213,27 @@ type Config struct {
 	// a mailbox via AddPacket.
 	MailboxDeliveryTimeout time.Duration
 
+	// MailboxMaxAdds is the maximum number of adds that are queued in the
+	// mailbox of a single link. A value of zero means that the number of
+	// queued adds isn't limited. Adds of local payments are never limited.
+	MailboxMaxAdds int
+
+	// MailboxOverflowPolicy determines how forwarded adds are handled once
+	// a mailbox holds MailboxMaxAdds adds.
+	MailboxOverflowPolicy MailboxOverflowPolicy
+
+	// MailboxMaxInFlightAdds is the maximum number of adds that are
+	// delivered to the links, but not yet processed by them, across all
+	// mailboxes. Delivery slots are handed out fairly across the mailboxes
+	// once the limit is reached. A value of zero disables the limit.
+	MailboxMaxInFlightAdds int
+
+	// MailboxWeight returns the weight of the mailbox of the given channel
+	// when delivery slots are handed out. A mailbox with a higher weight
+	// receives proportionally more slots. If nil, all mailboxes have the
+	// same weight.
+	MailboxWeight func(lnwire.ChannelID) uint32
+
 	// MaxFeeExposure is the threshold in milli-satoshis after which we'll
 	// fail incoming or outgoing payments for a particular channel.
 	MaxFeeExposure lnwire.MilliSatoshi
@@ -226,6 +247,13 @@ type Config struct {
 
 	// IsAlias returns whether or not a given SCID is an alias.
 	IsAlias func(scid lnwire.ShortChannelID) bool
+
+	// ShouldFwdExpEndorsement returns whether experimental endorsement
+	// signals should be forwarded. If so, the switch tracks the reputation
+	// of the incoming channels, and only endorses an outgoing htlc if its
+	// incoming htlc was endorsed by a channel with a good reputation. If
+	// nil, no endorsement signals are forwarded.
+	ShouldFwdExpEndorsement func() bool
 }
 
 // Switch is the central messaging bus for all incoming/outgoing HTLCs.
@@ -245,6 +273,15 @@ type Switch struct {
 	// This will be retrieved by the registered links atomically.
 	bestHeight uint32
 
+	// feeExposureLimit is the threshold in milli-satoshis after which
+	// we'll fail incoming or outgoing dust htlcs. It is initialized from
+	// the config, and can be updated at runtime.
+	feeExposureLimit atomic.Uint64
+
+	// reputation tracks the reputation of our incoming channels to
+	// decide whether to endorse the htlcs they forward.
+	reputation *ReputationTracker
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 
@@ -387,12 +424,29 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 
 	s.aliasToReal = make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
 	s.baseIndex = make(map[lnwire.ShortChannelID]lnwire.ShortChannelID)
+	s.feeExposureLimit.Store(uint64(cfg.MaxFeeExposure))
+	s.reputation = NewReputationTracker(ReputationConfig{
+		DecayWindow:      DefaultReputationDecayWindow,
+		ResolutionPeriod: DefaultResolutionPeriod,
+		Clock:            cfg.Clock,
+	})
+
+	// Only limit the number of adds in flight across all mailboxes if
+	// requested.
+	var scheduler *mailboxScheduler
+	if cfg.MailboxMaxInFlightAdds > 0 {
+		scheduler = newMailboxScheduler(cfg.MailboxMaxInFlightAdds)
+	}
 
 	s.mailOrchestrator = newMailOrchestrator(&mailOrchConfig{
 		forwardPackets:    s.ForwardPackets,
 		clock:             s.cfg.Clock,
 		expiry:            s.cfg.MailboxDeliveryTimeout,
 		failMailboxUpdate: s.failMailboxUpdate,
+		maxAdds:           s.cfg.MailboxMaxAdds,
+		overflowPolicy:    s.cfg.MailboxOverflowPolicy,
+		scheduler:         scheduler,
+		weight:            s.cfg.MailboxWeight,
 	})
 
 	return s, nil
@@ -564,7 +618,7 @@ func (s *Switch) SendHTLC(firstHop lnwire.ShortChannelID, attemptID uint64,
 		// link. Incoming timelock/amount values are not set because
 		// they are not present for local sends.
 		s.cfg.HtlcNotifier.NotifyLinkFailEvent(
-			newHtlcKey(packet),
+			newCorrelationID(packet), newHtlcKey(packet),
 			HtlcInfo{
 				OutgoingTimeLock: htlc.Expiry,
 				OutgoingAmt:      htlc.Amount,
@@ -587,7 +641,7 @@ func (s *Switch) SendHTLC(firstHop lnwire.ShortChannelID, attemptID uint64,
 			&lnwire.FailTemporaryChannelFailure{},
 		)
 		s.cfg.HtlcNotifier.NotifyLinkFailEvent(
-			newHtlcKey(packet),
+			newCorrelationID(packet), newHtlcKey(packet),
 			HtlcInfo{
 				OutgoingTimeLock: htlc.Expiry,
 				OutgoingAmt:      htlc.Amount,
@@ -989,16 +1043,21 @@ func (s *Switch) handleLocalResponse(pkt *htlcPacket) {
 	}
 
 	// Finally, notify on the htlc failure or success that has been handled.
+	id := newCorrelationID(pkt)
 	key := newHtlcKey(pkt)
+	info := newResolvedHtlcInfo(pkt)
 	eventType := getEventType(pkt)
 
 	switch htlc := pkt.htlc.(type) {
 	case *lnwire.UpdateFulfillHTLC:
-		s.cfg.HtlcNotifier.NotifySettleEvent(key, htlc.PaymentPreimage,
-			eventType)
+		s.cfg.HtlcNotifier.NotifySettleEvent(
+			id, key, info, htlc.PaymentPreimage, eventType,
+		)
 
 	case *lnwire.UpdateFailHTLC:
-		s.cfg.HtlcNotifier.NotifyForwardingFailEvent(key, eventType)
+		s.cfg.HtlcNotifier.NotifyForwardingFailEvent(
+			id, key, info, eventType, pkt.isResolution,
+		)
 	}
 }
 
@@ -1026,9 +1085,35 @@ func (s *Switch) extractResult(deobfuscator ErrorDecrypter, n *networkResult,
 			n.isResolution, htlc,
 		)
 
-		return &PaymentResult{
+		result := &PaymentResult{
 			Error: paymentErr,
-		}, nil
+		}
+
+		// Failures that have been received from the remote party may
+		// carry attribution data, which allows the router to blame a
+		// specific hop even if the failure couldn't be decrypted.
+		if n.unencrypted || htlc.Reason == nil {
+			return result, nil
+		}
+
+		attrData, err := htlc.AttributionData()
+		if err != nil {
+			log.Warnf("Invalid attribution data (hash=%v, "+
+				"pid=%d): %v", paymentHash, attemptID, err)
+
+			return result, nil
+		}
+
+		result.Attribution = fn.MapOption(
+			func(data lnwire.AttributionData) FailureAttribution {
+				return FailureAttribution{
+					Reason: htlc.Reason,
+					Data:   data,
+				}
+			},
+		)(attrData)
+
+		return result, nil
 
 	default:
 		return nil, fmt.Errorf("received unknown response type: %T",
@@ -1418,7 +1503,10 @@ func (s *Switch) CloseLink(chanPoint *wire.OutPoint,
 	deliveryScript lnwire.DeliveryAddress) (chan interface{}, chan error) {
 
 	// TODO(roasbeef) abstract out the close updates.
-	updateChan := make(chan interface{}, 2)
+	//
+	// The buffer leaves room for a fee negotiation update in addition to
+	// the pending and final updates of the close.
+	updateChan := make(chan interface{}, 3)
 	errChan := make(chan error, 1)
 
 	command := &ChanClose{
@@ -2036,6 +2124,10 @@ func (s *Switch) AddLink(link ChannelLink) error {
 	// Attach the Switch's failAliasUpdate function to the link.
 	link.attachFailAliasUpdate(s.failAliasUpdate)
 
+	// Make sure the link uses the current fee exposure threshold, which
+	// may have been updated since the link was created.
+	link.setMaxFeeExposure(s.maxFeeExposure())
+
 	if err := link.Start(); err != nil {
 		log.Errorf("AddLink failed to start link with chanID=%v: %v",
 			chanID, err)
@@ -2580,7 +2672,7 @@ func (s *Switch) dustExceedsFeeThreshold(link ChannelLink,
 		}
 
 		// Finally check against the defined fee threshold.
-		if localSum > s.cfg.MaxFeeExposure {
+		if localSum > s.maxFeeExposure() {
 			return true
 		}
 	}
@@ -2600,7 +2692,7 @@ func (s *Switch) dustExceedsFeeThreshold(link ChannelLink,
 		}
 
 		// Finally check against the defined fee threshold.
-		if remoteSum > s.cfg.MaxFeeExposure {
+		if remoteSum > s.maxFeeExposure() {
 			return true
 		}
 	}
@@ -2986,7 +3078,38 @@ func (s *Switch) handlePacketAdd(packet *htlcPacket,
 	// channel.
 	packet.outgoingChanID = destination.ShortChanID()
 
-	return destination.handleSwitchPacket(packet)
+	// Set the endorsement signal of the outgoing htlc, if we take part in
+	// the experiment.
+	fwdEndorsement := s.fwdExpEndorsement()
+
+	var endorsed bool
+	if fwdEndorsement {
+		endorsed = s.setOutgoingEndorsement(packet, htlc)
+	}
+
+	err = destination.handleSwitchPacket(packet)
+	if err == nil && fwdEndorsement {
+		s.reputation.addHtlc(
+			packet.inKey(), packet.incomingAmount-packet.amount,
+			endorsed,
+		)
+	}
+	if errors.Is(err, ErrMailBoxFull) {
+		// The mailbox of the destination link is full, so we fail the
+		// add back rather than queueing it up.
+		log.Debugf("Mailbox of outgoing link %v is full, failing "+
+			"incoming htlc %v", packet.outgoingChanID,
+			packet.inKey())
+
+		linkErr := NewDetailedLinkError(
+			&lnwire.FailTemporaryChannelFailure{},
+			OutgoingFailureDownstreamHtlcAdd,
+		)
+
+		return s.failAddPacket(packet, linkErr)
+	}
+
+	return err
 }
 
 // handlePacketSettle handles forwarding a settle packet.
@@ -3028,6 +3151,10 @@ func (s *Switch) handlePacketSettle(packet *htlcPacket) error {
 		return nil
 	}
 
+	// The forwarded htlc was settled, which adds to the reputation of its
+	// incoming channel.
+	s.reputation.resolveHtlc(packet.inKey(), true)
+
 	// If this is an HTLC settle, and it wasn't from a locally initiated
 	// HTLC, then we'll log a forwarding event so we can flush it to disk
 	// later.
@@ -3084,6 +3211,10 @@ func (s *Switch) handlePacketFail(packet *htlcPacket,
 		return nil
 	}
 
+	// The forwarded htlc failed, so we stop tracking it for the
+	// reputation of its incoming channel.
+	s.reputation.resolveHtlc(packet.inKey(), false)
+
 	// Exit early if this hasSource is true. This flag is only set via
 	// mailbox's `FailAdd`. This method has two callsites,
 	// - the packet has timed out after `MailboxDeliveryTimeout`, defaults
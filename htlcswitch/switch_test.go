@@ -3321,8 +3321,8 @@ func TestInvalidFailure(t *testing.T) {
 // values provided. These functions take dynamic values so that changes to
 // external systems (such as our default timelock delta) do not break
 // these tests.
-type htlcNotifierEvents func(channels *clusterChannels, htlcID uint64,
-	ts time.Time, htlc *lnwire.UpdateAddHTLC,
+type htlcNotifierEvents func(channels *clusterChannels, htlcID,
+	attemptID uint64, ts time.Time, htlc *lnwire.UpdateAddHTLC,
 	hops []*hop.Payload,
 	preimage *lntypes.Preimage) ([]interface{}, []interface{}, []interface{})
 
@@ -3353,14 +3353,15 @@ func TestHtlcNotifier(t *testing.T) {
 			name:    "successful three hop payment",
 			options: nil,
 			expectedEvents: func(channels *clusterChannels,
-				htlcID uint64, ts time.Time,
+				htlcID, attemptID uint64, ts time.Time,
 				htlc *lnwire.UpdateAddHTLC,
 				hops []*hop.Payload,
 				preimage *lntypes.Preimage) ([]interface{},
 				[]interface{}, []interface{}) {
 
 				return getThreeHopEvents(
-					channels, htlcID, ts, htlc, hops, nil, preimage,
+					channels, htlcID, attemptID, ts, htlc,
+					hops, nil, preimage,
 				)
 			},
 			iterations: 2,
@@ -3373,15 +3374,15 @@ func TestHtlcNotifier(t *testing.T) {
 				serverOptionRejectHtlc(false, true, false),
 			},
 			expectedEvents: func(channels *clusterChannels,
-				htlcID uint64, ts time.Time,
+				htlcID, attemptID uint64, ts time.Time,
 				htlc *lnwire.UpdateAddHTLC,
 				hops []*hop.Payload,
 				preimage *lntypes.Preimage) ([]interface{},
 				[]interface{}, []interface{}) {
 
 				return getThreeHopEvents(
-					channels, htlcID, ts, htlc, hops,
-					&LinkError{
+					channels, htlcID, attemptID, ts, htlc,
+					hops, &LinkError{
 						msg:           &lnwire.FailChannelDisabled{},
 						FailureDetail: OutgoingFailureForwardsDisabled,
 					},
@@ -3506,10 +3507,11 @@ func testHtcNotifier(t *testing.T, testOpts []serverOption, iterations int,
 		// We'll start off by making a payment from
 		// Alice -> Bob -> Carol. The preimage, generated
 		// by Carol's Invoice is expected in the Settle events
-		htlc, hops, preimage := n.sendThreeHopPayment(t)
+		htlc, hops, attemptID, preimage := n.sendThreeHopPayment(t)
 
 		alice, bob, carol := getEvents(
-			channels, uint64(i), now, htlc, hops, preimage,
+			channels, uint64(i), attemptID, now, htlc, hops,
+			preimage,
 		)
 
 		checkHtlcEvents(t, aliceEvents.Updates(), alice)
@@ -3547,10 +3549,10 @@ func checkHtlcEvents(t *testing.T, events <-chan interface{},
 }
 
 // sendThreeHopPayment is a helper function which sends a payment over
-// Alice -> Bob -> Carol in a three hop network and returns Alice's first htlc
-// and the remainder of the hops.
+// Alice -> Bob -> Carol in a three hop network and returns Alice's first htlc,
+// the remainder of the hops and the attempt id of the payment.
 func (n *threeHopNetwork) sendThreeHopPayment(t *testing.T) (*lnwire.UpdateAddHTLC,
-	[]*hop.Payload, *lntypes.Preimage) {
+	[]*hop.Payload, uint64, *lntypes.Preimage) {
 
 	amount := lnwire.NewMSatFromSatoshis(btcutil.SatoshiPerBitcoin)
 
@@ -3578,13 +3580,13 @@ func (n *threeHopNetwork) sendThreeHopPayment(t *testing.T) (*lnwire.UpdateAddHT
 		t.Fatalf("could not send htlc")
 	}
 
-	return htlc, hops, invoice.Terms.PaymentPreimage
+	return htlc, hops, pid, invoice.Terms.PaymentPreimage
 }
 
 // getThreeHopEvents gets the set of htlc events that we expect for a payment
 // from Alice -> Bob -> Carol. If a non-nil link error is provided, the set
 // of events will fail on Bob's outgoing link.
-func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
+func getThreeHopEvents(channels *clusterChannels, htlcID, attemptID uint64,
 	ts time.Time, htlc *lnwire.UpdateAddHTLC, hops []*hop.Payload,
 	linkError *LinkError,
 	preimage *lntypes.Preimage) ([]interface{}, []interface{}, []interface{}) {
@@ -3597,11 +3599,19 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 		},
 	}
 
+	// All of alice's events are correlated by the attempt id of her
+	// payment.
+	aliceID := models.CircuitKey{
+		ChanID: hop.Source,
+		HtlcID: attemptID,
+	}
+
 	// Alice always needs a forwarding event because she initiates the
 	// send.
 	aliceEvents := []interface{}{
 		&ForwardingEvent{
-			HtlcKey: aliceKey,
+			HtlcKey:       aliceKey,
+			CorrelationID: aliceID,
 			HtlcInfo: HtlcInfo{
 				OutgoingTimeLock: htlc.Expiry,
 				OutgoingAmt:      htlc.Amount,
@@ -3629,12 +3639,24 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 		OutgoingAmt:      hops[1].FwdInfo.AmountToForward,
 	}
 
+	// Settles and forwarding failures only report the amounts of the
+	// htlc.
+	aliceResolvedInfo := HtlcInfo{
+		OutgoingAmt: htlc.Amount,
+	}
+	bobResolvedInfo := HtlcInfo{
+		IncomingAmt: bobInfo.IncomingAmt,
+		OutgoingAmt: bobInfo.OutgoingAmt,
+	}
+
 	// If we expect the payment to fail, we add failures for alice and
 	// bob, and no events for carol because the payment never reaches her.
 	if linkError != nil {
 		aliceEvents = append(aliceEvents,
 			&ForwardingFailEvent{
 				HtlcKey:       aliceKey,
+				CorrelationID: aliceID,
+				HtlcInfo:      aliceResolvedInfo,
 				HtlcEventType: HtlcEventTypeSend,
 				Timestamp:     ts,
 			},
@@ -3643,6 +3665,7 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 		bobEvents := []interface{}{
 			&LinkFailEvent{
 				HtlcKey:       bobKey,
+				CorrelationID: bobKey.IncomingCircuit,
 				HtlcInfo:      bobInfo,
 				HtlcEventType: HtlcEventTypeForward,
 				LinkError:     linkError,
@@ -3650,10 +3673,11 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 				Timestamp:     ts,
 			},
 			&FinalHtlcEvent{
-				CircuitKey: bobKey.IncomingCircuit,
-				Settled:    false,
-				Offchain:   true,
-				Timestamp:  ts,
+				CircuitKey:    bobKey.IncomingCircuit,
+				CorrelationID: bobKey.IncomingCircuit,
+				Settled:       false,
+				Offchain:      true,
+				Timestamp:     ts,
 			},
 		}
 
@@ -3667,6 +3691,8 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 		aliceEvents,
 		&SettleEvent{
 			HtlcKey:       aliceKey,
+			CorrelationID: aliceID,
+			HtlcInfo:      aliceResolvedInfo,
 			Preimage:      *preimage,
 			HtlcEventType: HtlcEventTypeSend,
 			Timestamp:     ts,
@@ -3676,44 +3702,52 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 	bobEvents := []interface{}{
 		&ForwardingEvent{
 			HtlcKey:       bobKey,
+			CorrelationID: bobKey.IncomingCircuit,
 			HtlcInfo:      bobInfo,
 			HtlcEventType: HtlcEventTypeForward,
 			Timestamp:     ts,
 		},
 		&SettleEvent{
 			HtlcKey:       bobKey,
+			CorrelationID: bobKey.IncomingCircuit,
+			HtlcInfo:      bobResolvedInfo,
 			Preimage:      *preimage,
 			HtlcEventType: HtlcEventTypeForward,
 			Timestamp:     ts,
 		},
 		&FinalHtlcEvent{
-			CircuitKey: bobKey.IncomingCircuit,
-			Settled:    true,
-			Offchain:   true,
-			Timestamp:  ts,
+			CircuitKey:    bobKey.IncomingCircuit,
+			CorrelationID: bobKey.IncomingCircuit,
+			Settled:       true,
+			Offchain:      true,
+			Timestamp:     ts,
 		},
 	}
 
+	carolCircuit := models.CircuitKey{
+		ChanID: channels.carolToBob.ShortChanID(),
+		HtlcID: htlcID,
+	}
 	carolEvents := []interface{}{
 		&SettleEvent{
 			HtlcKey: HtlcKey{
-				IncomingCircuit: models.CircuitKey{
-					ChanID: channels.carolToBob.ShortChanID(),
-					HtlcID: htlcID,
-				},
+				IncomingCircuit: carolCircuit,
 				OutgoingCircuit: zeroCircuit,
 			},
+			CorrelationID: carolCircuit,
+			HtlcInfo: HtlcInfo{
+				IncomingTimeLock: bobInfo.OutgoingTimeLock,
+				IncomingAmt:      bobInfo.OutgoingAmt,
+			},
 			Preimage:      *preimage,
 			HtlcEventType: HtlcEventTypeReceive,
 			Timestamp:     ts,
 		}, &FinalHtlcEvent{
-			CircuitKey: models.CircuitKey{
-				ChanID: channels.carolToBob.ShortChanID(),
-				HtlcID: htlcID,
-			},
-			Settled:   true,
-			Offchain:  true,
-			Timestamp: ts,
+			CircuitKey:    carolCircuit,
+			CorrelationID: carolCircuit,
+			Settled:       true,
+			Offchain:      true,
+			Timestamp:     ts,
 		},
 	}
 
@@ -3998,6 +4032,49 @@ func TestSwitchHoldForward(t *testing.T) {
 	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
 	assertNumCircuits(t, c.s, 0, 0)
 
+	// Test resume a hold forward with a modified amount and expiry.
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, c.createTestPacket(),
+	))
+
+	intercepted := c.forwardInterceptor.getIntercepted()
+	outExpiry := intercepted.IncomingExpiry - 1
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Action:        FwdActionResumeModified,
+		Key:           intercepted.IncomingCircuit,
+		OutAmountMsat: fn.Some(lnwire.MilliSatoshi(2)),
+		OutExpiry:     fn.Some(outExpiry),
+	}))
+	receivedPkt = assertOutgoingLinkReceive(t, c.bobChannelLink, true)
+	assertNumCircuits(t, c.s, 1, 1)
+
+	receivedAdd, ok := receivedPkt.htlc.(*lnwire.UpdateAddHTLC)
+	require.True(t, ok)
+	require.Equal(t, lnwire.MilliSatoshi(2), receivedAdd.Amount)
+	require.Equal(t, outExpiry, receivedAdd.Expiry)
+	require.Equal(t, outExpiry, receivedPkt.outgoingTimeout)
+
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false,
+		c.createSettlePacket(receivedPkt.outgoingHTLCID),
+	))
+	assertOutgoingLinkReceive(t, c.aliceChannelLink, true)
+	assertNumCircuits(t, c.s, 0, 0)
+
+	// An outgoing expiry that isn't below the incoming expiry is rejected.
+	require.NoError(t, switchForwardInterceptor.ForwardPackets(
+		linkQuit, false, c.createTestPacket(),
+	))
+
+	intercepted = c.forwardInterceptor.getIntercepted()
+	require.Error(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Action:    FwdActionResumeModified,
+		Key:       intercepted.IncomingCircuit,
+		OutExpiry: fn.Some(intercepted.IncomingExpiry),
+	}))
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+	assertNumCircuits(t, c.s, 0, 0)
+
 	// Test resume a hold forward after disconnection.
 	require.NoError(t, switchForwardInterceptor.ForwardPackets(
 		linkQuit, false, c.createTestPacket(),
@@ -4177,7 +4254,7 @@ func TestSwitchHoldForward(t *testing.T) {
 	)
 
 	// A replay of the held packet is expected.
-	intercepted := c.forwardInterceptor.getIntercepted()
+	intercepted = c.forwardInterceptor.getIntercepted()
 
 	// Settle the packet.
 	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
@@ -4725,6 +4802,108 @@ func TestSwitchMailboxDust(t *testing.T) {
 	}
 }
 
+// TestSwitchDustExposure tests that the switch reports the dust exposure of
+// its links, and that the fee exposure threshold can be updated at runtime.
+func TestSwitchDustExposure(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	require.NoError(t, err)
+
+	s, err := initSwitchWithTempDB(t, testStartingHeight)
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	t.Cleanup(func() { _ = s.Stop() })
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+
+	aliceLink := newMockChannelLink(
+		s, chanID1, aliceChanID, emptyScid, alicePeer, true, false,
+		false, false,
+	)
+	require.NoError(t, s.AddLink(aliceLink))
+
+	// The link uses the threshold of the switch once it is added.
+	require.Equal(t, DefaultMaxFeeExposure, aliceLink.getMaxFeeExposure())
+
+	_, err = s.DustExposure(chanID2)
+	require.ErrorIs(t, err, ErrChannelLinkNotFound)
+
+	// mockChannelLink sets the local and remote dust limits of the mailbox
+	// to 400 satoshis, so these htlcs are dust on both commitments.
+	preimage, err := genPreimage()
+	require.NoError(t, err)
+	rhash := sha256.Sum256(preimage[:])
+	amt := lnwire.NewMSatFromSatoshis(350)
+	addMsg := &lnwire.UpdateAddHTLC{
+		PaymentHash: rhash,
+		Amount:      amt,
+		ChanID:      chanID1,
+	}
+
+	const numDust = 10
+	mailbox := s.mailOrchestrator.GetOrCreateMailBox(chanID1, aliceChanID)
+	for i := 0; i < numDust; i++ {
+		err := mailbox.AddPacket(&htlcPacket{
+			incomingChanID: bobChanID,
+			incomingHTLCID: uint64(i),
+			outgoingChanID: aliceChanID,
+			obfuscator:     NewMockObfuscator(),
+			incomingAmount: amt,
+			amount:         amt,
+			htlc:           addMsg,
+		})
+		require.NoError(t, err)
+	}
+
+	mailboxDust := amt * numDust
+	exposure, err := s.DustExposure(chanID1)
+	require.NoError(t, err)
+	require.Equal(t, DustExposure{
+		ChanID:      chanID1,
+		ShortChanID: aliceChanID,
+		MailboxDust: lntypes.Dual[lnwire.MilliSatoshi]{
+			Local:  mailboxDust,
+			Remote: mailboxDust,
+		},
+		MaxFeeExposure: DefaultMaxFeeExposure,
+	}, exposure)
+	require.False(t, exposure.Exceeded())
+	require.Equal(t, []DustExposure{exposure}, s.DustExposures())
+
+	// A zero threshold is rejected.
+	require.ErrorIs(
+		t, s.UpdateMaxFeeExposure(0), ErrInvalidMaxFeeExposure,
+	)
+
+	// Lower the threshold so that one more dust htlc exceeds it. The
+	// active link must use the new threshold too.
+	require.NoError(t, s.UpdateMaxFeeExposure(mailboxDust))
+	require.Equal(t, mailboxDust, aliceLink.getMaxFeeExposure())
+
+	err = s.SendHTLC(aliceChanID, 0, addMsg)
+	require.ErrorIs(t, err, errFeeExposureExceeded)
+
+	// Links that are added after the update use the new threshold.
+	bobLink := newMockChannelLink(
+		s, chanID2, bobChanID, emptyScid, bobPeer, true, false, false,
+		false,
+	)
+	require.NoError(t, s.AddLink(bobLink))
+	require.Equal(t, mailboxDust, bobLink.getMaxFeeExposure())
+
+	// Raising the threshold allows dust htlcs to be sent again.
+	require.NoError(t, s.UpdateMaxFeeExposure(DefaultMaxFeeExposure))
+	require.NoError(t, s.SendHTLC(aliceChanID, 1, addMsg))
+}
+
 // TestSwitchResolution checks the ability of the switch to persist and handle
 // resolution messages.
 func TestSwitchResolution(t *testing.T) {
@@ -5593,3 +5772,82 @@ func testSwitchAliasInterceptFail(t *testing.T, zeroConf bool) {
 
 	require.NoError(t, interceptSwitch.Stop())
 }
+
+// TestInterceptableSwitchZeroConfHold asserts that htlcs above the hold
+// threshold that come in over an unconfirmed zero-conf channel funded by the
+// peer are held until the channel confirms.
+func TestInterceptableSwitchZeroConfHold(t *testing.T) {
+	t.Parallel()
+
+	c := newInterceptableSwitchTestContext(t)
+	defer c.finish()
+
+	c.aliceChannelLink.zeroConf = true
+	c.aliceChannelLink.confirmedZC = false
+
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch, 1),
+	}
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{Height: testStartingHeight}
+
+	switchForwardInterceptor, err := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch:                c.s,
+			CltvRejectDelta:       c.cltvRejectDelta,
+			CltvInterceptDelta:    c.cltvInterceptDelta,
+			Notifier:              notifier,
+			ZeroConfHoldThreshold: 1000,
+		},
+	)
+	require.NoError(t, err)
+	require.NoError(t, switchForwardInterceptor.Start())
+
+	linkQuit := make(chan struct{})
+
+	// An htlc above the threshold is held.
+	packet := c.createTestPacket()
+	packet.incomingAmount = 1001
+
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, false, packet)
+	require.NoError(t, err)
+	assertOutgoingLinkReceive(t, c.bobChannelLink, false)
+
+	// An htlc at the threshold is forwarded right away.
+	packet = c.createTestPacket()
+	packet.incomingAmount = 1000
+
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, false, packet)
+	require.NoError(t, err)
+	assertOutgoingLinkReceive(t, c.bobChannelLink, true)
+
+	// Once the channel confirmed, the held htlc is forwarded.
+	c.aliceChannelLink.confirmedZC = true
+	switchForwardInterceptor.ZeroConfConfirmed()
+
+	received := assertOutgoingLinkReceive(t, c.bobChannelLink, true)
+	require.EqualValues(t, 1001, received.incomingAmount)
+}
+
+// TestInterceptableSwitchSetCltvInterceptDelta tests that the cltv intercept
+// delta can be changed at runtime, as long as it stays above the cltv reject
+// delta.
+func TestInterceptableSwitchSetCltvInterceptDelta(t *testing.T) {
+	t.Parallel()
+
+	interceptSwitch, err := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			CltvRejectDelta:    10,
+			CltvInterceptDelta: 13,
+		},
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 13, interceptSwitch.CltvInterceptDelta())
+
+	require.NoError(t, interceptSwitch.SetCltvInterceptDelta(20))
+	require.EqualValues(t, 20, interceptSwitch.CltvInterceptDelta())
+
+	// A delta that doesn't leave the interceptor any time to resolve the
+	// htlc is rejected, and the previous delta is kept.
+	require.Error(t, interceptSwitch.SetCltvInterceptDelta(10))
+	require.EqualValues(t, 20, interceptSwitch.CltvInterceptDelta())
+}
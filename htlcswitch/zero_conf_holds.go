@@ -0,0 +1,115 @@
+package htlcswitch
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ZeroConfConfirmed signals that a zero-conf channel confirmed, so that the
+// htlcs that were held until its confirmation are released. Signals that
+// arrive while the previous one wasn't processed yet are coalesced.
+func (s *InterceptableSwitch) ZeroConfConfirmed() {
+	select {
+	case s.zeroConfConfirmed <- struct{}{}:
+	default:
+	}
+}
+
+// holdZeroConf holds the forward if its htlc is above the hold threshold and
+// comes in over a zero-conf channel that was funded by the peer and didn't
+// confirm yet. The peer can still double spend the funding transaction of
+// such a channel, so we don't forward large htlcs from it before it confirms.
+func (s *InterceptableSwitch) holdZeroConf(fwd InterceptedForward) (bool,
+	error) {
+
+	packet := fwd.Packet()
+	if s.zeroConfHoldThreshold == 0 ||
+		packet.IncomingAmount <= s.zeroConfHoldThreshold {
+
+		return false, nil
+	}
+
+	inKey := packet.IncomingCircuit
+	if !s.unconfirmedZeroConf(inKey.ChanID) {
+		return false, nil
+	}
+
+	// Ignore htlcs that are held already.
+	if s.zeroConfHeld.exists(inKey) {
+		return true, nil
+	}
+
+	if err := s.zeroConfHeld.push(inKey, fwd); err != nil {
+		return false, err
+	}
+
+	log.Infof("Holding htlc %v of %v until its zero-conf channel "+
+		"confirms", inKey, packet.IncomingAmount)
+
+	return true, nil
+}
+
+// unconfirmedZeroConf returns whether the given incoming channel is a
+// zero-conf channel that was funded by the peer and didn't confirm yet.
+func (s *InterceptableSwitch) unconfirmedZeroConf(
+	chanID lnwire.ShortChannelID) bool {
+
+	link, err := s.htlcSwitch.GetLinkByShortID(chanID)
+	if err != nil {
+		return false
+	}
+
+	return link.isZeroConf() && !link.zeroConfConfirmed() &&
+		!link.isInitiator()
+}
+
+// releaseZeroConfHolds releases the held htlcs whose incoming channel
+// confirmed. The htlcs of channels whose link isn't active are kept until the
+// link is back, or until they're failed because they're about to expire.
+func (s *InterceptableSwitch) releaseZeroConfHolds() {
+	var confirmed []InterceptedForward
+	s.zeroConfHeld.forEach(func(fwd InterceptedForward) {
+		chanID := fwd.Packet().IncomingCircuit.ChanID
+
+		link, err := s.htlcSwitch.GetLinkByShortID(chanID)
+		if err != nil {
+			return
+		}
+
+		if link.isZeroConf() && !link.zeroConfConfirmed() {
+			return
+		}
+
+		confirmed = append(confirmed, fwd)
+	})
+
+	for _, fwd := range confirmed {
+		inKey := fwd.Packet().IncomingCircuit
+		if _, err := s.zeroConfHeld.pop(inKey); err != nil {
+			log.Errorf("Cannot release held htlc %v: %v", inKey,
+				err)
+
+			continue
+		}
+
+		log.Infof("Releasing htlc %v held until its zero-conf "+
+			"channel confirmed", inKey)
+
+		// The released htlc is offered to the interceptor like any
+		// other forward.
+		intercepted, err := s.forward(fwd, false)
+		if err != nil {
+			log.Errorf("Cannot forward released htlc %v: %v",
+				inKey, err)
+
+			continue
+		}
+		if intercepted {
+			continue
+		}
+
+		if err := fwd.Resume(); err != nil {
+			log.Errorf("Cannot resume released htlc %v: %v",
+				inKey, err)
+		}
+	}
+}
@@ -78,6 +78,11 @@ type TxInfo struct {
 
 	// Weight is the weight of the tx.
 	Weight lntypes.WeightUnit
+
+	// Tx is the fully signed tx, if known. It allows the tx to be
+	// broadcast together with its child as a package, such that it can
+	// enter the mempool even if its own fee is too low.
+	Tx *wire.MsgTx
 }
 
 // String returns a human readable version of the tx info.
@@ -116,6 +121,10 @@ type inputKit struct {
 	// resolutionBlob is an optional blob that can be used to resolve an
 	// input.
 	resolutionBlob fn.Option[tlv.Blob]
+
+	// witnessSizeEstimator is an optional estimator of the witness size
+	// provided by the caller.
+	witnessSizeEstimator fn.Option[WitnessSizeEstimator]
 }
 
 // OutPoint returns the breached output's identifier that is to be included as
@@ -172,11 +181,46 @@ func (i *inputKit) ResolutionBlob() fn.Option[tlv.Blob] {
 	return i.resolutionBlob
 }
 
+// CustomWitnessSize returns the upper bound of the witness size of the input
+// if it differs from the bound of its witness type, which is the case if the
+// caller provided a witness size estimator or the input carries an annex.
+//
+// NOTE: Part of the CustomWitnessSizer interface.
+func (i *inputKit) CustomWitnessSize() (fn.Option[lntypes.WeightUnit],
+	error) {
+
+	annexSize := annexWitnessSize(i.signDesc.Annex)
+	if i.witnessSizeEstimator.IsNone() && annexSize == 0 {
+		return fn.None[lntypes.WeightUnit](), nil
+	}
+
+	estimate := func() (lntypes.WeightUnit, error) {
+		size, _, err := i.witnessType.SizeUpperBound()
+		return size, err
+	}
+	estimate = i.witnessSizeEstimator.UnwrapOr(estimate)
+
+	size, err := estimate()
+	if err != nil {
+		return fn.None[lntypes.WeightUnit](), err
+	}
+
+	return fn.Some(size + annexSize), nil
+}
+
 // inputOpts contains options for constructing a new input.
 type inputOpts struct {
 	// resolutionBlob is an optional blob that can be used to resolve an
 	// input.
 	resolutionBlob fn.Option[tlv.Blob]
+
+	// witnessSizeEstimator is an optional estimator of the witness size
+	// of the input.
+	witnessSizeEstimator fn.Option[WitnessSizeEstimator]
+
+	// annex is an optional taproot annex that's appended to the witness
+	// of the input.
+	annex []byte
 }
 
 // defaultInputOpts returns a new inputOpts with default values.
@@ -196,6 +240,25 @@ func WithResolutionBlob(b fn.Option[tlv.Blob]) InputOpt {
 	}
 }
 
+// WithWitnessSizeEstimator is an option that can be used to provide the
+// estimator of the witness size of an input, for inputs whose witness can't be
+// derived from their witness type, such as tapscript path spends of custom
+// channels.
+func WithWitnessSizeEstimator(f WitnessSizeEstimator) InputOpt {
+	return func(o *inputOpts) {
+		o.witnessSizeEstimator = fn.Some(f)
+	}
+}
+
+// WithAnnex is an option that can be used to attach a taproot annex to an
+// input. The annex must start with the annex tag and is committed to by the
+// signature of the input.
+func WithAnnex(annex []byte) InputOpt {
+	return func(o *inputOpts) {
+		o.annex = annex
+	}
+}
+
 // BaseInput contains all the information needed to sweep a basic
 // output (CSV/CLTV/no time lock).
 type BaseInput struct {
@@ -213,14 +276,20 @@ func MakeBaseInput(outpoint *wire.OutPoint, witnessType WitnessType,
 		optF(opt)
 	}
 
+	signDesc := *signDescriptor
+	if len(opt.annex) > 0 {
+		signDesc.Annex = opt.annex
+	}
+
 	return BaseInput{
 		inputKit{
-			outpoint:       *outpoint,
-			witnessType:    witnessType,
-			signDesc:       *signDescriptor,
-			heightHint:     heightHint,
-			unconfParent:   unconfParent,
-			resolutionBlob: opt.resolutionBlob,
+			outpoint:             *outpoint,
+			witnessType:          witnessType,
+			signDesc:             signDesc,
+			heightHint:           heightHint,
+			unconfParent:         unconfParent,
+			resolutionBlob:       opt.resolutionBlob,
+			witnessSizeEstimator: opt.witnessSizeEstimator,
 		},
 	}
 }
@@ -282,7 +351,21 @@ func (bi *BaseInput) CraftInputScript(signer Signer, txn *wire.MsgTx,
 	signDesc.PrevOutputFetcher = prevOutputFetcher
 	witnessFunc := bi.witnessType.WitnessGenerator(signer, signDesc)
 
-	return witnessFunc(txn, hashCache, txinIdx)
+	script, err := witnessFunc(txn, hashCache, txinIdx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The annex, if any, is always the last element of the witness.
+	if len(signDesc.Annex) > 0 {
+		if signDesc.Annex[0] != txscript.TaprootAnnexTag {
+			return nil, ErrInvalidAnnex
+		}
+
+		script.Witness = append(script.Witness, signDesc.Annex)
+	}
+
+	return script, nil
 }
 
 // HtlcSucceedInput constitutes a sweep input that needs a pre-image. The input
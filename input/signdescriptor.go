@@ -105,6 +105,11 @@ type SignDescriptor struct {
 	// inclusion proof for the WitnessScript.
 	ControlBlock []byte
 
+	// Annex is an optional taproot annex that's appended to the witness of
+	// the input. If set, the signature commits to it. This is only
+	// supported for input.TaprootScriptSpendSignMethod.
+	Annex []byte
+
 	// InputIndex is the target input within the transaction that should be
 	// signed.
 	InputIndex int
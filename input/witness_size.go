@@ -0,0 +1,85 @@
+package input
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+// ErrInvalidAnnex is returned when the annex of an input doesn't start with
+// the taproot annex tag.
+var ErrInvalidAnnex = errors.New("annex must start with the annex tag")
+
+// WitnessSizeEstimator returns the upper bound of the witness size of an
+// input. It allows the caller to provide the size of witnesses that can't be
+// derived from the witness type, such as the tapscript path spends of custom
+// channel types.
+type WitnessSizeEstimator func() (lntypes.WeightUnit, error)
+
+// CustomWitnessSizer is an optional interface implemented by inputs whose
+// witness size may differ from the bound of their witness type.
+type CustomWitnessSizer interface {
+	// CustomWitnessSize returns the upper bound of the witness size of the
+	// input if it differs from the bound of its witness type. A fn.None is
+	// returned otherwise.
+	CustomWitnessSize() (fn.Option[lntypes.WeightUnit], error)
+}
+
+// customWitnessSize returns the custom witness size of the given input, if
+// it has one.
+func customWitnessSize(inp Input) (fn.Option[lntypes.WeightUnit], error) {
+	sizer, ok := inp.(CustomWitnessSizer)
+	if !ok {
+		return fn.None[lntypes.WeightUnit](), nil
+	}
+
+	return sizer.CustomWitnessSize()
+}
+
+// WitnessSizeUpperBound returns the upper bound of the witness size of the
+// given input, and whether it spends a nested P2SH output. The custom witness
+// size of the input takes precedence over the bound of its witness type.
+func WitnessSizeUpperBound(inp Input) (lntypes.WeightUnit, bool, error) {
+	custom, err := customWitnessSize(inp)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if custom.IsSome() {
+		return custom.UnsafeFromSome(), false, nil
+	}
+
+	return inp.WitnessType().SizeUpperBound()
+}
+
+// AddInputWeightEstimation adds the estimated weight of the given input to the
+// weight estimator. The custom witness size of the input takes precedence
+// over the estimate of its witness type.
+func AddInputWeightEstimation(e *TxWeightEstimator, inp Input) error {
+	custom, err := customWitnessSize(inp)
+	if err != nil {
+		return err
+	}
+
+	if custom.IsNone() {
+		return inp.WitnessType().AddWeightEstimation(e)
+	}
+
+	e.AddWitnessInput(custom.UnsafeFromSome())
+
+	return nil
+}
+
+// annexWitnessSize returns the number of bytes the given annex adds to a
+// witness, including its length prefix.
+func annexWitnessSize(annex []byte) lntypes.WeightUnit {
+	if len(annex) == 0 {
+		return 0
+	}
+
+	return lntypes.WeightUnit(
+		wire.VarIntSerializeSize(uint64(len(annex))) + len(annex),
+	)
+}
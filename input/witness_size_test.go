@@ -0,0 +1,131 @@
+package input
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCustomWitnessSize checks that the witness size estimator and the annex
+// of an input take precedence over the bound of its witness type.
+func TestCustomWitnessSize(t *testing.T) {
+	t.Parallel()
+
+	var (
+		op       = wire.OutPoint{Index: 1}
+		signDesc = &SignDescriptor{}
+		annex    = append([]byte{txscript.TaprootAnnexTag},
+			bytes.Repeat([]byte{1}, 9)...)
+		errEstimate = errors.New("estimate failed")
+	)
+
+	typeSize, _, err := TaprootPubKeySpend.SizeUpperBound()
+	require.NoError(t, err)
+
+	estimator := func() (lntypes.WeightUnit, error) {
+		return 500, nil
+	}
+
+	testCases := []struct {
+		name     string
+		opts     []InputOpt
+		expected lntypes.WeightUnit
+		err      error
+	}{
+		{
+			name:     "witness type bound",
+			expected: typeSize,
+		},
+		{
+			name: "estimator",
+			opts: []InputOpt{
+				WithWitnessSizeEstimator(estimator),
+			},
+			expected: 500,
+		},
+		{
+			name:     "annex",
+			opts:     []InputOpt{WithAnnex(annex)},
+			expected: typeSize + 1 + 10,
+		},
+		{
+			name: "estimator and annex",
+			opts: []InputOpt{
+				WithWitnessSizeEstimator(estimator),
+				WithAnnex(annex),
+			},
+			expected: 500 + 1 + 10,
+		},
+		{
+			name: "estimator error",
+			opts: []InputOpt{WithWitnessSizeEstimator(
+				func() (lntypes.WeightUnit, error) {
+					return 0, errEstimate
+				},
+			)},
+			err: errEstimate,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inp := NewBaseInput(
+				&op, TaprootPubKeySpend, signDesc, 0,
+				tc.opts...,
+			)
+
+			size, nested, err := WitnessSizeUpperBound(inp)
+			require.ErrorIs(t, err, tc.err)
+			if tc.err != nil {
+				return
+			}
+			require.False(t, nested)
+			require.Equal(t, tc.expected, size)
+
+			var e TxWeightEstimator
+			require.NoError(t, AddInputWeightEstimation(&e, inp))
+
+			var expected TxWeightEstimator
+			expected.AddWitnessInput(tc.expected)
+			require.Equal(t, expected.Weight(), e.Weight())
+		})
+	}
+}
+
+// TestCraftInputScriptAnnex checks that the annex of an input is appended to
+// its witness, and that an annex without the annex tag is rejected.
+func TestCraftInputScriptAnnex(t *testing.T) {
+	t.Parallel()
+
+	op := wire.OutPoint{Index: 1}
+	witness := wire.TxWitness{[]byte{1}, []byte{2}}
+
+	wt := &MockWitnessType{}
+	wt.On("WitnessGenerator").Return(WitnessGenerator(
+		func(*wire.MsgTx, *txscript.TxSigHashes, int) (*Script,
+			error) {
+
+			return &Script{Witness: witness}, nil
+		},
+	))
+
+	annex := []byte{txscript.TaprootAnnexTag, 1, 2}
+	inp := NewBaseInput(
+		&op, wt, &SignDescriptor{}, 0, WithAnnex(annex),
+	)
+
+	script, err := inp.CraftInputScript(nil, nil, nil, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, append(witness, annex), script.Witness)
+
+	inp = NewBaseInput(
+		&op, wt, &SignDescriptor{}, 0, WithAnnex([]byte{1, 2}),
+	)
+	_, err = inp.CraftInputScript(nil, nil, nil, nil, 0)
+	require.ErrorIs(t, err, ErrInvalidAnnex)
+}
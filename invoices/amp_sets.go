@@ -0,0 +1,240 @@
+package invoices
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// AMPSet is the state of a single AMP sub-invoice, identified by its set ID.
+type AMPSet struct {
+	InvoiceStateAMP
+
+	// SetID is the set ID of the sub-invoice.
+	SetID SetID
+}
+
+// AMPSetQuery represents a query to the settled sets of an AMP invoice.
+type AMPSetQuery struct {
+	// IndexOffset is the settle index to start at. Only sets with a
+	// settle index after (or before, if Reversed is set) the offset are
+	// returned.
+	IndexOffset uint64
+
+	// NumMaxSets is the maximum number of sets that should be returned.
+	// Zero means no limit.
+	NumMaxSets uint64
+
+	// Reversed, if set, indicates that the sets returned should start
+	// from the IndexOffset and go backwards.
+	Reversed bool
+}
+
+// AMPSetSlice is the response to an AMP set query. It contains the page of
+// settled sets in ascending settle index order, along with the offsets that
+// allow callers to resume their query.
+type AMPSetSlice struct {
+	AMPSetQuery
+
+	// Sets is the page of settled sets that matched the query.
+	Sets []AMPSet
+
+	// FirstIndexOffset is the settle index of the first set in Sets.
+	FirstIndexOffset uint64
+
+	// LastIndexOffset is the settle index of the last set in Sets.
+	LastIndexOffset uint64
+}
+
+// QueryAMPSets returns a page of the settled sets of the AMP invoice. Sets
+// are ordered by their settle index, which is stable as new sets are settled.
+func (i *Invoice) QueryAMPSets(q AMPSetQuery) AMPSetSlice {
+	var settled []AMPSet
+	for setID, state := range i.AMPState {
+		if state.State != HtlcStateSettled {
+			continue
+		}
+
+		matches := state.SettleIndex > q.IndexOffset
+		if q.Reversed {
+			matches = q.IndexOffset == 0 ||
+				state.SettleIndex < q.IndexOffset
+		}
+		if !matches {
+			continue
+		}
+
+		settled = append(settled, AMPSet{
+			InvoiceStateAMP: state,
+			SetID:           setID,
+		})
+	}
+
+	sort.Slice(settled, func(a, b int) bool {
+		return settled[a].SettleIndex < settled[b].SettleIndex
+	})
+
+	// Cut the page from the start, or from the end if we're paginating
+	// backwards.
+	if q.NumMaxSets != 0 && uint64(len(settled)) > q.NumMaxSets {
+		if q.Reversed {
+			settled = settled[uint64(len(settled))-q.NumMaxSets:]
+		} else {
+			settled = settled[:q.NumMaxSets]
+		}
+	}
+
+	slice := AMPSetSlice{
+		AMPSetQuery: q,
+		Sets:        settled,
+	}
+	if len(settled) > 0 {
+		slice.FirstIndexOffset = settled[0].SettleIndex
+		slice.LastIndexOffset = settled[len(settled)-1].SettleIndex
+	}
+
+	return slice
+}
+
+// PendingAMPSets returns the sets of the AMP invoice that have accepted htlcs
+// which are neither settled nor canceled, ordered by set ID.
+func (i *Invoice) PendingAMPSets() []AMPSet {
+	var pending []AMPSet
+	for setID, state := range i.AMPState {
+		if state.State != HtlcStateAccepted {
+			continue
+		}
+
+		pending = append(pending, AMPSet{
+			InvoiceStateAMP: state,
+			SetID:           setID,
+		})
+	}
+
+	sort.Slice(pending, func(a, b int) bool {
+		setA, setB := pending[a].SetID, pending[b].SetID
+		for i := range setA {
+			if setA[i] != setB[i] {
+				return setA[i] < setB[i]
+			}
+		}
+
+		return false
+	})
+
+	return pending
+}
+
+// LookupAMPSet looks up the AMP invoice the given set ID belongs to, with
+// only the htlcs of that set populated, along with the state of the set.
+func (i *InvoiceRegistry) LookupAMPSet(ctx context.Context,
+	setID SetID) (Invoice, InvoiceStateAMP, error) {
+
+	invoice, err := i.idb.LookupInvoice(
+		ctx, InvoiceRefBySetIDFiltered(setID),
+	)
+	if err != nil {
+		return Invoice{}, InvoiceStateAMP{}, err
+	}
+
+	state, ok := invoice.AMPState[setID]
+	if !ok {
+		return Invoice{}, InvoiceStateAMP{}, ErrInvoiceNotFound
+	}
+
+	return invoice, state, nil
+}
+
+// CancelAMPSet cancels the accepted htlcs of a pending AMP set, without
+// affecting the AMP invoice itself or any of its other sets.
+func (i *InvoiceRegistry) CancelAMPSet(ctx context.Context,
+	setID SetID) error {
+
+	i.Lock()
+	defer i.Unlock()
+
+	ref := InvoiceRefBySetID(setID)
+
+	var canceled map[CircuitKey]struct{}
+	invoice, err := i.idb.UpdateInvoice(ctx, ref, &setID,
+		func(invoice *Invoice) (*InvoiceUpdateDesc, error) {
+			if invoice.State == ContractCanceled {
+				return nil, ErrInvoiceAlreadyCanceled
+			}
+
+			state, ok := invoice.AMPState[setID]
+			if !ok || state.State != HtlcStateAccepted {
+				return nil, ErrAMPSetNotPending
+			}
+
+			canceled = make(map[CircuitKey]struct{})
+			for key := range state.InvoiceKeys {
+				htlc, ok := invoice.Htlcs[key]
+				if !ok || htlc.State != HtlcStateAccepted {
+					continue
+				}
+
+				canceled[key] = struct{}{}
+			}
+
+			if len(canceled) == 0 {
+				return nil, ErrAMPSetNotPending
+			}
+
+			return &InvoiceUpdateDesc{
+				UpdateType:  CancelHTLCsUpdate,
+				CancelHtlcs: canceled,
+				SetID:       &setID,
+			}, nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to cancel AMP set %x: %w", setID[:],
+			err)
+	}
+
+	log.Debugf("Canceled %v htlcs of AMP set %x", len(canceled), setID[:])
+
+	// Resolve the canceled htlcs with our peers and let the subscribers
+	// of the set know about it.
+	for key := range canceled {
+		htlc, ok := invoice.Htlcs[key]
+		if !ok {
+			continue
+		}
+
+		i.notifyHodlSubscribers(NewFailResolution(
+			key, int32(htlc.AcceptHeight), ResultCanceled,
+		))
+	}
+
+	i.notifySetClients(invoice, setID)
+
+	return nil
+}
+
+// notifySetClients notifies the subscribers of an AMP set about a change to
+// the set that doesn't affect the state of the AMP invoice itself.
+func (i *InvoiceRegistry) notifySetClients(invoice *Invoice, setID SetID) {
+	setIDBytes := [32]byte(setID)
+	event := &invoiceEvent{
+		invoice: invoice,
+		setID:   &setIDBytes,
+		setOnly: true,
+	}
+
+	select {
+	case i.invoiceEvents <- event:
+	case <-i.quit:
+	}
+}
+
+// SubscribeAMPSet returns a SingleInvoiceSubscription that receives updates
+// for a single set of an AMP invoice. Every update carries the AMP invoice
+// with only the htlcs of the set populated, and its AMPState entry for the
+// set reflects the state of the set.
+func (i *InvoiceRegistry) SubscribeAMPSet(ctx context.Context,
+	setID SetID) (*SingleInvoiceSubscription, error) {
+
+	return i.subscribeSingle(ctx, InvoiceRefBySetIDFiltered(setID))
+}
@@ -0,0 +1,68 @@
+package invoices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestQueryAMPSets tests pagination of the settled sets of an AMP invoice and
+// the selection of its pending sets.
+func TestQueryAMPSets(t *testing.T) {
+	t.Parallel()
+
+	invoice := &Invoice{
+		AMPState: AMPInvoiceState{
+			{1}: {State: HtlcStateSettled, SettleIndex: 3},
+			{2}: {State: HtlcStateSettled, SettleIndex: 1},
+			{3}: {State: HtlcStateSettled, SettleIndex: 2},
+			{4}: {State: HtlcStateAccepted},
+			{5}: {State: HtlcStateCanceled},
+			{6}: {State: HtlcStateAccepted},
+		},
+	}
+
+	setIDs := func(sets []AMPSet) []SetID {
+		ids := make([]SetID, 0, len(sets))
+		for _, set := range sets {
+			ids = append(ids, set.SetID)
+		}
+
+		return ids
+	}
+
+	// Without limits, all settled sets are returned in settle order.
+	slice := invoice.QueryAMPSets(AMPSetQuery{})
+	require.Equal(t, []SetID{{2}, {3}, {1}}, setIDs(slice.Sets))
+	require.EqualValues(t, 1, slice.FirstIndexOffset)
+	require.EqualValues(t, 3, slice.LastIndexOffset)
+
+	// Paginate forwards.
+	slice = invoice.QueryAMPSets(AMPSetQuery{NumMaxSets: 2})
+	require.Equal(t, []SetID{{2}, {3}}, setIDs(slice.Sets))
+
+	slice = invoice.QueryAMPSets(AMPSetQuery{
+		IndexOffset: slice.LastIndexOffset,
+		NumMaxSets:  2,
+	})
+	require.Equal(t, []SetID{{1}}, setIDs(slice.Sets))
+
+	// Paginate backwards from the most recently settled set.
+	slice = invoice.QueryAMPSets(AMPSetQuery{
+		NumMaxSets: 2,
+		Reversed:   true,
+	})
+	require.Equal(t, []SetID{{3}, {1}}, setIDs(slice.Sets))
+
+	slice = invoice.QueryAMPSets(AMPSetQuery{
+		IndexOffset: slice.FirstIndexOffset,
+		NumMaxSets:  2,
+		Reversed:    true,
+	})
+	require.Equal(t, []SetID{{2}}, setIDs(slice.Sets))
+
+	// Only the accepted sets are pending.
+	require.Equal(
+		t, []SetID{{4}, {6}}, setIDs(invoice.PendingAMPSets()),
+	)
+}
@@ -94,6 +94,30 @@ var (
 	// ErrNoPaymentsCreated is returned when bucket of payments hasn't been
 	// created.
 	ErrNoPaymentsCreated = errors.New("there are no existing payments")
+
+	// ErrAMPSetNotPending is returned when an AMP set that has no pending
+	// htlcs is canceled.
+	ErrAMPSetNotPending = errors.New("AMP set has no pending htlcs")
+
+	// ErrSubscriptionNotFound is returned when a targeted subscription
+	// can't be found.
+	ErrSubscriptionNotFound = errors.New("unable to locate subscription")
+
+	// ErrSubscriptionNotActive is returned when a subscription that is
+	// canceled or completed is canceled again.
+	ErrSubscriptionNotActive = errors.New("subscription is not active")
+
+	// ErrInvalidSubscriptionPeriod is returned when a subscription is
+	// created with a period that is too short.
+	ErrInvalidSubscriptionPeriod = errors.New(
+		"subscription period too short",
+	)
+
+	// ErrInvalidSubscriptionValue is returned when a subscription is
+	// created without a value.
+	ErrInvalidSubscriptionValue = errors.New(
+		"subscription value must be positive",
+	)
 )
 
 // ErrDuplicateSetID is an error returned when attempting to adding an AMP HTLC
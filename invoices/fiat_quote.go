@@ -0,0 +1,58 @@
+package invoices
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// MaxFiatRateSourceSize is the maximum size of the rate source of a
+	// fiat quote.
+	MaxFiatRateSourceSize = 256
+)
+
+// FiatQuote is the fiat price that was quoted for an invoice when it was
+// created. It is stored along with the invoice so that accounting systems
+// have a consistent record of the quoted price, but it doesn't affect the
+// settlement of the invoice in any way.
+type FiatQuote struct {
+	// Currency is the ISO 4217 code of the fiat currency, e.g. "USD".
+	Currency string
+
+	// Amount is the quoted amount in the minor unit of the currency, e.g.
+	// cents for "USD".
+	Amount uint64
+
+	// RateSource identifies the source of the exchange rate the quote was
+	// based on.
+	RateSource string
+
+	// QuoteTime is the time at which the quote was made.
+	QuoteTime time.Time
+}
+
+// Validate checks that the fiat quote is well-formed.
+func (q *FiatQuote) Validate() error {
+	if len(q.Currency) != 3 {
+		return fmt.Errorf("invalid fiat currency code: %q", q.Currency)
+	}
+
+	for _, c := range q.Currency {
+		if c < 'A' || c > 'Z' {
+			return fmt.Errorf("invalid fiat currency code: %q",
+				q.Currency)
+		}
+	}
+
+	if q.Amount == 0 {
+		return fmt.Errorf("fiat quote amount must be positive")
+	}
+
+	if len(q.RateSource) > MaxFiatRateSourceSize {
+		return fmt.Errorf("max length of fiat rate source is %v, "+
+			"length provided was %v", MaxFiatRateSourceSize,
+			len(q.RateSource))
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package invoices
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+const (
+	// holdExpiryWarningBufferSize is the number of warnings that are
+	// buffered for a subscriber before new warnings are dropped.
+	holdExpiryWarningBufferSize = 20
+)
+
+// HoldExpiryWarning is sent out when an accepted hold invoice has not been
+// settled or canceled by the application and is approaching the height at
+// which it will be canceled automatically to prevent a force close.
+type HoldExpiryWarning struct {
+	// PaymentHash is the payment hash of the hold invoice.
+	PaymentHash lntypes.Hash
+
+	// HtlcExpiry is the lowest expiry height of the accepted htlcs of the
+	// invoice.
+	HtlcExpiry uint32
+
+	// CancelHeight is the height at which the invoice will be canceled
+	// automatically if it is still unresolved.
+	CancelHeight uint32
+
+	// CurrentHeight is the block height at which the warning was created.
+	CurrentHeight uint32
+}
+
+// HoldExpiryWarningSubscription represents an intent to receive warnings for
+// accepted hold invoices that are about to be canceled automatically.
+type HoldExpiryWarningSubscription struct {
+	id uint32
+
+	// Warnings is the channel that warnings are delivered on. Warnings are
+	// dropped if the subscriber doesn't keep up with them.
+	Warnings chan *HoldExpiryWarning
+
+	cancelOnce sync.Once
+	cancel     func()
+}
+
+// Cancel unregisters the HoldExpiryWarningSubscription.
+func (s *HoldExpiryWarningSubscription) Cancel() {
+	s.cancelOnce.Do(s.cancel)
+}
+
+// SubscribeHoldExpiryWarnings returns a subscription that receives a warning
+// for every accepted hold invoice that reaches the configured warning delta
+// before its htlcs expire.
+func (i *InvoiceRegistry) SubscribeHoldExpiryWarnings() *HoldExpiryWarningSubscription { //nolint:lll
+	client := &HoldExpiryWarningSubscription{
+		id: atomic.AddUint32(&i.nextClientID, 1),
+		Warnings: make(
+			chan *HoldExpiryWarning, holdExpiryWarningBufferSize,
+		),
+	}
+	client.cancel = func() {
+		i.notificationClientMux.Lock()
+		delete(i.holdWarningClients, client.id)
+		i.notificationClientMux.Unlock()
+	}
+
+	i.notificationClientMux.Lock()
+	i.holdWarningClients[client.id] = client
+	i.notificationClientMux.Unlock()
+
+	log.Infof("New hold expiry warning subscription client: id=%v",
+		client.id)
+
+	return client
+}
+
+// warnHoldInvoice is called by the expiry watcher when an accepted hold
+// invoice reaches its warning height. The warning is only sent out if the
+// invoice is still accepted.
+func (i *InvoiceRegistry) warnHoldInvoice(warning *HoldExpiryWarning) {
+	invoice, err := i.idb.LookupInvoice(
+		context.Background(), InvoiceRefByHash(warning.PaymentHash),
+	)
+	if err != nil {
+		log.Errorf("Unable to look up hold invoice %v for expiry "+
+			"warning: %v", warning.PaymentHash, err)
+
+		return
+	}
+
+	if invoice.State != ContractAccepted {
+		return
+	}
+
+	log.Warnf("Accepted hold invoice %v is unresolved and will be "+
+		"canceled at height %v (htlc expiry %v, current height %v)",
+		warning.PaymentHash, warning.CancelHeight, warning.HtlcExpiry,
+		warning.CurrentHeight)
+
+	i.notificationClientMux.RLock()
+	defer i.notificationClientMux.RUnlock()
+
+	for _, client := range i.holdWarningClients {
+		select {
+		case client.Warnings <- warning:
+
+		default:
+			log.Warnf("Hold expiry warning client %v is not "+
+				"keeping up, dropping warning for %v",
+				client.id, warning.PaymentHash)
+		}
+	}
+}
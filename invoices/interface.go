@@ -116,6 +116,10 @@ type Payload interface {
 	TotalAmtMsat() lnwire.MilliSatoshi
 }
 
+// MaxQueryInvoices is the maximum number of invoices that a single invoice
+// query returns, regardless of the number of invoices requested.
+const MaxQueryInvoices = 10000
+
 // InvoiceQuery represents a query to the invoice database. The query allows a
 // caller to retrieve all invoices starting from a particular add index and
 // limit the number of results returned.
@@ -125,7 +129,7 @@ type InvoiceQuery struct {
 	IndexOffset uint64
 
 	// NumMaxInvoices is the maximum number of invoices that should be
-	// starting from the add index.
+	// starting from the add index. It is capped at MaxQueryInvoices.
 	NumMaxInvoices uint64
 
 	// PendingOnly, if set, returns unsettled invoices starting from the
@@ -275,3 +279,37 @@ type HtlcInterceptor interface {
 	// registered.
 	Intercept(HtlcModifyRequest, func(HtlcModifyResponse)) error
 }
+
+// PreimageRequest is the request that is passed to a PreimageProvider once the
+// htlc set of a hold invoice has been fully accepted.
+type PreimageRequest struct {
+	// PaymentHash is the payment hash of the hold invoice.
+	PaymentHash lntypes.Hash
+
+	// HtlcExpiry is the lowest expiry height of the accepted htlcs. The
+	// preimage must be released well before this height.
+	HtlcExpiry uint32
+
+	// Invoice is the accepted hold invoice.
+	Invoice Invoice
+}
+
+// PreimageProvider is an interface that allows an external service to release
+// the preimage of a hold invoice once its htlcs have been accepted, for
+// example when a submarine swap has confirmed or a fiat payment was received.
+type PreimageProvider interface {
+	// RequestPreimage blocks until the preimage of the hold invoice is
+	// released, the context is canceled or an error occurs. If an error
+	// is returned, the invoice is canceled.
+	RequestPreimage(ctx context.Context,
+		req PreimageRequest) (lntypes.Preimage, error)
+}
+
+// FiatRateProvider is an interface that allows an external service to quote
+// the fiat price of an invoice when it is created.
+type FiatRateProvider interface {
+	// QuoteFiat returns a quote of the given amount in the fiat currency
+	// with the given ISO 4217 code.
+	QuoteFiat(ctx context.Context, amt lnwire.MilliSatoshi,
+		currency string) (*FiatQuote, error)
+}
@@ -82,6 +82,13 @@ type InvoiceExpiryWatcher struct {
 	// before this to prevent force closes.
 	blockExpiryDelta uint32
 
+	// blockWarningDelta is the number of blocks before a htlc's expiry
+	// that we warn about an accepted hold invoice that is still
+	// unresolved. Warnings are only sent if this value is greater than
+	// blockExpiryDelta, since the invoice would be canceled before
+	// otherwise.
+	blockWarningDelta uint32
+
 	// currentHeight is the current block height.
 	currentHeight uint32
 
@@ -91,6 +98,11 @@ type InvoiceExpiryWatcher struct {
 	// cancelInvoice is a template method that cancels an expired invoice.
 	cancelInvoice func(lntypes.Hash, bool) error
 
+	// warnInvoice is a template method that is called when an accepted
+	// hold invoice is approaching its cancellation height. It may be nil
+	// if no warnings should be sent.
+	warnInvoice func(*HoldExpiryWarning)
+
 	// timestampExpiryQueue holds invoiceExpiry items and is used to find
 	// the next invoice to expire.
 	timestampExpiryQueue queue.PriorityQueue
@@ -104,6 +116,11 @@ type InvoiceExpiryWatcher struct {
 	// active htlcs.
 	blockExpiryQueue queue.PriorityQueue
 
+	// blockWarningQueue holds the same items as the blockExpiryQueue and
+	// is used to find the next hold invoice that we need to warn about
+	// before it is canceled.
+	blockWarningQueue queue.PriorityQueue
+
 	// newInvoices channel is used to wake up the main loop when a new
 	// invoices is added.
 	newInvoices chan []invoiceExpiry
@@ -116,26 +133,30 @@ type InvoiceExpiryWatcher struct {
 
 // NewInvoiceExpiryWatcher creates a new InvoiceExpiryWatcher instance.
 func NewInvoiceExpiryWatcher(clock clock.Clock,
-	expiryDelta, startHeight uint32, startHash *chainhash.Hash,
+	expiryDelta, warningDelta, startHeight uint32,
+	startHash *chainhash.Hash,
 	notifier chainntnfs.ChainNotifier) *InvoiceExpiryWatcher {
 
 	return &InvoiceExpiryWatcher{
-		clock:            clock,
-		notifier:         notifier,
-		blockExpiryDelta: expiryDelta,
-		currentHeight:    startHeight,
-		currentHash:      startHash,
-		newInvoices:      make(chan []invoiceExpiry),
-		quit:             make(chan struct{}),
+		clock:             clock,
+		notifier:          notifier,
+		blockExpiryDelta:  expiryDelta,
+		blockWarningDelta: warningDelta,
+		currentHeight:     startHeight,
+		currentHash:       startHash,
+		newInvoices:       make(chan []invoiceExpiry),
+		quit:              make(chan struct{}),
 	}
 }
 
 // Start starts the subscription handler and the main loop. Start() will
 // return with error if InvoiceExpiryWatcher is already started. Start()
 // expects a cancellation function passed that will be use to cancel expired
-// invoices by their payment hash.
+// invoices by their payment hash, and an optional warning function that is
+// called for accepted hold invoices that are about to be canceled.
 func (ew *InvoiceExpiryWatcher) Start(
-	cancelInvoice func(lntypes.Hash, bool) error) error {
+	cancelInvoice func(lntypes.Hash, bool) error,
+	warnInvoice func(*HoldExpiryWarning)) error {
 
 	ew.Lock()
 	defer ew.Unlock()
@@ -146,6 +167,7 @@ func (ew *InvoiceExpiryWatcher) Start(
 
 	ew.started = true
 	ew.cancelInvoice = cancelInvoice
+	ew.warnInvoice = warnInvoice
 
 	ntfn, err := ew.notifier.RegisterBlockEpochNtfn(&chainntnfs.BlockEpoch{
 		Height: int32(ew.currentHeight),
@@ -303,6 +325,30 @@ func (ew *InvoiceExpiryWatcher) nextHeightExpiry() <-chan uint32 {
 	return blockChan
 }
 
+// warningsEnabled returns true if we warn about accepted hold invoices before
+// they are canceled.
+func (ew *InvoiceExpiryWatcher) warningsEnabled() bool {
+	return ew.warnInvoice != nil &&
+		ew.blockWarningDelta > ew.blockExpiryDelta
+}
+
+// nextHeightWarning returns a channel that will immediately be read from if
+// the top item on our warning queue has reached its warning height.
+func (ew *InvoiceExpiryWatcher) nextHeightWarning() <-chan uint32 {
+	if ew.blockWarningQueue.Empty() {
+		return nil
+	}
+
+	top := ew.blockWarningQueue.Top().(*invoiceExpiryHeight)
+	if !top.expired(ew.currentHeight, ew.blockWarningDelta) {
+		return nil
+	}
+
+	blockChan := make(chan uint32, 1)
+	blockChan <- top.expiryHeight
+	return blockChan
+}
+
 // cancelNextExpiredInvoice will cancel the next expired invoice and removes
 // it from the expiry queue.
 func (ew *InvoiceExpiryWatcher) cancelNextExpiredInvoice() {
@@ -342,6 +388,33 @@ func (ew *InvoiceExpiryWatcher) cancelNextHeightExpiredInvoice() {
 	ew.blockExpiryQueue.Pop()
 }
 
+// warnNextHeightExpiringInvoice looks at our warning queue and sends out a
+// warning for the next invoice if we have reached its warning height.
+func (ew *InvoiceExpiryWatcher) warnNextHeightExpiringInvoice() {
+	if ew.blockWarningQueue.Empty() {
+		return
+	}
+
+	top := ew.blockWarningQueue.Top().(*invoiceExpiryHeight)
+	if !top.expired(ew.currentHeight, ew.blockWarningDelta) {
+		return
+	}
+	ew.blockWarningQueue.Pop()
+
+	// If the invoice is already due to be canceled, there is no point in
+	// warning about it anymore.
+	if top.expired(ew.currentHeight, ew.blockExpiryDelta) {
+		return
+	}
+
+	ew.warnInvoice(&HoldExpiryWarning{
+		PaymentHash:   top.paymentHash,
+		HtlcExpiry:    top.expiryHeight,
+		CancelHeight:  top.expiryHeight - ew.blockExpiryDelta,
+		CurrentHeight: ew.currentHeight,
+	})
+}
+
 // expireInvoice attempts to expire an invoice and logs an error if we get an
 // unexpected error.
 func (ew *InvoiceExpiryWatcher) expireInvoice(hash lntypes.Hash, force bool) {
@@ -376,8 +449,13 @@ func (ew *InvoiceExpiryWatcher) pushInvoices(invoices []invoiceExpiry) {
 			}
 
 		case *invoiceExpiryHeight:
-			if expiry != nil {
-				ew.blockExpiryQueue.Push(expiry)
+			if expiry == nil {
+				continue
+			}
+
+			ew.blockExpiryQueue.Push(expiry)
+			if ew.warningsEnabled() {
+				ew.blockWarningQueue.Push(expiry)
 			}
 
 		default:
@@ -394,9 +472,9 @@ func (ew *InvoiceExpiryWatcher) mainLoop(blockNtfns *chainntnfs.BlockEpochEvent)
 		ew.wg.Done()
 	}()
 
-	// We have two different queues, so we use a different cancel method
-	// depending on which expiry condition we have hit. Starting with time
-	// based expiry is an arbitrary choice to start off.
+	// We have multiple queues, so we use a different cancel (or warn)
+	// method depending on which expiry condition we have hit. Starting
+	// with time based expiry is an arbitrary choice to start off.
 	cancelNext := ew.cancelNextExpiredInvoice
 
 	for {
@@ -421,6 +499,10 @@ func (ew *InvoiceExpiryWatcher) mainLoop(blockNtfns *chainntnfs.BlockEpochEvent)
 				cancelNext = ew.cancelNextHeightExpiredInvoice
 				continue
 
+			case <-ew.nextHeightWarning():
+				cancelNext = ew.warnNextHeightExpiringInvoice
+				continue
+
 			case newInvoices := <-ew.newInvoices:
 				ew.pushInvoices(newInvoices)
 
@@ -28,7 +28,7 @@ func newInvoiceExpiryWatcherTest(t *testing.T, now time.Time,
 	mockNotifier := newMockNotifier()
 	test := &invoiceExpiryWatcherTest{
 		watcher: NewInvoiceExpiryWatcher(
-			clock.NewTestClock(testTime), 0,
+			clock.NewTestClock(testTime), 0, 0,
 			uint32(testCurrentHeight), nil, mockNotifier,
 		),
 		testData: generateInvoiceExpiryTestData(
@@ -46,7 +46,7 @@ func newInvoiceExpiryWatcherTest(t *testing.T, now time.Time,
 		)
 		test.wg.Done()
 		return nil
-	})
+	}, nil)
 
 	require.NoError(t, err, "cannot start InvoiceExpiryWatcher")
 
@@ -88,25 +88,25 @@ func (t *invoiceExpiryWatcherTest) checkExpectations() {
 // Tests that InvoiceExpiryWatcher can be started and stopped.
 func TestInvoiceExpiryWatcherStartStop(t *testing.T) {
 	watcher := NewInvoiceExpiryWatcher(
-		clock.NewTestClock(testTime), 0, uint32(testCurrentHeight), nil,
-		newMockNotifier(),
+		clock.NewTestClock(testTime), 0, 0, uint32(testCurrentHeight),
+		nil, newMockNotifier(),
 	)
 	cancel := func(lntypes.Hash, bool) error {
 		t.Fatalf("unexpected call")
 		return nil
 	}
 
-	if err := watcher.Start(cancel); err != nil {
+	if err := watcher.Start(cancel, nil); err != nil {
 		t.Fatalf("unexpected error upon start: %v", err)
 	}
 
-	if err := watcher.Start(cancel); err == nil {
+	if err := watcher.Start(cancel, nil); err == nil {
 		t.Fatalf("expected error upon second start")
 	}
 
 	watcher.Stop()
 
-	if err := watcher.Start(cancel); err != nil {
+	if err := watcher.Start(cancel, nil); err != nil {
 		t.Fatalf("unexpected error upon start: %v", err)
 	}
 }
@@ -290,3 +290,73 @@ func TestExpiryHeightArrives(t *testing.T) {
 	test.announceBlock(t, htlc2-delta)
 	test.assertCanceled(t, test.hash)
 }
+
+// TestHoldExpiryWarning tests that a warning is sent out for an accepted hodl
+// invoice once its warning height is reached, and that it is canceled later
+// on at its cancellation height.
+func TestHoldExpiryWarning(t *testing.T) {
+	t.Parallel()
+
+	const (
+		expiryDelta  uint32 = 2
+		warningDelta uint32 = 5
+	)
+
+	mockNotifier := newMockNotifier()
+	test := &hodlExpiryTest{
+		watcher: NewInvoiceExpiryWatcher(
+			clock.NewTestClock(testTime), expiryDelta,
+			warningDelta, uint32(testCurrentHeight), nil,
+			mockNotifier,
+		),
+		cancelChan:   make(chan lntypes.Hash),
+		mockNotifier: mockNotifier,
+	}
+
+	cancel := func(paymentHash lntypes.Hash, _ bool) error {
+		select {
+		case test.cancelChan <- paymentHash:
+		case <-time.After(testTimeout):
+		}
+
+		return nil
+	}
+
+	warnings := make(chan *HoldExpiryWarning, 1)
+	warn := func(warning *HoldExpiryWarning) {
+		warnings <- warning
+	}
+
+	require.NoError(t, test.watcher.Start(cancel, warn))
+	defer test.watcher.Stop()
+
+	test.hash = lntypes.Hash{1, 2, 3}
+	htlcExpiry := uint32(testCurrentHeight + 10)
+	test.watcher.AddInvoices(makeHeightExpiry(test.hash, htlcExpiry))
+
+	// One block before the warning height, nothing should happen yet.
+	test.announceBlock(t, htlcExpiry-warningDelta-1)
+	require.Empty(t, warnings)
+
+	// Once we reach the warning height, we expect a warning.
+	test.announceBlock(t, htlcExpiry-warningDelta)
+
+	select {
+	case warning := <-warnings:
+		require.Equal(t, &HoldExpiryWarning{
+			PaymentHash:   test.hash,
+			HtlcExpiry:    htlcExpiry,
+			CancelHeight:  htlcExpiry - expiryDelta,
+			CurrentHeight: htlcExpiry - warningDelta,
+		}, warning)
+
+	case <-time.After(testTimeout):
+		t.Fatalf("no warning received")
+	}
+
+	// At the cancellation height the invoice is canceled, without sending
+	// out another warning.
+	test.announceBlock(t, htlcExpiry-expiryDelta)
+	test.assertCanceled(t, test.hash)
+	require.Empty(t, warnings)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
 )
 
 var (
@@ -78,6 +79,27 @@ type RegistryConfig struct {
 	// HtlcInterceptor is an interface that allows the invoice registry to
 	// let clients intercept invoices before they are settled.
 	HtlcInterceptor HtlcInterceptor
+
+	// PreimageProvider if set, is asked for the preimage of every hold
+	// invoice whose htlc set has been fully accepted. The invoice is
+	// settled with the released preimage, or canceled if the provider
+	// fails or times out.
+	PreimageProvider PreimageProvider
+
+	// PreimageProviderTimeout is the maximum time we wait for the
+	// preimage provider to release a preimage before the invoice is
+	// canceled. If zero, DefaultPreimageProviderTimeout is used.
+	PreimageProviderTimeout time.Duration
+
+	// StatelessSecret if set, enables the settlement of stateless
+	// invoices that were issued with this secret.
+	StatelessSecret *StatelessSecret
+
+	// FetchChannelPeer returns the peer of the channel with the given
+	// short channel id. It is used to enforce the allowed peers of an
+	// invoice's htlc constraints. If not set, htlcs to invoices that
+	// restrict their peers are rejected.
+	FetchChannelPeer func(lnwire.ShortChannelID) (route.Vertex, error)
 }
 
 // htlcReleaseEvent describes an htlc auto-release event. It is used to release
@@ -129,6 +151,10 @@ type InvoiceRegistry struct {
 	// performance.
 	singleNotificationClients map[uint32]*SingleInvoiceSubscription
 
+	// holdWarningClients are the subscribers of hold invoice expiry
+	// warnings.
+	holdWarningClients map[uint32]*HoldExpiryWarningSubscription
+
 	// invoiceEvents is a single channel over which invoice updates are
 	// carried.
 	invoiceEvents chan *invoiceEvent
@@ -166,10 +192,12 @@ func NewRegistry(idb InvoiceDB, expiryWatcher *InvoiceExpiryWatcher,
 
 	notificationClients := make(map[uint32]*InvoiceSubscription)
 	singleNotificationClients := make(map[uint32]*SingleInvoiceSubscription)
+	holdWarningClients := make(map[uint32]*HoldExpiryWarningSubscription)
 	return &InvoiceRegistry{
 		idb:                       idb,
 		notificationClients:       notificationClients,
 		singleNotificationClients: singleNotificationClients,
+		holdWarningClients:        holdWarningClients,
 		invoiceEvents:             make(chan *invoiceEvent, 100),
 		hodlSubscriptions: make(
 			map[CircuitKey]map[chan<- interface{}]struct{},
@@ -200,6 +228,12 @@ func (i *InvoiceRegistry) scanInvoicesOnStart(ctx context.Context) error {
 		if expiryRef != nil {
 			pending = append(pending, expiryRef)
 		}
+
+		// Hold invoices that were accepted before we shut down are
+		// handed to the preimage provider again.
+		if invoice.State == ContractAccepted {
+			i.maybeRequestPreimage(paymentHash, &invoice)
+		}
 	}
 
 	log.Debugf("Adding %d pending invoices to the expiry watcher",
@@ -234,7 +268,8 @@ func (i *InvoiceRegistry) Start() error {
 			return i.cancelInvoiceImpl(
 				context.Background(), hash, force,
 			)
-		})
+		}, i.warnHoldInvoice,
+	)
 	if err != nil {
 		return err
 	}
@@ -289,6 +324,10 @@ type invoiceEvent struct {
 	hash    lntypes.Hash
 	invoice *Invoice
 	setID   *[32]byte
+
+	// setOnly indicates that the event only concerns the subscribers of
+	// the AMP set identified by setID.
+	setOnly bool
 }
 
 // tickAt returns a channel that ticks at the specified time. If the time has
@@ -324,7 +363,7 @@ func (i *InvoiceRegistry) invoiceEventLoop() {
 			// invoice subscribers of cancel and accept events.
 			state := event.invoice.State
 			if state != ContractCanceled &&
-				state != ContractAccepted {
+				state != ContractAccepted && !event.setOnly {
 
 				i.dispatchToClients(event)
 			}
@@ -366,8 +405,18 @@ func (i *InvoiceRegistry) dispatchToSingleClients(event *invoiceEvent) {
 	clients := i.copySingleClients()
 	for _, client := range clients {
 		payHash := client.invoiceRef.PayHash()
+		setID := client.invoiceRef.SetID()
 
-		if payHash == nil || *payHash != event.hash {
+		switch {
+		// Subscribers of an AMP set receive all events of their set.
+		case setID != nil:
+			if event.setID == nil || *event.setID != *setID {
+				continue
+			}
+
+		// Subscribers of an invoice don't receive events that only
+		// concern a single AMP set.
+		case payHash == nil || *payHash != event.hash || event.setOnly:
 			continue
 		}
 
@@ -549,20 +598,26 @@ func (i *InvoiceRegistry) deliverSingleBacklogEvents(ctx context.Context,
 	}
 
 	payHash := client.invoiceRef.PayHash()
-	if payHash == nil {
+	setID := client.invoiceRef.SetID()
+	if payHash == nil && setID == nil {
 		return nil
 	}
 
-	err = client.notify(&invoiceEvent{
-		hash:    *payHash,
+	event := &invoiceEvent{
 		invoice: &invoice,
-	})
+		setID:   setID,
+	}
+	if payHash != nil {
+		event.hash = *payHash
+	}
+
+	err = client.notify(event)
 	if err != nil {
 		return err
 	}
 
-	log.Debugf("Client(id=%v) delivered single backlog event: payHash=%v",
-		client.id, payHash)
+	log.Debugf("Client(id=%v) delivered single backlog event: ref=%v",
+		client.id, client.invoiceRef)
 
 	return nil
 }
@@ -702,6 +757,24 @@ func (i *InvoiceRegistry) cancelSingleHtlc(invoiceRef InvoiceRef,
 			return nil, nil
 		}
 
+		// If the accepted htlcs reached the minimum amount of the
+		// invoice's partial payment policy, we settle the invoice
+		// rather than letting the incomplete set time out.
+		if result == ResultMppTimeout &&
+			partialSettleEligible(invoice) {
+
+			log.Debugf("cancelSingleHtlc: settling partially paid "+
+				"invoice %v", invoiceRef)
+
+			return &InvoiceUpdateDesc{
+				UpdateType: AddHTLCsUpdate,
+				State: &InvoiceStateUpdateDesc{
+					NewState: ContractSettled,
+					Preimage: invoice.Terms.PaymentPreimage,
+				},
+			}, nil
+		}
+
 		log.Debugf("cancelSingleHtlc: cancelling htlc %v on invoice %v",
 			key, invoiceRef)
 
@@ -744,6 +817,27 @@ func (i *InvoiceRegistry) cancelSingleHtlc(invoiceRef InvoiceRef,
 		return nil
 	}
 
+	// If the invoice was settled under its partial payment policy, we
+	// settle all of its htlcs with our peers.
+	if invoice.State == ContractSettled {
+		preimage := *invoice.Terms.PaymentPreimage
+		for key, htlc := range invoice.Htlcs {
+			if htlc.State != HtlcStateSettled {
+				continue
+			}
+
+			resolution := NewSettleResolution(
+				preimage, key, int32(htlc.AcceptHeight),
+				ResultSettledPartial,
+			)
+
+			i.notifyHodlSubscribers(resolution)
+		}
+		i.notifyClients(preimage.Hash(), invoice, nil)
+
+		return nil
+	}
+
 	// The invoice has been updated. Notify subscribers of the htlc
 	// resolution.
 	htlc, ok := invoice.Htlcs[key]
@@ -760,6 +854,27 @@ func (i *InvoiceRegistry) cancelSingleHtlc(invoiceRef InvoiceRef,
 	return nil
 }
 
+// partialSettleEligible returns true if the accepted htlcs of the given open
+// mpp invoice reach the minimum settle amount of its partial payment policy.
+func partialSettleEligible(invoice *Invoice) bool {
+	minAmt := invoice.Terms.PartialPayment.MinSettleAmt
+
+	// Hodl invoices are never settled automatically, and AMP invoices
+	// don't support partial payments.
+	if minAmt == 0 || invoice.HodlInvoice || invoice.IsAMP() ||
+		invoice.Terms.PaymentPreimage == nil {
+
+		return false
+	}
+
+	var total lnwire.MilliSatoshi
+	for _, htlc := range invoice.HTLCSet(nil, HtlcStateAccepted) {
+		total += htlc.Amt
+	}
+
+	return total >= minAmt
+}
+
 // processKeySend just-in-time inserts an invoice if this htlc is a keysend
 // htlc.
 func (i *InvoiceRegistry) processKeySend(ctx invoiceUpdateCtx) error {
@@ -900,6 +1015,72 @@ func (i *InvoiceRegistry) processAMP(ctx invoiceUpdateCtx) error {
 	}
 }
 
+// StatelessSecret returns the secret that stateless invoices are issued with,
+// or nil if stateless invoices are disabled.
+func (i *InvoiceRegistry) StatelessSecret() *StatelessSecret {
+	return i.cfg.StatelessSecret
+}
+
+// processStateless just-in-time inserts an invoice if this htlc pays to a
+// stateless payment address that was issued with our stateless secret.
+func (i *InvoiceRegistry) processStateless(ctx invoiceUpdateCtx) error {
+	payAddr := ctx.mpp.PaymentAddr()
+	amt := ctx.mpp.TotalMsat()
+
+	// If the payment address wasn't issued by us, this isn't a stateless
+	// invoice and we continue with the regular invoice lookup.
+	secret := i.cfg.StatelessSecret
+	if !secret.VerifyPaymentAddr(payAddr, amt) {
+		return nil
+	}
+
+	preimage := secret.Preimage(payAddr)
+	if preimage.Hash() != ctx.hash {
+		return ErrStatelessPreimageMismatch
+	}
+
+	// Use the minimum block delta that we require for settling htlcs.
+	finalCltvDelta := i.cfg.FinalCltvRejectDelta
+
+	// Pre-check expiry here to prevent inserting an invoice that will not
+	// be settled.
+	if ctx.expiry < uint32(ctx.currentHeight+finalCltvDelta) {
+		return errors.New("final expiry too soon")
+	}
+
+	rawFeatures := lnwire.NewRawFeatureVector(
+		lnwire.TLVOnionPayloadRequired,
+		lnwire.PaymentAddrRequired,
+		lnwire.MPPOptional,
+	)
+	features := lnwire.NewFeatureVector(rawFeatures, lnwire.Features)
+
+	// Create the invoice that the issuer would have stored, which is
+	// indexed by its payment address from here on.
+	invoice := &Invoice{
+		CreationDate: i.cfg.Clock.Now(),
+		Terms: ContractTerm{
+			FinalCltvDelta:  finalCltvDelta,
+			Value:           amt,
+			PaymentPreimage: &preimage,
+			PaymentAddr:     payAddr,
+			Features:        features,
+		},
+	}
+
+	// Insert invoice into database. Ignore duplicates, because this may
+	// be a replay or another htlc of the same mpp set.
+	_, err := i.AddInvoice(context.Background(), invoice, ctx.hash)
+	isDuplicatedInvoice := errors.Is(err, ErrDuplicateInvoice)
+	isDuplicatedPayAddr := errors.Is(err, ErrDuplicatePayAddr)
+	switch {
+	case isDuplicatedInvoice || isDuplicatedPayAddr:
+		return nil
+	default:
+		return err
+	}
+}
+
 // NotifyExitHopHtlc attempts to mark an invoice as settled. The return value
 // describes how the htlc should be resolved.
 //
@@ -968,6 +1149,20 @@ func (i *InvoiceRegistry) NotifyExitHopHtlc(rHash lntypes.Hash,
 		}
 	}
 
+	// If stateless invoices are enabled, mpp htlcs may pay to an invoice
+	// that we never stored. Insert it now so that it can be settled below.
+	if i.cfg.StatelessSecret != nil && ctx.mpp != nil && ctx.amp == nil {
+		err := i.processStateless(ctx)
+		if err != nil {
+			ctx.log(fmt.Sprintf("stateless invoice error: %v", err))
+
+			return NewFailResolution(
+				circuitKey, currentHeight,
+				ResultStatelessError,
+			), nil
+		}
+	}
+
 	// Execute locked notify exit hop logic.
 	i.Lock()
 	resolution, invoiceToExpire, err := i.notifyExitHopHtlcLocked(
@@ -1049,6 +1244,21 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 		return nil, nil, err
 	}
 
+	// If the invoice restricts the peers it may be paid from, look up the
+	// peer that sent us this htlc.
+	restrictsPeers := len(
+		existingInvoice.Terms.HtlcConstraints.AllowedPeers,
+	) != 0
+	if restrictsPeers && i.cfg.FetchChannelPeer != nil {
+		peer, err := i.cfg.FetchChannelPeer(ctx.circuitKey.ChanID)
+		if err != nil {
+			ctx.log(fmt.Sprintf("unable to fetch channel peer: %v",
+				err))
+		} else {
+			ctx.incomingPeer = &peer
+		}
+	}
+
 	// Provide the invoice to the settlement interceptor to allow
 	// the interceptor's client an opportunity to manipulate the
 	// settlement process.
@@ -1080,6 +1290,7 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 	var (
 		resolution        HtlcResolution
 		updateSubscribers bool
+		htlcAdded         bool
 	)
 	callback := func(inv *Invoice) (*InvoiceUpdateDesc, error) {
 		updateDesc, res, err := updateInvoice(ctx, inv)
@@ -1087,9 +1298,13 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 			return nil, err
 		}
 
-		// Only send an update if the invoice state was changed.
+		// Only send an update if the invoice state was changed, or if
+		// an htlc was added to an invoice that accepts partial
+		// payments so that subscribers can track its progress.
 		updateSubscribers = updateDesc != nil &&
-			updateDesc.State != nil
+			(updateDesc.State != nil ||
+				inv.Terms.PartialPayment.IsEnabled())
+		htlcAdded = updateDesc != nil && len(updateDesc.AddHtlcs) > 0
 
 		// Assign resolution to outer scope variable.
 		resolution = res
@@ -1258,6 +1473,7 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 		// expiry height could change.
 		if res.outcome == resultAccepted {
 			invoiceToExpire = makeInvoiceExpiry(ctx.hash, invoice)
+			i.maybeRequestPreimage(ctx.hash, invoice)
 		}
 
 		i.hodlSubscribe(hodlChan, ctx.circuitKey)
@@ -1280,6 +1496,14 @@ func (i *InvoiceRegistry) notifyExitHopHtlcLocked(
 		i.notifyClients(ctx.hash, invoice, setID)
 	}
 
+	// Subscribers of an AMP set are also notified about htlcs that are
+	// accepted into the set, as these don't change the invoice state.
+	if htlcAdded && ctx.amp != nil {
+		if _, ok := resolution.(*HtlcSettleResolution); !ok {
+			i.notifySetClients(invoice, *ctx.setID())
+		}
+	}
+
 	return resolution, invoiceToExpire, nil
 }
 
@@ -1687,6 +1911,14 @@ func (i *InvoiceRegistry) SubscribeNotifications(ctx context.Context,
 func (i *InvoiceRegistry) SubscribeSingleInvoice(ctx context.Context,
 	hash lntypes.Hash) (*SingleInvoiceSubscription, error) {
 
+	return i.subscribeSingle(ctx, InvoiceRefByHash(hash))
+}
+
+// subscribeSingle returns a SingleInvoiceSubscription for the invoice that is
+// identified by the given reference.
+func (i *InvoiceRegistry) subscribeSingle(ctx context.Context,
+	ref InvoiceRef) (*SingleInvoiceSubscription, error) {
+
 	client := &SingleInvoiceSubscription{
 		Updates: make(chan *Invoice),
 		invoiceSubscriptionKit: invoiceSubscriptionKit{
@@ -1695,7 +1927,7 @@ func (i *InvoiceRegistry) SubscribeSingleInvoice(ctx context.Context,
 			cancelChan:       make(chan struct{}),
 			backlogDelivered: make(chan struct{}),
 		},
-		invoiceRef: InvoiceRefByHash(hash),
+		invoiceRef: ref,
 	}
 	client.ntfnQueue.Start()
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"database/sql"
+	"errors"
 	"fmt"
 	"math"
 	"testing"
@@ -19,6 +20,7 @@ import (
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/sqldb"
 	"github.com/stretchr/testify/require"
 )
@@ -108,6 +110,30 @@ func TestInvoiceRegistry(t *testing.T) {
 			name: "SpontaneousAmpPayment",
 			test: testSpontaneousAmpPayment,
 		},
+		{
+			name: "StatelessInvoice",
+			test: testStatelessInvoice,
+		},
+		{
+			name: "CancelAMPSet",
+			test: testCancelAMPSet,
+		},
+		{
+			name: "PreimageProvider",
+			test: testPreimageProvider,
+		},
+		{
+			name: "MppPartialPayment",
+			test: testMppPartialPayment,
+		},
+		{
+			name: "HtlcConstraints",
+			test: testHtlcConstraints,
+		},
+		{
+			name: "FiatQuote",
+			test: testFiatQuote,
+		},
 	}
 
 	makeKeyValueDB := func(t *testing.T) (invpkg.InvoiceDB,
@@ -532,7 +558,8 @@ func testSettleHoldInvoice(t *testing.T,
 	}
 
 	expiryWatcher := invpkg.NewInvoiceExpiryWatcher(
-		cfg.Clock, 0, uint32(testCurrentHeight), nil, newMockNotifier(),
+		cfg.Clock, 0, 0, uint32(testCurrentHeight), nil,
+		newMockNotifier(),
 	)
 	registry := invpkg.NewRegistry(idb, expiryWatcher, &cfg)
 
@@ -701,7 +728,8 @@ func testCancelHoldInvoice(t *testing.T,
 		HtlcInterceptor:      htlcModifierMock,
 	}
 	expiryWatcher := invpkg.NewInvoiceExpiryWatcher(
-		cfg.Clock, 0, uint32(testCurrentHeight), nil, newMockNotifier(),
+		cfg.Clock, 0, 0, uint32(testCurrentHeight), nil,
+		newMockNotifier(),
 	)
 	registry := invpkg.NewRegistry(idb, expiryWatcher, &cfg)
 
@@ -1128,6 +1156,71 @@ func testMppPayment(t *testing.T,
 	}
 }
 
+// testStatelessInvoice tests that an mpp payment to a stateless payment
+// address is settled without the invoice having been added beforehand.
+func testStatelessInvoice(t *testing.T,
+	makeDB func(t *testing.T) (invpkg.InvoiceDB, *clock.TestClock)) {
+
+	t.Parallel()
+	defer timeout()()
+
+	secret := invpkg.StatelessSecret{1, 2, 3}
+	cfg := defaultRegistryConfig()
+	cfg.StatelessSecret = &secret
+
+	ctx := newTestContext(t, &cfg, makeDB)
+	ctxb := context.Background()
+
+	payAddr := secret.PaymentAddr([16]byte{4, 5, 6}, testInvoiceAmount)
+	preimage := secret.Preimage(payAddr)
+	payHash := preimage.Hash()
+
+	mppPayload := &mockPayload{
+		mpp: record.NewMPP(testInvoiceAmount, payAddr),
+	}
+
+	// An htlc that pays to the stateless payment address with a payment
+	// hash that doesn't match the derived preimage is failed.
+	resolution, err := ctx.registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, testInvoiceAmount/2, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(10), nil, nil, mppPayload,
+	)
+	require.NoError(t, err)
+	checkFailResolution(t, resolution, invpkg.ResultStatelessError)
+
+	// Send the first half of the payment, which should be held.
+	hodlChan1 := make(chan interface{}, 1)
+	resolution, err = ctx.registry.NotifyExitHopHtlc(
+		payHash, testInvoiceAmount/2, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(11), hodlChan1, nil,
+		mppPayload,
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+
+	// The second half completes the set and settles the invoice.
+	hodlChan2 := make(chan interface{}, 1)
+	resolution, err = ctx.registry.NotifyExitHopHtlc(
+		payHash, testInvoiceAmount/2, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(12), hodlChan2, nil,
+		mppPayload,
+	)
+	require.NoError(t, err)
+
+	settleResolution, ok := resolution.(*invpkg.HtlcSettleResolution)
+	require.True(t, ok)
+	require.Equal(t, invpkg.ResultSettled, settleResolution.Outcome)
+	require.Equal(t, preimage, settleResolution.Preimage)
+
+	// The invoice can now be looked up by its payment address.
+	inv, err := ctx.registry.LookupInvoiceByRef(
+		ctxb, invpkg.InvoiceRefByAddr(payAddr),
+	)
+	require.NoError(t, err)
+	require.Equal(t, invpkg.ContractSettled, inv.State)
+	require.Equal(t, testInvoiceAmount, inv.AmtPaid)
+}
+
 // testMppPaymentWithOverpayment tests settling of an invoice with multiple
 // partial payments. It covers the case where the mpp overpays what is in the
 // invoice.
@@ -1222,7 +1315,8 @@ func testInvoiceExpiryWithRegistry(t *testing.T,
 	}
 
 	expiryWatcher := invpkg.NewInvoiceExpiryWatcher(
-		cfg.Clock, 0, uint32(testCurrentHeight), nil, newMockNotifier(),
+		cfg.Clock, 0, 0, uint32(testCurrentHeight), nil,
+		newMockNotifier(),
 	)
 	registry := invpkg.NewRegistry(idb, expiryWatcher, &cfg)
 
@@ -1333,7 +1427,8 @@ func testOldInvoiceRemovalOnStart(t *testing.T,
 	}
 
 	expiryWatcher := invpkg.NewInvoiceExpiryWatcher(
-		cfg.Clock, 0, uint32(testCurrentHeight), nil, newMockNotifier(),
+		cfg.Clock, 0, 0, uint32(testCurrentHeight), nil,
+		newMockNotifier(),
 	)
 	registry := invpkg.NewRegistry(idb, expiryWatcher, &cfg)
 
@@ -2109,3 +2204,529 @@ func testSpontaneousAmpPaymentImpl(
 		}
 	}
 }
+
+// testCancelAMPSet tests that a pending AMP set can be canceled without
+// affecting the AMP invoice, and that subscribers of the set are notified.
+func testCancelAMPSet(t *testing.T,
+	makeDB func(t *testing.T) (invpkg.InvoiceDB, *clock.TestClock)) {
+
+	t.Parallel()
+	defer timeout()()
+
+	cfg := defaultRegistryConfig()
+	cfg.AcceptAMP = true
+	ctx := newTestContext(t, &cfg, makeDB)
+	ctxb := context.Background()
+
+	const totalAmt = lnwire.MilliSatoshi(360)
+
+	var payAddr, setID [32]byte
+	_, err := rand.Read(payAddr[:])
+	require.NoError(t, err)
+	_, err = rand.Read(setID[:])
+	require.NoError(t, err)
+
+	subscription, err := ctx.registry.SubscribeAMPSet(ctxb, setID)
+	require.NoError(t, err)
+	defer subscription.Cancel()
+
+	sharer, err := amp.NewSeedSharer()
+	require.NoError(t, err)
+	child := sharer.Child(0)
+
+	// Send only the first shard of the set, which leaves the set pending.
+	hodlChan := make(chan interface{}, 1)
+	resolution, err := ctx.registry.NotifyExitHopHtlc(
+		child.Hash, totalAmt/2, testHtlcExpiry, testCurrentHeight,
+		getCircuitKey(0), hodlChan, nil, &mockPayload{
+			mpp: record.NewMPP(totalAmt, payAddr),
+			amp: record.NewAMP(child.Share, setID, 0),
+		},
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+
+	// The subscriber of the set is notified about the accepted htlc.
+	update := <-subscription.Updates
+	require.Equal(
+		t, invpkg.HtlcStateAccepted, update.AMPState[setID].State,
+	)
+
+	invoice, state, err := ctx.registry.LookupAMPSet(ctxb, setID)
+	require.NoError(t, err)
+	require.Equal(t, invpkg.HtlcStateAccepted, state.State)
+	require.Len(t, invoice.PendingAMPSets(), 1)
+
+	// Cancel the set and assert that the htlc is failed back.
+	require.NoError(t, ctx.registry.CancelAMPSet(ctxb, setID))
+
+	resolution, ok := (<-hodlChan).(invpkg.HtlcResolution)
+	require.True(t, ok)
+	checkFailResolution(t, resolution, invpkg.ResultCanceled)
+
+	update = <-subscription.Updates
+	require.Equal(t, invpkg.ContractOpen, update.State)
+	for _, htlc := range update.Htlcs {
+		require.Equal(t, invpkg.HtlcStateCanceled, htlc.State)
+	}
+
+	// The set can't be canceled again.
+	err = ctx.registry.CancelAMPSet(ctxb, setID)
+	require.ErrorIs(t, err, invpkg.ErrAMPSetNotPending)
+}
+
+// mockPreimageProvider is a PreimageProvider that releases a fixed preimage or
+// returns a fixed error.
+type mockPreimageProvider struct {
+	requests chan invpkg.PreimageRequest
+	preimage lntypes.Preimage
+	err      error
+}
+
+// RequestPreimage records the request and returns the configured preimage and
+// error.
+func (m *mockPreimageProvider) RequestPreimage(_ context.Context,
+	req invpkg.PreimageRequest) (lntypes.Preimage, error) {
+
+	m.requests <- req
+
+	return m.preimage, m.err
+}
+
+// testPreimageProvider tests that accepted hold invoices are settled with the
+// preimage released by the preimage provider, and canceled if the provider
+// doesn't release a valid preimage.
+func testPreimageProvider(t *testing.T,
+	makeDB func(t *testing.T) (invpkg.InvoiceDB, *clock.TestClock)) {
+
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		preimage  lntypes.Preimage
+		err       error
+		expSettle bool
+	}{
+		{
+			name:      "preimage released",
+			preimage:  testInvoicePreimage,
+			expSettle: true,
+		},
+		{
+			name: "provider error",
+			err:  errors.New("fiat payment not received"),
+		},
+		{
+			name:     "preimage mismatch",
+			preimage: lntypes.Preimage{9},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			defer timeout()()
+
+			provider := &mockPreimageProvider{
+				requests: make(chan invpkg.PreimageRequest, 1),
+				preimage: test.preimage,
+				err:      test.err,
+			}
+
+			cfg := defaultRegistryConfig()
+			cfg.PreimageProvider = provider
+			ctx := newTestContext(t, &cfg, makeDB)
+			ctxb := context.Background()
+
+			invoice := newInvoice(t, true)
+			_, err := ctx.registry.AddInvoice(
+				ctxb, invoice, testInvoicePaymentHash,
+			)
+			require.NoError(t, err)
+
+			// Accepting the htlc hands the invoice to the preimage
+			// provider.
+			hodlChan := make(chan interface{}, 1)
+			resolution, err := ctx.registry.NotifyExitHopHtlc(
+				testInvoicePaymentHash, testInvoiceAmount,
+				testHtlcExpiry, testCurrentHeight,
+				getCircuitKey(0), hodlChan, nil, testPayload,
+			)
+			require.NoError(t, err)
+			require.Nil(t, resolution)
+
+			req := <-provider.requests
+			require.Equal(
+				t, testInvoicePaymentHash, req.PaymentHash,
+			)
+			require.EqualValues(t, testHtlcExpiry, req.HtlcExpiry)
+			require.Equal(
+				t, invpkg.ContractAccepted, req.Invoice.State,
+			)
+
+			resolution, ok := (<-hodlChan).(invpkg.HtlcResolution)
+			require.True(t, ok)
+
+			expState := invpkg.ContractCanceled
+			if test.expSettle {
+				expState = invpkg.ContractSettled
+				checkSettleResolution(
+					t, resolution, testInvoicePreimage,
+				)
+			} else {
+				checkFailResolution(
+					t, resolution, invpkg.ResultCanceled,
+				)
+			}
+
+			inv, err := ctx.registry.LookupInvoice(
+				ctxb, testInvoicePaymentHash,
+			)
+			require.NoError(t, err)
+			require.Equal(t, expState, inv.State)
+		})
+	}
+}
+
+// testMppPartialPayment tests that an invoice with a partial payment policy
+// accepts htlc sets that pay less than its value.
+func testMppPartialPayment(t *testing.T,
+	makeDB func(t *testing.T) (invpkg.InvoiceDB, *clock.TestClock)) {
+
+	t.Parallel()
+	defer timeout()()
+
+	addInvoice := func(t *testing.T, ctx *testContext,
+		policy invpkg.PartialPaymentPolicy) *invpkg.Invoice {
+
+		testInvoice := newInvoice(t, false)
+		testInvoice.Terms.PartialPayment = policy
+		_, err := ctx.registry.AddInvoice(
+			context.Background(), testInvoice,
+			testInvoicePaymentHash,
+		)
+		require.NoError(t, err)
+
+		return testInvoice
+	}
+
+	// A set that pays the invoice value minus the tolerance is settled
+	// right away.
+	t.Run("tolerance", func(t *testing.T) {
+		ctx := newTestContext(t, nil, makeDB)
+		testInvoice := addInvoice(t, ctx, invpkg.PartialPaymentPolicy{
+			Tolerance: testInvoiceAmount / 10,
+		})
+
+		// A set that falls short by more than the tolerance is
+		// rejected.
+		lowAmt := testInvoiceAmount * 8 / 10
+		resolution, err := ctx.registry.NotifyExitHopHtlc(
+			testInvoicePaymentHash, lowAmt, testHtlcExpiry,
+			testCurrentHeight, getCircuitKey(10),
+			make(chan interface{}, 1), nil, &mockPayload{
+				mpp: record.NewMPP(lowAmt, [32]byte{}),
+			},
+		)
+		require.NoError(t, err)
+		checkFailResolution(
+			t, resolution, invpkg.ResultHtlcSetTotalTooLow,
+		)
+
+		amt := testInvoiceAmount * 95 / 100
+		resolution, err = ctx.registry.NotifyExitHopHtlc(
+			testInvoicePaymentHash, amt, testHtlcExpiry,
+			testCurrentHeight, getCircuitKey(11),
+			make(chan interface{}, 1), nil, &mockPayload{
+				mpp: record.NewMPP(amt, [32]byte{}),
+			},
+		)
+		require.NoError(t, err)
+		settleResolution := checkSettleResolution(
+			t, resolution, *testInvoice.Terms.PaymentPreimage,
+		)
+		require.Equal(
+			t, invpkg.ResultSettledPartial,
+			settleResolution.Outcome,
+		)
+
+		inv, err := ctx.registry.LookupInvoice(
+			context.Background(), testInvoicePaymentHash,
+		)
+		require.NoError(t, err)
+		require.Equal(t, invpkg.ContractSettled, inv.State)
+		require.Equal(t, amt, inv.AmtPaid)
+	})
+
+	// An incomplete set that reached the minimum settle amount is settled
+	// when its htlcs time out.
+	t.Run("min settle amount", func(t *testing.T) {
+		ctx := newTestContext(t, nil, makeDB)
+		testInvoice := addInvoice(t, ctx, invpkg.PartialPaymentPolicy{
+			MinSettleAmt: testInvoiceAmount / 2,
+		})
+
+		subscription, err := ctx.registry.SubscribeSingleInvoice(
+			context.Background(), testInvoicePaymentHash,
+		)
+		require.NoError(t, err)
+		defer subscription.Cancel()
+
+		mppPayload := &mockPayload{
+			mpp: record.NewMPP(testInvoiceAmount, [32]byte{}),
+		}
+
+		hodlChan := make(chan interface{}, 1)
+		resolution, err := ctx.registry.NotifyExitHopHtlc(
+			testInvoicePaymentHash, testInvoiceAmount/2,
+			testHtlcExpiry, testCurrentHeight, getCircuitKey(10),
+			hodlChan, nil, mppPayload,
+		)
+		require.NoError(t, err)
+		require.Nil(t, resolution)
+
+		// The subscriber is notified of the accepted htlc. The updates
+		// for the added invoice may arrive before it, so we skip them.
+		update := waitForInvoiceUpdate(
+			t, subscription, func(inv *invpkg.Invoice) bool {
+				return len(inv.Htlcs) == 1
+			},
+		)
+		require.Equal(t, invpkg.ContractOpen, update.State)
+
+		// Simulate the mpp timeout, which settles the invoice instead
+		// of canceling the htlc.
+		ctx.clock.SetTime(testTime.Add(30 * time.Second))
+
+		var htlcResolution invpkg.HtlcResolution
+		select {
+		case res := <-hodlChan:
+			htlcResolution = res.(invpkg.HtlcResolution)
+
+		case <-time.After(testTimeout):
+			t.Fatal("no resolution received")
+		}
+		settleResolution := checkSettleResolution(
+			t, htlcResolution, *testInvoice.Terms.PaymentPreimage,
+		)
+		require.Equal(
+			t, invpkg.ResultSettledPartial,
+			settleResolution.Outcome,
+		)
+
+		update = waitForInvoiceUpdate(
+			t, subscription, func(inv *invpkg.Invoice) bool {
+				return inv.State == invpkg.ContractSettled
+			},
+		)
+		require.Equal(t, testInvoiceAmount/2, update.AmtPaid)
+	})
+}
+
+// waitForInvoiceUpdate waits for the first update of the subscription that
+// matches the given predicate and returns it.
+func waitForInvoiceUpdate(t *testing.T,
+	subscription *invpkg.SingleInvoiceSubscription,
+	match func(*invpkg.Invoice) bool) *invpkg.Invoice {
+
+	t.Helper()
+
+	for {
+		select {
+		case update := <-subscription.Updates:
+			if match(update) {
+				return update
+			}
+
+		case <-time.After(testTimeout):
+			t.Fatal("no matching update received")
+		}
+	}
+}
+
+// testHtlcConstraints tests that the htlc constraints of an invoice are
+// enforced for the htlcs that pay it.
+func testHtlcConstraints(t *testing.T,
+	makeDB func(t *testing.T) (invpkg.InvoiceDB, *clock.TestClock)) {
+
+	t.Parallel()
+	defer timeout()()
+
+	// All htlcs in this test arrive on the channel of getCircuitKey, which
+	// belongs to the peer below.
+	peer := route.Vertex{1}
+	cfg := defaultRegistryConfig()
+	cfg.FetchChannelPeer = func(lnwire.ShortChannelID) (route.Vertex,
+		error) {
+
+		return peer, nil
+	}
+
+	addInvoice := func(t *testing.T,
+		constraints invpkg.HtlcConstraints) *testContext {
+
+		ctx := newTestContext(t, &cfg, makeDB)
+
+		testInvoice := newInvoice(t, false)
+		testInvoice.Terms.HtlcConstraints = constraints
+		_, err := ctx.registry.AddInvoice(
+			context.Background(), testInvoice,
+			testInvoicePaymentHash,
+		)
+		require.NoError(t, err)
+
+		// The constraints are persisted with the invoice. The allowed
+		// channels and peers are sets, so their order isn't kept.
+		inv, err := ctx.registry.LookupInvoice(
+			context.Background(), testInvoicePaymentHash,
+		)
+		require.NoError(t, err)
+		stored := inv.Terms.HtlcConstraints
+		require.Equal(t, constraints.MaxHtlcs, stored.MaxHtlcs)
+		require.Equal(t, constraints.MinHtlcAmt, stored.MinHtlcAmt)
+		require.ElementsMatch(
+			t, constraints.AllowedChannels, stored.AllowedChannels,
+		)
+		require.ElementsMatch(
+			t, constraints.AllowedPeers, stored.AllowedPeers,
+		)
+
+		return ctx
+	}
+
+	sendHtlc := func(t *testing.T, ctx *testContext, htlcID uint64,
+		amt lnwire.MilliSatoshi) invpkg.HtlcResolution {
+
+		mppPayload := &mockPayload{
+			mpp: record.NewMPP(testInvoiceAmount, [32]byte{}),
+		}
+		resolution, err := ctx.registry.NotifyExitHopHtlc(
+			testInvoicePaymentHash, amt, testHtlcExpiry,
+			testCurrentHeight, getCircuitKey(htlcID),
+			make(chan interface{}, 1), nil, mppPayload,
+		)
+		require.NoError(t, err)
+
+		return resolution
+	}
+
+	t.Run("max htlcs", func(t *testing.T) {
+		ctx := addInvoice(t, invpkg.HtlcConstraints{
+			MaxHtlcs: 2,
+		})
+
+		// The first two htlcs are held, the third one would exceed
+		// the maximum set size.
+		require.Nil(t, sendHtlc(t, ctx, 10, testInvoiceAmount/4))
+		require.Nil(t, sendHtlc(t, ctx, 11, testInvoiceAmount/4))
+		checkFailResolution(
+			t, sendHtlc(t, ctx, 12, testInvoiceAmount/2),
+			invpkg.ResultHtlcConstraintViolation,
+		)
+	})
+
+	t.Run("min htlc amount", func(t *testing.T) {
+		ctx := addInvoice(t, invpkg.HtlcConstraints{
+			MinHtlcAmt: testInvoiceAmount / 2,
+		})
+
+		checkFailResolution(
+			t, sendHtlc(t, ctx, 10, testInvoiceAmount/4),
+			invpkg.ResultHtlcConstraintViolation,
+		)
+
+		require.Nil(t, sendHtlc(t, ctx, 11, testInvoiceAmount/2))
+		checkSettleResolution(
+			t, sendHtlc(t, ctx, 12, testInvoiceAmount/2),
+			testInvoicePreimage,
+		)
+	})
+
+	t.Run("allowed channels", func(t *testing.T) {
+		ctx := addInvoice(t, invpkg.HtlcConstraints{
+			AllowedChannels: []lnwire.ShortChannelID{
+				lnwire.NewShortChanIDFromInt(1),
+			},
+		})
+
+		checkFailResolution(
+			t, sendHtlc(t, ctx, 10, testInvoiceAmount),
+			invpkg.ResultHtlcConstraintViolation,
+		)
+
+		ctx = addInvoice(t, invpkg.HtlcConstraints{
+			AllowedChannels: []lnwire.ShortChannelID{
+				getCircuitKey(0).ChanID,
+			},
+		})
+		checkSettleResolution(
+			t, sendHtlc(t, ctx, 10, testInvoiceAmount),
+			testInvoicePreimage,
+		)
+	})
+
+	t.Run("allowed peers", func(t *testing.T) {
+		ctx := addInvoice(t, invpkg.HtlcConstraints{
+			AllowedPeers: []route.Vertex{{2}},
+		})
+
+		checkFailResolution(
+			t, sendHtlc(t, ctx, 10, testInvoiceAmount),
+			invpkg.ResultHtlcConstraintViolation,
+		)
+
+		ctx = addInvoice(t, invpkg.HtlcConstraints{
+			AllowedPeers: []route.Vertex{{2}, peer},
+		})
+		checkSettleResolution(
+			t, sendHtlc(t, ctx, 10, testInvoiceAmount),
+			testInvoicePreimage,
+		)
+	})
+}
+
+// testFiatQuote tests that the fiat quote of an invoice is persisted with the
+// invoice.
+func testFiatQuote(t *testing.T,
+	makeDB func(t *testing.T) (invpkg.InvoiceDB, *clock.TestClock)) {
+
+	t.Parallel()
+
+	ctx := newTestContext(t, nil, makeDB)
+	ctxb := context.Background()
+
+	quote := &invpkg.FiatQuote{
+		Currency:   "USD",
+		Amount:     1234,
+		RateSource: "test",
+		QuoteTime:  testNow,
+	}
+	testInvoice := newInvoice(t, false)
+	testInvoice.FiatQuote = quote
+	_, err := ctx.registry.AddInvoice(
+		ctxb, testInvoice, testInvoicePaymentHash,
+	)
+	require.NoError(t, err)
+
+	inv, err := ctx.registry.LookupInvoice(ctxb, testInvoicePaymentHash)
+	require.NoError(t, err)
+	require.NotNil(t, inv.FiatQuote)
+	require.Equal(t, quote.Currency, inv.FiatQuote.Currency)
+	require.Equal(t, quote.Amount, inv.FiatQuote.Amount)
+	require.Equal(t, quote.RateSource, inv.FiatQuote.RateSource)
+	require.True(t, quote.QuoteTime.Equal(inv.FiatQuote.QuoteTime))
+
+	// Invoices without a quote don't get one when they're loaded.
+	plainInvoice := newInvoice(t, false)
+	plainPreimage := lntypes.Preimage{2}
+	plainInvoice.Terms.PaymentPreimage = &plainPreimage
+	plainHash := plainPreimage.Hash()
+	_, err = ctx.registry.AddInvoice(ctxb, plainInvoice, plainHash)
+	require.NoError(t, err)
+
+	inv, err = ctx.registry.LookupInvoice(ctxb, plainHash)
+	require.NoError(t, err)
+	require.Nil(t, inv.FiatQuote)
+}
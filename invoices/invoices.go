@@ -10,6 +10,7 @@ import (
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
 )
 
 const (
@@ -272,6 +273,164 @@ type ContractTerm struct {
 
 	// Features is the feature vectors advertised on the payment request.
 	Features *lnwire.FeatureVector
+
+	// PartialPayment is the policy used to accept htlc sets that pay less
+	// than Value. The zero value requires the full Value to be paid.
+	PartialPayment PartialPaymentPolicy
+
+	// HtlcConstraints restricts the htlcs that may pay to the invoice. The
+	// zero value accepts any htlc.
+	HtlcConstraints HtlcConstraints
+}
+
+// HtlcConstraints restricts the htlcs that are accepted to pay an invoice,
+// which gives the receiver control over the dust and privacy properties of
+// incoming payments.
+type HtlcConstraints struct {
+	// MaxHtlcs is the maximum number of htlcs that may make up the htlc
+	// set that pays the invoice. Zero means no limit.
+	MaxHtlcs uint32
+
+	// MinHtlcAmt is the minimum amount of every htlc that pays the
+	// invoice.
+	MinHtlcAmt lnwire.MilliSatoshi
+
+	// AllowedChannels is the set of incoming channels that htlcs may
+	// arrive on. If empty, htlcs are accepted on any channel. Channels are
+	// identified by the short channel id the htlc was received on, which
+	// is the alias for channels that use the scid alias feature.
+	AllowedChannels []lnwire.ShortChannelID
+
+	// AllowedPeers is the set of peers that htlcs may be received from.
+	// If empty, htlcs are accepted from any peer.
+	AllowedPeers []route.Vertex
+}
+
+// IsEnabled returns true if any constraint is set.
+func (c HtlcConstraints) IsEnabled() bool {
+	return c.MaxHtlcs != 0 || c.MinHtlcAmt != 0 ||
+		len(c.AllowedChannels) != 0 || len(c.AllowedPeers) != 0
+}
+
+// Copy returns a deep copy of the constraints.
+func (c HtlcConstraints) Copy() HtlcConstraints {
+	if c.AllowedChannels != nil {
+		c.AllowedChannels = append(
+			[]lnwire.ShortChannelID{}, c.AllowedChannels...,
+		)
+	}
+
+	if c.AllowedPeers != nil {
+		c.AllowedPeers = append([]route.Vertex{}, c.AllowedPeers...)
+	}
+
+	return c
+}
+
+// Validate checks that the constraints are sane for an invoice of the given
+// value.
+func (c HtlcConstraints) Validate(value lnwire.MilliSatoshi) error {
+	if value != 0 && c.MinHtlcAmt > value {
+		return fmt.Errorf("minimum htlc amount %v exceeds invoice "+
+			"value %v", c.MinHtlcAmt, value)
+	}
+
+	return nil
+}
+
+// allowsChannel returns true if htlcs may be received on the given channel.
+func (c HtlcConstraints) allowsChannel(chanID lnwire.ShortChannelID) bool {
+	if len(c.AllowedChannels) == 0 {
+		return true
+	}
+
+	for _, allowed := range c.AllowedChannels {
+		if allowed == chanID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsPeer returns true if htlcs may be received from the given peer. If
+// the peer is unknown, it is only allowed if no peers are restricted.
+func (c HtlcConstraints) allowsPeer(peer *route.Vertex) bool {
+	if len(c.AllowedPeers) == 0 {
+		return true
+	}
+
+	if peer == nil {
+		return false
+	}
+
+	for _, allowed := range c.AllowedPeers {
+		if allowed == *peer {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PartialPaymentPolicy describes under which conditions an mpp invoice may be
+// settled for less than its value, which is useful for donations and
+// crowdfunding.
+type PartialPaymentPolicy struct {
+	// Tolerance is the amount by which the total of an htlc set may fall
+	// short of the invoice value for the set to be accepted.
+	Tolerance lnwire.MilliSatoshi
+
+	// MinSettleAmt is the minimum amount that must be received before the
+	// invoice may be settled for less than its value. If set, htlc sets
+	// that total at least this amount are accepted, and an incomplete
+	// set that has reached it is settled rather than canceled when its
+	// htlcs time out.
+	MinSettleAmt lnwire.MilliSatoshi
+}
+
+// IsEnabled returns true if the policy allows partial payments.
+func (p PartialPaymentPolicy) IsEnabled() bool {
+	return p.Tolerance != 0 || p.MinSettleAmt != 0
+}
+
+// MinSetTotal returns the minimum total an htlc set must commit to for an
+// invoice of the given value.
+func (p PartialPaymentPolicy) MinSetTotal(
+	value lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	minTotal := value
+	if p.Tolerance < value {
+		minTotal = value - p.Tolerance
+	}
+
+	if p.MinSettleAmt != 0 && p.MinSettleAmt < minTotal {
+		minTotal = p.MinSettleAmt
+	}
+
+	return minTotal
+}
+
+// Validate checks that the policy is sane for an invoice of the given value.
+func (p PartialPaymentPolicy) Validate(value lnwire.MilliSatoshi) error {
+	if !p.IsEnabled() {
+		return nil
+	}
+
+	switch {
+	case value == 0:
+		return errors.New("partial payments require an invoice value")
+
+	case p.Tolerance >= value:
+		return fmt.Errorf("partial payment tolerance %v must be less "+
+			"than invoice value %v", p.Tolerance, value)
+
+	case p.MinSettleAmt > value:
+		return fmt.Errorf("partial payment minimum %v exceeds invoice "+
+			"value %v", p.MinSettleAmt, value)
+	}
+
+	return nil
 }
 
 // String returns a human-readable description of the prominent contract terms.
@@ -419,6 +578,10 @@ type Invoice struct {
 	// HodlInvoice indicates whether the invoice should be held in the
 	// Accepted state or be settled right away.
 	HodlInvoice bool
+
+	// FiatQuote is the optional fiat price that was quoted for the invoice
+	// when it was created.
+	FiatQuote *FiatQuote
 }
 
 // HTLCSet returns the set of HTLCs belonging to setID and in the provided
@@ -796,7 +959,22 @@ func ValidateInvoice(i *Invoice, paymentHash lntypes.Hash) error {
 		return ErrInvoiceHasHtlcs
 	}
 
-	return nil
+	if i.Terms.PartialPayment.IsEnabled() && i.IsAMP() {
+		return errors.New("AMP invoices don't support partial payments")
+	}
+
+	err = i.Terms.HtlcConstraints.Validate(i.Terms.Value)
+	if err != nil {
+		return err
+	}
+
+	if i.FiatQuote != nil {
+		if err := i.FiatQuote.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return i.Terms.PartialPayment.Validate(i.Terms.Value)
 }
 
 // requiresPreimage returns true if the invoice requires a preimage to be valid.
@@ -842,12 +1020,18 @@ func CopyInvoice(src *Invoice) (*Invoice, error) {
 	}
 
 	dest.Terms.Features = src.Terms.Features.Clone()
+	dest.Terms.HtlcConstraints = src.Terms.HtlcConstraints.Copy()
 
 	if src.Terms.PaymentPreimage != nil {
 		preimage := *src.Terms.PaymentPreimage
 		dest.Terms.PaymentPreimage = &preimage
 	}
 
+	if src.FiatQuote != nil {
+		fiatQuote := *src.FiatQuote
+		dest.FiatQuote = &fiatQuote
+	}
+
 	for k, v := range src.Htlcs {
 		dest.Htlcs[k] = v.Copy()
 	}
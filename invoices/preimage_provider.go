@@ -0,0 +1,129 @@
+package invoices
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+)
+
+const (
+	// DefaultPreimageProviderTimeout is the default maximum time we wait
+	// for the preimage provider to release the preimage of a hold invoice.
+	DefaultPreimageProviderTimeout = 10 * time.Minute
+)
+
+// ErrPreimageProviderMismatch is returned when the preimage provider releases
+// a preimage that doesn't match the payment hash of the hold invoice.
+var ErrPreimageProviderMismatch = errors.New("released preimage doesn't " +
+	"match payment hash")
+
+// maybeRequestPreimage hands the accepted hold invoice to the preimage
+// provider, if one is configured. Invoices that already have a preimage are
+// settled by the registry itself and are skipped.
+func (i *InvoiceRegistry) maybeRequestPreimage(hash lntypes.Hash,
+	invoice *Invoice) {
+
+	if i.cfg.PreimageProvider == nil || !invoice.HodlInvoice ||
+		invoice.Terms.PaymentPreimage != nil || invoice.IsAMP() {
+
+		return
+	}
+
+	var htlcExpiry uint32
+	for _, htlc := range invoice.Htlcs {
+		if htlc.State != HtlcStateAccepted {
+			continue
+		}
+
+		if htlcExpiry == 0 || htlc.Expiry < htlcExpiry {
+			htlcExpiry = htlc.Expiry
+		}
+	}
+
+	invoiceCopy, err := CopyInvoice(invoice)
+	if err != nil {
+		log.Errorf("Unable to copy hold invoice %v for preimage "+
+			"request: %v", hash, err)
+
+		return
+	}
+
+	req := PreimageRequest{
+		PaymentHash: hash,
+		HtlcExpiry:  htlcExpiry,
+		Invoice:     *invoiceCopy,
+	}
+
+	i.wg.Add(1)
+	go i.requestPreimage(req)
+}
+
+// requestPreimage asks the preimage provider for the preimage of the accepted
+// hold invoice and settles the invoice with it. If the provider fails or
+// doesn't release the preimage in time, the invoice is canceled so that the
+// htlcs don't linger until the expiry watcher cancels them.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (i *InvoiceRegistry) requestPreimage(req PreimageRequest) {
+	defer i.wg.Done()
+
+	timeout := i.cfg.PreimageProviderTimeout
+	if timeout == 0 {
+		timeout = DefaultPreimageProviderTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Abort the request if we're shutting down. The invoice stays accepted
+	// in that case, and is handed to the provider again on startup.
+	go func() {
+		select {
+		case <-i.quit:
+			cancel()
+
+		case <-ctx.Done():
+		}
+	}()
+
+	log.Debugf("Requesting preimage for hold invoice %v", req.PaymentHash)
+
+	preimage, err := i.cfg.PreimageProvider.RequestPreimage(ctx, req)
+
+	select {
+	case <-i.quit:
+		return
+
+	default:
+	}
+
+	if err == nil && preimage.Hash() != req.PaymentHash {
+		err = ErrPreimageProviderMismatch
+	}
+
+	if err == nil {
+		err = i.SettleHodlInvoice(context.Background(), preimage)
+		if err != nil {
+			log.Errorf("Unable to settle hold invoice %v with "+
+				"released preimage: %v", req.PaymentHash, err)
+		}
+
+		return
+	}
+
+	log.Warnf("Preimage provider failed for hold invoice %v, canceling "+
+		"invoice: %v", req.PaymentHash, err)
+
+	err = i.CancelInvoice(context.Background(), req.PaymentHash)
+	switch {
+	// The invoice may have been settled in the meantime.
+	case errors.Is(err, ErrInvoiceAlreadySettled):
+		log.Debugf("Hold invoice %v already settled", req.PaymentHash)
+
+	case err != nil:
+		log.Errorf("Unable to cancel hold invoice %v: %v",
+			req.PaymentHash, err)
+	}
+}
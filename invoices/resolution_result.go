@@ -120,6 +120,14 @@ const (
 	// ResultAmpReconstruction is returned when the derived child
 	// hash/preimage pairs were invalid for at least one HTLC in the set.
 	ResultAmpReconstruction
+
+	// ResultStatelessError is returned when we receive an htlc for a
+	// stateless payment address with invalid parameters.
+	ResultStatelessError
+
+	// ResultHtlcConstraintViolation is returned when an htlc violates the
+	// htlc constraints of the invoice it pays to.
+	ResultHtlcConstraintViolation
 )
 
 // String returns a string representation of the result.
@@ -189,6 +197,12 @@ func (f FailResolutionResult) FailureString() string {
 	case ResultAmpReconstruction:
 		return "amp reconstruction failed"
 
+	case ResultStatelessError:
+		return "invalid stateless invoice parameters"
+
+	case ResultHtlcConstraintViolation:
+		return "htlc violates invoice htlc constraints"
+
 	default:
 		return "unknown failure resolution result"
 	}
@@ -228,6 +242,10 @@ const (
 	// ResultDuplicateToSettled is returned when we settle an invoice which
 	// has already been settled at least once.
 	ResultDuplicateToSettled
+
+	// ResultSettledPartial is returned when we settle an invoice for less
+	// than its value, as allowed by its partial payment policy.
+	ResultSettledPartial
 )
 
 // String returns a string representation of the result.
@@ -245,6 +263,9 @@ func (s SettleResolutionResult) String() string {
 	case ResultDuplicateToSettled:
 		return "accepting duplicate payment to settled invoice"
 
+	case ResultSettledPartial:
+		return "settled partial payment"
+
 	default:
 		return "unknown settle resolution result"
 	}
@@ -16,6 +16,7 @@ import (
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/sqldb"
 	"github.com/lightningnetwork/lnd/sqldb/sqlc"
 )
@@ -41,6 +42,21 @@ type SQLInvoiceQueries interface { //nolint:interfacebloat
 	InsertInvoiceHTLCCustomRecord(ctx context.Context,
 		arg sqlc.InsertInvoiceHTLCCustomRecordParams) error
 
+	InsertInvoicePartialPaymentPolicy(ctx context.Context,
+		arg sqlc.InsertInvoicePartialPaymentPolicyParams) error
+
+	InsertInvoiceHTLCConstraints(ctx context.Context,
+		arg sqlc.InsertInvoiceHTLCConstraintsParams) error
+
+	InsertInvoiceHTLCAllowedChannel(ctx context.Context,
+		arg sqlc.InsertInvoiceHTLCAllowedChannelParams) error
+
+	InsertInvoiceHTLCAllowedPeer(ctx context.Context,
+		arg sqlc.InsertInvoiceHTLCAllowedPeerParams) error
+
+	InsertInvoiceFiatQuote(ctx context.Context,
+		arg sqlc.InsertInvoiceFiatQuoteParams) error
+
 	FilterInvoices(ctx context.Context,
 		arg sqlc.FilterInvoicesParams) ([]sqlc.Invoice, error)
 
@@ -59,6 +75,21 @@ type SQLInvoiceQueries interface { //nolint:interfacebloat
 	GetInvoiceHTLCs(ctx context.Context,
 		invoiceID int64) ([]sqlc.InvoiceHtlc, error)
 
+	GetInvoicePartialPaymentPolicy(ctx context.Context,
+		invoiceID int64) (sqlc.InvoicePartialPaymentPolicy, error)
+
+	GetInvoiceHTLCConstraints(ctx context.Context,
+		invoiceID int64) (sqlc.InvoiceHtlcConstraint, error)
+
+	GetInvoiceHTLCAllowedChannels(ctx context.Context,
+		invoiceID int64) ([]string, error)
+
+	GetInvoiceHTLCAllowedPeers(ctx context.Context,
+		invoiceID int64) ([][]byte, error)
+
+	GetInvoiceFiatQuote(ctx context.Context,
+		invoiceID int64) (sqlc.InvoiceFiatQuote, error)
+
 	UpdateInvoiceState(ctx context.Context,
 		arg sqlc.UpdateInvoiceStateParams) (sql.Result, error)
 
@@ -289,6 +320,51 @@ func (i *SQLStore) AddInvoice(ctx context.Context,
 			}
 		}
 
+		// Store the partial payment policy if the invoice accepts
+		// partial payments.
+		policy := newInvoice.Terms.PartialPayment
+		if policy.IsEnabled() {
+			params := sqlc.InsertInvoicePartialPaymentPolicyParams{
+				InvoiceID:           invoiceID,
+				ToleranceMsat:       int64(policy.Tolerance),
+				MinSettleAmountMsat: int64(policy.MinSettleAmt),
+			}
+
+			err := db.InsertInvoicePartialPaymentPolicy(ctx, params)
+			if err != nil {
+				return fmt.Errorf("unable to insert partial "+
+					"payment policy: %w", err)
+			}
+		}
+
+		// Store the htlc constraints if the invoice has any.
+		constraints := newInvoice.Terms.HtlcConstraints
+		if constraints.IsEnabled() {
+			err := insertHtlcConstraints(
+				ctx, db, invoiceID, constraints,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Store the fiat quote if one was made for the invoice.
+		if quote := newInvoice.FiatQuote; quote != nil {
+			params := sqlc.InsertInvoiceFiatQuoteParams{
+				InvoiceID:  invoiceID,
+				Currency:   quote.Currency,
+				Amount:     int64(quote.Amount),
+				RateSource: quote.RateSource,
+				QuotedAt:   quote.QuoteTime.UTC(),
+			}
+
+			err := db.InsertInvoiceFiatQuote(ctx, params)
+			if err != nil {
+				return fmt.Errorf("unable to insert fiat "+
+					"quote: %w", err)
+			}
+		}
+
 		// Finally add a new event for this invoice.
 		return db.OnInvoiceCreated(ctx, sqlc.OnInvoiceCreatedParams{
 			AddedAt:   newInvoice.CreationDate.UTC(),
@@ -886,6 +962,10 @@ func (i *SQLStore) QueryInvoices(ctx context.Context,
 			"be non-zero")
 	}
 
+	if q.NumMaxInvoices > MaxQueryInvoices {
+		q.NumMaxInvoices = MaxQueryInvoices
+	}
+
 	readTxOpt := NewSQLInvoiceQueryReadTx()
 	err := i.db.ExecTx(ctx, &readTxOpt, func(db SQLInvoiceQueries) error {
 		return queryWithLimit(func(offset int) (int, error) {
@@ -1481,6 +1561,30 @@ func fetchInvoiceData(ctx context.Context, db SQLInvoiceQueries,
 
 	invoice.Terms.Features = features
 
+	// Fetch the partial payment policy.
+	policy, err := getInvoicePartialPaymentPolicy(ctx, db, row.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invoice.Terms.PartialPayment = policy
+
+	// Fetch the htlc constraints.
+	constraints, err := getInvoiceHtlcConstraints(ctx, db, row.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invoice.Terms.HtlcConstraints = constraints
+
+	// Fetch the fiat quote.
+	quote, err := getInvoiceFiatQuote(ctx, db, row.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	invoice.FiatQuote = quote
+
 	// If this is an AMP invoice, we'll need fetch the AMP state along
 	// with the HTLCs (if requested).
 	if invoice.IsAMP() {
@@ -1536,6 +1640,158 @@ func getInvoiceFeatures(ctx context.Context, db SQLInvoiceQueries,
 	return features, nil
 }
 
+// getInvoicePartialPaymentPolicy fetches the partial payment policy for the
+// given invoice id. Invoices that don't accept partial payments have no policy
+// stored, for which the disabled zero policy is returned.
+func getInvoicePartialPaymentPolicy(ctx context.Context, db SQLInvoiceQueries,
+	invoiceID int64) (PartialPaymentPolicy, error) {
+
+	row, err := db.GetInvoicePartialPaymentPolicy(ctx, invoiceID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return PartialPaymentPolicy{}, nil
+
+	case err != nil:
+		return PartialPaymentPolicy{}, fmt.Errorf("unable to get "+
+			"partial payment policy: %w", err)
+	}
+
+	return PartialPaymentPolicy{
+		Tolerance:    lnwire.MilliSatoshi(row.ToleranceMsat),
+		MinSettleAmt: lnwire.MilliSatoshi(row.MinSettleAmountMsat),
+	}, nil
+}
+
+// insertHtlcConstraints stores the htlc constraints of the invoice with the
+// given id.
+func insertHtlcConstraints(ctx context.Context, db SQLInvoiceQueries,
+	invoiceID int64, constraints HtlcConstraints) error {
+
+	err := db.InsertInvoiceHTLCConstraints(
+		ctx, sqlc.InsertInvoiceHTLCConstraintsParams{
+			InvoiceID:         invoiceID,
+			MaxHtlcs:          int32(constraints.MaxHtlcs),
+			MinHtlcAmountMsat: int64(constraints.MinHtlcAmt),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("unable to insert htlc constraints: %w", err)
+	}
+
+	for _, chanID := range constraints.AllowedChannels {
+		err := db.InsertInvoiceHTLCAllowedChannel(
+			ctx, sqlc.InsertInvoiceHTLCAllowedChannelParams{
+				InvoiceID: invoiceID,
+				ChanID: strconv.FormatUint(
+					chanID.ToUint64(), 10,
+				),
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert allowed "+
+				"channel(%v): %w", chanID, err)
+		}
+	}
+
+	for _, peer := range constraints.AllowedPeers {
+		err := db.InsertInvoiceHTLCAllowedPeer(
+			ctx, sqlc.InsertInvoiceHTLCAllowedPeerParams{
+				InvoiceID: invoiceID,
+				PubKey:    peer[:],
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("unable to insert allowed "+
+				"peer(%v): %w", peer, err)
+		}
+	}
+
+	return nil
+}
+
+// getInvoiceHtlcConstraints fetches the htlc constraints for the given invoice
+// id. Invoices without constraints have none stored, for which the empty
+// constraints are returned.
+func getInvoiceHtlcConstraints(ctx context.Context, db SQLInvoiceQueries,
+	invoiceID int64) (HtlcConstraints, error) {
+
+	row, err := db.GetInvoiceHTLCConstraints(ctx, invoiceID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return HtlcConstraints{}, nil
+
+	case err != nil:
+		return HtlcConstraints{}, fmt.Errorf("unable to get htlc "+
+			"constraints: %w", err)
+	}
+
+	constraints := HtlcConstraints{
+		MaxHtlcs:   uint32(row.MaxHtlcs),
+		MinHtlcAmt: lnwire.MilliSatoshi(row.MinHtlcAmountMsat),
+	}
+
+	chanIDs, err := db.GetInvoiceHTLCAllowedChannels(ctx, invoiceID)
+	if err != nil {
+		return HtlcConstraints{}, fmt.Errorf("unable to get allowed "+
+			"channels: %w", err)
+	}
+
+	for _, chanID := range chanIDs {
+		scid, err := strconv.ParseUint(chanID, 10, 64)
+		if err != nil {
+			return HtlcConstraints{}, fmt.Errorf("invalid allowed "+
+				"channel %v: %w", chanID, err)
+		}
+
+		constraints.AllowedChannels = append(
+			constraints.AllowedChannels,
+			lnwire.NewShortChanIDFromInt(scid),
+		)
+	}
+
+	peers, err := db.GetInvoiceHTLCAllowedPeers(ctx, invoiceID)
+	if err != nil {
+		return HtlcConstraints{}, fmt.Errorf("unable to get allowed "+
+			"peers: %w", err)
+	}
+
+	for _, pubKey := range peers {
+		peer, err := route.NewVertexFromBytes(pubKey)
+		if err != nil {
+			return HtlcConstraints{}, fmt.Errorf("invalid allowed "+
+				"peer: %w", err)
+		}
+
+		constraints.AllowedPeers = append(
+			constraints.AllowedPeers, peer,
+		)
+	}
+
+	return constraints, nil
+}
+
+// getInvoiceFiatQuote fetches the fiat quote for the given invoice id, or nil
+// if no quote was made for the invoice.
+func getInvoiceFiatQuote(ctx context.Context, db SQLInvoiceQueries,
+	invoiceID int64) (*FiatQuote, error) {
+
+	row, err := db.GetInvoiceFiatQuote(ctx, invoiceID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+
+	case err != nil:
+		return nil, fmt.Errorf("unable to get fiat quote: %w", err)
+	}
+
+	return &FiatQuote{
+		Currency:   row.Currency,
+		Amount:     uint64(row.Amount),
+		RateSource: row.RateSource,
+		QuoteTime:  row.QuotedAt.Local(),
+	}, nil
+}
+
 // getInvoiceHtlcs fetches the invoice htlcs for the given invoice id.
 func getInvoiceHtlcs(ctx context.Context, db SQLInvoiceQueries,
 	invoiceID int64) (map[CircuitKey]*InvoiceHTLC, error) {
@@ -0,0 +1,124 @@
+package invoices
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// StatelessNonceSize is the size of the random nonce that makes up the
+	// first half of a stateless payment address.
+	StatelessNonceSize = 16
+)
+
+var (
+	// StatelessKeyLocator is the locator of the key the stateless invoice
+	// secret is derived from. Using the BIP43-like derivation of the
+	// keychain package, this results in the path m/1017'/coinType'/7'/0/1.
+	// The secret is the sha256 of the compressed public key at that path,
+	// which allows external issuance services that know the seed to derive
+	// the same secret.
+	StatelessKeyLocator = keychain.KeyLocator{
+		Family: keychain.KeyFamilyBaseEncryption,
+		Index:  1,
+	}
+
+	// statelessAddrTag is the domain separation tag used when computing
+	// the authenticated half of a stateless payment address.
+	statelessAddrTag = []byte("lnd-stateless-payaddr")
+
+	// statelessPreimageTag is the domain separation tag used when deriving
+	// the preimage of a stateless invoice.
+	statelessPreimageTag = []byte("lnd-stateless-preimage")
+
+	// ErrStatelessPreimageMismatch is returned when a stateless payment
+	// address is paid with a payment hash that doesn't match the preimage
+	// derived from it.
+	ErrStatelessPreimageMismatch = errors.New("payment hash doesn't " +
+		"match stateless preimage")
+)
+
+// StatelessSecret is the secret that stateless invoices are issued with. It
+// allows us to settle payments to invoices that were never stored, because
+// both the validity of the payment address and the preimage can be derived
+// from it.
+//
+// A stateless payment address is made up of a random 16 byte nonce followed
+// by the first 16 bytes of
+//
+//	HMAC-SHA256(secret, "lnd-stateless-payaddr" || nonce || amt_msat)
+//
+// with amt_msat encoded as a big endian uint64. The preimage of the invoice
+// is
+//
+//	HMAC-SHA256(secret, "lnd-stateless-preimage" || payment_addr).
+type StatelessSecret [32]byte
+
+// DeriveStatelessSecret derives the stateless invoice secret from the key
+// ring using the StatelessKeyLocator.
+func DeriveStatelessSecret(keyRing keychain.KeyRing) (StatelessSecret,
+	error) {
+
+	keyDesc, err := keyRing.DeriveKey(StatelessKeyLocator)
+	if err != nil {
+		return StatelessSecret{}, err
+	}
+
+	return sha256.Sum256(keyDesc.PubKey.SerializeCompressed()), nil
+}
+
+// addrTag computes the authenticated half of a stateless payment address.
+func (s StatelessSecret) addrTag(nonce []byte,
+	amt lnwire.MilliSatoshi) []byte {
+
+	var amtBytes [8]byte
+	binary.BigEndian.PutUint64(amtBytes[:], uint64(amt))
+
+	mac := hmac.New(sha256.New, s[:])
+	_, _ = mac.Write(statelessAddrTag)
+	_, _ = mac.Write(nonce)
+	_, _ = mac.Write(amtBytes[:])
+
+	return mac.Sum(nil)[:32-StatelessNonceSize]
+}
+
+// PaymentAddr returns the stateless payment address for an invoice of the
+// given amount, using the passed random nonce.
+func (s StatelessSecret) PaymentAddr(nonce [StatelessNonceSize]byte,
+	amt lnwire.MilliSatoshi) [32]byte {
+
+	var payAddr [32]byte
+	copy(payAddr[:], nonce[:])
+	copy(payAddr[StatelessNonceSize:], s.addrTag(nonce[:], amt))
+
+	return payAddr
+}
+
+// VerifyPaymentAddr returns true if the payment address was issued with this
+// secret for an invoice of the given amount.
+func (s StatelessSecret) VerifyPaymentAddr(payAddr [32]byte,
+	amt lnwire.MilliSatoshi) bool {
+
+	tag := s.addrTag(payAddr[:StatelessNonceSize], amt)
+
+	return hmac.Equal(tag, payAddr[StatelessNonceSize:])
+}
+
+// Preimage returns the preimage of the stateless invoice with the given
+// payment address.
+func (s StatelessSecret) Preimage(payAddr [32]byte) lntypes.Preimage {
+	mac := hmac.New(sha256.New, s[:])
+	_, _ = mac.Write(statelessPreimageTag)
+	_, _ = mac.Write(payAddr[:])
+
+	var preimage lntypes.Preimage
+	copy(preimage[:], mac.Sum(nil))
+
+	return preimage
+}
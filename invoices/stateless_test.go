@@ -0,0 +1,40 @@
+package invoices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestStatelessSecret tests that stateless payment addresses can only be
+// verified with the secret and amount they were issued with, and that the
+// preimage derivation is deterministic.
+func TestStatelessSecret(t *testing.T) {
+	t.Parallel()
+
+	secret := StatelessSecret{1}
+	nonce := [StatelessNonceSize]byte{2}
+
+	payAddr := secret.PaymentAddr(nonce, 1000)
+	require.Equal(t, nonce[:], payAddr[:StatelessNonceSize])
+	require.True(t, secret.VerifyPaymentAddr(payAddr, 1000))
+
+	// The payment address commits to the amount.
+	require.False(t, secret.VerifyPaymentAddr(payAddr, 1001))
+
+	// A different secret can't verify the payment address.
+	otherSecret := StatelessSecret{3}
+	require.False(t, otherSecret.VerifyPaymentAddr(payAddr, 1000))
+
+	// Tampering with the nonce invalidates the payment address.
+	tampered := payAddr
+	tampered[0] ^= 1
+	require.False(t, secret.VerifyPaymentAddr(tampered, 1000))
+
+	// The preimage only depends on the secret and the payment address.
+	require.Equal(t, secret.Preimage(payAddr), secret.Preimage(payAddr))
+	require.NotEqual(t, secret.Preimage(payAddr), secret.Preimage(tampered))
+	require.NotEqual(
+		t, secret.Preimage(payAddr), otherSecret.Preimage(payAddr),
+	)
+}
@@ -0,0 +1,581 @@
+package invoices
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// MinSubscriptionPeriod is the shortest period a subscription may
+	// have.
+	MinSubscriptionPeriod = time.Minute
+
+	// DefaultSubscriptionPollInterval is the default interval at which
+	// the subscription manager checks whether new invoices are due.
+	DefaultSubscriptionPollInterval = 30 * time.Second
+)
+
+// SubscriptionID uniquely identifies a subscription.
+type SubscriptionID [32]byte
+
+// String returns the hex encoding of the subscription id.
+func (s SubscriptionID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SubscriptionTemplate describes the invoice that is generated for each
+// period of a subscription.
+type SubscriptionTemplate struct {
+	// Memo is the memo of the generated invoices.
+	Memo string
+
+	// Value is the amount that is due each period.
+	Value lnwire.MilliSatoshi
+
+	// Period is the duration of a single period.
+	Period time.Duration
+
+	// StartTime is the start of the first period.
+	StartTime time.Time
+
+	// NumPeriods is the number of periods after which the subscription
+	// completes. A value of zero means that the subscription continues
+	// until it is canceled.
+	NumPeriods uint32
+
+	// Expiry is the expiry of the generated invoices. If zero, the
+	// invoices expire at the end of their period.
+	Expiry time.Duration
+
+	// CltvExpiry is the final cltv delta of the generated invoices. If
+	// zero, the default of the node is used.
+	CltvExpiry uint64
+
+	// Private indicates whether the generated invoices include route hints
+	// for private channels.
+	Private bool
+}
+
+// Validate checks that the template describes a valid subscription.
+func (t *SubscriptionTemplate) Validate() error {
+	if t.Period < MinSubscriptionPeriod {
+		return fmt.Errorf("%w: %v is less than %v",
+			ErrInvalidSubscriptionPeriod, t.Period,
+			MinSubscriptionPeriod)
+	}
+
+	if t.Value == 0 {
+		return ErrInvalidSubscriptionValue
+	}
+
+	return nil
+}
+
+// PeriodStart returns the start of the period with the given index.
+func (t *SubscriptionTemplate) PeriodStart(period uint32) time.Time {
+	return t.StartTime.Add(time.Duration(period) * t.Period)
+}
+
+// InvoiceExpiry returns the expiry of the generated invoices.
+func (t *SubscriptionTemplate) InvoiceExpiry() time.Duration {
+	if t.Expiry == 0 {
+		return t.Period
+	}
+
+	return t.Expiry
+}
+
+// currentPeriod returns the index of the period that contains the given time,
+// and false if the subscription hasn't started yet.
+func (t *SubscriptionTemplate) currentPeriod(now time.Time) (uint32, bool) {
+	if now.Before(t.StartTime) {
+		return 0, false
+	}
+
+	return uint32(now.Sub(t.StartTime) / t.Period), true
+}
+
+// SubscriptionState describes the state a subscription is in.
+type SubscriptionState uint8
+
+const (
+	// SubscriptionActive means that invoices are generated for the
+	// subscription.
+	SubscriptionActive SubscriptionState = 0
+
+	// SubscriptionCanceled means that the subscription was canceled
+	// before its last period.
+	SubscriptionCanceled SubscriptionState = 1
+
+	// SubscriptionCompleted means that the invoices of all periods of the
+	// subscription have been generated.
+	SubscriptionCompleted SubscriptionState = 2
+)
+
+// String returns a human readable identifier for the subscription state.
+func (s SubscriptionState) String() string {
+	switch s {
+	case SubscriptionActive:
+		return "Active"
+
+	case SubscriptionCanceled:
+		return "Canceled"
+
+	case SubscriptionCompleted:
+		return "Completed"
+	}
+
+	return "Unknown"
+}
+
+// SubscriptionInvoice links the invoice of a period to its subscription.
+type SubscriptionInvoice struct {
+	// Period is the index of the period the invoice was generated for.
+	Period uint32
+
+	// PaymentHash is the payment hash of the invoice.
+	PaymentHash lntypes.Hash
+}
+
+// Subscription is an invoice template that generates a new invoice for each
+// period.
+type Subscription struct {
+	// ID uniquely identifies the subscription.
+	ID SubscriptionID
+
+	// Secret is used to derive the preimages of the generated invoices.
+	// Deriving them deterministically ensures that an invoice isn't
+	// generated twice for the same period.
+	Secret [32]byte
+
+	// Template describes the generated invoices.
+	Template SubscriptionTemplate
+
+	// State is the state of the subscription.
+	State SubscriptionState
+
+	// Invoices holds the generated invoices, ordered by their period.
+	// Periods that passed while we were offline are skipped.
+	Invoices []SubscriptionInvoice
+}
+
+// Copy returns a deep copy of the subscription.
+func (s *Subscription) Copy() *Subscription {
+	c := *s
+	c.Invoices = append([]SubscriptionInvoice(nil), s.Invoices...)
+
+	return &c
+}
+
+// preimage derives the preimage of the invoice of the given period.
+func (s *Subscription) preimage(period uint32) lntypes.Preimage {
+	var periodBytes [4]byte
+	binary.BigEndian.PutUint32(periodBytes[:], period)
+
+	h := sha256.New()
+	h.Write(s.Secret[:])
+	h.Write(periodBytes[:])
+
+	var preimage lntypes.Preimage
+	copy(preimage[:], h.Sum(nil))
+
+	return preimage
+}
+
+// lastPeriod returns the period of the most recently generated invoice, and
+// false if no invoice was generated yet.
+func (s *Subscription) lastPeriod() (uint32, bool) {
+	if len(s.Invoices) == 0 {
+		return 0, false
+	}
+
+	return s.Invoices[len(s.Invoices)-1].Period, true
+}
+
+// SubscriptionPeriod is the payment status of a single period of a
+// subscription.
+type SubscriptionPeriod struct {
+	// Period is the index of the period.
+	Period uint32
+
+	// Start is the start of the period.
+	Start time.Time
+
+	// PaymentHash is the payment hash of the invoice of the period.
+	PaymentHash lntypes.Hash
+
+	// State is the state of the invoice of the period.
+	State ContractState
+
+	// AmtPaid is the amount paid to the invoice of the period.
+	AmtPaid lnwire.MilliSatoshi
+}
+
+// SubscriptionStatus is a subscription along with the payment status of each
+// period an invoice was generated for.
+type SubscriptionStatus struct {
+	*Subscription
+
+	// Periods holds the payment status of each generated invoice.
+	Periods []SubscriptionPeriod
+}
+
+// SubscriptionStore persists subscriptions.
+type SubscriptionStore interface {
+	// PutSubscription adds the subscription to the store, or updates it
+	// if it exists already.
+	PutSubscription(sub *Subscription) error
+
+	// FetchSubscriptions returns all stored subscriptions.
+	FetchSubscriptions() ([]*Subscription, error)
+}
+
+// SubscriptionManagerConfig houses the dependencies of the subscription
+// manager.
+type SubscriptionManagerConfig struct {
+	// Store persists the subscriptions.
+	Store SubscriptionStore
+
+	// CreateInvoice creates the invoice of the given period of a
+	// subscription, using the given preimage. It returns
+	// ErrDuplicateInvoice if the invoice exists already.
+	CreateInvoice func(ctx context.Context, tmpl *SubscriptionTemplate,
+		period uint32, preimage lntypes.Preimage) error
+
+	// LookupInvoice looks up the invoice with the given payment hash.
+	LookupInvoice func(ctx context.Context,
+		hash lntypes.Hash) (Invoice, error)
+
+	// Clock is the time source of the manager.
+	Clock clock.Clock
+
+	// PollTicker determines how often the manager checks whether new
+	// invoices are due.
+	PollTicker ticker.Ticker
+}
+
+// SubscriptionManager generates the invoices of recurring payments. Merchants
+// create a subscription from an invoice template, and the manager generates a
+// new invoice at the start of each period, so that no external scheduler is
+// needed.
+type SubscriptionManager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *SubscriptionManagerConfig
+
+	// mu guards subscriptions and serializes the invoice generation.
+	mu sync.Mutex
+
+	// subscriptions holds all known subscriptions.
+	subscriptions map[SubscriptionID]*Subscription
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewSubscriptionManager creates a new subscription manager.
+func NewSubscriptionManager(
+	cfg *SubscriptionManagerConfig) *SubscriptionManager {
+
+	return &SubscriptionManager{
+		cfg:           cfg,
+		subscriptions: make(map[SubscriptionID]*Subscription),
+		quit:          make(chan struct{}),
+	}
+}
+
+// Start loads the stored subscriptions, generates any invoices that became
+// due while we were offline and starts generating new invoices as they become
+// due.
+func (m *SubscriptionManager) Start() error {
+	var startErr error
+	m.started.Do(func() {
+		log.Info("SubscriptionManager starting...")
+
+		subs, err := m.cfg.Store.FetchSubscriptions()
+		if err != nil {
+			startErr = fmt.Errorf("unable to fetch subscriptions: "+
+				"%w", err)
+			return
+		}
+
+		m.mu.Lock()
+		for _, sub := range subs {
+			m.subscriptions[sub.ID] = sub
+		}
+		m.mu.Unlock()
+
+		m.generateDueInvoices()
+
+		m.cfg.PollTicker.Resume()
+
+		m.wg.Add(1)
+		go m.pollLoop()
+	})
+
+	return startErr
+}
+
+// Stop stops generating invoices.
+func (m *SubscriptionManager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("SubscriptionManager shutting down...")
+		defer log.Debug("SubscriptionManager shutdown complete")
+
+		close(m.quit)
+		m.wg.Wait()
+
+		m.cfg.PollTicker.Stop()
+	})
+
+	return nil
+}
+
+// pollLoop periodically generates the invoices that became due.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *SubscriptionManager) pollLoop() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case <-m.cfg.PollTicker.Ticks():
+			m.generateDueInvoices()
+
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// generateDueInvoices generates the due invoices of all active subscriptions.
+func (m *SubscriptionManager) generateDueInvoices() {
+	ctx := context.Background()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, sub := range m.subscriptions {
+		if sub.State != SubscriptionActive {
+			continue
+		}
+
+		if err := m.generateDueInvoice(ctx, sub); err != nil {
+			log.Errorf("Unable to generate invoice for "+
+				"subscription %v: %v", sub.ID, err)
+		}
+	}
+}
+
+// generateDueInvoice generates the invoice of the current period of the
+// subscription, unless it exists already. Invoices of periods that passed
+// while we were offline are not generated, since they'd be expired already.
+// The caller must hold the mutex.
+func (m *SubscriptionManager) generateDueInvoice(ctx context.Context,
+	sub *Subscription) error {
+
+	tmpl := &sub.Template
+	period, started := tmpl.currentPeriod(m.cfg.Clock.Now())
+	if !started {
+		return nil
+	}
+
+	// Once the last period has passed, the subscription is completed.
+	if tmpl.NumPeriods > 0 && period >= tmpl.NumPeriods {
+		updated := sub.Copy()
+		updated.State = SubscriptionCompleted
+		if err := m.cfg.Store.PutSubscription(updated); err != nil {
+			return err
+		}
+
+		log.Infof("Subscription %v completed", sub.ID)
+		*sub = *updated
+
+		return nil
+	}
+
+	if last, ok := sub.lastPeriod(); ok && last >= period {
+		return nil
+	}
+
+	// Since the preimage is derived from the period, creating the invoice
+	// is idempotent. This ensures we don't create a second invoice if we
+	// failed to persist the subscription after creating the invoice.
+	preimage := sub.preimage(period)
+	err := m.cfg.CreateInvoice(ctx, tmpl, period, preimage)
+	if err != nil && !errors.Is(err, ErrDuplicateInvoice) {
+		return err
+	}
+
+	updated := sub.Copy()
+	updated.Invoices = append(updated.Invoices, SubscriptionInvoice{
+		Period:      period,
+		PaymentHash: preimage.Hash(),
+	})
+	if err := m.cfg.Store.PutSubscription(updated); err != nil {
+		return err
+	}
+
+	log.Debugf("Generated invoice %v for period %d of subscription %v",
+		preimage.Hash(), period, sub.ID)
+	*sub = *updated
+
+	return nil
+}
+
+// AddSubscription creates a new subscription from the given template. If the
+// first period already started, its invoice is generated right away. If the
+// template has no start time, the subscription starts now.
+func (m *SubscriptionManager) AddSubscription(ctx context.Context,
+	tmpl SubscriptionTemplate) (*Subscription, error) {
+
+	if err := tmpl.Validate(); err != nil {
+		return nil, err
+	}
+
+	if tmpl.StartTime.IsZero() {
+		tmpl.StartTime = m.cfg.Clock.Now()
+	}
+
+	sub := &Subscription{
+		Template: tmpl,
+		State:    SubscriptionActive,
+	}
+	if _, err := rand.Read(sub.ID[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(sub.Secret[:]); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.cfg.Store.PutSubscription(sub); err != nil {
+		return nil, err
+	}
+	m.subscriptions[sub.ID] = sub
+
+	log.Infof("Added subscription %v with period %v", sub.ID,
+		tmpl.Period)
+
+	if err := m.generateDueInvoice(ctx, sub); err != nil {
+		return nil, err
+	}
+
+	return sub.Copy(), nil
+}
+
+// CancelSubscription stops generating invoices for the given subscription.
+// Invoices that were generated already are not canceled.
+func (m *SubscriptionManager) CancelSubscription(id SubscriptionID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sub, ok := m.subscriptions[id]
+	if !ok {
+		return ErrSubscriptionNotFound
+	}
+
+	if sub.State != SubscriptionActive {
+		return ErrSubscriptionNotActive
+	}
+
+	updated := sub.Copy()
+	updated.State = SubscriptionCanceled
+	if err := m.cfg.Store.PutSubscription(updated); err != nil {
+		return err
+	}
+	*sub = *updated
+
+	log.Infof("Canceled subscription %v", id)
+
+	return nil
+}
+
+// SubscriptionStatus returns the given subscription along with the payment
+// status of each of its periods.
+func (m *SubscriptionManager) SubscriptionStatus(ctx context.Context,
+	id SubscriptionID) (*SubscriptionStatus, error) {
+
+	m.mu.Lock()
+	sub, ok := m.subscriptions[id]
+	if ok {
+		sub = sub.Copy()
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	return m.status(ctx, sub)
+}
+
+// Subscriptions returns all subscriptions along with the payment status of
+// each of their periods.
+func (m *SubscriptionManager) Subscriptions(
+	ctx context.Context) ([]*SubscriptionStatus, error) {
+
+	m.mu.Lock()
+	subs := make([]*Subscription, 0, len(m.subscriptions))
+	for _, sub := range m.subscriptions {
+		subs = append(subs, sub.Copy())
+	}
+	m.mu.Unlock()
+
+	statuses := make([]*SubscriptionStatus, 0, len(subs))
+	for _, sub := range subs {
+		status, err := m.status(ctx, sub)
+		if err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// status looks up the invoices of the given subscription to determine the
+// payment status of its periods.
+func (m *SubscriptionManager) status(ctx context.Context,
+	sub *Subscription) (*SubscriptionStatus, error) {
+
+	status := &SubscriptionStatus{
+		Subscription: sub,
+		Periods:      make([]SubscriptionPeriod, 0, len(sub.Invoices)),
+	}
+
+	for _, inv := range sub.Invoices {
+		invoice, err := m.cfg.LookupInvoice(ctx, inv.PaymentHash)
+		if err != nil {
+			return nil, fmt.Errorf("unable to look up invoice %v "+
+				"of period %d: %w", inv.PaymentHash,
+				inv.Period, err)
+		}
+
+		status.Periods = append(status.Periods, SubscriptionPeriod{
+			Period:      inv.Period,
+			Start:       sub.Template.PeriodStart(inv.Period),
+			PaymentHash: inv.PaymentHash,
+			State:       invoice.State,
+			AmtPaid:     invoice.AmtPaid,
+		})
+	}
+
+	return status, nil
+}
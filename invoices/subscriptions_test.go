@@ -0,0 +1,276 @@
+package invoices
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSubscriptionStore is an in-memory subscription store.
+type mockSubscriptionStore struct {
+	sync.Mutex
+	subs map[SubscriptionID]*Subscription
+}
+
+func (m *mockSubscriptionStore) PutSubscription(sub *Subscription) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.subs[sub.ID] = sub.Copy()
+
+	return nil
+}
+
+func (m *mockSubscriptionStore) FetchSubscriptions() ([]*Subscription,
+	error) {
+
+	m.Lock()
+	defer m.Unlock()
+
+	subs := make([]*Subscription, 0, len(m.subs))
+	for _, sub := range m.subs {
+		subs = append(subs, sub.Copy())
+	}
+
+	return subs, nil
+}
+
+// subscriptionTestCtx holds the state of a subscription manager test.
+type subscriptionTestCtx struct {
+	t      *testing.T
+	clock  *clock.TestClock
+	ticker *ticker.Force
+	store  *mockSubscriptionStore
+
+	mu       sync.Mutex
+	invoices map[lntypes.Hash]Invoice
+
+	mgr *SubscriptionManager
+}
+
+func newSubscriptionTestCtx(t *testing.T) *subscriptionTestCtx {
+	ctx := &subscriptionTestCtx{
+		t:     t,
+		clock: clock.NewTestClock(time.Unix(1_000_000, 0)),
+		store: &mockSubscriptionStore{
+			subs: make(map[SubscriptionID]*Subscription),
+		},
+		invoices: make(map[lntypes.Hash]Invoice),
+	}
+	ctx.restart()
+
+	return ctx
+}
+
+// restart creates a new subscription manager backed by the same store and
+// starts it.
+func (c *subscriptionTestCtx) restart() {
+	if c.mgr != nil {
+		require.NoError(c.t, c.mgr.Stop())
+	}
+
+	c.ticker = ticker.NewForce(time.Hour)
+	c.mgr = NewSubscriptionManager(&SubscriptionManagerConfig{
+		Store:         c.store,
+		CreateInvoice: c.createInvoice,
+		LookupInvoice: c.lookupInvoice,
+		Clock:         c.clock,
+		PollTicker:    c.ticker,
+	})
+	require.NoError(c.t, c.mgr.Start())
+	c.t.Cleanup(func() {
+		require.NoError(c.t, c.mgr.Stop())
+	})
+}
+
+func (c *subscriptionTestCtx) createInvoice(_ context.Context,
+	tmpl *SubscriptionTemplate, _ uint32,
+	preimage lntypes.Preimage) error {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.invoices[preimage.Hash()]; ok {
+		return ErrDuplicateInvoice
+	}
+
+	c.invoices[preimage.Hash()] = Invoice{
+		Terms: ContractTerm{
+			PaymentPreimage: &preimage,
+			Value:           tmpl.Value,
+		},
+		State: ContractOpen,
+	}
+
+	return nil
+}
+
+func (c *subscriptionTestCtx) lookupInvoice(_ context.Context,
+	hash lntypes.Hash) (Invoice, error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	invoice, ok := c.invoices[hash]
+	if !ok {
+		return Invoice{}, ErrInvoiceNotFound
+	}
+
+	return invoice, nil
+}
+
+// settle marks the invoice with the given hash as paid.
+func (c *subscriptionTestCtx) settle(hash lntypes.Hash) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	invoice := c.invoices[hash]
+	invoice.State = ContractSettled
+	invoice.AmtPaid = invoice.Terms.Value
+	c.invoices[hash] = invoice
+}
+
+// numInvoices returns the number of created invoices.
+func (c *subscriptionTestCtx) numInvoices() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.invoices)
+}
+
+// tick advances the clock by the given duration and waits until the manager
+// generated the expected number of invoices.
+func (c *subscriptionTestCtx) tick(d time.Duration, expected int) {
+	c.clock.SetTime(c.clock.Now().Add(d))
+	c.ticker.Force <- c.clock.Now()
+
+	require.Eventually(c.t, func() bool {
+		return c.numInvoices() == expected
+	}, time.Second, 10*time.Millisecond)
+}
+
+// TestSubscriptionTemplateValidate asserts that invalid templates are
+// rejected.
+func TestSubscriptionTemplateValidate(t *testing.T) {
+	t.Parallel()
+
+	tmpl := SubscriptionTemplate{
+		Value:  1000,
+		Period: time.Second,
+	}
+	require.ErrorIs(t, tmpl.Validate(), ErrInvalidSubscriptionPeriod)
+
+	tmpl.Period = time.Hour
+	tmpl.Value = 0
+	require.ErrorIs(t, tmpl.Validate(), ErrInvalidSubscriptionValue)
+
+	tmpl.Value = 1000
+	require.NoError(t, tmpl.Validate())
+}
+
+// TestSubscriptionManager tests that the manager generates an invoice for
+// each period and reports the payment status of each period.
+func TestSubscriptionManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSubscriptionTestCtx(t)
+
+	sub, err := ctx.mgr.AddSubscription(
+		context.Background(), SubscriptionTemplate{
+			Value:      1000,
+			Period:     time.Hour,
+			NumPeriods: 3,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, ctx.clock.Now(), sub.Template.StartTime)
+
+	// The invoice of the first period is created right away.
+	require.Equal(t, 1, ctx.numInvoices())
+	require.Len(t, sub.Invoices, 1)
+	ctx.settle(sub.Invoices[0].PaymentHash)
+
+	// Ticking within the first period doesn't create a new invoice.
+	ctx.tick(time.Minute, 1)
+
+	// Moving into the second period creates its invoice.
+	ctx.tick(time.Hour, 2)
+
+	status, err := ctx.mgr.SubscriptionStatus(context.Background(), sub.ID)
+	require.NoError(t, err)
+	require.Equal(t, SubscriptionActive, status.State)
+	require.Len(t, status.Periods, 2)
+	require.Equal(t, ContractSettled, status.Periods[0].State)
+	require.EqualValues(t, 1000, status.Periods[0].AmtPaid)
+	require.Equal(t, ContractOpen, status.Periods[1].State)
+	require.Equal(t, sub.Template.PeriodStart(1), status.Periods[1].Start)
+
+	// A restart must not create a duplicate invoice for the current
+	// period.
+	ctx.restart()
+	require.Equal(t, 2, ctx.numInvoices())
+
+	// Skipping a period while offline only creates the invoice of the
+	// current period.
+	ctx.clock.SetTime(sub.Template.PeriodStart(2).Add(time.Minute))
+	ctx.restart()
+	require.Equal(t, 3, ctx.numInvoices())
+
+	// After the last period, the subscription is completed.
+	ctx.tick(time.Hour, 3)
+	require.Eventually(t, func() bool {
+		status, err := ctx.mgr.SubscriptionStatus(
+			context.Background(), sub.ID,
+		)
+		require.NoError(t, err)
+
+		return status.State == SubscriptionCompleted
+	}, time.Second, 10*time.Millisecond)
+
+	err = ctx.mgr.CancelSubscription(sub.ID)
+	require.ErrorIs(t, err, ErrSubscriptionNotActive)
+}
+
+// TestSubscriptionManagerCancel tests that no invoices are generated for a
+// canceled subscription.
+func TestSubscriptionManagerCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx := newSubscriptionTestCtx(t)
+
+	// A subscription starting in the future doesn't create an invoice
+	// yet.
+	sub, err := ctx.mgr.AddSubscription(
+		context.Background(), SubscriptionTemplate{
+			Value:     1000,
+			Period:    time.Hour,
+			StartTime: ctx.clock.Now().Add(time.Hour),
+		},
+	)
+	require.NoError(t, err)
+	require.Zero(t, ctx.numInvoices())
+
+	ctx.tick(time.Hour, 1)
+
+	require.NoError(t, ctx.mgr.CancelSubscription(sub.ID))
+	require.ErrorIs(
+		t, ctx.mgr.CancelSubscription(SubscriptionID{}),
+		ErrSubscriptionNotFound,
+	)
+
+	// The cancellation is persisted.
+	ctx.restart()
+	ctx.tick(time.Hour, 1)
+
+	subs, err := ctx.mgr.Subscriptions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	require.Equal(t, SubscriptionCanceled, subs[0].State)
+	require.Len(t, subs[0].Periods, 1)
+}
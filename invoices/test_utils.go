@@ -222,8 +222,8 @@ func setupHodlExpiry(t *testing.T, creationDate time.Time,
 	test := &hodlExpiryTest{
 		state: startState,
 		watcher: NewInvoiceExpiryWatcher(
-			mockClock, heightDelta, uint32(testCurrentHeight), nil,
-			mockNotifier,
+			mockClock, heightDelta, 0, uint32(testCurrentHeight),
+			nil, mockNotifier,
 		),
 		cancelChan:   make(chan lntypes.Hash),
 		mockNotifier: mockNotifier,
@@ -249,7 +249,7 @@ func setupHodlExpiry(t *testing.T, creationDate time.Time,
 		return nil
 	}
 
-	require.NoError(t, test.watcher.Start(cancelImpl))
+	require.NoError(t, test.watcher.Start(cancelImpl, nil))
 
 	// We set preimage and hash so that we can use our existing test
 	// helpers. In practice we would only have the hash, but this does not
@@ -10,6 +10,7 @@ import (
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
 )
 
 // invoiceUpdateCtx is an object that describes the context for the invoice
@@ -35,6 +36,11 @@ type invoiceUpdateCtx struct {
 	metadata     []byte
 	pathID       *chainhash.Hash
 	totalAmtMsat lnwire.MilliSatoshi
+
+	// incomingPeer is the peer the htlc was received from. It is only
+	// looked up for invoices that restrict the peers they can be paid
+	// from, and nil otherwise.
+	incomingPeer *route.Vertex
 }
 
 // invoiceRef returns an identifier that can be used to lookup or update the
@@ -109,6 +115,30 @@ func (i invoiceUpdateCtx) acceptRes(
 	return newAcceptResolution(i.circuitKey, outcome)
 }
 
+// checkHtlcConstraints returns a failure resolution if the htlc violates the
+// htlc constraints of the invoice, and nil otherwise. The set size is the
+// number of htlcs in the set, including this one.
+func (i *invoiceUpdateCtx) checkHtlcConstraints(inv *Invoice,
+	setSize int) *HtlcFailResolution {
+
+	constraints := inv.Terms.HtlcConstraints
+	switch {
+	case constraints.MaxHtlcs != 0 && setSize > int(constraints.MaxHtlcs):
+		return i.failRes(ResultHtlcConstraintViolation)
+
+	case i.amtPaid < constraints.MinHtlcAmt:
+		return i.failRes(ResultHtlcConstraintViolation)
+
+	case !constraints.allowsChannel(i.circuitKey.ChanID):
+		return i.failRes(ResultHtlcConstraintViolation)
+
+	case !constraints.allowsPeer(i.incomingPeer):
+		return i.failRes(ResultHtlcConstraintViolation)
+	}
+
+	return nil
+}
+
 // updateInvoice is a callback for DB.UpdateInvoice that contains the invoice
 // settlement logic. It returns a HTLC resolution that indicates what the
 // outcome of the update was.
@@ -232,8 +262,9 @@ func updateMpp(ctx *invoiceUpdateCtx, inv *Invoice) (*InvoiceUpdateDesc,
 	}
 
 	// Check that the total amt of the htlc set is high enough. In case this
-	// is a zero-valued invoice, it will always be enough.
-	if totalAmt < inv.Terms.Value {
+	// is a zero-valued invoice, it will always be enough. The partial
+	// payment policy of the invoice may allow a lower total.
+	if totalAmt < inv.Terms.PartialPayment.MinSetTotal(inv.Terms.Value) {
 		return nil, ctx.failRes(ResultHtlcSetTotalTooLow), nil
 	}
 
@@ -252,6 +283,11 @@ func updateMpp(ctx *invoiceUpdateCtx, inv *Invoice) (*InvoiceUpdateDesc,
 	// Add amount of new htlc.
 	newSetTotal += ctx.amtPaid
 
+	// Enforce the constraints the invoice puts on the htlcs paying it.
+	if res := ctx.checkHtlcConstraints(inv, len(htlcSet)+1); res != nil {
+		return nil, res, nil
+	}
+
 	// The invoice is still open. Check the expiry.
 	if ctx.expiry < uint32(ctx.currentHeight+ctx.finalCltvRejectDelta) {
 		return nil, ctx.failRes(ResultExpiryTooSoon), nil
@@ -320,7 +356,12 @@ func updateMpp(ctx *invoiceUpdateCtx, inv *Invoice) (*InvoiceUpdateDesc,
 		SetID:         setID,
 	}
 
-	return &update, ctx.settleRes(htlcPreimage, ResultSettled), nil
+	result := ResultSettled
+	if newSetTotal < inv.Terms.Value {
+		result = ResultSettledPartial
+	}
+
+	return &update, ctx.settleRes(htlcPreimage, result), nil
 }
 
 // HTLCSet is a map of CircuitKey to InvoiceHTLC.
@@ -441,6 +482,12 @@ func updateLegacy(ctx *invoiceUpdateCtx,
 		}
 	}
 
+	// Enforce the constraints the invoice puts on the htlcs paying it. A
+	// legacy payment always consists of a single htlc.
+	if res := ctx.checkHtlcConstraints(inv, 1); res != nil {
+		return nil, res, nil
+	}
+
 	// The invoice is still open. Check the expiry.
 	if ctx.expiry < uint32(ctx.currentHeight+ctx.finalCltvRejectDelta) {
 		return nil, ctx.failRes(ResultExpiryTooSoon), nil
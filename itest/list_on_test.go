@@ -338,6 +338,10 @@ var allTestCases = []*lntest.TestCase{
 		Name:     "revoked uncooperative close retribution remote hodl",
 		TestFunc: testRevokedCloseRetributionRemoteHodl,
 	},
+	{
+		Name:     "revoked uncooperative close retribution taproot",
+		TestFunc: testRevokedCloseRetributionTaproot,
+	},
 	{
 		Name:     "single-hop send to route",
 		TestFunc: testSingleHopSendToRoute,
@@ -702,4 +706,20 @@ var allTestCases = []*lntest.TestCase{
 		Name:     "send to route failed htlc timeout",
 		TestFunc: testSendToRouteFailHTLCTimeout,
 	},
+	{
+		Name:     "accounts",
+		TestFunc: testAccounts,
+	},
+	{
+		Name:     "forwarding rules",
+		TestFunc: testForwardingRules,
+	},
+	{
+		Name:     "override sweep params",
+		TestFunc: testOverrideSweepParams,
+	},
+	{
+		Name:     "graph metrics",
+		TestFunc: testGraphMetrics,
+	},
 }
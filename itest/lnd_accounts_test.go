@@ -0,0 +1,135 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testAccounts tests that a macaroon that is bound to an off-chain account
+// can only spend the balance of its account, that paid invoices of the
+// account are credited and that the account can be managed over RPC.
+func testAccounts(ht *lntest.HarnessTest) {
+	const (
+		chanAmt = btcutil.Amount(1_000_000)
+		pushAmt = btcutil.Amount(500_000)
+	)
+
+	alice := ht.NewNode("Alice", []string{"--accounts.active"})
+	bob := ht.NewNode("Bob", nil)
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, alice)
+	ht.ConnectNodes(alice, bob)
+
+	chanPoint := ht.OpenChannel(alice, bob, lntest.OpenChannelParams{
+		Amt:     chanAmt,
+		PushAmt: pushAmt,
+	})
+	defer ht.CloseChannel(alice, chanPoint)
+
+	// Alice creates an account with an initial balance of 50k sat and
+	// hands out the macaroon that is bound to it.
+	resp := alice.RPC.CreateAccount(&lnrpc.CreateAccountRequest{
+		Label:       "app",
+		BalanceMsat: 50_000_000,
+	})
+	accountID := resp.Account.Id
+	require.EqualValues(ht, 50_000_000, resp.Account.BalanceMsat)
+
+	mac, err := readMacaroonFromHex(resp.Macaroon)
+	require.NoError(ht, err)
+	cleanup, client := macaroonClient(ht.T, alice, mac)
+	defer cleanup()
+
+	ctxt := ht.Context()
+
+	// assertBalance waits until the account has the given balance.
+	assertBalance := func(balanceMsat uint64) {
+		err := wait.NoError(func() error {
+			accounts := alice.RPC.ListOffChainAccounts().Accounts
+			if len(accounts) != 1 {
+				return fmt.Errorf("expected 1 account, got %d",
+					len(accounts))
+			}
+
+			if accounts[0].BalanceMsat != balanceMsat {
+				return fmt.Errorf("expected balance %d, got "+
+					"%d", balanceMsat,
+					accounts[0].BalanceMsat)
+			}
+
+			return nil
+		}, defaultTimeout)
+		require.NoError(ht, err)
+	}
+
+	// The account macaroon can't be used for methods that aren't
+	// accounted for.
+	_, err = client.ListChannels(ctxt, &lnrpc.ListChannelsRequest{})
+	require.ErrorContains(ht, err, "method not allowed")
+
+	// An invoice of the account that Bob pays is credited to it.
+	invoice, err := client.AddInvoice(ctxt, &lnrpc.Invoice{Value: 20_000})
+	require.NoError(ht, err)
+	ht.CompletePaymentRequests(bob, []string{invoice.PaymentRequest})
+	assertBalance(70_000_000)
+
+	// A payment that exceeds the balance of the account is rejected, even
+	// though Alice's channel could carry it.
+	noFee := &lnrpc.FeeLimit{Limit: &lnrpc.FeeLimit_Fixed{Fixed: 0}}
+	bigInvoice := bob.RPC.AddInvoice(&lnrpc.Invoice{Value: 100_000})
+	_, err = client.SendPaymentSync(ctxt, &lnrpc.SendRequest{
+		PaymentRequest: bigInvoice.PaymentRequest,
+		FeeLimit:       noFee,
+	})
+	require.ErrorContains(ht, err, "insufficient account balance")
+
+	// A payment within the balance succeeds and is debited.
+	smallInvoice := bob.RPC.AddInvoice(&lnrpc.Invoice{Value: 30_000})
+	payResp, err := client.SendPaymentSync(ctxt, &lnrpc.SendRequest{
+		PaymentRequest: smallInvoice.PaymentRequest,
+		FeeLimit:       noFee,
+	})
+	require.NoError(ht, err)
+	require.Empty(ht, payResp.PaymentError)
+	assertBalance(40_000_000)
+
+	// The ledger holds the initial balance, the invoice and the payment.
+	entries := alice.RPC.AccountLedger(accountID).Entries
+	require.Len(ht, entries, 3)
+	require.Equal(ht, "adjustment", entries[0].Kind)
+	require.EqualValues(ht, 50_000_000, entries[0].AmountMsat)
+	require.Equal(ht, "invoice", entries[1].Kind)
+	require.EqualValues(ht, 20_000_000, entries[1].AmountMsat)
+	require.Equal(ht, invoice.RHash, entries[1].PaymentHash)
+	require.Equal(ht, "payment", entries[2].Kind)
+	require.EqualValues(ht, -30_000_000, entries[2].AmountMsat)
+	require.Equal(ht, payResp.PaymentHash, entries[2].PaymentHash)
+
+	// Alice debits the rest of the balance, so that the account can't pay
+	// anything anymore.
+	adjustResp := alice.RPC.AdjustAccountBalance(
+		&lnrpc.AdjustAccountBalanceRequest{
+			Id:         accountID,
+			AmountMsat: -40_000_000,
+		},
+	)
+	require.Zero(ht, adjustResp.Account.BalanceMsat)
+
+	tinyInvoice := bob.RPC.AddInvoice(&lnrpc.Invoice{Value: 1})
+	_, err = client.SendPaymentSync(ctxt, &lnrpc.SendRequest{
+		PaymentRequest: tinyInvoice.PaymentRequest,
+		FeeLimit:       noFee,
+	})
+	require.ErrorContains(ht, err, "insufficient account balance")
+
+	// Once the account is removed, its macaroon is rejected.
+	alice.RPC.RemoveAccount(accountID)
+	require.Empty(ht, alice.RPC.ListOffChainAccounts().Accounts)
+
+	_, err = client.GetInfo(ctxt, &lnrpc.GetInfoRequest{})
+	require.ErrorContains(ht, err, "account not found")
+}
@@ -0,0 +1,67 @@
+package itest
+
+import (
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/stretchr/testify/require"
+)
+
+// testForwardingRules tests that the forwarding rules set over RPC are
+// enforced by the outgoing link, and that forwards resume once the rules are
+// removed.
+func testForwardingRules(ht *lntest.HarnessTest) {
+	const chanAmt = btcutil.Amount(1_000_000)
+
+	alice, bob := ht.Alice, ht.Bob
+	carol := ht.NewNode("Carol", nil)
+	ht.EnsureConnected(alice, bob)
+	ht.ConnectNodes(bob, carol)
+
+	chanPointAB := ht.OpenChannel(
+		alice, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(alice, chanPointAB)
+
+	chanPointBC := ht.OpenChannel(
+		bob, carol, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(bob, chanPointBC)
+
+	// Alice needs to know about the channel between Bob and Carol to pay
+	// Carol through Bob.
+	ht.AssertTopologyChannelOpen(alice, chanPointBC)
+
+	// Bob denies all forwards over his channel with Carol.
+	bobCarolChan := ht.QueryChannelByChanPoint(bob, chanPointBC)
+	bob.RPC.SetForwardingRules([]*lnrpc.ForwardingRule{{
+		ChanId: bobCarolChan.ChanId,
+		Deny:   true,
+	}})
+
+	rules := bob.RPC.ListForwardingRules().Rules
+	require.Len(ht, rules, 1)
+	require.Equal(ht, bobCarolChan.ChanId, rules[0].ChanId)
+	require.True(ht, rules[0].Deny)
+
+	// Bob is the only route to Carol, so Alice's payment fails.
+	invoice := carol.RPC.AddInvoice(&lnrpc.Invoice{Value: 10_000})
+	ht.SendPaymentAssertFail(
+		alice, &routerrpc.SendPaymentRequest{
+			PaymentRequest: invoice.PaymentRequest,
+			TimeoutSeconds: 60,
+			FeeLimitMsat:   noFeeLimitMsat,
+		},
+		lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE,
+	)
+
+	// Once Bob removes the rules, and Alice forgets about the failure,
+	// the payment goes through.
+	bob.RPC.SetForwardingRules(nil)
+	require.Empty(ht, bob.RPC.ListForwardingRules().Rules)
+	alice.RPC.ResetMissionControl()
+
+	invoice = carol.RPC.AddInvoice(&lnrpc.Invoice{Value: 10_000})
+	ht.CompletePaymentRequests(alice, []string{invoice.PaymentRequest})
+}
@@ -0,0 +1,115 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/node"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testGraphMetrics tests that the graph analytics are recomputed once
+// channels are added to or removed from the graph, and that the reachability
+// and bridges of the graph are returned over RPC.
+func testGraphMetrics(ht *lntest.HarnessTest) {
+	const chanAmt = btcutil.Amount(1_000_000)
+
+	alice := ht.NewNode("Alice", []string{
+		"--routing.graph-analytics",
+		"--routing.graph-analytics-batch-delay=1s",
+	})
+	bob := ht.NewNode("Bob", nil)
+	carol := ht.NewNode("Carol", nil)
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, alice)
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, bob)
+	ht.ConnectNodes(alice, bob)
+	ht.ConnectNodes(bob, carol)
+
+	// assertMetrics waits until Alice's graph metrics report the given
+	// number of channels.
+	assertMetrics := func(numChannels uint32) *lnrpc.GraphMetricsResponse {
+		var metrics *lnrpc.GraphMetricsResponse
+		err := wait.NoError(func() error {
+			metrics = alice.RPC.GetGraphMetrics()
+			if metrics.NumChannels != numChannels {
+				return fmt.Errorf("expected %d channels, got "+
+					"%d", numChannels, metrics.NumChannels)
+			}
+
+			return nil
+		}, defaultTimeout)
+		require.NoError(ht, err, "graph metrics not recomputed")
+
+		return metrics
+	}
+
+	// Alice, Bob and Carol form a line, so both channels are bridges.
+	chanPointAB := ht.OpenChannel(
+		alice, bob, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	defer ht.CloseChannel(alice, chanPointAB)
+
+	chanPointBC := ht.OpenChannel(
+		bob, carol, lntest.OpenChannelParams{Amt: chanAmt},
+	)
+	ht.AssertTopologyChannelOpen(alice, chanPointBC)
+
+	metrics := assertMetrics(2)
+	require.EqualValues(ht, 3, metrics.NumNodes)
+	require.EqualValues(ht, 2, metrics.Diameter)
+	require.Equal(ht, []uint32{3}, metrics.ComponentSizes)
+
+	// assertBridge asserts that the given bridge is formed by the
+	// channels between the given nodes.
+	assertBridge := func(bridge *lnrpc.GraphBridge,
+		node1, node2 *node.HarnessNode) {
+
+		require.ElementsMatch(
+			ht, []string{node1.PubKeyStr, node2.PubKeyStr},
+			[]string{bridge.Node1Pub, bridge.Node2Pub},
+		)
+	}
+
+	require.Len(ht, metrics.Bridges, 2)
+	if metrics.Bridges[0].Node1Pub == carol.PubKeyStr ||
+		metrics.Bridges[0].Node2Pub == carol.PubKeyStr {
+
+		metrics.Bridges[0], metrics.Bridges[1] =
+			metrics.Bridges[1], metrics.Bridges[0]
+	}
+	assertBridge(metrics.Bridges[0], alice, bob)
+	assertBridge(metrics.Bridges[1], bob, carol)
+
+	// Every node can reach the two other nodes.
+	nodeMetrics := alice.RPC.GetNodeMetrics(&lnrpc.NodeMetricsRequest{
+		Types: []lnrpc.NodeMetricType{
+			lnrpc.NodeMetricType_REACHABILITY,
+		},
+	})
+	for _, hn := range []*node.HarnessNode{alice, bob, carol} {
+		require.EqualValues(
+			ht, 2, nodeMetrics.Reachability[hn.PubKeyStr],
+		)
+	}
+
+	// Once the channel between Bob and Carol is closed, Carol can't be
+	// reached anymore and only the channel between Alice and Bob is left
+	// as a bridge.
+	ht.CloseChannel(bob, chanPointBC)
+	ht.AssertTopologyChannelClosed(alice, chanPointBC)
+
+	metrics = assertMetrics(1)
+	require.Len(ht, metrics.Bridges, 1)
+	assertBridge(metrics.Bridges[0], alice, bob)
+
+	nodeMetrics = alice.RPC.GetNodeMetrics(&lnrpc.NodeMetricsRequest{
+		Types: []lnrpc.NodeMetricType{
+			lnrpc.NodeMetricType_REACHABILITY,
+		},
+	})
+	require.EqualValues(ht, 1, nodeMetrics.Reachability[alice.PubKeyStr])
+	require.Zero(ht, nodeMetrics.Reachability[carol.PubKeyStr])
+}
@@ -137,6 +137,29 @@ func testRemoteSigner(ht *lntest.HarnessTest) {
 			// sure we can fund and then sign PSBTs from our wallet.
 			runFundAndSignPsbt(ht, wo)
 		},
+	}, {
+		name:       "psbt taproot",
+		randomSeed: true,
+		fn: func(tt *lntest.HarnessTest, wo, carol *node.HarnessNode) {
+			// The initiator of a taproot channel needs to be able
+			// to pay for the anchor reserve.
+			tt.FundCoins(50_000, carol)
+			runPsbtChanFunding(
+				tt, carol, wo, true,
+				lnrpc.CommitmentType_SIMPLE_TAPROOT,
+			)
+
+			// The watch-only node must also be able to open a
+			// taproot channel itself, which includes the musig2
+			// nonce exchange of the funding flow and signing the
+			// first commitment through the remote signer.
+			tt.FundCoins(50_000, wo)
+			runPsbtChanFunding(
+				tt, wo, carol, true,
+				lnrpc.CommitmentType_SIMPLE_TAPROOT,
+			)
+		},
+		commitType: lnrpc.CommitmentType_SIMPLE_TAPROOT,
 	}, {
 		name:      "sign output raw",
 		sendCoins: true,
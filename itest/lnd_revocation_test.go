@@ -2,6 +2,7 @@ package itest
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 	"testing"
 
@@ -11,8 +12,12 @@ import (
 	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lnd/funding"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/node"
 	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/stretchr/testify/require"
 )
 
@@ -702,3 +707,149 @@ func testRevokedCloseRetributionRemoteHodl(ht *lntest.HarnessTest) {
 		})
 	}
 }
+
+// testRevokedCloseRetributionTaproot tests that Dave sweeps all outputs of a
+// revoked taproot commitment of Carol that has HTLCs in both directions. The
+// to_local output must be swept through the revocation leaf, the HTLC outputs
+// through a key spend with the revocation key and Dave's own output through
+// its script path, while Dave's anchor is left to the sweeper.
+func testRevokedCloseRetributionTaproot(ht *lntest.HarnessTest) {
+	const (
+		chanAmt = funding.MaxBtcFundingAmount
+		pushAmt = 200_000
+		htlcAmt = 20_000
+	)
+
+	// Carol will be the breaching party, Dave the breached one. We set
+	// --nolisten to ensure Carol won't be able to connect to Dave and
+	// trigger the channel data protection logic automatically.
+	commitType := lnrpc.CommitmentType_SIMPLE_TAPROOT
+	nodeArgs := lntest.NodeArgsForCommitType(commitType)
+	carol := ht.NewNode("Carol", nodeArgs)
+	dave := ht.NewNode("Dave", append(nodeArgs, "--nolisten"))
+	ht.ConnectNodes(dave, carol)
+
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, dave)
+
+	// Taproot channels can only be private for now.
+	chanPoint := ht.OpenChannel(
+		dave, carol, lntest.OpenChannelParams{
+			Amt:            chanAmt,
+			PushAmt:        pushAmt,
+			Private:        true,
+			CommitmentType: commitType,
+		},
+	)
+
+	// holdPayment makes the sender pay a hold invoice of the receiver,
+	// which won't be settled so the HTLC stays on the commitments.
+	holdPayment := func(sender, receiver *node.HarnessNode) {
+		payHash := sha256.Sum256(ht.Random32Bytes())
+		invoice := receiver.RPC.AddHoldInvoice(
+			&invoicesrpc.AddHoldInvoiceRequest{
+				Value: htlcAmt,
+				Hash:  payHash[:],
+			},
+		)
+		stream := receiver.RPC.SubscribeSingleInvoice(payHash[:])
+
+		ht.SendPaymentAssertInflight(
+			sender, &routerrpc.SendPaymentRequest{
+				PaymentRequest: invoice.PaymentRequest,
+				TimeoutSeconds: 60,
+				FeeLimitMsat:   noFeeLimitMsat,
+			},
+		)
+		ht.AssertInvoiceState(stream, lnrpc.Invoice_ACCEPTED)
+	}
+
+	// Lock an HTLC in each direction into Carol's commitment.
+	holdPayment(dave, carol)
+	holdPayment(carol, dave)
+	ht.AssertNumActiveHtlcs(carol, 2)
+	ht.AssertNumActiveHtlcs(dave, 2)
+
+	// Grab Carol's current commitment height and back up her state, which
+	// will be revoked by the next payment.
+	carolChan := ht.QueryChannelByChanPoint(carol, chanPoint)
+	carolStateNumPreCopy := int(carolChan.NumUpdates)
+	ht.BackupDB(carol)
+
+	ht.EnsureConnected(dave, carol)
+	ht.AssertTopologyChannelOpen(dave, chanPoint)
+
+	carolPayReqs, _, _ := ht.CreatePayReqs(carol, htlcAmt, 1)
+	ht.CompletePaymentRequestsNoWait(dave, carolPayReqs, chanPoint)
+
+	// Suspend Dave, such that Carol won't reconnect at startup, and make
+	// Carol force close with her revoked state.
+	restartDave := ht.SuspendNode(dave)
+	ht.RestartNodeAndRestoreDB(carol)
+	ht.AssertChannelCommitHeight(carol, chanPoint, carolStateNumPreCopy)
+
+	closeUpdates, closeTxID := ht.CloseChannelAssertPending(
+		carol, chanPoint, true,
+	)
+	block := ht.MineBlocksAndAssertNumTxes(1, 1)[0]
+
+	require.NoError(ht, restartDave(), "unable to restart Dave's node")
+
+	breachTXID := ht.WaitForChannelCloseEvent(closeUpdates)
+	require.Equal(ht, closeTxID, breachTXID)
+	ht.AssertTxInBlock(block, breachTXID)
+
+	// The breach transaction carries two anchors, the outputs of both
+	// parties and the two HTLCs.
+	breachTx := ht.GetRawTransaction(breachTXID).MsgTx()
+	require.Len(ht, breachTx.TxOut, 6)
+
+	var (
+		numAnchors int
+		htlcOp     wire.OutPoint
+	)
+	for i, txOut := range breachTx.TxOut {
+		switch txOut.Value {
+		case int64(lnwallet.AnchorSize):
+			numAnchors++
+
+		case htlcAmt:
+			htlcOp = wire.OutPoint{
+				Hash:  breachTXID,
+				Index: uint32(i),
+			}
+		}
+	}
+	require.Equal(ht, 2, numAnchors)
+
+	// Dave's justice transaction must sweep all outputs but the anchors.
+	justiceTx := ht.AssertOutpointInMempool(htlcOp)
+	require.Len(ht, justiceTx.TxIn, len(breachTx.TxOut)-numAnchors)
+
+	for _, txIn := range justiceTx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		require.Equal(ht, breachTXID, prevOut.Hash,
+			"justice tx not spending commitment utxo")
+
+		// The HTLC outputs are swept through a key spend with the
+		// revocation key, which only needs a signature. The outputs of
+		// both parties are swept through a script path, which needs a
+		// signature, the script and the control block.
+		expWitnessLen := 3
+		if breachTx.TxOut[prevOut.Index].Value == htlcAmt {
+			expWitnessLen = 1
+		}
+		require.Len(ht, txIn.Witness, expWitnessLen,
+			"unexpected witness for %v", prevOut)
+	}
+
+	// Dave persists his retribution state, so he continues to wait for the
+	// justice transaction to confirm after a restart. His anchor is offered
+	// to the sweeper, but won't be swept as it's uneconomical.
+	ht.RestartNode(dave)
+	ht.AssertNumPendingSweeps(dave, 1)
+
+	block = ht.MineBlocksAndAssertNumTxes(1, 1)[0]
+	ht.AssertTxInBlock(block, justiceTx.TxHash())
+
+	ht.AssertNodeNumChannels(dave, 0)
+}
@@ -0,0 +1,135 @@
+package itest
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/lntest"
+	"github.com/lightningnetwork/lnd/lntest/wait"
+	"github.com/stretchr/testify/require"
+)
+
+// testOverrideSweepParams checks that `PendingSweeps` returns the fee function
+// state of the sweeping tx of an input, and that the `OverrideSweepParams`
+// RPC replaces that tx using the overridden params.
+func testOverrideSweepParams(ht *lntest.HarnessTest) {
+	// Skip this test for neutrino, as it's not aware of mempool
+	// transactions.
+	if ht.IsNeutrinoBackend() {
+		ht.Skipf("skipping OverrideSweepParams test for neutrino " +
+			"backend")
+	}
+
+	alice := ht.NewNode("Alice", nil)
+	ht.FundCoins(btcutil.SatoshiPerBitcoin, alice)
+
+	// Alice sends a coin to herself and offers its first output to the
+	// sweeper.
+	tx := ht.SendCoins(alice, alice, btcutil.SatoshiPerBitcoin)
+	txid := tx.TxHash()
+	op := &lnrpc.OutPoint{
+		TxidBytes:   txid[:],
+		OutputIndex: uint32(0),
+	}
+	value := btcutil.Amount(tx.TxOut[0].Value)
+
+	budget := uint64(value / 10)
+	alice.RPC.BumpFee(&walletrpc.BumpFeeRequest{
+		Outpoint:  op,
+		Immediate: true,
+		Budget:    budget,
+	})
+
+	// assertSweepTx waits until the pending sweep reports a sweeping tx
+	// that is funded with the given budget and deadline, other than the
+	// given tx. The fee function state of the tx is returned.
+	assertSweepTx := func(budget uint64, deadline uint32,
+		replaced string) *walletrpc.SweepTxState {
+
+		var sweepTx *walletrpc.SweepTxState
+		err := wait.NoError(func() error {
+			pendingSweep := ht.AssertNumPendingSweeps(alice, 1)[0]
+
+			sweepTx = pendingSweep.SweepTx
+			switch {
+			case sweepTx == nil:
+				return fmt.Errorf("no sweeping tx")
+
+			case sweepTx.Txid == replaced:
+				return fmt.Errorf("sweeping tx %v not "+
+					"replaced", replaced)
+
+			case sweepTx.BudgetSat != budget:
+				return fmt.Errorf("expected budget %d, got %d",
+					budget, sweepTx.BudgetSat)
+
+			case deadline != 0 &&
+				sweepTx.DeadlineHeight != deadline:
+
+				return fmt.Errorf("expected deadline %d, got "+
+					"%d", deadline,
+					sweepTx.DeadlineHeight)
+			}
+
+			return nil
+		}, wait.DefaultTimeout)
+		require.NoError(ht, err, "sweeping tx not updated")
+
+		// The reported tx sweeps our input and is in the mempool.
+		require.Len(ht, sweepTx.Inputs, 1)
+		require.Equal(ht, txid[:], sweepTx.Inputs[0].TxidBytes)
+		sweepTxid, err := chainhash.NewHashFromStr(sweepTx.Txid)
+		require.NoError(ht, err)
+		ht.AssertTxInMempool(*sweepTxid)
+
+		// The fee function previews a next fee bump that doesn't
+		// decrease the fee rate or exceed the budget.
+		require.NotZero(ht, sweepTx.FeeFunctionWidth)
+		require.LessOrEqual(ht, sweepTx.FeeFunctionPosition,
+			sweepTx.FeeFunctionWidth)
+		require.LessOrEqual(ht, sweepTx.StartingSatPerVbyte,
+			sweepTx.CurrentSatPerVbyte)
+		require.LessOrEqual(ht, sweepTx.CurrentSatPerVbyte,
+			sweepTx.NextSatPerVbyte)
+		require.LessOrEqual(ht, sweepTx.NextSatPerVbyte,
+			sweepTx.EndingSatPerVbyte)
+		require.LessOrEqual(ht, sweepTx.FeeSat, sweepTx.NextFeeSat)
+		require.LessOrEqual(ht, sweepTx.NextFeeSat, budget)
+
+		return sweepTx
+	}
+
+	sweepTx := assertSweepTx(budget, 0, "")
+
+	// Alice now overrides the budget and the deadline of the input, which
+	// replaces the sweeping tx.
+	newBudget := budget * 2
+	newDeadline := uint32(ht.CurrentHeight()) + 6
+	alice.RPC.OverrideSweepParams(&walletrpc.OverrideSweepParamsRequest{
+		Outpoint:       op,
+		Budget:         newBudget,
+		DeadlineHeight: newDeadline,
+		Immediate:      true,
+	})
+
+	oldTxid, err := chainhash.NewHashFromStr(sweepTx.Txid)
+	require.NoError(ht, err)
+	ht.AssertTxNotInMempool(*oldTxid)
+
+	sweepTx = assertSweepTx(newBudget, newDeadline, sweepTx.Txid)
+
+	// The overridden params are also reported for the input itself.
+	pendingSweep := ht.AssertNumPendingSweeps(alice, 1)[0]
+	require.Equal(ht, newBudget, pendingSweep.Budget)
+	require.Equal(ht, newDeadline, pendingSweep.DeadlineHeight)
+
+	// Mine the sweeping tx, after which the input is no longer pending.
+	newTxid, err := chainhash.NewHashFromStr(sweepTx.Txid)
+	require.NoError(ht, err)
+	block := ht.MineBlocksAndAssertNumTxes(1, 2)[0]
+	ht.AssertTxInBlock(block, *newTxid)
+	ht.AssertNumPendingSweeps(alice, 0)
+}
@@ -474,6 +474,11 @@ func testRevokedCloseRetributionAltruistWatchtowerCase(ht *lntest.HarnessTest,
 	}, defaultTimeout)
 	require.NoError(ht, err, "unable to verify backup task completed")
 
+	// The session the backup was sent to is stored in the client
+	// database.
+	dbStats := dave.RPC.WatchtowerDBStats()
+	require.NotZero(ht, dbStats.NumSessions)
+
 	// Shutdown Dave to simulate going offline for an extended period of
 	// time. Once he's not watching, Carol will try to breach the channel.
 	restart := ht.SuspendNode(dave)
@@ -0,0 +1,358 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// ErrJobNotFound is returned when a job with the given id doesn't
+	// exist.
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrJobNotRunning is returned when a job that isn't running is
+	// canceled.
+	ErrJobNotRunning = errors.New("job not running")
+
+	// ErrManagerShuttingDown is returned when a job is submitted or
+	// subscribed to while the manager is shutting down.
+	ErrManagerShuttingDown = errors.New("job manager shutting down")
+)
+
+// State describes the state of a job.
+type State uint8
+
+const (
+	// StateRunning indicates that the operation of the job is running.
+	StateRunning State = iota
+
+	// StateSucceeded indicates that the operation of the job completed
+	// successfully.
+	StateSucceeded
+
+	// StateFailed indicates that the operation of the job returned an
+	// error.
+	StateFailed
+
+	// StateCanceled indicates that the job was canceled.
+	StateCanceled
+
+	// StateInterrupted indicates that lnd shut down while the job was
+	// running. The operation isn't resumed on startup.
+	StateInterrupted
+)
+
+// String returns a human-readable representation of the job state.
+func (s State) String() string {
+	switch s {
+	case StateRunning:
+		return "Running"
+
+	case StateSucceeded:
+		return "Succeeded"
+
+	case StateFailed:
+		return "Failed"
+
+	case StateCanceled:
+		return "Canceled"
+
+	case StateInterrupted:
+		return "Interrupted"
+
+	default:
+		return fmt.Sprintf("Unknown(%d)", uint8(s))
+	}
+}
+
+// IsFinal returns true if the job won't change its state anymore.
+func (s State) IsFinal() bool {
+	return s != StateRunning
+}
+
+// Job describes a long-running operation that is tracked by the manager.
+type Job struct {
+	// ID is the unique id of the job.
+	ID uint64
+
+	// Kind describes the type of operation, e.g. "closechannel".
+	Kind string
+
+	// State is the current state of the job.
+	State State
+
+	// CreatedAt is the time the job was submitted.
+	CreatedAt time.Time
+
+	// UpdatedAt is the time the state of the job last changed.
+	UpdatedAt time.Time
+
+	// Error is the error the operation failed with, if any.
+	Error string
+
+	// NumEvents is the number of events that were emitted by the job.
+	NumEvents uint64
+}
+
+// Event is an update that was emitted by the operation of a job.
+type Event struct {
+	// JobID is the id of the job that emitted the event.
+	JobID uint64
+
+	// Index is the index of the event within the events of the job,
+	// starting at zero.
+	Index uint64
+
+	// Time is the time the event was emitted.
+	Time time.Time
+
+	// Payload is the serialized update. Its format is determined by the
+	// kind of the job.
+	Payload []byte
+}
+
+// Func is the operation that is run by a job. Every payload passed to emit is
+// persisted as an event and delivered to the subscribers of the job. The
+// operation must return once the context is canceled.
+type Func func(ctx context.Context, emit func(payload []byte) error) error
+
+// Config holds the dependencies of the job manager.
+type Config struct {
+	// DB is the database the jobs are persisted in.
+	DB kvdb.Backend
+
+	// Clock is used to timestamp jobs and events.
+	Clock clock.Clock
+}
+
+// runningJob tracks a job whose operation is running.
+type runningJob struct {
+	cancel   context.CancelFunc
+	canceled atomic.Bool
+}
+
+// Manager runs long-running operations as jobs. The state and events of
+// every job are persisted, so that clients can list jobs, cancel them and
+// resume their event stream after disconnecting, even across restarts.
+type Manager struct {
+	started atomic.Bool
+	stopped atomic.Bool
+
+	cfg   *Config
+	store *store
+
+	mu      sync.Mutex
+	running map[uint64]*runningJob
+
+	// subscribers holds the wake-up channels of the subscribers of every
+	// job, keyed by job id and subscription id.
+	subscribers map[uint64]map[uint64]chan struct{}
+	nextSubID   uint64
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewManager creates a new job manager.
+func NewManager(cfg *Config) (*Manager, error) {
+	s, err := newStore(cfg.DB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Manager{
+		cfg:         cfg,
+		store:       s,
+		running:     make(map[uint64]*runningJob),
+		subscribers: make(map[uint64]map[uint64]chan struct{}),
+		quit:        make(chan struct{}),
+	}, nil
+}
+
+// Start marks the jobs that were running when lnd last shut down as
+// interrupted.
+func (m *Manager) Start() error {
+	if !m.started.CompareAndSwap(false, true) {
+		return errors.New("job manager already started")
+	}
+
+	log.Info("Job manager starting")
+
+	numInterrupted, err := m.store.interruptRunning(m.cfg.Clock.Now())
+	if err != nil {
+		return err
+	}
+
+	if numInterrupted > 0 {
+		log.Infof("Marked %v jobs as interrupted", numInterrupted)
+	}
+
+	return nil
+}
+
+// Stop cancels all running jobs and waits for their operations to return.
+// The jobs are marked as interrupted on the next start.
+func (m *Manager) Stop() error {
+	if !m.stopped.CompareAndSwap(false, true) {
+		return errors.New("job manager already stopped")
+	}
+
+	log.Info("Job manager shutting down...")
+	defer log.Debug("Job manager shutdown complete")
+
+	close(m.quit)
+
+	m.mu.Lock()
+	for _, job := range m.running {
+		job.cancel()
+	}
+	m.mu.Unlock()
+
+	m.wg.Wait()
+
+	return nil
+}
+
+// Submit starts a new job of the given kind that runs the operation, and
+// returns its id.
+func (m *Manager) Submit(kind string, f Func) (uint64, error) {
+	select {
+	case <-m.quit:
+		return 0, ErrManagerShuttingDown
+	default:
+	}
+
+	id, err := m.store.createJob(kind, m.cfg.Clock.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &runningJob{cancel: cancel}
+
+	m.mu.Lock()
+	m.running[id] = job
+	m.mu.Unlock()
+
+	log.Debugf("Started job %v of kind %v", id, kind)
+
+	m.wg.Add(1)
+	go m.runJob(ctx, id, job, f)
+
+	return id, nil
+}
+
+// runJob runs the operation of a job and records its outcome.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) runJob(ctx context.Context, id uint64, job *runningJob,
+	f Func) {
+
+	defer m.wg.Done()
+	defer job.cancel()
+
+	emit := func(payload []byte) error {
+		_, err := m.store.addEvent(id, payload, m.cfg.Clock.Now())
+		if err != nil {
+			return err
+		}
+
+		m.notifySubscribers(id)
+
+		return nil
+	}
+
+	err := f(ctx, emit)
+
+	// If we're shutting down, the job is left running so that it is
+	// marked as interrupted on the next start.
+	select {
+	case <-m.quit:
+		m.mu.Lock()
+		delete(m.running, id)
+		m.mu.Unlock()
+
+		m.notifySubscribers(id)
+
+		return
+
+	default:
+	}
+
+	state := StateSucceeded
+	var errStr string
+	switch {
+	case job.canceled.Load():
+		state = StateCanceled
+
+	case err != nil:
+		state = StateFailed
+		errStr = err.Error()
+	}
+
+	log.Debugf("Job %v finished: state=%v, err=%v", id, state, err)
+
+	updateErr := m.store.updateState(id, state, errStr, m.cfg.Clock.Now())
+	if updateErr != nil {
+		log.Errorf("Unable to update state of job %v: %v", id,
+			updateErr)
+	}
+
+	m.mu.Lock()
+	delete(m.running, id)
+	m.mu.Unlock()
+
+	m.notifySubscribers(id)
+}
+
+// Jobs returns all jobs, ordered by their id.
+func (m *Manager) Jobs() ([]*Job, error) {
+	return m.store.fetchJobs()
+}
+
+// Job returns the job with the given id.
+func (m *Manager) Job(id uint64) (*Job, error) {
+	return m.store.fetchJob(id)
+}
+
+// Cancel cancels the operation of a running job.
+func (m *Manager) Cancel(id uint64) error {
+	m.mu.Lock()
+	job, ok := m.running[id]
+	m.mu.Unlock()
+
+	if !ok {
+		if _, err := m.store.fetchJob(id); err != nil {
+			return err
+		}
+
+		return ErrJobNotRunning
+	}
+
+	log.Debugf("Canceling job %v", id)
+
+	job.canceled.Store(true)
+	job.cancel()
+
+	return nil
+}
+
+// notifySubscribers wakes up all subscribers of the job.
+func (m *Manager) notifySubscribers(id uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, wakeup := range m.subscribers[id] {
+		select {
+		case wakeup <- struct{}{}:
+		default:
+		}
+	}
+}
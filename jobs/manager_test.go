@@ -0,0 +1,223 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 5 * time.Second
+
+var testTime = time.Unix(1700000000, 0)
+
+// newTestManager creates a started job manager backed by the given database.
+func newTestManager(t *testing.T, db kvdb.Backend) *Manager {
+	t.Helper()
+
+	m, err := NewManager(&Config{
+		DB:    db,
+		Clock: clock.NewTestClock(testTime),
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.Start())
+
+	return m
+}
+
+// newTestDB creates a new database for the tests.
+func newTestDB(t *testing.T) kvdb.Backend {
+	t.Helper()
+
+	db, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "jobs")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	return db
+}
+
+// receiveEvents reads the events from the subscription until it is closed.
+func receiveEvents(t *testing.T, sub *Subscription) []*Event {
+	t.Helper()
+
+	var events []*Event
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+
+		case <-time.After(testTimeout):
+			t.Fatalf("subscription not closed")
+		}
+	}
+}
+
+// waitForState waits until the job reached the given state.
+func waitForState(t *testing.T, m *Manager, id uint64, state State) *Job {
+	t.Helper()
+
+	var job *Job
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = m.Job(id)
+		require.NoError(t, err)
+
+		return job.State == state
+	}, testTimeout, 10*time.Millisecond)
+
+	return job
+}
+
+// TestJobEvents tests that the events of a job are delivered to subscribers,
+// and that a subscriber can resume the event stream at a given index.
+func TestJobEvents(t *testing.T) {
+	t.Parallel()
+
+	m := newTestManager(t, newTestDB(t))
+	t.Cleanup(func() {
+		require.NoError(t, m.Stop())
+	})
+
+	proceed := make(chan struct{})
+	id, err := m.Submit("test", func(ctx context.Context,
+		emit func([]byte) error) error {
+
+		if err := emit([]byte{0}); err != nil {
+			return err
+		}
+
+		<-proceed
+
+		for i := byte(1); i < 3; i++ {
+			if err := emit([]byte{i}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	sub, err := m.Subscribe(id, 0)
+	require.NoError(t, err)
+
+	close(proceed)
+
+	events := receiveEvents(t, sub)
+	require.Len(t, events, 3)
+	for i, event := range events {
+		require.EqualValues(t, i, event.Index)
+		require.Equal(t, id, event.JobID)
+		require.Equal(t, []byte{byte(i)}, event.Payload)
+	}
+
+	job := waitForState(t, m, id, StateSucceeded)
+	require.Equal(t, "test", job.Kind)
+	require.EqualValues(t, 3, job.NumEvents)
+
+	// Resuming the stream of the finished job replays the remaining
+	// events.
+	sub, err = m.Subscribe(id, 2)
+	require.NoError(t, err)
+
+	events = receiveEvents(t, sub)
+	require.Len(t, events, 1)
+	require.EqualValues(t, 2, events[0].Index)
+
+	_, err = m.Subscribe(id+1, 0)
+	require.ErrorIs(t, err, ErrJobNotFound)
+}
+
+// TestJobCancel tests that running jobs can be canceled, and that failed jobs
+// record their error.
+func TestJobCancel(t *testing.T) {
+	t.Parallel()
+
+	m := newTestManager(t, newTestDB(t))
+	t.Cleanup(func() {
+		require.NoError(t, m.Stop())
+	})
+
+	id, err := m.Submit("test", func(ctx context.Context,
+		_ func([]byte) error) error {
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Cancel(id))
+	waitForState(t, m, id, StateCanceled)
+
+	require.ErrorIs(t, m.Cancel(id), ErrJobNotRunning)
+	require.ErrorIs(t, m.Cancel(id+1), ErrJobNotFound)
+
+	id, err = m.Submit("test", func(context.Context,
+		func([]byte) error) error {
+
+		return errors.New("failed")
+	})
+	require.NoError(t, err)
+
+	job := waitForState(t, m, id, StateFailed)
+	require.Equal(t, "failed", job.Error)
+
+	jobs, err := m.Jobs()
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+}
+
+// TestJobInterrupted tests that jobs that were running on shutdown are marked
+// as interrupted on the next start, and that their events are retained.
+func TestJobInterrupted(t *testing.T) {
+	t.Parallel()
+
+	db := newTestDB(t)
+	m := newTestManager(t, db)
+
+	emitted := make(chan struct{})
+	id, err := m.Submit("test", func(ctx context.Context,
+		emit func([]byte) error) error {
+
+		if err := emit([]byte{1}); err != nil {
+			return err
+		}
+		close(emitted)
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+
+	<-emitted
+	require.NoError(t, m.Stop())
+
+	job, err := m.Job(id)
+	require.NoError(t, err)
+	require.Equal(t, StateRunning, job.State)
+
+	m = newTestManager(t, db)
+	t.Cleanup(func() {
+		require.NoError(t, m.Stop())
+	})
+
+	job, err = m.Job(id)
+	require.NoError(t, err)
+	require.Equal(t, StateInterrupted, job.State)
+	require.EqualValues(t, 1, job.NumEvents)
+
+	sub, err := m.Subscribe(id, 0)
+	require.NoError(t, err)
+	require.Len(t, receiveEvents(t, sub), 1)
+
+	require.ErrorIs(t, m.Cancel(id), ErrJobNotRunning)
+}
@@ -0,0 +1,404 @@
+package jobs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// jobsBucket is the top level bucket that stores the jobs. Every job
+	// is stored in a sub-bucket keyed by its id.
+	//
+	// jobs
+	//   |
+	//   |-- <job-id>
+	//   |       |-- info: <tlv encoded job info>
+	//   |       |-- events
+	//   |             |-- <event-index>: <time><payload>
+	//   |
+	//   |-- <job-id>
+	//           |-- ...
+	jobsBucket = []byte("jobs")
+
+	// jobInfoKey is the key in the job bucket that stores the job info.
+	jobInfoKey = []byte("info")
+
+	// jobEventsBucket is the sub-bucket of a job that stores its events,
+	// keyed by their index.
+	jobEventsBucket = []byte("events")
+
+	// byteOrder is the byte order used to encode ids, indexes and times.
+	byteOrder = binary.BigEndian
+)
+
+const (
+	kindType      tlv.Type = 0
+	stateType     tlv.Type = 1
+	createdAtType tlv.Type = 2
+	updatedAtType tlv.Type = 3
+	errorType     tlv.Type = 4
+)
+
+// store persists jobs and their events.
+type store struct {
+	db kvdb.Backend
+}
+
+// newStore creates a new job store, creating the top level bucket if needed.
+func newStore(db kvdb.Backend) (*store, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(jobsBucket)
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &store{db: db}, nil
+}
+
+// jobKey encodes the id of a job.
+func jobKey(id uint64) []byte {
+	var k [8]byte
+	byteOrder.PutUint64(k[:], id)
+
+	return k[:]
+}
+
+// encodeJob encodes the info of a job.
+func encodeJob(job *Job) ([]byte, error) {
+	var (
+		kind      = []byte(job.Kind)
+		state     = uint8(job.State)
+		createdAt = uint64(job.CreatedAt.UnixNano())
+		updatedAt = uint64(job.UpdatedAt.UnixNano())
+		errStr    = []byte(job.Error)
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(kindType, &kind),
+		tlv.MakePrimitiveRecord(stateType, &state),
+		tlv.MakePrimitiveRecord(createdAtType, &createdAt),
+		tlv.MakePrimitiveRecord(updatedAtType, &updatedAt),
+		tlv.MakePrimitiveRecord(errorType, &errStr),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeJob decodes the info of the job with the given id.
+func decodeJob(id uint64, b []byte) (*Job, error) {
+	var (
+		kind, errStr         []byte
+		state                uint8
+		createdAt, updatedAt uint64
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(kindType, &kind),
+		tlv.MakePrimitiveRecord(stateType, &state),
+		tlv.MakePrimitiveRecord(createdAtType, &createdAt),
+		tlv.MakePrimitiveRecord(updatedAtType, &updatedAt),
+		tlv.MakePrimitiveRecord(errorType, &errStr),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := stream.Decode(bytes.NewReader(b)); err != nil {
+		return nil, err
+	}
+
+	return &Job{
+		ID:        id,
+		Kind:      string(kind),
+		State:     State(state),
+		CreatedAt: time.Unix(0, int64(createdAt)),
+		UpdatedAt: time.Unix(0, int64(updatedAt)),
+		Error:     string(errStr),
+	}, nil
+}
+
+// fetchJobInfo reads the job from its bucket.
+func fetchJobInfo(id uint64, jobBucket kvdb.RBucket) (*Job, error) {
+	job, err := decodeJob(id, jobBucket.Get(jobInfoKey))
+	if err != nil {
+		return nil, err
+	}
+
+	// Events are indexed from zero, so the number of events follows from
+	// the index of the last one.
+	events := jobBucket.NestedReadBucket(jobEventsBucket)
+	if events == nil {
+		return job, nil
+	}
+
+	if k, _ := events.ReadCursor().Last(); k != nil {
+		job.NumEvents = byteOrder.Uint64(k) + 1
+	}
+
+	return job, nil
+}
+
+// createJob stores a new running job of the given kind and returns its id.
+func (s *store) createJob(kind string, now time.Time) (uint64, error) {
+	var id uint64
+	err := kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		jobs := tx.ReadWriteBucket(jobsBucket)
+
+		var err error
+		id, err = jobs.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		jobBucket, err := jobs.CreateBucket(jobKey(id))
+		if err != nil {
+			return err
+		}
+
+		_, err = jobBucket.CreateBucket(jobEventsBucket)
+		if err != nil {
+			return err
+		}
+
+		info, err := encodeJob(&Job{
+			Kind:      kind,
+			State:     StateRunning,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		return jobBucket.Put(jobInfoKey, info)
+	}, func() {
+		id = 0
+	})
+
+	return id, err
+}
+
+// addEvent stores a new event of the job and returns its index.
+func (s *store) addEvent(id uint64, payload []byte,
+	now time.Time) (uint64, error) {
+
+	var index uint64
+	err := kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		jobs := tx.ReadWriteBucket(jobsBucket)
+		jobBucket := jobs.NestedReadWriteBucket(jobKey(id))
+		if jobBucket == nil {
+			return ErrJobNotFound
+		}
+
+		events := jobBucket.NestedReadWriteBucket(jobEventsBucket)
+
+		// Event indexes start at zero, while the bucket sequence
+		// starts at one.
+		seq, err := events.NextSequence()
+		if err != nil {
+			return err
+		}
+		index = seq - 1
+
+		var b bytes.Buffer
+		var ts [8]byte
+		byteOrder.PutUint64(ts[:], uint64(now.UnixNano()))
+		b.Write(ts[:])
+		b.Write(payload)
+
+		return events.Put(jobKey(index), b.Bytes())
+	}, func() {
+		index = 0
+	})
+
+	return index, err
+}
+
+// updateState updates the state and error of the job.
+func (s *store) updateState(id uint64, state State, errStr string,
+	now time.Time) error {
+
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		jobs := tx.ReadWriteBucket(jobsBucket)
+		jobBucket := jobs.NestedReadWriteBucket(jobKey(id))
+		if jobBucket == nil {
+			return ErrJobNotFound
+		}
+
+		job, err := decodeJob(id, jobBucket.Get(jobInfoKey))
+		if err != nil {
+			return err
+		}
+
+		job.State = state
+		job.Error = errStr
+		job.UpdatedAt = now
+
+		info, err := encodeJob(job)
+		if err != nil {
+			return err
+		}
+
+		return jobBucket.Put(jobInfoKey, info)
+	}, func() {})
+}
+
+// interruptRunning marks all jobs that are still running as interrupted. It
+// is called on startup, as the operations of these jobs didn't survive the
+// restart.
+func (s *store) interruptRunning(now time.Time) (int, error) {
+	var numInterrupted int
+	err := kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		jobs := tx.ReadWriteBucket(jobsBucket)
+
+		var running []*Job
+		err := jobs.ForEach(func(k, _ []byte) error {
+			jobBucket := jobs.NestedReadBucket(k)
+			if jobBucket == nil {
+				return nil
+			}
+
+			job, err := decodeJob(
+				byteOrder.Uint64(k), jobBucket.Get(jobInfoKey),
+			)
+			if err != nil {
+				return err
+			}
+
+			if job.State == StateRunning {
+				running = append(running, job)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, job := range running {
+			job.State = StateInterrupted
+			job.UpdatedAt = now
+
+			info, err := encodeJob(job)
+			if err != nil {
+				return err
+			}
+
+			jobBucket := jobs.NestedReadWriteBucket(jobKey(job.ID))
+			if err := jobBucket.Put(jobInfoKey, info); err != nil {
+				return err
+			}
+		}
+
+		numInterrupted = len(running)
+
+		return nil
+	}, func() {
+		numInterrupted = 0
+	})
+
+	return numInterrupted, err
+}
+
+// fetchJob returns the job with the given id.
+func (s *store) fetchJob(id uint64) (*Job, error) {
+	var job *Job
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		jobBucket := tx.ReadBucket(jobsBucket).NestedReadBucket(
+			jobKey(id),
+		)
+		if jobBucket == nil {
+			return ErrJobNotFound
+		}
+
+		var err error
+		job, err = fetchJobInfo(id, jobBucket)
+
+		return err
+	}, func() {
+		job = nil
+	})
+
+	return job, err
+}
+
+// fetchJobs returns all stored jobs, ordered by their id.
+func (s *store) fetchJobs() ([]*Job, error) {
+	var jobs []*Job
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(jobsBucket)
+
+		return bucket.ForEach(func(k, _ []byte) error {
+			jobBucket := bucket.NestedReadBucket(k)
+			if jobBucket == nil {
+				return nil
+			}
+
+			job, err := fetchJobInfo(byteOrder.Uint64(k), jobBucket)
+			if err != nil {
+				return err
+			}
+
+			jobs = append(jobs, job)
+
+			return nil
+		})
+	}, func() {
+		jobs = nil
+	})
+
+	return jobs, err
+}
+
+// fetchEvents returns the events of the job, starting at the given index.
+func (s *store) fetchEvents(id uint64, fromIndex uint64) ([]*Event, error) {
+	var events []*Event
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		jobBucket := tx.ReadBucket(jobsBucket).NestedReadBucket(
+			jobKey(id),
+		)
+		if jobBucket == nil {
+			return ErrJobNotFound
+		}
+
+		cursor := jobBucket.NestedReadBucket(jobEventsBucket).
+			ReadCursor()
+
+		k, v := cursor.Seek(jobKey(fromIndex))
+		for ; k != nil; k, v = cursor.Next() {
+			if len(v) < 8 {
+				return errors.New("invalid job event")
+			}
+
+			events = append(events, &Event{
+				JobID: id,
+				Index: byteOrder.Uint64(k),
+				Time: time.Unix(
+					0, int64(byteOrder.Uint64(v[:8])),
+				),
+				Payload: append([]byte(nil), v[8:]...),
+			})
+		}
+
+		return nil
+	}, func() {
+		events = nil
+	})
+
+	return events, err
+}
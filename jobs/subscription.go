@@ -0,0 +1,133 @@
+package jobs
+
+import (
+	"sync"
+)
+
+// Subscription delivers the events of a job. The events channel is closed
+// once all events of a job that reached a final state were delivered, or when
+// the subscription is canceled.
+type Subscription struct {
+	// JobID is the id of the job the subscription is for.
+	JobID uint64
+
+	id     uint64
+	events chan *Event
+	wakeup chan struct{}
+
+	cancelOnce sync.Once
+	quit       chan struct{}
+}
+
+// Events returns the channel the events of the job are delivered on.
+func (s *Subscription) Events() <-chan *Event {
+	return s.events
+}
+
+// Cancel stops the delivery of events.
+func (s *Subscription) Cancel() {
+	s.cancelOnce.Do(func() {
+		close(s.quit)
+	})
+}
+
+// Subscribe returns a subscription that delivers the events of the job,
+// starting at the given event index. Events that were already emitted are
+// replayed from the database, which allows a client to resume the event
+// stream after disconnecting.
+func (m *Manager) Subscribe(id uint64, fromIndex uint64) (*Subscription,
+	error) {
+
+	select {
+	case <-m.quit:
+		return nil, ErrManagerShuttingDown
+	default:
+	}
+
+	if _, err := m.store.fetchJob(id); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.nextSubID++
+	sub := &Subscription{
+		JobID:  id,
+		id:     m.nextSubID,
+		events: make(chan *Event),
+		wakeup: make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+	}
+
+	if m.subscribers[id] == nil {
+		m.subscribers[id] = make(map[uint64]chan struct{})
+	}
+	m.subscribers[id][sub.id] = sub.wakeup
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go m.deliverEvents(sub, fromIndex)
+
+	return sub, nil
+}
+
+// deliverEvents reads the events of the job from the database and delivers
+// them to the subscriber, until the job reached a final state and all of its
+// events were delivered.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *Manager) deliverEvents(sub *Subscription, nextIndex uint64) {
+	defer m.wg.Done()
+	defer close(sub.events)
+	defer func() {
+		m.mu.Lock()
+		delete(m.subscribers[sub.JobID], sub.id)
+		if len(m.subscribers[sub.JobID]) == 0 {
+			delete(m.subscribers, sub.JobID)
+		}
+		m.mu.Unlock()
+	}()
+
+	for {
+		// Determine whether the job is done before reading the events,
+		// so that no events emitted before the job finished are
+		// missed.
+		m.mu.Lock()
+		_, running := m.running[sub.JobID]
+		m.mu.Unlock()
+
+		events, err := m.store.fetchEvents(sub.JobID, nextIndex)
+		if err != nil {
+			log.Errorf("Unable to fetch events of job %v: %v",
+				sub.JobID, err)
+
+			return
+		}
+
+		for _, event := range events {
+			select {
+			case sub.events <- event:
+				nextIndex = event.Index + 1
+
+			case <-sub.quit:
+				return
+
+			case <-m.quit:
+				return
+			}
+		}
+
+		if !running {
+			return
+		}
+
+		select {
+		case <-sub.wakeup:
+
+		case <-sub.quit:
+			return
+
+		case <-m.quit:
+			return
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package lnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/jobs"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// closeChannelJobKind is the kind of the jobs that run a channel close.
+const closeChannelJobKind = "closechannel"
+
+// jobCloseStream is a close channel update stream that persists every update
+// as an event of a job, instead of sending it to a client.
+type jobCloseStream struct {
+	// ServerStream is embedded to implement the rest of the stream
+	// interface. It is nil, so only Send and Context may be called.
+	grpc.ServerStream
+
+	ctx  context.Context
+	emit func(payload []byte) error
+}
+
+// A compile-time check to ensure jobCloseStream implements the close channel
+// update stream.
+var _ lnrpc.Lightning_CloseChannelServer = (*jobCloseStream)(nil)
+
+// Send serializes the update and emits it as an event of the job.
+func (s *jobCloseStream) Send(update *lnrpc.CloseStatusUpdate) error {
+	payload, err := proto.Marshal(update)
+	if err != nil {
+		return err
+	}
+
+	return s.emit(payload)
+}
+
+// Context returns the context of the job, which is canceled once the job is
+// canceled or lnd shuts down.
+func (s *jobCloseStream) Context() context.Context {
+	return s.ctx
+}
+
+// closeChannelJob submits the close as a job and sends its id as the only
+// update of the stream.
+func (r *rpcServer) closeChannelJob(in *lnrpc.CloseChannelRequest,
+	updateStream lnrpc.Lightning_CloseChannelServer) error {
+
+	if in.DryRun {
+		return fmt.Errorf("a dry run can't be run as a job")
+	}
+
+	jobReq := proto.Clone(in).(*lnrpc.CloseChannelRequest)
+	jobReq.AsJob = false
+
+	jobID, err := r.server.jobMgr.Submit(
+		closeChannelJobKind, func(ctx context.Context,
+			emit func(payload []byte) error) error {
+
+			return r.CloseChannel(jobReq, &jobCloseStream{
+				ctx:  ctx,
+				emit: emit,
+			})
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	rpcsLog.Infof("[closechannel] running close of ChannelPoint(%v) as "+
+		"job %v", in.ChannelPoint, jobID)
+
+	return updateStream.Send(&lnrpc.CloseStatusUpdate{
+		Update: &lnrpc.CloseStatusUpdate_Job{
+			Job: &lnrpc.JobUpdate{
+				JobId: jobID,
+			},
+		},
+	})
+}
+
+// marshallJobState converts a job state to its RPC representation.
+func marshallJobState(state jobs.State) (lnrpc.JobState, error) {
+	switch state {
+	case jobs.StateRunning:
+		return lnrpc.JobState_JOB_RUNNING, nil
+
+	case jobs.StateSucceeded:
+		return lnrpc.JobState_JOB_SUCCEEDED, nil
+
+	case jobs.StateFailed:
+		return lnrpc.JobState_JOB_FAILED, nil
+
+	case jobs.StateCanceled:
+		return lnrpc.JobState_JOB_CANCELED, nil
+
+	case jobs.StateInterrupted:
+		return lnrpc.JobState_JOB_INTERRUPTED, nil
+
+	default:
+		return 0, fmt.Errorf("unknown job state: %v", state)
+	}
+}
+
+// marshallJob converts a job to its RPC representation.
+func marshallJob(job *jobs.Job) (*lnrpc.Job, error) {
+	state, err := marshallJobState(job.State)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.Job{
+		Id:        job.ID,
+		Kind:      job.Kind,
+		State:     state,
+		CreatedAt: job.CreatedAt.Unix(),
+		UpdatedAt: job.UpdatedAt.Unix(),
+		Error:     job.Error,
+		NumEvents: job.NumEvents,
+	}, nil
+}
+
+// jobRPCError maps the errors of the job manager to gRPC status errors.
+func jobRPCError(err error) error {
+	switch {
+	case errors.Is(err, jobs.ErrJobNotFound):
+		return status.Error(codes.NotFound, err.Error())
+
+	case errors.Is(err, jobs.ErrJobNotRunning):
+		return status.Error(codes.FailedPrecondition, err.Error())
+
+	case errors.Is(err, jobs.ErrManagerShuttingDown):
+		return status.Error(codes.Unavailable, err.Error())
+
+	default:
+		return err
+	}
+}
+
+// ListJobs returns the jobs of long-running operations, ordered by their id.
+func (r *rpcServer) ListJobs(_ context.Context,
+	_ *lnrpc.ListJobsRequest) (*lnrpc.ListJobsResponse, error) {
+
+	allJobs, err := r.server.jobMgr.Jobs()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListJobsResponse{}
+	for _, job := range allJobs {
+		rpcJob, err := marshallJob(job)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Jobs = append(resp.Jobs, rpcJob)
+	}
+
+	return resp, nil
+}
+
+// SubscribeJobEvents streams the events of a job, starting at the given event
+// index. The stream ends once the job reached a final state and all its
+// events were sent.
+func (r *rpcServer) SubscribeJobEvents(req *lnrpc.SubscribeJobEventsRequest,
+	updateStream lnrpc.Lightning_SubscribeJobEventsServer) error {
+
+	sub, err := r.server.jobMgr.Subscribe(req.JobId, req.FromIndex)
+	if err != nil {
+		return jobRPCError(err)
+	}
+	defer sub.Cancel()
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				return nil
+			}
+
+			err := updateStream.Send(&lnrpc.JobEvent{
+				JobId:     event.JobID,
+				Index:     event.Index,
+				Timestamp: event.Time.Unix(),
+				Payload:   event.Payload,
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return updateStream.Context().Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// CancelJob cancels the operation of a running job.
+func (r *rpcServer) CancelJob(_ context.Context,
+	req *lnrpc.CancelJobRequest) (*lnrpc.CancelJobResponse, error) {
+
+	if err := r.server.jobMgr.Cancel(req.JobId); err != nil {
+		return nil, jobRPCError(err)
+	}
+
+	rpcsLog.Infof("Canceled job %v", req.JobId)
+
+	return &lnrpc.CancelJobResponse{}, nil
+}
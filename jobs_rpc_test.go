@@ -0,0 +1,146 @@
+package lnd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/jobs"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestMarshallJob tests that a job is returned over RPC with its state and
+// timestamps.
+func TestMarshallJob(t *testing.T) {
+	t.Parallel()
+
+	job := &jobs.Job{
+		ID:        3,
+		Kind:      closeChannelJobKind,
+		State:     jobs.StateInterrupted,
+		CreatedAt: time.Unix(1_700_000_000, 0),
+		UpdatedAt: time.Unix(1_700_000_060, 0),
+		Error:     "boom",
+		NumEvents: 2,
+	}
+
+	rpcJob, err := marshallJob(job)
+	require.NoError(t, err)
+	require.Equal(t, &lnrpc.Job{
+		Id:        3,
+		Kind:      closeChannelJobKind,
+		State:     lnrpc.JobState_JOB_INTERRUPTED,
+		CreatedAt: 1_700_000_000,
+		UpdatedAt: 1_700_000_060,
+		Error:     "boom",
+		NumEvents: 2,
+	}, rpcJob)
+
+	job.State = jobs.State(100)
+	_, err = marshallJob(job)
+	require.ErrorContains(t, err, "unknown job state")
+}
+
+// TestJobRPCError tests that the errors of the job manager are mapped to the
+// matching gRPC status codes.
+func TestJobRPCError(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t, codes.NotFound,
+		status.Code(jobRPCError(jobs.ErrJobNotFound)),
+	)
+	require.Equal(
+		t, codes.FailedPrecondition,
+		status.Code(jobRPCError(jobs.ErrJobNotRunning)),
+	)
+	require.Equal(
+		t, codes.Unavailable,
+		status.Code(jobRPCError(jobs.ErrManagerShuttingDown)),
+	)
+}
+
+// TestJobCloseStream tests that the updates sent on the stream of a close
+// that is run as a job are persisted as events of the job, and that the
+// stream context is canceled when the job is canceled.
+func TestJobCloseStream(t *testing.T) {
+	t.Parallel()
+
+	db, cleanup, err := kvdb.GetTestBackend(t.TempDir(), "jobs")
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+
+	mgr, err := jobs.NewManager(&jobs.Config{
+		DB:    db,
+		Clock: clock.NewDefaultClock(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, mgr.Start())
+	t.Cleanup(func() {
+		require.NoError(t, mgr.Stop())
+	})
+
+	updates := []*lnrpc.CloseStatusUpdate{{
+		Update: &lnrpc.CloseStatusUpdate_CloseInstant{
+			CloseInstant: &lnrpc.InstantUpdate{},
+		},
+	}, {
+		Update: &lnrpc.CloseStatusUpdate_ClosePending{
+			ClosePending: &lnrpc.PendingUpdate{
+				Txid: []byte{1, 2, 3},
+			},
+		},
+	}}
+
+	sent := make(chan struct{})
+	jobID, err := mgr.Submit(closeChannelJobKind, func(ctx context.Context,
+		emit func(payload []byte) error) error {
+
+		stream := &jobCloseStream{ctx: ctx, emit: emit}
+		for _, update := range updates {
+			if err := stream.Send(update); err != nil {
+				return err
+			}
+		}
+		close(sent)
+
+		<-stream.Context().Done()
+
+		return stream.Context().Err()
+	})
+	require.NoError(t, err)
+
+	select {
+	case <-sent:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("updates not sent")
+	}
+
+	require.NoError(t, mgr.Cancel(jobID))
+
+	sub, err := mgr.Subscribe(jobID, 0)
+	require.NoError(t, err)
+	defer sub.Cancel()
+
+	var received []*lnrpc.CloseStatusUpdate
+	for event := range sub.Events() {
+		update := &lnrpc.CloseStatusUpdate{}
+		require.NoError(t, proto.Unmarshal(event.Payload, update))
+		received = append(received, update)
+	}
+
+	require.Len(t, received, len(updates))
+	for i := range updates {
+		require.True(t, proto.Equal(updates[i], received[i]))
+	}
+
+	job, err := mgr.Job(jobID)
+	require.NoError(t, err)
+	require.Equal(t, jobs.StateCanceled, job.State)
+}
@@ -0,0 +1,129 @@
+package lnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
+)
+
+var (
+	// errKeysendInboxDisabled is returned when the keysend inbox is used
+	// without being enabled.
+	errKeysendInboxDisabled = errors.New("keysend inbox not enabled, " +
+		"set invoices.keysendinbox to enable it")
+)
+
+// marshallInboxMessage converts an inbox message to its RPC form.
+func marshallInboxMessage(msg *invoicesrpc.InboxMessage) *lnrpc.InboxMessage {
+	customRecords := make(map[uint64][]byte, len(msg.CustomRecords))
+	for key, value := range msg.CustomRecords {
+		customRecords[key] = value
+	}
+
+	return &lnrpc.InboxMessage{
+		Id:            msg.ID,
+		PaymentHash:   msg.PaymentHash[:],
+		AmtPaidMsat:   int64(msg.AmtPaid),
+		ReceivedAt:    msg.ReceivedAt.Unix(),
+		Text:          msg.Text,
+		Sender:        msg.Sender,
+		CustomRecords: customRecords,
+		Acked:         msg.Acked,
+	}
+}
+
+// ListInboxMessages returns the messages received with keysend payments that
+// match the query.
+func (r *rpcServer) ListInboxMessages(_ context.Context,
+	req *lnrpc.ListInboxMessagesRequest) (*lnrpc.ListInboxMessagesResponse,
+	error) {
+
+	if r.server.keysendInbox == nil {
+		return nil, errKeysendInboxDisabled
+	}
+
+	msgs := r.server.keysendInbox.Messages(&invoicesrpc.InboxQuery{
+		IndexOffset: req.IndexOffset,
+		MaxMessages: req.MaxMessages,
+		UnackedOnly: req.UnackedOnly,
+	})
+
+	resp := &lnrpc.ListInboxMessagesResponse{
+		Messages: make([]*lnrpc.InboxMessage, 0, len(msgs)),
+	}
+	for _, msg := range msgs {
+		resp.Messages = append(resp.Messages, marshallInboxMessage(msg))
+	}
+
+	return resp, nil
+}
+
+// AckInboxMessages acknowledges the inbox messages with the given IDs, so they
+// are no longer returned when only unacknowledged messages are listed.
+func (r *rpcServer) AckInboxMessages(_ context.Context,
+	req *lnrpc.AckInboxMessagesRequest) (*lnrpc.AckInboxMessagesResponse,
+	error) {
+
+	if r.server.keysendInbox == nil {
+		return nil, errKeysendInboxDisabled
+	}
+
+	if len(req.Ids) == 0 {
+		return nil, errors.New("no message ids given")
+	}
+
+	if err := r.server.keysendInbox.Ack(req.Ids); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.AckInboxMessagesResponse{}, nil
+}
+
+// SubscribeInbox pushes every new message of the keysend inbox to the client
+// until the stream is closed. Messages that were received while the client
+// wasn't subscribed can be caught up with ListInboxMessages.
+func (r *rpcServer) SubscribeInbox(_ *lnrpc.SubscribeInboxRequest,
+	updateStream lnrpc.Lightning_SubscribeInboxServer) error {
+
+	if r.server.keysendInbox == nil {
+		return errKeysendInboxDisabled
+	}
+
+	client, err := r.server.keysendInbox.Subscribe()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	ctx := updateStream.Context()
+	for {
+		select {
+		case update := <-client.Updates():
+			msg, ok := update.(*invoicesrpc.InboxMessage)
+			if !ok {
+				return fmt.Errorf("unexpected inbox update %T",
+					update)
+			}
+
+			err := updateStream.Send(marshallInboxMessage(msg))
+			if err != nil {
+				return err
+			}
+
+		case <-client.Quit():
+			return errors.New("keysend inbox shutting down")
+
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return ctx.Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package lnd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshallInboxMessage tests the conversion of inbox messages to their
+// RPC form.
+func TestMarshallInboxMessage(t *testing.T) {
+	t.Parallel()
+
+	msg := &invoicesrpc.InboxMessage{
+		ID:          7,
+		PaymentHash: lntypes.Hash{1, 2, 3},
+		AmtPaid:     1500,
+		ReceivedAt:  time.Unix(1_700_000_000, 0),
+		Text:        "hello",
+		Sender:      []byte{4, 5},
+		CustomRecords: record.CustomSet{
+			record.CustomTypeStart: []byte("hello"),
+		},
+		Acked: true,
+	}
+
+	require.Equal(t, &lnrpc.InboxMessage{
+		Id:          7,
+		PaymentHash: msg.PaymentHash[:],
+		AmtPaidMsat: 1500,
+		ReceivedAt:  1_700_000_000,
+		Text:        "hello",
+		Sender:      []byte{4, 5},
+		CustomRecords: map[uint64][]byte{
+			record.CustomTypeStart: []byte("hello"),
+		},
+		Acked: true,
+	}, marshallInboxMessage(msg))
+}
+
+// TestKeysendInboxDisabled tests that the inbox RPCs fail if the inbox isn't
+// enabled.
+func TestKeysendInboxDisabled(t *testing.T) {
+	t.Parallel()
+
+	r := &rpcServer{
+		server: &server{},
+	}
+
+	_, err := r.ListInboxMessages(
+		context.Background(), &lnrpc.ListInboxMessagesRequest{},
+	)
+	require.ErrorIs(t, err, errKeysendInboxDisabled)
+
+	_, err = r.AckInboxMessages(
+		context.Background(), &lnrpc.AckInboxMessagesRequest{
+			Ids: []uint64{1},
+		},
+	)
+	require.ErrorIs(t, err, errKeysendInboxDisabled)
+}
@@ -0,0 +1,52 @@
+package lncfg
+
+import "fmt"
+
+// DefaultAcceptPolicyOrder is the default order in which the channel accept
+// policies are evaluated.
+var DefaultAcceptPolicyOrder = []string{
+	"peerlimit", "minsize", "reputation", "feerate", "rpc",
+}
+
+//nolint:lll
+type AcceptPolicy struct {
+	Order []string `long:"order" description:"The order in which the channel accept policies are evaluated. Must name each of peerlimit, minsize, reputation, feerate and rpc exactly once. Can be specified multiple times, once per policy."`
+
+	MinChanSize uint64 `long:"minchansize" description:"The minimum size in satoshis of incoming channels accepted by the minsize policy, which can be changed at runtime unlike --minchansize. Setting this value to 0 disables the policy."`
+
+	PeerReputation bool `long:"peerreputation" description:"Enable the reputation policy, which rejects channels of peers whose existing channels have a forwarding reputation below minpeerreputation."`
+
+	MinPeerReputation int64 `long:"minpeerreputation" description:"The minimum forwarding reputation in milli-satoshis of the existing channels of a peer accepted by the reputation policy."`
+
+	MaxFeeRate uint64 `long:"maxfeerate" description:"The maximum on-chain fee rate in sat/vbyte at which incoming channels are accepted by the feerate policy, as closing them would be costly. Setting this value to 0 disables the policy."`
+
+	MaxDecisions int `long:"maxdecisions" description:"The number of recent channel accept decisions that are kept for inspection."`
+}
+
+// Validate checks the values configured for the channel accept policies.
+func (a *AcceptPolicy) Validate() error {
+	if len(a.Order) != len(DefaultAcceptPolicyOrder) {
+		return fmt.Errorf("order: must name all %d policies, got %d",
+			len(DefaultAcceptPolicyOrder), len(a.Order))
+	}
+
+	seen := make(map[string]struct{}, len(a.Order))
+	for _, name := range a.Order {
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("order: duplicate policy %v", name)
+		}
+		seen[name] = struct{}{}
+	}
+
+	for _, name := range DefaultAcceptPolicyOrder {
+		if _, ok := seen[name]; !ok {
+			return fmt.Errorf("order: missing policy %v", name)
+		}
+	}
+
+	if a.MaxDecisions < 0 {
+		return fmt.Errorf("maxdecisions: must be non-negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package lncfg
+
+// Accounts holds the configuration of the accounts subsystem, which splits
+// the off-chain balance of the node between several applications.
+//
+//nolint:lll
+type Accounts struct {
+	Active bool `long:"active" description:"Enable accounts. Macaroons that are bound to an account can only spend the balance of their account, and are restricted to the RPCs that are accounted for."`
+}
+
+// DefaultAccounts returns the default configuration of the accounts, which
+// are disabled.
+func DefaultAccounts() *Accounts {
+	return &Accounts{}
+}
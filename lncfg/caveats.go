@@ -0,0 +1,17 @@
+package lncfg
+
+// Caveats holds the configuration of the custom macaroon caveats that lnd
+// enforces itself.
+//
+//nolint:lll
+type Caveats struct {
+	MaxAmountPerDay bool `long:"maxamountperday" description:"Enforce the max-amount-per-day caveat. Macaroons with this caveat can spend at most the amount in satoshis of its condition within 24 hours, counting payments including their fee limits, on-chain sends and channel openings. Methods that spend funds which can't be metered are rejected."`
+
+	ChannelAllowlist bool `long:"channelallowlist" description:"Enforce the channel-allowlist caveat. Macaroons with this caveat can only close, abandon and update the channels in the comma separated short channel IDs of its condition, and can only make payments that are restricted to outgoing channels of the list."`
+}
+
+// DefaultCaveats returns the default configuration of the custom caveats,
+// which aren't enforced.
+func DefaultCaveats() *Caveats {
+	return &Caveats{}
+}
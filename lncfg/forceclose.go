@@ -0,0 +1,31 @@
+package lncfg
+
+import "fmt"
+
+// MaxDustHtlcDelay is the max number of blocks by which the force close for
+// dust HTLCs can be delayed. Delaying it for too long risks the incoming
+// channel of a forwarded dust HTLC to be force closed by our peer instead.
+const MaxDustHtlcDelay = 144
+
+//nolint:lll
+type ForceClose struct {
+	MinHtlcAmt uint64 `long:"minhtlcamt" description:"The minimum amount in satoshis of an expiring HTLC to trigger a force close of its channel. Smaller HTLCs are only resolved on-chain if the channel is force closed for another reason. Setting this value to 0 lets all HTLCs trigger a force close."`
+
+	DustHtlcDelay uint32 `long:"dusthtlcdelay" description:"The number of blocks by which the force close is delayed for expiring HTLCs that are dust on the commitment, and thus can't be resolved on-chain."`
+
+	CoopHtlcSweep bool `long:"coophtlcsweep" description:"Ask the peer of a force closed channel to co-sign the sweep of expired HTLCs directly from our commitment, saving the fees and the CSV delay of the second-level timeout transaction, and co-sign such sweeps for our peers in turn. The protocol uses custom messages and falls back to the second-level transaction if the peer doesn't respond."`
+
+	PrioritizeClaims bool `long:"prioritizeclaims" description:"Prioritize the on-chain claims of all channels when many of them are resolved at the same time. The wallet funds that pay the fees of the claims are handed to the claims with the most value at risk first, and claims that cost more to sweep than they're worth at the current fee rate are deferred until the fees drop."`
+
+	DelegateAnchorBumps bool `long:"delegateanchorbumps" description:"Delegate the fee bumps of our force closed commitment transactions to an external service instead of the sweeper, which is useful for nodes whose wallets are intentionally unfunded. The service receives the commitment txid and a signed template that spends the anchor output, to which it adds its own inputs and outputs to fund the child transaction."`
+}
+
+// Validate checks the values configured for the force close policy.
+func (f *ForceClose) Validate() error {
+	if f.DustHtlcDelay > MaxDustHtlcDelay {
+		return fmt.Errorf("dusthtlcdelay: %v exceeds maximum: %v",
+			f.DustHtlcDelay, MaxDustHtlcDelay)
+	}
+
+	return nil
+}
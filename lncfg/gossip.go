@@ -1,8 +1,12 @@
 package lncfg
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/discovery"
 	"github.com/lightningnetwork/lnd/routing/route"
 )
@@ -18,10 +22,29 @@ type Gossip struct {
 	ChannelUpdateInterval time.Duration `long:"channel-update-interval" description:"The interval used to determine how often lnd should allow a burst of new updates for a specific channel and direction."`
 
 	SubBatchDelay time.Duration `long:"sub-batch-delay" description:"The duration to wait before sending the next announcement batch if there are multiple. Use a small value if there are a lot announcements and they need to be broadcast quickly."`
+
+	PeerMsgRate float64 `long:"peer-msg-rate" description:"The number of gossip messages per second lnd processes from a peer with a neutral reputation once the graph is synced. Peers that send useful gossip are allowed up to twice this rate, while peers that send invalid or excess gossip are throttled down to a tenth of it and eventually disconnected. Set to 0 to disable the per-peer rate limit."`
+
+	PeerMsgBurst int `long:"peer-msg-burst" description:"The number of gossip messages a peer can send at once before it is rate limited."`
+
+	SnapshotSource string `long:"snapshot-source" description:"A file path or http(s) URL of a signed graph snapshot, as exported by another node. If our channel graph has no channels yet, it is bootstrapped from the snapshot on startup before we connect to peers and sync gossip. Requires at least one snapshot-signer."`
+
+	SnapshotSignersRaw []string `long:"snapshot-signer" description:"The hex-encoded public key of a node that is trusted to sign graph snapshots. The flag can be specified multiple times to trust multiple nodes."`
+
+	SnapshotSigners []*btcec.PublicKey
 }
 
-// Parse the pubkeys for the pinned syncers.
+// Parse the pubkeys for the pinned syncers and the graph snapshot signers, and
+// check the per-peer rate limit.
 func (g *Gossip) Parse() error {
+	if g.PeerMsgRate < 0 {
+		return errors.New("gossip.peer-msg-rate must not be negative")
+	}
+	if g.PeerMsgRate > 0 && g.PeerMsgBurst <= 0 {
+		return errors.New("gossip.peer-msg-burst must be positive if " +
+			"gossip.peer-msg-rate is set")
+	}
+
 	pinnedSyncers := make(discovery.PinnedSyncers)
 	for _, pubkeyStr := range g.PinnedSyncersRaw {
 		vertex, err := route.NewVertexFromStr(pubkeyStr)
@@ -33,5 +56,28 @@ func (g *Gossip) Parse() error {
 
 	g.PinnedSyncers = pinnedSyncers
 
+	var snapshotSigners []*btcec.PublicKey
+	for _, pubkeyStr := range g.SnapshotSignersRaw {
+		pubkeyBytes, err := hex.DecodeString(pubkeyStr)
+		if err != nil {
+			return fmt.Errorf("invalid gossip.snapshot-signer: %w",
+				err)
+		}
+
+		pubkey, err := btcec.ParsePubKey(pubkeyBytes)
+		if err != nil {
+			return fmt.Errorf("invalid gossip.snapshot-signer: %w",
+				err)
+		}
+		snapshotSigners = append(snapshotSigners, pubkey)
+	}
+
+	g.SnapshotSigners = snapshotSigners
+
+	if g.SnapshotSource != "" && len(g.SnapshotSigners) == 0 {
+		return errors.New("gossip.snapshot-source requires at least " +
+			"one gossip.snapshot-signer")
+	}
+
 	return nil
 }
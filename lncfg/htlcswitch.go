@@ -16,6 +16,18 @@ var (
 //nolint:lll
 type Htlcswitch struct {
 	MailboxDeliveryTimeout time.Duration `long:"mailboxdeliverytimeout" description:"The timeout value when delivering HTLCs to a channel link. Setting this value too small will result in local payment failures if large number of payments are sent over a short period."`
+
+	StuckHtlcThreshold time.Duration `long:"stuckhtlcthreshold" description:"The duration after which an HTLC that is pending on one of our outgoing channels is reported as stuck. Setting this value to 0 disables the detection of stuck HTLCs."`
+
+	StuckHtlcReconnect bool `long:"stuckhtlcreconnect" description:"Reconnect the peer of the outgoing channel of a stuck HTLC, to reestablish the channel state with it."`
+
+	MailboxMaxAdds int `long:"mailboxmaxadds" description:"The maximum number of forwarded HTLCs that are queued for a single channel link. Setting this value to 0 disables the limit."`
+
+	MailboxOverflowPolicy string `long:"mailboxoverflowpolicy" description:"How a forwarded HTLC is handled once the queue of its outgoing channel link is full. 'fail' fails the new HTLC back, 'drop' fails back the oldest queued HTLC instead." choice:"fail" choice:"drop"`
+
+	MailboxMaxInFlightAdds int `long:"mailboxmaxinflightadds" description:"The maximum number of HTLCs that are delivered to the channel links, but not yet processed by them, across all channels. Once the limit is reached, HTLCs are delivered fairly across the channels. Setting this value to 0 disables the limit."`
+
+	ForwardingRulesFile string `long:"forwardingrulesfile" description:"The path to a JSON file with the forwarding rules that are applied at startup, in the format of the SetForwardingRules RPC request. The rules can be replaced at runtime with that RPC."`
 }
 
 // Validate checks the values configured for htlcswitch.
@@ -30,5 +42,23 @@ func (h *Htlcswitch) Validate() error {
 			MaxMailboxDeliveryTimeout)
 	}
 
+	if h.StuckHtlcThreshold < 0 {
+		return fmt.Errorf("stuckhtlcthreshold must not be negative")
+	}
+
+	if h.StuckHtlcReconnect && h.StuckHtlcThreshold == 0 {
+		return fmt.Errorf("stuckhtlcreconnect requires " +
+			"stuckhtlcthreshold to be set")
+	}
+
+	if h.MailboxMaxAdds < 0 {
+		return fmt.Errorf("mailboxmaxadds must not be negative")
+	}
+
+	if h.MailboxMaxInFlightAdds < 0 {
+		return fmt.Errorf("mailboxmaxinflightadds must not be " +
+			"negative")
+	}
+
 	return nil
 }
@@ -0,0 +1,82 @@
+package lncfg
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// InboundPolicy holds the requirements for the channels that peers open to
+// us, in addition to the minimum channel size.
+//
+//nolint:lll
+type InboundPolicy struct {
+	CommitmentTypes []string `long:"commitment-type" description:"A commitment type peers may open channels with. Can be specified multiple times. One of legacy, static-remote-key, anchors, script-enforced-lease, simple-taproot or simple-taproot-overlay. If none is set, all commitment types are allowed."`
+
+	RequiredFeatures []uint16 `long:"required-feature" description:"A feature bit peers must signal, as optional or required, to open channels with us. Can be specified multiple times."`
+
+	Publish bool `long:"publish" description:"Publish the inbound channel requirements, including the minimum channel size, in the node announcement, so that peers can skip channel opens that would be rejected."`
+}
+
+// commitmentFormats are the commitment formats that can be configured.
+var commitmentFormats = []lnwire.CommitmentFormat{
+	lnwire.CommitmentFormatLegacy,
+	lnwire.CommitmentFormatStaticRemoteKey,
+	lnwire.CommitmentFormatAnchors,
+	lnwire.CommitmentFormatScriptEnforcedLease,
+	lnwire.CommitmentFormatSimpleTaproot,
+	lnwire.CommitmentFormatSimpleTaprootOverlay,
+}
+
+// commitmentFormat returns the commitment format of the given name.
+func commitmentFormat(name string) (lnwire.CommitmentFormat, bool) {
+	for _, format := range commitmentFormats {
+		if format.String() == name {
+			return format, true
+		}
+	}
+
+	return 0, false
+}
+
+// Validate checks the configured commitment types.
+func (p *InboundPolicy) Validate() error {
+	for _, name := range p.CommitmentTypes {
+		if _, ok := commitmentFormat(name); !ok {
+			return fmt.Errorf("inboundpolicy.commitment-type: "+
+				"unknown commitment type %q", name)
+		}
+	}
+
+	return nil
+}
+
+// Enabled returns true if any requirement beyond the minimum channel size is
+// configured, or if the requirements are published.
+func (p *InboundPolicy) Enabled() bool {
+	return len(p.CommitmentTypes) != 0 || len(p.RequiredFeatures) != 0 ||
+		p.Publish
+}
+
+// Policy returns the configured requirements as an inbound channel policy
+// with the given minimum channel size.
+func (p *InboundPolicy) Policy(
+	minChanSize btcutil.Amount) *lnwire.InboundChanPolicy {
+
+	policy := &lnwire.InboundChanPolicy{
+		MinChanSize:      minChanSize,
+		RequiredFeatures: lnwire.NewRawFeatureVector(),
+	}
+
+	for _, name := range p.CommitmentTypes {
+		format, _ := commitmentFormat(name)
+		policy.AllowCommitmentFormat(format)
+	}
+
+	for _, bit := range p.RequiredFeatures {
+		policy.RequiredFeatures.Set(lnwire.FeatureBit(bit))
+	}
+
+	return policy
+}
@@ -1,5 +1,11 @@
 package lncfg
 
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
 const (
 	// DefaultHoldInvoiceExpiryDelta defines the number of blocks before the
 	// expiry height of a hold invoice's htlc that lnd will automatically
@@ -8,6 +14,13 @@ const (
 	// force closes.
 	DefaultHoldInvoiceExpiryDelta = DefaultIncomingBroadcastDelta + 2
 
+	// DefaultHoldWarningDelta defines the number of blocks before the
+	// expiry height of a hold invoice's htlc that lnd will warn about the
+	// invoice still being unresolved. This gives applications roughly an
+	// hour to settle or cancel the invoice before it is canceled
+	// automatically.
+	DefaultHoldWarningDelta = DefaultHoldInvoiceExpiryDelta + 6
+
 	// DefaultMinNumRealBlindedPathHops is the minimum number of _real_
 	// hops to include in a blinded payment path. This doesn't include our
 	// node (the destination node), so if the minimum is 1, then the path
@@ -32,6 +45,10 @@ const (
 	// used to decrease certain blinded hop policy values in order to add a
 	// probing buffer.
 	DefaultBlindedPathPolicyDecreaseMultiplier = 0.9
+
+	// DefaultWebhookMaxAttempts is the default number of times we try to
+	// deliver an invoice event to the webhook.
+	DefaultWebhookMaxAttempts = 5
 )
 
 // Invoices holds the configuration options for invoices.
@@ -39,6 +56,18 @@ const (
 //nolint:lll
 type Invoices struct {
 	HoldExpiryDelta uint32 `long:"holdexpirydelta" description:"The number of blocks before a hold invoice's htlc expires that the invoice should be canceled to prevent a force close. Force closes will not be prevented if this value is not greater than DefaultIncomingBroadcastDelta."`
+
+	HoldWarningDelta uint32 `long:"holdwarningdelta" description:"The number of blocks before a hold invoice's htlc expires that a warning is logged and sent to subscribers if the invoice is still accepted. Must be greater than holdexpirydelta to have an effect, set to 0 to disable warnings."`
+
+	Stateless bool `long:"stateless" description:"If set, payments to stateless invoices that were issued with the secret derived from the key at m/1017'/coinType'/7'/0/1 are settled without the invoice having been added to lnd."`
+
+	WebhookURL string `long:"webhookurl" description:"If set, new and settled invoices are posted to this http(s) endpoint."`
+
+	WebhookSecret string `long:"webhooksecret" description:"The secret used to sign webhook requests with HMAC-SHA256. Required if webhookurl is set."`
+
+	WebhookMaxAttempts uint32 `long:"webhookmaxattempts" description:"The number of times the delivery of an invoice event to the webhook is attempted before it is moved to the dead letter queue."`
+
+	KeysendInbox bool `long:"keysendinbox" description:"If set, the custom records received with keysend payments are kept as a queryable inbox of messages. Building the inbox requires scanning all invoices on startup."`
 }
 
 // Validate checks that the various invoice config options are sane.
@@ -56,5 +85,38 @@ func (i *Invoices) Validate() error {
 			i.HoldExpiryDelta, DefaultIncomingBroadcastDelta)
 	}
 
+	// Warnings are sent before the invoice is canceled, so they are
+	// ineffective if they aren't sent earlier than the cancellation.
+	if i.HoldWarningDelta != 0 && i.HoldWarningDelta <= i.HoldExpiryDelta {
+		log.Warnf("Invoice hold warning delta: %v <= hold expiry "+
+			"delta: %v, no warnings will be sent for accepted "+
+			"hold invoices", i.HoldWarningDelta,
+			i.HoldExpiryDelta)
+	}
+
+	if i.WebhookURL == "" {
+		return nil
+	}
+
+	webhookURL, err := url.Parse(i.WebhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid invoices.webhookurl: %w", err)
+	}
+
+	if webhookURL.Scheme != "http" && webhookURL.Scheme != "https" {
+		return fmt.Errorf("invoices.webhookurl must be an http(s) "+
+			"url, got scheme %q", webhookURL.Scheme)
+	}
+
+	if i.WebhookSecret == "" {
+		return errors.New("invoices.webhooksecret must be set if " +
+			"invoices.webhookurl is set")
+	}
+
+	if i.WebhookMaxAttempts == 0 {
+		return errors.New("invoices.webhookmaxattempts must be " +
+			"positive")
+	}
+
 	return nil
 }
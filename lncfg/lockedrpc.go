@@ -0,0 +1,51 @@
+package lncfg
+
+import (
+	"fmt"
+)
+
+const (
+	// LockedRPCGetInfo is the name of the GetInfo RPC in the list of RPCs
+	// that are served while the wallet is locked.
+	LockedRPCGetInfo = "getinfo"
+
+	// LockedRPCDescribeGraph is the name of the DescribeGraph RPC in the
+	// list of RPCs that are served while the wallet is locked.
+	LockedRPCDescribeGraph = "describegraph"
+
+	// LockedRPCForwardingHistory is the name of the ForwardingHistory RPC
+	// in the list of RPCs that are served while the wallet is locked.
+	LockedRPCForwardingHistory = "forwardinghistory"
+)
+
+// LockedRPC holds the configuration of the read-only RPCs that are served from
+// the databases while the wallet is still locked.
+//
+//nolint:lll
+type LockedRPC struct {
+	Methods []string `long:"method" description:"A read-only RPC that is served from the databases while the wallet is locked. Macaroons can't be validated before the wallet is unlocked, so these calls are authenticated with the token in tokenpath instead, which clients send in the lockedrpc-token request metadata. Can be specified multiple times." choice:"getinfo" choice:"describegraph" choice:"forwardinghistory"`
+
+	TokenPath string `long:"tokenpath" description:"Path to the file that holds the hex encoded token which authenticates the locked RPCs. A random token is created if the file doesn't exist. Defaults to lockedrpc.token in the network directory."`
+}
+
+// Validate checks the values configured for the locked RPCs.
+func (l *LockedRPC) Validate() error {
+	for _, method := range l.Methods {
+		switch method {
+		case LockedRPCGetInfo, LockedRPCDescribeGraph,
+			LockedRPCForwardingHistory:
+
+		default:
+			return fmt.Errorf("RPC %v can't be served while the "+
+				"wallet is locked", method)
+		}
+	}
+
+	return nil
+}
+
+// DefaultLockedRPC returns the default configuration of the locked RPCs,
+// which doesn't serve any RPC while the wallet is locked.
+func DefaultLockedRPC() *LockedRPC {
+	return &LockedRPC{}
+}
@@ -70,6 +70,17 @@ type ProtocolOptions struct {
 	// NoRouteBlindingOption disables forwarding of payments in blinded routes.
 	NoRouteBlindingOption bool `long:"no-route-blinding" description:"do not forward payments that are a part of a blinded route"`
 
+	// NoQuiescenceOption disables the quiescence protocol, which locks
+	// the channel state ahead of protocols like splicing.
+	NoQuiescenceOption bool `long:"no-quiescence" description:"do not signal support for the quiescence protocol"`
+
+	// NoExperimentalEndorsementOption disables experimental endorsement.
+	NoExperimentalEndorsementOption bool `long:"no-experimental-endorsement" description:"do not forward experimental endorsement signals"`
+
+	// GossipV2Option enables the experimental handling of the taproot
+	// channel gossip messages (gossip 1.75).
+	GossipV2Option bool `long:"gossip-v2" description:"validate, store and relay the experimental taproot channel gossip messages"`
+
 	// CustomMessage allows the custom message APIs to handle messages with
 	// the provided protocol numbers, which fall outside the custom message
 	// number range.
@@ -127,6 +138,23 @@ func (l *ProtocolOptions) NoRouteBlinding() bool {
 	return l.NoRouteBlindingOption
 }
 
+// NoQuiescence returns true if the quiescence protocol is disabled.
+func (l *ProtocolOptions) NoQuiescence() bool {
+	return l.NoQuiescenceOption
+}
+
+// NoExpEndorsement returns true if experimental endorsement should be
+// disabled.
+func (l *ProtocolOptions) NoExpEndorsement() bool {
+	return l.NoExperimentalEndorsementOption
+}
+
+// GossipV2 returns true if the experimental taproot channel gossip messages
+// should be handled.
+func (l *ProtocolOptions) GossipV2() bool {
+	return l.GossipV2Option
+}
+
 // CustomMessageOverrides returns the set of protocol messages that we override
 // to allow custom handling.
 func (l ProtocolOptions) CustomMessageOverrides() []uint16 {
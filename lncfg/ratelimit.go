@@ -0,0 +1,74 @@
+package lncfg
+
+import (
+	"fmt"
+)
+
+const (
+	// DefaultRPCReadRate is the default number of read-only RPC calls
+	// per second that are allowed per macaroon.
+	DefaultRPCReadRate = 100
+
+	// DefaultRPCReadBurst is the default number of read-only RPC calls that
+	// can be made at once per macaroon.
+	DefaultRPCReadBurst = 200
+
+	// DefaultRPCWriteRate is the default number of RPC calls per second
+	// that modify state and are allowed per macaroon.
+	DefaultRPCWriteRate = 10
+
+	// DefaultRPCWriteBurst is the default number of RPC calls that modify
+	// state and can be made at once per macaroon.
+	DefaultRPCWriteBurst = 20
+
+	// DefaultRPCPaymentRate is the default number of RPC calls per second
+	// that move funds and are allowed per macaroon.
+	DefaultRPCPaymentRate = 5
+
+	// DefaultRPCPaymentBurst is the default number of RPC calls that move
+	// funds and can be made at once per macaroon.
+	DefaultRPCPaymentBurst = 10
+)
+
+// RPCRateLimit holds the configuration of the gRPC rate limiter.
+//
+//nolint:lll
+type RPCRateLimit struct {
+	Enable       bool    `long:"enable" description:"Limit the rate of RPC calls per macaroon. Calls that exceed the limit are rejected with a ResourceExhausted error."`
+	ReadRate     float64 `long:"readrate" description:"The number of read-only RPC calls per second that are allowed per macaroon."`
+	ReadBurst    int     `long:"readburst" description:"The number of read-only RPC calls that can be made at once per macaroon."`
+	WriteRate    float64 `long:"writerate" description:"The number of RPC calls per second that modify state and are allowed per macaroon."`
+	WriteBurst   int     `long:"writeburst" description:"The number of RPC calls that modify state and can be made at once per macaroon."`
+	PaymentRate  float64 `long:"paymentrate" description:"The number of RPC calls per second that move funds (on-chain or off-chain writes) and are allowed per macaroon."`
+	PaymentBurst int     `long:"paymentburst" description:"The number of RPC calls that move funds (on-chain or off-chain writes) and can be made at once per macaroon."`
+}
+
+// Validate checks the values configured for the RPC rate limiter.
+func (r *RPCRateLimit) Validate() error {
+	if !r.Enable {
+		return nil
+	}
+
+	if r.ReadRate <= 0 || r.WriteRate <= 0 || r.PaymentRate <= 0 {
+		return fmt.Errorf("RPC rate limits must be positive")
+	}
+
+	if r.ReadBurst < 1 || r.WriteBurst < 1 || r.PaymentBurst < 1 {
+		return fmt.Errorf("RPC rate limit bursts must be at least 1")
+	}
+
+	return nil
+}
+
+// DefaultRPCRateLimit returns the default values for the RPC rate limiter
+// configuration.
+func DefaultRPCRateLimit() *RPCRateLimit {
+	return &RPCRateLimit{
+		ReadRate:     DefaultRPCReadRate,
+		ReadBurst:    DefaultRPCReadBurst,
+		WriteRate:    DefaultRPCWriteRate,
+		WriteBurst:   DefaultRPCWriteBurst,
+		PaymentRate:  DefaultRPCPaymentRate,
+		PaymentBurst: DefaultRPCPaymentBurst,
+	}
+}
@@ -1,6 +1,9 @@
 package lncfg
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // Routing holds the configuration options for routing.
 //
@@ -11,6 +14,34 @@ type Routing struct {
 	StrictZombiePruning bool `long:"strictgraphpruning" description:"If true, then the graph will be pruned more aggressively for zombies. In practice this means that edges with a single stale edge will be considered a zombie."`
 
 	BlindedPaths BlindedPaths `group:"blinding" namespace:"blinding"`
+
+	Probing *Probing `group:"probing" namespace:"probing"`
+
+	MaxHopCltvDelta uint16 `long:"max-hop-cltv-delta" description:"The maximum time lock delta an intermediate hop of a payment route may charge. Routes through nodes that require a larger delta are not considered. If zero, intermediate hops are not constrained."`
+
+	MaxLastHopCltvDelta uint16 `long:"max-last-hop-cltv-delta" description:"The maximum time lock delta the last hop before the destination of a payment route may charge. If zero, the last hop is not constrained."`
+
+	GraphAnalytics bool `long:"graph-analytics" description:"If set, lnd will compute analytics such as the betweenness centrality of each node, the reachability of nodes and the set of bridge channels over the channel graph, and keep them up to date as gossip arrives. The analytics are recomputed when channels are added to or removed from the graph, which may be CPU intensive for large graphs."`
+
+	GraphAnalyticsBatchDelay time.Duration `long:"graph-analytics-batch-delay" description:"The delay between the first channel that is added to or removed from the graph and the recomputation of the graph analytics. All changes that arrive in the meantime are covered by the same recomputation."`
+
+	RouteCacheSize int `long:"route-cache-size" description:"The number of recently successful routes to remember, keyed by destination and amount. Repeated payments to the same destination reuse a cached route instead of running full path finding, as long as none of its channels failed since. If zero, no routes are cached."`
+
+	NumLandmarks int `long:"num-landmarks" description:"The number of landmark nodes to which the distances of all nodes in the graph are kept, used to direct path finding towards the destination. The distances are cached between payments and updated incrementally as gossip arrives, which speeds up path finding on large graphs at the cost of some memory. If zero, no landmarks are used."`
+
+	Trampoline bool `long:"trampoline" description:"If set, payments may be delegated to a trampoline node that signals support for trampoline routing. The trampoline node then finds the route to the final destination on our behalf, which allows paying destinations that are not part of our view of the graph."`
+}
+
+// Probing holds the configuration options for the background prober.
+//
+//nolint:lll
+type Probing struct {
+	Enable   bool          `long:"enable" description:"If set, lnd will periodically send probes with an unknown payment hash to the configured targets, or random nodes from the graph if no targets are set. The outcome of the probes is fed into mission control to improve the reliability of future payments."`
+	Interval time.Duration `long:"interval" description:"The interval at which a new probe is dispatched."`
+	Amount   uint64        `long:"amt" description:"The amount in satoshis that is used for each probe."`
+	FeeLimit uint64        `long:"fee-limit" description:"The maximum fee in satoshis a probe route may have."`
+	Timeout  time.Duration `long:"timeout" description:"The maximum time a single probe is allowed to take."`
+	Targets  []string      `long:"target" description:"The hex-encoded public key of a node that should be probed. Can be specified multiple times, targets are probed in a round-robin fashion."`
 }
 
 // BlindedPaths holds the configuration options for blinded path construction.
@@ -46,5 +77,29 @@ func (r *Routing) Validate() error {
 			"multiplier must be in the range (0,1]")
 	}
 
+	if r.RouteCacheSize < 0 {
+		return fmt.Errorf("the route cache size must not be negative")
+	}
+
+	if r.GraphAnalytics && r.GraphAnalyticsBatchDelay <= 0 {
+		return fmt.Errorf("the graph analytics batch delay must be " +
+			"positive")
+	}
+
+	if r.NumLandmarks < 0 {
+		return fmt.Errorf("the number of landmarks must not be " +
+			"negative")
+	}
+
+	if r.Probing != nil && r.Probing.Enable {
+		if r.Probing.Interval <= 0 {
+			return fmt.Errorf("the probe interval must be positive")
+		}
+
+		if r.Probing.Amount == 0 {
+			return fmt.Errorf("the probe amount must be positive")
+		}
+	}
+
 	return nil
 }
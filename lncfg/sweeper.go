@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/sweep"
 )
@@ -26,6 +27,12 @@ type Sweeper struct {
 
 	NoDeadlineConfTarget uint32 `long:"nodeadlineconftarget" description:"The conf target to use when sweeping non-time-sensitive outputs. This is useful for sweeping outputs that are not time-sensitive, and can be swept at a lower fee rate."`
 
+	UrgentBatchWindow     uint32 `long:"urgentbatchwindow" description:"The number of blocks to hold back the sweep of inputs whose deadline is less than 12 blocks away, so they can be batched with inputs that are offered later. Must be below 12."`
+	DeadlineBatchWindow   uint32 `long:"deadlinebatchwindow" description:"The number of blocks to hold back the sweep of inputs whose deadline is at least 12 blocks away, so they can be batched with inputs that are offered later."`
+	NoDeadlineBatchWindow uint32 `long:"nodeadlinebatchwindow" description:"The number of blocks to hold back the sweep of inputs without a deadline, so they can be batched with inputs that are offered later."`
+
+	NoWalletInputs []string `long:"nowalletinputs" description:"A sweep class whose outputs are never swept together with confirmed wallet UTXOs when their own value cannot pay the fees. Can be specified multiple times. Valid classes are: anchor, htlc, commitment and other."`
+
 	Budget *contractcourt.BudgetConfig `group:"sweeper.budget" namespace:"budget" long:"budget" description:"An optional config group that's used for the automatic sweep fee estimation. The Budget config gives options to limits ones fee exposure when sweeping unilateral close outputs and the fee rate calculated from budgets is capped at sweeper.maxfeerate. Check the budget config options for more details."`
 }
 
@@ -50,6 +57,17 @@ func (s *Sweeper) Validate() error {
 		return fmt.Errorf("nodeadlineconftarget must be at least 144")
 	}
 
+	// Make sure urgent inputs aren't held back past their deadline.
+	if s.UrgentBatchWindow >= uint32(sweep.DefaultBudgetEscalationDelta) {
+		return fmt.Errorf("urgentbatchwindow must be < %d",
+			sweep.DefaultBudgetEscalationDelta)
+	}
+
+	// Make sure the sweep classes are known.
+	if _, err := s.NoWalletInputClasses(); err != nil {
+		return fmt.Errorf("invalid nowalletinputs: %w", err)
+	}
+
 	// Validate the budget configuration.
 	if err := s.Budget.Validate(); err != nil {
 		return fmt.Errorf("invalid budget config: %w", err)
@@ -58,6 +76,31 @@ func (s *Sweeper) Validate() error {
 	return nil
 }
 
+// NoWalletInputClasses returns the set of sweep classes that aren't allowed to
+// use wallet inputs.
+func (s *Sweeper) NoWalletInputClasses() (fn.Set[sweep.SweepClass], error) {
+	classes := fn.NewSet[sweep.SweepClass]()
+	for _, name := range s.NoWalletInputs {
+		class, err := sweep.ParseSweepClass(name)
+		if err != nil {
+			return nil, err
+		}
+
+		classes.Add(class)
+	}
+
+	return classes, nil
+}
+
+// BatchWindows returns the batch windows of the urgency classes.
+func (s *Sweeper) BatchWindows() sweep.BatchWindows {
+	return sweep.BatchWindows{
+		Urgent:     s.UrgentBatchWindow,
+		Deadline:   s.DeadlineBatchWindow,
+		NoDeadline: s.NoDeadlineBatchWindow,
+	}
+}
+
 // DefaultSweeperConfig returns the default configuration for the sweeper.
 func DefaultSweeperConfig() *Sweeper {
 	return &Sweeper{
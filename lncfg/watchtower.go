@@ -1,6 +1,13 @@
 package lncfg
 
-import "github.com/lightningnetwork/lnd/watchtower"
+import (
+	"context"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/sqldb"
+	"github.com/lightningnetwork/lnd/watchtower"
+)
 
 // Watchtower holds the daemon specific configuration parameters for running a
 // watchtower that shares resources with the daemon.
@@ -11,6 +18,10 @@ type Watchtower struct {
 
 	TowerDir string `long:"towerdir" description:"Directory of the watchtower.db"`
 
+	Advertise bool `long:"advertise" description:"Advertise the watchtower in the node announcement, so that watchtower clients can discover it. The watchtower's external IPs and onion address are advertised"`
+
+	ShardDSNs []string `long:"shard-dsn" description:"The postgres connection string of a shard of the watchtower database. If set, the sessions of the tower's clients are spread over the given shards instead of being stored in the watchtower database of the node, which is migrated into the shards on first start. May be specified multiple times, the shards must always be given in the same order"`
+
 	watchtower.Conf
 }
 
@@ -24,3 +35,39 @@ func DefaultWatchtowerCfg(defaultTowerDir string) *Watchtower {
 		Conf:     *conf,
 	}
 }
+
+// GetShardBackends opens a postgres backend for each of the configured shards
+// of the watchtower database, in the order in which they were configured. The
+// connection settings other than the DSN are taken from the given postgres
+// config. The returned close functions are keyed by the name of the shard.
+func (w *Watchtower) GetShardBackends(ctx context.Context,
+	pgCfg *sqldb.PostgresConfig) ([]kvdb.Backend, map[string]func() error,
+	error) {
+
+	backends := make([]kvdb.Backend, 0, len(w.ShardDSNs))
+	closeFuncs := make(map[string]func() error)
+	for i, dsn := range w.ShardDSNs {
+		shardCfg := *pgCfg
+		shardCfg.Dsn = dsn
+
+		backend, err := kvdb.Open(
+			kvdb.PostgresBackendName, ctx,
+			GetPostgresConfigKVDB(&shardCfg), NSTowerServerDB,
+		)
+		if err != nil {
+			for _, closeFunc := range closeFuncs {
+				_ = closeFunc()
+			}
+
+			return nil, nil, fmt.Errorf("error opening postgres "+
+				"tower server DB shard %d: %w", i, err)
+		}
+
+		backends = append(backends, backend)
+
+		shardName := fmt.Sprintf("%s-shard-%d", NSTowerServerDB, i)
+		closeFuncs[shardName] = backend.Close
+	}
+
+	return backends, closeFuncs, nil
+}
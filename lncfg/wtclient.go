@@ -2,6 +2,7 @@ package lncfg
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
@@ -31,6 +32,38 @@ type WtClient struct {
 	// MaxUpdates is the maximum number of updates to be backed up in a
 	// single tower sessions.
 	MaxUpdates uint16 `long:"max-updates" description:"The maximum number of updates to be backed up in a single session."`
+
+	// HealthCheckInterval is the interval at which the towers are probed
+	// to determine their health.
+	HealthCheckInterval time.Duration `long:"health-check-interval" description:"The interval at which the watchtowers are probed to determine their health. Set to 0 to only judge the towers by the backups sent to them."`
+
+	// MaxTowerFailures is the number of consecutive failed exchanges with
+	// a tower after which it is considered dead.
+	MaxTowerFailures uint32 `long:"max-tower-failures" description:"The number of consecutive failed exchanges with a tower after which it is considered dead and new sessions are negotiated with other towers."`
+
+	// DeadTowerPolicy determines what happens to the states that were
+	// backed up to a tower once it is considered dead.
+	DeadTowerPolicy string `long:"dead-tower-policy" description:"What to do with the states that were backed up to a tower once it is considered dead." choice:"none" choice:"unacked" choice:"all"`
+
+	// DiscoverTowers determines whether the client adds towers that are
+	// advertised in node announcements.
+	DiscoverTowers bool `long:"discover-towers" description:"Whether to automatically add watchtowers that are advertised in the node announcements of the network."`
+
+	// MaxDiscoveredTowers is the number of active towers up to which
+	// advertised towers are added.
+	MaxDiscoveredTowers int `long:"max-discovered-towers" description:"The number of active watchtowers up to which advertised watchtowers are added."`
+
+	// DiscoveryInterval is the interval at which the advertised towers
+	// are checked.
+	DiscoveryInterval time.Duration `long:"discovery-interval" description:"The interval at which the advertised watchtowers are checked."`
+
+	// DiscoveryMinStorage is the minimum storage an advertised tower must
+	// reserve for its clients.
+	DiscoveryMinStorage uint64 `long:"discovery-min-storage" description:"The minimum number of bytes an advertised watchtower must reserve for the sessions of its clients. Watchtowers that don't limit their storage always qualify."`
+
+	// DiscoveryTorOnly restricts the discovery to towers that can be
+	// reached over Tor.
+	DiscoveryTorOnly bool `long:"discovery-tor-only" description:"Only add advertised watchtowers that can be reached at an onion address, and only use their onion addresses. Requires tor.active."`
 }
 
 // DefaultWtClientCfg returns the WtClient config struct with some default
@@ -43,10 +76,15 @@ func DefaultWtClientCfg() *WtClient {
 	sweepFeeRate := uint64(sweepSatsPerVB)
 
 	return &WtClient{
-		SweepFeeRate:       sweepFeeRate,
-		SessionCloseRange:  wtclient.DefaultSessionCloseRange,
-		MaxTasksInMemQueue: wtclient.DefaultMaxTasksInMemQueue,
-		MaxUpdates:         wtpolicy.DefaultMaxUpdates,
+		SweepFeeRate:        sweepFeeRate,
+		SessionCloseRange:   wtclient.DefaultSessionCloseRange,
+		MaxTasksInMemQueue:  wtclient.DefaultMaxTasksInMemQueue,
+		MaxUpdates:          wtpolicy.DefaultMaxUpdates,
+		HealthCheckInterval: wtclient.DefaultHealthCheckInterval,
+		MaxTowerFailures:    wtclient.DefaultMaxTowerFailures,
+		DeadTowerPolicy:     wtclient.DeadTowerRebackupNone.String(),
+		MaxDiscoveredTowers: wtclient.DefaultMaxDiscoveredTowers,
+		DiscoveryInterval:   wtclient.DefaultTowerDiscoveryInterval,
 	}
 }
 
@@ -70,6 +108,30 @@ func (c *WtClient) Validate() error {
 		return fmt.Errorf("session-close-range must be non-zero")
 	}
 
+	if c.MaxTowerFailures == 0 {
+		return fmt.Errorf("max-tower-failures must be non-zero")
+	}
+
+	if c.HealthCheckInterval < 0 {
+		return fmt.Errorf("health-check-interval must be non-negative")
+	}
+
+	_, err := wtclient.ParseDeadTowerPolicy(c.DeadTowerPolicy)
+	if err != nil {
+		return err
+	}
+
+	if c.DiscoverTowers {
+		if c.MaxDiscoveredTowers <= 0 {
+			return fmt.Errorf("max-discovered-towers must be " +
+				"positive")
+		}
+
+		if c.DiscoveryInterval <= 0 {
+			return fmt.Errorf("discovery-interval must be positive")
+		}
+	}
+
 	return nil
 }
 
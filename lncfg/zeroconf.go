@@ -0,0 +1,27 @@
+package lncfg
+
+import "fmt"
+
+// ZeroConf holds the exposure limits of the zero-conf channels that were
+// funded by the peer and didn't confirm yet.
+//
+//nolint:lll
+type ZeroConf struct {
+	MaxUnconfirmedInbound uint64 `long:"maxunconfirmedinbound" description:"The maximum total capacity in satoshis of the incoming zero-conf channels whose funding transaction didn't confirm yet. Incoming zero-conf channels above the limit are rejected. Setting this value to 0 disables the limit."`
+
+	MaxPeerUnconfirmedInbound uint64 `long:"maxpeerunconfirmedinbound" description:"The maximum total capacity in satoshis of the incoming zero-conf channels with a single peer whose funding transaction didn't confirm yet. Setting this value to 0 disables the limit."`
+
+	HoldThreshold uint64 `long:"holdthreshold" description:"The amount in satoshis above which HTLCs that come in over an incoming zero-conf channel are held until the channel confirms, as the peer can still double spend its funding transaction. Setting this value to 0 disables the holds."`
+}
+
+// Validate checks the values configured for the zero-conf limits.
+func (z *ZeroConf) Validate() error {
+	if z.MaxPeerUnconfirmedInbound != 0 && z.MaxUnconfirmedInbound != 0 &&
+		z.MaxPeerUnconfirmedInbound > z.MaxUnconfirmedInbound {
+
+		return fmt.Errorf("maxpeerunconfirmedinbound: must not be " +
+			"above maxunconfirmedinbound")
+	}
+
+	return nil
+}
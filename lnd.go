@@ -19,16 +19,21 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/lightningnetwork/lnd/accounts"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/caveats"
 	"github.com/lightningnetwork/lnd/chanacceptor"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
 	"github.com/lightningnetwork/lnd/cluster"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/monitoring"
 	"github.com/lightningnetwork/lnd/rpcperms"
@@ -36,8 +41,15 @@ import (
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/walletunlocker"
 	"github.com/lightningnetwork/lnd/watchtower"
+	"github.com/lightningnetwork/lnd/watchtower/lookout"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+
+	// Registering the gzip compressor allows clients to request
+	// compressed responses, which saves a lot of bandwidth for the large
+	// responses of calls like DescribeGraph.
+	_ "google.golang.org/grpc/encoding/gzip"
 	"google.golang.org/grpc/keepalive"
 	"gopkg.in/macaroon-bakery.v2/bakery"
 	"gopkg.in/macaroon.v2"
@@ -325,6 +337,34 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	if err := interceptorChain.Start(); err != nil {
 		return mkErr("error starting interceptor chain: %v", err)
 	}
+
+	if cfg.RPCRateLimit.Enable {
+		rl := cfg.RPCRateLimit
+		interceptorChain.EnableRateLimiting(
+			map[rpcperms.MethodClass]rpcperms.RateLimit{
+				rpcperms.MethodClassRead: {
+					Rate:  rate.Limit(rl.ReadRate),
+					Burst: rl.ReadBurst,
+				},
+				rpcperms.MethodClassWrite: {
+					Rate:  rate.Limit(rl.WriteRate),
+					Burst: rl.WriteBurst,
+				},
+				rpcperms.MethodClassPayment: {
+					Rate:  rate.Limit(rl.PaymentRate),
+					Burst: rl.PaymentBurst,
+				},
+			},
+		)
+	}
+
+	for name, validator := range implCfg.CaveatValidators {
+		err := interceptorChain.RegisterCaveatValidator(name, validator)
+		if err != nil {
+			return mkErr("error registering validator for custom "+
+				"caveat %v: %v", name, err)
+		}
+	}
 	defer func() {
 		err := interceptorChain.Stop()
 		if err != nil {
@@ -455,6 +495,13 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 
 	defer cleanUp()
 
+	// The configured read-only RPCs are served from the databases until
+	// the wallet is unlocked and the main RPC server is active.
+	lockedRPCs := newLockedRPCServer(cfg, dbs)
+	if err := lockedRPCs.register(interceptorChain); err != nil {
+		return mkErr("unable to register locked RPCs: %v", err)
+	}
+
 	partialChainControl, walletConfig, cleanUp, err := implCfg.BuildWalletConfig(
 		ctx, dbs, &implCfg.AuxComponents, interceptorChain,
 		grpcListeners,
@@ -526,7 +573,13 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 		}()
 	}
 
-	var tower *watchtower.Standalone
+	// The sweeper used to fee bump justice transactions is only created
+	// along with the server below, so the tower refers to it through
+	// justiceCPFP, which is set before the tower is started.
+	var (
+		tower       *watchtower.Standalone
+		justiceCPFP func(*lookout.CPFPRequest) error
+	)
 	if cfg.Watchtower.Active {
 		towerKeyDesc, err := activeChainControl.KeyRing.DeriveKey(
 			keychain.KeyLocator{
@@ -552,6 +605,15 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 			NodeKeyECDH: keychain.NewPubKeyECDH(
 				towerKeyDesc, activeChainControl.KeyRing,
 			),
+			FeeEstimator: activeChainControl.FeeEstimator,
+			CPFP: func(req *lookout.CPFPRequest) error {
+				if justiceCPFP == nil {
+					return errors.New("sweeper not " +
+						"available")
+				}
+
+				return justiceCPFP(req)
+			},
 			PublishTx: activeChainControl.Wallet.PublishTransaction,
 			ChainHash: *cfg.ActiveNetParams.GenesisHash,
 		}
@@ -606,6 +668,7 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 	if err != nil {
 		return mkErr("unable to create server: %v", err)
 	}
+	justiceCPFP = server.sweepJusticeReward
 
 	// Set up an autopilot manager from the current config. This will be
 	// used to manage the underlying autopilot agent, starting and stopping
@@ -771,6 +834,80 @@ func Main(cfg *Config, lisCfg ListenerCfg, implCfg *ImplementationCfg,
 			return mkErr("unable to start watchtower: %v", err)
 		}
 		defer tower.Stop()
+
+		if cfg.Watchtower.Advertise {
+			ann := tower.Announcement()
+			if ann == nil {
+				ltndLog.Warnf("Watchtower has no external IPs, " +
+					"not advertising it")
+			} else if err := server.advertiseTower(ann); err != nil {
+				return mkErr("unable to advertise watchtower: "+
+					"%v", err)
+			}
+		}
+	}
+
+	if cfg.InboundPolicy.Publish {
+		policy := cfg.InboundPolicy.Policy(
+			btcutil.Amount(cfg.MinChanSize),
+		)
+		if err := server.advertiseInboundPolicy(policy); err != nil {
+			return mkErr("unable to advertise inbound channel "+
+				"policy: %v", err)
+		}
+	}
+
+	// Macaroons that are bound to an account are only accepted once the
+	// account service enforces their balances.
+	if server.accounts != nil {
+		err = interceptorChain.RegisterCaveatValidator(
+			accounts.CaveatName, server.accounts,
+		)
+		if err != nil {
+			return mkErr("unable to register account caveat: %v",
+				err)
+		}
+	}
+
+	if cfg.Caveats.MaxAmountPerDay {
+		validator, err := caveats.NewMaxAmountValidator(
+			dbs.ChanStateDB, cfg.ActiveNetParams.Params,
+			clock.NewDefaultClock(), interceptorChain.Permissions(),
+		)
+		if err != nil {
+			return mkErr("unable to create max amount validator: "+
+				"%v", err)
+		}
+
+		err = interceptorChain.RegisterCaveatValidator(
+			caveats.MaxAmountPerDayName, validator,
+		)
+		if err != nil {
+			return mkErr("unable to register max amount caveat: "+
+				"%v", err)
+		}
+	}
+
+	if cfg.Caveats.ChannelAllowlist {
+		validator := caveats.NewChannelAllowlistValidator(
+			func(op wire.OutPoint) (lnwire.ShortChannelID, error) {
+				chanDB := dbs.ChanStateDB.ChannelStateDB()
+				channel, err := chanDB.FetchChannel(nil, op)
+				if err != nil {
+					return lnwire.ShortChannelID{}, err
+				}
+
+				return channel.ShortChannelID, nil
+			},
+		)
+
+		err := interceptorChain.RegisterCaveatValidator(
+			caveats.ChannelAllowlistName, validator,
+		)
+		if err != nil {
+			return mkErr("unable to register channel allowlist "+
+				"caveat: %v", err)
+		}
 	}
 
 	// Wait for shutdown signal from either a graceful server stop or from
@@ -23,6 +23,18 @@ type DevClient interface {
 	// ImportGraph imports a ChannelGraph into the graph database. Should only be
 	// used for development.
 	ImportGraph(ctx context.Context, in *lnrpc.ChannelGraph, opts ...grpc.CallOption) (*ImportGraphResponse, error)
+	// lncli: `fsm list`
+	// ListStateMachines lists the active protocol state machines and their
+	// current states.
+	ListStateMachines(ctx context.Context, in *ListStateMachinesRequest, opts ...grpc.CallOption) (*ListStateMachinesResponse, error)
+	// lncli: `fsm show`
+	// GetStateMachine returns the current state and the recent transitions of a
+	// protocol state machine.
+	GetStateMachine(ctx context.Context, in *GetStateMachineRequest, opts ...grpc.CallOption) (*StateMachine, error)
+	// lncli: `fsm inject`
+	// InjectStateMachineEvent sends an event to a protocol state machine. Events
+	// can only be injected on regtest and simnet.
+	InjectStateMachineEvent(ctx context.Context, in *InjectStateMachineEventRequest, opts ...grpc.CallOption) (*StateMachine, error)
 }
 
 type devClient struct {
@@ -42,6 +54,33 @@ func (c *devClient) ImportGraph(ctx context.Context, in *lnrpc.ChannelGraph, opt
 	return out, nil
 }
 
+func (c *devClient) ListStateMachines(ctx context.Context, in *ListStateMachinesRequest, opts ...grpc.CallOption) (*ListStateMachinesResponse, error) {
+	out := new(ListStateMachinesResponse)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/ListStateMachines", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devClient) GetStateMachine(ctx context.Context, in *GetStateMachineRequest, opts ...grpc.CallOption) (*StateMachine, error) {
+	out := new(StateMachine)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/GetStateMachine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *devClient) InjectStateMachineEvent(ctx context.Context, in *InjectStateMachineEventRequest, opts ...grpc.CallOption) (*StateMachine, error) {
+	out := new(StateMachine)
+	err := c.cc.Invoke(ctx, "/devrpc.Dev/InjectStateMachineEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DevServer is the server API for Dev service.
 // All implementations must embed UnimplementedDevServer
 // for forward compatibility
@@ -50,6 +89,18 @@ type DevServer interface {
 	// ImportGraph imports a ChannelGraph into the graph database. Should only be
 	// used for development.
 	ImportGraph(context.Context, *lnrpc.ChannelGraph) (*ImportGraphResponse, error)
+	// lncli: `fsm list`
+	// ListStateMachines lists the active protocol state machines and their
+	// current states.
+	ListStateMachines(context.Context, *ListStateMachinesRequest) (*ListStateMachinesResponse, error)
+	// lncli: `fsm show`
+	// GetStateMachine returns the current state and the recent transitions of a
+	// protocol state machine.
+	GetStateMachine(context.Context, *GetStateMachineRequest) (*StateMachine, error)
+	// lncli: `fsm inject`
+	// InjectStateMachineEvent sends an event to a protocol state machine. Events
+	// can only be injected on regtest and simnet.
+	InjectStateMachineEvent(context.Context, *InjectStateMachineEventRequest) (*StateMachine, error)
 	mustEmbedUnimplementedDevServer()
 }
 
@@ -60,6 +111,15 @@ type UnimplementedDevServer struct {
 func (UnimplementedDevServer) ImportGraph(context.Context, *lnrpc.ChannelGraph) (*ImportGraphResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ImportGraph not implemented")
 }
+func (UnimplementedDevServer) ListStateMachines(context.Context, *ListStateMachinesRequest) (*ListStateMachinesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStateMachines not implemented")
+}
+func (UnimplementedDevServer) GetStateMachine(context.Context, *GetStateMachineRequest) (*StateMachine, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStateMachine not implemented")
+}
+func (UnimplementedDevServer) InjectStateMachineEvent(context.Context, *InjectStateMachineEventRequest) (*StateMachine, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InjectStateMachineEvent not implemented")
+}
 func (UnimplementedDevServer) mustEmbedUnimplementedDevServer() {}
 
 // UnsafeDevServer may be embedded to opt out of forward compatibility for this service.
@@ -91,6 +151,60 @@ func _Dev_ImportGraph_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Dev_ListStateMachines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStateMachinesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).ListStateMachines(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/ListStateMachines",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).ListStateMachines(ctx, req.(*ListStateMachinesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dev_GetStateMachine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStateMachineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).GetStateMachine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/GetStateMachine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).GetStateMachine(ctx, req.(*GetStateMachineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dev_InjectStateMachineEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectStateMachineEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DevServer).InjectStateMachineEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/devrpc.Dev/InjectStateMachineEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DevServer).InjectStateMachineEvent(ctx, req.(*InjectStateMachineEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Dev_ServiceDesc is the grpc.ServiceDesc for Dev service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -102,6 +216,18 @@ var Dev_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ImportGraph",
 			Handler:    _Dev_ImportGraph_Handler,
 		},
+		{
+			MethodName: "ListStateMachines",
+			Handler:    _Dev_ListStateMachines_Handler,
+		},
+		{
+			MethodName: "GetStateMachine",
+			Handler:    _Dev_GetStateMachine_Handler,
+		},
+		{
+			MethodName: "InjectStateMachineEvent",
+			Handler:    _Dev_InjectStateMachineEvent_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "devrpc/dev.proto",
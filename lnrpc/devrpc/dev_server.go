@@ -40,6 +40,18 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/devrpc.Dev/ListStateMachines": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/devrpc.Dev/GetStateMachine": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/devrpc.Dev/InjectStateMachineEvent": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 )
 
@@ -0,0 +1,115 @@
+//go:build dev
+// +build dev
+
+package devrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/protofsm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errInjectionNotAllowed is returned if an event is injected into a state
+// machine on a network other than regtest or simnet.
+var errInjectionNotAllowed = status.Error(
+	codes.FailedPrecondition, "events can only be injected on regtest "+
+		"and simnet",
+)
+
+// ListStateMachines lists the active protocol state machines and their
+// current states.
+func (s *Server) ListStateMachines(_ context.Context,
+	_ *ListStateMachinesRequest) (*ListStateMachinesResponse, error) {
+
+	resp := &ListStateMachinesResponse{}
+	for _, machine := range s.cfg.FSMRegistry.Machines() {
+		resp.Machines = append(
+			resp.Machines, marshallStateMachine(machine, false),
+		)
+	}
+
+	return resp, nil
+}
+
+// GetStateMachine returns the current state and the recent transitions of a
+// protocol state machine.
+func (s *Server) GetStateMachine(_ context.Context,
+	req *GetStateMachineRequest) (*StateMachine, error) {
+
+	machine, err := s.stateMachine(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshallStateMachine(machine, true), nil
+}
+
+// InjectStateMachineEvent sends an event to a protocol state machine. Events
+// can only be injected on regtest and simnet.
+func (s *Server) InjectStateMachineEvent(_ context.Context,
+	req *InjectStateMachineEventRequest) (*StateMachine, error) {
+
+	switch s.cfg.ActiveNetParams.Name {
+	case chaincfg.RegressionNetParams.Name, chaincfg.SimNetParams.Name:
+	default:
+		return nil, errInjectionNotAllowed
+	}
+
+	machine, err := s.stateMachine(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = machine.InjectEvent(req.Event)
+	switch {
+	case errors.Is(err, protofsm.ErrInjectionUnsupported):
+		return nil, status.Error(codes.Unimplemented, err.Error())
+
+	case err != nil:
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return marshallStateMachine(machine, true), nil
+}
+
+// stateMachine looks up the state machine with the given name.
+func (s *Server) stateMachine(name string) (protofsm.Machine, error) {
+	machine, err := s.cfg.FSMRegistry.Machine(name)
+	if errors.Is(err, protofsm.ErrMachineNotFound) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return machine, err
+}
+
+// marshallStateMachine converts a state machine into its RPC counterpart,
+// optionally including its recent transitions.
+func marshallStateMachine(machine protofsm.Machine,
+	withTransitions bool) *StateMachine {
+
+	rpcMachine := &StateMachine{
+		Name:         machine.Name(),
+		CurrentState: machine.StateName(),
+		Terminal:     machine.IsTerminal(),
+	}
+	if !withTransitions {
+		return rpcMachine
+	}
+
+	for _, t := range machine.Transitions() {
+		rpcMachine.Transitions = append(
+			rpcMachine.Transitions, &StateTransition{
+				FromState:   t.From,
+				ToState:     t.To,
+				Event:       t.Event,
+				TimestampNs: t.Timestamp.UnixNano(),
+			},
+		)
+	}
+
+	return rpcMachine
+}
@@ -0,0 +1,116 @@
+package lnrpc
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCode is a stable, machine-readable code of an RPC error. Clients should
+// match on the code instead of the error message, which may change.
+type ErrorCode string
+
+const (
+	// ErrCodePaymentExists indicates that a payment with the same hash
+	// was already initiated.
+	ErrCodePaymentExists ErrorCode = "PAYMENT_EXISTS"
+
+	// ErrCodePaymentInFlight indicates that a payment with the same hash
+	// is currently in flight.
+	ErrCodePaymentInFlight ErrorCode = "PAYMENT_IN_FLIGHT"
+
+	// ErrCodeAlreadyPaid indicates that a payment with the same hash
+	// already succeeded.
+	ErrCodeAlreadyPaid ErrorCode = "ALREADY_PAID"
+
+	// ErrCodeInsufficientFunds indicates that the wallet doesn't hold
+	// enough funds for the operation.
+	ErrCodeInsufficientFunds ErrorCode = "INSUFFICIENT_FUNDS"
+
+	// ErrCodeChannelNotFound indicates that the channel isn't known.
+	ErrCodeChannelNotFound ErrorCode = "CHANNEL_NOT_FOUND"
+
+	// ErrCodeChannelStateInvalid indicates that the channel is in a state
+	// that doesn't allow the operation.
+	ErrCodeChannelStateInvalid ErrorCode = "CHANNEL_STATE_INVALID"
+
+	// ErrCodeChannelFrozen indicates that the channel can't be
+	// cooperatively closed before its thaw height.
+	ErrCodeChannelFrozen ErrorCode = "CHANNEL_FROZEN"
+
+	// ErrCodePeerOffline indicates that the operation requires the peer to
+	// be online.
+	ErrCodePeerOffline ErrorCode = "PEER_OFFLINE"
+
+	// ErrCodeInvalidCustomRecords indicates that the custom records of a
+	// payment are invalid or addressed to a node that can't receive them.
+	ErrCodeInvalidCustomRecords ErrorCode = "INVALID_CUSTOM_RECORDS"
+)
+
+// ErrorDetails are the typed details of an RPC error.
+type ErrorDetails struct {
+	// Code is the machine-readable code of the error.
+	Code ErrorCode
+
+	// ChannelPoint is the channel the error relates to, if any.
+	ChannelPoint string
+
+	// PaymentHash is the hex encoded hash of the payment the error relates
+	// to, if any.
+	PaymentHash string
+
+	// Retryable is true if the operation may succeed if it is retried
+	// later without any changes.
+	Retryable bool
+}
+
+// NewDetailedError returns a gRPC status error with the given code and the
+// message of err, that carries the details as an RPCErrorDetails message.
+func NewDetailedError(code codes.Code, err error,
+	details *ErrorDetails) error {
+
+	st, detailErr := status.New(code, err.Error()).WithDetails(
+		&RPCErrorDetails{
+			Code:         string(details.Code),
+			ChannelPoint: details.ChannelPoint,
+			PaymentHash:  details.PaymentHash,
+			Retryable:    details.Retryable,
+		},
+	)
+
+	// Attaching the details only fails if they can't be marshalled, in
+	// which case we still return the status code.
+	if detailErr != nil {
+		return status.Error(code, err.Error())
+	}
+
+	return st.Err()
+}
+
+// ErrorDetailsFromError extracts the typed details of an RPC error that were
+// attached by lnd. False is returned if the error doesn't carry any.
+func ErrorDetailsFromError(err error) (*ErrorDetails, bool) {
+	var grpcErr interface {
+		GRPCStatus() *status.Status
+	}
+	if !errors.As(err, &grpcErr) {
+		return nil, false
+	}
+
+	for _, detail := range grpcErr.GRPCStatus().Details() {
+		info, ok := detail.(*RPCErrorDetails)
+		if !ok {
+			continue
+		}
+
+		return &ErrorDetails{
+			Code:         ErrorCode(info.Code),
+			ChannelPoint: info.ChannelPoint,
+			PaymentHash:  info.PaymentHash,
+			Retryable:    info.Retryable,
+		}, true
+	}
+
+	return nil, false
+}
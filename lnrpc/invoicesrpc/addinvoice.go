@@ -96,6 +96,21 @@ type AddInvoiceConfig struct {
 	// QueryBlindedRoutes can be used to generate a few routes to this node
 	// that can then be used in the construction of a blinded payment path.
 	QueryBlindedRoutes func(lnwire.MilliSatoshi) ([]*route.Route, error)
+
+	// ChannelUptime returns the time the remote peer of the channel was
+	// online, along with the total time the channel has been monitored.
+	// It is used to score private channels as hop hint candidates.
+	ChannelUptime func(chanPoint wire.OutPoint,
+		peer route.Vertex) (time.Duration, time.Duration, error)
+
+	// PeerFlapCount returns the number of times the connection to the
+	// given peer flapped. It is used to score private channels as hop
+	// hint candidates.
+	PeerFlapCount func(peer route.Vertex) (int, error)
+
+	// FiatRateProvider is an optional provider that is used to quote the
+	// fiat price of invoices that are created with a fiat currency.
+	FiatRateProvider invoices.FiatRateProvider
 }
 
 // AddInvoiceData contains the required data to create a new invoice.
@@ -156,6 +171,28 @@ type AddInvoiceData struct {
 	// RouteHints are optional route hints that can each be individually
 	// used to assist in reaching the invoice's destination.
 	RouteHints [][]zpay32.HopHint
+
+	// PartialPayment is an optional policy that allows the invoice to be
+	// settled for less than its value.
+	PartialPayment invoices.PartialPaymentPolicy
+
+	// HtlcConstraints optionally restricts the htlcs that may pay the
+	// invoice.
+	HtlcConstraints invoices.HtlcConstraints
+
+	// FiatQuote is an optional fiat price that was quoted for the invoice
+	// by the caller. It is stored along with the invoice.
+	FiatQuote *invoices.FiatQuote
+
+	// FiatCurrency is the optional ISO 4217 code of the currency in which
+	// the fiat price of the invoice is quoted by the configured rate
+	// provider. It is ignored if FiatQuote is set.
+	FiatCurrency string
+
+	// StatelessSecret, if set, creates a stateless invoice whose payment
+	// address and preimage are derived from the secret. Stateless invoices
+	// aren't stored until they are paid.
+	StatelessSecret *invoices.StatelessSecret
 }
 
 // BlindedPathConfig holds the configuration values required for blinded path
@@ -290,9 +327,25 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 			"are not yet supported")
 	}
 
-	paymentPreimage, paymentHash, err := invoice.paymentHashAndPreimage()
-	if err != nil {
-		return nil, nil, err
+	var (
+		paymentPreimage *lntypes.Preimage
+		paymentHash     lntypes.Hash
+		paymentAddr     [32]byte
+		err             error
+	)
+	stateless := invoice.StatelessSecret != nil
+	if stateless {
+		paymentAddr, paymentPreimage, err = invoice.statelessPayment()
+		if err != nil {
+			return nil, nil, err
+		}
+		paymentHash = paymentPreimage.Hash()
+	} else {
+		paymentPreimage, paymentHash, err =
+			invoice.paymentHashAndPreimage()
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// The size of the memo, receipt and description hash attached must not
@@ -329,6 +382,11 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 
 	amtMSat := invoice.Value
 
+	fiatQuote, err := quoteFiat(ctx, cfg, invoice)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// We also create an encoded payment request which allows the
 	// caller to compactly send the invoice to the payer. We'll create a
 	// list of options to be added to the encoded payment request. For now
@@ -494,54 +552,18 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	// blinded paths, then this will be encoded in the invoice itself.
 	// Otherwise, it will instead be embedded in the encrypted recipient
 	// data of blinded paths. In the blinded path case, this will be used
-	// for the PathID.
-	var paymentAddr [32]byte
-	if _, err := rand.Read(paymentAddr[:]); err != nil {
-		return nil, nil, err
+	// for the PathID. The payment address of stateless invoices was
+	// derived already.
+	if !stateless {
+		if _, err := rand.Read(paymentAddr[:]); err != nil {
+			return nil, nil, err
+		}
 	}
 
 	if blind {
-		blindCfg := invoice.BlindedPathCfg
-
-		// Use the 10-min-per-block assumption to get a rough estimate
-		// of the number of blocks until the invoice expires. We want
-		// to make sure that the blinded path definitely does not expire
-		// before the invoice does, and so we add a healthy buffer.
-		invoiceExpiry := uint32(expiry.Minutes() / 10)
-		blindedPathExpiry := invoiceExpiry * 2
-
-		// Add BlockPadding to the finalCltvDelta so that the receiving
-		// node does not reject the HTLC if some blocks are mined while
-		// the payment is in-flight. Note that unlike vanilla invoices,
-		// with blinded paths, the recipient is responsible for adding
-		// this block padding instead of the sender.
-		finalCLTVDelta := uint32(cltvExpiryDelta)
-		finalCLTVDelta += uint32(routing.BlockPadding)
-
-		//nolint:lll
-		paths, err := blindedpath.BuildBlindedPaymentPaths(
-			&blindedpath.BuildBlindedPathCfg{
-				FindRoutes:              cfg.QueryBlindedRoutes,
-				FetchChannelEdgesByID:   cfg.Graph.FetchChannelEdgesByID,
-				FetchOurOpenChannels:    cfg.ChanDB.FetchAllOpenChannels,
-				PathID:                  paymentAddr[:],
-				ValueMsat:               invoice.Value,
-				BestHeight:              cfg.BestHeight,
-				MinFinalCLTVExpiryDelta: finalCLTVDelta,
-				BlocksUntilExpiry:       blindedPathExpiry,
-				AddPolicyBuffer: func(
-					p *blindedpath.BlindedHopPolicy) (
-					*blindedpath.BlindedHopPolicy, error) {
-
-					//nolint:lll
-					return blindedpath.AddPolicyBuffer(
-						p, blindCfg.RoutePolicyIncrMultiplier,
-						blindCfg.RoutePolicyDecrMultiplier,
-					)
-				},
-				MinNumHops:            blindCfg.MinNumPathHops,
-				DefaultDummyHopPolicy: blindCfg.DefaultDummyHopPolicy,
-			},
+		paths, err := buildBlindedPaths(
+			cfg, invoice.BlindedPathCfg, paymentAddr, amtMSat,
+			expiry, cltvExpiryDelta,
 		)
 		if err != nil {
 			return nil, nil, err
@@ -565,31 +587,7 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 		return nil, nil, err
 	}
 
-	payReqString, err := payReq.Encode(zpay32.MessageSigner{
-		SignCompact: func(msg []byte) ([]byte, error) {
-			// For an invoice without a blinded path, the main node
-			// key is used to sign the invoice so that the sender
-			// can derive the true pub key of the recipient.
-			if !blind {
-				return cfg.NodeSigner.SignMessageCompact(
-					msg, false,
-				)
-			}
-
-			// For an invoice with a blinded path, we use an
-			// ephemeral key to sign the invoice since we don't want
-			// the sender to be able to know the real pub key of
-			// the recipient.
-			ephemKey, err := btcec.NewPrivateKey()
-			if err != nil {
-				return nil, err
-			}
-
-			return ecdsa.SignCompact(
-				ephemKey, chainhash.HashB(msg), true,
-			), nil
-		},
-	})
+	payReqString, err := encodePaymentRequest(cfg, payReq, blind)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -605,8 +603,17 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 			PaymentPreimage: paymentPreimage,
 			PaymentAddr:     paymentAddr,
 			Features:        invoiceFeatures,
+			PartialPayment:  invoice.PartialPayment,
+			HtlcConstraints: invoice.HtlcConstraints,
 		},
 		HodlInvoice: invoice.HodlInvoice,
+		FiatQuote:   fiatQuote,
+	}
+
+	// Stateless invoices are only inserted by the registry once they are
+	// paid.
+	if stateless {
+		return &paymentHash, newInvoice, nil
 	}
 
 	log.Tracef("[addinvoice] adding new invoice %v",
@@ -621,6 +628,131 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	return &paymentHash, newInvoice, nil
 }
 
+// quoteFiat returns the fiat quote to store along with the invoice, if any. A
+// quote passed by the caller takes precedence, otherwise the configured rate
+// provider is asked for a quote in the requested currency.
+func quoteFiat(ctx context.Context, cfg *AddInvoiceConfig,
+	invoice *AddInvoiceData) (*invoices.FiatQuote, error) {
+
+	var quote *invoices.FiatQuote
+	switch {
+	case invoice.FiatQuote != nil:
+		fiatQuote := *invoice.FiatQuote
+		quote = &fiatQuote
+
+	case invoice.FiatCurrency == "":
+		return nil, nil
+
+	case invoice.Value == 0:
+		return nil, fmt.Errorf("fiat price of an invoice without " +
+			"amount can't be quoted")
+
+	case cfg.FiatRateProvider == nil:
+		return nil, fmt.Errorf("no fiat rate provider configured")
+
+	default:
+		var err error
+		quote, err = cfg.FiatRateProvider.QuoteFiat(
+			ctx, invoice.Value, invoice.FiatCurrency,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to quote fiat price: %w",
+				err)
+		}
+
+		if quote.Currency != invoice.FiatCurrency {
+			return nil, fmt.Errorf("fiat quote currency %v "+
+				"doesn't match requested currency %v",
+				quote.Currency, invoice.FiatCurrency)
+		}
+	}
+
+	if err := quote.Validate(); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// buildBlindedPaths constructs the blinded payment paths of an invoice with the
+// given payment address, value, expiry and final cltv delta.
+func buildBlindedPaths(cfg *AddInvoiceConfig, blindCfg *BlindedPathConfig,
+	paymentAddr [32]byte, value lnwire.MilliSatoshi, expiry time.Duration,
+	cltvExpiryDelta uint64) ([]*zpay32.BlindedPaymentPath, error) {
+
+	// Use the 10-min-per-block assumption to get a rough estimate of the
+	// number of blocks until the invoice expires. We want to make sure
+	// that the blinded path definitely does not expire before the invoice
+	// does, and so we add a healthy buffer.
+	invoiceExpiry := uint32(expiry.Minutes() / 10)
+	blindedPathExpiry := invoiceExpiry * 2
+
+	// Add BlockPadding to the finalCltvDelta so that the receiving node
+	// does not reject the HTLC if some blocks are mined while the payment
+	// is in-flight. Note that unlike vanilla invoices, with blinded paths,
+	// the recipient is responsible for adding this block padding instead
+	// of the sender.
+	finalCLTVDelta := uint32(cltvExpiryDelta)
+	finalCLTVDelta += uint32(routing.BlockPadding)
+
+	//nolint:lll
+	return blindedpath.BuildBlindedPaymentPaths(
+		&blindedpath.BuildBlindedPathCfg{
+			FindRoutes:              cfg.QueryBlindedRoutes,
+			FetchChannelEdgesByID:   cfg.Graph.FetchChannelEdgesByID,
+			FetchOurOpenChannels:    cfg.ChanDB.FetchAllOpenChannels,
+			PathID:                  paymentAddr[:],
+			ValueMsat:               value,
+			BestHeight:              cfg.BestHeight,
+			MinFinalCLTVExpiryDelta: finalCLTVDelta,
+			BlocksUntilExpiry:       blindedPathExpiry,
+			AddPolicyBuffer: func(
+				p *blindedpath.BlindedHopPolicy) (
+				*blindedpath.BlindedHopPolicy, error) {
+
+				return blindedpath.AddPolicyBuffer(
+					p, blindCfg.RoutePolicyIncrMultiplier,
+					blindCfg.RoutePolicyDecrMultiplier,
+				)
+			},
+			MinNumHops:            blindCfg.MinNumPathHops,
+			DefaultDummyHopPolicy: blindCfg.DefaultDummyHopPolicy,
+		},
+	)
+}
+
+// encodePaymentRequest signs and encodes the given payment request as a
+// bech32 (zpay32) string.
+func encodePaymentRequest(cfg *AddInvoiceConfig, payReq *zpay32.Invoice,
+	blind bool) (string, error) {
+
+	return payReq.Encode(zpay32.MessageSigner{
+		SignCompact: func(msg []byte) ([]byte, error) {
+			// For an invoice without a blinded path, the main node
+			// key is used to sign the invoice so that the sender
+			// can derive the true pub key of the recipient.
+			if !blind {
+				return cfg.NodeSigner.SignMessageCompact(
+					msg, false,
+				)
+			}
+
+			// For an invoice with a blinded path, we use an
+			// ephemeral key to sign the invoice since we don't want
+			// the sender to be able to know the real pub key of
+			// the recipient.
+			ephemKey, err := btcec.NewPrivateKey()
+			if err != nil {
+				return nil, err
+			}
+
+			return ecdsa.SignCompact(
+				ephemKey, chainhash.HashB(msg), true,
+			), nil
+		},
+	})
+}
+
 // chanCanBeHopHint returns true if the target channel is eligible to be a hop
 // hint.
 func chanCanBeHopHint(channel *HopHintInfo, cfg *SelectHopHintsCfg) (
@@ -780,6 +912,19 @@ type SelectHopHintsCfg struct {
 
 	// MaxHopHints is the maximum number of hop hints we are interested in.
 	MaxHopHints int
+
+	// ChannelUptime returns the time the remote peer of the channel was
+	// online, along with the total time the channel has been monitored.
+	// This is optional, if it is not set the uptime isn't taken into
+	// account when scoring hop hint candidates.
+	ChannelUptime func(chanPoint wire.OutPoint,
+		peer route.Vertex) (time.Duration, time.Duration, error)
+
+	// PeerFlapCount returns the number of times the connection to the
+	// given peer flapped. This is optional, if it is not set the peer's
+	// reliability isn't taken into account when scoring hop hint
+	// candidates.
+	PeerFlapCount func(peer route.Vertex) (int, error)
 }
 
 func newSelectHopHintsCfg(invoicesCfg *AddInvoiceConfig,
@@ -792,6 +937,8 @@ func newSelectHopHintsCfg(invoicesCfg *AddInvoiceConfig,
 		FetchChannelEdgesByID: invoicesCfg.Graph.FetchChannelEdgesByID,
 		GetAlias:              invoicesCfg.GetAlias,
 		MaxHopHints:           maxHopHints,
+		ChannelUptime:         invoicesCfg.ChannelUptime,
+		PeerFlapCount:         invoicesCfg.PeerFlapCount,
 	}
 }
 
@@ -823,9 +970,11 @@ func sufficientHints(nHintsLeft int, currentAmount,
 
 // getPotentialHints returns a slice of open channels that should be considered
 // for the hopHint list in an invoice. The slice is sorted in descending order
-// based on the remote balance.
-func getPotentialHints(cfg *SelectHopHintsCfg) ([]*channeldb.OpenChannel,
-	error) {
+// based on the channels' hop hint score, which takes their inbound liquidity
+// relative to the invoice amount, as well as the uptime and reliability of
+// their peers into account.
+func getPotentialHints(cfg *SelectHopHintsCfg,
+	amtMSat lnwire.MilliSatoshi) ([]*channeldb.OpenChannel, error) {
 
 	// TODO(positiveblue): get the channels slice already filtered by
 	// private == true and sorted by RemoteBalance?
@@ -834,21 +983,37 @@ func getPotentialHints(cfg *SelectHopHintsCfg) ([]*channeldb.OpenChannel,
 		return nil, err
 	}
 
+	var maxRemoteBalance lnwire.MilliSatoshi
 	privateChannels := make([]*channeldb.OpenChannel, 0, len(openChannels))
 	for _, oc := range openChannels {
 		isPublic := oc.ChannelFlags&lnwire.FFAnnounceChannel != 0
-		if !isPublic {
-			privateChannels = append(privateChannels, oc)
+		if isPublic {
+			continue
+		}
+
+		privateChannels = append(privateChannels, oc)
+
+		remoteBalance := oc.LocalCommitment.RemoteBalance
+		if remoteBalance > maxRemoteBalance {
+			maxRemoteBalance = remoteBalance
 		}
 	}
 
-	// Sort the channels in descending remote balance.
-	compareRemoteBalance := func(i, j int) bool {
-		iBalance := privateChannels[i].LocalCommitment.RemoteBalance
-		jBalance := privateChannels[j].LocalCommitment.RemoteBalance
-		return iBalance > jBalance
+	// Score all channels, then sort them in descending order of their
+	// score.
+	scores := make(map[*channeldb.OpenChannel]float64, len(privateChannels))
+	for _, oc := range privateChannels {
+		score := scoreHopHint(cfg, oc, maxRemoteBalance, amtMSat)
+		scores[oc] = score.total()
+
+		log.Tracef("Hop hint candidate %v: liquidity=%.3f, "+
+			"uptime=%.3f, reliability=%.3f", oc.ShortChannelID,
+			score.liquidity, score.uptime, score.reliability)
 	}
-	sort.Slice(privateChannels, compareRemoteBalance)
+
+	sort.SliceStable(privateChannels, func(i, j int) bool {
+		return scores[privateChannels[i]] > scores[privateChannels[j]]
+	})
 
 	return privateChannels, nil
 }
@@ -960,7 +1125,7 @@ func PopulateHopHints(cfg *SelectHopHintsCfg, amtMSat lnwire.MilliSatoshi,
 		alreadyIncluded[hopHint[0].ChannelID] = true
 	}
 
-	potentialHints, err := getPotentialHints(cfg)
+	potentialHints, err := getPotentialHints(cfg, amtMSat)
 	if err != nil {
 		return nil, err
 	}
@@ -1,14 +1,18 @@
 package invoicesrpc
 
 import (
+	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"github.com/stretchr/testify/mock"
@@ -896,3 +900,132 @@ func TestPopulateHopHints(t *testing.T) {
 		})
 	}
 }
+
+// mockFiatRateProvider is a FiatRateProvider that returns a fixed quote or
+// error.
+type mockFiatRateProvider struct {
+	quote *invoices.FiatQuote
+	err   error
+}
+
+// QuoteFiat returns the configured quote and error.
+func (m *mockFiatRateProvider) QuoteFiat(_ context.Context,
+	_ lnwire.MilliSatoshi, _ string) (*invoices.FiatQuote, error) {
+
+	return m.quote, m.err
+}
+
+// TestQuoteFiat tests the selection and validation of the fiat quote that is
+// stored along with a new invoice.
+func TestQuoteFiat(t *testing.T) {
+	t.Parallel()
+
+	usdQuote := &invoices.FiatQuote{
+		Currency:   "USD",
+		Amount:     1250,
+		RateSource: "exchange",
+		QuoteTime:  time.Unix(1, 0),
+	}
+
+	tests := []struct {
+		name     string
+		provider invoices.FiatRateProvider
+		invoice  *AddInvoiceData
+		expQuote *invoices.FiatQuote
+		expErr   bool
+	}{
+		{
+			name:    "no quote",
+			invoice: &AddInvoiceData{Value: 1000},
+		},
+		{
+			name: "explicit quote",
+			provider: &mockFiatRateProvider{
+				err: errors.New("not used"),
+			},
+			invoice: &AddInvoiceData{
+				Value:        1000,
+				FiatQuote:    usdQuote,
+				FiatCurrency: "EUR",
+			},
+			expQuote: usdQuote,
+		},
+		{
+			name: "invalid explicit quote",
+			invoice: &AddInvoiceData{
+				Value: 1000,
+				FiatQuote: &invoices.FiatQuote{
+					Currency: "usd",
+					Amount:   1,
+				},
+			},
+			expErr: true,
+		},
+		{
+			name:     "provider quote",
+			provider: &mockFiatRateProvider{quote: usdQuote},
+			invoice: &AddInvoiceData{
+				Value:        1000,
+				FiatCurrency: "USD",
+			},
+			expQuote: usdQuote,
+		},
+		{
+			name:     "provider currency mismatch",
+			provider: &mockFiatRateProvider{quote: usdQuote},
+			invoice: &AddInvoiceData{
+				Value:        1000,
+				FiatCurrency: "EUR",
+			},
+			expErr: true,
+		},
+		{
+			name: "provider error",
+			provider: &mockFiatRateProvider{
+				err: errors.New("rate unavailable"),
+			},
+			invoice: &AddInvoiceData{
+				Value:        1000,
+				FiatCurrency: "USD",
+			},
+			expErr: true,
+		},
+		{
+			name: "no provider",
+			invoice: &AddInvoiceData{
+				Value:        1000,
+				FiatCurrency: "USD",
+			},
+			expErr: true,
+		},
+		{
+			name:     "zero amount invoice",
+			provider: &mockFiatRateProvider{quote: usdQuote},
+			invoice:  &AddInvoiceData{FiatCurrency: "USD"},
+			expErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := &AddInvoiceConfig{
+				FiatRateProvider: test.provider,
+			}
+
+			quote, err := quoteFiat(
+				context.Background(), cfg, test.invoice,
+			)
+			if test.expErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expQuote, quote)
+		})
+	}
+}
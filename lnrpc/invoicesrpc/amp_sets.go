@@ -0,0 +1,68 @@
+package invoicesrpc
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/invoices"
+)
+
+// errInvalidSetID is returned when a set id of an invalid length is passed.
+var errInvalidSetID = errors.New("set id must be 32 bytes")
+
+// parseSetID parses the set id of an AMP set.
+func parseSetID(rawID []byte) (invoices.SetID, error) {
+	var setID invoices.SetID
+	if len(rawID) != len(setID) {
+		return setID, errInvalidSetID
+	}
+	copy(setID[:], rawID)
+
+	return setID, nil
+}
+
+// marshallAMPSets converts AMP sets to their rpc representation.
+func marshallAMPSets(sets []invoices.AMPSet) ([]*AMPSet, error) {
+	rpcSets := make([]*AMPSet, 0, len(sets))
+	for _, set := range sets {
+		state, err := CreateRPCAMPInvoiceState(set.InvoiceStateAMP)
+		if err != nil {
+			return nil, err
+		}
+
+		setID := set.SetID
+		rpcSets = append(rpcSets, &AMPSet{
+			SetId: setID[:],
+			State: state,
+		})
+	}
+
+	return rpcSets, nil
+}
+
+// marshallAMPSetList returns the pending sets of an AMP invoice along with the
+// page of its settled sets that matches the query.
+func marshallAMPSetList(invoice *invoices.Invoice,
+	q invoices.AMPSetQuery) (*ListAMPSetsResponse, error) {
+
+	if !invoice.IsAMP() {
+		return nil, errors.New("invoice is not an AMP invoice")
+	}
+
+	pending, err := marshallAMPSets(invoice.PendingAMPSets())
+	if err != nil {
+		return nil, err
+	}
+
+	slice := invoice.QueryAMPSets(q)
+	settled, err := marshallAMPSets(slice.Sets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ListAMPSetsResponse{
+		PendingSets:      pending,
+		SettledSets:      settled,
+		FirstIndexOffset: slice.FirstIndexOffset,
+		LastIndexOffset:  slice.LastIndexOffset,
+	}, nil
+}
@@ -0,0 +1,65 @@
+package invoicesrpc
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshallAMPSetList tests that the pending sets and a page of the settled
+// sets of an AMP invoice are marshalled.
+func TestMarshallAMPSetList(t *testing.T) {
+	t.Parallel()
+
+	invoice := &invoices.Invoice{
+		Terms: invoices.ContractTerm{
+			Features: lnwire.NewFeatureVector(
+				lnwire.NewRawFeatureVector(
+					lnwire.AMPRequired,
+				), lnwire.Features,
+			),
+		},
+		AMPState: invoices.AMPInvoiceState{
+			{1}: {
+				State:       invoices.HtlcStateSettled,
+				SettleIndex: 2,
+				AmtPaid:     1000,
+			},
+			{2}: {
+				State:       invoices.HtlcStateSettled,
+				SettleIndex: 1,
+			},
+			{3}: {State: invoices.HtlcStateAccepted},
+			{4}: {State: invoices.HtlcStateCanceled},
+		},
+	}
+
+	resp, err := marshallAMPSetList(invoice, invoices.AMPSetQuery{
+		IndexOffset: 1,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, resp.PendingSets, 1)
+	require.Equal(t, []byte{3}, resp.PendingSets[0].SetId[:1])
+	require.Equal(
+		t, lnrpc.InvoiceHTLCState_ACCEPTED,
+		resp.PendingSets[0].State.State,
+	)
+
+	require.Len(t, resp.SettledSets, 1)
+	require.Equal(t, []byte{1}, resp.SettledSets[0].SetId[:1])
+	require.EqualValues(t, 1000, resp.SettledSets[0].State.AmtPaidMsat)
+	require.EqualValues(t, 2, resp.FirstIndexOffset)
+	require.EqualValues(t, 2, resp.LastIndexOffset)
+
+	// Invoices that aren't AMP invoices have no sets.
+	_, err = marshallAMPSetList(&invoices.Invoice{}, invoices.AMPSetQuery{})
+	require.Error(t, err)
+
+	// Set ids must be 32 bytes.
+	_, err = parseSetID([]byte{1})
+	require.ErrorIs(t, err, errInvalidSetID)
+}
@@ -74,4 +74,11 @@ type Config struct {
 	// ParseAuxData is a function that can be used to parse the auxiliary
 	// data from the invoice.
 	ParseAuxData func(message proto.Message) error
+
+	// Webhook posts invoice events to the configured webhook. It is nil if
+	// no webhook is configured.
+	Webhook *WebhookDispatcher
+
+	// Subscriptions generates the invoices of recurring subscriptions.
+	Subscriptions *invoices.SubscriptionManager
 }
@@ -0,0 +1,18 @@
+package invoicesrpc
+
+import (
+	"github.com/lightningnetwork/lnd/invoices"
+)
+
+// marshallHoldExpiryWarning converts a hold expiry warning of the invoice
+// registry to its RPC representation.
+func marshallHoldExpiryWarning(
+	warning *invoices.HoldExpiryWarning) *HoldExpiryWarning {
+
+	return &HoldExpiryWarning{
+		PaymentHash:   warning.PaymentHash[:],
+		HtlcExpiry:    warning.HtlcExpiry,
+		CancelHeight:  warning.CancelHeight,
+		CurrentHeight: warning.CurrentHeight,
+	}
+}
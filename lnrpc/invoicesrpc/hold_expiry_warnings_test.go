@@ -0,0 +1,28 @@
+package invoicesrpc
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshallHoldExpiryWarning tests that a hold expiry warning of the
+// invoice registry is returned over RPC.
+func TestMarshallHoldExpiryWarning(t *testing.T) {
+	t.Parallel()
+
+	warning := &invoices.HoldExpiryWarning{
+		PaymentHash:   lntypes.Hash{1, 2, 3},
+		HtlcExpiry:    800_040,
+		CancelHeight:  800_030,
+		CurrentHeight: 800_020,
+	}
+
+	rpcWarning := marshallHoldExpiryWarning(warning)
+	require.Equal(t, warning.PaymentHash[:], rpcWarning.PaymentHash)
+	require.EqualValues(t, 800_040, rpcWarning.HtlcExpiry)
+	require.EqualValues(t, 800_030, rpcWarning.CancelHeight)
+	require.EqualValues(t, 800_020, rpcWarning.CurrentHeight)
+}
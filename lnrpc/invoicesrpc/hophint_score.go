@@ -0,0 +1,123 @@
+package invoicesrpc
+
+import (
+	"math"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// flapPenaltyCount is the number of times a peer must have flapped
+	// for its reliability score to be halved.
+	flapPenaltyCount = 10
+)
+
+// hopHintScore holds the individual components that make up the score of a
+// private channel as a hop hint candidate. Each of the components is in the
+// range [0, 1].
+type hopHintScore struct {
+	// liquidity scores the inbound liquidity of the channel relative to
+	// the other candidates and the invoice amount.
+	liquidity float64
+
+	// uptime is the fraction of the channel's monitored lifetime that the
+	// peer was online.
+	uptime float64
+
+	// reliability scores the peer based on how often its connection to us
+	// flapped.
+	reliability float64
+}
+
+// total returns the combined score of the channel. A channel that scores
+// zero in any of the components isn't a viable hop hint, so the components
+// are multiplied.
+func (s hopHintScore) total() float64 {
+	return s.liquidity * s.uptime * s.reliability
+}
+
+// scoreLiquidity scores the remote balance of a channel. It is scored relative
+// to the largest remote balance of all candidates, and is further penalized
+// if the channel can't carry the full invoice amount on its own.
+func scoreLiquidity(remoteBalance, maxRemoteBalance,
+	amt lnwire.MilliSatoshi) float64 {
+
+	if maxRemoteBalance == 0 {
+		return 0
+	}
+
+	score := float64(remoteBalance) / float64(maxRemoteBalance)
+	if amt != 0 && remoteBalance < amt {
+		score *= float64(remoteBalance) / float64(amt)
+	}
+
+	return score
+}
+
+// scoreUptime returns the fraction of the lifetime the channel peer was
+// online. If nothing is known about the channel yet, it is given the benefit
+// of the doubt.
+func scoreUptime(uptime, lifetime time.Duration) float64 {
+	if lifetime <= 0 {
+		return 1
+	}
+
+	return math.Min(float64(uptime)/float64(lifetime), 1)
+}
+
+// scoreReliability returns a score that halves for every flapPenaltyCount
+// flaps of the peer's connection.
+func scoreReliability(flapCount int) float64 {
+	return math.Pow(0.5, float64(flapCount)/flapPenaltyCount)
+}
+
+// scoreHopHint computes the score of the given private channel as a hop hint
+// candidate. Uptime and reliability are only taken into account if the
+// respective lookups are configured.
+func scoreHopHint(cfg *SelectHopHintsCfg, channel *channeldb.OpenChannel,
+	maxRemoteBalance, amt lnwire.MilliSatoshi) hopHintScore {
+
+	score := hopHintScore{
+		liquidity: scoreLiquidity(
+			channel.LocalCommitment.RemoteBalance,
+			maxRemoteBalance, amt,
+		),
+		uptime:      1,
+		reliability: 1,
+	}
+
+	// Without the key of the channel peer, we can't look up its uptime
+	// or reliability.
+	if channel.IdentityPub == nil {
+		return score
+	}
+
+	peer := route.NewVertex(channel.IdentityPub)
+
+	if cfg.ChannelUptime != nil {
+		uptime, lifetime, err := cfg.ChannelUptime(
+			channel.FundingOutpoint, peer,
+		)
+		if err != nil {
+			log.Debugf("Unable to fetch uptime of channel %v: %v",
+				channel.FundingOutpoint, err)
+		} else {
+			score.uptime = scoreUptime(uptime, lifetime)
+		}
+	}
+
+	if cfg.PeerFlapCount != nil {
+		flapCount, err := cfg.PeerFlapCount(peer)
+		if err != nil {
+			log.Debugf("Unable to fetch flap count of peer %v: %v",
+				peer, err)
+		} else {
+			score.reliability = scoreReliability(flapCount)
+		}
+	}
+
+	return score
+}
@@ -0,0 +1,109 @@
+package invoicesrpc
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHopHintScoreComponents tests the individual components of the hop hint
+// score.
+func TestHopHintScoreComponents(t *testing.T) {
+	t.Parallel()
+
+	// Liquidity is relative to the largest remote balance.
+	require.InDelta(t, 0.5, scoreLiquidity(50, 100, 0), 1e-9)
+	require.InDelta(t, 1.0, scoreLiquidity(100, 100, 0), 1e-9)
+	require.Zero(t, scoreLiquidity(0, 0, 0))
+
+	// Channels that can carry the full amount aren't penalized, others
+	// are in proportion to the amount they can carry.
+	require.InDelta(t, 0.5, scoreLiquidity(50, 100, 50), 1e-9)
+	require.InDelta(t, 0.25, scoreLiquidity(50, 100, 100), 1e-9)
+
+	// Unknown channels get the benefit of the doubt.
+	require.InDelta(t, 1.0, scoreUptime(0, 0), 1e-9)
+	require.InDelta(t, 0.75, scoreUptime(3*time.Hour, 4*time.Hour), 1e-9)
+
+	// Every flapPenaltyCount flaps halve the reliability.
+	require.InDelta(t, 1.0, scoreReliability(0), 1e-9)
+	require.InDelta(t, 0.5, scoreReliability(flapPenaltyCount), 1e-9)
+	require.InDelta(t, 0.25, scoreReliability(2*flapPenaltyCount), 1e-9)
+}
+
+// TestGetPotentialHintsScored asserts that the private channels are ordered by
+// their score, taking the uptime and reliability of their peers into account.
+func TestGetPotentialHintsScored(t *testing.T) {
+	t.Parallel()
+
+	newChannel := func(index uint32,
+		remoteBalance lnwire.MilliSatoshi) *channeldb.OpenChannel {
+
+		return &channeldb.OpenChannel{
+			FundingOutpoint: wire.OutPoint{Index: index},
+			IdentityPub:     getTestPubKey(),
+			LocalCommitment: channeldb.ChannelCommitment{
+				RemoteBalance: remoteBalance,
+			},
+			ShortChannelID: lnwire.NewShortChanIDFromInt(
+				uint64(index),
+			),
+		}
+	}
+
+	// The channel with the most liquidity has a poor uptime, so the
+	// second largest channel should be preferred.
+	largest := newChannel(1, 10_000)
+	second := newChannel(2, 8_000)
+	smallest := newChannel(3, 1_000)
+	public := newChannel(4, 20_000)
+	public.ChannelFlags = lnwire.FFAnnounceChannel
+
+	uptimes := map[uint32]time.Duration{
+		1: time.Hour,
+		2: 10 * time.Hour,
+	}
+
+	cfg := &SelectHopHintsCfg{
+		FetchAllChannels: func() ([]*channeldb.OpenChannel, error) {
+			return []*channeldb.OpenChannel{
+				smallest, public, largest, second,
+			}, nil
+		},
+		ChannelUptime: func(chanPoint wire.OutPoint,
+			_ route.Vertex) (time.Duration, time.Duration, error) {
+
+			uptime, ok := uptimes[chanPoint.Index]
+			if !ok {
+				return 0, 0, errors.New("unknown channel")
+			}
+
+			return uptime, 10 * time.Hour, nil
+		},
+		PeerFlapCount: func(route.Vertex) (int, error) {
+			return 0, nil
+		},
+	}
+
+	channels, err := getPotentialHints(cfg, 5_000)
+	require.NoError(t, err)
+	require.Equal(
+		t, []*channeldb.OpenChannel{second, largest, smallest},
+		channels,
+	)
+
+	// Without the uptime lookup, channels are ordered by liquidity.
+	cfg.ChannelUptime = nil
+	channels, err = getPotentialHints(cfg, 5_000)
+	require.NoError(t, err)
+	require.Equal(
+		t, []*channeldb.OpenChannel{largest, second, smallest},
+		channels,
+	)
+}
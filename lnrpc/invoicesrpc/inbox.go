@@ -0,0 +1,383 @@
+package invoicesrpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+const (
+	// InboxMessageRecord is the custom record that carries the text of a
+	// message sent along with a spontaneous payment, as used by chat
+	// applications on top of keysend.
+	InboxMessageRecord uint64 = 34349334
+
+	// InboxSenderRecord is the custom record that carries the public key
+	// of the sender of a message, if the sender chose to reveal it.
+	InboxSenderRecord uint64 = 34349339
+
+	// inboxQueryPageSize is the number of invoices we fetch at once while
+	// loading the inbox.
+	inboxQueryPageSize = 1000
+)
+
+var (
+	// ErrInboxMessageNotFound is returned when a message that doesn't
+	// exist is acknowledged.
+	ErrInboxMessageNotFound = errors.New("inbox message not found")
+)
+
+// InboxMessage is a message received as the custom records of a settled
+// payment, which usually is a spontaneous keysend payment. AMP payments are
+// not part of the inbox, as their invoices are never settled as a whole.
+type InboxMessage struct {
+	// ID identifies the message. It is the settle index of the invoice the
+	// message was received with, so the messages are ordered by the time
+	// they were received.
+	ID uint64
+
+	// PaymentHash is the hash of the payment.
+	PaymentHash lntypes.Hash
+
+	// AmtPaid is the amount that was paid along with the message.
+	AmtPaid lnwire.MilliSatoshi
+
+	// ReceivedAt is the time the payment was settled.
+	ReceivedAt time.Time
+
+	// Text is the content of the InboxMessageRecord, if present.
+	Text string
+
+	// Sender is the content of the InboxSenderRecord, if present. It is
+	// provided by the sender and not authenticated.
+	Sender []byte
+
+	// CustomRecords are all custom records of the payment except for the
+	// keysend preimage.
+	CustomRecords record.CustomSet
+
+	// Acked is true if the message has been acknowledged.
+	Acked bool
+}
+
+// copy returns a copy of the message that can be handed out to callers.
+func (m *InboxMessage) copy() *InboxMessage {
+	msgCopy := *m
+	msgCopy.CustomRecords = make(record.CustomSet, len(m.CustomRecords))
+	for k, v := range m.CustomRecords {
+		msgCopy.CustomRecords[k] = v
+	}
+
+	return &msgCopy
+}
+
+// newInboxMessage returns the inbox message of a settled invoice, or false if
+// the invoice didn't come with custom records.
+func newInboxMessage(invoice *invoices.Invoice) (*InboxMessage, bool) {
+	if invoice.State != invoices.ContractSettled ||
+		invoice.SettleIndex == 0 {
+
+		return nil, false
+	}
+
+	customRecords := make(record.CustomSet)
+	for _, htlc := range invoice.Htlcs {
+		if htlc.State != invoices.HtlcStateSettled {
+			continue
+		}
+
+		for k, v := range htlc.CustomRecords {
+			if k == record.KeySendType {
+				continue
+			}
+			customRecords[k] = v
+		}
+	}
+	if len(customRecords) == 0 {
+		return nil, false
+	}
+
+	msg := &InboxMessage{
+		ID:            invoice.SettleIndex,
+		AmtPaid:       invoice.AmtPaid,
+		ReceivedAt:    invoice.SettleDate,
+		Text:          string(customRecords[InboxMessageRecord]),
+		Sender:        customRecords[InboxSenderRecord],
+		CustomRecords: customRecords,
+	}
+	if invoice.Terms.PaymentPreimage != nil {
+		msg.PaymentHash = invoice.Terms.PaymentPreimage.Hash()
+	}
+
+	return msg, true
+}
+
+// InboxQuery selects the messages of the inbox to return.
+type InboxQuery struct {
+	// IndexOffset is the ID of the last message the caller has seen. Only
+	// messages with a higher ID are returned.
+	IndexOffset uint64
+
+	// MaxMessages is the maximum number of messages to return. Zero means
+	// no limit.
+	MaxMessages uint32
+
+	// UnackedOnly only returns the messages that haven't been
+	// acknowledged.
+	UnackedOnly bool
+}
+
+// InboxConfig houses the configuration of the inbox.
+type InboxConfig struct {
+	// QueryInvoices queries the invoice database.
+	QueryInvoices func(ctx context.Context,
+		q invoices.InvoiceQuery) (invoices.InvoiceSlice, error)
+
+	// SubscribeNotifications subscribes to new and settled invoices.
+	SubscribeNotifications func(ctx context.Context, addIndex,
+		settleIndex uint64) (*invoices.InvoiceSubscription, error)
+
+	// FetchAcks returns the IDs of the acknowledged messages.
+	FetchAcks func() (map[uint64]struct{}, error)
+
+	// PutAcks persists the acknowledgement of the messages.
+	PutAcks func(ids []uint64) error
+}
+
+// Inbox surfaces the custom records received with keysend payments as a
+// queryable inbox of messages, so applications don't have to scrape them from
+// the invoices. Messages can be acknowledged once they have been processed,
+// and new messages are pushed to subscribers.
+type Inbox struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *InboxConfig
+
+	// messages holds all messages sorted by their ID, byID indexes them.
+	// Both are guarded by mu.
+	messages []*InboxMessage
+	byID     map[uint64]*InboxMessage
+	mu       sync.RWMutex
+
+	ntfnServer *subscribe.Server
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewInbox creates a new inbox.
+func NewInbox(cfg *InboxConfig) *Inbox {
+	return &Inbox{
+		cfg:        cfg,
+		byID:       make(map[uint64]*InboxMessage),
+		ntfnServer: subscribe.NewServer(),
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start loads the messages of the settled invoices and starts watching for
+// new ones.
+func (i *Inbox) Start() error {
+	var startErr error
+	i.started.Do(func() {
+		log.Info("Keysend inbox starting")
+
+		if err := i.ntfnServer.Start(); err != nil {
+			startErr = err
+			return
+		}
+
+		// We subscribe before loading the invoices, so no invoice that
+		// is settled in the meantime is missed. Invoices that are
+		// delivered twice are skipped.
+		sub, err := i.cfg.SubscribeNotifications(
+			context.Background(), 0, 0,
+		)
+		if err != nil {
+			startErr = err
+			return
+		}
+
+		if err := i.load(); err != nil {
+			sub.Cancel()
+			startErr = err
+
+			return
+		}
+
+		i.wg.Add(1)
+		go i.notificationLoop(sub)
+	})
+
+	return startErr
+}
+
+// Stop stops watching for new messages.
+func (i *Inbox) Stop() error {
+	var stopErr error
+	i.stopped.Do(func() {
+		log.Info("Keysend inbox shutting down...")
+		defer log.Debug("Keysend inbox shutdown complete")
+
+		close(i.quit)
+		i.wg.Wait()
+
+		stopErr = i.ntfnServer.Stop()
+	})
+
+	return stopErr
+}
+
+// load adds the messages of all settled invoices to the inbox.
+func (i *Inbox) load() error {
+	acks, err := i.cfg.FetchAcks()
+	if err != nil {
+		return err
+	}
+
+	query := invoices.InvoiceQuery{
+		NumMaxInvoices: inboxQueryPageSize,
+	}
+	for {
+		slice, err := i.cfg.QueryInvoices(context.Background(), query)
+		if err != nil {
+			return err
+		}
+
+		for idx := range slice.Invoices {
+			msg, ok := newInboxMessage(&slice.Invoices[idx])
+			if !ok {
+				continue
+			}
+
+			_, msg.Acked = acks[msg.ID]
+			i.add(msg)
+		}
+
+		if len(slice.Invoices) < inboxQueryPageSize {
+			break
+		}
+		query.IndexOffset = slice.LastIndexOffset
+	}
+
+	log.Debugf("Loaded %d keysend inbox messages", len(i.messages))
+
+	return nil
+}
+
+// add adds the message to the inbox, keeping the messages sorted. It returns
+// false if the message is already known.
+func (i *Inbox) add(msg *InboxMessage) bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, ok := i.byID[msg.ID]; ok {
+		return false
+	}
+
+	idx := sort.Search(len(i.messages), func(j int) bool {
+		return i.messages[j].ID > msg.ID
+	})
+	i.messages = append(i.messages, nil)
+	copy(i.messages[idx+1:], i.messages[idx:])
+	i.messages[idx] = msg
+	i.byID[msg.ID] = msg
+
+	return true
+}
+
+// notificationLoop adds the messages of newly settled invoices to the inbox
+// and pushes them to the subscribers.
+//
+// NOTE: This MUST be run as a goroutine.
+func (i *Inbox) notificationLoop(sub *invoices.InvoiceSubscription) {
+	defer i.wg.Done()
+	defer sub.Cancel()
+
+	for {
+		select {
+		case invoice := <-sub.SettledInvoices:
+			msg, ok := newInboxMessage(invoice)
+			if !ok || !i.add(msg) {
+				continue
+			}
+
+			err := i.ntfnServer.SendUpdate(msg.copy())
+			if err != nil {
+				log.Errorf("Unable to send inbox message %d "+
+					"to subscribers: %v", msg.ID, err)
+			}
+
+		// New invoices aren't of interest, but we need to consume
+		// them.
+		case <-sub.NewInvoices:
+
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+// Messages returns the messages of the inbox that match the query.
+func (i *Inbox) Messages(q *InboxQuery) []*InboxMessage {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	start := sort.Search(len(i.messages), func(j int) bool {
+		return i.messages[j].ID > q.IndexOffset
+	})
+
+	var msgs []*InboxMessage
+	for _, msg := range i.messages[start:] {
+		if q.MaxMessages != 0 && uint32(len(msgs)) >= q.MaxMessages {
+			break
+		}
+
+		if q.UnackedOnly && msg.Acked {
+			continue
+		}
+
+		msgs = append(msgs, msg.copy())
+	}
+
+	return msgs
+}
+
+// Ack acknowledges the messages with the given IDs, so they are no longer
+// returned to queries for unacknowledged messages. Either all or none of
+// the messages are acknowledged.
+func (i *Inbox) Ack(ids []uint64) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, id := range ids {
+		if _, ok := i.byID[id]; !ok {
+			return ErrInboxMessageNotFound
+		}
+	}
+
+	if err := i.cfg.PutAcks(ids); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		i.byID[id].Acked = true
+	}
+
+	return nil
+}
+
+// Subscribe returns a client that receives every new message of the inbox as
+// *InboxMessage.
+func (i *Inbox) Subscribe() (*subscribe.Client, error) {
+	return i.ntfnServer.Subscribe()
+}
@@ -0,0 +1,131 @@
+package invoicesrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewInboxMessage tests that only settled invoices with custom records
+// become inbox messages.
+func TestNewInboxMessage(t *testing.T) {
+	t.Parallel()
+
+	preimage := lntypes.Preimage{1}
+	settleDate := time.Unix(1000, 0)
+	newInvoice := func(state invoices.ContractState,
+		records record.CustomSet) *invoices.Invoice {
+
+		settled := &invoices.InvoiceHTLC{
+			State:         invoices.HtlcStateSettled,
+			CustomRecords: records,
+		}
+		canceled := &invoices.InvoiceHTLC{
+			State: invoices.HtlcStateCanceled,
+			CustomRecords: record.CustomSet{
+				100000: []byte("canceled"),
+			},
+		}
+
+		return &invoices.Invoice{
+			Terms: invoices.ContractTerm{
+				PaymentPreimage: &preimage,
+			},
+			State:       state,
+			SettleIndex: 7,
+			SettleDate:  settleDate,
+			AmtPaid:     1000,
+			Htlcs: map[invoices.CircuitKey]*invoices.InvoiceHTLC{
+				{HtlcID: 1}: settled,
+				{HtlcID: 2}: canceled,
+			},
+		}
+	}
+
+	// A keysend payment without any other custom record isn't a message.
+	_, ok := newInboxMessage(newInvoice(
+		invoices.ContractSettled, record.CustomSet{
+			record.KeySendType: preimage[:],
+		},
+	))
+	require.False(t, ok)
+
+	// Neither is an invoice that isn't settled yet.
+	records := record.CustomSet{
+		record.KeySendType: preimage[:],
+		InboxMessageRecord: []byte("hello"),
+		InboxSenderRecord:  []byte{2, 3},
+	}
+	_, ok = newInboxMessage(newInvoice(invoices.ContractAccepted, records))
+	require.False(t, ok)
+
+	msg, ok := newInboxMessage(
+		newInvoice(invoices.ContractSettled, records),
+	)
+	require.True(t, ok)
+	require.Equal(t, &InboxMessage{
+		ID:          7,
+		PaymentHash: preimage.Hash(),
+		AmtPaid:     1000,
+		ReceivedAt:  settleDate,
+		Text:        "hello",
+		Sender:      []byte{2, 3},
+		CustomRecords: record.CustomSet{
+			InboxMessageRecord: []byte("hello"),
+			InboxSenderRecord:  []byte{2, 3},
+		},
+	}, msg)
+}
+
+// TestInboxMessages tests querying and acknowledging inbox messages.
+func TestInboxMessages(t *testing.T) {
+	t.Parallel()
+
+	var persisted []uint64
+	inbox := NewInbox(&InboxConfig{
+		PutAcks: func(ids []uint64) error {
+			persisted = append(persisted, ids...)
+			return nil
+		},
+	})
+
+	// Messages are kept sorted, no matter in which order they are added,
+	// and duplicates are skipped.
+	for _, id := range []uint64{3, 1, 2, 5} {
+		require.True(t, inbox.add(&InboxMessage{ID: id}))
+	}
+	require.False(t, inbox.add(&InboxMessage{ID: 2}))
+
+	ids := func(msgs []*InboxMessage) []uint64 {
+		var ids []uint64
+		for _, msg := range msgs {
+			ids = append(ids, msg.ID)
+		}
+
+		return ids
+	}
+
+	require.Equal(t, []uint64{1, 2, 3, 5}, ids(inbox.Messages(
+		&InboxQuery{},
+	)))
+	require.Equal(t, []uint64{3, 5}, ids(inbox.Messages(
+		&InboxQuery{IndexOffset: 2},
+	)))
+	require.Equal(t, []uint64{1, 2}, ids(inbox.Messages(
+		&InboxQuery{MaxMessages: 2},
+	)))
+
+	// Acknowledging an unknown message fails without acknowledging any.
+	require.ErrorIs(t, inbox.Ack([]uint64{1, 4}), ErrInboxMessageNotFound)
+	require.Empty(t, persisted)
+
+	require.NoError(t, inbox.Ack([]uint64{1, 3}))
+	require.Equal(t, []uint64{1, 3}, persisted)
+	require.Equal(t, []uint64{2, 5}, ids(inbox.Messages(
+		&InboxQuery{UnackedOnly: true},
+	)))
+}
@@ -44,6 +44,71 @@ type InvoicesClient interface {
 	// server will send HTLCs of invoices to the client and the client can modify
 	// some aspects of the HTLC in order to pass the invoice acceptance tests.
 	HtlcModifier(ctx context.Context, opts ...grpc.CallOption) (Invoices_HtlcModifierClient, error)
+	// lncli: `webhookdeadletters list`
+	// ListWebhookDeadLetters returns the invoice events that couldn't be
+	// delivered to the webhook set with invoices.webhookurl once
+	// invoices.webhookmaxattempts was exhausted.
+	ListWebhookDeadLetters(ctx context.Context, in *ListWebhookDeadLettersRequest, opts ...grpc.CallOption) (*ListWebhookDeadLettersResponse, error)
+	// lncli: `webhookdeadletters retry`
+	// RetryWebhookDeadLetters removes all events from the dead letter queue of
+	// the invoice webhook and queues them for delivery again.
+	RetryWebhookDeadLetters(ctx context.Context, in *RetryWebhookDeadLettersRequest, opts ...grpc.CallOption) (*RetryWebhookDeadLettersResponse, error)
+	// lncli: `subscription add`
+	// AddSubscription creates a recurring subscription from an invoice template.
+	// A new invoice is generated at the start of each period, so that no
+	// external scheduler is needed. If the first period already started, its
+	// invoice is generated right away.
+	AddSubscription(ctx context.Context, in *AddSubscriptionRequest, opts ...grpc.CallOption) (*InvoiceSubscription, error)
+	// lncli: `subscription cancel`
+	// CancelSubscription stops generating invoices for a subscription. Invoices
+	// that were generated already are not canceled.
+	CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*CancelSubscriptionResponse, error)
+	// lncli: `subscription list`
+	// ListSubscriptions returns all subscriptions, or a single subscription if
+	// its id is set, along with the payment status of each period an invoice
+	// was generated for.
+	ListSubscriptions(ctx context.Context, in *ListSubscriptionsRequest, opts ...grpc.CallOption) (*ListSubscriptionsResponse, error)
+	// lncli: `addstatelessinvoice`
+	// AddStatelessInvoice creates a stateless invoice, which isn't stored until
+	// it is paid. Its payment address and preimage are derived from the secret
+	// that is derived from the key at m/1017'/coinType'/7'/0/1, so that external
+	// services that know the seed can issue the same invoices. Requires
+	// invoices.stateless to be set.
+	AddStatelessInvoice(ctx context.Context, in *AddStatelessInvoiceRequest, opts ...grpc.CallOption) (*AddStatelessInvoiceResp, error)
+	// lncli: `verifystatelesssettlement`
+	// VerifyStatelessSettlement verifies that a payment address was issued as a
+	// stateless invoice for the given amount, and returns the preimage derived
+	// from it along with the settlement state of the invoice. Requires
+	// invoices.stateless to be set.
+	VerifyStatelessSettlement(ctx context.Context, in *VerifyStatelessSettlementRequest, opts ...grpc.CallOption) (*VerifyStatelessSettlementResponse, error)
+	// lncli: `ampset subscribe`
+	// SubscribeAMPSet returns a uni-directional stream (server -> client) for
+	// notifying the client of state changes of a single set of an AMP invoice.
+	// Every update carries the AMP invoice with only the HTLCs of the set
+	// populated. The stream is closed once the set is settled or canceled.
+	SubscribeAMPSet(ctx context.Context, in *SubscribeAMPSetRequest, opts ...grpc.CallOption) (Invoices_SubscribeAMPSetClient, error)
+	// lncli: `ampset cancel`
+	// CancelAMPSet cancels the accepted HTLCs of a pending AMP set, without
+	// affecting the AMP invoice itself or any of its other sets.
+	CancelAMPSet(ctx context.Context, in *CancelAMPSetRequest, opts ...grpc.CallOption) (*CancelAMPSetResponse, error)
+	// lncli: `ampset list`
+	// ListAMPSets returns the pending sets of an AMP invoice along with a page
+	// of its settled sets, ordered by their settle index.
+	ListAMPSets(ctx context.Context, in *ListAMPSetsRequest, opts ...grpc.CallOption) (*ListAMPSetsResponse, error)
+	// SubscribeHoldExpiryWarnings returns a uni-directional stream (server ->
+	// client) of warnings for accepted hold invoices that are still unresolved
+	// invoices.holdwarningdelta blocks before they are canceled automatically
+	// to prevent a force close. Warnings are dropped if the client doesn't keep
+	// up with them.
+	SubscribeHoldExpiryWarnings(ctx context.Context, in *SubscribeHoldExpiryWarningsRequest, opts ...grpc.CallOption) (Invoices_SubscribeHoldExpiryWarningsClient, error)
+	// lncli: `reissueinvoice`
+	// ReissueInvoice regenerates the hop hints of an open, unexpired invoice and
+	// returns a new payment request for the same payment hash and payment
+	// address. All other fields, including the timestamp and expiry, are
+	// carried over from the original payment request, so both remain payable.
+	// The invoice itself is not modified. Invoices with blinded paths can't be
+	// re-issued through this call.
+	ReissueInvoice(ctx context.Context, in *ReissueInvoiceRequest, opts ...grpc.CallOption) (*ReissueInvoiceResponse, error)
 }
 
 type invoicesClient struct {
@@ -153,6 +218,160 @@ func (x *invoicesHtlcModifierClient) Recv() (*HtlcModifyRequest, error) {
 	return m, nil
 }
 
+func (c *invoicesClient) ListWebhookDeadLetters(ctx context.Context, in *ListWebhookDeadLettersRequest, opts ...grpc.CallOption) (*ListWebhookDeadLettersResponse, error) {
+	out := new(ListWebhookDeadLettersResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/ListWebhookDeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) RetryWebhookDeadLetters(ctx context.Context, in *RetryWebhookDeadLettersRequest, opts ...grpc.CallOption) (*RetryWebhookDeadLettersResponse, error) {
+	out := new(RetryWebhookDeadLettersResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/RetryWebhookDeadLetters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) AddSubscription(ctx context.Context, in *AddSubscriptionRequest, opts ...grpc.CallOption) (*InvoiceSubscription, error) {
+	out := new(InvoiceSubscription)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/AddSubscription", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*CancelSubscriptionResponse, error) {
+	out := new(CancelSubscriptionResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/CancelSubscription", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) ListSubscriptions(ctx context.Context, in *ListSubscriptionsRequest, opts ...grpc.CallOption) (*ListSubscriptionsResponse, error) {
+	out := new(ListSubscriptionsResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/ListSubscriptions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) AddStatelessInvoice(ctx context.Context, in *AddStatelessInvoiceRequest, opts ...grpc.CallOption) (*AddStatelessInvoiceResp, error) {
+	out := new(AddStatelessInvoiceResp)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/AddStatelessInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) VerifyStatelessSettlement(ctx context.Context, in *VerifyStatelessSettlementRequest, opts ...grpc.CallOption) (*VerifyStatelessSettlementResponse, error) {
+	out := new(VerifyStatelessSettlementResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/VerifyStatelessSettlement", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) SubscribeAMPSet(ctx context.Context, in *SubscribeAMPSetRequest, opts ...grpc.CallOption) (Invoices_SubscribeAMPSetClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Invoices_ServiceDesc.Streams[2], "/invoicesrpc.Invoices/SubscribeAMPSet", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoicesSubscribeAMPSetClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Invoices_SubscribeAMPSetClient interface {
+	Recv() (*lnrpc.Invoice, error)
+	grpc.ClientStream
+}
+
+type invoicesSubscribeAMPSetClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoicesSubscribeAMPSetClient) Recv() (*lnrpc.Invoice, error) {
+	m := new(lnrpc.Invoice)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoicesClient) CancelAMPSet(ctx context.Context, in *CancelAMPSetRequest, opts ...grpc.CallOption) (*CancelAMPSetResponse, error) {
+	out := new(CancelAMPSetResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/CancelAMPSet", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) ListAMPSets(ctx context.Context, in *ListAMPSetsRequest, opts ...grpc.CallOption) (*ListAMPSetsResponse, error) {
+	out := new(ListAMPSetsResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/ListAMPSets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *invoicesClient) SubscribeHoldExpiryWarnings(ctx context.Context, in *SubscribeHoldExpiryWarningsRequest, opts ...grpc.CallOption) (Invoices_SubscribeHoldExpiryWarningsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Invoices_ServiceDesc.Streams[3], "/invoicesrpc.Invoices/SubscribeHoldExpiryWarnings", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoicesSubscribeHoldExpiryWarningsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Invoices_SubscribeHoldExpiryWarningsClient interface {
+	Recv() (*HoldExpiryWarning, error)
+	grpc.ClientStream
+}
+
+type invoicesSubscribeHoldExpiryWarningsClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoicesSubscribeHoldExpiryWarningsClient) Recv() (*HoldExpiryWarning, error) {
+	m := new(HoldExpiryWarning)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *invoicesClient) ReissueInvoice(ctx context.Context, in *ReissueInvoiceRequest, opts ...grpc.CallOption) (*ReissueInvoiceResponse, error) {
+	out := new(ReissueInvoiceResponse)
+	err := c.cc.Invoke(ctx, "/invoicesrpc.Invoices/ReissueInvoice", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // InvoicesServer is the server API for Invoices service.
 // All implementations must embed UnimplementedInvoicesServer
 // for forward compatibility
@@ -182,6 +401,71 @@ type InvoicesServer interface {
 	// server will send HTLCs of invoices to the client and the client can modify
 	// some aspects of the HTLC in order to pass the invoice acceptance tests.
 	HtlcModifier(Invoices_HtlcModifierServer) error
+	// lncli: `webhookdeadletters list`
+	// ListWebhookDeadLetters returns the invoice events that couldn't be
+	// delivered to the webhook set with invoices.webhookurl once
+	// invoices.webhookmaxattempts was exhausted.
+	ListWebhookDeadLetters(context.Context, *ListWebhookDeadLettersRequest) (*ListWebhookDeadLettersResponse, error)
+	// lncli: `webhookdeadletters retry`
+	// RetryWebhookDeadLetters removes all events from the dead letter queue of
+	// the invoice webhook and queues them for delivery again.
+	RetryWebhookDeadLetters(context.Context, *RetryWebhookDeadLettersRequest) (*RetryWebhookDeadLettersResponse, error)
+	// lncli: `subscription add`
+	// AddSubscription creates a recurring subscription from an invoice template.
+	// A new invoice is generated at the start of each period, so that no
+	// external scheduler is needed. If the first period already started, its
+	// invoice is generated right away.
+	AddSubscription(context.Context, *AddSubscriptionRequest) (*InvoiceSubscription, error)
+	// lncli: `subscription cancel`
+	// CancelSubscription stops generating invoices for a subscription. Invoices
+	// that were generated already are not canceled.
+	CancelSubscription(context.Context, *CancelSubscriptionRequest) (*CancelSubscriptionResponse, error)
+	// lncli: `subscription list`
+	// ListSubscriptions returns all subscriptions, or a single subscription if
+	// its id is set, along with the payment status of each period an invoice
+	// was generated for.
+	ListSubscriptions(context.Context, *ListSubscriptionsRequest) (*ListSubscriptionsResponse, error)
+	// lncli: `addstatelessinvoice`
+	// AddStatelessInvoice creates a stateless invoice, which isn't stored until
+	// it is paid. Its payment address and preimage are derived from the secret
+	// that is derived from the key at m/1017'/coinType'/7'/0/1, so that external
+	// services that know the seed can issue the same invoices. Requires
+	// invoices.stateless to be set.
+	AddStatelessInvoice(context.Context, *AddStatelessInvoiceRequest) (*AddStatelessInvoiceResp, error)
+	// lncli: `verifystatelesssettlement`
+	// VerifyStatelessSettlement verifies that a payment address was issued as a
+	// stateless invoice for the given amount, and returns the preimage derived
+	// from it along with the settlement state of the invoice. Requires
+	// invoices.stateless to be set.
+	VerifyStatelessSettlement(context.Context, *VerifyStatelessSettlementRequest) (*VerifyStatelessSettlementResponse, error)
+	// lncli: `ampset subscribe`
+	// SubscribeAMPSet returns a uni-directional stream (server -> client) for
+	// notifying the client of state changes of a single set of an AMP invoice.
+	// Every update carries the AMP invoice with only the HTLCs of the set
+	// populated. The stream is closed once the set is settled or canceled.
+	SubscribeAMPSet(*SubscribeAMPSetRequest, Invoices_SubscribeAMPSetServer) error
+	// lncli: `ampset cancel`
+	// CancelAMPSet cancels the accepted HTLCs of a pending AMP set, without
+	// affecting the AMP invoice itself or any of its other sets.
+	CancelAMPSet(context.Context, *CancelAMPSetRequest) (*CancelAMPSetResponse, error)
+	// lncli: `ampset list`
+	// ListAMPSets returns the pending sets of an AMP invoice along with a page
+	// of its settled sets, ordered by their settle index.
+	ListAMPSets(context.Context, *ListAMPSetsRequest) (*ListAMPSetsResponse, error)
+	// SubscribeHoldExpiryWarnings returns a uni-directional stream (server ->
+	// client) of warnings for accepted hold invoices that are still unresolved
+	// invoices.holdwarningdelta blocks before they are canceled automatically
+	// to prevent a force close. Warnings are dropped if the client doesn't keep
+	// up with them.
+	SubscribeHoldExpiryWarnings(*SubscribeHoldExpiryWarningsRequest, Invoices_SubscribeHoldExpiryWarningsServer) error
+	// lncli: `reissueinvoice`
+	// ReissueInvoice regenerates the hop hints of an open, unexpired invoice and
+	// returns a new payment request for the same payment hash and payment
+	// address. All other fields, including the timestamp and expiry, are
+	// carried over from the original payment request, so both remain payable.
+	// The invoice itself is not modified. Invoices with blinded paths can't be
+	// re-issued through this call.
+	ReissueInvoice(context.Context, *ReissueInvoiceRequest) (*ReissueInvoiceResponse, error)
 	mustEmbedUnimplementedInvoicesServer()
 }
 
@@ -207,6 +491,42 @@ func (UnimplementedInvoicesServer) LookupInvoiceV2(context.Context, *LookupInvoi
 func (UnimplementedInvoicesServer) HtlcModifier(Invoices_HtlcModifierServer) error {
 	return status.Errorf(codes.Unimplemented, "method HtlcModifier not implemented")
 }
+func (UnimplementedInvoicesServer) ListWebhookDeadLetters(context.Context, *ListWebhookDeadLettersRequest) (*ListWebhookDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeadLetters not implemented")
+}
+func (UnimplementedInvoicesServer) RetryWebhookDeadLetters(context.Context, *RetryWebhookDeadLettersRequest) (*RetryWebhookDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryWebhookDeadLetters not implemented")
+}
+func (UnimplementedInvoicesServer) AddSubscription(context.Context, *AddSubscriptionRequest) (*InvoiceSubscription, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSubscription not implemented")
+}
+func (UnimplementedInvoicesServer) CancelSubscription(context.Context, *CancelSubscriptionRequest) (*CancelSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelSubscription not implemented")
+}
+func (UnimplementedInvoicesServer) ListSubscriptions(context.Context, *ListSubscriptionsRequest) (*ListSubscriptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptions not implemented")
+}
+func (UnimplementedInvoicesServer) AddStatelessInvoice(context.Context, *AddStatelessInvoiceRequest) (*AddStatelessInvoiceResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddStatelessInvoice not implemented")
+}
+func (UnimplementedInvoicesServer) VerifyStatelessSettlement(context.Context, *VerifyStatelessSettlementRequest) (*VerifyStatelessSettlementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyStatelessSettlement not implemented")
+}
+func (UnimplementedInvoicesServer) SubscribeAMPSet(*SubscribeAMPSetRequest, Invoices_SubscribeAMPSetServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAMPSet not implemented")
+}
+func (UnimplementedInvoicesServer) CancelAMPSet(context.Context, *CancelAMPSetRequest) (*CancelAMPSetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelAMPSet not implemented")
+}
+func (UnimplementedInvoicesServer) ListAMPSets(context.Context, *ListAMPSetsRequest) (*ListAMPSetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAMPSets not implemented")
+}
+func (UnimplementedInvoicesServer) SubscribeHoldExpiryWarnings(*SubscribeHoldExpiryWarningsRequest, Invoices_SubscribeHoldExpiryWarningsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeHoldExpiryWarnings not implemented")
+}
+func (UnimplementedInvoicesServer) ReissueInvoice(context.Context, *ReissueInvoiceRequest) (*ReissueInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReissueInvoice not implemented")
+}
 func (UnimplementedInvoicesServer) mustEmbedUnimplementedInvoicesServer() {}
 
 // UnsafeInvoicesServer may be embedded to opt out of forward compatibility for this service.
@@ -339,6 +659,228 @@ func (x *invoicesHtlcModifierServer) Recv() (*HtlcModifyResponse, error) {
 	return m, nil
 }
 
+func _Invoices_ListWebhookDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).ListWebhookDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/ListWebhookDeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).ListWebhookDeadLetters(ctx, req.(*ListWebhookDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_RetryWebhookDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryWebhookDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).RetryWebhookDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/RetryWebhookDeadLetters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).RetryWebhookDeadLetters(ctx, req.(*RetryWebhookDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_AddSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).AddSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/AddSubscription",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).AddSubscription(ctx, req.(*AddSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_CancelSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).CancelSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/CancelSubscription",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).CancelSubscription(ctx, req.(*CancelSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_ListSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).ListSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/ListSubscriptions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).ListSubscriptions(ctx, req.(*ListSubscriptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_AddStatelessInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddStatelessInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).AddStatelessInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/AddStatelessInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).AddStatelessInvoice(ctx, req.(*AddStatelessInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_VerifyStatelessSettlement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyStatelessSettlementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).VerifyStatelessSettlement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/VerifyStatelessSettlement",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).VerifyStatelessSettlement(ctx, req.(*VerifyStatelessSettlementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_SubscribeAMPSet_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAMPSetRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoicesServer).SubscribeAMPSet(m, &invoicesSubscribeAMPSetServer{stream})
+}
+
+type Invoices_SubscribeAMPSetServer interface {
+	Send(*lnrpc.Invoice) error
+	grpc.ServerStream
+}
+
+type invoicesSubscribeAMPSetServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoicesSubscribeAMPSetServer) Send(m *lnrpc.Invoice) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Invoices_CancelAMPSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelAMPSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).CancelAMPSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/CancelAMPSet",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).CancelAMPSet(ctx, req.(*CancelAMPSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_ListAMPSets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAMPSetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).ListAMPSets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/ListAMPSets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).ListAMPSets(ctx, req.(*ListAMPSetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Invoices_SubscribeHoldExpiryWarnings_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeHoldExpiryWarningsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(InvoicesServer).SubscribeHoldExpiryWarnings(m, &invoicesSubscribeHoldExpiryWarningsServer{stream})
+}
+
+type Invoices_SubscribeHoldExpiryWarningsServer interface {
+	Send(*HoldExpiryWarning) error
+	grpc.ServerStream
+}
+
+type invoicesSubscribeHoldExpiryWarningsServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoicesSubscribeHoldExpiryWarningsServer) Send(m *HoldExpiryWarning) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Invoices_ReissueInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReissueInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InvoicesServer).ReissueInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/invoicesrpc.Invoices/ReissueInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InvoicesServer).ReissueInvoice(ctx, req.(*ReissueInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Invoices_ServiceDesc is the grpc.ServiceDesc for Invoices service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -362,6 +904,46 @@ var Invoices_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LookupInvoiceV2",
 			Handler:    _Invoices_LookupInvoiceV2_Handler,
 		},
+		{
+			MethodName: "ListWebhookDeadLetters",
+			Handler:    _Invoices_ListWebhookDeadLetters_Handler,
+		},
+		{
+			MethodName: "RetryWebhookDeadLetters",
+			Handler:    _Invoices_RetryWebhookDeadLetters_Handler,
+		},
+		{
+			MethodName: "AddSubscription",
+			Handler:    _Invoices_AddSubscription_Handler,
+		},
+		{
+			MethodName: "CancelSubscription",
+			Handler:    _Invoices_CancelSubscription_Handler,
+		},
+		{
+			MethodName: "ListSubscriptions",
+			Handler:    _Invoices_ListSubscriptions_Handler,
+		},
+		{
+			MethodName: "AddStatelessInvoice",
+			Handler:    _Invoices_AddStatelessInvoice_Handler,
+		},
+		{
+			MethodName: "VerifyStatelessSettlement",
+			Handler:    _Invoices_VerifyStatelessSettlement_Handler,
+		},
+		{
+			MethodName: "CancelAMPSet",
+			Handler:    _Invoices_CancelAMPSet_Handler,
+		},
+		{
+			MethodName: "ListAMPSets",
+			Handler:    _Invoices_ListAMPSets_Handler,
+		},
+		{
+			MethodName: "ReissueInvoice",
+			Handler:    _Invoices_ReissueInvoice_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -375,6 +957,16 @@ var Invoices_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "SubscribeAMPSet",
+			Handler:       _Invoices_SubscribeAMPSet_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeHoldExpiryWarnings",
+			Handler:       _Invoices_SubscribeHoldExpiryWarnings_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "invoicesrpc/invoices.proto",
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -33,6 +34,16 @@ var (
 	// ErrServerShuttingDown is returned when the server is shutting down.
 	ErrServerShuttingDown = errors.New("server shutting down")
 
+	// errNoWebhook is returned when the dead letters of the invoice
+	// webhook are requested while no webhook is configured.
+	errNoWebhook = errors.New("no invoice webhook configured, set " +
+		"invoices.webhookurl")
+
+	// errStatelessDisabled is returned when a stateless invoice rpc is
+	// called while stateless invoices are disabled.
+	errStatelessDisabled = errors.New("stateless invoices are disabled, " +
+		"set invoices.stateless")
+
 	// macaroonOps are the set of capabilities that our minted macaroon (if
 	// it doesn't already exist) will have.
 	macaroonOps = []bakery.Op{
@@ -72,6 +83,54 @@ var (
 			Entity: "invoices",
 			Action: "write",
 		}},
+		"/invoicesrpc.Invoices/ListWebhookDeadLetters": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/RetryWebhookDeadLetters": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/AddSubscription": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/CancelSubscription": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/ListSubscriptions": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/AddStatelessInvoice": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/VerifyStatelessSettlement": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/SubscribeAMPSet": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/CancelAMPSet": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/invoicesrpc.Invoices/ListAMPSets": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/SubscribeHoldExpiryWarnings": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/invoicesrpc.Invoices/ReissueInvoice": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
 	}
 
 	// DefaultInvoicesMacFilename is the default name of the invoices
@@ -507,3 +566,389 @@ func (s *Server) HtlcModifier(
 		}
 	}
 }
+
+// ListWebhookDeadLetters returns the invoice events that couldn't be delivered
+// to the webhook.
+func (s *Server) ListWebhookDeadLetters(_ context.Context,
+	_ *ListWebhookDeadLettersRequest) (*ListWebhookDeadLettersResponse,
+	error) {
+
+	if s.cfg.Webhook == nil {
+		return nil, errNoWebhook
+	}
+
+	letters := s.cfg.Webhook.DeadLetters()
+
+	resp := &ListWebhookDeadLettersResponse{
+		DeadLetters: make([]*FailedWebhookEvent, 0, len(letters)),
+	}
+	for _, letter := range letters {
+		resp.DeadLetters = append(
+			resp.DeadLetters, marshallWebhookDeadLetter(letter),
+		)
+	}
+
+	return resp, nil
+}
+
+// RetryWebhookDeadLetters queues the invoice events that couldn't be
+// delivered to the webhook for delivery again.
+func (s *Server) RetryWebhookDeadLetters(_ context.Context,
+	_ *RetryWebhookDeadLettersRequest) (*RetryWebhookDeadLettersResponse,
+	error) {
+
+	if s.cfg.Webhook == nil {
+		return nil, errNoWebhook
+	}
+
+	numRequeued := s.cfg.Webhook.RetryDeadLetters()
+
+	log.Infof("Requeued %d invoice webhook dead letters", numRequeued)
+
+	return &RetryWebhookDeadLettersResponse{
+		NumRequeued: uint32(numRequeued),
+	}, nil
+}
+
+// AddSubscription creates a recurring subscription from an invoice template.
+func (s *Server) AddSubscription(ctx context.Context,
+	req *AddSubscriptionRequest) (*InvoiceSubscription, error) {
+
+	tmpl, err := unmarshallSubscriptionTemplate(req)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.cfg.Subscriptions.AddSubscription(ctx, tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshallSubscription(sub)
+}
+
+// CancelSubscription stops generating invoices for a subscription.
+func (s *Server) CancelSubscription(_ context.Context,
+	req *CancelSubscriptionRequest) (*CancelSubscriptionResponse, error) {
+
+	id, err := parseSubscriptionID(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cfg.Subscriptions.CancelSubscription(id); err != nil {
+		return nil, err
+	}
+
+	return &CancelSubscriptionResponse{}, nil
+}
+
+// ListSubscriptions returns all subscriptions, or a single subscription, along
+// with the payment status of their periods.
+func (s *Server) ListSubscriptions(ctx context.Context,
+	req *ListSubscriptionsRequest) (*ListSubscriptionsResponse, error) {
+
+	var statuses []*invoices.SubscriptionStatus
+	if len(req.Id) != 0 {
+		id, err := parseSubscriptionID(req.Id)
+		if err != nil {
+			return nil, err
+		}
+
+		status, err := s.cfg.Subscriptions.SubscriptionStatus(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		statuses = []*invoices.SubscriptionStatus{status}
+	} else {
+		var err error
+		statuses, err = s.cfg.Subscriptions.Subscriptions(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &ListSubscriptionsResponse{
+		Subscriptions: make([]*InvoiceSubscription, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		rpcSub, err := marshallSubscriptionStatus(status)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Subscriptions = append(resp.Subscriptions, rpcSub)
+	}
+
+	return resp, nil
+}
+
+// AddStatelessInvoice creates a stateless invoice, which isn't stored until it
+// is paid.
+func (s *Server) AddStatelessInvoice(ctx context.Context,
+	req *AddStatelessInvoiceRequest) (*AddStatelessInvoiceResp, error) {
+
+	secret := s.cfg.InvoiceRegistry.StatelessSecret()
+	if secret == nil {
+		return nil, errStatelessDisabled
+	}
+
+	value, err := lnrpc.UnmarshallAmt(req.Value, req.ValueMsat)
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert the passed routing hints to the required format.
+	routeHints, err := CreateZpay32HopHints(req.RouteHints)
+	if err != nil {
+		return nil, err
+	}
+
+	addInvoiceCfg := &AddInvoiceConfig{
+		IsChannelActive:       s.cfg.IsChannelActive,
+		ChainParams:           s.cfg.ChainParams,
+		NodeSigner:            s.cfg.NodeSigner,
+		DefaultCLTVExpiry:     s.cfg.DefaultCLTVExpiry,
+		ChanDB:                s.cfg.ChanStateDB,
+		Graph:                 s.cfg.GraphDB,
+		GenInvoiceFeatures:    s.cfg.GenInvoiceFeatures,
+		GenAmpInvoiceFeatures: s.cfg.GenAmpInvoiceFeatures,
+		GetAlias:              s.cfg.GetAlias,
+	}
+	addInvoiceData := &AddInvoiceData{
+		Memo:            req.Memo,
+		Value:           value,
+		DescriptionHash: req.DescriptionHash,
+		Expiry:          req.Expiry,
+		FallbackAddr:    req.FallbackAddr,
+		CltvExpiry:      req.CltvExpiry,
+		Private:         req.Private,
+		RouteHints:      routeHints,
+		StatelessSecret: secret,
+	}
+
+	hash, invoice, err := AddInvoice(ctx, addInvoiceCfg, addInvoiceData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddStatelessInvoiceResp{
+		PaymentRequest: string(invoice.PaymentRequest),
+		PaymentHash:    hash[:],
+		PaymentAddr:    invoice.Terms.PaymentAddr[:],
+	}, nil
+}
+
+// VerifyStatelessSettlement verifies that a payment address was issued as a
+// stateless invoice for the given amount and returns its settlement state.
+func (s *Server) VerifyStatelessSettlement(ctx context.Context,
+	req *VerifyStatelessSettlementRequest) (
+	*VerifyStatelessSettlementResponse, error) {
+
+	secret := s.cfg.InvoiceRegistry.StatelessSecret()
+	if secret == nil {
+		return nil, errStatelessDisabled
+	}
+
+	if len(req.PaymentAddr) != 32 {
+		return nil, status.Errorf(codes.InvalidArgument, "payment "+
+			"address must be 32 bytes, got %d",
+			len(req.PaymentAddr))
+	}
+
+	var payAddr [32]byte
+	copy(payAddr[:], req.PaymentAddr)
+
+	settlement, err := VerifyStatelessSettlement(
+		ctx, s.cfg.InvoiceRegistry.LookupInvoiceByRef, *secret,
+		payAddr, lnwire.MilliSatoshi(req.ValueMsat),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &VerifyStatelessSettlementResponse{
+		PaymentHash: settlement.PaymentHash[:],
+		Preimage:    settlement.Preimage[:],
+		Settled:     settlement.Settled,
+		AmtPaidMsat: uint64(settlement.AmtPaid),
+	}
+	if settlement.Settled {
+		resp.SettleDate = settlement.SettleDate.Unix()
+	}
+
+	return resp, nil
+}
+
+// SubscribeAMPSet returns a uni-directional stream (server -> client) for
+// notifying the client of state changes of a single AMP set.
+func (s *Server) SubscribeAMPSet(req *SubscribeAMPSetRequest,
+	updateStream Invoices_SubscribeAMPSetServer) error {
+
+	setID, err := parseSetID(req.SetId)
+	if err != nil {
+		return err
+	}
+
+	setClient, err := s.cfg.InvoiceRegistry.SubscribeAMPSet(
+		updateStream.Context(), setID,
+	)
+	if err != nil {
+		return err
+	}
+	defer setClient.Cancel()
+
+	log.Debugf("Created new AMP set(set_id=%x) subscription", setID[:])
+
+	for {
+		select {
+		case newInvoice := <-setClient.Updates:
+			rpcInvoice, err := CreateRPCInvoice(
+				newInvoice, s.cfg.ChainParams,
+			)
+			if err != nil {
+				return err
+			}
+
+			// Give the aux data parser a chance to format the
+			// custom data in the invoice HTLCs.
+			err = s.cfg.ParseAuxData(rpcInvoice)
+			if err != nil {
+				return fmt.Errorf("error parsing custom data: "+
+					"%w", err)
+			}
+
+			if err := updateStream.Send(rpcInvoice); err != nil {
+				return err
+			}
+
+			// If the set or the invoice reached a terminal state,
+			// close the stream with no error.
+			setState, ok := newInvoice.AMPState[setID]
+			setFinal := ok &&
+				setState.State != invoices.HtlcStateAccepted
+			if setFinal || newInvoice.State.IsFinal() {
+				return nil
+			}
+
+		case <-updateStream.Context().Done():
+			return fmt.Errorf("subscription for AMP "+
+				"set(set_id=%x): %w", setID[:],
+				updateStream.Context().Err())
+
+		case <-s.quit:
+			return nil
+		}
+	}
+}
+
+// CancelAMPSet cancels the accepted htlcs of a pending AMP set.
+func (s *Server) CancelAMPSet(ctx context.Context,
+	req *CancelAMPSetRequest) (*CancelAMPSetResponse, error) {
+
+	setID, err := parseSetID(req.SetId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cfg.InvoiceRegistry.CancelAMPSet(ctx, setID); err != nil {
+		return nil, err
+	}
+
+	return &CancelAMPSetResponse{}, nil
+}
+
+// ListAMPSets returns the pending sets of an AMP invoice along with a page of
+// its settled sets.
+func (s *Server) ListAMPSets(ctx context.Context,
+	req *ListAMPSetsRequest) (*ListAMPSetsResponse, error) {
+
+	hash, err := lntypes.MakeHash(req.PaymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice, err := s.cfg.InvoiceRegistry.LookupInvoice(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return marshallAMPSetList(&invoice, invoices.AMPSetQuery{
+		IndexOffset: req.IndexOffset,
+		NumMaxSets:  req.NumMaxSets,
+		Reversed:    req.Reversed,
+	})
+}
+
+// SubscribeHoldExpiryWarnings returns a uni-directional stream (server ->
+// client) of warnings for accepted hold invoices that are about to be
+// canceled automatically.
+func (s *Server) SubscribeHoldExpiryWarnings(
+	_ *SubscribeHoldExpiryWarningsRequest,
+	updateStream Invoices_SubscribeHoldExpiryWarningsServer) error {
+
+	client := s.cfg.InvoiceRegistry.SubscribeHoldExpiryWarnings()
+	defer client.Cancel()
+
+	log.Debugf("Created new hold expiry warning subscription")
+
+	for {
+		select {
+		case warning := <-client.Warnings:
+			err := updateStream.Send(
+				marshallHoldExpiryWarning(warning),
+			)
+			if err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return fmt.Errorf("hold expiry warning subscription: "+
+				"%w", updateStream.Context().Err())
+
+		case <-s.quit:
+			return nil
+		}
+	}
+}
+
+// ReissueInvoice regenerates the hop hints of an open, unexpired invoice and
+// returns a new payment request for the same payment hash and payment address.
+func (s *Server) ReissueInvoice(ctx context.Context,
+	req *ReissueInvoiceRequest) (*ReissueInvoiceResponse, error) {
+
+	hash, err := lntypes.MakeHash(req.PaymentHash)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data, err := unmarshallReissueInvoiceData(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	invoice, err := s.cfg.InvoiceRegistry.LookupInvoice(ctx, hash)
+	if err != nil {
+		return nil, reissueRPCError(err)
+	}
+
+	addInvoiceCfg := &AddInvoiceConfig{
+		IsChannelActive: s.cfg.IsChannelActive,
+		ChainParams:     s.cfg.ChainParams,
+		NodeSigner:      s.cfg.NodeSigner,
+		ChanDB:          s.cfg.ChanStateDB,
+		Graph:           s.cfg.GraphDB,
+		GetAlias:        s.cfg.GetAlias,
+	}
+
+	payReq, err := ReissueInvoice(addInvoiceCfg, &invoice, data)
+	if err != nil {
+		return nil, reissueRPCError(err)
+	}
+
+	log.Infof("Re-issued payment request of invoice %v", hash)
+
+	return &ReissueInvoiceResponse{
+		PaymentRequest: payReq,
+	}, nil
+}
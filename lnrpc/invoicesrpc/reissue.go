@@ -0,0 +1,212 @@
+package invoicesrpc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrInvoiceNotOpen is returned when the payment request of an invoice
+	// that is no longer open is re-issued.
+	ErrInvoiceNotOpen = errors.New("invoice is not open")
+
+	// ErrInvoiceExpired is returned when the payment request of an expired
+	// invoice is re-issued.
+	ErrInvoiceExpired = errors.New("invoice is expired")
+)
+
+// ReissueInvoiceData contains the routing information to use when re-issuing
+// the payment request of an invoice.
+type ReissueInvoiceData struct {
+	// Private indicates whether the re-issued payment request should
+	// include hop hints for our private channels.
+	Private bool
+
+	// RouteHints are optional route hints to include in the re-issued
+	// payment request.
+	RouteHints [][]zpay32.HopHint
+
+	// BlindedPathCfg holds the config values used to construct new
+	// blinded paths. It must be set if and only if the invoice uses
+	// blinded paths.
+	BlindedPathCfg *BlindedPathConfig
+}
+
+// ReissueInvoice regenerates the hop hints or blinded paths of an open,
+// unexpired invoice, which is useful when the channels that were hinted at
+// are no longer usable. The returned payment request pays to the same payment
+// hash and payment address, and keeps the amount, description, features and
+// expiry of the original one, so that both remain valid. The invoice itself
+// is not modified.
+func ReissueInvoice(cfg *AddInvoiceConfig, invoice *invoices.Invoice,
+	data *ReissueInvoiceData) (string, error) {
+
+	if invoice.State != invoices.ContractOpen {
+		return "", ErrInvoiceNotOpen
+	}
+
+	if len(invoice.PaymentRequest) == 0 {
+		return "", errors.New("invoice has no payment request")
+	}
+
+	payReq, err := zpay32.Decode(
+		string(invoice.PaymentRequest), cfg.ChainParams,
+	)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode payment request: %w",
+			err)
+	}
+
+	expiry := payReq.Expiry()
+	if time.Now().After(payReq.Timestamp.Add(expiry)) {
+		return "", ErrInvoiceExpired
+	}
+
+	blind := len(payReq.BlindedPaymentPaths) > 0
+	switch {
+	case blind && data.BlindedPathCfg == nil:
+		return "", errors.New("invoice uses blinded paths, but no " +
+			"blinded path config was provided")
+
+	case !blind && data.BlindedPathCfg != nil:
+		return "", errors.New("can't add blinded paths to an invoice " +
+			"that doesn't use them")
+
+	case blind && (len(data.RouteHints) > 0 || data.Private):
+		return "", errors.New("can't set both hop hints and add " +
+			"blinded payment paths")
+
+	case len(data.RouteHints) > maxHopHints:
+		return "", fmt.Errorf("number of routing hints must not "+
+			"exceed maximum of %v", maxHopHints)
+	}
+
+	// Carry over all fields of the original payment request, except for
+	// the routing information.
+	options := []func(*zpay32.Invoice){
+		zpay32.Expiry(expiry),
+		zpay32.Features(payReq.Features),
+	}
+	if payReq.MilliSat != nil {
+		options = append(options, zpay32.Amount(*payReq.MilliSat))
+	}
+	if payReq.FallbackAddr != nil {
+		options = append(
+			options, zpay32.FallbackAddr(payReq.FallbackAddr),
+		)
+	}
+	if descHash := payReq.DescriptionHash; descHash != nil {
+		options = append(options, zpay32.DescriptionHash(*descHash))
+	} else if payReq.Description != nil {
+		options = append(
+			options, zpay32.Description(*payReq.Description),
+		)
+	}
+	if payReq.Metadata != nil {
+		options = append(options, zpay32.Metadata(payReq.Metadata))
+	}
+
+	amtMSat := invoice.Terms.Value
+	cltvExpiryDelta := payReq.MinFinalCLTVExpiry()
+
+	if blind {
+		// The payment address of the invoice is used as the path id
+		// of the blinded paths, so payments through the new paths
+		// are matched to the invoice.
+		paths, err := buildBlindedPaths(
+			cfg, data.BlindedPathCfg, invoice.Terms.PaymentAddr,
+			amtMSat, expiry, cltvExpiryDelta,
+		)
+		if err != nil {
+			return "", err
+		}
+
+		for _, path := range paths {
+			options = append(options, zpay32.WithBlindedPaymentPath(
+				path,
+			))
+		}
+	} else {
+		options = append(
+			options, zpay32.CLTVExpiry(cltvExpiryDelta),
+			zpay32.PaymentAddr(invoice.Terms.PaymentAddr),
+		)
+	}
+
+	if len(data.RouteHints) > 0 || data.Private {
+		for _, hint := range data.RouteHints {
+			if len(hint) == 0 {
+				return "", fmt.Errorf("number of hop hint " +
+					"within a route must be positive")
+			}
+		}
+
+		totalHopHints := len(data.RouteHints)
+		if data.Private {
+			totalHopHints = maxHopHints
+		}
+
+		hopHintsCfg := newSelectHopHintsCfg(cfg, totalHopHints)
+		hopHints, err := PopulateHopHints(
+			hopHintsCfg, amtMSat, data.RouteHints,
+		)
+		if err != nil {
+			return "", fmt.Errorf("unable to populate hop "+
+				"hints: %w", err)
+		}
+
+		for _, hopHint := range hopHints {
+			options = append(options, zpay32.RouteHint(hopHint))
+		}
+	}
+
+	// We keep the timestamp of the original payment request, so that the
+	// re-issued one expires at the same time.
+	newPayReq, err := zpay32.NewInvoice(
+		cfg.ChainParams, *payReq.PaymentHash, payReq.Timestamp,
+		options...,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return encodePaymentRequest(cfg, newPayReq, blind)
+}
+
+// unmarshallReissueInvoiceData converts the routing information of an RPC
+// re-issue request.
+func unmarshallReissueInvoiceData(
+	req *ReissueInvoiceRequest) (*ReissueInvoiceData, error) {
+
+	routeHints, err := CreateZpay32HopHints(req.RouteHints)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReissueInvoiceData{
+		Private:    req.Private,
+		RouteHints: routeHints,
+	}, nil
+}
+
+// reissueRPCError maps the errors of a re-issue to gRPC status errors.
+func reissueRPCError(err error) error {
+	switch {
+	case errors.Is(err, invoices.ErrInvoiceNotFound):
+		return status.Error(codes.NotFound, err.Error())
+
+	case errors.Is(err, ErrInvoiceNotOpen),
+		errors.Is(err, ErrInvoiceExpired):
+
+		return status.Error(codes.FailedPrecondition, err.Error())
+
+	default:
+		return err
+	}
+}
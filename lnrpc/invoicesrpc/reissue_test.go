@@ -0,0 +1,180 @@
+package invoicesrpc
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestReissueInvoice tests that a re-issued payment request keeps all fields
+// of the original one, except for its route hints.
+func TestReissueInvoice(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	cfg := &AddInvoiceConfig{
+		ChainParams: &chaincfg.RegressionNetParams,
+		NodeSigner: netann.NewNodeSigner(
+			keychain.NewPrivKeyMessageSigner(
+				privKey, keychain.KeyLocator{},
+			),
+		),
+	}
+
+	var (
+		hash    = [32]byte{1}
+		payAddr = [32]byte{2}
+		amt     = lnwire.MilliSatoshi(10_000)
+	)
+
+	features := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.TLVOnionPayloadRequired,
+			lnwire.PaymentAddrRequired,
+		), lnwire.Features,
+	)
+
+	oldHint := []zpay32.HopHint{{
+		NodeID:    getTestPubKey(),
+		ChannelID: 1,
+	}}
+
+	payReq, err := zpay32.NewInvoice(
+		cfg.ChainParams, hash, time.Now(), zpay32.Amount(amt),
+		zpay32.Description("test"), zpay32.Expiry(time.Hour),
+		zpay32.CLTVExpiry(40), zpay32.PaymentAddr(payAddr),
+		zpay32.Features(features), zpay32.RouteHint(oldHint),
+	)
+	require.NoError(t, err)
+
+	encoded, err := encodePaymentRequest(cfg, payReq, false)
+	require.NoError(t, err)
+
+	invoice := &invoices.Invoice{
+		PaymentRequest: []byte(encoded),
+		State:          invoices.ContractOpen,
+		Terms: invoices.ContractTerm{
+			Value:       amt,
+			PaymentAddr: payAddr,
+		},
+	}
+
+	newHint := []zpay32.HopHint{{
+		NodeID:          getTestPubKey(),
+		ChannelID:       2,
+		FeeBaseMSat:     1000,
+		CLTVExpiryDelta: 80,
+	}}
+	reissued, err := ReissueInvoice(cfg, invoice, &ReissueInvoiceData{
+		RouteHints: [][]zpay32.HopHint{newHint},
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, encoded, reissued)
+
+	oldPayReq, err := zpay32.Decode(encoded, cfg.ChainParams)
+	require.NoError(t, err)
+	newPayReq, err := zpay32.Decode(reissued, cfg.ChainParams)
+	require.NoError(t, err)
+
+	require.Len(t, newPayReq.RouteHints, 1)
+	require.Len(t, newPayReq.RouteHints[0], 1)
+
+	hint := newPayReq.RouteHints[0][0]
+	require.True(t, hint.NodeID.IsEqual(newHint[0].NodeID))
+	require.Equal(t, newHint[0].ChannelID, hint.ChannelID)
+	require.Equal(t, newHint[0].FeeBaseMSat, hint.FeeBaseMSat)
+	require.Equal(t, newHint[0].CLTVExpiryDelta, hint.CLTVExpiryDelta)
+
+	// Apart from the route hints, both payment requests should be
+	// identical.
+	newPayReq.RouteHints = oldPayReq.RouteHints
+	require.Equal(t, oldPayReq, newPayReq)
+
+	// A settled invoice can't be re-issued.
+	invoice.State = invoices.ContractSettled
+	_, err = ReissueInvoice(cfg, invoice, &ReissueInvoiceData{})
+	require.ErrorIs(t, err, ErrInvoiceNotOpen)
+
+	// Neither can an expired one.
+	payReq.Timestamp = time.Now().Add(-2 * time.Hour)
+	encoded, err = encodePaymentRequest(cfg, payReq, false)
+	require.NoError(t, err)
+
+	invoice.State = invoices.ContractOpen
+	invoice.PaymentRequest = []byte(encoded)
+	_, err = ReissueInvoice(cfg, invoice, &ReissueInvoiceData{})
+	require.ErrorIs(t, err, ErrInvoiceExpired)
+}
+
+// TestUnmarshallReissueInvoiceData tests that the route hints of an RPC
+// re-issue request are converted, and that invalid hints are rejected.
+func TestUnmarshallReissueInvoiceData(t *testing.T) {
+	t.Parallel()
+
+	pubKey := getTestPubKey()
+	req := &ReissueInvoiceRequest{
+		Private: true,
+		RouteHints: []*lnrpc.RouteHint{{
+			HopHints: []*lnrpc.HopHint{{
+				NodeId: hex.EncodeToString(
+					pubKey.SerializeCompressed(),
+				),
+				ChanId:                    7,
+				FeeBaseMsat:               1000,
+				FeeProportionalMillionths: 10,
+				CltvExpiryDelta:           80,
+			}},
+		}},
+	}
+
+	data, err := unmarshallReissueInvoiceData(req)
+	require.NoError(t, err)
+	require.True(t, data.Private)
+	require.Nil(t, data.BlindedPathCfg)
+	require.Len(t, data.RouteHints, 1)
+	require.Len(t, data.RouteHints[0], 1)
+
+	hint := data.RouteHints[0][0]
+	require.True(t, hint.NodeID.IsEqual(pubKey))
+	require.EqualValues(t, 7, hint.ChannelID)
+	require.EqualValues(t, 1000, hint.FeeBaseMSat)
+	require.EqualValues(t, 10, hint.FeeProportionalMillionths)
+	require.EqualValues(t, 80, hint.CLTVExpiryDelta)
+
+	req.RouteHints[0].HopHints[0].NodeId = "invalid"
+	_, err = unmarshallReissueInvoiceData(req)
+	require.Error(t, err)
+}
+
+// TestReissueRPCError tests that the errors of a re-issue are mapped to the
+// matching gRPC status codes.
+func TestReissueRPCError(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(
+		t, codes.NotFound,
+		status.Code(reissueRPCError(invoices.ErrInvoiceNotFound)),
+	)
+	require.Equal(
+		t, codes.FailedPrecondition,
+		status.Code(reissueRPCError(ErrInvoiceNotOpen)),
+	)
+	require.Equal(
+		t, codes.FailedPrecondition,
+		status.Code(reissueRPCError(ErrInvoiceExpired)),
+	)
+}
@@ -0,0 +1,124 @@
+package invoicesrpc
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// ErrInvalidStatelessPayAddr is returned when a payment address wasn't issued
+// with our stateless secret for the given amount.
+var ErrInvalidStatelessPayAddr = errors.New("payment address wasn't issued " +
+	"as stateless invoice for this amount")
+
+// statelessPayment generates a random stateless payment address for the
+// invoice and derives its preimage from it. Only the fields that are part of
+// the payment request may be set, because the invoice that the registry
+// inserts once it is paid only consists of its payment address, value and
+// preimage.
+func (d *AddInvoiceData) statelessPayment() ([32]byte, *lntypes.Preimage,
+	error) {
+
+	var payAddr [32]byte
+
+	switch {
+	case d.Value == 0:
+		return payAddr, nil, errors.New("stateless invoices must " +
+			"specify a value")
+
+	case d.Preimage != nil || d.Hash != nil || d.HodlInvoice:
+		return payAddr, nil, errors.New("the preimage of stateless " +
+			"invoices is derived from their payment address")
+
+	case d.Amp:
+		return payAddr, nil, errors.New("stateless AMP invoices are " +
+			"not supported")
+
+	case d.BlindedPathCfg != nil:
+		return payAddr, nil, errors.New("stateless invoices with " +
+			"blinded paths are not supported")
+
+	case d.PartialPayment.IsEnabled() || d.HtlcConstraints.IsEnabled() ||
+		d.FiatQuote != nil || d.FiatCurrency != "":
+
+		return payAddr, nil, errors.New("stateless invoices can't " +
+			"carry a partial payment policy, htlc constraints or " +
+			"fiat quote")
+	}
+
+	var nonce [invoices.StatelessNonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return payAddr, nil, err
+	}
+
+	payAddr = d.StatelessSecret.PaymentAddr(nonce, d.Value)
+	preimage := d.StatelessSecret.Preimage(payAddr)
+
+	return payAddr, &preimage, nil
+}
+
+// StatelessSettlement describes the settlement state of a stateless invoice.
+type StatelessSettlement struct {
+	// PaymentHash is the payment hash of the stateless invoice.
+	PaymentHash lntypes.Hash
+
+	// Preimage is the preimage that is derived from the payment address.
+	Preimage lntypes.Preimage
+
+	// Settled indicates whether the invoice has been paid and settled.
+	Settled bool
+
+	// AmtPaid is the amount that was paid to the invoice.
+	AmtPaid lnwire.MilliSatoshi
+
+	// SettleDate is the time at which the invoice was settled.
+	SettleDate time.Time
+}
+
+// VerifyStatelessSettlement verifies that the payment address was issued with
+// the stateless secret for an invoice of the given amount, and returns the
+// preimage derived from it along with the settlement state of the invoice.
+// Stateless invoices are only stored once they are paid, so an invoice that
+// isn't found is reported as unsettled.
+func VerifyStatelessSettlement(ctx context.Context,
+	lookupInvoice func(context.Context, invoices.InvoiceRef) (
+		invoices.Invoice, error),
+	secret invoices.StatelessSecret, payAddr [32]byte,
+	amt lnwire.MilliSatoshi) (*StatelessSettlement, error) {
+
+	if !secret.VerifyPaymentAddr(payAddr, amt) {
+		return nil, ErrInvalidStatelessPayAddr
+	}
+
+	preimage := secret.Preimage(payAddr)
+	settlement := &StatelessSettlement{
+		PaymentHash: preimage.Hash(),
+		Preimage:    preimage,
+	}
+
+	invoice, err := lookupInvoice(
+		ctx, invoices.InvoiceRefByHashAndAddr(
+			settlement.PaymentHash, payAddr,
+		),
+	)
+	switch {
+	case errors.Is(err, invoices.ErrInvoiceNotFound):
+		return settlement, nil
+
+	case err != nil:
+		return nil, err
+	}
+
+	if invoice.State == invoices.ContractSettled {
+		settlement.Settled = true
+		settlement.AmtPaid = invoice.AmtPaid
+		settlement.SettleDate = invoice.SettleDate
+	}
+
+	return settlement, nil
+}
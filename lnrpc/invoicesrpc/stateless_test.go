@@ -0,0 +1,153 @@
+package invoicesrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+// TestVerifyStatelessSettlement tests that the settlement of stateless
+// invoices is verified from the payment address alone.
+func TestVerifyStatelessSettlement(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx        = context.Background()
+		secret     = invoices.StatelessSecret{1}
+		payAddr    = secret.PaymentAddr([16]byte{2}, 1000)
+		preimage   = secret.Preimage(payAddr)
+		settleDate = time.Unix(1000, 0)
+	)
+
+	settled := invoices.Invoice{
+		State:      invoices.ContractSettled,
+		AmtPaid:    1000,
+		SettleDate: settleDate,
+	}
+
+	notFound := func(context.Context, invoices.InvoiceRef) (
+		invoices.Invoice, error) {
+
+		return invoices.Invoice{}, invoices.ErrInvoiceNotFound
+	}
+	found := func(_ context.Context, ref invoices.InvoiceRef) (
+		invoices.Invoice, error) {
+
+		require.Equal(t, preimage.Hash(), *ref.PayHash())
+		require.Equal(t, payAddr, *ref.PayAddr())
+
+		return settled, nil
+	}
+
+	// A payment address that wasn't issued for the amount is rejected.
+	_, err := VerifyStatelessSettlement(ctx, found, secret, payAddr, 999)
+	require.ErrorIs(t, err, ErrInvalidStatelessPayAddr)
+
+	// An invoice that was never paid isn't stored and is reported as
+	// unsettled.
+	settlement, err := VerifyStatelessSettlement(
+		ctx, notFound, secret, payAddr, 1000,
+	)
+	require.NoError(t, err)
+	require.Equal(t, &StatelessSettlement{
+		PaymentHash: preimage.Hash(),
+		Preimage:    preimage,
+	}, settlement)
+
+	// A settled invoice is reported with its settlement details.
+	settlement, err = VerifyStatelessSettlement(
+		ctx, found, secret, payAddr, 1000,
+	)
+	require.NoError(t, err)
+	require.Equal(t, &StatelessSettlement{
+		PaymentHash: preimage.Hash(),
+		Preimage:    preimage,
+		Settled:     true,
+		AmtPaid:     1000,
+		SettleDate:  settleDate,
+	}, settlement)
+}
+
+// TestAddStatelessInvoice tests that stateless invoices are issued with a
+// payment address and preimage derived from the stateless secret, and that
+// they aren't stored.
+func TestAddStatelessInvoice(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	secret := invoices.StatelessSecret{1}
+	cfg := &AddInvoiceConfig{
+		AddInvoice: func(context.Context, *invoices.Invoice,
+			lntypes.Hash) (uint64, error) {
+
+			t.Fatal("stateless invoice was stored")
+
+			return 0, nil
+		},
+		ChainParams: &chaincfg.RegressionNetParams,
+		NodeSigner: netann.NewNodeSigner(
+			keychain.NewPrivKeyMessageSigner(
+				privKey, keychain.KeyLocator{},
+			),
+		),
+		DefaultCLTVExpiry: 80,
+		GenInvoiceFeatures: func() *lnwire.FeatureVector {
+			return lnwire.EmptyFeatureVector()
+		},
+	}
+
+	hash, invoice, err := AddInvoice(
+		context.Background(), cfg, &AddInvoiceData{
+			Memo:            "stateless",
+			Value:           1000,
+			StatelessSecret: &secret,
+		},
+	)
+	require.NoError(t, err)
+
+	payAddr := invoice.Terms.PaymentAddr
+	require.True(t, secret.VerifyPaymentAddr(payAddr, 1000))
+	preimage := secret.Preimage(payAddr)
+	require.Equal(t, preimage.Hash(), *hash)
+
+	payReq, err := zpay32.Decode(
+		string(invoice.PaymentRequest), cfg.ChainParams,
+	)
+	require.NoError(t, err)
+	require.Equal(t, payAddr, payReq.PaymentAddr.UnwrapOrFail(t))
+	require.Equal(t, *hash, lntypes.Hash(*payReq.PaymentHash))
+
+	// Invoices that need more than the payment address, value and
+	// preimage to be settled can't be issued as stateless invoices.
+	invalid := []*AddInvoiceData{
+		{},
+		{Value: 1000, Preimage: &lntypes.Preimage{}},
+		{Value: 1000, Amp: true},
+		{Value: 1000, BlindedPathCfg: &BlindedPathConfig{}},
+		{Value: 1000, FiatCurrency: "USD"},
+		{
+			Value: 1000,
+			PartialPayment: invoices.PartialPaymentPolicy{
+				Tolerance: 10,
+			},
+		},
+	}
+	for _, data := range invalid {
+		data.StatelessSecret = &secret
+
+		_, _, err := AddInvoice(context.Background(), cfg, data)
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,122 @@
+package invoicesrpc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// errInvalidSubscriptionID is returned when a subscription id of an invalid
+// length is passed.
+var errInvalidSubscriptionID = errors.New("subscription id must be 32 " +
+	"bytes")
+
+// parseSubscriptionID parses the id of a subscription.
+func parseSubscriptionID(rawID []byte) (invoices.SubscriptionID, error) {
+	var id invoices.SubscriptionID
+	if len(rawID) != len(id) {
+		return id, errInvalidSubscriptionID
+	}
+	copy(id[:], rawID)
+
+	return id, nil
+}
+
+// unmarshallSubscriptionTemplate creates the template of a new subscription
+// from its rpc representation.
+func unmarshallSubscriptionTemplate(
+	req *AddSubscriptionRequest) (invoices.SubscriptionTemplate, error) {
+
+	if req.Expiry < 0 {
+		return invoices.SubscriptionTemplate{}, errors.New("expiry " +
+			"must not be negative")
+	}
+
+	tmpl := invoices.SubscriptionTemplate{
+		Memo:       req.Memo,
+		Value:      lnwire.MilliSatoshi(req.ValueMsat),
+		Period:     time.Duration(req.PeriodSeconds) * time.Second,
+		NumPeriods: req.NumPeriods,
+		Expiry:     time.Duration(req.Expiry) * time.Second,
+		CltvExpiry: req.CltvExpiry,
+		Private:    req.Private,
+	}
+	if req.StartTime != 0 {
+		tmpl.StartTime = time.Unix(req.StartTime, 0)
+	}
+
+	return tmpl, nil
+}
+
+// marshallSubscriptionState converts the state of a subscription to its rpc
+// representation.
+func marshallSubscriptionState(
+	state invoices.SubscriptionState) (SubscriptionState, error) {
+
+	switch state {
+	case invoices.SubscriptionActive:
+		return SubscriptionState_SUBSCRIPTION_ACTIVE, nil
+
+	case invoices.SubscriptionCanceled:
+		return SubscriptionState_SUBSCRIPTION_CANCELED, nil
+
+	case invoices.SubscriptionCompleted:
+		return SubscriptionState_SUBSCRIPTION_COMPLETED, nil
+
+	default:
+		return 0, fmt.Errorf("unknown subscription state %v", state)
+	}
+}
+
+// marshallSubscription converts a subscription to its rpc representation.
+func marshallSubscription(
+	sub *invoices.Subscription) (*InvoiceSubscription, error) {
+
+	state, err := marshallSubscriptionState(sub.State)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := sub.Template
+
+	return &InvoiceSubscription{
+		Id:            sub.ID[:],
+		Memo:          tmpl.Memo,
+		ValueMsat:     uint64(tmpl.Value),
+		PeriodSeconds: uint64(tmpl.Period / time.Second),
+		StartTime:     tmpl.StartTime.Unix(),
+		NumPeriods:    tmpl.NumPeriods,
+		State:         state,
+	}, nil
+}
+
+// marshallSubscriptionStatus converts a subscription along with the payment
+// status of its periods to its rpc representation.
+func marshallSubscriptionStatus(
+	status *invoices.SubscriptionStatus) (*InvoiceSubscription, error) {
+
+	rpcSub, err := marshallSubscription(status.Subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, period := range status.Periods {
+		state, err := CreateRPCInvoiceState(period.State)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcSub.Periods = append(rpcSub.Periods, &SubscriptionPeriod{
+			Period:      period.Period,
+			StartTime:   period.Start.Unix(),
+			PaymentHash: period.PaymentHash[:],
+			State:       state,
+			AmtPaidMsat: uint64(period.AmtPaid),
+		})
+	}
+
+	return rpcSub, nil
+}
@@ -0,0 +1,73 @@
+package invoicesrpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshallSubscription tests the conversion of subscriptions between their
+// rpc and internal representations.
+func TestMarshallSubscription(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1_700_000_000, 0)
+	tmpl, err := unmarshallSubscriptionTemplate(&AddSubscriptionRequest{
+		Memo:          "coffee",
+		ValueMsat:     10_000,
+		PeriodSeconds: 3600,
+		StartTime:     start.Unix(),
+		NumPeriods:    12,
+		Expiry:        600,
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, tmpl.Period)
+	require.Equal(t, 10*time.Minute, tmpl.Expiry)
+	require.True(t, start.Equal(tmpl.StartTime))
+
+	_, err = unmarshallSubscriptionTemplate(&AddSubscriptionRequest{
+		Expiry: -1,
+	})
+	require.Error(t, err)
+
+	sub := &invoices.Subscription{
+		ID:       invoices.SubscriptionID{1},
+		Template: tmpl,
+		State:    invoices.SubscriptionCanceled,
+	}
+	status := &invoices.SubscriptionStatus{
+		Subscription: sub,
+		Periods: []invoices.SubscriptionPeriod{{
+			Period:      1,
+			Start:       tmpl.PeriodStart(1),
+			PaymentHash: lntypes.Hash{2},
+			State:       invoices.ContractSettled,
+			AmtPaid:     10_000,
+		}},
+	}
+
+	rpcSub, err := marshallSubscriptionStatus(status)
+	require.NoError(t, err)
+	require.Equal(t, sub.ID[:], rpcSub.Id)
+	require.EqualValues(t, 3600, rpcSub.PeriodSeconds)
+	require.Equal(t, start.Unix(), rpcSub.StartTime)
+	require.Equal(
+		t, SubscriptionState_SUBSCRIPTION_CANCELED, rpcSub.State,
+	)
+	require.Len(t, rpcSub.Periods, 1)
+	require.Equal(t, start.Add(time.Hour).Unix(),
+		rpcSub.Periods[0].StartTime)
+	require.Equal(t, lnrpc.Invoice_SETTLED, rpcSub.Periods[0].State)
+
+	// The id of a subscription must be 32 bytes.
+	id, err := parseSubscriptionID(rpcSub.Id)
+	require.NoError(t, err)
+	require.Equal(t, sub.ID, id)
+
+	_, err = parseSubscriptionID(rpcSub.Id[:31])
+	require.ErrorIs(t, err, errInvalidSubscriptionID)
+}
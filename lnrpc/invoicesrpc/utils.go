@@ -3,12 +3,14 @@ package invoicesrpc
 import (
 	"encoding/hex"
 	"fmt"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/invoices"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/zpay32"
 )
 
@@ -78,23 +80,9 @@ func CreateRPCInvoice(invoice *invoices.Invoice,
 
 	isSettled := invoice.State == invoices.ContractSettled
 
-	var state lnrpc.Invoice_InvoiceState
-	switch invoice.State {
-	case invoices.ContractOpen:
-		state = lnrpc.Invoice_OPEN
-
-	case invoices.ContractSettled:
-		state = lnrpc.Invoice_SETTLED
-
-	case invoices.ContractCanceled:
-		state = lnrpc.Invoice_CANCELED
-
-	case invoices.ContractAccepted:
-		state = lnrpc.Invoice_ACCEPTED
-
-	default:
-		return nil, fmt.Errorf("unknown invoice state %v",
-			invoice.State)
+	state, err := CreateRPCInvoiceState(invoice.State)
+	if err != nil {
+		return nil, err
 	}
 
 	rpcHtlcs := make([]*lnrpc.InvoiceHTLC, 0, len(invoice.Htlcs))
@@ -193,24 +181,11 @@ func CreateRPCInvoice(invoice *invoices.Invoice,
 	for setID, ampState := range invoice.AMPState {
 		setIDStr := hex.EncodeToString(setID[:])
 
-		var state lnrpc.InvoiceHTLCState
-		switch ampState.State {
-		case invoices.HtlcStateAccepted:
-			state = lnrpc.InvoiceHTLCState_ACCEPTED
-		case invoices.HtlcStateSettled:
-			state = lnrpc.InvoiceHTLCState_SETTLED
-		case invoices.HtlcStateCanceled:
-			state = lnrpc.InvoiceHTLCState_CANCELED
-		default:
-			return nil, fmt.Errorf("unknown state %v", ampState.State)
-		}
-
-		rpcInvoice.AmpInvoiceState[setIDStr] = &lnrpc.AMPInvoiceState{
-			State:       state,
-			SettleIndex: ampState.SettleIndex,
-			SettleTime:  ampState.SettleDate.Unix(),
-			AmtPaidMsat: int64(ampState.AmtPaid),
+		rpcState, err := CreateRPCAMPInvoiceState(ampState)
+		if err != nil {
+			return nil, err
 		}
+		rpcInvoice.AmpInvoiceState[setIDStr] = rpcState
 
 		// If at least one of the present HTLC sets show up as being
 		// settled, then we'll mark the invoice itself as being
@@ -221,6 +196,24 @@ func CreateRPCInvoice(invoice *invoices.Invoice,
 		}
 	}
 
+	if policy := invoice.Terms.PartialPayment; policy.IsEnabled() {
+		rpcInvoice.PartialPaymentPolicy = &lnrpc.PartialPaymentPolicy{
+			ToleranceMsat: uint64(policy.Tolerance),
+			MinSettleMsat: uint64(policy.MinSettleAmt),
+		}
+	}
+
+	constraints := invoice.Terms.HtlcConstraints
+	if constraints.IsEnabled() {
+		rpcInvoice.HtlcConstraints = CreateRPCHtlcConstraints(
+			constraints,
+		)
+	}
+
+	if invoice.FiatQuote != nil {
+		rpcInvoice.FiatQuote = CreateRPCFiatQuote(invoice.FiatQuote)
+	}
+
 	if preimage != nil {
 		rpcInvoice.RPreimage = preimage[:]
 	}
@@ -228,6 +221,54 @@ func CreateRPCInvoice(invoice *invoices.Invoice,
 	return rpcInvoice, nil
 }
 
+// CreateRPCInvoiceState converts the state of an invoice to its rpc
+// representation.
+func CreateRPCInvoiceState(
+	state invoices.ContractState) (lnrpc.Invoice_InvoiceState, error) {
+
+	switch state {
+	case invoices.ContractOpen:
+		return lnrpc.Invoice_OPEN, nil
+
+	case invoices.ContractSettled:
+		return lnrpc.Invoice_SETTLED, nil
+
+	case invoices.ContractCanceled:
+		return lnrpc.Invoice_CANCELED, nil
+
+	case invoices.ContractAccepted:
+		return lnrpc.Invoice_ACCEPTED, nil
+
+	default:
+		return 0, fmt.Errorf("unknown invoice state %v", state)
+	}
+}
+
+// CreateRPCAMPInvoiceState converts the state of an AMP set to its rpc
+// representation.
+func CreateRPCAMPInvoiceState(
+	ampState invoices.InvoiceStateAMP) (*lnrpc.AMPInvoiceState, error) {
+
+	var state lnrpc.InvoiceHTLCState
+	switch ampState.State {
+	case invoices.HtlcStateAccepted:
+		state = lnrpc.InvoiceHTLCState_ACCEPTED
+	case invoices.HtlcStateSettled:
+		state = lnrpc.InvoiceHTLCState_SETTLED
+	case invoices.HtlcStateCanceled:
+		state = lnrpc.InvoiceHTLCState_CANCELED
+	default:
+		return nil, fmt.Errorf("unknown state %v", ampState.State)
+	}
+
+	return &lnrpc.AMPInvoiceState{
+		State:       state,
+		SettleIndex: ampState.SettleIndex,
+		SettleTime:  ampState.SettleDate.Unix(),
+		AmtPaidMsat: int64(ampState.AmtPaid),
+	}, nil
+}
+
 // CreateRPCFeatures maps a feature vector into a list of lnrpc.Features.
 func CreateRPCFeatures(fv *lnwire.FeatureVector) map[uint32]*lnrpc.Feature {
 	if fv == nil {
@@ -358,3 +399,93 @@ func CreateZpay32HopHints(routeHints []*lnrpc.RouteHint) ([][]zpay32.HopHint, er
 	}
 	return res, nil
 }
+
+// CreateRPCHtlcConstraints converts the htlc constraints of an invoice to
+// their rpc representation.
+func CreateRPCHtlcConstraints(
+	c invoices.HtlcConstraints) *lnrpc.InvoiceHtlcConstraints {
+
+	rpcConstraints := &lnrpc.InvoiceHtlcConstraints{
+		MaxHtlcs:    c.MaxHtlcs,
+		MinHtlcMsat: uint64(c.MinHtlcAmt),
+	}
+	for _, chanID := range c.AllowedChannels {
+		rpcConstraints.AllowedChanIds = append(
+			rpcConstraints.AllowedChanIds, chanID.ToUint64(),
+		)
+	}
+	for _, peer := range c.AllowedPeers {
+		rpcConstraints.AllowedPeers = append(
+			rpcConstraints.AllowedPeers, peer[:],
+		)
+	}
+
+	return rpcConstraints
+}
+
+// UnmarshallHtlcConstraints parses the rpc representation of the htlc
+// constraints of an invoice.
+func UnmarshallHtlcConstraints(
+	c *lnrpc.InvoiceHtlcConstraints) (invoices.HtlcConstraints, error) {
+
+	var constraints invoices.HtlcConstraints
+	if c == nil {
+		return constraints, nil
+	}
+
+	constraints.MaxHtlcs = c.MaxHtlcs
+	constraints.MinHtlcAmt = lnwire.MilliSatoshi(c.MinHtlcMsat)
+
+	for _, chanID := range c.AllowedChanIds {
+		constraints.AllowedChannels = append(
+			constraints.AllowedChannels,
+			lnwire.NewShortChanIDFromInt(chanID),
+		)
+	}
+
+	for _, peerBytes := range c.AllowedPeers {
+		peer, err := route.NewVertexFromBytes(peerBytes)
+		if err != nil {
+			return constraints, fmt.Errorf("invalid allowed "+
+				"peer: %w", err)
+		}
+
+		constraints.AllowedPeers = append(
+			constraints.AllowedPeers, peer,
+		)
+	}
+
+	return constraints, nil
+}
+
+// CreateRPCFiatQuote converts the fiat quote of an invoice to its rpc
+// representation.
+func CreateRPCFiatQuote(quote *invoices.FiatQuote) *lnrpc.FiatQuote {
+	return &lnrpc.FiatQuote{
+		Currency:   quote.Currency,
+		Amount:     quote.Amount,
+		RateSource: quote.RateSource,
+		QuoteTime:  quote.QuoteTime.Unix(),
+	}
+}
+
+// UnmarshallFiatQuote parses the rpc representation of the fiat quote of an
+// invoice. If no quote time is set, the quote is timestamped with the current
+// time.
+func UnmarshallFiatQuote(quote *lnrpc.FiatQuote) *invoices.FiatQuote {
+	if quote == nil {
+		return nil
+	}
+
+	quoteTime := time.Now()
+	if quote.QuoteTime != 0 {
+		quoteTime = time.Unix(quote.QuoteTime, 0)
+	}
+
+	return &invoices.FiatQuote{
+		Currency:   quote.Currency,
+		Amount:     quote.Amount,
+		RateSource: quote.RateSource,
+		QuoteTime:  quoteTime,
+	}
+}
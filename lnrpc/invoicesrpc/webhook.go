@@ -0,0 +1,480 @@
+package invoicesrpc
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+const (
+	// WebhookIDHeader is the http header that carries the unique id of a
+	// webhook event. Receivers can use it to detect duplicate deliveries.
+	WebhookIDHeader = "Lnd-Webhook-Id"
+
+	// WebhookTimestampHeader is the http header that carries the unix
+	// timestamp at which a webhook request was signed.
+	WebhookTimestampHeader = "Lnd-Webhook-Timestamp"
+
+	// WebhookSignatureHeader is the http header that carries the signature
+	// of a webhook request, see SignWebhookPayload.
+	WebhookSignatureHeader = "Lnd-Webhook-Signature"
+
+	// DefaultWebhookRetryBackoff is the default delay before the first
+	// retry of a failed delivery. The delay doubles with every retry.
+	DefaultWebhookRetryBackoff = 5 * time.Second
+
+	// DefaultWebhookTimeout is the default timeout of a single delivery
+	// attempt.
+	DefaultWebhookTimeout = 10 * time.Second
+
+	// DefaultWebhookDeadLetterLimit is the default number of failed events
+	// kept in the dead letter queue.
+	DefaultWebhookDeadLetterLimit = 1000
+
+	// maxWebhookRetryBackoff is the maximum delay between two delivery
+	// attempts.
+	maxWebhookRetryBackoff = 10 * time.Minute
+
+	// maxWebhookResponseSize is the maximum number of bytes we read from
+	// the response of a webhook request.
+	maxWebhookResponseSize = 1 << 16
+)
+
+// WebhookEvent is the type of invoice event delivered to a webhook.
+type WebhookEvent string
+
+const (
+	// WebhookInvoiceAdded is delivered when a new invoice is added.
+	WebhookInvoiceAdded WebhookEvent = "invoice_added"
+
+	// WebhookInvoiceSettled is delivered when an invoice is settled.
+	WebhookInvoiceSettled WebhookEvent = "invoice_settled"
+)
+
+// webhookPayload is the json body of a webhook request. The invoice is
+// encoded the same way as by the REST api.
+type webhookPayload struct {
+	Event   WebhookEvent    `json:"event"`
+	Invoice json.RawMessage `json:"invoice"`
+}
+
+// WebhookDeadLetter is a webhook event that couldn't be delivered.
+type WebhookDeadLetter struct {
+	// ID is the unique id of the event.
+	ID string
+
+	// Event is the type of the event.
+	Event WebhookEvent
+
+	// Payload is the json body of the event.
+	Payload []byte
+
+	// Attempts is the number of failed delivery attempts.
+	Attempts uint32
+
+	// LastError is the error of the last delivery attempt.
+	LastError string
+
+	// FailedAt is the time of the last delivery attempt.
+	FailedAt time.Time
+}
+
+// webhookMessage is a single webhook event that is queued for delivery.
+type webhookMessage struct {
+	id      string
+	event   WebhookEvent
+	payload []byte
+}
+
+// WebhookConfig houses the configuration of the webhook dispatcher.
+type WebhookConfig struct {
+	// URL is the endpoint that invoice events are posted to.
+	URL string
+
+	// Secret is the key used to sign the webhook requests.
+	Secret []byte
+
+	// MaxAttempts is the number of times we try to deliver an event
+	// before moving it to the dead letter queue.
+	MaxAttempts uint32
+
+	// RetryBackoff is the delay before the first retry of a failed
+	// delivery. The delay doubles with every retry. If zero,
+	// DefaultWebhookRetryBackoff is used.
+	RetryBackoff time.Duration
+
+	// Timeout is the timeout of a single delivery attempt. If zero,
+	// DefaultWebhookTimeout is used.
+	Timeout time.Duration
+
+	// DeadLetterLimit is the maximum number of events kept in the dead
+	// letter queue. Once it is full, the oldest events are dropped. If
+	// zero, DefaultWebhookDeadLetterLimit is used.
+	DeadLetterLimit int
+
+	// ChainParams are the parameters of the active chain, used to decode
+	// the payment requests of invoices.
+	ChainParams *chaincfg.Params
+
+	// SubscribeNotifications subscribes to new and settled invoices.
+	SubscribeNotifications func(ctx context.Context, addIndex,
+		settleIndex uint64) (*invoices.InvoiceSubscription, error)
+}
+
+// WebhookDispatcher posts invoice events to a webhook, so that applications
+// such as web shops can integrate with lnd without keeping a gRPC
+// subscription alive. Requests are signed with an HMAC of the configured
+// secret, and failed deliveries are retried with an exponential backoff
+// before they are moved to a dead letter queue.
+type WebhookDispatcher struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *WebhookConfig
+
+	client *http.Client
+
+	// queue holds the events that are waiting to be delivered.
+	queue chan *webhookMessage
+
+	// deadLetters holds the events that couldn't be delivered. It is
+	// guarded by deadMtx.
+	deadLetters []*WebhookDeadLetter
+	deadMtx     sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher.
+func NewWebhookDispatcher(cfg *WebhookConfig) *WebhookDispatcher {
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = DefaultWebhookRetryBackoff
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = DefaultWebhookTimeout
+	}
+	if cfg.DeadLetterLimit == 0 {
+		cfg.DeadLetterLimit = DefaultWebhookDeadLetterLimit
+	}
+
+	return &WebhookDispatcher{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		queue:  make(chan *webhookMessage),
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start subscribes to invoice events and starts delivering them.
+func (d *WebhookDispatcher) Start() error {
+	var startErr error
+	d.started.Do(func() {
+		log.Infof("Invoice webhook dispatcher starting, url=%v",
+			d.cfg.URL)
+
+		// Only new events are delivered, since we don't track which
+		// events have been delivered across restarts.
+		sub, err := d.cfg.SubscribeNotifications(
+			context.Background(), 0, 0,
+		)
+		if err != nil {
+			startErr = err
+			return
+		}
+
+		d.wg.Add(2)
+		go d.notificationLoop(sub)
+		go d.deliveryLoop()
+	})
+
+	return startErr
+}
+
+// Stop stops delivering invoice events.
+func (d *WebhookDispatcher) Stop() error {
+	d.stopped.Do(func() {
+		log.Info("Invoice webhook dispatcher shutting down...")
+		defer log.Debug("Invoice webhook dispatcher shutdown complete")
+
+		close(d.quit)
+		d.wg.Wait()
+	})
+
+	return nil
+}
+
+// notificationLoop turns invoice notifications into webhook events.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *WebhookDispatcher) notificationLoop(
+	sub *invoices.InvoiceSubscription) {
+
+	defer d.wg.Done()
+	defer sub.Cancel()
+
+	for {
+		var (
+			event   WebhookEvent
+			invoice *invoices.Invoice
+			index   uint64
+		)
+		select {
+		case invoice = <-sub.NewInvoices:
+			event = WebhookInvoiceAdded
+			index = invoice.AddIndex
+
+		case invoice = <-sub.SettledInvoices:
+			event = WebhookInvoiceSettled
+			index = invoice.SettleIndex
+
+		case <-d.quit:
+			return
+		}
+
+		msg, err := d.newMessage(event, index, invoice)
+		if err != nil {
+			log.Errorf("Unable to create webhook event %v for "+
+				"invoice %d: %v", event, invoice.AddIndex, err)
+
+			continue
+		}
+
+		select {
+		case d.queue <- msg:
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// newMessage creates the webhook event for the given invoice.
+func (d *WebhookDispatcher) newMessage(event WebhookEvent, index uint64,
+	invoice *invoices.Invoice) (*webhookMessage, error) {
+
+	rpcInvoice, err := CreateRPCInvoice(invoice, d.cfg.ChainParams)
+	if err != nil {
+		return nil, err
+	}
+
+	invoiceJSON, err := lnrpc.ProtoJSONMarshalOpts.Marshal(rpcInvoice)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(&webhookPayload{
+		Event:   event,
+		Invoice: invoiceJSON,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookMessage{
+		id:      fmt.Sprintf("%s:%d", event, index),
+		event:   event,
+		payload: payload,
+	}, nil
+}
+
+// deliveryLoop delivers the queued events one at a time, so that they arrive
+// in the order they happened.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *WebhookDispatcher) deliveryLoop() {
+	defer d.wg.Done()
+
+	for {
+		select {
+		case msg := <-d.queue:
+			d.deliver(msg)
+
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// deliver posts the event to the webhook, retrying with an exponential
+// backoff. If all attempts fail, the event is moved to the dead letter queue.
+func (d *WebhookDispatcher) deliver(msg *webhookMessage) {
+	var (
+		backoff = d.cfg.RetryBackoff
+		attempt uint32
+		err     error
+	)
+	for attempt = 1; attempt <= d.cfg.MaxAttempts; attempt++ {
+		err = d.post(msg)
+		if err == nil {
+			log.Debugf("Delivered webhook event %v", msg.id)
+			return
+		}
+
+		log.Warnf("Webhook delivery of event %v failed (attempt "+
+			"%d/%d): %v", msg.id, attempt, d.cfg.MaxAttempts, err)
+
+		if attempt == d.cfg.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-d.quit:
+			return
+		}
+
+		backoff *= 2
+		if backoff > maxWebhookRetryBackoff {
+			backoff = maxWebhookRetryBackoff
+		}
+	}
+
+	log.Errorf("Giving up on webhook event %v after %d attempts, moving "+
+		"it to the dead letter queue", msg.id, d.cfg.MaxAttempts)
+
+	d.addDeadLetter(&WebhookDeadLetter{
+		ID:        msg.id,
+		Event:     msg.event,
+		Payload:   msg.payload,
+		Attempts:  d.cfg.MaxAttempts,
+		LastError: err.Error(),
+		FailedAt:  time.Now(),
+	})
+}
+
+// post makes a single attempt to deliver the event to the webhook.
+func (d *WebhookDispatcher) post(msg *webhookMessage) error {
+	ctx, cancel := context.WithTimeout(
+		context.Background(), d.cfg.Timeout,
+	)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, d.cfg.URL, bytes.NewReader(msg.payload),
+	)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := SignWebhookPayload(d.cfg.Secret, timestamp, msg.payload)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, msg.id)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, signature)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(
+		resp.Body, maxWebhookResponseSize,
+	))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status: %v",
+			resp.Status)
+	}
+
+	return nil
+}
+
+// addDeadLetter adds the event to the dead letter queue, dropping the oldest
+// event if the queue is full.
+func (d *WebhookDispatcher) addDeadLetter(letter *WebhookDeadLetter) {
+	d.deadMtx.Lock()
+	defer d.deadMtx.Unlock()
+
+	if len(d.deadLetters) >= d.cfg.DeadLetterLimit {
+		log.Warnf("Webhook dead letter queue full, dropping event %v",
+			d.deadLetters[0].ID)
+
+		d.deadLetters = d.deadLetters[1:]
+	}
+
+	d.deadLetters = append(d.deadLetters, letter)
+}
+
+// DeadLetters returns the events that couldn't be delivered.
+func (d *WebhookDispatcher) DeadLetters() []WebhookDeadLetter {
+	d.deadMtx.Lock()
+	defer d.deadMtx.Unlock()
+
+	letters := make([]WebhookDeadLetter, 0, len(d.deadLetters))
+	for _, letter := range d.deadLetters {
+		letters = append(letters, *letter)
+	}
+
+	return letters
+}
+
+// marshallWebhookDeadLetter converts a dead letter of the webhook to its RPC
+// representation.
+func marshallWebhookDeadLetter(letter WebhookDeadLetter) *FailedWebhookEvent {
+	return &FailedWebhookEvent{
+		Id:        letter.ID,
+		Event:     string(letter.Event),
+		Payload:   letter.Payload,
+		Attempts:  letter.Attempts,
+		LastError: letter.LastError,
+		FailedAt:  letter.FailedAt.Unix(),
+	}
+}
+
+// RetryDeadLetters removes all events from the dead letter queue and queues
+// them for delivery again. It blocks until all events have been handed to the
+// delivery loop, and returns the number of requeued events.
+func (d *WebhookDispatcher) RetryDeadLetters() int {
+	d.deadMtx.Lock()
+	letters := d.deadLetters
+	d.deadLetters = nil
+	d.deadMtx.Unlock()
+
+	for i, letter := range letters {
+		msg := &webhookMessage{
+			id:      letter.ID,
+			event:   letter.Event,
+			payload: letter.Payload,
+		}
+
+		select {
+		case d.queue <- msg:
+		case <-d.quit:
+			return i
+		}
+	}
+
+	return len(letters)
+}
+
+// SignWebhookPayload returns the signature of a webhook request with the given
+// timestamp and body. The signature is the hex encoded HMAC-SHA256 of
+// "<timestamp>.<body>", prefixed with "sha256=". Receivers should verify the
+// signature and reject requests with stale timestamps.
+func SignWebhookPayload(secret []byte, timestamp string,
+	payload []byte) string {
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
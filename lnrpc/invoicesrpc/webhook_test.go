@@ -0,0 +1,144 @@
+package invoicesrpc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// webhookTestServer is a webhook endpoint that fails a configurable number of
+// requests before it accepts them.
+type webhookTestServer struct {
+	t *testing.T
+
+	mu       sync.Mutex
+	failures int
+	received []string
+
+	*httptest.Server
+}
+
+func newWebhookTestServer(t *testing.T, secret []byte) *webhookTestServer {
+	s := &webhookTestServer{t: t}
+	s.Server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+
+			// Every request must carry a valid signature.
+			timestamp := r.Header.Get(WebhookTimestampHeader)
+			require.Equal(
+				t, SignWebhookPayload(secret, timestamp, body),
+				r.Header.Get(WebhookSignatureHeader),
+			)
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			if s.failures > 0 {
+				s.failures--
+				w.WriteHeader(http.StatusServiceUnavailable)
+
+				return
+			}
+
+			s.received = append(
+				s.received, r.Header.Get(WebhookIDHeader),
+			)
+		},
+	))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *webhookTestServer) setFailures(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = n
+}
+
+func (s *webhookTestServer) receivedIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.received...)
+}
+
+// TestWebhookDelivery tests that webhook events are retried and moved to the
+// dead letter queue once all attempts failed.
+func TestWebhookDelivery(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("secret")
+	server := newWebhookTestServer(t, secret)
+
+	d := NewWebhookDispatcher(&WebhookConfig{
+		URL:          server.URL,
+		Secret:       secret,
+		MaxAttempts:  3,
+		RetryBackoff: time.Millisecond,
+	})
+
+	// We only run the delivery loop, and feed it events directly.
+	d.wg.Add(1)
+	go d.deliveryLoop()
+	t.Cleanup(func() {
+		require.NoError(t, d.Stop())
+	})
+
+	send := func(id string) {
+		d.queue <- &webhookMessage{
+			id:      id,
+			event:   WebhookInvoiceSettled,
+			payload: []byte(`{"event":"invoice_settled"}`),
+		}
+	}
+
+	// An event that fails less often than the maximum number of attempts
+	// is delivered.
+	server.setFailures(2)
+	send("invoice_settled:1")
+
+	require.Eventually(t, func() bool {
+		return len(server.receivedIDs()) == 1
+	}, time.Second, 10*time.Millisecond)
+	require.Empty(t, d.DeadLetters())
+
+	// An event that fails all attempts ends up in the dead letter queue.
+	server.setFailures(3)
+	send("invoice_settled:2")
+
+	require.Eventually(t, func() bool {
+		return len(d.DeadLetters()) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	letter := d.DeadLetters()[0]
+	require.Equal(t, "invoice_settled:2", letter.ID)
+	require.EqualValues(t, 3, letter.Attempts)
+	require.Contains(t, letter.LastError, "503")
+
+	rpcLetter := marshallWebhookDeadLetter(letter)
+	require.Equal(t, letter.ID, rpcLetter.Id)
+	require.Equal(t, string(letter.Event), rpcLetter.Event)
+	require.Equal(t, letter.Payload, rpcLetter.Payload)
+	require.Equal(t, letter.FailedAt.Unix(), rpcLetter.FailedAt)
+
+	// Retrying the dead letters delivers the event.
+	require.Equal(t, 1, d.RetryDeadLetters())
+	require.Eventually(t, func() bool {
+		return len(server.receivedIDs()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	require.Equal(
+		t, []string{"invoice_settled:1", "invoice_settled:2"},
+		server.receivedIDs(),
+	)
+	require.Empty(t, d.DeadLetters())
+}
@@ -122,11 +122,24 @@ type LightningClient interface {
 	// ListChannels returns a description of all the open channels that this node
 	// is a participant in.
 	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
+	// lncli: `htlcexpiryrisk`
+	// HtlcExpiryRisks returns, for every active channel, the heights at which
+	// each outstanding HTLC forces the channel to be closed on-chain unless it's
+	// resolved off-chain before, so operators can see which channels will be
+	// force closed unless their peer comes back online.
+	HtlcExpiryRisks(ctx context.Context, in *HtlcExpiryRisksRequest, opts ...grpc.CallOption) (*HtlcExpiryRisksResponse, error)
 	// SubscribeChannelEvents creates a uni-directional stream from the server to
 	// the client in which any updates relevant to the state of the channels are
 	// sent over. Events include new active channels, inactive channels, and closed
 	// channels.
 	SubscribeChannelEvents(ctx context.Context, in *ChannelEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelEventsClient, error)
+	// SubscribeChannelResolutions creates a uni-directional stream from the server
+	// to the client in which the on-chain resolution progress of closed channels
+	// is sent over. Events include the confirmation of the closing transaction,
+	// the resolution of each of its outputs, such as HTLCs claimed via their
+	// timeout or success path and confirmed sweeps, and finally the full
+	// resolution of the channel along with the balance claimed on-chain.
+	SubscribeChannelResolutions(ctx context.Context, in *ChannelResolutionSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelResolutionsClient, error)
 	// lncli: `closedchannels`
 	// ClosedChannels returns a description of all the closed channels that
 	// this node was a participant in.
@@ -263,9 +276,20 @@ type LightningClient interface {
 	// delta, fee information, etc.
 	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
 	// lncli: `getnodemetrics`
-	// GetNodeMetrics returns node metrics calculated from the graph. Currently
-	// the only supported metric is betweenness centrality of individual nodes.
+	// GetNodeMetrics returns node metrics calculated from the graph: the
+	// betweenness centrality of individual nodes and the number of nodes that can
+	// be reached from each node.
 	GetNodeMetrics(ctx context.Context, in *NodeMetricsRequest, opts ...grpc.CallOption) (*NodeMetricsResponse, error)
+	// lncli: `getnodemetricsv2`
+	// GetNodeMetricsV2 returns the historical forwarding performance and peer
+	// downtime of our open channels and their peers over a window. The metrics
+	// are aggregated from htlc events and retained for 30 days.
+	GetNodeMetricsV2(ctx context.Context, in *NodeMetricsV2Request, opts ...grpc.CallOption) (*NodeMetricsV2Response, error)
+	// lncli: `getgraphmetrics`
+	// GetGraphMetrics returns the most recent analytics of the channel graph:
+	// its size, diameter, connected components and bridge channels. Requires
+	// lnd to be started with routing.graph-analytics.
+	GetGraphMetrics(ctx context.Context, in *GraphMetricsRequest, opts ...grpc.CallOption) (*GraphMetricsResponse, error)
 	// lncli: `getchaninfo`
 	// GetChanInfo returns the latest authenticated network announcement for the
 	// given channel identified by its channel ID: an 8-byte integer which
@@ -302,6 +326,10 @@ type LightningClient interface {
 	// nodes coming online, nodes updating their authenticated attributes, new
 	// channels being advertised, updates in the routing policy for a directional
 	// channel edge, and when channels are closed on-chain.
+	//
+	// Every update carries a sequence number, so a client can resume the
+	// subscription after a reconnect without missing a change. A client that
+	// has no state yet can request a snapshot of the graph as first update.
 	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
 	// lncli: `debuglevel`
 	// DebugLevel allows a caller to programmatically set the logging verbosity of
@@ -344,6 +372,16 @@ type LightningClient interface {
 	// as well, which contains a single encrypted blob containing the backups of
 	// each channel.
 	ExportAllChannelBackups(ctx context.Context, in *ChanBackupExportRequest, opts ...grpc.CallOption) (*ChanBackupSnapshot, error)
+	// lncli: `exportemergencybundle`
+	// ExportEmergencyCloseBundle returns the latest signed force close
+	// transaction of every active channel, together with unsigned PSBTs that
+	// sweep the anchor and to_local outputs of segwit v0 channels, as a single
+	// blob encrypted with a key derived from the aezeed seed of the user. The
+	// bundle allows funds to be recovered with cold storage tooling after a
+	// catastrophic node loss. It is only valid until the next channel update, as
+	// broadcasting an outdated force close transaction is treated as a breach by
+	// the remote party.
+	ExportEmergencyCloseBundle(ctx context.Context, in *EmergencyCloseBundleRequest, opts ...grpc.CallOption) (*EmergencyCloseBundle, error)
 	// lncli: `verifychanbackup`
 	// VerifyChanBackup allows a caller to verify the integrity of a channel backup
 	// snapshot. This method will accept either a packed Single or a packed Multi.
@@ -416,6 +454,144 @@ type LightningClient interface {
 	// If the htlc has no final resolution yet, a NotFound grpc status code is
 	// returned.
 	LookupHtlcResolution(ctx context.Context, in *LookupHtlcResolutionRequest, opts ...grpc.CallOption) (*LookupHtlcResolutionResponse, error)
+	// lncli: `channeltemplate list`
+	// ListChannelTemplates returns the channel templates stored by the node,
+	// ordered by name.
+	ListChannelTemplates(ctx context.Context, in *ListChannelTemplatesRequest, opts ...grpc.CallOption) (*ListChannelTemplatesResponse, error)
+	// lncli: `channeltemplate set`
+	// SetChannelTemplate stores a channel template under its name, replacing
+	// the one stored before. Channels that were opened with the template before
+	// are not changed.
+	SetChannelTemplate(ctx context.Context, in *SetChannelTemplateRequest, opts ...grpc.CallOption) (*SetChannelTemplateResponse, error)
+	// lncli: `channeltemplate delete`
+	// DeleteChannelTemplate removes the channel template of the given name.
+	DeleteChannelTemplate(ctx context.Context, in *DeleteChannelTemplateRequest, opts ...grpc.CallOption) (*DeleteChannelTemplateResponse, error)
+	// lncli: `runtimeconfig get`
+	// GetRuntimeConfig returns the current values of all options that can be
+	// changed while the daemon is running, keyed by their name in the config
+	// file.
+	GetRuntimeConfig(ctx context.Context, in *GetRuntimeConfigRequest, opts ...grpc.CallOption) (*RuntimeConfig, error)
+	// lncli: `runtimeconfig set`
+	// UpdateRuntimeConfig changes the given options of the running daemon. The
+	// updates are applied in the order of their names and stop at the first one
+	// that fails. All updates that were applied are written to the runtime
+	// overrides file, so they persist across restarts. The new values of all
+	// runtime options are returned.
+	UpdateRuntimeConfig(ctx context.Context, in *UpdateRuntimeConfigRequest, opts ...grpc.CallOption) (*RuntimeConfig, error)
+	// lncli: `nodeprofile export`
+	// ExportNodeProfile returns a bundle of the operator-tunable state of the
+	// node: the default and per-channel fee policies, the persistent peers and
+	// mission control. The bundle is signed by the node, so it can be verified
+	// with VerifyMessage.
+	ExportNodeProfile(ctx context.Context, in *ExportNodeProfileRequest, opts ...grpc.CallOption) (*ExportNodeProfileResponse, error)
+	// lncli: `nodeprofile import`
+	// ImportNodeProfile applies a node profile bundle to the node. The default
+	// fee policy is changed through the runtime config, so it persists across
+	// restarts. Channel policies are only applied to channels that exist on
+	// this node, and the mission control results are merged with the current
+	// ones. Bundles that were signed by another node are rejected unless
+	// allow_foreign is set.
+	ImportNodeProfile(ctx context.Context, in *ImportNodeProfileRequest, opts ...grpc.CallOption) (*ImportNodeProfileResponse, error)
+	// lncli: `inbox list`
+	// ListInboxMessages returns the messages received with keysend payments,
+	// ordered by the time they were received. Requires the
+	// invoices.keysendinbox option.
+	ListInboxMessages(ctx context.Context, in *ListInboxMessagesRequest, opts ...grpc.CallOption) (*ListInboxMessagesResponse, error)
+	// lncli: `inbox ack`
+	// AckInboxMessages acknowledges the inbox messages with the given ids, so
+	// they are no longer returned when only unacknowledged messages are listed.
+	// Either all or none of the messages are acknowledged.
+	AckInboxMessages(ctx context.Context, in *AckInboxMessagesRequest, opts ...grpc.CallOption) (*AckInboxMessagesResponse, error)
+	// lncli: `inbox subscribe`
+	// SubscribeInbox pushes every new message of the keysend inbox to the
+	// client. Messages that were received while the client wasn't subscribed
+	// can be caught up with ListInboxMessages.
+	SubscribeInbox(ctx context.Context, in *SubscribeInboxRequest, opts ...grpc.CallOption) (Lightning_SubscribeInboxClient, error)
+	// lncli: `acceptpolicy list`
+	// ListChannelAcceptPolicies returns the policies inbound channels are
+	// evaluated by, in the order they're evaluated.
+	ListChannelAcceptPolicies(ctx context.Context, in *ListChannelAcceptPoliciesRequest, opts ...grpc.CallOption) (*ListChannelAcceptPoliciesResponse, error)
+	// lncli: `acceptpolicy update`
+	// UpdateChannelAcceptPolicies reorders, changes, enables and disables the
+	// channel accept policies. Changing the parameters of a policy doesn't
+	// enable it. The changes are lost on restart.
+	UpdateChannelAcceptPolicies(ctx context.Context, in *UpdateChannelAcceptPoliciesRequest, opts ...grpc.CallOption) (*UpdateChannelAcceptPoliciesResponse, error)
+	// lncli: `acceptpolicy decisions`
+	// ListChannelAcceptDecisions returns the most recent decisions of the
+	// channel accept policies, oldest first. Requires the
+	// acceptpolicy.maxdecisions option.
+	ListChannelAcceptDecisions(ctx context.Context, in *ListChannelAcceptDecisionsRequest, opts ...grpc.CallOption) (*ListChannelAcceptDecisionsResponse, error)
+	// lncli: `psbtflows list`
+	// ListAbortedPsbtFlows returns the PSBT funding flows that were aborted
+	// because lnd restarted while they were in progress, together with the keys
+	// and scripts needed to recover funds that were sent to their funding
+	// output.
+	ListAbortedPsbtFlows(ctx context.Context, in *ListAbortedPsbtFlowsRequest, opts ...grpc.CallOption) (*ListAbortedPsbtFlowsResponse, error)
+	// lncli: `psbtflows forget`
+	// ForgetAbortedPsbtFlow removes an aborted PSBT funding flow. It should only
+	// be called once no funds are left in the funding output of the flow.
+	ForgetAbortedPsbtFlow(ctx context.Context, in *ForgetAbortedPsbtFlowRequest, opts ...grpc.CallOption) (*ForgetAbortedPsbtFlowResponse, error)
+	// lncli: `utxoreservations list`
+	// ListUtxoReservations returns the wallet outputs that are currently locked
+	// for the funding transactions of pending channels, grouped by funding flow
+	// and ordered by the time their lock expires.
+	ListUtxoReservations(ctx context.Context, in *ListUtxoReservationsRequest, opts ...grpc.CallOption) (*ListUtxoReservationsResponse, error)
+	// lncli: `utxoreservations release`
+	// ReleaseUtxoReservation releases the wallet outputs locked for the funding
+	// transaction of the given pending channel, failing its funding flow if it
+	// is still in progress.
+	ReleaseUtxoReservation(ctx context.Context, in *ReleaseUtxoReservationRequest, opts ...grpc.CallOption) (*ReleaseUtxoReservationResponse, error)
+	// lncli: `gossipscores`
+	// ListGossipPeerScores returns the gossip reputation of the peers we
+	// received gossip from, ordered from the lowest to the highest score.
+	ListGossipPeerScores(ctx context.Context, in *ListGossipPeerScoresRequest, opts ...grpc.CallOption) (*ListGossipPeerScoresResponse, error)
+	// lncli: `exportgraphsnapshot`
+	// ExportGraphSnapshot returns a snapshot of our public channel graph, signed
+	// with our node key. Other nodes can bootstrap their graph from it by
+	// trusting our node with the gossip.snapshot-signer option.
+	ExportGraphSnapshot(ctx context.Context, in *ExportGraphSnapshotRequest, opts ...grpc.CallOption) (*ExportGraphSnapshotResponse, error)
+	// lncli: `jobs list`
+	// ListJobs returns the jobs of long-running operations, e.g. channel closes
+	// that were run with as_job, ordered by their id. Jobs are persisted, so
+	// jobs of previous runs of lnd are listed as well.
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	// lncli: `jobs subscribe`
+	// SubscribeJobEvents streams the events of a job, starting at the given
+	// event index. Events that were already emitted are replayed first, which
+	// allows a client to resume the event stream after disconnecting. The
+	// stream ends once the job reached a final state and all its events were
+	// sent.
+	SubscribeJobEvents(ctx context.Context, in *SubscribeJobEventsRequest, opts ...grpc.CallOption) (Lightning_SubscribeJobEventsClient, error)
+	// lncli: `jobs cancel`
+	// CancelJob cancels the operation of a running job.
+	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error)
+	// lncli: `accounts create`
+	// CreateAccount creates an off-chain account with the given initial balance
+	// and returns a macaroon that is bound to it. Requires the accounts.active
+	// option.
+	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
+	// lncli: `accounts list`
+	// ListAccounts returns all off-chain accounts.
+	ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error)
+	// lncli: `accounts adjust`
+	// AdjustAccountBalance credits or debits the balance of an account.
+	AdjustAccountBalance(ctx context.Context, in *AdjustAccountBalanceRequest, opts ...grpc.CallOption) (*AdjustAccountBalanceResponse, error)
+	// lncli: `accounts ledger`
+	// AccountLedger returns the balance changes of an account in order.
+	AccountLedger(ctx context.Context, in *AccountLedgerRequest, opts ...grpc.CallOption) (*AccountLedgerResponse, error)
+	// lncli: `accounts remove`
+	// RemoveAccount removes an account. Macaroons that are bound to the account
+	// are rejected afterwards.
+	RemoveAccount(ctx context.Context, in *RemoveAccountRequest, opts ...grpc.CallOption) (*RemoveAccountResponse, error)
+	// lncli: `forwardingrules set`
+	// SetForwardingRules replaces the forwarding rules, which are evaluated by
+	// the outgoing link before an HTLC is forwarded, on top of the forwarding
+	// policy of the channel. Rate limits start afresh. An empty list removes all
+	// rules.
+	SetForwardingRules(ctx context.Context, in *SetForwardingRulesRequest, opts ...grpc.CallOption) (*SetForwardingRulesResponse, error)
+	// lncli: `forwardingrules list`
+	// ListForwardingRules returns the active forwarding rules.
+	ListForwardingRules(ctx context.Context, in *ListForwardingRulesRequest, opts ...grpc.CallOption) (*ListForwardingRulesResponse, error)
 }
 
 type lightningClient struct {
@@ -652,6 +828,15 @@ func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequ
 	return out, nil
 }
 
+func (c *lightningClient) HtlcExpiryRisks(ctx context.Context, in *HtlcExpiryRisksRequest, opts ...grpc.CallOption) (*HtlcExpiryRisksResponse, error) {
+	out := new(HtlcExpiryRisksResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/HtlcExpiryRisks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) SubscribeChannelEvents(ctx context.Context, in *ChannelEventSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelEventsClient, error) {
 	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[2], "/lnrpc.Lightning/SubscribeChannelEvents", opts...)
 	if err != nil {
@@ -684,6 +869,38 @@ func (x *lightningSubscribeChannelEventsClient) Recv() (*ChannelEventUpdate, err
 	return m, nil
 }
 
+func (c *lightningClient) SubscribeChannelResolutions(ctx context.Context, in *ChannelResolutionSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelResolutionsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[3], "/lnrpc.Lightning/SubscribeChannelResolutions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeChannelResolutionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeChannelResolutionsClient interface {
+	Recv() (*ChannelResolutionUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeChannelResolutionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeChannelResolutionsClient) Recv() (*ChannelResolutionUpdate, error) {
+	m := new(ChannelResolutionUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *lightningClient) ClosedChannels(ctx context.Context, in *ClosedChannelsRequest, opts ...grpc.CallOption) (*ClosedChannelsResponse, error) {
 	out := new(ClosedChannelsResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ClosedChannels", in, out, opts...)
@@ -703,7 +920,7 @@ func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRe
 }
 
 func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[3], "/lnrpc.Lightning/OpenChannel", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/OpenChannel", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -753,7 +970,7 @@ func (c *lightningClient) FundingStateStep(ctx context.Context, in *FundingTrans
 }
 
 func (c *lightningClient) ChannelAcceptor(ctx context.Context, opts ...grpc.CallOption) (Lightning_ChannelAcceptorClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[4], "/lnrpc.Lightning/ChannelAcceptor", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/ChannelAcceptor", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -784,7 +1001,7 @@ func (x *lightningChannelAcceptorClient) Recv() (*ChannelAcceptRequest, error) {
 }
 
 func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[5], "/lnrpc.Lightning/CloseChannel", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/CloseChannel", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -826,7 +1043,7 @@ func (c *lightningClient) AbandonChannel(ctx context.Context, in *AbandonChannel
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[6], "/lnrpc.Lightning/SendPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -867,7 +1084,7 @@ func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest,
 
 // Deprecated: Do not use.
 func (c *lightningClient) SendToRoute(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendToRouteClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[7], "/lnrpc.Lightning/SendToRoute", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SendToRoute", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -934,7 +1151,7 @@ func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, op
 }
 
 func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[8], "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeInvoices", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1019,6 +1236,24 @@ func (c *lightningClient) GetNodeMetrics(ctx context.Context, in *NodeMetricsReq
 	return out, nil
 }
 
+func (c *lightningClient) GetNodeMetricsV2(ctx context.Context, in *NodeMetricsV2Request, opts ...grpc.CallOption) (*NodeMetricsV2Response, error) {
+	out := new(NodeMetricsV2Response)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetNodeMetricsV2", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetGraphMetrics(ctx context.Context, in *GraphMetricsRequest, opts ...grpc.CallOption) (*GraphMetricsResponse, error) {
+	out := new(GraphMetricsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetGraphMetrics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error) {
 	out := new(ChannelEdge)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetChanInfo", in, out, opts...)
@@ -1065,7 +1300,7 @@ func (c *lightningClient) StopDaemon(ctx context.Context, in *StopRequest, opts
 }
 
 func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[9], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1150,6 +1385,15 @@ func (c *lightningClient) ExportAllChannelBackups(ctx context.Context, in *ChanB
 	return out, nil
 }
 
+func (c *lightningClient) ExportEmergencyCloseBundle(ctx context.Context, in *EmergencyCloseBundleRequest, opts ...grpc.CallOption) (*EmergencyCloseBundle, error) {
+	out := new(EmergencyCloseBundle)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ExportEmergencyCloseBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *lightningClient) VerifyChanBackup(ctx context.Context, in *ChanBackupSnapshot, opts ...grpc.CallOption) (*VerifyChanBackupResponse, error) {
 	out := new(VerifyChanBackupResponse)
 	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/VerifyChanBackup", in, out, opts...)
@@ -1169,7 +1413,7 @@ func (c *lightningClient) RestoreChannelBackups(ctx context.Context, in *Restore
 }
 
 func (c *lightningClient) SubscribeChannelBackups(ctx context.Context, in *ChannelBackupSubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelBackupsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[10], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/SubscribeChannelBackups", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1246,7 +1490,7 @@ func (c *lightningClient) CheckMacaroonPermissions(ctx context.Context, in *Chec
 }
 
 func (c *lightningClient) RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[11], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1286,7 +1530,7 @@ func (c *lightningClient) SendCustomMessage(ctx context.Context, in *SendCustomM
 }
 
 func (c *lightningClient) SubscribeCustomMessages(ctx context.Context, in *SubscribeCustomMessagesRequest, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[12], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[13], "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1335,213 +1579,533 @@ func (c *lightningClient) LookupHtlcResolution(ctx context.Context, in *LookupHt
 	return out, nil
 }
 
-// LightningServer is the server API for Lightning service.
-// All implementations must embed UnimplementedLightningServer
-// for forward compatibility
-type LightningServer interface {
-	// lncli: `walletbalance`
-	// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
-	// confirmed unspent outputs and all unconfirmed unspent outputs under control
-	// of the wallet.
-	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
-	// lncli: `channelbalance`
-	// ChannelBalance returns a report on the total funds across all open channels,
-	// categorized in local/remote, pending local/remote and unsettled local/remote
-	// balances.
-	ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error)
-	// lncli: `listchaintxns`
-	// GetTransactions returns a list describing all the known transactions
-	// relevant to the wallet.
-	GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error)
-	// lncli: `estimatefee`
-	// EstimateFee asks the chain backend to estimate the fee rate and total fees
-	// for a transaction that pays to multiple specified outputs.
-	//
-	// When using REST, the `AddrToAmount` map type can be set by appending
-	// `&AddrToAmount[<address>]=<amount_to_send>` to the URL. Unfortunately this
-	// map type doesn't appear in the REST API documentation because of a bug in
-	// the grpc-gateway library.
-	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
-	// lncli: `sendcoins`
-	// SendCoins executes a request to send coins to a particular address. Unlike
-	// SendMany, this RPC call only allows creating a single output at a time. If
-	// neither target_conf, or sat_per_vbyte are set, then the internal wallet will
-	// consult its fee model to determine a fee for the default confirmation
-	// target.
-	SendCoins(context.Context, *SendCoinsRequest) (*SendCoinsResponse, error)
-	// lncli: `listunspent`
-	// Deprecated, use walletrpc.ListUnspent instead.
-	//
-	// ListUnspent returns a list of all utxos spendable by the wallet with a
-	// number of confirmations between the specified minimum and maximum.
-	ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error)
-	// SubscribeTransactions creates a uni-directional stream from the server to
-	// the client in which any newly discovered transactions relevant to the
-	// wallet are sent over.
-	SubscribeTransactions(*GetTransactionsRequest, Lightning_SubscribeTransactionsServer) error
-	// lncli: `sendmany`
-	// SendMany handles a request for a transaction that creates multiple specified
-	// outputs in parallel. If neither target_conf, or sat_per_vbyte are set, then
-	// the internal wallet will consult its fee model to determine a fee for the
-	// default confirmation target.
-	SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error)
-	// lncli: `newaddress`
-	// NewAddress creates a new address under control of the local wallet.
-	NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error)
-	// lncli: `signmessage`
-	// SignMessage signs a message with this node's private key. The returned
-	// signature string is `zbase32` encoded and pubkey recoverable, meaning that
-	// only the message digest and signature are needed for verification.
-	SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error)
-	// lncli: `verifymessage`
-	// VerifyMessage verifies a signature over a message and recovers the signer's
-	// public key. The signature is only deemed valid if the recovered public key
-	// corresponds to a node key in the public Lightning network. The signature
-	// must be zbase32 encoded and signed by an active node in the resident node's
-	// channel database. In addition to returning the validity of the signature,
-	// VerifyMessage also returns the recovered pubkey from the signature.
-	VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error)
-	// lncli: `connect`
-	// ConnectPeer attempts to establish a connection to a remote peer. This is at
-	// the networking level, and is used for communication between nodes. This is
-	// distinct from establishing a channel with a peer.
-	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
-	// lncli: `disconnect`
-	// DisconnectPeer attempts to disconnect one peer from another identified by a
-	// given pubKey. In the case that we currently have a pending or active channel
-	// with the target peer, then this action will be not be allowed.
-	DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error)
-	// lncli: `listpeers`
-	// ListPeers returns a verbose listing of all currently active peers.
-	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
-	// SubscribePeerEvents creates a uni-directional stream from the server to
-	// the client in which any events relevant to the state of peers are sent
-	// over. Events include peers going online and offline.
-	SubscribePeerEvents(*PeerEventSubscription, Lightning_SubscribePeerEventsServer) error
-	// lncli: `getinfo`
-	// GetInfo returns general information concerning the lightning node including
-	// it's identity pubkey, alias, the chains it is connected to, and information
-	// concerning the number of open+pending channels.
-	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
-	// lncli: 'getdebuginfo'
-	// GetDebugInfo returns debug information concerning the state of the daemon
-	// and its subsystems. This includes the full configuration and the latest log
-	// entries from the log file.
-	GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error)
-	// * lncli: `getrecoveryinfo`
-	// GetRecoveryInfo returns information concerning the recovery mode including
-	// whether it's in a recovery mode, whether the recovery is finished, and the
-	// progress made so far.
-	GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error)
-	// lncli: `pendingchannels`
-	// PendingChannels returns a list of all the channels that are currently
-	// considered "pending". A channel is pending if it has finished the funding
-	// workflow and is waiting for confirmations for the funding txn, or is in the
-	// process of closure, either initiated cooperatively or non-cooperatively.
-	PendingChannels(context.Context, *PendingChannelsRequest) (*PendingChannelsResponse, error)
-	// lncli: `listchannels`
-	// ListChannels returns a description of all the open channels that this node
-	// is a participant in.
-	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
-	// SubscribeChannelEvents creates a uni-directional stream from the server to
-	// the client in which any updates relevant to the state of the channels are
-	// sent over. Events include new active channels, inactive channels, and closed
-	// channels.
-	SubscribeChannelEvents(*ChannelEventSubscription, Lightning_SubscribeChannelEventsServer) error
-	// lncli: `closedchannels`
-	// ClosedChannels returns a description of all the closed channels that
-	// this node was a participant in.
-	ClosedChannels(context.Context, *ClosedChannelsRequest) (*ClosedChannelsResponse, error)
-	// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
-	// call is meant to be consumed by clients to the REST proxy. As with all
-	// other sync calls, all byte slices are intended to be populated as hex
-	// encoded strings.
-	OpenChannelSync(context.Context, *OpenChannelRequest) (*ChannelPoint, error)
-	// lncli: `openchannel`
-	// OpenChannel attempts to open a singly funded channel specified in the
-	// request to a remote peer. Users are able to specify a target number of
-	// blocks that the funding transaction should be confirmed in, or a manual fee
-	// rate to us for the funding transaction. If neither are specified, then a
-	// lax block confirmation target is used. Each OpenStatusUpdate will return
-	// the pending channel ID of the in-progress channel. Depending on the
-	// arguments specified in the OpenChannelRequest, this pending channel ID can
-	// then be used to manually progress the channel funding flow.
-	OpenChannel(*OpenChannelRequest, Lightning_OpenChannelServer) error
-	// lncli: `batchopenchannel`
-	// BatchOpenChannel attempts to open multiple single-funded channels in a
-	// single transaction in an atomic way. This means either all channel open
-	// requests succeed at once or all attempts are aborted if any of them fail.
-	// This is the safer variant of using PSBTs to manually fund a batch of
-	// channels through the OpenChannel RPC.
-	BatchOpenChannel(context.Context, *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error)
-	// FundingStateStep is an advanced funding related call that allows the caller
-	// to either execute some preparatory steps for a funding workflow, or
-	// manually progress a funding workflow. The primary way a funding flow is
-	// identified is via its pending channel ID. As an example, this method can be
-	// used to specify that we're expecting a funding flow for a particular
-	// pending channel ID, for which we need to use specific parameters.
-	// Alternatively, this can be used to interactively drive PSBT signing for
-	// funding for partially complete funding transactions.
-	FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error)
-	// ChannelAcceptor dispatches a bi-directional streaming RPC in which
-	// OpenChannel requests are sent to the client and the client responds with
-	// a boolean that tells LND whether or not to accept the channel. This allows
-	// node operators to specify their own criteria for accepting inbound channels
-	// through a single persistent connection.
-	ChannelAcceptor(Lightning_ChannelAcceptorServer) error
-	// lncli: `closechannel`
-	// CloseChannel attempts to close an active channel identified by its channel
-	// outpoint (ChannelPoint). The actions of this method can additionally be
-	// augmented to attempt a force close after a timeout period in the case of an
-	// inactive peer. If a non-force close (cooperative closure) is requested,
-	// then the user can specify either a target number of blocks until the
-	// closure transaction is confirmed, or a manual fee rate. If neither are
-	// specified, then a default lax, block confirmation target is used.
-	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
-	// lncli: `abandonchannel`
-	// AbandonChannel removes all channel state from the database except for a
-	// close summary. This method can be used to get rid of permanently unusable
-	// channels due to bugs fixed in newer versions of lnd. This method can also be
-	// used to remove externally funded channels where the funding transaction was
-	// never broadcast. Only available for non-externally funded channels in dev
-	// build.
-	AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error)
-	// Deprecated: Do not use.
-	// lncli: `sendpayment`
-	// Deprecated, use routerrpc.SendPaymentV2. SendPayment dispatches a
-	// bi-directional streaming RPC for sending payments through the Lightning
-	// Network. A single RPC invocation creates a persistent bi-directional
-	// stream allowing clients to rapidly send payments through the Lightning
-	// Network with a single persistent connection.
-	SendPayment(Lightning_SendPaymentServer) error
-	// SendPaymentSync is the synchronous non-streaming version of SendPayment.
-	// This RPC is intended to be consumed by clients of the REST proxy.
-	// Additionally, this RPC expects the destination's public key and the payment
-	// hash (if any) to be encoded as hex strings.
-	SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error)
-	// Deprecated: Do not use.
-	// lncli: `sendtoroute`
-	// Deprecated, use routerrpc.SendToRouteV2. SendToRoute is a bi-directional
-	// streaming RPC for sending payment through the Lightning Network. This
-	// method differs from SendPayment in that it allows users to specify a full
-	// route manually. This can be used for things like rebalancing, and atomic
-	// swaps.
-	SendToRoute(Lightning_SendToRouteServer) error
-	// SendToRouteSync is a synchronous version of SendToRoute. It Will block
-	// until the payment either fails or succeeds.
-	SendToRouteSync(context.Context, *SendToRouteRequest) (*SendResponse, error)
-	// lncli: `addinvoice`
-	// AddInvoice attempts to add a new invoice to the invoice database. Any
-	// duplicated invoices are rejected, therefore all invoices *must* have a
-	// unique payment preimage.
-	AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error)
-	// lncli: `listinvoices`
-	// ListInvoices returns a list of all the invoices currently stored within the
-	// database. Any active debug invoices are ignored. It has full support for
-	// paginated responses, allowing users to query for specific invoices through
-	// their add_index. This can be done by using either the first_index_offset or
-	// last_index_offset fields included in the response as the index_offset of the
-	// next request. By default, the first 100 invoices created will be returned.
+func (c *lightningClient) ListChannelTemplates(ctx context.Context, in *ListChannelTemplatesRequest, opts ...grpc.CallOption) (*ListChannelTemplatesResponse, error) {
+	out := new(ListChannelTemplatesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListChannelTemplates", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SetChannelTemplate(ctx context.Context, in *SetChannelTemplateRequest, opts ...grpc.CallOption) (*SetChannelTemplateResponse, error) {
+	out := new(SetChannelTemplateResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SetChannelTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DeleteChannelTemplate(ctx context.Context, in *DeleteChannelTemplateRequest, opts ...grpc.CallOption) (*DeleteChannelTemplateResponse, error) {
+	out := new(DeleteChannelTemplateResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/DeleteChannelTemplate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetRuntimeConfig(ctx context.Context, in *GetRuntimeConfigRequest, opts ...grpc.CallOption) (*RuntimeConfig, error) {
+	out := new(RuntimeConfig)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/GetRuntimeConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateRuntimeConfig(ctx context.Context, in *UpdateRuntimeConfigRequest, opts ...grpc.CallOption) (*RuntimeConfig, error) {
+	out := new(RuntimeConfig)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/UpdateRuntimeConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportNodeProfile(ctx context.Context, in *ExportNodeProfileRequest, opts ...grpc.CallOption) (*ExportNodeProfileResponse, error) {
+	out := new(ExportNodeProfileResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ExportNodeProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ImportNodeProfile(ctx context.Context, in *ImportNodeProfileRequest, opts ...grpc.CallOption) (*ImportNodeProfileResponse, error) {
+	out := new(ImportNodeProfileResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ImportNodeProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListInboxMessages(ctx context.Context, in *ListInboxMessagesRequest, opts ...grpc.CallOption) (*ListInboxMessagesResponse, error) {
+	out := new(ListInboxMessagesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListInboxMessages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AckInboxMessages(ctx context.Context, in *AckInboxMessagesRequest, opts ...grpc.CallOption) (*AckInboxMessagesResponse, error) {
+	out := new(AckInboxMessagesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AckInboxMessages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeInbox(ctx context.Context, in *SubscribeInboxRequest, opts ...grpc.CallOption) (Lightning_SubscribeInboxClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[14], "/lnrpc.Lightning/SubscribeInbox", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeInboxClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeInboxClient interface {
+	Recv() (*InboxMessage, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeInboxClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeInboxClient) Recv() (*InboxMessage, error) {
+	m := new(InboxMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) ListChannelAcceptPolicies(ctx context.Context, in *ListChannelAcceptPoliciesRequest, opts ...grpc.CallOption) (*ListChannelAcceptPoliciesResponse, error) {
+	out := new(ListChannelAcceptPoliciesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListChannelAcceptPolicies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateChannelAcceptPolicies(ctx context.Context, in *UpdateChannelAcceptPoliciesRequest, opts ...grpc.CallOption) (*UpdateChannelAcceptPoliciesResponse, error) {
+	out := new(UpdateChannelAcceptPoliciesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/UpdateChannelAcceptPolicies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListChannelAcceptDecisions(ctx context.Context, in *ListChannelAcceptDecisionsRequest, opts ...grpc.CallOption) (*ListChannelAcceptDecisionsResponse, error) {
+	out := new(ListChannelAcceptDecisionsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListChannelAcceptDecisions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListAbortedPsbtFlows(ctx context.Context, in *ListAbortedPsbtFlowsRequest, opts ...grpc.CallOption) (*ListAbortedPsbtFlowsResponse, error) {
+	out := new(ListAbortedPsbtFlowsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListAbortedPsbtFlows", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ForgetAbortedPsbtFlow(ctx context.Context, in *ForgetAbortedPsbtFlowRequest, opts ...grpc.CallOption) (*ForgetAbortedPsbtFlowResponse, error) {
+	out := new(ForgetAbortedPsbtFlowResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ForgetAbortedPsbtFlow", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListUtxoReservations(ctx context.Context, in *ListUtxoReservationsRequest, opts ...grpc.CallOption) (*ListUtxoReservationsResponse, error) {
+	out := new(ListUtxoReservationsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListUtxoReservations", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ReleaseUtxoReservation(ctx context.Context, in *ReleaseUtxoReservationRequest, opts ...grpc.CallOption) (*ReleaseUtxoReservationResponse, error) {
+	out := new(ReleaseUtxoReservationResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ReleaseUtxoReservation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListGossipPeerScores(ctx context.Context, in *ListGossipPeerScoresRequest, opts ...grpc.CallOption) (*ListGossipPeerScoresResponse, error) {
+	out := new(ListGossipPeerScoresResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListGossipPeerScores", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportGraphSnapshot(ctx context.Context, in *ExportGraphSnapshotRequest, opts ...grpc.CallOption) (*ExportGraphSnapshotResponse, error) {
+	out := new(ExportGraphSnapshotResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ExportGraphSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListJobs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeJobEvents(ctx context.Context, in *SubscribeJobEventsRequest, opts ...grpc.CallOption) (Lightning_SubscribeJobEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Lightning_ServiceDesc.Streams[15], "/lnrpc.Lightning/SubscribeJobEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeJobEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeJobEventsClient interface {
+	Recv() (*JobEvent, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeJobEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeJobEventsClient) Recv() (*JobEvent, error) {
+	m := new(JobEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error) {
+	out := new(CancelJobResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CancelJob", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error) {
+	out := new(CreateAccountResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/CreateAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListAccounts(ctx context.Context, in *ListAccountsRequest, opts ...grpc.CallOption) (*ListAccountsResponse, error) {
+	out := new(ListAccountsResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListAccounts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AdjustAccountBalance(ctx context.Context, in *AdjustAccountBalanceRequest, opts ...grpc.CallOption) (*AdjustAccountBalanceResponse, error) {
+	out := new(AdjustAccountBalanceResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AdjustAccountBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AccountLedger(ctx context.Context, in *AccountLedgerRequest, opts ...grpc.CallOption) (*AccountLedgerResponse, error) {
+	out := new(AccountLedgerResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/AccountLedger", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RemoveAccount(ctx context.Context, in *RemoveAccountRequest, opts ...grpc.CallOption) (*RemoveAccountResponse, error) {
+	out := new(RemoveAccountResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/RemoveAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SetForwardingRules(ctx context.Context, in *SetForwardingRulesRequest, opts ...grpc.CallOption) (*SetForwardingRulesResponse, error) {
+	out := new(SetForwardingRulesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/SetForwardingRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListForwardingRules(ctx context.Context, in *ListForwardingRulesRequest, opts ...grpc.CallOption) (*ListForwardingRulesResponse, error) {
+	out := new(ListForwardingRulesResponse)
+	err := c.cc.Invoke(ctx, "/lnrpc.Lightning/ListForwardingRules", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LightningServer is the server API for Lightning service.
+// All implementations must embed UnimplementedLightningServer
+// for forward compatibility
+type LightningServer interface {
+	// lncli: `walletbalance`
+	// WalletBalance returns total unspent outputs(confirmed and unconfirmed), all
+	// confirmed unspent outputs and all unconfirmed unspent outputs under control
+	// of the wallet.
+	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
+	// lncli: `channelbalance`
+	// ChannelBalance returns a report on the total funds across all open channels,
+	// categorized in local/remote, pending local/remote and unsettled local/remote
+	// balances.
+	ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error)
+	// lncli: `listchaintxns`
+	// GetTransactions returns a list describing all the known transactions
+	// relevant to the wallet.
+	GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error)
+	// lncli: `estimatefee`
+	// EstimateFee asks the chain backend to estimate the fee rate and total fees
+	// for a transaction that pays to multiple specified outputs.
+	//
+	// When using REST, the `AddrToAmount` map type can be set by appending
+	// `&AddrToAmount[<address>]=<amount_to_send>` to the URL. Unfortunately this
+	// map type doesn't appear in the REST API documentation because of a bug in
+	// the grpc-gateway library.
+	EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error)
+	// lncli: `sendcoins`
+	// SendCoins executes a request to send coins to a particular address. Unlike
+	// SendMany, this RPC call only allows creating a single output at a time. If
+	// neither target_conf, or sat_per_vbyte are set, then the internal wallet will
+	// consult its fee model to determine a fee for the default confirmation
+	// target.
+	SendCoins(context.Context, *SendCoinsRequest) (*SendCoinsResponse, error)
+	// lncli: `listunspent`
+	// Deprecated, use walletrpc.ListUnspent instead.
+	//
+	// ListUnspent returns a list of all utxos spendable by the wallet with a
+	// number of confirmations between the specified minimum and maximum.
+	ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error)
+	// SubscribeTransactions creates a uni-directional stream from the server to
+	// the client in which any newly discovered transactions relevant to the
+	// wallet are sent over.
+	SubscribeTransactions(*GetTransactionsRequest, Lightning_SubscribeTransactionsServer) error
+	// lncli: `sendmany`
+	// SendMany handles a request for a transaction that creates multiple specified
+	// outputs in parallel. If neither target_conf, or sat_per_vbyte are set, then
+	// the internal wallet will consult its fee model to determine a fee for the
+	// default confirmation target.
+	SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error)
+	// lncli: `newaddress`
+	// NewAddress creates a new address under control of the local wallet.
+	NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error)
+	// lncli: `signmessage`
+	// SignMessage signs a message with this node's private key. The returned
+	// signature string is `zbase32` encoded and pubkey recoverable, meaning that
+	// only the message digest and signature are needed for verification.
+	SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error)
+	// lncli: `verifymessage`
+	// VerifyMessage verifies a signature over a message and recovers the signer's
+	// public key. The signature is only deemed valid if the recovered public key
+	// corresponds to a node key in the public Lightning network. The signature
+	// must be zbase32 encoded and signed by an active node in the resident node's
+	// channel database. In addition to returning the validity of the signature,
+	// VerifyMessage also returns the recovered pubkey from the signature.
+	VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error)
+	// lncli: `connect`
+	// ConnectPeer attempts to establish a connection to a remote peer. This is at
+	// the networking level, and is used for communication between nodes. This is
+	// distinct from establishing a channel with a peer.
+	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
+	// lncli: `disconnect`
+	// DisconnectPeer attempts to disconnect one peer from another identified by a
+	// given pubKey. In the case that we currently have a pending or active channel
+	// with the target peer, then this action will be not be allowed.
+	DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error)
+	// lncli: `listpeers`
+	// ListPeers returns a verbose listing of all currently active peers.
+	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
+	// SubscribePeerEvents creates a uni-directional stream from the server to
+	// the client in which any events relevant to the state of peers are sent
+	// over. Events include peers going online and offline.
+	SubscribePeerEvents(*PeerEventSubscription, Lightning_SubscribePeerEventsServer) error
+	// lncli: `getinfo`
+	// GetInfo returns general information concerning the lightning node including
+	// it's identity pubkey, alias, the chains it is connected to, and information
+	// concerning the number of open+pending channels.
+	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
+	// lncli: 'getdebuginfo'
+	// GetDebugInfo returns debug information concerning the state of the daemon
+	// and its subsystems. This includes the full configuration and the latest log
+	// entries from the log file.
+	GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error)
+	// * lncli: `getrecoveryinfo`
+	// GetRecoveryInfo returns information concerning the recovery mode including
+	// whether it's in a recovery mode, whether the recovery is finished, and the
+	// progress made so far.
+	GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error)
+	// lncli: `pendingchannels`
+	// PendingChannels returns a list of all the channels that are currently
+	// considered "pending". A channel is pending if it has finished the funding
+	// workflow and is waiting for confirmations for the funding txn, or is in the
+	// process of closure, either initiated cooperatively or non-cooperatively.
+	PendingChannels(context.Context, *PendingChannelsRequest) (*PendingChannelsResponse, error)
+	// lncli: `listchannels`
+	// ListChannels returns a description of all the open channels that this node
+	// is a participant in.
+	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
+	// lncli: `htlcexpiryrisk`
+	// HtlcExpiryRisks returns, for every active channel, the heights at which
+	// each outstanding HTLC forces the channel to be closed on-chain unless it's
+	// resolved off-chain before, so operators can see which channels will be
+	// force closed unless their peer comes back online.
+	HtlcExpiryRisks(context.Context, *HtlcExpiryRisksRequest) (*HtlcExpiryRisksResponse, error)
+	// SubscribeChannelEvents creates a uni-directional stream from the server to
+	// the client in which any updates relevant to the state of the channels are
+	// sent over. Events include new active channels, inactive channels, and closed
+	// channels.
+	SubscribeChannelEvents(*ChannelEventSubscription, Lightning_SubscribeChannelEventsServer) error
+	// SubscribeChannelResolutions creates a uni-directional stream from the server
+	// to the client in which the on-chain resolution progress of closed channels
+	// is sent over. Events include the confirmation of the closing transaction,
+	// the resolution of each of its outputs, such as HTLCs claimed via their
+	// timeout or success path and confirmed sweeps, and finally the full
+	// resolution of the channel along with the balance claimed on-chain.
+	SubscribeChannelResolutions(*ChannelResolutionSubscription, Lightning_SubscribeChannelResolutionsServer) error
+	// lncli: `closedchannels`
+	// ClosedChannels returns a description of all the closed channels that
+	// this node was a participant in.
+	ClosedChannels(context.Context, *ClosedChannelsRequest) (*ClosedChannelsResponse, error)
+	// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
+	// call is meant to be consumed by clients to the REST proxy. As with all
+	// other sync calls, all byte slices are intended to be populated as hex
+	// encoded strings.
+	OpenChannelSync(context.Context, *OpenChannelRequest) (*ChannelPoint, error)
+	// lncli: `openchannel`
+	// OpenChannel attempts to open a singly funded channel specified in the
+	// request to a remote peer. Users are able to specify a target number of
+	// blocks that the funding transaction should be confirmed in, or a manual fee
+	// rate to us for the funding transaction. If neither are specified, then a
+	// lax block confirmation target is used. Each OpenStatusUpdate will return
+	// the pending channel ID of the in-progress channel. Depending on the
+	// arguments specified in the OpenChannelRequest, this pending channel ID can
+	// then be used to manually progress the channel funding flow.
+	OpenChannel(*OpenChannelRequest, Lightning_OpenChannelServer) error
+	// lncli: `batchopenchannel`
+	// BatchOpenChannel attempts to open multiple single-funded channels in a
+	// single transaction in an atomic way. This means either all channel open
+	// requests succeed at once or all attempts are aborted if any of them fail.
+	// This is the safer variant of using PSBTs to manually fund a batch of
+	// channels through the OpenChannel RPC.
+	BatchOpenChannel(context.Context, *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error)
+	// FundingStateStep is an advanced funding related call that allows the caller
+	// to either execute some preparatory steps for a funding workflow, or
+	// manually progress a funding workflow. The primary way a funding flow is
+	// identified is via its pending channel ID. As an example, this method can be
+	// used to specify that we're expecting a funding flow for a particular
+	// pending channel ID, for which we need to use specific parameters.
+	// Alternatively, this can be used to interactively drive PSBT signing for
+	// funding for partially complete funding transactions.
+	FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error)
+	// ChannelAcceptor dispatches a bi-directional streaming RPC in which
+	// OpenChannel requests are sent to the client and the client responds with
+	// a boolean that tells LND whether or not to accept the channel. This allows
+	// node operators to specify their own criteria for accepting inbound channels
+	// through a single persistent connection.
+	ChannelAcceptor(Lightning_ChannelAcceptorServer) error
+	// lncli: `closechannel`
+	// CloseChannel attempts to close an active channel identified by its channel
+	// outpoint (ChannelPoint). The actions of this method can additionally be
+	// augmented to attempt a force close after a timeout period in the case of an
+	// inactive peer. If a non-force close (cooperative closure) is requested,
+	// then the user can specify either a target number of blocks until the
+	// closure transaction is confirmed, or a manual fee rate. If neither are
+	// specified, then a default lax, block confirmation target is used.
+	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
+	// lncli: `abandonchannel`
+	// AbandonChannel removes all channel state from the database except for a
+	// close summary. This method can be used to get rid of permanently unusable
+	// channels due to bugs fixed in newer versions of lnd. This method can also be
+	// used to remove externally funded channels where the funding transaction was
+	// never broadcast. Only available for non-externally funded channels in dev
+	// build.
+	AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error)
+	// Deprecated: Do not use.
+	// lncli: `sendpayment`
+	// Deprecated, use routerrpc.SendPaymentV2. SendPayment dispatches a
+	// bi-directional streaming RPC for sending payments through the Lightning
+	// Network. A single RPC invocation creates a persistent bi-directional
+	// stream allowing clients to rapidly send payments through the Lightning
+	// Network with a single persistent connection.
+	SendPayment(Lightning_SendPaymentServer) error
+	// SendPaymentSync is the synchronous non-streaming version of SendPayment.
+	// This RPC is intended to be consumed by clients of the REST proxy.
+	// Additionally, this RPC expects the destination's public key and the payment
+	// hash (if any) to be encoded as hex strings.
+	SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error)
+	// Deprecated: Do not use.
+	// lncli: `sendtoroute`
+	// Deprecated, use routerrpc.SendToRouteV2. SendToRoute is a bi-directional
+	// streaming RPC for sending payment through the Lightning Network. This
+	// method differs from SendPayment in that it allows users to specify a full
+	// route manually. This can be used for things like rebalancing, and atomic
+	// swaps.
+	SendToRoute(Lightning_SendToRouteServer) error
+	// SendToRouteSync is a synchronous version of SendToRoute. It Will block
+	// until the payment either fails or succeeds.
+	SendToRouteSync(context.Context, *SendToRouteRequest) (*SendResponse, error)
+	// lncli: `addinvoice`
+	// AddInvoice attempts to add a new invoice to the invoice database. Any
+	// duplicated invoices are rejected, therefore all invoices *must* have a
+	// unique payment preimage.
+	AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error)
+	// lncli: `listinvoices`
+	// ListInvoices returns a list of all the invoices currently stored within the
+	// database. Any active debug invoices are ignored. It has full support for
+	// paginated responses, allowing users to query for specific invoices through
+	// their add_index. This can be done by using either the first_index_offset or
+	// last_index_offset fields included in the response as the index_offset of the
+	// next request. By default, the first 100 invoices created will be returned.
 	// Backwards pagination is also supported through the Reversed flag.
 	ListInvoices(context.Context, *ListInvoiceRequest) (*ListInvoiceResponse, error)
 	// lncli: `lookupinvoice`
@@ -1584,9 +2148,20 @@ type LightningServer interface {
 	// delta, fee information, etc.
 	DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error)
 	// lncli: `getnodemetrics`
-	// GetNodeMetrics returns node metrics calculated from the graph. Currently
-	// the only supported metric is betweenness centrality of individual nodes.
+	// GetNodeMetrics returns node metrics calculated from the graph: the
+	// betweenness centrality of individual nodes and the number of nodes that can
+	// be reached from each node.
 	GetNodeMetrics(context.Context, *NodeMetricsRequest) (*NodeMetricsResponse, error)
+	// lncli: `getnodemetricsv2`
+	// GetNodeMetricsV2 returns the historical forwarding performance and peer
+	// downtime of our open channels and their peers over a window. The metrics
+	// are aggregated from htlc events and retained for 30 days.
+	GetNodeMetricsV2(context.Context, *NodeMetricsV2Request) (*NodeMetricsV2Response, error)
+	// lncli: `getgraphmetrics`
+	// GetGraphMetrics returns the most recent analytics of the channel graph:
+	// its size, diameter, connected components and bridge channels. Requires
+	// lnd to be started with routing.graph-analytics.
+	GetGraphMetrics(context.Context, *GraphMetricsRequest) (*GraphMetricsResponse, error)
 	// lncli: `getchaninfo`
 	// GetChanInfo returns the latest authenticated network announcement for the
 	// given channel identified by its channel ID: an 8-byte integer which
@@ -1623,6 +2198,10 @@ type LightningServer interface {
 	// nodes coming online, nodes updating their authenticated attributes, new
 	// channels being advertised, updates in the routing policy for a directional
 	// channel edge, and when channels are closed on-chain.
+	//
+	// Every update carries a sequence number, so a client can resume the
+	// subscription after a reconnect without missing a change. A client that
+	// has no state yet can request a snapshot of the graph as first update.
 	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
 	// lncli: `debuglevel`
 	// DebugLevel allows a caller to programmatically set the logging verbosity of
@@ -1665,6 +2244,16 @@ type LightningServer interface {
 	// as well, which contains a single encrypted blob containing the backups of
 	// each channel.
 	ExportAllChannelBackups(context.Context, *ChanBackupExportRequest) (*ChanBackupSnapshot, error)
+	// lncli: `exportemergencybundle`
+	// ExportEmergencyCloseBundle returns the latest signed force close
+	// transaction of every active channel, together with unsigned PSBTs that
+	// sweep the anchor and to_local outputs of segwit v0 channels, as a single
+	// blob encrypted with a key derived from the aezeed seed of the user. The
+	// bundle allows funds to be recovered with cold storage tooling after a
+	// catastrophic node loss. It is only valid until the next channel update, as
+	// broadcasting an outdated force close transaction is treated as a breach by
+	// the remote party.
+	ExportEmergencyCloseBundle(context.Context, *EmergencyCloseBundleRequest) (*EmergencyCloseBundle, error)
 	// lncli: `verifychanbackup`
 	// VerifyChanBackup allows a caller to verify the integrity of a channel backup
 	// snapshot. This method will accept either a packed Single or a packed Multi.
@@ -1737,1509 +2326,2370 @@ type LightningServer interface {
 	// If the htlc has no final resolution yet, a NotFound grpc status code is
 	// returned.
 	LookupHtlcResolution(context.Context, *LookupHtlcResolutionRequest) (*LookupHtlcResolutionResponse, error)
+	// lncli: `channeltemplate list`
+	// ListChannelTemplates returns the channel templates stored by the node,
+	// ordered by name.
+	ListChannelTemplates(context.Context, *ListChannelTemplatesRequest) (*ListChannelTemplatesResponse, error)
+	// lncli: `channeltemplate set`
+	// SetChannelTemplate stores a channel template under its name, replacing
+	// the one stored before. Channels that were opened with the template before
+	// are not changed.
+	SetChannelTemplate(context.Context, *SetChannelTemplateRequest) (*SetChannelTemplateResponse, error)
+	// lncli: `channeltemplate delete`
+	// DeleteChannelTemplate removes the channel template of the given name.
+	DeleteChannelTemplate(context.Context, *DeleteChannelTemplateRequest) (*DeleteChannelTemplateResponse, error)
+	// lncli: `runtimeconfig get`
+	// GetRuntimeConfig returns the current values of all options that can be
+	// changed while the daemon is running, keyed by their name in the config
+	// file.
+	GetRuntimeConfig(context.Context, *GetRuntimeConfigRequest) (*RuntimeConfig, error)
+	// lncli: `runtimeconfig set`
+	// UpdateRuntimeConfig changes the given options of the running daemon. The
+	// updates are applied in the order of their names and stop at the first one
+	// that fails. All updates that were applied are written to the runtime
+	// overrides file, so they persist across restarts. The new values of all
+	// runtime options are returned.
+	UpdateRuntimeConfig(context.Context, *UpdateRuntimeConfigRequest) (*RuntimeConfig, error)
+	// lncli: `nodeprofile export`
+	// ExportNodeProfile returns a bundle of the operator-tunable state of the
+	// node: the default and per-channel fee policies, the persistent peers and
+	// mission control. The bundle is signed by the node, so it can be verified
+	// with VerifyMessage.
+	ExportNodeProfile(context.Context, *ExportNodeProfileRequest) (*ExportNodeProfileResponse, error)
+	// lncli: `nodeprofile import`
+	// ImportNodeProfile applies a node profile bundle to the node. The default
+	// fee policy is changed through the runtime config, so it persists across
+	// restarts. Channel policies are only applied to channels that exist on
+	// this node, and the mission control results are merged with the current
+	// ones. Bundles that were signed by another node are rejected unless
+	// allow_foreign is set.
+	ImportNodeProfile(context.Context, *ImportNodeProfileRequest) (*ImportNodeProfileResponse, error)
+	// lncli: `inbox list`
+	// ListInboxMessages returns the messages received with keysend payments,
+	// ordered by the time they were received. Requires the
+	// invoices.keysendinbox option.
+	ListInboxMessages(context.Context, *ListInboxMessagesRequest) (*ListInboxMessagesResponse, error)
+	// lncli: `inbox ack`
+	// AckInboxMessages acknowledges the inbox messages with the given ids, so
+	// they are no longer returned when only unacknowledged messages are listed.
+	// Either all or none of the messages are acknowledged.
+	AckInboxMessages(context.Context, *AckInboxMessagesRequest) (*AckInboxMessagesResponse, error)
+	// lncli: `inbox subscribe`
+	// SubscribeInbox pushes every new message of the keysend inbox to the
+	// client. Messages that were received while the client wasn't subscribed
+	// can be caught up with ListInboxMessages.
+	SubscribeInbox(*SubscribeInboxRequest, Lightning_SubscribeInboxServer) error
+	// lncli: `acceptpolicy list`
+	// ListChannelAcceptPolicies returns the policies inbound channels are
+	// evaluated by, in the order they're evaluated.
+	ListChannelAcceptPolicies(context.Context, *ListChannelAcceptPoliciesRequest) (*ListChannelAcceptPoliciesResponse, error)
+	// lncli: `acceptpolicy update`
+	// UpdateChannelAcceptPolicies reorders, changes, enables and disables the
+	// channel accept policies. Changing the parameters of a policy doesn't
+	// enable it. The changes are lost on restart.
+	UpdateChannelAcceptPolicies(context.Context, *UpdateChannelAcceptPoliciesRequest) (*UpdateChannelAcceptPoliciesResponse, error)
+	// lncli: `acceptpolicy decisions`
+	// ListChannelAcceptDecisions returns the most recent decisions of the
+	// channel accept policies, oldest first. Requires the
+	// acceptpolicy.maxdecisions option.
+	ListChannelAcceptDecisions(context.Context, *ListChannelAcceptDecisionsRequest) (*ListChannelAcceptDecisionsResponse, error)
+	// lncli: `psbtflows list`
+	// ListAbortedPsbtFlows returns the PSBT funding flows that were aborted
+	// because lnd restarted while they were in progress, together with the keys
+	// and scripts needed to recover funds that were sent to their funding
+	// output.
+	ListAbortedPsbtFlows(context.Context, *ListAbortedPsbtFlowsRequest) (*ListAbortedPsbtFlowsResponse, error)
+	// lncli: `psbtflows forget`
+	// ForgetAbortedPsbtFlow removes an aborted PSBT funding flow. It should only
+	// be called once no funds are left in the funding output of the flow.
+	ForgetAbortedPsbtFlow(context.Context, *ForgetAbortedPsbtFlowRequest) (*ForgetAbortedPsbtFlowResponse, error)
+	// lncli: `utxoreservations list`
+	// ListUtxoReservations returns the wallet outputs that are currently locked
+	// for the funding transactions of pending channels, grouped by funding flow
+	// and ordered by the time their lock expires.
+	ListUtxoReservations(context.Context, *ListUtxoReservationsRequest) (*ListUtxoReservationsResponse, error)
+	// lncli: `utxoreservations release`
+	// ReleaseUtxoReservation releases the wallet outputs locked for the funding
+	// transaction of the given pending channel, failing its funding flow if it
+	// is still in progress.
+	ReleaseUtxoReservation(context.Context, *ReleaseUtxoReservationRequest) (*ReleaseUtxoReservationResponse, error)
+	// lncli: `gossipscores`
+	// ListGossipPeerScores returns the gossip reputation of the peers we
+	// received gossip from, ordered from the lowest to the highest score.
+	ListGossipPeerScores(context.Context, *ListGossipPeerScoresRequest) (*ListGossipPeerScoresResponse, error)
+	// lncli: `exportgraphsnapshot`
+	// ExportGraphSnapshot returns a snapshot of our public channel graph, signed
+	// with our node key. Other nodes can bootstrap their graph from it by
+	// trusting our node with the gossip.snapshot-signer option.
+	ExportGraphSnapshot(context.Context, *ExportGraphSnapshotRequest) (*ExportGraphSnapshotResponse, error)
+	// lncli: `jobs list`
+	// ListJobs returns the jobs of long-running operations, e.g. channel closes
+	// that were run with as_job, ordered by their id. Jobs are persisted, so
+	// jobs of previous runs of lnd are listed as well.
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	// lncli: `jobs subscribe`
+	// SubscribeJobEvents streams the events of a job, starting at the given
+	// event index. Events that were already emitted are replayed first, which
+	// allows a client to resume the event stream after disconnecting. The
+	// stream ends once the job reached a final state and all its events were
+	// sent.
+	SubscribeJobEvents(*SubscribeJobEventsRequest, Lightning_SubscribeJobEventsServer) error
+	// lncli: `jobs cancel`
+	// CancelJob cancels the operation of a running job.
+	CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error)
+	// lncli: `accounts create`
+	// CreateAccount creates an off-chain account with the given initial balance
+	// and returns a macaroon that is bound to it. Requires the accounts.active
+	// option.
+	CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
+	// lncli: `accounts list`
+	// ListAccounts returns all off-chain accounts.
+	ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error)
+	// lncli: `accounts adjust`
+	// AdjustAccountBalance credits or debits the balance of an account.
+	AdjustAccountBalance(context.Context, *AdjustAccountBalanceRequest) (*AdjustAccountBalanceResponse, error)
+	// lncli: `accounts ledger`
+	// AccountLedger returns the balance changes of an account in order.
+	AccountLedger(context.Context, *AccountLedgerRequest) (*AccountLedgerResponse, error)
+	// lncli: `accounts remove`
+	// RemoveAccount removes an account. Macaroons that are bound to the account
+	// are rejected afterwards.
+	RemoveAccount(context.Context, *RemoveAccountRequest) (*RemoveAccountResponse, error)
+	// lncli: `forwardingrules set`
+	// SetForwardingRules replaces the forwarding rules, which are evaluated by
+	// the outgoing link before an HTLC is forwarded, on top of the forwarding
+	// policy of the channel. Rate limits start afresh. An empty list removes all
+	// rules.
+	SetForwardingRules(context.Context, *SetForwardingRulesRequest) (*SetForwardingRulesResponse, error)
+	// lncli: `forwardingrules list`
+	// ListForwardingRules returns the active forwarding rules.
+	ListForwardingRules(context.Context, *ListForwardingRulesRequest) (*ListForwardingRulesResponse, error)
+	mustEmbedUnimplementedLightningServer()
+}
+
+// UnimplementedLightningServer must be embedded to have forward compatible implementations.
+type UnimplementedLightningServer struct {
+}
+
+func (UnimplementedLightningServer) WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WalletBalance not implemented")
+}
+func (UnimplementedLightningServer) ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChannelBalance not implemented")
+}
+func (UnimplementedLightningServer) GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactions not implemented")
+}
+func (UnimplementedLightningServer) EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EstimateFee not implemented")
+}
+func (UnimplementedLightningServer) SendCoins(context.Context, *SendCoinsRequest) (*SendCoinsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendCoins not implemented")
+}
+func (UnimplementedLightningServer) ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUnspent not implemented")
+}
+func (UnimplementedLightningServer) SubscribeTransactions(*GetTransactionsRequest, Lightning_SubscribeTransactionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeTransactions not implemented")
+}
+func (UnimplementedLightningServer) SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMany not implemented")
+}
+func (UnimplementedLightningServer) NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method NewAddress not implemented")
+}
+func (UnimplementedLightningServer) SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignMessage not implemented")
+}
+func (UnimplementedLightningServer) VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyMessage not implemented")
+}
+func (UnimplementedLightningServer) ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConnectPeer not implemented")
+}
+func (UnimplementedLightningServer) DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisconnectPeer not implemented")
+}
+func (UnimplementedLightningServer) ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPeers not implemented")
+}
+func (UnimplementedLightningServer) SubscribePeerEvents(*PeerEventSubscription, Lightning_SubscribePeerEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribePeerEvents not implemented")
+}
+func (UnimplementedLightningServer) GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+}
+func (UnimplementedLightningServer) GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDebugInfo not implemented")
+}
+func (UnimplementedLightningServer) GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecoveryInfo not implemented")
+}
+func (UnimplementedLightningServer) PendingChannels(context.Context, *PendingChannelsRequest) (*PendingChannelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PendingChannels not implemented")
+}
+func (UnimplementedLightningServer) ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChannels not implemented")
+}
+func (UnimplementedLightningServer) HtlcExpiryRisks(context.Context, *HtlcExpiryRisksRequest) (*HtlcExpiryRisksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HtlcExpiryRisks not implemented")
+}
+func (UnimplementedLightningServer) SubscribeChannelEvents(*ChannelEventSubscription, Lightning_SubscribeChannelEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeChannelEvents not implemented")
+}
+func (UnimplementedLightningServer) SubscribeChannelResolutions(*ChannelResolutionSubscription, Lightning_SubscribeChannelResolutionsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeChannelResolutions not implemented")
+}
+func (UnimplementedLightningServer) ClosedChannels(context.Context, *ClosedChannelsRequest) (*ClosedChannelsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClosedChannels not implemented")
+}
+func (UnimplementedLightningServer) OpenChannelSync(context.Context, *OpenChannelRequest) (*ChannelPoint, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OpenChannelSync not implemented")
+}
+func (UnimplementedLightningServer) OpenChannel(*OpenChannelRequest, Lightning_OpenChannelServer) error {
+	return status.Errorf(codes.Unimplemented, "method OpenChannel not implemented")
+}
+func (UnimplementedLightningServer) BatchOpenChannel(context.Context, *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchOpenChannel not implemented")
+}
+func (UnimplementedLightningServer) FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FundingStateStep not implemented")
+}
+func (UnimplementedLightningServer) ChannelAcceptor(Lightning_ChannelAcceptorServer) error {
+	return status.Errorf(codes.Unimplemented, "method ChannelAcceptor not implemented")
+}
+func (UnimplementedLightningServer) CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error {
+	return status.Errorf(codes.Unimplemented, "method CloseChannel not implemented")
+}
+func (UnimplementedLightningServer) AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbandonChannel not implemented")
+}
+func (UnimplementedLightningServer) SendPayment(Lightning_SendPaymentServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendPayment not implemented")
+}
+func (UnimplementedLightningServer) SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendPaymentSync not implemented")
+}
+func (UnimplementedLightningServer) SendToRoute(Lightning_SendToRouteServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendToRoute not implemented")
+}
+func (UnimplementedLightningServer) SendToRouteSync(context.Context, *SendToRouteRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendToRouteSync not implemented")
+}
+func (UnimplementedLightningServer) AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddInvoice not implemented")
+}
+func (UnimplementedLightningServer) ListInvoices(context.Context, *ListInvoiceRequest) (*ListInvoiceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInvoices not implemented")
+}
+func (UnimplementedLightningServer) LookupInvoice(context.Context, *PaymentHash) (*Invoice, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupInvoice not implemented")
+}
+func (UnimplementedLightningServer) SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeInvoices not implemented")
+}
+func (UnimplementedLightningServer) DecodePayReq(context.Context, *PayReqString) (*PayReq, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DecodePayReq not implemented")
+}
+func (UnimplementedLightningServer) ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPayments not implemented")
+}
+func (UnimplementedLightningServer) DeletePayment(context.Context, *DeletePaymentRequest) (*DeletePaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePayment not implemented")
+}
+func (UnimplementedLightningServer) DeleteAllPayments(context.Context, *DeleteAllPaymentsRequest) (*DeleteAllPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAllPayments not implemented")
+}
+func (UnimplementedLightningServer) DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeGraph not implemented")
+}
+func (UnimplementedLightningServer) GetNodeMetrics(context.Context, *NodeMetricsRequest) (*NodeMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeMetrics not implemented")
+}
+func (UnimplementedLightningServer) GetNodeMetricsV2(context.Context, *NodeMetricsV2Request) (*NodeMetricsV2Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeMetricsV2 not implemented")
+}
+func (UnimplementedLightningServer) GetGraphMetrics(context.Context, *GraphMetricsRequest) (*GraphMetricsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetGraphMetrics not implemented")
+}
+func (UnimplementedLightningServer) GetChanInfo(context.Context, *ChanInfoRequest) (*ChannelEdge, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChanInfo not implemented")
+}
+func (UnimplementedLightningServer) GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeInfo not implemented")
+}
+func (UnimplementedLightningServer) QueryRoutes(context.Context, *QueryRoutesRequest) (*QueryRoutesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryRoutes not implemented")
+}
+func (UnimplementedLightningServer) GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNetworkInfo not implemented")
+}
+func (UnimplementedLightningServer) StopDaemon(context.Context, *StopRequest) (*StopResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StopDaemon not implemented")
+}
+func (UnimplementedLightningServer) SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeChannelGraph not implemented")
+}
+func (UnimplementedLightningServer) DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DebugLevel not implemented")
+}
+func (UnimplementedLightningServer) FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FeeReport not implemented")
+}
+func (UnimplementedLightningServer) UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateChannelPolicy not implemented")
+}
+func (UnimplementedLightningServer) ForwardingHistory(context.Context, *ForwardingHistoryRequest) (*ForwardingHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForwardingHistory not implemented")
+}
+func (UnimplementedLightningServer) ExportChannelBackup(context.Context, *ExportChannelBackupRequest) (*ChannelBackup, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportChannelBackup not implemented")
+}
+func (UnimplementedLightningServer) ExportAllChannelBackups(context.Context, *ChanBackupExportRequest) (*ChanBackupSnapshot, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportAllChannelBackups not implemented")
+}
+func (UnimplementedLightningServer) ExportEmergencyCloseBundle(context.Context, *EmergencyCloseBundleRequest) (*EmergencyCloseBundle, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportEmergencyCloseBundle not implemented")
+}
+func (UnimplementedLightningServer) VerifyChanBackup(context.Context, *ChanBackupSnapshot) (*VerifyChanBackupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyChanBackup not implemented")
+}
+func (UnimplementedLightningServer) RestoreChannelBackups(context.Context, *RestoreChanBackupRequest) (*RestoreBackupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreChannelBackups not implemented")
+}
+func (UnimplementedLightningServer) SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeChannelBackups not implemented")
+}
+func (UnimplementedLightningServer) BakeMacaroon(context.Context, *BakeMacaroonRequest) (*BakeMacaroonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BakeMacaroon not implemented")
+}
+func (UnimplementedLightningServer) ListMacaroonIDs(context.Context, *ListMacaroonIDsRequest) (*ListMacaroonIDsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMacaroonIDs not implemented")
+}
+func (UnimplementedLightningServer) DeleteMacaroonID(context.Context, *DeleteMacaroonIDRequest) (*DeleteMacaroonIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMacaroonID not implemented")
+}
+func (UnimplementedLightningServer) ListPermissions(context.Context, *ListPermissionsRequest) (*ListPermissionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPermissions not implemented")
+}
+func (UnimplementedLightningServer) CheckMacaroonPermissions(context.Context, *CheckMacPermRequest) (*CheckMacPermResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckMacaroonPermissions not implemented")
+}
+func (UnimplementedLightningServer) RegisterRPCMiddleware(Lightning_RegisterRPCMiddlewareServer) error {
+	return status.Errorf(codes.Unimplemented, "method RegisterRPCMiddleware not implemented")
+}
+func (UnimplementedLightningServer) SendCustomMessage(context.Context, *SendCustomMessageRequest) (*SendCustomMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendCustomMessage not implemented")
+}
+func (UnimplementedLightningServer) SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeCustomMessages not implemented")
+}
+func (UnimplementedLightningServer) ListAliases(context.Context, *ListAliasesRequest) (*ListAliasesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAliases not implemented")
+}
+func (UnimplementedLightningServer) LookupHtlcResolution(context.Context, *LookupHtlcResolutionRequest) (*LookupHtlcResolutionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LookupHtlcResolution not implemented")
+}
+func (UnimplementedLightningServer) ListChannelTemplates(context.Context, *ListChannelTemplatesRequest) (*ListChannelTemplatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChannelTemplates not implemented")
+}
+func (UnimplementedLightningServer) SetChannelTemplate(context.Context, *SetChannelTemplateRequest) (*SetChannelTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetChannelTemplate not implemented")
+}
+func (UnimplementedLightningServer) DeleteChannelTemplate(context.Context, *DeleteChannelTemplateRequest) (*DeleteChannelTemplateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteChannelTemplate not implemented")
+}
+func (UnimplementedLightningServer) GetRuntimeConfig(context.Context, *GetRuntimeConfigRequest) (*RuntimeConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRuntimeConfig not implemented")
+}
+func (UnimplementedLightningServer) UpdateRuntimeConfig(context.Context, *UpdateRuntimeConfigRequest) (*RuntimeConfig, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateRuntimeConfig not implemented")
+}
+func (UnimplementedLightningServer) ExportNodeProfile(context.Context, *ExportNodeProfileRequest) (*ExportNodeProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportNodeProfile not implemented")
+}
+func (UnimplementedLightningServer) ImportNodeProfile(context.Context, *ImportNodeProfileRequest) (*ImportNodeProfileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportNodeProfile not implemented")
+}
+func (UnimplementedLightningServer) ListInboxMessages(context.Context, *ListInboxMessagesRequest) (*ListInboxMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInboxMessages not implemented")
+}
+func (UnimplementedLightningServer) AckInboxMessages(context.Context, *AckInboxMessagesRequest) (*AckInboxMessagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AckInboxMessages not implemented")
+}
+func (UnimplementedLightningServer) SubscribeInbox(*SubscribeInboxRequest, Lightning_SubscribeInboxServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeInbox not implemented")
+}
+func (UnimplementedLightningServer) ListChannelAcceptPolicies(context.Context, *ListChannelAcceptPoliciesRequest) (*ListChannelAcceptPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChannelAcceptPolicies not implemented")
+}
+func (UnimplementedLightningServer) UpdateChannelAcceptPolicies(context.Context, *UpdateChannelAcceptPoliciesRequest) (*UpdateChannelAcceptPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateChannelAcceptPolicies not implemented")
+}
+func (UnimplementedLightningServer) ListChannelAcceptDecisions(context.Context, *ListChannelAcceptDecisionsRequest) (*ListChannelAcceptDecisionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListChannelAcceptDecisions not implemented")
+}
+func (UnimplementedLightningServer) ListAbortedPsbtFlows(context.Context, *ListAbortedPsbtFlowsRequest) (*ListAbortedPsbtFlowsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAbortedPsbtFlows not implemented")
+}
+func (UnimplementedLightningServer) ForgetAbortedPsbtFlow(context.Context, *ForgetAbortedPsbtFlowRequest) (*ForgetAbortedPsbtFlowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForgetAbortedPsbtFlow not implemented")
+}
+func (UnimplementedLightningServer) ListUtxoReservations(context.Context, *ListUtxoReservationsRequest) (*ListUtxoReservationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUtxoReservations not implemented")
+}
+func (UnimplementedLightningServer) ReleaseUtxoReservation(context.Context, *ReleaseUtxoReservationRequest) (*ReleaseUtxoReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseUtxoReservation not implemented")
+}
+func (UnimplementedLightningServer) ListGossipPeerScores(context.Context, *ListGossipPeerScoresRequest) (*ListGossipPeerScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGossipPeerScores not implemented")
+}
+func (UnimplementedLightningServer) ExportGraphSnapshot(context.Context, *ExportGraphSnapshotRequest) (*ExportGraphSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportGraphSnapshot not implemented")
+}
+func (UnimplementedLightningServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedLightningServer) SubscribeJobEvents(*SubscribeJobEventsRequest, Lightning_SubscribeJobEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeJobEvents not implemented")
+}
+func (UnimplementedLightningServer) CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelJob not implemented")
+}
+func (UnimplementedLightningServer) CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccount not implemented")
+}
+func (UnimplementedLightningServer) ListAccounts(context.Context, *ListAccountsRequest) (*ListAccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccounts not implemented")
+}
+func (UnimplementedLightningServer) AdjustAccountBalance(context.Context, *AdjustAccountBalanceRequest) (*AdjustAccountBalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdjustAccountBalance not implemented")
+}
+func (UnimplementedLightningServer) AccountLedger(context.Context, *AccountLedgerRequest) (*AccountLedgerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AccountLedger not implemented")
+}
+func (UnimplementedLightningServer) RemoveAccount(context.Context, *RemoveAccountRequest) (*RemoveAccountResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveAccount not implemented")
+}
+func (UnimplementedLightningServer) SetForwardingRules(context.Context, *SetForwardingRulesRequest) (*SetForwardingRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetForwardingRules not implemented")
+}
+func (UnimplementedLightningServer) ListForwardingRules(context.Context, *ListForwardingRulesRequest) (*ListForwardingRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListForwardingRules not implemented")
+}
+func (UnimplementedLightningServer) mustEmbedUnimplementedLightningServer() {}
+
+// UnsafeLightningServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LightningServer will
+// result in compilation errors.
+type UnsafeLightningServer interface {
 	mustEmbedUnimplementedLightningServer()
 }
 
-// UnimplementedLightningServer must be embedded to have forward compatible implementations.
-type UnimplementedLightningServer struct {
+func RegisterLightningServer(s grpc.ServiceRegistrar, srv LightningServer) {
+	s.RegisterService(&Lightning_ServiceDesc, srv)
+}
+
+func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).WalletBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/WalletBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).WalletBalance(ctx, req.(*WalletBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ChannelBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ChannelBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ChannelBalance(ctx, req.(*ChannelBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EstimateFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).EstimateFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/EstimateFee",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).EstimateFee(ctx, req.(*EstimateFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (UnimplementedLightningServer) WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method WalletBalance not implemented")
-}
-func (UnimplementedLightningServer) ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ChannelBalance not implemented")
-}
-func (UnimplementedLightningServer) GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetTransactions not implemented")
+func _Lightning_SendCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCoinsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendCoins(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendCoins",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendCoins(ctx, req.(*SendCoinsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) EstimateFee(context.Context, *EstimateFeeRequest) (*EstimateFeeResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method EstimateFee not implemented")
+
+func _Lightning_ListUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnspentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListUnspent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListUnspent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListUnspent(ctx, req.(*ListUnspentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) SendCoins(context.Context, *SendCoinsRequest) (*SendCoinsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendCoins not implemented")
+
+func _Lightning_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTransactionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeTransactions(m, &lightningSubscribeTransactionsServer{stream})
 }
-func (UnimplementedLightningServer) ListUnspent(context.Context, *ListUnspentRequest) (*ListUnspentResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListUnspent not implemented")
+
+type Lightning_SubscribeTransactionsServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) SubscribeTransactions(*GetTransactionsRequest, Lightning_SubscribeTransactionsServer) error {
-	return status.Errorf(codes.Unimplemented, "method SubscribeTransactions not implemented")
+
+type lightningSubscribeTransactionsServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendMany not implemented")
+
+func (x *lightningSubscribeTransactionsServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedLightningServer) NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method NewAddress not implemented")
+
+func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendManyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendMany",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendMany(ctx, req.(*SendManyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) SignMessage(context.Context, *SignMessageRequest) (*SignMessageResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SignMessage not implemented")
+
+func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).NewAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/NewAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).NewAddress(ctx, req.(*NewAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) VerifyMessage(context.Context, *VerifyMessageRequest) (*VerifyMessageResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method VerifyMessage not implemented")
+
+func _Lightning_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SignMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SignMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SignMessage(ctx, req.(*SignMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ConnectPeer not implemented")
+
+func _Lightning_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).VerifyMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/VerifyMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) DisconnectPeer(context.Context, *DisconnectPeerRequest) (*DisconnectPeerResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DisconnectPeer not implemented")
+
+func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ConnectPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ConnectPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ConnectPeer(ctx, req.(*ConnectPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListPeers not implemented")
+
+func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisconnectPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DisconnectPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DisconnectPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DisconnectPeer(ctx, req.(*DisconnectPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) SubscribePeerEvents(*PeerEventSubscription, Lightning_SubscribePeerEventsServer) error {
-	return status.Errorf(codes.Unimplemented, "method SubscribePeerEvents not implemented")
+
+func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListPeers(ctx, req.(*ListPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetInfo not implemented")
+
+func _Lightning_SubscribePeerEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PeerEventSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribePeerEvents(m, &lightningSubscribePeerEventsServer{stream})
 }
-func (UnimplementedLightningServer) GetDebugInfo(context.Context, *GetDebugInfoRequest) (*GetDebugInfoResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetDebugInfo not implemented")
+
+type Lightning_SubscribePeerEventsServer interface {
+	Send(*PeerEvent) error
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) GetRecoveryInfo(context.Context, *GetRecoveryInfoRequest) (*GetRecoveryInfoResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetRecoveryInfo not implemented")
+
+type lightningSubscribePeerEventsServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) PendingChannels(context.Context, *PendingChannelsRequest) (*PendingChannelsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PendingChannels not implemented")
+
+func (x *lightningSubscribePeerEventsServer) Send(m *PeerEvent) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedLightningServer) ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListChannels not implemented")
+
+func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetInfo(ctx, req.(*GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) SubscribeChannelEvents(*ChannelEventSubscription, Lightning_SubscribeChannelEventsServer) error {
-	return status.Errorf(codes.Unimplemented, "method SubscribeChannelEvents not implemented")
+
+func _Lightning_GetDebugInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDebugInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetDebugInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetDebugInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetDebugInfo(ctx, req.(*GetDebugInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) ClosedChannels(context.Context, *ClosedChannelsRequest) (*ClosedChannelsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ClosedChannels not implemented")
+
+func _Lightning_GetRecoveryInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecoveryInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetRecoveryInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetRecoveryInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetRecoveryInfo(ctx, req.(*GetRecoveryInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) OpenChannelSync(context.Context, *OpenChannelRequest) (*ChannelPoint, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method OpenChannelSync not implemented")
+
+func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).PendingChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/PendingChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).PendingChannels(ctx, req.(*PendingChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) OpenChannel(*OpenChannelRequest, Lightning_OpenChannelServer) error {
-	return status.Errorf(codes.Unimplemented, "method OpenChannel not implemented")
+
+func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListChannels(ctx, req.(*ListChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) BatchOpenChannel(context.Context, *BatchOpenChannelRequest) (*BatchOpenChannelResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method BatchOpenChannel not implemented")
+
+func _Lightning_HtlcExpiryRisks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HtlcExpiryRisksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).HtlcExpiryRisks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/HtlcExpiryRisks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).HtlcExpiryRisks(ctx, req.(*HtlcExpiryRisksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) FundingStateStep(context.Context, *FundingTransitionMsg) (*FundingStateStepResp, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method FundingStateStep not implemented")
+
+func _Lightning_SubscribeChannelEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChannelEventSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeChannelEvents(m, &lightningSubscribeChannelEventsServer{stream})
 }
-func (UnimplementedLightningServer) ChannelAcceptor(Lightning_ChannelAcceptorServer) error {
-	return status.Errorf(codes.Unimplemented, "method ChannelAcceptor not implemented")
+
+type Lightning_SubscribeChannelEventsServer interface {
+	Send(*ChannelEventUpdate) error
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error {
-	return status.Errorf(codes.Unimplemented, "method CloseChannel not implemented")
+
+type lightningSubscribeChannelEventsServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) AbandonChannel(context.Context, *AbandonChannelRequest) (*AbandonChannelResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AbandonChannel not implemented")
+
+func (x *lightningSubscribeChannelEventsServer) Send(m *ChannelEventUpdate) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedLightningServer) SendPayment(Lightning_SendPaymentServer) error {
-	return status.Errorf(codes.Unimplemented, "method SendPayment not implemented")
+
+func _Lightning_SubscribeChannelResolutions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChannelResolutionSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeChannelResolutions(m, &lightningSubscribeChannelResolutionsServer{stream})
 }
-func (UnimplementedLightningServer) SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendPaymentSync not implemented")
+
+type Lightning_SubscribeChannelResolutionsServer interface {
+	Send(*ChannelResolutionUpdate) error
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) SendToRoute(Lightning_SendToRouteServer) error {
-	return status.Errorf(codes.Unimplemented, "method SendToRoute not implemented")
+
+type lightningSubscribeChannelResolutionsServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) SendToRouteSync(context.Context, *SendToRouteRequest) (*SendResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendToRouteSync not implemented")
+
+func (x *lightningSubscribeChannelResolutionsServer) Send(m *ChannelResolutionUpdate) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedLightningServer) AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AddInvoice not implemented")
+
+func _Lightning_ClosedChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClosedChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ClosedChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ClosedChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ClosedChannels(ctx, req.(*ClosedChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) ListInvoices(context.Context, *ListInvoiceRequest) (*ListInvoiceResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListInvoices not implemented")
+
+func _Lightning_OpenChannelSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).OpenChannelSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/OpenChannelSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).OpenChannelSync(ctx, req.(*OpenChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) LookupInvoice(context.Context, *PaymentHash) (*Invoice, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method LookupInvoice not implemented")
+
+func _Lightning_OpenChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OpenChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).OpenChannel(m, &lightningOpenChannelServer{stream})
 }
-func (UnimplementedLightningServer) SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error {
-	return status.Errorf(codes.Unimplemented, "method SubscribeInvoices not implemented")
+
+type Lightning_OpenChannelServer interface {
+	Send(*OpenStatusUpdate) error
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) DecodePayReq(context.Context, *PayReqString) (*PayReq, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DecodePayReq not implemented")
+
+type lightningOpenChannelServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListPayments not implemented")
+
+func (x *lightningOpenChannelServer) Send(m *OpenStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedLightningServer) DeletePayment(context.Context, *DeletePaymentRequest) (*DeletePaymentResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeletePayment not implemented")
+
+func _Lightning_BatchOpenChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchOpenChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).BatchOpenChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/BatchOpenChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).BatchOpenChannel(ctx, req.(*BatchOpenChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) DeleteAllPayments(context.Context, *DeleteAllPaymentsRequest) (*DeleteAllPaymentsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteAllPayments not implemented")
+
+func _Lightning_FundingStateStep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundingTransitionMsg)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).FundingStateStep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/FundingStateStep",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).FundingStateStep(ctx, req.(*FundingTransitionMsg))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DescribeGraph not implemented")
+
+func _Lightning_ChannelAcceptor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).ChannelAcceptor(&lightningChannelAcceptorServer{stream})
 }
-func (UnimplementedLightningServer) GetNodeMetrics(context.Context, *NodeMetricsRequest) (*NodeMetricsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetNodeMetrics not implemented")
+
+type Lightning_ChannelAcceptorServer interface {
+	Send(*ChannelAcceptRequest) error
+	Recv() (*ChannelAcceptResponse, error)
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) GetChanInfo(context.Context, *ChanInfoRequest) (*ChannelEdge, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetChanInfo not implemented")
+
+type lightningChannelAcceptorServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetNodeInfo not implemented")
+
+func (x *lightningChannelAcceptorServer) Send(m *ChannelAcceptRequest) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedLightningServer) QueryRoutes(context.Context, *QueryRoutesRequest) (*QueryRoutesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method QueryRoutes not implemented")
+
+func (x *lightningChannelAcceptorServer) Recv() (*ChannelAcceptResponse, error) {
+	m := new(ChannelAcceptResponse)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
-func (UnimplementedLightningServer) GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetNetworkInfo not implemented")
+
+func _Lightning_CloseChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CloseChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).CloseChannel(m, &lightningCloseChannelServer{stream})
 }
-func (UnimplementedLightningServer) StopDaemon(context.Context, *StopRequest) (*StopResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method StopDaemon not implemented")
+
+type Lightning_CloseChannelServer interface {
+	Send(*CloseStatusUpdate) error
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error {
-	return status.Errorf(codes.Unimplemented, "method SubscribeChannelGraph not implemented")
+
+type lightningCloseChannelServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DebugLevel not implemented")
+
+func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedLightningServer) FeeReport(context.Context, *FeeReportRequest) (*FeeReportResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method FeeReport not implemented")
+
+func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbandonChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AbandonChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AbandonChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AbandonChannel(ctx, req.(*AbandonChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) UpdateChannelPolicy(context.Context, *PolicyUpdateRequest) (*PolicyUpdateResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method UpdateChannelPolicy not implemented")
+
+func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
 }
-func (UnimplementedLightningServer) ForwardingHistory(context.Context, *ForwardingHistoryRequest) (*ForwardingHistoryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ForwardingHistory not implemented")
+
+type Lightning_SendPaymentServer interface {
+	Send(*SendResponse) error
+	Recv() (*SendRequest, error)
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) ExportChannelBackup(context.Context, *ExportChannelBackupRequest) (*ChannelBackup, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ExportChannelBackup not implemented")
+
+type lightningSendPaymentServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) ExportAllChannelBackups(context.Context, *ChanBackupExportRequest) (*ChanBackupSnapshot, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ExportAllChannelBackups not implemented")
+
+func (x *lightningSendPaymentServer) Send(m *SendResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedLightningServer) VerifyChanBackup(context.Context, *ChanBackupSnapshot) (*VerifyChanBackupResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method VerifyChanBackup not implemented")
+
+func (x *lightningSendPaymentServer) Recv() (*SendRequest, error) {
+	m := new(SendRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
-func (UnimplementedLightningServer) RestoreChannelBackups(context.Context, *RestoreChanBackupRequest) (*RestoreBackupResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RestoreChannelBackups not implemented")
+
+func _Lightning_SendPaymentSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendPaymentSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendPaymentSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendPaymentSync(ctx, req.(*SendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) SubscribeChannelBackups(*ChannelBackupSubscription, Lightning_SubscribeChannelBackupsServer) error {
-	return status.Errorf(codes.Unimplemented, "method SubscribeChannelBackups not implemented")
+
+func _Lightning_SendToRoute_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).SendToRoute(&lightningSendToRouteServer{stream})
 }
-func (UnimplementedLightningServer) BakeMacaroon(context.Context, *BakeMacaroonRequest) (*BakeMacaroonResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method BakeMacaroon not implemented")
+
+type Lightning_SendToRouteServer interface {
+	Send(*SendResponse) error
+	Recv() (*SendToRouteRequest, error)
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) ListMacaroonIDs(context.Context, *ListMacaroonIDsRequest) (*ListMacaroonIDsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListMacaroonIDs not implemented")
+
+type lightningSendToRouteServer struct {
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) DeleteMacaroonID(context.Context, *DeleteMacaroonIDRequest) (*DeleteMacaroonIDResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteMacaroonID not implemented")
+
+func (x *lightningSendToRouteServer) Send(m *SendResponse) error {
+	return x.ServerStream.SendMsg(m)
 }
-func (UnimplementedLightningServer) ListPermissions(context.Context, *ListPermissionsRequest) (*ListPermissionsResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListPermissions not implemented")
+
+func (x *lightningSendToRouteServer) Recv() (*SendToRouteRequest, error) {
+	m := new(SendToRouteRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
-func (UnimplementedLightningServer) CheckMacaroonPermissions(context.Context, *CheckMacPermRequest) (*CheckMacPermResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CheckMacaroonPermissions not implemented")
+
+func _Lightning_SendToRouteSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendToRouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendToRouteSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendToRouteSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendToRouteSync(ctx, req.(*SendToRouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) RegisterRPCMiddleware(Lightning_RegisterRPCMiddlewareServer) error {
-	return status.Errorf(codes.Unimplemented, "method RegisterRPCMiddleware not implemented")
+
+func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Invoice)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AddInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AddInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AddInvoice(ctx, req.(*Invoice))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) SendCustomMessage(context.Context, *SendCustomMessageRequest) (*SendCustomMessageResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SendCustomMessage not implemented")
+
+func _Lightning_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListInvoices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListInvoices(ctx, req.(*ListInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) SubscribeCustomMessages(*SubscribeCustomMessagesRequest, Lightning_SubscribeCustomMessagesServer) error {
-	return status.Errorf(codes.Unimplemented, "method SubscribeCustomMessages not implemented")
+
+func _Lightning_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentHash)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).LookupInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/LookupInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).LookupInvoice(ctx, req.(*PaymentHash))
+	}
+	return interceptor(ctx, in, info, handler)
 }
-func (UnimplementedLightningServer) ListAliases(context.Context, *ListAliasesRequest) (*ListAliasesResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListAliases not implemented")
+
+func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InvoiceSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeInvoices(m, &lightningSubscribeInvoicesServer{stream})
 }
-func (UnimplementedLightningServer) LookupHtlcResolution(context.Context, *LookupHtlcResolutionRequest) (*LookupHtlcResolutionResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method LookupHtlcResolution not implemented")
+
+type Lightning_SubscribeInvoicesServer interface {
+	Send(*Invoice) error
+	grpc.ServerStream
 }
-func (UnimplementedLightningServer) mustEmbedUnimplementedLightningServer() {}
 
-// UnsafeLightningServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to LightningServer will
-// result in compilation errors.
-type UnsafeLightningServer interface {
-	mustEmbedUnimplementedLightningServer()
+type lightningSubscribeInvoicesServer struct {
+	grpc.ServerStream
 }
 
-func RegisterLightningServer(s grpc.ServiceRegistrar, srv LightningServer) {
-	s.RegisterService(&Lightning_ServiceDesc, srv)
+func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(WalletBalanceRequest)
+func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayReqString)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).WalletBalance(ctx, in)
+		return srv.(LightningServer).DecodePayReq(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/WalletBalance",
+		FullMethod: "/lnrpc.Lightning/DecodePayReq",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).WalletBalance(ctx, req.(*WalletBalanceRequest))
+		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChannelBalanceRequest)
+func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ChannelBalance(ctx, in)
+		return srv.(LightningServer).ListPayments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ChannelBalance",
+		FullMethod: "/lnrpc.Lightning/ListPayments",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ChannelBalance(ctx, req.(*ChannelBalanceRequest))
+		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTransactionsRequest)
+func _Lightning_DeletePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePaymentRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetTransactions(ctx, in)
+		return srv.(LightningServer).DeletePayment(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetTransactions",
+		FullMethod: "/lnrpc.Lightning/DeletePayment",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+		return srv.(LightningServer).DeletePayment(ctx, req.(*DeletePaymentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_EstimateFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(EstimateFeeRequest)
+func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAllPaymentsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).EstimateFee(ctx, in)
+		return srv.(LightningServer).DeleteAllPayments(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/EstimateFee",
+		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).EstimateFee(ctx, req.(*EstimateFeeRequest))
+		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SendCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendCoinsRequest)
+func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelGraphRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendCoins(ctx, in)
+		return srv.(LightningServer).DescribeGraph(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendCoins",
+		FullMethod: "/lnrpc.Lightning/DescribeGraph",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendCoins(ctx, req.(*SendCoinsRequest))
+		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListUnspent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListUnspentRequest)
+func _Lightning_GetNodeMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeMetricsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListUnspent(ctx, in)
+		return srv.(LightningServer).GetNodeMetrics(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListUnspent",
+		FullMethod: "/lnrpc.Lightning/GetNodeMetrics",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListUnspent(ctx, req.(*ListUnspentRequest))
+		return srv.(LightningServer).GetNodeMetrics(ctx, req.(*NodeMetricsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetTransactionsRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(LightningServer).SubscribeTransactions(m, &lightningSubscribeTransactionsServer{stream})
-}
-
-type Lightning_SubscribeTransactionsServer interface {
-	Send(*Transaction) error
-	grpc.ServerStream
-}
-
-type lightningSubscribeTransactionsServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSubscribeTransactionsServer) Send(m *Transaction) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendManyRequest)
+func _Lightning_GetNodeMetricsV2_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeMetricsV2Request)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendMany(ctx, in)
+		return srv.(LightningServer).GetNodeMetricsV2(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendMany",
+		FullMethod: "/lnrpc.Lightning/GetNodeMetricsV2",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendMany(ctx, req.(*SendManyRequest))
+		return srv.(LightningServer).GetNodeMetricsV2(ctx, req.(*NodeMetricsV2Request))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewAddressRequest)
+func _Lightning_GetGraphMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GraphMetricsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).NewAddress(ctx, in)
+		return srv.(LightningServer).GetGraphMetrics(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/NewAddress",
+		FullMethod: "/lnrpc.Lightning/GetGraphMetrics",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).NewAddress(ctx, req.(*NewAddressRequest))
+		return srv.(LightningServer).GetGraphMetrics(ctx, req.(*GraphMetricsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SignMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SignMessageRequest)
+func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChanInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SignMessage(ctx, in)
+		return srv.(LightningServer).GetChanInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SignMessage",
+		FullMethod: "/lnrpc.Lightning/GetChanInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SignMessage(ctx, req.(*SignMessageRequest))
+		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_VerifyMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(VerifyMessageRequest)
+func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).VerifyMessage(ctx, in)
+		return srv.(LightningServer).GetNodeInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/VerifyMessage",
+		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).VerifyMessage(ctx, req.(*VerifyMessageRequest))
+		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ConnectPeerRequest)
+func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRoutesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ConnectPeer(ctx, in)
+		return srv.(LightningServer).QueryRoutes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ConnectPeer",
+		FullMethod: "/lnrpc.Lightning/QueryRoutes",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ConnectPeer(ctx, req.(*ConnectPeerRequest))
+		return srv.(LightningServer).QueryRoutes(ctx, req.(*QueryRoutesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DisconnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DisconnectPeerRequest)
+func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DisconnectPeer(ctx, in)
+		return srv.(LightningServer).GetNetworkInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DisconnectPeer",
+		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DisconnectPeer(ctx, req.(*DisconnectPeerRequest))
+		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPeersRequest)
+func _Lightning_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPeers(ctx, in)
+		return srv.(LightningServer).StopDaemon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPeers",
+		FullMethod: "/lnrpc.Lightning/StopDaemon",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPeers(ctx, req.(*ListPeersRequest))
+		return srv.(LightningServer).StopDaemon(ctx, req.(*StopRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribePeerEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(PeerEventSubscription)
+func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GraphTopologySubscription)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).SubscribePeerEvents(m, &lightningSubscribePeerEventsServer{stream})
+	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
 }
 
-type Lightning_SubscribePeerEventsServer interface {
-	Send(*PeerEvent) error
+type Lightning_SubscribeChannelGraphServer interface {
+	Send(*GraphTopologyUpdate) error
 	grpc.ServerStream
 }
 
-type lightningSubscribePeerEventsServer struct {
+type lightningSubscribeChannelGraphServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSubscribePeerEventsServer) Send(m *PeerEvent) error {
+func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetInfoRequest)
+func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugLevelRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetInfo(ctx, in)
+		return srv.(LightningServer).DebugLevel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetInfo",
+		FullMethod: "/lnrpc.Lightning/DebugLevel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetInfo(ctx, req.(*GetInfoRequest))
+		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetDebugInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetDebugInfoRequest)
+func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeeReportRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetDebugInfo(ctx, in)
+		return srv.(LightningServer).FeeReport(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetDebugInfo",
+		FullMethod: "/lnrpc.Lightning/FeeReport",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetDebugInfo(ctx, req.(*GetDebugInfoRequest))
+		return srv.(LightningServer).FeeReport(ctx, req.(*FeeReportRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetRecoveryInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetRecoveryInfoRequest)
+func _Lightning_UpdateChannelPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PolicyUpdateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetRecoveryInfo(ctx, in)
+		return srv.(LightningServer).UpdateChannelPolicy(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetRecoveryInfo",
+		FullMethod: "/lnrpc.Lightning/UpdateChannelPolicy",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetRecoveryInfo(ctx, req.(*GetRecoveryInfoRequest))
+		return srv.(LightningServer).UpdateChannelPolicy(ctx, req.(*PolicyUpdateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PendingChannelsRequest)
+func _Lightning_ForwardingHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardingHistoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).PendingChannels(ctx, in)
+		return srv.(LightningServer).ForwardingHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/PendingChannels",
+		FullMethod: "/lnrpc.Lightning/ForwardingHistory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).PendingChannels(ctx, req.(*PendingChannelsRequest))
+		return srv.(LightningServer).ForwardingHistory(ctx, req.(*ForwardingHistoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListChannelsRequest)
+func _Lightning_ExportChannelBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportChannelBackupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListChannels(ctx, in)
+		return srv.(LightningServer).ExportChannelBackup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListChannels",
+		FullMethod: "/lnrpc.Lightning/ExportChannelBackup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListChannels(ctx, req.(*ListChannelsRequest))
+		return srv.(LightningServer).ExportChannelBackup(ctx, req.(*ExportChannelBackupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeChannelEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ChannelEventSubscription)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(LightningServer).SubscribeChannelEvents(m, &lightningSubscribeChannelEventsServer{stream})
-}
-
-type Lightning_SubscribeChannelEventsServer interface {
-	Send(*ChannelEventUpdate) error
-	grpc.ServerStream
-}
-
-type lightningSubscribeChannelEventsServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSubscribeChannelEventsServer) Send(m *ChannelEventUpdate) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_ClosedChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ClosedChannelsRequest)
+func _Lightning_ExportAllChannelBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChanBackupExportRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ClosedChannels(ctx, in)
+		return srv.(LightningServer).ExportAllChannelBackups(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ClosedChannels",
+		FullMethod: "/lnrpc.Lightning/ExportAllChannelBackups",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ClosedChannels(ctx, req.(*ClosedChannelsRequest))
+		return srv.(LightningServer).ExportAllChannelBackups(ctx, req.(*ChanBackupExportRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_OpenChannelSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OpenChannelRequest)
+func _Lightning_ExportEmergencyCloseBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmergencyCloseBundleRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).OpenChannelSync(ctx, in)
+		return srv.(LightningServer).ExportEmergencyCloseBundle(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/OpenChannelSync",
+		FullMethod: "/lnrpc.Lightning/ExportEmergencyCloseBundle",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).OpenChannelSync(ctx, req.(*OpenChannelRequest))
+		return srv.(LightningServer).ExportEmergencyCloseBundle(ctx, req.(*EmergencyCloseBundleRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_OpenChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(OpenChannelRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(LightningServer).OpenChannel(m, &lightningOpenChannelServer{stream})
-}
-
-type Lightning_OpenChannelServer interface {
-	Send(*OpenStatusUpdate) error
-	grpc.ServerStream
-}
-
-type lightningOpenChannelServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningOpenChannelServer) Send(m *OpenStatusUpdate) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_BatchOpenChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BatchOpenChannelRequest)
+func _Lightning_VerifyChanBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChanBackupSnapshot)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).BatchOpenChannel(ctx, in)
+		return srv.(LightningServer).VerifyChanBackup(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/BatchOpenChannel",
+		FullMethod: "/lnrpc.Lightning/VerifyChanBackup",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).BatchOpenChannel(ctx, req.(*BatchOpenChannelRequest))
+		return srv.(LightningServer).VerifyChanBackup(ctx, req.(*ChanBackupSnapshot))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_FundingStateStep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FundingTransitionMsg)
+func _Lightning_RestoreChannelBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreChanBackupRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).FundingStateStep(ctx, in)
+		return srv.(LightningServer).RestoreChannelBackups(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/FundingStateStep",
+		FullMethod: "/lnrpc.Lightning/RestoreChannelBackups",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).FundingStateStep(ctx, req.(*FundingTransitionMsg))
+		return srv.(LightningServer).RestoreChannelBackups(ctx, req.(*RestoreChanBackupRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ChannelAcceptor_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(LightningServer).ChannelAcceptor(&lightningChannelAcceptorServer{stream})
-}
-
-type Lightning_ChannelAcceptorServer interface {
-	Send(*ChannelAcceptRequest) error
-	Recv() (*ChannelAcceptResponse, error)
-	grpc.ServerStream
-}
-
-type lightningChannelAcceptorServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningChannelAcceptorServer) Send(m *ChannelAcceptRequest) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func (x *lightningChannelAcceptorServer) Recv() (*ChannelAcceptResponse, error) {
-	m := new(ChannelAcceptResponse)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func _Lightning_CloseChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(CloseChannelRequest)
+func _Lightning_SubscribeChannelBackups_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChannelBackupSubscription)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).CloseChannel(m, &lightningCloseChannelServer{stream})
+	return srv.(LightningServer).SubscribeChannelBackups(m, &lightningSubscribeChannelBackupsServer{stream})
 }
 
-type Lightning_CloseChannelServer interface {
-	Send(*CloseStatusUpdate) error
+type Lightning_SubscribeChannelBackupsServer interface {
+	Send(*ChanBackupSnapshot) error
 	grpc.ServerStream
 }
 
-type lightningCloseChannelServer struct {
+type lightningSubscribeChannelBackupsServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
+func (x *lightningSubscribeChannelBackupsServer) Send(m *ChanBackupSnapshot) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(AbandonChannelRequest)
+func _Lightning_BakeMacaroon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BakeMacaroonRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).AbandonChannel(ctx, in)
+		return srv.(LightningServer).BakeMacaroon(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/AbandonChannel",
+		FullMethod: "/lnrpc.Lightning/BakeMacaroon",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).AbandonChannel(ctx, req.(*AbandonChannelRequest))
+		return srv.(LightningServer).BakeMacaroon(ctx, req.(*BakeMacaroonRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
-}
-
-type Lightning_SendPaymentServer interface {
-	Send(*SendResponse) error
-	Recv() (*SendRequest, error)
-	grpc.ServerStream
-}
-
-type lightningSendPaymentServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSendPaymentServer) Send(m *SendResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func (x *lightningSendPaymentServer) Recv() (*SendRequest, error) {
-	m := new(SendRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func _Lightning_SendPaymentSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendRequest)
+func _Lightning_ListMacaroonIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMacaroonIDsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendPaymentSync(ctx, in)
+		return srv.(LightningServer).ListMacaroonIDs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendPaymentSync",
+		FullMethod: "/lnrpc.Lightning/ListMacaroonIDs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendPaymentSync(ctx, req.(*SendRequest))
+		return srv.(LightningServer).ListMacaroonIDs(ctx, req.(*ListMacaroonIDsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SendToRoute_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(LightningServer).SendToRoute(&lightningSendToRouteServer{stream})
-}
-
-type Lightning_SendToRouteServer interface {
-	Send(*SendResponse) error
-	Recv() (*SendToRouteRequest, error)
-	grpc.ServerStream
-}
-
-type lightningSendToRouteServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSendToRouteServer) Send(m *SendResponse) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func (x *lightningSendToRouteServer) Recv() (*SendToRouteRequest, error) {
-	m := new(SendToRouteRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func _Lightning_SendToRouteSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendToRouteRequest)
+func _Lightning_DeleteMacaroonID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMacaroonIDRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendToRouteSync(ctx, in)
+		return srv.(LightningServer).DeleteMacaroonID(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendToRouteSync",
+		FullMethod: "/lnrpc.Lightning/DeleteMacaroonID",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendToRouteSync(ctx, req.(*SendToRouteRequest))
+		return srv.(LightningServer).DeleteMacaroonID(ctx, req.(*DeleteMacaroonIDRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Invoice)
+func _Lightning_ListPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPermissionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).AddInvoice(ctx, in)
+		return srv.(LightningServer).ListPermissions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/AddInvoice",
+		FullMethod: "/lnrpc.Lightning/ListPermissions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).AddInvoice(ctx, req.(*Invoice))
+		return srv.(LightningServer).ListPermissions(ctx, req.(*ListPermissionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListInvoiceRequest)
+func _Lightning_CheckMacaroonPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckMacPermRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListInvoices(ctx, in)
+		return srv.(LightningServer).CheckMacaroonPermissions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListInvoices",
+		FullMethod: "/lnrpc.Lightning/CheckMacaroonPermissions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListInvoices(ctx, req.(*ListInvoiceRequest))
+		return srv.(LightningServer).CheckMacaroonPermissions(ctx, req.(*CheckMacPermRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PaymentHash)
+func _Lightning_RegisterRPCMiddleware_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).RegisterRPCMiddleware(&lightningRegisterRPCMiddlewareServer{stream})
+}
+
+type Lightning_RegisterRPCMiddlewareServer interface {
+	Send(*RPCMiddlewareRequest) error
+	Recv() (*RPCMiddlewareResponse, error)
+	grpc.ServerStream
+}
+
+type lightningRegisterRPCMiddlewareServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningRegisterRPCMiddlewareServer) Send(m *RPCMiddlewareRequest) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lightningRegisterRPCMiddlewareServer) Recv() (*RPCMiddlewareResponse, error) {
+	m := new(RPCMiddlewareResponse)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Lightning_SendCustomMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCustomMessageRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).LookupInvoice(ctx, in)
+		return srv.(LightningServer).SendCustomMessage(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/LookupInvoice",
+		FullMethod: "/lnrpc.Lightning/SendCustomMessage",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).LookupInvoice(ctx, req.(*PaymentHash))
+		return srv.(LightningServer).SendCustomMessage(ctx, req.(*SendCustomMessageRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(InvoiceSubscription)
+func _Lightning_SubscribeCustomMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeCustomMessagesRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).SubscribeInvoices(m, &lightningSubscribeInvoicesServer{stream})
+	return srv.(LightningServer).SubscribeCustomMessages(m, &lightningSubscribeCustomMessagesServer{stream})
 }
 
-type Lightning_SubscribeInvoicesServer interface {
-	Send(*Invoice) error
+type Lightning_SubscribeCustomMessagesServer interface {
+	Send(*CustomMessage) error
 	grpc.ServerStream
 }
 
-type lightningSubscribeInvoicesServer struct {
+type lightningSubscribeCustomMessagesServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
+func (x *lightningSubscribeCustomMessagesServer) Send(m *CustomMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PayReqString)
+func _Lightning_ListAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAliasesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DecodePayReq(ctx, in)
+		return srv.(LightningServer).ListAliases(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DecodePayReq",
+		FullMethod: "/lnrpc.Lightning/ListAliases",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
+		return srv.(LightningServer).ListAliases(ctx, req.(*ListAliasesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPaymentsRequest)
+func _Lightning_LookupHtlcResolution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupHtlcResolutionRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPayments(ctx, in)
+		return srv.(LightningServer).LookupHtlcResolution(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPayments",
+		FullMethod: "/lnrpc.Lightning/LookupHtlcResolution",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+		return srv.(LightningServer).LookupHtlcResolution(ctx, req.(*LookupHtlcResolutionRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DeletePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeletePaymentRequest)
+func _Lightning_ListChannelTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelTemplatesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DeletePayment(ctx, in)
+		return srv.(LightningServer).ListChannelTemplates(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DeletePayment",
+		FullMethod: "/lnrpc.Lightning/ListChannelTemplates",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DeletePayment(ctx, req.(*DeletePaymentRequest))
+		return srv.(LightningServer).ListChannelTemplates(ctx, req.(*ListChannelTemplatesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteAllPaymentsRequest)
+func _Lightning_SetChannelTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetChannelTemplateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DeleteAllPayments(ctx, in)
+		return srv.(LightningServer).SetChannelTemplate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
+		FullMethod: "/lnrpc.Lightning/SetChannelTemplate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
+		return srv.(LightningServer).SetChannelTemplate(ctx, req.(*SetChannelTemplateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChannelGraphRequest)
+func _Lightning_DeleteChannelTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteChannelTemplateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DescribeGraph(ctx, in)
+		return srv.(LightningServer).DeleteChannelTemplate(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DescribeGraph",
+		FullMethod: "/lnrpc.Lightning/DeleteChannelTemplate",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
+		return srv.(LightningServer).DeleteChannelTemplate(ctx, req.(*DeleteChannelTemplateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNodeMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NodeMetricsRequest)
+func _Lightning_GetRuntimeConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRuntimeConfigRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNodeMetrics(ctx, in)
+		return srv.(LightningServer).GetRuntimeConfig(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNodeMetrics",
+		FullMethod: "/lnrpc.Lightning/GetRuntimeConfig",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNodeMetrics(ctx, req.(*NodeMetricsRequest))
+		return srv.(LightningServer).GetRuntimeConfig(ctx, req.(*GetRuntimeConfigRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChanInfoRequest)
+func _Lightning_UpdateRuntimeConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRuntimeConfigRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetChanInfo(ctx, in)
+		return srv.(LightningServer).UpdateRuntimeConfig(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetChanInfo",
+		FullMethod: "/lnrpc.Lightning/UpdateRuntimeConfig",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
+		return srv.(LightningServer).UpdateRuntimeConfig(ctx, req.(*UpdateRuntimeConfigRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NodeInfoRequest)
+func _Lightning_ExportNodeProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportNodeProfileRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNodeInfo(ctx, in)
+		return srv.(LightningServer).ExportNodeProfile(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
+		FullMethod: "/lnrpc.Lightning/ExportNodeProfile",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
+		return srv.(LightningServer).ExportNodeProfile(ctx, req.(*ExportNodeProfileRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_QueryRoutes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(QueryRoutesRequest)
+func _Lightning_ImportNodeProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportNodeProfileRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).QueryRoutes(ctx, in)
+		return srv.(LightningServer).ImportNodeProfile(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/QueryRoutes",
+		FullMethod: "/lnrpc.Lightning/ImportNodeProfile",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).QueryRoutes(ctx, req.(*QueryRoutesRequest))
+		return srv.(LightningServer).ImportNodeProfile(ctx, req.(*ImportNodeProfileRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NetworkInfoRequest)
+func _Lightning_ListInboxMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInboxMessagesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNetworkInfo(ctx, in)
+		return srv.(LightningServer).ListInboxMessages(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
+		FullMethod: "/lnrpc.Lightning/ListInboxMessages",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
+		return srv.(LightningServer).ListInboxMessages(ctx, req.(*ListInboxMessagesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_StopDaemon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(StopRequest)
+func _Lightning_AckInboxMessages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckInboxMessagesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).StopDaemon(ctx, in)
+		return srv.(LightningServer).AckInboxMessages(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/StopDaemon",
+		FullMethod: "/lnrpc.Lightning/AckInboxMessages",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).StopDaemon(ctx, req.(*StopRequest))
+		return srv.(LightningServer).AckInboxMessages(ctx, req.(*AckInboxMessagesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GraphTopologySubscription)
+func _Lightning_SubscribeInbox_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeInboxRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
+	return srv.(LightningServer).SubscribeInbox(m, &lightningSubscribeInboxServer{stream})
 }
 
-type Lightning_SubscribeChannelGraphServer interface {
-	Send(*GraphTopologyUpdate) error
+type Lightning_SubscribeInboxServer interface {
+	Send(*InboxMessage) error
 	grpc.ServerStream
 }
 
-type lightningSubscribeChannelGraphServer struct {
+type lightningSubscribeInboxServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
+func (x *lightningSubscribeInboxServer) Send(m *InboxMessage) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DebugLevelRequest)
+func _Lightning_ListChannelAcceptPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelAcceptPoliciesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DebugLevel(ctx, in)
+		return srv.(LightningServer).ListChannelAcceptPolicies(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DebugLevel",
+		FullMethod: "/lnrpc.Lightning/ListChannelAcceptPolicies",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+		return srv.(LightningServer).ListChannelAcceptPolicies(ctx, req.(*ListChannelAcceptPoliciesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_FeeReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FeeReportRequest)
+func _Lightning_UpdateChannelAcceptPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateChannelAcceptPoliciesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).FeeReport(ctx, in)
+		return srv.(LightningServer).UpdateChannelAcceptPolicies(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/FeeReport",
+		FullMethod: "/lnrpc.Lightning/UpdateChannelAcceptPolicies",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).FeeReport(ctx, req.(*FeeReportRequest))
+		return srv.(LightningServer).UpdateChannelAcceptPolicies(ctx, req.(*UpdateChannelAcceptPoliciesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_UpdateChannelPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PolicyUpdateRequest)
+func _Lightning_ListChannelAcceptDecisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelAcceptDecisionsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).UpdateChannelPolicy(ctx, in)
+		return srv.(LightningServer).ListChannelAcceptDecisions(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/UpdateChannelPolicy",
+		FullMethod: "/lnrpc.Lightning/ListChannelAcceptDecisions",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).UpdateChannelPolicy(ctx, req.(*PolicyUpdateRequest))
+		return srv.(LightningServer).ListChannelAcceptDecisions(ctx, req.(*ListChannelAcceptDecisionsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ForwardingHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ForwardingHistoryRequest)
+func _Lightning_ListAbortedPsbtFlows_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAbortedPsbtFlowsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ForwardingHistory(ctx, in)
+		return srv.(LightningServer).ListAbortedPsbtFlows(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ForwardingHistory",
+		FullMethod: "/lnrpc.Lightning/ListAbortedPsbtFlows",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ForwardingHistory(ctx, req.(*ForwardingHistoryRequest))
+		return srv.(LightningServer).ListAbortedPsbtFlows(ctx, req.(*ListAbortedPsbtFlowsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ExportChannelBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ExportChannelBackupRequest)
+func _Lightning_ForgetAbortedPsbtFlow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForgetAbortedPsbtFlowRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ExportChannelBackup(ctx, in)
+		return srv.(LightningServer).ForgetAbortedPsbtFlow(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ExportChannelBackup",
+		FullMethod: "/lnrpc.Lightning/ForgetAbortedPsbtFlow",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ExportChannelBackup(ctx, req.(*ExportChannelBackupRequest))
+		return srv.(LightningServer).ForgetAbortedPsbtFlow(ctx, req.(*ForgetAbortedPsbtFlowRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ExportAllChannelBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChanBackupExportRequest)
+func _Lightning_ListUtxoReservations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUtxoReservationsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ExportAllChannelBackups(ctx, in)
+		return srv.(LightningServer).ListUtxoReservations(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ExportAllChannelBackups",
+		FullMethod: "/lnrpc.Lightning/ListUtxoReservations",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ExportAllChannelBackups(ctx, req.(*ChanBackupExportRequest))
+		return srv.(LightningServer).ListUtxoReservations(ctx, req.(*ListUtxoReservationsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_VerifyChanBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChanBackupSnapshot)
+func _Lightning_ReleaseUtxoReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseUtxoReservationRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).VerifyChanBackup(ctx, in)
+		return srv.(LightningServer).ReleaseUtxoReservation(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/VerifyChanBackup",
+		FullMethod: "/lnrpc.Lightning/ReleaseUtxoReservation",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).VerifyChanBackup(ctx, req.(*ChanBackupSnapshot))
+		return srv.(LightningServer).ReleaseUtxoReservation(ctx, req.(*ReleaseUtxoReservationRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_RestoreChannelBackups_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RestoreChanBackupRequest)
+func _Lightning_ListGossipPeerScores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListGossipPeerScoresRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).RestoreChannelBackups(ctx, in)
+		return srv.(LightningServer).ListGossipPeerScores(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/RestoreChannelBackups",
+		FullMethod: "/lnrpc.Lightning/ListGossipPeerScores",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).RestoreChannelBackups(ctx, req.(*RestoreChanBackupRequest))
+		return srv.(LightningServer).ListGossipPeerScores(ctx, req.(*ListGossipPeerScoresRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeChannelBackups_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ChannelBackupSubscription)
+func _Lightning_ExportGraphSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportGraphSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExportGraphSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportGraphSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportGraphSnapshot(ctx, req.(*ExportGraphSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListJobs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeJobEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeJobEventsRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).SubscribeChannelBackups(m, &lightningSubscribeChannelBackupsServer{stream})
+	return srv.(LightningServer).SubscribeJobEvents(m, &lightningSubscribeJobEventsServer{stream})
 }
 
-type Lightning_SubscribeChannelBackupsServer interface {
-	Send(*ChanBackupSnapshot) error
+type Lightning_SubscribeJobEventsServer interface {
+	Send(*JobEvent) error
 	grpc.ServerStream
 }
 
-type lightningSubscribeChannelBackupsServer struct {
+type lightningSubscribeJobEventsServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSubscribeChannelBackupsServer) Send(m *ChanBackupSnapshot) error {
+func (x *lightningSubscribeJobEventsServer) Send(m *JobEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_BakeMacaroon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(BakeMacaroonRequest)
+func _Lightning_CancelJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelJobRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).BakeMacaroon(ctx, in)
+		return srv.(LightningServer).CancelJob(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/BakeMacaroon",
+		FullMethod: "/lnrpc.Lightning/CancelJob",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).BakeMacaroon(ctx, req.(*BakeMacaroonRequest))
+		return srv.(LightningServer).CancelJob(ctx, req.(*CancelJobRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListMacaroonIDs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListMacaroonIDsRequest)
+func _Lightning_CreateAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccountRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListMacaroonIDs(ctx, in)
+		return srv.(LightningServer).CreateAccount(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListMacaroonIDs",
+		FullMethod: "/lnrpc.Lightning/CreateAccount",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListMacaroonIDs(ctx, req.(*ListMacaroonIDsRequest))
+		return srv.(LightningServer).CreateAccount(ctx, req.(*CreateAccountRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DeleteMacaroonID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteMacaroonIDRequest)
+func _Lightning_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAccountsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DeleteMacaroonID(ctx, in)
+		return srv.(LightningServer).ListAccounts(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DeleteMacaroonID",
+		FullMethod: "/lnrpc.Lightning/ListAccounts",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DeleteMacaroonID(ctx, req.(*DeleteMacaroonIDRequest))
+		return srv.(LightningServer).ListAccounts(ctx, req.(*ListAccountsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPermissionsRequest)
+func _Lightning_AdjustAccountBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustAccountBalanceRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPermissions(ctx, in)
+		return srv.(LightningServer).AdjustAccountBalance(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPermissions",
+		FullMethod: "/lnrpc.Lightning/AdjustAccountBalance",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPermissions(ctx, req.(*ListPermissionsRequest))
+		return srv.(LightningServer).AdjustAccountBalance(ctx, req.(*AdjustAccountBalanceRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_CheckMacaroonPermissions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CheckMacPermRequest)
+func _Lightning_AccountLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountLedgerRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).CheckMacaroonPermissions(ctx, in)
+		return srv.(LightningServer).AccountLedger(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/CheckMacaroonPermissions",
+		FullMethod: "/lnrpc.Lightning/AccountLedger",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).CheckMacaroonPermissions(ctx, req.(*CheckMacPermRequest))
+		return srv.(LightningServer).AccountLedger(ctx, req.(*AccountLedgerRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_RegisterRPCMiddleware_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(LightningServer).RegisterRPCMiddleware(&lightningRegisterRPCMiddlewareServer{stream})
-}
-
-type Lightning_RegisterRPCMiddlewareServer interface {
-	Send(*RPCMiddlewareRequest) error
-	Recv() (*RPCMiddlewareResponse, error)
-	grpc.ServerStream
-}
-
-type lightningRegisterRPCMiddlewareServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningRegisterRPCMiddlewareServer) Send(m *RPCMiddlewareRequest) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func (x *lightningRegisterRPCMiddlewareServer) Recv() (*RPCMiddlewareResponse, error) {
-	m := new(RPCMiddlewareResponse)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
-}
-
-func _Lightning_SendCustomMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendCustomMessageRequest)
+func _Lightning_RemoveAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveAccountRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendCustomMessage(ctx, in)
+		return srv.(LightningServer).RemoveAccount(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendCustomMessage",
+		FullMethod: "/lnrpc.Lightning/RemoveAccount",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendCustomMessage(ctx, req.(*SendCustomMessageRequest))
+		return srv.(LightningServer).RemoveAccount(ctx, req.(*RemoveAccountRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeCustomMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(SubscribeCustomMessagesRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
-	}
-	return srv.(LightningServer).SubscribeCustomMessages(m, &lightningSubscribeCustomMessagesServer{stream})
-}
-
-type Lightning_SubscribeCustomMessagesServer interface {
-	Send(*CustomMessage) error
-	grpc.ServerStream
-}
-
-type lightningSubscribeCustomMessagesServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSubscribeCustomMessagesServer) Send(m *CustomMessage) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_ListAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListAliasesRequest)
+func _Lightning_SetForwardingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetForwardingRulesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListAliases(ctx, in)
+		return srv.(LightningServer).SetForwardingRules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListAliases",
+		FullMethod: "/lnrpc.Lightning/SetForwardingRules",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListAliases(ctx, req.(*ListAliasesRequest))
+		return srv.(LightningServer).SetForwardingRules(ctx, req.(*SetForwardingRulesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_LookupHtlcResolution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LookupHtlcResolutionRequest)
+func _Lightning_ListForwardingRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListForwardingRulesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).LookupHtlcResolution(ctx, in)
+		return srv.(LightningServer).ListForwardingRules(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/LookupHtlcResolution",
+		FullMethod: "/lnrpc.Lightning/ListForwardingRules",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).LookupHtlcResolution(ctx, req.(*LookupHtlcResolutionRequest))
+		return srv.(LightningServer).ListForwardingRules(ctx, req.(*ListForwardingRulesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -3323,6 +4773,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListChannels",
 			Handler:    _Lightning_ListChannels_Handler,
 		},
+		{
+			MethodName: "HtlcExpiryRisks",
+			Handler:    _Lightning_HtlcExpiryRisks_Handler,
+		},
 		{
 			MethodName: "ClosedChannels",
 			Handler:    _Lightning_ClosedChannels_Handler,
@@ -3387,6 +4841,14 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetNodeMetrics",
 			Handler:    _Lightning_GetNodeMetrics_Handler,
 		},
+		{
+			MethodName: "GetNodeMetricsV2",
+			Handler:    _Lightning_GetNodeMetricsV2_Handler,
+		},
+		{
+			MethodName: "GetGraphMetrics",
+			Handler:    _Lightning_GetGraphMetrics_Handler,
+		},
 		{
 			MethodName: "GetChanInfo",
 			Handler:    _Lightning_GetChanInfo_Handler,
@@ -3431,6 +4893,10 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExportAllChannelBackups",
 			Handler:    _Lightning_ExportAllChannelBackups_Handler,
 		},
+		{
+			MethodName: "ExportEmergencyCloseBundle",
+			Handler:    _Lightning_ExportEmergencyCloseBundle_Handler,
+		},
 		{
 			MethodName: "VerifyChanBackup",
 			Handler:    _Lightning_VerifyChanBackup_Handler,
@@ -3471,6 +4937,114 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LookupHtlcResolution",
 			Handler:    _Lightning_LookupHtlcResolution_Handler,
 		},
+		{
+			MethodName: "ListChannelTemplates",
+			Handler:    _Lightning_ListChannelTemplates_Handler,
+		},
+		{
+			MethodName: "SetChannelTemplate",
+			Handler:    _Lightning_SetChannelTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteChannelTemplate",
+			Handler:    _Lightning_DeleteChannelTemplate_Handler,
+		},
+		{
+			MethodName: "GetRuntimeConfig",
+			Handler:    _Lightning_GetRuntimeConfig_Handler,
+		},
+		{
+			MethodName: "UpdateRuntimeConfig",
+			Handler:    _Lightning_UpdateRuntimeConfig_Handler,
+		},
+		{
+			MethodName: "ExportNodeProfile",
+			Handler:    _Lightning_ExportNodeProfile_Handler,
+		},
+		{
+			MethodName: "ImportNodeProfile",
+			Handler:    _Lightning_ImportNodeProfile_Handler,
+		},
+		{
+			MethodName: "ListInboxMessages",
+			Handler:    _Lightning_ListInboxMessages_Handler,
+		},
+		{
+			MethodName: "AckInboxMessages",
+			Handler:    _Lightning_AckInboxMessages_Handler,
+		},
+		{
+			MethodName: "ListChannelAcceptPolicies",
+			Handler:    _Lightning_ListChannelAcceptPolicies_Handler,
+		},
+		{
+			MethodName: "UpdateChannelAcceptPolicies",
+			Handler:    _Lightning_UpdateChannelAcceptPolicies_Handler,
+		},
+		{
+			MethodName: "ListChannelAcceptDecisions",
+			Handler:    _Lightning_ListChannelAcceptDecisions_Handler,
+		},
+		{
+			MethodName: "ListAbortedPsbtFlows",
+			Handler:    _Lightning_ListAbortedPsbtFlows_Handler,
+		},
+		{
+			MethodName: "ForgetAbortedPsbtFlow",
+			Handler:    _Lightning_ForgetAbortedPsbtFlow_Handler,
+		},
+		{
+			MethodName: "ListUtxoReservations",
+			Handler:    _Lightning_ListUtxoReservations_Handler,
+		},
+		{
+			MethodName: "ReleaseUtxoReservation",
+			Handler:    _Lightning_ReleaseUtxoReservation_Handler,
+		},
+		{
+			MethodName: "ListGossipPeerScores",
+			Handler:    _Lightning_ListGossipPeerScores_Handler,
+		},
+		{
+			MethodName: "ExportGraphSnapshot",
+			Handler:    _Lightning_ExportGraphSnapshot_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _Lightning_ListJobs_Handler,
+		},
+		{
+			MethodName: "CancelJob",
+			Handler:    _Lightning_CancelJob_Handler,
+		},
+		{
+			MethodName: "CreateAccount",
+			Handler:    _Lightning_CreateAccount_Handler,
+		},
+		{
+			MethodName: "ListAccounts",
+			Handler:    _Lightning_ListAccounts_Handler,
+		},
+		{
+			MethodName: "AdjustAccountBalance",
+			Handler:    _Lightning_AdjustAccountBalance_Handler,
+		},
+		{
+			MethodName: "AccountLedger",
+			Handler:    _Lightning_AccountLedger_Handler,
+		},
+		{
+			MethodName: "RemoveAccount",
+			Handler:    _Lightning_RemoveAccount_Handler,
+		},
+		{
+			MethodName: "SetForwardingRules",
+			Handler:    _Lightning_SetForwardingRules_Handler,
+		},
+		{
+			MethodName: "ListForwardingRules",
+			Handler:    _Lightning_ListForwardingRules_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3488,6 +5062,11 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeChannelEvents_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeChannelResolutions",
+			Handler:       _Lightning_SubscribeChannelResolutions_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "OpenChannel",
 			Handler:       _Lightning_OpenChannel_Handler,
@@ -3542,6 +5121,16 @@ var Lightning_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeCustomMessages_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeInbox",
+			Handler:       _Lightning_SubscribeInbox_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeJobEvents",
+			Handler:       _Lightning_SubscribeJobEvents_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "lightning.proto",
 }
@@ -0,0 +1,137 @@
+package lnrpc
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// DefaultPageSize is the number of items returned per page if the
+	// caller doesn't specify a page size.
+	DefaultPageSize = 100
+
+	// MaxPageSize is the maximum number of items returned per page,
+	// regardless of the requested page size.
+	MaxPageSize = 1000
+
+	// pageCursorVersion is the version of the page cursor encoding.
+	pageCursorVersion = 0
+)
+
+// ErrInvalidPageCursor is returned if a page cursor can't be decoded, or
+// belongs to a different listing.
+var ErrInvalidPageCursor = errors.New("invalid page cursor")
+
+// CursorKind identifies the listing a page cursor belongs to, so that the
+// cursor of one listing can't be used to continue another one.
+type CursorKind uint8
+
+const (
+	// CursorInvoices is the kind of the cursors of ListInvoices.
+	CursorInvoices CursorKind = iota + 1
+
+	// CursorPayments is the kind of the cursors of ListPayments.
+	CursorPayments
+
+	// CursorForwards is the kind of the cursors of ForwardingHistory.
+	CursorForwards
+
+	// CursorChannels is the kind of the cursors of ListChannels.
+	CursorChannels
+
+	// CursorGraphNodes is the kind of the cursors of DescribeGraph while
+	// it lists the nodes of the graph.
+	CursorGraphNodes
+
+	// CursorGraphEdges is the kind of the cursors of DescribeGraph while
+	// it lists the edges of the graph.
+	CursorGraphEdges
+)
+
+// PageCursor is the decoded form of an opaque page token. It points to the
+// last item of the previous page.
+type PageCursor struct {
+	// Kind is the listing the cursor belongs to.
+	Kind CursorKind
+
+	// Position is the storage key of the last item of the previous page.
+	Position []byte
+}
+
+// Encode returns the opaque token of the cursor.
+func (c *PageCursor) Encode() string {
+	raw := make([]byte, 0, 2+len(c.Position))
+	raw = append(raw, pageCursorVersion, byte(c.Kind))
+	raw = append(raw, c.Position...)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodePageCursor decodes an opaque page token. A nil cursor is returned for
+// an empty token, which refers to the first page.
+func DecodePageCursor(token string) (*PageCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPageCursor, err)
+	}
+
+	if len(raw) < 2 || raw[0] != pageCursorVersion {
+		return nil, ErrInvalidPageCursor
+	}
+
+	return &PageCursor{
+		Kind:     CursorKind(raw[1]),
+		Position: raw[2:],
+	}, nil
+}
+
+// EncodeIndexCursor returns the opaque token of a cursor that points to the
+// item with the given index.
+func EncodeIndexCursor(kind CursorKind, index uint64) string {
+	var position [8]byte
+	binary.BigEndian.PutUint64(position[:], index)
+
+	cursor := &PageCursor{
+		Kind:     kind,
+		Position: position[:],
+	}
+
+	return cursor.Encode()
+}
+
+// DecodeIndexCursor decodes the opaque token of a cursor of the given kind
+// that points to an index. False is returned for an empty token, which refers
+// to the first page.
+func DecodeIndexCursor(kind CursorKind, token string) (uint64, bool, error) {
+	cursor, err := DecodePageCursor(token)
+	if err != nil || cursor == nil {
+		return 0, false, err
+	}
+
+	if cursor.Kind != kind || len(cursor.Position) != 8 {
+		return 0, false, ErrInvalidPageCursor
+	}
+
+	return binary.BigEndian.Uint64(cursor.Position), true, nil
+}
+
+// PageSize returns the number of items to return for the requested page size,
+// which is DefaultPageSize if none was requested and at most MaxPageSize.
+func PageSize(requested uint64) uint64 {
+	switch {
+	case requested == 0:
+		return DefaultPageSize
+
+	case requested > MaxPageSize:
+		return MaxPageSize
+
+	default:
+		return requested
+	}
+}
@@ -29,4 +29,8 @@ type Config struct {
 	// vector should be provided.
 	UpdateNodeAnnouncement func(features *lnwire.RawFeatureVector,
 		mods ...netann.NodeAnnModifier) error
+
+	// PeerPolicies manages the connection policies of peers and the
+	// inbound connection allow and deny lists.
+	PeerPolicies PeerPolicyManager
 }
@@ -0,0 +1,29 @@
+package peersrpc
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// PeerPolicyManager manages the connection policies of peers and the inbound
+// connection allow and deny lists.
+type PeerPolicyManager interface {
+	// PeerPolicies returns the connection policies of all peers.
+	PeerPolicies() map[route.Vertex]*channeldb.PeerPolicy
+
+	// SetPeerPolicy stores the connection policy of a peer and applies
+	// it.
+	SetPeerPolicy(pubKey route.Vertex, policy *channeldb.PeerPolicy) error
+
+	// DeletePeerPolicy removes the connection policy of a peer.
+	DeletePeerPolicy(pubKey route.Vertex) error
+
+	// InboundAccess returns the entries of the inbound connection allow
+	// and deny lists.
+	InboundAccess() map[route.Vertex]channeldb.InboundAccess
+
+	// SetInboundAccess puts a peer on the inbound connection allow or
+	// deny list, or removes it from both.
+	SetInboundAccess(pubKey route.Vertex,
+		access channeldb.InboundAccess) error
+}
@@ -0,0 +1,182 @@
+//go:build peersrpc
+// +build peersrpc
+
+package peersrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// SetPeerPolicy stores the connection policy of a peer. A persistent peer is
+// connected right away, the other settings apply to new connections and
+// channels.
+func (s *Server) SetPeerPolicy(_ context.Context,
+	req *SetPeerPolicyRequest) (*SetPeerPolicyResponse, error) {
+
+	policy := req.Policy
+	if policy == nil {
+		return nil, errors.New("policy must be set")
+	}
+
+	pubKey, err := route.NewVertexFromBytes(policy.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer public key: %w", err)
+	}
+
+	addrs := make([]net.Addr, 0, len(policy.Addresses))
+	for _, addrStr := range policy.Addresses {
+		addr, err := s.cfg.ParseAddr(addrStr)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve address "+
+				"%v: %w", addrStr, err)
+		}
+		addrs = append(addrs, addr)
+	}
+
+	err = s.cfg.PeerPolicies.SetPeerPolicy(pubKey, &channeldb.PeerPolicy{
+		Persistent:   policy.Persistent,
+		TorOnly:      policy.TorOnly,
+		AllowInbound: policy.AllowInbound,
+		MaxChannels:  policy.MaxChannels,
+		Addresses:    addrs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetPeerPolicyResponse{}, nil
+}
+
+// DeletePeerPolicy removes the connection policy of a peer, which is treated
+// like any other peer afterwards.
+func (s *Server) DeletePeerPolicy(_ context.Context,
+	req *DeletePeerPolicyRequest) (*DeletePeerPolicyResponse, error) {
+
+	pubKey, err := route.NewVertexFromBytes(req.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer public key: %w", err)
+	}
+
+	err = s.cfg.PeerPolicies.DeletePeerPolicy(pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DeletePeerPolicyResponse{}, nil
+}
+
+// ListPeerPolicies returns the connection policies of all peers, sorted by
+// their public key.
+func (s *Server) ListPeerPolicies(_ context.Context,
+	_ *ListPeerPoliciesRequest) (*ListPeerPoliciesResponse, error) {
+
+	policies := s.cfg.PeerPolicies.PeerPolicies()
+
+	resp := &ListPeerPoliciesResponse{
+		Policies: make([]*PeerPolicy, 0, len(policies)),
+	}
+	for pubKey, policy := range policies {
+		addrs := make([]string, 0, len(policy.Addresses))
+		for _, addr := range policy.Addresses {
+			addrs = append(addrs, addr.String())
+		}
+
+		resp.Policies = append(resp.Policies, &PeerPolicy{
+			PubKey:       append([]byte(nil), pubKey[:]...),
+			Persistent:   policy.Persistent,
+			TorOnly:      policy.TorOnly,
+			AllowInbound: policy.AllowInbound,
+			MaxChannels:  policy.MaxChannels,
+			Addresses:    addrs,
+		})
+	}
+
+	sort.Slice(resp.Policies, func(i, j int) bool {
+		return bytes.Compare(
+			resp.Policies[i].PubKey, resp.Policies[j].PubKey,
+		) < 0
+	})
+
+	return resp, nil
+}
+
+// unmarshallInboundAccess converts the inbound access of an RPC request.
+func unmarshallInboundAccess(
+	access InboundAccess) (channeldb.InboundAccess, error) {
+
+	switch access {
+	case InboundAccess_ACCESS_NONE:
+		return channeldb.InboundAccessNone, nil
+
+	case InboundAccess_ACCESS_ALLOW:
+		return channeldb.InboundAccessAllow, nil
+
+	case InboundAccess_ACCESS_DENY:
+		return channeldb.InboundAccessDeny, nil
+
+	default:
+		return 0, fmt.Errorf("unknown inbound access %v", access)
+	}
+}
+
+// SetInboundAccess puts a peer on the inbound connection allow or deny list,
+// or removes it from both with ACCESS_NONE. The lists apply to new
+// connections.
+func (s *Server) SetInboundAccess(_ context.Context,
+	req *SetInboundAccessRequest) (*SetInboundAccessResponse, error) {
+
+	pubKey, err := route.NewVertexFromBytes(req.PubKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer public key: %w", err)
+	}
+
+	access, err := unmarshallInboundAccess(req.Access)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.cfg.PeerPolicies.SetInboundAccess(pubKey, access)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetInboundAccessResponse{}, nil
+}
+
+// ListInboundAccess returns the inbound connection allow and deny lists,
+// sorted by public key.
+func (s *Server) ListInboundAccess(_ context.Context,
+	_ *ListInboundAccessRequest) (*ListInboundAccessResponse, error) {
+
+	pubKeys := make([]route.Vertex, 0)
+	access := s.cfg.PeerPolicies.InboundAccess()
+	for pubKey := range access {
+		pubKeys = append(pubKeys, pubKey)
+	}
+	sort.Slice(pubKeys, func(i, j int) bool {
+		return bytes.Compare(pubKeys[i][:], pubKeys[j][:]) < 0
+	})
+
+	resp := &ListInboundAccessResponse{}
+	for _, pubKey := range pubKeys {
+		pubKeyBytes := append([]byte(nil), pubKey[:]...)
+
+		switch access[pubKey] {
+		case channeldb.InboundAccessAllow:
+			resp.Allow = append(resp.Allow, pubKeyBytes)
+
+		case channeldb.InboundAccessDeny:
+			resp.Deny = append(resp.Deny, pubKeyBytes)
+		}
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,129 @@
+//go:build peersrpc
+// +build peersrpc
+
+package peersrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPeerPolicies is an in-memory PeerPolicyManager.
+type mockPeerPolicies struct {
+	policies map[route.Vertex]*channeldb.PeerPolicy
+	access   map[route.Vertex]channeldb.InboundAccess
+}
+
+func (m *mockPeerPolicies) PeerPolicies() map[route.Vertex]*channeldb.
+	PeerPolicy {
+
+	return m.policies
+}
+
+func (m *mockPeerPolicies) SetPeerPolicy(pubKey route.Vertex,
+	policy *channeldb.PeerPolicy) error {
+
+	m.policies[pubKey] = policy
+	return nil
+}
+
+func (m *mockPeerPolicies) DeletePeerPolicy(pubKey route.Vertex) error {
+	delete(m.policies, pubKey)
+	return nil
+}
+
+func (m *mockPeerPolicies) InboundAccess() map[route.Vertex]channeldb.
+	InboundAccess {
+
+	return m.access
+}
+
+func (m *mockPeerPolicies) SetInboundAccess(pubKey route.Vertex,
+	access channeldb.InboundAccess) error {
+
+	if access == channeldb.InboundAccessNone {
+		delete(m.access, pubKey)
+		return nil
+	}
+
+	m.access[pubKey] = access
+	return nil
+}
+
+// TestPeerPolicyRPCs tests that peer policies and the inbound access lists
+// are converted between their RPC and database form.
+func TestPeerPolicyRPCs(t *testing.T) {
+	t.Parallel()
+
+	manager := &mockPeerPolicies{
+		policies: make(map[route.Vertex]*channeldb.PeerPolicy),
+		access:   make(map[route.Vertex]channeldb.InboundAccess),
+	}
+	s := &Server{
+		cfg: &Config{
+			ParseAddr: func(addr string) (net.Addr, error) {
+				return net.ResolveTCPAddr("tcp", addr)
+			},
+			PeerPolicies: manager,
+		},
+	}
+	ctx := context.Background()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	pubKey := priv.PubKey().SerializeCompressed()
+
+	policy := &PeerPolicy{
+		PubKey:       pubKey,
+		Persistent:   true,
+		AllowInbound: true,
+		MaxChannels:  3,
+		Addresses:    []string{"127.0.0.1:9735"},
+	}
+	_, err = s.SetPeerPolicy(ctx, &SetPeerPolicyRequest{Policy: policy})
+	require.NoError(t, err)
+
+	policies, err := s.ListPeerPolicies(ctx, &ListPeerPoliciesRequest{})
+	require.NoError(t, err)
+	require.Len(t, policies.Policies, 1)
+	require.Equal(t, policy.PubKey, policies.Policies[0].PubKey)
+	require.Equal(t, policy.Addresses, policies.Policies[0].Addresses)
+	require.EqualValues(t, 3, policies.Policies[0].MaxChannels)
+	require.True(t, policies.Policies[0].Persistent)
+
+	// An invalid public key is rejected.
+	_, err = s.DeletePeerPolicy(ctx, &DeletePeerPolicyRequest{
+		PubKey: []byte{1},
+	})
+	require.Error(t, err)
+
+	_, err = s.DeletePeerPolicy(ctx, &DeletePeerPolicyRequest{
+		PubKey: pubKey,
+	})
+	require.NoError(t, err)
+	require.Empty(t, manager.policies)
+
+	_, err = s.SetInboundAccess(ctx, &SetInboundAccessRequest{
+		PubKey: pubKey,
+		Access: InboundAccess_ACCESS_DENY,
+	})
+	require.NoError(t, err)
+
+	lists, err := s.ListInboundAccess(ctx, &ListInboundAccessRequest{})
+	require.NoError(t, err)
+	require.Empty(t, lists.Allow)
+	require.Equal(t, [][]byte{pubKey}, lists.Deny)
+
+	// An unknown access is rejected.
+	_, err = s.SetInboundAccess(ctx, &SetInboundAccessRequest{
+		PubKey: pubKey,
+		Access: InboundAccess(5),
+	})
+	require.Error(t, err)
+}
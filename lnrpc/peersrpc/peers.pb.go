@@ -136,6 +136,59 @@ func (FeatureSet) EnumDescriptor() ([]byte, []int) {
 	return file_peersrpc_peers_proto_rawDescGZIP(), []int{1}
 }
 
+type InboundAccess int32
+
+const (
+	// ACCESS_NONE means the peer is on neither list.
+	InboundAccess_ACCESS_NONE InboundAccess = 0
+	// ACCESS_ALLOW means the peer is on the allow list. Once the allow list
+	// isn't empty, only the peers on it may connect to us.
+	InboundAccess_ACCESS_ALLOW InboundAccess = 1
+	// ACCESS_DENY means the peer is on the deny list and may never connect.
+	InboundAccess_ACCESS_DENY InboundAccess = 2
+)
+
+// Enum value maps for InboundAccess.
+var (
+	InboundAccess_name = map[int32]string{
+		0: "ACCESS_NONE",
+		1: "ACCESS_ALLOW",
+		2: "ACCESS_DENY",
+	}
+	InboundAccess_value = map[string]int32{
+		"ACCESS_NONE":  0,
+		"ACCESS_ALLOW": 1,
+		"ACCESS_DENY":  2,
+	}
+)
+
+func (x InboundAccess) Enum() *InboundAccess {
+	p := new(InboundAccess)
+	*p = x
+	return p
+}
+
+func (x InboundAccess) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (InboundAccess) Descriptor() protoreflect.EnumDescriptor {
+	return file_peersrpc_peers_proto_enumTypes[2].Descriptor()
+}
+
+func (InboundAccess) Type() protoreflect.EnumType {
+	return &file_peersrpc_peers_proto_enumTypes[2]
+}
+
+func (x InboundAccess) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use InboundAccess.Descriptor instead.
+func (InboundAccess) EnumDescriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{2}
+}
+
 type UpdateAddressAction struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -372,6 +425,548 @@ func (x *NodeAnnouncementUpdateResponse) GetOps() []*lnrpc.Op {
 	return nil
 }
 
+type PeerPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The compressed public key of the peer.
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	// Keep a connection to the peer, even if we have no channel with it.
+	Persistent bool `protobuf:"varint,2,opt,name=persistent,proto3" json:"persistent,omitempty"`
+	// Only allow connections to and from the peer over Tor.
+	TorOnly bool `protobuf:"varint,3,opt,name=tor_only,json=torOnly,proto3" json:"tor_only,omitempty"`
+	// Allow the peer to connect to us.
+	AllowInbound bool `protobuf:"varint,4,opt,name=allow_inbound,json=allowInbound,proto3" json:"allow_inbound,omitempty"`
+	// The maximum number of channels the peer may open with us, including
+	// pending ones. Zero means no limit.
+	MaxChannels uint32 `protobuf:"varint,5,opt,name=max_channels,json=maxChannels,proto3" json:"max_channels,omitempty"`
+	// The host:port addresses we connect to if the peer is persistent.
+	Addresses []string `protobuf:"bytes,6,rep,name=addresses,proto3" json:"addresses,omitempty"`
+}
+
+func (x *PeerPolicy) Reset() {
+	*x = PeerPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PeerPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeerPolicy) ProtoMessage() {}
+
+func (x *PeerPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeerPolicy.ProtoReflect.Descriptor instead.
+func (*PeerPolicy) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PeerPolicy) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *PeerPolicy) GetPersistent() bool {
+	if x != nil {
+		return x.Persistent
+	}
+	return false
+}
+
+func (x *PeerPolicy) GetTorOnly() bool {
+	if x != nil {
+		return x.TorOnly
+	}
+	return false
+}
+
+func (x *PeerPolicy) GetAllowInbound() bool {
+	if x != nil {
+		return x.AllowInbound
+	}
+	return false
+}
+
+func (x *PeerPolicy) GetMaxChannels() uint32 {
+	if x != nil {
+		return x.MaxChannels
+	}
+	return 0
+}
+
+func (x *PeerPolicy) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type SetPeerPolicyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The connection policy to store.
+	Policy *PeerPolicy `protobuf:"bytes,1,opt,name=policy,proto3" json:"policy,omitempty"`
+}
+
+func (x *SetPeerPolicyRequest) Reset() {
+	*x = SetPeerPolicyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetPeerPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPeerPolicyRequest) ProtoMessage() {}
+
+func (x *SetPeerPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPeerPolicyRequest.ProtoReflect.Descriptor instead.
+func (*SetPeerPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetPeerPolicyRequest) GetPolicy() *PeerPolicy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type SetPeerPolicyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SetPeerPolicyResponse) Reset() {
+	*x = SetPeerPolicyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetPeerPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetPeerPolicyResponse) ProtoMessage() {}
+
+func (x *SetPeerPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetPeerPolicyResponse.ProtoReflect.Descriptor instead.
+func (*SetPeerPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{6}
+}
+
+type DeletePeerPolicyRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The compressed public key of the peer.
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+}
+
+func (x *DeletePeerPolicyRequest) Reset() {
+	*x = DeletePeerPolicyRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePeerPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePeerPolicyRequest) ProtoMessage() {}
+
+func (x *DeletePeerPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePeerPolicyRequest.ProtoReflect.Descriptor instead.
+func (*DeletePeerPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeletePeerPolicyRequest) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+type DeletePeerPolicyResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeletePeerPolicyResponse) Reset() {
+	*x = DeletePeerPolicyResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeletePeerPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletePeerPolicyResponse) ProtoMessage() {}
+
+func (x *DeletePeerPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletePeerPolicyResponse.ProtoReflect.Descriptor instead.
+func (*DeletePeerPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{8}
+}
+
+type ListPeerPoliciesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListPeerPoliciesRequest) Reset() {
+	*x = ListPeerPoliciesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPeerPoliciesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPeerPoliciesRequest) ProtoMessage() {}
+
+func (x *ListPeerPoliciesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPeerPoliciesRequest.ProtoReflect.Descriptor instead.
+func (*ListPeerPoliciesRequest) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{9}
+}
+
+type ListPeerPoliciesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The connection policies of all peers, sorted by their public key.
+	Policies []*PeerPolicy `protobuf:"bytes,1,rep,name=policies,proto3" json:"policies,omitempty"`
+}
+
+func (x *ListPeerPoliciesResponse) Reset() {
+	*x = ListPeerPoliciesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListPeerPoliciesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPeerPoliciesResponse) ProtoMessage() {}
+
+func (x *ListPeerPoliciesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPeerPoliciesResponse.ProtoReflect.Descriptor instead.
+func (*ListPeerPoliciesResponse) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListPeerPoliciesResponse) GetPolicies() []*PeerPolicy {
+	if x != nil {
+		return x.Policies
+	}
+	return nil
+}
+
+type SetInboundAccessRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The compressed public key of the peer.
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	// The list to put the peer on, or ACCESS_NONE to remove it from both.
+	Access InboundAccess `protobuf:"varint,2,opt,name=access,proto3,enum=peersrpc.InboundAccess" json:"access,omitempty"`
+}
+
+func (x *SetInboundAccessRequest) Reset() {
+	*x = SetInboundAccessRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetInboundAccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetInboundAccessRequest) ProtoMessage() {}
+
+func (x *SetInboundAccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetInboundAccessRequest.ProtoReflect.Descriptor instead.
+func (*SetInboundAccessRequest) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetInboundAccessRequest) GetPubKey() []byte {
+	if x != nil {
+		return x.PubKey
+	}
+	return nil
+}
+
+func (x *SetInboundAccessRequest) GetAccess() InboundAccess {
+	if x != nil {
+		return x.Access
+	}
+	return InboundAccess_ACCESS_NONE
+}
+
+type SetInboundAccessResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SetInboundAccessResponse) Reset() {
+	*x = SetInboundAccessResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetInboundAccessResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetInboundAccessResponse) ProtoMessage() {}
+
+func (x *SetInboundAccessResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetInboundAccessResponse.ProtoReflect.Descriptor instead.
+func (*SetInboundAccessResponse) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{12}
+}
+
+type ListInboundAccessRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListInboundAccessRequest) Reset() {
+	*x = ListInboundAccessRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListInboundAccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInboundAccessRequest) ProtoMessage() {}
+
+func (x *ListInboundAccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInboundAccessRequest.ProtoReflect.Descriptor instead.
+func (*ListInboundAccessRequest) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{13}
+}
+
+type ListInboundAccessResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The peers that may connect to us, sorted by public key.
+	Allow [][]byte `protobuf:"bytes,1,rep,name=allow,proto3" json:"allow,omitempty"`
+	// The peers that may never connect to us, sorted by public key.
+	Deny [][]byte `protobuf:"bytes,2,rep,name=deny,proto3" json:"deny,omitempty"`
+}
+
+func (x *ListInboundAccessResponse) Reset() {
+	*x = ListInboundAccessResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_peersrpc_peers_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListInboundAccessResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListInboundAccessResponse) ProtoMessage() {}
+
+func (x *ListInboundAccessResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_peersrpc_peers_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListInboundAccessResponse.ProtoReflect.Descriptor instead.
+func (*ListInboundAccessResponse) Descriptor() ([]byte, []int) {
+	return file_peersrpc_peers_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListInboundAccessResponse) GetAllow() [][]byte {
+	if x != nil {
+		return x.Allow
+	}
+	return nil
+}
+
+func (x *ListInboundAccessResponse) GetDeny() [][]byte {
+	if x != nil {
+		return x.Deny
+	}
+	return nil
+}
+
 var File_peersrpc_peers_proto protoreflect.FileDescriptor
 
 var file_peersrpc_peers_proto_rawDesc = []byte{
@@ -409,27 +1004,104 @@ var file_peersrpc_peers_proto_rawDesc = []byte{
 	0x6f, 0x64, 0x65, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x55,
 	0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1b, 0x0a,
 	0x03, 0x6f, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x09, 0x2e, 0x6c, 0x6e, 0x72,
-	0x70, 0x63, 0x2e, 0x4f, 0x70, 0x52, 0x03, 0x6f, 0x70, 0x73, 0x2a, 0x23, 0x0a, 0x0c, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x44,
-	0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x01, 0x2a,
-	0x69, 0x0a, 0x0a, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x53, 0x65, 0x74, 0x12, 0x0c, 0x0a,
-	0x08, 0x53, 0x45, 0x54, 0x5f, 0x49, 0x4e, 0x49, 0x54, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x53,
-	0x45, 0x54, 0x5f, 0x4c, 0x45, 0x47, 0x41, 0x43, 0x59, 0x5f, 0x47, 0x4c, 0x4f, 0x42, 0x41, 0x4c,
-	0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x54, 0x5f, 0x4e, 0x4f, 0x44, 0x45, 0x5f, 0x41,
-	0x4e, 0x4e, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x45, 0x54, 0x5f, 0x49, 0x4e, 0x56, 0x4f,
-	0x49, 0x43, 0x45, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x45, 0x54, 0x5f, 0x49, 0x4e, 0x56,
-	0x4f, 0x49, 0x43, 0x45, 0x5f, 0x41, 0x4d, 0x50, 0x10, 0x04, 0x32, 0x74, 0x0a, 0x05, 0x50, 0x65,
-	0x65, 0x72, 0x73, 0x12, 0x6b, 0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64,
-	0x65, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x2e,
-	0x70, 0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x41, 0x6e, 0x6e,
-	0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72, 0x70,
-	0x63, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65,
-	0x6e, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x42, 0x30, 0x5a, 0x2e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c,
-	0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f,
-	0x6c, 0x6e, 0x64, 0x2f, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72,
-	0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x70, 0x63, 0x2e, 0x4f, 0x70, 0x52, 0x03, 0x6f, 0x70, 0x73, 0x22, 0xc6, 0x01, 0x0a, 0x0a, 0x50,
+	0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b,
+	0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x70, 0x65, 0x72, 0x73, 0x69, 0x73, 0x74, 0x65,
+	0x6e, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x74, 0x6f, 0x72, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x74, 0x6f, 0x72, 0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x23, 0x0a,
+	0x0d, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x69, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x49, 0x6e, 0x62, 0x6f, 0x75,
+	0x6e, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x65, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x65, 0x73, 0x22, 0x44, 0x0a, 0x14, 0x53, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x06, 0x70,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x65,
+	0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x52, 0x06, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x22, 0x17, 0x0a, 0x15, 0x53, 0x65, 0x74,
+	0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x32, 0x0a, 0x17, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a,
+	0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x22, 0x1a, 0x0a, 0x18, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x22, 0x19, 0x0a, 0x17, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4c, 0x0a,
+	0x18, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x08, 0x70, 0x6f, 0x6c,
+	0x69, 0x63, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x65,
+	0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x22, 0x63, 0x0a, 0x17, 0x53,
+	0x65, 0x74, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12,
+	0x2f, 0x0a, 0x06, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x17, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x6e, 0x62, 0x6f, 0x75,
+	0x6e, 0x64, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x06, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x22, 0x1a, 0x0a, 0x18, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1a, 0x0a, 0x18,
+	0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x63, 0x63, 0x65, 0x73,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x45, 0x0a, 0x19, 0x4c, 0x69, 0x73, 0x74,
+	0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x12, 0x12, 0x0a, 0x04, 0x64,
+	0x65, 0x6e, 0x79, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65, 0x6e, 0x79, 0x2a,
+	0x23, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x07, 0x0a, 0x03, 0x41, 0x44, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4d, 0x4f,
+	0x56, 0x45, 0x10, 0x01, 0x2a, 0x69, 0x0a, 0x0a, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x53,
+	0x65, 0x74, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x45, 0x54, 0x5f, 0x49, 0x4e, 0x49, 0x54, 0x10, 0x00,
+	0x12, 0x15, 0x0a, 0x11, 0x53, 0x45, 0x54, 0x5f, 0x4c, 0x45, 0x47, 0x41, 0x43, 0x59, 0x5f, 0x47,
+	0x4c, 0x4f, 0x42, 0x41, 0x4c, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x54, 0x5f, 0x4e,
+	0x4f, 0x44, 0x45, 0x5f, 0x41, 0x4e, 0x4e, 0x10, 0x02, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x45, 0x54,
+	0x5f, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x10, 0x03, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x45,
+	0x54, 0x5f, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x41, 0x4d, 0x50, 0x10, 0x04, 0x2a,
+	0x43, 0x0a, 0x0d, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x43, 0x43, 0x45, 0x53, 0x53, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10,
+	0x00, 0x12, 0x10, 0x0a, 0x0c, 0x41, 0x43, 0x43, 0x45, 0x53, 0x53, 0x5f, 0x41, 0x4c, 0x4c, 0x4f,
+	0x57, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x43, 0x43, 0x45, 0x53, 0x53, 0x5f, 0x44, 0x45,
+	0x4e, 0x59, 0x10, 0x02, 0x32, 0xb5, 0x04, 0x0a, 0x05, 0x50, 0x65, 0x65, 0x72, 0x73, 0x12, 0x6b,
+	0x0a, 0x16, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x41, 0x6e, 0x6e, 0x6f,
+	0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73,
+	0x72, 0x70, 0x63, 0x2e, 0x4e, 0x6f, 0x64, 0x65, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x28, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x4e, 0x6f, 0x64,
+	0x65, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x6d, 0x65, 0x6e, 0x74, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x50, 0x0a, 0x0d, 0x53,
+	0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x1e, 0x2e, 0x70,
+	0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70,
+	0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x50, 0x65, 0x65, 0x72, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a,
+	0x10, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x12, 0x21, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x10, 0x4c, 0x69, 0x73, 0x74,
+	0x50, 0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x70,
+	0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50, 0x65, 0x65, 0x72,
+	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x22, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x50,
+	0x65, 0x65, 0x72, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x59, 0x0a, 0x10, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e,
+	0x64, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x12, 0x21, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x65, 0x65,
+	0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64,
+	0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c,
+	0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x63, 0x63,
+	0x65, 0x73, 0x73, 0x12, 0x22, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x2e, 0x4c,
+	0x69, 0x73, 0x74, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72,
+	0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x49, 0x6e, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x41, 0x63,
+	0x63, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x30, 0x5a, 0x2e,
+	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74,
+	0x6e, 0x69, 0x6e, 0x67, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6c, 0x6e, 0x64, 0x2f,
+	0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x65, 0x65, 0x72, 0x73, 0x72, 0x70, 0x63, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -444,32 +1116,57 @@ func file_peersrpc_peers_proto_rawDescGZIP() []byte {
 	return file_peersrpc_peers_proto_rawDescData
 }
 
-var file_peersrpc_peers_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_peersrpc_peers_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_peersrpc_peers_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_peersrpc_peers_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_peersrpc_peers_proto_goTypes = []interface{}{
 	(UpdateAction)(0),                      // 0: peersrpc.UpdateAction
 	(FeatureSet)(0),                        // 1: peersrpc.FeatureSet
-	(*UpdateAddressAction)(nil),            // 2: peersrpc.UpdateAddressAction
-	(*UpdateFeatureAction)(nil),            // 3: peersrpc.UpdateFeatureAction
-	(*NodeAnnouncementUpdateRequest)(nil),  // 4: peersrpc.NodeAnnouncementUpdateRequest
-	(*NodeAnnouncementUpdateResponse)(nil), // 5: peersrpc.NodeAnnouncementUpdateResponse
-	(lnrpc.FeatureBit)(0),                  // 6: lnrpc.FeatureBit
-	(*lnrpc.Op)(nil),                       // 7: lnrpc.Op
+	(InboundAccess)(0),                     // 2: peersrpc.InboundAccess
+	(*UpdateAddressAction)(nil),            // 3: peersrpc.UpdateAddressAction
+	(*UpdateFeatureAction)(nil),            // 4: peersrpc.UpdateFeatureAction
+	(*NodeAnnouncementUpdateRequest)(nil),  // 5: peersrpc.NodeAnnouncementUpdateRequest
+	(*NodeAnnouncementUpdateResponse)(nil), // 6: peersrpc.NodeAnnouncementUpdateResponse
+	(*PeerPolicy)(nil),                     // 7: peersrpc.PeerPolicy
+	(*SetPeerPolicyRequest)(nil),           // 8: peersrpc.SetPeerPolicyRequest
+	(*SetPeerPolicyResponse)(nil),          // 9: peersrpc.SetPeerPolicyResponse
+	(*DeletePeerPolicyRequest)(nil),        // 10: peersrpc.DeletePeerPolicyRequest
+	(*DeletePeerPolicyResponse)(nil),       // 11: peersrpc.DeletePeerPolicyResponse
+	(*ListPeerPoliciesRequest)(nil),        // 12: peersrpc.ListPeerPoliciesRequest
+	(*ListPeerPoliciesResponse)(nil),       // 13: peersrpc.ListPeerPoliciesResponse
+	(*SetInboundAccessRequest)(nil),        // 14: peersrpc.SetInboundAccessRequest
+	(*SetInboundAccessResponse)(nil),       // 15: peersrpc.SetInboundAccessResponse
+	(*ListInboundAccessRequest)(nil),       // 16: peersrpc.ListInboundAccessRequest
+	(*ListInboundAccessResponse)(nil),      // 17: peersrpc.ListInboundAccessResponse
+	(lnrpc.FeatureBit)(0),                  // 18: lnrpc.FeatureBit
+	(*lnrpc.Op)(nil),                       // 19: lnrpc.Op
 }
 var file_peersrpc_peers_proto_depIdxs = []int32{
-	0, // 0: peersrpc.UpdateAddressAction.action:type_name -> peersrpc.UpdateAction
-	0, // 1: peersrpc.UpdateFeatureAction.action:type_name -> peersrpc.UpdateAction
-	6, // 2: peersrpc.UpdateFeatureAction.feature_bit:type_name -> lnrpc.FeatureBit
-	3, // 3: peersrpc.NodeAnnouncementUpdateRequest.feature_updates:type_name -> peersrpc.UpdateFeatureAction
-	2, // 4: peersrpc.NodeAnnouncementUpdateRequest.address_updates:type_name -> peersrpc.UpdateAddressAction
-	7, // 5: peersrpc.NodeAnnouncementUpdateResponse.ops:type_name -> lnrpc.Op
-	4, // 6: peersrpc.Peers.UpdateNodeAnnouncement:input_type -> peersrpc.NodeAnnouncementUpdateRequest
-	5, // 7: peersrpc.Peers.UpdateNodeAnnouncement:output_type -> peersrpc.NodeAnnouncementUpdateResponse
-	7, // [7:8] is the sub-list for method output_type
-	6, // [6:7] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	0,  // 0: peersrpc.UpdateAddressAction.action:type_name -> peersrpc.UpdateAction
+	0,  // 1: peersrpc.UpdateFeatureAction.action:type_name -> peersrpc.UpdateAction
+	18, // 2: peersrpc.UpdateFeatureAction.feature_bit:type_name -> lnrpc.FeatureBit
+	4,  // 3: peersrpc.NodeAnnouncementUpdateRequest.feature_updates:type_name -> peersrpc.UpdateFeatureAction
+	3,  // 4: peersrpc.NodeAnnouncementUpdateRequest.address_updates:type_name -> peersrpc.UpdateAddressAction
+	19, // 5: peersrpc.NodeAnnouncementUpdateResponse.ops:type_name -> lnrpc.Op
+	7,  // 6: peersrpc.SetPeerPolicyRequest.policy:type_name -> peersrpc.PeerPolicy
+	7,  // 7: peersrpc.ListPeerPoliciesResponse.policies:type_name -> peersrpc.PeerPolicy
+	2,  // 8: peersrpc.SetInboundAccessRequest.access:type_name -> peersrpc.InboundAccess
+	5,  // 9: peersrpc.Peers.UpdateNodeAnnouncement:input_type -> peersrpc.NodeAnnouncementUpdateRequest
+	8,  // 10: peersrpc.Peers.SetPeerPolicy:input_type -> peersrpc.SetPeerPolicyRequest
+	10, // 11: peersrpc.Peers.DeletePeerPolicy:input_type -> peersrpc.DeletePeerPolicyRequest
+	12, // 12: peersrpc.Peers.ListPeerPolicies:input_type -> peersrpc.ListPeerPoliciesRequest
+	14, // 13: peersrpc.Peers.SetInboundAccess:input_type -> peersrpc.SetInboundAccessRequest
+	16, // 14: peersrpc.Peers.ListInboundAccess:input_type -> peersrpc.ListInboundAccessRequest
+	6,  // 15: peersrpc.Peers.UpdateNodeAnnouncement:output_type -> peersrpc.NodeAnnouncementUpdateResponse
+	9,  // 16: peersrpc.Peers.SetPeerPolicy:output_type -> peersrpc.SetPeerPolicyResponse
+	11, // 17: peersrpc.Peers.DeletePeerPolicy:output_type -> peersrpc.DeletePeerPolicyResponse
+	13, // 18: peersrpc.Peers.ListPeerPolicies:output_type -> peersrpc.ListPeerPoliciesResponse
+	15, // 19: peersrpc.Peers.SetInboundAccess:output_type -> peersrpc.SetInboundAccessResponse
+	17, // 20: peersrpc.Peers.ListInboundAccess:output_type -> peersrpc.ListInboundAccessResponse
+	15, // [15:21] is the sub-list for method output_type
+	9,  // [9:15] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_peersrpc_peers_proto_init() }
@@ -526,14 +1223,146 @@ func file_peersrpc_peers_proto_init() {
 				return nil
 			}
 		}
+		file_peersrpc_peers_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PeerPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetPeerPolicyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetPeerPolicyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeletePeerPolicyRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeletePeerPolicyResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPeerPoliciesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListPeerPoliciesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetInboundAccessRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetInboundAccessResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListInboundAccessRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_peersrpc_peers_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListInboundAccessResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_peersrpc_peers_proto_rawDesc,
-			NumEnums:      2,
-			NumMessages:   4,
+			NumEnums:      3,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -22,6 +22,27 @@ type PeersClient interface {
 	// UpdateNodeAnnouncement allows the caller to update the node parameters
 	// and broadcasts a new version of the node announcement to its peers.
 	UpdateNodeAnnouncement(ctx context.Context, in *NodeAnnouncementUpdateRequest, opts ...grpc.CallOption) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers setpolicy
+	// SetPeerPolicy stores the connection policy of a peer. A persistent peer is
+	// connected right away, the other settings apply to new connections and
+	// channels.
+	SetPeerPolicy(ctx context.Context, in *SetPeerPolicyRequest, opts ...grpc.CallOption) (*SetPeerPolicyResponse, error)
+	// lncli: peers deletepolicy
+	// DeletePeerPolicy removes the connection policy of a peer, which is
+	// treated like any other peer afterwards.
+	DeletePeerPolicy(ctx context.Context, in *DeletePeerPolicyRequest, opts ...grpc.CallOption) (*DeletePeerPolicyResponse, error)
+	// lncli: peers listpolicies
+	// ListPeerPolicies returns the connection policies of all peers, sorted by
+	// their public key.
+	ListPeerPolicies(ctx context.Context, in *ListPeerPoliciesRequest, opts ...grpc.CallOption) (*ListPeerPoliciesResponse, error)
+	// lncli: peers setinboundaccess
+	// SetInboundAccess puts a peer on the inbound connection allow or deny
+	// list, or removes it from both. The lists apply to new connections.
+	SetInboundAccess(ctx context.Context, in *SetInboundAccessRequest, opts ...grpc.CallOption) (*SetInboundAccessResponse, error)
+	// lncli: peers listinboundaccess
+	// ListInboundAccess returns the inbound connection allow and deny lists,
+	// sorted by public key.
+	ListInboundAccess(ctx context.Context, in *ListInboundAccessRequest, opts ...grpc.CallOption) (*ListInboundAccessResponse, error)
 }
 
 type peersClient struct {
@@ -41,6 +62,51 @@ func (c *peersClient) UpdateNodeAnnouncement(ctx context.Context, in *NodeAnnoun
 	return out, nil
 }
 
+func (c *peersClient) SetPeerPolicy(ctx context.Context, in *SetPeerPolicyRequest, opts ...grpc.CallOption) (*SetPeerPolicyResponse, error) {
+	out := new(SetPeerPolicyResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/SetPeerPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) DeletePeerPolicy(ctx context.Context, in *DeletePeerPolicyRequest, opts ...grpc.CallOption) (*DeletePeerPolicyResponse, error) {
+	out := new(DeletePeerPolicyResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/DeletePeerPolicy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) ListPeerPolicies(ctx context.Context, in *ListPeerPoliciesRequest, opts ...grpc.CallOption) (*ListPeerPoliciesResponse, error) {
+	out := new(ListPeerPoliciesResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/ListPeerPolicies", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) SetInboundAccess(ctx context.Context, in *SetInboundAccessRequest, opts ...grpc.CallOption) (*SetInboundAccessResponse, error) {
+	out := new(SetInboundAccessResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/SetInboundAccess", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *peersClient) ListInboundAccess(ctx context.Context, in *ListInboundAccessRequest, opts ...grpc.CallOption) (*ListInboundAccessResponse, error) {
+	out := new(ListInboundAccessResponse)
+	err := c.cc.Invoke(ctx, "/peersrpc.Peers/ListInboundAccess", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PeersServer is the server API for Peers service.
 // All implementations must embed UnimplementedPeersServer
 // for forward compatibility
@@ -49,6 +115,27 @@ type PeersServer interface {
 	// UpdateNodeAnnouncement allows the caller to update the node parameters
 	// and broadcasts a new version of the node announcement to its peers.
 	UpdateNodeAnnouncement(context.Context, *NodeAnnouncementUpdateRequest) (*NodeAnnouncementUpdateResponse, error)
+	// lncli: peers setpolicy
+	// SetPeerPolicy stores the connection policy of a peer. A persistent peer is
+	// connected right away, the other settings apply to new connections and
+	// channels.
+	SetPeerPolicy(context.Context, *SetPeerPolicyRequest) (*SetPeerPolicyResponse, error)
+	// lncli: peers deletepolicy
+	// DeletePeerPolicy removes the connection policy of a peer, which is
+	// treated like any other peer afterwards.
+	DeletePeerPolicy(context.Context, *DeletePeerPolicyRequest) (*DeletePeerPolicyResponse, error)
+	// lncli: peers listpolicies
+	// ListPeerPolicies returns the connection policies of all peers, sorted by
+	// their public key.
+	ListPeerPolicies(context.Context, *ListPeerPoliciesRequest) (*ListPeerPoliciesResponse, error)
+	// lncli: peers setinboundaccess
+	// SetInboundAccess puts a peer on the inbound connection allow or deny
+	// list, or removes it from both. The lists apply to new connections.
+	SetInboundAccess(context.Context, *SetInboundAccessRequest) (*SetInboundAccessResponse, error)
+	// lncli: peers listinboundaccess
+	// ListInboundAccess returns the inbound connection allow and deny lists,
+	// sorted by public key.
+	ListInboundAccess(context.Context, *ListInboundAccessRequest) (*ListInboundAccessResponse, error)
 	mustEmbedUnimplementedPeersServer()
 }
 
@@ -59,6 +146,21 @@ type UnimplementedPeersServer struct {
 func (UnimplementedPeersServer) UpdateNodeAnnouncement(context.Context, *NodeAnnouncementUpdateRequest) (*NodeAnnouncementUpdateResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateNodeAnnouncement not implemented")
 }
+func (UnimplementedPeersServer) SetPeerPolicy(context.Context, *SetPeerPolicyRequest) (*SetPeerPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetPeerPolicy not implemented")
+}
+func (UnimplementedPeersServer) DeletePeerPolicy(context.Context, *DeletePeerPolicyRequest) (*DeletePeerPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeletePeerPolicy not implemented")
+}
+func (UnimplementedPeersServer) ListPeerPolicies(context.Context, *ListPeerPoliciesRequest) (*ListPeerPoliciesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPeerPolicies not implemented")
+}
+func (UnimplementedPeersServer) SetInboundAccess(context.Context, *SetInboundAccessRequest) (*SetInboundAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetInboundAccess not implemented")
+}
+func (UnimplementedPeersServer) ListInboundAccess(context.Context, *ListInboundAccessRequest) (*ListInboundAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInboundAccess not implemented")
+}
 func (UnimplementedPeersServer) mustEmbedUnimplementedPeersServer() {}
 
 // UnsafePeersServer may be embedded to opt out of forward compatibility for this service.
@@ -90,6 +192,96 @@ func _Peers_UpdateNodeAnnouncement_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Peers_SetPeerPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPeerPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).SetPeerPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/SetPeerPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).SetPeerPolicy(ctx, req.(*SetPeerPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_DeletePeerPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePeerPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).DeletePeerPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/DeletePeerPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).DeletePeerPolicy(ctx, req.(*DeletePeerPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_ListPeerPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeerPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).ListPeerPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/ListPeerPolicies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).ListPeerPolicies(ctx, req.(*ListPeerPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_SetInboundAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetInboundAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).SetInboundAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/SetInboundAccess",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).SetInboundAccess(ctx, req.(*SetInboundAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Peers_ListInboundAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInboundAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PeersServer).ListInboundAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/peersrpc.Peers/ListInboundAccess",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PeersServer).ListInboundAccess(ctx, req.(*ListInboundAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Peers_ServiceDesc is the grpc.ServiceDesc for Peers service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -101,6 +293,26 @@ var Peers_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateNodeAnnouncement",
 			Handler:    _Peers_UpdateNodeAnnouncement_Handler,
 		},
+		{
+			MethodName: "SetPeerPolicy",
+			Handler:    _Peers_SetPeerPolicy_Handler,
+		},
+		{
+			MethodName: "DeletePeerPolicy",
+			Handler:    _Peers_DeletePeerPolicy_Handler,
+		},
+		{
+			MethodName: "ListPeerPolicies",
+			Handler:    _Peers_ListPeerPolicies_Handler,
+		},
+		{
+			MethodName: "SetInboundAccess",
+			Handler:    _Peers_SetInboundAccess_Handler,
+		},
+		{
+			MethodName: "ListInboundAccess",
+			Handler:    _Peers_ListInboundAccess_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "peersrpc/peers.proto",
@@ -34,6 +34,26 @@ var (
 			Entity: "peers",
 			Action: "write",
 		}},
+		"/peersrpc.Peers/SetPeerPolicy": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/peersrpc.Peers/DeletePeerPolicy": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/peersrpc.Peers/ListPeerPolicies": {{
+			Entity: "peers",
+			Action: "read",
+		}},
+		"/peersrpc.Peers/SetInboundAccess": {{
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/peersrpc.Peers/ListInboundAccess": {{
+			Entity: "peers",
+			Action: "read",
+		}},
 	}
 )
 
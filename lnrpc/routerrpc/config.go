@@ -23,6 +23,14 @@ type Config struct {
 	// deployed to the network(v0.20.0).
 	UseStatusInitiated bool `long:"usestatusinitiated" description:"If true, the router will send Payment_INITIATED for new payments, otherwise Payment_In_FLIGHT will be sent for compatibility concerns."`
 
+	// InterceptorQueueSize is the maximum number of intercepted htlcs that
+	// are queued for delivery to the htlc interceptor client.
+	InterceptorQueueSize int `long:"interceptorqueuesize" description:"The maximum number of intercepted htlcs that are queued for delivery to the htlc interceptor client. Htlcs that don't fit into the queue stay held and are replayed when the client reconnects."`
+
+	// MaxParallelBatchPayments is the maximum number of payments of a
+	// batch that are in flight at the same time.
+	MaxParallelBatchPayments int `long:"maxparallelbatchpayments" description:"The maximum number of payments of a batch submitted through SendPayments that are in flight at the same time."`
+
 	// RouterMacPath is the path for the router macaroon. If unspecified
 	// then we assume that the macaroon will be found under the network
 	// directory, named DefaultRouterMacFilename.
@@ -78,7 +86,9 @@ func DefaultConfig() *Config {
 	}
 
 	return &Config{
-		RoutingConfig: defaultRoutingConfig,
+		RoutingConfig:            defaultRoutingConfig,
+		InterceptorQueueSize:     DefaultInterceptorQueueSize,
+		MaxParallelBatchPayments: DefaultMaxParallelBatchPayments,
 	}
 }
 
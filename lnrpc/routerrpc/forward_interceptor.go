@@ -13,6 +13,17 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// DefaultInterceptorQueueSize is the default maximum number of
+	// intercepted htlcs that are queued for delivery to the interceptor
+	// client.
+	DefaultInterceptorQueueSize = 10000
+
+	// maxResolutionBatchSize is the maximum number of client resolutions
+	// that are passed to the switch in a single batch.
+	maxResolutionBatchSize = 500
+)
+
 var (
 	// ErrFwdNotExists is an error returned when the caller tries to resolve
 	// a forward that doesn't exist anymore.
@@ -21,55 +32,284 @@ var (
 	// ErrMissingPreimage is an error returned when the caller tries to settle
 	// a forward and doesn't provide a preimage.
 	ErrMissingPreimage = errors.New("missing preimage")
+
+	// ErrInterceptorQueueFull is returned to the switch when an
+	// intercepted htlc can't be queued because the client doesn't keep up.
+	// The htlc stays held and is replayed when the client reconnects.
+	ErrInterceptorQueueFull = errors.New("interceptor queue full")
+
+	// errInterceptorStopped is returned to the switch when an htlc is
+	// intercepted after the interceptor stopped.
+	errInterceptorStopped = errors.New("interceptor stopped")
 )
 
+// interceptorStream is the client stream of an htlc interceptor RPC.
+type interceptorStream interface {
+	// sendHtlc sends an intercepted htlc to the client.
+	sendHtlc(req *ForwardHtlcInterceptRequest) error
+
+	// sendAck sends the acknowledgement of a resolution to the client.
+	sendAck(ack *ForwardHtlcInterceptAck) error
+
+	// recvResolutions receives the next resolutions from the client.
+	recvResolutions() ([]*ForwardHtlcInterceptResponse, error)
+}
+
+// singleInterceptorStream is the stream of the HtlcInterceptor RPC, which
+// carries a single resolution per message and doesn't support
+// acknowledgements.
+type singleInterceptorStream struct {
+	stream Router_HtlcInterceptorServer
+}
+
+// sendHtlc sends an intercepted htlc to the client.
+func (s *singleInterceptorStream) sendHtlc(
+	req *ForwardHtlcInterceptRequest) error {
+
+	return s.stream.Send(req)
+}
+
+// sendAck returns an error, since acknowledgements aren't supported by the
+// stream.
+func (s *singleInterceptorStream) sendAck(_ *ForwardHtlcInterceptAck) error {
+	return errors.New("acknowledgements not supported")
+}
+
+// recvResolutions receives the next resolution from the client.
+func (s *singleInterceptorStream) recvResolutions() (
+	[]*ForwardHtlcInterceptResponse, error) {
+
+	resp, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	return []*ForwardHtlcInterceptResponse{resp}, nil
+}
+
+// batchedInterceptorStream is the stream of the HtlcInterceptorBatched RPC,
+// which carries many resolutions per message and supports acknowledgements.
+type batchedInterceptorStream struct {
+	stream Router_HtlcInterceptorBatchedServer
+}
+
+// sendHtlc sends an intercepted htlc to the client.
+func (s *batchedInterceptorStream) sendHtlc(
+	req *ForwardHtlcInterceptRequest) error {
+
+	return s.stream.Send(&HtlcInterceptorUpdate{
+		Update: &HtlcInterceptorUpdate_Htlc{
+			Htlc: req,
+		},
+	})
+}
+
+// sendAck sends the acknowledgement of a resolution to the client.
+func (s *batchedInterceptorStream) sendAck(
+	ack *ForwardHtlcInterceptAck) error {
+
+	return s.stream.Send(&HtlcInterceptorUpdate{
+		Update: &HtlcInterceptorUpdate_Ack{
+			Ack: ack,
+		},
+	})
+}
+
+// recvResolutions receives the next batch of resolutions from the client.
+func (s *batchedInterceptorStream) recvResolutions() (
+	[]*ForwardHtlcInterceptResponse, error) {
+
+	batch, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	return batch.Resolutions, nil
+}
+
 // forwardInterceptor is a helper struct that handles the lifecycle of an RPC
 // interceptor streaming session.
 // It is created when the stream opens and disconnects when the stream closes.
 type forwardInterceptor struct {
 	// stream is the bidirectional RPC stream
-	stream Router_HtlcInterceptorServer
+	stream interceptorStream
+
+	// acksEnabled indicates whether resolutions that set request_ack are
+	// acknowledged.
+	acksEnabled bool
 
 	htlcSwitch htlcswitch.InterceptableHtlcForwarder
+
+	// requests queues the intercepted htlcs until they are sent to the
+	// client, so that a slow client doesn't block the switch.
+	requests chan *ForwardHtlcInterceptRequest
+
+	// acks passes the acknowledgements of resolutions to the send loop.
+	acks chan *ForwardHtlcInterceptAck
+
+	// responses passes the resolutions received from the client to the
+	// main loop.
+	responses chan *ForwardHtlcInterceptResponse
+
+	// errChan receives the first error of the send and receive loops.
+	errChan chan error
+
+	quit chan struct{}
 }
 
-// newForwardInterceptor creates a new forwardInterceptor.
+// newForwardInterceptor creates a new forwardInterceptor for the
+// HtlcInterceptor RPC. Up to queueSize intercepted htlcs are queued for
+// delivery to the client.
 func newForwardInterceptor(htlcSwitch htlcswitch.InterceptableHtlcForwarder,
-	stream Router_HtlcInterceptorServer) *forwardInterceptor {
+	stream Router_HtlcInterceptorServer,
+	queueSize int) *forwardInterceptor {
+
+	return newInterceptor(
+		htlcSwitch, &singleInterceptorStream{stream: stream}, false,
+		queueSize,
+	)
+}
+
+// newBatchedForwardInterceptor creates a new forwardInterceptor for the
+// HtlcInterceptorBatched RPC. Up to queueSize intercepted htlcs are queued for
+// delivery to the client.
+func newBatchedForwardInterceptor(
+	htlcSwitch htlcswitch.InterceptableHtlcForwarder,
+	stream Router_HtlcInterceptorBatchedServer,
+	queueSize int) *forwardInterceptor {
+
+	return newInterceptor(
+		htlcSwitch, &batchedInterceptorStream{stream: stream}, true,
+		queueSize,
+	)
+}
+
+// newInterceptor creates a new forwardInterceptor on the given stream.
+func newInterceptor(htlcSwitch htlcswitch.InterceptableHtlcForwarder,
+	stream interceptorStream, acksEnabled bool,
+	queueSize int) *forwardInterceptor {
+
+	if queueSize <= 0 {
+		queueSize = DefaultInterceptorQueueSize
+	}
 
 	return &forwardInterceptor{
-		htlcSwitch: htlcSwitch,
-		stream:     stream,
+		htlcSwitch:  htlcSwitch,
+		stream:      stream,
+		acksEnabled: acksEnabled,
+		requests:    make(chan *ForwardHtlcInterceptRequest, queueSize),
+		acks: make(
+			chan *ForwardHtlcInterceptAck, maxResolutionBatchSize,
+		),
+		responses: make(
+			chan *ForwardHtlcInterceptResponse,
+			maxResolutionBatchSize,
+		),
+		errChan: make(chan error, 2),
+		quit:    make(chan struct{}),
 	}
 }
 
 // run sends the intercepted packets to the client and receives the
 // corresponding responses. On one hand it registered itself as an interceptor
-// that receives the switch packets and on the other hand launches a go routine
-// to read from the client stream.
-// To coordinate all this and make sure it is safe for concurrent access all
-// packets are sent to the main where they are handled.
+// that receives the switch packets and on the other hand launches go routines
+// to write to and read from the client stream.
+// The resolutions received from the client are collected into batches, so
+// that a busy client only requires a single round trip to the switch for many
+// resolutions.
 func (r *forwardInterceptor) run() error {
+	// The send and receive loops may be blocked on the stream, which is
+	// only closed once the handler returns. We therefore don't wait for
+	// them on exit, they return on their own once the stream is closed.
+	go r.sendLoop()
+	go r.recvLoop()
+
+	defer close(r.quit)
+
 	// Register our interceptor so we receive all forwarded packets.
 	r.htlcSwitch.SetInterceptor(r.onIntercept)
 	defer r.htlcSwitch.SetInterceptor(nil)
 
 	for {
-		resp, err := r.stream.Recv()
-		if err != nil {
+		select {
+		case resp := <-r.responses:
+			batch := []*ForwardHtlcInterceptResponse{resp}
+
+			// Add any other resolutions that are ready to the
+			// batch.
+		drain:
+			for len(batch) < maxResolutionBatchSize {
+				select {
+				case resp := <-r.responses:
+					batch = append(batch, resp)
+
+				default:
+					break drain
+				}
+			}
+
+			if err := r.resolveBatchFromClient(batch); err != nil {
+				return err
+			}
+
+		case err := <-r.errChan:
 			return err
 		}
+	}
+}
 
-		if err := r.resolveFromClient(resp); err != nil {
-			return err
+// sendLoop delivers the queued intercepted htlcs and the acknowledgements of
+// resolutions to the client.
+//
+// NOTE: This MUST be run as a goroutine.
+func (r *forwardInterceptor) sendLoop() {
+	for {
+		var err error
+		select {
+		case req := <-r.requests:
+			err = r.stream.sendHtlc(req)
+
+		case ack := <-r.acks:
+			err = r.stream.sendAck(ack)
+
+		case <-r.quit:
+			return
+		}
+
+		if err != nil {
+			r.errChan <- err
+			return
+		}
+	}
+}
+
+// recvLoop reads the resolutions from the client and passes them to the main
+// loop.
+//
+// NOTE: This MUST be run as a goroutine.
+func (r *forwardInterceptor) recvLoop() {
+	for {
+		resps, err := r.stream.recvResolutions()
+		if err != nil {
+			r.errChan <- err
+			return
+		}
+
+		for _, resp := range resps {
+			select {
+			case r.responses <- resp:
+
+			case <-r.quit:
+				return
+			}
 		}
 	}
 }
 
 // onIntercept is the function that is called by the switch for every forwarded
-// packet. Our interceptor makes sure we hold the packet and then signal to the
-// main loop to handle the packet. We only return true if we were able
-// to deliver the packet to the main loop.
+// packet. The packet is held by the switch and queued for delivery to the
+// client. The call never blocks the switch: if the queue is full, an error is
+// returned and the packet is replayed once the client reconnects.
 func (r *forwardInterceptor) onIntercept(
 	htlc htlcswitch.InterceptedPacket) error {
 
@@ -95,19 +335,110 @@ func (r *forwardInterceptor) onIntercept(
 		InWireCustomRecords:     htlc.InWireCustomRecords,
 	}
 
-	return r.stream.Send(interceptionRequest)
+	select {
+	case r.requests <- interceptionRequest:
+		return nil
+
+	case <-r.quit:
+		return errInterceptorStopped
+
+	default:
+		log.Warnf("Interceptor queue full, not sending packet %v to "+
+			"client", inKey)
+
+		return ErrInterceptorQueueFull
+	}
+}
+
+// resolveBatchFromClient handles a batch of resolutions that arrived from the
+// client. All resolutions of the batch are passed to the switch, even if some
+// of them fail. Resolutions that requested an acknowledgement are acknowledged
+// with their outcome if acknowledgements are enabled. The first error of the
+// other resolutions is returned.
+func (r *forwardInterceptor) resolveBatchFromClient(
+	batch []*ForwardHtlcInterceptResponse) error {
+
+	// results holds the outcome of every resolution of the batch.
+	results := make([]error, len(batch))
+
+	var (
+		resolutions = make([]*htlcswitch.FwdResolution, 0, len(batch))
+		indices     = make([]int, 0, len(batch))
+	)
+	for i, in := range batch {
+		res, err := parseResolution(in)
+		if err != nil {
+			results[i] = err
+			continue
+		}
+
+		resolutions = append(resolutions, res)
+		indices = append(indices, i)
+	}
+
+	if len(resolutions) > 0 {
+		log.Tracef("Resolving batch of %v intercepted packets",
+			len(resolutions))
+
+		errs, err := r.htlcSwitch.ResolveBatch(resolutions)
+		if err != nil {
+			return err
+		}
+
+		for i, err := range errs {
+			results[indices[i]] = err
+		}
+	}
+
+	var firstErr error
+	for i, in := range batch {
+		if r.acksEnabled && in.RequestAck {
+			err := r.sendAck(in.IncomingCircuitKey, results[i])
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if results[i] != nil && firstErr == nil {
+			firstErr = results[i]
+		}
+	}
+
+	return firstErr
+}
+
+// sendAck passes the acknowledgement of a resolution with the given outcome to
+// the send loop.
+func (r *forwardInterceptor) sendAck(key *CircuitKey, result error) error {
+	ack := &ForwardHtlcInterceptAck{
+		IncomingCircuitKey: key,
+	}
+	if result != nil {
+		ack.Error = result.Error()
+	}
+
+	select {
+	case r.acks <- ack:
+		return nil
+
+	case err := <-r.errChan:
+		return err
+	}
 }
 
-// resolveFromClient handles a resolution arrived from the client.
-func (r *forwardInterceptor) resolveFromClient(
-	in *ForwardHtlcInterceptResponse) error {
+// parseResolution converts a resolution that arrived from the client into a
+// resolution for the switch.
+func parseResolution(
+	in *ForwardHtlcInterceptResponse) (*htlcswitch.FwdResolution, error) {
 
 	if in.IncomingCircuitKey == nil {
-		return status.Errorf(codes.InvalidArgument,
+		return nil, status.Errorf(codes.InvalidArgument,
 			"CircuitKey missing from ForwardHtlcInterceptResponse")
 	}
 
-	log.Tracef("Resolving intercepted packet %v", in)
+	log.Tracef("Parsing resolution of intercepted packet %v", in)
 
 	circuitKey := models.CircuitKey{
 		ChanID: lnwire.NewShortChanIDFromInt(
@@ -118,10 +449,10 @@ func (r *forwardInterceptor) resolveFromClient(
 
 	switch in.Action {
 	case ResolveHoldForwardAction_RESUME:
-		return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
+		return &htlcswitch.FwdResolution{
 			Key:    circuitKey,
 			Action: htlcswitch.FwdActionResume,
-		})
+		}, nil
 
 	case ResolveHoldForwardAction_RESUME_MODIFIED:
 		// Modify HTLC and resume forward.
@@ -144,7 +475,7 @@ func (r *forwardInterceptor) resolveFromClient(
 			// Validate custom records.
 			cr := lnwire.CustomRecords(in.OutWireCustomRecords)
 			if err := cr.Validate(); err != nil {
-				return status.Errorf(
+				return nil, status.Errorf(
 					codes.InvalidArgument,
 					"failed to validate custom records: %v",
 					err,
@@ -155,19 +486,19 @@ func (r *forwardInterceptor) resolveFromClient(
 		}
 
 		//nolint:lll
-		return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
+		return &htlcswitch.FwdResolution{
 			Key:                  circuitKey,
 			Action:               htlcswitch.FwdActionResumeModified,
 			InAmountMsat:         inAmtMsat,
 			OutAmountMsat:        outAmtMsat,
 			OutWireCustomRecords: outWireCustomRecords,
-		})
+		}, nil
 
 	case ResolveHoldForwardAction_FAIL:
 		// Fail with an encrypted reason.
 		if in.FailureMessage != nil {
 			if in.FailureCode != 0 {
-				return status.Errorf(
+				return nil, status.Errorf(
 					codes.InvalidArgument,
 					"failure message and failure code "+
 						"are mutually exclusive",
@@ -180,17 +511,17 @@ func (r *forwardInterceptor) resolveFromClient(
 			if len(in.FailureMessage) !=
 				lnwire.FailureMessageLength+32+2+2 {
 
-				return status.Errorf(
+				return nil, status.Errorf(
 					codes.InvalidArgument,
 					"failure message length invalid",
 				)
 			}
 
-			return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
+			return &htlcswitch.FwdResolution{
 				Key:            circuitKey,
 				Action:         htlcswitch.FwdActionFail,
 				FailureMessage: in.FailureMessage,
-			})
+			}, nil
 		}
 
 		var code lnwire.FailCode
@@ -209,35 +540,35 @@ func (r *forwardInterceptor) resolveFromClient(
 			code = lnwire.CodeTemporaryChannelFailure
 
 		default:
-			return status.Errorf(
+			return nil, status.Errorf(
 				codes.InvalidArgument,
 				"unsupported failure code: %v", in.FailureCode,
 			)
 		}
 
-		return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
+		return &htlcswitch.FwdResolution{
 			Key:         circuitKey,
 			Action:      htlcswitch.FwdActionFail,
 			FailureCode: code,
-		})
+		}, nil
 
 	case ResolveHoldForwardAction_SETTLE:
 		if in.Preimage == nil {
-			return ErrMissingPreimage
+			return nil, ErrMissingPreimage
 		}
 		preimage, err := lntypes.MakePreimage(in.Preimage)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
-		return r.htlcSwitch.Resolve(&htlcswitch.FwdResolution{
+		return &htlcswitch.FwdResolution{
 			Key:      circuitKey,
 			Action:   htlcswitch.FwdActionSettle,
 			Preimage: preimage,
-		})
+		}, nil
 
 	default:
-		return status.Errorf(
+		return nil, status.Errorf(
 			codes.InvalidArgument,
 			"unrecognized resolve action %v", in.Action,
 		)
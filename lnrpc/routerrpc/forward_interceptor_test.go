@@ -0,0 +1,304 @@
+package routerrpc
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// interceptorStreamMock is a mock of the htlc interceptor stream.
+type interceptorStreamMock struct {
+	grpc.ServerStream
+
+	sent chan *ForwardHtlcInterceptRequest
+	recv chan *ForwardHtlcInterceptResponse
+	quit chan struct{}
+}
+
+func newInterceptorStreamMock() *interceptorStreamMock {
+	return &interceptorStreamMock{
+		sent: make(chan *ForwardHtlcInterceptRequest),
+		recv: make(chan *ForwardHtlcInterceptResponse),
+		quit: make(chan struct{}),
+	}
+}
+
+func (m *interceptorStreamMock) Send(req *ForwardHtlcInterceptRequest) error {
+	select {
+	case m.sent <- req:
+		return nil
+
+	case <-m.quit:
+		return io.EOF
+	}
+}
+
+func (m *interceptorStreamMock) Recv() (*ForwardHtlcInterceptResponse,
+	error) {
+
+	select {
+	case resp := <-m.recv:
+		return resp, nil
+
+	case <-m.quit:
+		return nil, io.EOF
+	}
+}
+
+// batchedInterceptorStreamMock is a mock of the batched htlc interceptor
+// stream.
+type batchedInterceptorStreamMock struct {
+	grpc.ServerStream
+
+	sent chan *HtlcInterceptorUpdate
+	recv chan *ForwardHtlcInterceptBatch
+	quit chan struct{}
+}
+
+func newBatchedInterceptorStreamMock() *batchedInterceptorStreamMock {
+	return &batchedInterceptorStreamMock{
+		sent: make(chan *HtlcInterceptorUpdate),
+		recv: make(chan *ForwardHtlcInterceptBatch),
+		quit: make(chan struct{}),
+	}
+}
+
+func (m *batchedInterceptorStreamMock) Send(
+	update *HtlcInterceptorUpdate) error {
+
+	select {
+	case m.sent <- update:
+		return nil
+
+	case <-m.quit:
+		return io.EOF
+	}
+}
+
+func (m *batchedInterceptorStreamMock) Recv() (*ForwardHtlcInterceptBatch,
+	error) {
+
+	select {
+	case batch := <-m.recv:
+		return batch, nil
+
+	case <-m.quit:
+		return nil, io.EOF
+	}
+}
+
+// forwarderMock is a mock of the interceptable switch that records the
+// resolution batches it receives.
+type forwarderMock struct {
+	interceptors chan htlcswitch.ForwardInterceptor
+	batches      chan []*htlcswitch.FwdResolution
+}
+
+func newForwarderMock() *forwarderMock {
+	return &forwarderMock{
+		interceptors: make(chan htlcswitch.ForwardInterceptor, 2),
+		batches:      make(chan []*htlcswitch.FwdResolution, 10),
+	}
+}
+
+func (f *forwarderMock) SetInterceptor(
+	interceptor htlcswitch.ForwardInterceptor) {
+
+	f.interceptors <- interceptor
+}
+
+func (f *forwarderMock) Resolve(res *htlcswitch.FwdResolution) error {
+	errs, err := f.ResolveBatch([]*htlcswitch.FwdResolution{res})
+	if err != nil {
+		return err
+	}
+
+	return errs[0]
+}
+
+func (f *forwarderMock) ResolveBatch(
+	res []*htlcswitch.FwdResolution) ([]error, error) {
+
+	f.batches <- res
+
+	return make([]error, len(res)), nil
+}
+
+// TestForwardInterceptorBatching tests that intercepted htlcs are delivered to
+// the client and that all client resolutions are passed to the switch.
+func TestForwardInterceptorBatching(t *testing.T) {
+	t.Parallel()
+
+	stream := newInterceptorStreamMock()
+	forwarder := newForwarderMock()
+	interceptor := newForwardInterceptor(forwarder, stream, 0)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- interceptor.run()
+	}()
+
+	onIntercept := <-forwarder.interceptors
+	require.NotNil(t, onIntercept)
+
+	const numHtlcs = 5
+	for i := 0; i < numHtlcs; i++ {
+		err := onIntercept(htlcswitch.InterceptedPacket{
+			IncomingCircuit: models.CircuitKey{
+				ChanID: lnwire.NewShortChanIDFromInt(1),
+				HtlcID: uint64(i),
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < numHtlcs; i++ {
+		req := <-stream.sent
+		require.EqualValues(t, i, req.IncomingCircuitKey.HtlcId)
+
+		stream.recv <- &ForwardHtlcInterceptResponse{
+			IncomingCircuitKey: req.IncomingCircuitKey,
+			Action:             ResolveHoldForwardAction_RESUME,
+		}
+	}
+
+	// All resolutions arrive at the switch, possibly spread over several
+	// batches.
+	resolved := make(map[uint64]struct{})
+	for len(resolved) < numHtlcs {
+		batch := <-forwarder.batches
+		for _, res := range batch {
+			require.Equal(t, htlcswitch.FwdActionResume, res.Action)
+			resolved[res.Key.HtlcID] = struct{}{}
+		}
+	}
+
+	// An invalid resolution terminates the stream.
+	stream.recv <- &ForwardHtlcInterceptResponse{}
+	require.Error(t, <-errChan)
+	require.Nil(t, <-forwarder.interceptors)
+
+	close(stream.quit)
+}
+
+// TestForwardInterceptorQueueFull tests that the switch isn't blocked by a
+// client that doesn't keep up with the intercepted htlcs.
+func TestForwardInterceptorQueueFull(t *testing.T) {
+	t.Parallel()
+
+	stream := newInterceptorStreamMock()
+	forwarder := newForwarderMock()
+	interceptor := newForwardInterceptor(forwarder, stream, 1)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- interceptor.run()
+	}()
+
+	onIntercept := <-forwarder.interceptors
+
+	// The client doesn't read from the stream, so at most one htlc is
+	// being sent and one is queued. One of three htlcs must therefore be
+	// rejected without blocking.
+	var queueFull bool
+	for i := 0; i < 3; i++ {
+		err := onIntercept(htlcswitch.InterceptedPacket{
+			IncomingCircuit: models.CircuitKey{
+				HtlcID: uint64(i),
+			},
+		})
+		if errors.Is(err, ErrInterceptorQueueFull) {
+			queueFull = true
+			continue
+		}
+		require.NoError(t, err)
+	}
+	require.True(t, queueFull)
+
+	// Closing the stream stops the interceptor.
+	close(stream.quit)
+	require.ErrorIs(t, <-errChan, io.EOF)
+}
+
+// TestForwardInterceptorBatchedAcks tests that the batched interceptor stream
+// accepts many resolutions per message, acknowledges the resolutions that
+// request it, and reports invalid resolutions in their acknowledgement instead
+// of terminating the stream.
+func TestForwardInterceptorBatchedAcks(t *testing.T) {
+	t.Parallel()
+
+	stream := newBatchedInterceptorStreamMock()
+	forwarder := newForwarderMock()
+	interceptor := newBatchedForwardInterceptor(forwarder, stream, 0)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- interceptor.run()
+	}()
+
+	onIntercept := <-forwarder.interceptors
+	require.NotNil(t, onIntercept)
+
+	err := onIntercept(htlcswitch.InterceptedPacket{
+		IncomingCircuit: models.CircuitKey{
+			ChanID: lnwire.NewShortChanIDFromInt(1),
+			HtlcID: 7,
+		},
+	})
+	require.NoError(t, err)
+
+	update := <-stream.sent
+	htlc := update.GetHtlc()
+	require.NotNil(t, htlc)
+	require.EqualValues(t, 7, htlc.IncomingCircuitKey.HtlcId)
+
+	invalidKey := &CircuitKey{ChanId: 1, HtlcId: 8}
+	stream.recv <- &ForwardHtlcInterceptBatch{
+		Resolutions: []*ForwardHtlcInterceptResponse{{
+			IncomingCircuitKey: htlc.IncomingCircuitKey,
+			Action:             ResolveHoldForwardAction_RESUME,
+			RequestAck:         true,
+		}, {
+			// A settle without a preimage is invalid.
+			IncomingCircuitKey: invalidKey,
+			Action:             ResolveHoldForwardAction_SETTLE,
+			RequestAck:         true,
+		}, {
+			IncomingCircuitKey: &CircuitKey{ChanId: 1, HtlcId: 9},
+			Action:             ResolveHoldForwardAction_RESUME,
+		}},
+	}
+
+	// Only the valid resolutions are passed to the switch.
+	batch := <-forwarder.batches
+	require.Len(t, batch, 2)
+	require.EqualValues(t, 7, batch[0].Key.HtlcID)
+	require.EqualValues(t, 9, batch[1].Key.HtlcID)
+
+	// Both resolutions that requested it are acknowledged, in order.
+	ack := (<-stream.sent).GetAck()
+	require.NotNil(t, ack)
+	require.EqualValues(t, 7, ack.IncomingCircuitKey.HtlcId)
+	require.Empty(t, ack.Error)
+
+	ack = (<-stream.sent).GetAck()
+	require.NotNil(t, ack)
+	require.Equal(t, invalidKey, ack.IncomingCircuitKey)
+	require.Contains(t, ack.Error, ErrMissingPreimage.Error())
+
+	// An invalid resolution without an acknowledgement still terminates
+	// the stream.
+	stream.recv <- &ForwardHtlcInterceptBatch{
+		Resolutions: []*ForwardHtlcInterceptResponse{{}},
+	}
+	require.Error(t, <-errChan)
+	require.Nil(t, <-forwarder.interceptors)
+
+	close(stream.quit)
+}
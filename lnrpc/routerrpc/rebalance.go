@@ -0,0 +1,158 @@
+package routerrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// errRebalanceUnsupported is returned if the router backend lacks the
+// dependencies that are needed to rebalance channels.
+var errRebalanceUnsupported = errors.New("rebalancing is not supported")
+
+// Rebalance moves local balance from one of our channels to another by paying
+// ourselves in a circle. The payment leaves through the outgoing channel and
+// comes back from the peer of the incoming channel.
+func (s *Server) Rebalance(ctx context.Context,
+	req *RebalanceRequest) (*RebalanceResponse, error) {
+
+	backend := s.cfg.RouterBackend
+	if backend.ChannelBalance == nil || backend.AddInvoice == nil {
+		return nil, errRebalanceUnsupported
+	}
+
+	switch {
+	case req.OutgoingChanId == 0 || req.IncomingChanId == 0:
+		return nil, errors.New("both the outgoing and the incoming " +
+			"channel must be set")
+
+	case req.OutgoingChanId == req.IncomingChanId:
+		return nil, errors.New("the outgoing and the incoming channel " +
+			"must be different")
+
+	case req.AmtSat <= 0:
+		return nil, errors.New("amount must be greater than zero")
+
+	case req.FeeLimitSat < 0:
+		return nil, errors.New("fee limit must not be negative")
+	}
+
+	channels, lastHop, err := s.rebalanceChannels(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// A route to ourselves that leaves through the outgoing channel and
+	// arrives from the peer of the incoming channel is circular.
+	routes, err := backend.QueryRoutes(ctx, &lnrpc.QueryRoutesRequest{
+		PubKey:         backend.SelfNode.String(),
+		Amt:            req.AmtSat,
+		OutgoingChanId: req.OutgoingChanId,
+		LastHopPubkey:  lastHop,
+		FeeLimit: &lnrpc.FeeLimit{
+			Limit: &lnrpc.FeeLimit_Fixed{
+				Fixed: req.FeeLimitSat,
+			},
+		},
+		CltvLimit: req.CltvLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to find a circular route: %w",
+			err)
+	}
+	if len(routes.Routes) == 0 {
+		return nil, errors.New("no circular route found")
+	}
+
+	resp := &RebalanceResponse{
+		Route:    routes.Routes[0],
+		Channels: channels,
+	}
+	if req.QueryOnly {
+		return resp, nil
+	}
+
+	memo := fmt.Sprintf("rebalance %v -> %v", req.OutgoingChanId,
+		req.IncomingChanId)
+	amt := lnwire.NewMSatFromSatoshis(btcutil.Amount(req.AmtSat))
+	hash, payAddr, err := backend.AddInvoice(ctx, memo, amt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The invoice requires the payment address to be included in the
+	// final hop.
+	finalHop := resp.Route.Hops[len(resp.Route.Hops)-1]
+	finalHop.MppRecord = &lnrpc.MPPRecord{
+		PaymentAddr:  payAddr[:],
+		TotalAmtMsat: finalHop.AmtToForwardMsat,
+	}
+
+	attempt, err := s.SendToRouteV2(ctx, &SendToRouteRequest{
+		PaymentHash: hash[:],
+		Route:       resp.Route,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Status != lnrpc.HTLCAttempt_SUCCEEDED {
+		if attempt.Failure != nil {
+			return nil, fmt.Errorf("rebalance failed: %v at hop %d",
+				attempt.Failure.Code,
+				attempt.Failure.FailureSourceIndex)
+		}
+
+		return nil, fmt.Errorf("rebalance failed: %v", attempt.Status)
+	}
+	resp.PaymentHash = hash[:]
+
+	for _, channel := range resp.Channels {
+		_, balance, err := backend.ChannelBalance(channel.ChanId)
+		if err != nil {
+			return nil, err
+		}
+		channel.LocalBalanceAfter = int64(balance)
+	}
+
+	return resp, nil
+}
+
+// rebalanceChannels returns the current local balances of the outgoing and
+// the incoming channel of a rebalance, along with the peer of the incoming
+// channel.
+func (s *Server) rebalanceChannels(req *RebalanceRequest) (
+	[]*RebalanceChannel, []byte, error) {
+
+	backend := s.cfg.RouterBackend
+
+	_, outgoingBalance, err := backend.ChannelBalance(req.OutgoingChanId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to find outgoing channel "+
+			"%v: %w", req.OutgoingChanId, err)
+	}
+
+	peer, incomingBalance, err := backend.ChannelBalance(req.IncomingChanId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to find incoming channel "+
+			"%v: %w", req.IncomingChanId, err)
+	}
+
+	channels := []*RebalanceChannel{
+		{
+			ChanId:             req.OutgoingChanId,
+			LocalBalanceBefore: int64(outgoingBalance),
+			LocalBalanceAfter:  int64(outgoingBalance),
+		},
+		{
+			ChanId:             req.IncomingChanId,
+			LocalBalanceBefore: int64(incomingBalance),
+			LocalBalanceAfter:  int64(incomingBalance),
+		},
+	}
+
+	return channels, peer[:], nil
+}
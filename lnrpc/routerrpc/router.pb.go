@@ -8,6 +8,7 @@ package routerrpc
 
 import (
 	lnrpc "github.com/lightningnetwork/lnd/lnrpc"
+	chainrpc "github.com/lightningnetwork/lnd/lnrpc/chainrpc"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -307,6 +308,61 @@ func (ChanStatusAction) EnumDescriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{3}
 }
 
+type ForwardingPackageState int32
+
+const (
+	// The package hasn't committed to the exact set of adds to forward to the
+	// switch yet.
+	ForwardingPackageState_FWD_PKG_LOCKED_IN ForwardingPackageState = 0
+	// All adds have been processed and the forwarding decision has been
+	// persisted.
+	ForwardingPackageState_FWD_PKG_PROCESSED ForwardingPackageState = 1
+	// All adds have been acked, and all settles and fails have been delivered to
+	// their sources. The package will be removed.
+	ForwardingPackageState_FWD_PKG_COMPLETED ForwardingPackageState = 2
+)
+
+// Enum value maps for ForwardingPackageState.
+var (
+	ForwardingPackageState_name = map[int32]string{
+		0: "FWD_PKG_LOCKED_IN",
+		1: "FWD_PKG_PROCESSED",
+		2: "FWD_PKG_COMPLETED",
+	}
+	ForwardingPackageState_value = map[string]int32{
+		"FWD_PKG_LOCKED_IN": 0,
+		"FWD_PKG_PROCESSED": 1,
+		"FWD_PKG_COMPLETED": 2,
+	}
+)
+
+func (x ForwardingPackageState) Enum() *ForwardingPackageState {
+	p := new(ForwardingPackageState)
+	*p = x
+	return p
+}
+
+func (x ForwardingPackageState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ForwardingPackageState) Descriptor() protoreflect.EnumDescriptor {
+	return file_routerrpc_router_proto_enumTypes[4].Descriptor()
+}
+
+func (ForwardingPackageState) Type() protoreflect.EnumType {
+	return &file_routerrpc_router_proto_enumTypes[4]
+}
+
+func (x ForwardingPackageState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ForwardingPackageState.Descriptor instead.
+func (ForwardingPackageState) EnumDescriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{4}
+}
+
 type MissionControlConfig_ProbabilityModel int32
 
 const (
@@ -337,11 +393,11 @@ func (x MissionControlConfig_ProbabilityModel) String() string {
 }
 
 func (MissionControlConfig_ProbabilityModel) Descriptor() protoreflect.EnumDescriptor {
-	return file_routerrpc_router_proto_enumTypes[4].Descriptor()
+	return file_routerrpc_router_proto_enumTypes[5].Descriptor()
 }
 
 func (MissionControlConfig_ProbabilityModel) Type() protoreflect.EnumType {
-	return &file_routerrpc_router_proto_enumTypes[4]
+	return &file_routerrpc_router_proto_enumTypes[5]
 }
 
 func (x MissionControlConfig_ProbabilityModel) Number() protoreflect.EnumNumber {
@@ -350,7 +406,71 @@ func (x MissionControlConfig_ProbabilityModel) Number() protoreflect.EnumNumber
 
 // Deprecated: Use MissionControlConfig_ProbabilityModel.Descriptor instead.
 func (MissionControlConfig_ProbabilityModel) EnumDescriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{19, 0}
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{27, 0}
+}
+
+type SubscribeAllEvent_EventType int32
+
+const (
+	// A channel event, as sent by SubscribeChannelEvents.
+	SubscribeAllEvent_CHANNEL SubscribeAllEvent_EventType = 0
+	// A peer event, as sent by SubscribePeerEvents.
+	SubscribeAllEvent_PEER SubscribeAllEvent_EventType = 1
+	// A htlc event, as sent by SubscribeHtlcEvents.
+	SubscribeAllEvent_HTLC SubscribeAllEvent_EventType = 2
+	// An invoice update, as sent by SubscribeInvoices.
+	SubscribeAllEvent_INVOICE SubscribeAllEvent_EventType = 3
+	// A payment update, as sent by TrackPayments.
+	SubscribeAllEvent_PAYMENT SubscribeAllEvent_EventType = 4
+	// A new block, as sent by RegisterBlockEpochNtfn.
+	SubscribeAllEvent_BLOCK SubscribeAllEvent_EventType = 5
+)
+
+// Enum value maps for SubscribeAllEvent_EventType.
+var (
+	SubscribeAllEvent_EventType_name = map[int32]string{
+		0: "CHANNEL",
+		1: "PEER",
+		2: "HTLC",
+		3: "INVOICE",
+		4: "PAYMENT",
+		5: "BLOCK",
+	}
+	SubscribeAllEvent_EventType_value = map[string]int32{
+		"CHANNEL": 0,
+		"PEER":    1,
+		"HTLC":    2,
+		"INVOICE": 3,
+		"PAYMENT": 4,
+		"BLOCK":   5,
+	}
+)
+
+func (x SubscribeAllEvent_EventType) Enum() *SubscribeAllEvent_EventType {
+	p := new(SubscribeAllEvent_EventType)
+	*p = x
+	return p
+}
+
+func (x SubscribeAllEvent_EventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubscribeAllEvent_EventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_routerrpc_router_proto_enumTypes[6].Descriptor()
+}
+
+func (SubscribeAllEvent_EventType) Type() protoreflect.EnumType {
+	return &file_routerrpc_router_proto_enumTypes[6]
+}
+
+func (x SubscribeAllEvent_EventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubscribeAllEvent_EventType.Descriptor instead.
+func (SubscribeAllEvent_EventType) EnumDescriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{41, 0}
 }
 
 type HtlcEvent_EventType int32
@@ -389,11 +509,11 @@ func (x HtlcEvent_EventType) String() string {
 }
 
 func (HtlcEvent_EventType) Descriptor() protoreflect.EnumDescriptor {
-	return file_routerrpc_router_proto_enumTypes[5].Descriptor()
+	return file_routerrpc_router_proto_enumTypes[7].Descriptor()
 }
 
 func (HtlcEvent_EventType) Type() protoreflect.EnumType {
-	return &file_routerrpc_router_proto_enumTypes[5]
+	return &file_routerrpc_router_proto_enumTypes[7]
 }
 
 func (x HtlcEvent_EventType) Number() protoreflect.EnumNumber {
@@ -402,7 +522,7 @@ func (x HtlcEvent_EventType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use HtlcEvent_EventType.Descriptor instead.
 func (HtlcEvent_EventType) EnumDescriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{27, 0}
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{45, 0}
 }
 
 type SendPaymentRequest struct {
@@ -513,6 +633,31 @@ type SendPaymentRequest struct {
 	// the custom range >= 65536. When using REST, the values must be encoded as
 	// base64.
 	FirstHopCustomRecords map[uint64][]byte `protobuf:"bytes,25,rep,name=first_hop_custom_records,json=firstHopCustomRecords,proto3" json:"first_hop_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// An optional set of additional limits that are enforced while the payment
+	// is in flight. Once one of them is exhausted, no new HTLC attempts are made
+	// and the payment fails after its in-flight attempts are resolved. The budget
+	// isn't persisted, a payment that is resumed after a restart is only bound by
+	// its regular limits.
+	Budget *PaymentBudget `protobuf:"bytes,26,opt,name=budget,proto3" json:"budget,omitempty"`
+	// An optional list of TLV records to pass to intermediate hops of the route
+	// in their onion payload, grouped by the node they are addressed to. Records
+	// for nodes that don't end up on the route of an attempt aren't sent. The
+	// records can't be addressed to our own node or to the destination, which
+	// receives its records through dest_custom_records. Record types are required
+	// to be in the custom range >= 65536. When using REST, the values must be
+	// encoded as base64.
+	HopCustomRecords []*HopCustomRecords `protobuf:"bytes,27,rep,name=hop_custom_records,json=hopCustomRecords,proto3" json:"hop_custom_records,omitempty"`
+	// If set, finding the route to the destination is delegated to the given
+	// trampoline node. Only a route to the trampoline node is found, the nested
+	// trampoline onion instructs it to pay the destination on our behalf. This
+	// requires lnd to run with routing.trampoline and can't be combined with amp,
+	// blinded paths or custom records.
+	Trampoline *TrampolineOptions `protobuf:"bytes,28,opt,name=trampoline,proto3" json:"trampoline,omitempty"`
+	// An optional limit on the time lock delta that individual hops of the routes
+	// of the payment may charge. Limits that aren't set fall back to the ones
+	// configured with routing.max-hop-cltv-delta and
+	// routing.max-last-hop-cltv-delta.
+	CltvBudget *lnrpc.CltvBudget `protobuf:"bytes,29,opt,name=cltv_budget,json=cltvBudget,proto3" json:"cltv_budget,omitempty"`
 }
 
 func (x *SendPaymentRequest) Reset() {
@@ -723,20 +868,51 @@ func (x *SendPaymentRequest) GetFirstHopCustomRecords() map[uint64][]byte {
 	return nil
 }
 
-type TrackPaymentRequest struct {
+func (x *SendPaymentRequest) GetBudget() *PaymentBudget {
+	if x != nil {
+		return x.Budget
+	}
+	return nil
+}
+
+func (x *SendPaymentRequest) GetHopCustomRecords() []*HopCustomRecords {
+	if x != nil {
+		return x.HopCustomRecords
+	}
+	return nil
+}
+
+func (x *SendPaymentRequest) GetTrampoline() *TrampolineOptions {
+	if x != nil {
+		return x.Trampoline
+	}
+	return nil
+}
+
+func (x *SendPaymentRequest) GetCltvBudget() *lnrpc.CltvBudget {
+	if x != nil {
+		return x.CltvBudget
+	}
+	return nil
+}
+
+type TrampolineOptions struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The hash of the payment to look up.
-	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
-	// If set, only the final payment update is streamed back. Intermediate updates
-	// that show which htlcs are still in flight are suppressed.
-	NoInflightUpdates bool `protobuf:"varint,2,opt,name=no_inflight_updates,json=noInflightUpdates,proto3" json:"no_inflight_updates,omitempty"`
+	// The identity pubkey of the trampoline node.
+	Node []byte `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	// The fee in millisatoshis that is allotted to the trampoline node for paying
+	// the destination. It is deducted from the fee limit of the payment.
+	FeeMsat uint64 `protobuf:"varint,2,opt,name=fee_msat,json=feeMsat,proto3" json:"fee_msat,omitempty"`
+	// The CLTV delta that is allotted to the trampoline node for the route to the
+	// destination.
+	CltvDelta uint32 `protobuf:"varint,3,opt,name=cltv_delta,json=cltvDelta,proto3" json:"cltv_delta,omitempty"`
 }
 
-func (x *TrackPaymentRequest) Reset() {
-	*x = TrackPaymentRequest{}
+func (x *TrampolineOptions) Reset() {
+	*x = TrampolineOptions{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -744,13 +920,13 @@ func (x *TrackPaymentRequest) Reset() {
 	}
 }
 
-func (x *TrackPaymentRequest) String() string {
+func (x *TrampolineOptions) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TrackPaymentRequest) ProtoMessage() {}
+func (*TrampolineOptions) ProtoMessage() {}
 
-func (x *TrackPaymentRequest) ProtoReflect() protoreflect.Message {
+func (x *TrampolineOptions) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -762,37 +938,45 @@ func (x *TrackPaymentRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TrackPaymentRequest.ProtoReflect.Descriptor instead.
-func (*TrackPaymentRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TrampolineOptions.ProtoReflect.Descriptor instead.
+func (*TrampolineOptions) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *TrackPaymentRequest) GetPaymentHash() []byte {
+func (x *TrampolineOptions) GetNode() []byte {
 	if x != nil {
-		return x.PaymentHash
+		return x.Node
 	}
 	return nil
 }
 
-func (x *TrackPaymentRequest) GetNoInflightUpdates() bool {
+func (x *TrampolineOptions) GetFeeMsat() uint64 {
 	if x != nil {
-		return x.NoInflightUpdates
+		return x.FeeMsat
 	}
-	return false
+	return 0
 }
 
-type TrackPaymentsRequest struct {
+func (x *TrampolineOptions) GetCltvDelta() uint32 {
+	if x != nil {
+		return x.CltvDelta
+	}
+	return 0
+}
+
+type HopCustomRecords struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// If set, only the final payment updates are streamed back. Intermediate
-	// updates that show which htlcs are still in flight are suppressed.
-	NoInflightUpdates bool `protobuf:"varint,1,opt,name=no_inflight_updates,json=noInflightUpdates,proto3" json:"no_inflight_updates,omitempty"`
+	// The identity pubkey of the hop the records are addressed to.
+	PubKey []byte `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	// The TLV records that are sent to the hop.
+	CustomRecords map[uint64][]byte `protobuf:"bytes,2,rep,name=custom_records,json=customRecords,proto3" json:"custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *TrackPaymentsRequest) Reset() {
-	*x = TrackPaymentsRequest{}
+func (x *HopCustomRecords) Reset() {
+	*x = HopCustomRecords{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -800,13 +984,13 @@ func (x *TrackPaymentsRequest) Reset() {
 	}
 }
 
-func (x *TrackPaymentsRequest) String() string {
+func (x *HopCustomRecords) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TrackPaymentsRequest) ProtoMessage() {}
+func (*HopCustomRecords) ProtoMessage() {}
 
-func (x *TrackPaymentsRequest) ProtoReflect() protoreflect.Message {
+func (x *HopCustomRecords) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -818,50 +1002,49 @@ func (x *TrackPaymentsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TrackPaymentsRequest.ProtoReflect.Descriptor instead.
-func (*TrackPaymentsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use HopCustomRecords.ProtoReflect.Descriptor instead.
+func (*HopCustomRecords) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *TrackPaymentsRequest) GetNoInflightUpdates() bool {
+func (x *HopCustomRecords) GetPubKey() []byte {
 	if x != nil {
-		return x.NoInflightUpdates
+		return x.PubKey
 	}
-	return false
+	return nil
 }
 
-type RouteFeeRequest struct {
+func (x *HopCustomRecords) GetCustomRecords() map[uint64][]byte {
+	if x != nil {
+		return x.CustomRecords
+	}
+	return nil
+}
+
+type PaymentBudget struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The destination one wishes to obtain a routing fee quote to. If set, this
-	// parameter requires the amt_sat parameter also to be set. This parameter
-	// combination triggers a graph based routing fee estimation as opposed to a
-	// payment probe based estimate in case a payment request is provided. The
-	// graph based estimation is an algorithm that is executed on the in memory
-	// graph. Hence its runtime is significantly shorter than a payment probe
-	// estimation that sends out actual payments to the network.
-	Dest []byte `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
-	// The amount one wishes to send to the target destination. It is only to be
-	// used in combination with the dest parameter.
-	AmtSat int64 `protobuf:"varint,2,opt,name=amt_sat,json=amtSat,proto3" json:"amt_sat,omitempty"`
-	// A payment request of the target node that the route fee request is intended
-	// for. Its parameters are input to probe payments that estimate routing fees.
-	// The timeout parameter can be specified to set a maximum time on the probing
-	// attempt. Cannot be used in combination with dest and amt_sat.
-	PaymentRequest string `protobuf:"bytes,3,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
-	// A user preference of how long a probe payment should maximally be allowed to
-	// take, denoted in seconds. The probing payment loop is aborted if this
-	// timeout is reached. Note that the probing process itself can take longer
-	// than the timeout if the HTLC becomes delayed or stuck. Canceling the context
-	// of this call will not cancel the payment loop, the duration is only
-	// controlled by the timeout parameter.
-	Timeout uint32 `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
-}
-
-func (x *RouteFeeRequest) Reset() {
-	*x = RouteFeeRequest{}
+	// The maximum fee expressed as a percentage of the payment amount. The
+	// effective fee limit is the lower of this and the fee limit of the payment.
+	// If zero, only the fee limit of the payment is enforced.
+	MaxFeePercent float64 `protobuf:"fixed64,1,opt,name=max_fee_percent,json=maxFeePercent,proto3" json:"max_fee_percent,omitempty"`
+	// The unix timestamp in seconds after which no new HTLC attempts are made. If
+	// zero, the payment has no deadline.
+	Deadline int64 `protobuf:"varint,2,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	// The maximum number of HTLC attempts, including all shards of a multi-path
+	// payment, that are made for the payment. If zero, the number of attempts
+	// isn't limited.
+	MaxAttempts uint32 `protobuf:"varint,3,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// The maximum number of seconds an HTLC attempt may remain in flight. If an
+	// attempt exceeds it, no new HTLC attempts are made as the payment likely got
+	// stuck. If zero, in-flight attempts aren't timed.
+	AttemptTimeoutSeconds uint32 `protobuf:"varint,4,opt,name=attempt_timeout_seconds,json=attemptTimeoutSeconds,proto3" json:"attempt_timeout_seconds,omitempty"`
+}
+
+func (x *PaymentBudget) Reset() {
+	*x = PaymentBudget{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -869,13 +1052,13 @@ func (x *RouteFeeRequest) Reset() {
 	}
 }
 
-func (x *RouteFeeRequest) String() string {
+func (x *PaymentBudget) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RouteFeeRequest) ProtoMessage() {}
+func (*PaymentBudget) ProtoMessage() {}
 
-func (x *RouteFeeRequest) ProtoReflect() protoreflect.Message {
+func (x *PaymentBudget) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -887,58 +1070,53 @@ func (x *RouteFeeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RouteFeeRequest.ProtoReflect.Descriptor instead.
-func (*RouteFeeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PaymentBudget.ProtoReflect.Descriptor instead.
+func (*PaymentBudget) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *RouteFeeRequest) GetDest() []byte {
+func (x *PaymentBudget) GetMaxFeePercent() float64 {
 	if x != nil {
-		return x.Dest
+		return x.MaxFeePercent
 	}
-	return nil
+	return 0
 }
 
-func (x *RouteFeeRequest) GetAmtSat() int64 {
+func (x *PaymentBudget) GetDeadline() int64 {
 	if x != nil {
-		return x.AmtSat
+		return x.Deadline
 	}
 	return 0
 }
 
-func (x *RouteFeeRequest) GetPaymentRequest() string {
+func (x *PaymentBudget) GetMaxAttempts() uint32 {
 	if x != nil {
-		return x.PaymentRequest
+		return x.MaxAttempts
 	}
-	return ""
+	return 0
 }
 
-func (x *RouteFeeRequest) GetTimeout() uint32 {
+func (x *PaymentBudget) GetAttemptTimeoutSeconds() uint32 {
 	if x != nil {
-		return x.Timeout
+		return x.AttemptTimeoutSeconds
 	}
 	return 0
 }
 
-type RouteFeeResponse struct {
+type TrackPaymentRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// A lower bound of the estimated fee to the target destination within the
-	// network, expressed in milli-satoshis.
-	RoutingFeeMsat int64 `protobuf:"varint,1,opt,name=routing_fee_msat,json=routingFeeMsat,proto3" json:"routing_fee_msat,omitempty"`
-	// An estimate of the worst case time delay that can occur. Note that callers
-	// will still need to factor in the final CLTV delta of the last hop into this
-	// value.
-	TimeLockDelay int64 `protobuf:"varint,2,opt,name=time_lock_delay,json=timeLockDelay,proto3" json:"time_lock_delay,omitempty"`
-	// An indication whether a probing payment succeeded or whether and why it
-	// failed. FAILURE_REASON_NONE indicates success.
-	FailureReason lnrpc.PaymentFailureReason `protobuf:"varint,5,opt,name=failure_reason,json=failureReason,proto3,enum=lnrpc.PaymentFailureReason" json:"failure_reason,omitempty"`
+	// The hash of the payment to look up.
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	// If set, only the final payment update is streamed back. Intermediate updates
+	// that show which htlcs are still in flight are suppressed.
+	NoInflightUpdates bool `protobuf:"varint,2,opt,name=no_inflight_updates,json=noInflightUpdates,proto3" json:"no_inflight_updates,omitempty"`
 }
 
-func (x *RouteFeeResponse) Reset() {
-	*x = RouteFeeResponse{}
+func (x *TrackPaymentRequest) Reset() {
+	*x = TrackPaymentRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -946,13 +1124,13 @@ func (x *RouteFeeResponse) Reset() {
 	}
 }
 
-func (x *RouteFeeResponse) String() string {
+func (x *TrackPaymentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RouteFeeResponse) ProtoMessage() {}
+func (*TrackPaymentRequest) ProtoMessage() {}
 
-func (x *RouteFeeResponse) ProtoReflect() protoreflect.Message {
+func (x *TrackPaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -964,56 +1142,37 @@ func (x *RouteFeeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RouteFeeResponse.ProtoReflect.Descriptor instead.
-func (*RouteFeeResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use TrackPaymentRequest.ProtoReflect.Descriptor instead.
+func (*TrackPaymentRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *RouteFeeResponse) GetRoutingFeeMsat() int64 {
-	if x != nil {
-		return x.RoutingFeeMsat
-	}
-	return 0
-}
-
-func (x *RouteFeeResponse) GetTimeLockDelay() int64 {
+func (x *TrackPaymentRequest) GetPaymentHash() []byte {
 	if x != nil {
-		return x.TimeLockDelay
+		return x.PaymentHash
 	}
-	return 0
+	return nil
 }
 
-func (x *RouteFeeResponse) GetFailureReason() lnrpc.PaymentFailureReason {
+func (x *TrackPaymentRequest) GetNoInflightUpdates() bool {
 	if x != nil {
-		return x.FailureReason
+		return x.NoInflightUpdates
 	}
-	return lnrpc.PaymentFailureReason(0)
+	return false
 }
 
-type SendToRouteRequest struct {
+type TrackPaymentsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The payment hash to use for the HTLC.
-	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
-	// Route that should be used to attempt to complete the payment.
-	Route *lnrpc.Route `protobuf:"bytes,2,opt,name=route,proto3" json:"route,omitempty"`
-	// Whether the payment should be marked as failed when a temporary error is
-	// returned from the given route. Set it to true so the payment won't be
-	// failed unless a terminal error is occurred, such as payment timeout, no
-	// routes, incorrect payment details, or insufficient funds.
-	SkipTempErr bool `protobuf:"varint,3,opt,name=skip_temp_err,json=skipTempErr,proto3" json:"skip_temp_err,omitempty"`
-	// An optional field that can be used to pass an arbitrary set of TLV records
-	// to the first hop peer of this payment. This can be used to pass application
-	// specific data during the payment attempt. Record types are required to be in
-	// the custom range >= 65536. When using REST, the values must be encoded as
-	// base64.
-	FirstHopCustomRecords map[uint64][]byte `protobuf:"bytes,4,rep,name=first_hop_custom_records,json=firstHopCustomRecords,proto3" json:"first_hop_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// If set, only the final payment updates are streamed back. Intermediate
+	// updates that show which htlcs are still in flight are suppressed.
+	NoInflightUpdates bool `protobuf:"varint,1,opt,name=no_inflight_updates,json=noInflightUpdates,proto3" json:"no_inflight_updates,omitempty"`
 }
 
-func (x *SendToRouteRequest) Reset() {
-	*x = SendToRouteRequest{}
+func (x *TrackPaymentsRequest) Reset() {
+	*x = TrackPaymentsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1021,13 +1180,13 @@ func (x *SendToRouteRequest) Reset() {
 	}
 }
 
-func (x *SendToRouteRequest) String() string {
+func (x *TrackPaymentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendToRouteRequest) ProtoMessage() {}
+func (*TrackPaymentsRequest) ProtoMessage() {}
 
-func (x *SendToRouteRequest) ProtoReflect() protoreflect.Message {
+func (x *TrackPaymentsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1039,52 +1198,30 @@ func (x *SendToRouteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendToRouteRequest.ProtoReflect.Descriptor instead.
-func (*SendToRouteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use TrackPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*TrackPaymentsRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *SendToRouteRequest) GetPaymentHash() []byte {
+func (x *TrackPaymentsRequest) GetNoInflightUpdates() bool {
 	if x != nil {
-		return x.PaymentHash
-	}
-	return nil
-}
-
-func (x *SendToRouteRequest) GetRoute() *lnrpc.Route {
-	if x != nil {
-		return x.Route
-	}
-	return nil
-}
-
-func (x *SendToRouteRequest) GetSkipTempErr() bool {
-	if x != nil {
-		return x.SkipTempErr
+		return x.NoInflightUpdates
 	}
 	return false
 }
 
-func (x *SendToRouteRequest) GetFirstHopCustomRecords() map[uint64][]byte {
-	if x != nil {
-		return x.FirstHopCustomRecords
-	}
-	return nil
-}
-
-type SendToRouteResponse struct {
+type SendPaymentsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The preimage obtained by making the payment.
-	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
-	// The failure message in case the payment failed.
-	Failure *lnrpc.Failure `protobuf:"bytes,2,opt,name=failure,proto3" json:"failure,omitempty"`
+	// The payments of the batch. Up to 1000 payments can be sent in a single
+	// batch.
+	Payments []*SendPaymentRequest `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
 }
 
-func (x *SendToRouteResponse) Reset() {
-	*x = SendToRouteResponse{}
+func (x *SendPaymentsRequest) Reset() {
+	*x = SendPaymentsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1092,13 +1229,13 @@ func (x *SendToRouteResponse) Reset() {
 	}
 }
 
-func (x *SendToRouteResponse) String() string {
+func (x *SendPaymentsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendToRouteResponse) ProtoMessage() {}
+func (*SendPaymentsRequest) ProtoMessage() {}
 
-func (x *SendToRouteResponse) ProtoReflect() protoreflect.Message {
+func (x *SendPaymentsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1110,33 +1247,32 @@ func (x *SendToRouteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendToRouteResponse.ProtoReflect.Descriptor instead.
-func (*SendToRouteResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*SendPaymentsRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *SendToRouteResponse) GetPreimage() []byte {
-	if x != nil {
-		return x.Preimage
-	}
-	return nil
-}
-
-func (x *SendToRouteResponse) GetFailure() *lnrpc.Failure {
+func (x *SendPaymentsRequest) GetPayments() []*SendPaymentRequest {
 	if x != nil {
-		return x.Failure
+		return x.Payments
 	}
 	return nil
 }
 
-type ResetMissionControlRequest struct {
+type SendPaymentsResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Update:
+	//
+	//	*SendPaymentsResponse_PaymentStatus
+	//	*SendPaymentsResponse_Summary
+	Update isSendPaymentsResponse_Update `protobuf_oneof:"update"`
 }
 
-func (x *ResetMissionControlRequest) Reset() {
-	*x = ResetMissionControlRequest{}
+func (x *SendPaymentsResponse) Reset() {
+	*x = SendPaymentsResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1144,13 +1280,13 @@ func (x *ResetMissionControlRequest) Reset() {
 	}
 }
 
-func (x *ResetMissionControlRequest) String() string {
+func (x *SendPaymentsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetMissionControlRequest) ProtoMessage() {}
+func (*SendPaymentsResponse) ProtoMessage() {}
 
-func (x *ResetMissionControlRequest) ProtoReflect() protoreflect.Message {
+func (x *SendPaymentsResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1162,19 +1298,67 @@ func (x *ResetMissionControlRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetMissionControlRequest.ProtoReflect.Descriptor instead.
-func (*ResetMissionControlRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*SendPaymentsResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{7}
 }
 
-type ResetMissionControlResponse struct {
+func (m *SendPaymentsResponse) GetUpdate() isSendPaymentsResponse_Update {
+	if m != nil {
+		return m.Update
+	}
+	return nil
+}
+
+func (x *SendPaymentsResponse) GetPaymentStatus() *BatchPaymentStatus {
+	if x, ok := x.GetUpdate().(*SendPaymentsResponse_PaymentStatus); ok {
+		return x.PaymentStatus
+	}
+	return nil
+}
+
+func (x *SendPaymentsResponse) GetSummary() *BatchPaymentOutcome {
+	if x, ok := x.GetUpdate().(*SendPaymentsResponse_Summary); ok {
+		return x.Summary
+	}
+	return nil
+}
+
+type isSendPaymentsResponse_Update interface {
+	isSendPaymentsResponse_Update()
+}
+
+type SendPaymentsResponse_PaymentStatus struct {
+	// A status update of a payment of the batch. The updates of the
+	// payments of the batch are interleaved.
+	PaymentStatus *BatchPaymentStatus `protobuf:"bytes,1,opt,name=payment_status,json=paymentStatus,proto3,oneof"`
+}
+
+type SendPaymentsResponse_Summary struct {
+	// The summary of the batch, which is the last message of the stream.
+	Summary *BatchPaymentOutcome `protobuf:"bytes,2,opt,name=summary,proto3,oneof"`
+}
+
+func (*SendPaymentsResponse_PaymentStatus) isSendPaymentsResponse_Update() {}
+
+func (*SendPaymentsResponse_Summary) isSendPaymentsResponse_Update() {}
+
+type BatchPaymentStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The index of the payment within the batch.
+	Index uint32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// The latest state of the payment. Unset if the payment couldn't be
+	// initiated.
+	Payment *lnrpc.Payment `protobuf:"bytes,2,opt,name=payment,proto3" json:"payment,omitempty"`
+	// The error the payment couldn't be initiated with.
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
 }
 
-func (x *ResetMissionControlResponse) Reset() {
-	*x = ResetMissionControlResponse{}
+func (x *BatchPaymentStatus) Reset() {
+	*x = BatchPaymentStatus{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1182,13 +1366,13 @@ func (x *ResetMissionControlResponse) Reset() {
 	}
 }
 
-func (x *ResetMissionControlResponse) String() string {
+func (x *BatchPaymentStatus) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ResetMissionControlResponse) ProtoMessage() {}
+func (*BatchPaymentStatus) ProtoMessage() {}
 
-func (x *ResetMissionControlResponse) ProtoReflect() protoreflect.Message {
+func (x *BatchPaymentStatus) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1200,19 +1384,45 @@ func (x *ResetMissionControlResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ResetMissionControlResponse.ProtoReflect.Descriptor instead.
-func (*ResetMissionControlResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchPaymentStatus.ProtoReflect.Descriptor instead.
+func (*BatchPaymentStatus) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{8}
 }
 
-type QueryMissionControlRequest struct {
+func (x *BatchPaymentStatus) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchPaymentStatus) GetPayment() *lnrpc.Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *BatchPaymentStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchFailureReasonCount struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The failure reason of the payments.
+	Reason lnrpc.PaymentFailureReason `protobuf:"varint,1,opt,name=reason,proto3,enum=lnrpc.PaymentFailureReason" json:"reason,omitempty"`
+	// The number of failed payments of the batch with this reason.
+	Count uint32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
 }
 
-func (x *QueryMissionControlRequest) Reset() {
-	*x = QueryMissionControlRequest{}
+func (x *BatchFailureReasonCount) Reset() {
+	*x = BatchFailureReasonCount{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1220,13 +1430,13 @@ func (x *QueryMissionControlRequest) Reset() {
 	}
 }
 
-func (x *QueryMissionControlRequest) String() string {
+func (x *BatchFailureReasonCount) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryMissionControlRequest) ProtoMessage() {}
+func (*BatchFailureReasonCount) ProtoMessage() {}
 
-func (x *QueryMissionControlRequest) ProtoReflect() protoreflect.Message {
+func (x *BatchFailureReasonCount) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1238,23 +1448,43 @@ func (x *QueryMissionControlRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryMissionControlRequest.ProtoReflect.Descriptor instead.
-func (*QueryMissionControlRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchFailureReasonCount.ProtoReflect.Descriptor instead.
+func (*BatchFailureReasonCount) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{9}
 }
 
-// QueryMissionControlResponse contains mission control state.
-type QueryMissionControlResponse struct {
+func (x *BatchFailureReasonCount) GetReason() lnrpc.PaymentFailureReason {
+	if x != nil {
+		return x.Reason
+	}
+	return lnrpc.PaymentFailureReason(0)
+}
+
+func (x *BatchFailureReasonCount) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type BatchPaymentOutcome struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Node pair-level mission control state.
-	Pairs []*PairHistory `protobuf:"bytes,2,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	// The number of payments that succeeded.
+	NumSucceeded uint32 `protobuf:"varint,1,opt,name=num_succeeded,json=numSucceeded,proto3" json:"num_succeeded,omitempty"`
+	// The number of payments that were initiated, but failed.
+	NumFailed uint32 `protobuf:"varint,2,opt,name=num_failed,json=numFailed,proto3" json:"num_failed,omitempty"`
+	// The failed payments, counted by their failure reason.
+	FailureReasons []*BatchFailureReasonCount `protobuf:"bytes,3,rep,name=failure_reasons,json=failureReasons,proto3" json:"failure_reasons,omitempty"`
+	// The errors of the payments that couldn't be initiated, keyed by the
+	// index of the payment within the batch.
+	InitErrors map[uint32]string `protobuf:"bytes,4,rep,name=init_errors,json=initErrors,proto3" json:"init_errors,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *QueryMissionControlResponse) Reset() {
-	*x = QueryMissionControlResponse{}
+func (x *BatchPaymentOutcome) Reset() {
+	*x = BatchPaymentOutcome{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1262,13 +1492,13 @@ func (x *QueryMissionControlResponse) Reset() {
 	}
 }
 
-func (x *QueryMissionControlResponse) String() string {
+func (x *BatchPaymentOutcome) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryMissionControlResponse) ProtoMessage() {}
+func (*BatchPaymentOutcome) ProtoMessage() {}
 
-func (x *QueryMissionControlResponse) ProtoReflect() protoreflect.Message {
+func (x *BatchPaymentOutcome) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1280,33 +1510,78 @@ func (x *QueryMissionControlResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryMissionControlResponse.ProtoReflect.Descriptor instead.
-func (*QueryMissionControlResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchPaymentOutcome.ProtoReflect.Descriptor instead.
+func (*BatchPaymentOutcome) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *QueryMissionControlResponse) GetPairs() []*PairHistory {
+func (x *BatchPaymentOutcome) GetNumSucceeded() uint32 {
 	if x != nil {
-		return x.Pairs
+		return x.NumSucceeded
+	}
+	return 0
+}
+
+func (x *BatchPaymentOutcome) GetNumFailed() uint32 {
+	if x != nil {
+		return x.NumFailed
+	}
+	return 0
+}
+
+func (x *BatchPaymentOutcome) GetFailureReasons() []*BatchFailureReasonCount {
+	if x != nil {
+		return x.FailureReasons
 	}
 	return nil
 }
 
-type XImportMissionControlRequest struct {
+func (x *BatchPaymentOutcome) GetInitErrors() map[uint32]string {
+	if x != nil {
+		return x.InitErrors
+	}
+	return nil
+}
+
+type RouteFeeRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Node pair-level mission control state to be imported.
-	Pairs []*PairHistory `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
-	// Whether to force override MC pair history. Note that even with force
-	// override the failure pair is imported before the success pair and both
-	// still clamp existing failure/success amounts.
-	Force bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
+	// The destination one wishes to obtain a routing fee quote to. If set, this
+	// parameter requires the amt_sat parameter also to be set. This parameter
+	// combination triggers a graph based routing fee estimation as opposed to a
+	// payment probe based estimate in case a payment request is provided. The
+	// graph based estimation is an algorithm that is executed on the in memory
+	// graph. Hence its runtime is significantly shorter than a payment probe
+	// estimation that sends out actual payments to the network.
+	Dest []byte `protobuf:"bytes,1,opt,name=dest,proto3" json:"dest,omitempty"`
+	// The amount one wishes to send to the target destination. It is only to be
+	// used in combination with the dest parameter.
+	AmtSat int64 `protobuf:"varint,2,opt,name=amt_sat,json=amtSat,proto3" json:"amt_sat,omitempty"`
+	// A payment request of the target node that the route fee request is intended
+	// for. Its parameters are input to probe payments that estimate routing fees.
+	// The timeout parameter can be specified to set a maximum time on the probing
+	// attempt. Cannot be used in combination with dest and amt_sat.
+	PaymentRequest string `protobuf:"bytes,3,opt,name=payment_request,json=paymentRequest,proto3" json:"payment_request,omitempty"`
+	// A user preference of how long a probe payment should maximally be allowed to
+	// take, denoted in seconds. The probing payment loop is aborted if this
+	// timeout is reached. Note that the probing process itself can take longer
+	// than the timeout if the HTLC becomes delayed or stuck. Canceling the context
+	// of this call will not cancel the payment loop, the duration is only
+	// controlled by the timeout parameter.
+	Timeout uint32 `protobuf:"varint,4,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// Simulate the payment instead of querying a single route or sending probe
+	// payments. The simulation runs path finding against the current mission
+	// control state and splits the payment into multiple shards where needed,
+	// without sending any HTLCs. The estimated success probability is returned
+	// in success_prob. Can be used with either dest and amt_sat, or
+	// payment_request.
+	Simulate bool `protobuf:"varint,5,opt,name=simulate,proto3" json:"simulate,omitempty"`
 }
 
-func (x *XImportMissionControlRequest) Reset() {
-	*x = XImportMissionControlRequest{}
+func (x *RouteFeeRequest) Reset() {
+	*x = RouteFeeRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1314,13 +1589,13 @@ func (x *XImportMissionControlRequest) Reset() {
 	}
 }
 
-func (x *XImportMissionControlRequest) String() string {
+func (x *RouteFeeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*XImportMissionControlRequest) ProtoMessage() {}
+func (*RouteFeeRequest) ProtoMessage() {}
 
-func (x *XImportMissionControlRequest) ProtoReflect() protoreflect.Message {
+func (x *RouteFeeRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1332,33 +1607,68 @@ func (x *XImportMissionControlRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use XImportMissionControlRequest.ProtoReflect.Descriptor instead.
-func (*XImportMissionControlRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RouteFeeRequest.ProtoReflect.Descriptor instead.
+func (*RouteFeeRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *XImportMissionControlRequest) GetPairs() []*PairHistory {
+func (x *RouteFeeRequest) GetDest() []byte {
 	if x != nil {
-		return x.Pairs
+		return x.Dest
 	}
 	return nil
 }
 
-func (x *XImportMissionControlRequest) GetForce() bool {
+func (x *RouteFeeRequest) GetAmtSat() int64 {
 	if x != nil {
-		return x.Force
+		return x.AmtSat
+	}
+	return 0
+}
+
+func (x *RouteFeeRequest) GetPaymentRequest() string {
+	if x != nil {
+		return x.PaymentRequest
+	}
+	return ""
+}
+
+func (x *RouteFeeRequest) GetTimeout() uint32 {
+	if x != nil {
+		return x.Timeout
+	}
+	return 0
+}
+
+func (x *RouteFeeRequest) GetSimulate() bool {
+	if x != nil {
+		return x.Simulate
 	}
 	return false
 }
 
-type XImportMissionControlResponse struct {
+type RouteFeeResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// A lower bound of the estimated fee to the target destination within the
+	// network, expressed in milli-satoshis.
+	RoutingFeeMsat int64 `protobuf:"varint,1,opt,name=routing_fee_msat,json=routingFeeMsat,proto3" json:"routing_fee_msat,omitempty"`
+	// An estimate of the worst case time delay that can occur. Note that callers
+	// will still need to factor in the final CLTV delta of the last hop into this
+	// value.
+	TimeLockDelay int64 `protobuf:"varint,2,opt,name=time_lock_delay,json=timeLockDelay,proto3" json:"time_lock_delay,omitempty"`
+	// An indication whether a probing payment succeeded or whether and why it
+	// failed. FAILURE_REASON_NONE indicates success.
+	FailureReason lnrpc.PaymentFailureReason `protobuf:"varint,5,opt,name=failure_reason,json=failureReason,proto3,enum=lnrpc.PaymentFailureReason" json:"failure_reason,omitempty"`
+	// The estimated probability that all shards of the payment succeed on their
+	// first attempt. Only set if the payment was simulated.
+	SuccessProb float64 `protobuf:"fixed64,6,opt,name=success_prob,json=successProb,proto3" json:"success_prob,omitempty"`
 }
 
-func (x *XImportMissionControlResponse) Reset() {
-	*x = XImportMissionControlResponse{}
+func (x *RouteFeeResponse) Reset() {
+	*x = RouteFeeResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1366,13 +1676,13 @@ func (x *XImportMissionControlResponse) Reset() {
 	}
 }
 
-func (x *XImportMissionControlResponse) String() string {
+func (x *RouteFeeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*XImportMissionControlResponse) ProtoMessage() {}
+func (*RouteFeeResponse) ProtoMessage() {}
 
-func (x *XImportMissionControlResponse) ProtoReflect() protoreflect.Message {
+func (x *RouteFeeResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1384,26 +1694,63 @@ func (x *XImportMissionControlResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use XImportMissionControlResponse.ProtoReflect.Descriptor instead.
-func (*XImportMissionControlResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use RouteFeeResponse.ProtoReflect.Descriptor instead.
+func (*RouteFeeResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{12}
 }
 
-// PairHistory contains the mission control state for a particular node pair.
-type PairHistory struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *RouteFeeResponse) GetRoutingFeeMsat() int64 {
+	if x != nil {
+		return x.RoutingFeeMsat
+	}
+	return 0
+}
 
-	// The source node pubkey of the pair.
-	NodeFrom []byte `protobuf:"bytes,1,opt,name=node_from,json=nodeFrom,proto3" json:"node_from,omitempty"`
-	// The destination node pubkey of the pair.
-	NodeTo  []byte    `protobuf:"bytes,2,opt,name=node_to,json=nodeTo,proto3" json:"node_to,omitempty"`
-	History *PairData `protobuf:"bytes,7,opt,name=history,proto3" json:"history,omitempty"`
+func (x *RouteFeeResponse) GetTimeLockDelay() int64 {
+	if x != nil {
+		return x.TimeLockDelay
+	}
+	return 0
 }
 
-func (x *PairHistory) Reset() {
-	*x = PairHistory{}
+func (x *RouteFeeResponse) GetFailureReason() lnrpc.PaymentFailureReason {
+	if x != nil {
+		return x.FailureReason
+	}
+	return lnrpc.PaymentFailureReason(0)
+}
+
+func (x *RouteFeeResponse) GetSuccessProb() float64 {
+	if x != nil {
+		return x.SuccessProb
+	}
+	return 0
+}
+
+type SendToRouteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The payment hash to use for the HTLC.
+	PaymentHash []byte `protobuf:"bytes,1,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	// Route that should be used to attempt to complete the payment.
+	Route *lnrpc.Route `protobuf:"bytes,2,opt,name=route,proto3" json:"route,omitempty"`
+	// Whether the payment should be marked as failed when a temporary error is
+	// returned from the given route. Set it to true so the payment won't be
+	// failed unless a terminal error is occurred, such as payment timeout, no
+	// routes, incorrect payment details, or insufficient funds.
+	SkipTempErr bool `protobuf:"varint,3,opt,name=skip_temp_err,json=skipTempErr,proto3" json:"skip_temp_err,omitempty"`
+	// An optional field that can be used to pass an arbitrary set of TLV records
+	// to the first hop peer of this payment. This can be used to pass application
+	// specific data during the payment attempt. Record types are required to be in
+	// the custom range >= 65536. When using REST, the values must be encoded as
+	// base64.
+	FirstHopCustomRecords map[uint64][]byte `protobuf:"bytes,4,rep,name=first_hop_custom_records,json=firstHopCustomRecords,proto3" json:"first_hop_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *SendToRouteRequest) Reset() {
+	*x = SendToRouteRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1411,13 +1758,13 @@ func (x *PairHistory) Reset() {
 	}
 }
 
-func (x *PairHistory) String() string {
+func (x *SendToRouteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PairHistory) ProtoMessage() {}
+func (*SendToRouteRequest) ProtoMessage() {}
 
-func (x *PairHistory) ProtoReflect() protoreflect.Message {
+func (x *SendToRouteRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1429,55 +1776,52 @@ func (x *PairHistory) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PairHistory.ProtoReflect.Descriptor instead.
-func (*PairHistory) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendToRouteRequest.ProtoReflect.Descriptor instead.
+func (*SendToRouteRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *PairHistory) GetNodeFrom() []byte {
+func (x *SendToRouteRequest) GetPaymentHash() []byte {
 	if x != nil {
-		return x.NodeFrom
+		return x.PaymentHash
 	}
 	return nil
 }
 
-func (x *PairHistory) GetNodeTo() []byte {
+func (x *SendToRouteRequest) GetRoute() *lnrpc.Route {
 	if x != nil {
-		return x.NodeTo
+		return x.Route
 	}
 	return nil
 }
 
-func (x *PairHistory) GetHistory() *PairData {
+func (x *SendToRouteRequest) GetSkipTempErr() bool {
 	if x != nil {
-		return x.History
+		return x.SkipTempErr
+	}
+	return false
+}
+
+func (x *SendToRouteRequest) GetFirstHopCustomRecords() map[uint64][]byte {
+	if x != nil {
+		return x.FirstHopCustomRecords
 	}
 	return nil
 }
 
-type PairData struct {
+type SendToRouteResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Time of last failure.
-	FailTime int64 `protobuf:"varint,1,opt,name=fail_time,json=failTime,proto3" json:"fail_time,omitempty"`
-	// Lowest amount that failed to forward rounded to whole sats. This may be
-	// set to zero if the failure is independent of amount.
-	FailAmtSat int64 `protobuf:"varint,2,opt,name=fail_amt_sat,json=failAmtSat,proto3" json:"fail_amt_sat,omitempty"`
-	// Lowest amount that failed to forward in millisats. This may be
-	// set to zero if the failure is independent of amount.
-	FailAmtMsat int64 `protobuf:"varint,4,opt,name=fail_amt_msat,json=failAmtMsat,proto3" json:"fail_amt_msat,omitempty"`
-	// Time of last success.
-	SuccessTime int64 `protobuf:"varint,5,opt,name=success_time,json=successTime,proto3" json:"success_time,omitempty"`
-	// Highest amount that we could successfully forward rounded to whole sats.
-	SuccessAmtSat int64 `protobuf:"varint,6,opt,name=success_amt_sat,json=successAmtSat,proto3" json:"success_amt_sat,omitempty"`
-	// Highest amount that we could successfully forward in millisats.
-	SuccessAmtMsat int64 `protobuf:"varint,7,opt,name=success_amt_msat,json=successAmtMsat,proto3" json:"success_amt_msat,omitempty"`
+	// The preimage obtained by making the payment.
+	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// The failure message in case the payment failed.
+	Failure *lnrpc.Failure `protobuf:"bytes,2,opt,name=failure,proto3" json:"failure,omitempty"`
 }
 
-func (x *PairData) Reset() {
-	*x = PairData{}
+func (x *SendToRouteResponse) Reset() {
+	*x = SendToRouteResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1485,13 +1829,13 @@ func (x *PairData) Reset() {
 	}
 }
 
-func (x *PairData) String() string {
+func (x *SendToRouteResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PairData) ProtoMessage() {}
+func (*SendToRouteResponse) ProtoMessage() {}
 
-func (x *PairData) ProtoReflect() protoreflect.Message {
+func (x *SendToRouteResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1503,61 +1847,33 @@ func (x *PairData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PairData.ProtoReflect.Descriptor instead.
-func (*PairData) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendToRouteResponse.ProtoReflect.Descriptor instead.
+func (*SendToRouteResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *PairData) GetFailTime() int64 {
-	if x != nil {
-		return x.FailTime
-	}
-	return 0
-}
-
-func (x *PairData) GetFailAmtSat() int64 {
-	if x != nil {
-		return x.FailAmtSat
-	}
-	return 0
-}
-
-func (x *PairData) GetFailAmtMsat() int64 {
-	if x != nil {
-		return x.FailAmtMsat
-	}
-	return 0
-}
-
-func (x *PairData) GetSuccessTime() int64 {
-	if x != nil {
-		return x.SuccessTime
-	}
-	return 0
-}
-
-func (x *PairData) GetSuccessAmtSat() int64 {
+func (x *SendToRouteResponse) GetPreimage() []byte {
 	if x != nil {
-		return x.SuccessAmtSat
+		return x.Preimage
 	}
-	return 0
+	return nil
 }
 
-func (x *PairData) GetSuccessAmtMsat() int64 {
+func (x *SendToRouteResponse) GetFailure() *lnrpc.Failure {
 	if x != nil {
-		return x.SuccessAmtMsat
+		return x.Failure
 	}
-	return 0
+	return nil
 }
 
-type GetMissionControlConfigRequest struct {
+type ResetMissionControlRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *GetMissionControlConfigRequest) Reset() {
-	*x = GetMissionControlConfigRequest{}
+func (x *ResetMissionControlRequest) Reset() {
+	*x = ResetMissionControlRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1565,13 +1881,13 @@ func (x *GetMissionControlConfigRequest) Reset() {
 	}
 }
 
-func (x *GetMissionControlConfigRequest) String() string {
+func (x *ResetMissionControlRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMissionControlConfigRequest) ProtoMessage() {}
+func (*ResetMissionControlRequest) ProtoMessage() {}
 
-func (x *GetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
+func (x *ResetMissionControlRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1583,22 +1899,19 @@ func (x *GetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMissionControlConfigRequest.ProtoReflect.Descriptor instead.
-func (*GetMissionControlConfigRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResetMissionControlRequest.ProtoReflect.Descriptor instead.
+func (*ResetMissionControlRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{15}
 }
 
-type GetMissionControlConfigResponse struct {
+type ResetMissionControlResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// Mission control's currently active config.
-	Config *MissionControlConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 }
 
-func (x *GetMissionControlConfigResponse) Reset() {
-	*x = GetMissionControlConfigResponse{}
+func (x *ResetMissionControlResponse) Reset() {
+	*x = ResetMissionControlResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1606,13 +1919,13 @@ func (x *GetMissionControlConfigResponse) Reset() {
 	}
 }
 
-func (x *GetMissionControlConfigResponse) String() string {
+func (x *ResetMissionControlResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMissionControlConfigResponse) ProtoMessage() {}
+func (*ResetMissionControlResponse) ProtoMessage() {}
 
-func (x *GetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
+func (x *ResetMissionControlResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1624,30 +1937,19 @@ func (x *GetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMissionControlConfigResponse.ProtoReflect.Descriptor instead.
-func (*GetMissionControlConfigResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResetMissionControlResponse.ProtoReflect.Descriptor instead.
+func (*ResetMissionControlResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *GetMissionControlConfigResponse) GetConfig() *MissionControlConfig {
-	if x != nil {
-		return x.Config
-	}
-	return nil
-}
-
-type SetMissionControlConfigRequest struct {
+type QueryMissionControlRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// The config to set for mission control. Note that all values *must* be set,
-	// because the full config will be applied.
-	Config *MissionControlConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 }
 
-func (x *SetMissionControlConfigRequest) Reset() {
-	*x = SetMissionControlConfigRequest{}
+func (x *QueryMissionControlRequest) Reset() {
+	*x = QueryMissionControlRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1655,13 +1957,13 @@ func (x *SetMissionControlConfigRequest) Reset() {
 	}
 }
 
-func (x *SetMissionControlConfigRequest) String() string {
+func (x *QueryMissionControlRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetMissionControlConfigRequest) ProtoMessage() {}
+func (*QueryMissionControlRequest) ProtoMessage() {}
 
-func (x *SetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
+func (x *QueryMissionControlRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1673,26 +1975,23 @@ func (x *SetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetMissionControlConfigRequest.ProtoReflect.Descriptor instead.
-func (*SetMissionControlConfigRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryMissionControlRequest.ProtoReflect.Descriptor instead.
+func (*QueryMissionControlRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *SetMissionControlConfigRequest) GetConfig() *MissionControlConfig {
-	if x != nil {
-		return x.Config
-	}
-	return nil
-}
-
-type SetMissionControlConfigResponse struct {
+// QueryMissionControlResponse contains mission control state.
+type QueryMissionControlResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// Node pair-level mission control state.
+	Pairs []*PairHistory `protobuf:"bytes,2,rep,name=pairs,proto3" json:"pairs,omitempty"`
 }
 
-func (x *SetMissionControlConfigResponse) Reset() {
-	*x = SetMissionControlConfigResponse{}
+func (x *QueryMissionControlResponse) Reset() {
+	*x = QueryMissionControlResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[18]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1700,13 +1999,13 @@ func (x *SetMissionControlConfigResponse) Reset() {
 	}
 }
 
-func (x *SetMissionControlConfigResponse) String() string {
+func (x *QueryMissionControlResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetMissionControlConfigResponse) ProtoMessage() {}
+func (*QueryMissionControlResponse) ProtoMessage() {}
 
-func (x *SetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
+func (x *QueryMissionControlResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[18]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1718,60 +2017,33 @@ func (x *SetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetMissionControlConfigResponse.ProtoReflect.Descriptor instead.
-func (*SetMissionControlConfigResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryMissionControlResponse.ProtoReflect.Descriptor instead.
+func (*QueryMissionControlResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{18}
 }
 
-type MissionControlConfig struct {
+func (x *QueryMissionControlResponse) GetPairs() []*PairHistory {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+type XImportMissionControlRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Deprecated, use AprioriParameters. The amount of time mission control will
-	// take to restore a penalized node or channel back to 50% success probability,
-	// expressed in seconds. Setting this value to a higher value will penalize
-	// failures for longer, making mission control less likely to route through
-	// nodes and channels that we have previously recorded failures for.
-	//
-	// Deprecated: Marked as deprecated in routerrpc/router.proto.
-	HalfLifeSeconds uint64 `protobuf:"varint,1,opt,name=half_life_seconds,json=halfLifeSeconds,proto3" json:"half_life_seconds,omitempty"`
-	// Deprecated, use AprioriParameters. The probability of success mission
-	// control should assign to hop in a route where it has no other information
-	// available. Higher values will make mission control more willing to try hops
-	// that we have no information about, lower values will discourage trying these
-	// hops.
-	//
-	// Deprecated: Marked as deprecated in routerrpc/router.proto.
-	HopProbability float32 `protobuf:"fixed32,2,opt,name=hop_probability,json=hopProbability,proto3" json:"hop_probability,omitempty"`
-	// Deprecated, use AprioriParameters. The importance that mission control
-	// should place on historical results, expressed as a value in [0;1]. Setting
-	// this value to 1 will ignore all historical payments and just use the hop
-	// probability to assess the probability of success for each hop. A zero value
-	// ignores hop probability completely and relies entirely on historical
-	// results, unless none are available.
-	//
-	// Deprecated: Marked as deprecated in routerrpc/router.proto.
-	Weight float32 `protobuf:"fixed32,3,opt,name=weight,proto3" json:"weight,omitempty"`
-	// The maximum number of payment results that mission control will store.
-	MaximumPaymentResults uint32 `protobuf:"varint,4,opt,name=maximum_payment_results,json=maximumPaymentResults,proto3" json:"maximum_payment_results,omitempty"`
-	// The minimum time that must have passed since the previously recorded failure
-	// before we raise the failure amount.
-	MinimumFailureRelaxInterval uint64 `protobuf:"varint,5,opt,name=minimum_failure_relax_interval,json=minimumFailureRelaxInterval,proto3" json:"minimum_failure_relax_interval,omitempty"`
-	// ProbabilityModel defines which probability estimator should be used in
-	// pathfinding. Note that the bimodal estimator is experimental.
-	Model MissionControlConfig_ProbabilityModel `protobuf:"varint,6,opt,name=model,proto3,enum=routerrpc.MissionControlConfig_ProbabilityModel" json:"model,omitempty"`
-	// EstimatorConfig is populated dependent on the estimator type.
-	//
-	// Types that are assignable to EstimatorConfig:
-	//
-	//	*MissionControlConfig_Apriori
-	//	*MissionControlConfig_Bimodal
-	EstimatorConfig isMissionControlConfig_EstimatorConfig `protobuf_oneof:"EstimatorConfig"`
+	// Node pair-level mission control state to be imported.
+	Pairs []*PairHistory `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	// Whether to force override MC pair history. Note that even with force
+	// override the failure pair is imported before the success pair and both
+	// still clamp existing failure/success amounts.
+	Force bool `protobuf:"varint,2,opt,name=force,proto3" json:"force,omitempty"`
 }
 
-func (x *MissionControlConfig) Reset() {
-	*x = MissionControlConfig{}
+func (x *XImportMissionControlRequest) Reset() {
+	*x = XImportMissionControlRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[19]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1779,13 +2051,13 @@ func (x *MissionControlConfig) Reset() {
 	}
 }
 
-func (x *MissionControlConfig) String() string {
+func (x *XImportMissionControlRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MissionControlConfig) ProtoMessage() {}
+func (*XImportMissionControlRequest) ProtoMessage() {}
 
-func (x *MissionControlConfig) ProtoReflect() protoreflect.Message {
+func (x *XImportMissionControlRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[19]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1797,118 +2069,33 @@ func (x *MissionControlConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MissionControlConfig.ProtoReflect.Descriptor instead.
-func (*MissionControlConfig) Descriptor() ([]byte, []int) {
+// Deprecated: Use XImportMissionControlRequest.ProtoReflect.Descriptor instead.
+func (*XImportMissionControlRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{19}
 }
 
-// Deprecated: Marked as deprecated in routerrpc/router.proto.
-func (x *MissionControlConfig) GetHalfLifeSeconds() uint64 {
-	if x != nil {
-		return x.HalfLifeSeconds
-	}
-	return 0
-}
-
-// Deprecated: Marked as deprecated in routerrpc/router.proto.
-func (x *MissionControlConfig) GetHopProbability() float32 {
-	if x != nil {
-		return x.HopProbability
-	}
-	return 0
-}
-
-// Deprecated: Marked as deprecated in routerrpc/router.proto.
-func (x *MissionControlConfig) GetWeight() float32 {
-	if x != nil {
-		return x.Weight
-	}
-	return 0
-}
-
-func (x *MissionControlConfig) GetMaximumPaymentResults() uint32 {
-	if x != nil {
-		return x.MaximumPaymentResults
-	}
-	return 0
-}
-
-func (x *MissionControlConfig) GetMinimumFailureRelaxInterval() uint64 {
-	if x != nil {
-		return x.MinimumFailureRelaxInterval
-	}
-	return 0
-}
-
-func (x *MissionControlConfig) GetModel() MissionControlConfig_ProbabilityModel {
+func (x *XImportMissionControlRequest) GetPairs() []*PairHistory {
 	if x != nil {
-		return x.Model
-	}
-	return MissionControlConfig_APRIORI
-}
-
-func (m *MissionControlConfig) GetEstimatorConfig() isMissionControlConfig_EstimatorConfig {
-	if m != nil {
-		return m.EstimatorConfig
-	}
-	return nil
-}
-
-func (x *MissionControlConfig) GetApriori() *AprioriParameters {
-	if x, ok := x.GetEstimatorConfig().(*MissionControlConfig_Apriori); ok {
-		return x.Apriori
+		return x.Pairs
 	}
 	return nil
 }
 
-func (x *MissionControlConfig) GetBimodal() *BimodalParameters {
-	if x, ok := x.GetEstimatorConfig().(*MissionControlConfig_Bimodal); ok {
-		return x.Bimodal
+func (x *XImportMissionControlRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
 	}
-	return nil
-}
-
-type isMissionControlConfig_EstimatorConfig interface {
-	isMissionControlConfig_EstimatorConfig()
-}
-
-type MissionControlConfig_Apriori struct {
-	Apriori *AprioriParameters `protobuf:"bytes,7,opt,name=apriori,proto3,oneof"`
-}
-
-type MissionControlConfig_Bimodal struct {
-	Bimodal *BimodalParameters `protobuf:"bytes,8,opt,name=bimodal,proto3,oneof"`
+	return false
 }
 
-func (*MissionControlConfig_Apriori) isMissionControlConfig_EstimatorConfig() {}
-
-func (*MissionControlConfig_Bimodal) isMissionControlConfig_EstimatorConfig() {}
-
-type BimodalParameters struct {
+type XImportMissionControlResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// NodeWeight defines how strongly other previous forwardings on channels of a
-	// router should be taken into account when computing a channel's probability
-	// to route. The allowed values are in the range [0, 1], where a value of 0
-	// means that only direct information about a channel is taken into account.
-	NodeWeight float64 `protobuf:"fixed64,1,opt,name=node_weight,json=nodeWeight,proto3" json:"node_weight,omitempty"`
-	// ScaleMsat describes the scale over which channels statistically have some
-	// liquidity left. The value determines how quickly the bimodal distribution
-	// drops off from the edges of a channel. A larger value (compared to typical
-	// channel capacities) means that the drop off is slow and that channel
-	// balances are distributed more uniformly. A small value leads to the
-	// assumption of very unbalanced channels.
-	ScaleMsat uint64 `protobuf:"varint,2,opt,name=scale_msat,json=scaleMsat,proto3" json:"scale_msat,omitempty"`
-	// DecayTime describes the information decay of knowledge about previous
-	// successes and failures in channels. The smaller the decay time, the quicker
-	// we forget about past forwardings.
-	DecayTime uint64 `protobuf:"varint,3,opt,name=decay_time,json=decayTime,proto3" json:"decay_time,omitempty"`
 }
 
-func (x *BimodalParameters) Reset() {
-	*x = BimodalParameters{}
+func (x *XImportMissionControlResponse) Reset() {
+	*x = XImportMissionControlResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[20]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1916,13 +2103,13 @@ func (x *BimodalParameters) Reset() {
 	}
 }
 
-func (x *BimodalParameters) String() string {
+func (x *XImportMissionControlResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BimodalParameters) ProtoMessage() {}
+func (*XImportMissionControlResponse) ProtoMessage() {}
 
-func (x *BimodalParameters) ProtoReflect() protoreflect.Message {
+func (x *XImportMissionControlResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[20]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1934,64 +2121,26 @@ func (x *BimodalParameters) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BimodalParameters.ProtoReflect.Descriptor instead.
-func (*BimodalParameters) Descriptor() ([]byte, []int) {
+// Deprecated: Use XImportMissionControlResponse.ProtoReflect.Descriptor instead.
+func (*XImportMissionControlResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *BimodalParameters) GetNodeWeight() float64 {
-	if x != nil {
-		return x.NodeWeight
-	}
-	return 0
-}
-
-func (x *BimodalParameters) GetScaleMsat() uint64 {
-	if x != nil {
-		return x.ScaleMsat
-	}
-	return 0
-}
-
-func (x *BimodalParameters) GetDecayTime() uint64 {
-	if x != nil {
-		return x.DecayTime
-	}
-	return 0
-}
-
-type AprioriParameters struct {
+// PairHistory contains the mission control state for a particular node pair.
+type PairHistory struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The amount of time mission control will take to restore a penalized node
-	// or channel back to 50% success probability, expressed in seconds. Setting
-	// this value to a higher value will penalize failures for longer, making
-	// mission control less likely to route through nodes and channels that we
-	// have previously recorded failures for.
-	HalfLifeSeconds uint64 `protobuf:"varint,1,opt,name=half_life_seconds,json=halfLifeSeconds,proto3" json:"half_life_seconds,omitempty"`
-	// The probability of success mission control should assign to hop in a route
-	// where it has no other information available. Higher values will make mission
-	// control more willing to try hops that we have no information about, lower
-	// values will discourage trying these hops.
-	HopProbability float64 `protobuf:"fixed64,2,opt,name=hop_probability,json=hopProbability,proto3" json:"hop_probability,omitempty"`
-	// The importance that mission control should place on historical results,
-	// expressed as a value in [0;1]. Setting this value to 1 will ignore all
-	// historical payments and just use the hop probability to assess the
-	// probability of success for each hop. A zero value ignores hop probability
-	// completely and relies entirely on historical results, unless none are
-	// available.
-	Weight float64 `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
-	// The fraction of a channel's capacity that we consider to have liquidity. For
-	// amounts that come close to or exceed the fraction, an additional penalty is
-	// applied. A value of 1.0 disables the capacity factor. Allowed values are in
-	// [0.75, 1.0].
-	CapacityFraction float64 `protobuf:"fixed64,4,opt,name=capacity_fraction,json=capacityFraction,proto3" json:"capacity_fraction,omitempty"`
+	// The source node pubkey of the pair.
+	NodeFrom []byte `protobuf:"bytes,1,opt,name=node_from,json=nodeFrom,proto3" json:"node_from,omitempty"`
+	// The destination node pubkey of the pair.
+	NodeTo  []byte    `protobuf:"bytes,2,opt,name=node_to,json=nodeTo,proto3" json:"node_to,omitempty"`
+	History *PairData `protobuf:"bytes,7,opt,name=history,proto3" json:"history,omitempty"`
 }
 
-func (x *AprioriParameters) Reset() {
-	*x = AprioriParameters{}
+func (x *PairHistory) Reset() {
+	*x = PairHistory{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[21]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1999,13 +2148,13 @@ func (x *AprioriParameters) Reset() {
 	}
 }
 
-func (x *AprioriParameters) String() string {
+func (x *PairHistory) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AprioriParameters) ProtoMessage() {}
+func (*PairHistory) ProtoMessage() {}
 
-func (x *AprioriParameters) ProtoReflect() protoreflect.Message {
+func (x *PairHistory) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[21]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2017,54 +2166,55 @@ func (x *AprioriParameters) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AprioriParameters.ProtoReflect.Descriptor instead.
-func (*AprioriParameters) Descriptor() ([]byte, []int) {
+// Deprecated: Use PairHistory.ProtoReflect.Descriptor instead.
+func (*PairHistory) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *AprioriParameters) GetHalfLifeSeconds() uint64 {
+func (x *PairHistory) GetNodeFrom() []byte {
 	if x != nil {
-		return x.HalfLifeSeconds
+		return x.NodeFrom
 	}
-	return 0
+	return nil
 }
 
-func (x *AprioriParameters) GetHopProbability() float64 {
+func (x *PairHistory) GetNodeTo() []byte {
 	if x != nil {
-		return x.HopProbability
+		return x.NodeTo
 	}
-	return 0
+	return nil
 }
 
-func (x *AprioriParameters) GetWeight() float64 {
+func (x *PairHistory) GetHistory() *PairData {
 	if x != nil {
-		return x.Weight
+		return x.History
 	}
-	return 0
+	return nil
 }
 
-func (x *AprioriParameters) GetCapacityFraction() float64 {
-	if x != nil {
-		return x.CapacityFraction
-	}
-	return 0
-}
-
-type QueryProbabilityRequest struct {
+type PairData struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The source node pubkey of the pair.
-	FromNode []byte `protobuf:"bytes,1,opt,name=from_node,json=fromNode,proto3" json:"from_node,omitempty"`
-	// The destination node pubkey of the pair.
-	ToNode []byte `protobuf:"bytes,2,opt,name=to_node,json=toNode,proto3" json:"to_node,omitempty"`
-	// The amount for which to calculate a probability.
-	AmtMsat int64 `protobuf:"varint,3,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
+	// Time of last failure.
+	FailTime int64 `protobuf:"varint,1,opt,name=fail_time,json=failTime,proto3" json:"fail_time,omitempty"`
+	// Lowest amount that failed to forward rounded to whole sats. This may be
+	// set to zero if the failure is independent of amount.
+	FailAmtSat int64 `protobuf:"varint,2,opt,name=fail_amt_sat,json=failAmtSat,proto3" json:"fail_amt_sat,omitempty"`
+	// Lowest amount that failed to forward in millisats. This may be
+	// set to zero if the failure is independent of amount.
+	FailAmtMsat int64 `protobuf:"varint,4,opt,name=fail_amt_msat,json=failAmtMsat,proto3" json:"fail_amt_msat,omitempty"`
+	// Time of last success.
+	SuccessTime int64 `protobuf:"varint,5,opt,name=success_time,json=successTime,proto3" json:"success_time,omitempty"`
+	// Highest amount that we could successfully forward rounded to whole sats.
+	SuccessAmtSat int64 `protobuf:"varint,6,opt,name=success_amt_sat,json=successAmtSat,proto3" json:"success_amt_sat,omitempty"`
+	// Highest amount that we could successfully forward in millisats.
+	SuccessAmtMsat int64 `protobuf:"varint,7,opt,name=success_amt_msat,json=successAmtMsat,proto3" json:"success_amt_msat,omitempty"`
 }
 
-func (x *QueryProbabilityRequest) Reset() {
-	*x = QueryProbabilityRequest{}
+func (x *PairData) Reset() {
+	*x = PairData{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[22]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2072,13 +2222,13 @@ func (x *QueryProbabilityRequest) Reset() {
 	}
 }
 
-func (x *QueryProbabilityRequest) String() string {
+func (x *PairData) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryProbabilityRequest) ProtoMessage() {}
+func (*PairData) ProtoMessage() {}
 
-func (x *QueryProbabilityRequest) ProtoReflect() protoreflect.Message {
+func (x *PairData) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[22]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2090,45 +2240,61 @@ func (x *QueryProbabilityRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryProbabilityRequest.ProtoReflect.Descriptor instead.
-func (*QueryProbabilityRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PairData.ProtoReflect.Descriptor instead.
+func (*PairData) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *QueryProbabilityRequest) GetFromNode() []byte {
+func (x *PairData) GetFailTime() int64 {
 	if x != nil {
-		return x.FromNode
+		return x.FailTime
 	}
-	return nil
+	return 0
 }
 
-func (x *QueryProbabilityRequest) GetToNode() []byte {
+func (x *PairData) GetFailAmtSat() int64 {
 	if x != nil {
-		return x.ToNode
+		return x.FailAmtSat
 	}
-	return nil
+	return 0
 }
 
-func (x *QueryProbabilityRequest) GetAmtMsat() int64 {
+func (x *PairData) GetFailAmtMsat() int64 {
 	if x != nil {
-		return x.AmtMsat
+		return x.FailAmtMsat
 	}
 	return 0
 }
 
-type QueryProbabilityResponse struct {
+func (x *PairData) GetSuccessTime() int64 {
+	if x != nil {
+		return x.SuccessTime
+	}
+	return 0
+}
+
+func (x *PairData) GetSuccessAmtSat() int64 {
+	if x != nil {
+		return x.SuccessAmtSat
+	}
+	return 0
+}
+
+func (x *PairData) GetSuccessAmtMsat() int64 {
+	if x != nil {
+		return x.SuccessAmtMsat
+	}
+	return 0
+}
+
+type GetMissionControlConfigRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	// The success probability for the requested pair.
-	Probability float64 `protobuf:"fixed64,1,opt,name=probability,proto3" json:"probability,omitempty"`
-	// The historical data for the requested pair.
-	History *PairData `protobuf:"bytes,2,opt,name=history,proto3" json:"history,omitempty"`
 }
 
-func (x *QueryProbabilityResponse) Reset() {
-	*x = QueryProbabilityResponse{}
+func (x *GetMissionControlConfigRequest) Reset() {
+	*x = GetMissionControlConfigRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[23]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2136,13 +2302,13 @@ func (x *QueryProbabilityResponse) Reset() {
 	}
 }
 
-func (x *QueryProbabilityResponse) String() string {
+func (x *GetMissionControlConfigRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*QueryProbabilityResponse) ProtoMessage() {}
+func (*GetMissionControlConfigRequest) ProtoMessage() {}
 
-func (x *QueryProbabilityResponse) ProtoReflect() protoreflect.Message {
+func (x *GetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[23]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2154,55 +2320,22 @@ func (x *QueryProbabilityResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use QueryProbabilityResponse.ProtoReflect.Descriptor instead.
-func (*QueryProbabilityResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetMissionControlConfigRequest.ProtoReflect.Descriptor instead.
+func (*GetMissionControlConfigRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *QueryProbabilityResponse) GetProbability() float64 {
-	if x != nil {
-		return x.Probability
-	}
-	return 0
-}
-
-func (x *QueryProbabilityResponse) GetHistory() *PairData {
-	if x != nil {
-		return x.History
-	}
-	return nil
-}
-
-type BuildRouteRequest struct {
+type GetMissionControlConfigResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The amount to send expressed in msat. If set to zero, the minimum routable
-	// amount is used.
-	AmtMsat int64 `protobuf:"varint,1,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
-	// CLTV delta from the current height that should be used for the timelock
-	// of the final hop
-	FinalCltvDelta int32 `protobuf:"varint,2,opt,name=final_cltv_delta,json=finalCltvDelta,proto3" json:"final_cltv_delta,omitempty"`
-	// The channel id of the channel that must be taken to the first hop. If zero,
-	// any channel may be used.
-	OutgoingChanId uint64 `protobuf:"varint,3,opt,name=outgoing_chan_id,json=outgoingChanId,proto3" json:"outgoing_chan_id,omitempty"`
-	// A list of hops that defines the route. This does not include the source hop
-	// pubkey.
-	HopPubkeys [][]byte `protobuf:"bytes,4,rep,name=hop_pubkeys,json=hopPubkeys,proto3" json:"hop_pubkeys,omitempty"`
-	// An optional payment addr to be included within the last hop of the route.
-	// This is also called payment secret in specifications (e.g. BOLT 11).
-	PaymentAddr []byte `protobuf:"bytes,5,opt,name=payment_addr,json=paymentAddr,proto3" json:"payment_addr,omitempty"`
-	// An optional field that can be used to pass an arbitrary set of TLV records
-	// to the first hop peer of this payment. This can be used to pass application
-	// specific data during the payment attempt. Record types are required to be in
-	// the custom range >= 65536. When using REST, the values must be encoded as
-	// base64.
-	FirstHopCustomRecords map[uint64][]byte `protobuf:"bytes,6,rep,name=first_hop_custom_records,json=firstHopCustomRecords,proto3" json:"first_hop_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Mission control's currently active config.
+	Config *MissionControlConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 }
 
-func (x *BuildRouteRequest) Reset() {
-	*x = BuildRouteRequest{}
+func (x *GetMissionControlConfigResponse) Reset() {
+	*x = GetMissionControlConfigResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[24]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2210,13 +2343,13 @@ func (x *BuildRouteRequest) Reset() {
 	}
 }
 
-func (x *BuildRouteRequest) String() string {
+func (x *GetMissionControlConfigResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuildRouteRequest) ProtoMessage() {}
+func (*GetMissionControlConfigResponse) ProtoMessage() {}
 
-func (x *BuildRouteRequest) ProtoReflect() protoreflect.Message {
+func (x *GetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[24]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2228,64 +2361,30 @@ func (x *BuildRouteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuildRouteRequest.ProtoReflect.Descriptor instead.
-func (*BuildRouteRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetMissionControlConfigResponse.ProtoReflect.Descriptor instead.
+func (*GetMissionControlConfigResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *BuildRouteRequest) GetAmtMsat() int64 {
-	if x != nil {
-		return x.AmtMsat
-	}
-	return 0
-}
-
-func (x *BuildRouteRequest) GetFinalCltvDelta() int32 {
-	if x != nil {
-		return x.FinalCltvDelta
-	}
-	return 0
-}
-
-func (x *BuildRouteRequest) GetOutgoingChanId() uint64 {
-	if x != nil {
-		return x.OutgoingChanId
-	}
-	return 0
-}
-
-func (x *BuildRouteRequest) GetHopPubkeys() [][]byte {
-	if x != nil {
-		return x.HopPubkeys
-	}
-	return nil
-}
-
-func (x *BuildRouteRequest) GetPaymentAddr() []byte {
-	if x != nil {
-		return x.PaymentAddr
-	}
-	return nil
-}
-
-func (x *BuildRouteRequest) GetFirstHopCustomRecords() map[uint64][]byte {
+func (x *GetMissionControlConfigResponse) GetConfig() *MissionControlConfig {
 	if x != nil {
-		return x.FirstHopCustomRecords
+		return x.Config
 	}
 	return nil
 }
 
-type BuildRouteResponse struct {
+type SetMissionControlConfigRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Fully specified route that can be used to execute the payment.
-	Route *lnrpc.Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	// The config to set for mission control. Note that all values *must* be set,
+	// because the full config will be applied.
+	Config *MissionControlConfig `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
 }
 
-func (x *BuildRouteResponse) Reset() {
-	*x = BuildRouteResponse{}
+func (x *SetMissionControlConfigRequest) Reset() {
+	*x = SetMissionControlConfigRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[25]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2293,13 +2392,13 @@ func (x *BuildRouteResponse) Reset() {
 	}
 }
 
-func (x *BuildRouteResponse) String() string {
+func (x *SetMissionControlConfigRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BuildRouteResponse) ProtoMessage() {}
+func (*SetMissionControlConfigRequest) ProtoMessage() {}
 
-func (x *BuildRouteResponse) ProtoReflect() protoreflect.Message {
+func (x *SetMissionControlConfigRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[25]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2311,26 +2410,26 @@ func (x *BuildRouteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BuildRouteResponse.ProtoReflect.Descriptor instead.
-func (*BuildRouteResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetMissionControlConfigRequest.ProtoReflect.Descriptor instead.
+func (*SetMissionControlConfigRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *BuildRouteResponse) GetRoute() *lnrpc.Route {
+func (x *SetMissionControlConfigRequest) GetConfig() *MissionControlConfig {
 	if x != nil {
-		return x.Route
+		return x.Config
 	}
 	return nil
 }
 
-type SubscribeHtlcEventsRequest struct {
+type SetMissionControlConfigResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 }
 
-func (x *SubscribeHtlcEventsRequest) Reset() {
-	*x = SubscribeHtlcEventsRequest{}
+func (x *SetMissionControlConfigResponse) Reset() {
+	*x = SetMissionControlConfigResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[26]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2338,13 +2437,13 @@ func (x *SubscribeHtlcEventsRequest) Reset() {
 	}
 }
 
-func (x *SubscribeHtlcEventsRequest) String() string {
+func (x *SetMissionControlConfigResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscribeHtlcEventsRequest) ProtoMessage() {}
+func (*SetMissionControlConfigResponse) ProtoMessage() {}
 
-func (x *SubscribeHtlcEventsRequest) ProtoReflect() protoreflect.Message {
+func (x *SetMissionControlConfigResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[26]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2356,52 +2455,60 @@ func (x *SubscribeHtlcEventsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscribeHtlcEventsRequest.ProtoReflect.Descriptor instead.
-func (*SubscribeHtlcEventsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetMissionControlConfigResponse.ProtoReflect.Descriptor instead.
+func (*SetMissionControlConfigResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{26}
 }
 
-// HtlcEvent contains the htlc event that was processed. These are served on a
-// best-effort basis; events are not persisted, delivery is not guaranteed
-// (in the event of a crash in the switch, forward events may be lost) and
-// some events may be replayed upon restart. Events consumed from this package
-// should be de-duplicated by the htlc's unique combination of incoming and
-// outgoing channel id and htlc id. [EXPERIMENTAL]
-type HtlcEvent struct {
+type MissionControlConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The short channel id that the incoming htlc arrived at our node on. This
-	// value is zero for sends.
-	IncomingChannelId uint64 `protobuf:"varint,1,opt,name=incoming_channel_id,json=incomingChannelId,proto3" json:"incoming_channel_id,omitempty"`
-	// The short channel id that the outgoing htlc left our node on. This value
-	// is zero for receives.
-	OutgoingChannelId uint64 `protobuf:"varint,2,opt,name=outgoing_channel_id,json=outgoingChannelId,proto3" json:"outgoing_channel_id,omitempty"`
-	// Incoming id is the index of the incoming htlc in the incoming channel.
-	// This value is zero for sends.
-	IncomingHtlcId uint64 `protobuf:"varint,3,opt,name=incoming_htlc_id,json=incomingHtlcId,proto3" json:"incoming_htlc_id,omitempty"`
-	// Outgoing id is the index of the outgoing htlc in the outgoing channel.
-	// This value is zero for receives.
-	OutgoingHtlcId uint64 `protobuf:"varint,4,opt,name=outgoing_htlc_id,json=outgoingHtlcId,proto3" json:"outgoing_htlc_id,omitempty"`
-	// The time in unix nanoseconds that the event occurred.
-	TimestampNs uint64 `protobuf:"varint,5,opt,name=timestamp_ns,json=timestampNs,proto3" json:"timestamp_ns,omitempty"`
-	// The event type indicates whether the htlc was part of a send, receive or
-	// forward.
-	EventType HtlcEvent_EventType `protobuf:"varint,6,opt,name=event_type,json=eventType,proto3,enum=routerrpc.HtlcEvent_EventType" json:"event_type,omitempty"`
-	// Types that are assignable to Event:
-	//
-	//	*HtlcEvent_ForwardEvent
-	//	*HtlcEvent_ForwardFailEvent
-	//	*HtlcEvent_SettleEvent
-	//	*HtlcEvent_LinkFailEvent
-	//	*HtlcEvent_SubscribedEvent
-	//	*HtlcEvent_FinalHtlcEvent
-	Event isHtlcEvent_Event `protobuf_oneof:"event"`
+	// Deprecated, use AprioriParameters. The amount of time mission control will
+	// take to restore a penalized node or channel back to 50% success probability,
+	// expressed in seconds. Setting this value to a higher value will penalize
+	// failures for longer, making mission control less likely to route through
+	// nodes and channels that we have previously recorded failures for.
+	//
+	// Deprecated: Marked as deprecated in routerrpc/router.proto.
+	HalfLifeSeconds uint64 `protobuf:"varint,1,opt,name=half_life_seconds,json=halfLifeSeconds,proto3" json:"half_life_seconds,omitempty"`
+	// Deprecated, use AprioriParameters. The probability of success mission
+	// control should assign to hop in a route where it has no other information
+	// available. Higher values will make mission control more willing to try hops
+	// that we have no information about, lower values will discourage trying these
+	// hops.
+	//
+	// Deprecated: Marked as deprecated in routerrpc/router.proto.
+	HopProbability float32 `protobuf:"fixed32,2,opt,name=hop_probability,json=hopProbability,proto3" json:"hop_probability,omitempty"`
+	// Deprecated, use AprioriParameters. The importance that mission control
+	// should place on historical results, expressed as a value in [0;1]. Setting
+	// this value to 1 will ignore all historical payments and just use the hop
+	// probability to assess the probability of success for each hop. A zero value
+	// ignores hop probability completely and relies entirely on historical
+	// results, unless none are available.
+	//
+	// Deprecated: Marked as deprecated in routerrpc/router.proto.
+	Weight float32 `protobuf:"fixed32,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	// The maximum number of payment results that mission control will store.
+	MaximumPaymentResults uint32 `protobuf:"varint,4,opt,name=maximum_payment_results,json=maximumPaymentResults,proto3" json:"maximum_payment_results,omitempty"`
+	// The minimum time that must have passed since the previously recorded failure
+	// before we raise the failure amount.
+	MinimumFailureRelaxInterval uint64 `protobuf:"varint,5,opt,name=minimum_failure_relax_interval,json=minimumFailureRelaxInterval,proto3" json:"minimum_failure_relax_interval,omitempty"`
+	// ProbabilityModel defines which probability estimator should be used in
+	// pathfinding. Note that the bimodal estimator is experimental.
+	Model MissionControlConfig_ProbabilityModel `protobuf:"varint,6,opt,name=model,proto3,enum=routerrpc.MissionControlConfig_ProbabilityModel" json:"model,omitempty"`
+	// EstimatorConfig is populated dependent on the estimator type.
+	//
+	// Types that are assignable to EstimatorConfig:
+	//
+	//	*MissionControlConfig_Apriori
+	//	*MissionControlConfig_Bimodal
+	EstimatorConfig isMissionControlConfig_EstimatorConfig `protobuf_oneof:"EstimatorConfig"`
 }
 
-func (x *HtlcEvent) Reset() {
-	*x = HtlcEvent{}
+func (x *MissionControlConfig) Reset() {
+	*x = MissionControlConfig{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[27]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2409,13 +2516,13 @@ func (x *HtlcEvent) Reset() {
 	}
 }
 
-func (x *HtlcEvent) String() string {
+func (x *MissionControlConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HtlcEvent) ProtoMessage() {}
+func (*MissionControlConfig) ProtoMessage() {}
 
-func (x *HtlcEvent) ProtoReflect() protoreflect.Message {
+func (x *MissionControlConfig) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[27]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2427,159 +2534,118 @@ func (x *HtlcEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HtlcEvent.ProtoReflect.Descriptor instead.
-func (*HtlcEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use MissionControlConfig.ProtoReflect.Descriptor instead.
+func (*MissionControlConfig) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *HtlcEvent) GetIncomingChannelId() uint64 {
+// Deprecated: Marked as deprecated in routerrpc/router.proto.
+func (x *MissionControlConfig) GetHalfLifeSeconds() uint64 {
 	if x != nil {
-		return x.IncomingChannelId
+		return x.HalfLifeSeconds
 	}
 	return 0
 }
 
-func (x *HtlcEvent) GetOutgoingChannelId() uint64 {
+// Deprecated: Marked as deprecated in routerrpc/router.proto.
+func (x *MissionControlConfig) GetHopProbability() float32 {
 	if x != nil {
-		return x.OutgoingChannelId
+		return x.HopProbability
 	}
 	return 0
 }
 
-func (x *HtlcEvent) GetIncomingHtlcId() uint64 {
+// Deprecated: Marked as deprecated in routerrpc/router.proto.
+func (x *MissionControlConfig) GetWeight() float32 {
 	if x != nil {
-		return x.IncomingHtlcId
+		return x.Weight
 	}
 	return 0
 }
 
-func (x *HtlcEvent) GetOutgoingHtlcId() uint64 {
+func (x *MissionControlConfig) GetMaximumPaymentResults() uint32 {
 	if x != nil {
-		return x.OutgoingHtlcId
+		return x.MaximumPaymentResults
 	}
 	return 0
 }
 
-func (x *HtlcEvent) GetTimestampNs() uint64 {
+func (x *MissionControlConfig) GetMinimumFailureRelaxInterval() uint64 {
 	if x != nil {
-		return x.TimestampNs
+		return x.MinimumFailureRelaxInterval
 	}
 	return 0
 }
 
-func (x *HtlcEvent) GetEventType() HtlcEvent_EventType {
+func (x *MissionControlConfig) GetModel() MissionControlConfig_ProbabilityModel {
 	if x != nil {
-		return x.EventType
+		return x.Model
 	}
-	return HtlcEvent_UNKNOWN
+	return MissionControlConfig_APRIORI
 }
 
-func (m *HtlcEvent) GetEvent() isHtlcEvent_Event {
+func (m *MissionControlConfig) GetEstimatorConfig() isMissionControlConfig_EstimatorConfig {
 	if m != nil {
-		return m.Event
-	}
-	return nil
-}
-
-func (x *HtlcEvent) GetForwardEvent() *ForwardEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_ForwardEvent); ok {
-		return x.ForwardEvent
-	}
-	return nil
-}
-
-func (x *HtlcEvent) GetForwardFailEvent() *ForwardFailEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_ForwardFailEvent); ok {
-		return x.ForwardFailEvent
-	}
-	return nil
-}
-
-func (x *HtlcEvent) GetSettleEvent() *SettleEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_SettleEvent); ok {
-		return x.SettleEvent
-	}
-	return nil
-}
-
-func (x *HtlcEvent) GetLinkFailEvent() *LinkFailEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_LinkFailEvent); ok {
-		return x.LinkFailEvent
+		return m.EstimatorConfig
 	}
 	return nil
 }
 
-func (x *HtlcEvent) GetSubscribedEvent() *SubscribedEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_SubscribedEvent); ok {
-		return x.SubscribedEvent
+func (x *MissionControlConfig) GetApriori() *AprioriParameters {
+	if x, ok := x.GetEstimatorConfig().(*MissionControlConfig_Apriori); ok {
+		return x.Apriori
 	}
 	return nil
 }
 
-func (x *HtlcEvent) GetFinalHtlcEvent() *FinalHtlcEvent {
-	if x, ok := x.GetEvent().(*HtlcEvent_FinalHtlcEvent); ok {
-		return x.FinalHtlcEvent
+func (x *MissionControlConfig) GetBimodal() *BimodalParameters {
+	if x, ok := x.GetEstimatorConfig().(*MissionControlConfig_Bimodal); ok {
+		return x.Bimodal
 	}
 	return nil
 }
 
-type isHtlcEvent_Event interface {
-	isHtlcEvent_Event()
-}
-
-type HtlcEvent_ForwardEvent struct {
-	ForwardEvent *ForwardEvent `protobuf:"bytes,7,opt,name=forward_event,json=forwardEvent,proto3,oneof"`
-}
-
-type HtlcEvent_ForwardFailEvent struct {
-	ForwardFailEvent *ForwardFailEvent `protobuf:"bytes,8,opt,name=forward_fail_event,json=forwardFailEvent,proto3,oneof"`
-}
-
-type HtlcEvent_SettleEvent struct {
-	SettleEvent *SettleEvent `protobuf:"bytes,9,opt,name=settle_event,json=settleEvent,proto3,oneof"`
-}
-
-type HtlcEvent_LinkFailEvent struct {
-	LinkFailEvent *LinkFailEvent `protobuf:"bytes,10,opt,name=link_fail_event,json=linkFailEvent,proto3,oneof"`
+type isMissionControlConfig_EstimatorConfig interface {
+	isMissionControlConfig_EstimatorConfig()
 }
 
-type HtlcEvent_SubscribedEvent struct {
-	SubscribedEvent *SubscribedEvent `protobuf:"bytes,11,opt,name=subscribed_event,json=subscribedEvent,proto3,oneof"`
+type MissionControlConfig_Apriori struct {
+	Apriori *AprioriParameters `protobuf:"bytes,7,opt,name=apriori,proto3,oneof"`
 }
 
-type HtlcEvent_FinalHtlcEvent struct {
-	FinalHtlcEvent *FinalHtlcEvent `protobuf:"bytes,12,opt,name=final_htlc_event,json=finalHtlcEvent,proto3,oneof"`
+type MissionControlConfig_Bimodal struct {
+	Bimodal *BimodalParameters `protobuf:"bytes,8,opt,name=bimodal,proto3,oneof"`
 }
 
-func (*HtlcEvent_ForwardEvent) isHtlcEvent_Event() {}
-
-func (*HtlcEvent_ForwardFailEvent) isHtlcEvent_Event() {}
-
-func (*HtlcEvent_SettleEvent) isHtlcEvent_Event() {}
-
-func (*HtlcEvent_LinkFailEvent) isHtlcEvent_Event() {}
-
-func (*HtlcEvent_SubscribedEvent) isHtlcEvent_Event() {}
+func (*MissionControlConfig_Apriori) isMissionControlConfig_EstimatorConfig() {}
 
-func (*HtlcEvent_FinalHtlcEvent) isHtlcEvent_Event() {}
+func (*MissionControlConfig_Bimodal) isMissionControlConfig_EstimatorConfig() {}
 
-type HtlcInfo struct {
+type BimodalParameters struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The timelock on the incoming htlc.
-	IncomingTimelock uint32 `protobuf:"varint,1,opt,name=incoming_timelock,json=incomingTimelock,proto3" json:"incoming_timelock,omitempty"`
-	// The timelock on the outgoing htlc.
-	OutgoingTimelock uint32 `protobuf:"varint,2,opt,name=outgoing_timelock,json=outgoingTimelock,proto3" json:"outgoing_timelock,omitempty"`
-	// The amount of the incoming htlc.
-	IncomingAmtMsat uint64 `protobuf:"varint,3,opt,name=incoming_amt_msat,json=incomingAmtMsat,proto3" json:"incoming_amt_msat,omitempty"`
-	// The amount of the outgoing htlc.
-	OutgoingAmtMsat uint64 `protobuf:"varint,4,opt,name=outgoing_amt_msat,json=outgoingAmtMsat,proto3" json:"outgoing_amt_msat,omitempty"`
+	// NodeWeight defines how strongly other previous forwardings on channels of a
+	// router should be taken into account when computing a channel's probability
+	// to route. The allowed values are in the range [0, 1], where a value of 0
+	// means that only direct information about a channel is taken into account.
+	NodeWeight float64 `protobuf:"fixed64,1,opt,name=node_weight,json=nodeWeight,proto3" json:"node_weight,omitempty"`
+	// ScaleMsat describes the scale over which channels statistically have some
+	// liquidity left. The value determines how quickly the bimodal distribution
+	// drops off from the edges of a channel. A larger value (compared to typical
+	// channel capacities) means that the drop off is slow and that channel
+	// balances are distributed more uniformly. A small value leads to the
+	// assumption of very unbalanced channels.
+	ScaleMsat uint64 `protobuf:"varint,2,opt,name=scale_msat,json=scaleMsat,proto3" json:"scale_msat,omitempty"`
+	// DecayTime describes the information decay of knowledge about previous
+	// successes and failures in channels. The smaller the decay time, the quicker
+	// we forget about past forwardings.
+	DecayTime uint64 `protobuf:"varint,3,opt,name=decay_time,json=decayTime,proto3" json:"decay_time,omitempty"`
 }
 
-func (x *HtlcInfo) Reset() {
-	*x = HtlcInfo{}
+func (x *BimodalParameters) Reset() {
+	*x = BimodalParameters{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[28]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2587,13 +2653,13 @@ func (x *HtlcInfo) Reset() {
 	}
 }
 
-func (x *HtlcInfo) String() string {
+func (x *BimodalParameters) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HtlcInfo) ProtoMessage() {}
+func (*BimodalParameters) ProtoMessage() {}
 
-func (x *HtlcInfo) ProtoReflect() protoreflect.Message {
+func (x *BimodalParameters) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[28]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2605,66 +2671,80 @@ func (x *HtlcInfo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HtlcInfo.ProtoReflect.Descriptor instead.
-func (*HtlcInfo) Descriptor() ([]byte, []int) {
+// Deprecated: Use BimodalParameters.ProtoReflect.Descriptor instead.
+func (*BimodalParameters) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *HtlcInfo) GetIncomingTimelock() uint32 {
-	if x != nil {
-		return x.IncomingTimelock
-	}
-	return 0
-}
-
-func (x *HtlcInfo) GetOutgoingTimelock() uint32 {
+func (x *BimodalParameters) GetNodeWeight() float64 {
 	if x != nil {
-		return x.OutgoingTimelock
+		return x.NodeWeight
 	}
 	return 0
 }
 
-func (x *HtlcInfo) GetIncomingAmtMsat() uint64 {
+func (x *BimodalParameters) GetScaleMsat() uint64 {
 	if x != nil {
-		return x.IncomingAmtMsat
+		return x.ScaleMsat
 	}
 	return 0
 }
 
-func (x *HtlcInfo) GetOutgoingAmtMsat() uint64 {
+func (x *BimodalParameters) GetDecayTime() uint64 {
 	if x != nil {
-		return x.OutgoingAmtMsat
+		return x.DecayTime
 	}
 	return 0
 }
 
-type ForwardEvent struct {
+type AprioriParameters struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Info contains details about the htlc that was forwarded.
-	Info *HtlcInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
-}
-
-func (x *ForwardEvent) Reset() {
-	*x = ForwardEvent{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[29]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
-	}
-}
-
-func (x *ForwardEvent) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*ForwardEvent) ProtoMessage() {}
-
-func (x *ForwardEvent) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[29]
-	if protoimpl.UnsafeEnabled && x != nil {
+	// The amount of time mission control will take to restore a penalized node
+	// or channel back to 50% success probability, expressed in seconds. Setting
+	// this value to a higher value will penalize failures for longer, making
+	// mission control less likely to route through nodes and channels that we
+	// have previously recorded failures for.
+	HalfLifeSeconds uint64 `protobuf:"varint,1,opt,name=half_life_seconds,json=halfLifeSeconds,proto3" json:"half_life_seconds,omitempty"`
+	// The probability of success mission control should assign to hop in a route
+	// where it has no other information available. Higher values will make mission
+	// control more willing to try hops that we have no information about, lower
+	// values will discourage trying these hops.
+	HopProbability float64 `protobuf:"fixed64,2,opt,name=hop_probability,json=hopProbability,proto3" json:"hop_probability,omitempty"`
+	// The importance that mission control should place on historical results,
+	// expressed as a value in [0;1]. Setting this value to 1 will ignore all
+	// historical payments and just use the hop probability to assess the
+	// probability of success for each hop. A zero value ignores hop probability
+	// completely and relies entirely on historical results, unless none are
+	// available.
+	Weight float64 `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	// The fraction of a channel's capacity that we consider to have liquidity. For
+	// amounts that come close to or exceed the fraction, an additional penalty is
+	// applied. A value of 1.0 disables the capacity factor. Allowed values are in
+	// [0.75, 1.0].
+	CapacityFraction float64 `protobuf:"fixed64,4,opt,name=capacity_fraction,json=capacityFraction,proto3" json:"capacity_fraction,omitempty"`
+}
+
+func (x *AprioriParameters) Reset() {
+	*x = AprioriParameters{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AprioriParameters) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AprioriParameters) ProtoMessage() {}
+
+func (x *AprioriParameters) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
@@ -2674,26 +2754,54 @@ func (x *ForwardEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForwardEvent.ProtoReflect.Descriptor instead.
-func (*ForwardEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use AprioriParameters.ProtoReflect.Descriptor instead.
+func (*AprioriParameters) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *ForwardEvent) GetInfo() *HtlcInfo {
+func (x *AprioriParameters) GetHalfLifeSeconds() uint64 {
 	if x != nil {
-		return x.Info
+		return x.HalfLifeSeconds
 	}
-	return nil
+	return 0
 }
 
-type ForwardFailEvent struct {
+func (x *AprioriParameters) GetHopProbability() float64 {
+	if x != nil {
+		return x.HopProbability
+	}
+	return 0
+}
+
+func (x *AprioriParameters) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *AprioriParameters) GetCapacityFraction() float64 {
+	if x != nil {
+		return x.CapacityFraction
+	}
+	return 0
+}
+
+type QueryProbabilityRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The source node pubkey of the pair.
+	FromNode []byte `protobuf:"bytes,1,opt,name=from_node,json=fromNode,proto3" json:"from_node,omitempty"`
+	// The destination node pubkey of the pair.
+	ToNode []byte `protobuf:"bytes,2,opt,name=to_node,json=toNode,proto3" json:"to_node,omitempty"`
+	// The amount for which to calculate a probability.
+	AmtMsat int64 `protobuf:"varint,3,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
 }
 
-func (x *ForwardFailEvent) Reset() {
-	*x = ForwardFailEvent{}
+func (x *QueryProbabilityRequest) Reset() {
+	*x = QueryProbabilityRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[30]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2701,13 +2809,13 @@ func (x *ForwardFailEvent) Reset() {
 	}
 }
 
-func (x *ForwardFailEvent) String() string {
+func (x *QueryProbabilityRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForwardFailEvent) ProtoMessage() {}
+func (*QueryProbabilityRequest) ProtoMessage() {}
 
-func (x *ForwardFailEvent) ProtoReflect() protoreflect.Message {
+func (x *QueryProbabilityRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[30]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2719,22 +2827,45 @@ func (x *ForwardFailEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForwardFailEvent.ProtoReflect.Descriptor instead.
-func (*ForwardFailEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryProbabilityRequest.ProtoReflect.Descriptor instead.
+func (*QueryProbabilityRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{30}
 }
 
-type SettleEvent struct {
+func (x *QueryProbabilityRequest) GetFromNode() []byte {
+	if x != nil {
+		return x.FromNode
+	}
+	return nil
+}
+
+func (x *QueryProbabilityRequest) GetToNode() []byte {
+	if x != nil {
+		return x.ToNode
+	}
+	return nil
+}
+
+func (x *QueryProbabilityRequest) GetAmtMsat() int64 {
+	if x != nil {
+		return x.AmtMsat
+	}
+	return 0
+}
+
+type QueryProbabilityResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The revealed preimage.
-	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// The success probability for the requested pair.
+	Probability float64 `protobuf:"fixed64,1,opt,name=probability,proto3" json:"probability,omitempty"`
+	// The historical data for the requested pair.
+	History *PairData `protobuf:"bytes,2,opt,name=history,proto3" json:"history,omitempty"`
 }
 
-func (x *SettleEvent) Reset() {
-	*x = SettleEvent{}
+func (x *QueryProbabilityResponse) Reset() {
+	*x = QueryProbabilityResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[31]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2742,13 +2873,13 @@ func (x *SettleEvent) Reset() {
 	}
 }
 
-func (x *SettleEvent) String() string {
+func (x *QueryProbabilityResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SettleEvent) ProtoMessage() {}
+func (*QueryProbabilityResponse) ProtoMessage() {}
 
-func (x *SettleEvent) ProtoReflect() protoreflect.Message {
+func (x *QueryProbabilityResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[31]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2760,29 +2891,33 @@ func (x *SettleEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SettleEvent.ProtoReflect.Descriptor instead.
-func (*SettleEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use QueryProbabilityResponse.ProtoReflect.Descriptor instead.
+func (*QueryProbabilityResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *SettleEvent) GetPreimage() []byte {
+func (x *QueryProbabilityResponse) GetProbability() float64 {
 	if x != nil {
-		return x.Preimage
+		return x.Probability
+	}
+	return 0
+}
+
+func (x *QueryProbabilityResponse) GetHistory() *PairData {
+	if x != nil {
+		return x.History
 	}
 	return nil
 }
 
-type FinalHtlcEvent struct {
+type ProbeHistoryRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	Settled  bool `protobuf:"varint,1,opt,name=settled,proto3" json:"settled,omitempty"`
-	Offchain bool `protobuf:"varint,2,opt,name=offchain,proto3" json:"offchain,omitempty"`
 }
 
-func (x *FinalHtlcEvent) Reset() {
-	*x = FinalHtlcEvent{}
+func (x *ProbeHistoryRequest) Reset() {
+	*x = ProbeHistoryRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2790,13 +2925,13 @@ func (x *FinalHtlcEvent) Reset() {
 	}
 }
 
-func (x *FinalHtlcEvent) String() string {
+func (x *ProbeHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FinalHtlcEvent) ProtoMessage() {}
+func (*ProbeHistoryRequest) ProtoMessage() {}
 
-func (x *FinalHtlcEvent) ProtoReflect() protoreflect.Message {
+func (x *ProbeHistoryRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2808,33 +2943,35 @@ func (x *FinalHtlcEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FinalHtlcEvent.ProtoReflect.Descriptor instead.
-func (*FinalHtlcEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProbeHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ProbeHistoryRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *FinalHtlcEvent) GetSettled() bool {
-	if x != nil {
-		return x.Settled
-	}
-	return false
-}
-
-func (x *FinalHtlcEvent) GetOffchain() bool {
-	if x != nil {
-		return x.Offchain
-	}
-	return false
-}
-
-type SubscribedEvent struct {
+type ProbeResult struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-}
 
-func (x *SubscribedEvent) Reset() {
-	*x = SubscribedEvent{}
+	// The public key of the probed node.
+	Target []byte `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	// The amount in msat that was probed with.
+	AmtMsat int64 `protobuf:"varint,2,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
+	// The unix timestamp in nanoseconds at which the probe was dispatched.
+	TimestampNs int64 `protobuf:"varint,3,opt,name=timestamp_ns,json=timestampNs,proto3" json:"timestamp_ns,omitempty"`
+	// The time in milliseconds it took for the probe to resolve.
+	DurationMs int64 `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	// Whether the probe reached the target node, meaning that the target
+	// rejected the unknown payment hash.
+	Reached bool `protobuf:"varint,5,opt,name=reached,proto3" json:"reached,omitempty"`
+	// The reason the probe failed, if it was initiated.
+	FailureReason lnrpc.PaymentFailureReason `protobuf:"varint,6,opt,name=failure_reason,json=failureReason,proto3,enum=lnrpc.PaymentFailureReason" json:"failure_reason,omitempty"`
+	// The error the probe terminated with.
+	Error string `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ProbeResult) Reset() {
+	*x = ProbeResult{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2842,13 +2979,13 @@ func (x *SubscribedEvent) Reset() {
 	}
 }
 
-func (x *SubscribedEvent) String() string {
+func (x *ProbeResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscribedEvent) ProtoMessage() {}
+func (*ProbeResult) ProtoMessage() {}
 
-func (x *SubscribedEvent) ProtoReflect() protoreflect.Message {
+func (x *ProbeResult) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2860,30 +2997,71 @@ func (x *SubscribedEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscribedEvent.ProtoReflect.Descriptor instead.
-func (*SubscribedEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProbeResult.ProtoReflect.Descriptor instead.
+func (*ProbeResult) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{33}
 }
 
-type LinkFailEvent struct {
+func (x *ProbeResult) GetTarget() []byte {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *ProbeResult) GetAmtMsat() int64 {
+	if x != nil {
+		return x.AmtMsat
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetTimestampNs() int64 {
+	if x != nil {
+		return x.TimestampNs
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetDurationMs() int64 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+func (x *ProbeResult) GetReached() bool {
+	if x != nil {
+		return x.Reached
+	}
+	return false
+}
+
+func (x *ProbeResult) GetFailureReason() lnrpc.PaymentFailureReason {
+	if x != nil {
+		return x.FailureReason
+	}
+	return lnrpc.PaymentFailureReason(0)
+}
+
+func (x *ProbeResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ProbeHistoryResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// Info contains details about the htlc that we failed.
-	Info *HtlcInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
-	// FailureCode is the BOLT error code for the failure.
-	WireFailure lnrpc.Failure_FailureCode `protobuf:"varint,2,opt,name=wire_failure,json=wireFailure,proto3,enum=lnrpc.Failure_FailureCode" json:"wire_failure,omitempty"`
-	// FailureDetail provides additional information about the reason for the
-	// failure. This detail enriches the information provided by the wire message
-	// and may be 'no detail' if the wire message requires no additional metadata.
-	FailureDetail FailureDetail `protobuf:"varint,3,opt,name=failure_detail,json=failureDetail,proto3,enum=routerrpc.FailureDetail" json:"failure_detail,omitempty"`
-	// A string representation of the link failure.
-	FailureString string `protobuf:"bytes,4,opt,name=failure_string,json=failureString,proto3" json:"failure_string,omitempty"`
+	// The results of the most recent probes, oldest first.
+	Results []*ProbeResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
 }
 
-func (x *LinkFailEvent) Reset() {
-	*x = LinkFailEvent{}
+func (x *ProbeHistoryResponse) Reset() {
+	*x = ProbeHistoryResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[34]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2891,13 +3069,13 @@ func (x *LinkFailEvent) Reset() {
 	}
 }
 
-func (x *LinkFailEvent) String() string {
+func (x *ProbeHistoryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LinkFailEvent) ProtoMessage() {}
+func (*ProbeHistoryResponse) ProtoMessage() {}
 
-func (x *LinkFailEvent) ProtoReflect() protoreflect.Message {
+func (x *ProbeHistoryResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[34]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2909,54 +3087,48 @@ func (x *LinkFailEvent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LinkFailEvent.ProtoReflect.Descriptor instead.
-func (*LinkFailEvent) Descriptor() ([]byte, []int) {
+// Deprecated: Use ProbeHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ProbeHistoryResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *LinkFailEvent) GetInfo() *HtlcInfo {
+func (x *ProbeHistoryResponse) GetResults() []*ProbeResult {
 	if x != nil {
-		return x.Info
+		return x.Results
 	}
 	return nil
 }
 
-func (x *LinkFailEvent) GetWireFailure() lnrpc.Failure_FailureCode {
-	if x != nil {
-		return x.WireFailure
-	}
-	return lnrpc.Failure_FailureCode(0)
-}
+type BuildRouteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-func (x *LinkFailEvent) GetFailureDetail() FailureDetail {
-	if x != nil {
-		return x.FailureDetail
-	}
-	return FailureDetail_UNKNOWN
-}
-
-func (x *LinkFailEvent) GetFailureString() string {
-	if x != nil {
-		return x.FailureString
-	}
-	return ""
-}
-
-type PaymentStatus struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	// Current state the payment is in.
-	State PaymentState `protobuf:"varint,1,opt,name=state,proto3,enum=routerrpc.PaymentState" json:"state,omitempty"`
-	// The pre-image of the payment when state is SUCCEEDED.
-	Preimage []byte `protobuf:"bytes,2,opt,name=preimage,proto3" json:"preimage,omitempty"`
-	// The HTLCs made in attempt to settle the payment [EXPERIMENTAL].
-	Htlcs []*lnrpc.HTLCAttempt `protobuf:"bytes,4,rep,name=htlcs,proto3" json:"htlcs,omitempty"`
+	// The amount to send expressed in msat. If set to zero, the minimum routable
+	// amount is used.
+	AmtMsat int64 `protobuf:"varint,1,opt,name=amt_msat,json=amtMsat,proto3" json:"amt_msat,omitempty"`
+	// CLTV delta from the current height that should be used for the timelock
+	// of the final hop
+	FinalCltvDelta int32 `protobuf:"varint,2,opt,name=final_cltv_delta,json=finalCltvDelta,proto3" json:"final_cltv_delta,omitempty"`
+	// The channel id of the channel that must be taken to the first hop. If zero,
+	// any channel may be used.
+	OutgoingChanId uint64 `protobuf:"varint,3,opt,name=outgoing_chan_id,json=outgoingChanId,proto3" json:"outgoing_chan_id,omitempty"`
+	// A list of hops that defines the route. This does not include the source hop
+	// pubkey.
+	HopPubkeys [][]byte `protobuf:"bytes,4,rep,name=hop_pubkeys,json=hopPubkeys,proto3" json:"hop_pubkeys,omitempty"`
+	// An optional payment addr to be included within the last hop of the route.
+	// This is also called payment secret in specifications (e.g. BOLT 11).
+	PaymentAddr []byte `protobuf:"bytes,5,opt,name=payment_addr,json=paymentAddr,proto3" json:"payment_addr,omitempty"`
+	// An optional field that can be used to pass an arbitrary set of TLV records
+	// to the first hop peer of this payment. This can be used to pass application
+	// specific data during the payment attempt. Record types are required to be in
+	// the custom range >= 65536. When using REST, the values must be encoded as
+	// base64.
+	FirstHopCustomRecords map[uint64][]byte `protobuf:"bytes,6,rep,name=first_hop_custom_records,json=firstHopCustomRecords,proto3" json:"first_hop_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *PaymentStatus) Reset() {
-	*x = PaymentStatus{}
+func (x *BuildRouteRequest) Reset() {
+	*x = BuildRouteRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[35]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2964,13 +3136,13 @@ func (x *PaymentStatus) Reset() {
 	}
 }
 
-func (x *PaymentStatus) String() string {
+func (x *BuildRouteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PaymentStatus) ProtoMessage() {}
+func (*BuildRouteRequest) ProtoMessage() {}
 
-func (x *PaymentStatus) ProtoReflect() protoreflect.Message {
+func (x *BuildRouteRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[35]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -2982,45 +3154,64 @@ func (x *PaymentStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PaymentStatus.ProtoReflect.Descriptor instead.
-func (*PaymentStatus) Descriptor() ([]byte, []int) {
+// Deprecated: Use BuildRouteRequest.ProtoReflect.Descriptor instead.
+func (*BuildRouteRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *PaymentStatus) GetState() PaymentState {
+func (x *BuildRouteRequest) GetAmtMsat() int64 {
 	if x != nil {
-		return x.State
+		return x.AmtMsat
 	}
-	return PaymentState_IN_FLIGHT
+	return 0
 }
 
-func (x *PaymentStatus) GetPreimage() []byte {
+func (x *BuildRouteRequest) GetFinalCltvDelta() int32 {
 	if x != nil {
-		return x.Preimage
+		return x.FinalCltvDelta
+	}
+	return 0
+}
+
+func (x *BuildRouteRequest) GetOutgoingChanId() uint64 {
+	if x != nil {
+		return x.OutgoingChanId
+	}
+	return 0
+}
+
+func (x *BuildRouteRequest) GetHopPubkeys() [][]byte {
+	if x != nil {
+		return x.HopPubkeys
 	}
 	return nil
 }
 
-func (x *PaymentStatus) GetHtlcs() []*lnrpc.HTLCAttempt {
+func (x *BuildRouteRequest) GetPaymentAddr() []byte {
 	if x != nil {
-		return x.Htlcs
+		return x.PaymentAddr
 	}
 	return nil
 }
 
-type CircuitKey struct {
+func (x *BuildRouteRequest) GetFirstHopCustomRecords() map[uint64][]byte {
+	if x != nil {
+		return x.FirstHopCustomRecords
+	}
+	return nil
+}
+
+type BuildRouteResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// / The id of the channel that the is part of this circuit.
-	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
-	// / The index of the incoming htlc in the incoming channel.
-	HtlcId uint64 `protobuf:"varint,2,opt,name=htlc_id,json=htlcId,proto3" json:"htlc_id,omitempty"`
+	// Fully specified route that can be used to execute the payment.
+	Route *lnrpc.Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
 }
 
-func (x *CircuitKey) Reset() {
-	*x = CircuitKey{}
+func (x *BuildRouteResponse) Reset() {
+	*x = BuildRouteResponse{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[36]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3028,13 +3219,13 @@ func (x *CircuitKey) Reset() {
 	}
 }
 
-func (x *CircuitKey) String() string {
+func (x *BuildRouteResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CircuitKey) ProtoMessage() {}
+func (*BuildRouteResponse) ProtoMessage() {}
 
-func (x *CircuitKey) ProtoReflect() protoreflect.Message {
+func (x *BuildRouteResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[36]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3046,62 +3237,42 @@ func (x *CircuitKey) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CircuitKey.ProtoReflect.Descriptor instead.
-func (*CircuitKey) Descriptor() ([]byte, []int) {
+// Deprecated: Use BuildRouteResponse.ProtoReflect.Descriptor instead.
+func (*BuildRouteResponse) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *CircuitKey) GetChanId() uint64 {
-	if x != nil {
-		return x.ChanId
-	}
-	return 0
-}
-
-func (x *CircuitKey) GetHtlcId() uint64 {
+func (x *BuildRouteResponse) GetRoute() *lnrpc.Route {
 	if x != nil {
-		return x.HtlcId
+		return x.Route
 	}
-	return 0
+	return nil
 }
 
-type ForwardHtlcInterceptRequest struct {
+type RebalanceRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// The key of this forwarded htlc. It defines the incoming channel id and
-	// the index in this channel.
-	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
-	// The incoming htlc amount.
-	IncomingAmountMsat uint64 `protobuf:"varint,5,opt,name=incoming_amount_msat,json=incomingAmountMsat,proto3" json:"incoming_amount_msat,omitempty"`
-	// The incoming htlc expiry.
-	IncomingExpiry uint32 `protobuf:"varint,6,opt,name=incoming_expiry,json=incomingExpiry,proto3" json:"incoming_expiry,omitempty"`
-	// The htlc payment hash. This value is not guaranteed to be unique per
-	// request.
-	PaymentHash []byte `protobuf:"bytes,2,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
-	// The requested outgoing channel id for this forwarded htlc. Because of
-	// non-strict forwarding, this isn't necessarily the channel over which the
-	// packet will be forwarded eventually. A different channel to the same peer
-	// may be selected as well.
-	OutgoingRequestedChanId uint64 `protobuf:"varint,7,opt,name=outgoing_requested_chan_id,json=outgoingRequestedChanId,proto3" json:"outgoing_requested_chan_id,omitempty"`
-	// The outgoing htlc amount.
-	OutgoingAmountMsat uint64 `protobuf:"varint,3,opt,name=outgoing_amount_msat,json=outgoingAmountMsat,proto3" json:"outgoing_amount_msat,omitempty"`
-	// The outgoing htlc expiry.
-	OutgoingExpiry uint32 `protobuf:"varint,4,opt,name=outgoing_expiry,json=outgoingExpiry,proto3" json:"outgoing_expiry,omitempty"`
-	// Any custom records that were present in the payload.
-	CustomRecords map[uint64][]byte `protobuf:"bytes,8,rep,name=custom_records,json=customRecords,proto3" json:"custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	// The onion blob for the next hop
-	OnionBlob []byte `protobuf:"bytes,9,opt,name=onion_blob,json=onionBlob,proto3" json:"onion_blob,omitempty"`
-	// The block height at which this htlc will be auto-failed to prevent the
-	// channel from force-closing.
-	AutoFailHeight int32 `protobuf:"varint,10,opt,name=auto_fail_height,json=autoFailHeight,proto3" json:"auto_fail_height,omitempty"`
-	// The custom records of the peer's incoming p2p wire message.
-	InWireCustomRecords map[uint64][]byte `protobuf:"bytes,11,rep,name=in_wire_custom_records,json=inWireCustomRecords,proto3" json:"in_wire_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-}
-
-func (x *ForwardHtlcInterceptRequest) Reset() {
-	*x = ForwardHtlcInterceptRequest{}
+	// The channel id of the channel to move local balance out of.
+	OutgoingChanId uint64 `protobuf:"varint,1,opt,name=outgoing_chan_id,json=outgoingChanId,proto3" json:"outgoing_chan_id,omitempty"`
+	// The channel id of the channel to move local balance into.
+	IncomingChanId uint64 `protobuf:"varint,2,opt,name=incoming_chan_id,json=incomingChanId,proto3" json:"incoming_chan_id,omitempty"`
+	// The amount to move expressed in satoshis.
+	AmtSat int64 `protobuf:"varint,3,opt,name=amt_sat,json=amtSat,proto3" json:"amt_sat,omitempty"`
+	// The maximum fee that may be paid for the rebalance, expressed in
+	// satoshis.
+	FeeLimitSat int64 `protobuf:"varint,4,opt,name=fee_limit_sat,json=feeLimitSat,proto3" json:"fee_limit_sat,omitempty"`
+	// An optional maximum total time lock for the route. If zero, the maximum
+	// allowed by the node is used.
+	CltvLimit uint32 `protobuf:"varint,5,opt,name=cltv_limit,json=cltvLimit,proto3" json:"cltv_limit,omitempty"`
+	// If set, the circular route is only computed and returned, without sending
+	// the payment.
+	QueryOnly bool `protobuf:"varint,6,opt,name=query_only,json=queryOnly,proto3" json:"query_only,omitempty"`
+}
+
+func (x *RebalanceRequest) Reset() {
+	*x = RebalanceRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[37]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3109,13 +3280,13 @@ func (x *ForwardHtlcInterceptRequest) Reset() {
 	}
 }
 
-func (x *ForwardHtlcInterceptRequest) String() string {
+func (x *RebalanceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForwardHtlcInterceptRequest) ProtoMessage() {}
+func (*RebalanceRequest) ProtoMessage() {}
 
-func (x *ForwardHtlcInterceptRequest) ProtoReflect() protoreflect.Message {
+func (x *RebalanceRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[37]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3127,152 +3298,151 @@ func (x *ForwardHtlcInterceptRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForwardHtlcInterceptRequest.ProtoReflect.Descriptor instead.
-func (*ForwardHtlcInterceptRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RebalanceRequest.ProtoReflect.Descriptor instead.
+func (*RebalanceRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *ForwardHtlcInterceptRequest) GetIncomingCircuitKey() *CircuitKey {
+func (x *RebalanceRequest) GetOutgoingChanId() uint64 {
 	if x != nil {
-		return x.IncomingCircuitKey
+		return x.OutgoingChanId
 	}
-	return nil
+	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetIncomingAmountMsat() uint64 {
+func (x *RebalanceRequest) GetIncomingChanId() uint64 {
 	if x != nil {
-		return x.IncomingAmountMsat
+		return x.IncomingChanId
 	}
 	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetIncomingExpiry() uint32 {
+func (x *RebalanceRequest) GetAmtSat() int64 {
 	if x != nil {
-		return x.IncomingExpiry
+		return x.AmtSat
 	}
 	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetPaymentHash() []byte {
+func (x *RebalanceRequest) GetFeeLimitSat() int64 {
 	if x != nil {
-		return x.PaymentHash
+		return x.FeeLimitSat
 	}
-	return nil
+	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetOutgoingRequestedChanId() uint64 {
+func (x *RebalanceRequest) GetCltvLimit() uint32 {
 	if x != nil {
-		return x.OutgoingRequestedChanId
+		return x.CltvLimit
 	}
 	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetOutgoingAmountMsat() uint64 {
+func (x *RebalanceRequest) GetQueryOnly() bool {
 	if x != nil {
-		return x.OutgoingAmountMsat
+		return x.QueryOnly
 	}
-	return 0
+	return false
 }
 
-func (x *ForwardHtlcInterceptRequest) GetOutgoingExpiry() uint32 {
-	if x != nil {
-		return x.OutgoingExpiry
+type RebalanceChannel struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The channel id of the channel.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
+	// The local balance of the channel before the rebalance in satoshis.
+	LocalBalanceBefore int64 `protobuf:"varint,2,opt,name=local_balance_before,json=localBalanceBefore,proto3" json:"local_balance_before,omitempty"`
+	// The local balance of the channel after the rebalance in satoshis. It is
+	// equal to the balance before the rebalance if query_only was set.
+	LocalBalanceAfter int64 `protobuf:"varint,3,opt,name=local_balance_after,json=localBalanceAfter,proto3" json:"local_balance_after,omitempty"`
+}
+
+func (x *RebalanceChannel) Reset() {
+	*x = RebalanceChannel{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetCustomRecords() map[uint64][]byte {
-	if x != nil {
-		return x.CustomRecords
+func (x *RebalanceChannel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RebalanceChannel) ProtoMessage() {}
+
+func (x *RebalanceChannel) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *ForwardHtlcInterceptRequest) GetOnionBlob() []byte {
+// Deprecated: Use RebalanceChannel.ProtoReflect.Descriptor instead.
+func (*RebalanceChannel) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *RebalanceChannel) GetChanId() uint64 {
 	if x != nil {
-		return x.OnionBlob
+		return x.ChanId
 	}
-	return nil
+	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetAutoFailHeight() int32 {
+func (x *RebalanceChannel) GetLocalBalanceBefore() int64 {
 	if x != nil {
-		return x.AutoFailHeight
+		return x.LocalBalanceBefore
 	}
 	return 0
 }
 
-func (x *ForwardHtlcInterceptRequest) GetInWireCustomRecords() map[uint64][]byte {
+func (x *RebalanceChannel) GetLocalBalanceAfter() int64 {
 	if x != nil {
-		return x.InWireCustomRecords
+		return x.LocalBalanceAfter
 	}
-	return nil
+	return 0
 }
 
-// *
-// ForwardHtlcInterceptResponse enables the caller to resolve a previously hold
-// forward. The caller can choose either to:
-// - `Resume`: Execute the default behavior (usually forward).
-// - `ResumeModified`: Execute the default behavior (usually forward) with HTLC
-// field modifications.
-// - `Reject`: Fail the htlc backwards.
-// - `Settle`: Settle this htlc with a given preimage.
-type ForwardHtlcInterceptResponse struct {
+type RebalanceResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	// *
-	// The key of this forwarded htlc. It defines the incoming channel id and
-	// the index in this channel.
-	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
-	// The resolve action for this intercepted htlc.
-	Action ResolveHoldForwardAction `protobuf:"varint,2,opt,name=action,proto3,enum=routerrpc.ResolveHoldForwardAction" json:"action,omitempty"`
-	// The preimage in case the resolve action is Settle.
-	Preimage []byte `protobuf:"bytes,3,opt,name=preimage,proto3" json:"preimage,omitempty"`
-	// Encrypted failure message in case the resolve action is Fail.
-	//
-	// If failure_message is specified, the failure_code field must be set
-	// to zero.
-	FailureMessage []byte `protobuf:"bytes,4,opt,name=failure_message,json=failureMessage,proto3" json:"failure_message,omitempty"`
-	// Return the specified failure code in case the resolve action is Fail. The
-	// message data fields are populated automatically.
-	//
-	// If a non-zero failure_code is specified, failure_message must not be set.
-	//
-	// For backwards-compatibility reasons, TEMPORARY_CHANNEL_FAILURE is the
-	// default value for this field.
-	FailureCode lnrpc.Failure_FailureCode `protobuf:"varint,5,opt,name=failure_code,json=failureCode,proto3,enum=lnrpc.Failure_FailureCode" json:"failure_code,omitempty"`
-	// The amount that was set on the p2p wire message of the incoming HTLC.
-	// This field is ignored if the action is not RESUME_MODIFIED or the amount
-	// is zero.
-	InAmountMsat uint64 `protobuf:"varint,6,opt,name=in_amount_msat,json=inAmountMsat,proto3" json:"in_amount_msat,omitempty"`
-	// The amount to set on the p2p wire message of the resumed HTLC. This field
-	// is ignored if the action is not RESUME_MODIFIED or the amount is zero.
-	OutAmountMsat uint64 `protobuf:"varint,7,opt,name=out_amount_msat,json=outAmountMsat,proto3" json:"out_amount_msat,omitempty"`
-	// Any custom records that should be set on the p2p wire message message of
-	// the resumed HTLC. This field is ignored if the action is not
-	// RESUME_MODIFIED.
-	OutWireCustomRecords map[uint64][]byte `protobuf:"bytes,8,rep,name=out_wire_custom_records,json=outWireCustomRecords,proto3" json:"out_wire_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The circular route of the rebalance.
+	Route *lnrpc.Route `protobuf:"bytes,1,opt,name=route,proto3" json:"route,omitempty"`
+	// The hash of the payment that rebalanced the channels. It is empty if
+	// query_only was set.
+	PaymentHash []byte `protobuf:"bytes,2,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	// The outgoing and the incoming channel of the rebalance, in that order.
+	Channels []*RebalanceChannel `protobuf:"bytes,3,rep,name=channels,proto3" json:"channels,omitempty"`
 }
 
-func (x *ForwardHtlcInterceptResponse) Reset() {
-	*x = ForwardHtlcInterceptResponse{}
+func (x *RebalanceResponse) Reset() {
+	*x = RebalanceResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[38]
+		mi := &file_routerrpc_router_proto_msgTypes[39]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *ForwardHtlcInterceptResponse) String() string {
+func (x *RebalanceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ForwardHtlcInterceptResponse) ProtoMessage() {}
+func (*RebalanceResponse) ProtoMessage() {}
 
-func (x *ForwardHtlcInterceptResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[38]
+func (x *RebalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[39]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3283,93 +3453,67 @@ func (x *ForwardHtlcInterceptResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ForwardHtlcInterceptResponse.ProtoReflect.Descriptor instead.
-func (*ForwardHtlcInterceptResponse) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{38}
-}
-
-func (x *ForwardHtlcInterceptResponse) GetIncomingCircuitKey() *CircuitKey {
-	if x != nil {
-		return x.IncomingCircuitKey
-	}
-	return nil
-}
-
-func (x *ForwardHtlcInterceptResponse) GetAction() ResolveHoldForwardAction {
-	if x != nil {
-		return x.Action
-	}
-	return ResolveHoldForwardAction_SETTLE
+// Deprecated: Use RebalanceResponse.ProtoReflect.Descriptor instead.
+func (*RebalanceResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *ForwardHtlcInterceptResponse) GetPreimage() []byte {
+func (x *RebalanceResponse) GetRoute() *lnrpc.Route {
 	if x != nil {
-		return x.Preimage
+		return x.Route
 	}
 	return nil
 }
 
-func (x *ForwardHtlcInterceptResponse) GetFailureMessage() []byte {
+func (x *RebalanceResponse) GetPaymentHash() []byte {
 	if x != nil {
-		return x.FailureMessage
+		return x.PaymentHash
 	}
 	return nil
 }
 
-func (x *ForwardHtlcInterceptResponse) GetFailureCode() lnrpc.Failure_FailureCode {
-	if x != nil {
-		return x.FailureCode
-	}
-	return lnrpc.Failure_FailureCode(0)
-}
-
-func (x *ForwardHtlcInterceptResponse) GetInAmountMsat() uint64 {
-	if x != nil {
-		return x.InAmountMsat
-	}
-	return 0
-}
-
-func (x *ForwardHtlcInterceptResponse) GetOutAmountMsat() uint64 {
-	if x != nil {
-		return x.OutAmountMsat
-	}
-	return 0
-}
-
-func (x *ForwardHtlcInterceptResponse) GetOutWireCustomRecords() map[uint64][]byte {
+func (x *RebalanceResponse) GetChannels() []*RebalanceChannel {
 	if x != nil {
-		return x.OutWireCustomRecords
+		return x.Channels
 	}
 	return nil
 }
 
-type UpdateChanStatusRequest struct {
+type SubscribeAllRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ChanPoint *lnrpc.ChannelPoint `protobuf:"bytes,1,opt,name=chan_point,json=chanPoint,proto3" json:"chan_point,omitempty"`
-	Action    ChanStatusAction    `protobuf:"varint,2,opt,name=action,proto3,enum=routerrpc.ChanStatusAction" json:"action,omitempty"`
-}
-
-func (x *UpdateChanStatusRequest) Reset() {
-	*x = UpdateChanStatusRequest{}
+	// The types of events to subscribe to. All types are subscribed to if none
+	// are given.
+	Types []SubscribeAllEvent_EventType `protobuf:"varint,1,rep,packed,name=types,proto3,enum=routerrpc.SubscribeAllEvent_EventType" json:"types,omitempty"`
+	// Restricts the events that relate to channels or peers to those of the
+	// given channels. Events that don't relate to a channel or peer, such as new
+	// blocks, are always sent.
+	ChannelPoints []*lnrpc.ChannelPoint `protobuf:"bytes,2,rep,name=channel_points,json=channelPoints,proto3" json:"channel_points,omitempty"`
+	// Restricts the events that relate to channels or peers to those of the
+	// given peers. An event is sent if it relates to one of the given channels
+	// or peers.
+	Peers [][]byte `protobuf:"bytes,3,rep,name=peers,proto3" json:"peers,omitempty"`
+}
+
+func (x *SubscribeAllRequest) Reset() {
+	*x = SubscribeAllRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[39]
+		mi := &file_routerrpc_router_proto_msgTypes[40]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *UpdateChanStatusRequest) String() string {
+func (x *SubscribeAllRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateChanStatusRequest) ProtoMessage() {}
+func (*SubscribeAllRequest) ProtoMessage() {}
 
-func (x *UpdateChanStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[39]
+func (x *SubscribeAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[40]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3380,48 +3524,68 @@ func (x *UpdateChanStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateChanStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateChanStatusRequest) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use SubscribeAllRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeAllRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *UpdateChanStatusRequest) GetChanPoint() *lnrpc.ChannelPoint {
+func (x *SubscribeAllRequest) GetTypes() []SubscribeAllEvent_EventType {
 	if x != nil {
-		return x.ChanPoint
+		return x.Types
 	}
 	return nil
 }
 
-func (x *UpdateChanStatusRequest) GetAction() ChanStatusAction {
+func (x *SubscribeAllRequest) GetChannelPoints() []*lnrpc.ChannelPoint {
 	if x != nil {
-		return x.Action
+		return x.ChannelPoints
 	}
-	return ChanStatusAction_ENABLE
+	return nil
 }
 
-type UpdateChanStatusResponse struct {
+func (x *SubscribeAllRequest) GetPeers() [][]byte {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+type SubscribeAllEvent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	// The type of the event, which determines the field of the event that is
+	// set.
+	Type SubscribeAllEvent_EventType `protobuf:"varint,1,opt,name=type,proto3,enum=routerrpc.SubscribeAllEvent_EventType" json:"type,omitempty"`
+	// Types that are assignable to Event:
+	//
+	//	*SubscribeAllEvent_ChannelEvent
+	//	*SubscribeAllEvent_PeerEvent
+	//	*SubscribeAllEvent_HtlcEvent
+	//	*SubscribeAllEvent_Invoice
+	//	*SubscribeAllEvent_Payment
+	//	*SubscribeAllEvent_Block
+	Event isSubscribeAllEvent_Event `protobuf_oneof:"event"`
 }
 
-func (x *UpdateChanStatusResponse) Reset() {
-	*x = UpdateChanStatusResponse{}
+func (x *SubscribeAllEvent) Reset() {
+	*x = SubscribeAllEvent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[40]
+		mi := &file_routerrpc_router_proto_msgTypes[41]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *UpdateChanStatusResponse) String() string {
+func (x *SubscribeAllEvent) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateChanStatusResponse) ProtoMessage() {}
+func (*SubscribeAllEvent) ProtoMessage() {}
 
-func (x *UpdateChanStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[40]
+func (x *SubscribeAllEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[41]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3432,68 +3596,115 @@ func (x *UpdateChanStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateChanStatusResponse.ProtoReflect.Descriptor instead.
-func (*UpdateChanStatusResponse) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use SubscribeAllEvent.ProtoReflect.Descriptor instead.
+func (*SubscribeAllEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{41}
 }
 
-type AddAliasesRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *SubscribeAllEvent) GetType() SubscribeAllEvent_EventType {
+	if x != nil {
+		return x.Type
+	}
+	return SubscribeAllEvent_CHANNEL
+}
 
-	AliasMaps []*lnrpc.AliasMap `protobuf:"bytes,1,rep,name=alias_maps,json=aliasMaps,proto3" json:"alias_maps,omitempty"`
+func (m *SubscribeAllEvent) GetEvent() isSubscribeAllEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
 }
 
-func (x *AddAliasesRequest) Reset() {
-	*x = AddAliasesRequest{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[41]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *SubscribeAllEvent) GetChannelEvent() *lnrpc.ChannelEventUpdate {
+	if x, ok := x.GetEvent().(*SubscribeAllEvent_ChannelEvent); ok {
+		return x.ChannelEvent
 	}
+	return nil
 }
 
-func (x *AddAliasesRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *SubscribeAllEvent) GetPeerEvent() *lnrpc.PeerEvent {
+	if x, ok := x.GetEvent().(*SubscribeAllEvent_PeerEvent); ok {
+		return x.PeerEvent
+	}
+	return nil
 }
 
-func (*AddAliasesRequest) ProtoMessage() {}
+func (x *SubscribeAllEvent) GetHtlcEvent() *HtlcEvent {
+	if x, ok := x.GetEvent().(*SubscribeAllEvent_HtlcEvent); ok {
+		return x.HtlcEvent
+	}
+	return nil
+}
 
-func (x *AddAliasesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[41]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+func (x *SubscribeAllEvent) GetInvoice() *lnrpc.Invoice {
+	if x, ok := x.GetEvent().(*SubscribeAllEvent_Invoice); ok {
+		return x.Invoice
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use AddAliasesRequest.ProtoReflect.Descriptor instead.
-func (*AddAliasesRequest) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{41}
+func (x *SubscribeAllEvent) GetPayment() *lnrpc.Payment {
+	if x, ok := x.GetEvent().(*SubscribeAllEvent_Payment); ok {
+		return x.Payment
+	}
+	return nil
 }
 
-func (x *AddAliasesRequest) GetAliasMaps() []*lnrpc.AliasMap {
-	if x != nil {
-		return x.AliasMaps
+func (x *SubscribeAllEvent) GetBlock() *chainrpc.BlockEpoch {
+	if x, ok := x.GetEvent().(*SubscribeAllEvent_Block); ok {
+		return x.Block
 	}
 	return nil
 }
 
-type AddAliasesResponse struct {
+type isSubscribeAllEvent_Event interface {
+	isSubscribeAllEvent_Event()
+}
+
+type SubscribeAllEvent_ChannelEvent struct {
+	ChannelEvent *lnrpc.ChannelEventUpdate `protobuf:"bytes,2,opt,name=channel_event,json=channelEvent,proto3,oneof"`
+}
+
+type SubscribeAllEvent_PeerEvent struct {
+	PeerEvent *lnrpc.PeerEvent `protobuf:"bytes,3,opt,name=peer_event,json=peerEvent,proto3,oneof"`
+}
+
+type SubscribeAllEvent_HtlcEvent struct {
+	HtlcEvent *HtlcEvent `protobuf:"bytes,4,opt,name=htlc_event,json=htlcEvent,proto3,oneof"`
+}
+
+type SubscribeAllEvent_Invoice struct {
+	Invoice *lnrpc.Invoice `protobuf:"bytes,5,opt,name=invoice,proto3,oneof"`
+}
+
+type SubscribeAllEvent_Payment struct {
+	Payment *lnrpc.Payment `protobuf:"bytes,6,opt,name=payment,proto3,oneof"`
+}
+
+type SubscribeAllEvent_Block struct {
+	Block *chainrpc.BlockEpoch `protobuf:"bytes,7,opt,name=block,proto3,oneof"`
+}
+
+func (*SubscribeAllEvent_ChannelEvent) isSubscribeAllEvent_Event() {}
+
+func (*SubscribeAllEvent_PeerEvent) isSubscribeAllEvent_Event() {}
+
+func (*SubscribeAllEvent_HtlcEvent) isSubscribeAllEvent_Event() {}
+
+func (*SubscribeAllEvent_Invoice) isSubscribeAllEvent_Event() {}
+
+func (*SubscribeAllEvent_Payment) isSubscribeAllEvent_Event() {}
+
+func (*SubscribeAllEvent_Block) isSubscribeAllEvent_Event() {}
+
+type SubscribeStuckHtlcsRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	AliasMaps []*lnrpc.AliasMap `protobuf:"bytes,1,rep,name=alias_maps,json=aliasMaps,proto3" json:"alias_maps,omitempty"`
 }
 
-func (x *AddAliasesResponse) Reset() {
-	*x = AddAliasesResponse{}
+func (x *SubscribeStuckHtlcsRequest) Reset() {
+	*x = SubscribeStuckHtlcsRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[42]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3501,13 +3712,13 @@ func (x *AddAliasesResponse) Reset() {
 	}
 }
 
-func (x *AddAliasesResponse) String() string {
+func (x *SubscribeStuckHtlcsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddAliasesResponse) ProtoMessage() {}
+func (*SubscribeStuckHtlcsRequest) ProtoMessage() {}
 
-func (x *AddAliasesResponse) ProtoReflect() protoreflect.Message {
+func (x *SubscribeStuckHtlcsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[42]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3519,28 +3730,38 @@ func (x *AddAliasesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddAliasesResponse.ProtoReflect.Descriptor instead.
-func (*AddAliasesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SubscribeStuckHtlcsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeStuckHtlcsRequest) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *AddAliasesResponse) GetAliasMaps() []*lnrpc.AliasMap {
-	if x != nil {
-		return x.AliasMaps
-	}
-	return nil
-}
-
-type DeleteAliasesRequest struct {
+type StuckHtlc struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	AliasMaps []*lnrpc.AliasMap `protobuf:"bytes,1,rep,name=alias_maps,json=aliasMaps,proto3" json:"alias_maps,omitempty"`
-}
-
-func (x *DeleteAliasesRequest) Reset() {
-	*x = DeleteAliasesRequest{}
+	// The incoming circuit key of the htlc. The incoming channel id is zero for
+	// payments that we initiated.
+	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
+	// The outgoing circuit key of the htlc.
+	OutgoingCircuitKey *CircuitKey `protobuf:"bytes,2,opt,name=outgoing_circuit_key,json=outgoingCircuitKey,proto3" json:"outgoing_circuit_key,omitempty"`
+	// The payment hash of the htlc.
+	PaymentHash []byte `protobuf:"bytes,3,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	// The amount of the incoming htlc in msat.
+	IncomingAmtMsat uint64 `protobuf:"varint,4,opt,name=incoming_amt_msat,json=incomingAmtMsat,proto3" json:"incoming_amt_msat,omitempty"`
+	// The amount of the outgoing htlc in msat.
+	OutgoingAmtMsat uint64 `protobuf:"varint,5,opt,name=outgoing_amt_msat,json=outgoingAmtMsat,proto3" json:"outgoing_amt_msat,omitempty"`
+	// The unix timestamp in nanoseconds at which the htlc was first seen
+	// pending. Htlcs that were already pending when lnd started are assumed to
+	// have been added at startup.
+	FirstSeenNs int64 `protobuf:"varint,6,opt,name=first_seen_ns,json=firstSeenNs,proto3" json:"first_seen_ns,omitempty"`
+	// Whether the peer of the outgoing channel was reconnected to resolve the
+	// htlc, which is only done if htlcswitch.stuckhtlcreconnect is set.
+	Remediated bool `protobuf:"varint,7,opt,name=remediated,proto3" json:"remediated,omitempty"`
+}
+
+func (x *StuckHtlc) Reset() {
+	*x = StuckHtlc{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_routerrpc_router_proto_msgTypes[43]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3548,13 +3769,13 @@ func (x *DeleteAliasesRequest) Reset() {
 	}
 }
 
-func (x *DeleteAliasesRequest) String() string {
+func (x *StuckHtlc) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteAliasesRequest) ProtoMessage() {}
+func (*StuckHtlc) ProtoMessage() {}
 
-func (x *DeleteAliasesRequest) ProtoReflect() protoreflect.Message {
+func (x *StuckHtlc) ProtoReflect() protoreflect.Message {
 	mi := &file_routerrpc_router_proto_msgTypes[43]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -3566,926 +3787,4147 @@ func (x *DeleteAliasesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteAliasesRequest.ProtoReflect.Descriptor instead.
-func (*DeleteAliasesRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use StuckHtlc.ProtoReflect.Descriptor instead.
+func (*StuckHtlc) Descriptor() ([]byte, []int) {
 	return file_routerrpc_router_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *DeleteAliasesRequest) GetAliasMaps() []*lnrpc.AliasMap {
+func (x *StuckHtlc) GetIncomingCircuitKey() *CircuitKey {
 	if x != nil {
-		return x.AliasMaps
+		return x.IncomingCircuitKey
 	}
 	return nil
 }
 
-type DeleteAliasesResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
+func (x *StuckHtlc) GetOutgoingCircuitKey() *CircuitKey {
+	if x != nil {
+		return x.OutgoingCircuitKey
+	}
+	return nil
+}
 
-	AliasMaps []*lnrpc.AliasMap `protobuf:"bytes,1,rep,name=alias_maps,json=aliasMaps,proto3" json:"alias_maps,omitempty"`
+func (x *StuckHtlc) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
 }
 
-func (x *DeleteAliasesResponse) Reset() {
-	*x = DeleteAliasesResponse{}
-	if protoimpl.UnsafeEnabled {
-		mi := &file_routerrpc_router_proto_msgTypes[44]
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		ms.StoreMessageInfo(mi)
+func (x *StuckHtlc) GetIncomingAmtMsat() uint64 {
+	if x != nil {
+		return x.IncomingAmtMsat
 	}
+	return 0
 }
 
-func (x *DeleteAliasesResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *StuckHtlc) GetOutgoingAmtMsat() uint64 {
+	if x != nil {
+		return x.OutgoingAmtMsat
+	}
+	return 0
 }
 
-func (*DeleteAliasesResponse) ProtoMessage() {}
+func (x *StuckHtlc) GetFirstSeenNs() int64 {
+	if x != nil {
+		return x.FirstSeenNs
+	}
+	return 0
+}
 
-func (x *DeleteAliasesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_routerrpc_router_proto_msgTypes[44]
-	if protoimpl.UnsafeEnabled && x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+func (x *StuckHtlc) GetRemediated() bool {
+	if x != nil {
+		return x.Remediated
+	}
+	return false
+}
+
+type SubscribeHtlcEventsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SubscribeHtlcEventsRequest) Reset() {
+	*x = SubscribeHtlcEventsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[44]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeHtlcEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeHtlcEventsRequest) ProtoMessage() {}
+
+func (x *SubscribeHtlcEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[44]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeHtlcEventsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeHtlcEventsRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{44}
+}
+
+// HtlcEvent contains the htlc event that was processed. These are served on a
+// best-effort basis; events are not persisted, delivery is not guaranteed
+// (in the event of a crash in the switch, forward events may be lost) and
+// some events may be replayed upon restart. Events consumed from this package
+// should be de-duplicated by the htlc's unique combination of incoming and
+// outgoing channel id and htlc id. [EXPERIMENTAL]
+type HtlcEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short channel id that the incoming htlc arrived at our node on. This
+	// value is zero for sends.
+	IncomingChannelId uint64 `protobuf:"varint,1,opt,name=incoming_channel_id,json=incomingChannelId,proto3" json:"incoming_channel_id,omitempty"`
+	// The short channel id that the outgoing htlc left our node on. This value
+	// is zero for receives.
+	OutgoingChannelId uint64 `protobuf:"varint,2,opt,name=outgoing_channel_id,json=outgoingChannelId,proto3" json:"outgoing_channel_id,omitempty"`
+	// Incoming id is the index of the incoming htlc in the incoming channel.
+	// This value is zero for sends.
+	IncomingHtlcId uint64 `protobuf:"varint,3,opt,name=incoming_htlc_id,json=incomingHtlcId,proto3" json:"incoming_htlc_id,omitempty"`
+	// Outgoing id is the index of the outgoing htlc in the outgoing channel.
+	// This value is zero for receives.
+	OutgoingHtlcId uint64 `protobuf:"varint,4,opt,name=outgoing_htlc_id,json=outgoingHtlcId,proto3" json:"outgoing_htlc_id,omitempty"`
+	// The time in unix nanoseconds that the event occurred.
+	TimestampNs uint64 `protobuf:"varint,5,opt,name=timestamp_ns,json=timestampNs,proto3" json:"timestamp_ns,omitempty"`
+	// The event type indicates whether the htlc was part of a send, receive or
+	// forward.
+	EventType HtlcEvent_EventType `protobuf:"varint,6,opt,name=event_type,json=eventType,proto3,enum=routerrpc.HtlcEvent_EventType" json:"event_type,omitempty"`
+	// Types that are assignable to Event:
+	//
+	//	*HtlcEvent_ForwardEvent
+	//	*HtlcEvent_ForwardFailEvent
+	//	*HtlcEvent_SettleEvent
+	//	*HtlcEvent_LinkFailEvent
+	//	*HtlcEvent_SubscribedEvent
+	//	*HtlcEvent_FinalHtlcEvent
+	Event isHtlcEvent_Event `protobuf_oneof:"event"`
+	// The correlation id is shared by all events of a single htlc, from its
+	// forward through to its settle or failure and its final resolution. For
+	// forwards and receives this is the incoming circuit key of the htlc. For
+	// sends, the channel id is zero and the htlc id is the attempt id of the
+	// payment.
+	CorrelationId *CircuitKey `protobuf:"bytes,13,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+}
+
+func (x *HtlcEvent) Reset() {
+	*x = HtlcEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[45]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HtlcEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HtlcEvent) ProtoMessage() {}
+
+func (x *HtlcEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[45]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
 			ms.StoreMessageInfo(mi)
 		}
-		return ms
-	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use DeleteAliasesResponse.ProtoReflect.Descriptor instead.
-func (*DeleteAliasesResponse) Descriptor() ([]byte, []int) {
-	return file_routerrpc_router_proto_rawDescGZIP(), []int{44}
-}
-
-func (x *DeleteAliasesResponse) GetAliasMaps() []*lnrpc.AliasMap {
-	if x != nil {
-		return x.AliasMaps
-	}
-	return nil
-}
-
-var File_routerrpc_router_proto protoreflect.FileDescriptor
-
-var file_routerrpc_router_proto_rawDesc = []byte{
-	0x0a, 0x16, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2f, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x1a, 0x0f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd1, 0x09, 0x0a, 0x12, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79,
-	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64,
-	0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65, 0x73, 0x74, 0x12,
-	0x10, 0x0a, 0x03, 0x61, 0x6d, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x61, 0x6d,
-	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73,
-	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x48, 0x61, 0x73, 0x68, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x63, 0x6c,
-	0x74, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
-	0x66, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6c, 0x74, 0x76, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x27,
-	0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x6f,
-	0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0e, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
-	0x12, 0x22, 0x0a, 0x0d, 0x66, 0x65, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x73, 0x61,
-	0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x66, 0x65, 0x65, 0x4c, 0x69, 0x6d, 0x69,
-	0x74, 0x53, 0x61, 0x74, 0x12, 0x2e, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
-	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x42, 0x04,
-	0x18, 0x01, 0x30, 0x01, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68,
-	0x61, 0x6e, 0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x74, 0x76, 0x5f, 0x6c, 0x69, 0x6d,
-	0x69, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x6c, 0x74, 0x76, 0x4c, 0x69,
-	0x6d, 0x69, 0x74, 0x12, 0x31, 0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x68, 0x69, 0x6e,
-	0x74, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
-	0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x48, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x64, 0x0a, 0x13, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x63,
-	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x0b, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x34, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
-	0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x2e, 0x44, 0x65, 0x73, 0x74, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x64, 0x65, 0x73, 0x74, 0x43,
-	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x19, 0x0a, 0x08,
-	0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
-	0x61, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x66, 0x65, 0x65, 0x5f, 0x6c,
-	0x69, 0x6d, 0x69, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x0c, 0x66, 0x65, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x26, 0x0a,
-	0x0f, 0x6c, 0x61, 0x73, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79,
-	0x18, 0x0e, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x50,
-	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x73,
-	0x65, 0x6c, 0x66, 0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x53, 0x65, 0x6c, 0x66, 0x50, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x12, 0x36, 0x0a, 0x0d, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x65, 0x61, 0x74,
-	0x75, 0x72, 0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6c, 0x6e, 0x72,
-	0x70, 0x63, 0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x69, 0x74, 0x52, 0x0c, 0x64,
-	0x65, 0x73, 0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d,
-	0x61, 0x78, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08,
-	0x6d, 0x61, 0x78, 0x50, 0x61, 0x72, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f, 0x5f, 0x69,
-	0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18,
-	0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68,
-	0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6f, 0x75, 0x74, 0x67,
-	0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x13, 0x20,
-	0x03, 0x28, 0x04, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61,
-	0x6e, 0x49, 0x64, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
-	0x61, 0x64, 0x64, 0x72, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x12, 0x2d, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x5f, 0x73,
-	0x68, 0x61, 0x72, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x15,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x53, 0x68, 0x61, 0x72, 0x64, 0x53, 0x69,
-	0x7a, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x6d, 0x70, 0x18, 0x16, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x03, 0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65,
-	0x5f, 0x70, 0x72, 0x65, 0x66, 0x18, 0x17, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x74, 0x69, 0x6d,
-	0x65, 0x50, 0x72, 0x65, 0x66, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x61,
-	0x62, 0x6c, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65,
-	0x6c, 0x61, 0x62, 0x6c, 0x65, 0x12, 0x71, 0x0a, 0x18, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x68,
-	0x6f, 0x70, 0x5f, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x73, 0x18, 0x19, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43,
-	0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72,
-	0x79, 0x52, 0x15, 0x66, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f,
-	0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x1a, 0x44, 0x0a, 0x16, 0x44, 0x65, 0x73, 0x74,
-	0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x48,
-	0x0a, 0x1a, 0x46, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d,
-	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
-	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
-	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x68, 0x0a, 0x13, 0x54, 0x72, 0x61, 0x63,
-	0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61,
-	0x73, 0x68, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f, 0x5f, 0x69, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68,
-	0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52,
-	0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x73, 0x22, 0x46, 0x0a, 0x14, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f,
-	0x5f, 0x69, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69,
-	0x67, 0x68, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x81, 0x01, 0x0a, 0x0f, 0x52,
-	0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12,
-	0x0a, 0x04, 0x64, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65,
-	0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x06, 0x61, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x70,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x22, 0xa8,
-	0x01, 0x0a, 0x10, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x66,
-	0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x72,
-	0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x46, 0x65, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x26, 0x0a,
-	0x0f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x74, 0x69, 0x6d, 0x65, 0x4c, 0x6f, 0x63, 0x6b,
-	0x44, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x42, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
-	0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e,
-	0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x46, 0x61, 0x69,
-	0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c,
-	0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0xbc, 0x02, 0x0a, 0x12, 0x53, 0x65,
-	0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48,
-	0x61, 0x73, 0x68, 0x12, 0x22, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65,
-	0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x6b, 0x69, 0x70, 0x5f,
-	0x74, 0x65, 0x6d, 0x70, 0x5f, 0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
-	0x73, 0x6b, 0x69, 0x70, 0x54, 0x65, 0x6d, 0x70, 0x45, 0x72, 0x72, 0x12, 0x71, 0x0a, 0x18, 0x66,
-	0x69, 0x72, 0x73, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f,
-	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f,
-	0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x72,
-	0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72,
-	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x15, 0x66, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f,
-	0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x1a, 0x48,
-	0x0a, 0x1a, 0x46, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d,
-	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
-	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
-	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76,
-	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x5b, 0x0a, 0x13, 0x53, 0x65, 0x6e, 0x64,
-	0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x66,
-	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c,
-	0x6e, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x07, 0x66, 0x61,
-	0x69, 0x6c, 0x75, 0x72, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x22, 0x1d, 0x0a, 0x1b, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x22, 0x51, 0x0a, 0x1b, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x2c, 0x0a, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16,
-	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x48,
-	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x4a, 0x04, 0x08,
-	0x01, 0x10, 0x02, 0x22, 0x62, 0x0a, 0x1c, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50,
-	0x61, 0x69, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x05, 0x70, 0x61, 0x69, 0x72,
-	0x73, 0x12, 0x14, 0x0a, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x05, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x22, 0x1f, 0x0a, 0x1d, 0x58, 0x49, 0x6d, 0x70, 0x6f,
-	0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x8a, 0x01, 0x0a, 0x0b, 0x50, 0x61, 0x69,
-	0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65,
-	0x5f, 0x66, 0x72, 0x6f, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6e, 0x6f, 0x64,
-	0x65, 0x46, 0x72, 0x6f, 0x6d, 0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x74, 0x6f,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x54, 0x6f, 0x12, 0x2d,
-	0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72,
-	0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x4a, 0x04, 0x08,
-	0x03, 0x10, 0x04, 0x4a, 0x04, 0x08, 0x04, 0x10, 0x05, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x4a,
-	0x04, 0x08, 0x06, 0x10, 0x07, 0x22, 0xe8, 0x01, 0x0a, 0x08, 0x50, 0x61, 0x69, 0x72, 0x44, 0x61,
-	0x74, 0x61, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x12,
-	0x20, 0x0a, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x66, 0x61, 0x69, 0x6c, 0x41, 0x6d, 0x74, 0x53, 0x61,
-	0x74, 0x12, 0x22, 0x0a, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73,
-	0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x41, 0x6d,
-	0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
-	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x75, 0x63, 0x63,
-	0x65, 0x73, 0x73, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x0d, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x41, 0x6d, 0x74, 0x53, 0x61, 0x74,
-	0x12, 0x28, 0x0a, 0x10, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x61, 0x6d, 0x74, 0x5f,
-	0x6d, 0x73, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73, 0x75, 0x63, 0x63,
-	0x65, 0x73, 0x73, 0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04,
-	0x22, 0x20, 0x0a, 0x1e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
-	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x22, 0x5a, 0x0a, 0x1f, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x37, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
-	0x63, 0x2e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
-	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x59,
-	0x0a, 0x1e, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74,
-	0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x37, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x21, 0x0a, 0x1f, 0x53, 0x65, 0x74,
-	0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x89, 0x04, 0x0a,
-	0x14, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43,
-	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2e, 0x0a, 0x11, 0x68, 0x61, 0x6c, 0x66, 0x5f, 0x6c, 0x69,
-	0x66, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x42, 0x02, 0x18, 0x01, 0x52, 0x0f, 0x68, 0x61, 0x6c, 0x66, 0x4c, 0x69, 0x66, 0x65, 0x53, 0x65,
-	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x2b, 0x0a, 0x0f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x72, 0x6f,
-	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x42, 0x02,
-	0x18, 0x01, 0x52, 0x0e, 0x68, 0x6f, 0x70, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69,
-	0x74, 0x79, 0x12, 0x1a, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x02, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x36,
-	0x0a, 0x17, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x15, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52,
-	0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x43, 0x0a, 0x1e, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75,
-	0x6d, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x78, 0x5f,
-	0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x1b,
-	0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65,
-	0x6c, 0x61, 0x78, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x46, 0x0a, 0x05, 0x6d,
-	0x6f, 0x64, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x30, 0x2e, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
-	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x72, 0x6f, 0x62,
-	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x05, 0x6d, 0x6f,
-	0x64, 0x65, 0x6c, 0x12, 0x38, 0x0a, 0x07, 0x61, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x41, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65,
-	0x72, 0x73, 0x48, 0x00, 0x52, 0x07, 0x61, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x12, 0x38, 0x0a,
-	0x07, 0x62, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c,
-	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x69, 0x6d, 0x6f, 0x64,
-	0x61, 0x6c, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x48, 0x00, 0x52, 0x07,
-	0x62, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x22, 0x2c, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x62, 0x61,
-	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x0b, 0x0a, 0x07, 0x41,
-	0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x49, 0x4d, 0x4f,
-	0x44, 0x41, 0x4c, 0x10, 0x01, 0x42, 0x11, 0x0a, 0x0f, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74,
-	0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x72, 0x0a, 0x11, 0x42, 0x69, 0x6d, 0x6f,
-	0x64, 0x61, 0x6c, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x1f, 0x0a,
-	0x0b, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x01, 0x52, 0x0a, 0x6e, 0x6f, 0x64, 0x65, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1d,
-	0x0a, 0x0a, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x09, 0x73, 0x63, 0x61, 0x6c, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x1d, 0x0a,
-	0x0a, 0x64, 0x65, 0x63, 0x61, 0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x09, 0x64, 0x65, 0x63, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xad, 0x01, 0x0a,
-	0x11, 0x41, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65,
-	0x72, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x68, 0x61, 0x6c, 0x66, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x5f,
-	0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x68,
-	0x61, 0x6c, 0x66, 0x4c, 0x69, 0x66, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x27,
-	0x0a, 0x0f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
-	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0e, 0x68, 0x6f, 0x70, 0x50, 0x72, 0x6f, 0x62,
-	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68,
-	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12,
-	0x2b, 0x0a, 0x11, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x66, 0x72, 0x61, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x63, 0x61, 0x70, 0x61,
-	0x63, 0x69, 0x74, 0x79, 0x46, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x6a, 0x0a, 0x17,
-	0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x72, 0x6f, 0x6d, 0x5f,
-	0x6e, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x66, 0x72, 0x6f, 0x6d,
-	0x4e, 0x6f, 0x64, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x74, 0x6f, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x74, 0x6f, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x19, 0x0a,
-	0x08, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x07, 0x61, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x6b, 0x0a, 0x18, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c,
-	0x69, 0x74, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x62, 0x61,
-	0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x2d, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72,
-	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x68, 0x69,
-	0x73, 0x74, 0x6f, 0x72, 0x79, 0x22, 0x86, 0x03, 0x0a, 0x11, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52,
-	0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61,
-	0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61,
-	0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f,
-	0x63, 0x6c, 0x74, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6c, 0x74, 0x76, 0x44, 0x65, 0x6c, 0x74, 0x61,
-	0x12, 0x2c, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61,
-	0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x0e,
-	0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x1f,
-	0x0a, 0x0b, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x73, 0x18, 0x04, 0x20,
-	0x03, 0x28, 0x0c, 0x52, 0x0a, 0x68, 0x6f, 0x70, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x73, 0x12,
-	0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x41, 0x64,
-	0x64, 0x72, 0x12, 0x70, 0x0a, 0x18, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f,
-	0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x06,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x2e, 0x46, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f,
-	0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x15, 0x66,
-	0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x73, 0x1a, 0x48, 0x0a, 0x1a, 0x46, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70,
-	0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x38,
-	0x0a, 0x12, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74,
-	0x65, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x22, 0x1c, 0x0a, 0x1a, 0x53, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x86, 0x06, 0x0a, 0x09, 0x48, 0x74, 0x6c, 0x63, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67,
-	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x11, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x13, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
-	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x11, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x6e,
-	0x65, 0x6c, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67,
-	0x5f, 0x68, 0x74, 0x6c, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e,
-	0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x64, 0x12, 0x28,
-	0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x68, 0x74, 0x6c, 0x63, 0x5f,
-	0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69,
-	0x6e, 0x67, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69, 0x6d, 0x65,
-	0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0b,
-	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x12, 0x3d, 0x0a, 0x0a, 0x65,
-	0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52,
-	0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x3e, 0x0a, 0x0d, 0x66, 0x6f,
-	0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x17, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f,
-	0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0c, 0x66, 0x6f,
-	0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x4b, 0x0a, 0x12, 0x66, 0x6f,
-	0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
-	0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x46, 0x61,
-	0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3b, 0x0a, 0x0c, 0x73, 0x65, 0x74, 0x74, 0x6c,
-	0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x45,
-	0x76, 0x65, 0x6e, 0x74, 0x12, 0x42, 0x0a, 0x0f, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x66, 0x61, 0x69,
-	0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x6e, 0x6b, 0x46, 0x61,
-	0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0d, 0x6c, 0x69, 0x6e, 0x6b, 0x46,
-	0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x47, 0x0a, 0x10, 0x73, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
-	0x52, 0x0f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x12, 0x45, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x68, 0x74, 0x6c, 0x63, 0x5f,
-	0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x48, 0x74, 0x6c,
-	0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x48,
-	0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x3c, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
-	0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x45, 0x4e, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07,
-	0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4f, 0x52,
-	0x57, 0x41, 0x52, 0x44, 0x10, 0x03, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22,
-	0xbc, 0x01, 0x0a, 0x08, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x2b, 0x0a, 0x11,
-	0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63,
-	0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e,
-	0x67, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2b, 0x0a, 0x11, 0x6f, 0x75, 0x74,
-	0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x54, 0x69,
-	0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2a, 0x0a, 0x11, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69,
-	0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x04, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x74, 0x4d, 0x73,
-	0x61, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x61,
-	0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x6f,
-	0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x37,
-	0x0a, 0x0c, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x27,
-	0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72,
-	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66,
-	0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x12, 0x0a, 0x10, 0x46, 0x6f, 0x72, 0x77, 0x61,
-	0x72, 0x64, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x29, 0x0a, 0x0b, 0x53,
-	0x65, 0x74, 0x74, 0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72,
-	0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72,
-	0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x22, 0x46, 0x0a, 0x0e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x48,
-	0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x74, 0x74,
-	0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x65, 0x74, 0x74, 0x6c,
-	0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x66, 0x66, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x66, 0x66, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x22, 0x11,
-	0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e,
-	0x74, 0x22, 0xdf, 0x01, 0x0a, 0x0d, 0x4c, 0x69, 0x6e, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74,
-	0x6c, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x3d, 0x0a, 0x0c,
-	0x77, 0x69, 0x72, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75,
-	0x72, 0x65, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x0b,
-	0x77, 0x69, 0x72, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x3f, 0x0a, 0x0e, 0x66,
-	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
-	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x52, 0x0d, 0x66,
-	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x25, 0x0a, 0x0e,
-	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x53, 0x74, 0x72,
-	0x69, 0x6e, 0x67, 0x22, 0x8a, 0x01, 0x0a, 0x0d, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53,
-	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73,
-	0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65,
-	0x12, 0x28, 0x0a, 0x05, 0x68, 0x74, 0x6c, 0x63, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x12, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x54, 0x4c, 0x43, 0x41, 0x74, 0x74, 0x65,
-	0x6d, 0x70, 0x74, 0x52, 0x05, 0x68, 0x74, 0x6c, 0x63, 0x73, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04,
-	0x22, 0x3e, 0x0a, 0x0a, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x17,
-	0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52,
-	0x06, 0x63, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x68, 0x74, 0x6c, 0x63, 0x5f,
-	0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x68, 0x74, 0x6c, 0x63, 0x49, 0x64,
-	0x22, 0xa7, 0x06, 0x0a, 0x1b, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63,
-	0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x47, 0x0a, 0x14, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x69, 0x72,
-	0x63, 0x75, 0x69, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15,
-	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x75,
-	0x69, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43,
-	0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x30, 0x0a, 0x14, 0x69, 0x6e, 0x63,
-	0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61,
-	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e,
-	0x67, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x69,
-	0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x06,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x45, 0x78,
-	0x70, 0x69, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
-	0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x3b, 0x0a, 0x1a, 0x6f, 0x75, 0x74, 0x67, 0x6f,
-	0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x63, 0x68,
-	0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x17, 0x6f, 0x75, 0x74,
-	0x67, 0x6f, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x43, 0x68,
-	0x61, 0x6e, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
-	0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x04, 0x52, 0x12, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x6f, 0x75,
-	0x6e, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69,
-	0x6e, 0x67, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
-	0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79, 0x12,
-	0x60, 0x0a, 0x0e, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49,
-	0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e,
-	0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74,
-	0x72, 0x79, 0x52, 0x0d, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6e, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x62, 0x18,
-	0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6f, 0x6e, 0x69, 0x6f, 0x6e, 0x42, 0x6c, 0x6f, 0x62,
-	0x12, 0x28, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x68, 0x65,
-	0x69, 0x67, 0x68, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x61, 0x75, 0x74, 0x6f,
-	0x46, 0x61, 0x69, 0x6c, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x74, 0x0a, 0x16, 0x69, 0x6e,
-	0x5f, 0x77, 0x69, 0x72, 0x65, 0x5f, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63,
-	0x6f, 0x72, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3f, 0x2e, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74,
-	0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x2e, 0x49, 0x6e, 0x57, 0x69, 0x72, 0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52,
-	0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x13, 0x69, 0x6e, 0x57,
-	0x69, 0x72, 0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73,
-	0x1a, 0x40, 0x0a, 0x12, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
-	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75,
-	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02,
-	0x38, 0x01, 0x1a, 0x46, 0x0a, 0x18, 0x49, 0x6e, 0x57, 0x69, 0x72, 0x65, 0x43, 0x75, 0x73, 0x74,
-	0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10,
-	0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79,
-	0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xb9, 0x04, 0x0a, 0x1c, 0x46,
-	0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63,
-	0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x14, 0x69,
-	0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x5f,
-	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79,
-	0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69,
-	0x74, 0x4b, 0x65, 0x79, 0x12, 0x3b, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x48, 0x6f, 0x6c, 0x64, 0x46, 0x6f, 0x72, 0x77,
-	0x61, 0x72, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x27, 0x0a,
-	0x0f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x4d,
-	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
-	0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6c,
-	0x6e, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x2e, 0x46, 0x61, 0x69,
-	0x6c, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
-	0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x69, 0x6e, 0x5f, 0x61, 0x6d, 0x6f, 0x75,
-	0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c, 0x69,
-	0x6e, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x6f,
-	0x75, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x6f, 0x75, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d,
-	0x73, 0x61, 0x74, 0x12, 0x78, 0x0a, 0x17, 0x6f, 0x75, 0x74, 0x5f, 0x77, 0x69, 0x72, 0x65, 0x5f,
-	0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x08,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x41, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65,
-	0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x4f, 0x75,
-	0x74, 0x57, 0x69, 0x72, 0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72,
-	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x14, 0x6f, 0x75, 0x74, 0x57, 0x69, 0x72, 0x65,
-	0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x1a, 0x47, 0x0a,
-	0x19, 0x4f, 0x75, 0x74, 0x57, 0x69, 0x72, 0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65,
-	0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
-	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
-	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x82, 0x01, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x43,
-	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x63, 0x68, 0x61,
-	0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
-	0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x41, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x1a, 0x0a, 0x18, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x43, 0x0a, 0x11, 0x41, 0x64, 0x64, 0x41, 0x6c,
-	0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x0a,
-	0x61, 0x6c, 0x69, 0x61, 0x73, 0x5f, 0x6d, 0x61, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x0f, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61,
-	0x70, 0x52, 0x09, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x73, 0x22, 0x44, 0x0a, 0x12,
-	0x41, 0x64, 0x64, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x2e, 0x0a, 0x0a, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x5f, 0x6d, 0x61, 0x70, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x41,
-	0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x52, 0x09, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61,
-	0x70, 0x73, 0x22, 0x46, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x6c, 0x69, 0x61,
-	0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2e, 0x0a, 0x0a, 0x61, 0x6c,
-	0x69, 0x61, 0x73, 0x5f, 0x6d, 0x61, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
-	0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x52,
-	0x09, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x73, 0x22, 0x47, 0x0a, 0x15, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x0a, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x5f, 0x6d, 0x61, 0x70,
-	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e,
-	0x41, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x52, 0x09, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x4d,
-	0x61, 0x70, 0x73, 0x2a, 0x81, 0x04, 0x0a, 0x0d, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44,
-	0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
-	0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x5f, 0x44, 0x45, 0x54, 0x41, 0x49, 0x4c, 0x10,
-	0x01, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x4e, 0x49, 0x4f, 0x4e, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44,
-	0x45, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x4c, 0x49, 0x4e, 0x4b, 0x5f, 0x4e, 0x4f, 0x54, 0x5f,
-	0x45, 0x4c, 0x49, 0x47, 0x49, 0x42, 0x4c, 0x45, 0x10, 0x03, 0x12, 0x14, 0x0a, 0x10, 0x4f, 0x4e,
-	0x5f, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x04,
-	0x12, 0x14, 0x0a, 0x10, 0x48, 0x54, 0x4c, 0x43, 0x5f, 0x45, 0x58, 0x43, 0x45, 0x45, 0x44, 0x53,
-	0x5f, 0x4d, 0x41, 0x58, 0x10, 0x05, 0x12, 0x18, 0x0a, 0x14, 0x49, 0x4e, 0x53, 0x55, 0x46, 0x46,
-	0x49, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x42, 0x41, 0x4c, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x06,
-	0x12, 0x16, 0x0a, 0x12, 0x49, 0x4e, 0x43, 0x4f, 0x4d, 0x50, 0x4c, 0x45, 0x54, 0x45, 0x5f, 0x46,
-	0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x10, 0x07, 0x12, 0x13, 0x0a, 0x0f, 0x48, 0x54, 0x4c, 0x43,
-	0x5f, 0x41, 0x44, 0x44, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x08, 0x12, 0x15, 0x0a,
-	0x11, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x53, 0x5f, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c,
-	0x45, 0x44, 0x10, 0x09, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f,
-	0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x45, 0x44, 0x10, 0x0a, 0x12, 0x15, 0x0a, 0x11, 0x49, 0x4e,
-	0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x44, 0x45, 0x52, 0x50, 0x41, 0x49, 0x44, 0x10,
-	0x0b, 0x12, 0x1b, 0x0a, 0x17, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x45, 0x58, 0x50,
-	0x49, 0x52, 0x59, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x53, 0x4f, 0x4f, 0x4e, 0x10, 0x0c, 0x12, 0x14,
-	0x0a, 0x10, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x4f, 0x50,
-	0x45, 0x4e, 0x10, 0x0d, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x50, 0x50, 0x5f, 0x49, 0x4e, 0x56, 0x4f,
-	0x49, 0x43, 0x45, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x0e, 0x12, 0x14, 0x0a,
-	0x10, 0x41, 0x44, 0x44, 0x52, 0x45, 0x53, 0x53, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43,
-	0x48, 0x10, 0x0f, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x45, 0x54, 0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c,
-	0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x10, 0x12, 0x15, 0x0a, 0x11, 0x53,
-	0x45, 0x54, 0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x4c, 0x4f, 0x57,
-	0x10, 0x11, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x54, 0x5f, 0x4f, 0x56, 0x45, 0x52, 0x50, 0x41,
-	0x49, 0x44, 0x10, 0x12, 0x12, 0x13, 0x0a, 0x0f, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f,
-	0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x10, 0x13, 0x12, 0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x56,
-	0x41, 0x4c, 0x49, 0x44, 0x5f, 0x4b, 0x45, 0x59, 0x53, 0x45, 0x4e, 0x44, 0x10, 0x14, 0x12, 0x13,
-	0x0a, 0x0f, 0x4d, 0x50, 0x50, 0x5f, 0x49, 0x4e, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45, 0x53,
-	0x53, 0x10, 0x15, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x49, 0x52, 0x43, 0x55, 0x4c, 0x41, 0x52, 0x5f,
-	0x52, 0x4f, 0x55, 0x54, 0x45, 0x10, 0x16, 0x2a, 0xae, 0x01, 0x0a, 0x0c, 0x50, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x49, 0x4e, 0x5f, 0x46,
-	0x4c, 0x49, 0x47, 0x48, 0x54, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x55, 0x43, 0x43, 0x45,
-	0x45, 0x44, 0x45, 0x44, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44,
-	0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41,
-	0x49, 0x4c, 0x45, 0x44, 0x5f, 0x4e, 0x4f, 0x5f, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x10, 0x03, 0x12,
-	0x10, 0x0a, 0x0c, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10,
-	0x04, 0x12, 0x24, 0x0a, 0x20, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x49, 0x4e, 0x43, 0x4f,
-	0x52, 0x52, 0x45, 0x43, 0x54, 0x5f, 0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x44, 0x45,
-	0x54, 0x41, 0x49, 0x4c, 0x53, 0x10, 0x05, 0x12, 0x1f, 0x0a, 0x1b, 0x46, 0x41, 0x49, 0x4c, 0x45,
-	0x44, 0x5f, 0x49, 0x4e, 0x53, 0x55, 0x46, 0x46, 0x49, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x42,
-	0x41, 0x4c, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x06, 0x2a, 0x51, 0x0a, 0x18, 0x52, 0x65, 0x73, 0x6f,
-	0x6c, 0x76, 0x65, 0x48, 0x6f, 0x6c, 0x64, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x41, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x45, 0x54, 0x54, 0x4c, 0x45, 0x10, 0x00,
-	0x12, 0x08, 0x0a, 0x04, 0x46, 0x41, 0x49, 0x4c, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45,
-	0x53, 0x55, 0x4d, 0x45, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x45, 0x53, 0x55, 0x4d, 0x45,
-	0x5f, 0x4d, 0x4f, 0x44, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x03, 0x2a, 0x35, 0x0a, 0x10, 0x43,
-	0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
-	0x0a, 0x0a, 0x06, 0x45, 0x4e, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x44,
-	0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x41, 0x55, 0x54, 0x4f,
-	0x10, 0x02, 0x32, 0xe8, 0x0d, 0x0a, 0x06, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x12, 0x40, 0x0a,
-	0x0d, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x56, 0x32, 0x12, 0x1d,
-	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e,
-	0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12,
-	0x42, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x56,
-	0x32, 0x12, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72,
-	0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x4b, 0x0a, 0x10, 0x45, 0x73, 0x74, 0x69, 0x6d,
-	0x61, 0x74, 0x65, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x12, 0x1a, 0x2e, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
-	0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f,
-	0x75, 0x74, 0x65, 0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
-	0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53,
-	0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x22, 0x03, 0x88, 0x02, 0x01, 0x12, 0x42, 0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x64, 0x54,
-	0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x56, 0x32, 0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
-	0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e,
-	0x48, 0x54, 0x4c, 0x43, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x12, 0x64, 0x0a, 0x13, 0x52,
-	0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
-	0x6f, 0x6c, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52,
-	0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
-	0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x64, 0x0a, 0x13, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
-	0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6a, 0x0a, 0x15, 0x58, 0x49, 0x6d, 0x70, 0x6f,
-	0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
-	0x12, 0x27, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x58, 0x49, 0x6d,
-	0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
-	0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x28, 0x2e, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x17, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
-	0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x29,
-	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x69,
-	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x72, 0x6f, 0x75, 0x74,
-	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73,
-	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
-	0x12, 0x29, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74,
-	0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69,
-	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x10, 0x51, 0x75, 0x65, 0x72, 0x79,
-	0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x22, 0x2e, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f,
-	0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
-	0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72,
-	0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a, 0x0a, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75,
-	0x74, 0x65, 0x12, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42,
-	0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75, 0x69,
-	0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x54, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74, 0x6c, 0x63,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
-	0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74, 0x6c, 0x63,
-	0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e,
-	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76,
-	0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x4d, 0x0a, 0x0b, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79,
-	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
-	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x03, 0x88,
-	0x02, 0x01, 0x30, 0x01, 0x12, 0x4f, 0x0a, 0x0c, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79,
-	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x03,
-	0x88, 0x02, 0x01, 0x30, 0x01, 0x12, 0x66, 0x0a, 0x0f, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74,
-	0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x6f, 0x72, 0x12, 0x27, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
-	0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63,
-	0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f,
-	0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65,
-	0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x28, 0x01, 0x30, 0x01, 0x12, 0x5b, 0x0a,
-	0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
-	0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x53, 0x0a, 0x14, 0x58, 0x41,
-	0x64, 0x64, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x41, 0x6c, 0x69, 0x61, 0x73,
-	0x65, 0x73, 0x12, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x41,
-	0x64, 0x64, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64,
-	0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
-	0x5c, 0x0a, 0x17, 0x58, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x43,
-	0x68, 0x61, 0x6e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x1f, 0x2e, 0x72, 0x6f, 0x75,
-	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x6c, 0x69,
-	0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x72, 0x6f,
-	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x6c,
-	0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x31, 0x5a,
-	0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6c, 0x69, 0x67, 0x68,
-	0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x2f, 0x6c, 0x6e, 0x64,
-	0x2f, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2f, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-}
-
-var (
-	file_routerrpc_router_proto_rawDescOnce sync.Once
-	file_routerrpc_router_proto_rawDescData = file_routerrpc_router_proto_rawDesc
-)
-
-func file_routerrpc_router_proto_rawDescGZIP() []byte {
-	file_routerrpc_router_proto_rawDescOnce.Do(func() {
-		file_routerrpc_router_proto_rawDescData = protoimpl.X.CompressGZIP(file_routerrpc_router_proto_rawDescData)
-	})
-	return file_routerrpc_router_proto_rawDescData
-}
-
-var file_routerrpc_router_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
-var file_routerrpc_router_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
-var file_routerrpc_router_proto_goTypes = []interface{}{
-	(FailureDetail)(0),                         // 0: routerrpc.FailureDetail
-	(PaymentState)(0),                          // 1: routerrpc.PaymentState
-	(ResolveHoldForwardAction)(0),              // 2: routerrpc.ResolveHoldForwardAction
-	(ChanStatusAction)(0),                      // 3: routerrpc.ChanStatusAction
-	(MissionControlConfig_ProbabilityModel)(0), // 4: routerrpc.MissionControlConfig.ProbabilityModel
-	(HtlcEvent_EventType)(0),                   // 5: routerrpc.HtlcEvent.EventType
-	(*SendPaymentRequest)(nil),                 // 6: routerrpc.SendPaymentRequest
-	(*TrackPaymentRequest)(nil),                // 7: routerrpc.TrackPaymentRequest
-	(*TrackPaymentsRequest)(nil),               // 8: routerrpc.TrackPaymentsRequest
-	(*RouteFeeRequest)(nil),                    // 9: routerrpc.RouteFeeRequest
-	(*RouteFeeResponse)(nil),                   // 10: routerrpc.RouteFeeResponse
-	(*SendToRouteRequest)(nil),                 // 11: routerrpc.SendToRouteRequest
-	(*SendToRouteResponse)(nil),                // 12: routerrpc.SendToRouteResponse
-	(*ResetMissionControlRequest)(nil),         // 13: routerrpc.ResetMissionControlRequest
-	(*ResetMissionControlResponse)(nil),        // 14: routerrpc.ResetMissionControlResponse
-	(*QueryMissionControlRequest)(nil),         // 15: routerrpc.QueryMissionControlRequest
-	(*QueryMissionControlResponse)(nil),        // 16: routerrpc.QueryMissionControlResponse
-	(*XImportMissionControlRequest)(nil),       // 17: routerrpc.XImportMissionControlRequest
-	(*XImportMissionControlResponse)(nil),      // 18: routerrpc.XImportMissionControlResponse
-	(*PairHistory)(nil),                        // 19: routerrpc.PairHistory
-	(*PairData)(nil),                           // 20: routerrpc.PairData
-	(*GetMissionControlConfigRequest)(nil),     // 21: routerrpc.GetMissionControlConfigRequest
-	(*GetMissionControlConfigResponse)(nil),    // 22: routerrpc.GetMissionControlConfigResponse
-	(*SetMissionControlConfigRequest)(nil),     // 23: routerrpc.SetMissionControlConfigRequest
-	(*SetMissionControlConfigResponse)(nil),    // 24: routerrpc.SetMissionControlConfigResponse
-	(*MissionControlConfig)(nil),               // 25: routerrpc.MissionControlConfig
-	(*BimodalParameters)(nil),                  // 26: routerrpc.BimodalParameters
-	(*AprioriParameters)(nil),                  // 27: routerrpc.AprioriParameters
-	(*QueryProbabilityRequest)(nil),            // 28: routerrpc.QueryProbabilityRequest
-	(*QueryProbabilityResponse)(nil),           // 29: routerrpc.QueryProbabilityResponse
-	(*BuildRouteRequest)(nil),                  // 30: routerrpc.BuildRouteRequest
-	(*BuildRouteResponse)(nil),                 // 31: routerrpc.BuildRouteResponse
-	(*SubscribeHtlcEventsRequest)(nil),         // 32: routerrpc.SubscribeHtlcEventsRequest
-	(*HtlcEvent)(nil),                          // 33: routerrpc.HtlcEvent
-	(*HtlcInfo)(nil),                           // 34: routerrpc.HtlcInfo
-	(*ForwardEvent)(nil),                       // 35: routerrpc.ForwardEvent
-	(*ForwardFailEvent)(nil),                   // 36: routerrpc.ForwardFailEvent
-	(*SettleEvent)(nil),                        // 37: routerrpc.SettleEvent
-	(*FinalHtlcEvent)(nil),                     // 38: routerrpc.FinalHtlcEvent
-	(*SubscribedEvent)(nil),                    // 39: routerrpc.SubscribedEvent
-	(*LinkFailEvent)(nil),                      // 40: routerrpc.LinkFailEvent
-	(*PaymentStatus)(nil),                      // 41: routerrpc.PaymentStatus
-	(*CircuitKey)(nil),                         // 42: routerrpc.CircuitKey
-	(*ForwardHtlcInterceptRequest)(nil),        // 43: routerrpc.ForwardHtlcInterceptRequest
-	(*ForwardHtlcInterceptResponse)(nil),       // 44: routerrpc.ForwardHtlcInterceptResponse
-	(*UpdateChanStatusRequest)(nil),            // 45: routerrpc.UpdateChanStatusRequest
-	(*UpdateChanStatusResponse)(nil),           // 46: routerrpc.UpdateChanStatusResponse
-	(*AddAliasesRequest)(nil),                  // 47: routerrpc.AddAliasesRequest
-	(*AddAliasesResponse)(nil),                 // 48: routerrpc.AddAliasesResponse
-	(*DeleteAliasesRequest)(nil),               // 49: routerrpc.DeleteAliasesRequest
-	(*DeleteAliasesResponse)(nil),              // 50: routerrpc.DeleteAliasesResponse
-	nil,                                        // 51: routerrpc.SendPaymentRequest.DestCustomRecordsEntry
-	nil,                                        // 52: routerrpc.SendPaymentRequest.FirstHopCustomRecordsEntry
-	nil,                                        // 53: routerrpc.SendToRouteRequest.FirstHopCustomRecordsEntry
-	nil,                                        // 54: routerrpc.BuildRouteRequest.FirstHopCustomRecordsEntry
-	nil,                                        // 55: routerrpc.ForwardHtlcInterceptRequest.CustomRecordsEntry
-	nil,                                        // 56: routerrpc.ForwardHtlcInterceptRequest.InWireCustomRecordsEntry
-	nil,                                        // 57: routerrpc.ForwardHtlcInterceptResponse.OutWireCustomRecordsEntry
-	(*lnrpc.RouteHint)(nil),                    // 58: lnrpc.RouteHint
-	(lnrpc.FeatureBit)(0),                      // 59: lnrpc.FeatureBit
-	(lnrpc.PaymentFailureReason)(0),            // 60: lnrpc.PaymentFailureReason
-	(*lnrpc.Route)(nil),                        // 61: lnrpc.Route
-	(*lnrpc.Failure)(nil),                      // 62: lnrpc.Failure
-	(lnrpc.Failure_FailureCode)(0),             // 63: lnrpc.Failure.FailureCode
-	(*lnrpc.HTLCAttempt)(nil),                  // 64: lnrpc.HTLCAttempt
-	(*lnrpc.ChannelPoint)(nil),                 // 65: lnrpc.ChannelPoint
-	(*lnrpc.AliasMap)(nil),                     // 66: lnrpc.AliasMap
-	(*lnrpc.Payment)(nil),                      // 67: lnrpc.Payment
-}
-var file_routerrpc_router_proto_depIdxs = []int32{
-	58, // 0: routerrpc.SendPaymentRequest.route_hints:type_name -> lnrpc.RouteHint
-	51, // 1: routerrpc.SendPaymentRequest.dest_custom_records:type_name -> routerrpc.SendPaymentRequest.DestCustomRecordsEntry
-	59, // 2: routerrpc.SendPaymentRequest.dest_features:type_name -> lnrpc.FeatureBit
-	52, // 3: routerrpc.SendPaymentRequest.first_hop_custom_records:type_name -> routerrpc.SendPaymentRequest.FirstHopCustomRecordsEntry
-	60, // 4: routerrpc.RouteFeeResponse.failure_reason:type_name -> lnrpc.PaymentFailureReason
-	61, // 5: routerrpc.SendToRouteRequest.route:type_name -> lnrpc.Route
-	53, // 6: routerrpc.SendToRouteRequest.first_hop_custom_records:type_name -> routerrpc.SendToRouteRequest.FirstHopCustomRecordsEntry
-	62, // 7: routerrpc.SendToRouteResponse.failure:type_name -> lnrpc.Failure
-	19, // 8: routerrpc.QueryMissionControlResponse.pairs:type_name -> routerrpc.PairHistory
-	19, // 9: routerrpc.XImportMissionControlRequest.pairs:type_name -> routerrpc.PairHistory
-	20, // 10: routerrpc.PairHistory.history:type_name -> routerrpc.PairData
-	25, // 11: routerrpc.GetMissionControlConfigResponse.config:type_name -> routerrpc.MissionControlConfig
-	25, // 12: routerrpc.SetMissionControlConfigRequest.config:type_name -> routerrpc.MissionControlConfig
-	4,  // 13: routerrpc.MissionControlConfig.model:type_name -> routerrpc.MissionControlConfig.ProbabilityModel
-	27, // 14: routerrpc.MissionControlConfig.apriori:type_name -> routerrpc.AprioriParameters
-	26, // 15: routerrpc.MissionControlConfig.bimodal:type_name -> routerrpc.BimodalParameters
-	20, // 16: routerrpc.QueryProbabilityResponse.history:type_name -> routerrpc.PairData
-	54, // 17: routerrpc.BuildRouteRequest.first_hop_custom_records:type_name -> routerrpc.BuildRouteRequest.FirstHopCustomRecordsEntry
-	61, // 18: routerrpc.BuildRouteResponse.route:type_name -> lnrpc.Route
-	5,  // 19: routerrpc.HtlcEvent.event_type:type_name -> routerrpc.HtlcEvent.EventType
-	35, // 20: routerrpc.HtlcEvent.forward_event:type_name -> routerrpc.ForwardEvent
-	36, // 21: routerrpc.HtlcEvent.forward_fail_event:type_name -> routerrpc.ForwardFailEvent
-	37, // 22: routerrpc.HtlcEvent.settle_event:type_name -> routerrpc.SettleEvent
-	40, // 23: routerrpc.HtlcEvent.link_fail_event:type_name -> routerrpc.LinkFailEvent
-	39, // 24: routerrpc.HtlcEvent.subscribed_event:type_name -> routerrpc.SubscribedEvent
-	38, // 25: routerrpc.HtlcEvent.final_htlc_event:type_name -> routerrpc.FinalHtlcEvent
-	34, // 26: routerrpc.ForwardEvent.info:type_name -> routerrpc.HtlcInfo
-	34, // 27: routerrpc.LinkFailEvent.info:type_name -> routerrpc.HtlcInfo
-	63, // 28: routerrpc.LinkFailEvent.wire_failure:type_name -> lnrpc.Failure.FailureCode
-	0,  // 29: routerrpc.LinkFailEvent.failure_detail:type_name -> routerrpc.FailureDetail
-	1,  // 30: routerrpc.PaymentStatus.state:type_name -> routerrpc.PaymentState
-	64, // 31: routerrpc.PaymentStatus.htlcs:type_name -> lnrpc.HTLCAttempt
-	42, // 32: routerrpc.ForwardHtlcInterceptRequest.incoming_circuit_key:type_name -> routerrpc.CircuitKey
-	55, // 33: routerrpc.ForwardHtlcInterceptRequest.custom_records:type_name -> routerrpc.ForwardHtlcInterceptRequest.CustomRecordsEntry
-	56, // 34: routerrpc.ForwardHtlcInterceptRequest.in_wire_custom_records:type_name -> routerrpc.ForwardHtlcInterceptRequest.InWireCustomRecordsEntry
-	42, // 35: routerrpc.ForwardHtlcInterceptResponse.incoming_circuit_key:type_name -> routerrpc.CircuitKey
-	2,  // 36: routerrpc.ForwardHtlcInterceptResponse.action:type_name -> routerrpc.ResolveHoldForwardAction
-	63, // 37: routerrpc.ForwardHtlcInterceptResponse.failure_code:type_name -> lnrpc.Failure.FailureCode
-	57, // 38: routerrpc.ForwardHtlcInterceptResponse.out_wire_custom_records:type_name -> routerrpc.ForwardHtlcInterceptResponse.OutWireCustomRecordsEntry
-	65, // 39: routerrpc.UpdateChanStatusRequest.chan_point:type_name -> lnrpc.ChannelPoint
-	3,  // 40: routerrpc.UpdateChanStatusRequest.action:type_name -> routerrpc.ChanStatusAction
-	66, // 41: routerrpc.AddAliasesRequest.alias_maps:type_name -> lnrpc.AliasMap
-	66, // 42: routerrpc.AddAliasesResponse.alias_maps:type_name -> lnrpc.AliasMap
-	66, // 43: routerrpc.DeleteAliasesRequest.alias_maps:type_name -> lnrpc.AliasMap
-	66, // 44: routerrpc.DeleteAliasesResponse.alias_maps:type_name -> lnrpc.AliasMap
-	6,  // 45: routerrpc.Router.SendPaymentV2:input_type -> routerrpc.SendPaymentRequest
-	7,  // 46: routerrpc.Router.TrackPaymentV2:input_type -> routerrpc.TrackPaymentRequest
-	8,  // 47: routerrpc.Router.TrackPayments:input_type -> routerrpc.TrackPaymentsRequest
-	9,  // 48: routerrpc.Router.EstimateRouteFee:input_type -> routerrpc.RouteFeeRequest
-	11, // 49: routerrpc.Router.SendToRoute:input_type -> routerrpc.SendToRouteRequest
-	11, // 50: routerrpc.Router.SendToRouteV2:input_type -> routerrpc.SendToRouteRequest
-	13, // 51: routerrpc.Router.ResetMissionControl:input_type -> routerrpc.ResetMissionControlRequest
-	15, // 52: routerrpc.Router.QueryMissionControl:input_type -> routerrpc.QueryMissionControlRequest
-	17, // 53: routerrpc.Router.XImportMissionControl:input_type -> routerrpc.XImportMissionControlRequest
-	21, // 54: routerrpc.Router.GetMissionControlConfig:input_type -> routerrpc.GetMissionControlConfigRequest
-	23, // 55: routerrpc.Router.SetMissionControlConfig:input_type -> routerrpc.SetMissionControlConfigRequest
-	28, // 56: routerrpc.Router.QueryProbability:input_type -> routerrpc.QueryProbabilityRequest
-	30, // 57: routerrpc.Router.BuildRoute:input_type -> routerrpc.BuildRouteRequest
-	32, // 58: routerrpc.Router.SubscribeHtlcEvents:input_type -> routerrpc.SubscribeHtlcEventsRequest
-	6,  // 59: routerrpc.Router.SendPayment:input_type -> routerrpc.SendPaymentRequest
-	7,  // 60: routerrpc.Router.TrackPayment:input_type -> routerrpc.TrackPaymentRequest
-	44, // 61: routerrpc.Router.HtlcInterceptor:input_type -> routerrpc.ForwardHtlcInterceptResponse
-	45, // 62: routerrpc.Router.UpdateChanStatus:input_type -> routerrpc.UpdateChanStatusRequest
-	47, // 63: routerrpc.Router.XAddLocalChanAliases:input_type -> routerrpc.AddAliasesRequest
-	49, // 64: routerrpc.Router.XDeleteLocalChanAliases:input_type -> routerrpc.DeleteAliasesRequest
-	67, // 65: routerrpc.Router.SendPaymentV2:output_type -> lnrpc.Payment
-	67, // 66: routerrpc.Router.TrackPaymentV2:output_type -> lnrpc.Payment
-	67, // 67: routerrpc.Router.TrackPayments:output_type -> lnrpc.Payment
-	10, // 68: routerrpc.Router.EstimateRouteFee:output_type -> routerrpc.RouteFeeResponse
-	12, // 69: routerrpc.Router.SendToRoute:output_type -> routerrpc.SendToRouteResponse
-	64, // 70: routerrpc.Router.SendToRouteV2:output_type -> lnrpc.HTLCAttempt
-	14, // 71: routerrpc.Router.ResetMissionControl:output_type -> routerrpc.ResetMissionControlResponse
-	16, // 72: routerrpc.Router.QueryMissionControl:output_type -> routerrpc.QueryMissionControlResponse
-	18, // 73: routerrpc.Router.XImportMissionControl:output_type -> routerrpc.XImportMissionControlResponse
-	22, // 74: routerrpc.Router.GetMissionControlConfig:output_type -> routerrpc.GetMissionControlConfigResponse
-	24, // 75: routerrpc.Router.SetMissionControlConfig:output_type -> routerrpc.SetMissionControlConfigResponse
-	29, // 76: routerrpc.Router.QueryProbability:output_type -> routerrpc.QueryProbabilityResponse
-	31, // 77: routerrpc.Router.BuildRoute:output_type -> routerrpc.BuildRouteResponse
-	33, // 78: routerrpc.Router.SubscribeHtlcEvents:output_type -> routerrpc.HtlcEvent
-	41, // 79: routerrpc.Router.SendPayment:output_type -> routerrpc.PaymentStatus
-	41, // 80: routerrpc.Router.TrackPayment:output_type -> routerrpc.PaymentStatus
-	43, // 81: routerrpc.Router.HtlcInterceptor:output_type -> routerrpc.ForwardHtlcInterceptRequest
-	46, // 82: routerrpc.Router.UpdateChanStatus:output_type -> routerrpc.UpdateChanStatusResponse
-	48, // 83: routerrpc.Router.XAddLocalChanAliases:output_type -> routerrpc.AddAliasesResponse
-	50, // 84: routerrpc.Router.XDeleteLocalChanAliases:output_type -> routerrpc.DeleteAliasesResponse
-	65, // [65:85] is the sub-list for method output_type
-	45, // [45:65] is the sub-list for method input_type
-	45, // [45:45] is the sub-list for extension type_name
-	45, // [45:45] is the sub-list for extension extendee
-	0,  // [0:45] is the sub-list for field type_name
-}
-
-func init() { file_routerrpc_router_proto_init() }
-func file_routerrpc_router_proto_init() {
-	if File_routerrpc_router_proto != nil {
-		return
-	}
-	if !protoimpl.UnsafeEnabled {
-		file_routerrpc_router_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendPaymentRequest); i {
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HtlcEvent.ProtoReflect.Descriptor instead.
+func (*HtlcEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *HtlcEvent) GetIncomingChannelId() uint64 {
+	if x != nil {
+		return x.IncomingChannelId
+	}
+	return 0
+}
+
+func (x *HtlcEvent) GetOutgoingChannelId() uint64 {
+	if x != nil {
+		return x.OutgoingChannelId
+	}
+	return 0
+}
+
+func (x *HtlcEvent) GetIncomingHtlcId() uint64 {
+	if x != nil {
+		return x.IncomingHtlcId
+	}
+	return 0
+}
+
+func (x *HtlcEvent) GetOutgoingHtlcId() uint64 {
+	if x != nil {
+		return x.OutgoingHtlcId
+	}
+	return 0
+}
+
+func (x *HtlcEvent) GetTimestampNs() uint64 {
+	if x != nil {
+		return x.TimestampNs
+	}
+	return 0
+}
+
+func (x *HtlcEvent) GetEventType() HtlcEvent_EventType {
+	if x != nil {
+		return x.EventType
+	}
+	return HtlcEvent_UNKNOWN
+}
+
+func (m *HtlcEvent) GetEvent() isHtlcEvent_Event {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (x *HtlcEvent) GetForwardEvent() *ForwardEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_ForwardEvent); ok {
+		return x.ForwardEvent
+	}
+	return nil
+}
+
+func (x *HtlcEvent) GetForwardFailEvent() *ForwardFailEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_ForwardFailEvent); ok {
+		return x.ForwardFailEvent
+	}
+	return nil
+}
+
+func (x *HtlcEvent) GetSettleEvent() *SettleEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_SettleEvent); ok {
+		return x.SettleEvent
+	}
+	return nil
+}
+
+func (x *HtlcEvent) GetLinkFailEvent() *LinkFailEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_LinkFailEvent); ok {
+		return x.LinkFailEvent
+	}
+	return nil
+}
+
+func (x *HtlcEvent) GetSubscribedEvent() *SubscribedEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_SubscribedEvent); ok {
+		return x.SubscribedEvent
+	}
+	return nil
+}
+
+func (x *HtlcEvent) GetFinalHtlcEvent() *FinalHtlcEvent {
+	if x, ok := x.GetEvent().(*HtlcEvent_FinalHtlcEvent); ok {
+		return x.FinalHtlcEvent
+	}
+	return nil
+}
+
+func (x *HtlcEvent) GetCorrelationId() *CircuitKey {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return nil
+}
+
+type isHtlcEvent_Event interface {
+	isHtlcEvent_Event()
+}
+
+type HtlcEvent_ForwardEvent struct {
+	ForwardEvent *ForwardEvent `protobuf:"bytes,7,opt,name=forward_event,json=forwardEvent,proto3,oneof"`
+}
+
+type HtlcEvent_ForwardFailEvent struct {
+	ForwardFailEvent *ForwardFailEvent `protobuf:"bytes,8,opt,name=forward_fail_event,json=forwardFailEvent,proto3,oneof"`
+}
+
+type HtlcEvent_SettleEvent struct {
+	SettleEvent *SettleEvent `protobuf:"bytes,9,opt,name=settle_event,json=settleEvent,proto3,oneof"`
+}
+
+type HtlcEvent_LinkFailEvent struct {
+	LinkFailEvent *LinkFailEvent `protobuf:"bytes,10,opt,name=link_fail_event,json=linkFailEvent,proto3,oneof"`
+}
+
+type HtlcEvent_SubscribedEvent struct {
+	SubscribedEvent *SubscribedEvent `protobuf:"bytes,11,opt,name=subscribed_event,json=subscribedEvent,proto3,oneof"`
+}
+
+type HtlcEvent_FinalHtlcEvent struct {
+	FinalHtlcEvent *FinalHtlcEvent `protobuf:"bytes,12,opt,name=final_htlc_event,json=finalHtlcEvent,proto3,oneof"`
+}
+
+func (*HtlcEvent_ForwardEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_ForwardFailEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_SettleEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_LinkFailEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_SubscribedEvent) isHtlcEvent_Event() {}
+
+func (*HtlcEvent_FinalHtlcEvent) isHtlcEvent_Event() {}
+
+type HtlcInfo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The timelock on the incoming htlc.
+	IncomingTimelock uint32 `protobuf:"varint,1,opt,name=incoming_timelock,json=incomingTimelock,proto3" json:"incoming_timelock,omitempty"`
+	// The timelock on the outgoing htlc.
+	OutgoingTimelock uint32 `protobuf:"varint,2,opt,name=outgoing_timelock,json=outgoingTimelock,proto3" json:"outgoing_timelock,omitempty"`
+	// The amount of the incoming htlc.
+	IncomingAmtMsat uint64 `protobuf:"varint,3,opt,name=incoming_amt_msat,json=incomingAmtMsat,proto3" json:"incoming_amt_msat,omitempty"`
+	// The amount of the outgoing htlc.
+	OutgoingAmtMsat uint64 `protobuf:"varint,4,opt,name=outgoing_amt_msat,json=outgoingAmtMsat,proto3" json:"outgoing_amt_msat,omitempty"`
+}
+
+func (x *HtlcInfo) Reset() {
+	*x = HtlcInfo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[46]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HtlcInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HtlcInfo) ProtoMessage() {}
+
+func (x *HtlcInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[46]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HtlcInfo.ProtoReflect.Descriptor instead.
+func (*HtlcInfo) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *HtlcInfo) GetIncomingTimelock() uint32 {
+	if x != nil {
+		return x.IncomingTimelock
+	}
+	return 0
+}
+
+func (x *HtlcInfo) GetOutgoingTimelock() uint32 {
+	if x != nil {
+		return x.OutgoingTimelock
+	}
+	return 0
+}
+
+func (x *HtlcInfo) GetIncomingAmtMsat() uint64 {
+	if x != nil {
+		return x.IncomingAmtMsat
+	}
+	return 0
+}
+
+func (x *HtlcInfo) GetOutgoingAmtMsat() uint64 {
+	if x != nil {
+		return x.OutgoingAmtMsat
+	}
+	return 0
+}
+
+type ForwardEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Info contains details about the htlc that was forwarded.
+	Info *HtlcInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+}
+
+func (x *ForwardEvent) Reset() {
+	*x = ForwardEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[47]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardEvent) ProtoMessage() {}
+
+func (x *ForwardEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[47]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardEvent.ProtoReflect.Descriptor instead.
+func (*ForwardEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ForwardEvent) GetInfo() *HtlcInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type ForwardFailEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Info contains the amounts of the htlc that failed.
+	Info *HtlcInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	// Set if the htlc was failed back because its outgoing htlc was resolved
+	// on chain, rather than by a failure that our peer sent us.
+	OnchainResolution bool `protobuf:"varint,2,opt,name=onchain_resolution,json=onchainResolution,proto3" json:"onchain_resolution,omitempty"`
+}
+
+func (x *ForwardFailEvent) Reset() {
+	*x = ForwardFailEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[48]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardFailEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardFailEvent) ProtoMessage() {}
+
+func (x *ForwardFailEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[48]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardFailEvent.ProtoReflect.Descriptor instead.
+func (*ForwardFailEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ForwardFailEvent) GetInfo() *HtlcInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *ForwardFailEvent) GetOnchainResolution() bool {
+	if x != nil {
+		return x.OnchainResolution
+	}
+	return false
+}
+
+type SettleEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The revealed preimage.
+	Preimage []byte `protobuf:"bytes,1,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// Info contains the amounts of the htlc that was settled.
+	Info *HtlcInfo `protobuf:"bytes,2,opt,name=info,proto3" json:"info,omitempty"`
+}
+
+func (x *SettleEvent) Reset() {
+	*x = SettleEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[49]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SettleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SettleEvent) ProtoMessage() {}
+
+func (x *SettleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[49]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SettleEvent.ProtoReflect.Descriptor instead.
+func (*SettleEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *SettleEvent) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+func (x *SettleEvent) GetInfo() *HtlcInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+type FinalHtlcEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Settled  bool `protobuf:"varint,1,opt,name=settled,proto3" json:"settled,omitempty"`
+	Offchain bool `protobuf:"varint,2,opt,name=offchain,proto3" json:"offchain,omitempty"`
+}
+
+func (x *FinalHtlcEvent) Reset() {
+	*x = FinalHtlcEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[50]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FinalHtlcEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FinalHtlcEvent) ProtoMessage() {}
+
+func (x *FinalHtlcEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[50]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FinalHtlcEvent.ProtoReflect.Descriptor instead.
+func (*FinalHtlcEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *FinalHtlcEvent) GetSettled() bool {
+	if x != nil {
+		return x.Settled
+	}
+	return false
+}
+
+func (x *FinalHtlcEvent) GetOffchain() bool {
+	if x != nil {
+		return x.Offchain
+	}
+	return false
+}
+
+type SubscribedEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *SubscribedEvent) Reset() {
+	*x = SubscribedEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[51]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribedEvent) ProtoMessage() {}
+
+func (x *SubscribedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[51]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribedEvent.ProtoReflect.Descriptor instead.
+func (*SubscribedEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{51}
+}
+
+type LinkFailEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Info contains details about the htlc that we failed.
+	Info *HtlcInfo `protobuf:"bytes,1,opt,name=info,proto3" json:"info,omitempty"`
+	// FailureCode is the BOLT error code for the failure.
+	WireFailure lnrpc.Failure_FailureCode `protobuf:"varint,2,opt,name=wire_failure,json=wireFailure,proto3,enum=lnrpc.Failure_FailureCode" json:"wire_failure,omitempty"`
+	// FailureDetail provides additional information about the reason for the
+	// failure. This detail enriches the information provided by the wire message
+	// and may be 'no detail' if the wire message requires no additional metadata.
+	FailureDetail FailureDetail `protobuf:"varint,3,opt,name=failure_detail,json=failureDetail,proto3,enum=routerrpc.FailureDetail" json:"failure_detail,omitempty"`
+	// A string representation of the link failure.
+	FailureString string `protobuf:"bytes,4,opt,name=failure_string,json=failureString,proto3" json:"failure_string,omitempty"`
+}
+
+func (x *LinkFailEvent) Reset() {
+	*x = LinkFailEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[52]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *LinkFailEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkFailEvent) ProtoMessage() {}
+
+func (x *LinkFailEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[52]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkFailEvent.ProtoReflect.Descriptor instead.
+func (*LinkFailEvent) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *LinkFailEvent) GetInfo() *HtlcInfo {
+	if x != nil {
+		return x.Info
+	}
+	return nil
+}
+
+func (x *LinkFailEvent) GetWireFailure() lnrpc.Failure_FailureCode {
+	if x != nil {
+		return x.WireFailure
+	}
+	return lnrpc.Failure_FailureCode(0)
+}
+
+func (x *LinkFailEvent) GetFailureDetail() FailureDetail {
+	if x != nil {
+		return x.FailureDetail
+	}
+	return FailureDetail_UNKNOWN
+}
+
+func (x *LinkFailEvent) GetFailureString() string {
+	if x != nil {
+		return x.FailureString
+	}
+	return ""
+}
+
+type PaymentStatus struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Current state the payment is in.
+	State PaymentState `protobuf:"varint,1,opt,name=state,proto3,enum=routerrpc.PaymentState" json:"state,omitempty"`
+	// The pre-image of the payment when state is SUCCEEDED.
+	Preimage []byte `protobuf:"bytes,2,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// The HTLCs made in attempt to settle the payment [EXPERIMENTAL].
+	Htlcs []*lnrpc.HTLCAttempt `protobuf:"bytes,4,rep,name=htlcs,proto3" json:"htlcs,omitempty"`
+}
+
+func (x *PaymentStatus) Reset() {
+	*x = PaymentStatus{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[53]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PaymentStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentStatus) ProtoMessage() {}
+
+func (x *PaymentStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[53]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentStatus.ProtoReflect.Descriptor instead.
+func (*PaymentStatus) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *PaymentStatus) GetState() PaymentState {
+	if x != nil {
+		return x.State
+	}
+	return PaymentState_IN_FLIGHT
+}
+
+func (x *PaymentStatus) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+func (x *PaymentStatus) GetHtlcs() []*lnrpc.HTLCAttempt {
+	if x != nil {
+		return x.Htlcs
+	}
+	return nil
+}
+
+type CircuitKey struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// / The id of the channel that the is part of this circuit.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
+	// / The index of the incoming htlc in the incoming channel.
+	HtlcId uint64 `protobuf:"varint,2,opt,name=htlc_id,json=htlcId,proto3" json:"htlc_id,omitempty"`
+}
+
+func (x *CircuitKey) Reset() {
+	*x = CircuitKey{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[54]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CircuitKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CircuitKey) ProtoMessage() {}
+
+func (x *CircuitKey) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[54]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CircuitKey.ProtoReflect.Descriptor instead.
+func (*CircuitKey) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *CircuitKey) GetChanId() uint64 {
+	if x != nil {
+		return x.ChanId
+	}
+	return 0
+}
+
+func (x *CircuitKey) GetHtlcId() uint64 {
+	if x != nil {
+		return x.HtlcId
+	}
+	return 0
+}
+
+type ForwardHtlcInterceptRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key of this forwarded htlc. It defines the incoming channel id and
+	// the index in this channel.
+	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
+	// The incoming htlc amount.
+	IncomingAmountMsat uint64 `protobuf:"varint,5,opt,name=incoming_amount_msat,json=incomingAmountMsat,proto3" json:"incoming_amount_msat,omitempty"`
+	// The incoming htlc expiry.
+	IncomingExpiry uint32 `protobuf:"varint,6,opt,name=incoming_expiry,json=incomingExpiry,proto3" json:"incoming_expiry,omitempty"`
+	// The htlc payment hash. This value is not guaranteed to be unique per
+	// request.
+	PaymentHash []byte `protobuf:"bytes,2,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
+	// The requested outgoing channel id for this forwarded htlc. Because of
+	// non-strict forwarding, this isn't necessarily the channel over which the
+	// packet will be forwarded eventually. A different channel to the same peer
+	// may be selected as well.
+	OutgoingRequestedChanId uint64 `protobuf:"varint,7,opt,name=outgoing_requested_chan_id,json=outgoingRequestedChanId,proto3" json:"outgoing_requested_chan_id,omitempty"`
+	// The outgoing htlc amount.
+	OutgoingAmountMsat uint64 `protobuf:"varint,3,opt,name=outgoing_amount_msat,json=outgoingAmountMsat,proto3" json:"outgoing_amount_msat,omitempty"`
+	// The outgoing htlc expiry.
+	OutgoingExpiry uint32 `protobuf:"varint,4,opt,name=outgoing_expiry,json=outgoingExpiry,proto3" json:"outgoing_expiry,omitempty"`
+	// Any custom records that were present in the payload.
+	CustomRecords map[uint64][]byte `protobuf:"bytes,8,rep,name=custom_records,json=customRecords,proto3" json:"custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The onion blob for the next hop
+	OnionBlob []byte `protobuf:"bytes,9,opt,name=onion_blob,json=onionBlob,proto3" json:"onion_blob,omitempty"`
+	// The block height at which this htlc will be auto-failed to prevent the
+	// channel from force-closing.
+	AutoFailHeight int32 `protobuf:"varint,10,opt,name=auto_fail_height,json=autoFailHeight,proto3" json:"auto_fail_height,omitempty"`
+	// The custom records of the peer's incoming p2p wire message.
+	InWireCustomRecords map[uint64][]byte `protobuf:"bytes,11,rep,name=in_wire_custom_records,json=inWireCustomRecords,proto3" json:"in_wire_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *ForwardHtlcInterceptRequest) Reset() {
+	*x = ForwardHtlcInterceptRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[55]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardHtlcInterceptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardHtlcInterceptRequest) ProtoMessage() {}
+
+func (x *ForwardHtlcInterceptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[55]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardHtlcInterceptRequest.ProtoReflect.Descriptor instead.
+func (*ForwardHtlcInterceptRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ForwardHtlcInterceptRequest) GetIncomingCircuitKey() *CircuitKey {
+	if x != nil {
+		return x.IncomingCircuitKey
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptRequest) GetIncomingAmountMsat() uint64 {
+	if x != nil {
+		return x.IncomingAmountMsat
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetIncomingExpiry() uint32 {
+	if x != nil {
+		return x.IncomingExpiry
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetPaymentHash() []byte {
+	if x != nil {
+		return x.PaymentHash
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptRequest) GetOutgoingRequestedChanId() uint64 {
+	if x != nil {
+		return x.OutgoingRequestedChanId
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetOutgoingAmountMsat() uint64 {
+	if x != nil {
+		return x.OutgoingAmountMsat
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetOutgoingExpiry() uint32 {
+	if x != nil {
+		return x.OutgoingExpiry
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetCustomRecords() map[uint64][]byte {
+	if x != nil {
+		return x.CustomRecords
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptRequest) GetOnionBlob() []byte {
+	if x != nil {
+		return x.OnionBlob
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptRequest) GetAutoFailHeight() int32 {
+	if x != nil {
+		return x.AutoFailHeight
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptRequest) GetInWireCustomRecords() map[uint64][]byte {
+	if x != nil {
+		return x.InWireCustomRecords
+	}
+	return nil
+}
+
+// *
+// ForwardHtlcInterceptResponse enables the caller to resolve a previously hold
+// forward. The caller can choose either to:
+// - `Resume`: Execute the default behavior (usually forward).
+// - `ResumeModified`: Execute the default behavior (usually forward) with HTLC
+// field modifications.
+// - `Reject`: Fail the htlc backwards.
+// - `Settle`: Settle this htlc with a given preimage.
+type ForwardHtlcInterceptResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// *
+	// The key of this forwarded htlc. It defines the incoming channel id and
+	// the index in this channel.
+	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
+	// The resolve action for this intercepted htlc.
+	Action ResolveHoldForwardAction `protobuf:"varint,2,opt,name=action,proto3,enum=routerrpc.ResolveHoldForwardAction" json:"action,omitempty"`
+	// The preimage in case the resolve action is Settle.
+	Preimage []byte `protobuf:"bytes,3,opt,name=preimage,proto3" json:"preimage,omitempty"`
+	// Encrypted failure message in case the resolve action is Fail.
+	//
+	// If failure_message is specified, the failure_code field must be set
+	// to zero.
+	FailureMessage []byte `protobuf:"bytes,4,opt,name=failure_message,json=failureMessage,proto3" json:"failure_message,omitempty"`
+	// Return the specified failure code in case the resolve action is Fail. The
+	// message data fields are populated automatically.
+	//
+	// If a non-zero failure_code is specified, failure_message must not be set.
+	//
+	// For backwards-compatibility reasons, TEMPORARY_CHANNEL_FAILURE is the
+	// default value for this field.
+	FailureCode lnrpc.Failure_FailureCode `protobuf:"varint,5,opt,name=failure_code,json=failureCode,proto3,enum=lnrpc.Failure_FailureCode" json:"failure_code,omitempty"`
+	// The amount that was set on the p2p wire message of the incoming HTLC.
+	// This field is ignored if the action is not RESUME_MODIFIED or the amount
+	// is zero.
+	InAmountMsat uint64 `protobuf:"varint,6,opt,name=in_amount_msat,json=inAmountMsat,proto3" json:"in_amount_msat,omitempty"`
+	// The amount to set on the p2p wire message of the resumed HTLC. This field
+	// is ignored if the action is not RESUME_MODIFIED or the amount is zero.
+	OutAmountMsat uint64 `protobuf:"varint,7,opt,name=out_amount_msat,json=outAmountMsat,proto3" json:"out_amount_msat,omitempty"`
+	// Any custom records that should be set on the p2p wire message message of
+	// the resumed HTLC. This field is ignored if the action is not
+	// RESUME_MODIFIED.
+	OutWireCustomRecords map[uint64][]byte `protobuf:"bytes,8,rep,name=out_wire_custom_records,json=outWireCustomRecords,proto3" json:"out_wire_custom_records,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// If set, lnd acknowledges the resolution once it was applied or
+	// rejected. This field is only respected by HtlcInterceptorBatched.
+	RequestAck bool `protobuf:"varint,9,opt,name=request_ack,json=requestAck,proto3" json:"request_ack,omitempty"`
+}
+
+func (x *ForwardHtlcInterceptResponse) Reset() {
+	*x = ForwardHtlcInterceptResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[56]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardHtlcInterceptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardHtlcInterceptResponse) ProtoMessage() {}
+
+func (x *ForwardHtlcInterceptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[56]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardHtlcInterceptResponse.ProtoReflect.Descriptor instead.
+func (*ForwardHtlcInterceptResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ForwardHtlcInterceptResponse) GetIncomingCircuitKey() *CircuitKey {
+	if x != nil {
+		return x.IncomingCircuitKey
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptResponse) GetAction() ResolveHoldForwardAction {
+	if x != nil {
+		return x.Action
+	}
+	return ResolveHoldForwardAction_SETTLE
+}
+
+func (x *ForwardHtlcInterceptResponse) GetPreimage() []byte {
+	if x != nil {
+		return x.Preimage
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptResponse) GetFailureMessage() []byte {
+	if x != nil {
+		return x.FailureMessage
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptResponse) GetFailureCode() lnrpc.Failure_FailureCode {
+	if x != nil {
+		return x.FailureCode
+	}
+	return lnrpc.Failure_FailureCode(0)
+}
+
+func (x *ForwardHtlcInterceptResponse) GetInAmountMsat() uint64 {
+	if x != nil {
+		return x.InAmountMsat
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptResponse) GetOutAmountMsat() uint64 {
+	if x != nil {
+		return x.OutAmountMsat
+	}
+	return 0
+}
+
+func (x *ForwardHtlcInterceptResponse) GetOutWireCustomRecords() map[uint64][]byte {
+	if x != nil {
+		return x.OutWireCustomRecords
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptResponse) GetRequestAck() bool {
+	if x != nil {
+		return x.RequestAck
+	}
+	return false
+}
+
+type ForwardHtlcInterceptBatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The resolutions of intercepted htlcs.
+	Resolutions []*ForwardHtlcInterceptResponse `protobuf:"bytes,1,rep,name=resolutions,proto3" json:"resolutions,omitempty"`
+}
+
+func (x *ForwardHtlcInterceptBatch) Reset() {
+	*x = ForwardHtlcInterceptBatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[57]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardHtlcInterceptBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardHtlcInterceptBatch) ProtoMessage() {}
+
+func (x *ForwardHtlcInterceptBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[57]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardHtlcInterceptBatch.ProtoReflect.Descriptor instead.
+func (*ForwardHtlcInterceptBatch) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ForwardHtlcInterceptBatch) GetResolutions() []*ForwardHtlcInterceptResponse {
+	if x != nil {
+		return x.Resolutions
+	}
+	return nil
+}
+
+type ForwardHtlcInterceptAck struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The key of the intercepted htlc the resolution is for.
+	IncomingCircuitKey *CircuitKey `protobuf:"bytes,1,opt,name=incoming_circuit_key,json=incomingCircuitKey,proto3" json:"incoming_circuit_key,omitempty"`
+	// The reason the resolution couldn't be applied. It is empty if the
+	// resolution was applied.
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *ForwardHtlcInterceptAck) Reset() {
+	*x = ForwardHtlcInterceptAck{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[58]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardHtlcInterceptAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardHtlcInterceptAck) ProtoMessage() {}
+
+func (x *ForwardHtlcInterceptAck) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[58]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardHtlcInterceptAck.ProtoReflect.Descriptor instead.
+func (*ForwardHtlcInterceptAck) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *ForwardHtlcInterceptAck) GetIncomingCircuitKey() *CircuitKey {
+	if x != nil {
+		return x.IncomingCircuitKey
+	}
+	return nil
+}
+
+func (x *ForwardHtlcInterceptAck) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type HtlcInterceptorUpdate struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Update:
+	//
+	//	*HtlcInterceptorUpdate_Htlc
+	//	*HtlcInterceptorUpdate_Ack
+	Update isHtlcInterceptorUpdate_Update `protobuf_oneof:"update"`
+}
+
+func (x *HtlcInterceptorUpdate) Reset() {
+	*x = HtlcInterceptorUpdate{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[59]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HtlcInterceptorUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HtlcInterceptorUpdate) ProtoMessage() {}
+
+func (x *HtlcInterceptorUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[59]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HtlcInterceptorUpdate.ProtoReflect.Descriptor instead.
+func (*HtlcInterceptorUpdate) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{59}
+}
+
+func (m *HtlcInterceptorUpdate) GetUpdate() isHtlcInterceptorUpdate_Update {
+	if m != nil {
+		return m.Update
+	}
+	return nil
+}
+
+func (x *HtlcInterceptorUpdate) GetHtlc() *ForwardHtlcInterceptRequest {
+	if x, ok := x.GetUpdate().(*HtlcInterceptorUpdate_Htlc); ok {
+		return x.Htlc
+	}
+	return nil
+}
+
+func (x *HtlcInterceptorUpdate) GetAck() *ForwardHtlcInterceptAck {
+	if x, ok := x.GetUpdate().(*HtlcInterceptorUpdate_Ack); ok {
+		return x.Ack
+	}
+	return nil
+}
+
+type isHtlcInterceptorUpdate_Update interface {
+	isHtlcInterceptorUpdate_Update()
+}
+
+type HtlcInterceptorUpdate_Htlc struct {
+	// An intercepted htlc that waits for a resolution.
+	Htlc *ForwardHtlcInterceptRequest `protobuf:"bytes,1,opt,name=htlc,proto3,oneof"`
+}
+
+type HtlcInterceptorUpdate_Ack struct {
+	// The acknowledgement of a resolution that set request_ack.
+	Ack *ForwardHtlcInterceptAck `protobuf:"bytes,2,opt,name=ack,proto3,oneof"`
+}
+
+func (*HtlcInterceptorUpdate_Htlc) isHtlcInterceptorUpdate_Update() {}
+
+func (*HtlcInterceptorUpdate_Ack) isHtlcInterceptorUpdate_Update() {}
+
+type UpdateChanStatusRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChanPoint *lnrpc.ChannelPoint `protobuf:"bytes,1,opt,name=chan_point,json=chanPoint,proto3" json:"chan_point,omitempty"`
+	Action    ChanStatusAction    `protobuf:"varint,2,opt,name=action,proto3,enum=routerrpc.ChanStatusAction" json:"action,omitempty"`
+}
+
+func (x *UpdateChanStatusRequest) Reset() {
+	*x = UpdateChanStatusRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[60]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateChanStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateChanStatusRequest) ProtoMessage() {}
+
+func (x *UpdateChanStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[60]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateChanStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateChanStatusRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *UpdateChanStatusRequest) GetChanPoint() *lnrpc.ChannelPoint {
+	if x != nil {
+		return x.ChanPoint
+	}
+	return nil
+}
+
+func (x *UpdateChanStatusRequest) GetAction() ChanStatusAction {
+	if x != nil {
+		return x.Action
+	}
+	return ChanStatusAction_ENABLE
+}
+
+type UpdateChanStatusResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdateChanStatusResponse) Reset() {
+	*x = UpdateChanStatusResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[61]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateChanStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateChanStatusResponse) ProtoMessage() {}
+
+func (x *UpdateChanStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[61]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateChanStatusResponse.ProtoReflect.Descriptor instead.
+func (*UpdateChanStatusResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{61}
+}
+
+type DustExposureRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short channel id of the channel to return the dust exposure of. If
+	// not set, the dust exposure of all channels with an active link is
+	// returned.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
+}
+
+func (x *DustExposureRequest) Reset() {
+	*x = DustExposureRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[62]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DustExposureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DustExposureRequest) ProtoMessage() {}
+
+func (x *DustExposureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[62]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DustExposureRequest.ProtoReflect.Descriptor instead.
+func (*DustExposureRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *DustExposureRequest) GetChanId() uint64 {
+	if x != nil {
+		return x.ChanId
+	}
+	return 0
+}
+
+type DustExposureResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The dust exposure of the requested channels.
+	Channels []*ChannelDustExposure `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+}
+
+func (x *DustExposureResponse) Reset() {
+	*x = DustExposureResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[63]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DustExposureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DustExposureResponse) ProtoMessage() {}
+
+func (x *DustExposureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[63]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DustExposureResponse.ProtoReflect.Descriptor instead.
+func (*DustExposureResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *DustExposureResponse) GetChannels() []*ChannelDustExposure {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+type ChannelDustExposure struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short channel id of the channel.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
+	// The sum of the dust htlcs on our commitment in msat.
+	LocalCommitDustMsat uint64 `protobuf:"varint,2,opt,name=local_commit_dust_msat,json=localCommitDustMsat,proto3" json:"local_commit_dust_msat,omitempty"`
+	// The sum of the dust htlcs on the remote commitment in msat.
+	RemoteCommitDustMsat uint64 `protobuf:"varint,3,opt,name=remote_commit_dust_msat,json=remoteCommitDustMsat,proto3" json:"remote_commit_dust_msat,omitempty"`
+	// The sum of the dust htlcs that are waiting in the channel's mailbox to be
+	// added to our commitment in msat.
+	LocalMailboxDustMsat uint64 `protobuf:"varint,4,opt,name=local_mailbox_dust_msat,json=localMailboxDustMsat,proto3" json:"local_mailbox_dust_msat,omitempty"`
+	// The sum of the dust htlcs that are waiting in the channel's mailbox to be
+	// added to the remote commitment in msat.
+	RemoteMailboxDustMsat uint64 `protobuf:"varint,5,opt,name=remote_mailbox_dust_msat,json=remoteMailboxDustMsat,proto3" json:"remote_mailbox_dust_msat,omitempty"`
+	// The threshold after which new dust htlcs are rejected in msat.
+	MaxFeeExposureMsat uint64 `protobuf:"varint,6,opt,name=max_fee_exposure_msat,json=maxFeeExposureMsat,proto3" json:"max_fee_exposure_msat,omitempty"`
+	// Whether the dust exposure on either commitment exceeds the threshold.
+	Exceeded bool `protobuf:"varint,7,opt,name=exceeded,proto3" json:"exceeded,omitempty"`
+}
+
+func (x *ChannelDustExposure) Reset() {
+	*x = ChannelDustExposure{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[64]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelDustExposure) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelDustExposure) ProtoMessage() {}
+
+func (x *ChannelDustExposure) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[64]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelDustExposure.ProtoReflect.Descriptor instead.
+func (*ChannelDustExposure) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ChannelDustExposure) GetChanId() uint64 {
+	if x != nil {
+		return x.ChanId
+	}
+	return 0
+}
+
+func (x *ChannelDustExposure) GetLocalCommitDustMsat() uint64 {
+	if x != nil {
+		return x.LocalCommitDustMsat
+	}
+	return 0
+}
+
+func (x *ChannelDustExposure) GetRemoteCommitDustMsat() uint64 {
+	if x != nil {
+		return x.RemoteCommitDustMsat
+	}
+	return 0
+}
+
+func (x *ChannelDustExposure) GetLocalMailboxDustMsat() uint64 {
+	if x != nil {
+		return x.LocalMailboxDustMsat
+	}
+	return 0
+}
+
+func (x *ChannelDustExposure) GetRemoteMailboxDustMsat() uint64 {
+	if x != nil {
+		return x.RemoteMailboxDustMsat
+	}
+	return 0
+}
+
+func (x *ChannelDustExposure) GetMaxFeeExposureMsat() uint64 {
+	if x != nil {
+		return x.MaxFeeExposureMsat
+	}
+	return 0
+}
+
+func (x *ChannelDustExposure) GetExceeded() bool {
+	if x != nil {
+		return x.Exceeded
+	}
+	return false
+}
+
+type UpdateMaxFeeExposureRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The new threshold after which new dust htlcs are rejected in msat.
+	MaxFeeExposureMsat uint64 `protobuf:"varint,1,opt,name=max_fee_exposure_msat,json=maxFeeExposureMsat,proto3" json:"max_fee_exposure_msat,omitempty"`
+}
+
+func (x *UpdateMaxFeeExposureRequest) Reset() {
+	*x = UpdateMaxFeeExposureRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[65]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateMaxFeeExposureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMaxFeeExposureRequest) ProtoMessage() {}
+
+func (x *UpdateMaxFeeExposureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[65]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMaxFeeExposureRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMaxFeeExposureRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *UpdateMaxFeeExposureRequest) GetMaxFeeExposureMsat() uint64 {
+	if x != nil {
+		return x.MaxFeeExposureMsat
+	}
+	return 0
+}
+
+type UpdateMaxFeeExposureResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *UpdateMaxFeeExposureResponse) Reset() {
+	*x = UpdateMaxFeeExposureResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[66]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateMaxFeeExposureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMaxFeeExposureResponse) ProtoMessage() {}
+
+func (x *UpdateMaxFeeExposureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[66]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMaxFeeExposureResponse.ProtoReflect.Descriptor instead.
+func (*UpdateMaxFeeExposureResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{66}
+}
+
+type ChannelReputationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short channel id of the incoming channel to return the reputation of.
+	// If not set, the reputation of all incoming channels that forwarded htlcs
+	// while experimental endorsement was enabled is returned.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
+}
+
+func (x *ChannelReputationRequest) Reset() {
+	*x = ChannelReputationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[67]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelReputationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelReputationRequest) ProtoMessage() {}
+
+func (x *ChannelReputationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[67]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelReputationRequest.ProtoReflect.Descriptor instead.
+func (*ChannelReputationRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ChannelReputationRequest) GetChanId() uint64 {
+	if x != nil {
+		return x.ChanId
+	}
+	return 0
+}
+
+type ChannelReputationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The reputation of the requested incoming channels.
+	Channels []*IncomingChannelReputation `protobuf:"bytes,1,rep,name=channels,proto3" json:"channels,omitempty"`
+}
+
+func (x *ChannelReputationResponse) Reset() {
+	*x = ChannelReputationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[68]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChannelReputationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelReputationResponse) ProtoMessage() {}
+
+func (x *ChannelReputationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[68]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelReputationResponse.ProtoReflect.Descriptor instead.
+func (*ChannelReputationResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ChannelReputationResponse) GetChannels() []*IncomingChannelReputation {
+	if x != nil {
+		return x.Channels
+	}
+	return nil
+}
+
+type ForwardingPackagesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The channel to return the forwarding packages of.
+	ChanPoint *lnrpc.ChannelPoint `protobuf:"bytes,1,opt,name=chan_point,json=chanPoint,proto3" json:"chan_point,omitempty"`
+}
+
+func (x *ForwardingPackagesRequest) Reset() {
+	*x = ForwardingPackagesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[69]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardingPackagesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardingPackagesRequest) ProtoMessage() {}
+
+func (x *ForwardingPackagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[69]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardingPackagesRequest.ProtoReflect.Descriptor instead.
+func (*ForwardingPackagesRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ForwardingPackagesRequest) GetChanPoint() *lnrpc.ChannelPoint {
+	if x != nil {
+		return x.ChanPoint
+	}
+	return nil
+}
+
+type ForwardingPackagesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The forwarding packages of the channel.
+	Packages []*ForwardingPackage `protobuf:"bytes,1,rep,name=packages,proto3" json:"packages,omitempty"`
+}
+
+func (x *ForwardingPackagesResponse) Reset() {
+	*x = ForwardingPackagesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[70]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardingPackagesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardingPackagesResponse) ProtoMessage() {}
+
+func (x *ForwardingPackagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[70]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardingPackagesResponse.ProtoReflect.Descriptor instead.
+func (*ForwardingPackagesResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ForwardingPackagesResponse) GetPackages() []*ForwardingPackage {
+	if x != nil {
+		return x.Packages
+	}
+	return nil
+}
+
+type ForwardingPackage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short channel id of the channel the updates were received on.
+	SourceChanId uint64 `protobuf:"varint,1,opt,name=source_chan_id,json=sourceChanId,proto3" json:"source_chan_id,omitempty"`
+	// The height of the remote commitment chain at which the updates were
+	// locked in.
+	Height uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	// The processing state of the package.
+	State ForwardingPackageState `protobuf:"varint,3,opt,name=state,proto3,enum=routerrpc.ForwardingPackageState" json:"state,omitempty"`
+	// The number of adds in the package.
+	NumAdds uint32 `protobuf:"varint,4,opt,name=num_adds,json=numAdds,proto3" json:"num_adds,omitempty"`
+	// The number of adds that were forwarded to the switch, as opposed to being
+	// failed back or settled by us.
+	NumForwardedAdds uint32 `protobuf:"varint,5,opt,name=num_forwarded_adds,json=numForwardedAdds,proto3" json:"num_forwarded_adds,omitempty"`
+	// The number of adds that have been fully resolved.
+	NumAckedAdds uint32 `protobuf:"varint,6,opt,name=num_acked_adds,json=numAckedAdds,proto3" json:"num_acked_adds,omitempty"`
+	// The number of settles and fails in the package.
+	NumSettleFails uint32 `protobuf:"varint,7,opt,name=num_settle_fails,json=numSettleFails,proto3" json:"num_settle_fails,omitempty"`
+	// The number of settles and fails that have been propagated to the incoming
+	// channel.
+	NumAckedSettleFails uint32 `protobuf:"varint,8,opt,name=num_acked_settle_fails,json=numAckedSettleFails,proto3" json:"num_acked_settle_fails,omitempty"`
+}
+
+func (x *ForwardingPackage) Reset() {
+	*x = ForwardingPackage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[71]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ForwardingPackage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardingPackage) ProtoMessage() {}
+
+func (x *ForwardingPackage) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[71]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardingPackage.ProtoReflect.Descriptor instead.
+func (*ForwardingPackage) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ForwardingPackage) GetSourceChanId() uint64 {
+	if x != nil {
+		return x.SourceChanId
+	}
+	return 0
+}
+
+func (x *ForwardingPackage) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *ForwardingPackage) GetState() ForwardingPackageState {
+	if x != nil {
+		return x.State
+	}
+	return ForwardingPackageState_FWD_PKG_LOCKED_IN
+}
+
+func (x *ForwardingPackage) GetNumAdds() uint32 {
+	if x != nil {
+		return x.NumAdds
+	}
+	return 0
+}
+
+func (x *ForwardingPackage) GetNumForwardedAdds() uint32 {
+	if x != nil {
+		return x.NumForwardedAdds
+	}
+	return 0
+}
+
+func (x *ForwardingPackage) GetNumAckedAdds() uint32 {
+	if x != nil {
+		return x.NumAckedAdds
+	}
+	return 0
+}
+
+func (x *ForwardingPackage) GetNumSettleFails() uint32 {
+	if x != nil {
+		return x.NumSettleFails
+	}
+	return 0
+}
+
+func (x *ForwardingPackage) GetNumAckedSettleFails() uint32 {
+	if x != nil {
+		return x.NumAckedSettleFails
+	}
+	return 0
+}
+
+type ReprocessForwardingPackageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The channel whose forwarding package should be replayed.
+	ChanPoint *lnrpc.ChannelPoint `protobuf:"bytes,1,opt,name=chan_point,json=chanPoint,proto3" json:"chan_point,omitempty"`
+	// The height of the forwarding package to replay.
+	Height uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (x *ReprocessForwardingPackageRequest) Reset() {
+	*x = ReprocessForwardingPackageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[72]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReprocessForwardingPackageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReprocessForwardingPackageRequest) ProtoMessage() {}
+
+func (x *ReprocessForwardingPackageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[72]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReprocessForwardingPackageRequest.ProtoReflect.Descriptor instead.
+func (*ReprocessForwardingPackageRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *ReprocessForwardingPackageRequest) GetChanPoint() *lnrpc.ChannelPoint {
+	if x != nil {
+		return x.ChanPoint
+	}
+	return nil
+}
+
+func (x *ReprocessForwardingPackageRequest) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type ReprocessForwardingPackageResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ReprocessForwardingPackageResponse) Reset() {
+	*x = ReprocessForwardingPackageResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[73]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReprocessForwardingPackageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReprocessForwardingPackageResponse) ProtoMessage() {}
+
+func (x *ReprocessForwardingPackageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[73]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReprocessForwardingPackageResponse.ProtoReflect.Descriptor instead.
+func (*ReprocessForwardingPackageResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{73}
+}
+
+type IncomingChannelReputation struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The short channel id of the incoming channel.
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id,json=chanId,proto3" json:"chan_id,omitempty"`
+	// The decayed sum of the fees that the htlcs from the channel have paid us,
+	// less the penalties for endorsed htlcs that were held beyond the
+	// resolution period, in msat. It may be negative.
+	ReputationMsat int64 `protobuf:"varint,2,opt,name=reputation_msat,json=reputationMsat,proto3" json:"reputation_msat,omitempty"`
+	// The sum of the fees offered by the endorsed htlcs from the channel that
+	// are currently in flight in msat.
+	InFlightRiskMsat uint64 `protobuf:"varint,3,opt,name=in_flight_risk_msat,json=inFlightRiskMsat,proto3" json:"in_flight_risk_msat,omitempty"`
+	// The number of htlcs from the channel that are currently in flight.
+	NumInFlight uint32 `protobuf:"varint,4,opt,name=num_in_flight,json=numInFlight,proto3" json:"num_in_flight,omitempty"`
+}
+
+func (x *IncomingChannelReputation) Reset() {
+	*x = IncomingChannelReputation{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[74]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IncomingChannelReputation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncomingChannelReputation) ProtoMessage() {}
+
+func (x *IncomingChannelReputation) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[74]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncomingChannelReputation.ProtoReflect.Descriptor instead.
+func (*IncomingChannelReputation) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *IncomingChannelReputation) GetChanId() uint64 {
+	if x != nil {
+		return x.ChanId
+	}
+	return 0
+}
+
+func (x *IncomingChannelReputation) GetReputationMsat() int64 {
+	if x != nil {
+		return x.ReputationMsat
+	}
+	return 0
+}
+
+func (x *IncomingChannelReputation) GetInFlightRiskMsat() uint64 {
+	if x != nil {
+		return x.InFlightRiskMsat
+	}
+	return 0
+}
+
+func (x *IncomingChannelReputation) GetNumInFlight() uint32 {
+	if x != nil {
+		return x.NumInFlight
+	}
+	return 0
+}
+
+type AddAliasesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AliasMaps []*lnrpc.AliasMap `protobuf:"bytes,1,rep,name=alias_maps,json=aliasMaps,proto3" json:"alias_maps,omitempty"`
+}
+
+func (x *AddAliasesRequest) Reset() {
+	*x = AddAliasesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[75]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddAliasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAliasesRequest) ProtoMessage() {}
+
+func (x *AddAliasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[75]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAliasesRequest.ProtoReflect.Descriptor instead.
+func (*AddAliasesRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *AddAliasesRequest) GetAliasMaps() []*lnrpc.AliasMap {
+	if x != nil {
+		return x.AliasMaps
+	}
+	return nil
+}
+
+type AddAliasesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AliasMaps []*lnrpc.AliasMap `protobuf:"bytes,1,rep,name=alias_maps,json=aliasMaps,proto3" json:"alias_maps,omitempty"`
+}
+
+func (x *AddAliasesResponse) Reset() {
+	*x = AddAliasesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[76]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AddAliasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddAliasesResponse) ProtoMessage() {}
+
+func (x *AddAliasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[76]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddAliasesResponse.ProtoReflect.Descriptor instead.
+func (*AddAliasesResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *AddAliasesResponse) GetAliasMaps() []*lnrpc.AliasMap {
+	if x != nil {
+		return x.AliasMaps
+	}
+	return nil
+}
+
+type DeleteAliasesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AliasMaps []*lnrpc.AliasMap `protobuf:"bytes,1,rep,name=alias_maps,json=aliasMaps,proto3" json:"alias_maps,omitempty"`
+}
+
+func (x *DeleteAliasesRequest) Reset() {
+	*x = DeleteAliasesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[77]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteAliasesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAliasesRequest) ProtoMessage() {}
+
+func (x *DeleteAliasesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[77]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAliasesRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAliasesRequest) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *DeleteAliasesRequest) GetAliasMaps() []*lnrpc.AliasMap {
+	if x != nil {
+		return x.AliasMaps
+	}
+	return nil
+}
+
+type DeleteAliasesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	AliasMaps []*lnrpc.AliasMap `protobuf:"bytes,1,rep,name=alias_maps,json=aliasMaps,proto3" json:"alias_maps,omitempty"`
+}
+
+func (x *DeleteAliasesResponse) Reset() {
+	*x = DeleteAliasesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_routerrpc_router_proto_msgTypes[78]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteAliasesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAliasesResponse) ProtoMessage() {}
+
+func (x *DeleteAliasesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_routerrpc_router_proto_msgTypes[78]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAliasesResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAliasesResponse) Descriptor() ([]byte, []int) {
+	return file_routerrpc_router_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *DeleteAliasesResponse) GetAliasMaps() []*lnrpc.AliasMap {
+	if x != nil {
+		return x.AliasMaps
+	}
+	return nil
+}
+
+var File_routerrpc_router_proto protoreflect.FileDescriptor
+
+var file_routerrpc_router_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2f, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x1a, 0x1c, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x72, 0x70, 0x63, 0x2f, 0x63, 0x68,
+	0x61, 0x69, 0x6e, 0x6e, 0x6f, 0x74, 0x69, 0x66, 0x69, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x1a, 0x0f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0xc0, 0x0b, 0x0a, 0x12, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x61, 0x6d, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03, 0x61, 0x6d, 0x74, 0x12,
+	0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61,
+	0x73, 0x68, 0x12, 0x28, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x63, 0x6c, 0x74, 0x76,
+	0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x66, 0x69,
+	0x6e, 0x61, 0x6c, 0x43, 0x6c, 0x74, 0x76, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x27, 0x0a, 0x0f,
+	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
+	0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x22,
+	0x0a, 0x0d, 0x66, 0x65, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x66, 0x65, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x53,
+	0x61, 0x74, 0x12, 0x2e, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63,
+	0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x04, 0x42, 0x04, 0x18, 0x01,
+	0x30, 0x01, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e,
+	0x49, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x74, 0x76, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x63, 0x6c, 0x74, 0x76, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x12, 0x31, 0x0a, 0x0b, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x68, 0x69, 0x6e, 0x74, 0x73,
+	0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x48, 0x69, 0x6e, 0x74, 0x52, 0x0a, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x48,
+	0x69, 0x6e, 0x74, 0x73, 0x12, 0x64, 0x0a, 0x13, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x63, 0x75, 0x73,
+	0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x34, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
+	0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x2e, 0x44, 0x65, 0x73, 0x74, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x11, 0x64, 0x65, 0x73, 0x74, 0x43, 0x75, 0x73,
+	0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x6d,
+	0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x6d,
+	0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x24, 0x0a, 0x0e, 0x66, 0x65, 0x65, 0x5f, 0x6c, 0x69, 0x6d,
+	0x69, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x66,
+	0x65, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x6c,
+	0x61, 0x73, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x0e,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x0d, 0x6c, 0x61, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x73, 0x65, 0x6c,
+	0x66, 0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x53, 0x65, 0x6c, 0x66, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x36, 0x0a, 0x0d, 0x64, 0x65, 0x73, 0x74, 0x5f, 0x66, 0x65, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
+	0x2e, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x42, 0x69, 0x74, 0x52, 0x0c, 0x64, 0x65, 0x73,
+	0x74, 0x46, 0x65, 0x61, 0x74, 0x75, 0x72, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d, 0x61, 0x78,
+	0x5f, 0x70, 0x61, 0x72, 0x74, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6d, 0x61,
+	0x78, 0x50, 0x61, 0x72, 0x74, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f, 0x5f, 0x69, 0x6e, 0x66,
+	0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x12, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69,
+	0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x13, 0x20, 0x03, 0x28,
+	0x04, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x49,
+	0x64, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x64,
+	0x64, 0x72, 0x18, 0x14, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x41, 0x64, 0x64, 0x72, 0x12, 0x2d, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x68, 0x61,
+	0x72, 0x64, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x15, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x53, 0x68, 0x61, 0x72, 0x64, 0x53, 0x69, 0x7a, 0x65,
+	0x4d, 0x73, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x6d, 0x70, 0x18, 0x16, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x03, 0x61, 0x6d, 0x70, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x70,
+	0x72, 0x65, 0x66, 0x18, 0x17, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x50,
+	0x72, 0x65, 0x66, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x61, 0x62, 0x6c,
+	0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x63, 0x61, 0x6e, 0x63, 0x65, 0x6c, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x71, 0x0a, 0x18, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f, 0x68, 0x6f, 0x70,
+	0x5f, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18,
+	0x19, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73,
+	0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x15, 0x66, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x30, 0x0a, 0x06, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74,
+	0x18, 0x1a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74,
+	0x52, 0x06, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74, 0x12, 0x49, 0x0a, 0x12, 0x68, 0x6f, 0x70, 0x5f,
+	0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x1b,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x73, 0x52, 0x10, 0x68, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x73, 0x12, 0x3c, 0x0a, 0x0a, 0x74, 0x72, 0x61, 0x6d, 0x70, 0x6f, 0x6c, 0x69, 0x6e,
+	0x65, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x6d, 0x70, 0x6f, 0x6c, 0x69, 0x6e, 0x65, 0x4f, 0x70,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x0a, 0x74, 0x72, 0x61, 0x6d, 0x70, 0x6f, 0x6c, 0x69, 0x6e,
+	0x65, 0x12, 0x32, 0x0a, 0x0b, 0x63, 0x6c, 0x74, 0x76, 0x5f, 0x62, 0x75, 0x64, 0x67, 0x65, 0x74,
+	0x18, 0x1d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x43,
+	0x6c, 0x74, 0x76, 0x42, 0x75, 0x64, 0x67, 0x65, 0x74, 0x52, 0x0a, 0x63, 0x6c, 0x74, 0x76, 0x42,
+	0x75, 0x64, 0x67, 0x65, 0x74, 0x1a, 0x44, 0x0a, 0x16, 0x44, 0x65, 0x73, 0x74, 0x43, 0x75, 0x73,
+	0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x48, 0x0a, 0x1a, 0x46,
+	0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x61, 0x0a, 0x11, 0x54, 0x72, 0x61, 0x6d, 0x70, 0x6f, 0x6c,
+	0x69, 0x6e, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x6f,
+	0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x6e, 0x6f, 0x64, 0x65, 0x12, 0x19,
+	0x0a, 0x08, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x07, 0x66, 0x65, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x74,
+	0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x63,
+	0x6c, 0x74, 0x76, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x22, 0xc4, 0x01, 0x0a, 0x10, 0x48, 0x6f, 0x70,
+	0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x17, 0x0a,
+	0x07, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06,
+	0x70, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x55, 0x0a, 0x0e, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d,
+	0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x6f, 0x70, 0x43, 0x75,
+	0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x2e, 0x43, 0x75, 0x73, 0x74,
+	0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0d,
+	0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x1a, 0x40, 0x0a,
+	0x12, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22,
+	0xae, 0x01, 0x0a, 0x0d, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x42, 0x75, 0x64, 0x67, 0x65,
+	0x74, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x70, 0x65, 0x72,
+	0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x46,
+	0x65, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x65, 0x61,
+	0x64, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x64, 0x65, 0x61,
+	0x64, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x6d, 0x61, 0x78,
+	0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x17, 0x61, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x6f,
+	0x6e, 0x64, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x15, 0x61, 0x74, 0x74, 0x65, 0x6d,
+	0x70, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x22, 0x68, 0x0a, 0x13, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f,
+	0x5f, 0x69, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69,
+	0x67, 0x68, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x22, 0x46, 0x0a, 0x14, 0x54, 0x72,
+	0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x6e, 0x6f, 0x5f, 0x69, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68,
+	0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x11, 0x6e, 0x6f, 0x49, 0x6e, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x73, 0x22, 0x50, 0x0a, 0x13, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x39, 0x0a, 0x08, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x22, 0xa4, 0x01, 0x0a, 0x14, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a,
+	0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x48, 0x00, 0x52, 0x0d, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3a, 0x0a, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72, 0x79,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4f,
+	0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x48, 0x00, 0x52, 0x07, 0x73, 0x75, 0x6d, 0x6d, 0x61, 0x72,
+	0x79, 0x42, 0x08, 0x0a, 0x06, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x22, 0x6a, 0x0a, 0x12, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x28, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
+	0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x64, 0x0a, 0x17, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x43, 0x6f, 0x75,
+	0x6e, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52,
+	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0xb6, 0x02,
+	0x0a, 0x13, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x4f, 0x75,
+	0x74, 0x63, 0x6f, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6e, 0x75, 0x6d, 0x5f, 0x73, 0x75, 0x63,
+	0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x6e, 0x75,
+	0x6d, 0x53, 0x75, 0x63, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x75,
+	0x6d, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09,
+	0x6e, 0x75, 0x6d, 0x46, 0x61, 0x69, 0x6c, 0x65, 0x64, 0x12, 0x4b, 0x0a, 0x0f, 0x66, 0x61, 0x69,
+	0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x52, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x73, 0x12, 0x4f, 0x0a, 0x0b, 0x69, 0x6e, 0x69, 0x74, 0x5f, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x4f, 0x75, 0x74, 0x63, 0x6f, 0x6d, 0x65, 0x2e, 0x49, 0x6e, 0x69, 0x74,
+	0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0a, 0x69, 0x6e, 0x69,
+	0x74, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x1a, 0x3d, 0x0a, 0x0f, 0x49, 0x6e, 0x69, 0x74, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x9d, 0x01, 0x0a, 0x0f, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x46, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x65,
+	0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x65, 0x73, 0x74, 0x12, 0x17,
+	0x0a, 0x07, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x06, 0x61, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x07, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x69,
+	0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73, 0x69,
+	0x6d, 0x75, 0x6c, 0x61, 0x74, 0x65, 0x22, 0xcb, 0x01, 0x0a, 0x10, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x46, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x28, 0x0a, 0x10, 0x72,
+	0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x72, 0x6f, 0x75, 0x74, 0x69, 0x6e, 0x67, 0x46, 0x65,
+	0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x26, 0x0a, 0x0f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x6c, 0x6f,
+	0x63, 0x6b, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d,
+	0x74, 0x69, 0x6d, 0x65, 0x4c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x42, 0x0a,
+	0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x70, 0x72, 0x6f,
+	0x62, 0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x50, 0x72, 0x6f, 0x62, 0x22, 0xbc, 0x02, 0x0a, 0x12, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52,
+	0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x70,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x22,
+	0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e,
+	0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x05, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x12, 0x22, 0x0a, 0x0d, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x5f,
+	0x65, 0x72, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x73, 0x6b, 0x69, 0x70, 0x54,
+	0x65, 0x6d, 0x70, 0x45, 0x72, 0x72, 0x12, 0x71, 0x0a, 0x18, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f,
+	0x68, 0x6f, 0x70, 0x5f, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x38, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70,
+	0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x15, 0x66, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74,
+	0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x1a, 0x48, 0x0a, 0x1a, 0x46, 0x69, 0x72,
+	0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x5b, 0x0a, 0x13, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72,
+	0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72,
+	0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x07, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e,
+	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x07, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
+	0x22, 0x1c, 0x0a, 0x1a, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x1d,
+	0x0a, 0x1b, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f,
+	0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x1c, 0x0a,
+	0x1a, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x51, 0x0a, 0x1b, 0x51,
+	0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x05, 0x70, 0x61,
+	0x69, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x22, 0x62,
+	0x0a, 0x1c, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c,
+	0x0a, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x05, 0x70, 0x61, 0x69, 0x72, 0x73, 0x12, 0x14, 0x0a, 0x05,
+	0x66, 0x6f, 0x72, 0x63, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x66, 0x6f, 0x72,
+	0x63, 0x65, 0x22, 0x1f, 0x0a, 0x1d, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x22, 0x8a, 0x01, 0x0a, 0x0b, 0x50, 0x61, 0x69, 0x72, 0x48, 0x69, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x66, 0x72, 0x6f, 0x6d,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x46, 0x72, 0x6f, 0x6d,
+	0x12, 0x17, 0x0a, 0x07, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x74, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x06, 0x6e, 0x6f, 0x64, 0x65, 0x54, 0x6f, 0x12, 0x2d, 0x0a, 0x07, 0x68, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x44, 0x61, 0x74, 0x61, 0x52,
+	0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x4a, 0x04,
+	0x08, 0x04, 0x10, 0x05, 0x4a, 0x04, 0x08, 0x05, 0x10, 0x06, 0x4a, 0x04, 0x08, 0x06, 0x10, 0x07,
+	0x22, 0xe8, 0x01, 0x0a, 0x08, 0x50, 0x61, 0x69, 0x72, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1b, 0x0a,
+	0x09, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x08, 0x66, 0x61, 0x69, 0x6c, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0c, 0x66, 0x61,
+	0x69, 0x6c, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0a, 0x66, 0x61, 0x69, 0x6c, 0x41, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x22, 0x0a, 0x0d,
+	0x66, 0x61, 0x69, 0x6c, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74,
+	0x12, 0x21, 0x0a, 0x0c, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x54,
+	0x69, 0x6d, 0x65, 0x12, 0x26, 0x0a, 0x0f, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x61,
+	0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x73, 0x75,
+	0x63, 0x63, 0x65, 0x73, 0x73, 0x41, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x73,
+	0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x41, 0x6d,
+	0x74, 0x4d, 0x73, 0x61, 0x74, 0x4a, 0x04, 0x08, 0x03, 0x10, 0x04, 0x22, 0x20, 0x0a, 0x1e, 0x47,
+	0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x5a, 0x0a,
+	0x1f, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x37, 0x0a, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x73,
+	0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x59, 0x0a, 0x1e, 0x53, 0x65, 0x74,
+	0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x37, 0x0a, 0x06, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x22, 0x21, 0x0a, 0x1f, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69,
+	0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x89, 0x04, 0x0a, 0x14, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x12, 0x2e, 0x0a, 0x11, 0x68, 0x61, 0x6c, 0x66, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x5f, 0x73, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x18, 0x01, 0x52,
+	0x0f, 0x68, 0x61, 0x6c, 0x66, 0x4c, 0x69, 0x66, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73,
+	0x12, 0x2b, 0x0a, 0x0f, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x02, 0x42, 0x02, 0x18, 0x01, 0x52, 0x0e, 0x68,
+	0x6f, 0x70, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x1a, 0x0a,
+	0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x42, 0x02, 0x18,
+	0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x36, 0x0a, 0x17, 0x6d, 0x61, 0x78,
+	0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x72, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x15, 0x6d, 0x61, 0x78, 0x69,
+	0x6d, 0x75, 0x6d, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x73, 0x12, 0x43, 0x0a, 0x1e, 0x6d, 0x69, 0x6e, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x66, 0x61, 0x69,
+	0x6c, 0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x78, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x76, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x1b, 0x6d, 0x69, 0x6e, 0x69, 0x6d,
+	0x75, 0x6d, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x6c, 0x61, 0x78, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x46, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x30, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x38,
+	0x0a, 0x07, 0x61, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x70, 0x72, 0x69,
+	0x6f, 0x72, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x48, 0x00, 0x52,
+	0x07, 0x61, 0x70, 0x72, 0x69, 0x6f, 0x72, 0x69, 0x12, 0x38, 0x0a, 0x07, 0x62, 0x69, 0x6d, 0x6f,
+	0x64, 0x61, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x48, 0x00, 0x52, 0x07, 0x62, 0x69, 0x6d, 0x6f, 0x64,
+	0x61, 0x6c, 0x22, 0x2c, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x50, 0x52, 0x49, 0x4f, 0x52,
+	0x49, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x49, 0x4d, 0x4f, 0x44, 0x41, 0x4c, 0x10, 0x01,
+	0x42, 0x11, 0x0a, 0x0f, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x6f, 0x72, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x22, 0x72, 0x0a, 0x11, 0x42, 0x69, 0x6d, 0x6f, 0x64, 0x61, 0x6c, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x6e, 0x6f, 0x64, 0x65,
+	0x5f, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0a, 0x6e,
+	0x6f, 0x64, 0x65, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x63, 0x61,
+	0x6c, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73,
+	0x63, 0x61, 0x6c, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x65, 0x63, 0x61,
+	0x79, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x64, 0x65,
+	0x63, 0x61, 0x79, 0x54, 0x69, 0x6d, 0x65, 0x22, 0xad, 0x01, 0x0a, 0x11, 0x41, 0x70, 0x72, 0x69,
+	0x6f, 0x72, 0x69, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x2a, 0x0a,
+	0x11, 0x68, 0x61, 0x6c, 0x66, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x68, 0x61, 0x6c, 0x66, 0x4c, 0x69,
+	0x66, 0x65, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x68, 0x6f, 0x70,
+	0x5f, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x0e, 0x68, 0x6f, 0x70, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69,
+	0x74, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x61,
+	0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x5f, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x10, 0x63, 0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x46,
+	0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x6a, 0x0a, 0x17, 0x51, 0x75, 0x65, 0x72, 0x79,
+	0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x66, 0x72, 0x6f, 0x6d, 0x4e, 0x6f, 0x64, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x74, 0x6f, 0x5f, 0x6e, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x06, 0x74, 0x6f, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x6d, 0x74, 0x5f,
+	0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x6d, 0x74, 0x4d,
+	0x73, 0x61, 0x74, 0x22, 0x6b, 0x0a, 0x18, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62,
+	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x12, 0x2d, 0x0a, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50,
+	0x61, 0x69, 0x72, 0x44, 0x61, 0x74, 0x61, 0x52, 0x07, 0x68, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x22, 0x15, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xf8, 0x01, 0x0a, 0x0b, 0x50, 0x72, 0x6f, 0x62,
+	0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x12,
+	0x19, 0x0a, 0x08, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x61, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x12, 0x1f, 0x0a,
+	0x0b, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0a, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x12, 0x18,
+	0x0a, 0x07, 0x72, 0x65, 0x61, 0x63, 0x68, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x07, 0x72, 0x65, 0x61, 0x63, 0x68, 0x65, 0x64, 0x12, 0x42, 0x0a, 0x0e, 0x66, 0x61, 0x69, 0x6c,
+	0x75, 0x72, 0x65, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1b, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x52, 0x0d, 0x66,
+	0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x22, 0x48, 0x0a, 0x14, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f,
+	0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x07, 0x72, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x07, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x22, 0x86, 0x03, 0x0a,
+	0x11, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x61, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x28, 0x0a,
+	0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x63, 0x6c, 0x74, 0x76, 0x5f, 0x64, 0x65, 0x6c, 0x74,
+	0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x43, 0x6c,
+	0x74, 0x76, 0x44, 0x65, 0x6c, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f,
+	0x69, 0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43,
+	0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x68, 0x6f, 0x70, 0x5f, 0x70, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x0a, 0x68, 0x6f, 0x70, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61,
+	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x41, 0x64, 0x64, 0x72, 0x12, 0x70, 0x0a, 0x18, 0x66, 0x69, 0x72,
+	0x73, 0x74, 0x5f, 0x68, 0x6f, 0x70, 0x5f, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x37, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x72, 0x73, 0x74, 0x48,
+	0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x15, 0x66, 0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75,
+	0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x1a, 0x48, 0x0a, 0x1a, 0x46,
+	0x69, 0x72, 0x73, 0x74, 0x48, 0x6f, 0x70, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x38, 0x0a, 0x12, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f,
+	0x75, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6e, 0x72,
+	0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x22,
+	0xe9, 0x01, 0x0a, 0x10, 0x52, 0x65, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x2c, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67,
+	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02,
+	0x30, 0x01, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e,
+	0x49, 0x64, 0x12, 0x2c, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63,
+	0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01,
+	0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x49, 0x64,
+	0x12, 0x17, 0x0a, 0x07, 0x61, 0x6d, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x61, 0x6d, 0x74, 0x53, 0x61, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x66, 0x65, 0x65,
+	0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x0b, 0x66, 0x65, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x53, 0x61, 0x74, 0x12, 0x1d, 0x0a,
+	0x0a, 0x63, 0x6c, 0x74, 0x76, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x09, 0x63, 0x6c, 0x74, 0x76, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1d, 0x0a, 0x0a,
+	0x71, 0x75, 0x65, 0x72, 0x79, 0x5f, 0x6f, 0x6e, 0x6c, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x09, 0x71, 0x75, 0x65, 0x72, 0x79, 0x4f, 0x6e, 0x6c, 0x79, 0x22, 0x91, 0x01, 0x0a, 0x10,
+	0x52, 0x65, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x12, 0x1b, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x30, 0x0a,
+	0x14, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x62,
+	0x65, 0x66, 0x6f, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x12, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x42, 0x65, 0x66, 0x6f, 0x72, 0x65, 0x12,
+	0x2e, 0x0a, 0x13, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65,
+	0x5f, 0x61, 0x66, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x6c, 0x6f,
+	0x63, 0x61, 0x6c, 0x42, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x41, 0x66, 0x74, 0x65, 0x72, 0x22,
+	0x93, 0x01, 0x0a, 0x11, 0x52, 0x65, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x22, 0x0a, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x52, 0x05, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x37, 0x0a, 0x08,
+	0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x62, 0x61, 0x6c,
+	0x61, 0x6e, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x08, 0x63, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x22, 0xa5, 0x01, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x41, 0x6c, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x3c, 0x0a,
+	0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x26, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x41, 0x6c, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x05, 0x74, 0x79, 0x70, 0x65, 0x73, 0x12, 0x3a, 0x0a, 0x0e, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65,
+	0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0c, 0x52, 0x05, 0x70, 0x65, 0x65, 0x72, 0x73, 0x22, 0xdd, 0x03,
+	0x0a, 0x11, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x41, 0x6c, 0x6c, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x3a, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x41, 0x6c, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x40, 0x0a, 0x0d, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x43,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x48, 0x00, 0x52, 0x0c, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x12, 0x31, 0x0a, 0x0a, 0x70, 0x65, 0x65, 0x72, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x65,
+	0x65, 0x72, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x09, 0x70, 0x65, 0x65, 0x72, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x35, 0x0a, 0x0a, 0x68, 0x74, 0x6c, 0x63, 0x5f, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00,
+	0x52, 0x09, 0x68, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x07, 0x69,
+	0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c,
+	0x6e, 0x72, 0x70, 0x63, 0x2e, 0x49, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x48, 0x00, 0x52, 0x07,
+	0x69, 0x6e, 0x76, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x2a, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
+	0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x05, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x48, 0x00, 0x52, 0x05, 0x62, 0x6c, 0x6f, 0x63,
+	0x6b, 0x22, 0x51, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b,
+	0x0a, 0x07, 0x43, 0x48, 0x41, 0x4e, 0x4e, 0x45, 0x4c, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x50,
+	0x45, 0x45, 0x52, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x48, 0x54, 0x4c, 0x43, 0x10, 0x02, 0x12,
+	0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07,
+	0x50, 0x41, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x10, 0x04, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x4c, 0x4f,
+	0x43, 0x4b, 0x10, 0x05, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x1c, 0x0a,
+	0x1a, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x53, 0x74, 0x75, 0x63, 0x6b, 0x48,
+	0x74, 0x6c, 0x63, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xdc, 0x02, 0x0a, 0x09,
+	0x53, 0x74, 0x75, 0x63, 0x6b, 0x48, 0x74, 0x6c, 0x63, 0x12, 0x47, 0x0a, 0x14, 0x69, 0x6e, 0x63,
+	0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x5f, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x12,
+	0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b,
+	0x65, 0x79, 0x12, 0x47, 0x0a, 0x14, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x63,
+	0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x69, 0x72,
+	0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x12, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e,
+	0x67, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x70,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x2a,
+	0x0a, 0x11, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d,
+	0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6f, 0x6d,
+	0x69, 0x6e, 0x67, 0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x6f, 0x75,
+	0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x41,
+	0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x66, 0x69, 0x72, 0x73, 0x74, 0x5f,
+	0x73, 0x65, 0x65, 0x6e, 0x5f, 0x6e, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x66,
+	0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x65, 0x6e, 0x4e, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65,
+	0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x72, 0x65, 0x6d, 0x65, 0x64, 0x69, 0x61, 0x74, 0x65, 0x64, 0x22, 0x1c, 0x0a, 0x1a, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xc4, 0x06, 0x0a, 0x09, 0x48, 0x74, 0x6c,
+	0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69,
+	0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x11, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x13, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69,
+	0x6e, 0x67, 0x5f, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x11, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69,
+	0x6e, 0x67, 0x5f, 0x68, 0x74, 0x6c, 0x63, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x64,
+	0x12, 0x28, 0x0a, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x68, 0x74, 0x6c,
+	0x63, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67,
+	0x6f, 0x69, 0x6e, 0x67, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x5f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x0b, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x4e, 0x73, 0x12, 0x3d, 0x0a,
+	0x0a, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74,
+	0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x09, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x3e, 0x0a, 0x0d,
+	0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0c,
+	0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x4b, 0x0a, 0x12,
+	0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x65, 0x76, 0x65,
+	0x6e, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x46, 0x61, 0x69, 0x6c,
+	0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x10, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64,
+	0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3b, 0x0a, 0x0c, 0x73, 0x65, 0x74,
+	0x74, 0x6c, 0x65, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x74,
+	0x6c, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0b, 0x73, 0x65, 0x74, 0x74, 0x6c,
+	0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x42, 0x0a, 0x0f, 0x6c, 0x69, 0x6e, 0x6b, 0x5f, 0x66,
+	0x61, 0x69, 0x6c, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x6e, 0x6b,
+	0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0d, 0x6c, 0x69, 0x6e,
+	0x6b, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x47, 0x0a, 0x10, 0x73, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74,
+	0x48, 0x00, 0x52, 0x0f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x12, 0x45, 0x0a, 0x10, 0x66, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x68, 0x74, 0x6c,
+	0x63, 0x5f, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x69, 0x6e, 0x61, 0x6c, 0x48,
+	0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x48, 0x00, 0x52, 0x0e, 0x66, 0x69, 0x6e, 0x61,
+	0x6c, 0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x3c, 0x0a, 0x0e, 0x63, 0x6f,
+	0x72, 0x72, 0x65, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43,
+	0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x0d, 0x63, 0x6f, 0x72, 0x72, 0x65,
+	0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x3c, 0x0a, 0x09, 0x45, 0x76, 0x65, 0x6e,
+	0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
+	0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x45, 0x4e, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07,
+	0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x46, 0x4f, 0x52,
+	0x57, 0x41, 0x52, 0x44, 0x10, 0x03, 0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22,
+	0xbc, 0x01, 0x0a, 0x08, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x2b, 0x0a, 0x11,
+	0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63,
+	0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e,
+	0x67, 0x54, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2b, 0x0a, 0x11, 0x6f, 0x75, 0x74,
+	0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x54, 0x69,
+	0x6d, 0x65, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x2a, 0x0a, 0x11, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69,
+	0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0f, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x74, 0x4d, 0x73,
+	0x61, 0x74, 0x12, 0x2a, 0x0a, 0x11, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x61,
+	0x6d, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x6f,
+	0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x22, 0x37,
+	0x0a, 0x0c, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x27,
+	0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x6a, 0x0a, 0x10, 0x46, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x46, 0x61, 0x69, 0x6c, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x69,
+	0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04,
+	0x69, 0x6e, 0x66, 0x6f, 0x12, 0x2d, 0x0a, 0x12, 0x6f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x5f,
+	0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x11, 0x6f, 0x6e, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0x52, 0x0a, 0x0b, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x27,
+	0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x22, 0x46, 0x0a, 0x0e, 0x46, 0x69, 0x6e, 0x61, 0x6c,
+	0x48, 0x74, 0x6c, 0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x65, 0x74,
+	0x74, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x65, 0x74, 0x74,
+	0x6c, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6f, 0x66, 0x66, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6f, 0x66, 0x66, 0x63, 0x68, 0x61, 0x69, 0x6e, 0x22,
+	0x11, 0x0a, 0x0f, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x45, 0x76, 0x65,
+	0x6e, 0x74, 0x22, 0xdf, 0x01, 0x0a, 0x0d, 0x4c, 0x69, 0x6e, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48,
+	0x74, 0x6c, 0x63, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x04, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x3d, 0x0a,
+	0x0c, 0x77, 0x69, 0x72, 0x65, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c,
+	0x75, 0x72, 0x65, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x52,
+	0x0b, 0x77, 0x69, 0x72, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x12, 0x3f, 0x0a, 0x0e,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x52, 0x0d,
+	0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x25, 0x0a,
+	0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x53, 0x74,
+	0x72, 0x69, 0x6e, 0x67, 0x22, 0x8a, 0x01, 0x0a, 0x0d, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2d, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x12, 0x28, 0x0a, 0x05, 0x68, 0x74, 0x6c, 0x63, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x54, 0x4c, 0x43, 0x41, 0x74, 0x74,
+	0x65, 0x6d, 0x70, 0x74, 0x52, 0x05, 0x68, 0x74, 0x6c, 0x63, 0x73, 0x4a, 0x04, 0x08, 0x03, 0x10,
+	0x04, 0x22, 0x3e, 0x0a, 0x0a, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12,
+	0x17, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x68, 0x74, 0x6c, 0x63,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x68, 0x74, 0x6c, 0x63, 0x49,
+	0x64, 0x22, 0xa7, 0x06, 0x0a, 0x1b, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c,
+	0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x47, 0x0a, 0x14, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x69,
+	0x72, 0x63, 0x75, 0x69, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x69, 0x72, 0x63,
+	0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67,
+	0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x30, 0x0a, 0x14, 0x69, 0x6e,
+	0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73,
+	0x61, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69,
+	0x6e, 0x67, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f,
+	0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x45,
+	0x78, 0x70, 0x69, 0x72, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0b, 0x70, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x3b, 0x0a, 0x1a, 0x6f, 0x75, 0x74, 0x67,
+	0x6f, 0x69, 0x6e, 0x67, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x63,
+	0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x17, 0x6f, 0x75,
+	0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x64, 0x43,
+	0x68, 0x61, 0x6e, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e,
+	0x67, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x04, 0x52, 0x12, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x41, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x6f, 0x75, 0x74, 0x67, 0x6f,
+	0x69, 0x6e, 0x67, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0e, 0x6f, 0x75, 0x74, 0x67, 0x6f, 0x69, 0x6e, 0x67, 0x45, 0x78, 0x70, 0x69, 0x72, 0x79,
+	0x12, 0x60, 0x0a, 0x0e, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x2e, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x0d, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x6e, 0x69, 0x6f, 0x6e, 0x5f, 0x62, 0x6c, 0x6f, 0x62,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x6f, 0x6e, 0x69, 0x6f, 0x6e, 0x42, 0x6c, 0x6f,
+	0x62, 0x12, 0x28, 0x0a, 0x10, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x5f, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x61, 0x75, 0x74,
+	0x6f, 0x46, 0x61, 0x69, 0x6c, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x74, 0x0a, 0x16, 0x69,
+	0x6e, 0x5f, 0x77, 0x69, 0x72, 0x65, 0x5f, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x3f, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48,
+	0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x2e, 0x49, 0x6e, 0x57, 0x69, 0x72, 0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x13, 0x69, 0x6e,
+	0x57, 0x69, 0x72, 0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x73, 0x1a, 0x40, 0x0a, 0x12, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x46, 0x0a, 0x18, 0x49, 0x6e, 0x57, 0x69, 0x72, 0x65, 0x43, 0x75, 0x73,
+	0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xda, 0x04, 0x0a, 0x1c,
+	0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72,
+	0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x47, 0x0a, 0x14,
+	0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74,
+	0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65,
+	0x79, 0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x69, 0x72, 0x63, 0x75,
+	0x69, 0x74, 0x4b, 0x65, 0x79, 0x12, 0x3b, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x48, 0x6f, 0x6c, 0x64, 0x46, 0x6f, 0x72,
+	0x77, 0x61, 0x72, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x70, 0x72, 0x65, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x27,
+	0x0a, 0x0f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x3d, 0x0a, 0x0c, 0x66, 0x61, 0x69, 0x6c, 0x75,
+	0x72, 0x65, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e,
+	0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x2e, 0x46, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x0b, 0x66, 0x61, 0x69, 0x6c, 0x75,
+	0x72, 0x65, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x24, 0x0a, 0x0e, 0x69, 0x6e, 0x5f, 0x61, 0x6d, 0x6f,
+	0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0c,
+	0x69, 0x6e, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x26, 0x0a, 0x0f,
+	0x6f, 0x75, 0x74, 0x5f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0d, 0x6f, 0x75, 0x74, 0x41, 0x6d, 0x6f, 0x75, 0x6e, 0x74,
+	0x4d, 0x73, 0x61, 0x74, 0x12, 0x78, 0x0a, 0x17, 0x6f, 0x75, 0x74, 0x5f, 0x77, 0x69, 0x72, 0x65,
+	0x5f, 0x63, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x5f, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x41, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x4f,
+	0x75, 0x74, 0x57, 0x69, 0x72, 0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x14, 0x6f, 0x75, 0x74, 0x57, 0x69, 0x72,
+	0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1f,
+	0x0a, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x61, 0x63, 0x6b, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x41, 0x63, 0x6b, 0x1a,
+	0x47, 0x0a, 0x19, 0x4f, 0x75, 0x74, 0x57, 0x69, 0x72, 0x65, 0x43, 0x75, 0x73, 0x74, 0x6f, 0x6d,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x76,
+	0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x66, 0x0a, 0x19, 0x46, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74,
+	0x42, 0x61, 0x74, 0x63, 0x68, 0x12, 0x49, 0x0a, 0x0b, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74,
+	0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x22, 0x78, 0x0a, 0x17, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49,
+	0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x41, 0x63, 0x6b, 0x12, 0x47, 0x0a, 0x14, 0x69,
+	0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69, 0x74, 0x4b, 0x65, 0x79,
+	0x52, 0x12, 0x69, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x69, 0x72, 0x63, 0x75, 0x69,
+	0x74, 0x4b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22, 0x97, 0x01, 0x0a, 0x15, 0x48,
+	0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x6f, 0x72, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x12, 0x3c, 0x0a, 0x04, 0x68, 0x74, 0x6c, 0x63, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46,
+	0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63,
+	0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x04, 0x68, 0x74,
+	0x6c, 0x63, 0x12, 0x36, 0x0a, 0x03, 0x61, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74,
+	0x41, 0x63, 0x6b, 0x48, 0x00, 0x52, 0x03, 0x61, 0x63, 0x6b, 0x42, 0x08, 0x0a, 0x06, 0x75, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x22, 0x82, 0x01, 0x0a, 0x17, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43,
+	0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x32, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61,
+	0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6e, 0x50,
+	0x6f, 0x69, 0x6e, 0x74, 0x12, 0x33, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x1a, 0x0a, 0x18, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x32, 0x0a, 0x13, 0x44, 0x75, 0x73, 0x74, 0x45, 0x78, 0x70,
+	0x6f, 0x73, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x07,
+	0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30,
+	0x01, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x22, 0x52, 0x0a, 0x14, 0x44, 0x75, 0x73,
+	0x74, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x3a, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x75, 0x73, 0x74, 0x45, 0x78, 0x70, 0x6f, 0x73,
+	0x75, 0x72, 0x65, 0x52, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x22, 0xdd, 0x02,
+	0x0a, 0x13, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x44, 0x75, 0x73, 0x74, 0x45, 0x78, 0x70,
+	0x6f, 0x73, 0x75, 0x72, 0x65, 0x12, 0x1b, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e,
+	0x49, 0x64, 0x12, 0x33, 0x0a, 0x16, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x6d, 0x6d,
+	0x69, 0x74, 0x5f, 0x64, 0x75, 0x73, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x13, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x44,
+	0x75, 0x73, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x35, 0x0a, 0x17, 0x72, 0x65, 0x6d, 0x6f, 0x74,
+	0x65, 0x5f, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x64, 0x75, 0x73, 0x74, 0x5f, 0x6d, 0x73,
+	0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x14, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x43, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x44, 0x75, 0x73, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x35,
+	0x0a, 0x17, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x6d, 0x61, 0x69, 0x6c, 0x62, 0x6f, 0x78, 0x5f,
+	0x64, 0x75, 0x73, 0x74, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52,
+	0x14, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x4d, 0x61, 0x69, 0x6c, 0x62, 0x6f, 0x78, 0x44, 0x75, 0x73,
+	0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x37, 0x0a, 0x18, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f,
+	0x6d, 0x61, 0x69, 0x6c, 0x62, 0x6f, 0x78, 0x5f, 0x64, 0x75, 0x73, 0x74, 0x5f, 0x6d, 0x73, 0x61,
+	0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x15, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x4d,
+	0x61, 0x69, 0x6c, 0x62, 0x6f, 0x78, 0x44, 0x75, 0x73, 0x74, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x31,
+	0x0a, 0x15, 0x6d, 0x61, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x73, 0x75,
+	0x72, 0x65, 0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6d,
+	0x61, 0x78, 0x46, 0x65, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x4d, 0x73, 0x61,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x65, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x65, 0x78, 0x63, 0x65, 0x65, 0x64, 0x65, 0x64, 0x22, 0x50, 0x0a,
+	0x1b, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x78, 0x46, 0x65, 0x65, 0x45, 0x78, 0x70,
+	0x6f, 0x73, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x31, 0x0a, 0x15,
+	0x6d, 0x61, 0x78, 0x5f, 0x66, 0x65, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65,
+	0x5f, 0x6d, 0x73, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x6d, 0x61, 0x78,
+	0x46, 0x65, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x4d, 0x73, 0x61, 0x74, 0x22,
+	0x1e, 0x0a, 0x1c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x78, 0x46, 0x65, 0x65, 0x45,
+	0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x37, 0x0a, 0x18, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x75, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1b, 0x0a, 0x07, 0x63,
+	0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01,
+	0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x49, 0x64, 0x22, 0x5d, 0x0a, 0x19, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x75, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x08, 0x63, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x49, 0x6e, 0x63, 0x6f, 0x6d, 0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e,
+	0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x75, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x63,
+	0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x73, 0x22, 0x4f, 0x0a, 0x19, 0x46, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x63,
+	0x68, 0x61, 0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x22, 0x56, 0x0a, 0x1a, 0x46, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x38, 0x0a, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67,
+	0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x08, 0x70, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73,
+	0x22, 0xdc, 0x02, 0x0a, 0x11, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x12, 0x28, 0x0a, 0x0e, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
+	0x5f, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02,
+	0x30, 0x01, 0x52, 0x0c, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x49, 0x64,
+	0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x37, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x21, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x6e, 0x75, 0x6d, 0x5f, 0x61, 0x64, 0x64, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x07, 0x6e, 0x75, 0x6d, 0x41, 0x64, 0x64, 0x73, 0x12, 0x2c, 0x0a, 0x12,
+	0x6e, 0x75, 0x6d, 0x5f, 0x66, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x5f, 0x61, 0x64,
+	0x64, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x10, 0x6e, 0x75, 0x6d, 0x46, 0x6f, 0x72,
+	0x77, 0x61, 0x72, 0x64, 0x65, 0x64, 0x41, 0x64, 0x64, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6e, 0x75,
+	0x6d, 0x5f, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x61, 0x64, 0x64, 0x73, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0c, 0x6e, 0x75, 0x6d, 0x41, 0x63, 0x6b, 0x65, 0x64, 0x41, 0x64, 0x64, 0x73,
+	0x12, 0x28, 0x0a, 0x10, 0x6e, 0x75, 0x6d, 0x5f, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x5f, 0x66,
+	0x61, 0x69, 0x6c, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x6e, 0x75, 0x6d, 0x53,
+	0x65, 0x74, 0x74, 0x6c, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x73, 0x12, 0x33, 0x0a, 0x16, 0x6e, 0x75,
+	0x6d, 0x5f, 0x61, 0x63, 0x6b, 0x65, 0x64, 0x5f, 0x73, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x5f, 0x66,
+	0x61, 0x69, 0x6c, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x13, 0x6e, 0x75, 0x6d, 0x41,
+	0x63, 0x6b, 0x65, 0x64, 0x53, 0x65, 0x74, 0x74, 0x6c, 0x65, 0x46, 0x61, 0x69, 0x6c, 0x73, 0x22,
+	0x6f, 0x0a, 0x21, 0x52, 0x65, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x46, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x0a, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x70, 0x6f, 0x69,
+	0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63,
+	0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x52, 0x09, 0x63,
+	0x68, 0x61, 0x6e, 0x50, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x22, 0x24, 0x0a, 0x22, 0x52, 0x65, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x46, 0x6f, 0x72,
+	0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0xb4, 0x01, 0x0a, 0x19, 0x49, 0x6e, 0x63, 0x6f, 0x6d,
+	0x69, 0x6e, 0x67, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x75, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x07, 0x63, 0x68, 0x61, 0x6e, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x04, 0x42, 0x02, 0x30, 0x01, 0x52, 0x06, 0x63, 0x68, 0x61, 0x6e, 0x49,
+	0x64, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x70, 0x75, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
+	0x6d, 0x73, 0x61, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x72, 0x65, 0x70, 0x75,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x2d, 0x0a, 0x13, 0x69, 0x6e,
+	0x5f, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x5f, 0x72, 0x69, 0x73, 0x6b, 0x5f, 0x6d, 0x73, 0x61,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x69, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68,
+	0x74, 0x52, 0x69, 0x73, 0x6b, 0x4d, 0x73, 0x61, 0x74, 0x12, 0x22, 0x0a, 0x0d, 0x6e, 0x75, 0x6d,
+	0x5f, 0x69, 0x6e, 0x5f, 0x66, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0b, 0x6e, 0x75, 0x6d, 0x49, 0x6e, 0x46, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x22, 0x43, 0x0a,
+	0x11, 0x41, 0x64, 0x64, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2e, 0x0a, 0x0a, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x5f, 0x6d, 0x61, 0x70, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x41,
+	0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x52, 0x09, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61,
+	0x70, 0x73, 0x22, 0x44, 0x0a, 0x12, 0x41, 0x64, 0x64, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x0a, 0x61, 0x6c, 0x69, 0x61,
+	0x73, 0x5f, 0x6d, 0x61, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6c,
+	0x6e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x52, 0x09, 0x61,
+	0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x73, 0x22, 0x46, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x2e, 0x0a, 0x0a, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x5f, 0x6d, 0x61, 0x70, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6c, 0x69,
+	0x61, 0x73, 0x4d, 0x61, 0x70, 0x52, 0x09, 0x61, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x73,
+	0x22, 0x47, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x0a, 0x61, 0x6c, 0x69,
+	0x61, 0x73, 0x5f, 0x6d, 0x61, 0x70, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e,
+	0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x52, 0x09,
+	0x61, 0x6c, 0x69, 0x61, 0x73, 0x4d, 0x61, 0x70, 0x73, 0x2a, 0x81, 0x04, 0x0a, 0x0d, 0x46, 0x61,
+	0x69, 0x6c, 0x75, 0x72, 0x65, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x0b, 0x0a, 0x07, 0x55,
+	0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x4f, 0x5f, 0x44,
+	0x45, 0x54, 0x41, 0x49, 0x4c, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x4f, 0x4e, 0x49, 0x4f, 0x4e,
+	0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x4c, 0x49, 0x4e,
+	0x4b, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x45, 0x4c, 0x49, 0x47, 0x49, 0x42, 0x4c, 0x45, 0x10, 0x03,
+	0x12, 0x14, 0x0a, 0x10, 0x4f, 0x4e, 0x5f, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x5f, 0x54, 0x49, 0x4d,
+	0x45, 0x4f, 0x55, 0x54, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x48, 0x54, 0x4c, 0x43, 0x5f, 0x45,
+	0x58, 0x43, 0x45, 0x45, 0x44, 0x53, 0x5f, 0x4d, 0x41, 0x58, 0x10, 0x05, 0x12, 0x18, 0x0a, 0x14,
+	0x49, 0x4e, 0x53, 0x55, 0x46, 0x46, 0x49, 0x43, 0x49, 0x45, 0x4e, 0x54, 0x5f, 0x42, 0x41, 0x4c,
+	0x41, 0x4e, 0x43, 0x45, 0x10, 0x06, 0x12, 0x16, 0x0a, 0x12, 0x49, 0x4e, 0x43, 0x4f, 0x4d, 0x50,
+	0x4c, 0x45, 0x54, 0x45, 0x5f, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x10, 0x07, 0x12, 0x13,
+	0x0a, 0x0f, 0x48, 0x54, 0x4c, 0x43, 0x5f, 0x41, 0x44, 0x44, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45,
+	0x44, 0x10, 0x08, 0x12, 0x15, 0x0a, 0x11, 0x46, 0x4f, 0x52, 0x57, 0x41, 0x52, 0x44, 0x53, 0x5f,
+	0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x44, 0x10, 0x09, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e,
+	0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x45, 0x44, 0x10, 0x0a,
+	0x12, 0x15, 0x0a, 0x11, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x55, 0x4e, 0x44, 0x45,
+	0x52, 0x50, 0x41, 0x49, 0x44, 0x10, 0x0b, 0x12, 0x1b, 0x0a, 0x17, 0x49, 0x4e, 0x56, 0x4f, 0x49,
+	0x43, 0x45, 0x5f, 0x45, 0x58, 0x50, 0x49, 0x52, 0x59, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x53, 0x4f,
+	0x4f, 0x4e, 0x10, 0x0c, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f,
+	0x4e, 0x4f, 0x54, 0x5f, 0x4f, 0x50, 0x45, 0x4e, 0x10, 0x0d, 0x12, 0x17, 0x0a, 0x13, 0x4d, 0x50,
+	0x50, 0x5f, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55,
+	0x54, 0x10, 0x0e, 0x12, 0x14, 0x0a, 0x10, 0x41, 0x44, 0x44, 0x52, 0x45, 0x53, 0x53, 0x5f, 0x4d,
+	0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x0f, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x45, 0x54,
+	0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10,
+	0x10, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x45, 0x54, 0x5f, 0x54, 0x4f, 0x54, 0x41, 0x4c, 0x5f, 0x54,
+	0x4f, 0x4f, 0x5f, 0x4c, 0x4f, 0x57, 0x10, 0x11, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x54, 0x5f,
+	0x4f, 0x56, 0x45, 0x52, 0x50, 0x41, 0x49, 0x44, 0x10, 0x12, 0x12, 0x13, 0x0a, 0x0f, 0x55, 0x4e,
+	0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x49, 0x4e, 0x56, 0x4f, 0x49, 0x43, 0x45, 0x10, 0x13, 0x12,
+	0x13, 0x0a, 0x0f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x4b, 0x45, 0x59, 0x53, 0x45,
+	0x4e, 0x44, 0x10, 0x14, 0x12, 0x13, 0x0a, 0x0f, 0x4d, 0x50, 0x50, 0x5f, 0x49, 0x4e, 0x5f, 0x50,
+	0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53, 0x10, 0x15, 0x12, 0x12, 0x0a, 0x0e, 0x43, 0x49, 0x52,
+	0x43, 0x55, 0x4c, 0x41, 0x52, 0x5f, 0x52, 0x4f, 0x55, 0x54, 0x45, 0x10, 0x16, 0x2a, 0xae, 0x01,
+	0x0a, 0x0c, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x0d,
+	0x0a, 0x09, 0x49, 0x4e, 0x5f, 0x46, 0x4c, 0x49, 0x47, 0x48, 0x54, 0x10, 0x00, 0x12, 0x0d, 0x0a,
+	0x09, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44, 0x10, 0x01, 0x12, 0x12, 0x0a, 0x0e,
+	0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x02,
+	0x12, 0x13, 0x0a, 0x0f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x4e, 0x4f, 0x5f, 0x52, 0x4f,
+	0x55, 0x54, 0x45, 0x10, 0x03, 0x12, 0x10, 0x0a, 0x0c, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f,
+	0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x04, 0x12, 0x24, 0x0a, 0x20, 0x46, 0x41, 0x49, 0x4c, 0x45,
+	0x44, 0x5f, 0x49, 0x4e, 0x43, 0x4f, 0x52, 0x52, 0x45, 0x43, 0x54, 0x5f, 0x50, 0x41, 0x59, 0x4d,
+	0x45, 0x4e, 0x54, 0x5f, 0x44, 0x45, 0x54, 0x41, 0x49, 0x4c, 0x53, 0x10, 0x05, 0x12, 0x1f, 0x0a,
+	0x1b, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x5f, 0x49, 0x4e, 0x53, 0x55, 0x46, 0x46, 0x49, 0x43,
+	0x49, 0x45, 0x4e, 0x54, 0x5f, 0x42, 0x41, 0x4c, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x06, 0x2a, 0x51,
+	0x0a, 0x18, 0x52, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x48, 0x6f, 0x6c, 0x64, 0x46, 0x6f, 0x72,
+	0x77, 0x61, 0x72, 0x64, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x45,
+	0x54, 0x54, 0x4c, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x46, 0x41, 0x49, 0x4c, 0x10, 0x01,
+	0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x53, 0x55, 0x4d, 0x45, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f,
+	0x52, 0x45, 0x53, 0x55, 0x4d, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10,
+	0x03, 0x2a, 0x35, 0x0a, 0x10, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x41,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0a, 0x0a, 0x06, 0x45, 0x4e, 0x41, 0x42, 0x4c, 0x45, 0x10,
+	0x00, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x49, 0x53, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x01, 0x12, 0x08,
+	0x0a, 0x04, 0x41, 0x55, 0x54, 0x4f, 0x10, 0x02, 0x2a, 0x5d, 0x0a, 0x16, 0x46, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x46, 0x57, 0x44, 0x5f, 0x50, 0x4b, 0x47, 0x5f, 0x4c, 0x4f,
+	0x43, 0x4b, 0x45, 0x44, 0x5f, 0x49, 0x4e, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x46, 0x57, 0x44,
+	0x5f, 0x50, 0x4b, 0x47, 0x5f, 0x50, 0x52, 0x4f, 0x43, 0x45, 0x53, 0x53, 0x45, 0x44, 0x10, 0x01,
+	0x12, 0x15, 0x0a, 0x11, 0x46, 0x57, 0x44, 0x5f, 0x50, 0x4b, 0x47, 0x5f, 0x43, 0x4f, 0x4d, 0x50,
+	0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x02, 0x32, 0xd8, 0x15, 0x0a, 0x06, 0x52, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x12, 0x40, 0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
+	0x74, 0x56, 0x32, 0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x56, 0x32, 0x12, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x50,
+	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x42, 0x0a, 0x0d, 0x54, 0x72, 0x61, 0x63,
+	0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65,
+	0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x0e, 0x2e, 0x6c, 0x6e, 0x72,
+	0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x51, 0x0a, 0x0c,
+	0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x1e, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12,
+	0x4b, 0x0a, 0x10, 0x45, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x46, 0x65, 0x65, 0x12, 0x1a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x52, 0x6f, 0x75, 0x74, 0x65, 0x46, 0x65, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x6f, 0x75, 0x74,
+	0x65, 0x46, 0x65, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x51, 0x0a, 0x0b,
+	0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x1d, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f,
+	0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x03, 0x88, 0x02, 0x01, 0x12,
+	0x42, 0x0a, 0x0d, 0x53, 0x65, 0x6e, 0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x56, 0x32,
+	0x12, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e,
+	0x64, 0x54, 0x6f, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x12, 0x2e, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x54, 0x4c, 0x43, 0x41, 0x74, 0x74, 0x65,
+	0x6d, 0x70, 0x74, 0x12, 0x64, 0x0a, 0x13, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65,
+	0x73, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a, 0x13, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65,
+	0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e,
+	0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x6a, 0x0a, 0x15, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x12, 0x27, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x58, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x28, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x58, 0x49,
+	0x6d, 0x70, 0x6f, 0x72, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74,
+	0x72, 0x6f, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a, 0x17, 0x47,
+	0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x29, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e,
+	0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x2a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x47, 0x65,
+	0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x70, 0x0a,
+	0x17, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72,
+	0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x29, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43,
+	0x6f, 0x6e, 0x74, 0x72, 0x6f, 0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x2a, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x53, 0x65, 0x74, 0x4d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x6f,
+	0x6c, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x5b, 0x0a, 0x10, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c,
+	0x69, 0x74, 0x79, 0x12, 0x22, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e,
+	0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x51, 0x75, 0x65, 0x72, 0x79, 0x50, 0x72, 0x6f, 0x62, 0x61, 0x62, 0x69,
+	0x6c, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0c,
+	0x50, 0x72, 0x6f, 0x62, 0x65, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1e, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x72,
+	0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x72, 0x6f, 0x62, 0x65, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x49, 0x0a,
+	0x0a, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75,
+	0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x52, 0x6f, 0x75, 0x74, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x09, 0x52, 0x65, 0x62, 0x61,
+	0x6c, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x52, 0x65, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x65, 0x62, 0x61, 0x6c, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x54, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74, 0x6c,
+	0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x12, 0x25, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x48, 0x74, 0x6c,
+	0x63, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x14,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x48, 0x74, 0x6c, 0x63, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x4e, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x41, 0x6c, 0x6c, 0x12, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x41, 0x6c, 0x6c, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x41, 0x6c, 0x6c, 0x45,
+	0x76, 0x65, 0x6e, 0x74, 0x30, 0x01, 0x12, 0x54, 0x0a, 0x13, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x53, 0x74, 0x75, 0x63, 0x6b, 0x48, 0x74, 0x6c, 0x63, 0x73, 0x12, 0x25, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x53, 0x74, 0x75, 0x63, 0x6b, 0x48, 0x74, 0x6c, 0x63, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x53, 0x74, 0x75, 0x63, 0x6b, 0x48, 0x74, 0x6c, 0x63, 0x30, 0x01, 0x12, 0x4d, 0x0a, 0x0b,
+	0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d,
+	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x72, 0x6f, 0x75,
+	0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x03, 0x88, 0x02, 0x01, 0x30, 0x01, 0x12, 0x4f, 0x0a, 0x0c, 0x54,
+	0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x54, 0x72, 0x61, 0x63, 0x6b, 0x50, 0x61, 0x79,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x18, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x03, 0x88, 0x02, 0x01, 0x30, 0x01, 0x12, 0x66, 0x0a, 0x0f,
+	0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x6f, 0x72, 0x12,
+	0x27, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77,
+	0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x1a, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x48, 0x74, 0x6c, 0x63,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x28, 0x01, 0x30, 0x01, 0x12, 0x64, 0x0a, 0x16, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x63, 0x65, 0x70, 0x74, 0x6f, 0x72, 0x42, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x12, 0x24,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61,
+	0x72, 0x64, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x1a, 0x20, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63,
+	0x2e, 0x48, 0x74, 0x6c, 0x63, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x63, 0x65, 0x70, 0x74, 0x6f, 0x72,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x28, 0x01, 0x30, 0x01, 0x12, 0x5b, 0x0a, 0x10, 0x55, 0x70,
+	0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x22,
+	0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x68, 0x61, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4f, 0x0a, 0x0c, 0x44, 0x75, 0x73, 0x74, 0x45,
+	0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x12, 0x1e, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x44, 0x75, 0x73, 0x74, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x44, 0x75, 0x73, 0x74, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x67, 0x0a, 0x14, 0x55, 0x70, 0x64, 0x61,
+	0x74, 0x65, 0x4d, 0x61, 0x78, 0x46, 0x65, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65,
+	0x12, 0x26, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x4d, 0x61, 0x78, 0x46, 0x65, 0x65, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x27, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65,
+	0x72, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x78, 0x46, 0x65,
+	0x65, 0x45, 0x78, 0x70, 0x6f, 0x73, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x5e, 0x0a, 0x11, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x75,
+	0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72,
+	0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52, 0x65, 0x70, 0x75, 0x74, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x72, 0x6f,
+	0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x68, 0x61, 0x6e, 0x6e, 0x65, 0x6c, 0x52,
+	0x65, 0x70, 0x75, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x12, 0x61, 0x0a, 0x12, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50,
+	0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x12, 0x24, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61,
+	0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x25, 0x2e,
+	0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72,
+	0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x79, 0x0a, 0x1a, 0x52, 0x65, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73,
+	0x73, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67, 0x50, 0x61, 0x63, 0x6b, 0x61,
+	0x67, 0x65, 0x12, 0x2c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52,
+	0x65, 0x70, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69,
+	0x6e, 0x67, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x2d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x70,
+	0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x46, 0x6f, 0x72, 0x77, 0x61, 0x72, 0x64, 0x69, 0x6e, 0x67,
+	0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x53, 0x0a, 0x14, 0x58, 0x41, 0x64, 0x64, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x6e,
+	0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12, 0x1c, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72,
+	0x72, 0x70, 0x63, 0x2e, 0x41, 0x64, 0x64, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70,
+	0x63, 0x2e, 0x41, 0x64, 0x64, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5c, 0x0a, 0x17, 0x58, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x4c,
+	0x6f, 0x63, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x6e, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x12,
+	0x1f, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x20, 0x2e, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x72, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x41, 0x6c, 0x69, 0x61, 0x73, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x42, 0x31, 0x5a, 0x2f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x6c, 0x69, 0x67, 0x68, 0x74, 0x6e, 0x69, 0x6e, 0x67, 0x6e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x2f, 0x6c, 0x6e, 0x64, 0x2f, 0x6c, 0x6e, 0x72, 0x70, 0x63, 0x2f, 0x72, 0x6f, 0x75, 0x74,
+	0x65, 0x72, 0x72, 0x70, 0x63, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_routerrpc_router_proto_rawDescOnce sync.Once
+	file_routerrpc_router_proto_rawDescData = file_routerrpc_router_proto_rawDesc
+)
+
+func file_routerrpc_router_proto_rawDescGZIP() []byte {
+	file_routerrpc_router_proto_rawDescOnce.Do(func() {
+		file_routerrpc_router_proto_rawDescData = protoimpl.X.CompressGZIP(file_routerrpc_router_proto_rawDescData)
+	})
+	return file_routerrpc_router_proto_rawDescData
+}
+
+var file_routerrpc_router_proto_enumTypes = make([]protoimpl.EnumInfo, 8)
+var file_routerrpc_router_proto_msgTypes = make([]protoimpl.MessageInfo, 88)
+var file_routerrpc_router_proto_goTypes = []interface{}{
+	(FailureDetail)(0),                         // 0: routerrpc.FailureDetail
+	(PaymentState)(0),                          // 1: routerrpc.PaymentState
+	(ResolveHoldForwardAction)(0),              // 2: routerrpc.ResolveHoldForwardAction
+	(ChanStatusAction)(0),                      // 3: routerrpc.ChanStatusAction
+	(ForwardingPackageState)(0),                // 4: routerrpc.ForwardingPackageState
+	(MissionControlConfig_ProbabilityModel)(0), // 5: routerrpc.MissionControlConfig.ProbabilityModel
+	(SubscribeAllEvent_EventType)(0),           // 6: routerrpc.SubscribeAllEvent.EventType
+	(HtlcEvent_EventType)(0),                   // 7: routerrpc.HtlcEvent.EventType
+	(*SendPaymentRequest)(nil),                 // 8: routerrpc.SendPaymentRequest
+	(*TrampolineOptions)(nil),                  // 9: routerrpc.TrampolineOptions
+	(*HopCustomRecords)(nil),                   // 10: routerrpc.HopCustomRecords
+	(*PaymentBudget)(nil),                      // 11: routerrpc.PaymentBudget
+	(*TrackPaymentRequest)(nil),                // 12: routerrpc.TrackPaymentRequest
+	(*TrackPaymentsRequest)(nil),               // 13: routerrpc.TrackPaymentsRequest
+	(*SendPaymentsRequest)(nil),                // 14: routerrpc.SendPaymentsRequest
+	(*SendPaymentsResponse)(nil),               // 15: routerrpc.SendPaymentsResponse
+	(*BatchPaymentStatus)(nil),                 // 16: routerrpc.BatchPaymentStatus
+	(*BatchFailureReasonCount)(nil),            // 17: routerrpc.BatchFailureReasonCount
+	(*BatchPaymentOutcome)(nil),                // 18: routerrpc.BatchPaymentOutcome
+	(*RouteFeeRequest)(nil),                    // 19: routerrpc.RouteFeeRequest
+	(*RouteFeeResponse)(nil),                   // 20: routerrpc.RouteFeeResponse
+	(*SendToRouteRequest)(nil),                 // 21: routerrpc.SendToRouteRequest
+	(*SendToRouteResponse)(nil),                // 22: routerrpc.SendToRouteResponse
+	(*ResetMissionControlRequest)(nil),         // 23: routerrpc.ResetMissionControlRequest
+	(*ResetMissionControlResponse)(nil),        // 24: routerrpc.ResetMissionControlResponse
+	(*QueryMissionControlRequest)(nil),         // 25: routerrpc.QueryMissionControlRequest
+	(*QueryMissionControlResponse)(nil),        // 26: routerrpc.QueryMissionControlResponse
+	(*XImportMissionControlRequest)(nil),       // 27: routerrpc.XImportMissionControlRequest
+	(*XImportMissionControlResponse)(nil),      // 28: routerrpc.XImportMissionControlResponse
+	(*PairHistory)(nil),                        // 29: routerrpc.PairHistory
+	(*PairData)(nil),                           // 30: routerrpc.PairData
+	(*GetMissionControlConfigRequest)(nil),     // 31: routerrpc.GetMissionControlConfigRequest
+	(*GetMissionControlConfigResponse)(nil),    // 32: routerrpc.GetMissionControlConfigResponse
+	(*SetMissionControlConfigRequest)(nil),     // 33: routerrpc.SetMissionControlConfigRequest
+	(*SetMissionControlConfigResponse)(nil),    // 34: routerrpc.SetMissionControlConfigResponse
+	(*MissionControlConfig)(nil),               // 35: routerrpc.MissionControlConfig
+	(*BimodalParameters)(nil),                  // 36: routerrpc.BimodalParameters
+	(*AprioriParameters)(nil),                  // 37: routerrpc.AprioriParameters
+	(*QueryProbabilityRequest)(nil),            // 38: routerrpc.QueryProbabilityRequest
+	(*QueryProbabilityResponse)(nil),           // 39: routerrpc.QueryProbabilityResponse
+	(*ProbeHistoryRequest)(nil),                // 40: routerrpc.ProbeHistoryRequest
+	(*ProbeResult)(nil),                        // 41: routerrpc.ProbeResult
+	(*ProbeHistoryResponse)(nil),               // 42: routerrpc.ProbeHistoryResponse
+	(*BuildRouteRequest)(nil),                  // 43: routerrpc.BuildRouteRequest
+	(*BuildRouteResponse)(nil),                 // 44: routerrpc.BuildRouteResponse
+	(*RebalanceRequest)(nil),                   // 45: routerrpc.RebalanceRequest
+	(*RebalanceChannel)(nil),                   // 46: routerrpc.RebalanceChannel
+	(*RebalanceResponse)(nil),                  // 47: routerrpc.RebalanceResponse
+	(*SubscribeAllRequest)(nil),                // 48: routerrpc.SubscribeAllRequest
+	(*SubscribeAllEvent)(nil),                  // 49: routerrpc.SubscribeAllEvent
+	(*SubscribeStuckHtlcsRequest)(nil),         // 50: routerrpc.SubscribeStuckHtlcsRequest
+	(*StuckHtlc)(nil),                          // 51: routerrpc.StuckHtlc
+	(*SubscribeHtlcEventsRequest)(nil),         // 52: routerrpc.SubscribeHtlcEventsRequest
+	(*HtlcEvent)(nil),                          // 53: routerrpc.HtlcEvent
+	(*HtlcInfo)(nil),                           // 54: routerrpc.HtlcInfo
+	(*ForwardEvent)(nil),                       // 55: routerrpc.ForwardEvent
+	(*ForwardFailEvent)(nil),                   // 56: routerrpc.ForwardFailEvent
+	(*SettleEvent)(nil),                        // 57: routerrpc.SettleEvent
+	(*FinalHtlcEvent)(nil),                     // 58: routerrpc.FinalHtlcEvent
+	(*SubscribedEvent)(nil),                    // 59: routerrpc.SubscribedEvent
+	(*LinkFailEvent)(nil),                      // 60: routerrpc.LinkFailEvent
+	(*PaymentStatus)(nil),                      // 61: routerrpc.PaymentStatus
+	(*CircuitKey)(nil),                         // 62: routerrpc.CircuitKey
+	(*ForwardHtlcInterceptRequest)(nil),        // 63: routerrpc.ForwardHtlcInterceptRequest
+	(*ForwardHtlcInterceptResponse)(nil),       // 64: routerrpc.ForwardHtlcInterceptResponse
+	(*ForwardHtlcInterceptBatch)(nil),          // 65: routerrpc.ForwardHtlcInterceptBatch
+	(*ForwardHtlcInterceptAck)(nil),            // 66: routerrpc.ForwardHtlcInterceptAck
+	(*HtlcInterceptorUpdate)(nil),              // 67: routerrpc.HtlcInterceptorUpdate
+	(*UpdateChanStatusRequest)(nil),            // 68: routerrpc.UpdateChanStatusRequest
+	(*UpdateChanStatusResponse)(nil),           // 69: routerrpc.UpdateChanStatusResponse
+	(*DustExposureRequest)(nil),                // 70: routerrpc.DustExposureRequest
+	(*DustExposureResponse)(nil),               // 71: routerrpc.DustExposureResponse
+	(*ChannelDustExposure)(nil),                // 72: routerrpc.ChannelDustExposure
+	(*UpdateMaxFeeExposureRequest)(nil),        // 73: routerrpc.UpdateMaxFeeExposureRequest
+	(*UpdateMaxFeeExposureResponse)(nil),       // 74: routerrpc.UpdateMaxFeeExposureResponse
+	(*ChannelReputationRequest)(nil),           // 75: routerrpc.ChannelReputationRequest
+	(*ChannelReputationResponse)(nil),          // 76: routerrpc.ChannelReputationResponse
+	(*ForwardingPackagesRequest)(nil),          // 77: routerrpc.ForwardingPackagesRequest
+	(*ForwardingPackagesResponse)(nil),         // 78: routerrpc.ForwardingPackagesResponse
+	(*ForwardingPackage)(nil),                  // 79: routerrpc.ForwardingPackage
+	(*ReprocessForwardingPackageRequest)(nil),  // 80: routerrpc.ReprocessForwardingPackageRequest
+	(*ReprocessForwardingPackageResponse)(nil), // 81: routerrpc.ReprocessForwardingPackageResponse
+	(*IncomingChannelReputation)(nil),          // 82: routerrpc.IncomingChannelReputation
+	(*AddAliasesRequest)(nil),                  // 83: routerrpc.AddAliasesRequest
+	(*AddAliasesResponse)(nil),                 // 84: routerrpc.AddAliasesResponse
+	(*DeleteAliasesRequest)(nil),               // 85: routerrpc.DeleteAliasesRequest
+	(*DeleteAliasesResponse)(nil),              // 86: routerrpc.DeleteAliasesResponse
+	nil,                                        // 87: routerrpc.SendPaymentRequest.DestCustomRecordsEntry
+	nil,                                        // 88: routerrpc.SendPaymentRequest.FirstHopCustomRecordsEntry
+	nil,                                        // 89: routerrpc.HopCustomRecords.CustomRecordsEntry
+	nil,                                        // 90: routerrpc.BatchPaymentOutcome.InitErrorsEntry
+	nil,                                        // 91: routerrpc.SendToRouteRequest.FirstHopCustomRecordsEntry
+	nil,                                        // 92: routerrpc.BuildRouteRequest.FirstHopCustomRecordsEntry
+	nil,                                        // 93: routerrpc.ForwardHtlcInterceptRequest.CustomRecordsEntry
+	nil,                                        // 94: routerrpc.ForwardHtlcInterceptRequest.InWireCustomRecordsEntry
+	nil,                                        // 95: routerrpc.ForwardHtlcInterceptResponse.OutWireCustomRecordsEntry
+	(*lnrpc.RouteHint)(nil),                    // 96: lnrpc.RouteHint
+	(lnrpc.FeatureBit)(0),                      // 97: lnrpc.FeatureBit
+	(*lnrpc.CltvBudget)(nil),                   // 98: lnrpc.CltvBudget
+	(*lnrpc.Payment)(nil),                      // 99: lnrpc.Payment
+	(lnrpc.PaymentFailureReason)(0),            // 100: lnrpc.PaymentFailureReason
+	(*lnrpc.Route)(nil),                        // 101: lnrpc.Route
+	(*lnrpc.Failure)(nil),                      // 102: lnrpc.Failure
+	(*lnrpc.ChannelPoint)(nil),                 // 103: lnrpc.ChannelPoint
+	(*lnrpc.ChannelEventUpdate)(nil),           // 104: lnrpc.ChannelEventUpdate
+	(*lnrpc.PeerEvent)(nil),                    // 105: lnrpc.PeerEvent
+	(*lnrpc.Invoice)(nil),                      // 106: lnrpc.Invoice
+	(*chainrpc.BlockEpoch)(nil),                // 107: chainrpc.BlockEpoch
+	(lnrpc.Failure_FailureCode)(0),             // 108: lnrpc.Failure.FailureCode
+	(*lnrpc.HTLCAttempt)(nil),                  // 109: lnrpc.HTLCAttempt
+	(*lnrpc.AliasMap)(nil),                     // 110: lnrpc.AliasMap
+}
+var file_routerrpc_router_proto_depIdxs = []int32{
+	96,  // 0: routerrpc.SendPaymentRequest.route_hints:type_name -> lnrpc.RouteHint
+	87,  // 1: routerrpc.SendPaymentRequest.dest_custom_records:type_name -> routerrpc.SendPaymentRequest.DestCustomRecordsEntry
+	97,  // 2: routerrpc.SendPaymentRequest.dest_features:type_name -> lnrpc.FeatureBit
+	88,  // 3: routerrpc.SendPaymentRequest.first_hop_custom_records:type_name -> routerrpc.SendPaymentRequest.FirstHopCustomRecordsEntry
+	11,  // 4: routerrpc.SendPaymentRequest.budget:type_name -> routerrpc.PaymentBudget
+	10,  // 5: routerrpc.SendPaymentRequest.hop_custom_records:type_name -> routerrpc.HopCustomRecords
+	9,   // 6: routerrpc.SendPaymentRequest.trampoline:type_name -> routerrpc.TrampolineOptions
+	98,  // 7: routerrpc.SendPaymentRequest.cltv_budget:type_name -> lnrpc.CltvBudget
+	89,  // 8: routerrpc.HopCustomRecords.custom_records:type_name -> routerrpc.HopCustomRecords.CustomRecordsEntry
+	8,   // 9: routerrpc.SendPaymentsRequest.payments:type_name -> routerrpc.SendPaymentRequest
+	16,  // 10: routerrpc.SendPaymentsResponse.payment_status:type_name -> routerrpc.BatchPaymentStatus
+	18,  // 11: routerrpc.SendPaymentsResponse.summary:type_name -> routerrpc.BatchPaymentOutcome
+	99,  // 12: routerrpc.BatchPaymentStatus.payment:type_name -> lnrpc.Payment
+	100, // 13: routerrpc.BatchFailureReasonCount.reason:type_name -> lnrpc.PaymentFailureReason
+	17,  // 14: routerrpc.BatchPaymentOutcome.failure_reasons:type_name -> routerrpc.BatchFailureReasonCount
+	90,  // 15: routerrpc.BatchPaymentOutcome.init_errors:type_name -> routerrpc.BatchPaymentOutcome.InitErrorsEntry
+	100, // 16: routerrpc.RouteFeeResponse.failure_reason:type_name -> lnrpc.PaymentFailureReason
+	101, // 17: routerrpc.SendToRouteRequest.route:type_name -> lnrpc.Route
+	91,  // 18: routerrpc.SendToRouteRequest.first_hop_custom_records:type_name -> routerrpc.SendToRouteRequest.FirstHopCustomRecordsEntry
+	102, // 19: routerrpc.SendToRouteResponse.failure:type_name -> lnrpc.Failure
+	29,  // 20: routerrpc.QueryMissionControlResponse.pairs:type_name -> routerrpc.PairHistory
+	29,  // 21: routerrpc.XImportMissionControlRequest.pairs:type_name -> routerrpc.PairHistory
+	30,  // 22: routerrpc.PairHistory.history:type_name -> routerrpc.PairData
+	35,  // 23: routerrpc.GetMissionControlConfigResponse.config:type_name -> routerrpc.MissionControlConfig
+	35,  // 24: routerrpc.SetMissionControlConfigRequest.config:type_name -> routerrpc.MissionControlConfig
+	5,   // 25: routerrpc.MissionControlConfig.model:type_name -> routerrpc.MissionControlConfig.ProbabilityModel
+	37,  // 26: routerrpc.MissionControlConfig.apriori:type_name -> routerrpc.AprioriParameters
+	36,  // 27: routerrpc.MissionControlConfig.bimodal:type_name -> routerrpc.BimodalParameters
+	30,  // 28: routerrpc.QueryProbabilityResponse.history:type_name -> routerrpc.PairData
+	100, // 29: routerrpc.ProbeResult.failure_reason:type_name -> lnrpc.PaymentFailureReason
+	41,  // 30: routerrpc.ProbeHistoryResponse.results:type_name -> routerrpc.ProbeResult
+	92,  // 31: routerrpc.BuildRouteRequest.first_hop_custom_records:type_name -> routerrpc.BuildRouteRequest.FirstHopCustomRecordsEntry
+	101, // 32: routerrpc.BuildRouteResponse.route:type_name -> lnrpc.Route
+	101, // 33: routerrpc.RebalanceResponse.route:type_name -> lnrpc.Route
+	46,  // 34: routerrpc.RebalanceResponse.channels:type_name -> routerrpc.RebalanceChannel
+	6,   // 35: routerrpc.SubscribeAllRequest.types:type_name -> routerrpc.SubscribeAllEvent.EventType
+	103, // 36: routerrpc.SubscribeAllRequest.channel_points:type_name -> lnrpc.ChannelPoint
+	6,   // 37: routerrpc.SubscribeAllEvent.type:type_name -> routerrpc.SubscribeAllEvent.EventType
+	104, // 38: routerrpc.SubscribeAllEvent.channel_event:type_name -> lnrpc.ChannelEventUpdate
+	105, // 39: routerrpc.SubscribeAllEvent.peer_event:type_name -> lnrpc.PeerEvent
+	53,  // 40: routerrpc.SubscribeAllEvent.htlc_event:type_name -> routerrpc.HtlcEvent
+	106, // 41: routerrpc.SubscribeAllEvent.invoice:type_name -> lnrpc.Invoice
+	99,  // 42: routerrpc.SubscribeAllEvent.payment:type_name -> lnrpc.Payment
+	107, // 43: routerrpc.SubscribeAllEvent.block:type_name -> chainrpc.BlockEpoch
+	62,  // 44: routerrpc.StuckHtlc.incoming_circuit_key:type_name -> routerrpc.CircuitKey
+	62,  // 45: routerrpc.StuckHtlc.outgoing_circuit_key:type_name -> routerrpc.CircuitKey
+	7,   // 46: routerrpc.HtlcEvent.event_type:type_name -> routerrpc.HtlcEvent.EventType
+	55,  // 47: routerrpc.HtlcEvent.forward_event:type_name -> routerrpc.ForwardEvent
+	56,  // 48: routerrpc.HtlcEvent.forward_fail_event:type_name -> routerrpc.ForwardFailEvent
+	57,  // 49: routerrpc.HtlcEvent.settle_event:type_name -> routerrpc.SettleEvent
+	60,  // 50: routerrpc.HtlcEvent.link_fail_event:type_name -> routerrpc.LinkFailEvent
+	59,  // 51: routerrpc.HtlcEvent.subscribed_event:type_name -> routerrpc.SubscribedEvent
+	58,  // 52: routerrpc.HtlcEvent.final_htlc_event:type_name -> routerrpc.FinalHtlcEvent
+	62,  // 53: routerrpc.HtlcEvent.correlation_id:type_name -> routerrpc.CircuitKey
+	54,  // 54: routerrpc.ForwardEvent.info:type_name -> routerrpc.HtlcInfo
+	54,  // 55: routerrpc.ForwardFailEvent.info:type_name -> routerrpc.HtlcInfo
+	54,  // 56: routerrpc.SettleEvent.info:type_name -> routerrpc.HtlcInfo
+	54,  // 57: routerrpc.LinkFailEvent.info:type_name -> routerrpc.HtlcInfo
+	108, // 58: routerrpc.LinkFailEvent.wire_failure:type_name -> lnrpc.Failure.FailureCode
+	0,   // 59: routerrpc.LinkFailEvent.failure_detail:type_name -> routerrpc.FailureDetail
+	1,   // 60: routerrpc.PaymentStatus.state:type_name -> routerrpc.PaymentState
+	109, // 61: routerrpc.PaymentStatus.htlcs:type_name -> lnrpc.HTLCAttempt
+	62,  // 62: routerrpc.ForwardHtlcInterceptRequest.incoming_circuit_key:type_name -> routerrpc.CircuitKey
+	93,  // 63: routerrpc.ForwardHtlcInterceptRequest.custom_records:type_name -> routerrpc.ForwardHtlcInterceptRequest.CustomRecordsEntry
+	94,  // 64: routerrpc.ForwardHtlcInterceptRequest.in_wire_custom_records:type_name -> routerrpc.ForwardHtlcInterceptRequest.InWireCustomRecordsEntry
+	62,  // 65: routerrpc.ForwardHtlcInterceptResponse.incoming_circuit_key:type_name -> routerrpc.CircuitKey
+	2,   // 66: routerrpc.ForwardHtlcInterceptResponse.action:type_name -> routerrpc.ResolveHoldForwardAction
+	108, // 67: routerrpc.ForwardHtlcInterceptResponse.failure_code:type_name -> lnrpc.Failure.FailureCode
+	95,  // 68: routerrpc.ForwardHtlcInterceptResponse.out_wire_custom_records:type_name -> routerrpc.ForwardHtlcInterceptResponse.OutWireCustomRecordsEntry
+	64,  // 69: routerrpc.ForwardHtlcInterceptBatch.resolutions:type_name -> routerrpc.ForwardHtlcInterceptResponse
+	62,  // 70: routerrpc.ForwardHtlcInterceptAck.incoming_circuit_key:type_name -> routerrpc.CircuitKey
+	63,  // 71: routerrpc.HtlcInterceptorUpdate.htlc:type_name -> routerrpc.ForwardHtlcInterceptRequest
+	66,  // 72: routerrpc.HtlcInterceptorUpdate.ack:type_name -> routerrpc.ForwardHtlcInterceptAck
+	103, // 73: routerrpc.UpdateChanStatusRequest.chan_point:type_name -> lnrpc.ChannelPoint
+	3,   // 74: routerrpc.UpdateChanStatusRequest.action:type_name -> routerrpc.ChanStatusAction
+	72,  // 75: routerrpc.DustExposureResponse.channels:type_name -> routerrpc.ChannelDustExposure
+	82,  // 76: routerrpc.ChannelReputationResponse.channels:type_name -> routerrpc.IncomingChannelReputation
+	103, // 77: routerrpc.ForwardingPackagesRequest.chan_point:type_name -> lnrpc.ChannelPoint
+	79,  // 78: routerrpc.ForwardingPackagesResponse.packages:type_name -> routerrpc.ForwardingPackage
+	4,   // 79: routerrpc.ForwardingPackage.state:type_name -> routerrpc.ForwardingPackageState
+	103, // 80: routerrpc.ReprocessForwardingPackageRequest.chan_point:type_name -> lnrpc.ChannelPoint
+	110, // 81: routerrpc.AddAliasesRequest.alias_maps:type_name -> lnrpc.AliasMap
+	110, // 82: routerrpc.AddAliasesResponse.alias_maps:type_name -> lnrpc.AliasMap
+	110, // 83: routerrpc.DeleteAliasesRequest.alias_maps:type_name -> lnrpc.AliasMap
+	110, // 84: routerrpc.DeleteAliasesResponse.alias_maps:type_name -> lnrpc.AliasMap
+	8,   // 85: routerrpc.Router.SendPaymentV2:input_type -> routerrpc.SendPaymentRequest
+	12,  // 86: routerrpc.Router.TrackPaymentV2:input_type -> routerrpc.TrackPaymentRequest
+	13,  // 87: routerrpc.Router.TrackPayments:input_type -> routerrpc.TrackPaymentsRequest
+	14,  // 88: routerrpc.Router.SendPayments:input_type -> routerrpc.SendPaymentsRequest
+	19,  // 89: routerrpc.Router.EstimateRouteFee:input_type -> routerrpc.RouteFeeRequest
+	21,  // 90: routerrpc.Router.SendToRoute:input_type -> routerrpc.SendToRouteRequest
+	21,  // 91: routerrpc.Router.SendToRouteV2:input_type -> routerrpc.SendToRouteRequest
+	23,  // 92: routerrpc.Router.ResetMissionControl:input_type -> routerrpc.ResetMissionControlRequest
+	25,  // 93: routerrpc.Router.QueryMissionControl:input_type -> routerrpc.QueryMissionControlRequest
+	27,  // 94: routerrpc.Router.XImportMissionControl:input_type -> routerrpc.XImportMissionControlRequest
+	31,  // 95: routerrpc.Router.GetMissionControlConfig:input_type -> routerrpc.GetMissionControlConfigRequest
+	33,  // 96: routerrpc.Router.SetMissionControlConfig:input_type -> routerrpc.SetMissionControlConfigRequest
+	38,  // 97: routerrpc.Router.QueryProbability:input_type -> routerrpc.QueryProbabilityRequest
+	40,  // 98: routerrpc.Router.ProbeHistory:input_type -> routerrpc.ProbeHistoryRequest
+	43,  // 99: routerrpc.Router.BuildRoute:input_type -> routerrpc.BuildRouteRequest
+	45,  // 100: routerrpc.Router.Rebalance:input_type -> routerrpc.RebalanceRequest
+	52,  // 101: routerrpc.Router.SubscribeHtlcEvents:input_type -> routerrpc.SubscribeHtlcEventsRequest
+	48,  // 102: routerrpc.Router.SubscribeAll:input_type -> routerrpc.SubscribeAllRequest
+	50,  // 103: routerrpc.Router.SubscribeStuckHtlcs:input_type -> routerrpc.SubscribeStuckHtlcsRequest
+	8,   // 104: routerrpc.Router.SendPayment:input_type -> routerrpc.SendPaymentRequest
+	12,  // 105: routerrpc.Router.TrackPayment:input_type -> routerrpc.TrackPaymentRequest
+	64,  // 106: routerrpc.Router.HtlcInterceptor:input_type -> routerrpc.ForwardHtlcInterceptResponse
+	65,  // 107: routerrpc.Router.HtlcInterceptorBatched:input_type -> routerrpc.ForwardHtlcInterceptBatch
+	68,  // 108: routerrpc.Router.UpdateChanStatus:input_type -> routerrpc.UpdateChanStatusRequest
+	70,  // 109: routerrpc.Router.DustExposure:input_type -> routerrpc.DustExposureRequest
+	73,  // 110: routerrpc.Router.UpdateMaxFeeExposure:input_type -> routerrpc.UpdateMaxFeeExposureRequest
+	75,  // 111: routerrpc.Router.ChannelReputation:input_type -> routerrpc.ChannelReputationRequest
+	77,  // 112: routerrpc.Router.ForwardingPackages:input_type -> routerrpc.ForwardingPackagesRequest
+	80,  // 113: routerrpc.Router.ReprocessForwardingPackage:input_type -> routerrpc.ReprocessForwardingPackageRequest
+	83,  // 114: routerrpc.Router.XAddLocalChanAliases:input_type -> routerrpc.AddAliasesRequest
+	85,  // 115: routerrpc.Router.XDeleteLocalChanAliases:input_type -> routerrpc.DeleteAliasesRequest
+	99,  // 116: routerrpc.Router.SendPaymentV2:output_type -> lnrpc.Payment
+	99,  // 117: routerrpc.Router.TrackPaymentV2:output_type -> lnrpc.Payment
+	99,  // 118: routerrpc.Router.TrackPayments:output_type -> lnrpc.Payment
+	15,  // 119: routerrpc.Router.SendPayments:output_type -> routerrpc.SendPaymentsResponse
+	20,  // 120: routerrpc.Router.EstimateRouteFee:output_type -> routerrpc.RouteFeeResponse
+	22,  // 121: routerrpc.Router.SendToRoute:output_type -> routerrpc.SendToRouteResponse
+	109, // 122: routerrpc.Router.SendToRouteV2:output_type -> lnrpc.HTLCAttempt
+	24,  // 123: routerrpc.Router.ResetMissionControl:output_type -> routerrpc.ResetMissionControlResponse
+	26,  // 124: routerrpc.Router.QueryMissionControl:output_type -> routerrpc.QueryMissionControlResponse
+	28,  // 125: routerrpc.Router.XImportMissionControl:output_type -> routerrpc.XImportMissionControlResponse
+	32,  // 126: routerrpc.Router.GetMissionControlConfig:output_type -> routerrpc.GetMissionControlConfigResponse
+	34,  // 127: routerrpc.Router.SetMissionControlConfig:output_type -> routerrpc.SetMissionControlConfigResponse
+	39,  // 128: routerrpc.Router.QueryProbability:output_type -> routerrpc.QueryProbabilityResponse
+	42,  // 129: routerrpc.Router.ProbeHistory:output_type -> routerrpc.ProbeHistoryResponse
+	44,  // 130: routerrpc.Router.BuildRoute:output_type -> routerrpc.BuildRouteResponse
+	47,  // 131: routerrpc.Router.Rebalance:output_type -> routerrpc.RebalanceResponse
+	53,  // 132: routerrpc.Router.SubscribeHtlcEvents:output_type -> routerrpc.HtlcEvent
+	49,  // 133: routerrpc.Router.SubscribeAll:output_type -> routerrpc.SubscribeAllEvent
+	51,  // 134: routerrpc.Router.SubscribeStuckHtlcs:output_type -> routerrpc.StuckHtlc
+	61,  // 135: routerrpc.Router.SendPayment:output_type -> routerrpc.PaymentStatus
+	61,  // 136: routerrpc.Router.TrackPayment:output_type -> routerrpc.PaymentStatus
+	63,  // 137: routerrpc.Router.HtlcInterceptor:output_type -> routerrpc.ForwardHtlcInterceptRequest
+	67,  // 138: routerrpc.Router.HtlcInterceptorBatched:output_type -> routerrpc.HtlcInterceptorUpdate
+	69,  // 139: routerrpc.Router.UpdateChanStatus:output_type -> routerrpc.UpdateChanStatusResponse
+	71,  // 140: routerrpc.Router.DustExposure:output_type -> routerrpc.DustExposureResponse
+	74,  // 141: routerrpc.Router.UpdateMaxFeeExposure:output_type -> routerrpc.UpdateMaxFeeExposureResponse
+	76,  // 142: routerrpc.Router.ChannelReputation:output_type -> routerrpc.ChannelReputationResponse
+	78,  // 143: routerrpc.Router.ForwardingPackages:output_type -> routerrpc.ForwardingPackagesResponse
+	81,  // 144: routerrpc.Router.ReprocessForwardingPackage:output_type -> routerrpc.ReprocessForwardingPackageResponse
+	84,  // 145: routerrpc.Router.XAddLocalChanAliases:output_type -> routerrpc.AddAliasesResponse
+	86,  // 146: routerrpc.Router.XDeleteLocalChanAliases:output_type -> routerrpc.DeleteAliasesResponse
+	116, // [116:147] is the sub-list for method output_type
+	85,  // [85:116] is the sub-list for method input_type
+	85,  // [85:85] is the sub-list for extension type_name
+	85,  // [85:85] is the sub-list for extension extendee
+	0,   // [0:85] is the sub-list for field type_name
+}
+
+func init() { file_routerrpc_router_proto_init() }
+func file_routerrpc_router_proto_init() {
+	if File_routerrpc_router_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_routerrpc_router_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendPaymentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrampolineOptions); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HopCustomRecords); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PaymentBudget); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackPaymentRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TrackPaymentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendPaymentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendPaymentsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchPaymentStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchFailureReasonCount); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchPaymentOutcome); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouteFeeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RouteFeeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendToRouteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SendToRouteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResetMissionControlRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ResetMissionControlResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryMissionControlRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryMissionControlResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*XImportMissionControlRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*XImportMissionControlResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PairHistory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PairData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMissionControlConfigRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetMissionControlConfigResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetMissionControlConfigRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetMissionControlConfigResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MissionControlConfig); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BimodalParameters); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AprioriParameters); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryProbabilityRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*QueryProbabilityResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeHistoryRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4496,8 +7938,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TrackPaymentRequest); i {
+		file_routerrpc_router_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4508,8 +7950,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*TrackPaymentsRequest); i {
+		file_routerrpc_router_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProbeHistoryResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4520,8 +7962,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RouteFeeRequest); i {
+		file_routerrpc_router_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildRouteRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4532,8 +7974,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*RouteFeeResponse); i {
+		file_routerrpc_router_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BuildRouteResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4544,8 +7986,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendToRouteRequest); i {
+		file_routerrpc_router_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RebalanceRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4556,8 +7998,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SendToRouteResponse); i {
+		file_routerrpc_router_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RebalanceChannel); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4568,8 +8010,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResetMissionControlRequest); i {
+		file_routerrpc_router_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RebalanceResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4580,8 +8022,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ResetMissionControlResponse); i {
+		file_routerrpc_router_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeAllRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4592,8 +8034,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryMissionControlRequest); i {
+		file_routerrpc_router_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeAllEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4604,8 +8046,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryMissionControlResponse); i {
+		file_routerrpc_router_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeStuckHtlcsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4616,8 +8058,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*XImportMissionControlRequest); i {
+		file_routerrpc_router_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StuckHtlc); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4628,8 +8070,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*XImportMissionControlResponse); i {
+		file_routerrpc_router_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeHtlcEventsRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4640,8 +8082,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PairHistory); i {
+		file_routerrpc_router_proto_msgTypes[45].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HtlcEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4652,8 +8094,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PairData); i {
+		file_routerrpc_router_proto_msgTypes[46].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HtlcInfo); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4664,8 +8106,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetMissionControlConfigRequest); i {
+		file_routerrpc_router_proto_msgTypes[47].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4676,8 +8118,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GetMissionControlConfigResponse); i {
+		file_routerrpc_router_proto_msgTypes[48].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardFailEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4688,8 +8130,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetMissionControlConfigRequest); i {
+		file_routerrpc_router_proto_msgTypes[49].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SettleEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4700,8 +8142,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SetMissionControlConfigResponse); i {
+		file_routerrpc_router_proto_msgTypes[50].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FinalHtlcEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4712,8 +8154,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*MissionControlConfig); i {
+		file_routerrpc_router_proto_msgTypes[51].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribedEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4724,8 +8166,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BimodalParameters); i {
+		file_routerrpc_router_proto_msgTypes[52].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*LinkFailEvent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4736,8 +8178,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AprioriParameters); i {
+		file_routerrpc_router_proto_msgTypes[53].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PaymentStatus); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4748,8 +8190,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryProbabilityRequest); i {
+		file_routerrpc_router_proto_msgTypes[54].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CircuitKey); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4760,8 +8202,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*QueryProbabilityResponse); i {
+		file_routerrpc_router_proto_msgTypes[55].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardHtlcInterceptRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4772,8 +8214,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BuildRouteRequest); i {
+		file_routerrpc_router_proto_msgTypes[56].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardHtlcInterceptResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4784,8 +8226,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*BuildRouteResponse); i {
+		file_routerrpc_router_proto_msgTypes[57].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardHtlcInterceptBatch); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4796,8 +8238,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubscribeHtlcEventsRequest); i {
+		file_routerrpc_router_proto_msgTypes[58].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardHtlcInterceptAck); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4808,8 +8250,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HtlcEvent); i {
+		file_routerrpc_router_proto_msgTypes[59].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HtlcInterceptorUpdate); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4820,8 +8262,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HtlcInfo); i {
+		file_routerrpc_router_proto_msgTypes[60].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateChanStatusRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4832,8 +8274,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForwardEvent); i {
+		file_routerrpc_router_proto_msgTypes[61].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateChanStatusResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4844,8 +8286,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForwardFailEvent); i {
+		file_routerrpc_router_proto_msgTypes[62].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DustExposureRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4856,8 +8298,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SettleEvent); i {
+		file_routerrpc_router_proto_msgTypes[63].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DustExposureResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4868,8 +8310,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FinalHtlcEvent); i {
+		file_routerrpc_router_proto_msgTypes[64].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelDustExposure); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4880,8 +8322,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SubscribedEvent); i {
+		file_routerrpc_router_proto_msgTypes[65].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateMaxFeeExposureRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4892,8 +8334,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*LinkFailEvent); i {
+		file_routerrpc_router_proto_msgTypes[66].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateMaxFeeExposureResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4904,8 +8346,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PaymentStatus); i {
+		file_routerrpc_router_proto_msgTypes[67].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelReputationRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4916,8 +8358,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*CircuitKey); i {
+		file_routerrpc_router_proto_msgTypes[68].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChannelReputationResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4928,8 +8370,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForwardHtlcInterceptRequest); i {
+		file_routerrpc_router_proto_msgTypes[69].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardingPackagesRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4940,8 +8382,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ForwardHtlcInterceptResponse); i {
+		file_routerrpc_router_proto_msgTypes[70].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardingPackagesResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4952,8 +8394,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateChanStatusRequest); i {
+		file_routerrpc_router_proto_msgTypes[71].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ForwardingPackage); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4964,8 +8406,8 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateChanStatusResponse); i {
+		file_routerrpc_router_proto_msgTypes[72].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReprocessForwardingPackageRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -4976,7 +8418,31 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[41].Exporter = func(v interface{}, i int) interface{} {
+		file_routerrpc_router_proto_msgTypes[73].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReprocessForwardingPackageResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[74].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IncomingChannelReputation); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_routerrpc_router_proto_msgTypes[75].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddAliasesRequest); i {
 			case 0:
 				return &v.state
@@ -4988,7 +8454,7 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[42].Exporter = func(v interface{}, i int) interface{} {
+		file_routerrpc_router_proto_msgTypes[76].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AddAliasesResponse); i {
 			case 0:
 				return &v.state
@@ -5000,7 +8466,7 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[43].Exporter = func(v interface{}, i int) interface{} {
+		file_routerrpc_router_proto_msgTypes[77].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteAliasesRequest); i {
 			case 0:
 				return &v.state
@@ -5012,7 +8478,7 @@ func file_routerrpc_router_proto_init() {
 				return nil
 			}
 		}
-		file_routerrpc_router_proto_msgTypes[44].Exporter = func(v interface{}, i int) interface{} {
+		file_routerrpc_router_proto_msgTypes[78].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*DeleteAliasesResponse); i {
 			case 0:
 				return &v.state
@@ -5025,11 +8491,23 @@ func file_routerrpc_router_proto_init() {
 			}
 		}
 	}
-	file_routerrpc_router_proto_msgTypes[19].OneofWrappers = []interface{}{
+	file_routerrpc_router_proto_msgTypes[7].OneofWrappers = []interface{}{
+		(*SendPaymentsResponse_PaymentStatus)(nil),
+		(*SendPaymentsResponse_Summary)(nil),
+	}
+	file_routerrpc_router_proto_msgTypes[27].OneofWrappers = []interface{}{
 		(*MissionControlConfig_Apriori)(nil),
 		(*MissionControlConfig_Bimodal)(nil),
 	}
-	file_routerrpc_router_proto_msgTypes[27].OneofWrappers = []interface{}{
+	file_routerrpc_router_proto_msgTypes[41].OneofWrappers = []interface{}{
+		(*SubscribeAllEvent_ChannelEvent)(nil),
+		(*SubscribeAllEvent_PeerEvent)(nil),
+		(*SubscribeAllEvent_HtlcEvent)(nil),
+		(*SubscribeAllEvent_Invoice)(nil),
+		(*SubscribeAllEvent_Payment)(nil),
+		(*SubscribeAllEvent_Block)(nil),
+	}
+	file_routerrpc_router_proto_msgTypes[45].OneofWrappers = []interface{}{
 		(*HtlcEvent_ForwardEvent)(nil),
 		(*HtlcEvent_ForwardFailEvent)(nil),
 		(*HtlcEvent_SettleEvent)(nil),
@@ -5037,13 +8515,17 @@ func file_routerrpc_router_proto_init() {
 		(*HtlcEvent_SubscribedEvent)(nil),
 		(*HtlcEvent_FinalHtlcEvent)(nil),
 	}
+	file_routerrpc_router_proto_msgTypes[59].OneofWrappers = []interface{}{
+		(*HtlcInterceptorUpdate_Htlc)(nil),
+		(*HtlcInterceptorUpdate_Ack)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_routerrpc_router_proto_rawDesc,
-			NumEnums:      6,
-			NumMessages:   52,
+			NumEnums:      8,
+			NumMessages:   88,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
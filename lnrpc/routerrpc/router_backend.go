@@ -62,8 +62,23 @@ type RouterBackend struct {
 	// routes.
 	FindRoute func(*routing.RouteRequest) (*route.Route, float64, error)
 
+	// FindDiverseRoutes is a closure that locates up to the given number
+	// of routes, which share at most the given number of edges with each
+	// other.
+	FindDiverseRoutes func(*routing.RouteRequest, int, int) (
+		[]*routing.DiverseRoute, error)
+
+	// EstimatePayment is a closure that simulates a payment without
+	// sending it, including the splitting into multiple shards.
+	EstimatePayment func(*routing.LightningPayment) (
+		*routing.PaymentEstimate, error)
+
 	MissionControl MissionControl
 
+	// ProbeHistory returns the results of the most recent probes of the
+	// background prober. It is nil if probing is disabled.
+	ProbeHistory func() []routing.ProbeResult
+
 	// ActiveNetParams are the network parameters of the primary network
 	// that the route is operating on. This is necessary so we can ensure
 	// that we receive payment requests that send to destinations on our
@@ -78,6 +93,11 @@ type RouterBackend struct {
 	// have.
 	MaxTotalTimelock uint32
 
+	// CltvBudget optionally limits the time lock delta that individual
+	// hops of a payment's routes may charge. It is nil if no limits are
+	// configured.
+	CltvBudget *routing.CltvBudget
+
 	// DefaultFinalCltvDelta is the default value used as final cltv delta
 	// when an RPC caller doesn't specify a value.
 	DefaultFinalCltvDelta uint16
@@ -86,6 +106,49 @@ type RouterBackend struct {
 	// htlc events.
 	SubscribeHtlcEvents func() (*subscribe.Client, error)
 
+	// SubscribeAll multiplexes the channel, peer, htlc, invoice, payment
+	// and block events that match the request into a single stream,
+	// sending each of them with the given function until the context is
+	// canceled.
+	SubscribeAll func(ctx context.Context, req *SubscribeAllRequest,
+		send func(*SubscribeAllEvent) error) error
+
+	// StuckHtlcs returns the htlcs that are currently stuck. It is nil if
+	// the detection of stuck htlcs is disabled.
+	StuckHtlcs func() []htlcswitch.StuckHtlc
+
+	// SubscribeStuckHtlcs returns a subscription client that receives
+	// every htlc that becomes stuck. It is nil if the detection of stuck
+	// htlcs is disabled.
+	SubscribeStuckHtlcs func() (*subscribe.Client, error)
+
+	// DustExposures returns the dust exposure of all channels that have an
+	// active link.
+	DustExposures func() []htlcswitch.DustExposure
+
+	// UpdateMaxFeeExposure updates the threshold after which new dust
+	// htlcs are rejected.
+	UpdateMaxFeeExposure func(lnwire.MilliSatoshi) error
+
+	// ChannelReputation returns the reputation of the given incoming
+	// channel in the experimental htlc endorsement scheme.
+	ChannelReputation func(
+		lnwire.ShortChannelID) htlcswitch.ChannelReputation
+
+	// ChannelReputations returns the reputation of all incoming channels
+	// that forwarded htlcs while experimental endorsement was enabled.
+	ChannelReputations func() []htlcswitch.ChannelReputation
+
+	// ForwardingPackages returns the forwarding packages of the channel
+	// with the given channel id.
+	ForwardingPackages func(lnwire.ChannelID) ([]htlcswitch.FwdPkgInfo,
+		error)
+
+	// ReprocessForwardingPackage replays the forwarding package at the
+	// given height of the channel with the given channel id.
+	ReprocessForwardingPackage func(context.Context, lnwire.ChannelID,
+		uint64) error
+
 	// InterceptableForwarder exposes the ability to intercept forward events
 	// by letting the router register a ForwardInterceptor.
 	InterceptableForwarder htlcswitch.InterceptableHtlcForwarder
@@ -100,6 +163,16 @@ type RouterBackend struct {
 	// management after manually setting channel status.
 	SetChannelAuto func(wire.OutPoint) error
 
+	// ChannelBalance returns the remote node and the local balance of the
+	// open channel with the given channel id.
+	ChannelBalance func(chanID uint64) (route.Vertex, btcutil.Amount,
+		error)
+
+	// AddInvoice adds an invoice over the given amount to the invoice
+	// registry and returns its payment hash and payment address.
+	AddInvoice func(ctx context.Context, memo string,
+		amt lnwire.MilliSatoshi) (lntypes.Hash, [32]byte, error)
+
 	// UseStatusInitiated is a boolean that indicates whether the router
 	// should use the new status code `Payment_INITIATED`.
 	//
@@ -157,6 +230,14 @@ type MissionControl interface {
 func (r *RouterBackend) QueryRoutes(ctx context.Context,
 	in *lnrpc.QueryRoutesRequest) (*lnrpc.QueryRoutesResponse, error) {
 
+	// If more than one route is requested, we'll look for diverse routes
+	// instead.
+	if in.NumRoutes > 1 {
+		return r.QueryDiverseRoutes(
+			ctx, in, int(in.NumRoutes), int(in.MaxSharedEdges),
+		)
+	}
+
 	routeReq, err := r.parseQueryRoutesRequest(in)
 	if err != nil {
 		return nil, err
@@ -185,6 +266,74 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 	return routeResp, nil
 }
 
+// QueryDiverseRoutes queries the daemon's Channel Router for up to numRoutes
+// routes to a target destination, which share at most maxSharedEdges edges
+// with each other. The routes are returned in order of increasing cost, and
+// the success probability of the response is that of the first route. This
+// allows external payment orchestrators to implement their own retry logic.
+func (r *RouterBackend) QueryDiverseRoutes(ctx context.Context,
+	in *lnrpc.QueryRoutesRequest, numRoutes, maxSharedEdges int) (
+	*lnrpc.QueryRoutesResponse, error) {
+
+	routeReq, err := r.parseQueryRoutesRequest(in)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := r.FindDiverseRoutes(routeReq, numRoutes, maxSharedEdges)
+	if err != nil {
+		return nil, err
+	}
+
+	routeResp := &lnrpc.QueryRoutesResponse{
+		Routes:            make([]*lnrpc.Route, 0, len(routes)),
+		RouteSuccessProbs: make([]float64, 0, len(routes)),
+	}
+	for _, diverseRoute := range routes {
+		rpcRoute, err := r.MarshallRoute(diverseRoute.Route)
+		if err != nil {
+			return nil, err
+		}
+
+		routeResp.Routes = append(routeResp.Routes, rpcRoute)
+		routeResp.RouteSuccessProbs = append(
+			routeResp.RouteSuccessProbs, diverseRoute.Probability,
+		)
+	}
+
+	if len(routes) > 0 {
+		routeResp.SuccessProb = routes[0].Probability
+	}
+
+	return routeResp, nil
+}
+
+// EstimatePaymentFee simulates the payment described by the request without
+// sending it. Path finding takes the current mission control state into
+// account and the payment is split into shards if needed, so that the
+// estimate reflects the fees of an actual payment. The estimated success
+// probability of the payment is returned along with the fee estimate.
+func (r *RouterBackend) EstimatePaymentFee(ctx context.Context,
+	in *SendPaymentRequest) (*RouteFeeResponse, error) {
+
+	payment, err := r.extractIntentFromSendRequest(in)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := r.EstimatePayment(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RouteFeeResponse{
+		RoutingFeeMsat: int64(estimate.TotalFees),
+		TimeLockDelay:  int64(estimate.TotalTimeLock),
+		FailureReason:  lnrpc.PaymentFailureReason_FAILURE_REASON_NONE,
+		SuccessProb:    estimate.Probability,
+	}, nil
+}
+
 func parsePubKey(key string) (route.Vertex, error) {
 	pubKeyBytes, err := hex.DecodeString(key)
 	if err != nil {
@@ -368,6 +517,11 @@ func (r *RouterBackend) parseQueryRoutesRequest(in *lnrpc.QueryRoutesRequest) (
 		return nil, err
 	}
 
+	cltvBudget, err := r.unmarshalCltvBudget(in.CltvBudget)
+	if err != nil {
+		return nil, err
+	}
+
 	restrictions := &routing.RestrictParams{
 		FeeLimit: feeLimit,
 		ProbabilitySource: func(fromNode, toNode route.Vertex,
@@ -396,6 +550,7 @@ func (r *RouterBackend) parseQueryRoutesRequest(in *lnrpc.QueryRoutesRequest) (
 		},
 		DestCustomRecords:     record.CustomSet(in.DestCustomRecords),
 		CltvLimit:             cltvLimit,
+		CltvBudget:            cltvBudget,
 		DestFeatures:          destinationFeatures,
 		BlindedPaymentPathSet: blindedPathSet,
 	}
@@ -849,6 +1004,14 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	}
 	payIntent.CltvLimit = cltvLimit
 
+	// Limit the time lock delta of individual hops to the budget of the
+	// request, falling back to the configured budget.
+	cltvBudget, err := r.unmarshalCltvBudget(rpcPayReq.CltvBudget)
+	if err != nil {
+		return nil, err
+	}
+	payIntent.CltvBudget = cltvBudget
+
 	// Attempt to parse the max parts value set by the user, if this value
 	// isn't set, then we'll use the current default value for this
 	// setting.
@@ -891,9 +1054,31 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	}
 	payIntent.FirstHopCustomRecords = firstHopRecords
 
+	hopRecords, err := unmarshalHopCustomRecords(
+		rpcPayReq.HopCustomRecords,
+	)
+	if err != nil {
+		return nil, err
+	}
+	payIntent.HopCustomRecords = hopRecords
+
 	payIntent.PayAttemptTimeout = time.Second *
 		time.Duration(rpcPayReq.TimeoutSeconds)
 
+	// Pass along the optional trampoline node the payment is delegated to.
+	trampoline, err := unmarshalTrampolineOptions(rpcPayReq.Trampoline)
+	if err != nil {
+		return nil, err
+	}
+	payIntent.Trampoline = trampoline
+
+	// Pass along the optional payment budget.
+	budget, err := unmarshalPaymentBudget(rpcPayReq.Budget)
+	if err != nil {
+		return nil, err
+	}
+	payIntent.Budget = budget
+
 	// Route hints.
 	routeHints, err := unmarshallRouteHints(
 		rpcPayReq.RouteHints,
@@ -1192,6 +1377,141 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 	return payIntent, nil
 }
 
+// unmarshalHopCustomRecords parses the custom records that are addressed to
+// intermediate hops of a payment. Whether the hops are allowed to receive
+// records is checked by the router, as it requires the final destination.
+func unmarshalHopCustomRecords(rpcRecords []*HopCustomRecords) (
+	map[route.Vertex]record.CustomSet, error) {
+
+	if len(rpcRecords) == 0 {
+		return nil, nil
+	}
+
+	hopRecords := make(
+		map[route.Vertex]record.CustomSet, len(rpcRecords),
+	)
+	for _, rpcHop := range rpcRecords {
+		node, err := route.NewVertexFromBytes(rpcHop.PubKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w",
+				routing.ErrInvalidHopCustomRecords, err)
+		}
+
+		if _, ok := hopRecords[node]; ok {
+			return nil, fmt.Errorf("%w: duplicate records for "+
+				"hop %v", routing.ErrInvalidHopCustomRecords,
+				node)
+		}
+
+		records := record.CustomSet(rpcHop.CustomRecords)
+		if err := records.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: hop %v: %w",
+				routing.ErrInvalidHopCustomRecords, node, err)
+		}
+
+		hopRecords[node] = records
+	}
+
+	return hopRecords, nil
+}
+
+// unmarshalTrampolineOptions parses the optional trampoline options of a send
+// request.
+func unmarshalTrampolineOptions(
+	rpcOpts *TrampolineOptions) (*routing.TrampolineOptions, error) {
+
+	if rpcOpts == nil {
+		return nil, nil
+	}
+
+	node, err := route.NewVertexFromBytes(rpcOpts.Node)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trampoline node: %w", err)
+	}
+
+	if rpcOpts.CltvDelta > math.MaxUint16 {
+		return nil, fmt.Errorf("trampoline cltv_delta %v exceeds %v",
+			rpcOpts.CltvDelta, math.MaxUint16)
+	}
+
+	return &routing.TrampolineOptions{
+		Node:      node,
+		Fee:       lnwire.MilliSatoshi(rpcOpts.FeeMsat),
+		CltvDelta: uint16(rpcOpts.CltvDelta),
+	}, nil
+}
+
+// unmarshalPaymentBudget parses the optional payment budget of a send request.
+// A nil budget is returned if no budget or only an empty one was specified.
+func unmarshalPaymentBudget(
+	rpcBudget *PaymentBudget) (*routing.PaymentBudget, error) {
+
+	if rpcBudget == nil {
+		return nil, nil
+	}
+
+	switch {
+	case rpcBudget.MaxFeePercent < 0 || rpcBudget.MaxFeePercent > 100:
+		return nil, errors.New("budget max_fee_percent must be " +
+			"between 0 and 100")
+
+	case rpcBudget.Deadline < 0:
+		return nil, errors.New("budget deadline must not be negative")
+	}
+
+	budget := &routing.PaymentBudget{
+		MaxFeePercent: rpcBudget.MaxFeePercent,
+		MaxAttempts:   rpcBudget.MaxAttempts,
+		AttemptTimeout: time.Second *
+			time.Duration(rpcBudget.AttemptTimeoutSeconds),
+	}
+	if rpcBudget.Deadline != 0 {
+		budget.Deadline = time.Unix(rpcBudget.Deadline, 0)
+	}
+
+	if *budget == (routing.PaymentBudget{}) {
+		return nil, nil
+	}
+
+	return budget, nil
+}
+
+// unmarshalCltvBudget parses the optional CLTV budget of a payment or route
+// query. The limits that aren't set in the request fall back to the configured
+// ones. A nil budget is returned if no limit applies.
+func (r *RouterBackend) unmarshalCltvBudget(
+	rpcBudget *lnrpc.CltvBudget) (*routing.CltvBudget, error) {
+
+	var budget routing.CltvBudget
+	if r.CltvBudget != nil {
+		budget = *r.CltvBudget
+	}
+
+	if rpcBudget != nil {
+		if rpcBudget.MaxHopCltvDelta > math.MaxUint16 ||
+			rpcBudget.MaxLastHopCltvDelta > math.MaxUint16 {
+
+			return nil, fmt.Errorf("cltv budget limits must not "+
+				"exceed %d", math.MaxUint16)
+		}
+
+		if rpcBudget.MaxHopCltvDelta != 0 {
+			budget.MaxHopDelta = uint16(rpcBudget.MaxHopCltvDelta)
+		}
+		if rpcBudget.MaxLastHopCltvDelta != 0 {
+			budget.MaxLastHopDelta = uint16(
+				rpcBudget.MaxLastHopCltvDelta,
+			)
+		}
+	}
+
+	if budget == (routing.CltvBudget{}) {
+		return nil, nil
+	}
+
+	return &budget, nil
+}
+
 // BuildBlindedPathSet marshals a set of zpay32.BlindedPaymentPath and uses
 // the result to build a new routing.BlindedPaymentPathSet.
 func BuildBlindedPathSet(paths []*zpay32.BlindedPaymentPath) (
@@ -1453,6 +1773,12 @@ func marshallHtlcFailure(failure *channeldb.HTLCFailInfo) (*lnrpc.Failure,
 		FailureSourceIndex: failure.FailureSourceIndex,
 	}
 
+	for _, holdTime := range failure.HoldTimes {
+		rpcFailure.HoldTimesMs = append(
+			rpcFailure.HoldTimesMs, uint64(holdTime.Milliseconds()),
+		)
+	}
+
 	switch failure.Reason {
 	case channeldb.HTLCFailUnknown:
 		rpcFailure.Code = lnrpc.Failure_UNKNOWN_FAILURE
@@ -4,14 +4,20 @@ import (
 	"bytes"
 	"context"
 	"encoding/hex"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -241,6 +247,81 @@ func testQueryRoutes(t *testing.T, useMissionControl bool, useMsat bool,
 	}
 }
 
+// TestQueryDiverseRoutes asserts that QueryRoutes looks for diverse routes if
+// more than one route is requested, and returns the probability of each.
+func TestQueryDiverseRoutes(t *testing.T) {
+	t.Parallel()
+
+	destNodeBytes, err := hex.DecodeString(destKey)
+	require.NoError(t, err)
+
+	var dest route.Vertex
+	copy(dest[:], destNodeBytes)
+
+	findDiverseRoutes := func(req *routing.RouteRequest, numRoutes,
+		maxSharedEdges int) ([]*routing.DiverseRoute, error) {
+
+		require.Equal(t, 3, numRoutes)
+		require.Equal(t, 1, maxSharedEdges)
+		require.Equal(t, dest, req.Target)
+
+		var routes []*routing.DiverseRoute
+		for i, prob := range []float64{0.9, 0.6} {
+			hops := []*route.Hop{{
+				PubKeyBytes:      dest,
+				ChannelID:        uint64(i + 1),
+				AmtToForward:     req.Amount,
+				OutgoingTimeLock: 144,
+			}}
+			rt, err := route.NewRouteFromHops(
+				req.Amount, 144, req.Source, hops,
+			)
+			require.NoError(t, err)
+
+			routes = append(routes, &routing.DiverseRoute{
+				Route:       rt,
+				Probability: prob,
+			})
+		}
+
+		return routes, nil
+	}
+
+	backend := &RouterBackend{
+		FindRoute: func(*routing.RouteRequest) (*route.Route,
+			float64, error) {
+
+			t.Fatal("expected diverse routes to be searched")
+
+			return nil, 0, nil
+		},
+		FindDiverseRoutes: findDiverseRoutes,
+		SelfNode:          route.Vertex{1, 2, 3},
+		FetchChannelCapacity: func(chanID uint64) (
+			btcutil.Amount, error) {
+
+			return 1, nil
+		},
+		MissionControl:   &mockMissionControl{},
+		MaxTotalTimelock: 1000,
+	}
+
+	resp, err := backend.QueryRoutes(
+		context.Background(), &lnrpc.QueryRoutesRequest{
+			PubKey:         destKey,
+			Amt:            100000,
+			FinalCltvDelta: 100,
+			NumRoutes:      3,
+			MaxSharedEdges: 1,
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Routes, 2)
+	require.Equal(t, []float64{0.9, 0.6}, resp.RouteSuccessProbs)
+	require.Equal(t, 0.9, resp.SuccessProb)
+	require.EqualValues(t, 2, resp.Routes[1].Hops[0].ChanId)
+}
+
 type mockMissionControl struct {
 	MissionControl
 }
@@ -475,3 +556,384 @@ func testUnmarshalAMP(t *testing.T, test unmarshalAMPTest) {
 		t.Fatalf("test case has non-standard outcome")
 	}
 }
+
+// TestMarshallHtlcFailureHoldTimes tests that the hold times of a failed htlc
+// attempt are reported in milliseconds.
+func TestMarshallHtlcFailureHoldTimes(t *testing.T) {
+	t.Parallel()
+
+	rpcFailure, err := marshallHtlcFailure(&channeldb.HTLCFailInfo{
+		Reason:             channeldb.HTLCFailUnreadable,
+		FailureSourceIndex: 2,
+		HoldTimes: []time.Duration{
+			100 * time.Millisecond, 2 * time.Second,
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, lnrpc.Failure_UNREADABLE_FAILURE, rpcFailure.Code)
+	require.EqualValues(t, 2, rpcFailure.FailureSourceIndex)
+	require.Equal(t, []uint64{100, 2000}, rpcFailure.HoldTimesMs)
+}
+
+// newTestSendRequest returns a minimal send request for a keysend-style
+// payment to destKey that passes the validation of the router backend.
+func newTestSendRequest(t *testing.T) *SendPaymentRequest {
+	dest, err := hex.DecodeString(destKey)
+	require.NoError(t, err)
+
+	return &SendPaymentRequest{
+		Dest:           dest,
+		Amt:            100_000,
+		PaymentHash:    bytes.Repeat([]byte{1}, 32),
+		TimeoutSeconds: 60,
+	}
+}
+
+// TestExtractIntentBudget asserts that the payment budget of a send request
+// is validated and passed on to the payment.
+func TestExtractIntentBudget(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name   string
+		budget *PaymentBudget
+		expect *routing.PaymentBudget
+		err    string
+	}{
+		{
+			name: "no budget",
+		},
+		{
+			name:   "empty budget",
+			budget: &PaymentBudget{},
+		},
+		{
+			name: "full budget",
+			budget: &PaymentBudget{
+				MaxFeePercent:         0.5,
+				Deadline:              deadline.Unix(),
+				MaxAttempts:           10,
+				AttemptTimeoutSeconds: 30,
+			},
+			expect: &routing.PaymentBudget{
+				MaxFeePercent:  0.5,
+				Deadline:       deadline,
+				MaxAttempts:    10,
+				AttemptTimeout: 30 * time.Second,
+			},
+		},
+		{
+			name: "attempts only",
+			budget: &PaymentBudget{
+				MaxAttempts: 3,
+			},
+			expect: &routing.PaymentBudget{
+				MaxAttempts: 3,
+			},
+		},
+		{
+			name: "fee percent out of range",
+			budget: &PaymentBudget{
+				MaxFeePercent: 101,
+			},
+			err: "max_fee_percent",
+		},
+		{
+			name: "negative deadline",
+			budget: &PaymentBudget{
+				Deadline: -1,
+			},
+			err: "deadline",
+		},
+	}
+
+	backend := &RouterBackend{
+		MaxTotalTimelock: 1000,
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := newTestSendRequest(t)
+			req.Budget = test.budget
+
+			payment, err := backend.extractIntentFromSendRequest(req)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expect, payment.Budget)
+		})
+	}
+}
+
+// TestCltvBudget asserts that the CLTV budget of a send request and a route
+// query is passed on to path finding, and that the limits the request doesn't
+// set fall back to the configured ones.
+func TestCltvBudget(t *testing.T) {
+	t.Parallel()
+
+	configured := &routing.CltvBudget{
+		MaxHopDelta:     144,
+		MaxLastHopDelta: 288,
+	}
+
+	tests := []struct {
+		name       string
+		configured *routing.CltvBudget
+		budget     *lnrpc.CltvBudget
+		expect     *routing.CltvBudget
+		err        string
+	}{
+		{
+			name: "no budget",
+		},
+		{
+			name:       "configured budget only",
+			configured: configured,
+			expect:     configured,
+		},
+		{
+			name: "request budget only",
+			budget: &lnrpc.CltvBudget{
+				MaxHopCltvDelta: 40,
+			},
+			expect: &routing.CltvBudget{
+				MaxHopDelta: 40,
+			},
+		},
+		{
+			name:       "request overrides configured limit",
+			configured: configured,
+			budget: &lnrpc.CltvBudget{
+				MaxLastHopCltvDelta: 80,
+			},
+			expect: &routing.CltvBudget{
+				MaxHopDelta:     144,
+				MaxLastHopDelta: 80,
+			},
+		},
+		{
+			name: "limit out of range",
+			budget: &lnrpc.CltvBudget{
+				MaxHopCltvDelta: math.MaxUint16 + 1,
+			},
+			err: "cltv budget",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			backend := &RouterBackend{
+				MaxTotalTimelock:      1000,
+				DefaultFinalCltvDelta: 40,
+				CltvBudget:            test.configured,
+			}
+
+			req := newTestSendRequest(t)
+			req.CltvBudget = test.budget
+
+			payment, err := backend.extractIntentFromSendRequest(req)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.expect, payment.CltvBudget)
+			}
+
+			routeReq, err := backend.parseQueryRoutesRequest(
+				&lnrpc.QueryRoutesRequest{
+					PubKey:     destKey,
+					Amt:        100_000,
+					CltvBudget: test.budget,
+				},
+			)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(
+				t, test.expect, routeReq.Restrictions.CltvBudget,
+			)
+		})
+	}
+}
+
+// TestExtractIntentHopCustomRecords asserts that the custom records addressed
+// to intermediate hops are parsed and that invalid records are rejected with
+// structured error details.
+func TestExtractIntentHopCustomRecords(t *testing.T) {
+	t.Parallel()
+
+	hopKey, err := hex.DecodeString(hintNodeKey)
+	require.NoError(t, err)
+	hop, err := route.NewVertexFromBytes(hopKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		records []*HopCustomRecords
+		expect  map[route.Vertex]record.CustomSet
+		invalid bool
+	}{
+		{
+			name: "no records",
+		},
+		{
+			name: "valid records",
+			records: []*HopCustomRecords{{
+				PubKey: hopKey,
+				CustomRecords: map[uint64][]byte{
+					65536: {1, 2, 3},
+				},
+			}},
+			expect: map[route.Vertex]record.CustomSet{
+				hop: {65536: {1, 2, 3}},
+			},
+		},
+		{
+			name: "invalid pubkey",
+			records: []*HopCustomRecords{{
+				PubKey: []byte{1, 2, 3},
+			}},
+			invalid: true,
+		},
+		{
+			name: "duplicate hop",
+			records: []*HopCustomRecords{
+				{PubKey: hopKey},
+				{PubKey: hopKey},
+			},
+			invalid: true,
+		},
+		{
+			name: "record type below custom range",
+			records: []*HopCustomRecords{{
+				PubKey: hopKey,
+				CustomRecords: map[uint64][]byte{
+					100: {1},
+				},
+			}},
+			invalid: true,
+		},
+	}
+
+	backend := &RouterBackend{
+		MaxTotalTimelock: 1000,
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := newTestSendRequest(t)
+			req.HopCustomRecords = test.records
+
+			payment, err := backend.extractIntentFromSendRequest(req)
+			if !test.invalid {
+				require.NoError(t, err)
+				require.Equal(
+					t, test.expect, payment.HopCustomRecords,
+				)
+
+				return
+			}
+
+			require.ErrorIs(t, err, routing.ErrInvalidHopCustomRecords)
+
+			err = sendRequestError(err)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+			details, ok := lnrpc.ErrorDetailsFromError(err)
+			require.True(t, ok)
+			require.Equal(
+				t, lnrpc.ErrCodeInvalidCustomRecords, details.Code,
+			)
+		})
+	}
+}
+
+// TestExtractIntentTrampoline asserts that the trampoline options of a send
+// request are parsed and passed on to the payment.
+func TestExtractIntentTrampoline(t *testing.T) {
+	t.Parallel()
+
+	nodeKey, err := hex.DecodeString(hintNodeKey)
+	require.NoError(t, err)
+	node, err := route.NewVertexFromBytes(nodeKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		opts   *TrampolineOptions
+		expect *routing.TrampolineOptions
+		err    string
+	}{
+		{
+			name: "no trampoline",
+		},
+		{
+			name: "trampoline",
+			opts: &TrampolineOptions{
+				Node:      nodeKey,
+				FeeMsat:   2_000,
+				CltvDelta: 144,
+			},
+			expect: &routing.TrampolineOptions{
+				Node:      node,
+				Fee:       2_000,
+				CltvDelta: 144,
+			},
+		},
+		{
+			name: "invalid node",
+			opts: &TrampolineOptions{
+				Node: []byte{1, 2, 3},
+			},
+			err: "invalid trampoline node",
+		},
+		{
+			name: "cltv delta out of range",
+			opts: &TrampolineOptions{
+				Node:      nodeKey,
+				CltvDelta: 1 << 16,
+			},
+			err: "cltv_delta",
+		},
+	}
+
+	backend := &RouterBackend{
+		MaxTotalTimelock: 1000,
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := newTestSendRequest(t)
+			req.Trampoline = test.opts
+
+			payment, err := backend.extractIntentFromSendRequest(req)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expect, payment.Trampoline)
+		})
+	}
+}
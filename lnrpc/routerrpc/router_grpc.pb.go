@@ -37,6 +37,13 @@ type RouterClient interface {
 	// payment attempt make sure to subscribe to this method before initiating any
 	// payments.
 	TrackPayments(ctx context.Context, in *TrackPaymentsRequest, opts ...grpc.CallOption) (Router_TrackPaymentsClient, error)
+	// lncli: `sendpayments`
+	// SendPayments sends a batch of payments and streams the interleaved status
+	// updates of all of them. At most routerrpc.maxparallelbatchpayments
+	// payments of the batch are in flight at the same time. A failing payment
+	// doesn't abort the batch. Once all payments reached a final state, the
+	// stream ends with a summary of the outcome of the batch.
+	SendPayments(ctx context.Context, in *SendPaymentsRequest, opts ...grpc.CallOption) (Router_SendPaymentsClient, error)
 	// EstimateRouteFee allows callers to obtain a lower bound w.r.t how much it
 	// may cost to send an HTLC to the target end destination.
 	EstimateRouteFee(ctx context.Context, in *RouteFeeRequest, opts ...grpc.CallOption) (*RouteFeeResponse, error)
@@ -80,6 +87,12 @@ type RouterClient interface {
 	// probability if no channel is available or if the amount violates min/max
 	// HTLC constraints.
 	QueryProbability(ctx context.Context, in *QueryProbabilityRequest, opts ...grpc.CallOption) (*QueryProbabilityResponse, error)
+	// lncli: `probehistory`
+	// ProbeHistory returns the results of the most recent probes of the
+	// background prober that is enabled with routing.probing.enable, oldest
+	// first. Probes aren't kept in the payment database, so this is the only
+	// place their outcome is recorded apart from mission control.
+	ProbeHistory(ctx context.Context, in *ProbeHistoryRequest, opts ...grpc.CallOption) (*ProbeHistoryResponse, error)
 	// lncli: `buildroute`
 	// BuildRoute builds a fully specified route based on a list of hop public
 	// keys. It retrieves the relevant channel policies from the graph in order to
@@ -89,9 +102,31 @@ type RouterClient interface {
 	// restriction. Moreover the caller has to make sure to provide the
 	// payment_addr if the route is paying an invoice which signaled it.
 	BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error)
+	// lncli: `rebalance`
+	// Rebalance moves local balance from one of our channels to another by
+	// paying ourselves in a circle. The payment leaves through the outgoing
+	// channel and comes back from the peer of the incoming channel. If
+	// query_only is set, the circular route is only computed and returned
+	// without sending the payment.
+	Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error)
 	// SubscribeHtlcEvents creates a uni-directional stream from the server to
 	// the client which delivers a stream of htlc events.
 	SubscribeHtlcEvents(ctx context.Context, in *SubscribeHtlcEventsRequest, opts ...grpc.CallOption) (Router_SubscribeHtlcEventsClient, error)
+	// lncli: `subscribeall`
+	// SubscribeAll multiplexes the channel, peer, htlc, invoice, payment and
+	// block events into a single stream, such that a client doesn't need to
+	// maintain a subscription for each of them. Only the events that match the
+	// filter of the request are sent. The stream ends once one of the underlying
+	// subscriptions terminates.
+	SubscribeAll(ctx context.Context, in *SubscribeAllRequest, opts ...grpc.CallOption) (Router_SubscribeAllClient, error)
+	// lncli: `stuckhtlcs`
+	// SubscribeStuckHtlcs creates a uni-directional stream from the server to
+	// the client which first delivers the htlcs that are currently stuck,
+	// followed by every htlc that becomes stuck. An htlc is stuck once it has
+	// been pending on one of our outgoing channels for longer than
+	// htlcswitch.stuckhtlcthreshold, which needs to be set to enable the
+	// detection of stuck htlcs.
+	SubscribeStuckHtlcs(ctx context.Context, in *SubscribeStuckHtlcsRequest, opts ...grpc.CallOption) (Router_SubscribeStuckHtlcsClient, error)
 	// Deprecated: Do not use.
 	//
 	// Deprecated, use SendPaymentV2. SendPayment attempts to route a payment
@@ -110,12 +145,51 @@ type RouterClient interface {
 	// In case of interception, the htlc can be either settled, cancelled or
 	// resumed later by using the ResolveHoldForward endpoint.
 	HtlcInterceptor(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorClient, error)
+	// *
+	// HtlcInterceptorBatched is a variant of HtlcInterceptor for high-throughput
+	// clients. The client can send many resolutions in a single message, and can
+	// ask for an acknowledgement of every resolution by setting its request_ack
+	// field. A resolution that fails to apply is reported in its
+	// acknowledgement instead of terminating the stream. Only one of
+	// HtlcInterceptor and HtlcInterceptorBatched can be active at a time.
+	HtlcInterceptorBatched(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorBatchedClient, error)
 	// lncli: `updatechanstatus`
 	// UpdateChanStatus attempts to manually set the state of a channel
 	// (enabled, disabled, or auto). A manual "disable" request will cause the
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error)
+	// lncli: `dustexposure`
+	// DustExposure returns the dust exposure of the channels that have an active
+	// link, or of a single channel if its id is set. The exposure is reported
+	// per commitment and includes the dust htlcs that are still waiting in the
+	// channel's mailbox.
+	DustExposure(ctx context.Context, in *DustExposureRequest, opts ...grpc.CallOption) (*DustExposureResponse, error)
+	// lncli: `updatemaxfeeexposure`
+	// UpdateMaxFeeExposure updates the threshold after which new dust htlcs are
+	// rejected, which is initially set by dust-threshold. The new threshold
+	// applies to all channels until lnd is restarted. Htlcs that are already
+	// committed are not affected.
+	UpdateMaxFeeExposure(ctx context.Context, in *UpdateMaxFeeExposureRequest, opts ...grpc.CallOption) (*UpdateMaxFeeExposureResponse, error)
+	// lncli: `reputation`
+	// ChannelReputation returns the reputation of our incoming channels in the
+	// experimental htlc endorsement scheme, or of a single channel if its id is
+	// set. An outgoing htlc is only endorsed if the reputation of its incoming
+	// channel covers the fees of all of its endorsed htlcs in flight.
+	ChannelReputation(ctx context.Context, in *ChannelReputationRequest, opts ...grpc.CallOption) (*ChannelReputationResponse, error)
+	// lncli: `fwdpkgs`
+	// ForwardingPackages returns the forwarding packages of a channel that
+	// haven't been removed yet. A forwarding package holds the updates that the
+	// remote party locked in at a given height, and tracks which of them have
+	// been forwarded and acked.
+	ForwardingPackages(ctx context.Context, in *ForwardingPackagesRequest, opts ...grpc.CallOption) (*ForwardingPackagesResponse, error)
+	// lncli: `reprocessfwdpkg`
+	// ReprocessForwardingPackage replays a single forwarding package of a
+	// channel, just like the channel does for all of its packages when it is
+	// started. This is safe, since duplicate updates are filtered out. Completed
+	// packages can't be replayed, and neither can packages of a quiescent
+	// channel.
+	ReprocessForwardingPackage(ctx context.Context, in *ReprocessForwardingPackageRequest, opts ...grpc.CallOption) (*ReprocessForwardingPackageResponse, error)
 	// XAddLocalChanAliases is an experimental API that creates a set of new
 	// channel SCID alias mappings. The final total set of aliases in the manager
 	// after the add operation is returned. This is only a locally stored alias,
@@ -234,6 +308,38 @@ func (x *routerTrackPaymentsClient) Recv() (*lnrpc.Payment, error) {
 	return m, nil
 }
 
+func (c *routerClient) SendPayments(ctx context.Context, in *SendPaymentsRequest, opts ...grpc.CallOption) (Router_SendPaymentsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[3], "/routerrpc.Router/SendPayments", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerSendPaymentsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Router_SendPaymentsClient interface {
+	Recv() (*SendPaymentsResponse, error)
+	grpc.ClientStream
+}
+
+type routerSendPaymentsClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerSendPaymentsClient) Recv() (*SendPaymentsResponse, error) {
+	m := new(SendPaymentsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *routerClient) EstimateRouteFee(ctx context.Context, in *RouteFeeRequest, opts ...grpc.CallOption) (*RouteFeeResponse, error) {
 	out := new(RouteFeeResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/EstimateRouteFee", in, out, opts...)
@@ -316,6 +422,15 @@ func (c *routerClient) QueryProbability(ctx context.Context, in *QueryProbabilit
 	return out, nil
 }
 
+func (c *routerClient) ProbeHistory(ctx context.Context, in *ProbeHistoryRequest, opts ...grpc.CallOption) (*ProbeHistoryResponse, error) {
+	out := new(ProbeHistoryResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ProbeHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) BuildRoute(ctx context.Context, in *BuildRouteRequest, opts ...grpc.CallOption) (*BuildRouteResponse, error) {
 	out := new(BuildRouteResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/BuildRoute", in, out, opts...)
@@ -325,8 +440,17 @@ func (c *routerClient) BuildRoute(ctx context.Context, in *BuildRouteRequest, op
 	return out, nil
 }
 
+func (c *routerClient) Rebalance(ctx context.Context, in *RebalanceRequest, opts ...grpc.CallOption) (*RebalanceResponse, error) {
+	out := new(RebalanceResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/Rebalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) SubscribeHtlcEvents(ctx context.Context, in *SubscribeHtlcEventsRequest, opts ...grpc.CallOption) (Router_SubscribeHtlcEventsClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[3], "/routerrpc.Router/SubscribeHtlcEvents", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[4], "/routerrpc.Router/SubscribeHtlcEvents", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -357,9 +481,73 @@ func (x *routerSubscribeHtlcEventsClient) Recv() (*HtlcEvent, error) {
 	return m, nil
 }
 
+func (c *routerClient) SubscribeAll(ctx context.Context, in *SubscribeAllRequest, opts ...grpc.CallOption) (Router_SubscribeAllClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[5], "/routerrpc.Router/SubscribeAll", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerSubscribeAllClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Router_SubscribeAllClient interface {
+	Recv() (*SubscribeAllEvent, error)
+	grpc.ClientStream
+}
+
+type routerSubscribeAllClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerSubscribeAllClient) Recv() (*SubscribeAllEvent, error) {
+	m := new(SubscribeAllEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *routerClient) SubscribeStuckHtlcs(ctx context.Context, in *SubscribeStuckHtlcsRequest, opts ...grpc.CallOption) (Router_SubscribeStuckHtlcsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[6], "/routerrpc.Router/SubscribeStuckHtlcs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerSubscribeStuckHtlcsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Router_SubscribeStuckHtlcsClient interface {
+	Recv() (*StuckHtlc, error)
+	grpc.ClientStream
+}
+
+type routerSubscribeStuckHtlcsClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerSubscribeStuckHtlcsClient) Recv() (*StuckHtlc, error) {
+	m := new(StuckHtlc)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Deprecated: Do not use.
 func (c *routerClient) SendPayment(ctx context.Context, in *SendPaymentRequest, opts ...grpc.CallOption) (Router_SendPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[4], "/routerrpc.Router/SendPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[7], "/routerrpc.Router/SendPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -392,7 +580,7 @@ func (x *routerSendPaymentClient) Recv() (*PaymentStatus, error) {
 
 // Deprecated: Do not use.
 func (c *routerClient) TrackPayment(ctx context.Context, in *TrackPaymentRequest, opts ...grpc.CallOption) (Router_TrackPaymentClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[5], "/routerrpc.Router/TrackPayment", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[8], "/routerrpc.Router/TrackPayment", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -424,7 +612,7 @@ func (x *routerTrackPaymentClient) Recv() (*PaymentStatus, error) {
 }
 
 func (c *routerClient) HtlcInterceptor(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorClient, error) {
-	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[6], "/routerrpc.Router/HtlcInterceptor", opts...)
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[9], "/routerrpc.Router/HtlcInterceptor", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -454,6 +642,37 @@ func (x *routerHtlcInterceptorClient) Recv() (*ForwardHtlcInterceptRequest, erro
 	return m, nil
 }
 
+func (c *routerClient) HtlcInterceptorBatched(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorBatchedClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Router_ServiceDesc.Streams[10], "/routerrpc.Router/HtlcInterceptorBatched", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &routerHtlcInterceptorBatchedClient{stream}
+	return x, nil
+}
+
+type Router_HtlcInterceptorBatchedClient interface {
+	Send(*ForwardHtlcInterceptBatch) error
+	Recv() (*HtlcInterceptorUpdate, error)
+	grpc.ClientStream
+}
+
+type routerHtlcInterceptorBatchedClient struct {
+	grpc.ClientStream
+}
+
+func (x *routerHtlcInterceptorBatchedClient) Send(m *ForwardHtlcInterceptBatch) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *routerHtlcInterceptorBatchedClient) Recv() (*HtlcInterceptorUpdate, error) {
+	m := new(HtlcInterceptorUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *routerClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatusRequest, opts ...grpc.CallOption) (*UpdateChanStatusResponse, error) {
 	out := new(UpdateChanStatusResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/UpdateChanStatus", in, out, opts...)
@@ -463,6 +682,51 @@ func (c *routerClient) UpdateChanStatus(ctx context.Context, in *UpdateChanStatu
 	return out, nil
 }
 
+func (c *routerClient) DustExposure(ctx context.Context, in *DustExposureRequest, opts ...grpc.CallOption) (*DustExposureResponse, error) {
+	out := new(DustExposureResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/DustExposure", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) UpdateMaxFeeExposure(ctx context.Context, in *UpdateMaxFeeExposureRequest, opts ...grpc.CallOption) (*UpdateMaxFeeExposureResponse, error) {
+	out := new(UpdateMaxFeeExposureResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/UpdateMaxFeeExposure", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ChannelReputation(ctx context.Context, in *ChannelReputationRequest, opts ...grpc.CallOption) (*ChannelReputationResponse, error) {
+	out := new(ChannelReputationResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ChannelReputation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ForwardingPackages(ctx context.Context, in *ForwardingPackagesRequest, opts ...grpc.CallOption) (*ForwardingPackagesResponse, error) {
+	out := new(ForwardingPackagesResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ForwardingPackages", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *routerClient) ReprocessForwardingPackage(ctx context.Context, in *ReprocessForwardingPackageRequest, opts ...grpc.CallOption) (*ReprocessForwardingPackageResponse, error) {
+	out := new(ReprocessForwardingPackageResponse)
+	err := c.cc.Invoke(ctx, "/routerrpc.Router/ReprocessForwardingPackage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *routerClient) XAddLocalChanAliases(ctx context.Context, in *AddAliasesRequest, opts ...grpc.CallOption) (*AddAliasesResponse, error) {
 	out := new(AddAliasesResponse)
 	err := c.cc.Invoke(ctx, "/routerrpc.Router/XAddLocalChanAliases", in, out, opts...)
@@ -503,6 +767,13 @@ type RouterServer interface {
 	// payment attempt make sure to subscribe to this method before initiating any
 	// payments.
 	TrackPayments(*TrackPaymentsRequest, Router_TrackPaymentsServer) error
+	// lncli: `sendpayments`
+	// SendPayments sends a batch of payments and streams the interleaved status
+	// updates of all of them. At most routerrpc.maxparallelbatchpayments
+	// payments of the batch are in flight at the same time. A failing payment
+	// doesn't abort the batch. Once all payments reached a final state, the
+	// stream ends with a summary of the outcome of the batch.
+	SendPayments(*SendPaymentsRequest, Router_SendPaymentsServer) error
 	// EstimateRouteFee allows callers to obtain a lower bound w.r.t how much it
 	// may cost to send an HTLC to the target end destination.
 	EstimateRouteFee(context.Context, *RouteFeeRequest) (*RouteFeeResponse, error)
@@ -546,6 +817,12 @@ type RouterServer interface {
 	// probability if no channel is available or if the amount violates min/max
 	// HTLC constraints.
 	QueryProbability(context.Context, *QueryProbabilityRequest) (*QueryProbabilityResponse, error)
+	// lncli: `probehistory`
+	// ProbeHistory returns the results of the most recent probes of the
+	// background prober that is enabled with routing.probing.enable, oldest
+	// first. Probes aren't kept in the payment database, so this is the only
+	// place their outcome is recorded apart from mission control.
+	ProbeHistory(context.Context, *ProbeHistoryRequest) (*ProbeHistoryResponse, error)
 	// lncli: `buildroute`
 	// BuildRoute builds a fully specified route based on a list of hop public
 	// keys. It retrieves the relevant channel policies from the graph in order to
@@ -555,9 +832,31 @@ type RouterServer interface {
 	// restriction. Moreover the caller has to make sure to provide the
 	// payment_addr if the route is paying an invoice which signaled it.
 	BuildRoute(context.Context, *BuildRouteRequest) (*BuildRouteResponse, error)
+	// lncli: `rebalance`
+	// Rebalance moves local balance from one of our channels to another by
+	// paying ourselves in a circle. The payment leaves through the outgoing
+	// channel and comes back from the peer of the incoming channel. If
+	// query_only is set, the circular route is only computed and returned
+	// without sending the payment.
+	Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error)
 	// SubscribeHtlcEvents creates a uni-directional stream from the server to
 	// the client which delivers a stream of htlc events.
 	SubscribeHtlcEvents(*SubscribeHtlcEventsRequest, Router_SubscribeHtlcEventsServer) error
+	// lncli: `subscribeall`
+	// SubscribeAll multiplexes the channel, peer, htlc, invoice, payment and
+	// block events into a single stream, such that a client doesn't need to
+	// maintain a subscription for each of them. Only the events that match the
+	// filter of the request are sent. The stream ends once one of the underlying
+	// subscriptions terminates.
+	SubscribeAll(*SubscribeAllRequest, Router_SubscribeAllServer) error
+	// lncli: `stuckhtlcs`
+	// SubscribeStuckHtlcs creates a uni-directional stream from the server to
+	// the client which first delivers the htlcs that are currently stuck,
+	// followed by every htlc that becomes stuck. An htlc is stuck once it has
+	// been pending on one of our outgoing channels for longer than
+	// htlcswitch.stuckhtlcthreshold, which needs to be set to enable the
+	// detection of stuck htlcs.
+	SubscribeStuckHtlcs(*SubscribeStuckHtlcsRequest, Router_SubscribeStuckHtlcsServer) error
 	// Deprecated: Do not use.
 	//
 	// Deprecated, use SendPaymentV2. SendPayment attempts to route a payment
@@ -576,12 +875,51 @@ type RouterServer interface {
 	// In case of interception, the htlc can be either settled, cancelled or
 	// resumed later by using the ResolveHoldForward endpoint.
 	HtlcInterceptor(Router_HtlcInterceptorServer) error
+	// *
+	// HtlcInterceptorBatched is a variant of HtlcInterceptor for high-throughput
+	// clients. The client can send many resolutions in a single message, and can
+	// ask for an acknowledgement of every resolution by setting its request_ack
+	// field. A resolution that fails to apply is reported in its
+	// acknowledgement instead of terminating the stream. Only one of
+	// HtlcInterceptor and HtlcInterceptorBatched can be active at a time.
+	HtlcInterceptorBatched(Router_HtlcInterceptorBatchedServer) error
 	// lncli: `updatechanstatus`
 	// UpdateChanStatus attempts to manually set the state of a channel
 	// (enabled, disabled, or auto). A manual "disable" request will cause the
 	// channel to stay disabled until a subsequent manual request of either
 	// "enable" or "auto".
 	UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error)
+	// lncli: `dustexposure`
+	// DustExposure returns the dust exposure of the channels that have an active
+	// link, or of a single channel if its id is set. The exposure is reported
+	// per commitment and includes the dust htlcs that are still waiting in the
+	// channel's mailbox.
+	DustExposure(context.Context, *DustExposureRequest) (*DustExposureResponse, error)
+	// lncli: `updatemaxfeeexposure`
+	// UpdateMaxFeeExposure updates the threshold after which new dust htlcs are
+	// rejected, which is initially set by dust-threshold. The new threshold
+	// applies to all channels until lnd is restarted. Htlcs that are already
+	// committed are not affected.
+	UpdateMaxFeeExposure(context.Context, *UpdateMaxFeeExposureRequest) (*UpdateMaxFeeExposureResponse, error)
+	// lncli: `reputation`
+	// ChannelReputation returns the reputation of our incoming channels in the
+	// experimental htlc endorsement scheme, or of a single channel if its id is
+	// set. An outgoing htlc is only endorsed if the reputation of its incoming
+	// channel covers the fees of all of its endorsed htlcs in flight.
+	ChannelReputation(context.Context, *ChannelReputationRequest) (*ChannelReputationResponse, error)
+	// lncli: `fwdpkgs`
+	// ForwardingPackages returns the forwarding packages of a channel that
+	// haven't been removed yet. A forwarding package holds the updates that the
+	// remote party locked in at a given height, and tracks which of them have
+	// been forwarded and acked.
+	ForwardingPackages(context.Context, *ForwardingPackagesRequest) (*ForwardingPackagesResponse, error)
+	// lncli: `reprocessfwdpkg`
+	// ReprocessForwardingPackage replays a single forwarding package of a
+	// channel, just like the channel does for all of its packages when it is
+	// started. This is safe, since duplicate updates are filtered out. Completed
+	// packages can't be replayed, and neither can packages of a quiescent
+	// channel.
+	ReprocessForwardingPackage(context.Context, *ReprocessForwardingPackageRequest) (*ReprocessForwardingPackageResponse, error)
 	// XAddLocalChanAliases is an experimental API that creates a set of new
 	// channel SCID alias mappings. The final total set of aliases in the manager
 	// after the add operation is returned. This is only a locally stored alias,
@@ -610,6 +948,9 @@ func (UnimplementedRouterServer) TrackPaymentV2(*TrackPaymentRequest, Router_Tra
 func (UnimplementedRouterServer) TrackPayments(*TrackPaymentsRequest, Router_TrackPaymentsServer) error {
 	return status.Errorf(codes.Unimplemented, "method TrackPayments not implemented")
 }
+func (UnimplementedRouterServer) SendPayments(*SendPaymentsRequest, Router_SendPaymentsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SendPayments not implemented")
+}
 func (UnimplementedRouterServer) EstimateRouteFee(context.Context, *RouteFeeRequest) (*RouteFeeResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method EstimateRouteFee not implemented")
 }
@@ -637,12 +978,24 @@ func (UnimplementedRouterServer) SetMissionControlConfig(context.Context, *SetMi
 func (UnimplementedRouterServer) QueryProbability(context.Context, *QueryProbabilityRequest) (*QueryProbabilityResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method QueryProbability not implemented")
 }
+func (UnimplementedRouterServer) ProbeHistory(context.Context, *ProbeHistoryRequest) (*ProbeHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProbeHistory not implemented")
+}
 func (UnimplementedRouterServer) BuildRoute(context.Context, *BuildRouteRequest) (*BuildRouteResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BuildRoute not implemented")
 }
+func (UnimplementedRouterServer) Rebalance(context.Context, *RebalanceRequest) (*RebalanceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rebalance not implemented")
+}
 func (UnimplementedRouterServer) SubscribeHtlcEvents(*SubscribeHtlcEventsRequest, Router_SubscribeHtlcEventsServer) error {
 	return status.Errorf(codes.Unimplemented, "method SubscribeHtlcEvents not implemented")
 }
+func (UnimplementedRouterServer) SubscribeAll(*SubscribeAllRequest, Router_SubscribeAllServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeAll not implemented")
+}
+func (UnimplementedRouterServer) SubscribeStuckHtlcs(*SubscribeStuckHtlcsRequest, Router_SubscribeStuckHtlcsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeStuckHtlcs not implemented")
+}
 func (UnimplementedRouterServer) SendPayment(*SendPaymentRequest, Router_SendPaymentServer) error {
 	return status.Errorf(codes.Unimplemented, "method SendPayment not implemented")
 }
@@ -652,9 +1005,27 @@ func (UnimplementedRouterServer) TrackPayment(*TrackPaymentRequest, Router_Track
 func (UnimplementedRouterServer) HtlcInterceptor(Router_HtlcInterceptorServer) error {
 	return status.Errorf(codes.Unimplemented, "method HtlcInterceptor not implemented")
 }
+func (UnimplementedRouterServer) HtlcInterceptorBatched(Router_HtlcInterceptorBatchedServer) error {
+	return status.Errorf(codes.Unimplemented, "method HtlcInterceptorBatched not implemented")
+}
 func (UnimplementedRouterServer) UpdateChanStatus(context.Context, *UpdateChanStatusRequest) (*UpdateChanStatusResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateChanStatus not implemented")
 }
+func (UnimplementedRouterServer) DustExposure(context.Context, *DustExposureRequest) (*DustExposureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DustExposure not implemented")
+}
+func (UnimplementedRouterServer) UpdateMaxFeeExposure(context.Context, *UpdateMaxFeeExposureRequest) (*UpdateMaxFeeExposureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMaxFeeExposure not implemented")
+}
+func (UnimplementedRouterServer) ChannelReputation(context.Context, *ChannelReputationRequest) (*ChannelReputationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChannelReputation not implemented")
+}
+func (UnimplementedRouterServer) ForwardingPackages(context.Context, *ForwardingPackagesRequest) (*ForwardingPackagesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForwardingPackages not implemented")
+}
+func (UnimplementedRouterServer) ReprocessForwardingPackage(context.Context, *ReprocessForwardingPackageRequest) (*ReprocessForwardingPackageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReprocessForwardingPackage not implemented")
+}
 func (UnimplementedRouterServer) XAddLocalChanAliases(context.Context, *AddAliasesRequest) (*AddAliasesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method XAddLocalChanAliases not implemented")
 }
@@ -737,6 +1108,27 @@ func (x *routerTrackPaymentsServer) Send(m *lnrpc.Payment) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Router_SendPayments_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SendPaymentsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouterServer).SendPayments(m, &routerSendPaymentsServer{stream})
+}
+
+type Router_SendPaymentsServer interface {
+	Send(*SendPaymentsResponse) error
+	grpc.ServerStream
+}
+
+type routerSendPaymentsServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerSendPaymentsServer) Send(m *SendPaymentsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Router_EstimateRouteFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RouteFeeRequest)
 	if err := dec(in); err != nil {
@@ -899,6 +1291,24 @@ func _Router_QueryProbability_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_ProbeHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProbeHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ProbeHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ProbeHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ProbeHistory(ctx, req.(*ProbeHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_BuildRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BuildRouteRequest)
 	if err := dec(in); err != nil {
@@ -917,6 +1327,24 @@ func _Router_BuildRoute_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_Rebalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).Rebalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/Rebalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).Rebalance(ctx, req.(*RebalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_SubscribeHtlcEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(SubscribeHtlcEventsRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -938,6 +1366,48 @@ func (x *routerSubscribeHtlcEventsServer) Send(m *HtlcEvent) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Router_SubscribeAll_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeAllRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouterServer).SubscribeAll(m, &routerSubscribeAllServer{stream})
+}
+
+type Router_SubscribeAllServer interface {
+	Send(*SubscribeAllEvent) error
+	grpc.ServerStream
+}
+
+type routerSubscribeAllServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerSubscribeAllServer) Send(m *SubscribeAllEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Router_SubscribeStuckHtlcs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeStuckHtlcsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RouterServer).SubscribeStuckHtlcs(m, &routerSubscribeStuckHtlcsServer{stream})
+}
+
+type Router_SubscribeStuckHtlcsServer interface {
+	Send(*StuckHtlc) error
+	grpc.ServerStream
+}
+
+type routerSubscribeStuckHtlcsServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerSubscribeStuckHtlcsServer) Send(m *StuckHtlc) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _Router_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(SendPaymentRequest)
 	if err := stream.RecvMsg(m); err != nil {
@@ -1006,6 +1476,32 @@ func (x *routerHtlcInterceptorServer) Recv() (*ForwardHtlcInterceptResponse, err
 	return m, nil
 }
 
+func _Router_HtlcInterceptorBatched_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(RouterServer).HtlcInterceptorBatched(&routerHtlcInterceptorBatchedServer{stream})
+}
+
+type Router_HtlcInterceptorBatchedServer interface {
+	Send(*HtlcInterceptorUpdate) error
+	Recv() (*ForwardHtlcInterceptBatch, error)
+	grpc.ServerStream
+}
+
+type routerHtlcInterceptorBatchedServer struct {
+	grpc.ServerStream
+}
+
+func (x *routerHtlcInterceptorBatchedServer) Send(m *HtlcInterceptorUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *routerHtlcInterceptorBatchedServer) Recv() (*ForwardHtlcInterceptBatch, error) {
+	m := new(ForwardHtlcInterceptBatch)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Router_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateChanStatusRequest)
 	if err := dec(in); err != nil {
@@ -1024,6 +1520,96 @@ func _Router_UpdateChanStatus_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Router_DustExposure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DustExposureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).DustExposure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/DustExposure",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).DustExposure(ctx, req.(*DustExposureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_UpdateMaxFeeExposure_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMaxFeeExposureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).UpdateMaxFeeExposure(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/UpdateMaxFeeExposure",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).UpdateMaxFeeExposure(ctx, req.(*UpdateMaxFeeExposureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ChannelReputation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelReputationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ChannelReputation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ChannelReputation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ChannelReputation(ctx, req.(*ChannelReputationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ForwardingPackages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForwardingPackagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ForwardingPackages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ForwardingPackages",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ForwardingPackages(ctx, req.(*ForwardingPackagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Router_ReprocessForwardingPackage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReprocessForwardingPackageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RouterServer).ReprocessForwardingPackage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/routerrpc.Router/ReprocessForwardingPackage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RouterServer).ReprocessForwardingPackage(ctx, req.(*ReprocessForwardingPackageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Router_XAddLocalChanAliases_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AddAliasesRequest)
 	if err := dec(in); err != nil {
@@ -1103,14 +1689,42 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueryProbability",
 			Handler:    _Router_QueryProbability_Handler,
 		},
+		{
+			MethodName: "ProbeHistory",
+			Handler:    _Router_ProbeHistory_Handler,
+		},
 		{
 			MethodName: "BuildRoute",
 			Handler:    _Router_BuildRoute_Handler,
 		},
+		{
+			MethodName: "Rebalance",
+			Handler:    _Router_Rebalance_Handler,
+		},
 		{
 			MethodName: "UpdateChanStatus",
 			Handler:    _Router_UpdateChanStatus_Handler,
 		},
+		{
+			MethodName: "DustExposure",
+			Handler:    _Router_DustExposure_Handler,
+		},
+		{
+			MethodName: "UpdateMaxFeeExposure",
+			Handler:    _Router_UpdateMaxFeeExposure_Handler,
+		},
+		{
+			MethodName: "ChannelReputation",
+			Handler:    _Router_ChannelReputation_Handler,
+		},
+		{
+			MethodName: "ForwardingPackages",
+			Handler:    _Router_ForwardingPackages_Handler,
+		},
+		{
+			MethodName: "ReprocessForwardingPackage",
+			Handler:    _Router_ReprocessForwardingPackage_Handler,
+		},
 		{
 			MethodName: "XAddLocalChanAliases",
 			Handler:    _Router_XAddLocalChanAliases_Handler,
@@ -1136,11 +1750,26 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Router_TrackPayments_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SendPayments",
+			Handler:       _Router_SendPayments_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SubscribeHtlcEvents",
 			Handler:       _Router_SubscribeHtlcEvents_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SubscribeAll",
+			Handler:       _Router_SubscribeAll_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeStuckHtlcs",
+			Handler:       _Router_SubscribeStuckHtlcs_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "SendPayment",
 			Handler:       _Router_SendPayment_Handler,
@@ -1157,6 +1786,12 @@ var Router_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "HtlcInterceptorBatched",
+			Handler:       _Router_HtlcInterceptorBatched_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "routerrpc/router.proto",
 }
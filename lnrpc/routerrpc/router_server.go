@@ -17,6 +17,7 @@ import (
 	"github.com/lightningnetwork/lnd/aliasmgr"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lntypes"
@@ -64,6 +65,16 @@ var (
 	// allowed SCID aliases.
 	ErrNoValidAlias = errors.New("not a valid alias")
 
+	// errProbingDisabled is returned when the probe history is requested
+	// while the background prober isn't enabled.
+	errProbingDisabled = errors.New("probing not enabled, set " +
+		"routing.probing.enable")
+
+	// errStuckHtlcsDisabled is returned when stuck htlcs are requested
+	// while their detection isn't enabled.
+	errStuckHtlcsDisabled = errors.New("stuck htlc detection not " +
+		"enabled, set htlcswitch.stuckhtlcthreshold")
+
 	// macaroonOps are the set of capabilities that our minted macaroon (if
 	// it doesn't already exist) will have.
 	macaroonOps = []bakery.Op{
@@ -83,6 +94,10 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/SendPayments": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/routerrpc.Router/SendToRouteV2": {{
 			Entity: "offchain",
 			Action: "write",
@@ -131,10 +146,31 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/routerrpc.Router/Rebalance": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/routerrpc.Router/SubscribeHtlcEvents": {{
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/routerrpc.Router/SubscribeAll": {{
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "peers",
+			Action: "read",
+		}, {
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/routerrpc.Router/SubscribeStuckHtlcs": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/routerrpc.Router/SendPayment": {{
 			Entity: "offchain",
 			Action: "write",
@@ -147,10 +183,34 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/HtlcInterceptorBatched": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/routerrpc.Router/UpdateChanStatus": {{
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/DustExposure": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/UpdateMaxFeeExposure": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/routerrpc.Router/ChannelReputation": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/ForwardingPackages": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/routerrpc.Router/ReprocessForwardingPackage": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/routerrpc.Router/XAddLocalChanAliases": {{
 			Entity: "offchain",
 			Action: "write",
@@ -159,6 +219,10 @@ var (
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/routerrpc.Router/ProbeHistory": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 	}
 
 	// DefaultRouterMacFilename is the default name of the router macaroon
@@ -346,7 +410,7 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 
 	payment, err := s.cfg.RouterBackend.extractIntentFromSendRequest(req)
 	if err != nil {
-		return err
+		return sendRequestError(err)
 	}
 
 	// Get the payment hash.
@@ -359,16 +423,7 @@ func (s *Server) SendPaymentV2(req *SendPaymentRequest,
 			payment.Identifier(), err)
 
 		// Transform user errors to grpc code.
-		if errors.Is(err, channeldb.ErrPaymentExists) ||
-			errors.Is(err, channeldb.ErrPaymentInFlight) ||
-			errors.Is(err, channeldb.ErrAlreadyPaid) {
-
-			return status.Error(
-				codes.AlreadyExists, err.Error(),
-			)
-		}
-
-		return err
+		return paymentStatusError(payHash, err)
 	}
 
 	// Subscribe to the payment before sending it to make sure we won't
@@ -417,17 +472,17 @@ func (s *Server) EstimateRouteFee(ctx context.Context,
 		return nil, errors.New("specify either a destination or an " +
 			"invoice")
 
-	case isProbeDestination:
-		switch {
-		case len(req.Dest) != 33:
-			return nil, errors.New("invalid length destination key")
+	case isProbeDestination && len(req.Dest) != 33:
+		return nil, errors.New("invalid length destination key")
 
-		case req.AmtSat <= 0:
-			return nil, errors.New("amount must be greater than 0")
+	case isProbeDestination && req.AmtSat <= 0:
+		return nil, errors.New("amount must be greater than 0")
 
-		default:
-			return s.probeDestination(req.Dest, req.AmtSat)
-		}
+	case req.Simulate:
+		return s.simulatePayment(ctx, req)
+
+	case isProbeDestination:
+		return s.probeDestination(req.Dest, req.AmtSat)
 
 	case isProbeInvoice:
 		return s.probePaymentRequest(
@@ -487,6 +542,37 @@ func (s *Server) probeDestination(dest []byte, amtSat int64) (*RouteFeeResponse,
 	}, nil
 }
 
+// simulatePayment estimates the fees and the success probability of a payment
+// to a destination or an invoice by simulating it against the current mission
+// control state, without sending any HTLCs.
+func (s *Server) simulatePayment(ctx context.Context,
+	req *RouteFeeRequest) (*RouteFeeResponse, error) {
+
+	simRequest := &SendPaymentRequest{
+		// The timeout must be set, but it isn't used since the payment
+		// isn't sent.
+		TimeoutSeconds: 1,
+		PaymentRequest: req.PaymentRequest,
+		FeeLimitSat:    routeFeeLimitSat,
+	}
+
+	// A payment to a destination needs a payment hash, for which we use a
+	// random one.
+	if len(req.Dest) > 0 {
+		var paymentHash lntypes.Hash
+		if _, err := crand.Read(paymentHash[:]); err != nil {
+			return nil, fmt.Errorf("cannot generate random "+
+				"payment hash: %w", err)
+		}
+
+		simRequest.Dest = req.Dest
+		simRequest.Amt = req.AmtSat
+		simRequest.PaymentHash = paymentHash[:]
+	}
+
+	return s.cfg.RouterBackend.EstimatePaymentFee(ctx, simRequest)
+}
+
 // probePaymentRequest estimates fees along a route to a destination that is
 // specified in an invoice. The estimation duration is limited by a timeout. In
 // case that route hints are provided, this method applies a heuristic to
@@ -902,20 +988,58 @@ func (s *Server) SendToRouteV2(ctx context.Context,
 	}
 
 	// Transform user errors to grpc code.
+	return nil, paymentStatusError(hash, err)
+}
+
+// paymentStatusError transforms the errors that prevent a payment from being
+// initiated into a gRPC status error that carries structured details. Other
+// errors are returned unchanged.
+func paymentStatusError(hash lntypes.Hash, err error) error {
+	var (
+		code     lnrpc.ErrorCode
+		grpcCode = codes.AlreadyExists
+	)
 	switch {
 	case errors.Is(err, channeldb.ErrPaymentExists):
-		fallthrough
+		code = lnrpc.ErrCodePaymentExists
 
 	case errors.Is(err, channeldb.ErrPaymentInFlight):
-		fallthrough
+		code = lnrpc.ErrCodePaymentInFlight
 
 	case errors.Is(err, channeldb.ErrAlreadyPaid):
-		return nil, status.Error(
-			codes.AlreadyExists, err.Error(),
-		)
+		code = lnrpc.ErrCodeAlreadyPaid
+
+	case errors.Is(err, routing.ErrInvalidHopCustomRecords):
+		code = lnrpc.ErrCodeInvalidCustomRecords
+		grpcCode = codes.InvalidArgument
+
+	default:
+		return err
 	}
 
-	return nil, err
+	// A payment that is still in flight may be retried once it failed.
+	return lnrpc.NewDetailedError(
+		grpcCode, err, &lnrpc.ErrorDetails{
+			Code:        code,
+			PaymentHash: hash.String(),
+			Retryable:   code == lnrpc.ErrCodePaymentInFlight,
+		},
+	)
+}
+
+// sendRequestError transforms the errors of an invalid send request into a
+// gRPC status error that carries structured details. Other errors are returned
+// unchanged.
+func sendRequestError(err error) error {
+	if !errors.Is(err, routing.ErrInvalidHopCustomRecords) {
+		return err
+	}
+
+	return lnrpc.NewDetailedError(
+		codes.InvalidArgument, err, &lnrpc.ErrorDetails{
+			Code: lnrpc.ErrCodeInvalidCustomRecords,
+		},
+	)
 }
 
 // ResetMissionControl clears all mission control state and starts with a clean
@@ -1121,6 +1245,55 @@ func toRPCPairData(data *routing.TimedPairResult) *PairData {
 	return &rpcData
 }
 
+// ProbeHistory returns the results of the most recent probes of the background
+// prober, oldest first.
+func (s *Server) ProbeHistory(_ context.Context,
+	_ *ProbeHistoryRequest) (*ProbeHistoryResponse, error) {
+
+	if s.cfg.RouterBackend.ProbeHistory == nil {
+		return nil, errProbingDisabled
+	}
+
+	history := s.cfg.RouterBackend.ProbeHistory()
+
+	resp := &ProbeHistoryResponse{
+		Results: make([]*ProbeResult, 0, len(history)),
+	}
+	for _, result := range history {
+		rpcResult, err := marshallProbeResult(&result)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Results = append(resp.Results, rpcResult)
+	}
+
+	return resp, nil
+}
+
+// marshallProbeResult converts the result of a probe to its rpc
+// representation.
+func marshallProbeResult(result *routing.ProbeResult) (*ProbeResult, error) {
+	reason, err := marshallPaymentFailureReason(result.FailureReason)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcResult := &ProbeResult{
+		Target:        result.Target[:],
+		AmtMsat:       int64(result.Amount),
+		TimestampNs:   result.Timestamp.UnixNano(),
+		DurationMs:    result.Duration.Milliseconds(),
+		Reached:       result.Reached,
+		FailureReason: reason,
+	}
+	if result.Err != nil {
+		rpcResult.Error = result.Err.Error()
+	}
+
+	return rpcResult, nil
+}
+
 // XImportMissionControl imports the state provided to our internal mission
 // control. Only entries that are fresher than our existing state will be used.
 func (s *Server) XImportMissionControl(_ context.Context,
@@ -1558,6 +1731,96 @@ func (s *Server) SubscribeHtlcEvents(_ *SubscribeHtlcEventsRequest,
 	}
 }
 
+// SubscribeAll creates a uni-directional stream from the server to the client
+// which delivers the channel, peer, htlc, invoice, payment and block events
+// that match the filter of the request.
+func (s *Server) SubscribeAll(req *SubscribeAllRequest,
+	stream Router_SubscribeAllServer) error {
+
+	return s.cfg.RouterBackend.SubscribeAll(
+		stream.Context(), req, stream.Send,
+	)
+}
+
+// SubscribeStuckHtlcs creates a uni-directional stream from the server to the
+// client which first delivers the htlcs that are currently stuck, followed by
+// every htlc that becomes stuck.
+func (s *Server) SubscribeStuckHtlcs(_ *SubscribeStuckHtlcsRequest,
+	stream Router_SubscribeStuckHtlcsServer) error {
+
+	if s.cfg.RouterBackend.SubscribeStuckHtlcs == nil {
+		return errStuckHtlcsDisabled
+	}
+
+	// Subscribe before fetching the currently stuck htlcs, so that no
+	// htlc that becomes stuck in between is missed.
+	stuckClient, err := s.cfg.RouterBackend.SubscribeStuckHtlcs()
+	if err != nil {
+		return err
+	}
+	defer stuckClient.Cancel()
+
+	// Keep track of the htlcs that were sent initially, as they may be
+	// delivered by the subscription as well.
+	sent := make(map[htlcswitch.CircuitKey]struct{})
+	for _, htlc := range s.cfg.RouterBackend.StuckHtlcs() {
+		err := stream.Send(marshallStuckHtlc(&htlc))
+		if err != nil {
+			return err
+		}
+
+		sent[htlc.Outgoing] = struct{}{}
+	}
+
+	for {
+		select {
+		case update := <-stuckClient.Updates():
+			htlc, ok := update.(htlcswitch.StuckHtlc)
+			if !ok {
+				return fmt.Errorf("unexpected stuck htlc "+
+					"update: %T", update)
+			}
+
+			if _, ok := sent[htlc.Outgoing]; ok {
+				continue
+			}
+
+			err := stream.Send(marshallStuckHtlc(&htlc))
+			if err != nil {
+				return err
+			}
+
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+
+		case <-stuckClient.Quit():
+			return errors.New("stuck htlc subscription terminated")
+
+		case <-s.quit:
+			return errServerShuttingDown
+		}
+	}
+}
+
+// marshallStuckHtlc converts a stuck htlc to its rpc representation.
+func marshallStuckHtlc(htlc *htlcswitch.StuckHtlc) *StuckHtlc {
+	return &StuckHtlc{
+		IncomingCircuitKey: &CircuitKey{
+			ChanId: htlc.Incoming.ChanID.ToUint64(),
+			HtlcId: htlc.Incoming.HtlcID,
+		},
+		OutgoingCircuitKey: &CircuitKey{
+			ChanId: htlc.Outgoing.ChanID.ToUint64(),
+			HtlcId: htlc.Outgoing.HtlcID,
+		},
+		PaymentHash:     htlc.PaymentHash[:],
+		IncomingAmtMsat: uint64(htlc.IncomingAmount),
+		OutgoingAmtMsat: uint64(htlc.OutgoingAmount),
+		FirstSeenNs:     htlc.FirstSeen.UnixNano(),
+		Remediated:      htlc.Remediated,
+	}
+}
+
 // HtlcInterceptor is a bidirectional stream for streaming interception
 // requests to the caller.
 // Upon connection, it does the following:
@@ -1576,6 +1839,27 @@ func (s *Server) HtlcInterceptor(stream Router_HtlcInterceptorServer) error {
 	// Run the forward interceptor.
 	return newForwardInterceptor(
 		s.cfg.RouterBackend.InterceptableForwarder, stream,
+		s.cfg.InterceptorQueueSize,
+	).run()
+}
+
+// HtlcInterceptorBatched is a bidirectional stream for streaming interception
+// requests to the caller, which receives many resolutions per message and
+// acknowledges the resolutions that request it. It shares the single
+// interceptor slot with HtlcInterceptor.
+func (s *Server) HtlcInterceptorBatched(
+	stream Router_HtlcInterceptorBatchedServer) error {
+
+	// We ensure there is only one interceptor at a time.
+	if !atomic.CompareAndSwapInt32(&s.forwardInterceptorActive, 0, 1) {
+		return ErrInterceptorAlreadyExists
+	}
+	defer atomic.CompareAndSwapInt32(&s.forwardInterceptorActive, 1, 0)
+
+	// Run the forward interceptor.
+	return newBatchedForwardInterceptor(
+		s.cfg.RouterBackend.InterceptableForwarder, stream,
+		s.cfg.InterceptorQueueSize,
 	).run()
 }
 
@@ -1738,3 +2022,181 @@ func (s *Server) UpdateChanStatus(_ context.Context,
 	}
 	return &UpdateChanStatusResponse{}, nil
 }
+
+// DustExposure returns the dust exposure of the channels that have an active
+// link, or of a single channel if its id is set.
+func (s *Server) DustExposure(_ context.Context,
+	req *DustExposureRequest) (*DustExposureResponse, error) {
+
+	resp := &DustExposureResponse{}
+	for _, exposure := range s.cfg.RouterBackend.DustExposures() {
+		chanID := exposure.ShortChanID.ToUint64()
+		if req.ChanId != 0 && req.ChanId != chanID {
+			continue
+		}
+
+		commit, mailbox := exposure.CommitDust, exposure.MailboxDust
+		resp.Channels = append(resp.Channels, &ChannelDustExposure{
+			ChanId:                chanID,
+			LocalCommitDustMsat:   uint64(commit.Local),
+			RemoteCommitDustMsat:  uint64(commit.Remote),
+			LocalMailboxDustMsat:  uint64(mailbox.Local),
+			RemoteMailboxDustMsat: uint64(mailbox.Remote),
+			MaxFeeExposureMsat:    uint64(exposure.MaxFeeExposure),
+			Exceeded:              exposure.Exceeded(),
+		})
+	}
+
+	if req.ChanId != 0 && len(resp.Channels) == 0 {
+		return nil, fmt.Errorf("no active link for channel %v",
+			lnwire.NewShortChanIDFromInt(req.ChanId))
+	}
+
+	return resp, nil
+}
+
+// UpdateMaxFeeExposure updates the threshold after which new dust htlcs are
+// rejected.
+func (s *Server) UpdateMaxFeeExposure(_ context.Context,
+	req *UpdateMaxFeeExposureRequest) (*UpdateMaxFeeExposureResponse,
+	error) {
+
+	threshold := lnwire.MilliSatoshi(req.MaxFeeExposureMsat)
+	err := s.cfg.RouterBackend.UpdateMaxFeeExposure(threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpdateMaxFeeExposureResponse{}, nil
+}
+
+// chanIDFromChanPoint returns the channel id of the channel with the given
+// channel point.
+func chanIDFromChanPoint(chanPoint *lnrpc.ChannelPoint) (lnwire.ChannelID,
+	error) {
+
+	if chanPoint == nil {
+		return lnwire.ChannelID{}, errors.New("chan_point must be set")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
+	if err != nil {
+		return lnwire.ChannelID{}, err
+	}
+
+	outPoint := wire.NewOutPoint(txid, chanPoint.OutputIndex)
+
+	return lnwire.NewChanIDFromOutPoint(*outPoint), nil
+}
+
+// marshallFwdPkgState converts a forwarding package state to its rpc
+// representation.
+func marshallFwdPkgState(state channeldb.FwdState) (ForwardingPackageState,
+	error) {
+
+	switch state {
+	case channeldb.FwdStateLockedIn:
+		return ForwardingPackageState_FWD_PKG_LOCKED_IN, nil
+
+	case channeldb.FwdStateProcessed:
+		return ForwardingPackageState_FWD_PKG_PROCESSED, nil
+
+	case channeldb.FwdStateCompleted:
+		return ForwardingPackageState_FWD_PKG_COMPLETED, nil
+
+	default:
+		return 0, fmt.Errorf("unknown forwarding package state: %v",
+			state)
+	}
+}
+
+// ForwardingPackages returns the forwarding packages of a channel that haven't
+// been removed yet.
+func (s *Server) ForwardingPackages(_ context.Context,
+	req *ForwardingPackagesRequest) (*ForwardingPackagesResponse, error) {
+
+	chanID, err := chanIDFromChanPoint(req.ChanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs, err := s.cfg.RouterBackend.ForwardingPackages(chanID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ForwardingPackagesResponse{}
+	for _, pkg := range pkgs {
+		state, err := marshallFwdPkgState(pkg.State)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Packages = append(resp.Packages, &ForwardingPackage{
+			SourceChanId:        pkg.Source.ToUint64(),
+			Height:              pkg.Height,
+			State:               state,
+			NumAdds:             uint32(pkg.NumAdds),
+			NumForwardedAdds:    uint32(pkg.NumForwardedAdds),
+			NumAckedAdds:        uint32(pkg.NumAckedAdds),
+			NumSettleFails:      uint32(pkg.NumSettleFails),
+			NumAckedSettleFails: uint32(pkg.NumAckedSettleFails),
+		})
+	}
+
+	return resp, nil
+}
+
+// ReprocessForwardingPackage replays a single forwarding package of a channel.
+func (s *Server) ReprocessForwardingPackage(ctx context.Context,
+	req *ReprocessForwardingPackageRequest) (
+	*ReprocessForwardingPackageResponse, error) {
+
+	chanID, err := chanIDFromChanPoint(req.ChanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Reprocessing forwarding package at height %v of "+
+		"channel %v", req.Height, chanID)
+
+	err = s.cfg.RouterBackend.ReprocessForwardingPackage(
+		ctx, chanID, req.Height,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReprocessForwardingPackageResponse{}, nil
+}
+
+// ChannelReputation returns the reputation of our incoming channels in the
+// experimental htlc endorsement scheme, or of a single channel if its id is
+// set.
+func (s *Server) ChannelReputation(_ context.Context,
+	req *ChannelReputationRequest) (*ChannelReputationResponse, error) {
+
+	var reputations []htlcswitch.ChannelReputation
+	if req.ChanId != 0 {
+		scid := lnwire.NewShortChanIDFromInt(req.ChanId)
+		reputations = []htlcswitch.ChannelReputation{
+			s.cfg.RouterBackend.ChannelReputation(scid),
+		}
+	} else {
+		reputations = s.cfg.RouterBackend.ChannelReputations()
+	}
+
+	resp := &ChannelReputationResponse{}
+	for _, r := range reputations {
+		resp.Channels = append(resp.Channels,
+			&IncomingChannelReputation{
+				ChanId:           r.ShortChanID.ToUint64(),
+				ReputationMsat:   r.Reputation,
+				InFlightRiskMsat: uint64(r.InFlightRisk),
+				NumInFlight:      uint32(r.NumInFlight),
+			},
+		)
+	}
+
+	return resp, nil
+}
@@ -2,18 +2,27 @@ package routerrpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type streamMock struct {
@@ -400,3 +409,448 @@ func TestIsLsp(t *testing.T) {
 		})
 	}
 }
+
+// TestPaymentStatusError tests that the errors that prevent a payment from
+// being initiated carry structured error details.
+func TestPaymentStatusError(t *testing.T) {
+	t.Parallel()
+
+	hash := lntypes.Hash{1, 2, 3}
+
+	testCases := []struct {
+		err       error
+		grpcCode  codes.Code
+		code      lnrpc.ErrorCode
+		retryable bool
+	}{
+		{
+			err:      channeldb.ErrPaymentExists,
+			grpcCode: codes.AlreadyExists,
+			code:     lnrpc.ErrCodePaymentExists,
+		},
+		{
+			err:       channeldb.ErrPaymentInFlight,
+			grpcCode:  codes.AlreadyExists,
+			code:      lnrpc.ErrCodePaymentInFlight,
+			retryable: true,
+		},
+		{
+			err:      channeldb.ErrAlreadyPaid,
+			grpcCode: codes.AlreadyExists,
+			code:     lnrpc.ErrCodeAlreadyPaid,
+		},
+		{
+			err: fmt.Errorf("%w: hop %v: invalid",
+				routing.ErrInvalidHopCustomRecords, node1),
+			grpcCode: codes.InvalidArgument,
+			code:     lnrpc.ErrCodeInvalidCustomRecords,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := paymentStatusError(hash, tc.err)
+		require.Equal(t, tc.grpcCode, status.Code(err))
+
+		details, ok := lnrpc.ErrorDetailsFromError(err)
+		require.True(t, ok)
+		require.Equal(t, tc.code, details.Code)
+		require.Equal(t, hash.String(), details.PaymentHash)
+		require.Equal(t, tc.retryable, details.Retryable)
+		require.Empty(t, details.ChannelPoint)
+	}
+
+	// Other errors are returned unchanged and don't carry any details.
+	errOther := errors.New("other")
+	require.Equal(t, errOther, paymentStatusError(hash, errOther))
+
+	_, ok := lnrpc.ErrorDetailsFromError(errOther)
+	require.False(t, ok)
+}
+
+// TestEstimateRouteFeeSimulate tests that a simulated fee estimate to a
+// destination returns the fees and success probability of the simulation.
+func TestEstimateRouteFeeSimulate(t *testing.T) {
+	t.Parallel()
+
+	destKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	dest := destKey.PubKey().SerializeCompressed()
+
+	backend := &RouterBackend{
+		SelfNode:              route.Vertex{1},
+		MaxTotalTimelock:      1000,
+		DefaultFinalCltvDelta: 40,
+		EstimatePayment: func(p *routing.LightningPayment) (
+			*routing.PaymentEstimate, error) {
+
+			require.Equal(t, dest, p.Target[:])
+			require.EqualValues(t, 50_000_000, p.Amount)
+			require.NotEqual(t, [32]byte{}, p.Identifier())
+
+			return &routing.PaymentEstimate{
+				TotalFees:     1500,
+				TotalTimeLock: 300,
+				Probability:   0.75,
+			}, nil
+		},
+	}
+	server := &Server{cfg: &Config{RouterBackend: backend}}
+
+	resp, err := server.EstimateRouteFee(
+		context.Background(), &RouteFeeRequest{
+			Dest:     dest,
+			AmtSat:   50_000,
+			Simulate: true,
+		},
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, 1500, resp.RoutingFeeMsat)
+	require.EqualValues(t, 300, resp.TimeLockDelay)
+	require.Equal(t, 0.75, resp.SuccessProb)
+	require.Equal(
+		t, lnrpc.PaymentFailureReason_FAILURE_REASON_NONE,
+		resp.FailureReason,
+	)
+
+	// The destination is still validated.
+	_, err = server.EstimateRouteFee(
+		context.Background(), &RouteFeeRequest{
+			Dest:     dest[:32],
+			AmtSat:   50_000,
+			Simulate: true,
+		},
+	)
+	require.Error(t, err)
+}
+
+// TestProbeHistory tests that the results of the background prober are
+// returned by ProbeHistory, and that an error is returned if probing is
+// disabled.
+func TestProbeHistory(t *testing.T) {
+	t.Parallel()
+
+	backend := &RouterBackend{}
+	server := &Server{cfg: &Config{RouterBackend: backend}}
+
+	_, err := server.ProbeHistory(
+		context.Background(), &ProbeHistoryRequest{},
+	)
+	require.ErrorIs(t, err, errProbingDisabled)
+
+	reason := channeldb.FailureReasonPaymentDetails
+	timestamp := time.Unix(1_700_000_000, 0)
+	backend.ProbeHistory = func() []routing.ProbeResult {
+		return []routing.ProbeResult{{
+			Target:        route.Vertex{1},
+			Amount:        100_000,
+			Timestamp:     timestamp,
+			Duration:      2 * time.Second,
+			Reached:       true,
+			FailureReason: &reason,
+			Err:           reason,
+		}, {
+			Target:    route.Vertex{2},
+			Amount:    100_000,
+			Timestamp: timestamp.Add(time.Minute),
+			Err:       errors.New("unexpected"),
+		}}
+	}
+
+	resp, err := server.ProbeHistory(
+		context.Background(), &ProbeHistoryRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Results, 2)
+
+	reached := resp.Results[0]
+	target := route.Vertex{1}
+	require.Equal(t, target[:], reached.Target)
+	require.EqualValues(t, 100_000, reached.AmtMsat)
+	require.Equal(t, timestamp.UnixNano(), reached.TimestampNs)
+	require.EqualValues(t, 2000, reached.DurationMs)
+	require.True(t, reached.Reached)
+	require.Equal(
+		t, "FAILURE_REASON_INCORRECT_PAYMENT_DETAILS",
+		reached.FailureReason.String(),
+	)
+
+	failed := resp.Results[1]
+	require.False(t, failed.Reached)
+	require.Equal(
+		t, lnrpc.PaymentFailureReason_FAILURE_REASON_NONE,
+		failed.FailureReason,
+	)
+	require.Equal(t, "unexpected", failed.Error)
+}
+
+type stuckHtlcStreamMock struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *StuckHtlc
+}
+
+func (m *stuckHtlcStreamMock) Context() context.Context {
+	return m.ctx
+}
+
+func (m *stuckHtlcStreamMock) Send(htlc *StuckHtlc) error {
+	m.sent <- htlc
+	return nil
+}
+
+// TestSubscribeStuckHtlcs tests that the currently stuck htlcs are sent before
+// the htlcs that become stuck, that htlcs aren't sent twice, and that an error
+// is returned if the detection of stuck htlcs is disabled.
+func TestSubscribeStuckHtlcs(t *testing.T) {
+	t.Parallel()
+
+	backend := &RouterBackend{}
+	server := &Server{
+		cfg:  &Config{RouterBackend: backend},
+		quit: make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := &stuckHtlcStreamMock{
+		ctx:  ctx,
+		sent: make(chan *StuckHtlc, 10),
+	}
+
+	err := server.SubscribeStuckHtlcs(&SubscribeStuckHtlcsRequest{}, stream)
+	require.ErrorIs(t, err, errStuckHtlcsDisabled)
+
+	ntfnServer := subscribe.NewServer()
+	require.NoError(t, ntfnServer.Start())
+	t.Cleanup(func() {
+		require.NoError(t, ntfnServer.Stop())
+	})
+
+	stuckHtlc := func(htlcID uint64) htlcswitch.StuckHtlc {
+		return htlcswitch.StuckHtlc{
+			Incoming: htlcswitch.CircuitKey{
+				ChanID: lnwire.NewShortChanIDFromInt(1),
+				HtlcID: htlcID,
+			},
+			Outgoing: htlcswitch.CircuitKey{
+				ChanID: lnwire.NewShortChanIDFromInt(2),
+				HtlcID: htlcID,
+			},
+			PaymentHash:    [32]byte{byte(htlcID)},
+			IncomingAmount: 1000,
+			OutgoingAmount: 900,
+			FirstSeen:      time.Unix(1_700_000_000, 0),
+			Remediated:     true,
+		}
+	}
+
+	subscribed := make(chan struct{})
+	backend.SubscribeStuckHtlcs = func() (*subscribe.Client, error) {
+		defer close(subscribed)
+		return ntfnServer.Subscribe()
+	}
+	backend.StuckHtlcs = func() []htlcswitch.StuckHtlc {
+		return []htlcswitch.StuckHtlc{stuckHtlc(1)}
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.SubscribeStuckHtlcs(
+			&SubscribeStuckHtlcsRequest{}, stream,
+		)
+	}()
+
+	<-subscribed
+
+	// The htlc that was stuck already is delivered by the subscription as
+	// well, but must only be sent once.
+	require.NoError(t, ntfnServer.SendUpdate(stuckHtlc(1)))
+	require.NoError(t, ntfnServer.SendUpdate(stuckHtlc(2)))
+
+	for _, htlcID := range []uint64{1, 2} {
+		select {
+		case htlc := <-stream.sent:
+			require.Equal(t, htlcID, htlc.OutgoingCircuitKey.HtlcId)
+			require.EqualValues(
+				t, 2, htlc.OutgoingCircuitKey.ChanId,
+			)
+			require.EqualValues(
+				t, 1, htlc.IncomingCircuitKey.ChanId,
+			)
+			require.Equal(t, byte(htlcID), htlc.PaymentHash[0])
+			require.EqualValues(t, 1000, htlc.IncomingAmtMsat)
+			require.EqualValues(t, 900, htlc.OutgoingAmtMsat)
+			require.Equal(
+				t, time.Unix(1_700_000_000, 0).UnixNano(),
+				htlc.FirstSeenNs,
+			)
+			require.True(t, htlc.Remediated)
+
+		case <-time.After(time.Second):
+			t.Fatalf("stuck htlc %v not sent", htlcID)
+		}
+	}
+
+	cancel()
+
+	select {
+	case err := <-errChan:
+		require.ErrorIs(t, err, context.Canceled)
+
+	case <-time.After(time.Second):
+		t.Fatal("subscription not terminated")
+	}
+
+	require.Empty(t, stream.sent)
+}
+
+// TestDustExposure tests that the dust exposure of all channels, or of a
+// single channel, is returned.
+func TestDustExposure(t *testing.T) {
+	t.Parallel()
+
+	exposure := func(chanID uint64) htlcswitch.DustExposure {
+		return htlcswitch.DustExposure{
+			ShortChanID: lnwire.NewShortChanIDFromInt(chanID),
+			CommitDust: lntypes.Dual[lnwire.MilliSatoshi]{
+				Local:  1000,
+				Remote: 2000,
+			},
+			MailboxDust: lntypes.Dual[lnwire.MilliSatoshi]{
+				Local:  500,
+				Remote: 0,
+			},
+			MaxFeeExposure: 1200,
+		}
+	}
+
+	backend := &RouterBackend{
+		DustExposures: func() []htlcswitch.DustExposure {
+			return []htlcswitch.DustExposure{
+				exposure(1), exposure(2),
+			}
+		},
+	}
+	server := &Server{cfg: &Config{RouterBackend: backend}}
+
+	resp, err := server.DustExposure(
+		context.Background(), &DustExposureRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Channels, 2)
+
+	resp, err = server.DustExposure(
+		context.Background(), &DustExposureRequest{ChanId: 2},
+	)
+	require.NoError(t, err)
+	require.Equal(t, []*ChannelDustExposure{{
+		ChanId:                2,
+		LocalCommitDustMsat:   1000,
+		RemoteCommitDustMsat:  2000,
+		LocalMailboxDustMsat:  500,
+		RemoteMailboxDustMsat: 0,
+		MaxFeeExposureMsat:    1200,
+		Exceeded:              true,
+	}}, resp.Channels)
+
+	// A channel without an active link is an error.
+	_, err = server.DustExposure(
+		context.Background(), &DustExposureRequest{ChanId: 3},
+	)
+	require.Error(t, err)
+}
+
+// TestRebalanceQueryOnly tests that a rebalance finds a circular route from
+// the outgoing channel back to us over the peer of the incoming channel.
+func TestRebalanceQueryOnly(t *testing.T) {
+	t.Parallel()
+
+	var (
+		self = route.Vertex{1}
+		peer = route.Vertex{2}
+	)
+
+	backend := &RouterBackend{
+		SelfNode:         self,
+		MaxTotalTimelock: 1000,
+		MissionControl:   &mockMissionControl{},
+		FindRoute: func(req *routing.RouteRequest) (*route.Route,
+			float64, error) {
+
+			require.Equal(t, self, req.Target)
+			require.Equal(t, peer, *req.Restrictions.LastHop)
+			require.Equal(
+				t, []uint64{100},
+				req.Restrictions.OutgoingChannelIDs,
+			)
+			require.EqualValues(
+				t, 1_000, req.Restrictions.FeeLimit,
+			)
+
+			rt, err := route.NewRouteFromHops(
+				req.Amount, 144, req.Source, []*route.Hop{
+					{ChannelID: 100, PubKeyBytes: peer},
+					{ChannelID: 200, PubKeyBytes: self},
+				},
+			)
+
+			return rt, 1, err
+		},
+		FetchChannelCapacity: func(uint64) (btcutil.Amount, error) {
+			return 1_000_000, nil
+		},
+		ChannelBalance: func(chanID uint64) (route.Vertex,
+			btcutil.Amount, error) {
+
+			switch chanID {
+			case 100:
+				return route.Vertex{3}, 600_000, nil
+
+			case 200:
+				return peer, 100_000, nil
+
+			default:
+				return route.Vertex{}, 0, errors.New("unknown")
+			}
+		},
+		AddInvoice: func(context.Context, string,
+			lnwire.MilliSatoshi) (lntypes.Hash, [32]byte, error) {
+
+			t.Fatal("no invoice expected when querying the route")
+
+			return lntypes.Hash{}, [32]byte{}, nil
+		},
+	}
+	server := &Server{cfg: &Config{RouterBackend: backend}}
+
+	req := &RebalanceRequest{
+		OutgoingChanId: 100,
+		IncomingChanId: 200,
+		AmtSat:         50_000,
+		FeeLimitSat:    1,
+		QueryOnly:      true,
+	}
+	resp, err := server.Rebalance(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, resp.Route.Hops, 2)
+	require.Empty(t, resp.PaymentHash)
+	require.Equal(t, []*RebalanceChannel{{
+		ChanId:             100,
+		LocalBalanceBefore: 600_000,
+		LocalBalanceAfter:  600_000,
+	}, {
+		ChanId:             200,
+		LocalBalanceBefore: 100_000,
+		LocalBalanceAfter:  100_000,
+	}}, resp.Channels)
+
+	// Rebalancing a channel into itself or an unknown channel fails.
+	req.IncomingChanId = 100
+	_, err = server.Rebalance(context.Background(), req)
+	require.Error(t, err)
+
+	req.IncomingChanId = 300
+	_, err = server.Rebalance(context.Background(), req)
+	require.Error(t, err)
+}
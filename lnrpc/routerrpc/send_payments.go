@@ -0,0 +1,324 @@
+package routerrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+const (
+	// DefaultMaxParallelBatchPayments is the default maximum number of
+	// payments of a batch that are in flight at the same time.
+	DefaultMaxParallelBatchPayments = 10
+
+	// MaxBatchPayments is the maximum number of payments that can be
+	// submitted in a single batch.
+	MaxBatchPayments = 1000
+)
+
+var (
+	// ErrEmptyPaymentBatch is returned when a batch without payments is
+	// submitted.
+	ErrEmptyPaymentBatch = errors.New("payment batch is empty")
+
+	// ErrPaymentBatchTooLarge is returned when a batch with more than
+	// MaxBatchPayments payments is submitted.
+	ErrPaymentBatchTooLarge = fmt.Errorf("payment batch exceeds maximum "+
+		"of %d payments", MaxBatchPayments)
+)
+
+// BatchPaymentUpdate is a status update of a payment of a batch. The updates
+// of the payments of a batch are interleaved.
+type BatchPaymentUpdate struct {
+	// Index is the index of the payment request within the batch.
+	Index int
+
+	// Payment is the latest state of the payment. It is nil if the payment
+	// couldn't be initiated.
+	Payment *lnrpc.Payment
+
+	// Err is the error the payment couldn't be initiated with.
+	Err error
+}
+
+// BatchPaymentSummary aggregates the outcome of the payments of a batch.
+type BatchPaymentSummary struct {
+	// NumSucceeded is the number of payments that succeeded.
+	NumSucceeded int
+
+	// NumFailed is the number of payments that were initiated, but failed.
+	NumFailed int
+
+	// FailureReasons counts the failed payments by their failure reason.
+	FailureReasons map[lnrpc.PaymentFailureReason]int
+
+	// InitErrors holds the errors of the payments that couldn't be
+	// initiated, keyed by their index within the batch.
+	InitErrors map[int]error
+}
+
+// batchPaymentFunc initiates the payment with the given index of a batch and
+// reports its state updates until it reaches a final state.
+type batchPaymentFunc func(ctx context.Context, index int,
+	update func(*lnrpc.Payment) error) error
+
+// SendPayments sends a batch of payments and streams the interleaved status
+// updates of all payments. At most MaxParallelBatchPayments payments are in
+// flight at the same time. A failure of one payment doesn't affect the other
+// payments of the batch, the failures are aggregated in the summary that ends
+// the stream instead. Ending the stream stops the delivery of updates, and
+// cancels the payments that were requested to be cancelable.
+func (s *Server) SendPayments(req *SendPaymentsRequest,
+	stream Router_SendPaymentsServer) error {
+
+	pay := func(ctx context.Context, index int,
+		update func(*lnrpc.Payment) error) error {
+
+		return s.sendBatchPayment(ctx, req.Payments[index], update)
+	}
+
+	send := func(update *BatchPaymentUpdate) error {
+		status := marshallBatchPaymentUpdate(update)
+
+		return stream.Send(&SendPaymentsResponse{
+			Update: &SendPaymentsResponse_PaymentStatus{
+				PaymentStatus: status,
+			},
+		})
+	}
+
+	summary, err := runPaymentBatch(
+		stream.Context(), len(req.Payments),
+		s.cfg.MaxParallelBatchPayments, pay, send,
+	)
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&SendPaymentsResponse{
+		Update: &SendPaymentsResponse_Summary{
+			Summary: marshallBatchPaymentSummary(summary),
+		},
+	})
+}
+
+// marshallBatchPaymentUpdate converts a status update of a payment of a batch
+// to its rpc representation.
+func marshallBatchPaymentUpdate(
+	update *BatchPaymentUpdate) *BatchPaymentStatus {
+
+	status := &BatchPaymentStatus{
+		Index:   uint32(update.Index),
+		Payment: update.Payment,
+	}
+	if update.Err != nil {
+		status.Error = update.Err.Error()
+	}
+
+	return status
+}
+
+// marshallBatchPaymentSummary converts the summary of a batch to its rpc
+// representation. The failure reasons are sorted to make the summary
+// deterministic.
+func marshallBatchPaymentSummary(
+	summary *BatchPaymentSummary) *BatchPaymentOutcome {
+
+	outcome := &BatchPaymentOutcome{
+		NumSucceeded: uint32(summary.NumSucceeded),
+		NumFailed:    uint32(summary.NumFailed),
+		InitErrors:   make(map[uint32]string, len(summary.InitErrors)),
+	}
+
+	for reason, count := range summary.FailureReasons {
+		outcome.FailureReasons = append(
+			outcome.FailureReasons, &BatchFailureReasonCount{
+				Reason: reason,
+				Count:  uint32(count),
+			},
+		)
+	}
+	sort.Slice(outcome.FailureReasons, func(i, j int) bool {
+		return outcome.FailureReasons[i].Reason <
+			outcome.FailureReasons[j].Reason
+	})
+
+	for index, err := range summary.InitErrors {
+		outcome.InitErrors[uint32(index)] = err.Error()
+	}
+
+	return outcome
+}
+
+// sendBatchPayment initiates a payment of a batch and reports its updates.
+func (s *Server) sendBatchPayment(ctx context.Context, req *SendPaymentRequest,
+	update func(*lnrpc.Payment) error) error {
+
+	payment, err := s.cfg.RouterBackend.extractIntentFromSendRequest(req)
+	if err != nil {
+		return err
+	}
+
+	paySession, shardTracker, err := s.cfg.Router.PreparePayment(payment)
+	if err != nil {
+		return err
+	}
+
+	// Subscribe to the payment before sending it to make sure we won't
+	// miss events.
+	sub, err := s.subscribePayment(payment.Identifier())
+	if err != nil {
+		return err
+	}
+
+	// Just like for a single payment, the payment is only bound to the
+	// context of the batch if it was requested to be cancelable.
+	payCtx := context.Background()
+	if req.Cancelable {
+		payCtx = ctx
+	}
+
+	s.cfg.Router.SendPaymentAsync(payCtx, payment, paySession, shardTracker)
+
+	return s.trackPaymentStream(ctx, sub, req.NoInflightUpdates, update)
+}
+
+// runPaymentBatch runs the payments of a batch with at most maxParallel of
+// them in flight at the same time, and aggregates their outcome.
+func runPaymentBatch(ctx context.Context, numPayments, maxParallel int,
+	pay batchPaymentFunc,
+	send func(*BatchPaymentUpdate) error) (*BatchPaymentSummary, error) {
+
+	switch {
+	case numPayments == 0:
+		return nil, ErrEmptyPaymentBatch
+
+	case numPayments > MaxBatchPayments:
+		return nil, ErrPaymentBatchTooLarge
+	}
+
+	if maxParallel <= 0 {
+		maxParallel = DefaultMaxParallelBatchPayments
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		sendErr error
+		summary = &BatchPaymentSummary{
+			FailureReasons: make(
+				map[lnrpc.PaymentFailureReason]int,
+			),
+			InitErrors: make(map[int]error),
+		}
+	)
+
+	// sendUpdate serializes the updates of all payments, as they are
+	// written to a single stream. If the update can't be delivered, the
+	// whole batch is aborted.
+	sendUpdate := func(update *BatchPaymentUpdate) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if sendErr != nil {
+			return sendErr
+		}
+
+		if err := send(update); err != nil {
+			sendErr = err
+			cancel()
+
+			return err
+		}
+
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallel)
+
+	for i := 0; i < numPayments; i++ {
+		select {
+		case sem <- struct{}{}:
+
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			var last *lnrpc.Payment
+			update := func(payment *lnrpc.Payment) error {
+				last = payment
+
+				return sendUpdate(&BatchPaymentUpdate{
+					Index:   index,
+					Payment: payment,
+				})
+			}
+
+			err := pay(ctx, index, update)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			// The payment couldn't be initiated.
+			case err != nil && last == nil:
+				log.Debugf("Unable to initiate payment %d of "+
+					"batch: %v", index, err)
+
+				summary.InitErrors[index] = err
+
+			case last == nil:
+
+			case last.Status == lnrpc.Payment_SUCCEEDED:
+				summary.NumSucceeded++
+
+			case last.Status == lnrpc.Payment_FAILED:
+				summary.NumFailed++
+				summary.FailureReasons[last.FailureReason]++
+			}
+
+			// Report initiation errors to the client as well, so it
+			// doesn't have to wait for the summary.
+			if err != nil && last == nil && sendErr == nil {
+				sendErr = send(&BatchPaymentUpdate{
+					Index: index,
+					Err:   err,
+				})
+				if sendErr != nil {
+					cancel()
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	// All payments returned, so the error can be read without holding
+	// the mutex.
+	if sendErr != nil {
+		return nil, sendErr
+	}
+
+	// If the batch was canceled, payments may not have reached a final
+	// state, so the summary would be incomplete.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,165 @@
+package routerrpc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRunPaymentBatch tests that the payments of a batch are run with limited
+// concurrency, and that their outcome is aggregated.
+func TestRunPaymentBatch(t *testing.T) {
+	t.Parallel()
+
+	const (
+		numPayments = 20
+		maxParallel = 3
+		noRoute     = lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE
+	)
+
+	var inFlight, maxInFlight int32
+	pay := func(_ context.Context, index int,
+		update func(*lnrpc.Payment) error) error {
+
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			cur := atomic.LoadInt32(&maxInFlight)
+			if n <= cur {
+				break
+			}
+			if atomic.CompareAndSwapInt32(&maxInFlight, cur, n) {
+				break
+			}
+		}
+
+		// Every fifth payment can't be initiated.
+		if index%5 == 0 {
+			return errors.New("invalid payment request")
+		}
+
+		err := update(&lnrpc.Payment{
+			Status: lnrpc.Payment_IN_FLIGHT,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Every other payment fails.
+		final := &lnrpc.Payment{Status: lnrpc.Payment_SUCCEEDED}
+		if index%2 == 0 {
+			final = &lnrpc.Payment{
+				Status:        lnrpc.Payment_FAILED,
+				FailureReason: noRoute,
+			}
+		}
+
+		return update(final)
+	}
+
+	var updates []*BatchPaymentUpdate
+	send := func(update *BatchPaymentUpdate) error {
+		updates = append(updates, update)
+		return nil
+	}
+
+	summary, err := runPaymentBatch(
+		context.Background(), numPayments, maxParallel, pay, send,
+	)
+	require.NoError(t, err)
+
+	require.LessOrEqual(t, maxInFlight, int32(maxParallel))
+
+	// Indexes 0, 5, 10 and 15 couldn't be initiated. Of the others, the
+	// even ones failed.
+	require.Len(t, summary.InitErrors, 4)
+	require.Equal(t, 8, summary.NumSucceeded)
+	require.Equal(t, 8, summary.NumFailed)
+	require.Equal(t, 8, summary.FailureReasons[noRoute])
+
+	// Every initiated payment reports two updates, the others report their
+	// error.
+	require.Len(t, updates, 16*2+4)
+	for _, update := range updates {
+		if update.Index%5 == 0 {
+			require.Error(t, update.Err)
+			continue
+		}
+
+		require.NotNil(t, update.Payment)
+	}
+}
+
+// TestRunPaymentBatchSendError tests that a batch is aborted once an update
+// can't be delivered.
+func TestRunPaymentBatchSendError(t *testing.T) {
+	t.Parallel()
+
+	pay := func(ctx context.Context, _ int,
+		update func(*lnrpc.Payment) error) error {
+
+		if err := update(&lnrpc.Payment{}); err != nil {
+			return err
+		}
+
+		<-ctx.Done()
+
+		return ctx.Err()
+	}
+
+	errSend := errors.New("stream closed")
+	send := func(*BatchPaymentUpdate) error {
+		return errSend
+	}
+
+	_, err := runPaymentBatch(context.Background(), 5, 2, pay, send)
+	require.ErrorIs(t, err, errSend)
+
+	_, err = runPaymentBatch(context.Background(), 0, 2, pay, send)
+	require.ErrorIs(t, err, ErrEmptyPaymentBatch)
+
+	_, err = runPaymentBatch(
+		context.Background(), MaxBatchPayments+1, 2, pay, send,
+	)
+	require.ErrorIs(t, err, ErrPaymentBatchTooLarge)
+}
+
+// TestMarshallBatchPaymentSummary tests that the summary of a batch is
+// converted to its rpc representation with sorted failure reasons.
+func TestMarshallBatchPaymentSummary(t *testing.T) {
+	t.Parallel()
+
+	const (
+		timeout = lnrpc.PaymentFailureReason_FAILURE_REASON_TIMEOUT
+		noRoute = lnrpc.PaymentFailureReason_FAILURE_REASON_NO_ROUTE
+	)
+
+	outcome := marshallBatchPaymentSummary(&BatchPaymentSummary{
+		NumSucceeded: 3,
+		NumFailed:    5,
+		FailureReasons: map[lnrpc.PaymentFailureReason]int{
+			noRoute: 4,
+			timeout: 1,
+		},
+		InitErrors: map[int]error{
+			7: errors.New("invalid payment request"),
+		},
+	})
+
+	require.EqualValues(t, 3, outcome.NumSucceeded)
+	require.EqualValues(t, 5, outcome.NumFailed)
+	require.Len(t, outcome.FailureReasons, 2)
+	require.Equal(t, timeout, outcome.FailureReasons[0].Reason)
+	require.EqualValues(t, 1, outcome.FailureReasons[0].Count)
+	require.Equal(t, noRoute, outcome.FailureReasons[1].Reason)
+	require.EqualValues(t, 4, outcome.FailureReasons[1].Count)
+	require.Equal(
+		t, map[uint32]string{7: "invalid payment request"},
+		outcome.InitErrors,
+	)
+}
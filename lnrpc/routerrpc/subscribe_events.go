@@ -9,13 +9,19 @@ import (
 	"github.com/lightningnetwork/lnd/lnrpc"
 )
 
+// MarshallHtlcEvent returns a rpc htlc event from a htlcswitch event.
+func MarshallHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
+	return rpcHtlcEvent(htlcEvent)
+}
+
 // rpcHtlcEvent returns a rpc htlc event from a htlcswitch event.
 func rpcHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
 	var (
-		key       htlcswitch.HtlcKey
-		timestamp time.Time
-		eventType *htlcswitch.HtlcEventType
-		event     isHtlcEvent_Event
+		key           htlcswitch.HtlcKey
+		correlationID htlcswitch.CircuitKey
+		timestamp     time.Time
+		eventType     *htlcswitch.HtlcEventType
+		event         isHtlcEvent_Event
 	)
 
 	switch e := htlcEvent.(type) {
@@ -27,15 +33,20 @@ func rpcHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
 		}
 
 		key = e.HtlcKey
+		correlationID = e.CorrelationID
 		eventType = &e.HtlcEventType
 		timestamp = e.Timestamp
 
 	case *htlcswitch.ForwardingFailEvent:
 		event = &HtlcEvent_ForwardFailEvent{
-			ForwardFailEvent: &ForwardFailEvent{},
+			ForwardFailEvent: &ForwardFailEvent{
+				Info:              rpcInfo(e.HtlcInfo),
+				OnchainResolution: e.Resolution,
+			},
 		}
 
 		key = e.HtlcKey
+		correlationID = e.CorrelationID
 		eventType = &e.HtlcEventType
 		timestamp = e.Timestamp
 
@@ -57,6 +68,7 @@ func rpcHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
 		}
 
 		key = e.HtlcKey
+		correlationID = e.CorrelationID
 		eventType = &e.HtlcEventType
 		timestamp = e.Timestamp
 
@@ -64,10 +76,12 @@ func rpcHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
 		event = &HtlcEvent_SettleEvent{
 			SettleEvent: &SettleEvent{
 				Preimage: e.Preimage[:],
+				Info:     rpcInfo(e.HtlcInfo),
 			},
 		}
 
 		key = e.HtlcKey
+		correlationID = e.CorrelationID
 		eventType = &e.HtlcEventType
 		timestamp = e.Timestamp
 
@@ -82,6 +96,7 @@ func rpcHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
 		key = htlcswitch.HtlcKey{
 			IncomingCircuit: e.CircuitKey,
 		}
+		correlationID = e.CorrelationID
 		timestamp = e.Timestamp
 
 	default:
@@ -95,6 +110,10 @@ func rpcHtlcEvent(htlcEvent interface{}) (*HtlcEvent, error) {
 		OutgoingHtlcId:    key.OutgoingCircuit.HtlcID,
 		TimestampNs:       uint64(timestamp.UnixNano()),
 		Event:             event,
+		CorrelationId: &CircuitKey{
+			ChanId: correlationID.ChanID.ToUint64(),
+			HtlcId: correlationID.HtlcID,
+		},
 	}
 
 	// Convert the htlc event type to a rpc event.
@@ -251,7 +270,12 @@ func rpcOutgoingFailure(failureDetail htlcswitch.OutgoingFailure) (
 	case htlcswitch.OutgoingFailureDownstreamHtlcAdd:
 		return FailureDetail_HTLC_ADD_FAILED, nil
 
-	case htlcswitch.OutgoingFailureForwardsDisabled:
+	// Forwards that are rejected by a forwarding rule are reported as
+	// disabled forwards, as they are both the result of the node's
+	// configuration.
+	case htlcswitch.OutgoingFailureForwardsDisabled,
+		htlcswitch.OutgoingFailureForwardingRule:
+
 		return FailureDetail_FORWARDS_DISABLED, nil
 
 	default:
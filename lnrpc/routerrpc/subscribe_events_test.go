@@ -0,0 +1,74 @@
+package routerrpc
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRpcHtlcEventCorrelation tests that the correlation id and amounts of
+// htlc events are exposed in their rpc representation.
+func TestRpcHtlcEventCorrelation(t *testing.T) {
+	t.Parallel()
+
+	incoming := htlcswitch.CircuitKey{
+		ChanID: lnwire.NewShortChanIDFromInt(1),
+		HtlcID: 2,
+	}
+	key := htlcswitch.HtlcKey{
+		IncomingCircuit: incoming,
+		OutgoingCircuit: htlcswitch.CircuitKey{
+			ChanID: lnwire.NewShortChanIDFromInt(3),
+			HtlcID: 4,
+		},
+	}
+	info := htlcswitch.HtlcInfo{
+		IncomingAmt: 1000,
+		OutgoingAmt: 900,
+	}
+	rpcCorrelation := &CircuitKey{
+		ChanId: incoming.ChanID.ToUint64(),
+		HtlcId: incoming.HtlcID,
+	}
+
+	settle, err := rpcHtlcEvent(&htlcswitch.SettleEvent{
+		HtlcKey:       key,
+		CorrelationID: incoming,
+		HtlcInfo:      info,
+		Preimage:      lntypes.Preimage{1},
+		HtlcEventType: htlcswitch.HtlcEventTypeForward,
+	})
+	require.NoError(t, err)
+	require.Equal(t, rpcCorrelation, settle.CorrelationId)
+	require.EqualValues(
+		t, 1000, settle.GetSettleEvent().Info.IncomingAmtMsat,
+	)
+	require.EqualValues(
+		t, 900, settle.GetSettleEvent().Info.OutgoingAmtMsat,
+	)
+
+	fail, err := rpcHtlcEvent(&htlcswitch.ForwardingFailEvent{
+		HtlcKey:       key,
+		CorrelationID: incoming,
+		HtlcInfo:      info,
+		Resolution:    true,
+		HtlcEventType: htlcswitch.HtlcEventTypeForward,
+	})
+	require.NoError(t, err)
+	require.Equal(t, rpcCorrelation, fail.CorrelationId)
+	require.True(t, fail.GetForwardFailEvent().OnchainResolution)
+	require.EqualValues(
+		t, 900, fail.GetForwardFailEvent().Info.OutgoingAmtMsat,
+	)
+
+	final, err := rpcHtlcEvent(&htlcswitch.FinalHtlcEvent{
+		CircuitKey:    incoming,
+		CorrelationID: incoming,
+		Settled:       true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, rpcCorrelation, final.CorrelationId)
+}
@@ -415,6 +415,19 @@ func (s *Server) SignOutputRaw(_ context.Context, in *SignReq) (*SignResp,
 			}
 		}
 
+		// Only the signature of a taproot script spend can commit to
+		// an annex.
+		if len(signDesc.Annex) > 0 {
+			if signMethod != input.TaprootScriptSpendSignMethod {
+				return nil, fmt.Errorf("annex not supported "+
+					"for sign method %v", signMethod)
+			}
+
+			if signDesc.Annex[0] != txscript.TaprootAnnexTag {
+				return nil, input.ErrInvalidAnnex
+			}
+		}
+
 		// If the users provided a double tweak, then we'll need to
 		// parse that out now to ensure their input is properly signed.
 		var tweakPrivKey *btcec.PrivateKey
@@ -436,6 +449,7 @@ func (s *Server) SignOutputRaw(_ context.Context, in *SignReq) (*SignResp,
 			DoubleTweak:   tweakPrivKey,
 			TapTweak:      signDesc.TapTweak,
 			WitnessScript: signDesc.WitnessScript,
+			Annex:         signDesc.Annex,
 			SignMethod:    signMethod,
 			Output: &wire.TxOut{
 				Value:    signDesc.Output.Value,
@@ -0,0 +1,18 @@
+package walletrpc
+
+import (
+	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/subscribe"
+)
+
+// AnchorBumpSource provides the fee bumps of our commitment txns that are
+// delegated to an external service.
+type AnchorBumpSource interface {
+	// PendingAnchorBumps returns the delegated anchor bumps of the
+	// commitment txns that haven't confirmed yet.
+	PendingAnchorBumps() []*contractcourt.AnchorBumpRequest
+
+	// SubscribeAnchorBumps returns a subscription that receives every new
+	// delegated anchor bump.
+	SubscribeAnchorBumps() (*subscribe.Client, error)
+}
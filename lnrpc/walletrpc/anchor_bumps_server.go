@@ -0,0 +1,132 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// ListDelegatedAnchorBumps returns the delegated fee bumps of our commitment
+// txns that haven't confirmed yet. They're only delegated if
+// forceclose.delegateanchorbumps is set.
+func (w *WalletKit) ListDelegatedAnchorBumps(_ context.Context,
+	_ *ListDelegatedAnchorBumpsRequest) (*ListDelegatedAnchorBumpsResponse,
+	error) {
+
+	reqs := w.cfg.AnchorBumps.PendingAnchorBumps()
+
+	bumps := make([]*DelegatedAnchorBump, 0, len(reqs))
+	for _, req := range reqs {
+		bump, err := marshalAnchorBump(req)
+		if err != nil {
+			return nil, err
+		}
+		bumps = append(bumps, bump)
+	}
+
+	return &ListDelegatedAnchorBumpsResponse{
+		Bumps: bumps,
+	}, nil
+}
+
+// SubscribeDelegatedAnchorBumps pushes every new delegated fee bump of our
+// commitment txns to the client until the stream is closed. The bumps that
+// were delegated while the client wasn't subscribed can be caught up with
+// ListDelegatedAnchorBumps.
+func (w *WalletKit) SubscribeDelegatedAnchorBumps(
+	_ *SubscribeDelegatedAnchorBumpsRequest,
+	stream WalletKit_SubscribeDelegatedAnchorBumpsServer) error {
+
+	client, err := w.cfg.AnchorBumps.SubscribeAnchorBumps()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case update := <-client.Updates():
+			req, ok := update.(*contractcourt.AnchorBumpRequest)
+			if !ok {
+				return fmt.Errorf("unexpected anchor bump "+
+					"update %T", update)
+			}
+
+			bump, err := marshalAnchorBump(req)
+			if err != nil {
+				return err
+			}
+
+			if err := stream.Send(bump); err != nil {
+				return err
+			}
+
+		case <-client.Quit():
+			return errors.New("anchor bumps shutting down")
+
+		case <-stream.Context().Done():
+			ctxErr := stream.Context().Err()
+			if errors.Is(ctxErr, context.Canceled) {
+				return nil
+			}
+			return ctxErr
+		}
+	}
+}
+
+// marshalAnchorBump converts a delegated anchor bump into its RPC format.
+func marshalAnchorBump(
+	req *contractcourt.AnchorBumpRequest) (*DelegatedAnchorBump, error) {
+
+	var rawCommitTx []byte
+	if req.CommitTx != nil {
+		var b bytes.Buffer
+		if err := req.CommitTx.Serialize(&b); err != nil {
+			return nil, err
+		}
+		rawCommitTx = b.Bytes()
+	}
+
+	// The template is turned into a PSBT with the anchor input finalized,
+	// so the service can fund it like any other PSBT.
+	unsignedTx := req.SpendTemplate.Copy()
+	unsignedTx.TxIn[0].Witness = nil
+
+	packet, err := psbt.NewFromUnsignedTx(unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	var witness bytes.Buffer
+	err = psbt.WriteTxWitness(&witness, req.SpendTemplate.TxIn[0].Witness)
+	if err != nil {
+		return nil, err
+	}
+	packet.Inputs[0].WitnessUtxo = req.AnchorOutput
+	packet.Inputs[0].FinalScriptWitness = witness.Bytes()
+
+	var template bytes.Buffer
+	if err := packet.Serialize(&template); err != nil {
+		return nil, err
+	}
+
+	return &DelegatedAnchorBump{
+		ChannelPoint:      lnrpc.MarshalOutPoint(&req.ChanPoint),
+		CommitTxid:        req.CommitTxid[:],
+		RawCommitTx:       rawCommitTx,
+		CommitFeeSat:      uint64(req.CommitFee),
+		CommitWeight:      uint64(req.CommitWeight),
+		AnchorOutpoint:    lnrpc.MarshalOutPoint(&req.AnchorOutPoint),
+		SpendTemplatePsbt: template.Bytes(),
+		DeadlineHeight:    uint32(req.DeadlineHeight.UnwrapOr(0)),
+		BudgetSat:         uint64(req.Budget),
+	}, nil
+}
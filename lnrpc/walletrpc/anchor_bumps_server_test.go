@@ -0,0 +1,98 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/contractcourt"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/stretchr/testify/require"
+)
+
+// mockAnchorBumps is an AnchorBumpSource with a fixed set of pending bumps.
+type mockAnchorBumps struct {
+	pending []*contractcourt.AnchorBumpRequest
+}
+
+func (m *mockAnchorBumps) PendingAnchorBumps() []*contractcourt.
+	AnchorBumpRequest {
+
+	return m.pending
+}
+
+func (m *mockAnchorBumps) SubscribeAnchorBumps() (*subscribe.Client,
+	error) {
+
+	return nil, nil
+}
+
+// TestListDelegatedAnchorBumps tests that the pending anchor bumps are
+// returned with a spend template PSBT whose anchor input is finalized.
+func TestListDelegatedAnchorBumps(t *testing.T) {
+	t.Parallel()
+
+	anchorOutPoint := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+	anchorOutput := &wire.TxOut{Value: 330, PkScript: []byte{0x51}}
+	witness := wire.TxWitness{{0x01, 0x02}, {0x03}}
+
+	template := wire.NewMsgTx(2)
+	template.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: anchorOutPoint,
+		Witness:          witness,
+	})
+
+	req := &contractcourt.AnchorBumpRequest{
+		ChanPoint:      wire.OutPoint{Hash: chainhash.Hash{1}},
+		CommitTxid:     anchorOutPoint.Hash,
+		CommitFee:      1000,
+		CommitWeight:   700,
+		AnchorOutPoint: anchorOutPoint,
+		AnchorOutput:   anchorOutput,
+		SpendTemplate:  template,
+		DeadlineHeight: fn.Some(int32(800)),
+		Budget:         5000,
+	}
+
+	w := &WalletKit{
+		cfg: &Config{
+			AnchorBumps: &mockAnchorBumps{
+				pending: []*contractcourt.AnchorBumpRequest{req},
+			},
+		},
+	}
+
+	resp, err := w.ListDelegatedAnchorBumps(
+		context.Background(), &ListDelegatedAnchorBumpsRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Bumps, 1)
+
+	bump := resp.Bumps[0]
+	require.Equal(t, anchorOutPoint.Hash[:], bump.CommitTxid)
+	require.Empty(t, bump.RawCommitTx)
+	require.EqualValues(t, 1000, bump.CommitFeeSat)
+	require.EqualValues(t, 700, bump.CommitWeight)
+	require.EqualValues(t, 1, bump.AnchorOutpoint.OutputIndex)
+	require.EqualValues(t, 800, bump.DeadlineHeight)
+	require.EqualValues(t, 5000, bump.BudgetSat)
+
+	packet, err := psbt.NewFromRawBytes(
+		bytes.NewReader(bump.SpendTemplatePsbt), false,
+	)
+	require.NoError(t, err)
+	require.Len(t, packet.Inputs, 1)
+	require.Equal(t, anchorOutput, packet.Inputs[0].WitnessUtxo)
+	require.NotEmpty(t, packet.Inputs[0].FinalScriptWitness)
+	require.Empty(t, packet.UnsignedTx.TxIn[0].Witness)
+
+	// The template of the request must not be modified.
+	require.Equal(t, witness, template.TxIn[0].Witness)
+}
@@ -63,6 +63,15 @@ type Config struct {
 	// sweeping inputs in batches back into the wallet.
 	Sweeper *sweep.UtxoSweeper
 
+	// SpendArbiter keeps track of which subsystem spends which outpoint.
+	// The inputs of the txns published by the user are claimed with it, so
+	// they don't conflict with the txns of other subsystems.
+	SpendArbiter *sweep.SpendArbiter
+
+	// SweepLedger holds the history of all sweeps published by the
+	// sweeper.
+	SweepLedger sweep.SweepLedger
+
 	// Chain is an interface that the WalletKit will use to determine state
 	// about the backing chain of the wallet.
 	Chain lnwallet.BlockChainIO
@@ -80,4 +89,8 @@ type Config struct {
 
 	// ChanStateDB is the reference to the channel db.
 	ChanStateDB *channeldb.ChannelStateDB
+
+	// AnchorBumps provides the fee bumps of our commitment txns that are
+	// delegated to an external service.
+	AnchorBumps AnchorBumpSource
 }
@@ -0,0 +1,93 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+// errNoSpendArbiter is returned if the spend claims are requested while no
+// spend arbiter is configured.
+var errNoSpendArbiter = errors.New("spend arbiter not available")
+
+// ListSpendClaims returns the outpoints that are currently claimed by one of
+// the subsystems that broadcast txns, together with the most recent
+// broadcasts that were rejected because they spent an outpoint claimed by
+// another subsystem.
+func (w *WalletKit) ListSpendClaims(_ context.Context,
+	_ *ListSpendClaimsRequest) (*ListSpendClaimsResponse, error) {
+
+	if w.cfg.SpendArbiter == nil {
+		return nil, errNoSpendArbiter
+	}
+
+	claims := w.cfg.SpendArbiter.Claims()
+	conflicts := w.cfg.SpendArbiter.Conflicts()
+
+	resp := &ListSpendClaimsResponse{
+		Claims:    make([]*SpendClaim, 0, len(claims)),
+		Conflicts: make([]*SpendConflict, 0, len(conflicts)),
+	}
+	for _, claim := range claims {
+		source, err := marshalSpendSource(claim.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		txids := make([][]byte, 0, len(claim.Txids))
+		for _, txid := range claim.Txids {
+			txids = append(txids, txid[:])
+		}
+
+		resp.Claims = append(resp.Claims, &SpendClaim{
+			Outpoint: lnrpc.MarshalOutPoint(&claim.OutPoint),
+			Source:   source,
+			Txids:    txids,
+		})
+	}
+
+	for _, conflict := range conflicts {
+		claimant, err := marshalSpendSource(conflict.Claimant)
+		if err != nil {
+			return nil, err
+		}
+
+		source, err := marshalSpendSource(conflict.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Conflicts = append(resp.Conflicts, &SpendConflict{
+			Outpoint:  lnrpc.MarshalOutPoint(&conflict.OutPoint),
+			Claimant:  claimant,
+			Source:    source,
+			Txid:      conflict.Txid[:],
+			Timestamp: conflict.Timestamp.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+// marshalSpendSource converts a spend source to its rpc representation.
+func marshalSpendSource(source sweep.SpendSource) (SpendSource, error) {
+	switch source {
+	case sweep.SpendSourceSweeper:
+		return SpendSource_SPEND_SOURCE_SWEEPER, nil
+
+	case sweep.SpendSourceContractCourt:
+		return SpendSource_SPEND_SOURCE_CONTRACT_COURT, nil
+
+	case sweep.SpendSourceUser:
+		return SpendSource_SPEND_SOURCE_USER, nil
+
+	default:
+		return 0, fmt.Errorf("unknown spend source %v", source)
+	}
+}
@@ -0,0 +1,63 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/stretchr/testify/require"
+)
+
+// TestListSpendClaims tests that the claims and conflicts of the spend
+// arbiter are returned.
+func TestListSpendClaims(t *testing.T) {
+	t.Parallel()
+
+	w := &WalletKit{cfg: &Config{}}
+	_, err := w.ListSpendClaims(
+		context.Background(), &ListSpendClaimsRequest{},
+	)
+	require.ErrorIs(t, err, errNoSpendArbiter)
+
+	op := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+
+	sweepTx := wire.NewMsgTx(2)
+	sweepTx.AddTxIn(&wire.TxIn{PreviousOutPoint: op})
+
+	userTx := wire.NewMsgTx(2)
+	userTx.AddTxIn(&wire.TxIn{PreviousOutPoint: op})
+	userTx.AddTxOut(&wire.TxOut{Value: 1000})
+
+	arbiter := sweep.NewSpendArbiter()
+	require.NoError(t, arbiter.Claim(sweep.SpendSourceSweeper, sweepTx))
+	require.ErrorIs(
+		t, arbiter.Claim(sweep.SpendSourceUser, userTx),
+		sweep.ErrSpendConflict,
+	)
+
+	w.cfg.SpendArbiter = arbiter
+	resp, err := w.ListSpendClaims(
+		context.Background(), &ListSpendClaimsRequest{},
+	)
+	require.NoError(t, err)
+
+	sweepTxid := sweepTx.TxHash()
+	require.Len(t, resp.Claims, 1)
+	require.Equal(t, op.String(), resp.Claims[0].Outpoint.TxidStr+":2")
+	require.Equal(
+		t, SpendSource_SPEND_SOURCE_SWEEPER, resp.Claims[0].Source,
+	)
+	require.Equal(t, [][]byte{sweepTxid[:]}, resp.Claims[0].Txids)
+
+	userTxid := userTx.TxHash()
+	require.Len(t, resp.Conflicts, 1)
+	conflict := resp.Conflicts[0]
+	require.Equal(t, SpendSource_SPEND_SOURCE_SWEEPER, conflict.Claimant)
+	require.Equal(t, SpendSource_SPEND_SOURCE_USER, conflict.Source)
+	require.Equal(t, userTxid[:], conflict.Txid)
+}
@@ -0,0 +1,101 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+// errNoSweepLedger is returned if the sweep ledger is requested while no
+// ledger is configured.
+var errNoSweepLedger = errors.New("sweep ledger not available")
+
+// ListSweepLedger returns the history of every sweep published by the
+// sweeper, either as a list of sweeps or in CSV format.
+func (w *WalletKit) ListSweepLedger(_ context.Context,
+	req *ListSweepLedgerRequest) (*ListSweepLedgerResponse, error) {
+
+	if w.cfg.SweepLedger == nil {
+		return nil, errNoSweepLedger
+	}
+
+	sweeps, err := w.cfg.SweepLedger.ListLedgerSweeps()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Csv {
+		var b bytes.Buffer
+		if err := sweep.WriteLedgerCSV(&b, sweeps); err != nil {
+			return nil, err
+		}
+
+		return &ListSweepLedgerResponse{
+			Csv: b.Bytes(),
+		}, nil
+	}
+
+	resp := &ListSweepLedgerResponse{
+		Sweeps: make([]*LedgerSweep, 0, len(sweeps)),
+	}
+	for _, l := range sweeps {
+		rpcSweep, err := marshalLedgerSweep(l)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Sweeps = append(resp.Sweeps, rpcSweep)
+	}
+
+	return resp, nil
+}
+
+// marshalLedgerSweep converts a sweep of the ledger to its rpc
+// representation.
+func marshalLedgerSweep(l *sweep.LedgerSweep) (*LedgerSweep, error) {
+	var outcome LedgerSweepOutcome
+	switch l.Outcome {
+	case sweep.SweepOutcomePending:
+		outcome = LedgerSweepOutcome_LEDGER_SWEEP_PENDING
+
+	case sweep.SweepOutcomeConfirmed:
+		outcome = LedgerSweepOutcome_LEDGER_SWEEP_CONFIRMED
+
+	case sweep.SweepOutcomeFailed:
+		outcome = LedgerSweepOutcome_LEDGER_SWEEP_FAILED
+
+	default:
+		return nil, fmt.Errorf("unknown sweep outcome %v", l.Outcome)
+	}
+
+	inputs := make([]*lnrpc.OutPoint, 0, len(l.Inputs))
+	for _, op := range l.Inputs {
+		inputs = append(inputs, lnrpc.MarshalOutPoint(&op))
+	}
+
+	attempts := make([]*LedgerSweepAttempt, 0, len(l.Attempts))
+	for _, attempt := range l.Attempts {
+		attempts = append(attempts, &LedgerSweepAttempt{
+			Txid:     attempt.Txid[:],
+			SatPerKw: uint64(attempt.FeeRate),
+			FeeSat:   int64(attempt.Fee),
+			Height:   attempt.Height,
+		})
+	}
+
+	return &LedgerSweep{
+		Id:            l.ID,
+		Inputs:        inputs,
+		Attempts:      attempts,
+		Outcome:       outcome,
+		FailureReason: l.FailureReason,
+		FeePaidSat:    int64(l.FeePaid()),
+	}, nil
+}
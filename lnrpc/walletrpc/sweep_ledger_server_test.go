@@ -0,0 +1,89 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSweepLedger is a sweep ledger that returns a fixed list of sweeps.
+type mockSweepLedger struct {
+	sweeps []*sweep.LedgerSweep
+}
+
+func (m *mockSweepLedger) AddSweep(*sweep.LedgerSweep) error {
+	return nil
+}
+
+func (m *mockSweepLedger) UpdateSweep(*sweep.LedgerSweep) error {
+	return nil
+}
+
+func (m *mockSweepLedger) ListLedgerSweeps() ([]*sweep.LedgerSweep, error) {
+	return m.sweeps, nil
+}
+
+// TestListSweepLedger tests that the sweeps of the ledger are returned, both
+// as a list and in CSV format.
+func TestListSweepLedger(t *testing.T) {
+	t.Parallel()
+
+	w := &WalletKit{cfg: &Config{}}
+	_, err := w.ListSweepLedger(
+		context.Background(), &ListSweepLedgerRequest{},
+	)
+	require.ErrorIs(t, err, errNoSweepLedger)
+
+	op := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+	w.cfg.SweepLedger = &mockSweepLedger{
+		sweeps: []*sweep.LedgerSweep{{
+			ID:     7,
+			Inputs: []wire.OutPoint{op},
+			Attempts: []sweep.SweepAttempt{{
+				Txid:    chainhash.Hash{2},
+				FeeRate: 253,
+				Fee:     500,
+				Height:  100,
+			}, {
+				Txid:    chainhash.Hash{3},
+				FeeRate: 1000,
+				Fee:     2000,
+				Height:  101,
+			}},
+			Outcome: sweep.SweepOutcomeConfirmed,
+		}},
+	}
+
+	resp, err := w.ListSweepLedger(
+		context.Background(), &ListSweepLedgerRequest{},
+	)
+	require.NoError(t, err)
+	require.Len(t, resp.Sweeps, 1)
+
+	s := resp.Sweeps[0]
+	require.EqualValues(t, 7, s.Id)
+	require.Equal(t, LedgerSweepOutcome_LEDGER_SWEEP_CONFIRMED, s.Outcome)
+	require.EqualValues(t, 2000, s.FeePaidSat)
+	require.Len(t, s.Inputs, 1)
+	require.EqualValues(t, 2, s.Inputs[0].OutputIndex)
+	require.Len(t, s.Attempts, 2)
+	require.EqualValues(t, 1000, s.Attempts[1].SatPerKw)
+	require.EqualValues(t, 101, s.Attempts[1].Height)
+
+	resp, err = w.ListSweepLedger(
+		context.Background(), &ListSweepLedgerRequest{Csv: true},
+	)
+	require.NoError(t, err)
+	require.Empty(t, resp.Sweeps)
+
+	lines := strings.Split(strings.TrimSpace(string(resp.Csv)), "\n")
+	require.Len(t, lines, 2)
+}
@@ -0,0 +1,38 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// SweepNow sweeps the given inputs right away, bypassing the batch windows of
+// the sweeper.
+func (w *WalletKit) SweepNow(_ context.Context,
+	req *SweepNowRequest) (*SweepNowResponse, error) {
+
+	if len(req.Outpoints) == 0 {
+		return nil, errors.New("no outpoints specified")
+	}
+
+	inputs := make([]wire.OutPoint, 0, len(req.Outpoints))
+	for _, rpcOp := range req.Outpoints {
+		op, err := UnmarshallOutPoint(rpcOp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid outpoint: %w", err)
+		}
+
+		inputs = append(inputs, *op)
+	}
+
+	if err := w.cfg.Sweeper.SweepNow(inputs); err != nil {
+		return nil, err
+	}
+
+	return &SweepNowResponse{}, nil
+}
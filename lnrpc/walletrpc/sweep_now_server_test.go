@@ -0,0 +1,30 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSweepNowInvalidRequest tests that requests without valid outpoints are
+// rejected before they reach the sweeper.
+func TestSweepNowInvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	w := &WalletKit{cfg: &Config{}}
+
+	_, err := w.SweepNow(context.Background(), &SweepNowRequest{})
+	require.ErrorContains(t, err, "no outpoints specified")
+
+	_, err = w.SweepNow(context.Background(), &SweepNowRequest{
+		Outpoints: []*lnrpc.OutPoint{{
+			TxidStr: "invalid",
+		}},
+	})
+	require.ErrorContains(t, err, "invalid outpoint")
+}
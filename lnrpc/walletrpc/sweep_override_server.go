@@ -0,0 +1,103 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+// unmarshallParamsOverride converts an OverrideSweepParams request into the
+// overrides that are applied by the sweeper. Unset fields of the request
+// retain the current parameters of the input.
+func unmarshallParamsOverride(
+	req *OverrideSweepParamsRequest) (sweep.ParamsOverride, error) {
+
+	override := sweep.ParamsOverride{
+		RemoveExclusiveGroup: req.RemoveExclusiveGroup,
+		Immediate:            req.Immediate,
+	}
+
+	if req.ExclusiveGroup != 0 && req.RemoveExclusiveGroup {
+		return override, errors.New("exclusive_group and " +
+			"remove_exclusive_group are mutually exclusive")
+	}
+
+	if req.Budget != 0 {
+		override.Budget = fn.Some(btcutil.Amount(req.Budget))
+	}
+	if req.DeadlineHeight != 0 {
+		override.DeadlineHeight = fn.Some(int32(req.DeadlineHeight))
+	}
+	if req.ExclusiveGroup != 0 {
+		override.ExclusiveGroup = fn.Some(req.ExclusiveGroup)
+	}
+
+	return override, nil
+}
+
+// marshallSweepTxState converts the state of a sweeping transaction into its
+// RPC representation.
+func marshallSweepTxState(state *sweep.BumpState) *SweepTxState {
+	inputs := make([]*lnrpc.OutPoint, 0, len(state.Inputs))
+	for i := range state.Inputs {
+		inputs = append(inputs, lnrpc.MarshalOutPoint(&state.Inputs[i]))
+	}
+
+	feeFunc := state.FeeFunction
+
+	return &SweepTxState{
+		Txid:           state.Txid.String(),
+		Inputs:         inputs,
+		FeeSat:         uint64(state.Fee),
+		BudgetSat:      uint64(state.Budget),
+		DeadlineHeight: uint32(state.DeadlineHeight),
+		StartingSatPerVbyte: uint64(
+			feeFunc.StartingFeeRate.FeePerVByte(),
+		),
+		EndingSatPerVbyte: uint64(
+			feeFunc.EndingFeeRate.FeePerVByte(),
+		),
+		CurrentSatPerVbyte: uint64(
+			feeFunc.CurrentFeeRate.FeePerVByte(),
+		),
+		NextSatPerVbyte: uint64(
+			feeFunc.NextFeeRate.FeePerVByte(),
+		),
+		NextFeeSat:          uint64(state.NextFee),
+		FeeFunctionPosition: feeFunc.Position,
+		FeeFunctionWidth:    feeFunc.Width,
+	}
+}
+
+// OverrideSweepParams overrides the sweep parameters of an input that is
+// pending in the sweeper. The input is swept again with the new parameters.
+func (w *WalletKit) OverrideSweepParams(_ context.Context,
+	req *OverrideSweepParamsRequest) (*OverrideSweepParamsResponse, error) {
+
+	op, err := UnmarshallOutPoint(req.Outpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outpoint: %w", err)
+	}
+
+	override, err := unmarshallParamsOverride(req)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = w.cfg.Sweeper.OverrideParams(*op, override)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OverrideSweepParamsResponse{
+		Status: "Successfully overrode the sweep params",
+	}, nil
+}
@@ -0,0 +1,122 @@
+//go:build walletrpc
+// +build walletrpc
+
+package walletrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/sweep"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUnmarshallParamsOverride tests that only the set fields of an
+// OverrideSweepParams request are overridden.
+func TestUnmarshallParamsOverride(t *testing.T) {
+	t.Parallel()
+
+	override, err := unmarshallParamsOverride(&OverrideSweepParamsRequest{
+		Budget:    1000,
+		Immediate: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, sweep.ParamsOverride{
+		Budget:    fn.Some(btcutil.Amount(1000)),
+		Immediate: true,
+	}, override)
+
+	override, err = unmarshallParamsOverride(&OverrideSweepParamsRequest{
+		DeadlineHeight: 800,
+		ExclusiveGroup: 7,
+	})
+	require.NoError(t, err)
+	require.Equal(t, sweep.ParamsOverride{
+		DeadlineHeight: fn.Some(int32(800)),
+		ExclusiveGroup: fn.Some(uint64(7)),
+	}, override)
+
+	_, err = unmarshallParamsOverride(&OverrideSweepParamsRequest{
+		ExclusiveGroup:       7,
+		RemoveExclusiveGroup: true,
+	})
+	require.ErrorContains(t, err, "mutually exclusive")
+}
+
+// TestOverrideSweepParamsInvalidRequest tests that requests without a valid
+// outpoint are rejected before they reach the sweeper.
+func TestOverrideSweepParamsInvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	w := &WalletKit{cfg: &Config{}}
+
+	_, err := w.OverrideSweepParams(
+		context.Background(), &OverrideSweepParamsRequest{},
+	)
+	require.ErrorContains(t, err, "invalid outpoint")
+
+	_, err = w.OverrideSweepParams(
+		context.Background(), &OverrideSweepParamsRequest{
+			Outpoint: &lnrpc.OutPoint{
+				TxidStr: chainhash.Hash{1}.String(),
+			},
+			ExclusiveGroup:       1,
+			RemoveExclusiveGroup: true,
+		},
+	)
+	require.ErrorContains(t, err, "mutually exclusive")
+}
+
+// TestMarshallSweepTxState tests that the fee function state of a sweeping tx
+// is converted into sat/vbyte.
+func TestMarshallSweepTxState(t *testing.T) {
+	t.Parallel()
+
+	op := wire.OutPoint{Hash: chainhash.Hash{2}, Index: 1}
+	state := &sweep.BumpState{
+		Txid:           chainhash.Hash{3},
+		Inputs:         []wire.OutPoint{op},
+		Fee:            500,
+		Budget:         5000,
+		DeadlineHeight: 144,
+		FeeFunction: sweep.FeeFunctionState{
+			StartingFeeRate: chainfee.SatPerKVByte(
+				2000,
+			).FeePerKWeight(),
+			EndingFeeRate: chainfee.SatPerKVByte(
+				20000,
+			).FeePerKWeight(),
+			CurrentFeeRate: chainfee.SatPerKVByte(
+				4000,
+			).FeePerKWeight(),
+			NextFeeRate: chainfee.SatPerKVByte(
+				6000,
+			).FeePerKWeight(),
+			Position: 1,
+			Width:    9,
+		},
+		NextFee: 750,
+	}
+
+	rpcState := marshallSweepTxState(state)
+	require.Equal(t, chainhash.Hash{3}.String(), rpcState.Txid)
+	require.Len(t, rpcState.Inputs, 1)
+	require.Equal(t, op.Hash.String(), rpcState.Inputs[0].TxidStr)
+	require.Equal(t, op.Index, rpcState.Inputs[0].OutputIndex)
+	require.EqualValues(t, 500, rpcState.FeeSat)
+	require.EqualValues(t, 5000, rpcState.BudgetSat)
+	require.EqualValues(t, 144, rpcState.DeadlineHeight)
+	require.EqualValues(t, 2, rpcState.StartingSatPerVbyte)
+	require.EqualValues(t, 20, rpcState.EndingSatPerVbyte)
+	require.EqualValues(t, 4, rpcState.CurrentSatPerVbyte)
+	require.EqualValues(t, 6, rpcState.NextSatPerVbyte)
+	require.EqualValues(t, 750, rpcState.NextFeeSat)
+	require.EqualValues(t, 1, rpcState.FeeFunctionPosition)
+	require.EqualValues(t, 9, rpcState.FeeFunctionWidth)
+}
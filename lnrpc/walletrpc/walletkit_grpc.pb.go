@@ -279,6 +279,42 @@ type WalletKitClient interface {
 	// caller's responsibility to either publish the transaction on success or
 	// unlock/release any locked UTXOs in case of an error in this method.
 	FinalizePsbt(ctx context.Context, in *FinalizePsbtRequest, opts ...grpc.CallOption) (*FinalizePsbtResponse, error)
+	// lncli: `wallet anchorbumps list`
+	// ListDelegatedAnchorBumps returns the delegated fee bumps of our commitment
+	// transactions that haven't confirmed yet. They're only delegated if
+	// forceclose.delegateanchorbumps is set.
+	ListDelegatedAnchorBumps(ctx context.Context, in *ListDelegatedAnchorBumpsRequest, opts ...grpc.CallOption) (*ListDelegatedAnchorBumpsResponse, error)
+	// lncli: `wallet anchorbumps subscribe`
+	// SubscribeDelegatedAnchorBumps streams every new delegated fee bump of our
+	// commitment transactions. The bumps that were delegated while the client
+	// wasn't subscribed can be caught up with ListDelegatedAnchorBumps.
+	SubscribeDelegatedAnchorBumps(ctx context.Context, in *SubscribeDelegatedAnchorBumpsRequest, opts ...grpc.CallOption) (WalletKit_SubscribeDelegatedAnchorBumpsClient, error)
+	// lncli: `wallet spendclaims`
+	// ListSpendClaims returns the outpoints that are currently claimed by one of
+	// the subsystems that broadcast transactions (the sweeper, the contract court
+	// and the user), together with the most recent broadcasts that were rejected
+	// because they spent an outpoint claimed by another subsystem.
+	ListSpendClaims(ctx context.Context, in *ListSpendClaimsRequest, opts ...grpc.CallOption) (*ListSpendClaimsResponse, error)
+	// lncli: `wallet sweepledger`
+	// ListSweepLedger returns the history of every sweep published by the
+	// sweeper, including each of its broadcast attempts and the fee that went to
+	// the chain once it confirmed. The ledger can also be exported as CSV.
+	ListSweepLedger(ctx context.Context, in *ListSweepLedgerRequest, opts ...grpc.CallOption) (*ListSweepLedgerResponse, error)
+	// lncli: `wallet sweepnow`
+	// SweepNow sweeps the given inputs right away, bypassing the batch windows
+	// of the sweeper and without waiting for the next block. The inputs are
+	// swept in transactions of their own, and inputs that were already published
+	// are swept again. This allows operators to respond to pinning attacks on
+	// sweeping transactions.
+	SweepNow(ctx context.Context, in *SweepNowRequest, opts ...grpc.CallOption) (*SweepNowResponse, error)
+	// lncli: `wallet overridesweep`
+	// OverrideSweepParams overrides the sweep parameters of an input that is
+	// pending in the sweeper. Only the parameters that are set are changed, the
+	// others are left as they are. The input is swept again with the new
+	// parameters, replacing its current sweeping transaction. The state of the
+	// fee function of the sweeping transaction, including a preview of its next
+	// fee bump, is returned by PendingSweeps.
+	OverrideSweepParams(ctx context.Context, in *OverrideSweepParamsRequest, opts ...grpc.CallOption) (*OverrideSweepParamsResponse, error)
 }
 
 type walletKitClient struct {
@@ -541,6 +577,83 @@ func (c *walletKitClient) FinalizePsbt(ctx context.Context, in *FinalizePsbtRequ
 	return out, nil
 }
 
+func (c *walletKitClient) ListDelegatedAnchorBumps(ctx context.Context, in *ListDelegatedAnchorBumpsRequest, opts ...grpc.CallOption) (*ListDelegatedAnchorBumpsResponse, error) {
+	out := new(ListDelegatedAnchorBumpsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ListDelegatedAnchorBumps", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) SubscribeDelegatedAnchorBumps(ctx context.Context, in *SubscribeDelegatedAnchorBumpsRequest, opts ...grpc.CallOption) (WalletKit_SubscribeDelegatedAnchorBumpsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WalletKit_ServiceDesc.Streams[0], "/walletrpc.WalletKit/SubscribeDelegatedAnchorBumps", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &walletKitSubscribeDelegatedAnchorBumpsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WalletKit_SubscribeDelegatedAnchorBumpsClient interface {
+	Recv() (*DelegatedAnchorBump, error)
+	grpc.ClientStream
+}
+
+type walletKitSubscribeDelegatedAnchorBumpsClient struct {
+	grpc.ClientStream
+}
+
+func (x *walletKitSubscribeDelegatedAnchorBumpsClient) Recv() (*DelegatedAnchorBump, error) {
+	m := new(DelegatedAnchorBump)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *walletKitClient) ListSpendClaims(ctx context.Context, in *ListSpendClaimsRequest, opts ...grpc.CallOption) (*ListSpendClaimsResponse, error) {
+	out := new(ListSpendClaimsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ListSpendClaims", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) ListSweepLedger(ctx context.Context, in *ListSweepLedgerRequest, opts ...grpc.CallOption) (*ListSweepLedgerResponse, error) {
+	out := new(ListSweepLedgerResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/ListSweepLedger", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) SweepNow(ctx context.Context, in *SweepNowRequest, opts ...grpc.CallOption) (*SweepNowResponse, error) {
+	out := new(SweepNowResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/SweepNow", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *walletKitClient) OverrideSweepParams(ctx context.Context, in *OverrideSweepParamsRequest, opts ...grpc.CallOption) (*OverrideSweepParamsResponse, error) {
+	out := new(OverrideSweepParamsResponse)
+	err := c.cc.Invoke(ctx, "/walletrpc.WalletKit/OverrideSweepParams", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WalletKitServer is the server API for WalletKit service.
 // All implementations must embed UnimplementedWalletKitServer
 // for forward compatibility
@@ -804,6 +917,42 @@ type WalletKitServer interface {
 	// caller's responsibility to either publish the transaction on success or
 	// unlock/release any locked UTXOs in case of an error in this method.
 	FinalizePsbt(context.Context, *FinalizePsbtRequest) (*FinalizePsbtResponse, error)
+	// lncli: `wallet anchorbumps list`
+	// ListDelegatedAnchorBumps returns the delegated fee bumps of our commitment
+	// transactions that haven't confirmed yet. They're only delegated if
+	// forceclose.delegateanchorbumps is set.
+	ListDelegatedAnchorBumps(context.Context, *ListDelegatedAnchorBumpsRequest) (*ListDelegatedAnchorBumpsResponse, error)
+	// lncli: `wallet anchorbumps subscribe`
+	// SubscribeDelegatedAnchorBumps streams every new delegated fee bump of our
+	// commitment transactions. The bumps that were delegated while the client
+	// wasn't subscribed can be caught up with ListDelegatedAnchorBumps.
+	SubscribeDelegatedAnchorBumps(*SubscribeDelegatedAnchorBumpsRequest, WalletKit_SubscribeDelegatedAnchorBumpsServer) error
+	// lncli: `wallet spendclaims`
+	// ListSpendClaims returns the outpoints that are currently claimed by one of
+	// the subsystems that broadcast transactions (the sweeper, the contract court
+	// and the user), together with the most recent broadcasts that were rejected
+	// because they spent an outpoint claimed by another subsystem.
+	ListSpendClaims(context.Context, *ListSpendClaimsRequest) (*ListSpendClaimsResponse, error)
+	// lncli: `wallet sweepledger`
+	// ListSweepLedger returns the history of every sweep published by the
+	// sweeper, including each of its broadcast attempts and the fee that went to
+	// the chain once it confirmed. The ledger can also be exported as CSV.
+	ListSweepLedger(context.Context, *ListSweepLedgerRequest) (*ListSweepLedgerResponse, error)
+	// lncli: `wallet sweepnow`
+	// SweepNow sweeps the given inputs right away, bypassing the batch windows
+	// of the sweeper and without waiting for the next block. The inputs are
+	// swept in transactions of their own, and inputs that were already published
+	// are swept again. This allows operators to respond to pinning attacks on
+	// sweeping transactions.
+	SweepNow(context.Context, *SweepNowRequest) (*SweepNowResponse, error)
+	// lncli: `wallet overridesweep`
+	// OverrideSweepParams overrides the sweep parameters of an input that is
+	// pending in the sweeper. Only the parameters that are set are changed, the
+	// others are left as they are. The input is swept again with the new
+	// parameters, replacing its current sweeping transaction. The state of the
+	// fee function of the sweeping transaction, including a preview of its next
+	// fee bump, is returned by PendingSweeps.
+	OverrideSweepParams(context.Context, *OverrideSweepParamsRequest) (*OverrideSweepParamsResponse, error)
 	mustEmbedUnimplementedWalletKitServer()
 }
 
@@ -895,6 +1044,24 @@ func (UnimplementedWalletKitServer) SignPsbt(context.Context, *SignPsbtRequest)
 func (UnimplementedWalletKitServer) FinalizePsbt(context.Context, *FinalizePsbtRequest) (*FinalizePsbtResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FinalizePsbt not implemented")
 }
+func (UnimplementedWalletKitServer) ListDelegatedAnchorBumps(context.Context, *ListDelegatedAnchorBumpsRequest) (*ListDelegatedAnchorBumpsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDelegatedAnchorBumps not implemented")
+}
+func (UnimplementedWalletKitServer) SubscribeDelegatedAnchorBumps(*SubscribeDelegatedAnchorBumpsRequest, WalletKit_SubscribeDelegatedAnchorBumpsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeDelegatedAnchorBumps not implemented")
+}
+func (UnimplementedWalletKitServer) ListSpendClaims(context.Context, *ListSpendClaimsRequest) (*ListSpendClaimsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSpendClaims not implemented")
+}
+func (UnimplementedWalletKitServer) ListSweepLedger(context.Context, *ListSweepLedgerRequest) (*ListSweepLedgerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSweepLedger not implemented")
+}
+func (UnimplementedWalletKitServer) SweepNow(context.Context, *SweepNowRequest) (*SweepNowResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SweepNow not implemented")
+}
+func (UnimplementedWalletKitServer) OverrideSweepParams(context.Context, *OverrideSweepParamsRequest) (*OverrideSweepParamsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method OverrideSweepParams not implemented")
+}
 func (UnimplementedWalletKitServer) mustEmbedUnimplementedWalletKitServer() {}
 
 // UnsafeWalletKitServer may be embedded to opt out of forward compatibility for this service.
@@ -1412,6 +1579,117 @@ func _WalletKit_FinalizePsbt_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WalletKit_ListDelegatedAnchorBumps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDelegatedAnchorBumpsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ListDelegatedAnchorBumps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ListDelegatedAnchorBumps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ListDelegatedAnchorBumps(ctx, req.(*ListDelegatedAnchorBumpsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_SubscribeDelegatedAnchorBumps_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeDelegatedAnchorBumpsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WalletKitServer).SubscribeDelegatedAnchorBumps(m, &walletKitSubscribeDelegatedAnchorBumpsServer{stream})
+}
+
+type WalletKit_SubscribeDelegatedAnchorBumpsServer interface {
+	Send(*DelegatedAnchorBump) error
+	grpc.ServerStream
+}
+
+type walletKitSubscribeDelegatedAnchorBumpsServer struct {
+	grpc.ServerStream
+}
+
+func (x *walletKitSubscribeDelegatedAnchorBumpsServer) Send(m *DelegatedAnchorBump) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _WalletKit_ListSpendClaims_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSpendClaimsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ListSpendClaims(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ListSpendClaims",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ListSpendClaims(ctx, req.(*ListSpendClaimsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_ListSweepLedger_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSweepLedgerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).ListSweepLedger(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/ListSweepLedger",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).ListSweepLedger(ctx, req.(*ListSweepLedgerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_SweepNow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SweepNowRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).SweepNow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/SweepNow",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).SweepNow(ctx, req.(*SweepNowRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WalletKit_OverrideSweepParams_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OverrideSweepParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletKitServer).OverrideSweepParams(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/walletrpc.WalletKit/OverrideSweepParams",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletKitServer).OverrideSweepParams(ctx, req.(*OverrideSweepParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WalletKit_ServiceDesc is the grpc.ServiceDesc for WalletKit service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1531,7 +1809,33 @@ var WalletKit_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FinalizePsbt",
 			Handler:    _WalletKit_FinalizePsbt_Handler,
 		},
+		{
+			MethodName: "ListDelegatedAnchorBumps",
+			Handler:    _WalletKit_ListDelegatedAnchorBumps_Handler,
+		},
+		{
+			MethodName: "ListSpendClaims",
+			Handler:    _WalletKit_ListSpendClaims_Handler,
+		},
+		{
+			MethodName: "ListSweepLedger",
+			Handler:    _WalletKit_ListSweepLedger_Handler,
+		},
+		{
+			MethodName: "SweepNow",
+			Handler:    _WalletKit_SweepNow_Handler,
+		},
+		{
+			MethodName: "OverrideSweepParams",
+			Handler:    _WalletKit_OverrideSweepParams_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeDelegatedAnchorBumps",
+			Handler:       _WalletKit_SubscribeDelegatedAnchorBumps_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "walletrpc/walletkit.proto",
 }
@@ -108,6 +108,30 @@ var (
 			Entity: "onchain",
 			Action: "write",
 		}},
+		"/walletrpc.WalletKit/ListDelegatedAnchorBumps": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/SubscribeDelegatedAnchorBumps": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/ListSpendClaims": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/ListSweepLedger": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/walletrpc.WalletKit/SweepNow": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
+		"/walletrpc.WalletKit/OverrideSweepParams": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
 		"/walletrpc.WalletKit/BumpForceCloseFee": {{
 			Entity: "onchain",
 			Action: "write",
@@ -677,7 +701,14 @@ func (w *WalletKit) PublishTransaction(ctx context.Context,
 		return nil, err
 	}
 
-	err = w.cfg.Wallet.PublishTransaction(tx, label)
+	publish := w.cfg.Wallet.PublishTransaction
+	if w.cfg.SpendArbiter != nil {
+		publish = w.cfg.SpendArbiter.PublishFunc(
+			sweep.SpendSourceUser, publish,
+		)
+	}
+
+	err = publish(tx, label)
 	if err != nil {
 		return nil, err
 	}
@@ -910,6 +941,13 @@ func (w *WalletKit) PendingSweeps(ctx context.Context,
 			DeadlineHeight:       inp.DeadlineHeight,
 			RequestedSatPerVbyte: startingFeeRate,
 		}
+
+		// Attach the state of the sweeping tx, which includes a
+		// preview of its next fee bump.
+		inp.Bump.WhenSome(func(state sweep.BumpState) {
+			ps.SweepTx = marshallSweepTxState(&state)
+		})
+
 		rpcPendingSweeps = append(rpcPendingSweeps, ps)
 	}
 
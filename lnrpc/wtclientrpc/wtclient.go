@@ -11,9 +11,11 @@ import (
 	"strconv"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/watchtower"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
@@ -68,6 +70,30 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/wtclientrpc.WatchtowerClient/DBStats": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/wtclientrpc.WatchtowerClient/ExcludeChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/wtclientrpc.WatchtowerClient/SetChannelSweepFeeRate": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/wtclientrpc.WatchtowerClient/ChannelCoverage": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/wtclientrpc.WatchtowerClient/ListJusticeReports": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/wtclientrpc.WatchtowerClient/SubscribeJusticeReports": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 	}
 
 	// ErrWtclientNotActive signals that RPC calls cannot be processed
@@ -491,6 +517,274 @@ func (c *WatchtowerClient) Stats(_ context.Context,
 	}, nil
 }
 
+// DBStats returns the storage statistics of the client database, such as the
+// number of stored sessions and the size of the backlog of committed updates
+// and queued backups.
+func (c *WatchtowerClient) DBStats(_ context.Context,
+	_ *DBStatsRequest) (*DBStatsResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	stats, err := c.cfg.ClientMgr.DBStats()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DBStatsResponse{
+		NumSessions:         stats.NumSessions,
+		NumClosableSessions: stats.NumClosableSessions,
+		NumCommittedUpdates: stats.NumCommittedUpdates,
+		CommittedBytes:      stats.CommittedBytes,
+		NumQueuedBackups:    stats.NumQueuedBackups,
+		QueuedBytes:         stats.QueuedBytes,
+		NumAckedUpdates:     stats.NumAckedUpdates,
+		NumAckedRanges:      stats.NumAckedRanges,
+	}, nil
+}
+
+// ExcludeChannel excludes the channel with the given funding outpoint from
+// being backed up to the watchtowers, or includes it again if exclude is
+// false. States of the channel that are revoked while it is excluded are
+// never backed up.
+func (c *WatchtowerClient) ExcludeChannel(_ context.Context,
+	req *ExcludeChannelRequest) (*ExcludeChannelResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	chanPoint, err := unmarshallChanPoint(req.ChanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.updateChannelPolicy(
+		chanPoint, func(policy *wtdb.ChannelBackupPolicy) {
+			policy.Excluded = req.Exclude
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExcludeChannelResponse{}, nil
+}
+
+// SetChannelSweepFeeRate sets the minimum fee rate in sat/vbyte that the
+// justice transactions of the channel with the given funding outpoint must
+// pay. States of the channel are only backed up while the client's sweep fee
+// rate is at least this high. A fee rate of zero removes the minimum.
+func (c *WatchtowerClient) SetChannelSweepFeeRate(_ context.Context,
+	req *SetChannelSweepFeeRateRequest) (*SetChannelSweepFeeRateResponse,
+	error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	chanPoint, err := unmarshallChanPoint(req.ChanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	feeRate := chainfee.SatPerKVByte(req.SatPerVbyte * 1000).FeePerKWeight()
+
+	err = c.updateChannelPolicy(
+		chanPoint, func(policy *wtdb.ChannelBackupPolicy) {
+			policy.MinSweepFeeRate = feeRate
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SetChannelSweepFeeRateResponse{}, nil
+}
+
+// ChannelCoverage returns the backup settings and the commitment heights that
+// are protected by the watchtowers for the channels with the given funding
+// outpoints, or for all registered channels if none are given.
+func (c *WatchtowerClient) ChannelCoverage(_ context.Context,
+	req *ChannelCoverageRequest) (*ChannelCoverageResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	chanIDs := make([]lnwire.ChannelID, 0, len(req.ChanPoints))
+	for _, rpcChanPoint := range req.ChanPoints {
+		chanPoint, err := unmarshallChanPoint(rpcChanPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		chanIDs = append(
+			chanIDs, lnwire.NewChanIDFromOutPoint(chanPoint),
+		)
+	}
+
+	coverages, err := c.cfg.ClientMgr.ChannelCoverage(chanIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ChannelCoverageResponse{
+		Channels: make([]*ChannelCoverage, 0, len(coverages)),
+	}
+	for _, coverage := range coverages {
+		resp.Channels = append(
+			resp.Channels, marshallChannelCoverage(coverage),
+		)
+	}
+
+	return resp, nil
+}
+
+// marshallChannelCoverage converts the coverage of a channel into its RPC
+// type.
+func marshallChannelCoverage(
+	coverage *wtclient.ChannelCoverage) *ChannelCoverage {
+
+	policy := coverage.BackupPolicy
+	minSweepFeeRate := policy.MinSweepFeeRate.FeePerVByte()
+
+	rpcCoverage := &ChannelCoverage{
+		ChanId:              coverage.ChanID[:],
+		Excluded:            policy.Excluded,
+		MinSweepSatPerVbyte: uint64(minSweepFeeRate),
+		HasStates:           coverage.MaxHeight.IsSome(),
+		MaxHeight:           coverage.MaxHeight.UnwrapOr(0),
+		NumProtected:        coverage.NumProtected(),
+	}
+	for _, r := range coverage.ProtectedRanges {
+		rpcCoverage.ProtectedRanges = append(
+			rpcCoverage.ProtectedRanges, &HeightRange{
+				Start: r.Start,
+				End:   r.End,
+			},
+		)
+	}
+
+	return rpcCoverage
+}
+
+// unmarshallChanPoint converts the channel point of an RPC request.
+func unmarshallChanPoint(chanPoint *lnrpc.ChannelPoint) (wire.OutPoint,
+	error) {
+
+	if chanPoint == nil {
+		return wire.OutPoint{}, errors.New("channel point must be set")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(chanPoint)
+	if err != nil {
+		return wire.OutPoint{}, err
+	}
+
+	return wire.OutPoint{
+		Hash:  *txid,
+		Index: chanPoint.OutputIndex,
+	}, nil
+}
+
+// ListJusticeReports returns the justice transactions the watchtowers reported
+// to have published, each of which indicates that one of our channels was
+// breached, possibly while we were offline.
+func (c *WatchtowerClient) ListJusticeReports(_ context.Context,
+	_ *ListJusticeReportsRequest) (*ListJusticeReportsResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	reports, err := c.cfg.ClientMgr.JusticeReports()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &ListJusticeReportsResponse{
+		Reports: make([]*JusticeReport, 0, len(reports)),
+	}
+	for _, report := range reports {
+		resp.Reports = append(
+			resp.Reports, marshallJusticeReport(report),
+		)
+	}
+
+	return resp, nil
+}
+
+// SubscribeJusticeReports streams every justice transaction a watchtower
+// reports from now on.
+func (c *WatchtowerClient) SubscribeJusticeReports(
+	_ *SubscribeJusticeReportsRequest,
+	stream WatchtowerClient_SubscribeJusticeReportsServer) error {
+
+	if err := c.isActive(); err != nil {
+		return err
+	}
+
+	client, err := c.cfg.ClientMgr.SubscribeJusticeReports()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case update := <-client.Updates():
+			report, ok := update.(*wtdb.ClientJusticeReport)
+			if !ok {
+				return fmt.Errorf("unexpected justice report "+
+					"update %T", update)
+			}
+
+			err := stream.Send(marshallJusticeReport(report))
+			if err != nil {
+				return err
+			}
+
+		case <-client.Quit():
+			return errors.New("watchtower client shutting down")
+
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// marshallJusticeReport converts a justice report into its RPC type.
+func marshallJusticeReport(report *wtdb.ClientJusticeReport) *JusticeReport {
+	return &JusticeReport{
+		TowerPubkey: report.TowerPubKey.SerializeCompressed(),
+		SessionId:   report.SessionID[:],
+		SeqNum:      uint32(report.SeqNum),
+		BreachTxid:  report.BreachTxID.String(),
+		JusticeTxid: report.JusticeTxID.String(),
+	}
+}
+
+// updateChannelPolicy applies the given modification to the backup settings
+// of the channel with the given funding outpoint.
+func (c *WatchtowerClient) updateChannelPolicy(chanPoint wire.OutPoint,
+	modify func(*wtdb.ChannelBackupPolicy)) error {
+
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+
+	coverages, err := c.cfg.ClientMgr.ChannelCoverage(chanID)
+	if err != nil {
+		return fmt.Errorf("unable to look up channel %v: %w",
+			chanPoint, err)
+	}
+
+	policy := coverages[0].BackupPolicy
+	modify(&policy)
+
+	return c.cfg.ClientMgr.SetChannelBackupPolicy(chanID, policy)
+}
+
 // Policy returns the active watchtower client policy configuration.
 func (c *WatchtowerClient) Policy(ctx context.Context,
 	req *PolicyRequest) (*PolicyResponse, error) {
@@ -51,6 +51,36 @@ type WatchtowerClientClient interface {
 	// lncli: `wtclient policy`
 	// Policy returns the active watchtower client policy configuration.
 	Policy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*PolicyResponse, error)
+	// lncli: `wtclient dbstats`
+	// DBStats returns the storage statistics of the client database, such as
+	// the number of stored sessions and the size of the backlog of committed
+	// updates and queued backups.
+	DBStats(ctx context.Context, in *DBStatsRequest, opts ...grpc.CallOption) (*DBStatsResponse, error)
+	// lncli: `wtclient channel exclude`
+	// ExcludeChannel excludes a channel from being backed up to the
+	// watchtowers, or includes it again. States of the channel that are revoked
+	// while it is excluded are never backed up.
+	ExcludeChannel(ctx context.Context, in *ExcludeChannelRequest, opts ...grpc.CallOption) (*ExcludeChannelResponse, error)
+	// lncli: `wtclient channel sweepfeerate`
+	// SetChannelSweepFeeRate sets the minimum fee rate that the justice
+	// transactions of a channel must pay. States of the channel are only backed
+	// up while the client's sweep fee rate is at least this high. A fee rate of
+	// zero removes the minimum.
+	SetChannelSweepFeeRate(ctx context.Context, in *SetChannelSweepFeeRateRequest, opts ...grpc.CallOption) (*SetChannelSweepFeeRateResponse, error)
+	// lncli: `wtclient channel coverage`
+	// ChannelCoverage returns the backup settings and the commitment heights
+	// that are protected by the watchtowers for the given channels, or for all
+	// registered channels if none are given.
+	ChannelCoverage(ctx context.Context, in *ChannelCoverageRequest, opts ...grpc.CallOption) (*ChannelCoverageResponse, error)
+	// lncli: `wtclient justicereports list`
+	// ListJusticeReports returns the justice transactions the watchtowers
+	// reported to have published, each of which indicates that one of our
+	// channels was breached, possibly while we were offline.
+	ListJusticeReports(ctx context.Context, in *ListJusticeReportsRequest, opts ...grpc.CallOption) (*ListJusticeReportsResponse, error)
+	// lncli: `wtclient justicereports subscribe`
+	// SubscribeJusticeReports streams every justice transaction a watchtower
+	// reports from now on.
+	SubscribeJusticeReports(ctx context.Context, in *SubscribeJusticeReportsRequest, opts ...grpc.CallOption) (WatchtowerClient_SubscribeJusticeReportsClient, error)
 }
 
 type watchtowerClientClient struct {
@@ -133,6 +163,83 @@ func (c *watchtowerClientClient) Policy(ctx context.Context, in *PolicyRequest,
 	return out, nil
 }
 
+func (c *watchtowerClientClient) DBStats(ctx context.Context, in *DBStatsRequest, opts ...grpc.CallOption) (*DBStatsResponse, error) {
+	out := new(DBStatsResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/DBStats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) ExcludeChannel(ctx context.Context, in *ExcludeChannelRequest, opts ...grpc.CallOption) (*ExcludeChannelResponse, error) {
+	out := new(ExcludeChannelResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/ExcludeChannel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) SetChannelSweepFeeRate(ctx context.Context, in *SetChannelSweepFeeRateRequest, opts ...grpc.CallOption) (*SetChannelSweepFeeRateResponse, error) {
+	out := new(SetChannelSweepFeeRateResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/SetChannelSweepFeeRate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) ChannelCoverage(ctx context.Context, in *ChannelCoverageRequest, opts ...grpc.CallOption) (*ChannelCoverageResponse, error) {
+	out := new(ChannelCoverageResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/ChannelCoverage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) ListJusticeReports(ctx context.Context, in *ListJusticeReportsRequest, opts ...grpc.CallOption) (*ListJusticeReportsResponse, error) {
+	out := new(ListJusticeReportsResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/ListJusticeReports", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) SubscribeJusticeReports(ctx context.Context, in *SubscribeJusticeReportsRequest, opts ...grpc.CallOption) (WatchtowerClient_SubscribeJusticeReportsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &WatchtowerClient_ServiceDesc.Streams[0], "/wtclientrpc.WatchtowerClient/SubscribeJusticeReports", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &watchtowerClientSubscribeJusticeReportsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type WatchtowerClient_SubscribeJusticeReportsClient interface {
+	Recv() (*JusticeReport, error)
+	grpc.ClientStream
+}
+
+type watchtowerClientSubscribeJusticeReportsClient struct {
+	grpc.ClientStream
+}
+
+func (x *watchtowerClientSubscribeJusticeReportsClient) Recv() (*JusticeReport, error) {
+	m := new(JusticeReport)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // WatchtowerClientServer is the server API for WatchtowerClient service.
 // All implementations must embed UnimplementedWatchtowerClientServer
 // for forward compatibility
@@ -170,6 +277,36 @@ type WatchtowerClientServer interface {
 	// lncli: `wtclient policy`
 	// Policy returns the active watchtower client policy configuration.
 	Policy(context.Context, *PolicyRequest) (*PolicyResponse, error)
+	// lncli: `wtclient dbstats`
+	// DBStats returns the storage statistics of the client database, such as
+	// the number of stored sessions and the size of the backlog of committed
+	// updates and queued backups.
+	DBStats(context.Context, *DBStatsRequest) (*DBStatsResponse, error)
+	// lncli: `wtclient channel exclude`
+	// ExcludeChannel excludes a channel from being backed up to the
+	// watchtowers, or includes it again. States of the channel that are revoked
+	// while it is excluded are never backed up.
+	ExcludeChannel(context.Context, *ExcludeChannelRequest) (*ExcludeChannelResponse, error)
+	// lncli: `wtclient channel sweepfeerate`
+	// SetChannelSweepFeeRate sets the minimum fee rate that the justice
+	// transactions of a channel must pay. States of the channel are only backed
+	// up while the client's sweep fee rate is at least this high. A fee rate of
+	// zero removes the minimum.
+	SetChannelSweepFeeRate(context.Context, *SetChannelSweepFeeRateRequest) (*SetChannelSweepFeeRateResponse, error)
+	// lncli: `wtclient channel coverage`
+	// ChannelCoverage returns the backup settings and the commitment heights
+	// that are protected by the watchtowers for the given channels, or for all
+	// registered channels if none are given.
+	ChannelCoverage(context.Context, *ChannelCoverageRequest) (*ChannelCoverageResponse, error)
+	// lncli: `wtclient justicereports list`
+	// ListJusticeReports returns the justice transactions the watchtowers
+	// reported to have published, each of which indicates that one of our
+	// channels was breached, possibly while we were offline.
+	ListJusticeReports(context.Context, *ListJusticeReportsRequest) (*ListJusticeReportsResponse, error)
+	// lncli: `wtclient justicereports subscribe`
+	// SubscribeJusticeReports streams every justice transaction a watchtower
+	// reports from now on.
+	SubscribeJusticeReports(*SubscribeJusticeReportsRequest, WatchtowerClient_SubscribeJusticeReportsServer) error
 	mustEmbedUnimplementedWatchtowerClientServer()
 }
 
@@ -201,6 +338,24 @@ func (UnimplementedWatchtowerClientServer) Stats(context.Context, *StatsRequest)
 func (UnimplementedWatchtowerClientServer) Policy(context.Context, *PolicyRequest) (*PolicyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Policy not implemented")
 }
+func (UnimplementedWatchtowerClientServer) DBStats(context.Context, *DBStatsRequest) (*DBStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DBStats not implemented")
+}
+func (UnimplementedWatchtowerClientServer) ExcludeChannel(context.Context, *ExcludeChannelRequest) (*ExcludeChannelResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExcludeChannel not implemented")
+}
+func (UnimplementedWatchtowerClientServer) SetChannelSweepFeeRate(context.Context, *SetChannelSweepFeeRateRequest) (*SetChannelSweepFeeRateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetChannelSweepFeeRate not implemented")
+}
+func (UnimplementedWatchtowerClientServer) ChannelCoverage(context.Context, *ChannelCoverageRequest) (*ChannelCoverageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChannelCoverage not implemented")
+}
+func (UnimplementedWatchtowerClientServer) ListJusticeReports(context.Context, *ListJusticeReportsRequest) (*ListJusticeReportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListJusticeReports not implemented")
+}
+func (UnimplementedWatchtowerClientServer) SubscribeJusticeReports(*SubscribeJusticeReportsRequest, WatchtowerClient_SubscribeJusticeReportsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeJusticeReports not implemented")
+}
 func (UnimplementedWatchtowerClientServer) mustEmbedUnimplementedWatchtowerClientServer() {}
 
 // UnsafeWatchtowerClientServer may be embedded to opt out of forward compatibility for this service.
@@ -358,6 +513,117 @@ func _WatchtowerClient_Policy_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WatchtowerClient_DBStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DBStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).DBStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/DBStats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).DBStats(ctx, req.(*DBStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_ExcludeChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExcludeChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).ExcludeChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/ExcludeChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).ExcludeChannel(ctx, req.(*ExcludeChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_SetChannelSweepFeeRate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetChannelSweepFeeRateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).SetChannelSweepFeeRate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/SetChannelSweepFeeRate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).SetChannelSweepFeeRate(ctx, req.(*SetChannelSweepFeeRateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_ChannelCoverage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelCoverageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).ChannelCoverage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/ChannelCoverage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).ChannelCoverage(ctx, req.(*ChannelCoverageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_ListJusticeReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJusticeReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).ListJusticeReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/ListJusticeReports",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).ListJusticeReports(ctx, req.(*ListJusticeReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_SubscribeJusticeReports_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeJusticeReportsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WatchtowerClientServer).SubscribeJusticeReports(m, &watchtowerClientSubscribeJusticeReportsServer{stream})
+}
+
+type WatchtowerClient_SubscribeJusticeReportsServer interface {
+	Send(*JusticeReport) error
+	grpc.ServerStream
+}
+
+type watchtowerClientSubscribeJusticeReportsServer struct {
+	grpc.ServerStream
+}
+
+func (x *watchtowerClientSubscribeJusticeReportsServer) Send(m *JusticeReport) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // WatchtowerClient_ServiceDesc is the grpc.ServiceDesc for WatchtowerClient service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -397,7 +663,33 @@ var WatchtowerClient_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Policy",
 			Handler:    _WatchtowerClient_Policy_Handler,
 		},
+		{
+			MethodName: "DBStats",
+			Handler:    _WatchtowerClient_DBStats_Handler,
+		},
+		{
+			MethodName: "ExcludeChannel",
+			Handler:    _WatchtowerClient_ExcludeChannel_Handler,
+		},
+		{
+			MethodName: "SetChannelSweepFeeRate",
+			Handler:    _WatchtowerClient_SetChannelSweepFeeRate_Handler,
+		},
+		{
+			MethodName: "ChannelCoverage",
+			Handler:    _WatchtowerClient_ChannelCoverage_Handler,
+		},
+		{
+			MethodName: "ListJusticeReports",
+			Handler:    _WatchtowerClient_ListJusticeReports_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeJusticeReports",
+			Handler:       _WatchtowerClient_SubscribeJusticeReports_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "wtclientrpc/wtclient.proto",
 }
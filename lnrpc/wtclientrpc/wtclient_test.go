@@ -0,0 +1,99 @@
+package wtclientrpc
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/wtclient"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshallChannelCoverage tests the conversion of a channel's coverage
+// into its RPC type.
+func TestMarshallChannelCoverage(t *testing.T) {
+	t.Parallel()
+
+	feeRate := chainfee.SatPerKVByte(20 * 1000).FeePerKWeight()
+	coverage := &wtclient.ChannelCoverage{
+		ChanID: lnwire.ChannelID{1, 2, 3},
+		BackupPolicy: wtdb.ChannelBackupPolicy{
+			Excluded:        true,
+			MinSweepFeeRate: feeRate,
+		},
+		MaxHeight: fn.Some(uint64(9)),
+		ProtectedRanges: []wtdb.HeightRange{
+			{Start: 0, End: 4},
+			{Start: 7, End: 8},
+		},
+	}
+
+	rpcCoverage := marshallChannelCoverage(coverage)
+	require.Equal(t, coverage.ChanID[:], rpcCoverage.ChanId)
+	require.True(t, rpcCoverage.Excluded)
+	require.EqualValues(t, 20, rpcCoverage.MinSweepSatPerVbyte)
+	require.True(t, rpcCoverage.HasStates)
+	require.EqualValues(t, 9, rpcCoverage.MaxHeight)
+	require.EqualValues(t, 7, rpcCoverage.NumProtected)
+	require.Len(t, rpcCoverage.ProtectedRanges, 2)
+	require.EqualValues(t, 7, rpcCoverage.ProtectedRanges[1].Start)
+	require.EqualValues(t, 8, rpcCoverage.ProtectedRanges[1].End)
+
+	// A channel without states has no max height.
+	coverage = &wtclient.ChannelCoverage{}
+	rpcCoverage = marshallChannelCoverage(coverage)
+	require.False(t, rpcCoverage.HasStates)
+	require.Zero(t, rpcCoverage.NumProtected)
+}
+
+// TestUnmarshallChanPoint tests that channel points of RPC requests must be
+// set.
+func TestUnmarshallChanPoint(t *testing.T) {
+	t.Parallel()
+
+	_, err := unmarshallChanPoint(nil)
+	require.Error(t, err)
+
+	txid := []byte{31: 1}
+	chanPoint, err := unmarshallChanPoint(&lnrpc.ChannelPoint{
+		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: txid,
+		},
+		OutputIndex: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, txid, chanPoint.Hash[:])
+	require.EqualValues(t, 2, chanPoint.Index)
+}
+
+// TestMarshallJusticeReport tests the conversion of a justice report into its
+// RPC type.
+func TestMarshallJusticeReport(t *testing.T) {
+	t.Parallel()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	report := &wtdb.ClientJusticeReport{
+		JusticeReport: wtdb.JusticeReport{
+			SeqNum:      3,
+			BreachTxID:  chainhash.Hash{1},
+			JusticeTxID: chainhash.Hash{2},
+		},
+		TowerPubKey: priv.PubKey(),
+		SessionID:   wtdb.SessionID{4},
+	}
+
+	require.Equal(t, &JusticeReport{
+		TowerPubkey: priv.PubKey().SerializeCompressed(),
+		SessionId:   report.SessionID[:],
+		SeqNum:      3,
+		BreachTxid:  report.BreachTxID.String(),
+		JusticeTxid: report.JusticeTxID.String(),
+	}, marshallJusticeReport(report))
+}
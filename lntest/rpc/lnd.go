@@ -730,3 +730,117 @@ func (h *HarnessRPC) LookupHtlcResolutionAssertErr(
 
 	return err
 }
+
+// CreateAccount makes a RPC call to the node's CreateAccount and asserts.
+func (h *HarnessRPC) CreateAccount(
+	req *lnrpc.CreateAccountRequest) *lnrpc.CreateAccountResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.CreateAccount(ctxt, req)
+	h.NoError(err, "CreateAccount")
+
+	return resp
+}
+
+// ListOffChainAccounts makes a RPC call to the node's ListAccounts of the
+// Lightning service and asserts. It isn't named ListAccounts to not clash with
+// the wallet accounts.
+func (h *HarnessRPC) ListOffChainAccounts() *lnrpc.ListAccountsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.ListAccounts(ctxt, &lnrpc.ListAccountsRequest{})
+	h.NoError(err, "ListAccounts")
+
+	return resp
+}
+
+// AdjustAccountBalance makes a RPC call to the node's AdjustAccountBalance
+// and asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) AdjustAccountBalance(
+	req *lnrpc.AdjustAccountBalanceRequest) *lnrpc.AdjustAccountBalanceResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.AdjustAccountBalance(ctxt, req)
+	h.NoError(err, "AdjustAccountBalance")
+
+	return resp
+}
+
+// AccountLedger makes a RPC call to the node's AccountLedger and asserts.
+func (h *HarnessRPC) AccountLedger(id string) *lnrpc.AccountLedgerResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.AccountLedger(
+		ctxt, &lnrpc.AccountLedgerRequest{Id: id},
+	)
+	h.NoError(err, "AccountLedger")
+
+	return resp
+}
+
+// RemoveAccount makes a RPC call to the node's RemoveAccount and asserts.
+func (h *HarnessRPC) RemoveAccount(id string) {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	_, err := h.LN.RemoveAccount(ctxt, &lnrpc.RemoveAccountRequest{Id: id})
+	h.NoError(err, "RemoveAccount")
+}
+
+// SetForwardingRules makes a RPC call to the node's SetForwardingRules and
+// asserts.
+func (h *HarnessRPC) SetForwardingRules(rules []*lnrpc.ForwardingRule) {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	_, err := h.LN.SetForwardingRules(
+		ctxt, &lnrpc.SetForwardingRulesRequest{Rules: rules},
+	)
+	h.NoError(err, "SetForwardingRules")
+}
+
+// ListForwardingRules makes a RPC call to the node's ListForwardingRules and
+// asserts.
+func (h *HarnessRPC) ListForwardingRules() *lnrpc.ListForwardingRulesResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.ListForwardingRules(
+		ctxt, &lnrpc.ListForwardingRulesRequest{},
+	)
+	h.NoError(err, "ListForwardingRules")
+
+	return resp
+}
+
+// GetNodeMetrics makes a RPC call to the node's GetNodeMetrics and asserts.
+func (h *HarnessRPC) GetNodeMetrics(
+	req *lnrpc.NodeMetricsRequest) *lnrpc.NodeMetricsResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.GetNodeMetrics(ctxt, req)
+	h.NoError(err, "GetNodeMetrics")
+
+	return resp
+}
+
+// GetGraphMetrics makes a RPC call to the node's GetGraphMetrics and asserts.
+func (h *HarnessRPC) GetGraphMetrics() *lnrpc.GraphMetricsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.LN.GetGraphMetrics(ctxt, &lnrpc.GraphMetricsRequest{})
+	h.NoError(err, "GetGraphMetrics")
+
+	return resp
+}
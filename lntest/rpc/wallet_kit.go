@@ -254,6 +254,22 @@ func (h *HarnessRPC) BumpFee(
 	return resp
 }
 
+// OverrideSweepParams makes a RPC call to the node's WalletKitClient and
+// asserts.
+//
+//nolint:lll
+func (h *HarnessRPC) OverrideSweepParams(
+	req *walletrpc.OverrideSweepParamsRequest) *walletrpc.OverrideSweepParamsResponse {
+
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	resp, err := h.WalletKit.OverrideSweepParams(ctxt, req)
+	h.NoError(err, "OverrideSweepParams")
+
+	return resp
+}
+
 // BumpForceCloseFee makes a RPC call to the node's WalletKitClient and asserts.
 //
 //nolint:lll
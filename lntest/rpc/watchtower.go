@@ -96,3 +96,16 @@ func (h *HarnessRPC) WatchtowerStats() *wtclientrpc.StatsResponse {
 
 	return resp
 }
+
+// WatchtowerDBStats makes a RPC call to the WatchtowerClient of the given node
+// and asserts.
+func (h *HarnessRPC) WatchtowerDBStats() *wtclientrpc.DBStatsResponse {
+	ctxt, cancel := context.WithTimeout(h.runCtx, DefaultTimeout)
+	defer cancel()
+
+	req := &wtclientrpc.DBStatsRequest{}
+	resp, err := h.WatchtowerClient.DBStats(ctxt, req)
+	h.NoError(err, "DBStats from Watchtower")
+
+	return resp
+}
@@ -35,6 +35,11 @@ var (
 	// "do", short for "double".
 	PsbtKeyTypeInputSignatureTweakDouble = []byte{0xd0}
 
+	// PsbtKeyTypeInputTaprootAnnex is a custom/proprietary PSBT key for an
+	// input that specifies the taproot annex the signature of a script
+	// spend should commit to. The value 50 is the annex tag.
+	PsbtKeyTypeInputTaprootAnnex = []byte{0x50}
+
 	// ErrInputMissingUTXOInfo is returned if a PSBT input is supplied that
 	// does not specify the witness UTXO info.
 	ErrInputMissingUTXOInfo = errors.New(
@@ -240,6 +245,16 @@ func (b *BtcWallet) SignPsbt(packet *psbt.Packet) ([]uint32, error) {
 			return nil, err
 		}
 
+		// An annex is also sent as a custom/proprietary field. Only the
+		// signature of a script spend can commit to it.
+		annex := psbtAnnex(in.Unknowns)
+		if len(annex) > 0 &&
+			signMethod != input.TaprootScriptSpendSignMethod {
+
+			return nil, fmt.Errorf("annex not supported for sign "+
+				"method %v", signMethod)
+		}
+
 		switch signMethod {
 		// For p2wkh, np2wkh and p2wsh.
 		case input.WitnessV0SignMethod:
@@ -267,7 +282,7 @@ func (b *BtcWallet) SignPsbt(packet *psbt.Packet) ([]uint32, error) {
 				Script:      leafScript.Script,
 			}
 			err = signSegWitV1ScriptSpend(
-				in, tx, sigHashes, idx, privKey, leaf,
+				in, tx, sigHashes, idx, privKey, leaf, annex,
 			)
 
 		default:
@@ -490,14 +505,15 @@ func signSegWitV1KeySpend(in *psbt.PInput, tx *wire.MsgTx,
 }
 
 // signSegWitV1ScriptSpend attempts to generate a signature for a SegWit version
-// 1 (p2tr) input and stores it in the TaprootScriptSpendSig field.
+// 1 (p2tr) input and stores it in the TaprootScriptSpendSig field. If an annex
+// is given, the signature commits to it.
 func signSegWitV1ScriptSpend(in *psbt.PInput, tx *wire.MsgTx,
 	sigHashes *txscript.TxSigHashes, idx int, privKey *btcec.PrivateKey,
-	leaf txscript.TapLeaf) error {
+	leaf txscript.TapLeaf, annex []byte) error {
 
-	rawSig, err := txscript.RawTxInTapscriptSignature(
-		tx, sigHashes, idx, in.WitnessUtxo.Value,
-		in.WitnessUtxo.PkScript, leaf, in.SighashType, privKey,
+	rawSig, err := rawTapscriptSignature(
+		tx, sigHashes, idx, in.WitnessUtxo, leaf, in.SighashType,
+		annex, privKey,
 	)
 	if err != nil {
 		return fmt.Errorf("error signing taproot script input %d: %w",
@@ -565,6 +581,18 @@ func maybeTweakPrivKeyPsbt(unknowns []*psbt.Unknown,
 	return privKey
 }
 
+// psbtAnnex returns the taproot annex that is sent as a custom/proprietary
+// field in the PSBT input section, if any.
+func psbtAnnex(unknowns []*psbt.Unknown) []byte {
+	for _, u := range unknowns {
+		if bytes.Equal(u.Key, PsbtKeyTypeInputTaprootAnnex) {
+			return u.Value
+		}
+	}
+
+	return nil
+}
+
 // FinalizePsbt expects a partial transaction with all inputs and outputs fully
 // declared and tries to sign all inputs that belong to the specified account.
 // Lnd must be the last signer of the transaction. That means, if there are any
@@ -9,6 +9,7 @@ import (
 
 	"github.com/btcsuite/btcd/blockchain"
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg"
@@ -346,6 +347,120 @@ func TestSignPsbt(t *testing.T) {
 	}
 }
 
+// TestSignPsbtTaprootAnnex tests that the signature of a taproot script spend
+// commits to the annex that is sent as a custom field of the PSBT input.
+func TestSignPsbtTaprootAnnex(t *testing.T) {
+	w, _ := newTestWallet(t, netParams, seedBytes)
+
+	keyPath := singleKeyP2WSH.keyPath()
+	privKey, err := w.deriveKeyByBIP32Path(keyPath)
+	require.NoError(t, err)
+
+	leafScript, err := txscript.NewScriptBuilder().
+		AddData(schnorr.SerializePubKey(privKey.PubKey())).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	require.NoError(t, err)
+
+	leaf := txscript.NewBaseTapLeaf(leafScript)
+	leafHash := leaf.TapHash()
+	tree := txscript.AssembleTaprootScriptTree(leaf)
+	rootHash := tree.RootNode.TapHash()
+
+	internalKey, _ := btcec.PrivKeyFromBytes(testCommitSecretBytes)
+	outputKey := txscript.ComputeTaprootOutputKey(
+		internalKey.PubKey(), rootHash[:],
+	)
+	pkScript, err := txscript.PayToTaprootScript(outputKey)
+	require.NoError(t, err)
+
+	controlBlock := tree.LeafMerkleProofs[0].ToControlBlock(
+		internalKey.PubKey(),
+	)
+	controlBlockBytes, err := controlBlock.ToBytes()
+	require.NoError(t, err)
+
+	prevOut := &wire.TxOut{Value: testValue, PkScript: pkScript}
+	spendTx := wire.NewMsgTx(2)
+	spendTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 1},
+	})
+	spendTx.AddTxOut(prevOut)
+
+	annex := []byte{txscript.TaprootAnnexTag, 1, 2, 3}
+
+	newPacket := func(annex []byte) *psbt.Packet {
+		packet, err := psbt.NewFromUnsignedTx(spendTx)
+		require.NoError(t, err)
+
+		in := &packet.Inputs[0]
+		in.WitnessUtxo = prevOut
+		in.SighashType = txscript.SigHashDefault
+		in.Bip32Derivation = []*psbt.Bip32Derivation{{
+			PubKey:    privKey.PubKey().SerializeCompressed(),
+			Bip32Path: keyPath,
+		}}
+		in.TaprootBip32Derivation = []*psbt.TaprootBip32Derivation{{
+			XOnlyPubKey: schnorr.SerializePubKey(privKey.PubKey()),
+			LeafHashes:  [][]byte{leafHash[:]},
+			Bip32Path:   keyPath,
+		}}
+		in.TaprootLeafScript = []*psbt.TaprootTapLeafScript{{
+			ControlBlock: controlBlockBytes,
+			Script:       leafScript,
+			LeafVersion:  txscript.BaseLeafVersion,
+		}}
+		if len(annex) > 0 {
+			in.Unknowns = []*psbt.Unknown{{
+				Key:   PsbtKeyTypeInputTaprootAnnex,
+				Value: annex,
+			}}
+		}
+
+		return packet
+	}
+
+	// verify executes the script spend with the given witness.
+	verify := func(witness wire.TxWitness) error {
+		tx := spendTx.Copy()
+		tx.TxIn[0].Witness = witness
+
+		fetcher := txscript.NewCannedPrevOutputFetcher(
+			pkScript, testValue,
+		)
+		vm, err := txscript.NewEngine(
+			pkScript, tx, 0, txscript.StandardVerifyFlags, nil,
+			txscript.NewTxSigHashes(tx, fetcher), testValue,
+			fetcher,
+		)
+		require.NoError(t, err)
+
+		return vm.Execute()
+	}
+
+	packet := newPacket(annex)
+	signedInputs, err := w.SignPsbt(packet)
+	require.NoError(t, err)
+	require.Len(t, signedInputs, 1)
+	require.Len(t, packet.Inputs[0].TaprootScriptSpendSig, 1)
+
+	sig := packet.Inputs[0].TaprootScriptSpendSig[0].Signature
+	require.NoError(t, verify(wire.TxWitness{
+		sig, leafScript, controlBlockBytes, annex,
+	}))
+
+	// Without the annex in the witness, the signature is invalid, since
+	// it commits to the annex.
+	require.Error(t, verify(wire.TxWitness{
+		sig, leafScript, controlBlockBytes,
+	}))
+
+	// An annex without the annex tag is rejected.
+	packet = newPacket([]byte{1, 2, 3})
+	_, err = w.SignPsbt(packet)
+	require.ErrorIs(t, err, input.ErrInvalidAnnex)
+}
+
 // TestEstimateInputWeight tests that we correctly estimate the weight of a
 // PSBT input if it supplies all required information.
 func TestEstimateInputWeight(t *testing.T) {
@@ -343,6 +343,50 @@ func maybeTweakPrivKey(signDesc *input.SignDescriptor,
 	return retPriv, nil
 }
 
+// rawTapscriptSignature returns a schnorr signature for the script path spend
+// of the given leaf. If an annex is given, the signature commits to it.
+func rawTapscriptSignature(tx *wire.MsgTx, sigHashes *txscript.TxSigHashes,
+	idx int, prevOut *wire.TxOut, leaf txscript.TapLeaf,
+	hashType txscript.SigHashType, annex []byte,
+	privKey *btcec.PrivateKey) ([]byte, error) {
+
+	if len(annex) == 0 {
+		return txscript.RawTxInTapscriptSignature(
+			tx, sigHashes, idx, prevOut.Value, prevOut.PkScript,
+			leaf, hashType, privKey,
+		)
+	}
+
+	if annex[0] != txscript.TaprootAnnexTag {
+		return nil, input.ErrInvalidAnnex
+	}
+
+	sigHash, err := txscript.CalcTapscriptSignaturehash(
+		sigHashes, hashType, tx, idx,
+		txscript.NewCannedPrevOutputFetcher(
+			prevOut.PkScript, prevOut.Value,
+		),
+		leaf, txscript.WithAnnex(annex),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := schnorr.Sign(privKey, sigHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Like txscript, we only append the sighash type if it isn't the
+	// default one.
+	rawSig := sig.Serialize()
+	if hashType != txscript.SigHashDefault {
+		rawSig = append(rawSig, byte(hashType))
+	}
+
+	return rawSig, nil
+}
+
 // SignOutputRaw generates a signature for the passed transaction according to
 // the data within the passed SignDescriptor.
 //
@@ -382,6 +426,14 @@ func (b *BtcWallet) SignOutputRaw(tx *wire.MsgTx,
 		case input.TaprootKeySpendBIP0086SignMethod,
 			input.TaprootKeySpendSignMethod:
 
+			// The sighash of a key spend can't commit to an
+			// annex with the current txscript API.
+			if len(signDesc.Annex) > 0 {
+				return nil, fmt.Errorf("annex not supported "+
+					"for sign method %v",
+					signDesc.SignMethod)
+			}
+
 			// This function tweaks the private key using the tap
 			// root key supplied as the tweak.
 			rawSig, err = txscript.RawTxInTaprootSignature(
@@ -399,10 +451,10 @@ func (b *BtcWallet) SignOutputRaw(tx *wire.MsgTx,
 				LeafVersion: txscript.BaseLeafVersion,
 				Script:      witnessScript,
 			}
-			rawSig, err = txscript.RawTxInTapscriptSignature(
+			rawSig, err = rawTapscriptSignature(
 				tx, sigHashes, signDesc.InputIndex,
-				signDesc.Output.Value, signDesc.Output.PkScript,
-				leaf, signDesc.HashType, privKey,
+				signDesc.Output, leaf, signDesc.HashType,
+				signDesc.Annex, privKey,
 			)
 			if err != nil {
 				return nil, err
@@ -7649,6 +7649,11 @@ type AnchorResolution struct {
 
 	// CommitWeight is the weight of the commit tx.
 	CommitWeight lntypes.WeightUnit
+
+	// CommitTx is the fully signed commit tx, if known. It's only set for
+	// our own commitment once we broadcast it, and allows the anchor to be
+	// swept in a package together with the commitment.
+	CommitTx *wire.MsgTx
 }
 
 // LocalForceCloseSummary describes the final commitment state before the
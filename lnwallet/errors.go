@@ -152,6 +152,26 @@ func ErrChanTooLarge(chanSize, maxChanSize btcutil.Amount) ReservationError {
 	}
 }
 
+// ErrCommitFormatNotAllowed returns an error indicating that an incoming
+// channel request uses a commitment format our inbound channel policy doesn't
+// allow.
+func ErrCommitFormatNotAllowed(
+	format lnwire.CommitmentFormat) ReservationError {
+
+	return ReservationError{
+		fmt.Errorf("commitment format %v is not allowed", format),
+	}
+}
+
+// ErrRequiredFeatureMissing returns an error indicating that the peer of an
+// incoming channel request doesn't signal a feature our inbound channel policy
+// requires.
+func ErrRequiredFeatureMissing(bit lnwire.FeatureBit) ReservationError {
+	return ReservationError{
+		fmt.Errorf("required feature bit %d is not signaled", bit),
+	}
+}
+
 // ErrInvalidDustLimit returns an error indicating that a proposed DustLimit
 // was rejected.
 func ErrInvalidDustLimit(dustLimit btcutil.Amount) ReservationError {
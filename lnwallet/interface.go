@@ -587,6 +587,19 @@ type BlockChainIO interface {
 	GetBlockHeader(blockHash *chainhash.Hash) (*wire.BlockHeader, error)
 }
 
+// PackageSubmitter is implemented by chain backends that support package
+// relay. It allows a parent transaction that pays too little fees to enter the
+// mempool on its own, such as a commitment transaction, to be broadcast
+// together with a child that pays for both.
+type PackageSubmitter interface {
+	// SubmitPackage submits the given parent transactions together with
+	// a child that spends from them to the mempool of the backend, such
+	// that the parents are accepted based on the fees of the whole
+	// package. Parents that are already in the mempool or confirmed are
+	// skipped.
+	SubmitPackage(parents []*wire.MsgTx, child *wire.MsgTx) error
+}
+
 // MessageSigner represents an abstract object capable of signing arbitrary
 // messages. The capabilities of this interface are used to sign announcements
 // to the network, or just arbitrary messages that leverage the wallet's keys
@@ -0,0 +1,92 @@
+package lnwallet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+)
+
+// ReservedUtxo is a wallet output that is locked for the funding transaction
+// of a pending channel reservation.
+type ReservedUtxo struct {
+	// OutPoint is the locked output.
+	OutPoint wire.OutPoint
+
+	// PendingChanID is the pending channel ID of the reservation the
+	// output is locked for.
+	PendingChanID [32]byte
+
+	// Expiry is the time at which the lock of the output expires. The
+	// reservation should be canceled by then, as the output can be
+	// selected for other transactions afterwards.
+	Expiry time.Time
+}
+
+// trackReservedUtxos records the inputs our wallet contributes to the given
+// reservation as locked until the lock of the coin selection expires.
+//
+// NOTE: The limboMtx MUST be held when calling this method.
+func (l *LightningWallet) trackReservedUtxos(res *ChannelReservation) {
+	expiry := time.Now().Add(chanfunding.DefaultReservationTimeout)
+	for _, txIn := range res.ourContribution.Inputs {
+		l.lockedOutPoints[txIn.PreviousOutPoint] = &ReservedUtxo{
+			OutPoint:      txIn.PreviousOutPoint,
+			PendingChanID: res.pendingChanID,
+			Expiry:        expiry,
+		}
+	}
+}
+
+// untrackReservedUtxos removes the inputs of the given reservation from the
+// set of locked outpoints once the reservation completed. The outputs stay
+// locked until the funding transaction spends them or the lock expires.
+//
+// NOTE: The limboMtx MUST be held when calling this method.
+func (l *LightningWallet) untrackReservedUtxos(res *ChannelReservation) {
+	for _, txIn := range res.ourContribution.Inputs {
+		delete(l.lockedOutPoints, txIn.PreviousOutPoint)
+	}
+}
+
+// ReservedUtxos returns the wallet outputs that are currently locked for the
+// funding transactions of pending reservations.
+func (l *LightningWallet) ReservedUtxos() []ReservedUtxo {
+	l.limboMtx.RLock()
+	defer l.limboMtx.RUnlock()
+
+	utxos := make([]ReservedUtxo, 0, len(l.lockedOutPoints))
+	for _, utxo := range l.lockedOutPoints {
+		utxos = append(utxos, *utxo)
+	}
+
+	return utxos
+}
+
+// CancelReservation cancels the pending reservation of the given pending
+// channel ID, releasing the wallet outputs locked for its funding
+// transaction.
+func (l *LightningWallet) CancelReservation(pendingChanID [32]byte) error {
+	l.limboMtx.RLock()
+	id, ok := l.reservationIDs[pendingChanID]
+	l.limboMtx.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("no reservation for pending channel %x",
+			pendingChanID[:])
+	}
+
+	errChan := make(chan error, 1)
+	select {
+	case l.msgChan <- &fundingReserveCancelMsg{
+		pendingFundingID: id,
+		err:              errChan,
+	}:
+	case <-l.quit:
+		return errors.New("wallet shutting down")
+	}
+
+	return <-errChan
+}
@@ -1098,6 +1098,12 @@ func (r *RPCKeyRing) remoteSign(tx *wire.MsgTx, signDesc *input.SignDescriptor,
 			Value: signDesc.DoubleTweak.Serialize(),
 		})
 	}
+	if len(signDesc.Annex) > 0 {
+		in.Unknowns = append(in.Unknowns, &psbt.Unknown{
+			Key:   btcwallet.PsbtKeyTypeInputTaprootAnnex,
+			Value: signDesc.Annex,
+		})
+	}
 
 	// Add taproot specific fields.
 	switch signDesc.SignMethod {
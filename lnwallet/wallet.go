@@ -443,10 +443,13 @@ type LightningWallet struct {
 	reservationIDs map[[32]byte]uint64
 	limboMtx       sync.RWMutex
 
-	// lockedOutPoints is a set of the currently locked outpoint. This
-	// information is kept in order to provide an easy way to unlock all
-	// the currently locked outpoints.
-	lockedOutPoints map[wire.OutPoint]struct{}
+	// lockedOutPoints are the outpoints currently locked for the funding
+	// transactions of pending reservations. This information is kept in
+	// order to report the locked outpoints together with their expiry, and
+	// to provide an easy way to unlock all of them.
+	//
+	// NOTE: This map is protected by the limboMtx.
+	lockedOutPoints map[wire.OutPoint]*ReservedUtxo
 
 	// fundingIntents houses all the "interception" registered by a caller
 	// using the RegisterFundingIntent method.
@@ -471,7 +474,7 @@ func NewLightningWallet(Cfg Config) (*LightningWallet, error) {
 		nextFundingID:    0,
 		fundingLimbo:     make(map[uint64]*ChannelReservation),
 		reservationIDs:   make(map[[32]byte]uint64),
-		lockedOutPoints:  make(map[wire.OutPoint]struct{}),
+		lockedOutPoints:  make(map[wire.OutPoint]*ReservedUtxo),
 		fundingIntents:   make(map[[32]byte]chanfunding.Intent),
 		quit:             make(chan struct{}),
 	}, nil
@@ -617,6 +620,9 @@ func (l *LightningWallet) ListUnspentWitnessFromDefaultAccount(
 
 // LockedOutpoints returns a list of all currently locked outpoint.
 func (l *LightningWallet) LockedOutpoints() []*wire.OutPoint {
+	l.limboMtx.RLock()
+	defer l.limboMtx.RUnlock()
+
 	outPoints := make([]*wire.OutPoint, 0, len(l.lockedOutPoints))
 	for outPoint := range l.lockedOutPoints {
 		outPoint := outPoint
@@ -630,6 +636,9 @@ func (l *LightningWallet) LockedOutpoints() []*wire.OutPoint {
 // ResetReservations reset the volatile wallet state which tracks all currently
 // active reservations.
 func (l *LightningWallet) ResetReservations() {
+	l.limboMtx.Lock()
+	defer l.limboMtx.Unlock()
+
 	l.nextFundingID = 0
 	l.fundingLimbo = make(map[uint64]*ChannelReservation)
 	l.reservationIDs = make(map[[32]byte]uint64)
@@ -637,7 +646,7 @@ func (l *LightningWallet) ResetReservations() {
 	for outpoint := range l.lockedOutPoints {
 		_ = l.ReleaseOutput(chanfunding.LndInternalLockID, outpoint)
 	}
-	l.lockedOutPoints = make(map[wire.OutPoint]struct{})
+	l.lockedOutPoints = make(map[wire.OutPoint]*ReservedUtxo)
 }
 
 // ActiveReservations returns a slice of all the currently active
@@ -1092,6 +1101,7 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 	l.limboMtx.Lock()
 	l.fundingLimbo[id] = reservation
 	l.reservationIDs[req.PendingChanID] = id
+	l.trackReservedUtxos(reservation)
 	l.limboMtx.Unlock()
 
 	// Funding reservation request successfully handled. The funding inputs
@@ -2300,6 +2310,7 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	l.limboMtx.Lock()
 	delete(l.fundingLimbo, res.reservationID)
 	delete(l.reservationIDs, res.pendingChanID)
+	l.untrackReservedUtxos(res)
 	l.limboMtx.Unlock()
 
 	l.intentMtx.Lock()
@@ -2543,6 +2554,7 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 	l.limboMtx.Lock()
 	delete(l.fundingLimbo, req.pendingFundingID)
 	delete(l.reservationIDs, pendingReservation.pendingChanID)
+	l.untrackReservedUtxos(pendingReservation)
 	l.limboMtx.Unlock()
 
 	l.intentMtx.Lock()
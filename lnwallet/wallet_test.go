@@ -2,7 +2,10 @@ package lnwallet
 
 import (
 	"testing"
+	"time"
 
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,3 +35,46 @@ func TestRegisterFundingIntent(t *testing.T) {
 	err = lw.RegisterFundingIntent(testID, nil)
 	require.ErrorIs(err, ErrDuplicatePendingChanID)
 }
+
+// TestReservedUtxos checks that the inputs of a reservation are reported as
+// reserved until the reservation completes.
+func TestReservedUtxos(t *testing.T) {
+	t.Parallel()
+
+	lw, err := NewLightningWallet(Config{})
+	require.NoError(t, err)
+
+	var pendingChanID [32]byte
+	pendingChanID[0] = 1
+
+	op := wire.OutPoint{Index: 1}
+	res := &ChannelReservation{
+		pendingChanID: pendingChanID,
+		ourContribution: &ChannelContribution{
+			Inputs: []*wire.TxIn{{PreviousOutPoint: op}},
+		},
+	}
+
+	before := time.Now()
+	lw.limboMtx.Lock()
+	lw.trackReservedUtxos(res)
+	lw.limboMtx.Unlock()
+
+	utxos := lw.ReservedUtxos()
+	require.Len(t, utxos, 1)
+	require.Equal(t, op, utxos[0].OutPoint)
+	require.Equal(t, pendingChanID, utxos[0].PendingChanID)
+	require.False(t, utxos[0].Expiry.Before(
+		before.Add(chanfunding.DefaultReservationTimeout),
+	))
+	require.Equal(t, []*wire.OutPoint{&op}, lw.LockedOutpoints())
+
+	lw.limboMtx.Lock()
+	lw.untrackReservedUtxos(res)
+	lw.limboMtx.Unlock()
+
+	require.Empty(t, lw.ReservedUtxos())
+
+	// Canceling an unknown reservation fails.
+	require.Error(t, lw.CancelReservation(pendingChanID))
+}
@@ -0,0 +1,103 @@
+package lnwire
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// AttributionMaxHops is the maximum number of hops for which
+	// attribution data can be provided.
+	AttributionMaxHops = 20
+
+	// AttributionHoldTimeSize is the size of the hold time of a single
+	// hop.
+	AttributionHoldTimeSize = 4
+
+	// AttributionHMACSize is the size of a single truncated HMAC.
+	AttributionHMACSize = 4
+
+	// AttributionNumHMACs is the number of HMACs in the attribution data.
+	// As a hop doesn't know its position in the route, it provides an
+	// HMAC for every position it may be at. The further downstream a hop
+	// is, the fewer positions remain possible, which results in a
+	// triangular layout.
+	AttributionNumHMACs = AttributionMaxHops * (AttributionMaxHops + 1) / 2
+
+	// AttributionHoldTimesSize is the size of the hold times section of
+	// the attribution data.
+	AttributionHoldTimesSize = AttributionMaxHops * AttributionHoldTimeSize
+
+	// AttributionDataSize is the total size of the attribution data.
+	AttributionDataSize = AttributionHoldTimesSize +
+		AttributionNumHMACs*AttributionHMACSize
+
+	// AttributionDataType is the TLV type of the attribution data in the
+	// extra data of an update_fail_htlc message.
+	AttributionDataType tlv.Type = 1
+
+	// HoldTimeUnit is the unit in which hold times are reported in the
+	// attribution data.
+	HoldTimeUnit = 100 * time.Millisecond
+)
+
+// AttributionData is the data that is attached to a failed HTLC to attribute
+// the failure to a specific hop, even if the failure message itself has been
+// corrupted. It also carries the time that each hop held the HTLC. The data is
+// obfuscated by every hop on the way back to the sender.
+type AttributionData [AttributionDataSize]byte
+
+// AttributionData returns the attribution data that is included in the extra
+// data of the message, if any.
+func (c *UpdateFailHTLC) AttributionData() (fn.Option[AttributionData],
+	error) {
+
+	var data []byte
+	record := tlv.MakePrimitiveRecord(AttributionDataType, &data)
+
+	typeMap, err := c.ExtraData.ExtractRecords(&record)
+	if err != nil {
+		return fn.None[AttributionData](), err
+	}
+
+	if val, ok := typeMap[AttributionDataType]; !ok || val != nil {
+		return fn.None[AttributionData](), nil
+	}
+
+	if len(data) != AttributionDataSize {
+		return fn.None[AttributionData](), fmt.Errorf("invalid "+
+			"attribution data length: %d", len(data))
+	}
+
+	var attrData AttributionData
+	copy(attrData[:], data)
+
+	return fn.Some(attrData), nil
+}
+
+// SetAttributionData includes the given attribution data in the extra data of
+// the message, replacing any existing attribution data.
+func (c *UpdateFailHTLC) SetAttributionData(attrData AttributionData) error {
+	producers, err := c.ExtraData.RecordProducers()
+	if err != nil {
+		return err
+	}
+
+	records := make([]tlv.RecordProducer, 0, len(producers)+1)
+	for _, producer := range producers {
+		rec := producer.Record()
+		if rec.Type() == AttributionDataType {
+			continue
+		}
+
+		records = append(records, producer)
+	}
+
+	data := attrData[:]
+	record := tlv.MakePrimitiveRecord(AttributionDataType, &data)
+
+	return c.ExtraData.PackRecords(append(records, &record)...)
+}
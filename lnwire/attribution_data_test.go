@@ -0,0 +1,54 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateFailHTLCAttributionData asserts that attribution data can be
+// attached to an update_fail_htlc message and survives an encoding round trip
+// without affecting other extra data.
+func TestUpdateFailHTLCAttributionData(t *testing.T) {
+	t.Parallel()
+
+	msg := &UpdateFailHTLC{
+		ID:     1,
+		Reason: OpaqueReason(bytes.Repeat([]byte{1}, 32)),
+	}
+
+	// Without any extra data, no attribution data is returned.
+	attrData, err := msg.AttributionData()
+	require.NoError(t, err)
+	require.True(t, attrData.IsNone())
+
+	// Add an unrelated record to the extra data, which must be preserved
+	// when setting the attribution data.
+	otherData := []byte("other")
+	otherRecord := &recordProducer{
+		tlv.MakePrimitiveRecord(3, &otherData),
+	}
+	require.NoError(t, msg.ExtraData.PackRecords(otherRecord))
+
+	var data AttributionData
+	copy(data[:], bytes.Repeat([]byte{2}, AttributionDataSize))
+	require.NoError(t, msg.SetAttributionData(data))
+
+	var b bytes.Buffer
+	require.NoError(t, msg.Encode(&b, 0))
+
+	var decoded UpdateFailHTLC
+	require.NoError(t, decoded.Decode(&b, 0))
+
+	attrData, err = decoded.AttributionData()
+	require.NoError(t, err)
+	require.Equal(t, data, attrData.UnwrapOrFail(t))
+
+	var decodedOther []byte
+	decodedRecord := tlv.MakePrimitiveRecord(3, &decodedOther)
+	_, err = decoded.ExtraData.ExtractRecords(&decodedRecord)
+	require.NoError(t, err)
+	require.Equal(t, []byte("other"), decodedOther)
+}
@@ -14,6 +14,20 @@ const (
 	// MinCustomRecordsTlvType is the minimum custom records TLV type as
 	// defined in BOLT 01.
 	MinCustomRecordsTlvType = 65536
+
+	// ExperimentalEndorsementType is the TLV type used for a custom
+	// record that sets an experimental endorsement value.
+	ExperimentalEndorsementType = MinCustomRecordsTlvType + 555
+
+	// ExperimentalUnendorsed is the value that the experimental endorsement
+	// field contains when a HTLC is not endorsed.
+	ExperimentalUnendorsed = 0
+
+	// ExperimentalEndorsed is the value that the experimental endorsement
+	// field contains when a HTLC is endorsed. The endorsement value is a
+	// single byte of which only the first three bits are used, so an
+	// endorsed HTLC has all three of them set.
+	ExperimentalEndorsed = 7
 )
 
 // CustomRecords stores a set of custom key/value pairs. Map keys are TLV types
@@ -171,6 +171,18 @@ const (
 	// sender-generated preimages according to BOLT XX.
 	AMPOptional FeatureBit = 31
 
+	// QuiescenceRequired is a required feature bit that denotes that a
+	// connection established with this node must support the quiescence
+	// protocol, which allows the channel state to be locked using the
+	// stfu message.
+	QuiescenceRequired FeatureBit = 34
+
+	// QuiescenceOptional is an optional feature bit that denotes that a
+	// connection established with this node may use the quiescence
+	// protocol, which allows the channel state to be locked using the
+	// stfu message.
+	QuiescenceOptional FeatureBit = 35
+
 	// ExplicitChannelTypeRequired is a required bit that denotes that a
 	// connection established with this node is to use explicit channel
 	// commitment types for negotiation instead of the existing implicit
@@ -223,6 +235,18 @@ const (
 	// able and willing to accept keysend payments.
 	KeysendOptional = 55
 
+	// TrampolineRoutingRequired is a required feature bit that signals
+	// that the node is able to act as a trampoline node, meaning it will
+	// find a route to the next trampoline node or the final recipient on
+	// behalf of the sender.
+	TrampolineRoutingRequired FeatureBit = 56
+
+	// TrampolineRoutingOptional is an optional feature bit that signals
+	// that the node is able to act as a trampoline node, meaning it will
+	// find a route to the next trampoline node or the final recipient on
+	// behalf of the sender.
+	TrampolineRoutingOptional FeatureBit = 57
+
 	// ScriptEnforcedLeaseRequired is a required feature bit that signals
 	// that the node requires channels having zero-fee second-level HTLC
 	// transactions, which also imply anchor commitments, along with an
@@ -327,12 +351,16 @@ var Features = map[FeatureBit]string{
 	WumboChannelsOptional:                "wumbo-channels",
 	AMPRequired:                          "amp",
 	AMPOptional:                          "amp",
+	QuiescenceRequired:                   "quiescence",
+	QuiescenceOptional:                   "quiescence",
 	PaymentMetadataOptional:              "payment-metadata",
 	PaymentMetadataRequired:              "payment-metadata",
 	ExplicitChannelTypeOptional:          "explicit-commitment-type",
 	ExplicitChannelTypeRequired:          "explicit-commitment-type",
 	KeysendOptional:                      "keysend",
 	KeysendRequired:                      "keysend",
+	TrampolineRoutingOptional:            "trampoline-routing",
+	TrampolineRoutingRequired:            "trampoline-routing",
 	ScriptEnforcedLeaseRequired:          "script-enforced-lease",
 	ScriptEnforcedLeaseOptional:          "script-enforced-lease",
 	ScidAliasRequired:                    "scid-alias",
@@ -0,0 +1,273 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// InboundChanPolicyRecordType is the TLV type under which a node
+	// publishes the requirements for the channels it accepts in the extra
+	// opaque data of its node announcement. The type is odd, so nodes that
+	// don't understand it ignore it.
+	InboundChanPolicyRecordType tlv.Type = 65543
+
+	// The types of the records within an inbound channel policy.
+	inboundPolicyMinChanSizeType      tlv.Type = 0
+	inboundPolicyCommitFormatsType    tlv.Type = 2
+	inboundPolicyRequiredFeaturesType tlv.Type = 4
+)
+
+// maxCommitmentFormats is the number of commitment formats the bit set of an
+// inbound channel policy can hold.
+const maxCommitmentFormats = 64
+
+// CommitmentFormat identifies a commitment format in an inbound channel
+// policy.
+type CommitmentFormat uint8
+
+const (
+	// CommitmentFormatLegacy is the legacy commitment format with a
+	// tweaked to_remote key.
+	CommitmentFormatLegacy CommitmentFormat = 0
+
+	// CommitmentFormatStaticRemoteKey is the commitment format with a
+	// static to_remote key.
+	CommitmentFormatStaticRemoteKey CommitmentFormat = 1
+
+	// CommitmentFormatAnchors is the commitment format with anchor outputs
+	// and zero-fee second level HTLC transactions.
+	CommitmentFormatAnchors CommitmentFormat = 2
+
+	// CommitmentFormatScriptEnforcedLease is the anchors commitment format
+	// with a CLTV clause on the outputs paying to the channel initiator.
+	CommitmentFormatScriptEnforcedLease CommitmentFormat = 3
+
+	// CommitmentFormatSimpleTaproot is the commitment format of simple
+	// taproot channels.
+	CommitmentFormatSimpleTaproot CommitmentFormat = 4
+
+	// CommitmentFormatSimpleTaprootOverlay is the commitment format of
+	// simple taproot channels with a custom overlay.
+	CommitmentFormatSimpleTaprootOverlay CommitmentFormat = 5
+)
+
+// String returns a human readable version of the commitment format.
+func (c CommitmentFormat) String() string {
+	switch c {
+	case CommitmentFormatLegacy:
+		return "legacy"
+
+	case CommitmentFormatStaticRemoteKey:
+		return "static-remote-key"
+
+	case CommitmentFormatAnchors:
+		return "anchors"
+
+	case CommitmentFormatScriptEnforcedLease:
+		return "script-enforced-lease"
+
+	case CommitmentFormatSimpleTaproot:
+		return "simple-taproot"
+
+	case CommitmentFormatSimpleTaprootOverlay:
+		return "simple-taproot-overlay"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
+
+// InboundChanPolicy are the requirements a node publishes for the channels
+// that peers open to it, so that peers can skip channel opens that would be
+// rejected anyway.
+type InboundChanPolicy struct {
+	// MinChanSize is the smallest channel the node accepts.
+	MinChanSize btcutil.Amount
+
+	// CommitmentFormats is the bit set of the commitment formats the node
+	// accepts, where bit n stands for CommitmentFormat n. If no bit is
+	// set, all commitment formats are accepted.
+	CommitmentFormats uint64
+
+	// RequiredFeatures are the feature bits a peer must signal, as
+	// optional or required, in its init message to open a channel.
+	RequiredFeatures *RawFeatureVector
+}
+
+// AllowCommitmentFormat adds the given commitment format to the formats the
+// policy accepts.
+func (p *InboundChanPolicy) AllowCommitmentFormat(c CommitmentFormat) {
+	if c >= maxCommitmentFormats {
+		return
+	}
+
+	p.CommitmentFormats |= 1 << c
+}
+
+// AllowsCommitmentFormat returns true if the policy accepts channels of the
+// given commitment format.
+func (p *InboundChanPolicy) AllowsCommitmentFormat(c CommitmentFormat) bool {
+	if p.CommitmentFormats == 0 {
+		return true
+	}
+
+	if c >= maxCommitmentFormats {
+		return false
+	}
+
+	return p.CommitmentFormats&(1<<c) != 0
+}
+
+// MissingFeature returns a feature bit of the policy's required features that
+// the given feature vector doesn't signal, either as optional or required.
+// False is returned if all required features are signaled.
+func (p *InboundChanPolicy) MissingFeature(
+	features *RawFeatureVector) (FeatureBit, bool) {
+
+	if p.RequiredFeatures == nil {
+		return 0, false
+	}
+
+	for bit := range p.RequiredFeatures.features {
+		if features != nil &&
+			(features.IsSet(bit) || features.IsSet(bit^1)) {
+
+			continue
+		}
+
+		return bit, true
+	}
+
+	return 0, false
+}
+
+// Encode serializes the inbound channel policy as a TLV stream.
+func (p *InboundChanPolicy) Encode(w io.Writer) error {
+	features := p.RequiredFeatures
+	if features == nil {
+		features = NewRawFeatureVector()
+	}
+
+	var featureBytes bytes.Buffer
+	if err := features.Encode(&featureBytes); err != nil {
+		return err
+	}
+
+	minChanSize := uint64(p.MinChanSize)
+	rawFeatures := featureBytes.Bytes()
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(
+			inboundPolicyMinChanSizeType, &minChanSize,
+		),
+		tlv.MakePrimitiveRecord(
+			inboundPolicyCommitFormatsType, &p.CommitmentFormats,
+		),
+		tlv.MakePrimitiveRecord(
+			inboundPolicyRequiredFeaturesType, &rawFeatures,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// Decode deserializes an inbound channel policy from a TLV stream.
+func (p *InboundChanPolicy) Decode(r io.Reader) error {
+	var (
+		minChanSize uint64
+		rawFeatures []byte
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(
+			inboundPolicyMinChanSizeType, &minChanSize,
+		),
+		tlv.MakePrimitiveRecord(
+			inboundPolicyCommitFormatsType, &p.CommitmentFormats,
+		),
+		tlv.MakePrimitiveRecord(
+			inboundPolicyRequiredFeaturesType, &rawFeatures,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := stream.DecodeWithParsedTypesP2P(r); err != nil {
+		return err
+	}
+
+	features := NewRawFeatureVector()
+	err = features.Decode(bytes.NewReader(rawFeatures))
+	if err != nil {
+		return err
+	}
+
+	p.MinChanSize = btcutil.Amount(minChanSize)
+	p.RequiredFeatures = features
+
+	return nil
+}
+
+// SetInboundChanPolicy adds the given inbound channel policy to the extra
+// opaque data of a node announcement, replacing any existing one. Any other
+// records of the extra opaque data are kept. If policy is nil, only the
+// existing policy is removed.
+func SetInboundChanPolicy(extra *ExtraOpaqueData,
+	policy *InboundChanPolicy) error {
+
+	tlvMap, err := extra.ExtractRecords()
+	if err != nil {
+		return err
+	}
+	delete(tlvMap, InboundChanPolicyRecordType)
+
+	if policy != nil {
+		var b bytes.Buffer
+		if err := policy.Encode(&b); err != nil {
+			return err
+		}
+
+		tlvMap[InboundChanPolicyRecordType] = b.Bytes()
+	}
+
+	records := TlvMapToRecords(tlvMap)
+
+	return extra.PackRecords(RecordsAsProducers(records)...)
+}
+
+// ParseInboundChanPolicy extracts the inbound channel policy from the extra
+// opaque data of a node announcement. If the node doesn't publish a policy,
+// nil is returned.
+func ParseInboundChanPolicy(extra ExtraOpaqueData) (*InboundChanPolicy,
+	error) {
+
+	if len(extra) == 0 {
+		return nil, nil
+	}
+
+	tlvMap, err := extra.ExtractRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	policyBytes, ok := tlvMap[InboundChanPolicyRecordType]
+	if !ok {
+		return nil, nil
+	}
+
+	var policy InboundChanPolicy
+	if err := policy.Decode(bytes.NewReader(policyBytes)); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
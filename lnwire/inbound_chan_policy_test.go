@@ -0,0 +1,86 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInboundChanPolicy asserts that an inbound channel policy survives an
+// encoding round trip and that it can be added to, replaced in and removed
+// from the extra opaque data of a node announcement without touching its
+// other records.
+func TestInboundChanPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := &InboundChanPolicy{
+		MinChanSize: 1_000_000,
+		RequiredFeatures: NewRawFeatureVector(
+			ScidAliasOptional, AnchorsZeroFeeHtlcTxOptional,
+		),
+	}
+	policy.AllowCommitmentFormat(CommitmentFormatAnchors)
+	policy.AllowCommitmentFormat(CommitmentFormatSimpleTaproot)
+
+	var b bytes.Buffer
+	require.NoError(t, policy.Encode(&b))
+
+	var decoded InboundChanPolicy
+	require.NoError(t, decoded.Decode(&b))
+	require.Equal(t, policy, &decoded)
+
+	require.True(t, decoded.AllowsCommitmentFormat(CommitmentFormatAnchors))
+	require.False(
+		t, decoded.AllowsCommitmentFormat(CommitmentFormatLegacy),
+	)
+
+	// A peer signaling one of the features as required and the other one
+	// as optional satisfies the policy.
+	_, missing := decoded.MissingFeature(NewRawFeatureVector(
+		ScidAliasRequired, AnchorsZeroFeeHtlcTxOptional,
+	))
+	require.False(t, missing)
+
+	bit, missing := decoded.MissingFeature(NewRawFeatureVector(
+		AnchorsZeroFeeHtlcTxOptional,
+	))
+	require.True(t, missing)
+	require.Equal(t, ScidAliasOptional, bit)
+
+	// Without any extra data, there is no policy.
+	var extra ExtraOpaqueData
+	parsed, err := ParseInboundChanPolicy(extra)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+
+	const otherType tlv.Type = 65539
+	otherValue := []byte{1, 2, 3}
+	records := TlvMapToRecords(tlv.TypeMap{otherType: otherValue})
+	require.NoError(t, extra.PackRecords(RecordsAsProducers(records)...))
+
+	require.NoError(t, SetInboundChanPolicy(&extra, policy))
+
+	parsed, err = ParseInboundChanPolicy(extra)
+	require.NoError(t, err)
+	require.Equal(t, policy, parsed)
+
+	// Removing the policy keeps the other record.
+	require.NoError(t, SetInboundChanPolicy(&extra, nil))
+
+	parsed, err = ParseInboundChanPolicy(extra)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+
+	tlvMap, err := extra.ExtractRecords()
+	require.NoError(t, err)
+	require.Equal(t, otherValue, tlvMap[otherType])
+
+	// A policy without any commitment format allows all of them.
+	require.True(
+		t, (&InboundChanPolicy{}).AllowsCommitmentFormat(
+			CommitmentFormatLegacy,
+		),
+	)
+}
@@ -1670,6 +1670,71 @@ func TestLightningWireProtocol(t *testing.T) {
 				require.NoError(t, err)
 			}
 
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgNodeAnnouncement2: func(v []reflect.Value, r *rand.Rand) {
+			req := NodeAnnouncement2{
+				Signature:       testSchnorrSig,
+				ExtraOpaqueData: make([]byte, 0),
+			}
+
+			req.Features.Val = *randRawFeatureVector(r)
+			req.BlockHeight.Val = r.Uint32()
+			req.NodeID.Val = randRawKey(t)
+
+			// Sometimes set the color and the alias.
+			if r.Int31()%2 == 0 {
+				rgbColor := tlv.ZeroRecordT[
+					tlv.TlvType1, Color,
+				]()
+				rgbColor.Val = Color{
+					R: uint8(r.Int31()),
+					G: uint8(r.Int31()),
+					B: uint8(r.Int31()),
+				}
+				req.Color = tlv.SomeRecordT(rgbColor)
+
+				alias := tlv.ZeroRecordT[tlv.TlvType3, []byte]()
+				alias.Val = []byte("alias")
+				req.Alias = tlv.SomeRecordT(alias)
+			}
+
+			// Sometimes set an address of each type.
+			if r.Int31()%2 == 0 {
+				ipv4Addr, err := randTCP4Addr(r)
+				require.NoError(t, err)
+				ipv4Addrs := tlv.ZeroRecordT[
+					tlv.TlvType5, IPV4Addrs,
+				]()
+				ipv4Addrs.Val = IPV4Addrs{ipv4Addr}
+				req.IPV4Addrs = tlv.SomeRecordT(ipv4Addrs)
+
+				ipv6Addr, err := randTCP6Addr(r)
+				require.NoError(t, err)
+				ipv6Addrs := tlv.ZeroRecordT[
+					tlv.TlvType7, IPV6Addrs,
+				]()
+				ipv6Addrs.Val = IPV6Addrs{ipv6Addr}
+				req.IPV6Addrs = tlv.SomeRecordT(ipv6Addrs)
+
+				onionAddr, err := randV3OnionAddr(r)
+				require.NoError(t, err)
+				torV3Addrs := tlv.ZeroRecordT[
+					tlv.TlvType9, TorV3Addrs,
+				]()
+				torV3Addrs.Val = TorV3Addrs{onionAddr}
+				req.TorV3Addrs = tlv.SomeRecordT(torV3Addrs)
+			}
+
+			numExtraBytes := r.Int31n(1000)
+			if numExtraBytes > 0 {
+				req.ExtraOpaqueData = make(
+					[]byte, numExtraBytes,
+				)
+				_, err := r.Read(req.ExtraOpaqueData[:])
+				require.NoError(t, err)
+			}
+
 			v[0] = reflect.ValueOf(req)
 		},
 	}
@@ -1918,6 +1983,12 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgNodeAnnouncement2,
+			scenario: func(m NodeAnnouncement2) bool {
+				return mainScenario(&m)
+			},
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.msgType.String(), func(t *testing.T) {
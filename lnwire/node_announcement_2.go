@@ -0,0 +1,462 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"io"
+	"net"
+
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/lightningnetwork/lnd/tor"
+)
+
+const (
+	// ipv4AddrLen is the length of an encoded IPv4 address, consisting of
+	// the address followed by the port.
+	ipv4AddrLen = 4 + 2
+
+	// ipv6AddrLen is the length of an encoded IPv6 address, consisting of
+	// the address followed by the port.
+	ipv6AddrLen = 16 + 2
+
+	// torV3AddrLen is the length of an encoded Tor v3 onion address,
+	// consisting of the decoded onion service followed by the port.
+	torV3AddrLen = tor.V3DecodedLen + 2
+)
+
+// NodeAnnouncement2 message is used to announce the presence of a node
+// that has taproot channels, and to advertise its features and addresses. Its
+// block height takes the place of the timestamp of the legacy node
+// announcement.
+type NodeAnnouncement2 struct {
+	// Signature is a Schnorr signature of the node over the TLV stream of
+	// the message.
+	Signature Sig
+
+	// Features is the feature vector that encodes the features supported
+	// by the node.
+	Features tlv.RecordT[tlv.TlvType0, RawFeatureVector]
+
+	// Color is an optional color that is used to customize the node's
+	// appearance in maps and graphs.
+	Color tlv.OptionalRecordT[tlv.TlvType1, Color]
+
+	// BlockHeight allows ordering in the case of multiple announcements.
+	// We should ignore the message if the block height is not greater
+	// than the last-received.
+	BlockHeight tlv.RecordT[tlv.TlvType2, uint32]
+
+	// Alias is an optional alias of at most 32 bytes that is used to
+	// customize the node's appearance in maps and graphs.
+	Alias tlv.OptionalRecordT[tlv.TlvType3, []byte]
+
+	// NodeID is the public key of the node creating the announcement.
+	NodeID tlv.RecordT[tlv.TlvType4, [33]byte]
+
+	// IPV4Addrs is an optional list of the IPv4 addresses of the node.
+	IPV4Addrs tlv.OptionalRecordT[tlv.TlvType5, IPV4Addrs]
+
+	// IPV6Addrs is an optional list of the IPv6 addresses of the node.
+	IPV6Addrs tlv.OptionalRecordT[tlv.TlvType7, IPV6Addrs]
+
+	// TorV3Addrs is an optional list of the Tor v3 onion addresses of the
+	// node.
+	TorV3Addrs tlv.OptionalRecordT[tlv.TlvType9, TorV3Addrs]
+
+	// ExtraOpaqueData is the set of data that was appended to this
+	// message, some of which we may not actually know how to iterate or
+	// parse. By holding onto this data, we ensure that we're able to
+	// properly validate the set of signatures that cover these new fields,
+	// and ensure we're able to make upgrades to the network in a forwards
+	// compatible manner.
+	ExtraOpaqueData ExtraOpaqueData
+}
+
+// Decode deserializes a serialized NodeAnnouncement2 stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (n *NodeAnnouncement2) Decode(r io.Reader, _ uint32) error {
+	err := ReadElement(r, &n.Signature)
+	if err != nil {
+		return err
+	}
+	n.Signature.ForceSchnorr()
+
+	return n.DecodeTLVRecords(r)
+}
+
+// DecodeTLVRecords decodes only the TLV section of the message.
+func (n *NodeAnnouncement2) DecodeTLVRecords(r io.Reader) error {
+	// First extract into extra opaque data.
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	var (
+		rgbColor   = tlv.ZeroRecordT[tlv.TlvType1, Color]()
+		alias      = tlv.ZeroRecordT[tlv.TlvType3, []byte]()
+		ipv4Addrs  = tlv.ZeroRecordT[tlv.TlvType5, IPV4Addrs]()
+		ipv6Addrs  = tlv.ZeroRecordT[tlv.TlvType7, IPV6Addrs]()
+		torV3Addrs = tlv.ZeroRecordT[tlv.TlvType9, TorV3Addrs]()
+	)
+	typeMap, err := tlvRecords.ExtractRecords(
+		&n.Features, &rgbColor, &n.BlockHeight, &alias, &n.NodeID,
+		&ipv4Addrs, &ipv6Addrs, &torV3Addrs,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := typeMap[n.Color.TlvType()]; ok {
+		n.Color = tlv.SomeRecordT(rgbColor)
+	}
+
+	if _, ok := typeMap[n.Alias.TlvType()]; ok {
+		n.Alias = tlv.SomeRecordT(alias)
+	}
+
+	if _, ok := typeMap[n.IPV4Addrs.TlvType()]; ok {
+		n.IPV4Addrs = tlv.SomeRecordT(ipv4Addrs)
+	}
+
+	if _, ok := typeMap[n.IPV6Addrs.TlvType()]; ok {
+		n.IPV6Addrs = tlv.SomeRecordT(ipv6Addrs)
+	}
+
+	if _, ok := typeMap[n.TorV3Addrs.TlvType()]; ok {
+		n.TorV3Addrs = tlv.SomeRecordT(torV3Addrs)
+	}
+
+	if len(tlvRecords) != 0 {
+		n.ExtraOpaqueData = tlvRecords
+	}
+
+	return nil
+}
+
+// Encode serializes the target NodeAnnouncement2 into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (n *NodeAnnouncement2) Encode(w *bytes.Buffer, _ uint32) error {
+	_, err := w.Write(n.Signature.RawBytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = n.DataToSign()
+	if err != nil {
+		return err
+	}
+
+	return WriteBytes(w, n.ExtraOpaqueData)
+}
+
+// DataToSign encodes the data to be signed into the ExtraOpaqueData member and
+// returns it.
+func (n *NodeAnnouncement2) DataToSign() ([]byte, error) {
+	recordProducers := []tlv.RecordProducer{&n.Features}
+
+	n.Color.WhenSome(func(c tlv.RecordT[tlv.TlvType1, Color]) {
+		recordProducers = append(recordProducers, &c)
+	})
+
+	recordProducers = append(recordProducers, &n.BlockHeight)
+
+	n.Alias.WhenSome(func(a tlv.RecordT[tlv.TlvType3, []byte]) {
+		recordProducers = append(recordProducers, &a)
+	})
+
+	recordProducers = append(recordProducers, &n.NodeID)
+
+	n.IPV4Addrs.WhenSome(func(a tlv.RecordT[tlv.TlvType5, IPV4Addrs]) {
+		recordProducers = append(recordProducers, &a)
+	})
+
+	n.IPV6Addrs.WhenSome(func(a tlv.RecordT[tlv.TlvType7, IPV6Addrs]) {
+		recordProducers = append(recordProducers, &a)
+	})
+
+	n.TorV3Addrs.WhenSome(func(a tlv.RecordT[tlv.TlvType9, TorV3Addrs]) {
+		recordProducers = append(recordProducers, &a)
+	})
+
+	err := EncodeMessageExtraData(&n.ExtraOpaqueData, recordProducers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return n.ExtraOpaqueData, nil
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (n *NodeAnnouncement2) MsgType() MessageType {
+	return MsgNodeAnnouncement2
+}
+
+// A compile time check to ensure NodeAnnouncement2 implements the
+// lnwire.Message interface.
+var _ Message = (*NodeAnnouncement2)(nil)
+
+// Color is the RGB color of a node, encoded as three bytes.
+type Color color.RGBA
+
+// Record returns the tlv record of the color.
+func (c *Color) Record() tlv.Record {
+	return tlv.MakeStaticRecord(0, c, 3, encodeColor, decodeColor)
+}
+
+func encodeColor(w io.Writer, val interface{}, _ *[8]byte) error {
+	if v, ok := val.(*Color); ok {
+		_, err := w.Write([]byte{v.R, v.G, v.B})
+
+		return err
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.Color")
+}
+
+func decodeColor(r io.Reader, val interface{}, _ *[8]byte, l uint64) error {
+	if v, ok := val.(*Color); ok && l == 3 {
+		var rgb [3]byte
+		if _, err := io.ReadFull(r, rgb[:]); err != nil {
+			return err
+		}
+
+		*v = Color{R: rgb[0], G: rgb[1], B: rgb[2]}
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.Color", l, 3)
+}
+
+// IPV4Addrs is a list of IPv4 addresses, each encoded as the address followed
+// by the port.
+type IPV4Addrs []*net.TCPAddr
+
+// Record returns the tlv record of the address list.
+func (a *IPV4Addrs) Record() tlv.Record {
+	size := func() uint64 {
+		return uint64(len(*a) * ipv4AddrLen)
+	}
+
+	return tlv.MakeDynamicRecord(
+		0, a, size, encodeIPV4Addrs, decodeIPV4Addrs,
+	)
+}
+
+func encodeIPV4Addrs(w io.Writer, val interface{}, _ *[8]byte) error {
+	if v, ok := val.(*IPV4Addrs); ok {
+		for _, addr := range *v {
+			ip := addr.IP.To4()
+			if ip == nil {
+				return fmt.Errorf("%v is not an IPv4 address",
+					addr)
+			}
+
+			if err := writeIPAddr(w, ip, addr.Port); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.IPV4Addrs")
+}
+
+func decodeIPV4Addrs(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*IPV4Addrs); ok && l%ipv4AddrLen == 0 {
+		addrs := make(IPV4Addrs, 0, l/ipv4AddrLen)
+		for i := uint64(0); i < l/ipv4AddrLen; i++ {
+			addr, err := readIPAddr(r, net.IPv4len)
+			if err != nil {
+				return err
+			}
+
+			addrs = append(addrs, addr)
+		}
+		*v = addrs
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.IPV4Addrs", l, l)
+}
+
+// IPV6Addrs is a list of IPv6 addresses, each encoded as the address followed
+// by the port.
+type IPV6Addrs []*net.TCPAddr
+
+// Record returns the tlv record of the address list.
+func (a *IPV6Addrs) Record() tlv.Record {
+	size := func() uint64 {
+		return uint64(len(*a) * ipv6AddrLen)
+	}
+
+	return tlv.MakeDynamicRecord(
+		0, a, size, encodeIPV6Addrs, decodeIPV6Addrs,
+	)
+}
+
+func encodeIPV6Addrs(w io.Writer, val interface{}, _ *[8]byte) error {
+	if v, ok := val.(*IPV6Addrs); ok {
+		for _, addr := range *v {
+			ip := addr.IP.To16()
+			if ip == nil || addr.IP.To4() != nil {
+				return fmt.Errorf("%v is not an IPv6 address",
+					addr)
+			}
+
+			if err := writeIPAddr(w, ip, addr.Port); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.IPV6Addrs")
+}
+
+func decodeIPV6Addrs(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*IPV6Addrs); ok && l%ipv6AddrLen == 0 {
+		addrs := make(IPV6Addrs, 0, l/ipv6AddrLen)
+		for i := uint64(0); i < l/ipv6AddrLen; i++ {
+			addr, err := readIPAddr(r, net.IPv6len)
+			if err != nil {
+				return err
+			}
+
+			addrs = append(addrs, addr)
+		}
+		*v = addrs
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.IPV6Addrs", l, l)
+}
+
+// writeIPAddr writes the given IP address followed by the given port.
+func writeIPAddr(w io.Writer, ip net.IP, port int) error {
+	if _, err := w.Write(ip); err != nil {
+		return err
+	}
+
+	var p [2]byte
+	binary.BigEndian.PutUint16(p[:], uint16(port))
+	_, err := w.Write(p[:])
+
+	return err
+}
+
+// readIPAddr reads an IP address of the given length followed by a port.
+func readIPAddr(r io.Reader, ipLen int) (*net.TCPAddr, error) {
+	ip := make(net.IP, ipLen)
+	if _, err := io.ReadFull(r, ip); err != nil {
+		return nil, err
+	}
+
+	var p [2]byte
+	if _, err := io.ReadFull(r, p[:]); err != nil {
+		return nil, err
+	}
+
+	return &net.TCPAddr{
+		IP:   ip,
+		Port: int(binary.BigEndian.Uint16(p[:])),
+	}, nil
+}
+
+// TorV3Addrs is a list of Tor v3 onion addresses, each encoded as the decoded
+// onion service followed by the port.
+type TorV3Addrs []*tor.OnionAddr
+
+// Record returns the tlv record of the address list.
+func (a *TorV3Addrs) Record() tlv.Record {
+	size := func() uint64 {
+		return uint64(len(*a) * torV3AddrLen)
+	}
+
+	return tlv.MakeDynamicRecord(
+		0, a, size, encodeTorV3Addrs, decodeTorV3Addrs,
+	)
+}
+
+func encodeTorV3Addrs(w io.Writer, val interface{}, _ *[8]byte) error {
+	if v, ok := val.(*TorV3Addrs); ok {
+		for _, addr := range *v {
+			if len(addr.OnionService) != tor.V3Len {
+				return fmt.Errorf("%v is not a v3 onion "+
+					"address", addr)
+			}
+
+			suffixIndex := tor.V3Len - tor.OnionSuffixLen
+			host, err := tor.Base32Encoding.DecodeString(
+				addr.OnionService[:suffixIndex],
+			)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(host); err != nil {
+				return err
+			}
+
+			var p [2]byte
+			binary.BigEndian.PutUint16(p[:], uint16(addr.Port))
+			if _, err := w.Write(p[:]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.TorV3Addrs")
+}
+
+func decodeTorV3Addrs(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*TorV3Addrs); ok && l%torV3AddrLen == 0 {
+		addrs := make(TorV3Addrs, 0, l/torV3AddrLen)
+		for i := uint64(0); i < l/torV3AddrLen; i++ {
+			var h [tor.V3DecodedLen]byte
+			if _, err := io.ReadFull(r, h[:]); err != nil {
+				return err
+			}
+
+			var p [2]byte
+			if _, err := io.ReadFull(r, p[:]); err != nil {
+				return err
+			}
+
+			onionService := tor.Base32Encoding.EncodeToString(h[:])
+			onionService += tor.OnionSuffix
+			port := int(binary.BigEndian.Uint16(p[:]))
+
+			addrs = append(addrs, &tor.OnionAddr{
+				OnionService: onionService,
+				Port:         port,
+			})
+		}
+		*v = addrs
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.TorV3Addrs", l, l)
+}
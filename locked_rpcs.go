@@ -0,0 +1,282 @@
+package lnd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/chainreg"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/graph"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/rpcperms"
+	"google.golang.org/grpc"
+)
+
+// lockedRPCTokenSize is the size in bytes of the token that authenticates the
+// locked RPCs.
+const lockedRPCTokenSize = 32
+
+// lockedRPCServer serves the read-only RPCs that are configured to be
+// available while the wallet is locked. Neither the wallet nor the chain
+// backend are available at that point, so all responses are built from the
+// databases.
+type lockedRPCServer struct {
+	cfg *Config
+
+	graphDB *channeldb.ChannelGraph
+
+	chanStateDB *channeldb.ChannelStateDB
+
+	fwdLog *channeldb.ForwardingLog
+}
+
+// newLockedRPCServer creates a server for the locked RPCs that is backed by
+// the given databases.
+func newLockedRPCServer(cfg *Config,
+	dbs *DatabaseInstances) *lockedRPCServer {
+
+	return &lockedRPCServer{
+		cfg:         cfg,
+		graphDB:     dbs.GraphDB.ChannelGraph(),
+		chanStateDB: dbs.ChanStateDB.ChannelStateDB(),
+		fwdLog:      dbs.ChanStateDB.ForwardingLog(),
+	}
+}
+
+// register registers the handlers of the configured locked RPCs with the
+// interceptor chain, along with the token that authenticates them.
+func (l *lockedRPCServer) register(
+	interceptorChain *rpcperms.InterceptorChain) error {
+
+	if len(l.cfg.LockedRPC.Methods) == 0 {
+		return nil
+	}
+
+	token, err := loadLockedRPCToken(l.cfg.LockedRPC.TokenPath)
+	if err != nil {
+		return fmt.Errorf("unable to load locked RPC token: %w", err)
+	}
+	if err := interceptorChain.SetLockedRPCToken(token); err != nil {
+		return err
+	}
+
+	for _, method := range l.cfg.LockedRPC.Methods {
+		fullMethod, handler, err := l.handler(method)
+		if err != nil {
+			return err
+		}
+
+		err = interceptorChain.RegisterLockedHandler(
+			fullMethod, handler,
+		)
+		if err != nil {
+			return err
+		}
+
+		ltndLog.Infof("Serving %v with the token in %v while the "+
+			"wallet is locked", fullMethod,
+			l.cfg.LockedRPC.TokenPath)
+	}
+
+	return nil
+}
+
+// handler returns the full method name and the handler of the locked RPC with
+// the given name.
+func (l *lockedRPCServer) handler(method string) (string, grpc.UnaryHandler,
+	error) {
+
+	switch method {
+	case lncfg.LockedRPCGetInfo:
+		return "/lnrpc.Lightning/GetInfo", func(_ context.Context,
+			_ interface{}) (interface{}, error) {
+
+			return l.getInfo()
+		}, nil
+
+	case lncfg.LockedRPCDescribeGraph:
+		return "/lnrpc.Lightning/DescribeGraph", func(_ context.Context,
+			req interface{}) (interface{}, error) {
+
+			r, ok := req.(*lnrpc.ChannelGraphRequest)
+			if !ok {
+				return nil, fmt.Errorf("invalid request type "+
+					"%T", req)
+			}
+
+			return describeGraph(l.graphDB, r.IncludeUnannounced)
+		}, nil
+
+	case lncfg.LockedRPCForwardingHistory:
+		return "/lnrpc.Lightning/ForwardingHistory", func(
+			_ context.Context, req interface{}) (interface{},
+			error) {
+
+			r, ok := req.(*lnrpc.ForwardingHistoryRequest)
+			if !ok {
+				return nil, fmt.Errorf("invalid request type "+
+					"%T", req)
+			}
+
+			return queryForwardingHistory(l.fwdLog, r, l.peerAlias)
+		}, nil
+
+	default:
+		return "", nil, fmt.Errorf("RPC %v can't be served while the "+
+			"wallet is locked", method)
+	}
+}
+
+// getInfo returns the information of our node that is known without the
+// wallet. As the chain backend isn't started yet, the best block is the one
+// the channel graph was last synced to, and the node is never reported as
+// synced.
+func (l *lockedRPCServer) getInfo() (*lnrpc.GetInfoResponse, error) {
+	selfNode, err := l.graphDB.SourceNode()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch source node: %w", err)
+	}
+
+	openChannels, err := l.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	pendingChannels, err := l.chanStateDB.FetchPendingChannels()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get retrieve pending "+
+			"channels: %v", err)
+	}
+
+	pruneHash, pruneHeight, err := l.graphDB.PruneTip()
+	switch {
+	case errors.Is(err, channeldb.ErrGraphNeverPruned):
+	case err != nil:
+		return nil, fmt.Errorf("unable to fetch graph prune tip: %w",
+			err)
+	}
+
+	encodedIDPub := hex.EncodeToString(selfNode.PubKeyBytes[:])
+	uris := make([]string, len(selfNode.Addresses))
+	for i, addr := range selfNode.Addresses {
+		uris[i] = fmt.Sprintf("%s@%s", encodedIDPub, addr.String())
+	}
+
+	features := make(map[uint32]*lnrpc.Feature)
+	if selfNode.Features != nil {
+		features = invoicesrpc.CreateRPCFeatures(selfNode.Features)
+	}
+
+	network := lncfg.NormalizeNetwork(l.cfg.ActiveNetParams.Name)
+	isTestNet := chainreg.IsTestnet(&l.cfg.ActiveNetParams)
+	nodeColor := graph.EncodeHexColor(selfNode.Color)
+	version := build.Version() + " commit=" + build.Commit
+
+	// None of the links are active while the wallet is locked.
+	resp := &lnrpc.GetInfoResponse{
+		IdentityPubkey:      encodedIDPub,
+		NumPendingChannels:  uint32(len(pendingChannels)),
+		NumInactiveChannels: uint32(len(openChannels)),
+		BlockHeight:         pruneHeight,
+		Testnet:             isTestNet,
+		Chains: []*lnrpc.Chain{
+			{
+				Chain:   BitcoinChainName,
+				Network: network,
+			},
+		},
+		Uris:                      uris,
+		Alias:                     selfNode.Alias,
+		Color:                     nodeColor,
+		Version:                   version,
+		CommitHash:                build.CommitHash,
+		Features:                  features,
+		RequireHtlcInterceptor:    l.cfg.RequireInterceptor,
+		StoreFinalHtlcResolutions: l.cfg.StoreFinalHtlcResolutions,
+	}
+	if pruneHash != nil {
+		resp.BlockHash = pruneHash.String()
+	}
+
+	return resp, nil
+}
+
+// peerAlias looks up the alias of our peer in the channel with the given short
+// channel ID in the channel graph.
+func (l *lockedRPCServer) peerAlias(chanID lnwire.ShortChannelID) (string,
+	error) {
+
+	selfNode, err := l.graphDB.SourceNode()
+	if err != nil {
+		return "", err
+	}
+
+	edge, _, _, err := l.graphDB.FetchChannelEdgesByID(chanID.ToUint64())
+	if err != nil {
+		return "", err
+	}
+
+	remotePub := edge.NodeKey1Bytes
+	if remotePub == selfNode.PubKeyBytes {
+		remotePub = edge.NodeKey2Bytes
+	}
+
+	peer, err := l.graphDB.FetchLightningNode(remotePub)
+	if err != nil {
+		return "", err
+	}
+
+	return peer.Alias, nil
+}
+
+// loadLockedRPCToken reads the hex encoded token that authenticates the locked
+// RPCs from the given file. If the file doesn't exist, it is created with a
+// new random token that only the owner can read.
+func loadLockedRPCToken(path string) ([]byte, error) {
+	tokenHex, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		token, err := hex.DecodeString(strings.TrimSpace(
+			string(tokenHex),
+		))
+		if err != nil {
+			return nil, fmt.Errorf("invalid token in %v: %w", path,
+				err)
+		}
+		if len(token) < lockedRPCTokenSize {
+			return nil, fmt.Errorf("token in %v must be at least "+
+				"%d bytes", path, lockedRPCTokenSize)
+		}
+
+		return token, nil
+
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	token := make([]byte, lockedRPCTokenSize)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(path, []byte(hex.EncodeToString(token)), 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
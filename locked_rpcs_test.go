@@ -0,0 +1,100 @@
+package lnd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btclog/v2"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/rpcperms"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLockedRPCForwardingHistory tests that the forwarding history is served
+// from the forwarding log while the wallet is locked, and that its handler is
+// registered along with the token.
+func TestLockedRPCForwardingHistory(t *testing.T) {
+	t.Parallel()
+
+	db, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	events := []channeldb.ForwardingEvent{{
+		Timestamp:      time.Unix(1_700_000_000, 0),
+		IncomingChanID: lnwire.NewShortChanIDFromInt(1),
+		OutgoingChanID: lnwire.NewShortChanIDFromInt(2),
+		AmtIn:          2_000,
+		AmtOut:         1_000,
+	}, {
+		Timestamp:      time.Unix(1_700_000_060, 0),
+		IncomingChanID: lnwire.NewShortChanIDFromInt(2),
+		OutgoingChanID: lnwire.NewShortChanIDFromInt(1),
+		AmtIn:          5_000,
+		AmtOut:         4_000,
+	}}
+	require.NoError(t, db.ForwardingLog().AddForwardingEvents(events))
+
+	tokenPath := filepath.Join(t.TempDir(), "lockedrpc.token")
+	cfg := &Config{
+		LockedRPC: &lncfg.LockedRPC{
+			Methods: []string{
+				lncfg.LockedRPCGetInfo,
+				lncfg.LockedRPCDescribeGraph,
+				lncfg.LockedRPCForwardingHistory,
+			},
+			TokenPath: tokenPath,
+		},
+	}
+	server := newLockedRPCServer(cfg, &DatabaseInstances{
+		GraphDB:     db,
+		ChanStateDB: db,
+	})
+
+	// All the configured methods are registered with the interceptor
+	// chain, which creates the token.
+	chain := rpcperms.NewInterceptorChain(btclog.Disabled, false, nil)
+	require.NoError(t, server.register(chain))
+	require.FileExists(t, tokenPath)
+
+	fullMethod, handler, err := server.handler(
+		lncfg.LockedRPCForwardingHistory,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "/lnrpc.Lightning/ForwardingHistory", fullMethod)
+
+	resp, err := handler(
+		context.Background(), &lnrpc.ForwardingHistoryRequest{
+			EndTime: 1_700_000_100,
+		},
+	)
+	require.NoError(t, err)
+
+	history, ok := resp.(*lnrpc.ForwardingHistoryResponse)
+	require.True(t, ok)
+	require.Len(t, history.ForwardingEvents, len(events))
+	require.EqualValues(t, 2, history.LastOffsetIndex)
+
+	for i, event := range history.ForwardingEvents {
+		require.Equal(
+			t, events[i].IncomingChanID.ToUint64(), event.ChanIdIn,
+		)
+		require.Equal(
+			t, events[i].OutgoingChanID.ToUint64(), event.ChanIdOut,
+		)
+		require.EqualValues(
+			t, events[i].AmtIn.ToSatoshis(), event.AmtIn,
+		)
+		require.EqualValues(
+			t, events[i].AmtOut.ToSatoshis(), event.AmtOut,
+		)
+	}
+
+	// The handler only serves forwarding history requests.
+	_, err = handler(context.Background(), &lnrpc.GetInfoRequest{})
+	require.ErrorContains(t, err, "invalid request type")
+}
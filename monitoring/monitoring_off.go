@@ -17,6 +17,10 @@ func GetPromInterceptors() ([]grpc.UnaryServerInterceptor, []grpc.StreamServerIn
 	return []grpc.UnaryServerInterceptor{}, []grpc.StreamServerInterceptor{}
 }
 
+// RecordRateLimited is required for lnd to compile so that Prometheus metric
+// exporting can be hidden behind a build tag.
+func RecordRateLimited(_, _ string) {}
+
 // ExportPrometheusMetrics is required for lnd to compile so that Prometheus
 // metric exporting can be hidden behind a build tag.
 func ExportPrometheusMetrics(_ *grpc.Server, _ lncfg.Prometheus) error {
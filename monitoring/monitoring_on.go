@@ -9,11 +9,31 @@ import (
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 )
 
-var started sync.Once
+var (
+	started sync.Once
+
+	// rateLimitedRequests counts the RPC calls that were rejected by the
+	// rate limiter, by method and method class.
+	rateLimitedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "lnd_grpc_rate_limited_total",
+			Help: "Total number of RPC calls rejected by " +
+				"the rate limiter.",
+		},
+		[]string{"grpc_method", "method_class"},
+	)
+)
+
+// RecordRateLimited records that an RPC call of the given method and method
+// class was rejected by the rate limiter.
+func RecordRateLimited(fullMethod, class string) {
+	rateLimitedRequests.WithLabelValues(fullMethod, class).Inc()
+}
 
 // GetPromInterceptors returns the set of interceptors for Prometheus
 // monitoring.
@@ -34,6 +54,7 @@ func ExportPrometheusMetrics(grpcServer *grpc.Server, cfg lncfg.Prometheus) erro
 		log.Infof("Prometheus exporter started on %v/metrics", cfg.Listen)
 
 		grpc_prometheus.Register(grpcServer)
+		prometheus.MustRegister(rateLimitedRequests)
 
 		// Enable the histograms which can allow plotting latency
 		// distributions of inbound calls. However we guard this behind
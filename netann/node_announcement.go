@@ -1,15 +1,33 @@
 package netann
 
 import (
+	"fmt"
 	"image/color"
 	"net"
 	"time"
+	"unicode/utf8"
 
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+const (
+	// nodeAnn2MsgName is a string representing the name of the
+	// NodeAnnouncement2 message. This string will be used during the
+	// construction of the tagged hash message to be signed when producing
+	// the signature for the NodeAnnouncement2 message.
+	nodeAnn2MsgName = "node_announcement_2"
+
+	// nodeAnn2SigFieldName is the name of the signature field of the
+	// NodeAnnouncement2 message. This string will be used during the
+	// construction of the tagged hash message to be signed when producing
+	// the signature for the NodeAnnouncement2 message.
+	nodeAnn2SigFieldName = "signature"
+)
+
 // NodeAnnModifier is a closure that makes in-place modifications to an
 // lnwire.NodeAnnouncement.
 type NodeAnnModifier func(*lnwire.NodeAnnouncement)
@@ -76,3 +94,52 @@ func SignNodeAnnouncement(signer lnwallet.MessageSigner,
 	nodeAnn.Signature, err = lnwire.NewSigFromSignature(sig)
 	return err
 }
+
+// NodeAnn2DigestToSign computes the digest of the NodeAnnouncement2 message to
+// be signed.
+func NodeAnn2DigestToSign(a *lnwire.NodeAnnouncement2) (*chainhash.Hash,
+	error) {
+
+	data, err := a.DataToSign()
+	if err != nil {
+		return nil, err
+	}
+
+	return MsgHash(nodeAnn2MsgName, nodeAnn2SigFieldName, data), nil
+}
+
+// ValidateNodeAnn2 validates the fields of the given NodeAnnouncement2 and
+// ensures that it's signed by the announced node.
+func ValidateNodeAnn2(a *lnwire.NodeAnnouncement2) error {
+	var aliasErr error
+	a.Alias.WhenSomeV(func(alias []byte) {
+		if len(alias) > 32 || !utf8.Valid(alias) {
+			aliasErr = fmt.Errorf("invalid alias %x", alias)
+		}
+	})
+	if aliasErr != nil {
+		return aliasErr
+	}
+
+	nodeKey, err := btcec.ParsePubKey(a.NodeID.Val[:])
+	if err != nil {
+		return err
+	}
+
+	digest, err := NodeAnn2DigestToSign(a)
+	if err != nil {
+		return fmt.Errorf("unable to reconstruct message data: %w", err)
+	}
+
+	nodeSig, err := a.Signature.ToSignature()
+	if err != nil {
+		return err
+	}
+
+	if !nodeSig.Verify(digest[:], nodeKey) {
+		return fmt.Errorf("invalid signature for NodeAnnouncement2 of "+
+			"node %x", a.NodeID.Val)
+	}
+
+	return nil
+}
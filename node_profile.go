@@ -0,0 +1,527 @@
+package lnd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/tv42/zbase32"
+)
+
+// nodeProfileVersion is the version of the node profile format.
+const nodeProfileVersion = 1
+
+var (
+	// ErrForeignNodeProfile is returned if a node profile that was signed
+	// by another node is imported without allowing it explicitly.
+	ErrForeignNodeProfile = errors.New("node profile was signed by " +
+		"another node")
+
+	// ErrInvalidProfileSignature is returned if the signature of a node
+	// profile bundle doesn't match the node that created it.
+	ErrInvalidProfileSignature = errors.New("invalid node profile " +
+		"signature")
+)
+
+// NodeProfile is the operator-tunable state of a node that can be exported
+// for disaster recovery or used as a template for other nodes.
+type NodeProfile struct {
+	// Version is the version of the profile format.
+	Version uint32 `json:"version"`
+
+	// Node is the hex encoded public key of the node that created the
+	// profile.
+	Node string `json:"node"`
+
+	// CreatedAt is the time the profile was created at.
+	CreatedAt time.Time `json:"created_at"`
+
+	// DefaultPolicy is the fee policy of new channels.
+	DefaultPolicy ProfileDefaultPolicy `json:"default_policy"`
+
+	// ChannelPolicies are the policies of our channels.
+	ChannelPolicies []ProfileChannelPolicy `json:"channel_policies"`
+
+	// Peers are the peers we keep a persistent connection to.
+	Peers []ProfilePeer `json:"peers"`
+
+	// MissionControl are the results of past payment attempts.
+	MissionControl []ProfileMissionControlPair `json:"mission_control"`
+}
+
+// ProfileDefaultPolicy is the fee policy that is used for new channels.
+type ProfileDefaultPolicy struct {
+	// BaseFeeMsat is the base fee in millisatoshis.
+	BaseFeeMsat uint64 `json:"base_fee_msat"`
+
+	// FeeRatePpm is the proportional fee in parts per million.
+	FeeRatePpm uint64 `json:"fee_rate_ppm"`
+}
+
+// ProfileChannelPolicy is the policy of one of our channels in a node
+// profile.
+type ProfileChannelPolicy struct {
+	// ChannelPoint is the funding outpoint of the channel.
+	ChannelPoint string `json:"channel_point"`
+
+	// BaseFeeMsat is the base fee in millisatoshis.
+	BaseFeeMsat uint64 `json:"base_fee_msat"`
+
+	// FeeRatePpm is the proportional fee in parts per million.
+	FeeRatePpm uint32 `json:"fee_rate_ppm"`
+
+	// TimeLockDelta is the CLTV delta of the channel.
+	TimeLockDelta uint32 `json:"time_lock_delta"`
+
+	// MinHtlcMsat is the minimum HTLC we forward over the channel.
+	MinHtlcMsat uint64 `json:"min_htlc_msat"`
+
+	// MaxHtlcMsat is the maximum HTLC we forward over the channel.
+	MaxHtlcMsat uint64 `json:"max_htlc_msat"`
+
+	// InboundBaseFeeMsat is the inbound base fee in millisatoshis.
+	InboundBaseFeeMsat int32 `json:"inbound_base_fee_msat"`
+
+	// InboundFeeRatePpm is the inbound proportional fee in parts per
+	// million.
+	InboundFeeRatePpm int32 `json:"inbound_fee_rate_ppm"`
+}
+
+// ProfilePeer is a peer we keep a persistent connection to.
+type ProfilePeer struct {
+	// PubKey is the hex encoded public key of the peer.
+	PubKey string `json:"pub_key"`
+
+	// Addresses are the known addresses of the peer.
+	Addresses []string `json:"addresses"`
+}
+
+// ProfileMissionControlPair is the mission control state of a node pair.
+type ProfileMissionControlPair struct {
+	// From is the hex encoded public key of the sending node.
+	From string `json:"from"`
+
+	// To is the hex encoded public key of the receiving node.
+	To string `json:"to"`
+
+	// FailTime is the time of the last failure.
+	FailTime time.Time `json:"fail_time"`
+
+	// FailAmtMsat is the amount of the last failure.
+	FailAmtMsat uint64 `json:"fail_amt_msat"`
+
+	// SuccessTime is the time of the last success.
+	SuccessTime time.Time `json:"success_time"`
+
+	// SuccessAmtMsat is the highest amount that was forwarded
+	// successfully.
+	SuccessAmtMsat uint64 `json:"success_amt_msat"`
+}
+
+// NodeProfileBundle is a node profile signed by the node that created it.
+// The signature can be checked with VerifyMessage, which makes tampering with
+// the bundle detectable.
+type NodeProfileBundle struct {
+	// Profile is the JSON encoded node profile.
+	Profile json.RawMessage `json:"profile"`
+
+	// Signature is the zbase32 encoded signature of the profile by the
+	// node that created it.
+	Signature string `json:"signature"`
+}
+
+// ExportNodeProfile returns a signed bundle of the operator-tunable state of
+// the node: the default and per-channel fee policies, the persistent peers
+// and mission control.
+func (r *rpcServer) ExportNodeProfile(ctx context.Context,
+	_ *lnrpc.ExportNodeProfileRequest) (*lnrpc.ExportNodeProfileResponse,
+	error) {
+
+	defaultPolicy := r.server.fundingMgr.DefaultRoutingPolicy()
+
+	profile := &NodeProfile{
+		Version:   nodeProfileVersion,
+		Node:      r.selfNode.String(),
+		CreatedAt: time.Now().UTC(),
+		DefaultPolicy: ProfileDefaultPolicy{
+			BaseFeeMsat: uint64(defaultPolicy.BaseFee),
+			FeeRatePpm:  uint64(defaultPolicy.FeeRate),
+		},
+		Peers: r.server.persistentProfilePeers(),
+	}
+
+	var err error
+	profile.ChannelPolicies, err = r.profileChannelPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	mc := r.routerBackend.MissionControl.GetHistorySnapshot()
+	for _, pair := range mc.Pairs {
+		profile.MissionControl = append(
+			profile.MissionControl, ProfileMissionControlPair{
+				From:           pair.Pair.From.String(),
+				To:             pair.Pair.To.String(),
+				FailTime:       pair.FailTime,
+				FailAmtMsat:    uint64(pair.FailAmt),
+				SuccessTime:    pair.SuccessTime,
+				SuccessAmtMsat: uint64(pair.SuccessAmt),
+			},
+		)
+	}
+
+	profileBytes, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	// We sign the profile like SignMessage does, so the bundle can also
+	// be verified with the regular message verification tools.
+	sig, err := r.SignMessage(ctx, &lnrpc.SignMessageRequest{
+		Msg: profileBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign node profile: %w", err)
+	}
+
+	rpcsLog.Infof("[exportnodeprofile] exported %d channel policies, %d "+
+		"peers and %d mission control pairs",
+		len(profile.ChannelPolicies), len(profile.Peers),
+		len(profile.MissionControl))
+
+	bundle, err := json.Marshal(&NodeProfileBundle{
+		Profile:   profileBytes,
+		Signature: sig.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ExportNodeProfileResponse{
+		Bundle: bundle,
+	}, nil
+}
+
+// profileChannelPolicies returns the policies of all our channels in the
+// graph.
+func (r *rpcServer) profileChannelPolicies() ([]ProfileChannelPolicy,
+	error) {
+
+	var policies []ProfileChannelPolicy
+	err := r.server.graphDB.ForEachNodeChannel(r.selfNode,
+		func(_ kvdb.RTx, chanInfo *models.ChannelEdgeInfo,
+			edgePolicy, _ *models.ChannelEdgePolicy) error {
+
+			// Channels we haven't announced a policy for yet
+			// have nothing to export.
+			if edgePolicy == nil {
+				return nil
+			}
+
+			policy, err := newProfileChannelPolicy(
+				chanInfo.ChannelPoint, edgePolicy,
+			)
+			if err != nil {
+				return err
+			}
+			policies = append(policies, policy)
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// newProfileChannelPolicy converts the policy of the channel with the given
+// channel point.
+func newProfileChannelPolicy(chanPoint wire.OutPoint,
+	policy *models.ChannelEdgePolicy) (ProfileChannelPolicy, error) {
+
+	var inboundFee lnwire.Fee
+	_, err := policy.ExtraOpaqueData.ExtractRecords(&inboundFee)
+	if err != nil {
+		return ProfileChannelPolicy{}, err
+	}
+
+	return ProfileChannelPolicy{
+		ChannelPoint:       chanPoint.String(),
+		BaseFeeMsat:        uint64(policy.FeeBaseMSat),
+		FeeRatePpm:         uint32(policy.FeeProportionalMillionths),
+		TimeLockDelta:      uint32(policy.TimeLockDelta),
+		MinHtlcMsat:        uint64(policy.MinHTLC),
+		MaxHtlcMsat:        uint64(policy.MaxHTLC),
+		InboundBaseFeeMsat: inboundFee.BaseFee,
+		InboundFeeRatePpm:  inboundFee.FeeRate,
+	}, nil
+}
+
+// persistentProfilePeers returns the peers we keep a persistent connection to
+// together with their known addresses.
+func (s *server) persistentProfilePeers() []ProfilePeer {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	peers := make([]ProfilePeer, 0, len(s.persistentPeers))
+	for pubStr := range s.persistentPeers {
+		vertex, err := route.NewVertexFromBytes([]byte(pubStr))
+		if err != nil {
+			continue
+		}
+
+		peer := ProfilePeer{
+			PubKey: vertex.String(),
+		}
+		for _, addr := range s.persistentPeerAddrs[pubStr] {
+			peer.Addresses = append(
+				peer.Addresses, addr.Address.String(),
+			)
+		}
+
+		peers = append(peers, peer)
+	}
+
+	return peers
+}
+
+// parseNodeProfileBundle decodes the bundle and checks that it was signed by
+// the node that created the profile.
+func parseNodeProfileBundle(bundleBytes []byte) (*NodeProfile, error) {
+	var bundle NodeProfileBundle
+	if err := json.Unmarshal(bundleBytes, &bundle); err != nil {
+		return nil, fmt.Errorf("unable to decode node profile "+
+			"bundle: %w", err)
+	}
+
+	var profile NodeProfile
+	if err := json.Unmarshal(bundle.Profile, &profile); err != nil {
+		return nil, fmt.Errorf("unable to decode node profile: %w",
+			err)
+	}
+
+	if profile.Version != nodeProfileVersion {
+		return nil, fmt.Errorf("unknown node profile version %d",
+			profile.Version)
+	}
+
+	sig, err := zbase32.DecodeString(bundle.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProfileSignature,
+			err)
+	}
+
+	msg := append(signedMsgPrefix, bundle.Profile...)
+	digest := chainhash.DoubleHashB(msg)
+	pubKey, _, err := ecdsa.RecoverCompact(sig, digest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProfileSignature,
+			err)
+	}
+
+	signer := route.NewVertex(pubKey)
+	if signer.String() != profile.Node {
+		return nil, fmt.Errorf("%w: signed by %v instead of %v",
+			ErrInvalidProfileSignature, signer, profile.Node)
+	}
+
+	return &profile, nil
+}
+
+// ImportNodeProfile applies a signed node profile bundle to the node. The
+// default fee policy is changed through the runtime config, so it persists
+// across restarts. Channel policies are only applied to channels that exist
+// on this node, and the mission control results are merged with the current
+// ones.
+func (r *rpcServer) ImportNodeProfile(ctx context.Context,
+	req *lnrpc.ImportNodeProfileRequest) (*lnrpc.ImportNodeProfileResponse,
+	error) {
+
+	profile, err := parseNodeProfileBundle(req.Bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.Node != r.selfNode.String() && !req.AllowForeign {
+		return nil, fmt.Errorf("%w %v", ErrForeignNodeProfile,
+			profile.Node)
+	}
+
+	// We decode the mission control results before changing anything, so
+	// a malformed profile is rejected as a whole.
+	snapshot := &routing.MissionControlSnapshot{}
+	for _, pair := range profile.MissionControl {
+		from, err := route.NewVertexFromStr(pair.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := route.NewVertexFromStr(pair.To)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot.Pairs = append(
+			snapshot.Pairs, routing.MissionControlPairSnapshot{
+				Pair: routing.NewDirectedNodePair(from, to),
+				TimedPairResult: routing.TimedPairResult{
+					FailTime: pair.FailTime,
+					FailAmt: lnwire.MilliSatoshi(
+						pair.FailAmtMsat,
+					),
+					SuccessTime: pair.SuccessTime,
+					SuccessAmt: lnwire.MilliSatoshi(
+						pair.SuccessAmtMsat,
+					),
+				},
+			},
+		)
+	}
+
+	rpcsLog.Infof("[importnodeprofile] importing profile of %v created "+
+		"at %v", profile.Node, profile.CreatedAt)
+
+	_, err = r.UpdateRuntimeConfig(ctx, &lnrpc.UpdateRuntimeConfigRequest{
+		Options: map[string]string{
+			"bitcoin.basefee": strconv.FormatUint(
+				profile.DefaultPolicy.BaseFeeMsat, 10,
+			),
+			"bitcoin.feerate": strconv.FormatUint(
+				profile.DefaultPolicy.FeeRatePpm, 10,
+			),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to import default policy: %w",
+			err)
+	}
+
+	result := &lnrpc.ImportNodeProfileResponse{
+		SkippedChannelPolicies: make(map[string]string),
+		SkippedPeers:           make(map[string]string),
+	}
+
+	for _, policy := range profile.ChannelPolicies {
+		err := r.importChannelPolicy(ctx, policy)
+		if err != nil {
+			result.SkippedChannelPolicies[policy.ChannelPoint] =
+				err.Error()
+
+			continue
+		}
+
+		result.ChannelPolicies++
+	}
+
+	for _, peer := range profile.Peers {
+		err := r.importPersistentPeer(ctx, peer)
+		if err != nil {
+			result.SkippedPeers[peer.PubKey] = err.Error()
+			continue
+		}
+
+		result.Peers++
+	}
+
+	if len(snapshot.Pairs) > 0 {
+		err := r.routerBackend.MissionControl.ImportHistory(
+			snapshot, req.ForceMissionControl,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to import mission "+
+				"control: %w", err)
+		}
+		result.MissionControlPairs = uint32(len(snapshot.Pairs))
+	}
+
+	return result, nil
+}
+
+// importChannelPolicy applies the policy to the channel with the same channel
+// point.
+func (r *rpcServer) importChannelPolicy(ctx context.Context,
+	policy ProfileChannelPolicy) error {
+
+	chanPoint, err := wire.NewOutPointFromString(policy.ChannelPoint)
+	if err != nil {
+		return err
+	}
+
+	rpcChanPoint := &lnrpc.ChannelPoint{
+		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: chanPoint.Hash[:],
+		},
+		OutputIndex: chanPoint.Index,
+	}
+
+	resp, err := r.UpdateChannelPolicy(ctx, &lnrpc.PolicyUpdateRequest{
+		Scope: &lnrpc.PolicyUpdateRequest_ChanPoint{
+			ChanPoint: rpcChanPoint,
+		},
+		BaseFeeMsat:          int64(policy.BaseFeeMsat),
+		FeeRatePpm:           policy.FeeRatePpm,
+		TimeLockDelta:        policy.TimeLockDelta,
+		MinHtlcMsat:          policy.MinHtlcMsat,
+		MinHtlcMsatSpecified: true,
+		MaxHtlcMsat:          policy.MaxHtlcMsat,
+		InboundFee: &lnrpc.InboundFee{
+			BaseFeeMsat: policy.InboundBaseFeeMsat,
+			FeeRatePpm:  policy.InboundFeeRatePpm,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(resp.FailedUpdates) > 0 {
+		failed := resp.FailedUpdates[0]
+		return fmt.Errorf("%v: %v", failed.Reason, failed.UpdateError)
+	}
+
+	return nil
+}
+
+// importPersistentPeer connects to the peer permanently, using the first of
+// its addresses that can be parsed.
+func (r *rpcServer) importPersistentPeer(ctx context.Context,
+	peer ProfilePeer) error {
+
+	if peer.PubKey == r.selfNode.String() {
+		return errors.New("peer is this node")
+	}
+
+	if len(peer.Addresses) == 0 {
+		return errors.New("peer has no known address")
+	}
+
+	var err error
+	for _, addr := range peer.Addresses {
+		_, err = r.ConnectPeer(ctx, &lnrpc.ConnectPeerRequest{
+			Addr: &lnrpc.LightningAddress{
+				Pubkey: peer.PubKey,
+				Host:   addr,
+			},
+			Perm: true,
+		})
+
+		var alreadyConnected *errPeerAlreadyConnected
+		if err == nil || errors.As(err, &alreadyConnected) {
+			return nil
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,136 @@
+package lnd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+	"github.com/tv42/zbase32"
+)
+
+// signNodeProfile returns a bundle of the profile signed by the given key
+// the way SignMessage does.
+func signNodeProfile(t *testing.T, key *btcec.PrivateKey,
+	profile *NodeProfile) *NodeProfileBundle {
+
+	profileBytes, err := json.Marshal(profile)
+	require.NoError(t, err)
+
+	msg := append(append([]byte{}, signedMsgPrefix...), profileBytes...)
+	sig := ecdsa.SignCompact(key, chainhash.DoubleHashB(msg), true)
+
+	return &NodeProfileBundle{
+		Profile:   profileBytes,
+		Signature: zbase32.EncodeToString(sig),
+	}
+}
+
+// TestParseNodeProfileBundle tests that only node profiles that were signed by
+// the node that created them are accepted.
+func TestParseNodeProfileBundle(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	node := route.NewVertex(key.PubKey()).String()
+
+	profile := &NodeProfile{
+		Version:   nodeProfileVersion,
+		Node:      node,
+		CreatedAt: time.Unix(1700000000, 0).UTC(),
+		DefaultPolicy: ProfileDefaultPolicy{
+			BaseFeeMsat: 1000,
+			FeeRatePpm:  100,
+		},
+		ChannelPolicies: []ProfileChannelPolicy{{
+			ChannelPoint:  chainhash.Hash{1}.String() + ":1",
+			BaseFeeMsat:   1500,
+			FeeRatePpm:    250,
+			TimeLockDelta: 80,
+		}},
+		Peers: []ProfilePeer{{
+			PubKey:    route.NewVertex(otherKey.PubKey()).String(),
+			Addresses: []string{"127.0.0.1:9735"},
+		}},
+	}
+
+	encode := func(bundle *NodeProfileBundle) []byte {
+		bundleBytes, err := json.Marshal(bundle)
+		require.NoError(t, err)
+
+		return bundleBytes
+	}
+
+	// A bundle signed by the node of the profile is accepted.
+	parsed, err := parseNodeProfileBundle(
+		encode(signNodeProfile(t, key, profile)),
+	)
+	require.NoError(t, err)
+	require.Equal(t, profile, parsed)
+
+	// A bundle signed by another node is rejected.
+	_, err = parseNodeProfileBundle(
+		encode(signNodeProfile(t, otherKey, profile)),
+	)
+	require.ErrorIs(t, err, ErrInvalidProfileSignature)
+
+	// A profile that was changed after signing is rejected.
+	bundle := signNodeProfile(t, key, profile)
+	tampered := *profile
+	tampered.DefaultPolicy.BaseFeeMsat = 0
+	bundle.Profile, err = json.Marshal(&tampered)
+	require.NoError(t, err)
+
+	_, err = parseNodeProfileBundle(encode(bundle))
+	require.ErrorIs(t, err, ErrInvalidProfileSignature)
+
+	// A profile of an unknown version is rejected.
+	future := *profile
+	future.Version = nodeProfileVersion + 1
+	_, err = parseNodeProfileBundle(
+		encode(signNodeProfile(t, key, &future)),
+	)
+	require.ErrorContains(t, err, "unknown node profile version")
+
+	// Garbage is rejected.
+	_, err = parseNodeProfileBundle([]byte("not a bundle"))
+	require.Error(t, err)
+}
+
+// TestImportForeignNodeProfile tests that a bundle of another node is only
+// imported if that is allowed explicitly.
+func TestImportForeignNodeProfile(t *testing.T) {
+	t.Parallel()
+
+	key, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	otherKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	bundle, err := json.Marshal(signNodeProfile(t, otherKey, &NodeProfile{
+		Version: nodeProfileVersion,
+		Node:    route.NewVertex(otherKey.PubKey()).String(),
+	}))
+	require.NoError(t, err)
+
+	r := &rpcServer{
+		selfNode: route.NewVertex(key.PubKey()),
+	}
+
+	_, err = r.ImportNodeProfile(
+		context.Background(), &lnrpc.ImportNodeProfileRequest{
+			Bundle: bundle,
+		},
+	)
+	require.ErrorIs(t, err, ErrForeignNodeProfile)
+}
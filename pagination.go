@@ -0,0 +1,307 @@
+package lnd
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sort"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// The paginated listings below continue from the opaque cursor of the
+// request instead of a caller provided offset, and return at most
+// lnrpc.MaxPageSize items per page. An empty next page cursor indicates that
+// the last page was reached.
+
+// listInvoicesPage returns a page of the invoices that match the request,
+// starting after the cursor of the request, along with the cursor of the next
+// page. The index offset of the request is only used for the first page.
+func (r *rpcServer) listInvoicesPage(ctx context.Context,
+	req *lnrpc.ListInvoiceRequest) (*lnrpc.ListInvoiceResponse, error) {
+
+	pageReq, ok := proto.Clone(req).(*lnrpc.ListInvoiceRequest)
+	if !ok {
+		return nil, errors.New("unable to copy request")
+	}
+	pageReq.Paginate = false
+
+	offset, ok, err := lnrpc.DecodeIndexCursor(
+		lnrpc.CursorInvoices, req.PageCursor,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		pageReq.IndexOffset = offset
+	}
+	pageReq.NumMaxInvoices = lnrpc.PageSize(req.NumMaxInvoices)
+
+	resp, err := r.ListInvoices(ctx, pageReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// A full page indicates that there may be further invoices.
+	if uint64(len(resp.Invoices)) == pageReq.NumMaxInvoices {
+		index := resp.LastIndexOffset
+		if req.Reversed {
+			index = resp.FirstIndexOffset
+		}
+		resp.NextPageCursor = lnrpc.EncodeIndexCursor(
+			lnrpc.CursorInvoices, index,
+		)
+	}
+
+	return resp, nil
+}
+
+// listPaymentsPage returns a page of the payments that match the request,
+// starting after the cursor of the request, along with the cursor of the next
+// page. The index offset of the request is only used for the first page.
+func (r *rpcServer) listPaymentsPage(ctx context.Context,
+	req *lnrpc.ListPaymentsRequest) (*lnrpc.ListPaymentsResponse, error) {
+
+	pageReq, ok := proto.Clone(req).(*lnrpc.ListPaymentsRequest)
+	if !ok {
+		return nil, errors.New("unable to copy request")
+	}
+	pageReq.Paginate = false
+
+	offset, ok, err := lnrpc.DecodeIndexCursor(
+		lnrpc.CursorPayments, req.PageCursor,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		pageReq.IndexOffset = offset
+	}
+	pageReq.MaxPayments = lnrpc.PageSize(req.MaxPayments)
+
+	resp, err := r.ListPayments(ctx, pageReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// A full page indicates that there may be further payments.
+	if uint64(len(resp.Payments)) == pageReq.MaxPayments {
+		index := resp.LastIndexOffset
+		if req.Reversed {
+			index = resp.FirstIndexOffset
+		}
+		resp.NextPageCursor = lnrpc.EncodeIndexCursor(
+			lnrpc.CursorPayments, index,
+		)
+	}
+
+	return resp, nil
+}
+
+// forwardingHistoryPage returns a page of the forwarding events that match
+// the request, starting after the cursor of the request, along with the cursor
+// of the next page. The index offset of the request is only used for the first
+// page.
+func (r *rpcServer) forwardingHistoryPage(ctx context.Context,
+	req *lnrpc.ForwardingHistoryRequest) (*lnrpc.ForwardingHistoryResponse,
+	error) {
+
+	pageReq, ok := proto.Clone(req).(*lnrpc.ForwardingHistoryRequest)
+	if !ok {
+		return nil, errors.New("unable to copy request")
+	}
+	pageReq.Paginate = false
+
+	offset, ok, err := lnrpc.DecodeIndexCursor(
+		lnrpc.CursorForwards, req.PageCursor,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		pageReq.IndexOffset = uint32(offset)
+	}
+	pageReq.NumMaxEvents = uint32(
+		lnrpc.PageSize(uint64(req.NumMaxEvents)),
+	)
+
+	resp, err := r.ForwardingHistory(ctx, pageReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// A full page indicates that there may be further events.
+	if len(resp.ForwardingEvents) == int(pageReq.NumMaxEvents) {
+		resp.NextPageCursor = lnrpc.EncodeIndexCursor(
+			lnrpc.CursorForwards, uint64(resp.LastOffsetIndex),
+		)
+	}
+
+	return resp, nil
+}
+
+// listChannelsPage returns a page of the channels that match the request in
+// the order of their channel IDs, starting after the cursor of the request,
+// along with the cursor of the next page.
+func (r *rpcServer) listChannelsPage(ctx context.Context,
+	req *lnrpc.ListChannelsRequest) (*lnrpc.ListChannelsResponse, error) {
+
+	pageReq, ok := proto.Clone(req).(*lnrpc.ListChannelsRequest)
+	if !ok {
+		return nil, errors.New("unable to copy request")
+	}
+	pageReq.Paginate = false
+
+	afterChanID, _, err := lnrpc.DecodeIndexCursor(
+		lnrpc.CursorChannels, req.PageCursor,
+	)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := lnrpc.PageSize(uint64(req.PageSize))
+
+	// The open channels are all loaded from the database anyway, so we
+	// page through them in memory.
+	resp, err := r.ListChannels(ctx, pageReq)
+	if err != nil {
+		return nil, err
+	}
+
+	channels := resp.Channels
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].ChanId < channels[j].ChanId
+	})
+
+	start := sort.Search(len(channels), func(i int) bool {
+		return channels[i].ChanId > afterChanID
+	})
+	channels = channels[start:]
+
+	if uint64(len(channels)) > pageSize {
+		channels = channels[:pageSize]
+
+		lastChanID := channels[len(channels)-1].ChanId
+		resp.NextPageCursor = lnrpc.EncodeIndexCursor(
+			lnrpc.CursorChannels, lastChanID,
+		)
+	}
+	resp.Channels = channels
+
+	return resp, nil
+}
+
+// describeGraphPage returns a page of the channel graph, starting after the
+// cursor of the request, along with the cursor of the next page. The nodes of
+// the graph are listed first, followed by its edges. Pages may hold fewer
+// items than the page size if unannounced channels are skipped.
+func (r *rpcServer) describeGraphPage(
+	req *lnrpc.ChannelGraphRequest) (*lnrpc.ChannelGraph, error) {
+
+	pageCursor, err := lnrpc.DecodePageCursor(req.PageCursor)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := lnrpc.PageSize(uint64(req.PageSize))
+
+	graph := r.server.graphDB
+	resp := &lnrpc.ChannelGraph{}
+
+	var (
+		afterNode   []byte
+		afterChanID uint64
+		listNodes   = true
+	)
+	switch {
+	case pageCursor == nil:
+
+	case pageCursor.Kind == lnrpc.CursorGraphNodes &&
+		len(pageCursor.Position) == 33:
+
+		afterNode = pageCursor.Position
+
+	case pageCursor.Kind == lnrpc.CursorGraphEdges &&
+		len(pageCursor.Position) == 8:
+
+		afterChanID = binary.BigEndian.Uint64(pageCursor.Position)
+		listNodes = false
+
+	default:
+		return nil, lnrpc.ErrInvalidPageCursor
+	}
+
+	if listNodes {
+		var lastNode []byte
+		more, err := graph.ForEachNodePage(
+			afterNode, pageSize,
+			func(node *channeldb.LightningNode) error {
+				lnNode := marshalNode(node)
+				resp.Nodes = append(resp.Nodes, lnNode)
+				lastNode = node.PubKeyBytes[:]
+
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if more {
+			next := &lnrpc.PageCursor{
+				Kind:     lnrpc.CursorGraphNodes,
+				Position: lastNode,
+			}
+			resp.NextPageCursor = next.Encode()
+
+			return resp, nil
+		}
+
+		// The remainder of the page is filled with the first edges.
+		pageSize -= uint64(len(resp.Nodes))
+		if pageSize == 0 {
+			resp.NextPageCursor = lnrpc.EncodeIndexCursor(
+				lnrpc.CursorGraphEdges, 0,
+			)
+
+			return resp, nil
+		}
+	}
+
+	var lastChanID uint64
+	more, err := graph.ForEachChannelPage(
+		afterChanID, pageSize, func(edgeInfo *models.ChannelEdgeInfo,
+			c1, c2 *models.ChannelEdgePolicy) error {
+
+			lastChanID = edgeInfo.ChannelID
+
+			// Unannounced channels are only included if
+			// requested.
+			announced := edgeInfo.AuthProof != nil
+			if !req.IncludeUnannounced && !announced {
+				return nil
+			}
+
+			edge := marshalDBEdge(edgeInfo, c1, c2)
+			resp.Edges = append(resp.Edges, edge)
+
+			return nil
+		},
+	)
+	switch {
+	case errors.Is(err, channeldb.ErrGraphNoEdgesFound):
+		return resp, nil
+
+	case err != nil:
+		return nil, err
+	}
+
+	if more {
+		resp.NextPageCursor = lnrpc.EncodeIndexCursor(
+			lnrpc.CursorGraphEdges, lastChanID,
+		)
+	}
+
+	return resp, nil
+}
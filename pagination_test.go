@@ -0,0 +1,125 @@
+package lnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDescribeGraphPaginated tests that the channel graph can be listed page
+// by page through DescribeGraph, with the nodes listed before the edges.
+func TestDescribeGraphPaginated(t *testing.T) {
+	t.Parallel()
+
+	cdb, err := channeldb.Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, cdb.Close())
+	})
+	graph := cdb.ChannelGraph()
+
+	var nodes []*channeldb.LightningNode
+	for i := 0; i < 3; i++ {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		node := &channeldb.LightningNode{
+			HaveNodeAnnouncement: true,
+			LastUpdate:           time.Unix(1_700_000_000, 0),
+			Features: lnwire.NewFeatureVector(
+				nil, lnwire.Features,
+			),
+		}
+		copy(node.PubKeyBytes[:], priv.PubKey().SerializeCompressed())
+		require.NoError(t, graph.AddLightningNode(node))
+
+		nodes = append(nodes, node)
+	}
+
+	// The first channel is announced, the second one isn't and is only
+	// listed if unannounced channels are requested.
+	for i := 0; i < 2; i++ {
+		edge := &models.ChannelEdgeInfo{
+			ChannelID:     uint64(i + 1),
+			NodeKey1Bytes: nodes[i].PubKeyBytes,
+			NodeKey2Bytes: nodes[i+1].PubKeyBytes,
+			ChannelPoint:  wire.OutPoint{Index: uint32(i)},
+			Capacity:      1_000_000,
+		}
+		if i == 0 {
+			edge.AuthProof = &models.ChannelAuthProof{
+				NodeSig1Bytes:    []byte{1},
+				NodeSig2Bytes:    []byte{2},
+				BitcoinSig1Bytes: []byte{3},
+				BitcoinSig2Bytes: []byte{4},
+			}
+		}
+		require.NoError(t, graph.AddChannelEdge(edge))
+	}
+
+	r := &rpcServer{
+		server: &server{
+			graphDB: graph,
+		},
+	}
+
+	// The first page only holds nodes.
+	req := &lnrpc.ChannelGraphRequest{
+		Paginate: true,
+		PageSize: 2,
+	}
+	page, err := r.DescribeGraph(nil, req)
+	require.NoError(t, err)
+	require.Len(t, page.Nodes, 2)
+	require.Empty(t, page.Edges)
+	require.NotEmpty(t, page.NextPageCursor)
+
+	// The second page holds the last node, followed by the announced
+	// channel.
+	secondCursor := page.NextPageCursor
+	req.PageCursor = secondCursor
+	page, err = r.DescribeGraph(nil, req)
+	require.NoError(t, err)
+	require.Len(t, page.Nodes, 1)
+	require.Len(t, page.Edges, 1)
+	require.EqualValues(t, 1, page.Edges[0].ChannelId)
+	require.NotEmpty(t, page.NextPageCursor)
+
+	// The unannounced channel is skipped, leaving the last page empty.
+	req.PageCursor = page.NextPageCursor
+	page, err = r.DescribeGraph(nil, req)
+	require.NoError(t, err)
+	require.Empty(t, page.Nodes)
+	require.Empty(t, page.Edges)
+	require.Empty(t, page.NextPageCursor)
+
+	// With unannounced channels, the last channel is listed on the third
+	// page.
+	req.IncludeUnannounced = true
+	req.PageCursor = secondCursor
+	page, err = r.DescribeGraph(nil, req)
+	require.NoError(t, err)
+	require.Len(t, page.Nodes, 1)
+	require.Len(t, page.Edges, 1)
+	require.NotEmpty(t, page.NextPageCursor)
+
+	req.PageCursor = page.NextPageCursor
+	page, err = r.DescribeGraph(nil, req)
+	require.NoError(t, err)
+	require.Empty(t, page.Nodes)
+	require.Len(t, page.Edges, 1)
+	require.EqualValues(t, 2, page.Edges[0].ChannelId)
+	require.Empty(t, page.NextPageCursor)
+
+	// A cursor of another listing is rejected.
+	req.PageCursor = lnrpc.EncodeIndexCursor(lnrpc.CursorInvoices, 1)
+	_, err = r.DescribeGraph(nil, req)
+	require.ErrorIs(t, err, lnrpc.ErrInvalidPageCursor)
+}
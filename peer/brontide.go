@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/connmgr"
 	"github.com/btcsuite/btcd/txscript"
@@ -140,6 +141,17 @@ type PendingUpdate struct {
 	OutputIndex uint32
 }
 
+// CloseNegotiationUpdate describes a round of the fee negotiation of a
+// cooperative close.
+type CloseNegotiationUpdate struct {
+	// LocalFee is the closing fee that we proposed in this round.
+	LocalFee btcutil.Amount
+
+	// RemoteFee is the closing fee that the remote party proposed in this
+	// round. It is zero if the remote party hasn't proposed a fee yet.
+	RemoteFee btcutil.Amount
+}
+
 // ChannelCloseUpdate contains the outcome of the close channel operation.
 type ChannelCloseUpdate struct {
 	ClosingTxid []byte
@@ -231,6 +243,10 @@ type Config struct {
 	// ChannelLinkConfig.
 	InterceptSwitch *htlcswitch.InterceptableSwitch
 
+	// ForwardingRules are the operator defined rules that htlcs forwarded
+	// over the peer's channels must satisfy.
+	ForwardingRules *htlcswitch.ForwardingRules
+
 	// ChannelDB is used to fetch opened channels, and closed channels.
 	ChannelDB *channeldb.ChannelStateDB
 
@@ -372,6 +388,11 @@ type Config struct {
 	// that is accumulated before signing a new commitment.
 	ChannelCommitBatchSize uint32
 
+	// ChannelSettleBatchWindow is the maximum time that settles and fails
+	// are held before signing a new commitment that includes them. If
+	// zero, a new commitment is signed right away.
+	ChannelSettleBatchWindow time.Duration
+
 	// HandleCustomMessage is called whenever a custom message is received
 	// from the peer.
 	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
@@ -1302,6 +1323,7 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 			p.cfg.PendingCommitInterval,
 		),
 		BatchSize:               p.cfg.ChannelCommitBatchSize,
+		SettleBatchWindow:       p.cfg.ChannelSettleBatchWindow,
 		UnsafeReplay:            p.cfg.UnsafeReplay,
 		MinUpdateTimeout:        htlcswitch.DefaultMinLinkFeeUpdateTimeout,
 		MaxUpdateTimeout:        htlcswitch.DefaultMaxLinkFeeUpdateTimeout,
@@ -1319,6 +1341,7 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		PreviouslySentShutdown:  shutdownMsg,
 		DisallowRouteBlinding:   p.cfg.DisallowRouteBlinding,
 		MaxFeeExposure:          p.cfg.MaxFeeExposure,
+		ForwardingRules:         p.cfg.ForwardingRules,
 	}
 
 	// Before adding our new link, purge the switch of any pending or live
@@ -2038,7 +2061,10 @@ out:
 
 		case *lnwire.ChannelUpdate1,
 			*lnwire.ChannelAnnouncement1,
+			*lnwire.ChannelUpdate2,
+			*lnwire.ChannelAnnouncement2,
 			*lnwire.NodeAnnouncement,
+			*lnwire.NodeAnnouncement2,
 			*lnwire.AnnounceSignatures1,
 			*lnwire.GossipTimestampRange,
 			*lnwire.QueryShortChanIDs,
@@ -2048,6 +2074,13 @@ out:
 
 			discStream.AddMsg(msg)
 
+		// Our taproot channels can't be announced yet, so there's no
+		// signing ceremony the partial signatures could belong to.
+		case *lnwire.AnnounceSignatures2:
+			p.log.Debugf("Ignoring AnnounceSignatures2 for "+
+				"chan_id=%v, announcing taproot channels is "+
+				"not supported", msg.ChannelID)
+
 		case *lnwire.Custom:
 			err := p.handleCustomMessage(msg)
 			if err != nil {
@@ -2312,10 +2345,24 @@ func messageSummary(msg lnwire.Message) string {
 			msg.ShortChannelID.ToUint64(), msg.MessageFlags,
 			msg.ChannelFlags, time.Unix(int64(msg.Timestamp), 0))
 
+	case *lnwire.ChannelAnnouncement2:
+		return fmt.Sprintf("chain_hash=%v, short_chan_id=%v",
+			msg.ChainHash.Val, msg.SCID().ToUint64())
+
+	case *lnwire.ChannelUpdate2:
+		return fmt.Sprintf("chain_hash=%v, short_chan_id=%v, "+
+			"node1=%v, block_height=%v", msg.ChainHash.Val,
+			msg.SCID().ToUint64(), msg.IsNode1(),
+			msg.BlockHeight.Val)
+
 	case *lnwire.NodeAnnouncement:
 		return fmt.Sprintf("node=%x, update_time=%v",
 			msg.NodeID, time.Unix(int64(msg.Timestamp), 0))
 
+	case *lnwire.NodeAnnouncement2:
+		return fmt.Sprintf("node=%x, block_height=%v", msg.NodeID.Val,
+			msg.BlockHeight.Val)
+
 	case *lnwire.Ping:
 		return fmt.Sprintf("ping_bytes=%x", msg.PaddingBytes[:])
 
@@ -4048,6 +4095,9 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 			}
 
 			oClosingSigned.WhenSome(func(msg lnwire.ClosingSigned) {
+				p.notifyCloseNegotiation(
+					chanCloser, msg.FeeSatoshis, 0,
+				)
 				p.queueMsg(&msg, nil)
 			})
 		}
@@ -4072,9 +4122,16 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 			return
 		}
 
+		// If we don't make a counter offer, we accepted the fee of the
+		// remote party.
+		localFee := typed.FeeSatoshis
 		oClosingSigned.WhenSome(func(msg lnwire.ClosingSigned) {
+			localFee = msg.FeeSatoshis
 			p.queueMsg(&msg, nil)
 		})
+		p.notifyCloseNegotiation(
+			chanCloser, localFee, typed.FeeSatoshis,
+		)
 
 	default:
 		panic("impossible closeMsg type")
@@ -4092,6 +4149,27 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 	p.finalizeChanClosure(chanCloser)
 }
 
+// notifyCloseNegotiation reports a round of the fee negotiation to the
+// creator of a locally initiated close request. The update is only
+// informational, so it's dropped rather than delaying the negotiation if the
+// creator hasn't consumed the previous one yet.
+func (p *Brontide) notifyCloseNegotiation(chanCloser *chancloser.ChanCloser,
+	localFee, remoteFee btcutil.Amount) {
+
+	closeReq := chanCloser.CloseRequest()
+	if closeReq == nil || len(closeReq.Updates) > 0 {
+		return
+	}
+
+	select {
+	case closeReq.Updates <- &CloseNegotiationUpdate{
+		LocalFee:  localFee,
+		RemoteFee: remoteFee,
+	}:
+	default:
+	}
+}
+
 // HandleLocalCloseChanReqs accepts a *htlcswitch.ChanClose and passes it onto
 // the channelManager goroutine, which will shut down the link and possibly
 // close the channel.
@@ -198,8 +198,10 @@ func TestPeerChannelClosureAcceptFeeInitiator(t *testing.T) {
 
 	dummyDeliveryScript := genScript(t, p2wshAddress)
 
-	// We make Alice send a shutdown request.
-	updateChan := make(chan interface{}, 1)
+	// We make Alice send a shutdown request. The update channel leaves
+	// room for a fee negotiation update besides the pending update of the
+	// close, like the switch does.
+	updateChan := make(chan interface{}, 2)
 	errChan := make(chan error, 1)
 	closeCommand := &htlcswitch.ChanClose{
 		CloseType:      contractcourt.CloseRegular,
@@ -501,8 +503,10 @@ func TestPeerChannelClosureFeeNegotiationsInitiator(t *testing.T) {
 	mockLink := newMockUpdateHandler(chanID)
 	mockSwitch.links = append(mockSwitch.links, mockLink)
 
-	// We make the initiator send a shutdown request.
-	updateChan := make(chan interface{}, 1)
+	// We make the initiator send a shutdown request. The update channel
+	// leaves room for a fee negotiation update besides the pending update
+	// of the close, like the switch does.
+	updateChan := make(chan interface{}, 2)
 	errChan := make(chan error, 1)
 	closeCommand := &htlcswitch.ChanClose{
 		CloseType:      contractcourt.CloseRegular,
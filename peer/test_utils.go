@@ -2,6 +2,7 @@ package peer
 
 import (
 	"bytes"
+	"context"
 	crand "crypto/rand"
 	"encoding/binary"
 	"io"
@@ -24,6 +25,7 @@ import (
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lntest/channels"
 	"github.com/lightningnetwork/lnd/lntest/mock"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -478,6 +480,16 @@ func (m *mockUpdateHandler) OnCommitOnce(
 	hook()
 }
 
+func (m *mockUpdateHandler) Quiesce(
+	context.Context) (lntypes.ChannelParty, error) {
+
+	return 0, htlcswitch.ErrQuiescenceNotNegotiated
+}
+
+func (m *mockUpdateHandler) Resume(context.Context) error {
+	return htlcswitch.ErrNotQuiescent
+}
+
 func newMockConn(t *testing.T, expectedMessages int) *mockMessageConn {
 	return &mockMessageConn{
 		t:               t,
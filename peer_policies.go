@@ -0,0 +1,420 @@
+package lnd
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/chanacceptor"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tor"
+)
+
+var (
+	// errInboundDenied is returned when a peer on the inbound deny list
+	// connects to us.
+	errInboundDenied = errors.New("peer is on the inbound deny list")
+
+	// errInboundNotAllowed is returned when a peer that isn't on the
+	// inbound allow list connects to us while the list isn't empty.
+	errInboundNotAllowed = errors.New("peer isn't on the inbound allow " +
+		"list")
+
+	// errInboundRejected is returned when a peer whose policy rejects
+	// inbound connections connects to us.
+	errInboundRejected = errors.New("peer policy rejects inbound " +
+		"connections")
+
+	// errTorOnlyPeer is returned when a tor-only peer would be connected
+	// without Tor.
+	errTorOnlyPeer = errors.New("peer policy only allows connections " +
+		"over Tor")
+)
+
+// peerPolicyCache holds the peer connection policies and the inbound access
+// lists of the channel state DB in memory, as they are consulted for every
+// connection. All changes are written through to the database.
+type peerPolicyCache struct {
+	db *channeldb.ChannelStateDB
+
+	mu         sync.RWMutex
+	policies   map[route.Vertex]*channeldb.PeerPolicy
+	access     map[route.Vertex]channeldb.InboundAccess
+	numAllowed int
+}
+
+// newPeerPolicyCache loads the peer policies and inbound access lists from
+// the database.
+func newPeerPolicyCache(db *channeldb.ChannelStateDB) (*peerPolicyCache,
+	error) {
+
+	policies, err := db.FetchPeerPolicies()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch peer policies: %w", err)
+	}
+
+	access, err := db.FetchInboundAccess()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch inbound access lists: "+
+			"%w", err)
+	}
+
+	c := &peerPolicyCache{
+		db:       db,
+		policies: policies,
+		access:   access,
+	}
+	for _, a := range access {
+		if a == channeldb.InboundAccessAllow {
+			c.numAllowed++
+		}
+	}
+
+	return c, nil
+}
+
+// policy returns the policy of the peer, if one is set.
+func (c *peerPolicyCache) policy(
+	pubKey route.Vertex) (*channeldb.PeerPolicy, bool) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	policy, ok := c.policies[pubKey]
+
+	return policy, ok
+}
+
+// allPolicies returns the policies of all peers.
+func (c *peerPolicyCache) allPolicies() map[route.Vertex]*channeldb.PeerPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	policies := make(
+		map[route.Vertex]*channeldb.PeerPolicy, len(c.policies),
+	)
+	for pubKey, policy := range c.policies {
+		policies[pubKey] = policy
+	}
+
+	return policies
+}
+
+// putPolicy stores the policy of the peer and returns the one it replaced,
+// if any.
+func (c *peerPolicyCache) putPolicy(pubKey route.Vertex,
+	policy *channeldb.PeerPolicy) (*channeldb.PeerPolicy, error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.db.PutPeerPolicy(pubKey, policy); err != nil {
+		return nil, err
+	}
+
+	oldPolicy := c.policies[pubKey]
+	c.policies[pubKey] = policy
+
+	return oldPolicy, nil
+}
+
+// deletePolicy removes the policy of the peer and returns it.
+func (c *peerPolicyCache) deletePolicy(
+	pubKey route.Vertex) (*channeldb.PeerPolicy, error) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.db.DeletePeerPolicy(pubKey); err != nil {
+		return nil, err
+	}
+
+	oldPolicy := c.policies[pubKey]
+	delete(c.policies, pubKey)
+
+	return oldPolicy, nil
+}
+
+// allAccess returns the entries of the inbound access lists.
+func (c *peerPolicyCache) allAccess() map[route.Vertex]channeldb.InboundAccess {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	access := make(map[route.Vertex]channeldb.InboundAccess, len(c.access))
+	for pubKey, a := range c.access {
+		access[pubKey] = a
+	}
+
+	return access
+}
+
+// putAccess puts the peer on the inbound allow or deny list, or removes it
+// from both.
+func (c *peerPolicyCache) putAccess(pubKey route.Vertex,
+	access channeldb.InboundAccess) error {
+
+	switch access {
+	case channeldb.InboundAccessNone, channeldb.InboundAccessAllow,
+		channeldb.InboundAccessDeny:
+
+	default:
+		return fmt.Errorf("unknown inbound access %d", access)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.db.PutInboundAccess(pubKey, access); err != nil {
+		return err
+	}
+
+	if c.access[pubKey] == channeldb.InboundAccessAllow {
+		c.numAllowed--
+	}
+	if access == channeldb.InboundAccessAllow {
+		c.numAllowed++
+	}
+
+	if access == channeldb.InboundAccessNone {
+		delete(c.access, pubKey)
+	} else {
+		c.access[pubKey] = access
+	}
+
+	return nil
+}
+
+// checkInbound returns an error if an inbound connection of the peer from the
+// given address isn't allowed. Tor forwards the connections to our onion
+// service from the loopback interface, so a tor-only peer must connect from
+// there.
+func (c *peerPolicyCache) checkInbound(pubKey route.Vertex,
+	remoteAddr net.Addr) error {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	access := c.access[pubKey]
+	switch {
+	case access == channeldb.InboundAccessDeny:
+		return errInboundDenied
+
+	case c.numAllowed > 0 && access != channeldb.InboundAccessAllow:
+		return errInboundNotAllowed
+	}
+
+	policy, ok := c.policies[pubKey]
+	if !ok {
+		return nil
+	}
+
+	if !policy.AllowInbound {
+		return errInboundRejected
+	}
+
+	if policy.TorOnly && !lncfg.IsLoopback(remoteAddr.String()) {
+		return errTorOnlyPeer
+	}
+
+	return nil
+}
+
+// checkOutbound returns an error if we may not connect to the peer at the
+// given address.
+func (c *peerPolicyCache) checkOutbound(pubKey route.Vertex,
+	addr net.Addr) error {
+
+	policy, ok := c.policy(pubKey)
+	if !ok || !policy.TorOnly {
+		return nil
+	}
+
+	if _, ok := addr.(*tor.OnionAddr); !ok {
+		return errTorOnlyPeer
+	}
+
+	return nil
+}
+
+// policyPeerLimit is the name of the peerPolicyAcceptor in the channel accept
+// policy chain.
+const policyPeerLimit = "peerlimit"
+
+// peerPolicyAcceptor is a channel accept policy that rejects channels of peers
+// that already have as many channels with us as their policy allows.
+type peerPolicyAcceptor struct {
+	policies *peerPolicyCache
+
+	// fetchChannels returns the open and pending channels with the peer.
+	fetchChannels func(*btcec.PublicKey) ([]*channeldb.OpenChannel, error)
+}
+
+// Name returns the name of the policy.
+//
+// NOTE: Part of the chanacceptor.Policy interface.
+func (p *peerPolicyAcceptor) Name() string {
+	return policyPeerLimit
+}
+
+// Accept rejects the channel if the peer reached its channel limit.
+//
+// NOTE: Part of the chanacceptor.ChannelAcceptor interface.
+func (p *peerPolicyAcceptor) Accept(
+	req *chanacceptor.ChannelAcceptRequest,
+) *chanacceptor.ChannelAcceptResponse {
+
+	reject := func(err error) *chanacceptor.ChannelAcceptResponse {
+		return chanacceptor.NewChannelAcceptResponse(
+			false, err, nil, 0, 0, 0, 0, 0, 0, false,
+		)
+	}
+
+	policy, ok := p.policies.policy(route.NewVertex(req.Node))
+	if ok && policy.MaxChannels > 0 {
+		channels, err := p.fetchChannels(req.Node)
+		if err != nil {
+			srvrLog.Errorf("Unable to fetch channels of peer %x: "+
+				"%v", req.Node.SerializeCompressed(), err)
+
+			return reject(nil)
+		}
+
+		if uint32(len(channels)) >= policy.MaxChannels {
+			return reject(fmt.Errorf("channel limit of %d "+
+				"reached", policy.MaxChannels))
+		}
+	}
+
+	return chanacceptor.NewChannelAcceptResponse(
+		true, nil, nil, 0, 0, 0, 0, 0, 0, false,
+	)
+}
+
+// A compile-time constraint to ensure peerPolicyAcceptor implements the
+// Policy interface.
+var _ chanacceptor.Policy = (*peerPolicyAcceptor)(nil)
+
+// PeerPolicies returns the connection policies of all peers.
+//
+// NOTE: Part of the peersrpc.PeerPolicyManager interface.
+func (s *server) PeerPolicies() map[route.Vertex]*channeldb.PeerPolicy {
+	return s.peerPolicies.allPolicies()
+}
+
+// SetPeerPolicy stores the connection policy of a peer. If the peer is
+// persistent, we connect to it right away and keep the connection, whether or
+// not we have a channel with it. The other settings only apply to new
+// connections and channels.
+//
+// NOTE: Part of the peersrpc.PeerPolicyManager interface.
+func (s *server) SetPeerPolicy(pubKey route.Vertex,
+	policy *channeldb.PeerPolicy) error {
+
+	if policy.Persistent && len(policy.Addresses) == 0 {
+		return errors.New("a persistent peer needs an address")
+	}
+
+	if policy.TorOnly {
+		for _, addr := range policy.Addresses {
+			if _, ok := addr.(*tor.OnionAddr); !ok {
+				return fmt.Errorf("address %v of tor-only "+
+					"peer isn't an onion address", addr)
+			}
+		}
+	}
+
+	identityKey, err := btcec.ParsePubKey(pubKey[:])
+	if err != nil {
+		return err
+	}
+
+	oldPolicy, err := s.peerPolicies.putPolicy(pubKey, policy)
+	if err != nil {
+		return err
+	}
+	pubStr := string(pubKey[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !policy.Persistent {
+		if oldPolicy != nil && oldPolicy.Persistent {
+			s.unpersistPeer(pubStr)
+		}
+
+		return nil
+	}
+
+	s.persistentPeers[pubStr] = true
+	if _, ok := s.persistentPeersBackoff[pubStr]; !ok {
+		s.persistentPeersBackoff[pubStr] = s.cfg.MinBackoff
+	}
+
+	addrs := make([]*lnwire.NetAddress, 0, len(policy.Addresses))
+	for _, addr := range policy.Addresses {
+		addrs = append(addrs, &lnwire.NetAddress{
+			IdentityKey: identityKey,
+			Address:     addr,
+		})
+	}
+	s.persistentPeerAddrs[pubStr] = addrs
+
+	if _, err := s.findPeerByPubStr(pubStr); err == ErrPeerNotConnected {
+		go s.connectToPersistentPeer(pubStr)
+	}
+
+	return nil
+}
+
+// DeletePeerPolicy removes the connection policy of a peer. If the peer was
+// persistent, we only keep the connection as long as we have a channel with
+// it.
+//
+// NOTE: Part of the peersrpc.PeerPolicyManager interface.
+func (s *server) DeletePeerPolicy(pubKey route.Vertex) error {
+	oldPolicy, err := s.peerPolicies.deletePolicy(pubKey)
+	if err != nil {
+		return err
+	}
+
+	if oldPolicy != nil && oldPolicy.Persistent {
+		s.mu.Lock()
+		s.unpersistPeer(string(pubKey[:]))
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// unpersistPeer marks the connection to a peer as one we only keep while we
+// have a channel with it.
+//
+// NOTE: This MUST be called with the server's mutex held.
+func (s *server) unpersistPeer(pubStr string) {
+	if _, ok := s.persistentPeers[pubStr]; ok {
+		s.persistentPeers[pubStr] = false
+	}
+}
+
+// InboundAccess returns the entries of the inbound connection allow and deny
+// lists.
+//
+// NOTE: Part of the peersrpc.PeerPolicyManager interface.
+func (s *server) InboundAccess() map[route.Vertex]channeldb.InboundAccess {
+	return s.peerPolicies.allAccess()
+}
+
+// SetInboundAccess puts a peer on the inbound connection allow or deny list,
+// or removes it from both. The lists only apply to new connections.
+//
+// NOTE: Part of the peersrpc.PeerPolicyManager interface.
+func (s *server) SetInboundAccess(pubKey route.Vertex,
+	access channeldb.InboundAccess) error {
+
+	return s.peerPolicies.putAccess(pubKey, access)
+}
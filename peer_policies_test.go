@@ -0,0 +1,89 @@
+package lnd
+
+import (
+	"net"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPeerPolicyCache tests that the peer policy cache decides which inbound
+// and outbound connections are allowed.
+func TestPeerPolicyCache(t *testing.T) {
+	t.Parallel()
+
+	db, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	cache, err := newPeerPolicyCache(db.ChannelStateDB())
+	require.NoError(t, err)
+
+	var (
+		peer1 = route.Vertex{1}
+		peer2 = route.Vertex{2}
+		peer3 = route.Vertex{3}
+
+		clearnet = &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 9735}
+		loopback = &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}
+		onion    = &tor.OnionAddr{
+			OnionService: "3g2upl4pq6kufc4m.onion",
+			Port:         9735,
+		}
+	)
+
+	// Without any policy, every connection is allowed.
+	require.NoError(t, cache.checkInbound(peer1, clearnet))
+	require.NoError(t, cache.checkOutbound(peer1, clearnet))
+
+	// A tor-only peer may only connect through our onion service and we
+	// only connect to its onion addresses.
+	_, err = cache.putPolicy(peer1, &channeldb.PeerPolicy{
+		TorOnly:      true,
+		AllowInbound: true,
+	})
+	require.NoError(t, err)
+
+	require.ErrorIs(t, cache.checkInbound(peer1, clearnet), errTorOnlyPeer)
+	require.NoError(t, cache.checkInbound(peer1, loopback))
+	require.ErrorIs(
+		t, cache.checkOutbound(peer1, clearnet), errTorOnlyPeer,
+	)
+	require.NoError(t, cache.checkOutbound(peer1, onion))
+
+	// A peer whose policy rejects inbound connections can't connect.
+	_, err = cache.putPolicy(peer2, &channeldb.PeerPolicy{})
+	require.NoError(t, err)
+	require.ErrorIs(
+		t, cache.checkInbound(peer2, clearnet), errInboundRejected,
+	)
+
+	// Once a peer is on the allow list, all other peers are rejected.
+	require.NoError(t, cache.putAccess(peer3, channeldb.InboundAccessAllow))
+	require.NoError(t, cache.checkInbound(peer3, clearnet))
+	require.ErrorIs(
+		t, cache.checkInbound(peer1, loopback), errInboundNotAllowed,
+	)
+
+	// Moving the peer to the deny list rejects it and empties the allow
+	// list again.
+	require.NoError(t, cache.putAccess(peer3, channeldb.InboundAccessDeny))
+	require.ErrorIs(
+		t, cache.checkInbound(peer3, clearnet), errInboundDenied,
+	)
+	require.NoError(t, cache.checkInbound(peer1, loopback))
+
+	// The policies and lists are loaded from the database on restart.
+	_, err = cache.deletePolicy(peer2)
+	require.NoError(t, err)
+
+	cache, err = newPeerPolicyCache(db.ChannelStateDB())
+	require.NoError(t, err)
+	require.Len(t, cache.allPolicies(), 1)
+	require.Equal(t, map[route.Vertex]channeldb.InboundAccess{
+		peer3: channeldb.InboundAccessDeny,
+	}, cache.allAccess())
+	require.NoError(t, cache.checkInbound(peer2, clearnet))
+}
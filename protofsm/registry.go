@@ -0,0 +1,100 @@
+package protofsm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrMachineNotFound is returned if a state machine isn't registered.
+var ErrMachineNotFound = errors.New("state machine not found")
+
+// Machine is the view of a state machine that is independent of its event
+// and environment types, so that state machines of different types can be
+// inspected together.
+type Machine interface {
+	// Name returns the unique name of the state machine.
+	Name() string
+
+	// StateName returns the name of the current state.
+	StateName() string
+
+	// IsTerminal returns true if the state machine reached a terminal
+	// state.
+	IsTerminal() bool
+
+	// Transitions returns the recent transitions, oldest first.
+	Transitions() []Transition
+
+	// InjectEvent parses the event from its textual form and sends it to
+	// the state machine.
+	InjectEvent(event string) error
+}
+
+// Registry keeps track of the active state machines, so that they can be
+// listed and inspected.
+type Registry struct {
+	mu       sync.RWMutex
+	machines map[string]Machine
+}
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		machines: make(map[string]Machine),
+	}
+}
+
+// Register adds a state machine to the registry. Its name must be unique.
+func (r *Registry) Register(machine Machine) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := machine.Name()
+	if _, ok := r.machines[name]; ok {
+		return fmt.Errorf("state machine %v already registered", name)
+	}
+
+	r.machines[name] = machine
+
+	return nil
+}
+
+// Unregister removes the state machine with the given name from the
+// registry.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.machines, name)
+}
+
+// Machine returns the state machine with the given name.
+func (r *Registry) Machine(name string) (Machine, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	machine, ok := r.machines[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrMachineNotFound, name)
+	}
+
+	return machine, nil
+}
+
+// Machines returns all registered state machines, ordered by name.
+func (r *Registry) Machines() []Machine {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	machines := make([]Machine, 0, len(r.machines))
+	for _, machine := range r.machines {
+		machines = append(machines, machine)
+	}
+	sort.Slice(machines, func(i, j int) bool {
+		return machines[i].Name() < machines[j].Name()
+	})
+
+	return machines
+}
@@ -0,0 +1,294 @@
+package protofsm
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+)
+
+const (
+	// DefaultMaxHistory is the default number of recent transitions that
+	// a state machine keeps.
+	DefaultMaxHistory = 50
+
+	// maxInternalEvents is the maximum number of internal events that are
+	// applied in response to a single event. It guards against states
+	// that emit events to each other forever.
+	maxInternalEvents = 1000
+)
+
+var (
+	// ErrTerminalState is returned if an event is sent to a state machine
+	// that reached a terminal state.
+	ErrTerminalState = errors.New("state machine is in a terminal state")
+
+	// ErrInjectionUnsupported is returned if an event is injected into a
+	// state machine that can't parse events.
+	ErrInjectionUnsupported = errors.New("state machine doesn't support " +
+		"event injection")
+)
+
+// State is a state of a state machine. It processes the events of type Event
+// within the environment Env, which holds the dependencies of the states.
+type State[Event any, Env any] interface {
+	// ProcessEvent applies the event to the state and returns the
+	// transition to the next state. If it returns no transition, the
+	// event was handled without leaving the state.
+	ProcessEvent(event Event, env Env) (*StateTransition[Event, Env],
+		error)
+
+	// IsTerminal returns true if the state machine is done once it
+	// reached this state.
+	IsTerminal() bool
+
+	// String returns the name of the state.
+	String() string
+}
+
+// StateTransition is the result of applying an event to a state.
+type StateTransition[Event any, Env any] struct {
+	// NextState is the state the state machine transitions to.
+	NextState State[Event, Env]
+
+	// NewEvents are internal events that are applied to the next state,
+	// in order, before the event that caused the transition returns.
+	NewEvents []Event
+}
+
+// Transition is a recorded transition of a state machine.
+type Transition struct {
+	// From is the name of the state the event was applied to.
+	From string
+
+	// To is the name of the state the state machine transitioned to.
+	To string
+
+	// Event describes the event that caused the transition.
+	Event string
+
+	// Timestamp is the time of the transition.
+	Timestamp time.Time
+}
+
+// Config holds the configuration of a state machine.
+type Config[Event any, Env any] struct {
+	// Name uniquely identifies the state machine, for example in the
+	// registry.
+	Name string
+
+	// InitialState is the state the state machine starts in.
+	InitialState State[Event, Env]
+
+	// Env is the environment that is passed to the states.
+	Env Env
+
+	// Checkpoint is called with the next state before the state machine
+	// transitions to it, so that the state can be persisted. If it
+	// returns an error, the state machine stays in its current state. It
+	// is optional.
+	Checkpoint func(State[Event, Env]) error
+
+	// ParseEvent parses an event from its textual form, so that events
+	// can be injected for testing. It is optional, events can't be
+	// injected if it is nil.
+	ParseEvent func(string) (Event, error)
+
+	// MaxHistory is the number of recent transitions that are kept. If it
+	// is zero, DefaultMaxHistory is used.
+	MaxHistory int
+
+	// Clock is the clock that timestamps the transitions. If it is nil,
+	// the default clock is used.
+	Clock clock.Clock
+}
+
+// StateMachine applies events to its current state, and transitions to the
+// states that result from them. Events are applied synchronously, so a call
+// to SendEvent returns once the event and all internal events it caused were
+// applied.
+type StateMachine[Event any, Env any] struct {
+	cfg Config[Event, Env]
+
+	// eventMtx serializes the application of events. It isn't held when
+	// the state is read, so the state can be inspected while an event is
+	// applied, also from within the states themselves.
+	eventMtx sync.Mutex
+
+	// mu guards the state and the history. Both are only modified while
+	// eventMtx is held as well.
+	mu      sync.Mutex
+	state   State[Event, Env]
+	history []Transition
+}
+
+// A compile time check to ensure StateMachine implements the Machine
+// interface.
+var _ Machine = (*StateMachine[any, any])(nil)
+
+// NewStateMachine creates a new state machine in its initial state.
+func NewStateMachine[Event any, Env any](
+	cfg Config[Event, Env]) *StateMachine[Event, Env] {
+
+	if cfg.MaxHistory == 0 {
+		cfg.MaxHistory = DefaultMaxHistory
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.NewDefaultClock()
+	}
+
+	return &StateMachine[Event, Env]{
+		cfg:   cfg,
+		state: cfg.InitialState,
+	}
+}
+
+// Name returns the name of the state machine.
+//
+// NOTE: This is part of the Machine interface.
+func (s *StateMachine[Event, Env]) Name() string {
+	return s.cfg.Name
+}
+
+// CurrentState returns the current state of the state machine.
+func (s *StateMachine[Event, Env]) CurrentState() State[Event, Env] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// StateName returns the name of the current state.
+//
+// NOTE: This is part of the Machine interface.
+func (s *StateMachine[Event, Env]) StateName() string {
+	return s.CurrentState().String()
+}
+
+// IsTerminal returns true if the state machine reached a terminal state.
+//
+// NOTE: This is part of the Machine interface.
+func (s *StateMachine[Event, Env]) IsTerminal() bool {
+	return s.CurrentState().IsTerminal()
+}
+
+// Transitions returns the recent transitions of the state machine, oldest
+// first.
+//
+// NOTE: This is part of the Machine interface.
+func (s *StateMachine[Event, Env]) Transitions() []Transition {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := make([]Transition, len(s.history))
+	copy(history, s.history)
+
+	return history
+}
+
+// InjectEvent parses the event and sends it to the state machine.
+//
+// NOTE: This is part of the Machine interface.
+func (s *StateMachine[Event, Env]) InjectEvent(event string) error {
+	if s.cfg.ParseEvent == nil {
+		return ErrInjectionUnsupported
+	}
+
+	e, err := s.cfg.ParseEvent(event)
+	if err != nil {
+		return err
+	}
+
+	return s.SendEvent(e)
+}
+
+// SendEvent applies the event to the current state, followed by the internal
+// events the transitions emit. Each transition is checkpointed before the
+// state machine moves on. If an event fails to apply, the state machine stays
+// in the state it reached so far and the error is returned.
+func (s *StateMachine[Event, Env]) SendEvent(event Event) error {
+	s.eventMtx.Lock()
+	defer s.eventMtx.Unlock()
+
+	// The state is only modified while eventMtx is held, so it can be
+	// read without holding mu here.
+	queue := []Event{event}
+	for i := 0; len(queue) > 0; i++ {
+		if i > maxInternalEvents {
+			return fmt.Errorf("more than %d internal events "+
+				"emitted in state %v", maxInternalEvents,
+				s.state)
+		}
+
+		if s.state.IsTerminal() {
+			return fmt.Errorf("%w: %v", ErrTerminalState, s.state)
+		}
+
+		event := queue[0]
+		queue = queue[1:]
+
+		transition, err := s.state.ProcessEvent(event, s.cfg.Env)
+		if err != nil {
+			return fmt.Errorf("unable to apply event %v in state "+
+				"%v: %w", describe(event), s.state, err)
+		}
+
+		// Without a transition, the event was handled in the current
+		// state, so there is nothing to checkpoint or record.
+		if transition == nil {
+			continue
+		}
+		if transition.NextState == nil {
+			return fmt.Errorf("event %v in state %v has no next "+
+				"state", describe(event), s.state)
+		}
+
+		if s.cfg.Checkpoint != nil {
+			err := s.cfg.Checkpoint(transition.NextState)
+			if err != nil {
+				return fmt.Errorf("unable to checkpoint state "+
+					"%v: %w", transition.NextState, err)
+			}
+		}
+
+		log.Debugf("State machine %v: %v -> %v on %v", s.cfg.Name,
+			s.state, transition.NextState, describe(event))
+
+		s.mu.Lock()
+		s.record(Transition{
+			From:      s.state.String(),
+			To:        transition.NextState.String(),
+			Event:     describe(event),
+			Timestamp: s.cfg.Clock.Now(),
+		})
+		s.state = transition.NextState
+		s.mu.Unlock()
+
+		queue = append(queue, transition.NewEvents...)
+	}
+
+	return nil
+}
+
+// record adds a transition to the history, dropping the oldest one once the
+// history is full.
+//
+// NOTE: Both mutexes must be held when calling this method.
+func (s *StateMachine[Event, Env]) record(t Transition) {
+	if len(s.history) >= s.cfg.MaxHistory {
+		s.history = append(s.history[:0], s.history[1:]...)
+	}
+
+	s.history = append(s.history, t)
+}
+
+// describe returns the textual form of an event.
+func describe(event any) string {
+	if stringer, ok := event.(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	return fmt.Sprintf("%T", event)
+}
@@ -0,0 +1,194 @@
+package protofsm
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/stretchr/testify/require"
+)
+
+// testEvent is an event of the test state machine.
+type testEvent string
+
+// String returns the name of the event.
+func (e testEvent) String() string {
+	return string(e)
+}
+
+// testEnv counts the events the test states processed.
+type testEnv struct {
+	processed int
+
+	// machine is the state machine of the env. The states look up its
+	// current state on a "peek" event.
+	machine *StateMachine[testEvent, *testEnv]
+
+	// peeked is the state name the last "peek" event looked up.
+	peeked string
+}
+
+// testState is a state of the test state machine. It moves to the next state
+// on a "next" event, emits a "next" event on a "skip" event, stays in the
+// state on a "stay" event, looks up the current state of the machine on a
+// "peek" event and fails on any other event.
+type testState struct {
+	index    int
+	terminal int
+}
+
+// ProcessEvent applies the event to the state.
+func (s *testState) ProcessEvent(event testEvent,
+	env *testEnv) (*StateTransition[testEvent, *testEnv], error) {
+
+	env.processed++
+
+	next := &testState{index: s.index + 1, terminal: s.terminal}
+	switch event {
+	case "next":
+		return &StateTransition[testEvent, *testEnv]{
+			NextState: next,
+		}, nil
+
+	case "skip":
+		return &StateTransition[testEvent, *testEnv]{
+			NextState: next,
+			NewEvents: []testEvent{"next"},
+		}, nil
+
+	case "stay":
+		return nil, nil
+
+	case "peek":
+		env.peeked = env.machine.StateName()
+
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("unknown event %v", event)
+	}
+}
+
+// IsTerminal returns true once the terminal index is reached.
+func (s *testState) IsTerminal() bool {
+	return s.index >= s.terminal
+}
+
+// String returns the name of the state.
+func (s *testState) String() string {
+	return fmt.Sprintf("state%d", s.index)
+}
+
+// TestStateMachine tests that events are applied synchronously, transitions
+// are checkpointed and recorded, and failed events leave the state as is.
+func TestStateMachine(t *testing.T) {
+	t.Parallel()
+
+	testClock := clock.NewTestClock(time.Unix(1000, 0))
+	env := &testEnv{}
+
+	var (
+		checkpoints []string
+		failCheck   bool
+	)
+	machine := NewStateMachine(Config[testEvent, *testEnv]{
+		Name:         "test",
+		InitialState: &testState{terminal: 5},
+		Env:          env,
+		Checkpoint: func(s State[testEvent, *testEnv]) error {
+			if failCheck {
+				return errors.New("checkpoint failed")
+			}
+
+			checkpoints = append(checkpoints, s.String())
+
+			return nil
+		},
+		ParseEvent: func(s string) (testEvent, error) {
+			return testEvent(s), nil
+		},
+		MaxHistory: 2,
+		Clock:      testClock,
+	})
+	env.machine = machine
+
+	require.Equal(t, "test", machine.Name())
+	require.Equal(t, "state0", machine.StateName())
+
+	// Internal events are applied before SendEvent returns.
+	require.NoError(t, machine.SendEvent("skip"))
+	require.Equal(t, "state2", machine.StateName())
+	require.Equal(t, 2, env.processed)
+	require.Equal(t, []string{"state1", "state2"}, checkpoints)
+
+	// An event without a transition is neither checkpointed nor recorded,
+	// and the state can be read while an event is applied.
+	require.NoError(t, machine.SendEvent("stay"))
+	require.NoError(t, machine.SendEvent("peek"))
+	require.Equal(t, "state2", env.peeked)
+	require.Equal(t, []string{"state1", "state2"}, checkpoints)
+	require.Len(t, machine.Transitions(), 2)
+
+	// A failing event or checkpoint leaves the state as is.
+	require.ErrorContains(t, machine.SendEvent("bogus"), "unknown event")
+	failCheck = true
+	require.ErrorContains(t, machine.SendEvent("next"), "checkpoint")
+	require.Equal(t, "state2", machine.StateName())
+	failCheck = false
+
+	// Events can be injected in their textual form.
+	require.NoError(t, machine.InjectEvent("next"))
+	require.Equal(t, "state3", machine.StateName())
+
+	// Only the most recent transitions are kept.
+	require.Equal(t, []Transition{{
+		From:      "state1",
+		To:        "state2",
+		Event:     "next",
+		Timestamp: testClock.Now(),
+	}, {
+		From:      "state2",
+		To:        "state3",
+		Event:     "next",
+		Timestamp: testClock.Now(),
+	}}, machine.Transitions())
+
+	// No events are applied once a terminal state is reached.
+	require.NoError(t, machine.SendEvent("skip"))
+	require.True(t, machine.IsTerminal())
+	require.ErrorIs(t, machine.SendEvent("next"), ErrTerminalState)
+}
+
+// TestRegistry tests that state machines can be registered, listed and looked
+// up by name.
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	newMachine := func(name string) *StateMachine[testEvent, *testEnv] {
+		return NewStateMachine(Config[testEvent, *testEnv]{
+			Name:         name,
+			InitialState: &testState{terminal: 1},
+			Env:          &testEnv{},
+		})
+	}
+
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(newMachine("b")))
+	require.NoError(t, registry.Register(newMachine("a")))
+	require.Error(t, registry.Register(newMachine("a")))
+
+	machines := registry.Machines()
+	require.Len(t, machines, 2)
+	require.Equal(t, "a", machines[0].Name())
+	require.Equal(t, "b", machines[1].Name())
+
+	machine, err := registry.Machine("b")
+	require.NoError(t, err)
+	require.ErrorIs(t, machine.InjectEvent("next"), ErrInjectionUnsupported)
+
+	registry.Unregister("b")
+	_, err = registry.Machine("b")
+	require.ErrorIs(t, err, ErrMachineNotFound)
+}
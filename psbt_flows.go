@@ -0,0 +1,111 @@
+package lnd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// ListAbortedPsbtFlows returns the PSBT funding flows that were aborted
+// because lnd restarted while they were in progress, together with the keys
+// and scripts needed to recover funds that were sent to their funding output.
+func (r *rpcServer) ListAbortedPsbtFlows(_ context.Context,
+	_ *lnrpc.ListAbortedPsbtFlowsRequest) (
+	*lnrpc.ListAbortedPsbtFlowsResponse, error) {
+
+	flows, err := r.server.fundingMgr.AbortedPsbtFlows()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ListAbortedPsbtFlowsResponse{}
+	for _, flow := range flows {
+		rpcFlow, err := marshallAbortedPsbtFlow(flow)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Flows = append(resp.Flows, rpcFlow)
+	}
+
+	return resp, nil
+}
+
+// marshallAbortedPsbtFlow converts an aborted PSBT funding flow to its RPC
+// representation.
+func marshallAbortedPsbtFlow(
+	flow *channeldb.PendingFundingFlow) (*lnrpc.AbortedPsbtFlow, error) {
+
+	rpcFlow := &lnrpc.AbortedPsbtFlow{
+		PendingChanId:        flow.PendingChanID[:],
+		UpdatedAt:            flow.UpdatedAt.Unix(),
+		PeerPubkey:           flow.Peer.SerializeCompressed(),
+		Capacity:             int64(flow.Capacity),
+		FundingPkScript:      flow.FundingPkScript,
+		FundingWitnessScript: flow.FundingWitnessScript,
+		LocalKey: &lnrpc.KeyDescriptor{
+			KeyLoc: &lnrpc.KeyLocator{
+				KeyFamily: int32(flow.LocalKey.Family),
+				KeyIndex:  int32(flow.LocalKey.Index),
+			},
+		},
+		RemoteKey: flow.RemoteKey.SerializeCompressed(),
+	}
+
+	if flow.LocalKey.PubKey != nil {
+		rpcFlow.LocalKey.RawKeyBytes =
+			flow.LocalKey.PubKey.SerializeCompressed()
+	}
+
+	switch flow.Step {
+	case channeldb.FundingFlowAwaitingPsbt:
+		rpcFlow.Step = lnrpc.PsbtFlowStep_AWAITING_PSBT
+
+	case channeldb.FundingFlowPsbtFinalized:
+		rpcFlow.Step = lnrpc.PsbtFlowStep_PSBT_FINALIZED
+		rpcFlow.ChannelPoint = flow.FundingOutpoint.String()
+
+	default:
+		return nil, fmt.Errorf("unknown funding flow step %v",
+			flow.Step)
+	}
+
+	if flow.FundingTx != nil {
+		var buf bytes.Buffer
+		if err := flow.FundingTx.Serialize(&buf); err != nil {
+			return nil, err
+		}
+
+		rpcFlow.RawFundingTx = buf.Bytes()
+	}
+
+	return rpcFlow, nil
+}
+
+// ForgetAbortedPsbtFlow removes an aborted PSBT funding flow once the user
+// made sure that no funds are left in its funding output.
+func (r *rpcServer) ForgetAbortedPsbtFlow(_ context.Context,
+	req *lnrpc.ForgetAbortedPsbtFlowRequest) (
+	*lnrpc.ForgetAbortedPsbtFlowResponse, error) {
+
+	if len(req.PendingChanId) != 32 {
+		return nil, fmt.Errorf("pending channel ID must be 32 bytes, "+
+			"got %d", len(req.PendingChanId))
+	}
+
+	var id [32]byte
+	copy(id[:], req.PendingChanId)
+
+	err := r.server.fundingMgr.ForgetAbortedPsbtFlow(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("Aborted PSBT funding flow pending_chan_id=%x forgotten",
+		id[:])
+
+	return &lnrpc.ForgetAbortedPsbtFlowResponse{}, nil
+}
@@ -0,0 +1,85 @@
+package lnd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshallAbortedPsbtFlow tests that the recovery information of an
+// aborted PSBT funding flow is returned over RPC, and that the funding
+// outpoint is only set once the funding transaction was finalized.
+func TestMarshallAbortedPsbtFlow(t *testing.T) {
+	t.Parallel()
+
+	peer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	local, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	remote, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	flow := &channeldb.PendingFundingFlow{
+		PendingChanID:        [32]byte{1},
+		Step:                 channeldb.FundingFlowAwaitingPsbt,
+		Aborted:              true,
+		UpdatedAt:            time.Unix(1_700_000_000, 0),
+		Peer:                 peer.PubKey(),
+		Capacity:             1_000_000,
+		FundingPkScript:      []byte{0x00, 0x20},
+		FundingWitnessScript: []byte{0x52},
+		LocalKey: keychain.KeyDescriptor{
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamilyMultiSig,
+				Index:  3,
+			},
+			PubKey: local.PubKey(),
+		},
+		RemoteKey: remote.PubKey(),
+	}
+
+	rpcFlow, err := marshallAbortedPsbtFlow(flow)
+	require.NoError(t, err)
+	require.Equal(t, flow.PendingChanID[:], rpcFlow.PendingChanId)
+	require.Equal(t, lnrpc.PsbtFlowStep_AWAITING_PSBT, rpcFlow.Step)
+	require.EqualValues(t, 1_700_000_000, rpcFlow.UpdatedAt)
+	require.Equal(
+		t, peer.PubKey().SerializeCompressed(), rpcFlow.PeerPubkey,
+	)
+	require.EqualValues(t, 1_000_000, rpcFlow.Capacity)
+	require.Equal(
+		t, local.PubKey().SerializeCompressed(),
+		rpcFlow.LocalKey.RawKeyBytes,
+	)
+	require.EqualValues(t, 3, rpcFlow.LocalKey.KeyLoc.KeyIndex)
+	require.Equal(
+		t, remote.PubKey().SerializeCompressed(), rpcFlow.RemoteKey,
+	)
+	require.Empty(t, rpcFlow.ChannelPoint)
+	require.Empty(t, rpcFlow.RawFundingTx)
+
+	// Once finalized, the funding outpoint and transaction are known.
+	fundingTx := wire.NewMsgTx(2)
+	fundingTx.AddTxOut(wire.NewTxOut(1_000_000, flow.FundingPkScript))
+
+	flow.Step = channeldb.FundingFlowPsbtFinalized
+	flow.FundingOutpoint = wire.OutPoint{Hash: chainhash.Hash{2}}
+	flow.FundingTx = fundingTx
+
+	rpcFlow, err = marshallAbortedPsbtFlow(flow)
+	require.NoError(t, err)
+	require.Equal(t, lnrpc.PsbtFlowStep_PSBT_FINALIZED, rpcFlow.Step)
+	require.Equal(t, flow.FundingOutpoint.String(), rpcFlow.ChannelPoint)
+
+	var buf bytes.Buffer
+	require.NoError(t, fundingTx.Serialize(&buf))
+	require.Equal(t, buf.Bytes(), rpcFlow.RawFundingTx)
+}
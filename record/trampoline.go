@@ -0,0 +1,45 @@
+package record
+
+import (
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// OutgoingNodeIDOnionType is the type used in a trampoline onion
+	// payload to reference the node the payment should be forwarded to.
+	OutgoingNodeIDOnionType tlv.Type = 14
+
+	// InvoiceFeaturesOnionType is the type used in a trampoline onion
+	// payload to pass the feature bits of the recipient's invoice to the
+	// last trampoline node when the recipient doesn't support trampoline.
+	InvoiceFeaturesOnionType tlv.Type = 66097
+
+	// InvoiceRoutingInfoOnionType is the type used in a trampoline onion
+	// payload to pass the route hints of the recipient's invoice to the
+	// last trampoline node when the recipient doesn't support trampoline.
+	InvoiceRoutingInfoOnionType tlv.Type = 66099
+
+	// TrampolineOnionType is the type used in the outer onion payload of
+	// a trampoline node to carry the nested trampoline onion packet. The
+	// type lies in the custom record range, which is also the type
+	// existing trampoline implementations use.
+	TrampolineOnionType tlv.Type = 66100
+)
+
+// NewOutgoingNodeIDRecord creates a tlv.Record that encodes the
+// outgoing_node_id (type 14) for a trampoline onion payload.
+func NewOutgoingNodeIDRecord(nodeID *[33]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(OutgoingNodeIDOnionType, nodeID)
+}
+
+// NewInvoiceFeaturesRecord creates a tlv.Record that encodes the
+// invoice_features (type 66097) for a trampoline onion payload.
+func NewInvoiceFeaturesRecord(features *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(InvoiceFeaturesOnionType, features)
+}
+
+// NewInvoiceRoutingInfoRecord creates a tlv.Record that encodes the
+// invoice_routing_info (type 66099) for a trampoline onion payload.
+func NewInvoiceRoutingInfoRecord(routingInfo *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(InvoiceRoutingInfoOnionType, routingInfo)
+}
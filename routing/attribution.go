@@ -0,0 +1,249 @@
+package routing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// attributionStreamKey is the key type used to derive the stream that
+	// obfuscates the attribution data at every hop.
+	attributionStreamKey = "ammagext"
+
+	// attributionHMACKey is the key type used to derive the key of the
+	// HMACs that a hop adds to the attribution data.
+	attributionHMACKey = "um"
+
+	// failureStreamKey is the key type used to derive the stream that
+	// obfuscates the failure reason at every hop.
+	failureStreamKey = "ammag"
+)
+
+// attributedError is a payment failure annotated with the outcome of the
+// analysis of the attribution data that was attached to it.
+type attributedError struct {
+	error
+
+	// holdTimes are the times that the hops held the HTLC, in route
+	// order. Only hops whose attribution data could be authenticated are
+	// included.
+	holdTimes []time.Duration
+
+	// blamedIdx is the position in the route of the node that is held
+	// responsible for an unreadable failure. Position zero is the sender
+	// node.
+	blamedIdx fn.Option[int]
+}
+
+// Unwrap returns the underlying payment failure.
+func (e *attributedError) Unwrap() error {
+	return e.error
+}
+
+// attributeFailure analyzes the attribution data that is attached to a
+// failed payment attempt, if any. The returned error wraps the original
+// failure together with the hold times reported by the hops and, if the
+// failure itself couldn't be decrypted, the node that is held responsible.
+func attributeFailure(attempt *channeldb.HTLCAttempt,
+	result *htlcswitch.PaymentResult) error {
+
+	sendErr := result.Error
+	if result.Attribution.IsNone() {
+		return sendErr
+	}
+
+	rt := &attempt.Route
+	unreadable := errors.Is(sendErr, htlcswitch.ErrUnreadableFailureMessage)
+
+	// For readable failures, we only need to look at the hops up to and
+	// including the one that reported the failure. Local failures never
+	// carry attribution data.
+	numHops := len(rt.Hops)
+	if !unreadable {
+		var fErr *htlcswitch.ForwardingError
+		if !errors.As(sendErr, &fErr) {
+			return sendErr
+		}
+
+		numHops = fErr.FailureSourceIdx
+	}
+
+	holdTimes, err := decodeAttribution(
+		attempt.SessionKey(), rt, result.Attribution.UnsafeFromSome(),
+		numHops,
+	)
+	if err != nil {
+		log.Errorf("Unable to decode attribution data of attempt %v: "+
+			"%v", attempt.AttemptID, err)
+
+		return sendErr
+	}
+
+	attrErr := &attributedError{
+		error:     sendErr,
+		holdTimes: holdTimes,
+	}
+
+	log.Debugf("Attempt %v failed with hold times %v", attempt.AttemptID,
+		holdTimes)
+
+	if !unreadable {
+		return attrErr
+	}
+
+	// Routes that are longer than the attribution data can cover can't
+	// be fully verified, so we can't blame any of the hops beyond it.
+	verified := len(holdTimes)
+	if verified == lnwire.AttributionMaxHops &&
+		len(rt.Hops) > lnwire.AttributionMaxHops {
+
+		return attrErr
+	}
+
+	// The attribution data of the first hop that couldn't be
+	// authenticated was corrupted either by that hop or by its
+	// predecessor, which was the last one to vouch for the data. We blame
+	// the predecessor, unless it is ourselves. If all hops could be
+	// authenticated, the final hop sent the unreadable failure.
+	attrErr.blamedIdx = fn.Some(max(verified, 1))
+
+	return attrErr
+}
+
+// decodeAttribution peels off the attribution data layer by layer for the
+// given number of hops of the route, and returns the hold times of the hops
+// whose HMAC could be verified. Decoding stops at the first hop whose HMAC is
+// invalid.
+func decodeAttribution(sessionKey *btcec.PrivateKey, rt *route.Route,
+	attr htlcswitch.FailureAttribution, numHops int) ([]time.Duration,
+	error) {
+
+	numHops = min(numHops, len(rt.Hops), lnwire.AttributionMaxHops)
+
+	pubKeys := make([]*btcec.PublicKey, numHops)
+	for i, hop := range rt.Hops[:numHops] {
+		pubKey, err := btcec.ParsePubKey(hop.PubKeyBytes[:])
+		if err != nil {
+			return nil, err
+		}
+
+		pubKeys[i] = pubKey
+	}
+
+	_, sharedSecrets, err := onionSharedSecrets(sessionKey, pubKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := make([]byte, len(attr.Reason))
+	copy(reason, attr.Reason)
+	data := attr.Data
+
+	holdTimes := make([]time.Duration, 0, numHops)
+	for position, secret := range sharedSecrets {
+		xorBytes(data[:], onionCipherStream(
+			onionKey(attributionStreamKey, secret),
+			lnwire.AttributionDataSize,
+		))
+
+		hmacKey := onionKey(attributionHMACKey, secret)
+		expected := attributionHMAC(hmacKey, position, reason, &data)
+
+		offset := attributionHMACOffset(0, position)
+		actual := data[offset : offset+lnwire.AttributionHMACSize]
+		if !hmac.Equal(expected, actual) {
+			break
+		}
+
+		holdTime := binary.BigEndian.Uint32(
+			data[:lnwire.AttributionHoldTimeSize],
+		)
+		holdTimes = append(
+			holdTimes, time.Duration(holdTime)*lnwire.HoldTimeUnit,
+		)
+
+		// Remove this hop's layer of the failure reason and
+		// attribution data to obtain the data as sent by the next hop.
+		xorBytes(reason, onionCipherStream(
+			onionKey(failureStreamKey, secret), len(reason),
+		))
+		data = unshiftAttribution(&data)
+	}
+
+	return holdTimes, nil
+}
+
+// attributionHMACOffset returns the offset in the attribution data of the
+// HMAC at the given index of the given block. The attribution data holds a
+// block of HMACs for every hop, where block zero belongs to the hop that
+// added it last. Every block has one HMAC less than the previous one, as the
+// hops further downstream have fewer positions left in the route.
+func attributionHMACOffset(block, idx int) int {
+	const n = lnwire.AttributionMaxHops
+
+	hmacs := block*n - block*(block-1)/2 + idx
+	hmacsOffset := hmacs * lnwire.AttributionHMACSize
+
+	return lnwire.AttributionHoldTimesSize + hmacsOffset
+}
+
+// attributionHMAC computes the truncated HMAC that a hop at the given
+// position adds to the attribution data. It commits to the failure reason as
+// sent by the hop, the hold times of the hop and its successors, and the HMACs
+// that its successors added for their respective positions.
+func attributionHMAC(key []byte, position int, reason []byte,
+	data *lnwire.AttributionData) []byte {
+
+	const n = lnwire.AttributionMaxHops
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(reason)
+	mac.Write(data[:(n-position)*lnwire.AttributionHoldTimeSize])
+
+	for block := 1; block < n-position; block++ {
+		offset := attributionHMACOffset(block, position)
+		mac.Write(data[offset : offset+lnwire.AttributionHMACSize])
+	}
+
+	return mac.Sum(nil)[:lnwire.AttributionHMACSize]
+}
+
+// unshiftAttribution reverts the shift that a hop applied to the attribution
+// data before adding its own hold time and HMACs. The slots that the hop
+// dropped can't be recovered and are left empty.
+func unshiftAttribution(data *lnwire.AttributionData) lnwire.AttributionData {
+	const n = lnwire.AttributionMaxHops
+
+	const (
+		holdTimeSize  = lnwire.AttributionHoldTimeSize
+		holdTimesSize = lnwire.AttributionHoldTimesSize
+	)
+
+	var next lnwire.AttributionData
+	copy(
+		next[:holdTimesSize-holdTimeSize],
+		data[holdTimeSize:holdTimesSize],
+	)
+
+	// The HMACs of block k+1 were shifted into block k by the hop, which
+	// dropped the first HMAC of every block.
+	for block := 0; block < n-1; block++ {
+		dst := attributionHMACOffset(block, 1)
+		src := attributionHMACOffset(block+1, 0)
+		size := (n - block - 1) * lnwire.AttributionHMACSize
+
+		copy(next[dst:dst+size], data[src:src+size])
+	}
+
+	return next
+}
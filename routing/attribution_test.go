@@ -0,0 +1,212 @@
+package routing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// addAttribution adds the layer of a hop to the attribution data, as done by
+// a node that fails an htlc back. The reason is the failure reason as sent by
+// the hop.
+func addAttribution(secret, reason []byte, holdTime uint32,
+	data *lnwire.AttributionData) {
+
+	const (
+		n             = lnwire.AttributionMaxHops
+		holdTimeSize  = lnwire.AttributionHoldTimeSize
+		holdTimesSize = lnwire.AttributionHoldTimesSize
+	)
+
+	var next lnwire.AttributionData
+	copy(
+		next[holdTimeSize:holdTimesSize],
+		data[:holdTimesSize-holdTimeSize],
+	)
+	binary.BigEndian.PutUint32(next[:holdTimeSize], holdTime)
+
+	for block := 0; block < n-1; block++ {
+		dst := attributionHMACOffset(block+1, 0)
+		src := attributionHMACOffset(block, 1)
+		size := (n - block - 1) * lnwire.AttributionHMACSize
+
+		copy(next[dst:dst+size], data[src:src+size])
+	}
+
+	key := onionKey(attributionHMACKey, secret)
+	for position := 0; position < n; position++ {
+		offset := attributionHMACOffset(0, position)
+		mac := attributionHMAC(key, position, reason, &next)
+		copy(next[offset:], mac)
+	}
+
+	xorBytes(next[:], onionCipherStream(
+		onionKey(attributionStreamKey, secret),
+		lnwire.AttributionDataSize,
+	))
+
+	*data = next
+}
+
+// TestAttributeFailure asserts that hold times are extracted from the
+// attribution data and that unreadable failures are attributed to the
+// correct node.
+func TestAttributeFailure(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 4
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	pubKeys := make([]*btcec.PublicKey, numHops)
+	hops := make([]*route.Hop, numHops)
+	for i := range hops {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		pubKeys[i] = privKey.PubKey()
+		hops[i] = &route.Hop{
+			PubKeyBytes: route.NewVertex(pubKeys[i]),
+			ChannelID:   uint64(i + 1),
+		}
+	}
+
+	_, secrets, err := onionSharedSecrets(sessionKey, pubKeys)
+	require.NoError(t, err)
+
+	attempt := channeldb.NewHtlcAttempt(
+		1, sessionKey, route.Route{Hops: hops}, time.Now(), nil,
+	)
+
+	// buildAttribution creates the attribution data of a failure that
+	// originated at the given hop. Every hop holds the htlc for one unit
+	// longer than its successor. If corruptAt is set, the data is
+	// corrupted after the hop at that index added its layer.
+	buildAttribution := func(erringHop int,
+		corruptAt fn.Option[int]) htlcswitch.FailureAttribution {
+
+		reason := bytes.Repeat([]byte{1}, 256)
+
+		var data lnwire.AttributionData
+		for i := erringHop; i >= 0; i-- {
+			xorBytes(reason, onionCipherStream(
+				onionKey(failureStreamKey, secrets[i]),
+				len(reason),
+			))
+
+			holdTime := uint32(numHops - i)
+			addAttribution(secrets[i], reason, holdTime, &data)
+
+			if corruptAt == fn.Some(i) {
+				data[0] ^= 1
+			}
+		}
+
+		return htlcswitch.FailureAttribution{
+			Reason: reason,
+			Data:   data,
+		}
+	}
+
+	holdTimes := func(hops int) []time.Duration {
+		holdTimes := make([]time.Duration, 0, hops)
+		for i := 0; i < hops; i++ {
+			holdTimes = append(
+				holdTimes,
+				time.Duration(numHops-i)*lnwire.HoldTimeUnit,
+			)
+		}
+
+		return holdTimes
+	}
+
+	testCases := []struct {
+		name              string
+		sendErr           error
+		attribution       fn.Option[htlcswitch.FailureAttribution]
+		expectedHoldTimes []time.Duration
+		expectedBlamedIdx fn.Option[int]
+	}{
+		{
+			name:    "no attribution",
+			sendErr: htlcswitch.ErrUnreadableFailureMessage,
+		},
+		{
+			name: "forwarding error",
+			sendErr: htlcswitch.NewForwardingError(
+				&lnwire.FailTemporaryNodeFailure{}, 2,
+			),
+			attribution: fn.Some(
+				buildAttribution(1, fn.None[int]()),
+			),
+			expectedHoldTimes: holdTimes(2),
+		},
+		{
+			name:    "unreadable from final hop",
+			sendErr: htlcswitch.ErrUnreadableFailureMessage,
+			attribution: fn.Some(
+				buildAttribution(numHops-1, fn.None[int]()),
+			),
+			expectedHoldTimes: holdTimes(numHops),
+			expectedBlamedIdx: fn.Some(numHops),
+		},
+		{
+			name:    "corrupted by intermediate hop",
+			sendErr: htlcswitch.ErrUnreadableFailureMessage,
+			attribution: fn.Some(
+				buildAttribution(numHops-1, fn.Some(2)),
+			),
+			expectedHoldTimes: holdTimes(2),
+			expectedBlamedIdx: fn.Some(2),
+		},
+		{
+			name:    "corrupted by first hop",
+			sendErr: htlcswitch.ErrUnreadableFailureMessage,
+			attribution: fn.Some(
+				buildAttribution(numHops-1, fn.Some(0)),
+			),
+			expectedHoldTimes: holdTimes(0),
+			expectedBlamedIdx: fn.Some(1),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := &htlcswitch.PaymentResult{
+				Error:       tc.sendErr,
+				Attribution: tc.attribution,
+			}
+
+			err := attributeFailure(attempt, result)
+			require.ErrorIs(t, err, tc.sendErr)
+
+			if tc.attribution.IsNone() {
+				require.Equal(t, tc.sendErr, err)
+				return
+			}
+
+			var attrErr *attributedError
+			require.ErrorAs(t, err, &attrErr)
+			require.Equal(
+				t, tc.expectedHoldTimes, attrErr.holdTimes,
+			)
+			require.Equal(
+				t, tc.expectedBlamedIdx, attrErr.blamedIdx,
+			)
+		})
+	}
+}
@@ -27,6 +27,10 @@ type bandwidthHints interface {
 	// firstHopCustomBlob returns the custom blob for the first hop of the
 	// payment, if available.
 	firstHopCustomBlob() fn.Option[tlv.Blob]
+
+	// peerChannels returns our local channels, grouped by the peer they
+	// connect us to.
+	peerChannels() map[route.Vertex][]uint64
 }
 
 // TlvTrafficShaper is an interface that allows the sender to determine if a
@@ -71,7 +75,7 @@ type getLinkQuery func(lnwire.ShortChannelID) (
 // balances.
 type bandwidthManager struct {
 	getLink       getLinkQuery
-	localChans    map[lnwire.ShortChannelID]struct{}
+	localChans    map[lnwire.ShortChannelID]route.Vertex
 	firstHopBlob  fn.Option[tlv.Blob]
 	trafficShaper fn.Option[TlvTrafficShaper]
 }
@@ -88,7 +92,7 @@ func newBandwidthManager(graph Graph, sourceNode route.Vertex,
 
 	manager := &bandwidthManager{
 		getLink:       linkQuery,
-		localChans:    make(map[lnwire.ShortChannelID]struct{}),
+		localChans:    make(map[lnwire.ShortChannelID]route.Vertex),
 		firstHopBlob:  firstHopBlob,
 		trafficShaper: trafficShaper,
 	}
@@ -100,7 +104,7 @@ func newBandwidthManager(graph Graph, sourceNode route.Vertex,
 			shortID := lnwire.NewShortChanIDFromInt(
 				channel.ChannelID,
 			)
-			manager.localChans[shortID] = struct{}{}
+			manager.localChans[shortID] = channel.OtherNode
 
 			return nil
 		})
@@ -263,3 +267,14 @@ func (b *bandwidthManager) availableChanBandwidth(channelID uint64,
 func (b *bandwidthManager) firstHopCustomBlob() fn.Option[tlv.Blob] {
 	return b.firstHopBlob
 }
+
+// peerChannels returns our local channels, grouped by the peer they connect
+// us to.
+func (b *bandwidthManager) peerChannels() map[route.Vertex][]uint64 {
+	peerChans := make(map[route.Vertex][]uint64)
+	for shortID, peer := range b.localChans {
+		peerChans[peer] = append(peerChans[peer], shortID.ToUint64())
+	}
+
+	return peerChans
+}
@@ -0,0 +1,35 @@
+package routing
+
+// CltvBudget limits the time lock delta that individual hops of a route may
+// add to a payment. This allows senders with strict timeout exposure policies
+// to avoid routes through nodes with excessive time lock deltas, even if the
+// total time lock of the route is within the payment's CLTV limit.
+//
+// Hops are divided into classes, each having their own limit. A zero limit
+// means that hops of the respective class aren't constrained.
+type CltvBudget struct {
+	// MaxHopDelta is the maximum time lock delta that an intermediate hop
+	// may charge.
+	MaxHopDelta uint16
+
+	// MaxLastHopDelta is the maximum time lock delta that the last hop
+	// before the destination may charge. It is a separate class as the
+	// last hop is often chosen by the receiver through route hints.
+	MaxLastHopDelta uint16
+}
+
+// allows returns true if the given time lock delta is within the budget of
+// the hop's class. The time lock delta of a blinded path is the aggregate of
+// all of its hops and isn't subject to the budget.
+func (b *CltvBudget) allows(delta uint16, lastHop, blinded bool) bool {
+	if b == nil || blinded {
+		return true
+	}
+
+	limit := b.MaxHopDelta
+	if lastHop {
+		limit = b.MaxLastHopDelta
+	}
+
+	return limit == 0 || delta <= limit
+}
@@ -446,7 +446,7 @@ func testPaymentControlSubscribeFail(t *testing.T, registerAttempt,
 		if err != nil {
 			t.Fatalf("unable to fail htlc: %v", err)
 		}
-		if *htlcAttempt.Failure != failInfo {
+		if !reflect.DeepEqual(*htlcAttempt.Failure, failInfo) {
 			t.Fatalf("unexpected fail info returned")
 		}
 	}
@@ -0,0 +1,219 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// maxDiverseRouteAttempts is the maximum number of path finding
+	// attempts made per requested route when searching for diverse
+	// routes.
+	maxDiverseRouteAttempts = 10
+)
+
+// HopCost is the cost that a single hop of a route charges for forwarding.
+type HopCost struct {
+	// PubKeyBytes is the node that charges the cost.
+	PubKeyBytes route.Vertex
+
+	// Fee is the fee the hop charges for forwarding to the next hop.
+	Fee lnwire.MilliSatoshi
+
+	// TimeLockDelta is the time lock delta the hop requires between its
+	// incoming and outgoing htlc.
+	TimeLockDelta uint32
+}
+
+// RouteCostBreakdown returns the fee and time lock delta that every hop of the
+// route charges, in route order. The final hop doesn't forward and therefore
+// is not included.
+func RouteCostBreakdown(rt *route.Route) []HopCost {
+	if len(rt.Hops) == 0 {
+		return nil
+	}
+
+	costs := make([]HopCost, 0, len(rt.Hops)-1)
+	for i, hop := range rt.Hops[:len(rt.Hops)-1] {
+		nextHop := rt.Hops[i+1]
+		delta := hop.OutgoingTimeLock - nextHop.OutgoingTimeLock
+
+		costs = append(costs, HopCost{
+			PubKeyBytes:   hop.PubKeyBytes,
+			Fee:           rt.HopFee(i),
+			TimeLockDelta: delta,
+		})
+	}
+
+	return costs
+}
+
+// DiverseRoute is one of the routes found by FindDiverseRoutes.
+type DiverseRoute struct {
+	// Route is the route to the destination.
+	Route *route.Route
+
+	// Probability is the estimated success probability of the route.
+	Probability float64
+
+	// Costs is the breakdown of the fees and time lock deltas charged by
+	// the hops of the route.
+	Costs []HopCost
+}
+
+// FindDiverseRoutes finds up to numRoutes routes for the given request, in
+// order of increasing cost. Every route shares at most maxSharedEdges node
+// pairs with each of the routes found before it. This allows callers to
+// implement their own retry logic over a set of alternative routes.
+func (r *ChannelRouter) FindDiverseRoutes(req *RouteRequest, numRoutes,
+	maxSharedEdges int) ([]*DiverseRoute, error) {
+
+	if numRoutes < 1 {
+		return nil, fmt.Errorf("invalid number of routes: %d",
+			numRoutes)
+	}
+
+	if maxSharedEdges < 0 {
+		return nil, fmt.Errorf("invalid number of shared edges: %d",
+			maxSharedEdges)
+	}
+
+	log.Debugf("Searching for %d diverse paths to %v, sending %v",
+		numRoutes, req.Target, req.Amount)
+
+	bandwidthHints, err := newBandwidthManager(
+		r.cfg.RoutingGraph, r.cfg.SelfNode, r.cfg.GetLink,
+		fn.None[tlv.Blob](), r.cfg.TrafficShaper,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.TimePreference < -1 || req.TimePreference > 1 {
+		return nil, errors.New("time preference out of range")
+	}
+
+	// Pairs that are blocked are excluded from path finding by reporting
+	// a zero success probability for them.
+	blocked := make(map[DirectedNodePair]struct{})
+	restrictions := *req.Restrictions
+	restrictions.ProbabilitySource = func(from, to route.Vertex,
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
+
+		if _, ok := blocked[NewDirectedNodePair(from, to)]; ok {
+			return 0
+		}
+
+		return req.Restrictions.ProbabilitySource(
+			from, to, amt, capacity,
+		)
+	}
+
+	var (
+		routes   []*DiverseRoute
+		attempts = numRoutes * maxDiverseRouteAttempts
+	)
+	for len(routes) < numRoutes && attempts > 0 {
+		attempts--
+
+		rt, probability, err := r.findRoute(
+			req, &restrictions, bandwidthHints,
+			uint32(currentHeight),
+		)
+		switch {
+		// Return the routes found so far, if any.
+		case errors.Is(err, errNoPathFound) && len(routes) > 0:
+			return routes, nil
+
+		case err != nil:
+			return nil, err
+		}
+
+		// If the route shares too many pairs with one of the routes
+		// found before, we block one of the shared pairs and search
+		// again.
+		shared := sharedPairs(rt, routes, maxSharedEdges)
+		if len(shared) > 0 {
+			pair := pickPairToBlock(shared, req.Source, req.Target)
+			blocked[pair] = struct{}{}
+
+			log.Tracef("Route shares more than %d pairs with "+
+				"previous routes, blocking pair %v",
+				maxSharedEdges, pair)
+
+			continue
+		}
+
+		routes = append(routes, &DiverseRoute{
+			Route:       rt,
+			Probability: probability,
+			Costs:       RouteCostBreakdown(rt),
+		})
+	}
+
+	return routes, nil
+}
+
+// routePairs returns the node pairs that the route traverses.
+func routePairs(rt *route.Route) []DirectedNodePair {
+	pairs := make([]DirectedNodePair, 0, len(rt.Hops))
+
+	from := rt.SourcePubKey
+	for _, hop := range rt.Hops {
+		pair := NewDirectedNodePair(from, hop.PubKeyBytes)
+		pairs = append(pairs, pair)
+
+		from = hop.PubKeyBytes
+	}
+
+	return pairs
+}
+
+// sharedPairs returns the pairs that the route shares with the first of the
+// given routes with which it shares more than maxShared pairs. If there is no
+// such route, nil is returned.
+func sharedPairs(rt *route.Route, routes []*DiverseRoute,
+	maxShared int) []DirectedNodePair {
+
+	pairs := fn.NewSet(routePairs(rt)...)
+	for _, other := range routes {
+		var shared []DirectedNodePair
+		for _, pair := range routePairs(other.Route) {
+			if pairs.Contains(pair) {
+				shared = append(shared, pair)
+			}
+		}
+
+		if len(shared) > maxShared {
+			return shared
+		}
+	}
+
+	return nil
+}
+
+// pickPairToBlock selects which of the shared pairs to exclude from the next
+// path finding attempt. Pairs that connect to the source or the target are
+// often unavoidable, so we prefer to block a pair in the middle of the route.
+func pickPairToBlock(shared []DirectedNodePair, source,
+	target route.Vertex) DirectedNodePair {
+
+	for _, pair := range shared {
+		if pair.From != source && pair.To != target {
+			return pair
+		}
+	}
+
+	return shared[0]
+}
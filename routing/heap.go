@@ -49,6 +49,20 @@ type nodeWithDist struct {
 	// routingInfoSize is the total size requirement for the payloads field
 	// in the onion packet from this hop towards the final destination.
 	routingInfoSize uint64
+
+	// estimate is a lower bound of the distance from the source to this
+	// node, which is used to direct the search towards the source.
+	estimate int64
+}
+
+// priority returns the value that the node is ordered by in the distance
+// heap, which is its distance plus the estimated remaining distance.
+func (n *nodeWithDist) priority() int64 {
+	if n.dist > infinity-n.estimate {
+		return infinity
+	}
+
+	return n.dist + n.estimate
 }
 
 // distanceHeap is a min-distance heap that's used within our path finding
@@ -83,12 +97,15 @@ func (d *distanceHeap) Len() int { return len(d.nodes) }
 //
 // NOTE: This is part of the heap.Interface implementation.
 func (d *distanceHeap) Less(i, j int) bool {
+	iPriority := d.nodes[i].priority()
+	jPriority := d.nodes[j].priority()
+
 	// If distances are equal, tie break on probability.
-	if d.nodes[i].dist == d.nodes[j].dist {
+	if iPriority == jPriority {
 		return d.nodes[i].probability > d.nodes[j].probability
 	}
 
-	return d.nodes[i].dist < d.nodes[j].dist
+	return iPriority < jPriority
 }
 
 // Swap swaps the nodes at the passed indices in the priority queue.
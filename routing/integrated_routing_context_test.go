@@ -23,7 +23,8 @@ const (
 )
 
 type mockBandwidthHints struct {
-	hints map[uint64]lnwire.MilliSatoshi
+	hints     map[uint64]lnwire.MilliSatoshi
+	peerChans map[route.Vertex][]uint64
 }
 
 func (m *mockBandwidthHints) availableChanBandwidth(channelID uint64,
@@ -41,6 +42,10 @@ func (m *mockBandwidthHints) firstHopCustomBlob() fn.Option[tlv.Blob] {
 	return fn.None[tlv.Blob]()
 }
 
+func (m *mockBandwidthHints) peerChannels() map[route.Vertex][]uint64 {
+	return m.peerChans
+}
+
 // integratedRoutingContext defines the context in which integrated routing
 // tests run.
 type integratedRoutingContext struct {
@@ -0,0 +1,598 @@
+package routing
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/graph"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// DefaultLandmarkRefreshInterval is the default interval at which the
+	// landmark distances are recomputed from scratch if the graph changed
+	// in the meantime.
+	DefaultLandmarkRefreshInterval = time.Hour
+)
+
+// landmarkTable holds, for a set of landmark nodes, a lower bound of the path
+// finding weight of the cheapest path from every node to each landmark. A
+// table is never modified once it is published, updates create a new table.
+//
+// The distances don't need to be exact. For the estimates to be a lower bound,
+// it is sufficient that for every channel from node a to node b, the distance
+// of a to a landmark doesn't exceed the weight of the channel plus the
+// distance of b to the landmark.
+type landmarkTable struct {
+	// landmarks are the selected landmark nodes.
+	landmarks []route.Vertex
+
+	// dists holds the distances to each of the landmarks, in the same
+	// order as landmarks. Nodes that can't reach a landmark are absent
+	// from its map.
+	dists []map[route.Vertex]int64
+}
+
+// LandmarkHeuristicConfig houses the values and methods the LandmarkHeuristic
+// needs to maintain its landmark distances.
+type LandmarkHeuristicConfig struct {
+	// Graph is the channel graph that the distances are computed over.
+	Graph Graph
+
+	// SelfNode is our own node. It is used as the starting point to
+	// select the landmarks.
+	SelfNode route.Vertex
+
+	// NumLandmarks is the number of landmarks to select.
+	NumLandmarks int
+
+	// SubscribeTopology is used to get a subscription for topology changes
+	// on the network.
+	SubscribeTopology func() (*graph.TopologyClient, error)
+
+	// RefreshTicker is the ticker that triggers a full recomputation of
+	// the distances if the graph changed since they were last computed.
+	// In between, gossip updates are only applied incrementally, which
+	// keeps the estimates valid but may leave them less tight.
+	RefreshTicker ticker.Ticker
+}
+
+// LandmarkHeuristic maintains the distances from all nodes of the graph to a
+// small set of landmark nodes. Using the triangle inequality, these distances
+// provide a lower bound of the remaining distance to the source during path
+// finding, which is used to direct the search towards the source instead of
+// exploring the graph in all directions. The distances are cached between
+// payments and updated incrementally as gossip arrives.
+type LandmarkHeuristic struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *LandmarkHeuristicConfig
+
+	// table is the most recently computed landmark table. It is nil until
+	// the distances have been computed for the first time.
+	table atomic.Pointer[landmarkTable]
+
+	// updateMtx ensures that only one update of the table runs at a time.
+	updateMtx sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewLandmarkHeuristic creates a new LandmarkHeuristic from the passed config.
+func NewLandmarkHeuristic(cfg *LandmarkHeuristicConfig) (*LandmarkHeuristic,
+	error) {
+
+	if cfg.Graph == nil {
+		return nil, errors.New("graph must be set")
+	}
+
+	if cfg.NumLandmarks < 1 {
+		return nil, fmt.Errorf("invalid number of landmarks: %d",
+			cfg.NumLandmarks)
+	}
+
+	if cfg.RefreshTicker == nil {
+		cfg.RefreshTicker = ticker.New(DefaultLandmarkRefreshInterval)
+	}
+
+	return &LandmarkHeuristic{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}, nil
+}
+
+// Start computes the initial landmark distances in the background and
+// subscribes to topology changes to keep them up to date.
+func (h *LandmarkHeuristic) Start() error {
+	var startErr error
+	h.started.Do(func() {
+		log.Info("Landmark heuristic starting")
+
+		var topology *graph.TopologyClient
+		if h.cfg.SubscribeTopology != nil {
+			var err error
+			topology, err = h.cfg.SubscribeTopology()
+			if err != nil {
+				startErr = fmt.Errorf("unable to subscribe to "+
+					"topology changes: %w", err)
+
+				return
+			}
+		}
+
+		h.cfg.RefreshTicker.Resume()
+
+		h.wg.Add(1)
+		go h.landmarkLoop(topology)
+	})
+
+	return startErr
+}
+
+// Stop stops the LandmarkHeuristic.
+func (h *LandmarkHeuristic) Stop() error {
+	h.stopped.Do(func() {
+		log.Info("Landmark heuristic shutting down...")
+		defer log.Debug("Landmark heuristic shutdown complete")
+
+		close(h.quit)
+		h.wg.Wait()
+
+		h.cfg.RefreshTicker.Stop()
+	})
+
+	return nil
+}
+
+// landmarkLoop computes the distances once at startup, applies gossip
+// updates as they arrive and recomputes the distances from scratch on every
+// tick of the refresh ticker if the graph changed.
+//
+// NOTE: This MUST be run as a goroutine.
+func (h *LandmarkHeuristic) landmarkLoop(topology *graph.TopologyClient) {
+	defer h.wg.Done()
+
+	var topologyChanges <-chan *graph.TopologyChange
+	if topology != nil {
+		defer topology.Cancel()
+		topologyChanges = topology.TopologyChanges
+	}
+
+	refresh := func() {
+		if err := h.Refresh(); err != nil {
+			log.Errorf("Unable to compute landmark distances: %v",
+				err)
+		}
+	}
+
+	refresh()
+
+	var dirty bool
+	for {
+		select {
+		case change, ok := <-topologyChanges:
+			if !ok {
+				topologyChanges = nil
+				continue
+			}
+
+			// Closed channels can only increase distances, which
+			// keeps the current estimates valid. They are only
+			// picked up by the next refresh.
+			dirty = true
+
+			var nodes []route.Vertex
+			for _, update := range change.ChannelEdgeUpdates {
+				nodes = append(
+					nodes,
+					route.NewVertex(update.AdvertisingNode),
+					route.NewVertex(update.ConnectingNode),
+				)
+			}
+
+			if len(nodes) == 0 {
+				continue
+			}
+
+			if err := h.applyUpdates(nodes); err != nil {
+				log.Errorf("Unable to update landmark "+
+					"distances: %v", err)
+			}
+
+		case <-h.cfg.RefreshTicker.Ticks():
+			if !dirty {
+				continue
+			}
+
+			dirty = false
+			refresh()
+
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// Refresh selects the landmarks and recomputes the distances to them over the
+// current channel graph.
+func (h *LandmarkHeuristic) Refresh() error {
+	h.updateMtx.Lock()
+	defer h.updateMtx.Unlock()
+
+	start := time.Now()
+
+	// The distances to our own node are used to select the first
+	// landmark, which is the node that is furthest away from us.
+	// Subsequent landmarks are the nodes that are furthest away from all
+	// landmarks selected so far.
+	selfDists, err := relaxDistances(
+		h.cfg.Graph, map[route.Vertex]int64{h.cfg.SelfNode: 0},
+		[]route.Vertex{h.cfg.SelfNode},
+	)
+	if err != nil {
+		return err
+	}
+	selfDists[h.cfg.SelfNode] = 0
+
+	table := &landmarkTable{}
+	selected := []map[route.Vertex]int64{selfDists}
+	for len(table.landmarks) < h.cfg.NumLandmarks {
+		landmark, ok := furthestNode(selected)
+		if !ok {
+			break
+		}
+
+		dists, err := relaxDistances(
+			h.cfg.Graph, map[route.Vertex]int64{landmark: 0},
+			[]route.Vertex{landmark},
+		)
+		if err != nil {
+			return err
+		}
+		dists[landmark] = 0
+
+		table.landmarks = append(table.landmarks, landmark)
+		table.dists = append(table.dists, dists)
+		selected = append(selected, dists)
+	}
+
+	log.Debugf("Computed distances to %d landmarks in %v",
+		len(table.landmarks), time.Since(start))
+
+	h.table.Store(table)
+
+	return nil
+}
+
+// applyUpdates incorporates channel updates that involve the given nodes
+// into the landmark distances. Only decreasing distances need to be
+// propagated, as an increase of the weight of a channel keeps all distances
+// valid lower bounds.
+func (h *LandmarkHeuristic) applyUpdates(nodes []route.Vertex) error {
+	h.updateMtx.Lock()
+	defer h.updateMtx.Unlock()
+
+	table := h.table.Load()
+	if table == nil {
+		return nil
+	}
+
+	var newTable *landmarkTable
+	for i, dists := range table.dists {
+		updates, err := relaxDistances(h.cfg.Graph, dists, nodes)
+		if err != nil {
+			return err
+		}
+
+		if len(updates) == 0 {
+			continue
+		}
+
+		// Only copy the table and the distances that actually
+		// changed, as the published table may be in use by path
+		// finding.
+		if newTable == nil {
+			newTable = &landmarkTable{
+				landmarks: table.landmarks,
+				dists: append(
+					[]map[route.Vertex]int64(nil),
+					table.dists...,
+				),
+			}
+		}
+
+		newDists := maps.Clone(dists)
+		maps.Copy(newDists, updates)
+		newTable.dists[i] = newDists
+
+		log.Tracef("Lowered distance to landmark %v of %d nodes",
+			table.landmarks[i], len(updates))
+	}
+
+	if newTable != nil {
+		h.table.Store(newTable)
+	}
+
+	return nil
+}
+
+// estimator returns a distance estimator for a path finding run from the
+// given source. Any nodes that additional edges start from must be passed in
+// as hintNodes, as these edges aren't part of the graph the landmark
+// distances were computed over. If the landmark distances aren't available
+// yet, nil is returned, which estimates all distances as zero.
+func (h *LandmarkHeuristic) estimator(g Graph, source route.Vertex,
+	hintNodes []route.Vertex) (*distanceEstimator, error) {
+
+	if h == nil {
+		return nil, nil
+	}
+
+	table := h.table.Load()
+	if table == nil {
+		return nil, nil
+	}
+
+	e := &distanceEstimator{
+		table:       table,
+		source:      source,
+		sourceDists: make([]int64, len(table.landmarks)),
+		floor:       infinity,
+	}
+
+	// The source doesn't charge a fee for the first hop, so the distance
+	// of the source to a landmark is that of the cheapest of its peers.
+	for i := range e.sourceDists {
+		e.sourceDists[i] = infinity
+		if table.landmarks[i] == source {
+			e.sourceDists[i] = 0
+		}
+	}
+
+	err := g.ForEachNodeChannel(source,
+		func(channel *channeldb.DirectedChannel) error {
+			for i, dists := range table.dists {
+				dist, ok := dists[channel.OtherNode]
+				if ok && dist < e.sourceDists[i] {
+					e.sourceDists[i] = dist
+				}
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// A path that uses an additional edge is at least as expensive as the
+	// path from the source to the node that the edge starts from. The
+	// estimate of any node therefore can't exceed the lowest estimate of
+	// those nodes.
+	for _, node := range hintNodes {
+		e.floor = min(e.floor, e.estimate(node))
+	}
+
+	return e, nil
+}
+
+// distanceEstimator estimates a lower bound of the remaining distance from
+// the source to a node during a single path finding run.
+type distanceEstimator struct {
+	// table is the landmark table that the estimates are derived from.
+	table *landmarkTable
+
+	// source is the source of the path finding run.
+	source route.Vertex
+
+	// sourceDists are the distances of the source to each of the
+	// landmarks, excluding the fee of the source itself.
+	sourceDists []int64
+
+	// floor is the maximum estimate for any node, which accounts for
+	// shortcuts through additional edges.
+	floor int64
+}
+
+// estimate returns a lower bound of the path finding weight of the path from
+// the source to the given node, excluding the fee charged by the node itself.
+// A nil estimator always returns zero.
+func (e *distanceEstimator) estimate(node route.Vertex) int64 {
+	if e == nil || node == e.source {
+		return 0
+	}
+
+	// By the triangle inequality, the distance from the source to the node
+	// is at least the difference of their distances to a landmark.
+	var estimate int64
+	for i, dists := range e.table.dists {
+		if e.sourceDists[i] == infinity {
+			continue
+		}
+
+		// If the node can't reach the landmark, it provides no bound.
+		dist, ok := dists[node]
+		if !ok {
+			continue
+		}
+
+		estimate = max(estimate, e.sourceDists[i]-dist)
+	}
+
+	return min(estimate, e.floor)
+}
+
+// channelWeightBound returns a lower bound of the path finding weight of the
+// given channel in the direction towards the node whose channel it is. The
+// node that forwards over the channel charges at least its base fee, unless
+// the receiving node offers an inbound discount. If the channel can't be used
+// in this direction, false is returned.
+func channelWeightBound(channel *channeldb.DirectedChannel) (int64, bool) {
+	if channel.InPolicy == nil {
+		return 0, false
+	}
+
+	if channel.InboundFee.BaseFee < 0 || channel.InboundFee.FeeRate < 0 {
+		return 0, true
+	}
+
+	return int64(channel.InPolicy.FeeBaseMSat), true
+}
+
+// relaxDistances lowers the distances to a landmark where a channel that
+// points into one of the given nodes provides a cheaper path, and propagates
+// the lowered distances to the rest of the graph. The passed distances aren't
+// modified, instead all distances that were lowered are returned.
+func relaxDistances(g Graph, dists map[route.Vertex]int64,
+	nodes []route.Vertex) (map[route.Vertex]int64, error) {
+
+	updates := make(map[route.Vertex]int64)
+	distance := func(node route.Vertex) (int64, bool) {
+		if dist, ok := updates[node]; ok {
+			return dist, true
+		}
+
+		dist, ok := dists[node]
+
+		return dist, ok
+	}
+
+	var queue vertexDistHeap
+	for _, node := range nodes {
+		if dist, ok := distance(node); ok {
+			heap.Push(&queue, vertexDist{node: node, dist: dist})
+		}
+	}
+
+	for queue.Len() > 0 {
+		current := heap.Pop(&queue).(vertexDist)
+
+		// Skip entries that were superseded by a lower distance.
+		if dist, _ := distance(current.node); dist < current.dist {
+			continue
+		}
+
+		err := g.ForEachNodeChannel(current.node,
+			func(channel *channeldb.DirectedChannel) error {
+				weight, ok := channelWeightBound(channel)
+				if !ok {
+					return nil
+				}
+
+				from := channel.OtherNode
+				newDist := current.dist + weight
+
+				dist, ok := distance(from)
+				if ok && dist <= newDist {
+					return nil
+				}
+
+				updates[from] = newDist
+				heap.Push(&queue, vertexDist{
+					node: from,
+					dist: newDist,
+				})
+
+				return nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return updates, nil
+}
+
+// furthestNode returns the node whose smallest distance to any of the given
+// sets of distances is the largest. Only nodes that appear in all sets are
+// considered. Ties are broken by the public key to keep the selection
+// deterministic.
+func furthestNode(dists []map[route.Vertex]int64) (route.Vertex, bool) {
+	var (
+		furthest route.Vertex
+		maxDist  int64 = -1
+	)
+
+	for node := range dists[0] {
+		minDist := int64(infinity)
+		for _, d := range dists {
+			dist, ok := d[node]
+			if !ok {
+				minDist = -1
+				break
+			}
+
+			minDist = min(minDist, dist)
+		}
+
+		// Nodes at distance zero are either already selected or
+		// provide no useful bound.
+		if minDist <= 0 {
+			continue
+		}
+
+		if minDist > maxDist || (minDist == maxDist &&
+			bytes.Compare(node[:], furthest[:]) < 0) {
+
+			furthest = node
+			maxDist = minDist
+		}
+	}
+
+	return furthest, maxDist > 0
+}
+
+// vertexDist couples a node with its distance to a landmark.
+type vertexDist struct {
+	node route.Vertex
+	dist int64
+}
+
+// vertexDistHeap is a min-distance heap of nodes that is used to compute the
+// distances to a landmark.
+type vertexDistHeap []vertexDist
+
+// Len returns the number of nodes in the heap.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h vertexDistHeap) Len() int { return len(h) }
+
+// Less returns whether the node at index i is closer than the one at index j.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h vertexDistHeap) Less(i, j int) bool { return h[i].dist < h[j].dist }
+
+// Swap swaps the nodes at the passed indices in the heap.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h vertexDistHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+// Push pushes the passed node onto the heap.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h *vertexDistHeap) Push(x interface{}) {
+	*h = append(*h, x.(vertexDist))
+}
+
+// Pop removes the closest node from the heap and returns it.
+//
+// NOTE: This is part of the heap.Interface implementation.
+func (h *vertexDistHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+
+	return x
+}
@@ -0,0 +1,137 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLandmarks creates a landmark heuristic over the given test graph and
+// computes its initial distances.
+func newTestLandmarks(t *testing.T, graph *testGraphInstance,
+	numLandmarks int) *LandmarkHeuristic {
+
+	t.Helper()
+
+	sourceNode, err := graph.graph.SourceNode()
+	require.NoError(t, err)
+
+	landmarks, err := NewLandmarkHeuristic(&LandmarkHeuristicConfig{
+		Graph:        newMockGraphSessionChanDB(graph.graph),
+		SelfNode:     sourceNode.PubKeyBytes,
+		NumLandmarks: numLandmarks,
+	})
+	require.NoError(t, err)
+	require.NoError(t, landmarks.Refresh())
+
+	return landmarks
+}
+
+// TestLandmarkPathFinding asserts that path finding with landmark estimates
+// finds the same paths as path finding without them.
+func TestLandmarkPathFinding(t *testing.T) {
+	t.Parallel()
+
+	graph, err := parseTestGraph(t, true, basicGraphFilePath)
+	require.NoError(t, err)
+
+	sourceNode, err := graph.graph.SourceNode()
+	require.NoError(t, err)
+	source := route.Vertex(sourceNode.PubKeyBytes)
+
+	landmarks := newTestLandmarks(t, graph, 3)
+	require.NotEmpty(t, landmarks.table.Load().landmarks)
+
+	graphSess := newMockGraphSessionChanDB(graph.graph)
+	findPathWith := func(landmarks *LandmarkHeuristic,
+		target route.Vertex) ([]uint64, error) {
+
+		path, _, err := findPath(
+			&graphParams{
+				bandwidthHints: &mockBandwidthHints{},
+				graph:          graphSess,
+				landmarks:      landmarks,
+			},
+			noRestrictions, testPathFindingConfig, source, source,
+			target, lnwire.NewMSatFromSatoshis(100_000), 0, 0,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		chanIDs := make([]uint64, 0, len(path))
+		for _, edge := range path {
+			chanIDs = append(chanIDs, edge.policy.ChannelID)
+		}
+
+		return chanIDs, nil
+	}
+
+	for alias, target := range graph.aliasMap {
+		if target == source {
+			continue
+		}
+
+		expected, expectedErr := findPathWith(nil, target)
+		path, err := findPathWith(landmarks, target)
+
+		require.Equal(t, expectedErr, err, alias)
+		require.Equal(t, expected, path, alias)
+	}
+}
+
+// TestLandmarkDistanceUpdates asserts that lowered channel fees are
+// propagated to the landmark distances.
+func TestLandmarkDistanceUpdates(t *testing.T) {
+	t.Parallel()
+
+	graph, err := parseTestGraph(t, true, basicGraphFilePath)
+	require.NoError(t, err)
+
+	landmarks := newTestLandmarks(t, graph, 3)
+	table := landmarks.table.Load()
+
+	// Raising a fee keeps the distances valid, so no new table should be
+	// published.
+	phamToSophon := uint64(99999)
+	_, e1, e2, err := graph.graph.FetchChannelEdgesByID(phamToSophon)
+	require.NoError(t, err)
+
+	e1.FeeBaseMSat *= 2
+	e2.FeeBaseMSat *= 2
+	require.NoError(t, graph.graph.UpdateEdgePolicy(e1))
+	require.NoError(t, graph.graph.UpdateEdgePolicy(e2))
+
+	nodes := []route.Vertex{
+		graph.aliasMap["phamnuwen"], graph.aliasMap["sophon"],
+	}
+	require.NoError(t, landmarks.applyUpdates(nodes))
+	require.Same(t, table, landmarks.table.Load())
+
+	// Lowering the fee makes the channel attractive, which should lower
+	// the distances to exactly those that a full recomputation yields.
+	e1.FeeBaseMSat = 1
+	e2.FeeBaseMSat = 1
+	require.NoError(t, graph.graph.UpdateEdgePolicy(e1))
+	require.NoError(t, graph.graph.UpdateEdgePolicy(e2))
+
+	require.NoError(t, landmarks.applyUpdates(nodes))
+
+	updated := landmarks.table.Load()
+	require.NotSame(t, table, updated)
+	require.Equal(t, table.landmarks, updated.landmarks)
+
+	for i, landmark := range updated.landmarks {
+		expected, err := relaxDistances(
+			landmarks.cfg.Graph,
+			map[route.Vertex]int64{landmark: 0},
+			[]route.Vertex{landmark},
+		)
+		require.NoError(t, err)
+		expected[landmark] = 0
+
+		require.Equal(t, expected, updated.dists[i])
+	}
+}
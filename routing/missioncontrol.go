@@ -48,6 +48,19 @@ const (
 	// restrictive channel level tracking scheme here.
 	minSecondChanceInterval = time.Minute
 
+	// blindedPathFailurePenalty is the factor that the success probability
+	// of a blinded path is multiplied with for every recent failure that
+	// occurred inside the path.
+	blindedPathFailurePenalty = 0.5
+
+	// blindedPathHalfLife is the time after which the effect of a failure
+	// inside a blinded path is halved.
+	blindedPathHalfLife = time.Hour
+
+	// blindedPathMaxAge is the time after the most recent failure of a
+	// blinded path at which its history is dropped.
+	blindedPathMaxAge = 24 * time.Hour
+
 	// DefaultMaxMcHistory is the default maximum history size.
 	DefaultMaxMcHistory = 1000
 
@@ -129,6 +142,10 @@ type MissionControl struct {
 	// mission control state is updated.
 	onConfigUpdate fn.Option[func(cfg *MissionControlConfig)]
 
+	// onPairFailure is a function that is called whenever a failure is
+	// recorded for a node pair.
+	onPairFailure fn.Option[func(from, to route.Vertex)]
+
 	log btclog.Logger
 
 	mu sync.Mutex
@@ -188,6 +205,10 @@ type MissionControlConfig struct {
 	// mission control state is updated.
 	OnConfigUpdate fn.Option[func(cfg *MissionControlConfig)]
 
+	// OnPairFailure is a function that is called whenever a failure is
+	// recorded for a node pair.
+	OnPairFailure fn.Option[func(from, to route.Vertex)]
+
 	// MaxMcHistory defines the maximum number of payment results that are
 	// held on disk.
 	MaxMcHistory int
@@ -389,6 +410,7 @@ func (m *MissionController) initMissionControl(namespace string) (
 			fmt.Sprintf("[%s]:", namespace), log,
 		),
 		onConfigUpdate: cfg.OnConfigUpdate,
+		onPairFailure:  cfg.OnPairFailure,
 	}
 
 	m.mc[namespace] = mc
@@ -515,12 +537,19 @@ func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
 	now := m.cfg.clock.Now()
 	results, _ := m.state.getLastPairResult(fromNode)
 
+	// If the connection leads into a blinded path that failed before, the
+	// hops inside the path are hidden from us. We therefore degrade the
+	// probability of entering the path as a whole.
+	blindedPenalty := m.state.blindedPathPenalty(now, fromNode, toNode)
+
 	// Use a distinct probability estimation function for local channels.
 	if fromNode == m.cfg.selfNode {
-		return m.estimator.LocalPairProbability(now, results, toNode)
+		return blindedPenalty * m.estimator.LocalPairProbability(
+			now, results, toNode,
+		)
 	}
 
-	return m.estimator.PairProbability(
+	return blindedPenalty * m.estimator.PairProbability(
 		now, results, toNode, amt, capacity,
 	)
 }
@@ -695,6 +724,12 @@ func (m *MissionControl) applyPaymentResult(
 			m.log.Debugf("Reporting pair failure to Mission "+
 				"Control: pair=%v, amt=%v",
 				pair, pairResult.amt)
+
+			m.onPairFailure.WhenSome(
+				func(f func(from, to route.Vertex)) {
+					f(pair.From, pair.To)
+				},
+			)
 		}
 
 		m.state.setLastPairResult(
@@ -702,6 +737,18 @@ func (m *MissionControl) applyPaymentResult(
 		)
 	}
 
+	if i.blindedPathResult != nil {
+		m.log.Debugf("Reporting blinded path result to Mission "+
+			"Control: intro=%v, path=%v, success=%v",
+			i.blindedPathResult.key.introNode,
+			i.blindedPathResult.key.pathID,
+			i.blindedPathResult.success)
+
+		m.state.setBlindedPathResult(
+			result.timeReply, i.blindedPathResult,
+		)
+	}
+
 	return i.finalFailureReason
 }
 
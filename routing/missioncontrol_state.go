@@ -1,6 +1,7 @@
 package routing
 
 import (
+	"math"
 	"time"
 
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -20,6 +21,10 @@ type missionControlState struct {
 	// a directed node pair.
 	lastSecondChance map[DirectedNodePair]time.Time
 
+	// blindedPaths tracks the failures of blinded paths, whose hops can't
+	// be penalized individually.
+	blindedPaths map[blindedPathKey]*blindedPathHistory
+
 	// minFailureRelaxInterval is the minimum time that must have passed
 	// since the previously recorded failure before the failure amount may
 	// be raised.
@@ -34,6 +39,9 @@ func newMissionControlState(
 		lastPairResult:          make(map[route.Vertex]NodeResults),
 		lastSecondChance:        make(map[DirectedNodePair]time.Time),
 		minFailureRelaxInterval: minFailureRelaxInterval,
+		blindedPaths: make(
+			map[blindedPathKey]*blindedPathHistory,
+		),
 	}
 }
 
@@ -51,6 +59,7 @@ func (m *missionControlState) getLastPairResult(node route.Vertex) (NodeResults,
 func (m *missionControlState) resetHistory() {
 	m.lastPairResult = make(map[route.Vertex]NodeResults)
 	m.lastSecondChance = make(map[DirectedNodePair]time.Time)
+	m.blindedPaths = make(map[blindedPathKey]*blindedPathHistory)
 }
 
 // setLastPairResult stores a result for a node pair.
@@ -154,6 +163,75 @@ func (m *missionControlState) setAllFail(node route.Vertex,
 	}
 }
 
+// blindedPathHistory is the aggregated failure history of a blinded path.
+type blindedPathHistory struct {
+	// failures is the number of failures since the last success.
+	failures int
+
+	// lastFail is the time of the most recent failure.
+	lastFail time.Time
+}
+
+// penalty returns the factor that the success probability of the blinded path
+// is multiplied with. Every failure halves the probability, and the effect of
+// the failures decays over time.
+func (b *blindedPathHistory) penalty(now time.Time) float64 {
+	elapsed := now.Sub(b.lastFail)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+
+	decay := math.Exp2(-float64(elapsed) / float64(blindedPathHalfLife))
+	failures := float64(b.failures) * decay
+
+	return math.Pow(blindedPathFailurePenalty, failures)
+}
+
+// setBlindedPathResult records the outcome of a payment attempt through a
+// blinded path. A success clears the failure history of the path.
+func (m *missionControlState) setBlindedPathResult(timestamp time.Time,
+	result *blindedPathResult) {
+
+	if result.success {
+		delete(m.blindedPaths, result.key)
+		return
+	}
+
+	// Blinded paths are specific to an invoice, so we drop the history of
+	// paths that haven't failed in a long time to keep the state bounded.
+	for key, history := range m.blindedPaths {
+		if timestamp.Sub(history.lastFail) > blindedPathMaxAge {
+			delete(m.blindedPaths, key)
+		}
+	}
+
+	history, ok := m.blindedPaths[result.key]
+	if !ok {
+		history = &blindedPathHistory{}
+		m.blindedPaths[result.key] = history
+	}
+
+	history.failures++
+	history.lastFail = timestamp
+}
+
+// blindedPathPenalty returns the factor that the success probability of the
+// connection from fromNode to toNode is multiplied with, if the connection is
+// the entry into a blinded path that failed before.
+func (m *missionControlState) blindedPathPenalty(now time.Time, fromNode,
+	toNode route.Vertex) float64 {
+
+	history, ok := m.blindedPaths[blindedPathKey{
+		introNode: fromNode,
+		pathID:    toNode,
+	}]
+	if !ok {
+		return 1
+	}
+
+	return history.penalty(now)
+}
+
 // requestSecondChance checks whether the node fromNode can have a second chance
 // at providing a channel update for its channel with toNode.
 func (m *missionControlState) requestSecondChance(timestamp time.Time,
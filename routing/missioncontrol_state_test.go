@@ -91,3 +91,61 @@ func TestMissionControlStateSetLastPairResult(t *testing.T) {
 	}
 	require.Equal(t, expected, result[to])
 }
+
+// TestMissionControlStateBlindedPathResult tests that failures of a blinded
+// path degrade the probability of entering it, and that the penalty decays
+// over time and is cleared by a success.
+func TestMissionControlStateBlindedPathResult(t *testing.T) {
+	state := newMissionControlState(time.Minute)
+
+	key := blindedPathKey{
+		introNode: route.Vertex{1},
+		pathID:    route.Vertex{2},
+	}
+	penalty := func(now time.Time) float64 {
+		return state.blindedPathPenalty(now, key.introNode, key.pathID)
+	}
+
+	// Without any history, the path isn't penalized.
+	require.Equal(t, 1.0, penalty(testTime))
+
+	// Every failure halves the probability.
+	failure := &blindedPathResult{key: key}
+	state.setBlindedPathResult(testTime, failure)
+	require.InDelta(t, 0.5, penalty(testTime), 1e-9)
+
+	state.setBlindedPathResult(testTime, failure)
+	require.InDelta(t, 0.25, penalty(testTime), 1e-9)
+
+	// Other connections of the introduction node aren't affected.
+	require.Equal(t, 1.0, state.blindedPathPenalty(
+		testTime, key.introNode, route.Vertex{3},
+	))
+
+	// After one half life, the effect of the failures is halved.
+	require.InDelta(
+		t, 0.5, penalty(testTime.Add(blindedPathHalfLife)), 1e-9,
+	)
+
+	// A success clears the history.
+	state.setBlindedPathResult(testTime, &blindedPathResult{
+		key:     key,
+		success: true,
+	})
+	require.Equal(t, 1.0, penalty(testTime))
+
+	// Histories that haven't seen a failure in a long time are dropped
+	// when a new failure is recorded.
+	state.setBlindedPathResult(testTime, failure)
+
+	otherKey := blindedPathKey{
+		introNode: route.Vertex{1},
+		pathID:    route.Vertex{3},
+	}
+	state.setBlindedPathResult(
+		testTime.Add(blindedPathMaxAge+time.Second),
+		&blindedPathResult{key: otherKey},
+	)
+	require.NotContains(t, state.blindedPaths, key)
+	require.Contains(t, state.blindedPaths, otherKey)
+}
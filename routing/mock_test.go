@@ -124,6 +124,13 @@ func (m *mockPaymentSessionSourceOld) NewPaymentSessionEmpty() PaymentSession {
 	return &mockPaymentSessionOld{}
 }
 
+func (m *mockPaymentSessionSourceOld) EstimatePayment(_ *LightningPayment,
+	_ fn.Option[tlv.Blob], _ fn.Option[TlvTrafficShaper],
+	_ uint32) (*PaymentEstimate, error) {
+
+	return nil, errors.New("not implemented")
+}
+
 type mockMissionControlOld struct {
 	MissionController
 }
@@ -653,6 +660,15 @@ func (m *mockPaymentSessionSource) NewPaymentSessionEmpty() PaymentSession {
 	return args.Get(0).(PaymentSession)
 }
 
+func (m *mockPaymentSessionSource) EstimatePayment(payment *LightningPayment,
+	firstHopBlob fn.Option[tlv.Blob],
+	tlvShaper fn.Option[TlvTrafficShaper],
+	height uint32) (*PaymentEstimate, error) {
+
+	args := m.Called(payment, firstHopBlob, tlvShaper, height)
+	return args.Get(0).(*PaymentEstimate), args.Error(1)
+}
+
 type mockMissionControl struct {
 	mock.Mock
 }
@@ -0,0 +1,81 @@
+package routing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"golang.org/x/crypto/chacha20"
+)
+
+// onionSharedSecrets derives the ephemeral public key and the shared secret
+// for every hop of an onion as specified in BOLT 04. The ephemeral key of
+// each hop is blinded by the hash of the previous hop's ephemeral key and
+// shared secret.
+func onionSharedSecrets(sessionKey *btcec.PrivateKey,
+	pubKeys []*btcec.PublicKey) ([]*btcec.PublicKey, [][]byte, error) {
+
+	ephemeralKeys := make([]*btcec.PublicKey, len(pubKeys))
+	sharedSecrets := make([][]byte, len(pubKeys))
+
+	ephemeralPriv := sessionKey
+	for i, pubKey := range pubKeys {
+		ecdh := &keychain.PrivKeyECDH{PrivKey: ephemeralPriv}
+		secret, err := ecdh.ECDH(pubKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ephemeralPub := ephemeralPriv.PubKey()
+		ephemeralKeys[i] = ephemeralPub
+		sharedSecrets[i] = secret[:]
+
+		blindingFactor := sha256.Sum256(append(
+			ephemeralPub.SerializeCompressed(), secret[:]...,
+		))
+
+		var blinding btcec.ModNScalar
+		blinding.SetBytes(&blindingFactor)
+
+		nextKey := ephemeralPriv.Key
+		nextKey.Mul(&blinding)
+
+		keyBytes := nextKey.Bytes()
+		ephemeralPriv, _ = btcec.PrivKeyFromBytes(keyBytes[:])
+	}
+
+	return ephemeralKeys, sharedSecrets, nil
+}
+
+// onionKey derives a key of the given type from the shared secret.
+func onionKey(keyType string, secret []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(keyType))
+	mac.Write(secret)
+
+	return mac.Sum(nil)
+}
+
+// onionCipherStream generates a ChaCha20 key stream of the given length using
+// an all-zero nonce.
+func onionCipherStream(key []byte, length int) []byte {
+	var nonce [chacha20.NonceSize]byte
+	cipher, err := chacha20.NewUnauthenticatedCipher(key, nonce[:])
+	if err != nil {
+		// This can only happen if the key or nonce size is invalid,
+		// which is never the case here.
+		panic(err)
+	}
+
+	stream := make([]byte, length)
+	cipher.XORKeyStream(stream, stream)
+
+	return stream
+}
+
+// xorBytes xors the bytes of b into dst.
+func xorBytes(dst, b []byte) {
+	for i := range dst {
+		dst[i] ^= b[i]
+	}
+}
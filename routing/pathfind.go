@@ -19,6 +19,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
 )
 
 const (
@@ -410,6 +411,10 @@ type graphParams struct {
 	// particular, it should be set to the current available sending
 	// bandwidth for active local channels, and 0 for inactive channels.
 	bandwidthHints bandwidthHints
+
+	// landmarks optionally provides lower bounds of the remaining distance
+	// to the source, which speed up the search.
+	landmarks *LandmarkHeuristic
 }
 
 // RestrictParams wraps the set of restrictions passed to findPath that the
@@ -437,10 +442,18 @@ type RestrictParams struct {
 	// all cltv expiry heights with the required final cltv delta.
 	CltvLimit uint32
 
+	// CltvBudget optionally limits the time lock delta that individual
+	// hops of the route may charge.
+	CltvBudget *CltvBudget
+
 	// DestCustomRecords contains the custom records to drop off at the
 	// final hop, if any.
 	DestCustomRecords record.CustomSet
 
+	// HopCustomRecords contains the custom records to drop off at
+	// intermediate hops, keyed by the node they are addressed to.
+	HopCustomRecords map[route.Vertex]record.CustomSet
+
 	// DestFeatures is a feature vector describing what the final hop
 	// supports. If none are provided, pathfinding will try to inspect any
 	// features on the node announcement instead.
@@ -656,6 +669,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	distance := make(map[route.Vertex]*nodeWithDist, estimatedNodeCount)
 
 	additionalEdgesWithSrc := make(map[route.Vertex][]*edgePolicyWithSource)
+	hintNodes := make([]route.Vertex, 0, len(g.additionalEdges))
 	for vertex, additionalEdges := range g.additionalEdges {
 		// Edges connected to self are always included in the graph,
 		// therefore can be skipped. This prevents us from trying
@@ -664,6 +678,8 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			continue
 		}
 
+		hintNodes = append(hintNodes, vertex)
+
 		// Build reverse lookup to find incoming edges. Needed because
 		// search is taken place from target to source.
 		for _, additionalEdge := range additionalEdges {
@@ -681,6 +697,15 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		}
 	}
 
+	// If landmark distances are available, use them to estimate the
+	// remaining distance to the source of every node that we add to the
+	// heap. As the estimates never exceed the actual distance, the search
+	// still finds the cheapest path.
+	estimator, err := g.landmarks.estimator(g.graph, source, hintNodes)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	// The payload size of the final hop differ from intermediate hops
 	// and depends on whether the destination is blinded or not.
 	lastHopPayloadSize := lastHopPayloadSize(r, finalHtlcExpiry, amt)
@@ -817,6 +842,16 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			timeLockDelta = edge.policy.TimeLockDelta
 		}
 
+		// Check that the hop is within the cltv budget of its class.
+		// The node that forwards to the target is the last hop.
+		if !r.CltvBudget.allows(
+			timeLockDelta, toNodeDist.node == target,
+			edge.blindedPayment != nil,
+		) {
+
+			return
+		}
+
 		incomingCltv := toNodeDist.incomingCltv + int32(timeLockDelta)
 
 		// Check that we are within our CLTV limit.
@@ -920,6 +955,12 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 				uint32(toNodeDist.incomingCltv),
 				edge.policy.ChannelID,
 			)
+
+			// Account for the custom records that are
+			// addressed to this hop, if any.
+			payloadSize += hopCustomRecordsSize(
+				r.HopCustomRecords[fromVertex],
+			)
 		}
 
 		routingInfoSize := toNodeDist.routingInfoSize + payloadSize
@@ -942,6 +983,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			probability:       probability,
 			nextHop:           edge,
 			routingInfoSize:   routingInfoSize,
+			estimate:          estimator.estimate(fromVertex),
 		}
 		distance[fromVertex] = withDist
 
@@ -1457,6 +1499,46 @@ func lastHopPayloadSize(r *RestrictParams, finalHtlcExpiry int32,
 	return finalHop.PayloadSize(0)
 }
 
+// addHopCustomRecords attaches the given custom records to the intermediate
+// hops of the route that they are addressed to. Hops inside a blinded path are
+// skipped, as their payload is dictated by the receiver.
+func addHopCustomRecords(rt *route.Route,
+	records map[route.Vertex]record.CustomSet) {
+
+	if len(records) == 0 || len(rt.Hops) == 0 {
+		return
+	}
+
+	for _, hop := range rt.Hops[:len(rt.Hops)-1] {
+		if hop.EncryptedData != nil {
+			continue
+		}
+
+		if hopRecords, ok := records[hop.PubKeyBytes]; ok {
+			hop.CustomRecords = hopRecords
+		}
+	}
+}
+
+// hopCustomRecordsSize returns the additional size that the given custom
+// records take up in the payload of an intermediate hop.
+func hopCustomRecordsSize(records record.CustomSet) uint64 {
+	if len(records) == 0 {
+		return 0
+	}
+
+	var size uint64
+	for k, v := range records {
+		size += tlv.VarIntSize(k) + tlv.VarIntSize(uint64(len(v))) +
+			uint64(len(v))
+	}
+
+	// The records may increase the size of the varint that encodes the
+	// length of the payload. As payloads are limited in size, it grows by
+	// at most two bytes.
+	return size + 2
+}
+
 // overflowSafeAdd adds two MilliSatoshi values and returns the result. If an
 // overflow could occur, zero is returned instead and the boolean is set to
 // true.
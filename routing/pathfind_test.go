@@ -813,6 +813,9 @@ func TestPathFinding(t *testing.T) {
 	}, {
 		name: "CLTV limit",
 		fn:   runCltvLimit,
+	}, {
+		name: "CLTV budget",
+		fn:   runCltvBudget,
 	}, {
 		name: "probability routing",
 		fn:   runProbabilityRouting,
@@ -2517,6 +2520,103 @@ func testCltvLimit(t *testing.T, useCache bool, limit uint32,
 	}
 }
 
+// runCltvBudget asserts that the per hop class cltv budget is obeyed by the
+// path finding algorithm.
+func runCltvBudget(t *testing.T, useCache bool) {
+	// Set up a test graph with three possible paths to the target. The
+	// path through a is the cheapest, but its last hop has a high time
+	// lock delta (144). The path through b is more expensive with a lower
+	// time lock delta (100). The path through c and d has an intermediate
+	// hop with a time lock delta of 30.
+	testChannels := []*testChannel{
+		symmetricTestChannel("roasbeef", "a", 100000,
+			&testChannelPolicy{}, 1),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: 10000,
+			MinHTLC:     1,
+		}),
+		symmetricTestChannel("roasbeef", "b", 100000,
+			&testChannelPolicy{}, 2),
+		symmetricTestChannel("b", "target", 100000, &testChannelPolicy{
+			Expiry:      100,
+			FeeBaseMsat: 20000,
+			MinHTLC:     1,
+		}),
+		symmetricTestChannel("roasbeef", "c", 100000,
+			&testChannelPolicy{}, 3),
+		symmetricTestChannel("c", "d", 100000, &testChannelPolicy{
+			Expiry:      30,
+			FeeBaseMsat: 10000,
+			MinHTLC:     1,
+		}),
+		symmetricTestChannel("d", "target", 100000, &testChannelPolicy{
+			Expiry:      30,
+			FeeBaseMsat: 10000,
+			MinHTLC:     1,
+		}),
+	}
+
+	testCases := []struct {
+		name            string
+		budget          *CltvBudget
+		expectedChannel uint64
+	}{
+		{
+			name:            "no budget",
+			expectedChannel: 1,
+		},
+		{
+			name: "intermediate hops constrained",
+			budget: &CltvBudget{
+				MaxHopDelta: 20,
+			},
+			expectedChannel: 1,
+		},
+		{
+			name: "all hops constrained",
+			budget: &CltvBudget{
+				MaxHopDelta:     20,
+				MaxLastHopDelta: 100,
+			},
+			expectedChannel: 2,
+		},
+		{
+			name: "no path",
+			budget: &CltvBudget{
+				MaxLastHopDelta: 20,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := newPathFindingTestContext(
+				t, useCache, testChannels, "roasbeef",
+			)
+
+			paymentAmt := lnwire.NewMSatFromSatoshis(100)
+			target := ctx.keyFromAlias("target")
+
+			ctx.restrictParams.CltvBudget = tc.budget
+			path, err := ctx.findPath(target, paymentAmt)
+			if tc.expectedChannel == 0 {
+				require.ErrorIs(t, err, errNoPathFound)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(
+				t, tc.expectedChannel, path[0].policy.ChannelID,
+			)
+		})
+	}
+}
+
 // runProbabilityRouting asserts that path finding not only takes into account
 // fees but also success probability.
 func runProbabilityRouting(t *testing.T, useCache bool) {
@@ -3734,3 +3834,76 @@ func TestFindBlindedPaths(t *testing.T) {
 		"dave",
 	})
 }
+
+// TestAddHopCustomRecords asserts that custom records are only attached to
+// the intermediate hops that they are addressed to.
+func TestAddHopCustomRecords(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = route.Vertex{1}
+		bob   = route.Vertex{2}
+		carol = route.Vertex{3}
+		dave  = route.Vertex{4}
+	)
+
+	rt := &route.Route{
+		Hops: []*route.Hop{
+			{PubKeyBytes: alice},
+			{PubKeyBytes: bob, EncryptedData: []byte{1}},
+			{PubKeyBytes: carol},
+		},
+	}
+
+	records := map[route.Vertex]record.CustomSet{
+		alice: {record.CustomTypeStart: []byte{1}},
+		bob:   {record.CustomTypeStart: []byte{2}},
+		carol: {record.CustomTypeStart: []byte{3}},
+		dave:  {record.CustomTypeStart: []byte{4}},
+	}
+	addHopCustomRecords(rt, records)
+
+	// Only alice is an intermediate hop outside of a blinded path.
+	require.Equal(t, records[alice], rt.Hops[0].CustomRecords)
+	require.Nil(t, rt.Hops[1].CustomRecords)
+	require.Nil(t, rt.Hops[2].CustomRecords)
+}
+
+// TestHopCustomRecordsPayloadSize asserts that custom records for
+// intermediate hops are accounted for in the payload size during path
+// finding.
+func TestHopCustomRecordsPayloadSize(t *testing.T) {
+	t.Parallel()
+
+	testChannels := []*testChannel{
+		symmetricTestChannel("alice", "bob", 100000, &testChannelPolicy{
+			Expiry:  144,
+			MinHTLC: 1,
+			MaxHTLC: 100000000,
+		}),
+		symmetricTestChannel("bob", "carol", 100000, &testChannelPolicy{
+			Expiry:  144,
+			MinHTLC: 1,
+			MaxHTLC: 100000000,
+		}),
+	}
+
+	ctx := newPathFindingTestContext(t, true, testChannels, "alice")
+
+	// A small record for bob fits into the onion.
+	bob := ctx.keyFromAlias("bob")
+	ctx.restrictParams.HopCustomRecords = map[route.Vertex]record.CustomSet{
+		bob: {record.CustomTypeStart: make([]byte, 100)},
+	}
+
+	_, err := ctx.findPath(ctx.keyFromAlias("carol"), 1000)
+	require.NoError(t, err)
+
+	// A record that exceeds the onion size makes the payment impossible.
+	ctx.restrictParams.HopCustomRecords[bob] = record.CustomSet{
+		record.CustomTypeStart: make([]byte, sphinx.MaxPayloadSize),
+	}
+
+	_, err = ctx.findPath(ctx.keyFromAlias("carol"), 1000)
+	require.ErrorIs(t, err, errNoPathFound)
+}
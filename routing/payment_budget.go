@@ -0,0 +1,196 @@
+package routing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// BudgetConstraint identifies one of the constraints of a PaymentBudget.
+type BudgetConstraint uint8
+
+const (
+	// BudgetConstraintFee indicates that no route could be found within
+	// the fee budget derived from PaymentBudget.MaxFeePercent.
+	BudgetConstraintFee BudgetConstraint = iota
+
+	// BudgetConstraintDeadline indicates that the deadline of the payment
+	// has passed.
+	BudgetConstraintDeadline
+
+	// BudgetConstraintAttempts indicates that the maximum number of HTLC
+	// attempts has been made.
+	BudgetConstraintAttempts
+
+	// BudgetConstraintAttemptTimeout indicates that an HTLC attempt didn't
+	// resolve within the per-attempt timeout.
+	BudgetConstraintAttemptTimeout
+)
+
+// String returns a human-readable representation of the constraint.
+func (c BudgetConstraint) String() string {
+	switch c {
+	case BudgetConstraintFee:
+		return "fee"
+
+	case BudgetConstraintDeadline:
+		return "deadline"
+
+	case BudgetConstraintAttempts:
+		return "attempts"
+
+	case BudgetConstraintAttemptTimeout:
+		return "attempt_timeout"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(c))
+	}
+}
+
+// PaymentBudget holds a set of optional per-payment limits that are enforced
+// by the payment lifecycle in addition to the payment's fee limit and attempt
+// timeout. A zero value for any of the fields means that the respective limit
+// isn't enforced.
+//
+// NOTE: The budget isn't persisted, a payment that is resumed after a restart
+// is only bound by its regular limits.
+type PaymentBudget struct {
+	// MaxFeePercent is the maximum fee expressed as a percentage of the
+	// payment amount. The effective fee limit is the lower of this and
+	// the payment's fee limit.
+	MaxFeePercent float64
+
+	// Deadline is the wall clock time after which no new HTLC attempts
+	// are made.
+	Deadline time.Time
+
+	// MaxAttempts is the maximum number of HTLC attempts, including all
+	// shards of a multi-path payment, that are made for the payment.
+	MaxAttempts uint32
+
+	// AttemptTimeout is the maximum time an HTLC attempt may remain in
+	// flight. If an attempt exceeds it, no new HTLC attempts are made, as
+	// it is likely that the payment got stuck.
+	AttemptTimeout time.Duration
+}
+
+// feeLimit returns the fee limit for the given amount that is derived from
+// the budget's fee percentage, if any.
+func (b *PaymentBudget) feeLimit(
+	amt lnwire.MilliSatoshi) fn.Option[lnwire.MilliSatoshi] {
+
+	if b == nil || b.MaxFeePercent <= 0 {
+		return fn.None[lnwire.MilliSatoshi]()
+	}
+
+	limit := float64(amt) * b.MaxFeePercent / 100
+
+	return fn.Some(lnwire.MilliSatoshi(limit))
+}
+
+// BudgetExhaustedError is returned when a payment failed after one of the
+// constraints of its budget was exhausted.
+type BudgetExhaustedError struct {
+	// Constraint is the constraint that was exhausted.
+	Constraint BudgetConstraint
+
+	// Reason is the failure reason the payment was failed with.
+	Reason channeldb.FailureReason
+}
+
+// Error returns a human-readable error string.
+func (e *BudgetExhaustedError) Error() string {
+	return fmt.Sprintf("payment budget exhausted (%v): %v", e.Constraint,
+		e.Reason)
+}
+
+// Unwrap returns the failure reason of the payment, such that callers that
+// only care about the failure reason can use errors.As.
+func (e *BudgetExhaustedError) Unwrap() error {
+	return e.Reason
+}
+
+// checkBudget checks whether any of the constraints of the payment budget has
+// been exhausted. If so, the payment is marked as failed so that no new HTLC
+// attempts are made, while the results of in-flight attempts are still
+// collected.
+func (p *paymentLifecycle) checkBudget(payment DBMPPayment) error {
+	// Nothing to check if there's no budget, or it was already exhausted
+	// and the payment failed.
+	if p.budget == nil || p.budgetExhausted.IsSome() {
+		return nil
+	}
+
+	budget := p.budget
+	now := p.router.cfg.Clock.Now()
+
+	var (
+		exhausted fn.Option[BudgetConstraint]
+		reason    channeldb.FailureReason
+	)
+	switch {
+	case !budget.Deadline.IsZero() && !now.Before(budget.Deadline):
+		exhausted = fn.Some(BudgetConstraintDeadline)
+		reason = channeldb.FailureReasonTimeout
+
+	case budget.MaxAttempts > 0 &&
+		uint32(len(payment.GetHTLCs())) >= budget.MaxAttempts:
+
+		exhausted = fn.Some(BudgetConstraintAttempts)
+		reason = channeldb.FailureReasonNoRoute
+
+	case budget.AttemptTimeout > 0:
+		for _, htlc := range payment.InFlightHTLCs() {
+			if now.Sub(htlc.AttemptTime) < budget.AttemptTimeout {
+				continue
+			}
+
+			exhausted = fn.Some(BudgetConstraintAttemptTimeout)
+			reason = channeldb.FailureReasonTimeout
+
+			break
+		}
+	}
+
+	return fn.MapOptionZ(exhausted, func(c BudgetConstraint) error {
+		log.Warnf("Payment %v exhausted its %v budget, marking "+
+			"payment failed with reason %v", p.identifier, c,
+			reason)
+
+		p.budgetExhausted = exhausted
+
+		err := p.router.cfg.Control.FailPayment(p.identifier, reason)
+		if err != nil {
+			return fmt.Errorf("FailPayment got: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// budgetFailure wraps the payment's failure reason in a BudgetExhaustedError
+// if the payment failed due to one of the constraints of its budget.
+func (p *paymentLifecycle) budgetFailure(
+	reason channeldb.FailureReason) error {
+
+	// If the fee limit was capped by the budget and no route was found,
+	// the fee budget was too tight.
+	if p.budgetExhausted.IsNone() && p.feeLimitCapped &&
+		reason == channeldb.FailureReasonNoRoute {
+
+		return &BudgetExhaustedError{
+			Constraint: BudgetConstraintFee,
+			Reason:     reason,
+		}
+	}
+
+	return fn.MapOptionZ(p.budgetExhausted, func(c BudgetConstraint) error {
+		return &BudgetExhaustedError{
+			Constraint: c,
+			Reason:     reason,
+		}
+	})
+}
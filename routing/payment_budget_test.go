@@ -0,0 +1,164 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckBudget asserts that the payment is failed with the expected reason
+// once one of the constraints of its budget is exhausted.
+func TestCheckBudget(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1_000_000, 0)
+
+	attempt := func(age time.Duration) channeldb.HTLCAttempt {
+		return channeldb.HTLCAttempt{
+			HTLCAttemptInfo: channeldb.HTLCAttemptInfo{
+				AttemptTime: now.Add(-age),
+			},
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		budget   *PaymentBudget
+		htlcs    []channeldb.HTLCAttempt
+		inflight []channeldb.HTLCAttempt
+
+		expectedConstraint BudgetConstraint
+		expectedReason     channeldb.FailureReason
+		exhausted          bool
+	}{
+		{
+			name: "deadline passed",
+			budget: &PaymentBudget{
+				Deadline: now.Add(-time.Second),
+			},
+			expectedConstraint: BudgetConstraintDeadline,
+			expectedReason:     channeldb.FailureReasonTimeout,
+			exhausted:          true,
+		},
+		{
+			name: "deadline not passed",
+			budget: &PaymentBudget{
+				Deadline: now.Add(time.Second),
+			},
+		},
+		{
+			name: "max attempts reached",
+			budget: &PaymentBudget{
+				MaxAttempts: 2,
+			},
+			htlcs: []channeldb.HTLCAttempt{
+				attempt(0), attempt(0),
+			},
+			expectedConstraint: BudgetConstraintAttempts,
+			expectedReason:     channeldb.FailureReasonNoRoute,
+			exhausted:          true,
+		},
+		{
+			name: "max attempts not reached",
+			budget: &PaymentBudget{
+				MaxAttempts: 2,
+			},
+			htlcs: []channeldb.HTLCAttempt{attempt(0)},
+		},
+		{
+			name: "attempt timed out",
+			budget: &PaymentBudget{
+				AttemptTimeout: time.Minute,
+			},
+			inflight: []channeldb.HTLCAttempt{
+				attempt(time.Second), attempt(2 * time.Minute),
+			},
+			expectedConstraint: BudgetConstraintAttemptTimeout,
+			expectedReason:     channeldb.FailureReasonTimeout,
+			exhausted:          true,
+		},
+		{
+			name: "attempt not timed out",
+			budget: &PaymentBudget{
+				AttemptTimeout: time.Minute,
+			},
+			inflight: []channeldb.HTLCAttempt{attempt(time.Second)},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			p, m := newTestPaymentLifecycle(t)
+			p.budget = tc.budget
+
+			m.clock.On("Now").Return(now)
+			m.payment.On("GetHTLCs").Return(tc.htlcs).Maybe()
+			m.payment.On("InFlightHTLCs").Return(
+				tc.inflight,
+			).Maybe()
+
+			if tc.exhausted {
+				m.control.On(
+					"FailPayment", p.identifier,
+					tc.expectedReason,
+				).Return(nil).Once()
+			}
+
+			require.NoError(t, p.checkBudget(m.payment))
+
+			err := p.budgetFailure(tc.expectedReason)
+			if !tc.exhausted {
+				require.NoError(t, err)
+				return
+			}
+
+			var budgetErr *BudgetExhaustedError
+			require.ErrorAs(t, err, &budgetErr)
+			require.Equal(
+				t, tc.expectedConstraint, budgetErr.Constraint,
+			)
+
+			// The failure reason is still accessible.
+			var reason channeldb.FailureReason
+			require.True(t, errors.As(err, &reason))
+			require.Equal(t, tc.expectedReason, reason)
+
+			// Once exhausted, the payment isn't failed again.
+			require.NoError(t, p.checkBudget(m.payment))
+		})
+	}
+}
+
+// TestBudgetFeeLimit asserts that the fee limit is derived from the fee
+// percentage and that a no route failure is attributed to it.
+func TestBudgetFeeLimit(t *testing.T) {
+	t.Parallel()
+
+	var budget *PaymentBudget
+	require.True(t, budget.feeLimit(1000).IsNone())
+
+	budget = &PaymentBudget{MaxFeePercent: 0.5}
+	limit := budget.feeLimit(1_000_000)
+	require.Equal(t, lnwire.MilliSatoshi(5_000), limit.UnwrapOr(0))
+
+	p := createTestPaymentLifecycle()
+	p.budget = budget
+	p.feeLimitCapped = true
+
+	var budgetErr *BudgetExhaustedError
+	err := p.budgetFailure(channeldb.FailureReasonNoRoute)
+	require.ErrorAs(t, err, &budgetErr)
+	require.Equal(t, BudgetConstraintFee, budgetErr.Constraint)
+
+	// Other failures are not attributed to the fee budget.
+	err = p.budgetFailure(channeldb.FailureReasonPaymentDetails)
+	require.NoError(t, err)
+}
@@ -0,0 +1,146 @@
+package routing
+
+import (
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// PaymentEstimate is the outcome of a simulated payment.
+type PaymentEstimate struct {
+	// Routes are the routes of the shards that the payment would be split
+	// into.
+	Routes []*route.Route
+
+	// TotalFees is the sum of the fees of all shards.
+	TotalFees lnwire.MilliSatoshi
+
+	// TotalTimeLock is the highest absolute time lock of all shards.
+	TotalTimeLock uint32
+
+	// Probability is the estimated probability that all shards succeed on
+	// their first attempt.
+	Probability float64
+}
+
+// simulatedBandwidthHints wraps the bandwidth hints of our local channels and
+// deducts the amounts of the shards that a simulated payment has already
+// sent out over them.
+type simulatedBandwidthHints struct {
+	bandwidthHints
+
+	// inFlight is the amount that simulated shards have locked up per
+	// local channel.
+	inFlight map[uint64]lnwire.MilliSatoshi
+}
+
+// availableChanBandwidth returns the bandwidth of the channel, minus the
+// amount locked up by simulated shards.
+//
+// NOTE: Part of the bandwidthHints interface.
+func (s *simulatedBandwidthHints) availableChanBandwidth(channelID uint64,
+	amount lnwire.MilliSatoshi) (lnwire.MilliSatoshi, bool) {
+
+	bandwidth, ok := s.bandwidthHints.availableChanBandwidth(
+		channelID, amount,
+	)
+	if !ok {
+		return 0, false
+	}
+
+	inFlight := s.inFlight[channelID]
+	if inFlight >= bandwidth {
+		return 0, true
+	}
+
+	return bandwidth - inFlight, true
+}
+
+// EstimatePayment simulates the given payment without sending it. It runs the
+// same path finding and splitting logic as a real payment, using the current
+// mission control state, and assumes that every shard succeeds. The liquidity
+// of our local channels that earlier shards use is not available to the later
+// ones.
+//
+// NOTE: Part of the PaymentSessionSource interface.
+func (m *SessionSource) EstimatePayment(p *LightningPayment,
+	firstHopBlob fn.Option[tlv.Blob],
+	trafficShaper fn.Option[TlvTrafficShaper],
+	height uint32) (*PaymentEstimate, error) {
+
+	inFlight := make(map[uint64]lnwire.MilliSatoshi)
+	getBandwidthHints := func(graph Graph) (bandwidthHints, error) {
+		hints, err := newBandwidthManager(
+			graph, m.SourceNode.PubKeyBytes, m.GetLink,
+			firstHopBlob, trafficShaper,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &simulatedBandwidthHints{
+			bandwidthHints: hints,
+			inFlight:       inFlight,
+		}, nil
+	}
+
+	session, err := newPaymentSession(
+		p, m.SourceNode.PubKeyBytes, getBandwidthHints,
+		m.GraphSessionFactory, m.MissionControl, m.PathFindingConfig,
+	)
+	if err != nil {
+		return nil, err
+	}
+	session.landmarks = m.Landmarks
+
+	// The route cache is deliberately not consulted, because we need the
+	// probability that path finding assigns to every shard.
+	var probability float64
+	session.pathFinder = func(g *graphParams, r *RestrictParams,
+		cfg *PathFindingConfig, self, source, target route.Vertex,
+		amt lnwire.MilliSatoshi, timePref float64,
+		finalHtlcExpiry int32) ([]*unifiedEdge, float64, error) {
+
+		path, prob, err := findPath(
+			g, r, cfg, self, source, target, amt, timePref,
+			finalHtlcExpiry,
+		)
+		probability = prob
+
+		return path, prob, err
+	}
+
+	estimate := &PaymentEstimate{
+		Probability: 1,
+	}
+
+	remaining := p.Amount
+	for remaining > 0 {
+		rt, err := session.RequestRoute(
+			remaining, p.FeeLimit-estimate.TotalFees,
+			uint32(len(estimate.Routes)), height,
+			p.FirstHopCustomRecords,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		inFlight[rt.Hops[0].ChannelID] += rt.TotalAmount
+
+		estimate.Routes = append(estimate.Routes, rt)
+		estimate.TotalFees += rt.TotalFees()
+		estimate.TotalTimeLock = max(
+			estimate.TotalTimeLock, rt.TotalTimeLock,
+		)
+		estimate.Probability *= probability
+
+		remaining -= rt.ReceiverAmt()
+	}
+
+	log.Debugf("Estimated payment of %v to %v: %v shards, fees=%v, "+
+		"probability=%v", p.Amount, p.Target, len(estimate.Routes),
+		estimate.TotalFees, estimate.Probability)
+
+	return estimate, nil
+}
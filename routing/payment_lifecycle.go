@@ -50,6 +50,17 @@ type paymentLifecycle struct {
 	// except in unit test, where we use a much simpler resultCollector to
 	// decouple the test flow for the payment lifecycle.
 	resultCollector func(attempt *channeldb.HTLCAttempt)
+
+	// budget is an optional set of additional limits for this payment.
+	budget *PaymentBudget
+
+	// feeLimitCapped is true if the fee limit was lowered to match the
+	// fee percentage of the budget.
+	feeLimitCapped bool
+
+	// budgetExhausted is set to the constraint of the budget that was
+	// exhausted, if any.
+	budgetExhausted fn.Option[BudgetConstraint]
 }
 
 // newPaymentLifecycle initiates a new payment lifecycle and returns it.
@@ -234,6 +245,12 @@ lifecycle:
 			return exitWithErr(err)
 		}
 
+		// Likewise, we'll make sure none of the constraints of the
+		// payment's budget have been exhausted.
+		if err := p.checkBudget(payment); err != nil {
+			return exitWithErr(err)
+		}
+
 		// Now decide the next step of the current lifecycle.
 		step, err := p.decideNextStep(payment)
 		if err != nil {
@@ -319,6 +336,12 @@ lifecycle:
 		return htlc.Settle.Preimage, &htlc.Route, nil
 	}
 
+	// If the payment failed because its budget was exhausted, we'll
+	// report the constraint along with the failure reason.
+	if err := p.budgetFailure(*failure); err != nil {
+		return [32]byte{}, nil, err
+	}
+
 	// Otherwise return the payment failure reason.
 	return [32]byte{}, nil, *failure
 }
@@ -554,7 +577,9 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 	// In case of a payment failure, fail the attempt with the control
 	// tower and return.
 	if result.Error != nil {
-		return p.handleSwitchErr(attempt, result.Error)
+		return p.handleSwitchErr(
+			attempt, attributeFailure(attempt, result),
+		)
 	}
 
 	// We successfully got a payment result back from the switch.
@@ -569,6 +594,12 @@ func (p *paymentLifecycle) collectResult(attempt *channeldb.HTLCAttempt) (
 		log.Errorf("Error reporting payment success to mc: %v", err)
 	}
 
+	// Remember the route, so that repeated payments to the same
+	// destination can skip path finding.
+	if p.router.cfg.RouteCache != nil {
+		p.router.cfg.RouteCache.AddRoute(&attempt.Route)
+	}
+
 	// In case of success we atomically store settle result to the DB move
 	// the shard to the settled state.
 	htlcAttempt, err := p.router.cfg.Control.SettleAttempt(
@@ -883,6 +914,19 @@ func (p *paymentLifecycle) handleSwitchErr(attempt *channeldb.HTLCAttempt,
 		log.Warn("Unreadable failure when sending htlc: id=%v, hash=%v",
 			attempt.AttemptID, attempt.Hash)
 
+		// If the failure carried attribution data, we know which node
+		// to blame for it.
+		var attrErr *attributedError
+		if errors.As(sendErr, &attrErr) && attrErr.blamedIdx.IsSome() {
+			blamedIdx := attrErr.blamedIdx.UnsafeFromSome()
+
+			log.Debugf("Attributed unreadable failure of attempt "+
+				"%v to node at position %v", attempt.AttemptID,
+				blamedIdx)
+
+			return reportAndFail(&blamedIdx, nil)
+		}
+
 		// Since this error message cannot be decrypted, we will send a
 		// nil error message to our mission controller and fail the
 		// payment.
@@ -1046,6 +1090,13 @@ func marshallError(sendError error, time time.Time) *channeldb.HTLCFailInfo {
 		FailTime: time,
 	}
 
+	// Store the hold times that the hops reported through the attribution
+	// data of the failure, if any.
+	var attrErr *attributedError
+	if errors.As(sendError, &attrErr) {
+		response.HoldTimes = attrErr.holdTimes
+	}
+
 	switch {
 	case errors.Is(sendError, htlcswitch.ErrPaymentIDNotFound):
 		response.Reason = channeldb.HTLCFailInternal
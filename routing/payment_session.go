@@ -8,6 +8,7 @@ import (
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/channeldb/models"
+	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
@@ -186,6 +187,14 @@ type paymentSession struct {
 
 	missionControl MissionControlQuerier
 
+	// routeCache is an optional cache of recently successful routes that
+	// is consulted before running full path finding.
+	routeCache *RouteCache
+
+	// landmarks optionally provides distance estimates that speed up path
+	// finding.
+	landmarks *LandmarkHeuristic
+
 	// minShardAmt is the amount beyond which we won't try to further split
 	// the payment if no route is found. If the maximum number of htlcs
 	// specified in the payment is one, under no circumstances splitting
@@ -274,7 +283,9 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		OutgoingChannelIDs:    p.payment.OutgoingChannelIDs,
 		LastHop:               p.payment.LastHop,
 		CltvLimit:             cltvLimit,
+		CltvBudget:            p.payment.CltvBudget,
 		DestCustomRecords:     p.payment.DestCustomRecords,
+		HopCustomRecords:      p.payment.HopCustomRecords,
 		DestFeatures:          p.payment.DestFeatures,
 		PaymentAddr:           p.payment.PaymentAddr,
 		Amp:                   p.payment.amp,
@@ -322,18 +333,27 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 
 		p.log.Debugf("pathfinding for amt=%v", maxAmt)
 
-		// Find a route for the current amount.
-		path, _, err := p.pathFinder(
-			&graphParams{
-				additionalEdges: p.additionalEdges,
-				bandwidthHints:  bandwidthHints,
-				graph:           graph,
-			},
-			restrictions, &p.pathFindingConfig,
-			p.selfNode, p.selfNode, p.payment.Target,
-			maxAmt, p.payment.TimePref, finalHtlcExpiry,
+		// Before resorting to full path finding, try the route that
+		// recently succeeded for a similar amount to the destination.
+		path := p.pathFromCache(
+			graph, bandwidthHints, restrictions, maxAmt,
 		)
 
+		// Find a route for the current amount.
+		if path == nil {
+			path, _, err = p.pathFinder(
+				&graphParams{
+					additionalEdges: p.additionalEdges,
+					bandwidthHints:  bandwidthHints,
+					graph:           graph,
+					landmarks:       p.landmarks,
+				},
+				restrictions, &p.pathFindingConfig,
+				p.selfNode, p.selfNode, p.payment.Target,
+				maxAmt, p.payment.TimePref, finalHtlcExpiry,
+			)
+		}
+
 		// Close routing graph session.
 		if err := closeGraph(); err != nil {
 			log.Errorf("could not close graph session: %v", err)
@@ -381,6 +401,24 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 				return nil, errNoPathFound
 			}
 
+			// If the channels to one of our peers can carry the
+			// amount together, but none of them can on its own, we
+			// treat them as an aggregate first hop. The shard is
+			// then sized to fit the best of these channels, so
+			// that the next shards are spread over the others.
+			peerAmt := peerChannelsShardAmt(
+				bandwidthHints, p.payment.OutgoingChannelIDs,
+				maxAmt,
+			)
+			if peerAmt > 0 && peerAmt >= p.minShardAmt {
+				p.log.Debugf("Splitting shard to %v to fit "+
+					"the local channels to a peer", peerAmt)
+
+				maxAmt = peerAmt
+
+				continue
+			}
+
 			// This is where the magic happens. If we can't find a
 			// route, try it for half the amount.
 			maxAmt /= 2
@@ -429,6 +467,10 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 			return nil, err
 		}
 
+		// Attach the custom records that are addressed to any of the
+		// intermediate hops of the route.
+		addHopCustomRecords(route, p.payment.HopCustomRecords)
+
 		return route, err
 	}
 }
@@ -483,3 +525,107 @@ func (p *paymentSession) GetAdditionalEdgePolicy(pubKey *btcec.PublicKey,
 
 	return nil
 }
+
+// pathFromCache returns the path of a route that recently succeeded for a
+// similar amount to the payment's destination, if it is still usable within
+// the payment's fee and time lock limits. Payments that restrict the route,
+// use private edges or carry records for intermediate hops always go through
+// full path finding.
+func (p *paymentSession) pathFromCache(graph Graph, hints bandwidthHints,
+	r *RestrictParams, amt lnwire.MilliSatoshi) []*unifiedEdge {
+
+	if p.routeCache == nil || len(p.additionalEdges) > 0 ||
+		len(r.OutgoingChannelIDs) > 0 || r.LastHop != nil ||
+		r.CltvBudget != nil || len(r.HopCustomRecords) > 0 {
+
+		return nil
+	}
+
+	target := p.payment.Target
+	cached := p.routeCache.lookup(target, amt)
+	if cached == nil {
+		return nil
+	}
+
+	// Rebuild the path with the current policies and balances. If any of
+	// the channels is gone, the cached route is of no use anymore.
+	unifiers, err := getEdgeUnifiers(p.selfNode, cached.hops, nil, graph)
+	if err != nil {
+		p.log.Debugf("Removing stale cached route to %v: %v", target,
+			err)
+
+		p.routeCache.remove(target, amt)
+
+		return nil
+	}
+
+	path, senderAmt, err := senderAmtBackwardPass(
+		unifiers, fn.Some(amt), hints,
+	)
+	if err != nil {
+		p.log.Debugf("Cached route to %v can't carry %v: %v", target,
+			amt, err)
+
+		return nil
+	}
+
+	if senderAmt-amt > r.FeeLimit {
+		p.log.Debugf("Cached route to %v exceeds fee limit", target)
+
+		return nil
+	}
+
+	// We don't pay a time lock delta for our own channel.
+	var timeLockDelta uint32
+	for _, edge := range path[1:] {
+		timeLockDelta += uint32(edge.policy.TimeLockDelta)
+	}
+	if timeLockDelta > r.CltvLimit {
+		p.log.Debugf("Cached route to %v exceeds cltv limit", target)
+
+		return nil
+	}
+
+	p.log.Debugf("Using cached route to %v", target)
+
+	return path
+}
+
+// peerChannelsShardAmt returns the largest amount that a single local channel
+// can carry out of the channels to one of our peers whose combined bandwidth
+// covers the given amount, while none of the channels can carry the amount on
+// its own. If the payment is restricted to a set of outgoing channels, only
+// those are considered. Zero is returned if there is no such peer.
+func peerChannelsShardAmt(hints bandwidthHints, outgoingChans []uint64,
+	amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	allowed := fn.NewSet(outgoingChans...)
+
+	var shardAmt lnwire.MilliSatoshi
+	for _, chanIDs := range hints.peerChannels() {
+		var total, best lnwire.MilliSatoshi
+		for _, chanID := range chanIDs {
+			if len(allowed) > 0 && !allowed.Contains(chanID) {
+				continue
+			}
+
+			bandwidth, ok := hints.availableChanBandwidth(
+				chanID, 0,
+			)
+			if !ok {
+				continue
+			}
+
+			total += bandwidth
+			best = max(best, bandwidth)
+		}
+
+		if best >= amt || total < amt {
+			continue
+		}
+
+		shardAmt = max(shardAmt, best)
+	}
+
+	return shardAmt
+}
@@ -45,6 +45,14 @@ type SessionSource struct {
 	// PathFindingConfig defines global parameters that control the
 	// trade-off in path finding between fees and probability.
 	PathFindingConfig PathFindingConfig
+
+	// RouteCache is an optional cache of recently successful routes that
+	// is consulted before running full path finding.
+	RouteCache *RouteCache
+
+	// Landmarks optionally provides distance estimates that speed up path
+	// finding.
+	Landmarks *LandmarkHeuristic
 }
 
 // NewPaymentSession creates a new payment session backed by the latest prune
@@ -70,6 +78,9 @@ func (m *SessionSource) NewPaymentSession(p *LightningPayment,
 		return nil, err
 	}
 
+	session.routeCache = m.RouteCache
+	session.landmarks = m.Landmarks
+
 	return session, nil
 }
 
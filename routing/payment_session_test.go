@@ -251,6 +251,81 @@ func TestRequestRoute(t *testing.T) {
 	}
 }
 
+// TestPeerChannelsShardAmt asserts that shards are sized to fit the best
+// channel out of the channels to the same peer that can carry the amount
+// together.
+func TestPeerChannelsShardAmt(t *testing.T) {
+	t.Parallel()
+
+	peer1, peer2 := route.Vertex{1}, route.Vertex{2}
+
+	hints := &mockBandwidthHints{
+		hints: map[uint64]lnwire.MilliSatoshi{
+			1: 40_000,
+			2: 30_000,
+			3: 50_000,
+			4: 10_000,
+		},
+		peerChans: map[route.Vertex][]uint64{
+			peer1: {1, 2},
+			peer2: {3, 4},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		outgoingChans []uint64
+		amt           lnwire.MilliSatoshi
+		expectedAmt   lnwire.MilliSatoshi
+	}{
+		{
+			name:        "single channel suffices",
+			amt:         40_000,
+			expectedAmt: 0,
+		},
+		{
+			name:        "best peer",
+			amt:         60_000,
+			expectedAmt: 50_000,
+		},
+		{
+			name:        "only one peer suffices",
+			amt:         70_000,
+			expectedAmt: 40_000,
+		},
+		{
+			name:        "insufficient peer bandwidth",
+			amt:         80_000,
+			expectedAmt: 0,
+		},
+		{
+			name:          "restricted outgoing channels",
+			outgoingChans: []uint64{2, 3, 4},
+			amt:           55_000,
+			expectedAmt:   50_000,
+		},
+		{
+			name:          "restricted peer insufficient",
+			outgoingChans: []uint64{1, 4},
+			amt:           45_000,
+			expectedAmt:   0,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			amt := peerChannelsShardAmt(
+				hints, tc.outgoingChans, tc.amt,
+			)
+			require.Equal(t, tc.expectedAmt, amt)
+		})
+	}
+}
+
 type sessionGraph struct {
 	Graph
 }
@@ -0,0 +1,329 @@
+package routing
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/ticker"
+)
+
+const (
+	// DefaultProbeInterval is the default interval at which the prober
+	// dispatches a new probe.
+	DefaultProbeInterval = 10 * time.Minute
+
+	// DefaultProbeAmount is the default amount that is used for probes.
+	DefaultProbeAmount = lnwire.MilliSatoshi(100_000_000)
+
+	// DefaultProbeFeeLimit is the default maximum fee a probe route may
+	// have.
+	DefaultProbeFeeLimit = lnwire.MilliSatoshi(1_000_000)
+
+	// DefaultProbeTimeout is the default time after which a single probe
+	// is given up on.
+	DefaultProbeTimeout = time.Minute
+
+	// DefaultMaxProbeHistory is the default number of probe results that
+	// are kept in memory.
+	DefaultMaxProbeHistory = 1000
+)
+
+var (
+	// ErrNoProbeTarget is returned when the prober has no configured
+	// targets and is unable to select a random one.
+	ErrNoProbeTarget = errors.New("no probe target available")
+)
+
+// ProberConfig houses the parameters and dependencies of the Prober.
+type ProberConfig struct {
+	// Amount is the amount that is sent along with each probe.
+	Amount lnwire.MilliSatoshi
+
+	// FeeLimit is the maximum fee a single probe may pay. As a probe never
+	// settles, this only limits the routes that are explored.
+	FeeLimit lnwire.MilliSatoshi
+
+	// Timeout is the maximum time a single probe may take.
+	Timeout time.Duration
+
+	// Targets is the set of nodes that are probed in a round-robin
+	// fashion. If it is empty, RandomTarget is used to select a node.
+	Targets []route.Vertex
+
+	// RandomTarget returns a random node from the graph to probe. It is
+	// only used if no explicit targets are configured.
+	RandomTarget func() (route.Vertex, error)
+
+	// SendPayment dispatches a payment to the network and blocks until it
+	// resolves. The outcome of every attempt made is reported to mission
+	// control by the payment lifecycle.
+	SendPayment func(*LightningPayment) ([32]byte, *route.Route, error)
+
+	// DeletePayment removes the payment with the given hash from the
+	// payment database. Probes are deleted once they resolved so that
+	// they don't show up as failed payments.
+	DeletePayment func(lntypes.Hash) error
+
+	// Ticker signals when a new probe should be dispatched.
+	Ticker ticker.Ticker
+
+	// Clock is the time source used to timestamp the probe results.
+	Clock clock.Clock
+
+	// MaxHistory is the maximum number of probe results that are kept.
+	MaxHistory int
+}
+
+// ProbeResult describes the outcome of a single probe.
+type ProbeResult struct {
+	// Target is the node that was probed.
+	Target route.Vertex
+
+	// Amount is the amount that was probed with.
+	Amount lnwire.MilliSatoshi
+
+	// Timestamp is the time at which the probe was dispatched.
+	Timestamp time.Time
+
+	// Duration is the time it took for the probe to resolve.
+	Duration time.Duration
+
+	// Reached is true if the probe made it all the way to the target
+	// node, meaning that the target rejected the unknown payment hash.
+	Reached bool
+
+	// FailureReason is the reason the payment failed. This is nil if the
+	// probe failed with an unexpected error.
+	FailureReason *channeldb.FailureReason
+
+	// Err is the error the probe terminated with.
+	Err error
+}
+
+// Prober periodically sends payments with an unknown payment hash to a set of
+// target nodes. As these payments can never settle, they are a risk-free way
+// of learning about the liquidity in the network. The results of the
+// individual attempts are fed into mission control through the regular payment
+// lifecycle, which improves the success rate of future payments.
+type Prober struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *ProberConfig
+
+	// nextTarget is the index of the next configured target to probe.
+	nextTarget int
+
+	historyMtx sync.RWMutex
+	history    []*ProbeResult
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewProber creates a new prober from the given config.
+func NewProber(cfg *ProberConfig) (*Prober, error) {
+	if cfg.SendPayment == nil {
+		return nil, errors.New("prober requires SendPayment")
+	}
+
+	if cfg.DeletePayment == nil {
+		return nil, errors.New("prober requires DeletePayment")
+	}
+
+	if len(cfg.Targets) == 0 && cfg.RandomTarget == nil {
+		return nil, ErrNoProbeTarget
+	}
+
+	if cfg.Amount == 0 {
+		return nil, errors.New("probe amount must be positive")
+	}
+
+	if cfg.MaxHistory <= 0 {
+		cfg.MaxHistory = DefaultMaxProbeHistory
+	}
+
+	if cfg.Ticker == nil {
+		cfg.Ticker = ticker.New(DefaultProbeInterval)
+	}
+
+	if cfg.Clock == nil {
+		cfg.Clock = clock.NewDefaultClock()
+	}
+
+	return &Prober{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}, nil
+}
+
+// Start launches the probing goroutine.
+func (p *Prober) Start() error {
+	p.started.Do(func() {
+		log.Info("Prober starting")
+
+		p.cfg.Ticker.Resume()
+
+		p.wg.Add(1)
+		go p.probeLoop()
+	})
+
+	return nil
+}
+
+// Stop signals the probing goroutine to exit and waits for it to do so. A
+// probe that is currently in flight is waited for.
+func (p *Prober) Stop() error {
+	p.stopped.Do(func() {
+		log.Info("Prober shutting down...")
+		defer log.Debug("Prober shutdown complete")
+
+		p.cfg.Ticker.Stop()
+		close(p.quit)
+		p.wg.Wait()
+	})
+
+	return nil
+}
+
+// probeLoop dispatches a new probe on every tick of the ticker.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *Prober) probeLoop() {
+	defer p.wg.Done()
+
+	for {
+		select {
+		case <-p.cfg.Ticker.Ticks():
+			target, err := p.selectTarget()
+			if err != nil {
+				log.Warnf("Unable to select probe target: %v",
+					err)
+
+				continue
+			}
+
+			result := p.Probe(target, p.cfg.Amount)
+			log.Debugf("Probe to %v for %v completed in %v: "+
+				"reached=%v, err=%v", target, result.Amount,
+				result.Duration, result.Reached, result.Err)
+
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// selectTarget returns the next node to probe. Configured targets are
+// returned in a round-robin fashion, if there are none, a random node is
+// selected.
+func (p *Prober) selectTarget() (route.Vertex, error) {
+	if len(p.cfg.Targets) == 0 {
+		return p.cfg.RandomTarget()
+	}
+
+	target := p.cfg.Targets[p.nextTarget%len(p.cfg.Targets)]
+	p.nextTarget++
+
+	return target, nil
+}
+
+// Probe sends a single probe of the given amount to the target node and
+// blocks until it resolves. The result is added to the probe history.
+func (p *Prober) Probe(target route.Vertex,
+	amt lnwire.MilliSatoshi) *ProbeResult {
+
+	result := &ProbeResult{
+		Target:    target,
+		Amount:    amt,
+		Timestamp: p.cfg.Clock.Now(),
+	}
+
+	hash, err := p.sendProbe(target, amt)
+	result.Duration = p.cfg.Clock.Now().Sub(result.Timestamp)
+	result.Err = err
+
+	// A failure reason means that the payment was initiated and has
+	// failed, so its record is deleted from the payment database as it
+	// isn't a payment the user made.
+	var reason channeldb.FailureReason
+	if errors.As(err, &reason) {
+		result.FailureReason = &reason
+		result.Reached = reason == channeldb.FailureReasonPaymentDetails
+
+		if err := p.cfg.DeletePayment(hash); err != nil {
+			log.Warnf("Unable to delete probe %v: %v", hash, err)
+		}
+	}
+
+	p.addResult(result)
+
+	return result
+}
+
+// sendProbe dispatches a payment with a random payment hash to the target. The
+// hash of the payment is returned along with the error it terminated with.
+func (p *Prober) sendProbe(target route.Vertex,
+	amt lnwire.MilliSatoshi) (lntypes.Hash, error) {
+
+	var hash lntypes.Hash
+	if _, err := rand.Read(hash[:]); err != nil {
+		return hash, err
+	}
+
+	payment := &LightningPayment{
+		Target:            target,
+		Amount:            amt,
+		FeeLimit:          p.cfg.FeeLimit,
+		FinalCLTVDelta:    MinCLTVDelta,
+		PayAttemptTimeout: p.cfg.Timeout,
+		MaxParts:          1,
+	}
+	if err := payment.SetPaymentHash(hash); err != nil {
+		return hash, err
+	}
+
+	_, _, err := p.cfg.SendPayment(payment)
+	if err == nil {
+		// A payment with a random hash can't ever settle, so if it did
+		// something is seriously wrong.
+		return hash, fmt.Errorf("probe with random hash %v settled",
+			hash)
+	}
+
+	return hash, err
+}
+
+// addResult appends the result to the probe history, evicting the oldest
+// result if the history is full.
+func (p *Prober) addResult(result *ProbeResult) {
+	p.historyMtx.Lock()
+	defer p.historyMtx.Unlock()
+
+	p.history = append(p.history, result)
+	if len(p.history) > p.cfg.MaxHistory {
+		p.history = p.history[len(p.history)-p.cfg.MaxHistory:]
+	}
+}
+
+// ProbeHistory returns a copy of the results of all recent probes, ordered
+// from oldest to newest.
+func (p *Prober) ProbeHistory() []ProbeResult {
+	p.historyMtx.RLock()
+	defer p.historyMtx.RUnlock()
+
+	history := make([]ProbeResult, 0, len(p.history))
+	for _, result := range p.history {
+		history = append(history, *result)
+	}
+
+	return history
+}
@@ -0,0 +1,164 @@
+package routing
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/clock"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProbeSender records the payments dispatched by the prober and fails
+// them with a preset error.
+type mockProbeSender struct {
+	mu       sync.Mutex
+	payments []*LightningPayment
+	deleted  []lntypes.Hash
+	err      error
+	sent     chan struct{}
+}
+
+func (m *mockProbeSender) SendPayment(p *LightningPayment) ([32]byte,
+	*route.Route, error) {
+
+	m.mu.Lock()
+	m.payments = append(m.payments, p)
+	m.mu.Unlock()
+
+	if m.sent != nil {
+		m.sent <- struct{}{}
+	}
+
+	return [32]byte{}, nil, m.err
+}
+
+func (m *mockProbeSender) DeletePayment(hash lntypes.Hash) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.deleted = append(m.deleted, hash)
+
+	return nil
+}
+
+// TestProberRoundRobin asserts that the configured targets are probed in a
+// round-robin fashion and that the results are recorded.
+func TestProberRoundRobin(t *testing.T) {
+	t.Parallel()
+
+	targets := []route.Vertex{{1}, {2}}
+	sender := &mockProbeSender{
+		err:  channeldb.FailureReasonPaymentDetails,
+		sent: make(chan struct{}),
+	}
+	probeTicker := ticker.NewForce(time.Hour)
+
+	prober, err := NewProber(&ProberConfig{
+		Amount:        1000,
+		Targets:       targets,
+		SendPayment:   sender.SendPayment,
+		DeletePayment: sender.DeletePayment,
+		Ticker:        probeTicker,
+		Clock:         clock.NewTestClock(time.Unix(0, 0)),
+	})
+	require.NoError(t, err)
+	require.NoError(t, prober.Start())
+	t.Cleanup(func() {
+		require.NoError(t, prober.Stop())
+	})
+
+	for i := 0; i < 3; i++ {
+		probeTicker.Force <- time.Now()
+
+		select {
+		case <-sender.sent:
+		case <-time.After(time.Second):
+			t.Fatal("probe not dispatched")
+		}
+	}
+
+	sender.mu.Lock()
+	require.Len(t, sender.payments, 3)
+	require.Equal(t, targets[0], sender.payments[0].Target)
+	require.Equal(t, targets[1], sender.payments[1].Target)
+	require.Equal(t, targets[0], sender.payments[2].Target)
+	sender.mu.Unlock()
+
+	// The history is only updated after SendPayment returns, so wait for
+	// the last probe to be recorded.
+	require.Eventually(t, func() bool {
+		return len(prober.ProbeHistory()) == 3
+	}, time.Second, 10*time.Millisecond)
+
+	for _, result := range prober.ProbeHistory() {
+		require.True(t, result.Reached)
+		require.Equal(t, lnwire.MilliSatoshi(1000), result.Amount)
+	}
+}
+
+// TestProberResult asserts that probe outcomes are classified correctly and
+// that the history is bounded.
+func TestProberResult(t *testing.T) {
+	t.Parallel()
+
+	sender := &mockProbeSender{}
+	prober, err := NewProber(&ProberConfig{
+		Amount: 1000,
+		RandomTarget: func() (route.Vertex, error) {
+			return route.Vertex{}, ErrNoProbeTarget
+		},
+		SendPayment:   sender.SendPayment,
+		DeletePayment: sender.DeletePayment,
+		Clock:         clock.NewTestClock(time.Unix(0, 0)),
+		MaxHistory:    2,
+	})
+	require.NoError(t, err)
+
+	// A probe that fails before reaching the destination is not reached.
+	sender.err = channeldb.FailureReasonNoRoute
+	result := prober.Probe(route.Vertex{1}, 1000)
+	require.False(t, result.Reached)
+	require.NotNil(t, result.FailureReason)
+	require.Equal(t, channeldb.FailureReasonNoRoute, *result.FailureReason)
+
+	// The failed probe is removed from the payment database.
+	sender.mu.Lock()
+	require.Len(t, sender.deleted, 1)
+	require.Equal(
+		t, lntypes.Hash(sender.payments[0].Identifier()),
+		sender.deleted[0],
+	)
+	sender.mu.Unlock()
+
+	// An unexpected error doesn't carry a failure reason.
+	sender.err = errors.New("unexpected")
+	result = prober.Probe(route.Vertex{2}, 1000)
+	require.False(t, result.Reached)
+	require.Nil(t, result.FailureReason)
+	require.Error(t, result.Err)
+
+	// A probe that failed without a failure reason may never have been
+	// initiated, so there's nothing to delete.
+	sender.mu.Lock()
+	require.Len(t, sender.deleted, 1)
+	sender.mu.Unlock()
+
+	// A probe that settles is an error.
+	sender.err = nil
+	result = prober.Probe(route.Vertex{3}, 1000)
+	require.False(t, result.Reached)
+	require.Error(t, result.Err)
+
+	// Only the two most recent results are kept.
+	history := prober.ProbeHistory()
+	require.Len(t, history, 2)
+	require.Equal(t, route.Vertex{2}, history[0].Target)
+	require.Equal(t, route.Vertex{3}, history[1].Target)
+}
@@ -72,6 +72,33 @@ type interpretedResult struct {
 	// that connection. This is used to control the second chance logic for
 	// policy failures.
 	policyFailure *DirectedNodePair
+
+	// blindedPathResult is set if the outcome of the attempt concerns the
+	// blinded portion of the route. As the hops inside a blinded path are
+	// hidden from us, the outcome is tracked for the path as a whole.
+	blindedPathResult *blindedPathResult
+}
+
+// blindedPathKey identifies a blinded path.
+type blindedPathKey struct {
+	// introNode is the introduction node of the blinded path.
+	introNode route.Vertex
+
+	// pathID is the blinded node id of the first hop after the
+	// introduction node. As blinded node ids are derived from a random
+	// blinding point, it uniquely identifies the path.
+	pathID route.Vertex
+}
+
+// blindedPathResult is the outcome of a payment attempt through a blinded
+// path.
+type blindedPathResult struct {
+	// key identifies the blinded path.
+	key blindedPathKey
+
+	// success indicates whether the payment attempt made it through the
+	// blinded path.
+	success bool
 }
 
 // interpretResult interprets a payment outcome and returns an object that
@@ -96,6 +123,13 @@ func (i *interpretedResult) processSuccess(route *mcRoute) {
 	// For successes, all nodes must have acted in the right way. Therefore
 	// we mark all of them with a success result.
 	i.successPairRange(route, 0, len(route.hops)-1)
+
+	if key, ok := blindedPathKeyFromRoute(route); ok {
+		i.blindedPathResult = &blindedPathResult{
+			key:     key,
+			success: true,
+		}
+	}
 }
 
 // processFail processes a failed payment attempt.
@@ -112,6 +146,19 @@ func (i *interpretedResult) processFail(rt *mcRoute, errSourceIdx *int,
 	// has been a protocol violation from the introduction node. This
 	// penalty applies regardless of the error code that is returned.
 	introIdx, isBlinded := introductionPointIndex(rt)
+
+	// Failures that are reported by the introduction node or any node
+	// after it can't be attributed to a specific hop of the blinded path,
+	// so we record them against the blinded path as a whole. This allows
+	// repeated failures to degrade the path, even though its hops are
+	// hidden from us.
+	key, hasBlindedHops := blindedPathKeyFromRoute(rt)
+	if hasBlindedHops && introIdx <= *errSourceIdx {
+		i.blindedPathResult = &blindedPathResult{
+			key: key,
+		}
+	}
+
 	if isBlinded && introIdx < *errSourceIdx {
 		i.processPaymentOutcomeBadIntro(rt, introIdx, *errSourceIdx)
 		return
@@ -531,6 +578,23 @@ func introductionPointIndex(route *mcRoute) (int, bool) {
 	return 0, false
 }
 
+// blindedPathKeyFromRoute returns the key of the blinded path of the route. If
+// the route doesn't contain any blinded hops after the introduction node,
+// false is returned.
+func blindedPathKeyFromRoute(route *mcRoute) (blindedPathKey, bool) {
+	introIdx, isBlinded := introductionPointIndex(route)
+	if !isBlinded || introIdx >= len(route.hops) {
+		return blindedPathKey{}, false
+	}
+
+	// The introduction point index counts our own node as index zero, so
+	// it is the index of the first blinded hop in the route's hops.
+	return blindedPathKey{
+		introNode: route.hops[introIdx-1].pubKeyBytes,
+		pathID:    route.hops[introIdx].pubKeyBytes,
+	}, true
+}
+
 // processPaymentOutcomeUnknown processes a payment outcome for which no failure
 // message or source is available.
 func (i *interpretedResult) processPaymentOutcomeUnknown(route *mcRoute) {
@@ -454,6 +454,12 @@ var resultTestCases = []resultTestCase{
 			// Note: introduction node is failed even though the
 			// error source is after it.
 			nodeFailure: &hops[1],
+			blindedPathResult: &blindedPathResult{
+				key: blindedPathKey{
+					introNode: hops[1],
+					pathID:    hops[2],
+				},
+			},
 		},
 	},
 	// Test the case where we get a blinding failure from a blinded final
@@ -476,6 +482,12 @@ var resultTestCases = []resultTestCase{
 			// the final hop.
 			nodeFailure:        &hops[2],
 			finalFailureReason: &reasonError,
+			blindedPathResult: &blindedPathResult{
+				key: blindedPathKey{
+					introNode: hops[2],
+					pathID:    hops[3],
+				},
+			},
 		},
 	},
 	// Test a multi-hop blinded route where the failure occurs at the
@@ -492,6 +504,12 @@ var resultTestCases = []resultTestCase{
 				getTestPair(1, 2): successPairResult(99),
 				getTestPair(3, 4): failPairResult(88),
 			},
+			blindedPathResult: &blindedPathResult{
+				key: blindedPathKey{
+					introNode: hops[2],
+					pathID:    hops[3],
+				},
+			},
 		},
 	},
 	// Test a multi-hop blinded route where the failure occurs at the
@@ -507,6 +525,12 @@ var resultTestCases = []resultTestCase{
 				getTestPair(0, 1): successPairResult(100),
 				getTestPair(2, 3): failPairResult(75),
 			},
+			blindedPathResult: &blindedPathResult{
+				key: blindedPathKey{
+					introNode: hops[1],
+					pathID:    hops[2],
+				},
+			},
 		},
 	},
 	// Test a single-hop blinded route where the recipient is directly
@@ -523,6 +547,12 @@ var resultTestCases = []resultTestCase{
 				getTestPair(1, 2): successPairResult(99),
 			},
 			finalFailureReason: &reasonError,
+			blindedPathResult: &blindedPathResult{
+				key: blindedPathKey{
+					introNode: hops[2],
+					pathID:    hops[3],
+				},
+			},
 		},
 	},
 	// Test the case where a node before the introduction node returns a
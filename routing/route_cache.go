@@ -0,0 +1,149 @@
+package routing
+
+import (
+	"errors"
+	"math/bits"
+
+	"github.com/lightninglabs/neutrino/cache"
+	"github.com/lightninglabs/neutrino/cache/lru"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// routeCacheKey identifies the cached route to a destination for a range of
+// payment amounts.
+type routeCacheKey struct {
+	// target is the destination of the route.
+	target route.Vertex
+
+	// amtBucket is the bit length of the amount received by the
+	// destination. Each bucket therefore covers amounts that differ by at
+	// most a factor of two.
+	amtBucket int
+}
+
+// newRouteCacheKey returns the cache key for a payment of the given amount to
+// the given destination.
+func newRouteCacheKey(target route.Vertex,
+	amt lnwire.MilliSatoshi) routeCacheKey {
+
+	return routeCacheKey{
+		target:    target,
+		amtBucket: bits.Len64(uint64(amt)),
+	}
+}
+
+// cachedRoute is a route that recently succeeded.
+type cachedRoute struct {
+	// hops are the nodes along the route, excluding ourselves.
+	hops []route.Vertex
+
+	// pairs are the node pairs that the route traverses, including the
+	// pair from ourselves to the first hop.
+	pairs []DirectedNodePair
+}
+
+// Size returns the "size" of an entry.
+func (c *cachedRoute) Size() (uint64, error) {
+	return 1, nil
+}
+
+// RouteCache keeps the routes of recently successful payments, keyed by their
+// destination and amount, such that repeated payments to the same
+// destination can skip full path finding. Routes are evicted as soon as
+// mission control records a failure for any of their node pairs.
+type RouteCache struct {
+	routes *lru.Cache[routeCacheKey, *cachedRoute]
+}
+
+// NewRouteCache creates a route cache that holds at most the given number of
+// routes.
+func NewRouteCache(size int) *RouteCache {
+	return &RouteCache{
+		routes: lru.NewCache[routeCacheKey, *cachedRoute](
+			uint64(size),
+		),
+	}
+}
+
+// AddRoute adds a route that succeeded to the cache, replacing any route to
+// the same destination for a similar amount. Routes into blinded paths aren't
+// cached, as the blinded node ids are specific to a single invoice.
+func (c *RouteCache) AddRoute(rt *route.Route) {
+	if len(rt.Hops) == 0 {
+		return
+	}
+
+	cached := &cachedRoute{
+		hops:  make([]route.Vertex, 0, len(rt.Hops)),
+		pairs: make([]DirectedNodePair, 0, len(rt.Hops)),
+	}
+
+	from := rt.SourcePubKey
+	for _, hop := range rt.Hops {
+		if hop.EncryptedData != nil || hop.BlindingPoint != nil {
+			return
+		}
+
+		pair := NewDirectedNodePair(from, hop.PubKeyBytes)
+		cached.hops = append(cached.hops, hop.PubKeyBytes)
+		cached.pairs = append(cached.pairs, pair)
+
+		from = hop.PubKeyBytes
+	}
+
+	key := newRouteCacheKey(from, rt.ReceiverAmt())
+	if _, err := c.routes.Put(key, cached); err != nil {
+		log.Errorf("Unable to cache route to %v: %v", from, err)
+	}
+}
+
+// lookup returns the cached route to the destination for a payment of the
+// given amount, if any.
+func (c *RouteCache) lookup(target route.Vertex,
+	amt lnwire.MilliSatoshi) *cachedRoute {
+
+	cached, err := c.routes.Get(newRouteCacheKey(target, amt))
+	switch {
+	case errors.Is(err, cache.ErrElementNotFound):
+		return nil
+
+	case err != nil:
+		log.Errorf("Unable to look up cached route to %v: %v",
+			target, err)
+
+		return nil
+	}
+
+	return cached
+}
+
+// remove removes the cached route to the destination for a payment of the
+// given amount.
+func (c *RouteCache) remove(target route.Vertex, amt lnwire.MilliSatoshi) {
+	c.routes.Delete(newRouteCacheKey(target, amt))
+}
+
+// InvalidatePair removes all cached routes that traverse the given node pair.
+func (c *RouteCache) InvalidatePair(from, to route.Vertex) {
+	pair := NewDirectedNodePair(from, to)
+
+	var stale []routeCacheKey
+	c.routes.Range(func(key routeCacheKey, cached *cachedRoute) bool {
+		for _, cachedPair := range cached.pairs {
+			if cachedPair == pair {
+				stale = append(stale, key)
+				break
+			}
+		}
+
+		return true
+	})
+
+	for _, key := range stale {
+		log.Debugf("Invalidating cached route to %v after failure of "+
+			"pair %v", key.target, pair)
+
+		c.routes.Delete(key)
+	}
+}
@@ -0,0 +1,69 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRouteCache asserts that routes are cached by destination and amount
+// bucket, and that they are invalidated once one of their pairs fails.
+func TestRouteCache(t *testing.T) {
+	t.Parallel()
+
+	var (
+		source = route.Vertex{1}
+		alice  = route.Vertex{2}
+		bob    = route.Vertex{3}
+		carol  = route.Vertex{4}
+	)
+
+	newTestRoute := func(amt uint64, hops ...route.Vertex) *route.Route {
+		rt := &route.Route{
+			SourcePubKey: source,
+		}
+		for _, hop := range hops {
+			rt.Hops = append(rt.Hops, &route.Hop{
+				PubKeyBytes:  hop,
+				AmtToForward: lnwire.MilliSatoshi(amt),
+			})
+		}
+
+		return rt
+	}
+
+	routeCache := NewRouteCache(10)
+
+	routeCache.AddRoute(newTestRoute(1000, alice, bob))
+	routeCache.AddRoute(newTestRoute(100_000, carol, bob))
+
+	// Amounts in the same bucket share the cached route.
+	cached := routeCache.lookup(bob, 1023)
+	require.NotNil(t, cached)
+	require.Equal(t, []route.Vertex{alice, bob}, cached.hops)
+
+	cached = routeCache.lookup(bob, 100_001)
+	require.NotNil(t, cached)
+	require.Equal(t, []route.Vertex{carol, bob}, cached.hops)
+
+	require.Nil(t, routeCache.lookup(bob, 10_000))
+	require.Nil(t, routeCache.lookup(alice, 1000))
+
+	// A failure in the opposite direction doesn't affect the routes.
+	routeCache.InvalidatePair(bob, alice)
+	require.NotNil(t, routeCache.lookup(bob, 1000))
+
+	// A failure of our own channel to alice invalidates the route through
+	// alice, but not the one through carol.
+	routeCache.InvalidatePair(source, alice)
+	require.Nil(t, routeCache.lookup(bob, 1000))
+	require.NotNil(t, routeCache.lookup(bob, 100_000))
+
+	// Routes into blinded paths aren't cached.
+	blindedRoute := newTestRoute(1000, alice, carol)
+	blindedRoute.Hops[1].EncryptedData = []byte{1}
+	routeCache.AddRoute(blindedRoute)
+	require.Nil(t, routeCache.lookup(carol, 1000))
+}
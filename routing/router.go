@@ -101,6 +101,10 @@ var (
 	// ErrSkipTempErr is returned when a non-MPP is made yet the
 	// skipTempErr flag is set.
 	ErrSkipTempErr = errors.New("cannot skip temp error for non-MPP")
+
+	// ErrInvalidHopCustomRecords is returned when the custom records that
+	// are addressed to intermediate hops of a payment are invalid.
+	ErrInvalidHopCustomRecords = errors.New("invalid hop custom records")
 )
 
 // PaymentAttemptDispatcher is used by the router to send payment attempts onto
@@ -165,6 +169,13 @@ type PaymentSessionSource interface {
 	// to missioncontrol for resumed payment we don't want to make more
 	// attempts for.
 	NewPaymentSessionEmpty() PaymentSession
+
+	// EstimatePayment simulates the given payment at the given block
+	// height without sending it, and returns the routes that its shards
+	// would take.
+	EstimatePayment(p *LightningPayment, firstHopBlob fn.Option[tlv.Blob],
+		trafficShaper fn.Option[TlvTrafficShaper],
+		height uint32) (*PaymentEstimate, error)
 }
 
 // MissionControlQuerier is an interface that exposes failure reporting and
@@ -298,6 +309,18 @@ type Config struct {
 	// TrafficShaper is an optional traffic shaper that can be used to
 	// control the outgoing channel of a payment.
 	TrafficShaper fn.Option[TlvTrafficShaper]
+
+	// EnableTrampoline indicates whether payments may be delegated to a
+	// trampoline node.
+	EnableTrampoline bool
+
+	// RouteCache is an optional cache of recently successful routes. The
+	// routes of successful payment attempts are added to it.
+	RouteCache *RouteCache
+
+	// Landmarks optionally provides distance estimates that speed up path
+	// finding.
+	Landmarks *LandmarkHeuristic
 }
 
 // EdgeLocator is a struct used to identify a specific edge.
@@ -538,23 +561,35 @@ func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 		return nil, 0, err
 	}
 
-	// Now that we know the destination is reachable within the graph, we'll
-	// execute our path finding algorithm.
-	finalHtlcExpiry := currentHeight + int32(req.FinalExpiry)
-
 	// Validate time preference.
 	timePref := req.TimePreference
 	if timePref < -1 || timePref > 1 {
 		return nil, 0, errors.New("time preference out of range")
 	}
 
+	return r.findRoute(
+		req, req.Restrictions, bandwidthHints, uint32(currentHeight),
+	)
+}
+
+// findRoute finds a route for the given request using the passed restrictions
+// and bandwidth hints, and returns it along with its success probability.
+func (r *ChannelRouter) findRoute(req *RouteRequest,
+	restrictions *RestrictParams, bandwidthHints bandwidthHints,
+	currentHeight uint32) (*route.Route, float64, error) {
+
+	// Now that we know the destination is reachable within the graph, we'll
+	// execute our path finding algorithm.
+	finalHtlcExpiry := int32(currentHeight) + int32(req.FinalExpiry)
+
 	path, probability, err := findPath(
 		&graphParams{
 			additionalEdges: req.RouteHints,
 			bandwidthHints:  bandwidthHints,
 			graph:           r.cfg.RoutingGraph,
+			landmarks:       r.cfg.Landmarks,
 		},
-		req.Restrictions, &r.cfg.PathFindingConfig,
+		restrictions, &r.cfg.PathFindingConfig,
 		r.cfg.SelfNode, req.Source, req.Target, req.Amount,
 		req.TimePreference, finalHtlcExpiry,
 	)
@@ -564,7 +599,7 @@ func (r *ChannelRouter) FindRoute(req *RouteRequest) (*route.Route, float64,
 
 	// Create the route with absolute time lock values.
 	route, err := newRoute(
-		req.Source, path, uint32(currentHeight),
+		req.Source, path, currentHeight,
 		finalHopParams{
 			amt:       req.Amount,
 			totalAmt:  req.Amount,
@@ -806,6 +841,10 @@ type LightningPayment struct {
 	// complete this payment.
 	CltvLimit uint32
 
+	// CltvBudget optionally limits the time lock delta that individual
+	// hops of the routes that are attempted may charge.
+	CltvBudget *CltvBudget
+
 	// paymentHash is the r-hash value to use within the HTLC extended to
 	// the first hop. This won't be set for AMP payments.
 	paymentHash *lntypes.Hash
@@ -874,6 +913,12 @@ type LightningPayment struct {
 	// fail.
 	DestCustomRecords record.CustomSet
 
+	// HopCustomRecords are TLV records that are to be sent to specific
+	// intermediate hops in their onion payload, keyed by the node that
+	// they are addressed to. Records for nodes that don't end up on the
+	// route of an attempt are not sent.
+	HopCustomRecords map[route.Vertex]record.CustomSet
+
 	// FirstHopCustomRecords are the TLV records that are to be sent to the
 	// first hop of this payment. These records will be transmitted via the
 	// wire message and therefore do not affect the onion payload size.
@@ -899,6 +944,14 @@ type LightningPayment struct {
 	// Metadata is additional data that is sent along with the payment to
 	// the payee.
 	Metadata []byte
+
+	// Trampoline is an optional set of options that, if set, delegates
+	// finding the route to the destination to a trampoline node.
+	Trampoline *TrampolineOptions
+
+	// Budget is an optional set of additional limits that are enforced
+	// while the payment is in flight.
+	Budget *PaymentBudget
 }
 
 // AMPOptions houses information that must be known in order to send an AMP
@@ -930,6 +983,31 @@ func (l *LightningPayment) SetAMP(amp *AMPOptions) error {
 	return nil
 }
 
+// validateHopCustomRecords checks that the custom records addressed to
+// intermediate hops are valid. Records can't be addressed to our own node, as
+// it doesn't read a payload from the onion, nor to the destination, which
+// receives its records through DestCustomRecords.
+func (l *LightningPayment) validateHopCustomRecords(self route.Vertex) error {
+	for node, records := range l.HopCustomRecords {
+		switch node {
+		case self:
+			return errors.New("custom records can't be " +
+				"addressed to our own node")
+
+		case l.Target:
+			return errors.New("custom records for the " +
+				"destination must be set as destination " +
+				"custom records")
+		}
+
+		if err := records.Validate(); err != nil {
+			return fmt.Errorf("hop %v: %w", node, err)
+		}
+	}
+
+	return nil
+}
+
 // Identifier returns a 32-byte slice that uniquely identifies this single
 // payment. For non-AMP payments this will be the payment hash, for AMP
 // payments this will be the used SetID.
@@ -962,7 +1040,7 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte,
 	return r.sendPayment(
 		context.Background(), payment.FeeLimit, payment.Identifier(),
 		payment.PayAttemptTimeout, paySession, shardTracker,
-		payment.FirstHopCustomRecords,
+		payment.FirstHopCustomRecords, payment.Budget,
 	)
 }
 
@@ -983,7 +1061,7 @@ func (r *ChannelRouter) SendPaymentAsync(ctx context.Context,
 		_, _, err := r.sendPayment(
 			ctx, payment.FeeLimit, payment.Identifier(),
 			payment.PayAttemptTimeout, ps, st,
-			payment.FirstHopCustomRecords,
+			payment.FirstHopCustomRecords, payment.Budget,
 		)
 		if err != nil {
 			log.Errorf("Payment %x failed: %v",
@@ -1018,21 +1096,9 @@ func spewPayment(payment *LightningPayment) lnutils.LogClosure {
 func (r *ChannelRouter) PreparePayment(payment *LightningPayment) (
 	PaymentSession, shards.ShardTracker, error) {
 
-	// Assemble any custom data we want to send to the first hop only.
-	var firstHopData fn.Option[tlv.Blob]
-	if len(payment.FirstHopCustomRecords) > 0 {
-		if err := payment.FirstHopCustomRecords.Validate(); err != nil {
-			return nil, nil, fmt.Errorf("invalid first hop custom "+
-				"records: %w", err)
-		}
-
-		firstHopBlob, err := payment.FirstHopCustomRecords.Serialize()
-		if err != nil {
-			return nil, nil, fmt.Errorf("unable to serialize "+
-				"first hop custom records: %w", err)
-		}
-
-		firstHopData = fn.Some(firstHopBlob)
+	firstHopData, err := r.preparePaymentIntent(payment)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Before starting the HTLC routing attempt, we'll create a fresh
@@ -1085,6 +1151,69 @@ func (r *ChannelRouter) PreparePayment(payment *LightningPayment) (
 	return paySession, shardTracker, nil
 }
 
+// preparePaymentIntent validates the custom records of the payment and
+// rewrites it if it is delegated to a trampoline node. It returns the custom
+// data that is sent to the first hop only.
+func (r *ChannelRouter) preparePaymentIntent(
+	payment *LightningPayment) (fn.Option[tlv.Blob], error) {
+
+	// Assemble any custom data we want to send to the first hop only.
+	var firstHopData fn.Option[tlv.Blob]
+	if len(payment.FirstHopCustomRecords) > 0 {
+		if err := payment.FirstHopCustomRecords.Validate(); err != nil {
+			return firstHopData, fmt.Errorf("invalid first hop "+
+				"custom records: %w", err)
+		}
+
+		firstHopBlob, err := payment.FirstHopCustomRecords.Serialize()
+		if err != nil {
+			return firstHopData, fmt.Errorf("unable to serialize "+
+				"first hop custom records: %w", err)
+		}
+
+		firstHopData = fn.Some(firstHopBlob)
+	}
+
+	err := payment.validateHopCustomRecords(r.cfg.SelfNode)
+	if err != nil {
+		return firstHopData, fmt.Errorf("%w: %w",
+			ErrInvalidHopCustomRecords, err)
+	}
+
+	// If the payment is delegated to a trampoline node, we'll rewrite it
+	// so that we only need to find a route to the trampoline node.
+	if payment.Trampoline != nil {
+		if err := r.prepareTrampoline(payment); err != nil {
+			return firstHopData, err
+		}
+	}
+
+	return firstHopData, nil
+}
+
+// EstimatePayment simulates the given payment without sending it. The
+// simulation runs path finding against the current mission control state and
+// splits the payment into shards the same way a real payment would, which
+// allows wallets to preview the fees and success probability of a payment.
+func (r *ChannelRouter) EstimatePayment(
+	payment *LightningPayment) (*PaymentEstimate, error) {
+
+	firstHopData, err := r.preparePaymentIntent(payment)
+	if err != nil {
+		return nil, err
+	}
+
+	_, currentHeight, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.cfg.SessionSource.EstimatePayment(
+		payment, firstHopData, r.cfg.TrafficShaper,
+		uint32(currentHeight),
+	)
+}
+
 // SendToRoute sends a payment using the provided route and fails the payment
 // when an error is returned from the attempt.
 func (r *ChannelRouter) SendToRoute(htlcHash lntypes.Hash, rt *route.Route,
@@ -1291,8 +1420,8 @@ func (r *ChannelRouter) sendPayment(ctx context.Context,
 	feeLimit lnwire.MilliSatoshi, identifier lntypes.Hash,
 	paymentAttemptTimeout time.Duration, paySession PaymentSession,
 	shardTracker shards.ShardTracker,
-	firstHopCustomRecords lnwire.CustomRecords) ([32]byte, *route.Route,
-	error) {
+	firstHopCustomRecords lnwire.CustomRecords,
+	budget *PaymentBudget) ([32]byte, *route.Route, error) {
 
 	// If the user provides a timeout, we will additionally wrap the context
 	// in a deadline.
@@ -1324,6 +1453,24 @@ func (r *ChannelRouter) sendPayment(ctx context.Context,
 		r, feeLimit, identifier, paySession, shardTracker,
 		currentHeight, firstHopCustomRecords,
 	)
+	p.budget = budget
+
+	// If the budget limits the fee to a percentage of the amount, we'll
+	// lower the fee limit accordingly.
+	if budget != nil && budget.MaxFeePercent > 0 {
+		payment, err := r.cfg.Control.FetchPayment(identifier)
+		if err != nil {
+			return [32]byte{}, nil, err
+		}
+
+		amt := payment.GetState().RemainingAmt
+		budget.feeLimit(amt).WhenSome(func(limit lnwire.MilliSatoshi) {
+			if limit < p.feeLimit {
+				p.feeLimit = limit
+				p.feeLimitCapped = true
+			}
+		})
+	}
 
 	return p.resumePayment(ctx)
 }
@@ -1527,7 +1674,7 @@ func (r *ChannelRouter) resumePayments() error {
 		noTimeout := time.Duration(0)
 		_, _, err := r.sendPayment(
 			context.Background(), 0, payHash, noTimeout, paySession,
-			shardTracker, payment.Info.FirstHopCustomRecords,
+			shardTracker, payment.Info.FirstHopCustomRecords, nil,
 		)
 		if err != nil {
 			log.Errorf("Resuming payment %v failed: %v", payHash,
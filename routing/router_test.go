@@ -288,6 +288,133 @@ func TestFindRoutesWithFeeLimit(t *testing.T) {
 	)
 }
 
+// TestFindDiverseRoutes tests that multiple routes are returned that don't
+// share more than the allowed number of pairs, along with their cost
+// breakdown.
+func TestFindDiverseRoutes(t *testing.T) {
+	t.Parallel()
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxFromFile(t, startingBlockHeight, basicGraphFilePath)
+
+	// There are two routes from roasbeef to sophon that don't share any
+	// pair:
+	//	1. roasbeef -> songoku -> sophon
+	//	2. roasbeef -> phamnuwen -> sophon
+	target := ctx.aliases["sophon"]
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	restrictions := &RestrictParams{
+		FeeLimit:          lnwire.NewMSatFromSatoshis(1000),
+		ProbabilitySource: noProbabilitySource,
+		CltvLimit:         math.MaxUint32,
+	}
+
+	req, err := NewRouteRequest(
+		ctx.router.cfg.SelfNode, &target, paymentAmt, 0,
+		restrictions, nil, nil, nil, MinCLTVDelta,
+	)
+	require.NoError(t, err, "invalid route request")
+
+	routes, err := ctx.router.FindDiverseRoutes(req, 2, 0)
+	require.NoError(t, err, "unable to find any routes")
+	require.Len(t, routes, 2)
+
+	firstHops := []route.Vertex{
+		ctx.aliases["songoku"], ctx.aliases["phamnuwen"],
+	}
+	for i, diverseRoute := range routes {
+		rt := diverseRoute.Route
+		require.Len(t, rt.Hops, 2)
+		require.Equal(t, firstHops[i], rt.Hops[0].PubKeyBytes)
+
+		// The only forwarding hop charges all of the route's fees.
+		require.Len(t, diverseRoute.Costs, 1)
+		require.Equal(
+			t, rt.Hops[0].PubKeyBytes,
+			diverseRoute.Costs[0].PubKeyBytes,
+		)
+		require.Equal(t, rt.TotalFees(), diverseRoute.Costs[0].Fee)
+		delta := rt.Hops[0].OutgoingTimeLock -
+			rt.Hops[1].OutgoingTimeLock
+		require.Equal(t, delta, diverseRoute.Costs[0].TimeLockDelta)
+	}
+}
+
+// TestEstimatePayment tests that a simulated payment is split into shards
+// when no single route can carry the full amount, and that the liquidity used
+// by earlier shards isn't available to later ones.
+func TestEstimatePayment(t *testing.T) {
+	t.Parallel()
+
+	const chanCapSat = btcutil.Amount(100000)
+	localPolicy := &testChannelPolicy{
+		Expiry:  144,
+		MinHTLC: 1,
+		MaxHTLC: lnwire.NewMSatFromSatoshis(chanCapSat),
+	}
+	remotePolicy := &testChannelPolicy{
+		Expiry:      144,
+		FeeBaseMsat: 1000,
+		MinHTLC:     1,
+		MaxHTLC:     lnwire.NewMSatFromSatoshis(10 * chanCapSat),
+	}
+
+	// Both local channels can only carry part of the payment.
+	testChannels := []*testChannel{
+		symmetricTestChannel("a", "b", chanCapSat, localPolicy, 1),
+		symmetricTestChannel("a", "c", chanCapSat, localPolicy, 2),
+		symmetricTestChannel("b", "d", 10*chanCapSat, remotePolicy, 3),
+		symmetricTestChannel("c", "d", 10*chanCapSat, remotePolicy, 4),
+	}
+
+	testGraph, err := createTestGraphFromChannels(
+		t, true, testChannels, "a",
+	)
+	require.NoError(t, err, "unable to create graph")
+
+	const startingBlockHeight = 101
+	ctx := createTestCtxFromGraphInstance(t, startingBlockHeight, testGraph)
+
+	payment := &LightningPayment{
+		Target:         ctx.aliases["d"],
+		Amount:         lnwire.NewMSatFromSatoshis(chanCapSat * 3 / 2),
+		FeeLimit:       noFeeLimit,
+		CltvLimit:      math.MaxUint32,
+		FinalCLTVDelta: MinCLTVDelta,
+		MaxParts:       16,
+		PaymentAddr:    fn.Some([32]byte{1}),
+		DestFeatures: lnwire.NewFeatureVector(
+			mppFeatures, lnwire.Features,
+		),
+	}
+	require.NoError(t, payment.SetPaymentHash(lntypes.Hash{1}))
+
+	estimate, err := ctx.router.EstimatePayment(payment)
+	require.NoError(t, err)
+
+	// The payment is split in half, and the second shard has to use the
+	// other local channel.
+	require.Len(t, estimate.Routes, 2)
+	require.NotEqual(
+		t, estimate.Routes[0].Hops[0].ChannelID,
+		estimate.Routes[1].Hops[0].ChannelID,
+	)
+
+	var received lnwire.MilliSatoshi
+	for _, rt := range estimate.Routes {
+		received += rt.ReceiverAmt()
+	}
+	require.Equal(t, payment.Amount, received)
+
+	// Every shard pays the base fee of the intermediate hop.
+	require.Equal(t, lnwire.MilliSatoshi(2000), estimate.TotalFees)
+	require.Equal(
+		t, estimate.Routes[0].TotalTimeLock, estimate.TotalTimeLock,
+	)
+	require.Greater(t, estimate.Probability, 0.0)
+	require.LessOrEqual(t, estimate.Probability, 1.0)
+}
+
 // TestSendPaymentRouteFailureFallback tests that when sending a payment, if
 // one of the target routes is seen as unavailable, then the next route in the
 // queue is used instead. This process should continue until either a payment
@@ -1897,7 +2024,7 @@ func TestSenderAmtBackwardPass(t *testing.T) {
 
 			return nil, nil
 		},
-		localChans: make(map[lnwire.ShortChannelID]struct{}),
+		localChans: make(map[lnwire.ShortChannelID]route.Vertex),
 	}
 
 	var (
@@ -3297,3 +3424,73 @@ func TestFindBlindedPathsWithMC(t *testing.T) {
 		"alice,bob,dave",
 	})
 }
+
+// TestValidateHopCustomRecords asserts that custom records for intermediate
+// hops are validated.
+func TestValidateHopCustomRecords(t *testing.T) {
+	t.Parallel()
+
+	var (
+		self   = route.Vertex{1}
+		hop    = route.Vertex{2}
+		target = route.Vertex{3}
+	)
+
+	validRecords := record.CustomSet{record.CustomTypeStart: []byte{1}}
+
+	testCases := []struct {
+		name    string
+		records map[route.Vertex]record.CustomSet
+		valid   bool
+	}{
+		{
+			name:  "no records",
+			valid: true,
+		},
+		{
+			name: "intermediate hop",
+			records: map[route.Vertex]record.CustomSet{
+				hop: validRecords,
+			},
+			valid: true,
+		},
+		{
+			name: "own node",
+			records: map[route.Vertex]record.CustomSet{
+				self: validRecords,
+			},
+		},
+		{
+			name: "destination",
+			records: map[route.Vertex]record.CustomSet{
+				target: validRecords,
+			},
+		},
+		{
+			name: "reserved type",
+			records: map[route.Vertex]record.CustomSet{
+				hop: {1: []byte{1}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			payment := &LightningPayment{
+				Target:           target,
+				HopCustomRecords: tc.records,
+			}
+
+			err := payment.validateHopCustomRecords(self)
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package routing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// TrampolineOnionPayloadSize is the size of the routing info of a
+	// trampoline onion packet. It is considerably smaller than the regular
+	// onion so that it fits into the payload of the outer onion.
+	TrampolineOnionPayloadSize = 400
+
+	// trampolineOnionVersion is the version of the trampoline onion
+	// packet.
+	trampolineOnionVersion = 0
+)
+
+var (
+	// ErrTrampolineDisabled is returned when a trampoline payment is
+	// requested while trampoline payments are disabled.
+	ErrTrampolineDisabled = errors.New("trampoline payments are disabled")
+
+	// ErrTrampolineNotSupported is returned when the selected trampoline
+	// node doesn't signal support for trampoline routing.
+	ErrTrampolineNotSupported = errors.New("trampoline node doesn't " +
+		"support trampoline routing")
+
+	// ErrTrampolinePayloadTooLarge is returned when the trampoline
+	// payloads don't fit into a trampoline onion packet.
+	ErrTrampolinePayloadTooLarge = errors.New("trampoline payload " +
+		"exceeds onion size")
+)
+
+// TrampolineOptions describes how a payment is delegated to a trampoline node.
+// Instead of finding a route to the final destination, we only find a route
+// to the trampoline node, which then finds the route for the last mile on our
+// behalf. This allows nodes with a partial view of the graph to pay any
+// destination.
+type TrampolineOptions struct {
+	// Node is the trampoline node the last mile of the payment is
+	// delegated to.
+	Node route.Vertex
+
+	// Fee is the fee that is allotted to the trampoline node for finding
+	// and paying the route to the destination. It is deducted from the
+	// payment's fee limit.
+	Fee lnwire.MilliSatoshi
+
+	// CltvDelta is the CLTV delta that is allotted to the trampoline node
+	// for the route to the destination.
+	CltvDelta uint16
+}
+
+// trampolineHop is a single hop within a trampoline onion.
+type trampolineHop struct {
+	// pubKey is the public key of the node that processes this hop.
+	pubKey *btcec.PublicKey
+
+	// payload is the serialized TLV payload for this hop.
+	payload []byte
+}
+
+// prepareTrampoline rewrites the payment to be delegated to the trampoline
+// node given in the payment's trampoline options, after checking that
+// trampoline payments are enabled and the trampoline node supports them.
+func (r *ChannelRouter) prepareTrampoline(payment *LightningPayment) error {
+	if !r.cfg.EnableTrampoline {
+		return ErrTrampolineDisabled
+	}
+
+	features, err := r.cfg.RoutingGraph.FetchNodeFeatures(
+		payment.Trampoline.Node,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, height, err := r.cfg.Chain.GetBestBlock()
+	if err != nil {
+		return err
+	}
+
+	return prepareTrampolinePayment(payment, features, uint32(height))
+}
+
+// prepareTrampolinePayment rewrites the given payment so that it is sent to
+// the trampoline node, with the trampoline onion for the original destination
+// included in the payload of the final hop. The height is used to compute the
+// absolute expiry of the HTLC the trampoline node should send to the
+// destination.
+func prepareTrampolinePayment(payment *LightningPayment,
+	trampolineFeatures *lnwire.FeatureVector, height uint32) error {
+
+	opts := payment.Trampoline
+
+	switch {
+	case !trampolineFeatures.HasFeature(lnwire.TrampolineRoutingOptional):
+		return ErrTrampolineNotSupported
+
+	case payment.amp != nil:
+		return errors.New("amp is not supported for trampoline " +
+			"payments")
+
+	case payment.BlindedPathSet != nil:
+		return errors.New("blinded paths are not supported for " +
+			"trampoline payments")
+
+	case len(payment.DestCustomRecords) > 0,
+		len(payment.HopCustomRecords) > 0:
+
+		return errors.New("custom records are not supported for " +
+			"trampoline payments")
+
+	case payment.FeeLimit < opts.Fee:
+		return fmt.Errorf("fee limit %v is lower than trampoline fee "+
+			"%v", payment.FeeLimit, opts.Fee)
+	}
+
+	finalExpiry := height + uint32(payment.FinalCLTVDelta)
+	payload, err := trampolineLegacyPayload(payment, finalExpiry)
+	if err != nil {
+		return err
+	}
+
+	pubKey, err := btcec.ParsePubKey(opts.Node[:])
+	if err != nil {
+		return err
+	}
+
+	sessionKey, err := generateNewSessionKey()
+	if err != nil {
+		return err
+	}
+
+	onion, err := newTrampolineOnion(
+		sessionKey, []trampolineHop{{pubKey: pubKey, payload: payload}},
+		payment.paymentHash[:],
+	)
+	if err != nil {
+		return err
+	}
+
+	// The trampoline node requires its own payment secret to accept the
+	// (possibly multi-part) payment sent to it.
+	var trampolineSecret [32]byte
+	if _, err := rand.Read(trampolineSecret[:]); err != nil {
+		return err
+	}
+
+	payment.Target = opts.Node
+	payment.Amount += opts.Fee
+	payment.FeeLimit -= opts.Fee
+	payment.FinalCLTVDelta += opts.CltvDelta
+	payment.PaymentAddr = fn.Some(trampolineSecret)
+	payment.DestFeatures = trampolineFeatures
+	payment.RouteHints = nil
+	payment.LastHop = nil
+	payment.Metadata = nil
+	payment.DestCustomRecords = record.CustomSet{
+		uint64(record.TrampolineOnionType): onion,
+	}
+
+	return nil
+}
+
+// trampolineLegacyPayload creates the trampoline payload for the last
+// trampoline node when the destination itself doesn't support trampoline. In
+// this case the invoice details of the destination are passed on, so that the
+// trampoline node can pay the destination with a regular payment.
+func trampolineLegacyPayload(payment *LightningPayment,
+	finalExpiry uint32) ([]byte, error) {
+
+	amt := uint64(payment.Amount)
+	nodeID := [33]byte(payment.Target)
+
+	records := []tlv.Record{
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&finalExpiry),
+		record.NewOutgoingNodeIDRecord(&nodeID),
+	}
+
+	payment.PaymentAddr.WhenSome(func(addr [32]byte) {
+		mpp := record.NewMPP(payment.Amount, addr)
+		records = append(records, mpp.Record())
+	})
+
+	if len(payment.Metadata) > 0 {
+		records = append(
+			records, record.NewMetadataRecord(&payment.Metadata),
+		)
+	}
+
+	var features []byte
+	if payment.DestFeatures != nil {
+		var b bytes.Buffer
+		err := payment.DestFeatures.RawFeatureVector.Encode(&b)
+		if err != nil {
+			return nil, err
+		}
+		features = b.Bytes()
+
+		records = append(
+			records, record.NewInvoiceFeaturesRecord(&features),
+		)
+	}
+
+	var routingInfo []byte
+	if len(payment.RouteHints) > 0 {
+		var b bytes.Buffer
+		err := encodeTrampolineRoutingInfo(&b, payment.RouteHints)
+		if err != nil {
+			return nil, err
+		}
+		routingInfo = b.Bytes()
+
+		records = append(
+			records, record.NewInvoiceRoutingInfoRecord(
+				&routingInfo,
+			),
+		)
+	}
+
+	tlv.SortRecords(records)
+
+	stream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := stream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// encodeTrampolineRoutingInfo serializes the invoice's route hints. Every
+// route is prefixed by its number of hops, followed by the node id, channel
+// id, base fee, proportional fee and cltv delta of each hop.
+func encodeTrampolineRoutingInfo(w io.Writer,
+	routeHints [][]zpay32.HopHint) error {
+
+	for _, hints := range routeHints {
+		if len(hints) > 255 {
+			return fmt.Errorf("too many hops in route hint: %v",
+				len(hints))
+		}
+
+		if _, err := w.Write([]byte{uint8(len(hints))}); err != nil {
+			return err
+		}
+
+		for _, hint := range hints {
+			var b [33 + 8 + 4 + 4 + 2]byte
+			copy(b[:33], hint.NodeID.SerializeCompressed())
+			binary.BigEndian.PutUint64(b[33:41], hint.ChannelID)
+			binary.BigEndian.PutUint32(b[41:45], hint.FeeBaseMSat)
+			binary.BigEndian.PutUint32(
+				b[45:49], hint.FeeProportionalMillionths,
+			)
+			binary.BigEndian.PutUint16(
+				b[49:51], hint.CLTVExpiryDelta,
+			)
+
+			if _, err := w.Write(b[:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// newTrampolineOnion constructs a trampoline onion packet for the given hops.
+// The payment hash is used as associated data.
+func newTrampolineOnion(sessionKey *btcec.PrivateKey, hops []trampolineHop,
+	assocData []byte) ([]byte, error) {
+
+	return newOnionPacket(
+		sessionKey, hops, assocData, TrampolineOnionPayloadSize,
+	)
+}
+
+// newOnionPacket constructs a sphinx onion packet as specified in BOLT 04 for
+// the given hops, with routing info of the given size. The hop payloads and
+// the padding of the routing info are created by lightning-onion, which only
+// supports the routing info size of the regular onion and therefore can't
+// create the packet itself.
+func newOnionPacket(sessionKey *btcec.PrivateKey, hops []trampolineHop,
+	assocData []byte, routingInfoSize int) ([]byte, error) {
+
+	switch {
+	case len(hops) == 0:
+		return nil, errors.New("onion requires at least one hop")
+
+	case routingInfoSize > sphinx.MaxPayloadSize:
+		return nil, fmt.Errorf("routing info size %v exceeds %v",
+			routingInfoSize, sphinx.MaxPayloadSize)
+	}
+
+	hopPayloads := make([]sphinx.HopPayload, len(hops))
+	totalSize := 0
+	for i, hop := range hops {
+		hopPayload, err := sphinx.NewTLVHopPayload(hop.payload)
+		if err != nil {
+			return nil, err
+		}
+
+		hopPayloads[i] = hopPayload
+		totalSize += hopPayload.NumBytes()
+	}
+	if totalSize > routingInfoSize {
+		return nil, ErrTrampolinePayloadTooLarge
+	}
+
+	// Derive the ephemeral keys and shared secrets for all hops.
+	pubKeys := make([]*btcec.PublicKey, len(hops))
+	for i, hop := range hops {
+		pubKeys[i] = hop.pubKey
+	}
+	ephemeralKeys, sharedSecrets, err := onionSharedSecrets(
+		sessionKey, pubKeys,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute the filler that is appended to the routing info of the
+	// final hop, such that the HMACs of all hops remain valid while the
+	// routing info is shifted at every hop.
+	var filler []byte
+	for i := 0; i < len(hops)-1; i++ {
+		hopSize := hopPayloads[i].NumBytes()
+		filler = append(filler, make([]byte, hopSize)...)
+
+		stream := onionCipherStream(
+			onionKey("rho", sharedSecrets[i]),
+			routingInfoSize+hopSize,
+		)
+		xorBytes(filler, stream[routingInfoSize+hopSize-len(filler):])
+	}
+
+	// The routing info is initialized with the deterministic padding
+	// derived from the session key. The padding is a key stream, so the
+	// prefix of the regular sized padding is the padding of smaller
+	// routing info.
+	var padding [sphinx.MaxPayloadSize]byte
+	err = sphinx.DeterministicPacketFiller(sessionKey, &padding)
+	if err != nil {
+		return nil, err
+	}
+	mixHeader := padding[:routingInfoSize]
+
+	var nextHMAC [sphinx.HMACSize]byte
+	for i := len(hops) - 1; i >= 0; i-- {
+		// Shift the routing info to the right and prepend this hop's
+		// payload along with the HMAC of the next hop.
+		hopPayloads[i].HMAC = nextHMAC
+
+		var b bytes.Buffer
+		if err := hopPayloads[i].Encode(&b); err != nil {
+			return nil, err
+		}
+
+		copy(mixHeader[b.Len():], mixHeader[:routingInfoSize-b.Len()])
+		copy(mixHeader, b.Bytes())
+
+		stream := onionCipherStream(
+			onionKey("rho", sharedSecrets[i]), routingInfoSize,
+		)
+		xorBytes(mixHeader, stream)
+
+		if i == len(hops)-1 {
+			copy(mixHeader[routingInfoSize-len(filler):], filler)
+		}
+
+		mac := hmac.New(
+			sha256.New, onionKey("mu", sharedSecrets[i]),
+		)
+		mac.Write(mixHeader)
+		mac.Write(assocData)
+		copy(nextHMAC[:], mac.Sum(nil))
+	}
+
+	var packet bytes.Buffer
+	packet.WriteByte(trampolineOnionVersion)
+	packet.Write(ephemeralKeys[0].SerializeCompressed())
+	packet.Write(mixHeader)
+	packet.Write(nextHMAC[:])
+
+	return packet.Bytes(), nil
+}
@@ -0,0 +1,359 @@
+package routing
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
+)
+
+// onionTestVectorFilePath is the path of the BOLT 04 onion test vector.
+const onionTestVectorFilePath = "testdata/onion-test.json"
+
+// peelTrampolineOnion processes the trampoline onion as the node with the
+// given private key would. It returns the node's payload and the packet for
+// the next hop, which is nil if this node is the final hop.
+func peelTrampolineOnion(t *testing.T, priv *btcec.PrivateKey,
+	packet, assocData []byte) ([]byte, []byte) {
+
+	const payloadSize = TrampolineOnionPayloadSize
+
+	require.Len(t, packet, 1+33+payloadSize+sphinx.HMACSize)
+	require.EqualValues(t, trampolineOnionVersion, packet[0])
+
+	ephemeralPub, err := btcec.ParsePubKey(packet[1:34])
+	require.NoError(t, err)
+
+	routingInfo := packet[34 : 34+payloadSize]
+	packetHMAC := packet[34+payloadSize:]
+
+	ecdh := &keychain.PrivKeyECDH{PrivKey: priv}
+	secret, err := ecdh.ECDH(ephemeralPub)
+	require.NoError(t, err)
+
+	// Verify the HMAC of the packet.
+	mac := hmac.New(sha256.New, onionKey("mu", secret[:]))
+	mac.Write(routingInfo)
+	mac.Write(assocData)
+	require.Equal(t, mac.Sum(nil), packetHMAC)
+
+	// Decrypt the routing info, extended by zeroes.
+	decrypted := make([]byte, 2*payloadSize)
+	copy(decrypted, routingInfo)
+	xorBytes(
+		decrypted,
+		onionCipherStream(
+			onionKey("rho", secret[:]), 2*payloadSize,
+		),
+	)
+
+	r := bytes.NewReader(decrypted)
+	var buf [8]byte
+	payloadLen, err := tlv.ReadVarInt(r, &buf)
+	require.NoError(t, err)
+
+	payload := make([]byte, payloadLen)
+	_, err = r.Read(payload)
+	require.NoError(t, err)
+
+	var nextHMAC [sphinx.HMACSize]byte
+	_, err = r.Read(nextHMAC[:])
+	require.NoError(t, err)
+
+	if nextHMAC == [sphinx.HMACSize]byte{} {
+		return payload, nil
+	}
+
+	// Blind the ephemeral key for the next hop.
+	blindingFactor := sha256.Sum256(append(
+		ephemeralPub.SerializeCompressed(), secret[:]...,
+	))
+	var (
+		blinding btcec.ModNScalar
+		point    btcec.JacobianPoint
+		result   btcec.JacobianPoint
+	)
+	blinding.SetBytes(&blindingFactor)
+	ephemeralPub.AsJacobian(&point)
+	btcec.ScalarMultNonConst(&blinding, &point, &result)
+	result.ToAffine()
+	nextPub := btcec.NewPublicKey(&result.X, &result.Y)
+
+	// The routing info for the next hop starts right after our payload.
+	offset := len(decrypted) - r.Len()
+
+	var next bytes.Buffer
+	next.WriteByte(trampolineOnionVersion)
+	next.Write(nextPub.SerializeCompressed())
+	next.Write(decrypted[offset : offset+payloadSize])
+	next.Write(nextHMAC[:])
+
+	return payload, next.Bytes()
+}
+
+// TestTrampolineOnion asserts that a multi hop trampoline onion can be peeled
+// by each of the hops, revealing their respective payloads.
+func TestTrampolineOnion(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 3
+
+	privs := make([]*btcec.PrivateKey, numHops)
+	hops := make([]trampolineHop, numHops)
+	for i := range hops {
+		priv, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		privs[i] = priv
+		hops[i] = trampolineHop{
+			pubKey:  priv.PubKey(),
+			payload: bytes.Repeat([]byte{byte(i + 1)}, 20+i*10),
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	assocData := bytes.Repeat([]byte{0xaa}, 32)
+	packet, err := newTrampolineOnion(sessionKey, hops, assocData)
+	require.NoError(t, err)
+
+	for i := range hops {
+		var payload []byte
+		payload, packet = peelTrampolineOnion(
+			t, privs[i], packet, assocData,
+		)
+		require.Equal(t, hops[i].payload, payload)
+
+		if i == numHops-1 {
+			require.Nil(t, packet)
+		} else {
+			require.NotNil(t, packet)
+		}
+	}
+
+	// Payloads that exceed the onion size are rejected.
+	hops[0].payload = make([]byte, TrampolineOnionPayloadSize)
+	_, err = newTrampolineOnion(sessionKey, hops, assocData)
+	require.ErrorIs(t, err, ErrTrampolinePayloadTooLarge)
+}
+
+// onionTestVector is the BOLT 04 test vector for the construction of an onion
+// with variable length hop payloads.
+type onionTestVector struct {
+	Generate struct {
+		SessionKey     string `json:"session_key"`
+		AssociatedData string `json:"associated_data"`
+		Hops           []struct {
+			PubKey  string `json:"pubkey"`
+			Payload string `json:"payload"`
+		} `json:"hops"`
+	} `json:"generate"`
+
+	Onion string `json:"onion"`
+}
+
+// TestOnionPacketTestVector asserts that an onion with the routing info size
+// of the regular onion matches the BOLT 04 test vector.
+func TestOnionPacketTestVector(t *testing.T) {
+	t.Parallel()
+
+	vectorBytes, err := os.ReadFile(onionTestVectorFilePath)
+	require.NoError(t, err)
+
+	var vector onionTestVector
+	require.NoError(t, json.Unmarshal(vectorBytes, &vector))
+
+	sessionKeyBytes, err := hex.DecodeString(vector.Generate.SessionKey)
+	require.NoError(t, err)
+	sessionKey, _ := btcec.PrivKeyFromBytes(sessionKeyBytes)
+
+	assocData, err := hex.DecodeString(vector.Generate.AssociatedData)
+	require.NoError(t, err)
+
+	hops := make([]trampolineHop, len(vector.Generate.Hops))
+	for i, hop := range vector.Generate.Hops {
+		pubKeyBytes, err := hex.DecodeString(hop.PubKey)
+		require.NoError(t, err)
+		pubKey, err := btcec.ParsePubKey(pubKeyBytes)
+		require.NoError(t, err)
+
+		// The payloads of the test vector are prefixed with their
+		// length.
+		payload, err := hex.DecodeString(hop.Payload)
+		require.NoError(t, err)
+
+		r := bytes.NewReader(payload)
+		var buf [8]byte
+		payloadLen, err := tlv.ReadVarInt(r, &buf)
+		require.NoError(t, err)
+		require.EqualValues(t, r.Len(), payloadLen)
+
+		hops[i] = trampolineHop{
+			pubKey:  pubKey,
+			payload: payload[len(payload)-r.Len():],
+		}
+	}
+
+	packet, err := newOnionPacket(
+		sessionKey, hops, assocData, sphinx.MaxPayloadSize,
+	)
+	require.NoError(t, err)
+	require.Equal(t, vector.Onion, hex.EncodeToString(packet))
+}
+
+// TestOnionPacketSphinx asserts that an onion with the routing info size of
+// the regular onion is identical to the one that is created by lightning-onion
+// for random paths.
+func TestOnionPacketSphinx(t *testing.T) {
+	t.Parallel()
+
+	for numHops := 1; numHops <= 5; numHops++ {
+		sessionKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		var (
+			hops      = make([]trampolineHop, numHops)
+			path      sphinx.PaymentPath
+			assocData = bytes.Repeat([]byte{byte(numHops)}, 32)
+		)
+		for i := range hops {
+			priv, err := btcec.NewPrivateKey()
+			require.NoError(t, err)
+
+			payload := bytes.Repeat([]byte{byte(i)}, 30+i*40)
+			hops[i] = trampolineHop{
+				pubKey:  priv.PubKey(),
+				payload: payload,
+			}
+
+			hopPayload, err := sphinx.NewTLVHopPayload(payload)
+			require.NoError(t, err)
+
+			path[i] = sphinx.OnionHop{
+				NodePub:    *priv.PubKey(),
+				HopPayload: hopPayload,
+			}
+		}
+
+		sphinxPacket, err := sphinx.NewOnionPacket(
+			&path, sessionKey, assocData,
+			sphinx.DeterministicPacketFiller,
+		)
+		require.NoError(t, err)
+
+		var expected bytes.Buffer
+		require.NoError(t, sphinxPacket.Encode(&expected))
+
+		packet, err := newOnionPacket(
+			sessionKey, hops, assocData, sphinx.MaxPayloadSize,
+		)
+		require.NoError(t, err)
+		require.Equal(t, expected.Bytes(), packet)
+	}
+}
+
+// TestPrepareTrampolinePayment asserts that a payment is correctly rewritten
+// to be delegated to a trampoline node.
+func TestPrepareTrampolinePayment(t *testing.T) {
+	t.Parallel()
+
+	trampolinePriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	trampolineNode := route.NewVertex(trampolinePriv.PubKey())
+
+	dest := route.Vertex{2, 3}
+	hash := lntypes.Hash{1}
+	addr := [32]byte{9}
+
+	newPayment := func() *LightningPayment {
+		payment := &LightningPayment{
+			Target:         dest,
+			Amount:         100_000,
+			FeeLimit:       5_000,
+			FinalCLTVDelta: 40,
+			PaymentAddr:    fn.Some(addr),
+			Trampoline: &TrampolineOptions{
+				Node:      trampolineNode,
+				Fee:       2_000,
+				CltvDelta: 144,
+			},
+		}
+		require.NoError(t, payment.SetPaymentHash(hash))
+
+		return payment
+	}
+
+	// A trampoline node that doesn't signal support is rejected.
+	err = prepareTrampolinePayment(
+		newPayment(), lnwire.EmptyFeatureVector(), 100,
+	)
+	require.ErrorIs(t, err, ErrTrampolineNotSupported)
+
+	features := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.TrampolineRoutingOptional,
+			lnwire.TLVOnionPayloadRequired,
+			lnwire.PaymentAddrOptional,
+		), lnwire.Features,
+	)
+
+	// The fee limit must cover the trampoline fee.
+	payment := newPayment()
+	payment.FeeLimit = 1_000
+	err = prepareTrampolinePayment(payment, features, 100)
+	require.Error(t, err)
+
+	payment = newPayment()
+	require.NoError(t, prepareTrampolinePayment(payment, features, 100))
+
+	require.Equal(t, trampolineNode, payment.Target)
+	require.Equal(t, lnwire.MilliSatoshi(102_000), payment.Amount)
+	require.Equal(t, lnwire.MilliSatoshi(3_000), payment.FeeLimit)
+	require.Equal(t, uint16(184), payment.FinalCLTVDelta)
+	require.True(t, payment.PaymentAddr.IsSome())
+	require.NotEqual(t, addr, payment.PaymentAddr.UnwrapOr(addr))
+	require.Equal(t, features, payment.DestFeatures)
+
+	// The trampoline node should be able to decrypt its payload.
+	onion := payment.DestCustomRecords[uint64(record.TrampolineOnionType)]
+	payload, next := peelTrampolineOnion(t, trampolinePriv, onion, hash[:])
+	require.Nil(t, next)
+
+	var (
+		amt         uint64
+		expiry      uint32
+		outgoing    [33]byte
+		mpp         = &record.MPP{}
+		recordTypes = []tlv.Record{
+			record.NewAmtToFwdRecord(&amt),
+			record.NewLockTimeRecord(&expiry),
+			mpp.Record(),
+			record.NewOutgoingNodeIDRecord(&outgoing),
+		}
+	)
+	stream, err := tlv.NewStream(recordTypes...)
+	require.NoError(t, err)
+	_, err = stream.DecodeWithParsedTypes(bytes.NewReader(payload))
+	require.NoError(t, err)
+
+	require.Equal(t, uint64(100_000), amt)
+	require.Equal(t, uint32(140), expiry)
+	require.Equal(t, [33]byte(dest), outgoing)
+	require.Equal(t, addr, mpp.PaymentAddr())
+	require.Equal(t, lnwire.MilliSatoshi(100_000), mpp.TotalMsat())
+}
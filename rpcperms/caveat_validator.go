@@ -0,0 +1,357 @@
+package rpcperms
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/macaroons"
+	"google.golang.org/grpc"
+)
+
+// CaveatValidator enforces the condition of a custom macaroon caveat within
+// lnd itself. It is the in-process alternative to an RPC middleware that
+// handles a custom caveat, and allows operators to build least-privilege
+// macaroons (e.g. a maximum amount per day or a channel allowlist) without an
+// external proxy.
+type CaveatValidator interface {
+	// ValidateCaveat returns a non-nil error if a request to the given
+	// method isn't allowed by the condition of the custom caveat. The
+	// request is nil when a stream is being established, and is the
+	// received request message otherwise.
+	ValidateCaveat(ctx context.Context, fullMethod, condition string,
+		req interface{}) error
+}
+
+// CaveatReleaser is implemented by caveat validators that hold back resources
+// for the requests they accept, such as the balance reserved for a payment.
+// If an accepted request is rejected by another validator or a later
+// interceptor before it reaches its handler, ReleaseCaveat is called so that
+// the validator can release them.
+type CaveatReleaser interface {
+	// ReleaseCaveat releases what ValidateCaveat held back for the
+	// request.
+	ReleaseCaveat(ctx context.Context, fullMethod, condition string,
+		req interface{})
+}
+
+// CaveatValidatorFunc is a function that implements the CaveatValidator
+// interface.
+type CaveatValidatorFunc func(ctx context.Context, fullMethod,
+	condition string, req interface{}) error
+
+// ValidateCaveat calls the function itself.
+//
+// NOTE: This is part of the CaveatValidator interface.
+func (f CaveatValidatorFunc) ValidateCaveat(ctx context.Context, fullMethod,
+	condition string, req interface{}) error {
+
+	return f(ctx, fullMethod, condition, req)
+}
+
+// RegisterCaveatValidator registers a validator that enforces the condition of
+// custom caveats with the given name. Macaroons with such a caveat are
+// accepted once a validator is registered for it. Only one validator or
+// middleware can handle a custom caveat name.
+func (r *InterceptorChain) RegisterCaveatValidator(customCaveatName string,
+	validator CaveatValidator) error {
+
+	if customCaveatName == "" ||
+		strings.ContainsAny(customCaveatName, " \t\n") {
+
+		return fmt.Errorf("invalid custom caveat name '%s'",
+			customCaveatName)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.caveatValidators[customCaveatName]; ok {
+		return fmt.Errorf("a validator is already registered for the "+
+			"custom caveat name '%s'", customCaveatName)
+	}
+
+	for _, middleware := range r.registeredMiddleware {
+		if middleware.customCaveatName == customCaveatName {
+			return fmt.Errorf("a middleware is already registered "+
+				"for the custom caveat name '%s': %v",
+				customCaveatName, middleware.middlewareName)
+		}
+	}
+
+	log.Debugf("Registering validator for custom caveat %s",
+		customCaveatName)
+
+	r.caveatValidators[customCaveatName] = validator
+
+	return nil
+}
+
+// caveatReleases are the releases of the validators that accepted a request.
+type caveatReleases []func()
+
+// release calls all releases.
+func (c caveatReleases) release() {
+	for _, release := range c {
+		release()
+	}
+}
+
+// pendingRelease holds the releases of the validators that accepted a
+// request until the request reaches its handler. It is carried in the
+// request context, so that the innermost interceptor can mark the request as
+// delivered.
+type pendingRelease struct {
+	mu       sync.Mutex
+	releases caveatReleases
+}
+
+// pendingReleaseKey is the context key of the pending releases of a request.
+type pendingReleaseKey struct{}
+
+// set replaces the pending releases with those of a new request.
+func (p *pendingRelease) set(releases caveatReleases) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.releases = releases
+}
+
+// delivered marks the request as delivered to its handler, which means there
+// is nothing to release anymore.
+func (p *pendingRelease) delivered() {
+	p.set(nil)
+}
+
+// release releases what the validators held back for a request that didn't
+// reach its handler.
+func (p *pendingRelease) release() {
+	p.mu.Lock()
+	releases := p.releases
+	p.releases = nil
+	p.mu.Unlock()
+
+	releases.release()
+}
+
+// validateCaveats runs the registered validators for all custom caveats of
+// the macaroon in the request context. If a validator rejects the request,
+// the validators that accepted it before are released.
+func (r *InterceptorChain) validateCaveats(ctx context.Context,
+	fullMethod string, req interface{}) (caveatReleases, error) {
+
+	// Calls that don't require a macaroon are never restricted by one.
+	if _, ok := macaroonWhitelist[fullMethod]; ok {
+		return nil, nil
+	}
+
+	// Copy the validators, so they aren't run while holding the lock.
+	r.RLock()
+	validators := make(map[string]CaveatValidator, len(r.caveatValidators))
+	for name, validator := range r.caveatValidators {
+		validators[name] = validator
+	}
+	r.RUnlock()
+
+	if len(validators) == 0 {
+		return nil, nil
+	}
+
+	mac, _, err := macaroonFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if mac == nil {
+		return nil, nil
+	}
+
+	var releases caveatReleases
+	for name, validator := range validators {
+		if !macaroons.HasCustomCaveat(mac, name) {
+			continue
+		}
+
+		condition := macaroons.GetCustomCaveatCondition(mac, name)
+		err := validator.ValidateCaveat(
+			ctx, fullMethod, condition, req,
+		)
+		if err != nil {
+			releases.release()
+
+			return nil, fmt.Errorf("custom caveat '%s' rejected "+
+				"request: %w", name, err)
+		}
+
+		releaser, ok := validator.(CaveatReleaser)
+		if !ok {
+			continue
+		}
+		releases = append(releases, func() {
+			releaser.ReleaseCaveat(ctx, fullMethod, condition, req)
+		})
+	}
+
+	return releases, nil
+}
+
+// caveatValidatorUnaryServerInterceptor is a unary gRPC interceptor that
+// enforces the custom caveats of the macaroon a request is made with. If a
+// later interceptor rejects the request, the validators that accepted it are
+// released.
+func (r *InterceptorChain) caveatValidatorUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		releases, err := r.validateCaveats(ctx, info.FullMethod, req)
+		if err != nil {
+			return nil, err
+		}
+		if len(releases) == 0 {
+			return handler(ctx, req)
+		}
+
+		pending := &pendingRelease{releases: releases}
+		ctx = context.WithValue(ctx, pendingReleaseKey{}, pending)
+
+		resp, err := handler(ctx, req)
+
+		// The releases are only left if the request never reached its
+		// handler.
+		pending.release()
+
+		return resp, err
+	}
+}
+
+// caveatValidatorStreamServerInterceptor is a streaming gRPC interceptor that
+// enforces the custom caveats of the macaroon a stream is established with,
+// both for the stream itself and for every request received on it.
+func (r *InterceptorChain) caveatValidatorStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		releases, err := r.validateCaveats(
+			ss.Context(), info.FullMethod, nil,
+		)
+		if err != nil {
+			return err
+		}
+
+		pending := &pendingRelease{releases: releases}
+		ctx := context.WithValue(
+			ss.Context(), pendingReleaseKey{}, pending,
+		)
+
+		err = handler(srv, &caveatValidatingStream{
+			ServerStream: ss,
+			ctx:          ctx,
+			fullMethod:   info.FullMethod,
+			interceptor:  r,
+			pending:      pending,
+		})
+
+		// The releases are only left if the last request never reached
+		// the handler.
+		pending.release()
+
+		return err
+	}
+}
+
+// caveatValidatingStream is a server stream that validates every received
+// request against the custom caveats of the macaroon.
+type caveatValidatingStream struct {
+	grpc.ServerStream
+
+	ctx         context.Context
+	fullMethod  string
+	interceptor *InterceptorChain
+	pending     *pendingRelease
+}
+
+// Context returns the context of the stream, which carries the pending
+// releases of the received requests.
+func (s *caveatValidatingStream) Context() context.Context {
+	return s.ctx
+}
+
+// RecvMsg is called when lnd wants to receive a message from the client. This
+// is wrapped to validate streaming RPC requests.
+func (s *caveatValidatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	releases, err := s.interceptor.validateCaveats(
+		s.ctx, s.fullMethod, m,
+	)
+	if err != nil {
+		return err
+	}
+	s.pending.set(releases)
+
+	return nil
+}
+
+// caveatDeliveredUnaryServerInterceptor is the innermost unary gRPC
+// interceptor. A request that reaches it passed all other interceptors, so
+// the validators that accepted it are no longer released.
+func caveatDeliveredUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		pending, ok := ctx.Value(pendingReleaseKey{}).(*pendingRelease)
+		if ok {
+			pending.delivered()
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// caveatDeliveredStreamServerInterceptor is the innermost streaming gRPC
+// interceptor. A stream establishment or request that reaches it passed all
+// other interceptors, so the validators that accepted it are no longer
+// released.
+func caveatDeliveredStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream,
+		_ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		pending, ok := ss.Context().Value(
+			pendingReleaseKey{},
+		).(*pendingRelease)
+		if !ok {
+			return handler(srv, ss)
+		}
+		pending.delivered()
+
+		return handler(srv, &caveatDeliveredStream{
+			ServerStream: ss,
+			pending:      pending,
+		})
+	}
+}
+
+// caveatDeliveredStream is a server stream that marks the requests it
+// receives as delivered, and releases those that are rejected by an
+// interceptor after the caveat validators.
+type caveatDeliveredStream struct {
+	grpc.ServerStream
+
+	pending *pendingRelease
+}
+
+// RecvMsg is called when lnd wants to receive a message from the client. It
+// receives the message through all interceptors.
+func (s *caveatDeliveredStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		s.pending.release()
+		return err
+	}
+	s.pending.delivered()
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package rpcperms
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btclog/v2"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/macaroon.v2"
+)
+
+// macaroonContext returns an incoming request context that carries a macaroon
+// with the given custom caveat.
+func macaroonContext(t *testing.T, name, condition string) context.Context {
+	t.Helper()
+
+	return constraintsContext(
+		t, macaroons.CustomConstraint(name, condition),
+	)
+}
+
+// constraintsContext returns an incoming request context that carries a
+// macaroon with the given constraints.
+func constraintsContext(t *testing.T,
+	constraints ...macaroons.Constraint) context.Context {
+
+	t.Helper()
+
+	mac, err := macaroon.New(
+		[]byte("dummyRootKey"), []byte("dummyId"), "lnd",
+		macaroon.LatestVersion,
+	)
+	require.NoError(t, err)
+
+	mac, err = macaroons.AddConstraints(mac, constraints...)
+	require.NoError(t, err)
+
+	macBytes, err := mac.MarshalBinary()
+	require.NoError(t, err)
+
+	md := metadata.Pairs("macaroon", hex.EncodeToString(macBytes))
+
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+// TestCaveatValidator tests that registered caveat validators are enforced
+// for requests with a macaroon that carries their custom caveat.
+func TestCaveatValidator(t *testing.T) {
+	t.Parallel()
+
+	const (
+		caveatName = "max-amount"
+		fullMethod = "/lnrpc.Lightning/AddInvoice"
+	)
+
+	chain := NewInterceptorChain(btclog.Disabled, false, nil)
+
+	// Macaroons with an unknown custom caveat are rejected.
+	require.Error(t, chain.CustomCaveatSupported(caveatName))
+
+	validator := CaveatValidatorFunc(func(_ context.Context, method,
+		condition string, req interface{}) error {
+
+		require.Equal(t, fullMethod, method)
+		require.Equal(t, "1000", condition)
+
+		invoice, ok := req.(*lnrpc.Invoice)
+		require.True(t, ok)
+
+		if invoice.Value > 1000 {
+			return errors.New("amount too large")
+		}
+
+		return nil
+	})
+	require.NoError(t, chain.RegisterCaveatValidator(caveatName, validator))
+	require.NoError(t, chain.CustomCaveatSupported(caveatName))
+
+	// A caveat name can only be handled once.
+	require.Error(t, chain.RegisterCaveatValidator(caveatName, validator))
+	require.Error(t, chain.RegisterMiddleware(&MiddlewareHandler{
+		middlewareName:   "test",
+		customCaveatName: caveatName,
+	}))
+	require.Error(t, chain.RegisterCaveatValidator("a b", validator))
+
+	interceptor := chain.caveatValidatorUnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		return &lnrpc.AddInvoiceResponse{}, nil
+	}
+
+	ctx := macaroonContext(t, caveatName, "1000")
+	_, err := interceptor(ctx, &lnrpc.Invoice{Value: 1000}, info, handler)
+	require.NoError(t, err)
+
+	_, err = interceptor(ctx, &lnrpc.Invoice{Value: 1001}, info, handler)
+	require.ErrorContains(t, err, "amount too large")
+
+	// Requests with a macaroon without the caveat aren't validated.
+	ctx = macaroonContext(t, "other", "")
+	_, err = interceptor(ctx, &lnrpc.Invoice{Value: 1001}, info, handler)
+	require.NoError(t, err)
+}
+
+// releasingValidator is a caveat validator that counts the requests it holds
+// back resources for.
+type releasingValidator struct {
+	err      error
+	reserved int
+}
+
+// ValidateCaveat accepts the request unless the validator has an error.
+func (v *releasingValidator) ValidateCaveat(context.Context, string, string,
+	interface{}) error {
+
+	if v.err != nil {
+		return v.err
+	}
+	v.reserved++
+
+	return nil
+}
+
+// ReleaseCaveat releases a request.
+func (v *releasingValidator) ReleaseCaveat(context.Context, string, string,
+	interface{}) {
+
+	v.reserved--
+}
+
+// TestCaveatReleaser tests that the validators that accepted a request are
+// released if the request doesn't reach its handler.
+func TestCaveatReleaser(t *testing.T) {
+	t.Parallel()
+
+	const fullMethod = "/lnrpc.Lightning/SendPaymentSync"
+
+	chain := NewInterceptorChain(btclog.Disabled, false, nil)
+	accepting := &releasingValidator{}
+	rejecting := &releasingValidator{err: errors.New("rejected")}
+	require.NoError(t, chain.RegisterCaveatValidator("a", accepting))
+	require.NoError(t, chain.RegisterCaveatValidator("b", rejecting))
+
+	interceptor := chain.caveatValidatorUnaryServerInterceptor()
+	delivered := caveatDeliveredUnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		return &lnrpc.SendResponse{}, errors.New("payment failed")
+	}
+
+	// A request that reaches its handler stays accepted, even if the
+	// handler fails.
+	ctx := macaroonContext(t, "a", "")
+	_, err := interceptor(ctx, &lnrpc.SendRequest{}, info,
+		func(ctx context.Context, req interface{}) (interface{},
+			error) {
+
+			return delivered(ctx, req, info, handler)
+		},
+	)
+	require.ErrorContains(t, err, "payment failed")
+	require.Equal(t, 1, accepting.reserved)
+
+	// A request that a later interceptor rejects is released.
+	_, err = interceptor(ctx, &lnrpc.SendRequest{}, info,
+		func(context.Context, interface{}) (interface{}, error) {
+			return nil, errors.New("middleware rejected")
+		},
+	)
+	require.ErrorContains(t, err, "middleware rejected")
+	require.Equal(t, 1, accepting.reserved)
+
+	// A request that another validator rejects is released as well.
+	ctx = constraintsContext(
+		t, macaroons.CustomConstraint("a", ""),
+		macaroons.CustomConstraint("b", ""),
+	)
+
+	_, err = interceptor(ctx, &lnrpc.SendRequest{}, info, handler)
+	require.ErrorContains(t, err, "rejected")
+	require.Equal(t, 1, accepting.reserved)
+}
@@ -0,0 +1,209 @@
+package rpcperms
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// FieldMaskMetadataKey is the gRPC metadata key a client sets to only receive
+// some fields of a response. The value is a comma separated list of field
+// paths in the format of a protobuf FieldMask, using the proto field names,
+// for example "nodes.pub_key,edges.channel_id,edges.capacity". Repeated and
+// map fields apply the rest of the path to each of their elements.
+const FieldMaskMetadataKey = "field-mask"
+
+// fieldMaskTree is the parsed form of a field mask. Each node holds the
+// sub-fields to keep of a message field. A node without sub-fields keeps the
+// whole field.
+type fieldMaskTree map[protoreflect.Name]fieldMaskTree
+
+// parseFieldMask parses the paths of a field mask and checks that they name
+// existing fields of the given message.
+func parseFieldMask(md protoreflect.MessageDescriptor,
+	paths []string) (fieldMaskTree, error) {
+
+	tree := make(fieldMaskTree)
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		var (
+			node  = tree
+			desc  = md
+			names = strings.Split(path, ".")
+		)
+		for i, name := range names {
+			if desc == nil {
+				return nil, fmt.Errorf("field mask path %v "+
+					"descends into a non-message field",
+					path)
+			}
+
+			fd := desc.Fields().ByName(protoreflect.Name(name))
+			if fd == nil {
+				return nil, fmt.Errorf("field mask path %v: "+
+					"unknown field %v of %v", path, name,
+					desc.FullName())
+			}
+
+			desc = fd.Message()
+			if fd.IsMap() {
+				desc = fd.MapValue().Message()
+			}
+
+			// Once a field is kept as a whole, the rest of the
+			// path only needs to be valid.
+			if node == nil {
+				continue
+			}
+
+			// The last field of a path is kept as a whole, which
+			// covers any of its sub-paths requested before.
+			if i == len(names)-1 {
+				node[fd.Name()] = make(fieldMaskTree)
+				continue
+			}
+
+			child, ok := node[fd.Name()]
+			switch {
+			case ok && len(child) == 0:
+				child = nil
+
+			case !ok:
+				child = make(fieldMaskTree)
+				node[fd.Name()] = child
+			}
+			node = child
+		}
+	}
+
+	return tree, nil
+}
+
+// ApplyFieldMask returns a copy of the message that only holds the fields of
+// the given field mask paths. The message itself isn't modified, so it may be
+// shared, for example by a response cache. Fields that are kept as a whole
+// share their values with the original message.
+func ApplyFieldMask(msg proto.Message, paths []string) (proto.Message,
+	error) {
+
+	src := msg.ProtoReflect()
+	tree, err := parseFieldMask(src.Descriptor(), paths)
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty mask keeps everything, like it does for protobuf update
+	// requests.
+	if len(tree) == 0 {
+		return msg, nil
+	}
+
+	return pruneMessage(src, tree).Interface(), nil
+}
+
+// pruneMessage returns a new message that only holds the fields of the tree.
+func pruneMessage(src protoreflect.Message,
+	tree fieldMaskTree) protoreflect.Message {
+
+	dst := src.New()
+	for name, sub := range tree {
+		fd := src.Descriptor().Fields().ByName(name)
+		if !src.Has(fd) {
+			continue
+		}
+
+		value := src.Get(fd)
+		if len(sub) == 0 {
+			dst.Set(fd, value)
+			continue
+		}
+
+		switch {
+		case fd.IsList():
+			srcList := value.List()
+			dstList := dst.Mutable(fd).List()
+			for i := 0; i < srcList.Len(); i++ {
+				elem := srcList.Get(i).Message()
+				dstList.Append(protoreflect.ValueOfMessage(
+					pruneMessage(elem, sub),
+				))
+			}
+
+		case fd.IsMap():
+			dstMap := dst.Mutable(fd).Map()
+			value.Map().Range(func(key protoreflect.MapKey,
+				v protoreflect.Value) bool {
+
+				elem := pruneMessage(v.Message(), sub)
+				dstMap.Set(
+					key, protoreflect.ValueOfMessage(elem),
+				)
+
+				return true
+			})
+
+		default:
+			elem := pruneMessage(value.Message(), sub)
+			dst.Set(fd, protoreflect.ValueOfMessage(elem))
+		}
+	}
+
+	return dst
+}
+
+// fieldMaskFromContext returns the field mask paths of the incoming request,
+// if the client set any.
+func fieldMaskFromContext(ctx context.Context) []string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var paths []string
+	for _, value := range md.Get(FieldMaskMetadataKey) {
+		paths = append(paths, strings.Split(value, ",")...)
+	}
+
+	return paths
+}
+
+// fieldMaskUnaryServerInterceptor is a unary gRPC interceptor that reduces the
+// response to the fields of the field mask the client requested through the
+// request metadata.
+func fieldMaskUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		paths := fieldMaskFromContext(ctx)
+
+		resp, err := handler(ctx, req)
+		if err != nil || len(paths) == 0 {
+			return resp, err
+		}
+
+		msg, ok := resp.(proto.Message)
+		if !ok {
+			return resp, nil
+		}
+
+		pruned, err := ApplyFieldMask(msg, paths)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument,
+				"invalid field mask: %v", err)
+		}
+
+		return pruned, nil
+	}
+}
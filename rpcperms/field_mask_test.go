@@ -0,0 +1,107 @@
+package rpcperms
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestApplyFieldMask tests that a field mask reduces a response to the
+// requested fields without modifying the original response.
+func TestApplyFieldMask(t *testing.T) {
+	t.Parallel()
+
+	graph := &lnrpc.ChannelGraph{
+		Nodes: []*lnrpc.LightningNode{{
+			PubKey: "node1",
+			Alias:  "alias1",
+		}},
+		Edges: []*lnrpc.ChannelEdge{{
+			ChannelId: 1,
+			Capacity:  1000,
+			Node1Pub:  "node1",
+			Node1Policy: &lnrpc.RoutingPolicy{
+				FeeBaseMsat:   1000,
+				TimeLockDelta: 80,
+			},
+			Node2Policy: &lnrpc.RoutingPolicy{
+				FeeBaseMsat: 2000,
+			},
+		}},
+	}
+	original := proto.Clone(graph)
+
+	testCases := []struct {
+		name     string
+		paths    []string
+		expected *lnrpc.ChannelGraph
+		err      string
+	}{
+		{
+			name:     "empty mask",
+			expected: graph,
+		},
+		{
+			name:  "edges without policies",
+			paths: []string{"edges.channel_id", "edges.capacity"},
+			expected: &lnrpc.ChannelGraph{
+				Edges: []*lnrpc.ChannelEdge{{
+					ChannelId: 1,
+					Capacity:  1000,
+				}},
+			},
+		},
+		{
+			name:  "nested field",
+			paths: []string{"edges.node1_policy.fee_base_msat"},
+			expected: &lnrpc.ChannelGraph{
+				Edges: []*lnrpc.ChannelEdge{{
+					Node1Policy: &lnrpc.RoutingPolicy{
+						FeeBaseMsat: 1000,
+					},
+				}},
+			},
+		},
+		{
+			name: "whole field covers sub-path",
+			paths: []string{
+				"edges.node2_policy.fee_base_msat", "nodes",
+				"edges.node2_policy",
+			},
+			expected: &lnrpc.ChannelGraph{
+				Nodes: graph.Nodes,
+				Edges: []*lnrpc.ChannelEdge{{
+					Node2Policy: graph.Edges[0].Node2Policy,
+				}},
+			},
+		},
+		{
+			name:  "unknown field",
+			paths: []string{"edges.unknown"},
+			err:   "unknown field",
+		},
+		{
+			name:  "path into scalar field",
+			paths: []string{"edges.capacity.value"},
+			err:   "non-message field",
+		},
+	}
+
+	for _, tc := range testCases {
+		pruned, err := ApplyFieldMask(graph, tc.paths)
+		if tc.err != "" {
+			require.ErrorContains(t, err, tc.err, tc.name)
+			continue
+		}
+
+		require.NoError(t, err, tc.name)
+		require.True(
+			t, proto.Equal(tc.expected, pruned), "%s: %v", tc.name,
+			pruned,
+		)
+	}
+
+	require.True(t, proto.Equal(original, graph))
+}
@@ -181,6 +181,22 @@ type InterceptorChain struct {
 	// map.
 	registeredMiddlewareNames map[string]int
 
+	// caveatValidators holds the in-process validators of custom caveats,
+	// keyed by the custom caveat name they enforce.
+	caveatValidators map[string]CaveatValidator
+
+	// rateLimiter limits the rate of calls per macaroon. It is nil if rate
+	// limiting is disabled.
+	rateLimiter *rateLimiter
+
+	// lockedHandlers holds the handlers of the read-only calls that are
+	// served while the wallet is locked, keyed by their full method name.
+	lockedHandlers map[string]grpc.UnaryHandler
+
+	// lockedRPCToken is the token that authenticates calls to the locked
+	// handlers. No locked handler is served while it is empty.
+	lockedRPCToken []byte
+
 	// mandatoryMiddleware is a list of all middleware that is considered to
 	// be mandatory. If any of them is not registered then all RPC requests
 	// (except for the macaroon white listed methods and the middleware
@@ -208,6 +224,8 @@ func NewInterceptorChain(log btclog.Logger, noMacaroons bool,
 		permissionMap:             make(map[string][]bakery.Op),
 		rpcsLog:                   log,
 		registeredMiddlewareNames: make(map[string]int),
+		caveatValidators:          make(map[string]CaveatValidator),
+		lockedHandlers:            make(map[string]grpc.UnaryHandler),
 		mandatoryMiddleware:       mandatoryMiddleware,
 		quit:                      make(chan struct{}),
 	}
@@ -469,6 +487,11 @@ func (r *InterceptorChain) RegisterMiddleware(mw *MiddlewareHandler) error {
 		}
 	}
 
+	if _, ok := r.caveatValidators[mw.customCaveatName]; ok {
+		return fmt.Errorf("a validator is already registered for the "+
+			"custom caveat name '%s'", mw.customCaveatName)
+	}
+
 	r.registeredMiddleware = append(r.registeredMiddleware, mw)
 	index := len(r.registeredMiddleware) - 1
 	r.registeredMiddlewareNames[mw.middlewareName] = index
@@ -502,10 +525,10 @@ func (r *InterceptorChain) RemoveMiddleware(middlewareName string) {
 	}
 }
 
-// CustomCaveatSupported makes sure a middleware that handles the given custom
-// caveat name is registered. If none is, an error is returned, signalling to
-// the macaroon bakery and its validator to reject macaroons that have a custom
-// caveat with that name.
+// CustomCaveatSupported makes sure a middleware or validator that handles the
+// given custom caveat name is registered. If none is, an error is returned,
+// signalling to the macaroon bakery and its validator to reject macaroons that
+// have a custom caveat with that name.
 //
 // NOTE: This method is part of the macaroons.CustomCaveatAcceptor interface.
 func (r *InterceptorChain) CustomCaveatSupported(customCaveatName string) error {
@@ -526,8 +549,13 @@ func (r *InterceptorChain) CustomCaveatSupported(customCaveatName string) error
 		}
 	}
 
+	if _, ok := r.caveatValidators[customCaveatName]; ok {
+		return nil
+	}
+
 	return fmt.Errorf("cannot accept macaroon with custom caveat '%s', "+
-		"no middleware registered to handle it", customCaveatName)
+		"no middleware or validator registered to handle it",
+		customCaveatName)
 }
 
 // CreateServerOpts creates the GRPC server options that can be added to a GRPC
@@ -546,6 +574,13 @@ func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
 		strmInterceptors, errorLogStreamServerInterceptor(r.rpcsLog),
 	)
 
+	// The field mask interceptor reduces the final response to the fields
+	// the client asked for, so it wraps all interceptors that come after
+	// it.
+	unaryInterceptors = append(
+		unaryInterceptors, fieldMaskUnaryServerInterceptor(),
+	)
+
 	// Next we'll add our RPC state check interceptors, that will check
 	// whether the attempted call is allowed in the current state.
 	unaryInterceptors = append(
@@ -564,6 +599,24 @@ func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
 		strmInterceptors, r.MacaroonStreamServerInterceptor(),
 	)
 
+	// Calls that exceed the rate limit of their macaroon are rejected
+	// before any further work is done for them.
+	unaryInterceptors = append(
+		unaryInterceptors, r.rateLimitUnaryServerInterceptor(),
+	)
+	strmInterceptors = append(
+		strmInterceptors, r.rateLimitStreamServerInterceptor(),
+	)
+
+	// The custom caveats that are handled by in-process validators are
+	// enforced right after the macaroon itself was validated.
+	unaryInterceptors = append(
+		unaryInterceptors, r.caveatValidatorUnaryServerInterceptor(),
+	)
+	strmInterceptors = append(
+		strmInterceptors, r.caveatValidatorStreamServerInterceptor(),
+	)
+
 	// Next, we'll add the interceptors for our custom macaroon caveat based
 	// middleware.
 	unaryInterceptors = append(
@@ -583,6 +636,16 @@ func (r *InterceptorChain) CreateServerOpts() []grpc.ServerOption {
 	unaryInterceptors = append(unaryInterceptors, promUnaryInterceptors...)
 	strmInterceptors = append(strmInterceptors, promStrmInterceptors...)
 
+	// The last interceptors mark the requests as delivered, so that the
+	// caveat validators that accepted a request which a later interceptor
+	// rejected can release what they held back for it.
+	unaryInterceptors = append(
+		unaryInterceptors, caveatDeliveredUnaryServerInterceptor(),
+	)
+	strmInterceptors = append(
+		strmInterceptors, caveatDeliveredStreamServerInterceptor(),
+	)
+
 	// Create server options from the interceptors we just set up.
 	chainedUnary := grpc_middleware.WithUnaryServerChain(
 		unaryInterceptors...,
@@ -775,6 +838,19 @@ func (r *InterceptorChain) rpcStateUnaryServerInterceptor() grpc.UnaryServerInte
 		r.rpcsLog.Debugf("[%v] requested", info.FullMethod)
 
 		if err := r.checkRPCState(info.Server); err != nil {
+			// Some read-only calls can be served from the databases
+			// before the wallet is unlocked.
+			lockedHandler, ok, lockedErr := r.lockedHandler(
+				ctx, info.FullMethod,
+			)
+			switch {
+			case lockedErr != nil:
+				return nil, lockedErr
+
+			case ok:
+				return lockedHandler(ctx, req)
+			}
+
 			return nil, err
 		}
 
@@ -0,0 +1,96 @@
+package rpcperms
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// LockedRPCTokenKey is the key of the request metadata that holds the hex
+// encoded token which authenticates calls to locked handlers.
+const LockedRPCTokenKey = "lockedrpc-token"
+
+// ErrInvalidLockedRPCToken is returned if a call to a locked handler carries
+// a token that doesn't match the configured one.
+var ErrInvalidLockedRPCToken = errors.New("invalid locked RPC token")
+
+// RegisterLockedHandler registers a handler that serves the given unary method
+// while the wallet is locked, or unlocked but the RPC server isn't active yet.
+// Once the RPC server is active, calls are served by the regular service again.
+//
+// NOTE: Macaroons can't be validated before the wallet is unlocked, so calls
+// to locked handlers are authenticated with the token set by
+// SetLockedRPCToken instead. No call is served before a token is set. Only
+// read-only methods should be registered.
+func (r *InterceptorChain) RegisterLockedHandler(fullMethod string,
+	handler grpc.UnaryHandler) error {
+
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.lockedHandlers[fullMethod]; ok {
+		return fmt.Errorf("locked handler for %v already registered",
+			fullMethod)
+	}
+
+	r.lockedHandlers[fullMethod] = handler
+
+	return nil
+}
+
+// SetLockedRPCToken sets the token that calls to locked handlers must carry
+// in their LockedRPCTokenKey metadata.
+func (r *InterceptorChain) SetLockedRPCToken(token []byte) error {
+	if len(token) == 0 {
+		return errors.New("locked RPC token must not be empty")
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.lockedRPCToken = token
+
+	return nil
+}
+
+// lockedHandler returns the handler that serves the given method in the
+// current RPC state, if there is one and the call is made with the locked RPC
+// token. A call without a token isn't served, and one with an invalid token
+// is rejected.
+func (r *InterceptorChain) lockedHandler(ctx context.Context,
+	fullMethod string) (grpc.UnaryHandler, bool, error) {
+
+	r.RLock()
+	defer r.RUnlock()
+
+	switch r.state {
+	case walletLocked, walletUnlocked:
+	default:
+		return nil, false, nil
+	}
+
+	handler, ok := r.lockedHandlers[fullMethod]
+	if !ok || len(r.lockedRPCToken) == 0 {
+		return nil, false, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(LockedRPCTokenKey)
+	if len(tokens) == 0 {
+		return nil, false, nil
+	}
+
+	token, err := hex.DecodeString(tokens[0])
+	if err != nil || len(tokens) != 1 ||
+		subtle.ConstantTimeCompare(token, r.lockedRPCToken) != 1 {
+
+		return nil, false, ErrInvalidLockedRPCToken
+	}
+
+	return handler, true, nil
+}
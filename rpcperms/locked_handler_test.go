@@ -0,0 +1,115 @@
+package rpcperms
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btclog/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// TestLockedHandler tests that registered locked handlers serve their method
+// to calls with the locked RPC token, and only until the RPC server is active.
+func TestLockedHandler(t *testing.T) {
+	t.Parallel()
+
+	const (
+		lockedMethod = "/lnrpc.Lightning/GetInfo"
+		otherMethod  = "/lnrpc.Lightning/ListChannels"
+	)
+
+	chain := NewInterceptorChain(btclog.Disabled, false, nil)
+	require.NoError(t, chain.Start())
+	t.Cleanup(func() {
+		require.NoError(t, chain.Stop())
+	})
+
+	lockedHandler := func(context.Context, interface{}) (interface{},
+		error) {
+
+		return "locked", nil
+	}
+	require.NoError(t, chain.RegisterLockedHandler(
+		lockedMethod, lockedHandler,
+	))
+	require.Error(t, chain.RegisterLockedHandler(
+		lockedMethod, lockedHandler,
+	))
+
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		return "active", nil
+	}
+
+	token := []byte{1, 2, 3, 4}
+	tokenCtx := func(token string) context.Context {
+		return metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs(LockedRPCTokenKey, token),
+		)
+	}
+	ctx := tokenCtx(hex.EncodeToString(token))
+
+	interceptor := chain.rpcStateUnaryServerInterceptor()
+	callWithCtx := func(ctx context.Context, method string) (interface{},
+		error) {
+
+		return interceptor(
+			ctx, nil, &grpc.UnaryServerInfo{
+				Server:     struct{}{},
+				FullMethod: method,
+			}, handler,
+		)
+	}
+	call := func(method string) (interface{}, error) {
+		return callWithCtx(ctx, method)
+	}
+
+	// Nothing is served before lnd has started.
+	_, err := call(lockedMethod)
+	require.ErrorIs(t, err, ErrWaitingToStart)
+
+	// Nothing is served before a token is set either.
+	chain.SetWalletLocked()
+
+	_, err = call(lockedMethod)
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	require.Error(t, chain.SetLockedRPCToken(nil))
+	require.NoError(t, chain.SetLockedRPCToken(token))
+
+	// Calls without the token aren't served, and calls with an invalid
+	// token are rejected.
+	_, err = callWithCtx(context.Background(), lockedMethod)
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	_, err = callWithCtx(tokenCtx("01020305"), lockedMethod)
+	require.ErrorIs(t, err, ErrInvalidLockedRPCToken)
+
+	_, err = callWithCtx(tokenCtx("not hex"), lockedMethod)
+	require.ErrorIs(t, err, ErrInvalidLockedRPCToken)
+
+	// While the wallet is locked, only the locked method is served.
+	resp, err := call(lockedMethod)
+	require.NoError(t, err)
+	require.Equal(t, "locked", resp)
+
+	_, err = call(otherMethod)
+	require.ErrorIs(t, err, ErrWalletLocked)
+
+	chain.SetWalletUnlocked()
+
+	resp, err = call(lockedMethod)
+	require.NoError(t, err)
+	require.Equal(t, "locked", resp)
+
+	// Once the RPC server is active, calls are served by the regular
+	// handler again.
+	chain.SetRPCActive()
+
+	resp, err = call(lockedMethod)
+	require.NoError(t, err)
+	require.Equal(t, "active", resp)
+}
@@ -0,0 +1,234 @@
+package rpcperms
+
+import (
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/monitoring"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+// MethodClass is the class of an RPC method that determines the rate limit
+// that applies to it.
+type MethodClass uint8
+
+const (
+	// MethodClassRead is the class of methods that only read state.
+	MethodClassRead MethodClass = iota
+
+	// MethodClassWrite is the class of methods that modify state but don't
+	// move funds.
+	MethodClassWrite
+
+	// MethodClassPayment is the class of methods that require on-chain or
+	// off-chain write permissions, i.e. that may move funds.
+	MethodClassPayment
+)
+
+// String returns a human-readable name of the method class.
+func (c MethodClass) String() string {
+	switch c {
+	case MethodClassRead:
+		return "read"
+
+	case MethodClassWrite:
+		return "write"
+
+	case MethodClassPayment:
+		return "payment"
+
+	default:
+		return "unknown"
+	}
+}
+
+// classifyMethod returns the class of a method with the given required
+// permissions.
+func classifyMethod(ops []bakery.Op) MethodClass {
+	class := MethodClassRead
+	for _, op := range ops {
+		if op.Action != "write" {
+			continue
+		}
+
+		if op.Entity == "onchain" || op.Entity == "offchain" {
+			return MethodClassPayment
+		}
+
+		class = MethodClassWrite
+	}
+
+	return class
+}
+
+// RateLimit is the rate limit of a method class.
+type RateLimit struct {
+	// Rate is the number of calls per second that are allowed.
+	Rate rate.Limit
+
+	// Burst is the number of calls that can be made at once.
+	Burst int
+}
+
+// limiterSweepInterval is the minimum interval between two sweeps of the
+// idle limiters.
+const limiterSweepInterval = time.Minute
+
+// limiterKey identifies the limiter of a method class for a macaroon.
+type limiterKey struct {
+	identity string
+	class    MethodClass
+}
+
+// rateLimiter limits the rate of RPC calls per macaroon and method class.
+type rateLimiter struct {
+	limits map[MethodClass]RateLimit
+
+	// now returns the current time.
+	now func() time.Time
+
+	mu        sync.Mutex
+	limiters  map[limiterKey]*rate.Limiter
+	lastSweep time.Time
+}
+
+// newRateLimiter creates a new rate limiter with the given limits. Method
+// classes without a limit aren't limited.
+func newRateLimiter(limits map[MethodClass]RateLimit) *rateLimiter {
+	return &rateLimiter{
+		limits:   limits,
+		now:      time.Now,
+		limiters: make(map[limiterKey]*rate.Limiter),
+	}
+}
+
+// allow returns true if a call of the given class by the given identity is
+// within the rate limit.
+func (l *rateLimiter) allow(identity string, class MethodClass) bool {
+	limit, ok := l.limits[class]
+	if !ok {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.lastSweep) >= limiterSweepInterval {
+		l.sweep(now)
+	}
+
+	key := limiterKey{identity: identity, class: class}
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(limit.Rate, limit.Burst)
+		l.limiters[key] = limiter
+	}
+
+	return limiter.AllowN(now, 1)
+}
+
+// sweep removes the limiters that were idle long enough to refill their
+// burst. Such a limiter behaves like a new one, so removing it doesn't loosen
+// the limit, and the limiters are only kept for identities that recently made
+// calls.
+//
+// NOTE: The mutex must be held when calling this method.
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, limiter := range l.limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(l.limiters, key)
+		}
+	}
+
+	l.lastSweep = now
+}
+
+// EnableRateLimiting limits the rate of RPC calls per macaroon. Every method
+// class has its own limit, classes without a limit aren't limited. Calls
+// that aren't authenticated by a macaroon share a single limit per class.
+func (r *InterceptorChain) EnableRateLimiting(
+	limits map[MethodClass]RateLimit) {
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.rateLimiter = newRateLimiter(limits)
+}
+
+// checkRateLimit returns a ResourceExhausted error if a call to the given
+// method exceeds the rate limit of the macaroon it is made with.
+func (r *InterceptorChain) checkRateLimit(ctx context.Context,
+	fullMethod string) error {
+
+	r.RLock()
+	limiter := r.rateLimiter
+	ops := r.permissionMap[fullMethod]
+	r.RUnlock()
+
+	if limiter == nil {
+		return nil
+	}
+
+	// The macaroon was already validated at this point, so its id is a
+	// trusted identity. Calls to methods that don't require a macaroon,
+	// and all calls if macaroons are disabled, aren't authenticated, so
+	// they share the same identity. Otherwise made up macaroon ids could
+	// be used to evade the limit.
+	var identity string
+	_, whitelisted := macaroonWhitelist[fullMethod]
+	if !whitelisted && !r.noMacaroons {
+		mac, _, err := macaroonFromContext(ctx)
+		if err == nil && mac != nil {
+			identity = hex.EncodeToString(mac.Id())
+		}
+	}
+
+	class := classifyMethod(ops)
+	if limiter.allow(identity, class) {
+		return nil
+	}
+
+	monitoring.RecordRateLimited(fullMethod, class.String())
+
+	return status.Errorf(codes.ResourceExhausted, "rate limit of %v "+
+		"calls exceeded, try again later", class)
+}
+
+// rateLimitUnaryServerInterceptor is a unary gRPC interceptor that rejects
+// calls exceeding the rate limit of their macaroon.
+func (r *InterceptorChain) rateLimitUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+
+		err := r.checkRateLimit(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// rateLimitStreamServerInterceptor is a streaming gRPC interceptor that
+// rejects the establishment of streams exceeding the rate limit of their
+// macaroon.
+func (r *InterceptorChain) rateLimitStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream,
+		info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+
+		err := r.checkRateLimit(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
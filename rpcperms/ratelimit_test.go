@@ -0,0 +1,164 @@
+package rpcperms
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btclog/v2"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gopkg.in/macaroon-bakery.v2/bakery"
+)
+
+// TestClassifyMethod tests that methods are classified by their required
+// permissions.
+func TestClassifyMethod(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, MethodClassRead, classifyMethod(nil))
+	require.Equal(t, MethodClassRead, classifyMethod([]bakery.Op{{
+		Entity: "offchain",
+		Action: "read",
+	}}))
+	require.Equal(t, MethodClassWrite, classifyMethod([]bakery.Op{{
+		Entity: "info",
+		Action: "read",
+	}, {
+		Entity: "peers",
+		Action: "write",
+	}}))
+	require.Equal(t, MethodClassPayment, classifyMethod([]bakery.Op{{
+		Entity: "peers",
+		Action: "write",
+	}, {
+		Entity: "offchain",
+		Action: "write",
+	}}))
+}
+
+// TestRateLimit tests that calls exceeding the rate limit of their macaroon
+// and method class are rejected.
+func TestRateLimit(t *testing.T) {
+	t.Parallel()
+
+	const (
+		readMethod    = "/lnrpc.Lightning/GetInfo"
+		paymentMethod = "/lnrpc.Lightning/SendPaymentSync"
+	)
+
+	chain := NewInterceptorChain(btclog.Disabled, false, nil)
+	require.NoError(t, chain.AddPermission(readMethod, []bakery.Op{{
+		Entity: "info",
+		Action: "read",
+	}}))
+	require.NoError(t, chain.AddPermission(paymentMethod, []bakery.Op{{
+		Entity: "offchain",
+		Action: "write",
+	}}))
+
+	// Without rate limiting, all calls are allowed.
+	ctx := macaroonContext(t, "test", "")
+	require.NoError(t, chain.checkRateLimit(ctx, paymentMethod))
+
+	// Only limit payments, with a burst of two calls that is only
+	// refilled after a very long time.
+	chain.EnableRateLimiting(map[MethodClass]RateLimit{
+		MethodClassPayment: {
+			Rate:  0.0001,
+			Burst: 2,
+		},
+	})
+
+	require.NoError(t, chain.checkRateLimit(ctx, paymentMethod))
+	require.NoError(t, chain.checkRateLimit(ctx, paymentMethod))
+
+	err := chain.checkRateLimit(ctx, paymentMethod)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// Read calls aren't limited.
+	for i := 0; i < 10; i++ {
+		require.NoError(t, chain.checkRateLimit(ctx, readMethod))
+	}
+
+	// Calls without a macaroon have their own limit.
+	ctx = context.Background()
+	require.NoError(t, chain.checkRateLimit(ctx, paymentMethod))
+	require.NoError(t, chain.checkRateLimit(ctx, paymentMethod))
+
+	err = chain.checkRateLimit(ctx, paymentMethod)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+// TestRateLimitIdentity tests that calls which aren't authenticated by a
+// macaroon share a single limit, whatever macaroon they carry.
+func TestRateLimitIdentity(t *testing.T) {
+	t.Parallel()
+
+	const unlockMethod = "/lnrpc.WalletUnlocker/UnlockWallet"
+
+	chain := NewInterceptorChain(btclog.Disabled, false, nil)
+	chain.EnableRateLimiting(map[MethodClass]RateLimit{
+		MethodClassRead: {
+			Rate:  0.0001,
+			Burst: 2,
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		ctx := macaroonContext(t, fmt.Sprintf("unvalidated-%d", i), "")
+		require.NoError(t, chain.checkRateLimit(ctx, unlockMethod))
+	}
+
+	ctx := macaroonContext(t, "unvalidated-2", "")
+	err := chain.checkRateLimit(ctx, unlockMethod)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	chain.RLock()
+	numLimiters := len(chain.rateLimiter.limiters)
+	chain.RUnlock()
+	require.Equal(t, 1, numLimiters)
+}
+
+// TestRateLimiterSweep tests that limiters are removed once they were idle
+// long enough to refill their burst.
+func TestRateLimiterSweep(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1000, 0)
+	limiter := newRateLimiter(map[MethodClass]RateLimit{
+		MethodClassRead: {
+			Rate:  1,
+			Burst: 10,
+		},
+	})
+	limiter.now = func() time.Time {
+		return now
+	}
+
+	for i := 0; i < 10; i++ {
+		require.True(t, limiter.allow("a", MethodClassRead))
+	}
+	require.False(t, limiter.allow("a", MethodClassRead))
+	require.True(t, limiter.allow("b", MethodClassRead))
+
+	// After a sweep interval, the limiter of b refilled its single token
+	// and is removed, while the one of a still lacks tokens and is kept,
+	// so that its limit holds.
+	now = now.Add(limiterSweepInterval / 12)
+	limiter.sweep(now)
+	require.Len(t, limiter.limiters, 1)
+
+	for i := 0; i < 5; i++ {
+		require.True(t, limiter.allow("a", MethodClassRead))
+	}
+	require.False(t, limiter.allow("a", MethodClassRead))
+
+	// Once the burst of a is refilled as well, the next sweep removes
+	// it.
+	now = now.Add(limiterSweepInterval)
+	require.True(t, limiter.allow("c", MethodClassRead))
+	require.Len(t, limiter.limiters, 1)
+}
@@ -383,6 +383,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/HtlcExpiryRisks": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ListChannels": {{
 			Entity: "offchain",
 			Action: "read",
@@ -391,6 +395,13 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SubscribeChannelResolutions": {{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/ClosedChannels": {{
 			Entity: "offchain",
 			Action: "read",
@@ -443,6 +454,14 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "info",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/GetGraphMetrics": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/GetNodeMetricsV2": {{
+			Entity: "info",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/GetChanInfo": {{
 			Entity: "info",
 			Action: "read",
@@ -515,6 +534,10 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ExportEmergencyCloseBundle": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/SubscribeChannelBackups": {{
 			Entity: "offchain",
 			Action: "read",
@@ -577,6 +600,158 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/ListChannelTemplates": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SetChannelTemplate": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/DeleteChannelTemplate": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/GetRuntimeConfig": {{
+			Entity: "info",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/UpdateRuntimeConfig": {{
+			Entity: "info",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ExportNodeProfile": {{
+			Entity: "offchain",
+			Action: "read",
+		}, {
+			Entity: "peers",
+			Action: "read",
+		}, {
+			Entity: "message",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ImportNodeProfile": {{
+			Entity: "info",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}, {
+			Entity: "peers",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListInboxMessages": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AckInboxMessages": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribeInbox": {{
+			Entity: "invoices",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ListChannelAcceptPolicies": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/UpdateChannelAcceptPolicies": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListChannelAcceptDecisions": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ListAbortedPsbtFlows": {{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ForgetAbortedPsbtFlow": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListUtxoReservations": {{
+			Entity: "onchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ReleaseUtxoReservation": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListGossipPeerScores": {{
+			Entity: "peers",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/ExportGraphSnapshot": {{
+			Entity: "info",
+			Action: "read",
+		}, {
+			Entity: "message",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListJobs": {{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SubscribeJobEvents": {{
+			Entity: "onchain",
+			Action: "read",
+		}, {
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/CancelJob": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/CreateAccount": {{
+			Entity: "offchain",
+			Action: "write",
+		}, {
+			Entity: "macaroon",
+			Action: "generate",
+		}},
+		"/lnrpc.Lightning/ListAccounts": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AdjustAccountBalance": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/AccountLedger": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/RemoveAccount": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SetForwardingRules": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/ListForwardingRules": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 	}
 }
 
@@ -641,6 +816,10 @@ type rpcServer struct {
 	// interceptor is used to be able to request a shutdown
 	interceptor signal.Interceptor
 
+	// runtimeCfgMtx serializes changes of the runtime config and the
+	// writes of the runtime overrides file.
+	runtimeCfgMtx sync.Mutex
+
 	graphCache        sync.RWMutex
 	describeGraphResp *lnrpc.ChannelGraph
 	graphCacheEvictor *time.Timer
@@ -694,6 +873,18 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 	}
 	graph := s.graphDB
 
+	// Only restrict the time lock delta of individual hops if any of the
+	// limits is configured.
+	var cltvBudget *routing.CltvBudget
+	if r.cfg.Routing.MaxHopCltvDelta != 0 ||
+		r.cfg.Routing.MaxLastHopCltvDelta != 0 {
+
+		cltvBudget = &routing.CltvBudget{
+			MaxHopDelta:     r.cfg.Routing.MaxHopCltvDelta,
+			MaxLastHopDelta: r.cfg.Routing.MaxLastHopCltvDelta,
+		}
+	}
+
 	routerBackend := &routerrpc.RouterBackend{
 		SelfNode: selfNode.PubKeyBytes,
 		FetchChannelCapacity: func(chanID uint64) (btcutil.Amount,
@@ -729,13 +920,24 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 			return info.NodeKey1Bytes, info.NodeKey2Bytes, nil
 		},
 		FindRoute:              s.chanRouter.FindRoute,
+		FindDiverseRoutes:      s.chanRouter.FindDiverseRoutes,
+		EstimatePayment:        s.chanRouter.EstimatePayment,
 		MissionControl:         s.defaultMC,
 		ActiveNetParams:        r.cfg.ActiveNetParams.Params,
 		Tower:                  s.controlTower,
 		MaxTotalTimelock:       r.cfg.MaxOutgoingCltvExpiry,
+		CltvBudget:             cltvBudget,
 		DefaultFinalCltvDelta:  uint16(r.cfg.Bitcoin.TimeLockDelta),
 		SubscribeHtlcEvents:    s.htlcNotifier.SubscribeHtlcEvents,
+		SubscribeAll:           r.SubscribeAll,
+		DustExposures:          s.htlcSwitch.DustExposures,
+		UpdateMaxFeeExposure:   s.htlcSwitch.UpdateMaxFeeExposure,
+		ChannelReputation:      s.htlcSwitch.ChannelReputation,
+		ChannelReputations:     s.htlcSwitch.ChannelReputations,
+		ForwardingPackages:     s.htlcSwitch.ForwardingPackages,
 		InterceptableForwarder: s.interceptableSwitch,
+		ReprocessForwardingPackage: s.htlcSwitch.
+			ReprocessForwardingPackage,
 		SetChannelEnabled: func(outpoint wire.OutPoint) error {
 			return s.chanStatusMgr.RequestEnable(outpoint, true)
 		},
@@ -758,6 +960,63 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 
 			return nil
 		},
+		ChannelBalance: func(chanID uint64) (route.Vertex,
+			btcutil.Amount, error) {
+
+			channels, err := s.chanStateDB.FetchAllOpenChannels()
+			if err != nil {
+				return route.Vertex{}, 0, err
+			}
+
+			for _, channel := range channels {
+				scid := channel.ShortChannelID.ToUint64()
+				if scid != chanID {
+					continue
+				}
+
+				peer := route.NewVertex(channel.IdentityPub)
+				balance := channel.LocalCommitment.LocalBalance
+
+				return peer, balance.ToSatoshis(), nil
+			}
+
+			return route.Vertex{}, 0, fmt.Errorf("no open channel "+
+				"with id %v", chanID)
+		},
+		AddInvoice: func(ctx context.Context, memo string,
+			amt lnwire.MilliSatoshi) (lntypes.Hash, [32]byte,
+			error) {
+
+			var payAddr [32]byte
+			resp, err := r.AddInvoice(ctx, &lnrpc.Invoice{
+				Memo:      memo,
+				ValueMsat: int64(amt),
+			})
+			if err != nil {
+				return lntypes.Hash{}, payAddr, err
+			}
+
+			hash, err := lntypes.MakeHash(resp.RHash)
+			if err != nil {
+				return lntypes.Hash{}, payAddr, err
+			}
+			copy(payAddr[:], resp.PaymentAddr)
+
+			return hash, payAddr, nil
+		},
+	}
+
+	// The probe history is only available if the background prober is
+	// enabled.
+	if s.prober != nil {
+		routerBackend.ProbeHistory = s.prober.ProbeHistory
+	}
+
+	// Stuck htlcs are only available if their detection is enabled.
+	if s.stuckHtlcDetector != nil {
+		routerBackend.StuckHtlcs = s.stuckHtlcDetector.StuckHtlcs
+		routerBackend.SubscribeStuckHtlcs =
+			s.stuckHtlcDetector.SubscribeStuckHtlcs
 	}
 
 	genInvoiceFeatures := func() *lnwire.FeatureVector {
@@ -785,11 +1044,13 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		r.cfg, s.cc, r.cfg.networkDir, macService, atpl, invoiceRegistry,
 		s.htlcSwitch, r.cfg.ActiveNetParams.Params, s.chanRouter,
 		routerBackend, s.nodeSigner, s.graphDB, s.chanStateDB,
-		s.sweeper, tower, s.towerClientMgr, r.cfg.net.ResolveTCPAddr,
+		s.sweeper, s.spendArbiter, s.sweepLedger, s.chainArb, tower,
+		s.towerClientMgr, r.cfg.net.ResolveTCPAddr,
 		genInvoiceFeatures, genAmpInvoiceFeatures,
 		s.getNodeAnnouncement, s.updateAndBrodcastSelfNode, parseAddr,
-		rpcsLog, s.aliasMgr, r.implCfg.AuxDataParser,
-		invoiceHtlcModifier,
+		s, rpcsLog, s.aliasMgr, r.implCfg.AuxDataParser,
+		invoiceHtlcModifier, s.invoiceWebhook, s.subscriptions,
+		s.fsmRegistry,
 	)
 	if err != nil {
 		return err
@@ -1105,6 +1366,36 @@ func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64,
 
 	// We first do a dry run, to sanity check we won't spend our wallet
 	// balance below the reserved amount.
+	_, err = r.authorWalletTx(
+		outputs, feeRate, minConfs, strategy, selectedUtxos,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// If that checks out, we're fairly confident that creating sending to
+	// these outputs will keep the wallet balance above the reserve.
+	tx, err := r.server.cc.Wallet.SendOutputs(
+		selectedUtxos, outputs, feeRate, minConfs, label, strategy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash := tx.TxHash()
+	return &txHash, nil
+}
+
+// authorWalletTx authors, without signing or publishing it, a transaction of
+// the wallet that pays to the given outputs, and makes sure it doesn't spend
+// the wallet balance below the reserved amount.
+//
+// NOTE: The caller MUST hold the coin selection lock.
+func (r *rpcServer) authorWalletTx(outputs []*wire.TxOut,
+	feeRate chainfee.SatPerKWeight, minConfs int32,
+	strategy wallet.CoinSelectionStrategy,
+	selectedUtxos fn.Set[wire.OutPoint]) (*txauthor.AuthoredTx, error) {
+
 	authoredTx, err := r.server.cc.Wallet.CreateSimpleTx(
 		selectedUtxos, outputs, feeRate, minConfs, strategy, true,
 	)
@@ -1124,17 +1415,7 @@ func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64,
 		return nil, err
 	}
 
-	// If that checks out, we're fairly confident that creating sending to
-	// these outputs will keep the wallet balance above the reserve.
-	tx, err := r.server.cc.Wallet.SendOutputs(
-		selectedUtxos, outputs, feeRate, minConfs, label, strategy,
-	)
-	if err != nil {
-		return nil, err
-	}
-
-	txHash := tx.TxHash()
-	return &txHash, nil
+	return authoredTx, nil
 }
 
 // ListUnspent returns useful information about each unspent output owned by
@@ -1382,6 +1663,8 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 		selectOutpoints = fn.NewSet(wireOutpoints...)
 	}
 
+	dryRun := in.DryRun
+
 	// If the send all flag is active, then we'll attempt to sweep all the
 	// coins in the wallet in a single transaction (if possible),
 	// otherwise, we'll respect the amount, and attempt a regular 2-output
@@ -1430,6 +1713,7 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 		// If sending everything to this address would invalidate our
 		// reserved wallet balance, we create a new sweep tx, where
 		// we'll send the reserved value back to our wallet.
+		var changeAddr btcutil.Address
 		if err == lnwallet.ErrReservedValueInvalidated {
 			sweepTxPkg.CancelSweepAttempt()
 
@@ -1440,11 +1724,20 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 			// We'll request a change address from the wallet,
 			// where we'll send this reserved value back to. This
 			// ensures this is an address the wallet knows about,
-			// allowing us to pass the reserved value check.
-			changeAddr, err := r.server.cc.Wallet.NewAddress(
-				lnwallet.TaprootPubkey, true,
-				lnwallet.DefaultAccountName,
-			)
+			// allowing us to pass the reserved value check. A dry
+			// run mustn't derive a new address, so it uses the
+			// last unused one of the same type instead.
+			if dryRun {
+				changeAddr, err = wallet.LastUnusedAddress(
+					lnwallet.TaprootPubkey,
+					lnwallet.DefaultAccountName,
+				)
+			} else {
+				changeAddr, err = wallet.NewAddress(
+					lnwallet.TaprootPubkey, true,
+					lnwallet.DefaultAccountName,
+				)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -1488,6 +1781,25 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 			return nil, err
 		}
 
+		// A dry run only reports the sweep transaction. As the change
+		// address of a dry run differs from the one that would be
+		// used, its txid is only final without a change output.
+		if dryRun {
+			sweepTxPkg.CancelSweepAttempt()
+
+			plan, err := r.planSweepAllTx(
+				sweepTxPkg.SweepTx, changeAddr, feePerKw,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &lnrpc.SendCoinsResponse{
+				Txid:       plan.Txid,
+				DryRunPlan: plan,
+			}, nil
+		}
+
 		rpcsLog.Debugf("Sweeping coins from wallet to addr=%v, "+
 			"with tx=%v", in.Addr, spew.Sdump(sweepTxPkg.SweepTx))
 
@@ -1511,6 +1823,30 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 		// selection (funding, sweep alls, other sends) can proceed
 		// while we instruct the wallet to send this transaction.
 		paymentMap := map[string]int64{targetAddr.String(): in.Amount}
+
+		// A dry run plans the transaction without sending it.
+		if dryRun {
+			outputs, err := addrPairsToOutputs(
+				paymentMap, r.cfg.ActiveNetParams.Params,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			plan, err := r.planWalletTx(
+				dryRunSendCoins, outputs, feePerKw, minConfs,
+				coinSelectionStrategy, selectOutpoints,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			return &lnrpc.SendCoinsResponse{
+				Txid:       plan.Txid,
+				DryRunPlan: plan,
+			}, nil
+		}
+
 		err := wallet.WithCoinSelectLock(func() error {
 			newTXID, err := r.sendCoinsOnChain(
 				paymentMap, feePerKw, minConfs, label,
@@ -2413,11 +2749,32 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 		return err
 	}
 
+	tmpl, err := r.fetchChannelTemplate(in.Template)
+	if err != nil {
+		return err
+	}
+	applyChannelTemplate(in, tmpl)
+
 	req, err := r.parseOpenChannelReq(in, false)
 	if err != nil {
 		return err
 	}
 
+	// A dry run only plans the funding transaction, the plan is the only
+	// update of the stream.
+	if in.DryRun {
+		plan, err := r.planOpenChannel(in, req)
+		if err != nil {
+			return err
+		}
+
+		return updateStream.Send(&lnrpc.OpenStatusUpdate{
+			Update: &lnrpc.OpenStatusUpdate_DryRunPlan{
+				DryRunPlan: plan,
+			},
+		})
+	}
+
 	// If the user has provided a shim, then we'll now augment the based
 	// open channel request with this additional logic.
 	if in.FundingShim != nil {
@@ -2473,7 +2830,7 @@ out:
 		case err := <-errChan:
 			rpcsLog.Errorf("unable to open channel to NodeKey(%x): %v",
 				req.TargetPubkey.SerializeCompressed(), err)
-			return err
+			return openChannelStatusError(err)
 		case fundingUpdate := <-updateChan:
 			rpcsLog.Tracef("[openchannel] sending update: %v",
 				fundingUpdate)
@@ -2508,29 +2865,68 @@ out:
 	return nil
 }
 
-// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
-// call is meant to be consumed by clients to the REST proxy. As with all other
-// sync calls, all byte slices are instead to be populated as hex encoded
-// strings.
-func (r *rpcServer) OpenChannelSync(ctx context.Context,
-	in *lnrpc.OpenChannelRequest) (*lnrpc.ChannelPoint, error) {
+// openChannelStatusError transforms the errors of a failed channel opening
+// that a client may want to handle into a gRPC status error that carries
+// structured details. Other errors are returned unchanged.
+func openChannelStatusError(err error) error {
+	var errInsufficientFunds *chanfunding.ErrInsufficientFunds
+	switch {
+	case errors.As(err, &errInsufficientFunds):
+		return lnrpc.NewDetailedError(
+			codes.FailedPrecondition, err, &lnrpc.ErrorDetails{
+				Code: lnrpc.ErrCodeInsufficientFunds,
+			},
+		)
+
+	case errors.Is(err, ErrPeerNotConnected):
+		return lnrpc.NewDetailedError(
+			codes.Unavailable, err, &lnrpc.ErrorDetails{
+				Code:      lnrpc.ErrCodePeerOffline,
+				Retryable: true,
+			},
+		)
+
+	default:
+		return err
+	}
+}
+
+// OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
+// call is meant to be consumed by clients to the REST proxy. As with all other
+// sync calls, all byte slices are instead to be populated as hex encoded
+// strings.
+func (r *rpcServer) OpenChannelSync(ctx context.Context,
+	in *lnrpc.OpenChannelRequest) (*lnrpc.ChannelPoint, error) {
 
 	if err := r.canOpenChannel(); err != nil {
 		return nil, err
 	}
 
+	tmpl, err := r.fetchChannelTemplate(in.Template)
+	if err != nil {
+		return nil, err
+	}
+	applyChannelTemplate(in, tmpl)
+
 	req, err := r.parseOpenChannelReq(in, true)
 	if err != nil {
 		return nil, err
 	}
 
+	// A channel point can't carry the plan of a dry run, so dry runs are
+	// only supported by OpenChannel.
+	if in.DryRun {
+		return nil, errors.New("dry run not supported by " +
+			"OpenChannelSync, use OpenChannel instead")
+	}
+
 	updateChan, errChan := r.server.OpenChannel(req)
 	select {
 	// If an error occurs them immediately return the error to the client.
 	case err := <-errChan:
 		rpcsLog.Errorf("unable to open channel to NodeKey(%x): %v",
 			req.TargetPubkey.SerializeCompressed(), err)
-		return nil, err
+		return nil, openChannelStatusError(err)
 
 	// Otherwise, wait for the first channel update. The first update sent
 	// is when the funding transaction is broadcast to the network.
@@ -2602,16 +2998,29 @@ func (r *rpcServer) BatchOpenChannel(ctx context.Context,
 		return nil, fmt.Errorf("error fetching best block: %w", err)
 	}
 
+	// The channel template, if any, applies to all channels of the
+	// batch.
+	tmpl, err := r.fetchChannelTemplate(in.Template)
+	if err != nil {
+		return nil, err
+	}
+
 	// So far everything looks good and we can now start the heavy lifting
 	// that's done in the funding package.
 	requestParser := func(req *lnrpc.OpenChannelRequest) (
 		*funding.InitFundingMsg, error) {
 
+		applyChannelTemplate(req, tmpl)
+
 		return r.parseOpenChannelReq(req, false)
 	}
 	channelAbandoner := func(point *wire.OutPoint) error {
 		return r.abandonChan(point, uint32(bestHeight))
 	}
+	allowPartial, err := batchAllowPartialFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 	batcher := funding.NewBatcher(&funding.BatchConfig{
 		RequestParser:    requestParser,
 		ChannelAbandoner: channelAbandoner,
@@ -2620,8 +3029,31 @@ func (r *rpcServer) BatchOpenChannel(ctx context.Context,
 		Wallet:           r.server.cc.Wallet,
 		NetParams:        &r.server.cc.Wallet.Cfg.NetParams,
 		Quit:             r.quit,
+		AllowPartial:     allowPartial,
 	})
+
+	// A dry run only plans the batch funding transaction. As no channel
+	// is opened, no pending channels are returned.
+	if in.DryRun {
+		plan, err := r.planBatchOpenChannel(in, batcher)
+		if err != nil {
+			return nil, err
+		}
+
+		return &lnrpc.BatchOpenChannelResponse{
+			DryRunPlan: plan,
+		}, nil
+	}
+
 	rpcPoints, err := batcher.BatchFund(ctx, in)
+
+	// The outcome of each channel is sent back whether the batch failed
+	// or not, so the client knows which channels to retry.
+	outcomes := batcher.Outcomes()
+	if headerErr := sendBatchOutcomes(ctx, outcomes); headerErr != nil {
+		rpcsLog.Errorf("[batchopenchannel] unable to send channel "+
+			"outcomes: %v", headerErr)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("batch funding failed: %w", err)
 	}
@@ -2649,6 +3081,12 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		return fmt.Errorf("must specify channel point in close channel")
 	}
 
+	// A close that is run as a job is tracked by the job manager, which
+	// persists its updates for the client to stream.
+	if in.AsJob {
+		return r.closeChannelJob(in, updateStream)
+	}
+
 	// If force closing a channel, the fee set in the commitment transaction
 	// is used.
 	if in.Force && (in.SatPerByte != 0 || in.SatPerVbyte != 0 || // nolint:staticcheck
@@ -2657,6 +3095,7 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		return fmt.Errorf("force closing a channel uses a pre-defined fee")
 	}
 
+	dryRun := in.DryRun
 	force := in.Force
 	index := in.ChannelPoint.OutputIndex
 	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
@@ -2679,7 +3118,16 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 	// First, we'll fetch the channel as is, as we'll need to examine it
 	// regardless of if this is a force close or not.
 	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
-	if err != nil {
+	switch {
+	case errors.Is(err, channeldb.ErrChannelNotFound):
+		return lnrpc.NewDetailedError(
+			codes.NotFound, err, &lnrpc.ErrorDetails{
+				Code:         lnrpc.ErrCodeChannelNotFound,
+				ChannelPoint: chanPoint.String(),
+			},
+		)
+
+	case err != nil:
 		return err
 	}
 
@@ -2692,8 +3140,15 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 	if channel.HasChanStatus(channeldb.ChanStatusRestored) ||
 		channel.HasChanStatus(channeldb.ChanStatusLocalDataLoss) {
 
-		return fmt.Errorf("cannot close channel with state: %v",
+		err := fmt.Errorf("cannot close channel with state: %v",
 			channel.ChanStatus())
+
+		return lnrpc.NewDetailedError(
+			codes.FailedPrecondition, err, &lnrpc.ErrorDetails{
+				Code:         lnrpc.ErrCodeChannelStateInvalid,
+				ChannelPoint: chanPoint.String(),
+			},
+		)
 	}
 
 	// Retrieve the best height of the chain, which we'll use to complete
@@ -2708,6 +3163,20 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 	// transaction here rather than going to the switch as we don't require
 	// interaction from the peer.
 	if force {
+		// A dry run only reports our latest commitment transaction,
+		// which is what a force close broadcasts. It doesn't cover the
+		// transactions that sweep its outputs later on.
+		if dryRun {
+			commit := channel.LocalCommitment
+			plan := newDryRunPlan(
+				dryRunForceClose, commit.CommitTx,
+				channel.Capacity, -1,
+				chainfee.SatPerKWeight(commit.FeePerKw), true,
+			)
+
+			return sendCloseDryRunPlan(updateStream, plan)
+		}
+
 		// As we're force closing this channel, as a precaution, we'll
 		// ensure that the switch doesn't continue to see this channel
 		// as eligible for forwarding HTLC's. If the peer is online,
@@ -2770,10 +3239,22 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 				return err
 			}
 			if uint32(bestHeight) < absoluteThawHeight {
-				return fmt.Errorf("cannot co-op close frozen "+
+				err := fmt.Errorf("cannot co-op close frozen "+
 					"channel as initiator until height=%v, "+
 					"(current_height=%v)",
 					absoluteThawHeight, bestHeight)
+
+				// The channel can be closed once it thawed.
+				code := lnrpc.ErrCodeChannelFrozen
+				details := &lnrpc.ErrorDetails{
+					Code:         code,
+					ChannelPoint: chanPoint.String(),
+					Retryable:    true,
+				}
+
+				return lnrpc.NewDetailedError(
+					codes.FailedPrecondition, err, details,
+				)
 			}
 		}
 
@@ -2783,8 +3264,17 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		if _, err := r.server.htlcSwitch.GetLink(channelID); err != nil {
 			rpcsLog.Debugf("Trying to non-force close offline channel with "+
 				"chan_point=%v", chanPoint)
-			return fmt.Errorf("unable to gracefully close channel while peer "+
-				"is offline (try force closing it instead): %v", err)
+			err = fmt.Errorf("unable to gracefully close channel "+
+				"while peer is offline (try force closing it "+
+				"instead): %v", err)
+
+			return lnrpc.NewDetailedError(
+				codes.Unavailable, err, &lnrpc.ErrorDetails{
+					Code:         lnrpc.ErrCodePeerOffline,
+					ChannelPoint: chanPoint.String(),
+					Retryable:    true,
+				},
+			)
 		}
 
 		// Keep the old behavior prior to 0.18.0 - when the user
@@ -2821,30 +3311,29 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		// the htlc switch which will handle the negotiation and
 		// broadcast details.
 
-		var deliveryScript lnwire.DeliveryAddress
-
-		// If a delivery address to close out to was specified, decode it.
-		if len(in.DeliveryAddress) > 0 {
-			// Decode the address provided.
-			addr, err := btcutil.DecodeAddress(
-				in.DeliveryAddress, r.cfg.ActiveNetParams.Params,
-			)
-			if err != nil {
-				return fmt.Errorf("invalid delivery address: "+
-					"%v", err)
-			}
+		// If a delivery to close out to was specified, decode it.
+		delivery := &CloseDelivery{
+			Address: in.DeliveryAddress,
+			Psbt:    in.DeliveryPsbt,
+		}
+		deliveryScript, err := delivery.deliveryScript(
+			r.cfg.ActiveNetParams.Params,
+		)
+		if err != nil {
+			return err
+		}
 
-			if !addr.IsForNet(r.cfg.ActiveNetParams.Params) {
-				return fmt.Errorf("delivery address is not "+
-					"for %s",
-					r.cfg.ActiveNetParams.Params.Name)
+		// The fee of a cooperative close is negotiated with the peer,
+		// so a dry run can only report the fee rate we'd start the
+		// negotiation with.
+		if dryRun {
+			plan := &lnrpc.DryRunPlan{
+				Action:      dryRunCoopClose,
+				Inputs:      []string{chanPoint.String()},
+				SatPerVbyte: uint64(feeRate.FeePerVByte()),
 			}
 
-			// Create a script to pay out to the address provided.
-			deliveryScript, err = txscript.PayToAddrScript(addr)
-			if err != nil {
-				return err
-			}
+			return sendCloseDryRunPlan(updateStream, plan)
 		}
 
 		maxFee := chainfee.SatPerKVByte(
@@ -2876,6 +3365,19 @@ out:
 				"ChannelPoint(%v): %v", chanPoint, err)
 			return err
 		case closingUpdate := <-updateChan:
+			// The progress of the fee negotiation is only streamed
+			// to clients that asked for it, so that existing
+			// clients receive the same updates as before.
+			u, ok := closingUpdate.(*peer.CloseNegotiationUpdate)
+			if ok && !in.StreamFeeUpdates {
+				rpcsLog.Debugf("[closechannel] fee "+
+					"negotiation for ChannelPoint(%v): "+
+					"local_fee=%v, remote_fee=%v",
+					chanPoint, u.LocalFee, u.RemoteFee)
+
+				continue
+			}
+
 			rpcClosingUpdate, err := createRPCCloseUpdate(
 				closingUpdate,
 			)
@@ -2913,6 +3415,12 @@ out:
 					"txid(%v)", h)
 				break out
 			}
+
+		// The context is canceled if the client disconnects, or if the
+		// close is run as a job that is canceled.
+		case <-updateStream.Context().Done():
+			return updateStream.Context().Err()
+
 		case <-r.quit:
 			return nil
 		}
@@ -3008,6 +3516,16 @@ func createRPCCloseUpdate(
 				},
 			},
 		}, nil
+
+	case *peer.CloseNegotiationUpdate:
+		return &lnrpc.CloseStatusUpdate{
+			Update: &lnrpc.CloseStatusUpdate_FeeUpdate{
+				FeeUpdate: &lnrpc.ClosingFeeUpdate{
+					LocalFeeSat:  int64(u.LocalFee),
+					RemoteFeeSat: int64(u.RemoteFee),
+				},
+			},
+		}, nil
 	}
 
 	return nil, errors.New("unknown close status update")
@@ -4460,6 +4978,10 @@ func (r *rpcServer) LookupHtlcResolution(
 func (r *rpcServer) ListChannels(ctx context.Context,
 	in *lnrpc.ListChannelsRequest) (*lnrpc.ListChannelsResponse, error) {
 
+	if in.Paginate {
+		return r.listChannelsPage(ctx, in)
+	}
+
 	if in.ActiveOnly && in.InactiveOnly {
 		return nil, fmt.Errorf("either `active_only` or " +
 			"`inactive_only` can be set, but not both")
@@ -4549,6 +5071,57 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 	return resp, nil
 }
 
+// HtlcExpiryRisks returns the heights at which the outstanding HTLCs of every
+// active channel force the channel to be closed on-chain.
+func (r *rpcServer) HtlcExpiryRisks(_ context.Context,
+	_ *lnrpc.HtlcExpiryRisksRequest) (*lnrpc.HtlcExpiryRisksResponse,
+	error) {
+
+	_, bestHeight, err := r.server.cc.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	risks, err := r.server.chainArb.HtlcExpiryRisks()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.HtlcExpiryRisksResponse{
+		BestHeight: uint32(bestHeight),
+		Channels:   make([]*lnrpc.ChannelExpiryRisk, 0, len(risks)),
+	}
+	for _, risk := range risks {
+		channel := &lnrpc.ChannelExpiryRisk{
+			ChannelPoint: risk.ChanPoint.String(),
+			Htlcs: make(
+				[]*lnrpc.HtlcExpiryRisk, 0, len(risk.Htlcs),
+			),
+		}
+		for _, htlc := range risk.Htlcs {
+			channel.Htlcs = append(channel.Htlcs, &lnrpc.HtlcExpiryRisk{
+				HtlcIndex:          htlc.HtlcIndex,
+				Incoming:           htlc.Incoming,
+				HashLock:           htlc.RHash[:],
+				AmtMsat:            uint64(htlc.Amt),
+				ExpirationHeight:   htlc.RefundTimeout,
+				BroadcastDelta:     htlc.BroadcastDelta,
+				ForceCloseHeight:   htlc.ForceCloseHeight,
+				TriggersForceClose: htlc.TriggersForceClose,
+			})
+		}
+
+		channel.ForceCloseHeight = risk.ForceCloseHeight().UnwrapOr(0)
+		channel.BlocksUntilForceClose = risk.BlocksUntilForceClose(
+			uint32(bestHeight),
+		).UnwrapOr(0)
+
+		resp.Channels = append(resp.Channels, channel)
+	}
+
+	return resp, nil
+}
+
 // rpcCommitmentType takes the channel type and converts it to an rpc commitment
 // type value.
 func rpcCommitmentType(chanType channeldb.ChannelType) lnrpc.CommitmentType {
@@ -4881,7 +5454,6 @@ func (r *rpcServer) createRPCClosedChannel(
 	nodeID := hex.EncodeToString(nodePub.SerializeCompressed())
 
 	var (
-		closeType      lnrpc.ChannelCloseSummary_ClosureType
 		openInit       lnrpc.Initiator
 		closeInitiator lnrpc.Initiator
 		err            error
@@ -4897,20 +5469,7 @@ func (r *rpcServer) createRPCClosedChannel(
 	}
 
 	// Convert the close type to rpc type.
-	switch dbChannel.CloseType {
-	case channeldb.CooperativeClose:
-		closeType = lnrpc.ChannelCloseSummary_COOPERATIVE_CLOSE
-	case channeldb.LocalForceClose:
-		closeType = lnrpc.ChannelCloseSummary_LOCAL_FORCE_CLOSE
-	case channeldb.RemoteForceClose:
-		closeType = lnrpc.ChannelCloseSummary_REMOTE_FORCE_CLOSE
-	case channeldb.BreachClose:
-		closeType = lnrpc.ChannelCloseSummary_BREACH_CLOSE
-	case channeldb.FundingCanceled:
-		closeType = lnrpc.ChannelCloseSummary_FUNDING_CANCELED
-	case channeldb.Abandoned:
-		closeType = lnrpc.ChannelCloseSummary_ABANDONED
-	}
+	closeType := rpcCloseType(dbChannel.CloseType)
 
 	dbScid := dbChannel.ShortChanID
 
@@ -4994,6 +5553,26 @@ func (r *rpcServer) createRPCClosedChannel(
 	return channel, nil
 }
 
+// rpcCloseType converts the close type of a channel to its rpc type.
+func rpcCloseType(
+	closeType channeldb.ClosureType) lnrpc.ChannelCloseSummary_ClosureType {
+
+	switch closeType {
+	case channeldb.LocalForceClose:
+		return lnrpc.ChannelCloseSummary_LOCAL_FORCE_CLOSE
+	case channeldb.RemoteForceClose:
+		return lnrpc.ChannelCloseSummary_REMOTE_FORCE_CLOSE
+	case channeldb.BreachClose:
+		return lnrpc.ChannelCloseSummary_BREACH_CLOSE
+	case channeldb.FundingCanceled:
+		return lnrpc.ChannelCloseSummary_FUNDING_CANCELED
+	case channeldb.Abandoned:
+		return lnrpc.ChannelCloseSummary_ABANDONED
+	default:
+		return lnrpc.ChannelCloseSummary_COOPERATIVE_CLOSE
+	}
+}
+
 func rpcChannelResolution(report *channeldb.ResolverReport) (*lnrpc.Resolution,
 	error) {
 
@@ -5246,6 +5825,124 @@ func (r *rpcServer) SubscribeChannelEvents(req *lnrpc.ChannelEventSubscription,
 	}
 }
 
+// SubscribeChannelResolutions returns a uni-directional stream (server ->
+// client) for notifying the client of the on-chain resolution progress of
+// closed channels.
+func (r *rpcServer) SubscribeChannelResolutions(
+	req *lnrpc.ChannelResolutionSubscription,
+	updateStream lnrpc.Lightning_SubscribeChannelResolutionsServer) error {
+
+	// If the client is only interested in a single channel, we'll filter
+	// out the events of all other channels.
+	var filter *wire.OutPoint
+	if req.ChannelPoint != nil {
+		txid, err := lnrpc.GetChanPointFundingTxid(req.ChannelPoint)
+		if err != nil {
+			return err
+		}
+
+		filter = &wire.OutPoint{
+			Hash:  *txid,
+			Index: req.ChannelPoint.OutputIndex,
+		}
+	}
+
+	resolutionSub, err := r.server.chainArb.SubscribeResolutionEvents()
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the resources for the client is cleaned up once either
+	// the server, or client exits.
+	defer resolutionSub.Cancel()
+
+	for {
+		select {
+		case e := <-resolutionSub.Updates():
+			update, chanPoint, err := rpcResolutionUpdate(e)
+			if err != nil {
+				return err
+			}
+
+			if filter != nil && *filter != chanPoint {
+				continue
+			}
+
+			if err := updateStream.Send(update); err != nil {
+				return err
+			}
+
+		// The response stream's context for whatever reason has been
+		// closed. If context is closed by an exceeded deadline we will
+		// return an error.
+		case <-updateStream.Context().Done():
+			if errors.Is(updateStream.Context().Err(), context.Canceled) {
+				return nil
+			}
+			return updateStream.Context().Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// rpcResolutionUpdate converts a resolution event of the chain arbitrator to
+// its rpc update and returns the funding outpoint of the channel it belongs
+// to.
+func rpcResolutionUpdate(e interface{}) (*lnrpc.ChannelResolutionUpdate,
+	wire.OutPoint, error) {
+
+	switch event := e.(type) {
+	case *contractcourt.ResolutionCommitConfirmed:
+		return &lnrpc.ChannelResolutionUpdate{
+			ChannelPoint: event.ChanPoint.String(),
+			Update: &lnrpc.ChannelResolutionUpdate_CommitConfirmed{
+				CommitConfirmed: &lnrpc.ResolutionCommitConfirmed{
+					ClosingTxHash: event.ClosingTxid.String(),
+					CloseType: rpcCloseType(
+						event.CloseType,
+					),
+					CloseHeight:    event.CloseHeight,
+					SettledBalance: int64(event.SettledBalance),
+					TimeLockedBalance: int64(
+						event.TimeLockedBalance,
+					),
+				},
+			},
+		}, event.ChanPoint, nil
+
+	case *contractcourt.ResolutionOutputResolved:
+		resolution, err := rpcChannelResolution(&event.Report)
+		if err != nil {
+			return nil, wire.OutPoint{}, err
+		}
+
+		return &lnrpc.ChannelResolutionUpdate{
+			ChannelPoint: event.ChanPoint.String(),
+			Update: &lnrpc.ChannelResolutionUpdate_OutputResolved{
+				OutputResolved: resolution,
+			},
+		}, event.ChanPoint, nil
+
+	case *contractcourt.ResolutionFullyResolved:
+		return &lnrpc.ChannelResolutionUpdate{
+			ChannelPoint: event.ChanPoint.String(),
+			Update: &lnrpc.ChannelResolutionUpdate_FullyResolved{
+				FullyResolved: &lnrpc.ResolutionFullyResolved{
+					ClaimedBalance: int64(
+						event.ClaimedBalance,
+					),
+				},
+			},
+		}, event.ChanPoint, nil
+
+	default:
+		return nil, wire.OutPoint{}, fmt.Errorf("unexpected "+
+			"resolution event: %T", e)
+	}
+}
+
 // paymentStream enables different types of payment streams, such as:
 // lnrpc.Lightning_SendPaymentServer and lnrpc.Lightning_SendToRouteServer to
 // execute sendPayment. We use this struct as a sort of bridge to enable code
@@ -5661,6 +6358,7 @@ func (r *rpcServer) dispatchPaymentIntent(
 			FinalCLTVDelta:     payIntent.cltvDelta,
 			FeeLimit:           payIntent.feeLimit,
 			CltvLimit:          payIntent.cltvLimit,
+			CltvBudget:         r.routerBackend.CltvBudget,
 			RouteHints:         payIntent.routeHints,
 			OutgoingChannelIDs: payIntent.outgoingChannelIDs,
 			LastHop:            payIntent.lastHop,
@@ -6108,6 +6806,26 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 				blindingRestrictions,
 			)
 		},
+		ChannelUptime: func(chanPoint wire.OutPoint,
+			peer route.Vertex) (time.Duration, time.Duration,
+			error) {
+
+			info, err := r.server.chanEventStore.GetChanInfo(
+				chanPoint, peer,
+			)
+			if err != nil {
+				return 0, 0, err
+			}
+
+			return info.Uptime, info.Lifetime, nil
+		},
+		PeerFlapCount: func(peer route.Vertex) (int, error) {
+			count, _, err := r.server.chanEventStore.FlapCount(peer)
+			return count, err
+		},
+		FiatRateProvider: r.server.implCfg.FiatRateProvider.UnwrapOr(
+			nil,
+		),
 	}
 
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
@@ -6160,6 +6878,25 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		BlindedPathCfg:  blindedPathCfg,
 	}
 
+	if policy := invoice.PartialPaymentPolicy; policy != nil {
+		addInvoiceData.PartialPayment = invoices.PartialPaymentPolicy{
+			Tolerance:    lnwire.MilliSatoshi(policy.ToleranceMsat),
+			MinSettleAmt: lnwire.MilliSatoshi(policy.MinSettleMsat),
+		}
+	}
+
+	constraints, err := invoicesrpc.UnmarshallHtlcConstraints(
+		invoice.HtlcConstraints,
+	)
+	if err != nil {
+		return nil, err
+	}
+	addInvoiceData.HtlcConstraints = constraints
+	addInvoiceData.FiatQuote = invoicesrpc.UnmarshallFiatQuote(
+		invoice.FiatQuote,
+	)
+	addInvoiceData.FiatCurrency = invoice.FiatCurrency
+
 	if invoice.RPreimage != nil {
 		preimage, err := lntypes.MakePreimage(invoice.RPreimage)
 		if err != nil {
@@ -6254,6 +6991,10 @@ func (r *rpcServer) LookupInvoice(ctx context.Context,
 func (r *rpcServer) ListInvoices(ctx context.Context,
 	req *lnrpc.ListInvoiceRequest) (*lnrpc.ListInvoiceResponse, error) {
 
+	if req.Paginate {
+		return r.listInvoicesPage(ctx, req)
+	}
+
 	// If the number of invoices was not specified, then we'll default to
 	// returning the latest 100 invoices.
 	if req.NumMaxInvoices == 0 {
@@ -6482,8 +7223,11 @@ func (r *rpcServer) GetTransactions(ctx context.Context,
 func (r *rpcServer) DescribeGraph(ctx context.Context,
 	req *lnrpc.ChannelGraphRequest) (*lnrpc.ChannelGraph, error) {
 
-	resp := &lnrpc.ChannelGraph{}
-	includeUnannounced := req.IncludeUnannounced
+	// Pages of the graph are read straight from the database, bypassing
+	// the cache of the full graph.
+	if req.Paginate {
+		return r.describeGraphPage(req)
+	}
 
 	// Check to see if the cache is already populated, if so then we can
 	// just return it directly.
@@ -6502,7 +7246,27 @@ func (r *rpcServer) DescribeGraph(ctx context.Context,
 	// Obtain the pointer to the global singleton channel graph, this will
 	// provide a consistent view of the graph due to bolt db's
 	// transactional model.
-	graph := r.server.graphDB
+	resp, err := describeGraph(r.server.graphDB, req.IncludeUnannounced)
+	if err != nil {
+		return nil, err
+	}
+
+	// We still have the mutex held, so we can safely populate the cache
+	// now to save on GC churn for this query, but only if the cache isn't
+	// disabled.
+	if graphCacheActive {
+		r.describeGraphResp = resp
+	}
+
+	return resp, nil
+}
+
+// describeGraph returns a description of the given channel graph, which only
+// includes the unannounced channels if requested.
+func describeGraph(graph *channeldb.ChannelGraph,
+	includeUnannounced bool) (*lnrpc.ChannelGraph, error) {
+
+	resp := &lnrpc.ChannelGraph{}
 
 	// First iterate through all the known nodes (connected or unconnected
 	// within the graph), collating their current state into the RPC
@@ -6541,13 +7305,6 @@ func (r *rpcServer) DescribeGraph(ctx context.Context,
 		return nil, err
 	}
 
-	// We still have the mutex held, so we can safely populate the cache
-	// now to save on GC churn for this query, but only if the cache isn't
-	// disabled.
-	if graphCacheActive {
-		r.describeGraphResp = resp
-	}
-
 	return resp, nil
 }
 
@@ -6666,20 +7423,206 @@ func (r *rpcServer) GetNodeMetrics(ctx context.Context,
 	req *lnrpc.NodeMetricsRequest) (*lnrpc.NodeMetricsResponse, error) {
 
 	// Get requested metric types.
-	getCentrality := false
+	getCentrality, getReachability := false, false
 	for _, t := range req.Types {
-		if t == lnrpc.NodeMetricType_BETWEENNESS_CENTRALITY {
+		switch t {
+		case lnrpc.NodeMetricType_BETWEENNESS_CENTRALITY:
 			getCentrality = true
+
+		case lnrpc.NodeMetricType_REACHABILITY:
+			getReachability = true
 		}
 	}
 
-	// Only centrality can be requested for now.
-	if !getCentrality {
+	if !getCentrality && !getReachability {
 		return nil, nil
 	}
 
 	resp := &lnrpc.NodeMetricsResponse{
 		BetweennessCentrality: make(map[string]*lnrpc.FloatMetric),
+		Reachability:          make(map[string]uint32),
+	}
+
+	if getReachability {
+		reachability, err := r.nodeReachability()
+		if err != nil {
+			return nil, err
+		}
+
+		for nodeID, val := range reachability {
+			resp.Reachability[hex.EncodeToString(nodeID[:])] =
+				uint32(val)
+		}
+	}
+
+	if !getCentrality {
+		return resp, nil
+	}
+
+	normalized, centrality, err := r.nodeCentrality()
+	if err != nil {
+		return nil, err
+	}
+
+	// Fill normalized and non normalized centrality.
+	for nodeID, val := range normalized {
+		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])] =
+			&lnrpc.FloatMetric{
+				NormalizedValue: val,
+			}
+	}
+
+	for nodeID, val := range centrality {
+		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])].Value = val
+	}
+
+	return resp, nil
+}
+
+// defaultNodeMetricsWindow is the window that node metrics are aggregated
+// over if none is requested.
+const defaultNodeMetricsWindow = 24 * time.Hour
+
+// peerPerformance accumulates the metrics of the channels with a peer.
+type peerPerformance struct {
+	numChannels uint32
+	monitored   time.Duration
+	uptime      time.Duration
+	incoming    chanfitness.HtlcStats
+	outgoing    chanfitness.HtlcStats
+}
+
+// GetNodeMetricsV2 returns the historical forwarding performance and peer
+// downtime of our open channels and their peers over the requested window.
+func (r *rpcServer) GetNodeMetricsV2(_ context.Context,
+	req *lnrpc.NodeMetricsV2Request) (*lnrpc.NodeMetricsV2Response,
+	error) {
+
+	// The channel event store isn't started before the server is.
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	window := time.Duration(req.WindowSeconds) * time.Second
+	if window == 0 {
+		window = defaultNodeMetricsWindow
+	}
+
+	chanIDs := fn.NewSet(req.ChanIds...)
+
+	channels, err := r.server.chanStateDB.FetchAllOpenChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.NodeMetricsV2Response{}
+	peers := make(map[route.Vertex]*peerPerformance)
+	for _, channel := range channels {
+		chanID := channel.ShortChannelID.ToUint64()
+		if len(chanIDs) > 0 && !chanIDs.Contains(chanID) {
+			continue
+		}
+
+		peer := route.NewVertex(channel.IdentityPub)
+		metrics, err := r.server.chanEventStore.GetChannelMetrics(
+			channel.FundingOutpoint, peer, channel.ShortChannelID,
+			window,
+		)
+
+		// The event store may not know about a channel that was just
+		// opened yet, so we skip it instead of failing the call.
+		switch {
+		case errors.Is(err, chanfitness.ErrChannelNotFound),
+			errors.Is(err, chanfitness.ErrPeerNotFound):
+
+			rpcsLog.Debugf("Channel %v not found by channel event "+
+				"store", channel.FundingOutpoint)
+
+			continue
+
+		case err != nil:
+			return nil, err
+		}
+
+		resp.Channels = append(resp.Channels, &lnrpc.ChannelPerformance{
+			ChanId:           chanID,
+			ChannelPoint:     channel.FundingOutpoint.String(),
+			RemotePubkey:     peer.String(),
+			MonitoredSeconds: int64(metrics.Monitored.Seconds()),
+			UptimeSeconds:    int64(metrics.Uptime.Seconds()),
+			DowntimeSeconds:  int64(metrics.Downtime().Seconds()),
+			Incoming:         marshalHtlcMetrics(metrics.Incoming),
+			Outgoing:         marshalHtlcMetrics(metrics.Outgoing),
+		})
+
+		perf, ok := peers[peer]
+		if !ok {
+			perf = &peerPerformance{}
+			peers[peer] = perf
+		}
+
+		// The uptime of all channels with a peer is the uptime of the
+		// peer, so we report it for the longest monitored channel.
+		perf.numChannels++
+		if metrics.Monitored > perf.monitored {
+			perf.monitored = metrics.Monitored
+			perf.uptime = metrics.Uptime
+		}
+		perf.incoming.Merge(metrics.Incoming)
+		perf.outgoing.Merge(metrics.Outgoing)
+	}
+
+	for peer, perf := range peers {
+		resp.Peers = append(resp.Peers, &lnrpc.PeerPerformance{
+			PubKey:           peer.String(),
+			NumChannels:      perf.numChannels,
+			MonitoredSeconds: int64(perf.monitored.Seconds()),
+			UptimeSeconds:    int64(perf.uptime.Seconds()),
+			DowntimeSeconds: int64(
+				(perf.monitored - perf.uptime).Seconds(),
+			),
+			Incoming: marshalHtlcMetrics(&perf.incoming),
+			Outgoing: marshalHtlcMetrics(&perf.outgoing),
+		})
+	}
+	sort.Slice(resp.Peers, func(i, j int) bool {
+		return resp.Peers[i].PubKey < resp.Peers[j].PubKey
+	})
+
+	return resp, nil
+}
+
+// marshalHtlcMetrics converts the forwarding stats of a channel into their
+// RPC counterpart.
+func marshalHtlcMetrics(stats *chanfitness.HtlcStats) *lnrpc.HtlcMetrics {
+	return &lnrpc.HtlcMetrics{
+		NumSettled:        stats.NumSettled,
+		NumFailed:         stats.NumFailed,
+		SettledVolumeMsat: uint64(stats.SettledVolume),
+		SuccessRate:       stats.SuccessRate(),
+		AvgHoldTimeMs:     stats.AverageHoldTime().Milliseconds(),
+	}
+}
+
+// nodeCentrality returns the normalized and non normalized betweenness
+// centrality of each node in the graph. If graph analytics are enabled, the
+// most recently computed values are returned. Otherwise they are calculated
+// on the fly.
+func (r *rpcServer) nodeCentrality() (map[autopilot.NodeID]float64,
+	map[autopilot.NodeID]float64, error) {
+
+	if r.server.graphAnalytics != nil {
+		stats, err := r.server.graphAnalytics.Stats()
+		switch {
+		case err == nil:
+			return stats.NormalizedCentrality, stats.Centrality,
+				nil
+
+		// If the analytics haven't been computed yet, we fall back to
+		// calculating the centrality ourselves.
+		case !errors.Is(err, autopilot.ErrAnalyticsNotReady):
+			return nil, nil, err
+		}
 	}
 
 	// Obtain the pointer to the global singleton channel graph, this will
@@ -6694,24 +7637,70 @@ func (r *rpcServer) GetNodeMetrics(ctx context.Context,
 		runtime.NumCPU(),
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if err := centralityMetric.Refresh(channelGraph); err != nil {
+		return nil, nil, err
+	}
+
+	return centralityMetric.GetMetric(true),
+		centralityMetric.GetMetric(false), nil
+}
+
+// nodeReachability returns the number of nodes that can be reached from each
+// node in the graph. If graph analytics are enabled, the most recently
+// computed values are returned. Otherwise they are calculated on the fly.
+func (r *rpcServer) nodeReachability() (map[autopilot.NodeID]int, error) {
+	if r.server.graphAnalytics != nil {
+		stats, err := r.server.graphAnalytics.Stats()
+		switch {
+		case err == nil:
+			return stats.Reachability(), nil
+
+		// If the analytics haven't been computed yet, we fall back to
+		// calculating the reachability ourselves.
+		case !errors.Is(err, autopilot.ErrAnalyticsNotReady):
+			return nil, err
+		}
+	}
+
+	return autopilot.Reachability(
+		autopilot.ChannelGraphFromDatabase(r.server.graphDB),
+	)
+}
+
+// GetGraphMetrics returns the most recent analytics of the channel graph, such
+// as its connected components and bridge channels.
+func (r *rpcServer) GetGraphMetrics(_ context.Context,
+	_ *lnrpc.GraphMetricsRequest) (*lnrpc.GraphMetricsResponse, error) {
+
+	if r.server.graphAnalytics == nil {
+		return nil, status.Error(codes.FailedPrecondition, "graph "+
+			"analytics are disabled, enable them with "+
+			"routing.graph-analytics")
+	}
+
+	stats, err := r.server.graphAnalytics.Stats()
+	if err != nil {
 		return nil, err
 	}
 
-	// Fill normalized and non normalized centrality.
-	centrality := centralityMetric.GetMetric(true)
-	for nodeID, val := range centrality {
-		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])] =
-			&lnrpc.FloatMetric{
-				NormalizedValue: val,
-			}
+	resp := &lnrpc.GraphMetricsResponse{
+		Timestamp:   stats.Timestamp.Unix(),
+		NumNodes:    uint32(stats.NumNodes),
+		NumChannels: uint32(stats.NumChannels),
+		Diameter:    stats.Diameter,
 	}
 
-	centrality = centralityMetric.GetMetric(false)
-	for nodeID, val := range centrality {
-		resp.BetweennessCentrality[hex.EncodeToString(nodeID[:])].Value = val
+	for _, size := range stats.ComponentSizes {
+		resp.ComponentSizes = append(resp.ComponentSizes, uint32(size))
+	}
+
+	for _, bridge := range stats.Bridges {
+		resp.Bridges = append(resp.Bridges, &lnrpc.GraphBridge{
+			Node1Pub: hex.EncodeToString(bridge.Node1[:]),
+			Node2Pub: hex.EncodeToString(bridge.Node2[:]),
+		})
 	}
 
 	return resp, nil
@@ -7043,59 +8032,14 @@ func (r *rpcServer) StopDaemon(_ context.Context,
 // review of the responding node. Events notified include: new nodes coming
 // online, nodes updating their authenticated attributes, new channels being
 // advertised, updates in the routing policy for a directional channel edge,
-// and finally when prior channels are closed on-chain.
+// and finally when prior channels are closed on-chain. The updates are
+// numbered, so the subscription can be resumed after a reconnect.
 func (r *rpcServer) SubscribeChannelGraph(req *lnrpc.GraphTopologySubscription,
 	updateStream lnrpc.Lightning_SubscribeChannelGraphServer) error {
 
-	// First, we start by subscribing to a new intent to receive
-	// notifications from the channel router.
-	client, err := r.server.graphBuilder.SubscribeTopology()
-	if err != nil {
-		return err
-	}
-
-	// Ensure that the resources for the topology update client is cleaned
-	// up once either the server, or client exists.
-	defer client.Cancel()
-
-	for {
-		select {
-
-		// A new update has been sent by the channel router, we'll
-		// marshal it into the form expected by the gRPC client, then
-		// send it off.
-		case topChange, ok := <-client.TopologyChanges:
-			// If the second value from the channel read is nil,
-			// then this means that the channel router is exiting
-			// or the notification client was canceled. So we'll
-			// exit early.
-			if !ok {
-				return errors.New("server shutting down")
-			}
-
-			// Convert the struct from the channel router into the
-			// form expected by the gRPC service then send it off
-			// to the client.
-			graphUpdate := marshallTopologyChange(topChange)
-			if err := updateStream.Send(graphUpdate); err != nil {
-				return err
-			}
-
-		// The response stream's context for whatever reason has been
-		// closed. If context is closed by an exceeded deadline
-		// we will return an error.
-		case <-updateStream.Context().Done():
-			if errors.Is(updateStream.Context().Err(), context.Canceled) {
-				return nil
-			}
-			return updateStream.Context().Err()
-
-		// The server is quitting, so we'll exit immediately. Returning
-		// nil will close the clients read end of the stream.
-		case <-r.quit:
-			return nil
-		}
-	}
+	return r.subscribeChannelGraph(
+		updateStream.Context(), req, updateStream.Send,
+	)
 }
 
 // marshallTopologyChange performs a mapping from the topology change struct
@@ -7213,6 +8157,10 @@ func marshallTopologyChange(
 func (r *rpcServer) ListPayments(ctx context.Context,
 	req *lnrpc.ListPaymentsRequest) (*lnrpc.ListPaymentsResponse, error) {
 
+	if req.Paginate {
+		return r.listPaymentsPage(ctx, req)
+	}
+
 	// If both dates are set, we check that the start date is less than the
 	// end date, otherwise we'll get an empty result.
 	if req.CreationDateStart != 0 && req.CreationDateEnd != 0 {
@@ -7774,6 +8722,10 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 	req *lnrpc.ForwardingHistoryRequest) (*lnrpc.ForwardingHistoryResponse,
 	error) {
 
+	if req.Paginate {
+		return r.forwardingHistoryPage(ctx, req)
+	}
+
 	// Before we perform the queries below, we'll instruct the switch to
 	// flush any pending events to disk. This ensure we get a complete
 	// snapshot at this particular time.
@@ -7782,45 +8734,6 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 			"events: %v", err)
 	}
 
-	var (
-		startTime, endTime time.Time
-
-		numEvents uint32
-	)
-
-	// startTime defaults to the Unix epoch (0 unixtime, or
-	// midnight 01-01-1970).
-	startTime = time.Unix(int64(req.StartTime), 0)
-
-	// If the end time wasn't specified, assume a default end time of now.
-	if req.EndTime == 0 {
-		now := time.Now()
-		endTime = now
-	} else {
-		endTime = time.Unix(int64(req.EndTime), 0)
-	}
-
-	// If the number of events wasn't specified, then we'll default to
-	// returning the last 100 events.
-	numEvents = req.NumMaxEvents
-	if numEvents == 0 {
-		numEvents = 100
-	}
-
-	// Next, we'll map the proto request into a format that is understood by
-	// the forwarding log.
-	eventQuery := channeldb.ForwardingEventQuery{
-		StartTime:    startTime,
-		EndTime:      endTime,
-		IndexOffset:  req.IndexOffset,
-		NumMaxEvents: numEvents,
-	}
-	timeSlice, err := r.server.miscDB.ForwardingLog().Query(eventQuery)
-	if err != nil {
-		return nil, fmt.Errorf("unable to query forwarding log: %w",
-			err)
-	}
-
 	// chanToPeerAlias caches previously looked up channel information.
 	chanToPeerAlias := make(map[lnwire.ShortChannelID]string)
 
@@ -7861,6 +8774,58 @@ func (r *rpcServer) ForwardingHistory(ctx context.Context,
 		return peer.Alias, nil
 	}
 
+	return queryForwardingHistory(
+		r.server.miscDB.ForwardingLog(), req, getRemoteAlias,
+	)
+}
+
+// queryForwardingHistory queries the forwarding log for the events matching
+// the request. If requested, the aliases of the peers are looked up with the
+// given function.
+func queryForwardingHistory(fwdLog *channeldb.ForwardingLog,
+	req *lnrpc.ForwardingHistoryRequest,
+	getRemoteAlias func(lnwire.ShortChannelID) (string, error)) (
+	*lnrpc.ForwardingHistoryResponse, error) {
+
+	var (
+		startTime, endTime time.Time
+
+		numEvents uint32
+	)
+
+	// startTime defaults to the Unix epoch (0 unixtime, or
+	// midnight 01-01-1970).
+	startTime = time.Unix(int64(req.StartTime), 0)
+
+	// If the end time wasn't specified, assume a default end time of now.
+	if req.EndTime == 0 {
+		now := time.Now()
+		endTime = now
+	} else {
+		endTime = time.Unix(int64(req.EndTime), 0)
+	}
+
+	// If the number of events wasn't specified, then we'll default to
+	// returning the last 100 events.
+	numEvents = req.NumMaxEvents
+	if numEvents == 0 {
+		numEvents = 100
+	}
+
+	// Next, we'll map the proto request into a format that is understood by
+	// the forwarding log.
+	eventQuery := channeldb.ForwardingEventQuery{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		IndexOffset:  req.IndexOffset,
+		NumMaxEvents: numEvents,
+	}
+	timeSlice, err := fwdLog.Query(eventQuery)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query forwarding log: %w",
+			err)
+	}
+
 	// TODO(roasbeef): add settlement latency?
 	//  * use FPE on all records?
 
@@ -8117,6 +9082,52 @@ func (r *rpcServer) ExportAllChannelBackups(ctx context.Context,
 	return r.createBackupSnapshot(allUnpackedBackups)
 }
 
+// ExportEmergencyCloseBundle returns the latest force close txns of all active
+// channels, together with the PSBTs that sweep their outputs, encrypted with
+// the channel backup key.
+func (r *rpcServer) ExportEmergencyCloseBundle(_ context.Context,
+	_ *lnrpc.EmergencyCloseBundleRequest) (*lnrpc.EmergencyCloseBundle,
+	error) {
+
+	bundle, err := r.server.chainArb.EmergencyCloseBundle(
+		r.cfg.ActiveNetParams.HDCoinType,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create emergency close "+
+			"bundle: %w", err)
+	}
+
+	var b bytes.Buffer
+	if err := bundle.Encode(&b, r.server.cc.KeyRing); err != nil {
+		return nil, fmt.Errorf("unable to encode emergency close "+
+			"bundle: %w", err)
+	}
+
+	resp := &lnrpc.EmergencyCloseBundle{
+		Channels: make(
+			[]*lnrpc.EmergencyCloseChannel, 0, len(bundle.Entries),
+		),
+		Bundle: b.Bytes(),
+	}
+	for _, entry := range bundle.Entries {
+		chanPoint := &lnrpc.ChannelPoint{
+			FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+				FundingTxidBytes: entry.ChanPoint.Hash[:],
+			},
+			OutputIndex: entry.ChanPoint.Index,
+		}
+
+		channel := &lnrpc.EmergencyCloseChannel{
+			ChanPoint:     chanPoint,
+			CommitHeight:  entry.CommitHeight,
+			NumSweepPsbts: uint32(len(entry.SweepPsbts)),
+		}
+		resp.Channels = append(resp.Channels, channel)
+	}
+
+	return resp, nil
+}
+
 // RestoreChannelBackups accepts a set of singular channel backups, or a single
 // encrypted multi-chan backup and attempts to recover any funds remaining
 // within the channel. If we're able to unpack the backup, then the new channel
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/contractcourt"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/stretchr/testify/assert"
@@ -127,3 +130,69 @@ func TestRpcCommitmentType(t *testing.T) {
 		})
 	}
 }
+
+// TestRpcResolutionUpdate asserts that the resolution events of the chain
+// arbitrator are converted to their rpc updates.
+func TestRpcResolutionUpdate(t *testing.T) {
+	t.Parallel()
+
+	chanPoint := wire.OutPoint{Hash: chainhash.Hash{1}, Index: 2}
+	closingTxid := chainhash.Hash{3}
+
+	update, op, err := rpcResolutionUpdate(
+		&contractcourt.ResolutionCommitConfirmed{
+			ChanPoint:         chanPoint,
+			ClosingTxid:       closingTxid,
+			CloseType:         channeldb.RemoteForceClose,
+			CloseHeight:       100,
+			SettledBalance:    1_000,
+			TimeLockedBalance: 2_000,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, chanPoint, op)
+	require.Equal(t, chanPoint.String(), update.ChannelPoint)
+	require.Equal(t, &lnrpc.ResolutionCommitConfirmed{
+		ClosingTxHash:     closingTxid.String(),
+		CloseType:         lnrpc.ChannelCloseSummary_REMOTE_FORCE_CLOSE,
+		CloseHeight:       100,
+		SettledBalance:    1_000,
+		TimeLockedBalance: 2_000,
+	}, update.GetCommitConfirmed())
+
+	update, op, err = rpcResolutionUpdate(
+		&contractcourt.ResolutionOutputResolved{
+			ChanPoint: chanPoint,
+			Report: channeldb.ResolverReport{
+				OutPoint:        wire.OutPoint{Index: 1},
+				Amount:          500,
+				ResolverType:    channeldb.ResolverTypeOutgoingHtlc,
+				ResolverOutcome: channeldb.ResolverOutcomeTimeout,
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, chanPoint, op)
+
+	resolution := update.GetOutputResolved()
+	require.NotNil(t, resolution)
+	require.Equal(t, lnrpc.ResolutionType_OUTGOING_HTLC,
+		resolution.ResolutionType)
+	require.Equal(t, lnrpc.ResolutionOutcome_TIMEOUT, resolution.Outcome)
+	require.EqualValues(t, 500, resolution.AmountSat)
+
+	update, op, err = rpcResolutionUpdate(
+		&contractcourt.ResolutionFullyResolved{
+			ChanPoint:      chanPoint,
+			ClaimedBalance: 3_000,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, chanPoint, op)
+	require.EqualValues(
+		t, 3_000, update.GetFullyResolved().ClaimedBalance,
+	)
+
+	_, _, err = rpcResolutionUpdate(struct{}{})
+	require.Error(t, err)
+}
@@ -0,0 +1,362 @@
+package lnd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/build"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// runtimeOverridesFilename is the name of the file in the lnd
+	// directory that holds the options that were changed at runtime.
+	runtimeOverridesFilename = "runtime-overrides.conf"
+
+	// appOptionsSection is the section of the config file that holds the
+	// options without a namespace.
+	appOptionsSection = "Application Options"
+)
+
+// runtimeSetting is a config option that can be changed while lnd is
+// running.
+type runtimeSetting struct {
+	// name is the name of the option in the config file.
+	name string
+
+	// section is the section of the config file that holds the option.
+	section string
+
+	// get returns the current value of the option.
+	get func(r *rpcServer) string
+
+	// set parses the value and applies it to the running daemon.
+	set func(r *rpcServer, value string) error
+}
+
+// runtimeSettings is the list of options that can be changed without a
+// restart. The options are written to the overrides file in this order.
+var runtimeSettings = []runtimeSetting{
+	{
+		name:    "debuglevel",
+		section: appOptionsSection,
+		get:     getDebugLevels,
+		set: func(r *rpcServer, value string) error {
+			return build.ParseAndSetDebugLevels(
+				value, r.cfg.SubLogMgr,
+			)
+		},
+	},
+	{
+		name:    "cltvinterceptdelta",
+		section: appOptionsSection,
+		get: func(r *rpcServer) string {
+			iSwitch := r.server.interceptableSwitch
+			delta := iSwitch.CltvInterceptDelta()
+
+			return strconv.FormatUint(uint64(delta), 10)
+		},
+		set: func(r *rpcServer, value string) error {
+			delta, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return err
+			}
+
+			iSwitch := r.server.interceptableSwitch
+
+			return iSwitch.SetCltvInterceptDelta(uint32(delta))
+		},
+	},
+	{
+		name:    "bitcoin.basefee",
+		section: "Bitcoin",
+		get: func(r *rpcServer) string {
+			policy := r.server.fundingMgr.DefaultRoutingPolicy()
+
+			return strconv.FormatUint(uint64(policy.BaseFee), 10)
+		},
+		set: func(r *rpcServer, value string) error {
+			baseFee, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return err
+			}
+
+			policy := r.server.fundingMgr.DefaultRoutingPolicy()
+			policy.BaseFee = lnwire.MilliSatoshi(baseFee)
+			r.server.fundingMgr.SetDefaultRoutingPolicy(policy)
+
+			return nil
+		},
+	},
+	{
+		name:    "bitcoin.feerate",
+		section: "Bitcoin",
+		get: func(r *rpcServer) string {
+			policy := r.server.fundingMgr.DefaultRoutingPolicy()
+
+			return strconv.FormatUint(uint64(policy.FeeRate), 10)
+		},
+		set: func(r *rpcServer, value string) error {
+			feeRate, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return err
+			}
+
+			policy := r.server.fundingMgr.DefaultRoutingPolicy()
+			policy.FeeRate = lnwire.MilliSatoshi(feeRate)
+			r.server.fundingMgr.SetDefaultRoutingPolicy(policy)
+
+			return nil
+		},
+	},
+	{
+		name:    "gossip.max-channel-update-burst",
+		section: "gossip",
+		get: func(r *rpcServer) string {
+			gossiper := r.server.authGossiper
+			_, burst := gossiper.ChannelUpdateRateLimit()
+
+			return strconv.Itoa(burst)
+		},
+		set: func(r *rpcServer, value string) error {
+			burst, err := strconv.Atoi(value)
+			if err != nil {
+				return err
+			}
+
+			gossiper := r.server.authGossiper
+			interval, _ := gossiper.ChannelUpdateRateLimit()
+
+			return gossiper.SetChannelUpdateRateLimit(
+				interval, burst,
+			)
+		},
+	},
+	{
+		name:    "gossip.channel-update-interval",
+		section: "gossip",
+		get: func(r *rpcServer) string {
+			gossiper := r.server.authGossiper
+			interval, _ := gossiper.ChannelUpdateRateLimit()
+
+			return interval.String()
+		},
+		set: func(r *rpcServer, value string) error {
+			interval, err := time.ParseDuration(value)
+			if err != nil {
+				return err
+			}
+
+			gossiper := r.server.authGossiper
+			_, burst := gossiper.ChannelUpdateRateLimit()
+
+			return gossiper.SetChannelUpdateRateLimit(
+				interval, burst,
+			)
+		},
+	},
+}
+
+// findRuntimeSetting returns the runtime setting with the given name.
+func findRuntimeSetting(name string) (*runtimeSetting, error) {
+	for i := range runtimeSettings {
+		if runtimeSettings[i].name == name {
+			return &runtimeSettings[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("option %v can't be changed at runtime", name)
+}
+
+// getDebugLevels returns the log levels of all subsystems in the format of
+// the debuglevel option.
+func getDebugLevels(r *rpcServer) string {
+	subLoggers := r.cfg.SubLogMgr.SubLoggers()
+
+	tags := make([]string, 0, len(subLoggers))
+	for t := range subLoggers {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+
+	levels := make([]string, 0, len(tags))
+	for _, t := range tags {
+		levels = append(levels, fmt.Sprintf("%s=%s", t,
+			subLoggers[t].Level().String()))
+	}
+
+	return strings.Join(levels, ",")
+}
+
+// GetRuntimeConfig returns the current values of all options that can be
+// changed at runtime, keyed by their name in the config file.
+func (r *rpcServer) GetRuntimeConfig(_ context.Context,
+	_ *lnrpc.GetRuntimeConfigRequest) (*lnrpc.RuntimeConfig, error) {
+
+	r.runtimeCfgMtx.Lock()
+	defer r.runtimeCfgMtx.Unlock()
+
+	values := make(map[string]string, len(runtimeSettings))
+	for _, setting := range runtimeSettings {
+		values[setting.name] = setting.get(r)
+	}
+
+	return &lnrpc.RuntimeConfig{Options: values}, nil
+}
+
+// UpdateRuntimeConfig changes the given options of the running daemon, keyed
+// by their name in the config file. The updates are applied in the order of
+// their names and stop at the first one that fails. All updates that were
+// applied are written to the runtime overrides file, so they persist across
+// restarts. The new values of all runtime options are returned.
+func (r *rpcServer) UpdateRuntimeConfig(ctx context.Context,
+	req *lnrpc.UpdateRuntimeConfigRequest) (*lnrpc.RuntimeConfig, error) {
+
+	updates := req.Options
+	if len(updates) == 0 {
+		return nil, errors.New("no options to update")
+	}
+
+	names := make([]string, 0, len(updates))
+	for name := range updates {
+		if _, err := findRuntimeSetting(name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	r.runtimeCfgMtx.Lock()
+
+	applied := make(map[string]string, len(names))
+	var updateErr error
+	for _, name := range names {
+		setting, _ := findRuntimeSetting(name)
+
+		rpcsLog.Infof("[updateruntimeconfig] changing %v to: %v",
+			name, updates[name])
+
+		if err := setting.set(r, updates[name]); err != nil {
+			updateErr = fmt.Errorf("unable to set %v: %w", name,
+				err)
+			break
+		}
+
+		// We store the resulting value rather than the requested one,
+		// so a partial debug level spec doesn't drop the levels of
+		// earlier updates.
+		applied[name] = setting.get(r)
+	}
+
+	if len(applied) > 0 {
+		err := updateRuntimeOverrides(
+			r.cfg.runtimeOverridesPath, applied,
+		)
+		if err != nil && updateErr == nil {
+			updateErr = fmt.Errorf("unable to persist runtime "+
+				"config: %w", err)
+		}
+	}
+
+	// Propagate the new debug level to the main config struct like the
+	// DebugLevel RPC does.
+	if level, ok := applied["debuglevel"]; ok {
+		r.cfg.DebugLevel = level
+	}
+
+	r.runtimeCfgMtx.Unlock()
+
+	if updateErr != nil {
+		return nil, updateErr
+	}
+
+	return r.GetRuntimeConfig(ctx, &lnrpc.GetRuntimeConfigRequest{})
+}
+
+// readRuntimeOverrides reads the options of the runtime overrides file at the
+// given path. A missing file holds no options.
+func readRuntimeOverrides(path string) (map[string]string, error) {
+	overrides := make(map[string]string)
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return overrides, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// Sections are derived from the option names, so we only
+		// need the options themselves.
+		switch {
+		case line == "", strings.HasPrefix(line, ";"),
+			strings.HasPrefix(line, "#"),
+			strings.HasPrefix(line, "["):
+
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line in %v: %v", path,
+				line)
+		}
+
+		overrides[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return overrides, scanner.Err()
+}
+
+// updateRuntimeOverrides merges the given options into the runtime overrides
+// file at the given path. The file is replaced atomically, so a crash never
+// leaves a partially written file behind.
+func updateRuntimeOverrides(path string, updates map[string]string) error {
+	overrides, err := readRuntimeOverrides(path)
+	if err != nil {
+		return err
+	}
+	for name, value := range updates {
+		overrides[name] = value
+	}
+
+	var b strings.Builder
+	b.WriteString("; This file is written by lnd and holds the options " +
+		"that were\n; changed at runtime. They take precedence over " +
+		"lnd.conf.\n")
+
+	var section string
+	for _, setting := range runtimeSettings {
+		value, ok := overrides[setting.name]
+		if !ok {
+			continue
+		}
+
+		if setting.section != section {
+			section = setting.section
+			fmt.Fprintf(&b, "\n[%s]\n", section)
+		}
+		fmt.Fprintf(&b, "%s=%s\n", setting.name, value)
+	}
+
+	tempPath := path + ".tmp"
+	err = os.WriteFile(tempPath, []byte(b.String()), 0600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, path)
+}
@@ -0,0 +1,104 @@
+package lnd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jessevdk/go-flags"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRuntimeOverrides tests that updates of the runtime overrides file are
+// merged with the existing options and can be parsed as config file.
+func TestRuntimeOverrides(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), runtimeOverridesFilename)
+
+	// A missing file holds no options.
+	overrides, err := readRuntimeOverrides(path)
+	require.NoError(t, err)
+	require.Empty(t, overrides)
+
+	err = updateRuntimeOverrides(path, map[string]string{
+		"bitcoin.basefee":                "1500",
+		"gossip.channel-update-interval": "2m0s",
+	})
+	require.NoError(t, err)
+
+	err = updateRuntimeOverrides(path, map[string]string{
+		"bitcoin.basefee": "2000",
+		"debuglevel":      "PEER=debug,SRVR=info",
+	})
+	require.NoError(t, err)
+
+	overrides, err = readRuntimeOverrides(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"bitcoin.basefee":                "2000",
+		"gossip.channel-update-interval": "2m0s",
+		"debuglevel":                     "PEER=debug,SRVR=info",
+	}, overrides)
+
+	_, err = os.Stat(path + ".tmp")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// The file must be accepted by the config file parser, so the
+	// options are applied on startup.
+	cfg := DefaultConfig()
+	fileParser := flags.NewParser(&cfg, flags.Default)
+	require.NoError(t, flags.NewIniParser(fileParser).ParseFile(path))
+
+	require.EqualValues(t, 2000, cfg.Bitcoin.BaseFee)
+	require.Equal(t, "2m0s", cfg.Gossip.ChannelUpdateInterval.String())
+	require.Equal(t, "PEER=debug,SRVR=info", cfg.DebugLevel)
+}
+
+// TestRuntimeSettings makes sure all runtime settings have a unique name.
+func TestRuntimeSettings(t *testing.T) {
+	t.Parallel()
+
+	names := make(map[string]struct{})
+	for _, setting := range runtimeSettings {
+		_, ok := names[setting.name]
+		require.False(t, ok, setting.name)
+		names[setting.name] = struct{}{}
+
+		found, err := findRuntimeSetting(setting.name)
+		require.NoError(t, err)
+		require.Equal(t, setting.name, found.name)
+	}
+
+	_, err := findRuntimeSetting("rpclisten")
+	require.Error(t, err)
+}
+
+// TestUpdateRuntimeConfigRejected tests that an update with an option that
+// can't be changed at runtime is rejected before any option is applied.
+func TestUpdateRuntimeConfigRejected(t *testing.T) {
+	t.Parallel()
+
+	r := &rpcServer{}
+	ctx := context.Background()
+
+	_, err := r.UpdateRuntimeConfig(
+		ctx, &lnrpc.UpdateRuntimeConfigRequest{},
+	)
+	require.Error(t, err)
+
+	// The rpc server has no server to apply the fee to, so this would
+	// panic if the valid option was applied before the invalid one was
+	// detected.
+	_, err = r.UpdateRuntimeConfig(
+		ctx, &lnrpc.UpdateRuntimeConfigRequest{
+			Options: map[string]string{
+				"bitcoin.basefee": "1000",
+				"rpclisten":       "localhost:10009",
+			},
+		},
+	)
+	require.ErrorContains(t, err, "rpclisten")
+}
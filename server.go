@@ -9,6 +9,7 @@ import (
 	"math/big"
 	prand "math/rand"
 	"net"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,6 +26,7 @@ import (
 	"github.com/btcsuite/btcd/wire"
 	"github.com/go-errors/errors"
 	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/accounts"
 	"github.com/lightningnetwork/lnd/aliasmgr"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/brontide"
@@ -49,13 +51,16 @@ import (
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/jobs"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnencrypt"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
@@ -66,6 +71,7 @@ import (
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/pool"
+	"github.com/lightningnetwork/lnd/protofsm"
 	"github.com/lightningnetwork/lnd/queue"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/routing/localchans"
@@ -76,9 +82,11 @@ import (
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/walletunlocker"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/lookout"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
 )
 
 const (
@@ -215,6 +223,14 @@ type server struct {
 	persistentConnReqs     map[string][]*connmgr.ConnReq
 	persistentRetryCancels map[string]chan struct{}
 
+	// peerPolicies holds the connection policies the user set for peers
+	// and the inbound connection allow and deny lists.
+	peerPolicies *peerPolicyCache
+
+	// acceptPolicies is the ordered chain of policies that decides
+	// whether inbound channels are accepted.
+	acceptPolicies *chanacceptor.PolicyChain
+
 	// peerErrors keeps a set of peer error buffers for peers that have
 	// disconnected from us. This allows us to track historic peer errors
 	// over connections. The string of the peer's compressed pubkey is used
@@ -262,10 +278,29 @@ type server struct {
 
 	interceptableSwitch *htlcswitch.InterceptableSwitch
 
+	// forwardingRules holds the operator defined rules that forwarded
+	// htlcs must satisfy. They can be replaced at runtime.
+	forwardingRules *htlcswitch.ForwardingRules
+
+	// stuckHtlcDetector reports htlcs that have been pending on our
+	// outgoing channels for too long. It is nil if disabled.
+	stuckHtlcDetector *htlcswitch.StuckHtlcDetector
+
 	invoices *invoices.InvoiceRegistry
 
 	invoiceHtlcModifier *invoices.HtlcModificationInterceptor
 
+	// subscriptions generates the invoices of recurring subscriptions.
+	subscriptions *invoices.SubscriptionManager
+
+	// invoiceWebhook posts invoice events to the configured webhook. It
+	// is nil if no webhook is configured.
+	invoiceWebhook *invoicesrpc.WebhookDispatcher
+
+	// keysendInbox holds the messages received with keysend payments. It
+	// is nil unless the inbox is enabled.
+	keysendInbox *invoicesrpc.Inbox
+
 	channelNotifier *channelnotifier.ChannelNotifier
 
 	peerNotifier *peernotifier.PeerNotifier
@@ -283,6 +318,18 @@ type server struct {
 
 	chanRouter *routing.ChannelRouter
 
+	// prober periodically probes the network to improve the accuracy of
+	// mission control. It is nil if probing is disabled.
+	prober *routing.Prober
+
+	// graphAnalytics keeps analytics over the channel graph up to date as
+	// gossip arrives. It is nil if graph analytics are disabled.
+	graphAnalytics *autopilot.GraphAnalytics
+
+	// landmarks keeps the distances to a set of landmark nodes up to date
+	// to speed up path finding. It is nil if no landmarks are configured.
+	landmarks *routing.LandmarkHeuristic
+
 	controlTower routing.ControlTower
 
 	authGossiper *discovery.AuthenticatedGossiper
@@ -331,6 +378,18 @@ type server struct {
 	// provide insights into their health and performance.
 	chanEventStore *chanfitness.ChannelEventStore
 
+	// fsmRegistry keeps track of the active protocol state machines, so
+	// that they can be inspected.
+	fsmRegistry *protofsm.Registry
+
+	// jobMgr runs long-running operations as jobs that can be listed,
+	// canceled and resumed by clients.
+	jobMgr *jobs.Manager
+
+	// accounts enforces the balances of the off-chain accounts. It is nil
+	// if accounts aren't active.
+	accounts *accounts.Service
+
 	hostAnn *netann.HostAnnouncer
 
 	// livenessMonitor monitors that lnd has access to critical resources.
@@ -341,6 +400,14 @@ type server struct {
 	// txPublisher is a publisher with fee-bumping capability.
 	txPublisher *sweep.TxPublisher
 
+	// spendArbiter keeps track of which subsystem spends which outpoint,
+	// to prevent conflicting broadcasts.
+	spendArbiter *sweep.SpendArbiter
+
+	// sweepLedger persists the history of all sweeps published by the
+	// txPublisher.
+	sweepLedger sweep.SweepLedger
+
 	quit chan struct{}
 
 	wg sync.WaitGroup
@@ -572,6 +639,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		NoTaprootChans:           !cfg.ProtocolOptions.TaprootChans,
 		NoTaprootOverlay:         !cfg.ProtocolOptions.TaprootOverlayChans,
 		NoRouteBlinding:          cfg.ProtocolOptions.NoRouteBlinding(),
+		NoQuiescence:             cfg.ProtocolOptions.NoQuiescence(),
 	})
 	if err != nil {
 		return nil, err
@@ -590,6 +658,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		HtlcInterceptor:             invoiceHtlcModifier,
 	}
 
+	// If stateless invoices are enabled, derive the secret that they are
+	// issued with so that the registry can settle them.
+	if cfg.Invoices.Stateless {
+		statelessSecret, err := invoices.DeriveStatelessSecret(
+			cc.KeyRing,
+		)
+		if err != nil {
+			return nil, err
+		}
+		registryConfig.StatelessSecret = &statelessSecret
+	}
+
 	s := &server{
 		cfg:            cfg,
 		implCfg:        implCfg,
@@ -603,6 +683,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		writePool:      writePool,
 		readPool:       readPool,
 		chansToRestore: chansToRestore,
+		fsmRegistry:    protofsm.NewRegistry(),
 
 		channelNotifier: channelnotifier.New(
 			dbs.ChanStateDB.ChannelStateDB(),
@@ -651,14 +732,68 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	s.peerPolicies, err = newPeerPolicyCache(s.chanStateDB)
+	if err != nil {
+		return nil, err
+	}
+
+	// The registry resolves the peer of an incoming htlc through the link
+	// it arrived on, for invoices that restrict their allowed peers.
+	registryConfig.FetchChannelPeer = func(
+		scid lnwire.ShortChannelID) (route.Vertex, error) {
+
+		link, err := s.htlcSwitch.GetLinkByShortID(scid)
+		if err != nil {
+			return route.Vertex{}, err
+		}
+
+		return link.PeerPubKey(), nil
+	}
+
 	expiryWatcher := invoices.NewInvoiceExpiryWatcher(
 		clock.NewDefaultClock(), cfg.Invoices.HoldExpiryDelta,
-		uint32(currentHeight), currentHash, cc.ChainNotifier,
+		cfg.Invoices.HoldWarningDelta, uint32(currentHeight),
+		currentHash, cc.ChainNotifier,
 	)
 	s.invoices = invoices.NewRegistry(
 		dbs.InvoiceDB, expiryWatcher, &registryConfig,
 	)
 
+	s.subscriptions = invoices.NewSubscriptionManager(
+		&invoices.SubscriptionManagerConfig{
+			Store:         dbs.ChanStateDB,
+			CreateInvoice: s.createSubscriptionInvoice,
+			LookupInvoice: s.invoices.LookupInvoice,
+			Clock:         clock.NewDefaultClock(),
+			PollTicker: ticker.New(
+				invoices.DefaultSubscriptionPollInterval,
+			),
+		},
+	)
+
+	// Post invoice events to the webhook, if one is configured.
+	webhookCfg := &invoicesrpc.WebhookConfig{
+		URL:                    cfg.Invoices.WebhookURL,
+		Secret:                 []byte(cfg.Invoices.WebhookSecret),
+		MaxAttempts:            cfg.Invoices.WebhookMaxAttempts,
+		ChainParams:            cfg.ActiveNetParams.Params,
+		SubscribeNotifications: s.invoices.SubscribeNotifications,
+	}
+	if webhookCfg.URL != "" {
+		s.invoiceWebhook = invoicesrpc.NewWebhookDispatcher(webhookCfg)
+	}
+
+	// Keep the messages received with keysend payments, if enabled.
+	inboxCfg := &invoicesrpc.InboxConfig{
+		QueryInvoices:          s.invoicesDB.QueryInvoices,
+		SubscribeNotifications: s.invoices.SubscribeNotifications,
+		FetchAcks:              s.miscDB.FetchInboxAcks,
+		PutAcks:                s.miscDB.PutInboxAcks,
+	}
+	if cfg.Invoices.KeysendInbox {
+		s.keysendInbox = invoicesrpc.NewInbox(inboxCfg)
+	}
+
 	s.htlcNotifier = htlcswitch.NewHtlcNotifier(time.Now)
 
 	thresholdSats := btcutil.Amount(cfg.MaxFeeExposure)
@@ -680,6 +815,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	overflowPolicy := htlcswitch.MailboxOverflowFail
+	if cfg.Htlcswitch.MailboxOverflowPolicy == "drop" {
+		overflowPolicy = htlcswitch.MailboxOverflowDrop
+	}
+
 	s.htlcSwitch, err = htlcswitch.New(htlcswitch.Config{
 		DB:                   dbs.ChanStateDB,
 		FetchAllOpenChannels: s.chanStateDB.FetchAllOpenChannels,
@@ -712,9 +852,16 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RejectHTLC:             cfg.RejectHTLC,
 		Clock:                  clock.NewDefaultClock(),
 		MailboxDeliveryTimeout: cfg.Htlcswitch.MailboxDeliveryTimeout,
+		MailboxMaxAdds:         cfg.Htlcswitch.MailboxMaxAdds,
+		MailboxOverflowPolicy:  overflowPolicy,
+		MailboxMaxInFlightAdds: cfg.Htlcswitch.MailboxMaxInFlightAdds,
+		MailboxWeight:          s.mailboxWeight,
 		MaxFeeExposure:         thresholdMSats,
 		SignAliasUpdate:        s.signAliasUpdate,
 		IsAlias:                aliasmgr.IsAlias,
+		ShouldFwdExpEndorsement: func() bool {
+			return !cfg.ProtocolOptions.NoExpEndorsement()
+		},
 	}, uint32(currentHeight))
 	if err != nil {
 		return nil, err
@@ -723,15 +870,62 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		&htlcswitch.InterceptableSwitchConfig{
 			Switch:             s.htlcSwitch,
 			CltvRejectDelta:    lncfg.DefaultFinalCltvRejectDelta,
-			CltvInterceptDelta: lncfg.DefaultCltvInterceptDelta,
+			CltvInterceptDelta: s.cfg.CltvInterceptDelta,
 			RequireInterceptor: s.cfg.RequireInterceptor,
 			Notifier:           s.cc.ChainNotifier,
+			ZeroConfHoldThreshold: lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(cfg.ZeroConf.HoldThreshold),
+			),
 		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	s.forwardingRules = htlcswitch.NewForwardingRules(
+		clock.NewDefaultClock(),
+	)
+	if path := cfg.Htlcswitch.ForwardingRulesFile; path != "" {
+		rules, err := loadForwardingRules(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.forwardingRules.SetRules(rules); err != nil {
+			return nil, err
+		}
+	}
+
+	if threshold := cfg.Htlcswitch.StuckHtlcThreshold; threshold > 0 {
+		var reconnectPeer func([33]byte) error
+		if cfg.Htlcswitch.StuckHtlcReconnect {
+			reconnectPeer = func(peer [33]byte) error {
+				pubKey, err := btcec.ParsePubKey(peer[:])
+				if err != nil {
+					return err
+				}
+
+				return s.DisconnectPeer(pubKey)
+			}
+		}
+
+		// Scan the circuits a few times per threshold, so that stuck
+		// htlcs are reported shortly after crossing it.
+		s.stuckHtlcDetector, err = htlcswitch.NewStuckHtlcDetector(
+			&htlcswitch.StuckHtlcDetectorConfig{
+				Circuits:      s.htlcSwitch.CircuitLookup(),
+				FetchLink:     s.htlcSwitch.GetLinkByShortID,
+				Threshold:     threshold,
+				ReconnectPeer: reconnectPeer,
+				Ticker:        ticker.New(threshold / 4),
+				Clock:         clock.NewDefaultClock(),
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	s.witnessBeacon = newPreimageBeacon(
 		dbs.ChanStateDB.NewWitnessCache(),
 		s.interceptableSwitch.ForwardPacket,
@@ -962,8 +1156,20 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		}
 	}
 
+	// If enabled, keep a cache of recently successful routes. Routes are
+	// evicted as soon as mission control records a failure on them.
+	var (
+		routeCache    *routing.RouteCache
+		onPairFailure fn.Option[func(from, to route.Vertex)]
+	)
+	if cfg.Routing.RouteCacheSize > 0 {
+		routeCache = routing.NewRouteCache(cfg.Routing.RouteCacheSize)
+		onPairFailure = fn.Some(routeCache.InvalidatePair)
+	}
+
 	mcCfg := &routing.MissionControlConfig{
 		OnConfigUpdate:          fn.Some(s.UpdateRoutingConfig),
+		OnPairFailure:           onPairFailure,
 		Estimator:               estimator,
 		MaxMcHistory:            routingConfig.MaxMcHistory,
 		McFlushInterval:         routingConfig.McFlushInterval,
@@ -1011,6 +1217,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		MissionControl:    s.defaultMC,
 		GetLink:           s.htlcSwitch.GetLinkByShortID,
 		PathFindingConfig: pathFindingConfig,
+		RouteCache:        routeCache,
 	}
 
 	paymentControl := channeldb.NewPaymentControl(dbs.ChanStateDB)
@@ -1037,6 +1244,24 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, fmt.Errorf("can't create graph builder: %w", err)
 	}
 
+	if cfg.Routing.NumLandmarks > 0 {
+		landmarksCfg := &routing.LandmarkHeuristicConfig{
+			Graph: graphsession.NewRoutingGraph(
+				chanGraph,
+			),
+			SelfNode:          selfNode.PubKeyBytes,
+			NumLandmarks:      cfg.Routing.NumLandmarks,
+			SubscribeTopology: s.graphBuilder.SubscribeTopology,
+		}
+		s.landmarks, err = routing.NewLandmarkHeuristic(landmarksCfg)
+		if err != nil {
+			return nil, fmt.Errorf("can't create landmark "+
+				"heuristic: %w", err)
+		}
+
+		paymentSessionSource.Landmarks = s.landmarks
+	}
+
 	s.chanRouter, err = routing.New(routing.Config{
 		SelfNode:           selfNode.PubKeyBytes,
 		RoutingGraph:       graphsession.NewRoutingGraph(chanGraph),
@@ -1052,11 +1277,39 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		ApplyChannelUpdate: s.graphBuilder.ApplyChannelUpdate,
 		ClosedSCIDs:        s.fetchClosedChannelSCIDs(),
 		TrafficShaper:      implCfg.TrafficShaper,
+		EnableTrampoline:   cfg.Routing.Trampoline,
+		RouteCache:         routeCache,
+		Landmarks:          s.landmarks,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %w", err)
 	}
 
+	if cfg.Routing.Probing != nil && cfg.Routing.Probing.Enable {
+		s.prober, err = s.createProber(cfg.Routing.Probing)
+		if err != nil {
+			return nil, fmt.Errorf("can't create prober: %w", err)
+		}
+	}
+
+	if cfg.Routing.GraphAnalytics {
+		analyticsCfg := &autopilot.GraphAnalyticsCfg{
+			Graph: autopilot.ChannelGraphFromCachedDatabase(
+				s.graphDB,
+			),
+			SubscribeTopology: s.graphBuilder.SubscribeTopology,
+			Workers:           runtime.NumCPU(),
+			BatchDelay:        cfg.Routing.GraphAnalyticsBatchDelay,
+		}
+		s.graphAnalytics, err = autopilot.NewGraphAnalytics(
+			analyticsCfg,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't create graph analytics: "+
+				"%w", err)
+		}
+	}
+
 	chanSeries := discovery.NewChanSeries(s.graphDB)
 	gossipMessageStore, err := discovery.NewMessageStore(dbs.ChanStateDB)
 	if err != nil {
@@ -1067,6 +1320,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	// The taproot channel gossip messages are only handled if the
+	// experimental gossip v2 protocol option is set.
+	var gossipV2Store *discovery.GossipV2Store
+	if cfg.ProtocolOptions.GossipV2() {
+		gossipV2Store, err = discovery.NewGossipV2Store(
+			dbs.ChanStateDB,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	scidCloserMan := discovery.NewScidCloserMan(s.graphDB, s.chanStateDB)
 
 	s.authGossiper = discovery.New(discovery.Config{
@@ -1090,6 +1355,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RebroadcastInterval:     time.Hour * 24,
 		WaitingProofStore:       waitingProofStore,
 		MessageStore:            gossipMessageStore,
+		GossipV2Store:           gossipV2Store,
 		AnnSigner:               s.nodeSigner,
 		RotateTicker:            ticker.New(discovery.DefaultSyncerRotationInterval),
 		HistoricalSyncTicker:    ticker.New(cfg.HistoricalSyncInterval),
@@ -1101,6 +1367,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PinnedSyncers:           cfg.Gossip.PinnedSyncers,
 		MaxChannelUpdateBurst:   cfg.Gossip.MaxChannelUpdateBurst,
 		ChannelUpdateInterval:   cfg.Gossip.ChannelUpdateInterval,
+		PeerMsgRate:             cfg.Gossip.PeerMsgRate,
+		PeerMsgBurst:            cfg.Gossip.PeerMsgBurst,
 		IsAlias:                 aliasmgr.IsAlias,
 		SignAliasUpdate:         s.signAliasUpdate,
 		FindBaseByAlias:         s.aliasMgr.FindBaseSCID,
@@ -1134,19 +1402,43 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	s.sweepLedger, err = sweep.NewSweepLedger(dbs.ChanStateDB)
+	if err != nil {
+		srvrLog.Errorf("unable to create sweep ledger: %v", err)
+		return nil, err
+	}
+
 	aggregator := sweep.NewBudgetAggregator(
 		cc.FeeEstimator, sweep.DefaultMaxInputsPerTx,
 		s.implCfg.AuxSweeper,
 	)
 
+	s.spendArbiter = sweep.NewSpendArbiter()
+
+	// The txns published by the contract court claim their inputs with the
+	// spend arbiter, so they don't conflict with the sweeper or the user.
+	contractCourtPublish := s.spendArbiter.PublishFunc(
+		sweep.SpendSourceContractCourt, cc.Wallet.PublishTransaction,
+	)
+
 	s.txPublisher = sweep.NewTxPublisher(sweep.TxPublisherConfig{
-		Signer:     cc.Wallet.Cfg.Signer,
-		Wallet:     cc.Wallet,
-		Estimator:  cc.FeeEstimator,
-		Notifier:   cc.ChainNotifier,
-		AuxSweeper: s.implCfg.AuxSweeper,
+		Signer:           cc.Wallet.Cfg.Signer,
+		Wallet:           cc.Wallet,
+		Estimator:        cc.FeeEstimator,
+		Notifier:         cc.ChainNotifier,
+		AuxSweeper:       s.implCfg.AuxSweeper,
+		PackageSubmitter: cc.PackageSubmitter,
+		SpendArbiter:     fn.Some(s.spendArbiter),
+		Ledger:           fn.Some(s.sweepLedger),
+		Mempool:          cc.MempoolNotifier,
+		MempoolInspector: cc.MempoolInspector,
 	})
 
+	noWalletInputs, err := cfg.Sweeper.NoWalletInputClasses()
+	if err != nil {
+		return nil, err
+	}
+
 	s.sweeper = sweep.New(&sweep.UtxoSweeperConfig{
 		FeeEstimator: cc.FeeEstimator,
 		GenSweepScript: newSweepPkScriptGen(
@@ -1162,6 +1454,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Aggregator:           aggregator,
 		Publisher:            s.txPublisher,
 		NoDeadlineConfTarget: cfg.Sweeper.NoDeadlineConfTarget,
+		NoWalletInputs:       noWalletInputs,
+		BatchWindows:         cfg.Sweeper.BatchWindows(),
 	})
 
 	s.utxoNursery = contractcourt.NewUtxoNursery(&contractcourt.NurseryConfig{
@@ -1170,7 +1464,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		FetchClosedChannels: s.chanStateDB.FetchClosedChannels,
 		FetchClosedChannel:  s.chanStateDB.FetchClosedChannel,
 		Notifier:            cc.ChainNotifier,
-		PublishTransaction:  cc.Wallet.PublishTransaction,
+		PublishTransaction:  contractCourtPublish,
 		Store:               utxnStore,
 		SweepInput:          s.sweeper.SweepInput,
 		Budget:              s.cfg.Sweeper.Budget,
@@ -1201,13 +1495,14 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 				cc.Wallet, s.cfg.ActiveNetParams.Params,
 			),
 			Notifier:           cc.ChainNotifier,
-			PublishTransaction: cc.Wallet.PublishTransaction,
+			PublishTransaction: contractCourtPublish,
 			ContractBreaches:   contractBreaches,
 			Signer:             cc.Wallet.Cfg.Signer,
 			Store: contractcourt.NewRetributionStore(
 				dbs.ChanStateDB,
 			),
 			AuxSweeper: s.implCfg.AuxSweeper,
+			Budget:     s.cfg.Sweeper.Budget,
 		},
 	)
 
@@ -1226,7 +1521,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 			return addr.DeliveryAddress, nil
 		},
-		PublishTx: cc.Wallet.PublishTransaction,
+		PublishTx: contractCourtPublish,
 		DeliverResolutionMsg: func(msgs ...contractcourt.ResolutionMsg) error {
 			for _, msg := range msgs {
 				err := s.htlcSwitch.ProcessContractResolution(msg)
@@ -1311,10 +1606,26 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PaymentsExpirationGracePeriod: cfg.PaymentsExpirationGracePeriod,
 		IsForwardedHTLC:               s.htlcSwitch.IsForwardedHTLC,
 		Clock:                         clock.NewDefaultClock(),
+		FSMRegistry:                   s.fsmRegistry,
 		SubscribeBreachComplete:       s.breachArbitrator.SubscribeBreachComplete,
 		PutFinalHtlcOutcome:           s.chanStateDB.PutOnchainFinalHtlcOutcome,
 		HtlcNotifier:                  s.htlcNotifier,
 		Budget:                        *s.cfg.Sweeper.Budget,
+		ForceClosePolicy: contractcourt.ForceClosePolicy{
+			MinHtlcAmt: lnwire.NewMSatFromSatoshis(
+				btcutil.Amount(cfg.ForceClose.MinHtlcAmt),
+			),
+			DustHtlcDelay: cfg.ForceClose.DustHtlcDelay,
+		},
+		CoopHtlcSweep:     cfg.ForceClose.CoopHtlcSweep,
+		SendCustomMessage: s.SendCustomMessage,
+		PrioritizeClaims:  cfg.ForceClose.PrioritizeClaims,
+		WalletBalance: func() (btcutil.Amount, error) {
+			return cc.Wallet.ConfirmedBalance(
+				1, lnwallet.DefaultAccountName,
+			)
+		},
+		DelegateAnchorBumps: cfg.ForceClose.DelegateAnchorBumps,
 
 		// TODO(yy): remove this hack once PaymentCircuit is interfaced.
 		QueryIncomingCircuit: func(
@@ -1408,6 +1719,38 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			devCfg, reservationTimeout, zombieSweeperInterval)
 	}
 
+	// Inbound channels are evaluated by the chain of accept policies, in
+	// which the acceptors registered over RPC are one of the policies.
+	s.acceptPolicies, err = s.newAcceptPolicyChain(cfg, chanPredicate)
+	if err != nil {
+		return nil, err
+	}
+
+	// The exposure of the inbound zero-conf channels is only tracked if
+	// any of its limits is set.
+	var zeroConfRisk *funding.ZeroConfRisk
+	if cfg.ZeroConf.MaxUnconfirmedInbound != 0 ||
+		cfg.ZeroConf.MaxPeerUnconfirmedInbound != 0 {
+
+		zeroConfRisk = funding.NewZeroConfRisk(funding.ZeroConfLimits{
+			MaxUnconfirmed: btcutil.Amount(
+				cfg.ZeroConf.MaxUnconfirmedInbound,
+			),
+			MaxPeerUnconfirmed: btcutil.Amount(
+				cfg.ZeroConf.MaxPeerUnconfirmedInbound,
+			),
+		}, s.chanStateDB.FetchAllChannels)
+	}
+
+	// The inbound channel policy is only enforced beyond the minimum
+	// channel size if any of its requirements is configured.
+	var inboundPolicy *lnwire.InboundChanPolicy
+	if cfg.InboundPolicy.Enabled() {
+		inboundPolicy = cfg.InboundPolicy.Policy(
+			btcutil.Amount(cfg.MinChanSize),
+		)
+	}
+
 	//nolint:lll
 	s.fundingMgr, err = funding.NewFundingManager(funding.Config{
 		Dev:                devCfg,
@@ -1532,7 +1875,16 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		},
 		ReportShortChanID: func(chanPoint wire.OutPoint) error {
 			cid := lnwire.NewChanIDFromOutPoint(chanPoint)
-			return s.htlcSwitch.UpdateShortChanID(cid)
+			err := s.htlcSwitch.UpdateShortChanID(cid)
+			if err != nil {
+				return err
+			}
+
+			// A zero-conf channel may have confirmed, which
+			// releases the htlcs that were held until then.
+			s.interceptableSwitch.ZeroConfConfirmed()
+
+			return nil
 		},
 		RequiredRemoteChanReserve: func(chanAmt,
 			dustLimit btcutil.Amount) btcutil.Amount {
@@ -1573,7 +1925,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RejectPush:                    cfg.RejectPush,
 		MaxLocalCSVDelay:              chainCfg.MaxLocalDelay,
 		NotifyOpenChannelEvent:        s.channelNotifier.NotifyOpenChannelEvent,
-		OpenChannelPredicate:          chanPredicate,
+		OpenChannelPredicate:          s.acceptPolicies,
 		NotifyPendingOpenChannelEvent: s.channelNotifier.NotifyPendingOpenChannelEvent,
 		EnableUpfrontShutdown:         cfg.EnableUpfrontShutdown,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
@@ -1584,6 +1936,9 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		AuxFundingController: implCfg.AuxFundingController,
 		AuxSigner:            implCfg.AuxSigner,
 		AuxResolver:          implCfg.AuxContractResolver,
+		ZeroConfRisk:         zeroConfRisk,
+		InboundPolicy:        inboundPolicy,
+		FetchInboundPolicy:   s.fetchInboundPolicy,
 	})
 	if err != nil {
 		return nil, err
@@ -1621,6 +1976,9 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		SubscribePeerEvents: func() (subscribe.Subscription, error) {
 			return s.peerNotifier.SubscribePeerEvents()
 		},
+		SubscribeHtlcEvents: func() (subscribe.Subscription, error) {
+			return s.htlcNotifier.SubscribeHtlcEvents()
+		},
 		GetOpenChannels: s.chanStateDB.FetchAllOpenChannels,
 		Clock:           clock.NewDefaultClock(),
 		ReadFlapCount:   s.miscDB.ReadFlapCount,
@@ -1628,6 +1986,31 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		FlapCountTicker: ticker.New(chanfitness.FlapCountFlushRate),
 	})
 
+	s.jobMgr, err = jobs.NewManager(&jobs.Config{
+		DB:    dbs.ChanStateDB,
+		Clock: clock.NewDefaultClock(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Accounts.Active {
+		accountStore, err := accounts.NewStore(
+			dbs.ChanStateDB, clock.NewDefaultClock(),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		s.accounts = accounts.NewService(&accounts.Config{
+			Store:       accountStore,
+			Invoices:    s.invoices,
+			Payments:    s.controlTower,
+			ChainParams: cfg.ActiveNetParams.Params,
+			Clock:       clock.NewDefaultClock(),
+		})
+	}
+
 	if cfg.WtClient.Active {
 		policy := wtpolicy.DefaultPolicy()
 		policy.MaxUpdates = cfg.WtClient.MaxUpdates
@@ -1683,6 +2066,13 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 		fetchClosedChannel := s.chanStateDB.FetchClosedChannelForID
 
+		deadTowerPolicy, err := wtclient.ParseDeadTowerPolicy(
+			cfg.WtClient.DeadTowerPolicy,
+		)
+		if err != nil {
+			return nil, err
+		}
+
 		// Copy the policy for legacy channels and set the blob flag
 		// signalling support for anchor channels.
 		anchorPolicy := policy
@@ -1695,6 +2085,22 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			blob.FlagTaprootChannel,
 		)
 
+		var towerDiscovery *wtclient.TowerDiscoveryConfig
+		if cfg.WtClient.DiscoverTowers {
+			towerDiscovery = &wtclient.TowerDiscoveryConfig{
+				FetchTowerAnns: s.fetchTowerAnns,
+				Policy: wtclient.TowerDiscoveryPolicy{
+					MinStorage: cfg.WtClient.
+						DiscoveryMinStorage,
+					AllowOnion: cfg.Tor.Active,
+					TorOnly:    cfg.WtClient.DiscoveryTorOnly,
+					MaxTowers: cfg.WtClient.
+						MaxDiscoveredTowers,
+				},
+				Interval: cfg.WtClient.DiscoveryInterval,
+			}
+		}
+
 		s.towerClientMgr, err = wtclient.NewManager(&wtclient.Config{
 			FetchClosedChannel:     fetchClosedChannel,
 			BuildBreachRetribution: buildBreachRetribution,
@@ -1725,6 +2131,11 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			MinBackoff:         10 * time.Second,
 			MaxBackoff:         5 * time.Minute,
 			MaxTasksInMemQueue: cfg.WtClient.MaxTasksInMemQueue,
+			HealthCheckInterval: cfg.WtClient.
+				HealthCheckInterval,
+			MaxTowerFailures: cfg.WtClient.MaxTowerFailures,
+			DeadTowerPolicy:  deadTowerPolicy,
+			TowerDiscovery:   towerDiscovery,
 		}, policy, anchorPolicy, taprootPolicy)
 		if err != nil {
 			return nil, err
@@ -2177,6 +2588,14 @@ func (s *server) Start() error {
 			return
 		}
 
+		if s.stuckHtlcDetector != nil {
+			cleanup = cleanup.add(s.stuckHtlcDetector.Stop)
+			if err := s.stuckHtlcDetector.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		cleanup = cleanup.add(s.invoiceHtlcModifier.Stop)
 		if err := s.invoiceHtlcModifier.Start(); err != nil {
 			startErr = err
@@ -2195,11 +2614,36 @@ func (s *server) Start() error {
 			return
 		}
 
+		if s.graphAnalytics != nil {
+			cleanup = cleanup.add(s.graphAnalytics.Stop)
+			if err := s.graphAnalytics.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
+		if s.landmarks != nil {
+			cleanup = cleanup.add(s.landmarks.Stop)
+			if err := s.landmarks.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		cleanup = cleanup.add(s.chanRouter.Stop)
 		if err := s.chanRouter.Start(); err != nil {
 			startErr = err
 			return
 		}
+
+		if s.prober != nil {
+			cleanup = cleanup.add(s.prober.Stop)
+			if err := s.prober.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		// The authGossiper depends on the chanRouter and therefore
 		// should be started after it.
 		cleanup = cleanup.add(s.authGossiper.Stop)
@@ -2208,12 +2652,40 @@ func (s *server) Start() error {
 			return
 		}
 
+		// A fresh node can bootstrap its graph from a snapshot before
+		// we connect to any peers and start syncing gossip.
+		if s.cfg.Gossip.SnapshotSource != "" {
+			s.bootstrapGraphSnapshot()
+		}
+
 		cleanup = cleanup.add(s.invoices.Stop)
 		if err := s.invoices.Start(); err != nil {
 			startErr = err
 			return
 		}
 
+		cleanup = cleanup.add(s.subscriptions.Stop)
+		if err := s.subscriptions.Start(); err != nil {
+			startErr = err
+			return
+		}
+
+		if s.invoiceWebhook != nil {
+			cleanup = cleanup.add(s.invoiceWebhook.Stop)
+			if err := s.invoiceWebhook.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
+		if s.keysendInbox != nil {
+			cleanup = cleanup.add(s.keysendInbox.Stop)
+			if err := s.keysendInbox.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		cleanup = cleanup.add(s.sphinx.Stop)
 		if err := s.sphinx.Start(); err != nil {
 			startErr = err
@@ -2232,6 +2704,20 @@ func (s *server) Start() error {
 			return
 		}
 
+		cleanup = cleanup.add(s.jobMgr.Stop)
+		if err := s.jobMgr.Start(); err != nil {
+			startErr = err
+			return
+		}
+
+		if s.accounts != nil {
+			cleanup = cleanup.add(s.accounts.Stop)
+			if err := s.accounts.Start(); err != nil {
+				startErr = err
+				return
+			}
+		}
+
 		cleanup.add(func() error {
 			s.missionController.StopStoreTickers()
 			return nil
@@ -2459,6 +2945,21 @@ func (s *server) Stop() error {
 		if err := s.sphinx.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop sphinx: %v", err)
 		}
+		if s.invoiceWebhook != nil {
+			if err := s.invoiceWebhook.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop invoice "+
+					"webhook: %v", err)
+			}
+		}
+		if s.keysendInbox != nil {
+			if err := s.keysendInbox.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop keysend "+
+					"inbox: %v", err)
+			}
+		}
+		if err := s.subscriptions.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop subscriptions: %v", err)
+		}
 		if err := s.invoices.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop invoices: %v", err)
 		}
@@ -2466,10 +2967,33 @@ func (s *server) Stop() error {
 			srvrLog.Warnf("failed to stop interceptable "+
 				"switch: %v", err)
 		}
+		if s.stuckHtlcDetector != nil {
+			if err := s.stuckHtlcDetector.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop stuck htlc "+
+					"detector: %v", err)
+			}
+		}
 		if err := s.invoiceHtlcModifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlc invoices "+
 				"modifier: %v", err)
 		}
+		if s.graphAnalytics != nil {
+			if err := s.graphAnalytics.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop graph "+
+					"analytics: %v", err)
+			}
+		}
+		if s.landmarks != nil {
+			if err := s.landmarks.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop landmark "+
+					"heuristic: %v", err)
+			}
+		}
+		if s.prober != nil {
+			if err := s.prober.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop prober: %v", err)
+			}
+		}
 		if err := s.chanRouter.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanRouter: %v", err)
 		}
@@ -2535,6 +3059,15 @@ func (s *server) Stop() error {
 			srvrLog.Warnf("Unable to stop ChannelEventStore: %v",
 				err)
 		}
+		if err := s.jobMgr.Stop(); err != nil {
+			srvrLog.Warnf("Unable to stop JobManager: %v", err)
+		}
+		if s.accounts != nil {
+			if err := s.accounts.Stop(); err != nil {
+				srvrLog.Warnf("Unable to stop account "+
+					"service: %v", err)
+			}
+		}
 		s.missionController.StopStoreTickers()
 
 		// Disconnect from each active peers to ensure that
@@ -3270,6 +3803,7 @@ func (s *server) updateAndBrodcastSelfNode(features *lnwire.RawFeatureVector,
 	selfNode.Features = s.featureMgr.Get(feature.SetNodeAnn)
 	selfNode.Color = newNodeAnn.RGBColor
 	selfNode.AuthSigBytes = newNodeAnn.Signature.ToSignatureBytes()
+	selfNode.ExtraOpaqueData = newNodeAnn.ExtraOpaqueData
 
 	copy(selfNode.PubKeyBytes[:], s.identityECDH.PubKey().SerializeCompressed())
 
@@ -3288,6 +3822,99 @@ func (s *server) updateAndBrodcastSelfNode(features *lnwire.RawFeatureVector,
 	return nil
 }
 
+// advertiseTower adds the given watchtower advertisement to our node
+// announcement and broadcasts the updated announcement, so that watchtower
+// clients can discover the tower.
+func (s *server) advertiseTower(ann *wtwire.TowerAnnouncement) error {
+	extraData := s.getNodeAnnouncement().ExtraOpaqueData
+	if err := wtwire.SetTowerAnn(&extraData, ann); err != nil {
+		return fmt.Errorf("unable to add tower advertisement: %w", err)
+	}
+
+	return s.updateAndBrodcastSelfNode(
+		nil, func(nodeAnn *lnwire.NodeAnnouncement) {
+			nodeAnn.ExtraOpaqueData = extraData
+		},
+	)
+}
+
+// advertiseInboundPolicy adds the given inbound channel policy to our node
+// announcement and broadcasts the updated announcement, so that peers can skip
+// channel opens we would reject.
+func (s *server) advertiseInboundPolicy(
+	policy *lnwire.InboundChanPolicy) error {
+
+	extraData := s.getNodeAnnouncement().ExtraOpaqueData
+	if err := lnwire.SetInboundChanPolicy(&extraData, policy); err != nil {
+		return fmt.Errorf("unable to add inbound channel policy: %w",
+			err)
+	}
+
+	return s.updateAndBrodcastSelfNode(
+		nil, func(nodeAnn *lnwire.NodeAnnouncement) {
+			nodeAnn.ExtraOpaqueData = extraData
+		},
+	)
+}
+
+// fetchInboundPolicy returns the inbound channel policy the given node
+// publishes in its node announcement. Nil is returned if the node isn't known
+// or doesn't publish a policy.
+func (s *server) fetchInboundPolicy(
+	pub *btcec.PublicKey) (*lnwire.InboundChanPolicy, error) {
+
+	vertex := route.NewVertex(pub)
+	node, err := s.graphDB.FetchLightningNode(vertex)
+	switch {
+	case errors.Is(err, channeldb.ErrGraphNodeNotFound):
+		return nil, nil
+
+	case err != nil:
+		return nil, err
+	}
+
+	if !node.HaveNodeAnnouncement {
+		return nil, nil
+	}
+
+	return lnwire.ParseInboundChanPolicy(node.ExtraOpaqueData)
+}
+
+// fetchTowerAnns returns the watchtower advertisements found in the node
+// announcements of the graph. Our own node is skipped, as are advertisements
+// that can't be parsed.
+func (s *server) fetchTowerAnns() ([]*wtwire.TowerAnnouncement, error) {
+	selfKey := s.identityECDH.PubKey().SerializeCompressed()
+
+	var anns []*wtwire.TowerAnnouncement
+	err := s.graphDB.ForEachNode(func(_ kvdb.RTx,
+		node *channeldb.LightningNode) error {
+
+		if bytes.Equal(node.PubKeyBytes[:], selfKey) {
+			return nil
+		}
+
+		ann, err := wtwire.ParseTowerAnn(node.ExtraOpaqueData)
+		if err != nil {
+			srvrLog.Debugf("Unable to parse tower advertisement "+
+				"of node %x: %v", node.PubKeyBytes, err)
+
+			return nil
+		}
+
+		if ann != nil {
+			anns = append(anns, ann)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return anns, nil
+}
+
 type nodeAddresses struct {
 	pubKey    *btcec.PublicKey
 	addresses []net.Addr
@@ -3414,6 +4041,32 @@ func (s *server) establishPersistentConnections() error {
 		return err
 	}
 
+	// Finally, we'll add the peers the user asked us to keep a connection
+	// to through their policy, together with the addresses they gave us.
+	persistentPolicies := make(map[string]struct{})
+	for pubKey, policy := range s.peerPolicies.allPolicies() {
+		if !policy.Persistent {
+			continue
+		}
+
+		identityKey, err := btcec.ParsePubKey(pubKey[:])
+		if err != nil {
+			return err
+		}
+
+		pubStr := string(pubKey[:])
+		persistentPolicies[pubStr] = struct{}{}
+
+		n, ok := nodeAddrsMap[pubStr]
+		if !ok {
+			n = &nodeAddresses{
+				pubKey: identityKey,
+			}
+			nodeAddrsMap[pubStr] = n
+		}
+		n.addresses = append(n.addresses, policy.Addresses...)
+	}
+
 	srvrLog.Debugf("Establishing %v persistent connections on start",
 		len(nodeAddrsMap))
 
@@ -3427,11 +4080,13 @@ func (s *server) establishPersistentConnections() error {
 	var numOutboundConns int
 	for pubStr, nodeAddr := range nodeAddrsMap {
 		// Add this peer to the set of peers we should maintain a
-		// persistent connection with. We set the value to false to
-		// indicate that we should not continue to reconnect if the
-		// number of channels returns to zero, since this peer has not
-		// been requested as perm by the user.
-		s.persistentPeers[pubStr] = false
+		// persistent connection with. Unless the policy of the peer
+		// makes it persistent, we set the value to false to indicate
+		// that we should not continue to reconnect if the number of
+		// channels returns to zero, since this peer has not been
+		// requested as perm by the user.
+		_, perm := persistentPolicies[pubStr]
+		s.persistentPeers[pubStr] = perm
 		if _, ok := s.persistentPeersBackoff[pubStr]; !ok {
 			s.persistentPeersBackoff[pubStr] = s.cfg.MinBackoff
 		}
@@ -3776,6 +4431,18 @@ func (s *server) InboundPeerConnected(conn net.Conn) {
 		return
 	}
 
+	// Drop the connection if the inbound access lists or the policy of
+	// the peer don't allow it.
+	err := s.peerPolicies.checkInbound(pubBytes, conn.RemoteAddr())
+	if err != nil {
+		srvrLog.Debugf("Dropping inbound connection from %x: %v",
+			pubSer, err)
+
+		conn.Close()
+
+		return
+	}
+
 	// If we already have an outbound connection to this peer, then ignore
 	// this new connection.
 	if p, ok := s.outboundPeers[pubStr]; ok {
@@ -4044,6 +4711,12 @@ func (s *server) handleCustomMessage(peer [33]byte, msg *lnwire.Custom) error {
 	srvrLog.Debugf("Custom message received: peer=%x, type=%d",
 		peer, msg.Type)
 
+	// The messages of the cooperative HTLC sweep protocol are consumed by
+	// the chain arbitrator.
+	if s.chainArb.HandleCustomMessage(peer, msg) {
+		return nil
+	}
+
 	return s.customMessageServer.SendUpdate(&CustomMessage{
 		Peer: peer,
 		Msg:  msg,
@@ -4128,6 +4801,7 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		ReadPool:                s.readPool,
 		Switch:                  s.htlcSwitch,
 		InterceptSwitch:         s.interceptableSwitch,
+		ForwardingRules:         s.forwardingRules,
 		ChannelDB:               s.chanStateDB,
 		ChannelGraph:            s.graphDB,
 		ChainArb:                s.chainArb,
@@ -4184,6 +4858,8 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		MsgRouter:              s.implCfg.MsgRouter,
 		AuxChanCloser:          s.implCfg.AuxChanCloser,
 		AuxResolver:            s.implCfg.AuxContractResolver,
+
+		ChannelSettleBatchWindow: s.cfg.ChannelSettleBatchWindow,
 	}
 
 	copy(pCfg.PubKeyBytes[:], peerAddr.IdentityKey.SerializeCompressed())
@@ -4524,6 +5200,15 @@ func (s *server) connectToPersistentPeer(pubKeyStr string) {
 	// connection requests for.
 	addrMap := make(map[string]*lnwire.NetAddress)
 	for _, addr := range s.persistentPeerAddrs[pubKeyStr] {
+		// Skip the addresses the policy of the peer doesn't allow us
+		// to connect to.
+		err := s.peerPolicies.checkOutbound(
+			route.NewVertex(addr.IdentityKey), addr.Address,
+		)
+		if err != nil {
+			continue
+		}
+
 		addrMap[addr.String()] = addr
 	}
 
@@ -4664,6 +5349,14 @@ func (s *server) ConnectToPeer(addr *lnwire.NetAddress,
 
 	targetPub := string(addr.IdentityKey.SerializeCompressed())
 
+	// Make sure the policy of the peer allows connecting to the address.
+	err := s.peerPolicies.checkOutbound(
+		route.NewVertex(addr.IdentityKey), addr.Address,
+	)
+	if err != nil {
+		return err
+	}
+
 	// Acquire mutex, but use explicit unlocking instead of defer for
 	// better granularity.  In certain conditions, this method requires
 	// making an outbound connection to a remote peer, which requires the
@@ -4814,7 +5507,8 @@ func (s *server) OpenChannel(
 	if !ok {
 		s.mu.RUnlock()
 
-		req.Err <- fmt.Errorf("peer %x is not online", pubKeyBytes)
+		req.Err <- fmt.Errorf("peer %x is not online: %w", pubKeyBytes,
+			ErrPeerNotConnected)
 		return req.Updates, req.Err
 	}
 	req.Peer = peer
@@ -4825,7 +5519,8 @@ func (s *server) OpenChannel(
 	select {
 	case <-peer.ActiveSignal():
 	case <-peer.QuitSignal():
-		req.Err <- fmt.Errorf("peer %x disconnected", pubKeyBytes)
+		req.Err <- fmt.Errorf("peer %x disconnected: %w", pubKeyBytes,
+			ErrPeerNotConnected)
 		return req.Updates, req.Err
 	case <-s.quit:
 		req.Err <- ErrServerShuttingDown
@@ -4918,6 +5613,31 @@ func (s *server) fetchNodeAdvertisedAddrs(pub *btcec.PublicKey) ([]net.Addr, err
 	return node.Addresses, nil
 }
 
+// mailboxWeightUnit is the channel capacity that corresponds to one unit of
+// weight when the switch delivers htlcs fairly across the mailboxes.
+const mailboxWeightUnit btcutil.Amount = 1_000_000
+
+// mailboxWeight returns the weight of the mailbox of the given channel, which
+// grows with the channel's capacity. This allows large channels to receive
+// more delivery slots than small ones once the switch limits the number of
+// htlcs in flight.
+func (s *server) mailboxWeight(chanID lnwire.ChannelID) uint32 {
+	channel, err := s.chanStateDB.FetchChannelByID(nil, chanID)
+	if err != nil {
+		srvrLog.Debugf("Unable to fetch channel %v for mailbox "+
+			"weight: %v", chanID, err)
+
+		return 1
+	}
+
+	weight := channel.Capacity / mailboxWeightUnit
+	if weight < 1 {
+		return 1
+	}
+
+	return uint32(weight)
+}
+
 // fetchLastChanUpdate returns a function which is able to retrieve our latest
 // channel update for a target channel.
 func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
@@ -5096,3 +5816,170 @@ func (s *server) fetchClosedChannelSCIDs() map[lnwire.ShortChannelID]struct{} {
 
 	return closedSCIDs
 }
+
+// createProber creates the background prober from the given config. If no
+// explicit targets are configured, random nodes from the graph are probed.
+func (s *server) createProber(cfg *lncfg.Probing) (*routing.Prober, error) {
+	targets := make([]route.Vertex, 0, len(cfg.Targets))
+	for _, target := range cfg.Targets {
+		vertex, err := route.NewVertexFromStr(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid probe target %v: %w",
+				target, err)
+		}
+
+		targets = append(targets, vertex)
+	}
+
+	// randomTarget selects a random node other than ourselves from the
+	// graph using reservoir sampling.
+	self := route.NewVertex(s.identityECDH.PubKey())
+	randomTarget := func() (route.Vertex, error) {
+		var (
+			target route.Vertex
+			seen   int
+		)
+		err := s.graphDB.ForEachNodeCached(func(node route.Vertex,
+			_ map[uint64]*channeldb.DirectedChannel) error {
+
+			if node == self {
+				return nil
+			}
+
+			seen++
+			if prand.Intn(seen) == 0 {
+				target = node
+			}
+
+			return nil
+		})
+		if err != nil {
+			return route.Vertex{}, err
+		}
+
+		if seen == 0 {
+			return route.Vertex{}, routing.ErrNoProbeTarget
+		}
+
+		return target, nil
+	}
+
+	amt := lnwire.NewMSatFromSatoshis(btcutil.Amount(cfg.Amount))
+	feeLimit := lnwire.NewMSatFromSatoshis(btcutil.Amount(cfg.FeeLimit))
+
+	return routing.NewProber(&routing.ProberConfig{
+		Amount:       amt,
+		FeeLimit:     feeLimit,
+		Timeout:      cfg.Timeout,
+		Targets:      targets,
+		RandomTarget: randomTarget,
+		SendPayment:  s.chanRouter.SendPayment,
+		Ticker:       ticker.New(cfg.Interval),
+		Clock:        clock.NewDefaultClock(),
+		DeletePayment: func(hash lntypes.Hash) error {
+			return s.miscDB.DeletePayment(hash, false)
+		},
+	})
+}
+
+// createSubscriptionInvoice creates the invoice of the given period of a
+// recurring subscription.
+func (s *server) createSubscriptionInvoice(ctx context.Context,
+	tmpl *invoices.SubscriptionTemplate, period uint32,
+	preimage lntypes.Preimage) error {
+
+	cfg := &invoicesrpc.AddInvoiceConfig{
+		AddInvoice:        s.invoices.AddInvoice,
+		IsChannelActive:   s.htlcSwitch.HasActiveLink,
+		ChainParams:       s.cfg.ActiveNetParams.Params,
+		NodeSigner:        s.nodeSigner,
+		DefaultCLTVExpiry: s.cfg.Bitcoin.TimeLockDelta,
+		ChanDB:            s.chanStateDB,
+		Graph:             s.graphDB,
+		GenInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoice)
+		},
+		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoiceAmp)
+		},
+		GetAlias:   s.aliasMgr.GetPeerAlias,
+		BestHeight: s.cc.BestBlockTracker.BestHeight,
+	}
+
+	memo := tmpl.Memo
+	if memo != "" {
+		memo = fmt.Sprintf("%s (period %d)", memo, period+1)
+	}
+
+	invoice := &invoicesrpc.AddInvoiceData{
+		Memo:       memo,
+		Value:      tmpl.Value,
+		Preimage:   &preimage,
+		Expiry:     int64(tmpl.InvoiceExpiry().Seconds()),
+		CltvExpiry: tmpl.CltvExpiry,
+		Private:    tmpl.Private,
+	}
+	_, _, err := invoicesrpc.AddInvoice(ctx, cfg, invoice)
+
+	return err
+}
+
+// sweepJusticeReward bumps the fee of a justice transaction published by our
+// watchtower by handing its reward output to the sweeper. The sweeper's fee
+// function then raises the fee rate of the resulting CPFP package until it
+// confirms within the requested target, spending at most the default budget
+// ratio of the reward.
+func (s *server) sweepJusticeReward(req *lookout.CPFPRequest) error {
+	utxo, err := s.cc.Wallet.FetchOutpointInfo(&req.RewardOutPoint)
+	if err != nil {
+		return fmt.Errorf("unable to fetch reward output %v: %w",
+			req.RewardOutPoint, err)
+	}
+
+	signDesc := &input.SignDescriptor{
+		Output:   req.RewardOutput,
+		HashType: txscript.SigHashAll,
+	}
+
+	var witnessType input.WitnessType
+	switch utxo.AddressType {
+	case lnwallet.WitnessPubKey:
+		witnessType = input.WitnessKeyHash
+	case lnwallet.NestedWitnessPubKey:
+		witnessType = input.NestedWitnessKeyHash
+	case lnwallet.TaprootPubkey:
+		witnessType = input.TaprootPubKeySpend
+		signDesc.HashType = txscript.SigHashDefault
+	default:
+		return fmt.Errorf("unknown witness type of reward output %v",
+			req.RewardOutPoint)
+	}
+
+	height, err := s.cc.BestBlockTracker.BestHeight()
+	if err != nil {
+		return err
+	}
+
+	inp := input.MakeBaseInput(
+		&req.RewardOutPoint, witnessType, signDesc, height,
+		&input.TxInfo{
+			Fee:    req.Fee,
+			Weight: req.Weight,
+		},
+	)
+
+	rewardAmt := btcutil.Amount(req.RewardOutput.Value)
+	params := sweep.Params{
+		Budget: rewardAmt.MulF64(contractcourt.DefaultBudgetRatio),
+		DeadlineHeight: fn.Some(
+			int32(height + req.ConfTarget),
+		),
+	}
+
+	srvrLog.Infof("Sweeping reward output %v to bump justice txn, "+
+		"params=%v", req.RewardOutPoint, params)
+
+	_, err = s.sweeper.SweepInput(&inp, params)
+
+	return err
+}
@@ -329,6 +329,96 @@ func (q *Queries) GetInvoiceFeatures(ctx context.Context, invoiceID int64) ([]In
 	return items, nil
 }
 
+const getInvoiceFiatQuote = `-- name: GetInvoiceFiatQuote :one
+SELECT invoice_id, currency, amount, rate_source, quoted_at
+FROM invoice_fiat_quotes
+WHERE invoice_id = $1
+`
+
+func (q *Queries) GetInvoiceFiatQuote(ctx context.Context, invoiceID int64) (InvoiceFiatQuote, error) {
+	row := q.db.QueryRowContext(ctx, getInvoiceFiatQuote, invoiceID)
+	var i InvoiceFiatQuote
+	err := row.Scan(
+		&i.InvoiceID,
+		&i.Currency,
+		&i.Amount,
+		&i.RateSource,
+		&i.QuotedAt,
+	)
+	return i, err
+}
+
+const getInvoiceHTLCAllowedChannels = `-- name: GetInvoiceHTLCAllowedChannels :many
+SELECT chan_id
+FROM invoice_htlc_allowed_channels
+WHERE invoice_id = $1
+`
+
+func (q *Queries) GetInvoiceHTLCAllowedChannels(ctx context.Context, invoiceID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getInvoiceHTLCAllowedChannels, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var chan_id string
+		if err := rows.Scan(&chan_id); err != nil {
+			return nil, err
+		}
+		items = append(items, chan_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInvoiceHTLCAllowedPeers = `-- name: GetInvoiceHTLCAllowedPeers :many
+SELECT pub_key
+FROM invoice_htlc_allowed_peers
+WHERE invoice_id = $1
+`
+
+func (q *Queries) GetInvoiceHTLCAllowedPeers(ctx context.Context, invoiceID int64) ([][]byte, error) {
+	rows, err := q.db.QueryContext(ctx, getInvoiceHTLCAllowedPeers, invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items [][]byte
+	for rows.Next() {
+		var pub_key []byte
+		if err := rows.Scan(&pub_key); err != nil {
+			return nil, err
+		}
+		items = append(items, pub_key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInvoiceHTLCConstraints = `-- name: GetInvoiceHTLCConstraints :one
+SELECT invoice_id, max_htlcs, min_htlc_amount_msat
+FROM invoice_htlc_constraints
+WHERE invoice_id = $1
+`
+
+func (q *Queries) GetInvoiceHTLCConstraints(ctx context.Context, invoiceID int64) (InvoiceHtlcConstraint, error) {
+	row := q.db.QueryRowContext(ctx, getInvoiceHTLCConstraints, invoiceID)
+	var i InvoiceHtlcConstraint
+	err := row.Scan(&i.InvoiceID, &i.MaxHtlcs, &i.MinHtlcAmountMsat)
+	return i, err
+}
+
 const getInvoiceHTLCCustomRecords = `-- name: GetInvoiceHTLCCustomRecords :many
 SELECT ihcr.htlc_id, key, value
 FROM invoice_htlcs ih JOIN invoice_htlc_custom_records ihcr ON ih.id=ihcr.htlc_id 
@@ -405,6 +495,19 @@ func (q *Queries) GetInvoiceHTLCs(ctx context.Context, invoiceID int64) ([]Invoi
 	return items, nil
 }
 
+const getInvoicePartialPaymentPolicy = `-- name: GetInvoicePartialPaymentPolicy :one
+SELECT invoice_id, tolerance_msat, min_settle_amount_msat
+FROM invoice_partial_payment_policies
+WHERE invoice_id = $1
+`
+
+func (q *Queries) GetInvoicePartialPaymentPolicy(ctx context.Context, invoiceID int64) (InvoicePartialPaymentPolicy, error) {
+	row := q.db.QueryRowContext(ctx, getInvoicePartialPaymentPolicy, invoiceID)
+	var i InvoicePartialPaymentPolicy
+	err := row.Scan(&i.InvoiceID, &i.ToleranceMsat, &i.MinSettleAmountMsat)
+	return i, err
+}
+
 const insertInvoice = `-- name: InsertInvoice :one
 INSERT INTO invoices (
     hash, preimage, memo, amount_msat, cltv_delta, expiry, payment_addr, 
@@ -474,6 +577,33 @@ func (q *Queries) InsertInvoiceFeature(ctx context.Context, arg InsertInvoiceFea
 	return err
 }
 
+const insertInvoiceFiatQuote = `-- name: InsertInvoiceFiatQuote :exec
+INSERT INTO invoice_fiat_quotes (
+    invoice_id, currency, amount, rate_source, quoted_at
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+`
+
+type InsertInvoiceFiatQuoteParams struct {
+	InvoiceID  int64
+	Currency   string
+	Amount     int64
+	RateSource string
+	QuotedAt   time.Time
+}
+
+func (q *Queries) InsertInvoiceFiatQuote(ctx context.Context, arg InsertInvoiceFiatQuoteParams) error {
+	_, err := q.db.ExecContext(ctx, insertInvoiceFiatQuote,
+		arg.InvoiceID,
+		arg.Currency,
+		arg.Amount,
+		arg.RateSource,
+		arg.QuotedAt,
+	)
+	return err
+}
+
 const insertInvoiceHTLC = `-- name: InsertInvoiceHTLC :one
 INSERT INTO invoice_htlcs (
     htlc_id, chan_id, amount_msat, total_mpp_msat, accept_height, accept_time,
@@ -514,6 +644,61 @@ func (q *Queries) InsertInvoiceHTLC(ctx context.Context, arg InsertInvoiceHTLCPa
 	return id, err
 }
 
+const insertInvoiceHTLCAllowedChannel = `-- name: InsertInvoiceHTLCAllowedChannel :exec
+INSERT INTO invoice_htlc_allowed_channels (
+    invoice_id, chan_id
+) VALUES (
+    $1, $2
+)
+`
+
+type InsertInvoiceHTLCAllowedChannelParams struct {
+	InvoiceID int64
+	ChanID    string
+}
+
+func (q *Queries) InsertInvoiceHTLCAllowedChannel(ctx context.Context, arg InsertInvoiceHTLCAllowedChannelParams) error {
+	_, err := q.db.ExecContext(ctx, insertInvoiceHTLCAllowedChannel, arg.InvoiceID, arg.ChanID)
+	return err
+}
+
+const insertInvoiceHTLCAllowedPeer = `-- name: InsertInvoiceHTLCAllowedPeer :exec
+INSERT INTO invoice_htlc_allowed_peers (
+    invoice_id, pub_key
+) VALUES (
+    $1, $2
+)
+`
+
+type InsertInvoiceHTLCAllowedPeerParams struct {
+	InvoiceID int64
+	PubKey    []byte
+}
+
+func (q *Queries) InsertInvoiceHTLCAllowedPeer(ctx context.Context, arg InsertInvoiceHTLCAllowedPeerParams) error {
+	_, err := q.db.ExecContext(ctx, insertInvoiceHTLCAllowedPeer, arg.InvoiceID, arg.PubKey)
+	return err
+}
+
+const insertInvoiceHTLCConstraints = `-- name: InsertInvoiceHTLCConstraints :exec
+INSERT INTO invoice_htlc_constraints (
+    invoice_id, max_htlcs, min_htlc_amount_msat
+) VALUES (
+    $1, $2, $3
+)
+`
+
+type InsertInvoiceHTLCConstraintsParams struct {
+	InvoiceID         int64
+	MaxHtlcs          int32
+	MinHtlcAmountMsat int64
+}
+
+func (q *Queries) InsertInvoiceHTLCConstraints(ctx context.Context, arg InsertInvoiceHTLCConstraintsParams) error {
+	_, err := q.db.ExecContext(ctx, insertInvoiceHTLCConstraints, arg.InvoiceID, arg.MaxHtlcs, arg.MinHtlcAmountMsat)
+	return err
+}
+
 const insertInvoiceHTLCCustomRecord = `-- name: InsertInvoiceHTLCCustomRecord :exec
 INSERT INTO invoice_htlc_custom_records (
     key, value, htlc_id 
@@ -533,6 +718,25 @@ func (q *Queries) InsertInvoiceHTLCCustomRecord(ctx context.Context, arg InsertI
 	return err
 }
 
+const insertInvoicePartialPaymentPolicy = `-- name: InsertInvoicePartialPaymentPolicy :exec
+INSERT INTO invoice_partial_payment_policies (
+    invoice_id, tolerance_msat, min_settle_amount_msat
+) VALUES (
+    $1, $2, $3
+)
+`
+
+type InsertInvoicePartialPaymentPolicyParams struct {
+	InvoiceID           int64
+	ToleranceMsat       int64
+	MinSettleAmountMsat int64
+}
+
+func (q *Queries) InsertInvoicePartialPaymentPolicy(ctx context.Context, arg InsertInvoicePartialPaymentPolicyParams) error {
+	_, err := q.db.ExecContext(ctx, insertInvoicePartialPaymentPolicy, arg.InvoiceID, arg.ToleranceMsat, arg.MinSettleAmountMsat)
+	return err
+}
+
 const nextInvoiceSettleIndex = `-- name: NextInvoiceSettleIndex :one
 UPDATE invoice_sequences SET current_value = current_value + 1
 WHERE name = 'settle_index'
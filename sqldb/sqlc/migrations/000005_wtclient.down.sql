@@ -0,0 +1,18 @@
+DROP TABLE IF EXISTS wtclient_queue_items;
+
+DROP INDEX IF EXISTS wtclient_acked_ranges_channel_id_idx;
+DROP TABLE IF EXISTS wtclient_acked_ranges;
+
+DROP TABLE IF EXISTS wtclient_committed_updates;
+
+DROP TABLE IF EXISTS wtclient_channels;
+
+DROP INDEX IF EXISTS wtclient_sessions_closable_height_idx;
+DROP INDEX IF EXISTS wtclient_sessions_tower_id_idx;
+DROP TABLE IF EXISTS wtclient_sessions;
+
+DROP TABLE IF EXISTS wtclient_session_key_reservations;
+
+DROP TABLE IF EXISTS wtclient_towers;
+
+DROP TABLE IF EXISTS wtclient_state;
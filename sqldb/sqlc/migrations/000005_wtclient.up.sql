@@ -0,0 +1,161 @@
+-- wtclient_state holds the counters and flags of the watchtower client store.
+CREATE TABLE IF NOT EXISTS wtclient_state (
+    name TEXT PRIMARY KEY,
+
+    value BIGINT NOT NULL
+);
+
+-- session_key_index is the last session key index that was reserved.
+-- kv_migrated is set to 1 once the sessions of the legacy kv store were moved
+-- to this store.
+INSERT INTO wtclient_state (name, value)
+VALUES
+    ('session_key_index', 0),
+    ('kv_migrated', 0);
+
+-- wtclient_towers contains the watchtowers the client knows about.
+CREATE TABLE IF NOT EXISTS wtclient_towers (
+    -- The db-assigned id of the tower, used to reference it from its
+    -- sessions.
+    id BIGINT PRIMARY KEY,
+
+    -- The compressed public key of the tower.
+    pub_key BLOB NOT NULL UNIQUE,
+
+    -- The encoded list of addresses the tower can be reached at.
+    addresses BLOB NOT NULL,
+
+    -- The status of the tower as set by the client.
+    status SMALLINT NOT NULL
+);
+
+-- wtclient_session_key_reservations contains the session key indexes that were
+-- reserved for a tower and blob type, but not yet used to create a session.
+CREATE TABLE IF NOT EXISTS wtclient_session_key_reservations (
+    tower_id BIGINT NOT NULL REFERENCES wtclient_towers(id) ON DELETE CASCADE,
+
+    blob_type INTEGER NOT NULL,
+
+    key_index BIGINT NOT NULL,
+
+    UNIQUE (tower_id, blob_type)
+);
+
+-- wtclient_sessions contains the sessions negotiated with the towers.
+CREATE TABLE IF NOT EXISTS wtclient_sessions (
+    -- The db-assigned id of the session.
+    id BIGINT PRIMARY KEY,
+
+    -- The session id, which is the client's public key for the session.
+    session_id BLOB NOT NULL UNIQUE,
+
+    -- The tower the session was negotiated with.
+    tower_id BIGINT NOT NULL REFERENCES wtclient_towers(id),
+
+    -- The last allocated sequence number.
+    seq_num INTEGER NOT NULL,
+
+    -- The last last-applied value the tower echoed back.
+    tower_last_applied INTEGER NOT NULL,
+
+    -- The index of the key used to authenticate with the tower.
+    key_index BIGINT NOT NULL,
+
+    -- The status of the session.
+    status SMALLINT NOT NULL,
+
+    -- The encoded policy negotiated for the session.
+    policy BLOB NOT NULL,
+
+    -- The pkscript the tower's reward is paid to, if any.
+    reward_pk_script BLOB,
+
+    -- The number of updates acked for channels that were already closed.
+    rogue_update_count BIGINT NOT NULL,
+
+    -- The height at which the session became closable, or NULL if it is not
+    -- closable yet.
+    closable_height BIGINT
+);
+
+CREATE INDEX IF NOT EXISTS wtclient_sessions_tower_id_idx ON wtclient_sessions(tower_id);
+CREATE INDEX IF NOT EXISTS wtclient_sessions_closable_height_idx ON wtclient_sessions(closable_height);
+
+-- wtclient_channels contains the channels registered for backups.
+CREATE TABLE IF NOT EXISTS wtclient_channels (
+    -- The db-assigned id of the channel.
+    id BIGINT PRIMARY KEY,
+
+    -- The channel id.
+    chan_id BLOB NOT NULL UNIQUE,
+
+    -- The pkscript that tower sweeps of the channel pay to, or NULL if the
+    -- channel was registered without one.
+    sweep_pk_script BLOB,
+
+    -- The highest commitment height handed to the client for backup.
+    max_commit_height BIGINT,
+
+    -- The height the channel was closed at, or NULL if it is still open.
+    closed_height BIGINT
+);
+
+-- wtclient_committed_updates contains the updates sent to a tower that it
+-- hasn't acked yet.
+CREATE TABLE IF NOT EXISTS wtclient_committed_updates (
+    session_id BIGINT NOT NULL REFERENCES wtclient_sessions(id) ON DELETE CASCADE,
+
+    -- The sequence number allocated to the update.
+    seq_num INTEGER NOT NULL,
+
+    -- The channel id and commitment height of the revoked state. The channel
+    -- isn't referenced by its db-assigned id since it may be closed and
+    -- forgotten before the update is acked.
+    chan_id BLOB NOT NULL,
+
+    commit_height BIGINT NOT NULL,
+
+    -- The breach hint of the revoked commitment.
+    hint BLOB NOT NULL,
+
+    -- The encrypted justice kit.
+    encrypted_blob BLOB NOT NULL,
+
+    UNIQUE (session_id, seq_num)
+);
+
+-- wtclient_acked_ranges contains the commitment heights of a channel a tower
+-- acked for a session. Consecutive heights are merged into a single range, so
+-- that a session backing up the states of a channel in order only needs a
+-- single row per channel.
+CREATE TABLE IF NOT EXISTS wtclient_acked_ranges (
+    session_id BIGINT NOT NULL REFERENCES wtclient_sessions(id) ON DELETE CASCADE,
+
+    channel_id BIGINT NOT NULL REFERENCES wtclient_channels(id) ON DELETE CASCADE,
+
+    -- The first and last commitment height of the range, inclusive.
+    start_height BIGINT NOT NULL,
+
+    end_height BIGINT NOT NULL,
+
+    UNIQUE (session_id, channel_id, start_height)
+);
+
+CREATE INDEX IF NOT EXISTS wtclient_acked_ranges_channel_id_idx ON wtclient_acked_ranges(channel_id);
+
+-- wtclient_queue_items contains the backup tasks that are queued for a tower.
+CREATE TABLE IF NOT EXISTS wtclient_queue_items (
+    id BIGINT PRIMARY KEY,
+
+    -- The namespace of the queue the item belongs to.
+    namespace BLOB NOT NULL,
+
+    -- The position of the item in the queue. Items pushed to the head of the
+    -- queue get an index below the current lowest one.
+    queue_index BIGINT NOT NULL,
+
+    -- The encoded item.
+    item BLOB NOT NULL,
+
+    UNIQUE (namespace, queue_index)
+);
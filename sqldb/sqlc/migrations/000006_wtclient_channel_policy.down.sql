@@ -0,0 +1,2 @@
+ALTER TABLE wtclient_channels DROP COLUMN min_sweep_fee_rate;
+ALTER TABLE wtclient_channels DROP COLUMN backup_excluded;
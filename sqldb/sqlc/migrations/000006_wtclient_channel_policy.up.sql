@@ -0,0 +1,6 @@
+-- backup_excluded is set if the states of the channel shouldn't be backed up.
+ALTER TABLE wtclient_channels ADD COLUMN backup_excluded BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- min_sweep_fee_rate is the minimum fee rate in sat/kw the justice
+-- transactions of the channel must pay, zero meaning any fee rate.
+ALTER TABLE wtclient_channels ADD COLUMN min_sweep_fee_rate BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS wtclient_justice_reports;
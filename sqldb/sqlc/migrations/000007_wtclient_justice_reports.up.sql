@@ -0,0 +1,24 @@
+-- wtclient_justice_reports contains the justice transactions the towers
+-- reported to have published for the client's sessions. The reports are kept
+-- after their sessions are deleted, so they don't reference them.
+CREATE TABLE IF NOT EXISTS wtclient_justice_reports (
+    id BIGINT PRIMARY KEY,
+
+    -- The ID of the session holding the state update that was used to
+    -- construct the justice transaction.
+    session_id BLOB NOT NULL,
+
+    -- The sequence number of the state update within the session.
+    seq_num INTEGER NOT NULL,
+
+    -- The identity key of the tower that published the justice transaction.
+    tower_pub_key BLOB NOT NULL,
+
+    -- The txid of the revoked commitment transaction that was broadcast.
+    breach_txid BLOB NOT NULL,
+
+    -- The txid of the justice transaction that was published in response.
+    justice_txid BLOB NOT NULL,
+
+    UNIQUE (session_id, seq_num)
+);
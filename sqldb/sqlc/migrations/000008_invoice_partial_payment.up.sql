@@ -0,0 +1,15 @@
+-- invoice_partial_payment_policies contains the partial payment policy of
+-- the invoices that accept htlc sets paying less than their value.
+CREATE TABLE IF NOT EXISTS invoice_partial_payment_policies (
+    -- The invoice id this policy belongs to.
+    invoice_id BIGINT PRIMARY KEY REFERENCES invoices(id) ON DELETE CASCADE,
+
+    -- The amount in millisatoshis by which the total of an htlc set may fall
+    -- short of the invoice value.
+    tolerance_msat BIGINT NOT NULL,
+
+    -- The minimum amount in millisatoshis that must be received before the
+    -- invoice may be settled for less than its value, zero meaning no
+    -- minimum.
+    min_settle_amount_msat BIGINT NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS invoice_htlc_allowed_peers;
+DROP TABLE IF EXISTS invoice_htlc_allowed_channels;
+DROP TABLE IF EXISTS invoice_htlc_constraints;
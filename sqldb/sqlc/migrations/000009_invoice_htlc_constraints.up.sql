@@ -0,0 +1,41 @@
+-- invoice_htlc_constraints contains the constraints on the htlcs that are
+-- accepted to pay an invoice. Invoices without constraints have no row.
+CREATE TABLE IF NOT EXISTS invoice_htlc_constraints (
+    -- The invoice id these constraints belong to.
+    invoice_id BIGINT PRIMARY KEY REFERENCES invoices(id) ON DELETE CASCADE,
+
+    -- The maximum number of htlcs in the set that pays the invoice, zero
+    -- meaning no limit.
+    max_htlcs INTEGER NOT NULL,
+
+    -- The minimum amount in millisatoshis of every htlc that pays the
+    -- invoice.
+    min_htlc_amount_msat BIGINT NOT NULL
+);
+
+-- invoice_htlc_allowed_channels contains the incoming channels htlcs paying
+-- an invoice may arrive on. If an invoice has no rows, htlcs are accepted on
+-- any channel.
+CREATE TABLE IF NOT EXISTS invoice_htlc_allowed_channels (
+    -- The invoice id this channel belongs to.
+    invoice_id BIGINT NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+
+    -- The short channel id of the channel. uint64 stored as text.
+    chan_id TEXT NOT NULL,
+
+    -- The channel is unique per invoice.
+    UNIQUE (invoice_id, chan_id)
+);
+
+-- invoice_htlc_allowed_peers contains the peers htlcs paying an invoice may be
+-- received from. If an invoice has no rows, htlcs are accepted from any peer.
+CREATE TABLE IF NOT EXISTS invoice_htlc_allowed_peers (
+    -- The invoice id this peer belongs to.
+    invoice_id BIGINT NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+
+    -- The public key of the peer.
+    pub_key BLOB NOT NULL,
+
+    -- The peer is unique per invoice.
+    UNIQUE (invoice_id, pub_key)
+);
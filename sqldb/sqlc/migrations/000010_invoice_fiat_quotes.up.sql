@@ -0,0 +1,19 @@
+-- invoice_fiat_quotes contains the fiat price that was quoted for an invoice
+-- when it was created. Invoices without a quote have no row.
+CREATE TABLE IF NOT EXISTS invoice_fiat_quotes (
+    -- The invoice id this quote belongs to.
+    invoice_id BIGINT PRIMARY KEY REFERENCES invoices(id) ON DELETE CASCADE,
+
+    -- The ISO 4217 code of the fiat currency.
+    currency TEXT NOT NULL,
+
+    -- The quoted amount in the minor unit of the currency. uint64 stored as
+    -- int64.
+    amount BIGINT NOT NULL,
+
+    -- The source of the exchange rate the quote was based on.
+    rate_source TEXT NOT NULL,
+
+    -- The time at which the quote was made.
+    quoted_at TIMESTAMP NOT NULL
+);
@@ -67,6 +67,14 @@ type InvoiceFeature struct {
 	InvoiceID int64
 }
 
+type InvoiceFiatQuote struct {
+	InvoiceID  int64
+	Currency   string
+	Amount     int64
+	RateSource string
+	QuotedAt   time.Time
+}
+
 type InvoiceHtlc struct {
 	ID           int64
 	ChanID       string
@@ -81,13 +89,109 @@ type InvoiceHtlc struct {
 	InvoiceID    int64
 }
 
+type InvoiceHtlcAllowedChannel struct {
+	InvoiceID int64
+	ChanID    string
+}
+
+type InvoiceHtlcAllowedPeer struct {
+	InvoiceID int64
+	PubKey    []byte
+}
+
+type InvoiceHtlcConstraint struct {
+	InvoiceID         int64
+	MaxHtlcs          int32
+	MinHtlcAmountMsat int64
+}
+
 type InvoiceHtlcCustomRecord struct {
 	Key    int64
 	Value  []byte
 	HtlcID int64
 }
 
+type InvoicePartialPaymentPolicy struct {
+	InvoiceID           int64
+	ToleranceMsat       int64
+	MinSettleAmountMsat int64
+}
+
 type InvoiceSequence struct {
 	Name         string
 	CurrentValue int64
 }
+
+type WtclientAckedRange struct {
+	SessionID   int64
+	ChannelID   int64
+	StartHeight int64
+	EndHeight   int64
+}
+
+type WtclientChannel struct {
+	ID              int64
+	ChanID          []byte
+	SweepPkScript   []byte
+	MaxCommitHeight sql.NullInt64
+	ClosedHeight    sql.NullInt64
+	BackupExcluded  bool
+	MinSweepFeeRate int64
+}
+
+type WtclientCommittedUpdate struct {
+	SessionID     int64
+	SeqNum        int32
+	ChanID        []byte
+	CommitHeight  int64
+	Hint          []byte
+	EncryptedBlob []byte
+}
+
+type WtclientJusticeReport struct {
+	ID          int64
+	SessionID   []byte
+	SeqNum      int32
+	TowerPubKey []byte
+	BreachTxid  []byte
+	JusticeTxid []byte
+}
+
+type WtclientQueueItem struct {
+	ID         int64
+	Namespace  []byte
+	QueueIndex int64
+	Item       []byte
+}
+
+type WtclientSession struct {
+	ID               int64
+	SessionID        []byte
+	TowerID          int64
+	SeqNum           int32
+	TowerLastApplied int32
+	KeyIndex         int64
+	Status           int16
+	Policy           []byte
+	RewardPkScript   []byte
+	RogueUpdateCount int64
+	ClosableHeight   sql.NullInt64
+}
+
+type WtclientSessionKeyReservation struct {
+	TowerID  int64
+	BlobType int32
+	KeyIndex int64
+}
+
+type WtclientState struct {
+	Name  string
+	Value int64
+}
+
+type WtclientTower struct {
+	ID        int64
+	PubKey    []byte
+	Addresses []byte
+	Status    int16
+}
@@ -10,8 +10,23 @@ import (
 )
 
 type Querier interface {
+	CountWtclientAckedRanges(ctx context.Context) (int64, error)
+	CountWtclientClosableSessions(ctx context.Context) (int64, error)
+	CountWtclientCommittedUpdates(ctx context.Context) (int64, error)
+	CountWtclientQueueItems(ctx context.Context) (int64, error)
+	CountWtclientSessionOpenChannels(ctx context.Context, sessionID int64) (int64, error)
+	CountWtclientSessions(ctx context.Context) (int64, error)
 	DeleteCanceledInvoices(ctx context.Context) (sql.Result, error)
 	DeleteInvoice(ctx context.Context, arg DeleteInvoiceParams) (sql.Result, error)
+	DeleteWtclientAckedRange(ctx context.Context, arg DeleteWtclientAckedRangeParams) error
+	DeleteWtclientChannel(ctx context.Context, id int64) error
+	DeleteWtclientChannelIfUnused(ctx context.Context, id int64) error
+	DeleteWtclientCommittedUpdate(ctx context.Context, arg DeleteWtclientCommittedUpdateParams) error
+	DeleteWtclientCommittedUpdates(ctx context.Context, sessionID int64) error
+	DeleteWtclientQueueItem(ctx context.Context, arg DeleteWtclientQueueItemParams) error
+	DeleteWtclientSession(ctx context.Context, id int64) error
+	DeleteWtclientSessionKeyReservation(ctx context.Context, arg DeleteWtclientSessionKeyReservationParams) error
+	DeleteWtclientTower(ctx context.Context, id int64) error
 	FetchAMPSubInvoiceHTLCs(ctx context.Context, arg FetchAMPSubInvoiceHTLCsParams) ([]FetchAMPSubInvoiceHTLCsRow, error)
 	FetchAMPSubInvoices(ctx context.Context, arg FetchAMPSubInvoicesParams) ([]AmpSubInvoice, error)
 	FetchSettledAMPSubInvoices(ctx context.Context, arg FetchSettledAMPSubInvoicesParams) ([]FetchSettledAMPSubInvoicesRow, error)
@@ -23,13 +38,54 @@ type Querier interface {
 	GetInvoice(ctx context.Context, arg GetInvoiceParams) ([]Invoice, error)
 	GetInvoiceBySetID(ctx context.Context, setID []byte) ([]Invoice, error)
 	GetInvoiceFeatures(ctx context.Context, invoiceID int64) ([]InvoiceFeature, error)
+	GetInvoiceFiatQuote(ctx context.Context, invoiceID int64) (InvoiceFiatQuote, error)
+	GetInvoiceHTLCAllowedChannels(ctx context.Context, invoiceID int64) ([]string, error)
+	GetInvoiceHTLCAllowedPeers(ctx context.Context, invoiceID int64) ([][]byte, error)
+	GetInvoiceHTLCConstraints(ctx context.Context, invoiceID int64) (InvoiceHtlcConstraint, error)
 	GetInvoiceHTLCCustomRecords(ctx context.Context, invoiceID int64) ([]GetInvoiceHTLCCustomRecordsRow, error)
 	GetInvoiceHTLCs(ctx context.Context, invoiceID int64) ([]InvoiceHtlc, error)
+	GetInvoicePartialPaymentPolicy(ctx context.Context, invoiceID int64) (InvoicePartialPaymentPolicy, error)
+	GetWtclientAckedRangeByEnd(ctx context.Context, arg GetWtclientAckedRangeByEndParams) (WtclientAckedRange, error)
+	GetWtclientAckedRangeByStart(ctx context.Context, arg GetWtclientAckedRangeByStartParams) (WtclientAckedRange, error)
+	GetWtclientAckedRangeContaining(ctx context.Context, arg GetWtclientAckedRangeContainingParams) (WtclientAckedRange, error)
+	GetWtclientChannel(ctx context.Context, chanID []byte) (WtclientChannel, error)
+	GetWtclientCommittedUpdate(ctx context.Context, arg GetWtclientCommittedUpdateParams) (WtclientCommittedUpdate, error)
+	GetWtclientQueueBounds(ctx context.Context, namespace []byte) (GetWtclientQueueBoundsRow, error)
+	GetWtclientSession(ctx context.Context, sessionID []byte) (WtclientSession, error)
+	GetWtclientSessionByID(ctx context.Context, id int64) (WtclientSession, error)
+	GetWtclientSessionKeyReservation(ctx context.Context, arg GetWtclientSessionKeyReservationParams) (int64, error)
+	GetWtclientState(ctx context.Context, name string) (int64, error)
+	GetWtclientTowerByID(ctx context.Context, id int64) (WtclientTower, error)
+	GetWtclientTowerByPubKey(ctx context.Context, pubKey []byte) (WtclientTower, error)
+	IncrementWtclientSessionRogueCount(ctx context.Context, id int64) (int64, error)
 	InsertAMPSubInvoiceHTLC(ctx context.Context, arg InsertAMPSubInvoiceHTLCParams) error
 	InsertInvoice(ctx context.Context, arg InsertInvoiceParams) (int64, error)
 	InsertInvoiceFeature(ctx context.Context, arg InsertInvoiceFeatureParams) error
+	InsertInvoiceFiatQuote(ctx context.Context, arg InsertInvoiceFiatQuoteParams) error
 	InsertInvoiceHTLC(ctx context.Context, arg InsertInvoiceHTLCParams) (int64, error)
+	InsertInvoiceHTLCAllowedChannel(ctx context.Context, arg InsertInvoiceHTLCAllowedChannelParams) error
+	InsertInvoiceHTLCAllowedPeer(ctx context.Context, arg InsertInvoiceHTLCAllowedPeerParams) error
+	InsertInvoiceHTLCConstraints(ctx context.Context, arg InsertInvoiceHTLCConstraintsParams) error
 	InsertInvoiceHTLCCustomRecord(ctx context.Context, arg InsertInvoiceHTLCCustomRecordParams) error
+	InsertInvoicePartialPaymentPolicy(ctx context.Context, arg InsertInvoicePartialPaymentPolicyParams) error
+	InsertWtclientAckedRange(ctx context.Context, arg InsertWtclientAckedRangeParams) error
+	InsertWtclientChannel(ctx context.Context, arg InsertWtclientChannelParams) (int64, error)
+	InsertWtclientCommittedUpdate(ctx context.Context, arg InsertWtclientCommittedUpdateParams) error
+	InsertWtclientQueueItem(ctx context.Context, arg InsertWtclientQueueItemParams) error
+	InsertWtclientSession(ctx context.Context, arg InsertWtclientSessionParams) (int64, error)
+	InsertWtclientTower(ctx context.Context, arg InsertWtclientTowerParams) (int64, error)
+	ListWtclientChannelAckedRanges(ctx context.Context, channelID int64) ([]ListWtclientChannelAckedRangesRow, error)
+	ListWtclientChannelSessions(ctx context.Context, channelID int64) ([]int64, error)
+	ListWtclientClosableSessions(ctx context.Context) ([]ListWtclientClosableSessionsRow, error)
+	ListWtclientCommittedUpdates(ctx context.Context, sessionID int64) ([]WtclientCommittedUpdate, error)
+	ListWtclientJusticeReports(ctx context.Context) ([]WtclientJusticeReport, error)
+	ListWtclientOpenChannels(ctx context.Context) ([]WtclientChannel, error)
+	ListWtclientQueueItems(ctx context.Context, arg ListWtclientQueueItemsParams) ([]ListWtclientQueueItemsRow, error)
+	ListWtclientSessionAckedRanges(ctx context.Context, sessionID int64) ([]ListWtclientSessionAckedRangesRow, error)
+	ListWtclientSessionChannels(ctx context.Context, sessionID int64) ([]int64, error)
+	ListWtclientSessions(ctx context.Context) ([]WtclientSession, error)
+	ListWtclientTowerSessions(ctx context.Context, towerID int64) ([]WtclientSession, error)
+	ListWtclientTowers(ctx context.Context) ([]WtclientTower, error)
 	NextInvoiceSettleIndex(ctx context.Context) (int64, error)
 	OnAMPSubInvoiceCanceled(ctx context.Context, arg OnAMPSubInvoiceCanceledParams) error
 	OnAMPSubInvoiceCreated(ctx context.Context, arg OnAMPSubInvoiceCreatedParams) error
@@ -37,13 +93,29 @@ type Querier interface {
 	OnInvoiceCanceled(ctx context.Context, arg OnInvoiceCanceledParams) error
 	OnInvoiceCreated(ctx context.Context, arg OnInvoiceCreatedParams) error
 	OnInvoiceSettled(ctx context.Context, arg OnInvoiceSettledParams) error
+	SumWtclientAckedUpdates(ctx context.Context) (int64, error)
+	SumWtclientCommittedUpdateBytes(ctx context.Context) (int64, error)
+	SumWtclientQueueItemBytes(ctx context.Context) (int64, error)
+	SumWtclientSessionAckedUpdates(ctx context.Context, sessionID int64) (int64, error)
 	UpdateAMPSubInvoiceHTLCPreimage(ctx context.Context, arg UpdateAMPSubInvoiceHTLCPreimageParams) (sql.Result, error)
 	UpdateAMPSubInvoiceState(ctx context.Context, arg UpdateAMPSubInvoiceStateParams) error
 	UpdateInvoiceAmountPaid(ctx context.Context, arg UpdateInvoiceAmountPaidParams) (sql.Result, error)
 	UpdateInvoiceHTLC(ctx context.Context, arg UpdateInvoiceHTLCParams) error
 	UpdateInvoiceHTLCs(ctx context.Context, arg UpdateInvoiceHTLCsParams) error
 	UpdateInvoiceState(ctx context.Context, arg UpdateInvoiceStateParams) (sql.Result, error)
+	UpdateWtclientAckedRangeEnd(ctx context.Context, arg UpdateWtclientAckedRangeEndParams) error
+	UpdateWtclientChannelBackupPolicy(ctx context.Context, arg UpdateWtclientChannelBackupPolicyParams) error
+	UpdateWtclientChannelClosed(ctx context.Context, arg UpdateWtclientChannelClosedParams) error
+	UpdateWtclientChannelMaxHeight(ctx context.Context, arg UpdateWtclientChannelMaxHeightParams) error
+	UpdateWtclientSessionClosable(ctx context.Context, arg UpdateWtclientSessionClosableParams) error
+	UpdateWtclientSessionLastApplied(ctx context.Context, arg UpdateWtclientSessionLastAppliedParams) error
+	UpdateWtclientSessionSeqNum(ctx context.Context, arg UpdateWtclientSessionSeqNumParams) error
+	UpdateWtclientSessionStatus(ctx context.Context, arg UpdateWtclientSessionStatusParams) error
+	UpdateWtclientState(ctx context.Context, arg UpdateWtclientStateParams) error
+	UpdateWtclientTower(ctx context.Context, arg UpdateWtclientTowerParams) error
 	UpsertAMPSubInvoice(ctx context.Context, arg UpsertAMPSubInvoiceParams) (sql.Result, error)
+	UpsertWtclientJusticeReport(ctx context.Context, arg UpsertWtclientJusticeReportParams) error
+	UpsertWtclientSessionKeyReservation(ctx context.Context, arg UpsertWtclientSessionKeyReservationParams) error
 }
 
 var _ Querier = (*Queries)(nil)
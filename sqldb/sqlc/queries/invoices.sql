@@ -169,3 +169,63 @@ INSERT INTO invoice_htlc_custom_records (
 SELECT ihcr.htlc_id, key, value
 FROM invoice_htlcs ih JOIN invoice_htlc_custom_records ihcr ON ih.id=ihcr.htlc_id 
 WHERE ih.invoice_id = $1;
+
+-- name: InsertInvoiceHTLCConstraints :exec
+INSERT INTO invoice_htlc_constraints (
+    invoice_id, max_htlcs, min_htlc_amount_msat
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetInvoiceHTLCConstraints :one
+SELECT *
+FROM invoice_htlc_constraints
+WHERE invoice_id = $1;
+
+-- name: InsertInvoiceHTLCAllowedChannel :exec
+INSERT INTO invoice_htlc_allowed_channels (
+    invoice_id, chan_id
+) VALUES (
+    $1, $2
+);
+
+-- name: GetInvoiceHTLCAllowedChannels :many
+SELECT chan_id
+FROM invoice_htlc_allowed_channels
+WHERE invoice_id = $1;
+
+-- name: InsertInvoiceHTLCAllowedPeer :exec
+INSERT INTO invoice_htlc_allowed_peers (
+    invoice_id, pub_key
+) VALUES (
+    $1, $2
+);
+
+-- name: GetInvoiceHTLCAllowedPeers :many
+SELECT pub_key
+FROM invoice_htlc_allowed_peers
+WHERE invoice_id = $1;
+
+-- name: InsertInvoiceFiatQuote :exec
+INSERT INTO invoice_fiat_quotes (
+    invoice_id, currency, amount, rate_source, quoted_at
+) VALUES (
+    $1, $2, $3, $4, $5
+);
+
+-- name: GetInvoiceFiatQuote :one
+SELECT *
+FROM invoice_fiat_quotes
+WHERE invoice_id = $1;
+
+-- name: InsertInvoicePartialPaymentPolicy :exec
+INSERT INTO invoice_partial_payment_policies (
+    invoice_id, tolerance_msat, min_settle_amount_msat
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetInvoicePartialPaymentPolicy :one
+SELECT *
+FROM invoice_partial_payment_policies
+WHERE invoice_id = $1;
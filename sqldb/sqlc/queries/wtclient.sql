@@ -0,0 +1,333 @@
+-- name: GetWtclientState :one
+SELECT value
+FROM wtclient_state
+WHERE name = $1;
+
+-- name: UpdateWtclientState :exec
+UPDATE wtclient_state
+SET value = $1
+WHERE name = $2;
+
+-- name: InsertWtclientTower :one
+INSERT INTO wtclient_towers (
+    pub_key, addresses, status
+) VALUES (
+    $1, $2, $3
+) RETURNING id;
+
+-- name: UpdateWtclientTower :exec
+UPDATE wtclient_towers
+SET addresses = $1, status = $2
+WHERE id = $3;
+
+-- name: GetWtclientTowerByID :one
+SELECT *
+FROM wtclient_towers
+WHERE id = $1;
+
+-- name: GetWtclientTowerByPubKey :one
+SELECT *
+FROM wtclient_towers
+WHERE pub_key = $1;
+
+-- name: ListWtclientTowers :many
+SELECT *
+FROM wtclient_towers
+ORDER BY id;
+
+-- name: DeleteWtclientTower :exec
+DELETE FROM wtclient_towers
+WHERE id = $1;
+
+-- name: GetWtclientSessionKeyReservation :one
+SELECT key_index
+FROM wtclient_session_key_reservations
+WHERE tower_id = $1 AND blob_type = $2;
+
+-- name: UpsertWtclientSessionKeyReservation :exec
+INSERT INTO wtclient_session_key_reservations (
+    tower_id, blob_type, key_index
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (tower_id, blob_type) DO UPDATE
+SET key_index = EXCLUDED.key_index;
+
+-- name: DeleteWtclientSessionKeyReservation :exec
+DELETE FROM wtclient_session_key_reservations
+WHERE tower_id = $1 AND blob_type = $2;
+
+-- name: InsertWtclientSession :one
+INSERT INTO wtclient_sessions (
+    session_id, tower_id, seq_num, tower_last_applied, key_index, status,
+    policy, reward_pk_script, rogue_update_count, closable_height
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id;
+
+-- name: GetWtclientSession :one
+SELECT *
+FROM wtclient_sessions
+WHERE session_id = $1;
+
+-- name: GetWtclientSessionByID :one
+SELECT *
+FROM wtclient_sessions
+WHERE id = $1;
+
+-- name: ListWtclientSessions :many
+SELECT *
+FROM wtclient_sessions
+ORDER BY id;
+
+-- name: ListWtclientTowerSessions :many
+SELECT *
+FROM wtclient_sessions
+WHERE tower_id = $1
+ORDER BY id;
+
+-- name: ListWtclientClosableSessions :many
+SELECT session_id, closable_height
+FROM wtclient_sessions
+WHERE closable_height IS NOT NULL;
+
+-- name: UpdateWtclientSessionSeqNum :exec
+UPDATE wtclient_sessions
+SET seq_num = $1
+WHERE id = $2;
+
+-- name: UpdateWtclientSessionLastApplied :exec
+UPDATE wtclient_sessions
+SET tower_last_applied = $1
+WHERE id = $2;
+
+-- name: UpdateWtclientSessionStatus :exec
+UPDATE wtclient_sessions
+SET status = $1
+WHERE id = $2;
+
+-- name: IncrementWtclientSessionRogueCount :one
+UPDATE wtclient_sessions
+SET rogue_update_count = rogue_update_count + 1
+WHERE id = $1
+RETURNING rogue_update_count;
+
+-- name: UpdateWtclientSessionClosable :exec
+UPDATE wtclient_sessions
+SET closable_height = $1
+WHERE id = $2;
+
+-- name: DeleteWtclientSession :exec
+DELETE FROM wtclient_sessions
+WHERE id = $1;
+
+-- name: CountWtclientSessions :one
+SELECT COUNT(*)
+FROM wtclient_sessions;
+
+-- name: CountWtclientClosableSessions :one
+SELECT COUNT(*)
+FROM wtclient_sessions
+WHERE closable_height IS NOT NULL;
+
+-- name: InsertWtclientCommittedUpdate :exec
+INSERT INTO wtclient_committed_updates (
+    session_id, seq_num, chan_id, commit_height, hint, encrypted_blob
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+);
+
+-- name: GetWtclientCommittedUpdate :one
+SELECT *
+FROM wtclient_committed_updates
+WHERE session_id = $1 AND seq_num = $2;
+
+-- name: ListWtclientCommittedUpdates :many
+SELECT *
+FROM wtclient_committed_updates
+WHERE session_id = $1
+ORDER BY seq_num;
+
+-- name: DeleteWtclientCommittedUpdate :exec
+DELETE FROM wtclient_committed_updates
+WHERE session_id = $1 AND seq_num = $2;
+
+-- name: DeleteWtclientCommittedUpdates :exec
+DELETE FROM wtclient_committed_updates
+WHERE session_id = $1;
+
+-- name: CountWtclientCommittedUpdates :one
+SELECT COUNT(*)
+FROM wtclient_committed_updates;
+
+-- name: SumWtclientCommittedUpdateBytes :one
+SELECT CAST(COALESCE(SUM(LENGTH(hint) + LENGTH(encrypted_blob)), 0) AS BIGINT) AS num_bytes
+FROM wtclient_committed_updates;
+
+-- name: InsertWtclientChannel :one
+INSERT INTO wtclient_channels (
+    chan_id, sweep_pk_script, max_commit_height, closed_height
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING id;
+
+-- name: GetWtclientChannel :one
+SELECT *
+FROM wtclient_channels
+WHERE chan_id = $1;
+
+-- name: ListWtclientOpenChannels :many
+SELECT *
+FROM wtclient_channels
+WHERE closed_height IS NULL;
+
+-- name: UpdateWtclientChannelMaxHeight :exec
+UPDATE wtclient_channels
+SET max_commit_height = @height
+WHERE chan_id = @chan_id AND (
+    max_commit_height IS NULL OR max_commit_height < @height
+);
+
+-- name: UpdateWtclientChannelBackupPolicy :exec
+UPDATE wtclient_channels
+SET backup_excluded = $1, min_sweep_fee_rate = $2
+WHERE id = $3;
+
+-- name: UpdateWtclientChannelClosed :exec
+UPDATE wtclient_channels
+SET closed_height = $1
+WHERE id = $2;
+
+-- name: DeleteWtclientChannel :exec
+DELETE FROM wtclient_channels
+WHERE id = $1;
+
+-- name: DeleteWtclientChannelIfUnused :exec
+DELETE FROM wtclient_channels
+WHERE id = @id AND NOT EXISTS (
+    SELECT 1
+    FROM wtclient_acked_ranges
+    WHERE channel_id = @id
+);
+
+-- name: ListWtclientChannelSessions :many
+SELECT DISTINCT session_id
+FROM wtclient_acked_ranges
+WHERE channel_id = $1;
+
+-- name: ListWtclientSessionChannels :many
+SELECT DISTINCT channel_id
+FROM wtclient_acked_ranges
+WHERE session_id = $1;
+
+-- name: CountWtclientSessionOpenChannels :one
+SELECT COUNT(DISTINCT r.channel_id)
+FROM wtclient_acked_ranges r
+JOIN wtclient_channels c ON c.id = r.channel_id
+WHERE r.session_id = $1 AND c.closed_height IS NULL;
+
+-- name: InsertWtclientAckedRange :exec
+INSERT INTO wtclient_acked_ranges (
+    session_id, channel_id, start_height, end_height
+) VALUES (
+    $1, $2, $3, $4
+);
+
+-- name: GetWtclientAckedRangeContaining :one
+SELECT *
+FROM wtclient_acked_ranges
+WHERE session_id = @session_id AND channel_id = @channel_id AND
+    start_height <= @height AND end_height >= @height;
+
+-- name: GetWtclientAckedRangeByStart :one
+SELECT *
+FROM wtclient_acked_ranges
+WHERE session_id = $1 AND channel_id = $2 AND start_height = $3;
+
+-- name: GetWtclientAckedRangeByEnd :one
+SELECT *
+FROM wtclient_acked_ranges
+WHERE session_id = $1 AND channel_id = $2 AND end_height = $3;
+
+-- name: UpdateWtclientAckedRangeEnd :exec
+UPDATE wtclient_acked_ranges
+SET end_height = $1
+WHERE session_id = $2 AND channel_id = $3 AND start_height = $4;
+
+-- name: DeleteWtclientAckedRange :exec
+DELETE FROM wtclient_acked_ranges
+WHERE session_id = $1 AND channel_id = $2 AND start_height = $3;
+
+-- name: ListWtclientChannelAckedRanges :many
+SELECT start_height, end_height
+FROM wtclient_acked_ranges
+WHERE channel_id = $1
+ORDER BY start_height;
+
+-- name: ListWtclientSessionAckedRanges :many
+SELECT c.chan_id, r.start_height, r.end_height
+FROM wtclient_acked_ranges r
+JOIN wtclient_channels c ON c.id = r.channel_id
+WHERE r.session_id = $1
+ORDER BY r.channel_id, r.start_height;
+
+-- name: SumWtclientSessionAckedUpdates :one
+SELECT CAST(COALESCE(SUM(end_height - start_height + 1), 0) AS BIGINT) AS num_acked
+FROM wtclient_acked_ranges
+WHERE session_id = $1;
+
+-- name: CountWtclientAckedRanges :one
+SELECT COUNT(*)
+FROM wtclient_acked_ranges;
+
+-- name: SumWtclientAckedUpdates :one
+SELECT CAST(COALESCE(SUM(end_height - start_height + 1), 0) AS BIGINT) AS num_acked
+FROM wtclient_acked_ranges;
+
+-- name: InsertWtclientQueueItem :exec
+INSERT INTO wtclient_queue_items (
+    namespace, queue_index, item
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetWtclientQueueBounds :one
+SELECT
+    CAST(COALESCE(MIN(queue_index), 0) AS BIGINT) AS min_index,
+    CAST(COALESCE(MAX(queue_index), 0) AS BIGINT) AS max_index,
+    COUNT(*) AS num_items
+FROM wtclient_queue_items
+WHERE namespace = $1;
+
+-- name: ListWtclientQueueItems :many
+SELECT queue_index, item
+FROM wtclient_queue_items
+WHERE namespace = $1
+ORDER BY queue_index
+LIMIT $2;
+
+-- name: DeleteWtclientQueueItem :exec
+DELETE FROM wtclient_queue_items
+WHERE namespace = $1 AND queue_index = $2;
+
+-- name: CountWtclientQueueItems :one
+SELECT COUNT(*)
+FROM wtclient_queue_items;
+
+-- name: SumWtclientQueueItemBytes :one
+SELECT CAST(COALESCE(SUM(LENGTH(item)), 0) AS BIGINT) AS num_bytes
+FROM wtclient_queue_items;
+
+-- name: UpsertWtclientJusticeReport :exec
+INSERT INTO wtclient_justice_reports (
+    session_id, seq_num, tower_pub_key, breach_txid, justice_txid
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (session_id, seq_num) DO UPDATE
+SET tower_pub_key = EXCLUDED.tower_pub_key,
+    breach_txid = EXCLUDED.breach_txid,
+    justice_txid = EXCLUDED.justice_txid;
+
+-- name: ListWtclientJusticeReports :many
+SELECT *
+FROM wtclient_justice_reports
+ORDER BY session_id, seq_num;
@@ -29,6 +29,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/protofsm"
 	"github.com/lightningnetwork/lnd/routing"
 	"github.com/lightningnetwork/lnd/sweep"
 	"github.com/lightningnetwork/lnd/watchtower"
@@ -115,6 +116,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	graphDB *channeldb.ChannelGraph,
 	chanStateDB *channeldb.ChannelStateDB,
 	sweeper *sweep.UtxoSweeper,
+	spendArbiter *sweep.SpendArbiter,
+	sweepLedger sweep.SweepLedger,
+	anchorBumps walletrpc.AnchorBumpSource,
 	tower *watchtower.Standalone,
 	towerClientMgr *wtclient.Manager,
 	tcpResolver lncfg.TCPResolver,
@@ -124,9 +128,13 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	updateNodeAnnouncement func(features *lnwire.RawFeatureVector,
 		modifiers ...netann.NodeAnnModifier) error,
 	parseAddr func(addr string) (net.Addr, error),
+	peerPolicies peersrpc.PeerPolicyManager,
 	rpcLogger btclog.Logger, aliasMgr *aliasmgr.Manager,
 	auxDataParser fn.Option[AuxDataParser],
-	invoiceHtlcModifier *invoices.HtlcModificationInterceptor) error {
+	invoiceHtlcModifier *invoices.HtlcModificationInterceptor,
+	invoiceWebhook *invoicesrpc.WebhookDispatcher,
+	invoiceSubscriptions *invoices.SubscriptionManager,
+	fsmRegistry *protofsm.Registry) error {
 
 	// First, we'll use reflect to obtain a version of the config struct
 	// that allows us to programmatically inspect its fields.
@@ -192,6 +200,12 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("Sweeper").Set(
 				reflect.ValueOf(sweeper),
 			)
+			subCfgValue.FieldByName("SpendArbiter").Set(
+				reflect.ValueOf(spendArbiter),
+			)
+			subCfgValue.FieldByName("SweepLedger").Set(
+				reflect.ValueOf(sweepLedger),
+			)
 			subCfgValue.FieldByName("Chain").Set(
 				reflect.ValueOf(cc.ChainIO),
 			)
@@ -209,6 +223,9 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("ChanStateDB").Set(
 				reflect.ValueOf(chanStateDB),
 			)
+			subCfgValue.FieldByName("AnchorBumps").Set(
+				reflect.ValueOf(anchorBumps),
+			)
 
 		case *autopilotrpc.Config:
 			subCfgValue := extractReflectValue(subCfg)
@@ -248,6 +265,12 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("HtlcModifier").Set(
 				reflect.ValueOf(invoiceHtlcModifier),
 			)
+			subCfgValue.FieldByName("Webhook").Set(
+				reflect.ValueOf(invoiceWebhook),
+			)
+			subCfgValue.FieldByName("Subscriptions").Set(
+				reflect.ValueOf(invoiceSubscriptions),
+			)
 			subCfgValue.FieldByName("IsChannelActive").Set(
 				reflect.ValueOf(htlcSwitch.HasActiveLink),
 			)
@@ -264,6 +287,10 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("GraphDB").Set(
 				reflect.ValueOf(graphDB),
 			)
+
+			subCfgValue.FieldByName("FSMRegistry").Set(
+				reflect.ValueOf(fsmRegistry),
+			)
 			subCfgValue.FieldByName("ChanStateDB").Set(
 				reflect.ValueOf(chanStateDB),
 			)
@@ -361,6 +388,10 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 				reflect.ValueOf(updateNodeAnnouncement),
 			)
 
+			subCfgValue.FieldByName("PeerPolicies").Set(
+				reflect.ValueOf(peerPolicies),
+			)
+
 		default:
 			return fmt.Errorf("unknown field: %v, %T", fieldName,
 				cfg)
@@ -0,0 +1,537 @@
+package lnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"google.golang.org/grpc"
+)
+
+// subscribeAllEventTypes are the types of events of the combined event
+// stream.
+var subscribeAllEventTypes = routerrpc.SubscribeAllEvent_EventType_name
+
+// eventRefs are the channels and peers an event relates to.
+type eventRefs struct {
+	chanPoints []string
+	chanIDs    []uint64
+	peers      []string
+}
+
+// subscribeAllEventRefs returns the channels and peers the event relates to.
+func subscribeAllEventRefs(e *routerrpc.SubscribeAllEvent) eventRefs {
+	var refs eventRefs
+	switch e.Type {
+	case routerrpc.SubscribeAllEvent_CHANNEL:
+		switch update := e.GetChannelEvent().GetChannel().(type) {
+		case *lnrpc.ChannelEventUpdate_PendingOpenChannel:
+			pending := update.PendingOpenChannel
+			hash, err := chainhash.NewHash(pending.Txid)
+			if err == nil {
+				op := wire.OutPoint{
+					Hash:  *hash,
+					Index: pending.OutputIndex,
+				}
+				refs.chanPoints = append(
+					refs.chanPoints, op.String(),
+				)
+			}
+
+		case *lnrpc.ChannelEventUpdate_OpenChannel:
+			channel := update.OpenChannel
+			refs.chanPoints = append(
+				refs.chanPoints, channel.ChannelPoint,
+			)
+			refs.chanIDs = append(refs.chanIDs, channel.ChanId)
+			refs.peers = append(refs.peers, channel.RemotePubkey)
+
+		case *lnrpc.ChannelEventUpdate_ClosedChannel:
+			channel := update.ClosedChannel
+			refs.chanPoints = append(
+				refs.chanPoints, channel.ChannelPoint,
+			)
+			refs.chanIDs = append(refs.chanIDs, channel.ChanId)
+			refs.peers = append(refs.peers, channel.RemotePubkey)
+
+		case *lnrpc.ChannelEventUpdate_ActiveChannel:
+			refs.chanPoints = appendChanPoint(
+				refs.chanPoints, update.ActiveChannel,
+			)
+
+		case *lnrpc.ChannelEventUpdate_InactiveChannel:
+			refs.chanPoints = appendChanPoint(
+				refs.chanPoints, update.InactiveChannel,
+			)
+
+		case *lnrpc.ChannelEventUpdate_FullyResolvedChannel:
+			refs.chanPoints = appendChanPoint(
+				refs.chanPoints, update.FullyResolvedChannel,
+			)
+		}
+
+	case routerrpc.SubscribeAllEvent_PEER:
+		refs.peers = append(refs.peers, e.GetPeerEvent().GetPubKey())
+
+	case routerrpc.SubscribeAllEvent_HTLC:
+		refs.chanIDs = append(
+			refs.chanIDs, e.GetHtlcEvent().GetIncomingChannelId(),
+			e.GetHtlcEvent().GetOutgoingChannelId(),
+		)
+
+	case routerrpc.SubscribeAllEvent_INVOICE:
+		for _, htlc := range e.GetInvoice().GetHtlcs() {
+			refs.chanIDs = append(refs.chanIDs, htlc.ChanId)
+		}
+
+	// A payment relates to the first hop of each of its attempts.
+	case routerrpc.SubscribeAllEvent_PAYMENT:
+		for _, htlc := range e.GetPayment().GetHtlcs() {
+			if htlc.Route == nil || len(htlc.Route.Hops) == 0 {
+				continue
+			}
+
+			firstHop := htlc.Route.Hops[0]
+			refs.chanIDs = append(refs.chanIDs, firstHop.ChanId)
+			refs.peers = append(refs.peers, firstHop.PubKey)
+		}
+	}
+
+	return refs
+}
+
+// appendChanPoint appends the string representation of the channel point to
+// the slice, if it is valid.
+func appendChanPoint(chanPoints []string,
+	chanPoint *lnrpc.ChannelPoint) []string {
+
+	if chanPoint == nil {
+		return chanPoints
+	}
+
+	hash, err := lnrpc.GetChanPointFundingTxid(chanPoint)
+	if err != nil {
+		return chanPoints
+	}
+
+	op := wire.NewOutPoint(hash, chanPoint.OutputIndex)
+
+	return append(chanPoints, op.String())
+}
+
+// subscribeAllMatcher decides which events of the combined event stream match
+// a filter.
+type subscribeAllMatcher struct {
+	types      map[routerrpc.SubscribeAllEvent_EventType]struct{}
+	chanPoints map[string]struct{}
+	chanIDs    map[uint64]struct{}
+	peers      map[string]struct{}
+}
+
+// newSubscribeAllMatcher creates a matcher for the filter of the request. The
+// short channel IDs of the filtered channels are looked up, so that htlc
+// events can be matched against them.
+func newSubscribeAllMatcher(req *routerrpc.SubscribeAllRequest,
+	chanStateDB *channeldb.ChannelStateDB) (*subscribeAllMatcher, error) {
+
+	m := &subscribeAllMatcher{
+		types: make(
+			map[routerrpc.SubscribeAllEvent_EventType]struct{},
+		),
+		chanPoints: make(map[string]struct{}),
+		chanIDs:    make(map[uint64]struct{}),
+		peers:      make(map[string]struct{}),
+	}
+
+	for _, t := range req.Types {
+		if _, ok := subscribeAllEventTypes[int32(t)]; !ok {
+			return nil, fmt.Errorf("unknown event type %v", t)
+		}
+
+		m.types[t] = struct{}{}
+	}
+
+	for _, rpcChanPoint := range req.ChannelPoints {
+		txid, err := lnrpc.GetChanPointFundingTxid(rpcChanPoint)
+		if err != nil {
+			return nil, err
+		}
+		chanPoint := wire.NewOutPoint(txid, rpcChanPoint.OutputIndex)
+		m.chanPoints[chanPoint.String()] = struct{}{}
+
+		// Channels that aren't open (anymore) can still be matched by
+		// their channel point.
+		channel, err := chanStateDB.FetchChannel(nil, *chanPoint)
+		switch {
+		case errors.Is(err, channeldb.ErrChannelNotFound):
+			continue
+
+		case err != nil:
+			return nil, err
+		}
+
+		m.chanIDs[channel.ShortChanID().ToUint64()] = struct{}{}
+	}
+
+	for _, peerBytes := range req.Peers {
+		peer, err := route.NewVertexFromBytes(peerBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer: %w", err)
+		}
+		m.peers[peer.String()] = struct{}{}
+	}
+
+	return m, nil
+}
+
+// wantsType returns true if events of the given type are subscribed to.
+func (m *subscribeAllMatcher) wantsType(
+	t routerrpc.SubscribeAllEvent_EventType) bool {
+
+	if len(m.types) == 0 {
+		return true
+	}
+
+	_, ok := m.types[t]
+
+	return ok
+}
+
+// matches returns true if the event matches the filter. Events that don't
+// relate to any channel or peer, such as new blocks, only need to be of a
+// subscribed type. All other events need to relate to one of the filtered
+// channels or peers, if there are any.
+func (m *subscribeAllMatcher) matches(event *routerrpc.SubscribeAllEvent) bool {
+	if !m.wantsType(event.Type) {
+		return false
+	}
+
+	if len(m.chanPoints) == 0 && len(m.peers) == 0 {
+		return true
+	}
+
+	refs := subscribeAllEventRefs(event)
+	if len(refs.chanPoints) == 0 && len(refs.chanIDs) == 0 &&
+		len(refs.peers) == 0 {
+
+		return true
+	}
+
+	for _, chanPoint := range refs.chanPoints {
+		if _, ok := m.chanPoints[chanPoint]; ok {
+			return true
+		}
+	}
+
+	for _, chanID := range refs.chanIDs {
+		if _, ok := m.chanIDs[chanID]; ok && chanID != 0 {
+			return true
+		}
+	}
+
+	for _, peer := range refs.peers {
+		if _, ok := m.peers[peer]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// subscribeAllStream adapts a server stream of one of our subscriptions to
+// forward the events it sends into the combined event stream.
+type subscribeAllStream[T any] struct {
+	grpc.ServerStream
+
+	ctx  context.Context
+	send func(T) error
+}
+
+// Context returns the context of the combined event stream.
+func (s *subscribeAllStream[T]) Context() context.Context {
+	return s.ctx
+}
+
+// Send forwards an event into the combined event stream.
+func (s *subscribeAllStream[T]) Send(event T) error {
+	return s.send(event)
+}
+
+// SubscribeAll multiplexes the channel, peer, htlc, invoice, payment and
+// block events into a single stream. Only the events that match the filter of
+// the request are sent. The subscription ends once the context is canceled,
+// sending an event fails or one of the underlying subscriptions terminates.
+func (r *rpcServer) SubscribeAll(ctx context.Context,
+	req *routerrpc.SubscribeAllRequest,
+	send func(*routerrpc.SubscribeAllEvent) error) error {
+
+	matcher, err := newSubscribeAllMatcher(req, r.server.chanStateDB)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	events := make(chan *routerrpc.SubscribeAllEvent)
+	errChan := make(chan error, len(subscribeAllEventTypes))
+
+	emit := func(event *routerrpc.SubscribeAllEvent) error {
+		select {
+		case events <- event:
+			return nil
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	subscribe := func(t routerrpc.SubscribeAllEvent_EventType,
+		run func() error) {
+
+		if !matcher.wantsType(t) {
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := run()
+			if err != nil && !errors.Is(err, context.Canceled) {
+				errChan <- fmt.Errorf("%v subscription "+
+					"failed: %w", t, err)
+			}
+		}()
+	}
+
+	// The channel, peer and invoice events are marshalled by the existing
+	// subscriptions, whose streams forward them into the combined stream.
+	channelStream := &subscribeAllStream[*lnrpc.ChannelEventUpdate]{
+		ctx: ctx,
+		send: func(update *lnrpc.ChannelEventUpdate) error {
+			event := &routerrpc.SubscribeAllEvent_ChannelEvent{
+				ChannelEvent: update,
+			}
+
+			return emit(&routerrpc.SubscribeAllEvent{
+				Type:  routerrpc.SubscribeAllEvent_CHANNEL,
+				Event: event,
+			})
+		},
+	}
+	subscribe(routerrpc.SubscribeAllEvent_CHANNEL, func() error {
+		return r.SubscribeChannelEvents(
+			&lnrpc.ChannelEventSubscription{}, channelStream,
+		)
+	})
+
+	peerStream := &subscribeAllStream[*lnrpc.PeerEvent]{
+		ctx: ctx,
+		send: func(event *lnrpc.PeerEvent) error {
+			return emit(&routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_PEER,
+				Event: &routerrpc.SubscribeAllEvent_PeerEvent{
+					PeerEvent: event,
+				},
+			})
+		},
+	}
+	subscribe(routerrpc.SubscribeAllEvent_PEER, func() error {
+		return r.SubscribePeerEvents(
+			&lnrpc.PeerEventSubscription{}, peerStream,
+		)
+	})
+
+	invoiceStream := &subscribeAllStream[*lnrpc.Invoice]{
+		ctx: ctx,
+		send: func(invoice *lnrpc.Invoice) error {
+			return emit(&routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_INVOICE,
+				Event: &routerrpc.SubscribeAllEvent_Invoice{
+					Invoice: invoice,
+				},
+			})
+		},
+	}
+	subscribe(routerrpc.SubscribeAllEvent_INVOICE, func() error {
+		return r.SubscribeInvoices(
+			&lnrpc.InvoiceSubscription{}, invoiceStream,
+		)
+	})
+
+	subscribe(routerrpc.SubscribeAllEvent_HTLC, func() error {
+		return r.subscribeAllHtlcEvents(ctx, emit)
+	})
+
+	subscribe(routerrpc.SubscribeAllEvent_PAYMENT, func() error {
+		return r.subscribeAllPayments(ctx, emit)
+	})
+
+	subscribe(routerrpc.SubscribeAllEvent_BLOCK, func() error {
+		return r.subscribeAllBlocks(ctx, emit)
+	})
+
+	for {
+		select {
+		case event := <-events:
+			if !matcher.matches(event) {
+				continue
+			}
+
+			if err := send(event); err != nil {
+				return err
+			}
+
+		case err := <-errChan:
+			return err
+
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+
+			return ctx.Err()
+
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// subscribeAllHtlcEvents forwards the htlc events into the combined event
+// stream.
+func (r *rpcServer) subscribeAllHtlcEvents(ctx context.Context,
+	emit func(*routerrpc.SubscribeAllEvent) error) error {
+
+	htlcClient, err := r.routerBackend.SubscribeHtlcEvents()
+	if err != nil {
+		return err
+	}
+	defer htlcClient.Cancel()
+
+	for {
+		select {
+		case event := <-htlcClient.Updates():
+			rpcEvent, err := routerrpc.MarshallHtlcEvent(event)
+			if err != nil {
+				return err
+			}
+
+			err = emit(&routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_HTLC,
+				Event: &routerrpc.SubscribeAllEvent_HtlcEvent{
+					HtlcEvent: rpcEvent,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-htlcClient.Quit():
+			return errors.New("htlc event subscription terminated")
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// subscribeAllPayments forwards the payment updates into the combined event
+// stream.
+func (r *rpcServer) subscribeAllPayments(ctx context.Context,
+	emit func(*routerrpc.SubscribeAllEvent) error) error {
+
+	subscription, err := r.routerBackend.Tower.SubscribeAllPayments()
+	if err != nil {
+		return err
+	}
+	defer subscription.Close()
+
+	for {
+		select {
+		case item, ok := <-subscription.Updates():
+			if !ok {
+				return errors.New("payment subscription " +
+					"terminated")
+			}
+
+			payment, ok := item.(*channeldb.MPPayment)
+			if !ok {
+				return fmt.Errorf("unexpected payment update: "+
+					"%T", item)
+			}
+
+			rpcPayment, err := r.routerBackend.MarshallPayment(
+				payment,
+			)
+			if err != nil {
+				return err
+			}
+
+			err = emit(&routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_PAYMENT,
+				Event: &routerrpc.SubscribeAllEvent_Payment{
+					Payment: rpcPayment,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// subscribeAllBlocks forwards the new blocks into the combined event stream.
+func (r *rpcServer) subscribeAllBlocks(ctx context.Context,
+	emit func(*routerrpc.SubscribeAllEvent) error) error {
+
+	blockEpoch, err := r.server.cc.ChainNotifier.RegisterBlockEpochNtfn(
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	defer blockEpoch.Cancel()
+
+	for {
+		select {
+		case epoch, ok := <-blockEpoch.Epochs:
+			if !ok {
+				return errors.New("block subscription " +
+					"terminated")
+			}
+
+			block := &chainrpc.BlockEpoch{
+				Hash:   epoch.Hash[:],
+				Height: uint32(epoch.Height),
+			}
+			err := emit(&routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_BLOCK,
+				Event: &routerrpc.SubscribeAllEvent_Block{
+					Block: block,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package lnd
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/chainrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// activeChannel returns an active channel event for the channel point.
+func activeChannel(
+	chanPoint *lnrpc.ChannelPoint) *routerrpc.SubscribeAllEvent {
+
+	update := &lnrpc.ChannelEventUpdate{
+		Channel: &lnrpc.ChannelEventUpdate_ActiveChannel{
+			ActiveChannel: chanPoint,
+		},
+	}
+
+	return &routerrpc.SubscribeAllEvent{
+		Type: routerrpc.SubscribeAllEvent_CHANNEL,
+		Event: &routerrpc.SubscribeAllEvent_ChannelEvent{
+			ChannelEvent: update,
+		},
+	}
+}
+
+// TestSubscribeAllMatcher tests the filtering of the combined event stream.
+func TestSubscribeAllMatcher(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chanPoint = wire.OutPoint{Index: 1}
+		chanID    = uint64(1234)
+		peer      = route.Vertex{2}
+		otherPeer = route.Vertex{3}
+	)
+
+	rpcChanPoint := &lnrpc.ChannelPoint{
+		FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+			FundingTxidBytes: chanPoint.Hash[:],
+		},
+		OutputIndex: chanPoint.Index,
+	}
+
+	m, err := newSubscribeAllMatcher(&routerrpc.SubscribeAllRequest{
+		Types: []routerrpc.SubscribeAllEvent_EventType{
+			routerrpc.SubscribeAllEvent_CHANNEL,
+			routerrpc.SubscribeAllEvent_PEER,
+			routerrpc.SubscribeAllEvent_HTLC,
+			routerrpc.SubscribeAllEvent_BLOCK,
+		},
+		Peers: [][]byte{peer[:]},
+	}, nil)
+	require.NoError(t, err)
+
+	// The short channel ID is usually looked up in the database.
+	m.chanPoints[chanPoint.String()] = struct{}{}
+	m.chanIDs[chanID] = struct{}{}
+
+	testCases := []struct {
+		name    string
+		event   *routerrpc.SubscribeAllEvent
+		matches bool
+	}{
+		{
+			name:    "active channel",
+			event:   activeChannel(rpcChanPoint),
+			matches: true,
+		},
+		{
+			name: "other channel",
+			event: activeChannel(&lnrpc.ChannelPoint{
+				FundingTxid: rpcChanPoint.FundingTxid,
+				OutputIndex: 2,
+			}),
+		},
+		{
+			name: "filtered peer",
+			event: &routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_PEER,
+				Event: &routerrpc.SubscribeAllEvent_PeerEvent{
+					PeerEvent: &lnrpc.PeerEvent{
+						PubKey: peer.String(),
+					},
+				},
+			},
+			matches: true,
+		},
+		{
+			name: "other peer",
+			event: &routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_PEER,
+				Event: &routerrpc.SubscribeAllEvent_PeerEvent{
+					PeerEvent: &lnrpc.PeerEvent{
+						PubKey: otherPeer.String(),
+					},
+				},
+			},
+		},
+		{
+			name: "htlc on filtered channel",
+			event: &routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_HTLC,
+				Event: &routerrpc.SubscribeAllEvent_HtlcEvent{
+					HtlcEvent: &routerrpc.HtlcEvent{
+						IncomingChannelId: 1,
+						OutgoingChannelId: chanID,
+					},
+				},
+			},
+			matches: true,
+		},
+		{
+			name: "htlc on other channel",
+			event: &routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_HTLC,
+				Event: &routerrpc.SubscribeAllEvent_HtlcEvent{
+					HtlcEvent: &routerrpc.HtlcEvent{
+						IncomingChannelId: 1,
+					},
+				},
+			},
+		},
+		{
+			name: "block",
+			event: &routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_BLOCK,
+				Event: &routerrpc.SubscribeAllEvent_Block{
+					Block: &chainrpc.BlockEpoch{
+						Height: 100,
+					},
+				},
+			},
+			matches: true,
+		},
+		{
+			name: "unsubscribed type",
+			event: &routerrpc.SubscribeAllEvent{
+				Type: routerrpc.SubscribeAllEvent_INVOICE,
+				Event: &routerrpc.SubscribeAllEvent_Invoice{
+					Invoice: &lnrpc.Invoice{},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.matches, m.matches(tc.event), tc.name)
+	}
+
+	_, err = newSubscribeAllMatcher(&routerrpc.SubscribeAllRequest{
+		Types: []routerrpc.SubscribeAllEvent_EventType{100},
+	}, nil)
+	require.Error(t, err)
+
+	_, err = newSubscribeAllMatcher(&routerrpc.SubscribeAllRequest{
+		Peers: [][]byte{{1, 2, 3}},
+	}, nil)
+	require.Error(t, err)
+}
@@ -210,7 +210,7 @@ func (b *BudgetAggregator) filterInputs(inputs InputsMap) InputsMap {
 		op := pi.OutPoint()
 
 		// Get the size of the witness and skip if there's an error.
-		witnessSize, _, err := pi.WitnessType().SizeUpperBound()
+		witnessSize, _, err := input.WitnessSizeUpperBound(pi.Input)
 		if err != nil {
 			log.Warnf("Skipped input=%v: cannot get its size: %v",
 				op, err)
@@ -0,0 +1,197 @@
+package sweep
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+// UrgencyClass categorizes the inputs of the sweeper by how close their
+// deadline is. It's used to configure how long the inputs of each class are
+// held back so they can be batched with other inputs.
+type UrgencyClass uint8
+
+const (
+	// UrgencyClassUrgent is the class of inputs whose deadline is less
+	// than DefaultBudgetEscalationDelta blocks away.
+	UrgencyClassUrgent UrgencyClass = iota
+
+	// UrgencyClassDeadline is the class of inputs whose deadline is
+	// further away.
+	UrgencyClassDeadline
+
+	// UrgencyClassNoDeadline is the class of inputs that were offered
+	// without a deadline.
+	UrgencyClassNoDeadline
+)
+
+// String returns a human readable name of the urgency class.
+func (u UrgencyClass) String() string {
+	switch u {
+	case UrgencyClassUrgent:
+		return "urgent"
+
+	case UrgencyClassDeadline:
+		return "deadline"
+
+	case UrgencyClassNoDeadline:
+		return "nodeadline"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(u))
+	}
+}
+
+// BatchWindows defines the number of blocks the inputs of each urgency class
+// are held back once they can be swept, so they can be batched with inputs
+// that are offered later. A window of zero sweeps the inputs right away.
+type BatchWindows struct {
+	// Urgent is the batch window of the urgent inputs.
+	Urgent uint32
+
+	// Deadline is the batch window of the inputs whose deadline isn't
+	// close yet.
+	Deadline uint32
+
+	// NoDeadline is the batch window of the inputs without a deadline.
+	NoDeadline uint32
+}
+
+// Window returns the batch window of the given urgency class.
+func (b BatchWindows) Window(class UrgencyClass) uint32 {
+	switch class {
+	case UrgencyClassUrgent:
+		return b.Urgent
+
+	case UrgencyClassDeadline:
+		return b.Deadline
+
+	default:
+		return b.NoDeadline
+	}
+}
+
+// sweepNowReq is an internal message we'll use to represent an external
+// caller's intent to sweep the given inputs right away.
+type sweepNowReq struct {
+	inputs  []wire.OutPoint
+	errChan chan error
+}
+
+// urgencyClass returns the urgency class of the given input at the given
+// height.
+func urgencyClass(pi *SweeperInput, height int32) UrgencyClass {
+	if pi.params.DeadlineHeight.IsNone() {
+		return UrgencyClassNoDeadline
+	}
+
+	if pi.DeadlineHeight-height < DefaultBudgetEscalationDelta {
+		return UrgencyClassUrgent
+	}
+
+	return UrgencyClassDeadline
+}
+
+// batchWindowOver returns true if the batch window of the given input is
+// over. The window starts at the height at which the input could first be
+// swept, and its length depends on the current urgency of the input. Inputs
+// that are asked to be swept immediately have no batch window.
+func (s *UtxoSweeper) batchWindowOver(pi *SweeperInput) bool {
+	if pi.params.Immediate {
+		return true
+	}
+
+	start := pi.batchStart.UnwrapOr(s.currentHeight)
+	pi.batchStart = fn.Some(start)
+
+	class := urgencyClass(pi, s.currentHeight)
+	end := start + int32(s.cfg.BatchWindows.Window(class))
+	if s.currentHeight >= end {
+		return true
+	}
+
+	log.Debugf("Holding back input %v(class=%v) in batch window until "+
+		"height %v", pi.OutPoint(), class, end)
+
+	return false
+}
+
+// SweepNow sweeps the given inputs right away, bypassing their batch windows
+// and without waiting for the next block. The inputs are swept in txns of
+// their own, and inputs that were already published are swept again. The
+// inputs are marked as immediate, so they won't be held back in the future.
+// This allows operators to respond to pinning attacks on the sweeping txns.
+func (s *UtxoSweeper) SweepNow(inputs []wire.OutPoint) error {
+	errChan := make(chan error, 1)
+	select {
+	case s.sweepNowReqs <- &sweepNowReq{
+		inputs:  inputs,
+		errChan: errChan,
+	}:
+	case <-s.quit:
+		return ErrSweeperShuttingDown
+	}
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-s.quit:
+		return ErrSweeperShuttingDown
+	}
+}
+
+// handleSweepNowReq sweeps the inputs of the given request right away. The
+// inputs that can be swept are swept even if some of the others can't, in
+// which case an error is returned for the latter.
+func (s *UtxoSweeper) handleSweepNowReq(req *sweepNowReq) error {
+	for _, op := range req.inputs {
+		pi, ok := s.inputs[op]
+		if !ok {
+			return fmt.Errorf("%w: %v", lnwallet.ErrNotMine, op)
+		}
+
+		if pi.terminated() || pi.state == PendingPublish {
+			return fmt.Errorf("input %v cannot be swept in state "+
+				"%v", op, pi.state)
+		}
+	}
+
+	for _, op := range req.inputs {
+		pi := s.inputs[op]
+		pi.params.Immediate = true
+
+		// Reset the state of the published inputs so they are swept
+		// again in a new tx.
+		pi.state = Init
+	}
+
+	// Only sweep the requested inputs that can be swept at the current
+	// height.
+	sweepable := s.updateSweeperInputs()
+
+	var (
+		inputs   = make(InputsMap, len(req.inputs))
+		immature []wire.OutPoint
+	)
+	for _, op := range req.inputs {
+		pi, ok := sweepable[op]
+		if !ok {
+			immature = append(immature, op)
+			continue
+		}
+
+		inputs[op] = pi
+	}
+
+	log.Infof("Sweeping %d inputs now", len(inputs))
+
+	s.sweepPendingInputs(inputs)
+
+	if len(immature) > 0 {
+		return fmt.Errorf("%w: %v", ErrLocktimeImmature, immature)
+	}
+
+	return nil
+}
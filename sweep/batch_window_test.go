@@ -0,0 +1,179 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUrgencyClass checks that the inputs are classified by the blocks left
+// until their deadline.
+func TestUrgencyClass(t *testing.T) {
+	t.Parallel()
+
+	const height = int32(100)
+
+	withDeadline := func(deadline int32) *SweeperInput {
+		return &SweeperInput{
+			params: Params{
+				DeadlineHeight: fn.Some(deadline),
+			},
+			DeadlineHeight: deadline,
+		}
+	}
+
+	require.Equal(t, UrgencyClassNoDeadline,
+		urgencyClass(&SweeperInput{DeadlineHeight: height}, height))
+
+	require.Equal(t, UrgencyClassDeadline, urgencyClass(
+		withDeadline(height+DefaultBudgetEscalationDelta), height,
+	))
+
+	require.Equal(t, UrgencyClassUrgent, urgencyClass(
+		withDeadline(height+DefaultBudgetEscalationDelta-1), height,
+	))
+
+	windows := BatchWindows{Urgent: 1, Deadline: 2, NoDeadline: 3}
+	require.EqualValues(t, 1, windows.Window(UrgencyClassUrgent))
+	require.EqualValues(t, 2, windows.Window(UrgencyClassDeadline))
+	require.EqualValues(t, 3, windows.Window(UrgencyClassNoDeadline))
+}
+
+// TestBatchWindowOver checks that the inputs are held back until the batch
+// window of their urgency class is over.
+func TestBatchWindowOver(t *testing.T) {
+	t.Parallel()
+
+	s := New(&UtxoSweeperConfig{
+		BatchWindows: BatchWindows{
+			Urgent:     0,
+			Deadline:   3,
+			NoDeadline: 6,
+		},
+	})
+	s.currentHeight = 100
+
+	inp := &input.MockInput{}
+	inp.On("OutPoint").Return(wire.OutPoint{}).Maybe()
+
+	// An input without a deadline is held back for 6 blocks from the
+	// height it could first be swept.
+	noDeadline := &SweeperInput{Input: inp}
+	require.False(t, s.batchWindowOver(noDeadline))
+	require.Equal(t, fn.Some(int32(100)), noDeadline.batchStart)
+
+	// An input whose deadline is far away is held back for 3 blocks.
+	deadline := &SweeperInput{
+		Input: inp,
+		params: Params{
+			DeadlineHeight: fn.Some(int32(115)),
+		},
+		DeadlineHeight: 115,
+	}
+	require.False(t, s.batchWindowOver(deadline))
+
+	// An immediate input is never held back.
+	immediate := &SweeperInput{
+		Input:  inp,
+		params: Params{Immediate: true},
+	}
+	require.True(t, s.batchWindowOver(immediate))
+
+	// Once the input with the deadline becomes urgent, its window is
+	// over, while the input without a deadline is still held back.
+	s.currentHeight = 104
+	require.True(t, s.batchWindowOver(deadline))
+	require.False(t, s.batchWindowOver(noDeadline))
+
+	// The window of the input without a deadline ends 6 blocks after
+	// it started.
+	s.currentHeight = 106
+	require.True(t, s.batchWindowOver(noDeadline))
+}
+
+// TestHandleSweepNowReq checks that the requested inputs are swept right away
+// on their own, and that unknown and terminated inputs are rejected.
+func TestHandleSweepNowReq(t *testing.T) {
+	t.Parallel()
+
+	aggregator := &mockUtxoAggregator{}
+	defer aggregator.AssertExpectations(t)
+
+	s := New(&UtxoSweeperConfig{
+		Aggregator: aggregator,
+		BatchWindows: BatchWindows{
+			NoDeadline: 6,
+		},
+	})
+	s.currentHeight = 100
+
+	mature := &input.MockInput{}
+	defer mature.AssertExpectations(t)
+	mature.On("RequiredLockTime").Return(uint32(0), false)
+	mature.On("BlocksToMaturity").Return(uint32(0))
+	mature.On("HeightHint").Return(uint32(0))
+	mature.On("OutPoint").Return(wire.OutPoint{}).Maybe()
+
+	immature := &input.MockInput{}
+	defer immature.AssertExpectations(t)
+	immature.On("RequiredLockTime").Return(uint32(200), true)
+
+	var (
+		publishedOp = wire.OutPoint{Index: 1}
+		waitingOp   = wire.OutPoint{Index: 2}
+		immatureOp  = wire.OutPoint{Index: 3}
+		sweptOp     = wire.OutPoint{Index: 4}
+	)
+
+	published := &SweeperInput{state: Published, Input: mature}
+	waiting := &SweeperInput{state: Init, Input: mature}
+	immatureInput := &SweeperInput{state: Init, Input: immature}
+	s.inputs = InputsMap{
+		publishedOp: published,
+		waitingOp:   waiting,
+		immatureOp:  immatureInput,
+		sweptOp:     {state: Swept, Input: mature},
+	}
+
+	// Unknown inputs are rejected.
+	err := s.handleSweepNowReq(&sweepNowReq{
+		inputs: []wire.OutPoint{{Index: 5}},
+	})
+	require.ErrorIs(t, err, lnwallet.ErrNotMine)
+
+	// Terminated inputs are rejected.
+	err = s.handleSweepNowReq(&sweepNowReq{
+		inputs: []wire.OutPoint{publishedOp, sweptOp},
+	})
+	require.Error(t, err)
+	require.Equal(t, Published, published.state)
+
+	// Sweeping the published input sweeps it on its own right away,
+	// while the other input is held back in its batch window.
+	aggregator.On("ClusterInputs", InputsMap{
+		publishedOp: published,
+	}).Return([]InputSet{}).Once()
+
+	err = s.handleSweepNowReq(&sweepNowReq{
+		inputs: []wire.OutPoint{publishedOp},
+	})
+	require.NoError(t, err)
+	require.Equal(t, Init, published.state)
+	require.True(t, published.params.Immediate)
+
+	// The immature input can't be swept yet, which is reported while the
+	// mature input is still swept.
+	aggregator.On("ClusterInputs", InputsMap{
+		waitingOp: waiting,
+	}).Return([]InputSet{}).Once()
+
+	err = s.handleSweepNowReq(&sweepNowReq{
+		inputs: []wire.OutPoint{waitingOp, immatureOp},
+	})
+	require.ErrorIs(t, err, ErrLocktimeImmature)
+	require.True(t, immatureInput.params.Immediate)
+}
@@ -3,6 +3,7 @@ package sweep
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -42,6 +43,14 @@ var (
 	// ErrThirdPartySpent is returned when a third party has spent the
 	// input in the sweeping tx.
 	ErrThirdPartySpent = errors.New("third party spent the output")
+
+	// errPackageRelayUnsupported is returned when a tx can't be broadcast
+	// as a package because the chain backend doesn't support it.
+	errPackageRelayUnsupported = errors.New("package relay unsupported")
+
+	// errNoMempoolInspector is returned when the fees of the mempool txns
+	// can't be queried because the chain backend doesn't support it.
+	errNoMempoolInspector = errors.New("mempool inspector unavailable")
 )
 
 var (
@@ -66,6 +75,35 @@ type Bumper interface {
 	// returns a chan that the caller can use to receive updates about the
 	// broadcast result and potential RBF attempts.
 	Broadcast(req *BumpRequest) (<-chan *BumpResult, error)
+
+	// PendingBumps returns the state of the txns that are currently
+	// monitored for fee bumping.
+	PendingBumps() ([]*BumpState, error)
+}
+
+// BumpState describes a tx that is monitored for fee bumping, including a
+// preview of its next RBF step.
+type BumpState struct {
+	// Txid is the txid of the latest tx that was published.
+	Txid chainhash.Hash
+
+	// Inputs are the outpoints of the inputs swept by the tx.
+	Inputs []wire.OutPoint
+
+	// Fee is the fee paid by the tx.
+	Fee btcutil.Amount
+
+	// Budget is the max fee the tx is allowed to pay.
+	Budget btcutil.Amount
+
+	// DeadlineHeight is the height by which the tx should be confirmed.
+	DeadlineHeight int32
+
+	// FeeFunction is the state of the fee function used to bump the tx.
+	FeeFunction FeeFunctionState
+
+	// NextFee is the fee the tx would pay after its next RBF step.
+	NextFee btcutil.Amount
 }
 
 // BumpEvent represents the event of a fee bumping attempt.
@@ -295,6 +333,30 @@ type TxPublisherConfig struct {
 	// AuxSweeper is an optional interface that can be used to modify the
 	// way sweep transaction are generated.
 	AuxSweeper fn.Option[AuxSweeper]
+
+	// PackageSubmitter is an optional interface that is used to broadcast
+	// sweep txns together with their unconfirmed parents as a package,
+	// if the chain backend supports package relay.
+	PackageSubmitter fn.Option[lnwallet.PackageSubmitter]
+
+	// SpendArbiter is an optional arbiter that's used to claim the inputs
+	// of the sweeping txns, so they are not double spent by txns of other
+	// subsystems.
+	SpendArbiter fn.Option[*SpendArbiter]
+
+	// Ledger is an optional ledger that's used to persist the history of
+	// all sweeps, including their RBF attempts and the fees paid.
+	Ledger fn.Option[SweepLedger]
+
+	// Mempool is an optional mempool watcher that's used to find the txns
+	// that conflict with the sweeping txns.
+	Mempool chainntnfs.MempoolWatcher
+
+	// MempoolInspector is an optional interface that's used to query the
+	// fees of the conflicting txns and the unconfirmed parents in the
+	// mempool, so the fee rate of the sweeping txns is raised to the level
+	// needed to replace the former and to lift the latter.
+	MempoolInspector fn.Option[chainntnfs.MempoolInspector]
 }
 
 // TxPublisher is an implementation of the Bumper interface. It utilizes the
@@ -331,6 +393,15 @@ type TxPublisher struct {
 	// the chan that the publisher sends the fee bump result to.
 	subscriberChans lnutils.SyncMap[uint64, chan *BumpResult]
 
+	// pendingBumpsReqs is a channel used to query the state of the
+	// monitored txns from the monitor loop, which makes sure the fee
+	// functions aren't bumped while being read.
+	pendingBumpsReqs chan chan []*BumpState
+
+	// ledgerSweeps is a map keyed by the requestCounter, each item is the
+	// ledger entry of the monitored tx.
+	ledgerSweeps lnutils.SyncMap[uint64, *LedgerSweep]
+
 	// quit is used to signal the publisher to stop.
 	quit chan struct{}
 }
@@ -341,10 +412,12 @@ var _ Bumper = (*TxPublisher)(nil)
 // NewTxPublisher creates a new TxPublisher.
 func NewTxPublisher(cfg TxPublisherConfig) *TxPublisher {
 	return &TxPublisher{
-		cfg:             &cfg,
-		records:         lnutils.SyncMap[uint64, *monitorRecord]{},
-		subscriberChans: lnutils.SyncMap[uint64, chan *BumpResult]{},
-		quit:            make(chan struct{}),
+		cfg:              &cfg,
+		records:          lnutils.SyncMap[uint64, *monitorRecord]{},
+		subscriberChans:  lnutils.SyncMap[uint64, chan *BumpResult]{},
+		ledgerSweeps:     lnutils.SyncMap[uint64, *LedgerSweep]{},
+		pendingBumpsReqs: make(chan chan []*BumpState),
+		quit:             make(chan struct{}),
 	}
 }
 
@@ -545,6 +618,18 @@ func (t *TxPublisher) createAndCheckTx(req *BumpRequest,
 		return sweepCtx, fmt.Errorf("create sweep tx: %w", err)
 	}
 
+	// If the mempool shows the fee rate is too low to replace the
+	// conflicting txns or to lift the unconfirmed parents, we raise it
+	// and recreate the tx.
+	if t.raiseToMempoolFloor(sweepCtx.tx, f) {
+		sweepCtx, err = t.createSweepTx(
+			req.Inputs, req.DeliveryAddress, f.FeeRate(),
+		)
+		if err != nil {
+			return sweepCtx, fmt.Errorf("create sweep tx: %w", err)
+		}
+	}
+
 	// Sanity check the budget still covers the fee.
 	if sweepCtx.fee > req.Budget {
 		return sweepCtx, fmt.Errorf("%w: budget=%v, fee=%v",
@@ -563,6 +648,20 @@ func (t *TxPublisher) createAndCheckTx(req *BumpRequest,
 		return sweepCtx, nil
 	}
 
+	// If the tx spends from a parent that isn't in the mempool, the check
+	// is expected to fail. We skip it if the parent can be broadcast
+	// together with the tx as a package, as the mempool acceptance test
+	// doesn't evaluate the fees of a package as a whole.
+	if errors.Is(err, chain.ErrMissingInputs) &&
+		t.canSubmitPackage(req.Inputs) {
+
+		log.Debugf("Skipped mempool check of tx=%v, it will be "+
+			"broadcast as a package with its parents",
+			sweepCtx.tx.TxHash())
+
+		return sweepCtx, nil
+	}
+
 	// Print an error log if the chain backend doesn't support the mempool
 	// acceptance test RPC.
 	if errors.Is(err, rpcclient.ErrBackendVersion) {
@@ -582,6 +681,51 @@ func (t *TxPublisher) createAndCheckTx(req *BumpRequest,
 		sweepCtx.tx.TxHash(), err)
 }
 
+// raiseToMempoolFloor increments the fee function until its fee rate reaches
+// the floor imposed by the mempool on the given tx, or until it reaches its
+// max fee rate. It returns true if the fee rate was raised.
+func (t *TxPublisher) raiseToMempoolFloor(tx *wire.MsgTx,
+	f FeeFunction) bool {
+
+	inspector, err := t.cfg.MempoolInspector.UnwrapOrErr(
+		errNoMempoolInspector,
+	)
+	if err != nil {
+		return false
+	}
+
+	target := f.FeeRate()
+	floor, err := mempoolFeeRateFloor(inspector, t.cfg.Mempool, tx, target)
+	if err != nil {
+		log.Warnf("Unable to calculate mempool fee rate floor of "+
+			"tx=%v: %v", tx.TxHash(), err)
+
+		return false
+	}
+
+	if floor <= target {
+		return false
+	}
+
+	log.Debugf("Raising fee rate of tx=%v from %v to mempool floor %v",
+		tx.TxHash(), target, floor)
+
+	raised := false
+	for f.FeeRate() < floor {
+		increased, err := f.Increment()
+		if err != nil {
+			log.Debugf("Fee function maxed out below mempool "+
+				"floor %v: %v", floor, err)
+
+			break
+		}
+
+		raised = raised || increased
+	}
+
+	return raised
+}
+
 // broadcast takes a monitored tx and publishes it to the network. Prior to the
 // broadcast, it will subscribe the tx's confirmation notification and attach
 // the event channel to the record. Any broadcast-related errors will not be
@@ -616,9 +760,7 @@ func (t *TxPublisher) broadcast(requestID uint64) (*BumpResult, error) {
 	// Publish the sweeping tx with customized label. If the publish fails,
 	// this error will be saved in the `BumpResult` and it will be removed
 	// from being monitored.
-	err = t.cfg.Wallet.PublishTransaction(
-		tx, labels.MakeLabel(labels.LabelTypeSweepTransaction, nil),
-	)
+	err = t.publish(tx, record.req.Inputs)
 	if err != nil {
 		// NOTE: we decide to attach this error to the result instead
 		// of returning it here because by the time the tx reaches
@@ -644,6 +786,143 @@ func (t *TxPublisher) broadcast(requestID uint64) (*BumpResult, error) {
 	return result, nil
 }
 
+// unconfParentTxs returns the fully signed unconfirmed parents of the given
+// inputs that are known, without duplicates.
+func unconfParentTxs(inputs []input.Input) []*wire.MsgTx {
+	var (
+		parents []*wire.MsgTx
+		seen    = make(map[chainhash.Hash]struct{})
+	)
+	for _, inp := range inputs {
+		parent := inp.UnconfParent()
+		if parent == nil || parent.Tx == nil {
+			continue
+		}
+
+		txid := parent.Tx.TxHash()
+		if _, ok := seen[txid]; ok {
+			continue
+		}
+		seen[txid] = struct{}{}
+
+		parents = append(parents, parent.Tx)
+	}
+
+	return parents
+}
+
+// canSubmitPackage returns true if a tx spending the given inputs can be
+// broadcast together with their unconfirmed parents as a package.
+func (t *TxPublisher) canSubmitPackage(inputs []input.Input) bool {
+	return t.cfg.PackageSubmitter.IsSome() &&
+		len(unconfParentTxs(inputs)) > 0
+}
+
+// publish broadcasts the given sweeping tx. If it spends from unconfirmed
+// parents that are known and the backend supports package relay, the tx is
+// submitted as a package together with its parents, so that parents paying
+// too little fees, such as commitments, can still enter the mempool.
+// Otherwise, or if the package is rejected, we fall back to publishing the
+// parents first and then the tx, which only works if the parents pay enough
+// fees on their own. The inputs of the tx are claimed with the spend arbiter,
+// if used, before the tx is published.
+func (t *TxPublisher) publish(tx *wire.MsgTx, inputs []input.Input) error {
+	label := labels.MakeLabel(labels.LabelTypeSweepTransaction, nil)
+
+	// Claim the inputs of the tx before it's published, so it doesn't
+	// conflict with txns published by other subsystems.
+	err := fn.MapOptionZ(t.cfg.SpendArbiter, func(a *SpendArbiter) error {
+		return a.Claim(SpendSourceSweeper, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = t.publishWithParents(tx, inputs, label)
+	if err != nil {
+		t.releaseSpend(tx)
+	}
+
+	return err
+}
+
+// publishWithParents publishes the given sweeping tx, together with its
+// unconfirmed parents if there are any.
+func (t *TxPublisher) publishWithParents(tx *wire.MsgTx,
+	inputs []input.Input, label string) error {
+
+	parents := unconfParentTxs(inputs)
+	if len(parents) == 0 {
+		return t.cfg.Wallet.PublishTransaction(tx, label)
+	}
+
+	submitter, err := t.cfg.PackageSubmitter.UnwrapOrErr(
+		errPackageRelayUnsupported,
+	)
+	if err == nil {
+		err = submitter.SubmitPackage(parents, tx)
+	}
+
+	switch {
+	// The package was accepted. We still publish the tx to the wallet,
+	// which is a no-op for the backend as the tx is already in its
+	// mempool, so that the wallet keeps track of it.
+	case err == nil:
+		log.Debugf("Submitted tx %v as a package with %d parents",
+			tx.TxHash(), len(parents))
+
+	// Without package relay, the parents are published on their own.
+	case errors.Is(err, errPackageRelayUnsupported):
+		t.publishParents(parents)
+
+	default:
+		log.Warnf("Unable to submit tx %v as a package, falling back "+
+			"to publishing its parents first: %v", tx.TxHash(), err)
+
+		t.publishParents(parents)
+	}
+
+	return t.cfg.Wallet.PublishTransaction(tx, label)
+}
+
+// releaseSpend releases the claims on the inputs of the given tx, if a spend
+// arbiter is used.
+func (t *TxPublisher) releaseSpend(tx *wire.MsgTx) {
+	if tx == nil {
+		return
+	}
+
+	t.cfg.SpendArbiter.WhenSome(func(a *SpendArbiter) {
+		a.Release(tx.TxHash())
+	})
+}
+
+// publishParents publishes the unconfirmed parents of a sweeping tx on their
+// own. Parents that the wallet already knows are skipped, as the wallet
+// rebroadcasts them itself and publishing them again would overwrite their
+// labels. Errors are only logged, as the parents may already be in the
+// mempool or confirmed, and publishing the sweeping tx reports any actual
+// problem.
+func (t *TxPublisher) publishParents(parents []*wire.MsgTx) {
+	for _, parent := range parents {
+		txid := parent.TxHash()
+
+		known, err := t.cfg.Wallet.FetchTx(txid)
+		if err == nil && known != nil {
+			log.Debugf("Skipping parent tx %v known to the wallet",
+				txid)
+
+			continue
+		}
+
+		err = t.cfg.Wallet.PublishTransaction(parent, "")
+		if err != nil {
+			log.Warnf("Unable to publish parent tx %v: %v", txid,
+				err)
+		}
+	}
+}
+
 // notifyResult sends the result to the resultChan specified by the requestID.
 // This channel is expected to be read by the caller.
 func (t *TxPublisher) notifyResult(result *BumpResult) {
@@ -704,6 +983,24 @@ func (t *TxPublisher) removeResult(result *BumpResult) {
 // subscriber and remove the record if the tx is confirmed or failed to be
 // broadcast.
 func (t *TxPublisher) handleResult(result *BumpResult) {
+	// Release the claims of the txns that no longer spend the inputs.
+	switch result.Event {
+	case TxReplaced:
+		t.releaseSpend(result.ReplacedTx)
+
+	case TxFailed, TxConfirmed:
+		t.releaseSpend(result.Tx)
+		t.releaseSpend(result.ReplacedTx)
+	}
+
+	// Record the result in the ledger.
+	t.cfg.Ledger.WhenSome(func(ledger SweepLedger) {
+		if err := t.recordLedger(ledger, result); err != nil {
+			log.Errorf("Unable to record sweep tx %v in ledger: %v",
+				result.Tx.TxHash(), err)
+		}
+	})
+
 	// Notify the subscriber.
 	t.notifyResult(result)
 
@@ -711,6 +1008,70 @@ func (t *TxPublisher) handleResult(result *BumpResult) {
 	t.removeResult(result)
 }
 
+// recordLedger records the given result in the ledger. A new sweep is added to
+// the ledger on its initial broadcast, and each replacement is recorded as a
+// new attempt until the sweep is confirmed or has failed.
+func (t *TxPublisher) recordLedger(ledger SweepLedger,
+	result *BumpResult) error {
+
+	id := result.requestID
+	height := uint32(t.currentHeight.Load())
+
+	l, ok := t.ledgerSweeps.Load(id)
+	if !ok {
+		// This is the initial broadcast, which creates a new sweep.
+		l = &LedgerSweep{
+			Outcome: SweepOutcomePending,
+		}
+		for _, txIn := range result.Tx.TxIn {
+			l.Inputs = append(l.Inputs, txIn.PreviousOutPoint)
+		}
+		l.Attempts = append(l.Attempts, SweepAttempt{
+			Txid:    result.Tx.TxHash(),
+			FeeRate: result.FeeRate,
+			Fee:     result.Fee,
+			Height:  height,
+		})
+
+		if err := ledger.AddSweep(l); err != nil {
+			return err
+		}
+
+		t.ledgerSweeps.Store(id, l)
+	}
+
+	switch result.Event {
+	case TxReplaced:
+		l.Attempts = append(l.Attempts, SweepAttempt{
+			Txid:    result.Tx.TxHash(),
+			FeeRate: result.FeeRate,
+			Fee:     result.Fee,
+			Height:  height,
+		})
+
+	// The confirmed tx is always the last attempt.
+	case TxConfirmed:
+		l.Outcome = SweepOutcomeConfirmed
+
+	case TxFailed:
+		l.Outcome = SweepOutcomeFailed
+		if result.Err != nil {
+			l.FailureReason = result.Err.Error()
+		}
+
+	// The initial broadcast has already been recorded.
+	default:
+		return nil
+	}
+
+	// The sweep is no longer monitored once it's confirmed or failed.
+	if l.Outcome != SweepOutcomePending {
+		t.ledgerSweeps.Delete(id)
+	}
+
+	return ledger.UpdateSweep(l)
+}
+
 // monitorRecord is used to keep track of the tx being monitored by the
 // publisher internally.
 type monitorRecord struct {
@@ -797,6 +1158,9 @@ func (t *TxPublisher) monitor(blockEvent *chainntnfs.BlockEpochEvent) {
 			// to be bumped.
 			t.processRecords()
 
+		case respChan := <-t.pendingBumpsReqs:
+			respChan <- t.bumpStates()
+
 		case <-t.quit:
 			log.Debug("Fee bumper stopped, exit monitor")
 			return
@@ -804,6 +1168,80 @@ func (t *TxPublisher) monitor(blockEvent *chainntnfs.BlockEpochEvent) {
 	}
 }
 
+// PendingBumps returns the state of the txns that are currently monitored for
+// fee bumping.
+//
+// NOTE: part of the Bumper interface.
+func (t *TxPublisher) PendingBumps() ([]*BumpState, error) {
+	respChan := make(chan []*BumpState, 1)
+
+	select {
+	case t.pendingBumpsReqs <- respChan:
+	case <-t.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+
+	select {
+	case states := <-respChan:
+		return states, nil
+	case <-t.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+}
+
+// bumpStates returns the state of all the monitored txns, ordered by the time
+// they were first published.
+func (t *TxPublisher) bumpStates() []*BumpState {
+	var (
+		ids    []uint64
+		states = make(map[uint64]*BumpState)
+	)
+
+	t.records.ForEach(func(id uint64, r *monitorRecord) error {
+		// Skip the records that don't have a published tx yet.
+		if r.tx == nil || r.feeFunction == nil {
+			return nil
+		}
+
+		state := &BumpState{
+			Txid:           r.tx.TxHash(),
+			Fee:            r.fee,
+			Budget:         r.req.Budget,
+			DeadlineHeight: r.req.DeadlineHeight,
+			FeeFunction:    r.feeFunction.State(),
+			NextFee:        r.fee,
+		}
+		for _, inp := range r.req.Inputs {
+			state.Inputs = append(state.Inputs, inp.OutPoint())
+		}
+
+		// The fee of the next RBF step scales with the fee rate, as
+		// the tx keeps the same inputs and outputs.
+		current := state.FeeFunction.CurrentFeeRate
+		if current > 0 {
+			state.NextFee = r.fee *
+				btcutil.Amount(state.FeeFunction.NextFeeRate) /
+				btcutil.Amount(current)
+		}
+
+		ids = append(ids, id)
+		states[id] = state
+
+		return nil
+	})
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+
+	result := make([]*BumpState, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, states[id])
+	}
+
+	return result
+}
+
 // processRecords checks all the txns being monitored, and checks if any of
 // them needs to be bumped. If so, it will attempt to bump the fee of the tx.
 func (t *TxPublisher) processRecords() {
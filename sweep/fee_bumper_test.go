@@ -1476,3 +1476,188 @@ func TestProcessRecords(t *testing.T) {
 		require.Equal(t, requestID2, result.requestID)
 	}
 }
+
+// createTestInputWithParent creates a test input that spends from the given
+// unconfirmed parent tx.
+func createTestInputWithParent(value int64,
+	parent *wire.MsgTx) input.BaseInput {
+
+	return input.MakeBaseInput(
+		&wire.OutPoint{Hash: parent.TxHash()},
+		input.WitnessKeyHash,
+		&input.SignDescriptor{
+			Output: &wire.TxOut{
+				Value: value,
+			},
+			KeyDesc: keychain.KeyDescriptor{
+				PubKey: testPubKey,
+			},
+		},
+		0,
+		&input.TxInfo{
+			Weight: 500,
+			Tx:     parent,
+		},
+	)
+}
+
+// TestCreateAndCheckTxPackage checks that the mempool check of a tx whose
+// parent is missing is only skipped if the tx can be broadcast as a package.
+func TestCreateAndCheckTxPackage(t *testing.T) {
+	t.Parallel()
+
+	// Create a publisher using the mocks.
+	tp, m := createTestPublisher(t)
+
+	feerate := chainfee.SatPerKWeight(1000)
+	m.feeFunc.On("FeeRate").Return(feerate)
+
+	script := &input.Script{}
+	m.signer.On("ComputeInputScript", mock.Anything,
+		mock.Anything).Return(script, nil)
+
+	// The parent isn't in the mempool, so the check always fails.
+	m.wallet.On("CheckMempoolAcceptance", mock.Anything).Return(
+		fmt.Errorf("mempool rejection: %w", chain.ErrMissingInputs),
+	)
+
+	parent := wire.NewMsgTx(2)
+	parent.AddTxOut(&wire.TxOut{Value: 330})
+
+	inp := createTestInputWithParent(100_000, parent)
+	req := &BumpRequest{
+		DeliveryAddress: changePkScript,
+		Inputs:          []input.Input{&inp},
+		Budget:          btcutil.Amount(10_000),
+	}
+
+	// Without package relay, the error is returned.
+	_, err := tp.createAndCheckTx(req, m.feeFunc)
+	require.ErrorIs(t, err, chain.ErrMissingInputs)
+
+	// With package relay, the check is skipped.
+	tp.cfg.PackageSubmitter = fn.Some[lnwallet.PackageSubmitter](
+		&MockPackageSubmitter{},
+	)
+	_, err = tp.createAndCheckTx(req, m.feeFunc)
+	require.NoError(t, err)
+
+	// An input without a known parent can't be broadcast as a package.
+	other := createTestInput(1000, input.WitnessKeyHash)
+	req.Inputs = []input.Input{&other}
+	_, err = tp.createAndCheckTx(req, m.feeFunc)
+	require.ErrorIs(t, err, chain.ErrMissingInputs)
+}
+
+// TestPublishPackage checks that sweeping txns with unconfirmed parents are
+// submitted as a package if possible, and otherwise published after their
+// parents.
+func TestPublishPackage(t *testing.T) {
+	t.Parallel()
+
+	parent := wire.NewMsgTx(2)
+	parent.AddTxOut(&wire.TxOut{Value: 330})
+
+	withParent := createTestInputWithParent(1000, parent)
+	withoutParent := createTestInput(1000, input.WitnessKeyHash)
+
+	child := wire.NewMsgTx(2)
+	child.AddTxIn(&wire.TxIn{PreviousOutPoint: withParent.OutPoint()})
+
+	testCases := []struct {
+		name string
+
+		// inputs are the inputs of the child.
+		inputs []input.Input
+
+		// submitErr is the error returned from submitting the package.
+		// If None, package relay is unsupported.
+		submitErr fn.Option[error]
+
+		// expPublishParent indicates whether the parent is expected to
+		// be published on its own.
+		expPublishParent bool
+
+		// parentKnown indicates whether the wallet already knows the
+		// parent, in which case it isn't published again.
+		parentKnown bool
+	}{
+		{
+			name:      "no parent",
+			inputs:    []input.Input{&withoutParent},
+			submitErr: fn.Some[error](nil),
+		},
+		{
+			name: "package accepted",
+			inputs: []input.Input{
+				&withParent, &withoutParent,
+			},
+			submitErr: fn.Some[error](nil),
+		},
+		{
+			name:             "package rejected",
+			inputs:           []input.Input{&withParent},
+			submitErr:        fn.Some(errDummy),
+			expPublishParent: true,
+		},
+		{
+			name:             "package relay unsupported",
+			inputs:           []input.Input{&withParent},
+			submitErr:        fn.None[error](),
+			expPublishParent: true,
+		},
+		{
+			name:        "parent known to the wallet",
+			inputs:      []input.Input{&withParent},
+			submitErr:   fn.None[error](),
+			parentKnown: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tp, m := createTestPublisher(t)
+
+			submitter := &MockPackageSubmitter{}
+			t.Cleanup(func() {
+				submitter.AssertExpectations(t)
+			})
+
+			tc.submitErr.WhenSome(func(err error) {
+				tp.cfg.PackageSubmitter = fn.Some(
+					lnwallet.PackageSubmitter(submitter),
+				)
+
+				if len(unconfParentTxs(tc.inputs)) == 0 {
+					return
+				}
+
+				submitter.On(
+					"SubmitPackage",
+					[]*wire.MsgTx{parent}, child,
+				).Return(err).Once()
+			})
+
+			if tc.parentKnown {
+				m.wallet.On("FetchTx", parent.TxHash()).
+					Return(parent, nil).Once()
+			}
+
+			if tc.expPublishParent {
+				m.wallet.On("FetchTx", parent.TxHash()).
+					Return(nil, errDummy).Once()
+				m.wallet.On("PublishTransaction", parent, "").
+					Return(errDummy).Once()
+			}
+
+			// The child is always published to the wallet.
+			m.wallet.On(
+				"PublishTransaction", child, mock.Anything,
+			).Return(nil).Once()
+
+			require.NoError(t, tp.publish(child, tc.inputs))
+		})
+	}
+}
@@ -61,6 +61,33 @@ type FeeFunction interface {
 	// fee rate based on a conf target without taking care of the fee
 	// function's current state (position).
 	IncreaseFeeRate(confTarget uint32) (bool, error)
+
+	// State returns a snapshot of the fee function's current state,
+	// including the fee rate the next increment would result in.
+	State() FeeFunctionState
+}
+
+// FeeFunctionState is a snapshot of the state of a fee function.
+type FeeFunctionState struct {
+	// StartingFeeRate is the fee rate the function started with.
+	StartingFeeRate chainfee.SatPerKWeight
+
+	// EndingFeeRate is the max fee rate the function will reach.
+	EndingFeeRate chainfee.SatPerKWeight
+
+	// CurrentFeeRate is the current fee rate of the function.
+	CurrentFeeRate chainfee.SatPerKWeight
+
+	// NextFeeRate is the fee rate that the next increment would result
+	// in. It equals the current fee rate once the function reached its
+	// end.
+	NextFeeRate chainfee.SatPerKWeight
+
+	// Position is the current position of the function.
+	Position uint32
+
+	// Width is the number of positions of the function.
+	Width uint32
 }
 
 // LinearFeeFunction implements the FeeFunction interface with a linear
@@ -234,6 +261,25 @@ func (l *LinearFeeFunction) IncreaseFeeRate(confTarget uint32) (bool, error) {
 	return l.increaseFeeRate(newPosition)
 }
 
+// State returns a snapshot of the fee function's current state.
+//
+// NOTE: part of the FeeFunction interface.
+func (l *LinearFeeFunction) State() FeeFunctionState {
+	next := l.currentFeeRate
+	if l.position < l.width {
+		next = l.feeRateAtPosition(l.position + 1)
+	}
+
+	return FeeFunctionState{
+		StartingFeeRate: l.startingFeeRate,
+		EndingFeeRate:   l.endingFeeRate,
+		CurrentFeeRate:  l.currentFeeRate,
+		NextFeeRate:     next,
+		Position:        l.position,
+		Width:           l.width,
+	}
+}
+
 // increaseFeeRate increases the fee rate by the specified position, returns a
 // boolean to indicate whether the fee rate was increased, and an error if the
 // position is greater than the width. The increased fee rate will be set as
@@ -392,3 +392,60 @@ func TestLinearFeeFunctionIncreaseFeeRate(t *testing.T) {
 	rt.ErrorIs(err, ErrMaxPosition)
 	rt.False(increased)
 }
+
+// TestLinearFeeFunctionState checks that the state snapshot previews the fee
+// rate of the next increment.
+func TestLinearFeeFunctionState(t *testing.T) {
+	t.Parallel()
+
+	rt := require.New(t)
+
+	// Create a mock fee estimator.
+	estimator := &chainfee.MockEstimator{}
+	defer estimator.AssertExpectations(t)
+
+	// Create testing params. These params are chosen so the delta value is
+	// 100.
+	maxFeeRate := chainfee.SatPerKWeight(900)
+	estimatedFeeRate := chainfee.SatPerKWeight(100)
+	confTarget := uint32(9) // This means the width is 8.
+
+	// Mock the fee estimator to return the fee rate.
+	estimator.On("EstimateFeePerKW", confTarget).Return(
+		estimatedFeeRate, nil).Once()
+	estimator.On("RelayFeePerKW").Return(estimatedFeeRate).Once()
+
+	f, err := NewLinearFeeFunction(
+		maxFeeRate, confTarget, estimator,
+		fn.None[chainfee.SatPerKWeight](),
+	)
+	rt.NoError(err)
+
+	// The initial state previews the first increment.
+	rt.Equal(FeeFunctionState{
+		StartingFeeRate: estimatedFeeRate,
+		EndingFeeRate:   maxFeeRate,
+		CurrentFeeRate:  estimatedFeeRate,
+		NextFeeRate:     200,
+		Position:        0,
+		Width:           8,
+	}, f.State())
+
+	// The preview doesn't change the state of the fee function, and
+	// matches the fee rate of the next increment.
+	next := f.State().NextFeeRate
+	rt.Equal(estimatedFeeRate, f.FeeRate())
+
+	_, err = f.Increment()
+	rt.NoError(err)
+	rt.Equal(next, f.FeeRate())
+
+	// Once the end is reached, the preview equals the current fee rate.
+	_, err = f.IncreaseFeeRate(1)
+	rt.NoError(err)
+
+	state := f.State()
+	rt.Equal(maxFeeRate, state.CurrentFeeRate)
+	rt.Equal(maxFeeRate, state.NextFeeRate)
+	rt.Equal(uint32(8), state.Position)
+}
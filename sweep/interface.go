@@ -60,6 +60,10 @@ type Wallet interface {
 	// which could be e.g. btcd, bitcoind, neutrino, or another consensus
 	// service.
 	BackEnd() string
+
+	// OutputLeaser allows the sweeper to lease the wallet inputs it adds
+	// to its sweeping txns.
+	OutputLeaser
 }
 
 // SweepOutput is an output used to sweep funds from a channel output.
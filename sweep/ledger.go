@@ -0,0 +1,478 @@
+package sweep
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ledgerBucketKey is the key that points to a bucket containing the
+	// history of all sweeps.
+	//
+	// maps: sweepID -> LedgerSweep
+	ledgerBucketKey = []byte("sweeper-ledger")
+
+	// errNoLedgerBucket is returned when the ledger bucket is missing.
+	errNoLedgerBucket = errors.New("ledger bucket does not exist")
+
+	// ErrLedgerSweepNotFound is returned when a sweep cannot be found in
+	// the ledger.
+	ErrLedgerSweepNotFound = errors.New("ledger sweep not found")
+)
+
+// SweepOutcome describes the outcome of a sweep in the ledger.
+type SweepOutcome uint8
+
+const (
+	// SweepOutcomePending indicates the sweeping tx is not confirmed yet.
+	SweepOutcomePending SweepOutcome = iota
+
+	// SweepOutcomeConfirmed indicates the sweeping tx has been confirmed.
+	SweepOutcomeConfirmed
+
+	// SweepOutcomeFailed indicates the sweep has failed and its inputs
+	// were handed back to the sweeper.
+	SweepOutcomeFailed
+)
+
+// String returns a human readable name of the outcome.
+func (o SweepOutcome) String() string {
+	switch o {
+	case SweepOutcomePending:
+		return "pending"
+
+	case SweepOutcomeConfirmed:
+		return "confirmed"
+
+	case SweepOutcomeFailed:
+		return "failed"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(o))
+	}
+}
+
+// SweepAttempt is a single broadcast of a sweeping tx. Each RBF of a sweep
+// creates a new attempt.
+type SweepAttempt struct {
+	// Txid is the hash of the sweeping tx.
+	Txid chainhash.Hash
+
+	// FeeRate is the fee rate of the sweeping tx.
+	FeeRate chainfee.SatPerKWeight
+
+	// Fee is the fee of the sweeping tx.
+	Fee btcutil.Amount
+
+	// Height is the block height at which the tx was broadcast.
+	Height uint32
+}
+
+// LedgerSweep is the history of a sweep, from its first broadcast until the
+// sweeping tx confirmed or the sweep failed.
+type LedgerSweep struct {
+	// ID is the unique ID of the sweep in the ledger.
+	ID uint64
+
+	// Inputs are the inputs swept by the sweeping tx.
+	Inputs []wire.OutPoint
+
+	// Attempts are the broadcasts of the sweeping tx, in order.
+	Attempts []SweepAttempt
+
+	// Outcome is the outcome of the sweep.
+	Outcome SweepOutcome
+
+	// FailureReason describes why the sweep failed, if it did.
+	FailureReason string
+}
+
+// RBFIterations returns the number of times the sweeping tx was replaced.
+func (l *LedgerSweep) RBFIterations() int {
+	if len(l.Attempts) == 0 {
+		return 0
+	}
+
+	return len(l.Attempts) - 1
+}
+
+// LastAttempt returns the most recent broadcast of the sweeping tx, which is
+// the confirmed one if the sweep is confirmed.
+func (l *LedgerSweep) LastAttempt() *SweepAttempt {
+	if len(l.Attempts) == 0 {
+		return nil
+	}
+
+	return &l.Attempts[len(l.Attempts)-1]
+}
+
+// FeePaid returns the fee that went to the chain, which is only set once the
+// sweeping tx is confirmed.
+func (l *LedgerSweep) FeePaid() btcutil.Amount {
+	if l.Outcome != SweepOutcomeConfirmed || len(l.Attempts) == 0 {
+		return 0
+	}
+
+	return l.LastAttempt().Fee
+}
+
+// encodeOutPoints serializes a list of outpoints.
+func encodeOutPoints(ops []wire.OutPoint) []byte {
+	var b bytes.Buffer
+
+	var scratch [4]byte
+	byteOrder.PutUint32(scratch[:], uint32(len(ops)))
+	b.Write(scratch[:])
+
+	for _, op := range ops {
+		b.Write(op.Hash[:])
+		byteOrder.PutUint32(scratch[:], op.Index)
+		b.Write(scratch[:])
+	}
+
+	return b.Bytes()
+}
+
+// decodeOutPoints deserializes a list of outpoints.
+func decodeOutPoints(data []byte) ([]wire.OutPoint, error) {
+	r := bytes.NewReader(data)
+
+	var num uint32
+	if err := binary.Read(r, byteOrder, &num); err != nil {
+		return nil, err
+	}
+
+	ops := make([]wire.OutPoint, num)
+	for i := range ops {
+		if _, err := io.ReadFull(r, ops[i].Hash[:]); err != nil {
+			return nil, err
+		}
+
+		err := binary.Read(r, byteOrder, &ops[i].Index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ops, nil
+}
+
+// encodeAttempts serializes a list of sweep attempts.
+func encodeAttempts(attempts []SweepAttempt) []byte {
+	var b bytes.Buffer
+
+	var scratch [8]byte
+	byteOrder.PutUint32(scratch[:4], uint32(len(attempts)))
+	b.Write(scratch[:4])
+
+	for _, attempt := range attempts {
+		b.Write(attempt.Txid[:])
+
+		byteOrder.PutUint64(scratch[:], uint64(attempt.FeeRate))
+		b.Write(scratch[:])
+
+		byteOrder.PutUint64(scratch[:], uint64(attempt.Fee))
+		b.Write(scratch[:])
+
+		byteOrder.PutUint32(scratch[:4], attempt.Height)
+		b.Write(scratch[:4])
+	}
+
+	return b.Bytes()
+}
+
+// decodeAttempts deserializes a list of sweep attempts.
+func decodeAttempts(data []byte) ([]SweepAttempt, error) {
+	r := bytes.NewReader(data)
+
+	var num uint32
+	if err := binary.Read(r, byteOrder, &num); err != nil {
+		return nil, err
+	}
+
+	attempts := make([]SweepAttempt, num)
+	for i := range attempts {
+		attempt := &attempts[i]
+		if _, err := io.ReadFull(r, attempt.Txid[:]); err != nil {
+			return nil, err
+		}
+
+		var feeRate, fee uint64
+		if err := binary.Read(r, byteOrder, &feeRate); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, byteOrder, &fee); err != nil {
+			return nil, err
+		}
+		err := binary.Read(r, byteOrder, &attempt.Height)
+		if err != nil {
+			return nil, err
+		}
+
+		attempt.FeeRate = chainfee.SatPerKWeight(feeRate)
+		attempt.Fee = btcutil.Amount(fee)
+	}
+
+	return attempts, nil
+}
+
+// ledgerSweepTlvStream returns the tlv stream used to serialize a LedgerSweep
+// from the given encoded fields.
+func ledgerSweepTlvStream(outcome *uint8, inputs, attempts,
+	reason *[]byte) (*tlv.Stream, error) {
+
+	const (
+		// A set of tlv type definitions used to serialize LedgerSweep.
+		//
+		// NOTE: ID is stored as the key, so it's not included here.
+		outcomeType       tlv.Type = 0
+		inputsType        tlv.Type = 1
+		attemptsType      tlv.Type = 2
+		failureReasonType tlv.Type = 3
+	)
+
+	return tlv.NewStream(
+		tlv.MakePrimitiveRecord(outcomeType, outcome),
+		tlv.MakePrimitiveRecord(inputsType, inputs),
+		tlv.MakePrimitiveRecord(attemptsType, attempts),
+		tlv.MakePrimitiveRecord(failureReasonType, reason),
+	)
+}
+
+// serializeLedgerSweep serializes a LedgerSweep based on tlv format.
+func serializeLedgerSweep(w io.Writer, l *LedgerSweep) error {
+	var (
+		outcome  = uint8(l.Outcome)
+		inputs   = encodeOutPoints(l.Inputs)
+		attempts = encodeAttempts(l.Attempts)
+		reason   = []byte(l.FailureReason)
+	)
+
+	tlvStream, err := ledgerSweepTlvStream(
+		&outcome, &inputs, &attempts, &reason,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
+}
+
+// deserializeLedgerSweep deserializes a LedgerSweep based on tlv format.
+func deserializeLedgerSweep(r io.Reader) (*LedgerSweep, error) {
+	var (
+		outcome                  uint8
+		inputs, attempts, reason []byte
+	)
+
+	tlvStream, err := ledgerSweepTlvStream(
+		&outcome, &inputs, &attempts, &reason,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tlvStream.Decode(r); err != nil {
+		return nil, err
+	}
+
+	l := &LedgerSweep{
+		Outcome:       SweepOutcome(outcome),
+		FailureReason: string(reason),
+	}
+
+	l.Inputs, err = decodeOutPoints(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Attempts, err = decodeAttempts(attempts)
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// SweepLedger persists the history of all sweeps, so the fees that went to
+// the chain can be audited.
+type SweepLedger interface {
+	// AddSweep adds a new sweep to the ledger and sets its ID.
+	AddSweep(l *LedgerSweep) error
+
+	// UpdateSweep overwrites the sweep with the ID of the given sweep.
+	UpdateSweep(l *LedgerSweep) error
+
+	// ListLedgerSweeps returns all sweeps in the ledger, ordered by ID.
+	ListLedgerSweeps() ([]*LedgerSweep, error)
+}
+
+type sweepLedger struct {
+	db kvdb.Backend
+}
+
+// NewSweepLedger returns a new ledger instance.
+func NewSweepLedger(db kvdb.Backend) (SweepLedger, error) {
+	err := kvdb.Update(db, func(tx kvdb.RwTx) error {
+		_, err := tx.CreateTopLevelBucket(ledgerBucketKey)
+
+		return err
+	}, func() {})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sweepLedger{
+		db: db,
+	}, nil
+}
+
+// putLedgerSweep stores the given sweep under its ID.
+func putLedgerSweep(bucket kvdb.RwBucket, l *LedgerSweep) error {
+	var b bytes.Buffer
+	if err := serializeLedgerSweep(&b, l); err != nil {
+		return err
+	}
+
+	var key [8]byte
+	byteOrder.PutUint64(key[:], l.ID)
+
+	return bucket.Put(key[:], b.Bytes())
+}
+
+// AddSweep adds a new sweep to the ledger and sets its ID.
+func (s *sweepLedger) AddSweep(l *LedgerSweep) error {
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(ledgerBucketKey)
+		if bucket == nil {
+			return errNoLedgerBucket
+		}
+
+		id, err := bucket.NextSequence()
+		if err != nil {
+			return err
+		}
+		l.ID = id
+
+		return putLedgerSweep(bucket, l)
+	}, func() {})
+}
+
+// UpdateSweep overwrites the sweep with the ID of the given sweep.
+func (s *sweepLedger) UpdateSweep(l *LedgerSweep) error {
+	return kvdb.Update(s.db, func(tx kvdb.RwTx) error {
+		bucket := tx.ReadWriteBucket(ledgerBucketKey)
+		if bucket == nil {
+			return errNoLedgerBucket
+		}
+
+		var key [8]byte
+		byteOrder.PutUint64(key[:], l.ID)
+		if bucket.Get(key[:]) == nil {
+			return ErrLedgerSweepNotFound
+		}
+
+		return putLedgerSweep(bucket, l)
+	}, func() {})
+}
+
+// ListLedgerSweeps returns all sweeps in the ledger, ordered by ID.
+func (s *sweepLedger) ListLedgerSweeps() ([]*LedgerSweep, error) {
+	var sweeps []*LedgerSweep
+
+	err := kvdb.View(s.db, func(tx kvdb.RTx) error {
+		bucket := tx.ReadBucket(ledgerBucketKey)
+		if bucket == nil {
+			return errNoLedgerBucket
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			l, err := deserializeLedgerSweep(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			l.ID = byteOrder.Uint64(k)
+
+			sweeps = append(sweeps, l)
+
+			return nil
+		})
+	}, func() {
+		sweeps = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sweeps, nil
+}
+
+// Compile-time constraint to ensure sweepLedger implements SweepLedger.
+var _ SweepLedger = (*sweepLedger)(nil)
+
+// ledgerCSVHeader is the header of the CSV export of the ledger.
+var ledgerCSVHeader = []string{
+	"id", "outcome", "inputs", "rbf_iterations", "txid",
+	"fee_rate_sat_per_kw", "fee_sat", "first_height", "last_height",
+	"failure_reason",
+}
+
+// WriteLedgerCSV writes the given sweeps to w in CSV format, one row per
+// sweep. The txid, fee rate and fee are those of the last attempt, which is
+// the confirmed tx for confirmed sweeps. The inputs are separated by spaces.
+func WriteLedgerCSV(w io.Writer, sweeps []*LedgerSweep) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ledgerCSVHeader); err != nil {
+		return err
+	}
+
+	for _, l := range sweeps {
+		inputs := make([]string, len(l.Inputs))
+		for i, op := range l.Inputs {
+			inputs[i] = op.String()
+		}
+
+		var (
+			txid, feeRate, fee      string
+			firstHeight, lastHeight string
+		)
+		if last := l.LastAttempt(); last != nil {
+			txid = last.Txid.String()
+			feeRate = strconv.FormatInt(int64(last.FeeRate), 10)
+			fee = strconv.FormatInt(int64(last.Fee), 10)
+			firstHeight = strconv.FormatUint(
+				uint64(l.Attempts[0].Height), 10,
+			)
+			lastHeight = strconv.FormatUint(uint64(last.Height), 10)
+		}
+
+		err := cw.Write([]string{
+			strconv.FormatUint(l.ID, 10),
+			l.Outcome.String(),
+			strings.Join(inputs, " "),
+			strconv.Itoa(l.RBFIterations()),
+			txid, feeRate, fee, firstHeight, lastHeight,
+			l.FailureReason,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
@@ -0,0 +1,200 @@
+package sweep
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestLedger creates a ledger backed by a test database.
+func newTestLedger(t *testing.T) SweepLedger {
+	t.Helper()
+
+	cdb, err := channeldb.MakeTestDB(t)
+	require.NoError(t, err)
+
+	ledger, err := NewSweepLedger(cdb)
+	require.NoError(t, err)
+
+	return ledger
+}
+
+// TestSweepLedger checks that sweeps are persisted in the ledger and can be
+// updated and listed again.
+func TestSweepLedger(t *testing.T) {
+	t.Parallel()
+
+	ledger := newTestLedger(t)
+
+	l1 := &LedgerSweep{
+		Inputs: []wire.OutPoint{
+			{Hash: chainhash.Hash{1}, Index: 1},
+			{Hash: chainhash.Hash{2}, Index: 2},
+		},
+		Attempts: []SweepAttempt{{
+			Txid:    chainhash.Hash{3},
+			FeeRate: 1000,
+			Fee:     500,
+			Height:  100,
+		}},
+	}
+	require.NoError(t, ledger.AddSweep(l1))
+	require.EqualValues(t, 1, l1.ID)
+
+	l2 := &LedgerSweep{
+		Inputs: []wire.OutPoint{{Hash: chainhash.Hash{4}}},
+		Attempts: []SweepAttempt{{
+			Txid: chainhash.Hash{5},
+		}},
+		Outcome:       SweepOutcomeFailed,
+		FailureReason: "dummy error",
+	}
+	require.NoError(t, ledger.AddSweep(l2))
+	require.EqualValues(t, 2, l2.ID)
+
+	// Replace the tx of the first sweep and confirm it.
+	l1.Attempts = append(l1.Attempts, SweepAttempt{
+		Txid:    chainhash.Hash{6},
+		FeeRate: 2000,
+		Fee:     1000,
+		Height:  101,
+	})
+	l1.Outcome = SweepOutcomeConfirmed
+	require.NoError(t, ledger.UpdateSweep(l1))
+
+	sweeps, err := ledger.ListLedgerSweeps()
+	require.NoError(t, err)
+	require.Equal(t, []*LedgerSweep{l1, l2}, sweeps)
+
+	require.Equal(t, 1, sweeps[0].RBFIterations())
+	require.EqualValues(t, 1000, sweeps[0].FeePaid())
+	require.Zero(t, sweeps[1].FeePaid())
+
+	// Updating an unknown sweep fails.
+	err = ledger.UpdateSweep(&LedgerSweep{ID: 3})
+	require.ErrorIs(t, err, ErrLedgerSweepNotFound)
+}
+
+// TestWriteLedgerCSV checks the CSV export of the ledger.
+func TestWriteLedgerCSV(t *testing.T) {
+	t.Parallel()
+
+	sweeps := []*LedgerSweep{{
+		ID: 1,
+		Inputs: []wire.OutPoint{
+			{Hash: chainhash.Hash{1}, Index: 1},
+			{Hash: chainhash.Hash{2}, Index: 2},
+		},
+		Attempts: []SweepAttempt{
+			{Txid: chainhash.Hash{3}, FeeRate: 1000, Fee: 500,
+				Height: 100},
+			{Txid: chainhash.Hash{4}, FeeRate: 2000, Fee: 1000,
+				Height: 102},
+		},
+		Outcome: SweepOutcomeConfirmed,
+	}, {
+		ID:            2,
+		Outcome:       SweepOutcomeFailed,
+		FailureReason: "no tx, with comma",
+	}}
+
+	var b bytes.Buffer
+	require.NoError(t, WriteLedgerCSV(&b, sweeps))
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Equal(t, []string{
+		strings.Join(ledgerCSVHeader, ","),
+		"1,confirmed," + sweeps[0].Inputs[0].String() + " " +
+			sweeps[0].Inputs[1].String() + ",1," +
+			chainhash.Hash{4}.String() + ",2000,1000,100,102,",
+		`2,failed,,0,,,,,,"no tx, with comma"`,
+	}, lines)
+}
+
+// TestRecordLedger checks that the publisher records the initial broadcast,
+// the replacements and the confirmation of a sweep in the ledger.
+func TestRecordLedger(t *testing.T) {
+	t.Parallel()
+
+	tp, _ := createTestPublisher(t)
+	tp.currentHeight.Store(100)
+
+	ledger := newTestLedger(t)
+
+	op := wire.OutPoint{Hash: chainhash.Hash{1}}
+	tx1 := &wire.MsgTx{LockTime: 1}
+	tx1.AddTxIn(&wire.TxIn{PreviousOutPoint: op})
+	tx2 := &wire.MsgTx{LockTime: 2}
+	tx2.AddTxIn(&wire.TxIn{PreviousOutPoint: op})
+
+	// The initial broadcast adds the sweep.
+	err := tp.recordLedger(ledger, &BumpResult{
+		Event:     TxPublished,
+		Tx:        tx1,
+		Fee:       500,
+		FeeRate:   1000,
+		requestID: 7,
+	})
+	require.NoError(t, err)
+
+	// The replacement is recorded as a new attempt.
+	tp.currentHeight.Store(101)
+	err = tp.recordLedger(ledger, &BumpResult{
+		Event:      TxReplaced,
+		Tx:         tx2,
+		ReplacedTx: tx1,
+		Fee:        1000,
+		FeeRate:    2000,
+		requestID:  7,
+	})
+	require.NoError(t, err)
+
+	sweeps, err := ledger.ListLedgerSweeps()
+	require.NoError(t, err)
+	require.Len(t, sweeps, 1)
+	require.Equal(t, []wire.OutPoint{op}, sweeps[0].Inputs)
+	require.Equal(t, SweepOutcomePending, sweeps[0].Outcome)
+	require.Equal(t, []SweepAttempt{
+		{Txid: tx1.TxHash(), FeeRate: 1000, Fee: 500, Height: 100},
+		{Txid: tx2.TxHash(), FeeRate: 2000, Fee: 1000, Height: 101},
+	}, sweeps[0].Attempts)
+
+	// The confirmation completes the sweep.
+	err = tp.recordLedger(ledger, &BumpResult{
+		Event:     TxConfirmed,
+		Tx:        tx2,
+		Fee:       1000,
+		FeeRate:   2000,
+		requestID: 7,
+	})
+	require.NoError(t, err)
+
+	sweeps, err = ledger.ListLedgerSweeps()
+	require.NoError(t, err)
+	require.Equal(t, SweepOutcomeConfirmed, sweeps[0].Outcome)
+	require.EqualValues(t, 1000, sweeps[0].FeePaid())
+
+	_, ok := tp.ledgerSweeps.Load(7)
+	require.False(t, ok)
+
+	// A sweep that fails on its initial broadcast is recorded as failed.
+	err = tp.recordLedger(ledger, &BumpResult{
+		Event:     TxFailed,
+		Tx:        tx1,
+		Err:       errors.New("dummy error"),
+		requestID: 8,
+	})
+	require.NoError(t, err)
+
+	sweeps, err = ledger.ListLedgerSweeps()
+	require.NoError(t, err)
+	require.Len(t, sweeps, 2)
+	require.Equal(t, SweepOutcomeFailed, sweeps[1].Outcome)
+	require.Equal(t, "dummy error", sweeps[1].FailureReason)
+}
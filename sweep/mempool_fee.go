@@ -0,0 +1,150 @@
+package sweep
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// incrementalRelayFeeRate is the fee rate a replacement tx must pay on top of
+// the fees of the txns it replaces, matching the default incremental relay
+// fee of bitcoind.
+const incrementalRelayFeeRate = chainfee.FeePerKwFloor
+
+// mempoolFeeRateFloor returns the minimum fee rate the given sweeping tx must
+// pay according to the state of the mempool. The tx must pay enough to
+// replace the txns that spend the same inputs, including all their
+// descendants, so a pinning tx can be displaced. It must also pay for its
+// unconfirmed ancestors whose fee rate is below the target fee rate, so the
+// whole cluster confirms at the target. Zero is returned if the mempool
+// doesn't impose a floor.
+//
+// NOTE: the mempool watcher is optional and may be nil, in which case the
+// conflicting txns are not taken into account.
+func mempoolFeeRateFloor(inspector chainntnfs.MempoolInspector,
+	mempool chainntnfs.MempoolWatcher, tx *wire.MsgTx,
+	target chainfee.SatPerKWeight) (chainfee.SatPerKWeight, error) {
+
+	txid := tx.TxHash()
+	weight := lntypes.WeightUnit(
+		blockchain.GetTransactionWeight(btcutil.NewTx(tx)),
+	)
+
+	var floor chainfee.SatPerKWeight
+
+	// Find the txns that spend our inputs in the mempool and make sure
+	// we outbid them.
+	if mempool != nil {
+		conflicts := make(map[chainhash.Hash]struct{})
+		for _, txIn := range tx.TxIn {
+			op := txIn.PreviousOutPoint
+
+			mempool.LookupInputMempoolSpend(op).WhenSome(
+				func(spender wire.MsgTx) {
+					conflicts[spender.TxHash()] = struct{}{}
+				},
+			)
+		}
+		delete(conflicts, txid)
+
+		for conflict := range conflicts {
+			entry, err := lookupEntry(inspector, conflict)
+			if err != nil {
+				return 0, err
+			}
+
+			entry.WhenSome(func(e chainntnfs.MempoolEntry) {
+				rate := replacementFeeRate(e, weight)
+				if rate > floor {
+					floor = rate
+				}
+			})
+		}
+	}
+
+	// Calculate the extra fees needed to lift the unconfirmed ancestors
+	// to the target fee rate. Ancestors shared by several parents are
+	// counted more than once, which overestimates the required fees.
+	var (
+		deficit btcutil.Amount
+		parents = make(map[chainhash.Hash]struct{})
+	)
+	for _, txIn := range tx.TxIn {
+		parent := txIn.PreviousOutPoint.Hash
+		if _, ok := parents[parent]; ok {
+			continue
+		}
+		parents[parent] = struct{}{}
+
+		entry, err := lookupEntry(inspector, parent)
+		if err != nil {
+			return 0, err
+		}
+
+		entry.WhenSome(func(e chainntnfs.MempoolEntry) {
+			ancestorWeight := lntypes.VByte(e.AncestorVSize).ToWU()
+			required := target.FeeForWeight(ancestorWeight)
+			if required > e.AncestorFee {
+				deficit += required - e.AncestorFee
+			}
+		})
+	}
+
+	if deficit > 0 {
+		rate := chainfee.NewSatPerKWeight(
+			target.FeeForWeight(weight)+deficit, weight,
+		)
+		if rate > floor {
+			floor = rate
+		}
+	}
+
+	return floor, nil
+}
+
+// lookupEntry returns the mempool entry of the given tx.
+func lookupEntry(inspector chainntnfs.MempoolInspector,
+	txid chainhash.Hash) (fn.Option[chainntnfs.MempoolEntry], error) {
+
+	entry, err := inspector.LookupMempoolEntry(txid)
+	if err != nil {
+		return entry, fmt.Errorf("lookup mempool entry %v: %w", txid,
+			err)
+	}
+
+	return entry, nil
+}
+
+// replacementFeeRate returns the minimum fee rate a tx of the given weight
+// must pay to replace the given mempool tx. The replacement must pay a higher
+// fee rate than the replaced tx, and its fee must cover the fees of the
+// replaced tx and all its descendants plus the incremental relay fee.
+func replacementFeeRate(conflict chainntnfs.MempoolEntry,
+	weight lntypes.WeightUnit) chainfee.SatPerKWeight {
+
+	conflictRate := chainfee.NewSatPerKWeight(
+		conflict.Fee, lntypes.VByte(conflict.VSize).ToWU(),
+	) + 1
+
+	fee := conflict.DescendantFee +
+		incrementalRelayFeeRate.FeeForWeight(weight)
+	rate := chainfee.NewSatPerKWeight(fee, weight)
+
+	// Round up so the fee of the replacement isn't short by a satoshi.
+	for rate.FeeForWeight(weight) < fee {
+		rate++
+	}
+
+	if conflictRate > rate {
+		return conflictRate
+	}
+
+	return rate
+}
@@ -0,0 +1,238 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newMempoolTestTx returns a tx that spends the given outpoints to a single
+// output, together with its weight.
+func newMempoolTestTx(ops ...wire.OutPoint) (*wire.MsgTx,
+	lntypes.WeightUnit) {
+
+	tx := &wire.MsgTx{Version: 2}
+	for _, op := range ops {
+		tx.AddTxIn(&wire.TxIn{PreviousOutPoint: op})
+	}
+	tx.AddTxOut(&wire.TxOut{Value: 100_000, PkScript: make([]byte, 22)})
+
+	weight := lntypes.WeightUnit(
+		blockchain.GetTransactionWeight(btcutil.NewTx(tx)),
+	)
+
+	return tx, weight
+}
+
+// TestReplacementFeeRate checks that the replacement fee rate beats both the
+// fee rate of the conflicting tx and the fees of its descendants.
+func TestReplacementFeeRate(t *testing.T) {
+	t.Parallel()
+
+	const weight = lntypes.WeightUnit(800)
+
+	// A conflicting tx that pays a high fee rate but has no descendants
+	// is outbid by its fee rate.
+	conflict := chainntnfs.MempoolEntry{
+		VSize:           100,
+		Fee:             10_000,
+		DescendantVSize: 100,
+		DescendantFee:   10_000,
+	}
+	rate := replacementFeeRate(conflict, weight)
+	require.Greater(t, rate, chainfee.NewSatPerKWeight(10_000, 400))
+
+	// A pinning tx with a large descendant is outbid by the fees of the
+	// whole package plus the incremental relay fee.
+	conflict = chainntnfs.MempoolEntry{
+		VSize:           100,
+		Fee:             200,
+		DescendantVSize: 50_000,
+		DescendantFee:   50_000,
+	}
+	rate = replacementFeeRate(conflict, weight)
+
+	minFee := conflict.DescendantFee +
+		incrementalRelayFeeRate.FeeForWeight(weight)
+	require.GreaterOrEqual(t, rate.FeeForWeight(weight), minFee)
+	require.Less(t, (rate - 1).FeeForWeight(weight), minFee)
+}
+
+// TestMempoolFeeRateFloor checks the floor imposed on a sweeping tx by its
+// conflicts and unconfirmed ancestors in the mempool.
+func TestMempoolFeeRateFloor(t *testing.T) {
+	t.Parallel()
+
+	const target = chainfee.SatPerKWeight(1000)
+
+	parentA := chainhash.Hash{1}
+	parentB := chainhash.Hash{2}
+	opA := wire.OutPoint{Hash: parentA, Index: 0}
+	opB := wire.OutPoint{Hash: parentB, Index: 1}
+
+	tx, weight := newMempoolTestTx(opA, opB)
+
+	noEntry := fn.None[chainntnfs.MempoolEntry]()
+	noSpend := fn.None[wire.MsgTx]()
+
+	// The pinning tx spends our first input.
+	pin := wire.MsgTx{Version: 2, LockTime: 1}
+	pin.AddTxIn(&wire.TxIn{PreviousOutPoint: opA})
+	pinEntry := chainntnfs.MempoolEntry{
+		Txid:            pin.TxHash(),
+		VSize:           100,
+		Fee:             200,
+		DescendantVSize: 10_000,
+		DescendantFee:   20_000,
+	}
+
+	// The second parent is in the mempool and pays half the target fee
+	// rate together with its ancestors.
+	ancestorWeight := lntypes.VByte(1000).ToWU()
+	parentEntry := chainntnfs.MempoolEntry{
+		Txid:          parentB,
+		VSize:         1000,
+		Fee:           target.FeeForWeight(ancestorWeight) / 2,
+		AncestorVSize: 1000,
+		AncestorFee:   target.FeeForWeight(ancestorWeight) / 2,
+	}
+
+	testCases := []struct {
+		name  string
+		setup func(*chainntnfs.MockMempoolInspector,
+			*chainntnfs.MockMempoolWatcher)
+		expected chainfee.SatPerKWeight
+		err      bool
+	}{
+		{
+			name: "empty mempool",
+			setup: func(i *chainntnfs.MockMempoolInspector,
+				m *chainntnfs.MockMempoolWatcher) {
+
+				m.On("LookupInputMempoolSpend", mock.Anything).
+					Return(noSpend)
+				i.On("LookupMempoolEntry", mock.Anything).
+					Return(noEntry, nil)
+			},
+			expected: 0,
+		},
+		{
+			name: "pinning conflict",
+			setup: func(i *chainntnfs.MockMempoolInspector,
+				m *chainntnfs.MockMempoolWatcher) {
+
+				m.On("LookupInputMempoolSpend", opA).
+					Return(fn.Some(pin))
+				m.On("LookupInputMempoolSpend", opB).
+					Return(noSpend)
+				i.On("LookupMempoolEntry", pin.TxHash()).
+					Return(fn.Some(pinEntry), nil)
+				i.On("LookupMempoolEntry", mock.Anything).
+					Return(noEntry, nil)
+			},
+			expected: replacementFeeRate(pinEntry, weight),
+		},
+		{
+			name: "low fee ancestor",
+			setup: func(i *chainntnfs.MockMempoolInspector,
+				m *chainntnfs.MockMempoolWatcher) {
+
+				m.On("LookupInputMempoolSpend", mock.Anything).
+					Return(noSpend)
+				i.On("LookupMempoolEntry", parentB).
+					Return(fn.Some(parentEntry), nil)
+				i.On("LookupMempoolEntry", mock.Anything).
+					Return(noEntry, nil)
+			},
+			expected: chainfee.NewSatPerKWeight(
+				target.FeeForWeight(weight)+
+					parentEntry.AncestorFee,
+				weight,
+			),
+		},
+		{
+			name: "lookup error",
+			setup: func(i *chainntnfs.MockMempoolInspector,
+				m *chainntnfs.MockMempoolWatcher) {
+
+				m.On("LookupInputMempoolSpend", mock.Anything).
+					Return(noSpend)
+				i.On("LookupMempoolEntry", mock.Anything).
+					Return(noEntry, errDummy)
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			inspector := &chainntnfs.MockMempoolInspector{}
+			mempool := chainntnfs.NewMockMempoolWatcher()
+			tc.setup(inspector, mempool)
+
+			floor, err := mempoolFeeRateFloor(
+				inspector, mempool, tx, target,
+			)
+			if tc.err {
+				require.ErrorIs(t, err, errDummy)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, floor)
+		})
+	}
+}
+
+// TestRaiseToMempoolFloor checks that the fee function is incremented until
+// it reaches the mempool floor, and left untouched without an inspector.
+func TestRaiseToMempoolFloor(t *testing.T) {
+	t.Parallel()
+
+	op := wire.OutPoint{Hash: chainhash.Hash{1}}
+	tx, _ := newMempoolTestTx(op)
+
+	pin := wire.MsgTx{Version: 2, LockTime: 1}
+	pin.AddTxIn(&wire.TxIn{PreviousOutPoint: op})
+
+	tp, m := createTestPublisher(t)
+
+	// Without an inspector, the fee function isn't consulted.
+	require.False(t, tp.raiseToMempoolFloor(tx, m.feeFunc))
+
+	inspector := &chainntnfs.MockMempoolInspector{}
+	mempool := chainntnfs.NewMockMempoolWatcher()
+	tp.cfg.MempoolInspector = fn.Some[chainntnfs.MempoolInspector](
+		inspector,
+	)
+	tp.cfg.Mempool = mempool
+
+	mempool.On("LookupInputMempoolSpend", op).Return(fn.Some(pin))
+	inspector.On("LookupMempoolEntry", pin.TxHash()).Return(
+		fn.Some(chainntnfs.MempoolEntry{
+			VSize:         100,
+			Fee:           500,
+			DescendantFee: 500,
+		}), nil,
+	)
+	inspector.On("LookupMempoolEntry", op.Hash).Return(
+		fn.None[chainntnfs.MempoolEntry](), nil,
+	)
+
+	// The fee function starts below the floor, and is incremented twice
+	// to reach it.
+	m.feeFunc.On("FeeRate").Return(chainfee.SatPerKWeight(1000)).Times(3)
+	m.feeFunc.On("Increment").Return(true, nil).Twice()
+	m.feeFunc.On("FeeRate").Return(chainfee.SatPerKWeight(100_000)).Once()
+
+	require.True(t, tp.raiseToMempoolFloor(tx, m.feeFunc))
+}
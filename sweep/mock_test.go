@@ -1,9 +1,12 @@
 package sweep
 
 import (
+	"time"
+
 	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wtxmgr"
 	"github.com/lightningnetwork/lnd/fn"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
@@ -204,6 +207,24 @@ func (m *MockWallet) GetTransactionDetails(txHash *chainhash.Hash) (
 	return args.Get(0).(*lnwallet.TransactionDetail), args.Error(1)
 }
 
+// LeaseOutput leases a target output, rendering it unusable for coin
+// selection.
+func (m *MockWallet) LeaseOutput(id wtxmgr.LockID, op wire.OutPoint,
+	duration time.Duration) (time.Time, error) {
+
+	args := m.Called(id, op, duration)
+
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+// ReleaseOutput releases a target output, allowing it to be used for coin
+// selection once again.
+func (m *MockWallet) ReleaseOutput(id wtxmgr.LockID, op wire.OutPoint) error {
+	args := m.Called(id, op)
+
+	return args.Error(0)
+}
+
 // MockInputSet is a mock implementation of the InputSet interface.
 type MockInputSet struct {
 	mock.Mock
@@ -247,6 +268,18 @@ func (m *MockInputSet) NeedWalletInput() bool {
 	return args.Bool(0)
 }
 
+// WalletInputs returns the outpoints of the wallet inputs that were added to
+// the set.
+func (m *MockInputSet) WalletInputs() []wire.OutPoint {
+	args := m.Called()
+
+	if args.Get(0) == nil {
+		return nil
+	}
+
+	return args.Get(0).([]wire.OutPoint)
+}
+
 // DeadlineHeight returns the deadline height for the set.
 func (m *MockInputSet) DeadlineHeight() int32 {
 	args := m.Called()
@@ -287,6 +320,17 @@ func (m *MockBumper) Broadcast(req *BumpRequest) (<-chan *BumpResult, error) {
 	return args.Get(0).(chan *BumpResult), args.Error(1)
 }
 
+// PendingBumps returns the state of the monitored txns.
+func (m *MockBumper) PendingBumps() ([]*BumpState, error) {
+	args := m.Called()
+
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]*BumpState), args.Error(1)
+}
+
 // MockFeeFunction is a mock implementation of the FeeFunction interface.
 type MockFeeFunction struct {
 	mock.Mock
@@ -316,6 +360,13 @@ func (m *MockFeeFunction) IncreaseFeeRate(confTarget uint32) (bool, error) {
 	return args.Bool(0), args.Error(1)
 }
 
+// State returns a snapshot of the fee function's state.
+func (m *MockFeeFunction) State() FeeFunctionState {
+	args := m.Called()
+
+	return args.Get(0).(FeeFunctionState)
+}
+
 type MockAuxSweeper struct {
 	mock.Mock
 }
@@ -356,3 +407,22 @@ func (*MockAuxSweeper) NotifyBroadcast(_ *BumpRequest, _ *wire.MsgTx,
 
 	return nil
 }
+
+// MockPackageSubmitter is a mock implementation of the PackageSubmitter
+// interface.
+type MockPackageSubmitter struct {
+	mock.Mock
+}
+
+// Compile-time constraint to ensure MockPackageSubmitter implements
+// lnwallet.PackageSubmitter.
+var _ lnwallet.PackageSubmitter = (*MockPackageSubmitter)(nil)
+
+// SubmitPackage submits the given parents together with their child.
+func (m *MockPackageSubmitter) SubmitPackage(parents []*wire.MsgTx,
+	child *wire.MsgTx) error {
+
+	args := m.Called(parents, child)
+
+	return args.Error(0)
+}
@@ -0,0 +1,260 @@
+package sweep
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/fn"
+)
+
+// maxSpendConflicts is the number of most recent conflicts that are kept by
+// the SpendArbiter.
+const maxSpendConflicts = 100
+
+// ErrSpendConflict is returned when a subsystem attempts to broadcast a tx
+// that spends an outpoint which another subsystem already spends.
+var ErrSpendConflict = errors.New("outpoint already spent by other subsystem")
+
+// SpendSource identifies the subsystem that spends an outpoint.
+type SpendSource uint8
+
+const (
+	// SpendSourceSweeper is the source of the txns published by the
+	// sweeper.
+	SpendSourceSweeper SpendSource = iota
+
+	// SpendSourceContractCourt is the source of the txns published by the
+	// contract court, such as commitments, HTLC txns and justice txns.
+	SpendSourceContractCourt
+
+	// SpendSourceUser is the source of the txns published by the user,
+	// such as finalized PSBTs.
+	SpendSourceUser
+)
+
+// String returns a human readable name of the spend source.
+func (s SpendSource) String() string {
+	switch s {
+	case SpendSourceSweeper:
+		return "sweeper"
+
+	case SpendSourceContractCourt:
+		return "contractcourt"
+
+	case SpendSourceUser:
+		return "user"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// SpendClaim describes the intent of a subsystem to spend an outpoint.
+type SpendClaim struct {
+	// OutPoint is the outpoint that is spent.
+	OutPoint wire.OutPoint
+
+	// Source is the subsystem that spends the outpoint.
+	Source SpendSource
+
+	// Txids are the txns of the subsystem that spend the outpoint. There
+	// may be more than one if the subsystem replaced its spending tx.
+	Txids []chainhash.Hash
+}
+
+// SpendConflict describes a broadcast that was rejected because it spent an
+// outpoint that was already claimed by another subsystem.
+type SpendConflict struct {
+	// OutPoint is the outpoint that both subsystems attempted to spend.
+	OutPoint wire.OutPoint
+
+	// Claimant is the subsystem that already spent the outpoint.
+	Claimant SpendSource
+
+	// Source is the subsystem whose broadcast was rejected.
+	Source SpendSource
+
+	// Txid is the hash of the rejected tx.
+	Txid chainhash.Hash
+
+	// Timestamp is the time at which the broadcast was rejected.
+	Timestamp time.Time
+}
+
+// PublishTxFunc is a function that publishes a tx with the given label.
+type PublishTxFunc func(tx *wire.MsgTx, label string) error
+
+// spendClaim is the internal state of a claimed outpoint.
+type spendClaim struct {
+	source SpendSource
+	txids  fn.Set[chainhash.Hash]
+}
+
+// SpendArbiter keeps track of which subsystem spends each outpoint of our
+// wallet and channels, and rejects the broadcast of txns that would spend an
+// outpoint that's already spent by another subsystem. This prevents lnd from
+// double spending its own outputs, such as anchors that are swept by both the
+// sweeper and a user PSBT.
+//
+// A subsystem may replace its own spends, and the claims of a tx are kept
+// until the tx is released, which is done once it's confirmed, replaced or
+// has failed.
+type SpendArbiter struct {
+	mu sync.Mutex
+
+	// claims maps the claimed outpoints to the subsystem spending them.
+	claims map[wire.OutPoint]*spendClaim
+
+	// conflicts are the most recent rejected broadcasts.
+	conflicts []SpendConflict
+}
+
+// NewSpendArbiter creates a new SpendArbiter.
+func NewSpendArbiter() *SpendArbiter {
+	return &SpendArbiter{
+		claims: make(map[wire.OutPoint]*spendClaim),
+	}
+}
+
+// Claim claims the inputs of the given tx for the given subsystem. If any of
+// the inputs is already claimed by another subsystem for a different tx, none
+// of the inputs are claimed, the conflict is recorded and ErrSpendConflict is
+// returned.
+func (a *SpendArbiter) Claim(source SpendSource, tx *wire.MsgTx) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	txid := tx.TxHash()
+
+	var conflicts []SpendConflict
+	for _, txIn := range tx.TxIn {
+		op := txIn.PreviousOutPoint
+
+		claim, ok := a.claims[op]
+		if !ok || claim.source == source || claim.txids.Contains(txid) {
+			continue
+		}
+
+		conflicts = append(conflicts, SpendConflict{
+			OutPoint:  op,
+			Claimant:  claim.source,
+			Source:    source,
+			Txid:      txid,
+			Timestamp: time.Now(),
+		})
+	}
+
+	if len(conflicts) != 0 {
+		a.conflicts = append(a.conflicts, conflicts...)
+		if len(a.conflicts) > maxSpendConflicts {
+			a.conflicts = a.conflicts[len(a.conflicts)-
+				maxSpendConflicts:]
+		}
+
+		log.Warnf("Rejected tx %v from %v: outpoint %v is spent by %v",
+			txid, source, conflicts[0].OutPoint,
+			conflicts[0].Claimant)
+
+		return fmt.Errorf("%w: outpoint %v is spent by %v",
+			ErrSpendConflict, conflicts[0].OutPoint,
+			conflicts[0].Claimant)
+	}
+
+	for _, txIn := range tx.TxIn {
+		op := txIn.PreviousOutPoint
+
+		claim, ok := a.claims[op]
+		if !ok {
+			claim = &spendClaim{
+				source: source,
+				txids:  fn.NewSet[chainhash.Hash](),
+			}
+			a.claims[op] = claim
+		}
+
+		claim.txids.Add(txid)
+	}
+
+	return nil
+}
+
+// Release releases the claims of the given tx. Outpoints that aren't spent by
+// any other tx of the claiming subsystem become available to all subsystems.
+func (a *SpendArbiter) Release(txid chainhash.Hash) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for op, claim := range a.claims {
+		claim.txids.Remove(txid)
+
+		if claim.txids.IsEmpty() {
+			delete(a.claims, op)
+		}
+	}
+}
+
+// PublishFunc wraps the given publish function so that the inputs of the
+// published txns are claimed for the given subsystem. If the tx fails to be
+// published, its claims are released again.
+func (a *SpendArbiter) PublishFunc(source SpendSource,
+	publish PublishTxFunc) PublishTxFunc {
+
+	return func(tx *wire.MsgTx, label string) error {
+		if err := a.Claim(source, tx); err != nil {
+			return err
+		}
+
+		if err := publish(tx, label); err != nil {
+			a.Release(tx.TxHash())
+
+			return err
+		}
+
+		return nil
+	}
+}
+
+// Claims returns the current claims, ordered by outpoint.
+func (a *SpendArbiter) Claims() []SpendClaim {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	claims := make([]SpendClaim, 0, len(a.claims))
+	for op, claim := range a.claims {
+		txids := make([]chainhash.Hash, 0, len(claim.txids))
+		for txid := range claim.txids {
+			txids = append(txids, txid)
+		}
+		sort.Slice(txids, func(i, j int) bool {
+			return txids[i].String() < txids[j].String()
+		})
+
+		claims = append(claims, SpendClaim{
+			OutPoint: op,
+			Source:   claim.source,
+			Txids:    txids,
+		})
+	}
+
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].OutPoint.String() < claims[j].OutPoint.String()
+	})
+
+	return claims
+}
+
+// Conflicts returns the most recent rejected broadcasts, oldest first.
+func (a *SpendArbiter) Conflicts() []SpendConflict {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	conflicts := make([]SpendConflict, len(a.conflicts))
+	copy(conflicts, a.conflicts)
+
+	return conflicts
+}
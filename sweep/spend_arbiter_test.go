@@ -0,0 +1,116 @@
+package sweep
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// newArbiterTestTx returns a tx spending the given outpoints. The lock time is
+// used to make the txid unique.
+func newArbiterTestTx(lockTime uint32, ops ...wire.OutPoint) *wire.MsgTx {
+	tx := &wire.MsgTx{LockTime: lockTime}
+	for _, op := range ops {
+		tx.AddTxIn(&wire.TxIn{PreviousOutPoint: op})
+	}
+
+	return tx
+}
+
+// TestSpendArbiterClaim checks that a subsystem can replace its own spends,
+// while conflicting spends of other subsystems are rejected and recorded until
+// the claims are released.
+func TestSpendArbiterClaim(t *testing.T) {
+	t.Parallel()
+
+	a := NewSpendArbiter()
+
+	op1 := wire.OutPoint{Hash: chainhash.Hash{1}}
+	op2 := wire.OutPoint{Hash: chainhash.Hash{2}}
+
+	// The sweeper claims the first outpoint.
+	sweepTx := newArbiterTestTx(1, op1)
+	require.NoError(t, a.Claim(SpendSourceSweeper, sweepTx))
+
+	// The sweeper can replace its own tx.
+	replacementTx := newArbiterTestTx(2, op1)
+	require.NoError(t, a.Claim(SpendSourceSweeper, replacementTx))
+
+	// The same tx can be published by another subsystem.
+	require.NoError(t, a.Claim(SpendSourceContractCourt, sweepTx))
+
+	// A user tx spending both outpoints conflicts with the sweeper, and
+	// none of its inputs are claimed.
+	userTx := newArbiterTestTx(3, op1, op2)
+	err := a.Claim(SpendSourceUser, userTx)
+	require.ErrorIs(t, err, ErrSpendConflict)
+
+	require.Equal(t, []SpendClaim{{
+		OutPoint: op1,
+		Source:   SpendSourceSweeper,
+		Txids: sortedTxids(
+			sweepTx.TxHash(), replacementTx.TxHash(),
+		),
+	}}, a.Claims())
+
+	conflicts := a.Conflicts()
+	require.Len(t, conflicts, 1)
+	require.Equal(t, op1, conflicts[0].OutPoint)
+	require.Equal(t, SpendSourceSweeper, conflicts[0].Claimant)
+	require.Equal(t, SpendSourceUser, conflicts[0].Source)
+	require.Equal(t, userTx.TxHash(), conflicts[0].Txid)
+
+	// Releasing one of the sweeper's txns keeps the outpoint claimed.
+	a.Release(sweepTx.TxHash())
+	require.ErrorIs(t, a.Claim(SpendSourceUser, userTx), ErrSpendConflict)
+
+	// Once all of them are released, the user tx can be published.
+	a.Release(replacementTx.TxHash())
+	require.Empty(t, a.Claims())
+	require.NoError(t, a.Claim(SpendSourceUser, userTx))
+	require.Len(t, a.Claims(), 2)
+}
+
+// TestSpendArbiterPublishFunc checks that the wrapped publish function claims
+// the inputs of the published txns, and releases them if publishing fails.
+func TestSpendArbiterPublishFunc(t *testing.T) {
+	t.Parallel()
+
+	a := NewSpendArbiter()
+
+	var publishErr error
+	publish := a.PublishFunc(
+		SpendSourceContractCourt,
+		func(*wire.MsgTx, string) error {
+			return publishErr
+		},
+	)
+
+	op := wire.OutPoint{Hash: chainhash.Hash{1}}
+
+	// A failed publish doesn't claim the inputs.
+	publishErr = errDummy
+	require.ErrorIs(t, publish(newArbiterTestTx(1, op), ""), errDummy)
+	require.Empty(t, a.Claims())
+
+	// A successful publish claims the inputs, so the sweeper cannot spend
+	// them anymore.
+	publishErr = nil
+	require.NoError(t, publish(newArbiterTestTx(1, op), ""))
+	require.ErrorIs(
+		t, a.Claim(SpendSourceSweeper, newArbiterTestTx(2, op)),
+		ErrSpendConflict,
+	)
+}
+
+// sortedTxids returns the given txids in the order used by Claims.
+func sortedTxids(txids ...chainhash.Hash) []chainhash.Hash {
+	sort.Slice(txids, func(i, j int) bool {
+		return txids[i].String() < txids[j].String()
+	})
+
+	return txids
+}
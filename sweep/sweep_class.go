@@ -0,0 +1,118 @@
+package sweep
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+)
+
+// SweepClass categorizes the inputs offered to the sweeper by the kind of
+// output they spend. It's used to configure the behavior of the sweeper per
+// kind of output, such as whether wallet inputs may be added to pay the fees.
+type SweepClass uint8
+
+const (
+	// SweepClassAnchor is the class of anchor outputs.
+	SweepClassAnchor SweepClass = iota
+
+	// SweepClassHtlc is the class of the outputs of HTLCs, both on the
+	// commitment and on the second-level txns.
+	SweepClassHtlc
+
+	// SweepClassCommitment is the class of the to_local and to_remote
+	// outputs of the commitment.
+	SweepClassCommitment
+
+	// SweepClassOther is the class of all other outputs.
+	SweepClassOther
+)
+
+// String returns a human readable name of the sweep class.
+func (c SweepClass) String() string {
+	switch c {
+	case SweepClassAnchor:
+		return "anchor"
+
+	case SweepClassHtlc:
+		return "htlc"
+
+	case SweepClassCommitment:
+		return "commitment"
+
+	case SweepClassOther:
+		return "other"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(c))
+	}
+}
+
+// ParseSweepClass returns the sweep class with the given name.
+func ParseSweepClass(name string) (SweepClass, error) {
+	for _, c := range []SweepClass{
+		SweepClassAnchor, SweepClassHtlc, SweepClassCommitment,
+		SweepClassOther,
+	} {
+		if c.String() == name {
+			return c, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown sweep class: %v", name)
+}
+
+// classifyInput returns the sweep class of the given input based on its
+// witness type.
+func classifyInput(inp input.Input) SweepClass {
+	switch inp.WitnessType() {
+	case input.CommitmentAnchor,
+		input.TaprootAnchorSweepSpend:
+
+		return SweepClassAnchor
+
+	case input.HtlcOfferedTimeoutSecondLevel,
+		input.HtlcOfferedTimeoutSecondLevelInputConfirmed,
+		input.HtlcAcceptedSuccessSecondLevel,
+		input.HtlcAcceptedSuccessSecondLevelInputConfirmed,
+		input.HtlcOfferedRemoteTimeout,
+		input.HtlcAcceptedRemoteSuccess,
+		input.LeaseHtlcOfferedTimeoutSecondLevel,
+		input.LeaseHtlcAcceptedSuccessSecondLevel,
+		input.TaprootHtlcOfferedTimeoutSecondLevel,
+		input.TaprootHtlcAcceptedSuccessSecondLevel,
+		input.TaprootHtlcOfferedRemoteTimeout,
+		input.TaprootHtlcLocalOfferedTimeout,
+		input.TaprootHtlcAcceptedRemoteSuccess,
+		input.TaprootHtlcAcceptedLocalSuccess:
+
+		return SweepClassHtlc
+
+	case input.CommitmentTimeLock,
+		input.CommitmentNoDelay,
+		input.CommitSpendNoDelayTweakless,
+		input.CommitmentToRemoteConfirmed,
+		input.LeaseCommitmentTimeLock,
+		input.LeaseCommitmentToRemoteConfirmed,
+		input.TaprootLocalCommitSpend,
+		input.TaprootRemoteCommitSpend:
+
+		return SweepClassCommitment
+
+	default:
+		return SweepClassOther
+	}
+}
+
+// walletInputsAllowed returns true if wallet inputs may be added to the given
+// input set. This is the case unless any of its inputs belongs to a sweep
+// class that's configured to never use wallet inputs.
+func walletInputsAllowed(set InputSet, disabled fn.Set[SweepClass]) bool {
+	for _, inp := range set.Inputs() {
+		if disabled.Contains(classifyInput(inp)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,56 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseSweepClass checks that the names of the sweep classes are parsed
+// back to the classes.
+func TestParseSweepClass(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range []SweepClass{
+		SweepClassAnchor, SweepClassHtlc, SweepClassCommitment,
+		SweepClassOther,
+	} {
+		parsed, err := ParseSweepClass(c.String())
+		require.NoError(t, err)
+		require.Equal(t, c, parsed)
+	}
+
+	_, err := ParseSweepClass("unknown")
+	require.Error(t, err)
+}
+
+// TestWalletInputsAllowed checks that wallet inputs are only allowed for input
+// sets that don't contain inputs of a disabled sweep class.
+func TestWalletInputsAllowed(t *testing.T) {
+	t.Parallel()
+
+	anchor := &input.MockInput{}
+	defer anchor.AssertExpectations(t)
+	anchor.On("WitnessType").Return(input.CommitmentAnchor)
+
+	htlc := &input.MockInput{}
+	defer htlc.AssertExpectations(t)
+	htlc.On("WitnessType").Return(input.HtlcOfferedRemoteTimeout)
+
+	set := &MockInputSet{}
+	defer set.AssertExpectations(t)
+	set.On("Inputs").Return([]input.Input{anchor, htlc})
+
+	// Without any disabled classes, wallet inputs are allowed.
+	require.True(t, walletInputsAllowed(set, nil))
+
+	// Disabling a class that's not in the set doesn't matter.
+	disabled := fn.NewSet(SweepClassCommitment)
+	require.True(t, walletInputsAllowed(set, disabled))
+
+	// Disabling the class of any of the inputs disallows wallet inputs.
+	disabled.Add(SweepClassHtlc)
+	require.False(t, walletInputsAllowed(set, disabled))
+}
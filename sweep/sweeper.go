@@ -16,6 +16,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnutils"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 )
 
 var (
@@ -40,6 +41,11 @@ var (
 	// DefaultDeadlineDelta defines a default deadline delta (1 week) to be
 	// used when sweeping inputs with no deadline pressure.
 	DefaultDeadlineDelta = int32(1008)
+
+	// DefaultBudgetEscalationDelta defines the number of blocks before its
+	// deadline from which the budget of an input is raised towards its
+	// MaxBudget.
+	DefaultBudgetEscalationDelta = int32(12)
 )
 
 // Params contains the parameters that control the sweeping process.
@@ -57,6 +63,12 @@ type Params struct {
 	// fees for this sweep.
 	Budget btcutil.Amount
 
+	// MaxBudget optionally specifies the budget that the input is allowed
+	// to spend once its deadline is reached. When set, the budget is
+	// raised from Budget towards MaxBudget over the last
+	// DefaultBudgetEscalationDelta blocks before the deadline.
+	MaxBudget btcutil.Amount
+
 	// Immediate indicates that the input should be swept immediately
 	// without waiting for blocks to come to trigger the sweeping of
 	// inputs.
@@ -80,8 +92,38 @@ func (p Params) String() string {
 	}
 
 	return fmt.Sprintf("startingFeeRate=%v, immediate=%v, "+
-		"exclusive_group=%v, budget=%v, deadline=%v", p.StartingFeeRate,
-		p.Immediate, exclusiveGroup, p.Budget, deadline)
+		"exclusive_group=%v, budget=%v, max_budget=%v, deadline=%v",
+		p.StartingFeeRate, p.Immediate, exclusiveGroup, p.Budget,
+		p.MaxBudget, deadline)
+}
+
+// EscalateBudget returns the budget to use for an input whose deadline is
+// blocksLeft blocks away. The budget is raised linearly from the given budget
+// to maxBudget over the last DefaultBudgetEscalationDelta blocks, so the full
+// maxBudget is available once the deadline is reached.
+func EscalateBudget(budget, maxBudget btcutil.Amount,
+	blocksLeft int32) btcutil.Amount {
+
+	// Exit early if there's nothing to escalate to.
+	if maxBudget <= budget {
+		return budget
+	}
+
+	switch {
+	case blocksLeft >= DefaultBudgetEscalationDelta:
+		return budget
+
+	case blocksLeft <= 0:
+		return maxBudget
+	}
+
+	// Calculate how far we are into the escalation window and raise the
+	// budget accordingly.
+	elapsed := DefaultBudgetEscalationDelta - blocksLeft
+	increase := (maxBudget - budget) * btcutil.Amount(elapsed) /
+		btcutil.Amount(DefaultBudgetEscalationDelta)
+
+	return budget + increase
 }
 
 // SweepState represents the current state of a pending input.
@@ -196,10 +238,19 @@ type SweeperInput struct {
 	// rbf records the RBF constraints.
 	rbf fn.Option[RBFInfo]
 
+	// initialBudget is the budget the input was offered with. When the
+	// budget is escalated, the escalated value is stored in the params
+	// while this value is kept as the starting point.
+	initialBudget btcutil.Amount
+
 	// DeadlineHeight is the deadline height for this input. This is
 	// different from the DeadlineHeight in its params as it's an actual
 	// value than an option.
 	DeadlineHeight int32
+
+	// batchStart is the height at which the input could first be swept,
+	// from which its batch window is counted.
+	batchStart fn.Option[int32]
 }
 
 // String returns a human readable interpretation of the pending input.
@@ -258,6 +309,56 @@ type PendingInputResponse struct {
 
 	// DeadlineHeight records the deadline height of this input.
 	DeadlineHeight uint32
+
+	// Bump is the state of the published tx that sweeps this input, which
+	// includes the state of its fee function and a preview of its next
+	// RBF step.
+	Bump fn.Option[BumpState]
+}
+
+// ParamsOverride holds the sweep parameters of a pending input that are
+// overridden at runtime. Parameters that aren't set are left unchanged.
+type ParamsOverride struct {
+	// Budget overrides the budget of the input. This also disables the
+	// escalation of the budget.
+	Budget fn.Option[btcutil.Amount]
+
+	// DeadlineHeight overrides the deadline height of the input.
+	DeadlineHeight fn.Option[int32]
+
+	// ExclusiveGroup moves the input into the given exclusive group.
+	ExclusiveGroup fn.Option[uint64]
+
+	// RemoveExclusiveGroup removes the input from its exclusive group, so
+	// it can be batched with any other input.
+	RemoveExclusiveGroup bool
+
+	// Immediate indicates that the input should be swept immediately
+	// using the overridden parameters.
+	Immediate bool
+}
+
+// apply returns the given params with the overrides applied.
+func (o ParamsOverride) apply(p Params) Params {
+	o.Budget.WhenSome(func(budget btcutil.Amount) {
+		p.Budget = budget
+		p.MaxBudget = 0
+	})
+
+	o.DeadlineHeight.WhenSome(func(height int32) {
+		p.DeadlineHeight = fn.Some(height)
+	})
+
+	o.ExclusiveGroup.WhenSome(func(group uint64) {
+		p.ExclusiveGroup = &group
+	})
+	if o.RemoveExclusiveGroup {
+		p.ExclusiveGroup = nil
+	}
+
+	p.Immediate = o.Immediate
+
+	return p
 }
 
 // updateReq is an internal message we'll use to represent an external caller's
@@ -266,6 +367,10 @@ type updateReq struct {
 	input        wire.OutPoint
 	params       Params
 	responseChan chan *updateResp
+
+	// override, if set, is applied to the current params of the input
+	// instead of replacing them with the given params.
+	override fn.Option[ParamsOverride]
 }
 
 // updateResp is an internal message we'll use to hand off the response of a
@@ -294,6 +399,10 @@ type UtxoSweeper struct {
 	// callers who wish to bump the fee rate of a given input.
 	updateReqs chan *updateReq
 
+	// sweepNowReqs is a channel that will be sent requests by external
+	// callers who wish to sweep the given inputs right away.
+	sweepNowReqs chan *sweepNowReq
+
 	// inputs is the total set of inputs the UtxoSweeper has been requested
 	// to sweep.
 	inputs InputsMap
@@ -362,6 +471,15 @@ type UtxoSweeperConfig struct {
 	// NoDeadlineConfTarget is the conf target to use when sweeping
 	// non-time-sensitive outputs.
 	NoDeadlineConfTarget uint32
+
+	// NoWalletInputs is the set of sweep classes whose inputs are never
+	// swept together with wallet inputs, even if their own value cannot
+	// cover the fees.
+	NoWalletInputs fn.Set[SweepClass]
+
+	// BatchWindows defines how long the inputs of each urgency class are
+	// held back so they can be batched with other inputs.
+	BatchWindows BatchWindows
 }
 
 // Result is the struct that is pushed through the result channel. Callers can
@@ -393,6 +511,7 @@ func New(cfg *UtxoSweeperConfig) *UtxoSweeper {
 		spendChan:         make(chan *chainntnfs.SpendDetail),
 		updateReqs:        make(chan *updateReq),
 		pendingSweepsReqs: make(chan *pendingSweepsReq),
+		sweepNowReqs:      make(chan *sweepNowReq),
 		quit:              make(chan struct{}),
 		inputs:            make(InputsMap),
 		bumpResultChan:    make(chan *BumpResult, 100),
@@ -449,6 +568,9 @@ func (s *UtxoSweeper) Start() error {
 					err: ErrSweeperShuttingDown,
 				}
 
+			case req := <-s.sweepNowReqs:
+				req.errChan <- ErrSweeperShuttingDown
+
 			case <-s.quit:
 				return
 			}
@@ -681,6 +803,11 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 				s.sweepPendingInputs(inputs)
 			}
 
+		// A new external request has been received to sweep the given
+		// inputs right away.
+		case req := <-s.sweepNowReqs:
+			req.errChan <- s.handleSweepNowReq(req)
+
 		case result := <-s.bumpResultChan:
 			// Handle the bump event.
 			err := s.handleBumpEvent(result)
@@ -857,7 +984,7 @@ func (s *UtxoSweeper) sweep(set InputSet) error {
 	// subscribing to the result chan and listen for future updates about
 	// this tx.
 	s.wg.Add(1)
-	go s.monitorFeeBumpResult(resp)
+	go s.monitorFeeBumpResult(resp, set.WalletInputs())
 
 	return nil
 }
@@ -1037,14 +1164,34 @@ func (s *UtxoSweeper) PendingInputs() (
 		return nil, ErrSweeperShuttingDown
 	}
 
+	var pendingSweeps map[wire.OutPoint]*PendingInputResponse
 	select {
-	case pendingSweeps := <-respChan:
-		return pendingSweeps, nil
+	case pendingSweeps = <-respChan:
 	case err := <-errChan:
 		return nil, err
 	case <-s.quit:
 		return nil, ErrSweeperShuttingDown
 	}
+
+	// Attach the state of the txns sweeping the inputs. This is queried
+	// outside of the main event loop, as the publisher may be waiting on
+	// the loop to deliver its results.
+	bumps, err := s.cfg.Publisher.PendingBumps()
+	if err != nil {
+		return nil, err
+	}
+	for _, bump := range bumps {
+		for _, op := range bump.Inputs {
+			resp, ok := pendingSweeps[op]
+			if !ok {
+				continue
+			}
+
+			resp.Bump = fn.Some(*bump)
+		}
+	}
+
+	return pendingSweeps, nil
 }
 
 // handlePendingSweepsReq handles a request to retrieve all pending inputs the
@@ -1111,6 +1258,39 @@ func (s *UtxoSweeper) UpdateParams(input wire.OutPoint,
 	}
 }
 
+// OverrideParams overrides the sweep parameters of a pending input at runtime.
+// Only the parameters set in the override are changed, and the input is swept
+// again using the new parameters.
+func (s *UtxoSweeper) OverrideParams(input wire.OutPoint,
+	override ParamsOverride) (chan Result, error) {
+
+	if override.ExclusiveGroup.IsSome() && override.RemoveExclusiveGroup {
+		return nil, errors.New("cannot both set and remove the " +
+			"exclusive group")
+	}
+
+	responseChan := make(chan *updateResp, 1)
+	select {
+	case s.updateReqs <- &updateReq{
+		input: input,
+		params: Params{
+			Immediate: override.Immediate,
+		},
+		responseChan: responseChan,
+		override:     fn.Some(override),
+	}:
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+
+	select {
+	case response := <-responseChan:
+		return response.resultChan, response.err
+	case <-s.quit:
+		return nil, ErrSweeperShuttingDown
+	}
+}
+
 // handleUpdateReq handles an update request by simply updating the sweep
 // parameters of the pending input. Currently, no validation is done on the new
 // fee preference to ensure it will properly create a replacement transaction.
@@ -1141,14 +1321,21 @@ func (s *UtxoSweeper) handleUpdateReq(req *updateReq) (
 		StartingFeeRate: req.params.StartingFeeRate,
 		Immediate:       req.params.Immediate,
 		Budget:          req.params.Budget,
+		MaxBudget:       req.params.MaxBudget,
 		DeadlineHeight:  req.params.DeadlineHeight,
 		ExclusiveGroup:  sweeperInput.params.ExclusiveGroup,
 	}
 
+	// If this is an override, only the overridden parameters are changed.
+	req.override.WhenSome(func(o ParamsOverride) {
+		newParams = o.apply(sweeperInput.params)
+	})
+
 	log.Debugf("Updating parameters for %v(state=%v) from (%v) to (%v)",
 		req.input, sweeperInput.state, sweeperInput.params, newParams)
 
 	sweeperInput.params = newParams
+	sweeperInput.initialBudget = newParams.Budget
 
 	// We need to reset the state so this input will be attempted again by
 	// our sweeper.
@@ -1157,7 +1344,7 @@ func (s *UtxoSweeper) handleUpdateReq(req *updateReq) (
 	sweeperInput.state = Init
 
 	// If the new input specifies a deadline, update the deadline height.
-	sweeperInput.DeadlineHeight = req.params.DeadlineHeight.UnwrapOr(
+	sweeperInput.DeadlineHeight = newParams.DeadlineHeight.UnwrapOr(
 		sweeperInput.DeadlineHeight,
 	)
 
@@ -1220,6 +1407,8 @@ func (s *UtxoSweeper) handleNewInput(input *sweepInputMessage) error {
 		Input:     input.input,
 		params:    input.params,
 		rbf:       rbfInfo,
+
+		initialBudget: input.params.Budget,
 		// Set the acutal deadline height.
 		DeadlineHeight: input.params.DeadlineHeight.UnwrapOr(
 			defaultDeadline,
@@ -1337,6 +1526,7 @@ func (s *UtxoSweeper) handleExistingInput(input *sweepInputMessage,
 	// Update input details and sweep parameters. The re-offered input
 	// details may contain a change to the unconfirmed parent tx info.
 	oldInput.params = input.params
+	oldInput.initialBudget = input.params.Budget
 	oldInput.Input = input.input
 
 	// If the new input specifies a deadline, update the deadline height.
@@ -1498,6 +1688,13 @@ func (s *UtxoSweeper) updateSweeperInputs() InputsMap {
 			continue
 		}
 
+		// Raise the budget of the input if its deadline is close. If
+		// the input was already published, we reset its state so it's
+		// swept again using the new budget.
+		if s.escalateInputBudget(input) && input.state == Published {
+			input.state = Init
+		}
+
 		// If this input has already been published, we will need to
 		// check the RBF condition before attempting another sweeping.
 		if input.state == Published {
@@ -1526,6 +1723,12 @@ func (s *UtxoSweeper) updateSweeperInputs() InputsMap {
 			continue
 		}
 
+		// If the input is still in its batch window, we hold it back
+		// so it can be batched with inputs offered later.
+		if !s.batchWindowOver(input) {
+			continue
+		}
+
 		// If this input is new or has been failed to be published,
 		// we'd retry it. The assumption here is that when an error is
 		// returned from `PublishTransaction`, it means the tx has
@@ -1536,6 +1739,27 @@ func (s *UtxoSweeper) updateSweeperInputs() InputsMap {
 	return inputs
 }
 
+// escalateInputBudget raises the budget of the given input based on the blocks
+// left until its deadline. It returns a boolean to indicate whether the budget
+// was raised.
+func (s *UtxoSweeper) escalateInputBudget(pi *SweeperInput) bool {
+	budget := EscalateBudget(
+		pi.initialBudget, pi.params.MaxBudget,
+		pi.DeadlineHeight-s.currentHeight,
+	)
+	if budget <= pi.params.Budget {
+		return false
+	}
+
+	log.Infof("Escalating budget of input %v from %v to %v, deadline=%v, "+
+		"current_height=%v", pi, pi.params.Budget, budget,
+		pi.DeadlineHeight, s.currentHeight)
+
+	pi.params.Budget = budget
+
+	return true
+}
+
 // sweepPendingInputs is called when the ticker fires. It will create clusters
 // and attempt to create and publish the sweeping transactions.
 func (s *UtxoSweeper) sweepPendingInputs(inputs InputsMap) {
@@ -1553,9 +1777,21 @@ func (s *UtxoSweeper) sweepPendingInputs(inputs InputsMap) {
 				return err
 			}
 
-			// Create sweeping transaction for each set.
+			// The sweeping tx is published by the publisher after
+			// the coin selection lock is released, so we lease the
+			// wallet inputs to keep them from being selected
+			// elsewhere in the meantime.
+			err = s.leaseWalletInputs(set.WalletInputs())
+			if err != nil {
+				return err
+			}
+
+			// Create sweeping transaction for each set. If this
+			// fails, the wallet inputs can be used again.
 			err = s.sweep(set)
 			if err != nil {
+				s.releaseWalletInputs(set.WalletInputs())
+
 				return err
 			}
 
@@ -1565,13 +1801,23 @@ func (s *UtxoSweeper) sweepPendingInputs(inputs InputsMap) {
 
 	for _, set := range sets {
 		var err error
-		if set.NeedWalletInput() {
-			// Sweep the set of inputs that need the wallet inputs.
-			err = sweepWithLock(set)
-		} else {
-			// Sweep the set of inputs that don't need the wallet
-			// inputs.
+		switch {
+		// Sweep the set of inputs that don't need the wallet inputs.
+		case !set.NeedWalletInput():
+			err = s.sweep(set)
+
+		// Sweep the set of inputs that need the wallet inputs but
+		// belong to a sweep class that isn't allowed to use them. The
+		// publisher will fail the sweep if the budget cannot be
+		// covered.
+		case !walletInputsAllowed(set, s.cfg.NoWalletInputs):
+			log.Debugf("Wallet inputs are disabled for %v", set)
+
 			err = s.sweep(set)
+
+		// Sweep the set of inputs that need the wallet inputs.
+		default:
+			err = sweepWithLock(set)
 		}
 
 		if err != nil {
@@ -1580,11 +1826,48 @@ func (s *UtxoSweeper) sweepPendingInputs(inputs InputsMap) {
 	}
 }
 
+// leaseWalletInputs leases the given wallet inputs so they aren't used by
+// other coin selections. If any of the leases fails, the wallet inputs that
+// were already leased are released again.
+func (s *UtxoSweeper) leaseWalletInputs(ops []wire.OutPoint) error {
+	for i, op := range ops {
+		_, err := s.cfg.Wallet.LeaseOutput(
+			chanfunding.LndInternalLockID, op,
+			chanfunding.DefaultLockDuration,
+		)
+		if err != nil {
+			s.releaseWalletInputs(ops[:i])
+
+			return fmt.Errorf("lease wallet input %v: %w", op, err)
+		}
+
+		log.Debugf("Leased wallet input %v for sweeping", op)
+	}
+
+	return nil
+}
+
+// releaseWalletInputs releases the leases of the given wallet inputs.
+func (s *UtxoSweeper) releaseWalletInputs(ops []wire.OutPoint) {
+	for _, op := range ops {
+		err := s.cfg.Wallet.ReleaseOutput(
+			chanfunding.LndInternalLockID, op,
+		)
+		if err != nil {
+			log.Warnf("Unable to release wallet input %v: %v", op,
+				err)
+		}
+	}
+}
+
 // monitorFeeBumpResult subscribes to the passed result chan to listen for
-// future updates about the sweeping tx.
+// future updates about the sweeping tx. The leases of the given wallet inputs
+// used by the tx are released if the tx fails.
 //
 // NOTE: must run as a goroutine.
-func (s *UtxoSweeper) monitorFeeBumpResult(resultChan <-chan *BumpResult) {
+func (s *UtxoSweeper) monitorFeeBumpResult(resultChan <-chan *BumpResult,
+	walletInputs []wire.OutPoint) {
+
 	defer s.wg.Done()
 
 	for {
@@ -1620,6 +1903,12 @@ func (s *UtxoSweeper) monitorFeeBumpResult(resultChan <-chan *BumpResult) {
 				// Cancel the rebroadcasting of the failed tx.
 				s.cfg.Wallet.CancelRebroadcast(r.Tx.TxHash())
 
+				// The wallet inputs of a failed tx can be
+				// used again.
+				if r.Event == TxFailed {
+					s.releaseWalletInputs(walletInputs)
+				}
+
 				return
 			}
 
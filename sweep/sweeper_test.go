@@ -14,6 +14,7 @@ import (
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -450,7 +451,7 @@ func TestUpdateSweeperInputs(t *testing.T) {
 	require := require.New(t)
 
 	// Create a test sweeper.
-	s := New(nil)
+	s := New(&UtxoSweeperConfig{})
 
 	// Create mock inputs.
 	inp1 := &input.MockInput{}
@@ -543,6 +544,222 @@ func TestUpdateSweeperInputs(t *testing.T) {
 	require.Equal(expectedInputs, s.inputs)
 }
 
+// TestEscalateBudget checks that the budget is raised linearly towards the max
+// budget over the last blocks before the deadline.
+func TestEscalateBudget(t *testing.T) {
+	t.Parallel()
+
+	const (
+		budget    = btcutil.Amount(1_000)
+		maxBudget = btcutil.Amount(13_000)
+	)
+
+	testCases := []struct {
+		name       string
+		maxBudget  btcutil.Amount
+		blocksLeft int32
+		expected   btcutil.Amount
+	}{
+		{
+			name:       "no max budget",
+			maxBudget:  0,
+			blocksLeft: 0,
+			expected:   budget,
+		},
+		{
+			name:       "max budget below budget",
+			maxBudget:  budget - 1,
+			blocksLeft: 0,
+			expected:   budget,
+		},
+		{
+			name:       "deadline far away",
+			maxBudget:  maxBudget,
+			blocksLeft: DefaultBudgetEscalationDelta + 1,
+			expected:   budget,
+		},
+		{
+			name:       "start of escalation",
+			maxBudget:  maxBudget,
+			blocksLeft: DefaultBudgetEscalationDelta,
+			expected:   budget,
+		},
+		{
+			name:       "half way",
+			maxBudget:  maxBudget,
+			blocksLeft: DefaultBudgetEscalationDelta / 2,
+			expected:   7_000,
+		},
+		{
+			name:       "deadline reached",
+			maxBudget:  maxBudget,
+			blocksLeft: 0,
+			expected:   maxBudget,
+		},
+		{
+			name:       "deadline passed",
+			maxBudget:  maxBudget,
+			blocksLeft: -1,
+			expected:   maxBudget,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.expected, EscalateBudget(
+				budget, tc.maxBudget, tc.blocksLeft,
+			))
+		})
+	}
+}
+
+// TestUpdateSweeperInputsEscalateBudget checks that `updateSweeperInputs`
+// raises the budget of inputs close to their deadline, and retries the
+// published ones with the new budget.
+func TestUpdateSweeperInputsEscalateBudget(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	// Create a test sweeper.
+	s := New(&UtxoSweeperConfig{})
+	s.currentHeight = 100
+
+	inp := &input.MockInput{}
+	defer inp.AssertExpectations(t)
+
+	inp.On("OutPoint").Return(wire.OutPoint{}).Maybe()
+	inp.On("WitnessType").Return(input.CommitmentAnchor).Maybe()
+	inp.On("RequiredLockTime").Return(uint32(0), false)
+	inp.On("BlocksToMaturity").Return(uint32(0))
+	inp.On("HeightHint").Return(uint32(0))
+
+	params := Params{
+		Budget:    1_000,
+		MaxBudget: 13_000,
+	}
+
+	// The first input has a deadline far away so its budget stays the
+	// same.
+	input0 := &SweeperInput{
+		state:          Published,
+		Input:          inp,
+		params:         params,
+		initialBudget:  params.Budget,
+		DeadlineHeight: s.currentHeight + 100,
+	}
+
+	// The second input is half way into the escalation window, and is
+	// expected to be swept again using the escalated budget.
+	input1 := &SweeperInput{
+		state:          Published,
+		Input:          inp,
+		params:         params,
+		initialBudget:  params.Budget,
+		DeadlineHeight: s.currentHeight + 6,
+	}
+
+	s.inputs = map[wire.OutPoint]*SweeperInput{
+		{Index: 0}: input0,
+		{Index: 1}: input1,
+	}
+
+	inputs := s.updateSweeperInputs()
+	require.Equal(map[wire.OutPoint]*SweeperInput{
+		{Index: 1}: input1,
+	}, inputs)
+
+	require.Equal(Published, input0.state)
+	require.Equal(params.Budget, input0.params.Budget)
+
+	require.Equal(Init, input1.state)
+	require.Equal(btcutil.Amount(7_000), input1.params.Budget)
+
+	// Updating the inputs again at the same height leaves the budget
+	// untouched.
+	input1.state = Published
+	s.updateSweeperInputs()
+	require.Equal(Published, input1.state)
+	require.Equal(btcutil.Amount(7_000), input1.params.Budget)
+}
+
+// TestHandleUpdateReqOverride checks that an override only changes the
+// overridden params of an input and leaves the others untouched.
+func TestHandleUpdateReqOverride(t *testing.T) {
+	t.Parallel()
+
+	require := require.New(t)
+
+	// Create a test sweeper.
+	s := New(nil)
+
+	group := uint64(1)
+	params := Params{
+		StartingFeeRate: fn.Some(chainfee.SatPerKWeight(1000)),
+		Budget:          1_000,
+		MaxBudget:       2_000,
+		DeadlineHeight:  fn.Some(int32(100)),
+		ExclusiveGroup:  &group,
+	}
+
+	op := wire.OutPoint{Index: 1}
+	pi := &SweeperInput{
+		state:          Published,
+		params:         params,
+		initialBudget:  params.Budget,
+		DeadlineHeight: 100,
+	}
+	s.inputs = map[wire.OutPoint]*SweeperInput{op: pi}
+
+	// Updating an unknown input returns an error.
+	_, err := s.handleUpdateReq(&updateReq{
+		input:    wire.OutPoint{Index: 2},
+		override: fn.Some(ParamsOverride{}),
+	})
+	require.ErrorIs(err, lnwallet.ErrNotMine)
+
+	// Override the budget and the deadline.
+	resultChan, err := s.handleUpdateReq(&updateReq{
+		input: op,
+		override: fn.Some(ParamsOverride{
+			Budget:         fn.Some(btcutil.Amount(5_000)),
+			DeadlineHeight: fn.Some(int32(50)),
+			Immediate:      true,
+		}),
+	})
+	require.NoError(err)
+	require.NotNil(resultChan)
+
+	// The overridden budget disables the escalation, while the other
+	// params are kept.
+	require.Equal(Params{
+		StartingFeeRate: params.StartingFeeRate,
+		Immediate:       true,
+		Budget:          5_000,
+		DeadlineHeight:  fn.Some(int32(50)),
+		ExclusiveGroup:  &group,
+	}, pi.params)
+	require.Equal(btcutil.Amount(5_000), pi.initialBudget)
+	require.Equal(int32(50), pi.DeadlineHeight)
+	require.Equal(Init, pi.state)
+	require.Len(pi.listeners, 1)
+
+	// Removing the exclusive group leaves the rest unchanged.
+	_, err = s.handleUpdateReq(&updateReq{
+		input: op,
+		override: fn.Some(ParamsOverride{
+			RemoveExclusiveGroup: true,
+		}),
+	})
+	require.NoError(err)
+	require.Nil(pi.params.ExclusiveGroup)
+	require.False(pi.params.Immediate)
+	require.Equal(btcutil.Amount(5_000), pi.params.Budget)
+	require.Equal(int32(50), pi.DeadlineHeight)
+}
+
 // TestDecideStateAndRBFInfo checks that the expected state and RBFInfo are
 // returned based on whether this input can be found both in mempool and the
 // sweeper store.
@@ -688,6 +905,15 @@ func TestSweepPendingInputs(t *testing.T) {
 	setNeedWallet.On("NeedWalletInput").Return(true).Once()
 	setNeedWallet.On("AddWalletInputs", wallet).Return(nil).Once()
 
+	// Mock the set to have added a wallet input, which is leased and then
+	// released again as the broadcast fails.
+	walletOp := wire.OutPoint{Index: 1}
+	setNeedWallet.On("WalletInputs").Return([]wire.OutPoint{walletOp})
+	wallet.On("LeaseOutput", chanfunding.LndInternalLockID, walletOp,
+		chanfunding.DefaultLockDuration).Return(time.Time{}, nil).Once()
+	wallet.On("ReleaseOutput", chanfunding.LndInternalLockID,
+		walletOp).Return(nil).Once()
+
 	// Mock the wallet to require the lock once.
 	wallet.On("WithCoinSelectLock", mock.Anything).Return(nil).Once()
 
@@ -1085,7 +1311,7 @@ func TestMonitorFeeBumpResult(t *testing.T) {
 
 			s.wg.Add(1)
 			go func() {
-				s.monitorFeeBumpResult(resultChan)
+				s.monitorFeeBumpResult(resultChan, nil)
 				close(done)
 			}()
 
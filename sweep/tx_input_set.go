@@ -43,6 +43,10 @@ type InputSet interface {
 	// inputs.
 	NeedWalletInput() bool
 
+	// WalletInputs returns the outpoints of the wallet inputs that were
+	// added to the set.
+	WalletInputs() []wire.OutPoint
+
 	// DeadlineHeight returns an absolute block height to express the
 	// time-sensitivity of the input set. The outputs from a force close tx
 	// have different time preferences:
@@ -117,6 +121,10 @@ type BudgetInputSet struct {
 	// transaction, for example to cover fees for additional outputs of
 	// custom channels.
 	extraBudget btcutil.Amount
+
+	// walletInputs are the outpoints of the wallet inputs that have been
+	// added to the set.
+	walletInputs []wire.OutPoint
 }
 
 // Compile-time constraint to ensure budgetInputSet implements InputSet.
@@ -332,6 +340,7 @@ func (b *BudgetInputSet) AddWalletInputs(wallet Wallet) error {
 	// utxos to cover the budget, we will revert the current set to its
 	// original state by removing the added wallet inputs.
 	originalInputs := b.copyInputs()
+	originalWalletInputs := b.walletInputs
 
 	// Add wallet inputs to the set until the specified budget is covered.
 	for _, utxo := range utxos {
@@ -347,6 +356,7 @@ func (b *BudgetInputSet) AddWalletInputs(wallet Wallet) error {
 			},
 		}
 		b.addInput(pi)
+		b.walletInputs = append(b.walletInputs, utxo.OutPoint)
 
 		log.Debugf("Added wallet input to input set: op=%v, amt=%v",
 			pi.OutPoint(), utxo.Value)
@@ -360,10 +370,19 @@ func (b *BudgetInputSet) AddWalletInputs(wallet Wallet) error {
 	// The wallet doesn't have enough utxos to cover the budget. Revert the
 	// input set to its original state.
 	b.inputs = originalInputs
+	b.walletInputs = originalWalletInputs
 
 	return ErrNotEnoughInputs
 }
 
+// WalletInputs returns the outpoints of the wallet inputs that were added to
+// the set.
+//
+// NOTE: part of the InputSet interface.
+func (b *BudgetInputSet) WalletInputs() []wire.OutPoint {
+	return b.walletInputs
+}
+
 // Budget returns the total budget of the set.
 //
 // NOTE: part of the InputSet interface.
@@ -443,6 +443,7 @@ func TestAddWalletInputNotEnoughInputs(t *testing.T) {
 	// Check that the budget set is reverted to its initial state.
 	require.Len(t, set.inputs, 1)
 	require.Equal(t, pi, set.inputs[0])
+	require.Empty(t, set.WalletInputs())
 }
 
 // TestAddWalletInputSuccess checks that when there are enough wallet utxos,
@@ -516,6 +517,12 @@ func TestAddWalletInputSuccess(t *testing.T) {
 	input3Deadline := set.inputs[2].params.DeadlineHeight
 	require.Equal(t, deadline, input3Deadline.UnsafeFromSome())
 
+	// Both wallet inputs are reported so they can be leased.
+	require.Equal(
+		t, []wire.OutPoint{utxo.OutPoint, utxo.OutPoint},
+		set.WalletInputs(),
+	)
+
 	// Finally, check the interface methods.
 	require.EqualValues(t, budget, set.Budget())
 	require.Equal(t, deadline, set.DeadlineHeight())
@@ -38,9 +38,7 @@ func (w *weightEstimator) add(inp input.Input) error {
 	// If there is a parent tx, add the parent's fee and weight.
 	w.tryAddParent(inp)
 
-	wt := inp.WitnessType()
-
-	return wt.AddWeightEstimation(&w.estimator)
+	return input.AddInputWeightEstimation(&w.estimator, inp)
 }
 
 // tryAddParent examines the input and updates parent tx totals if required for
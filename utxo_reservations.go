@@ -0,0 +1,73 @@
+package lnd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// ListUtxoReservations returns the wallet outputs that are currently locked
+// for the funding transactions of pending channels, together with the time
+// their lock expires.
+func (r *rpcServer) ListUtxoReservations(_ context.Context,
+	_ *lnrpc.ListUtxoReservationsRequest) (
+	*lnrpc.ListUtxoReservationsResponse, error) {
+
+	resp := &lnrpc.ListUtxoReservationsResponse{}
+	for _, res := range r.server.fundingMgr.UtxoReservations() {
+		resp.Reservations = append(
+			resp.Reservations, marshallUtxoReservation(res),
+		)
+	}
+
+	return resp, nil
+}
+
+// marshallUtxoReservation converts a funding UTXO reservation to its RPC
+// representation.
+func marshallUtxoReservation(
+	res *funding.UtxoReservation) *lnrpc.UtxoReservation {
+
+	rpcRes := &lnrpc.UtxoReservation{
+		PendingChanId: res.PendingChanID[:],
+		Expiry:        res.Expiry.Unix(),
+	}
+
+	if res.Peer != nil {
+		rpcRes.PeerPubkey = res.Peer.SerializeCompressed()
+	}
+
+	for i := range res.OutPoints {
+		outPoint := lnrpc.MarshalOutPoint(&res.OutPoints[i])
+		rpcRes.Outpoints = append(rpcRes.Outpoints, outPoint)
+	}
+
+	return rpcRes
+}
+
+// ReleaseUtxoReservation releases the wallet outputs locked for the funding
+// transaction of the given pending channel, failing its funding flow if it is
+// still in progress.
+func (r *rpcServer) ReleaseUtxoReservation(_ context.Context,
+	req *lnrpc.ReleaseUtxoReservationRequest) (
+	*lnrpc.ReleaseUtxoReservationResponse, error) {
+
+	if len(req.PendingChanId) != 32 {
+		return nil, fmt.Errorf("pending channel ID must be 32 bytes, "+
+			"got %d", len(req.PendingChanId))
+	}
+
+	var id [32]byte
+	copy(id[:], req.PendingChanId)
+
+	err := r.server.fundingMgr.ReleaseUtxoReservation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("Funding inputs of pending_chan_id=%x released", id[:])
+
+	return &lnrpc.ReleaseUtxoReservationResponse{}, nil
+}
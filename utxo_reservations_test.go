@@ -0,0 +1,46 @@
+package lnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/funding"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMarshallUtxoReservation tests that the locked outputs of a funding UTXO
+// reservation are returned over RPC, and that the peer is left empty for a
+// reservation whose funding flow is no longer tracked.
+func TestMarshallUtxoReservation(t *testing.T) {
+	t.Parallel()
+
+	peer, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	res := &funding.UtxoReservation{
+		PendingChanID: funding.PendingChanID{1},
+		Peer:          peer.PubKey(),
+		OutPoints: []wire.OutPoint{
+			{Hash: chainhash.Hash{2}, Index: 0},
+			{Hash: chainhash.Hash{3}, Index: 1},
+		},
+		Expiry: time.Unix(1_700_000_000, 0),
+	}
+
+	rpcRes := marshallUtxoReservation(res)
+	require.Equal(t, res.PendingChanID[:], rpcRes.PendingChanId)
+	require.Equal(t, peer.PubKey().SerializeCompressed(), rpcRes.PeerPubkey)
+	require.EqualValues(t, 1_700_000_000, rpcRes.Expiry)
+	require.Len(t, rpcRes.Outpoints, 2)
+	require.Equal(
+		t, res.OutPoints[1].Hash.String(), rpcRes.Outpoints[1].TxidStr,
+	)
+	require.EqualValues(t, 1, rpcRes.Outpoints[1].OutputIndex)
+
+	res.Peer = nil
+	rpcRes = marshallUtxoReservation(res)
+	require.Empty(t, rpcRes.PeerPubkey)
+}
@@ -3,6 +3,8 @@ package watchtower
 import (
 	"strconv"
 	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/lookout"
 )
 
 // Conf specifies the watchtower options that can be configured from the command
@@ -21,13 +23,26 @@ type Conf struct {
 	// WriteTimeout specifies the duration the tower will wait when trying
 	// to write a message from a client before hanging up.
 	WriteTimeout time.Duration `long:"writetimeout" description:"Duration the watchtower server will wait for messages to be written before hanging up on client connections"`
+
+	// MaxSessionUpdates limits the number of state updates a client may
+	// negotiate for a single session.
+	MaxSessionUpdates uint16 `long:"maxsessionupdates" description:"The maximum number of state updates a client may negotiate for a single session, 0 means no limit"`
+
+	// MaxStorage limits the storage the tower reserves for the sessions
+	// of its clients.
+	MaxStorage uint64 `long:"maxstorage" description:"The maximum number of bytes of state updates the watchtower reserves for the sessions of its clients, 0 means no limit"`
+
+	// JusticeConfTarget is the number of blocks within which the tower
+	// aims to confirm its justice transactions.
+	JusticeConfTarget uint32 `long:"justiceconftarget" description:"The number of blocks within which the watchtower aims to confirm its justice transactions. If the fee rate signed by the client is too low, the transaction is fee bumped by spending the tower's reward output"`
 }
 
 // DefaultConf returns a Conf with some default values filled in.
 func DefaultConf() *Conf {
 	return &Conf{
-		ReadTimeout:  DefaultReadTimeout,
-		WriteTimeout: DefaultWriteTimeout,
+		ReadTimeout:       DefaultReadTimeout,
+		WriteTimeout:      DefaultWriteTimeout,
+		JusticeConfTarget: lookout.DefaultJusticeConfTarget,
 	}
 }
 
@@ -94,5 +109,23 @@ func (c *Conf) Apply(cfg *Config,
 		cfg.WriteTimeout = c.WriteTimeout
 	}
 
+	// If the Config has no session quotas, we will use the parsed Conf
+	// values.
+	if cfg.MaxSessionUpdates == 0 {
+		cfg.MaxSessionUpdates = c.MaxSessionUpdates
+	}
+	if cfg.MaxStorage == 0 {
+		cfg.MaxStorage = c.MaxStorage
+	}
+
+	// If the Config has no justice confirmation target, we will use the
+	// parsed Conf value, falling back to the default.
+	if cfg.JusticeConfTarget == 0 {
+		cfg.JusticeConfTarget = c.JusticeConfTarget
+	}
+	if cfg.JusticeConfTarget == 0 {
+		cfg.JusticeConfTarget = lookout.DefaultJusticeConfTarget
+	}
+
 	return cfg, nil
 }
@@ -9,8 +9,10 @@ import (
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/watchtower/lookout"
+	"github.com/lightningnetwork/lnd/watchtower/wtserver"
 )
 
 const (
@@ -74,6 +76,19 @@ type Config struct {
 	// have stronger guarantees wrt. returned error types.
 	PublishTx func(*wire.MsgTx, string) error
 
+	// FeeEstimator is used to check whether the fee rate of a published
+	// justice transaction is sufficient to confirm in time.
+	FeeEstimator chainfee.Estimator
+
+	// JusticeConfTarget is the number of blocks within which the tower
+	// aims to confirm its justice transactions.
+	JusticeConfTarget uint32
+
+	// CPFP, if set, is used to bump the fee of justice transactions whose
+	// fee rate is below the one needed to confirm within
+	// JusticeConfTarget, by spending the tower's reward output.
+	CPFP func(*lookout.CPFPRequest) error
+
 	// ListenAddrs specifies the listening addresses of the tower.
 	ListenAddrs []net.Addr
 
@@ -90,6 +105,18 @@ type Config struct {
 	// the server's replies.
 	WriteTimeout time.Duration
 
+	// MaxSessionUpdates is the maximum number of state updates a client
+	// may negotiate for a single session. Zero means no limit.
+	MaxSessionUpdates uint16
+
+	// MaxStorage is the maximum number of bytes of encrypted blobs the
+	// tower reserves for the sessions of its clients. Zero means no limit.
+	MaxStorage uint64
+
+	// AuthorizeSession, if set, is consulted before a new session is
+	// created, and may reject it, e.g. because it wasn't paid for.
+	AuthorizeSession wtserver.SessionAuthorizer
+
 	// TorController allows the watchtower to optionally setup an onion hidden
 	// service.
 	TorController *tor.Controller
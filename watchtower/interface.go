@@ -4,6 +4,7 @@ import (
 	"net"
 
 	"github.com/lightningnetwork/lnd/watchtower/lookout"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 	"github.com/lightningnetwork/lnd/watchtower/wtserver"
 )
 
@@ -13,6 +14,11 @@ import (
 type DB interface {
 	lookout.DB
 	wtserver.DB
+
+	// InsertJusticeReport records that the tower published a justice
+	// transaction for a state update of the given session, such that it
+	// can be reported to the client.
+	InsertJusticeReport(wtdb.SessionID, *wtdb.JusticeReport) error
 }
 
 // AddressNormalizer is a function signature that allows the tower to resolve
@@ -39,6 +39,10 @@ type JusticeDescriptor struct {
 	// the prenegotiated terms they agreed to.
 	SessionInfo *wtdb.SessionInfo
 
+	// SeqNum is the sequence number of the client's state update within
+	// the session.
+	SeqNum uint16
+
 	// JusticeKit contains the decrypted blob and information required to
 	// construct the transaction scripts and witnesses.
 	JusticeKit blob.JusticeKit
@@ -433,17 +433,27 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 	justiceDesc := &lookout.JusticeDescriptor{
 		BreachedCommitTx: breachTxn,
 		SessionInfo:      sessionInfo,
+		SeqNum:           1,
 		JusticeKit:       justiceKit,
 	}
 
 	// Construct a breach punisher that will feed published transactions
-	// over the buffered channel.
+	// over the buffered channel, and that records the justice reports.
 	publications := make(chan *wire.MsgTx, 1)
+	var reports []*wtdb.JusticeReport
 	punisher := lookout.NewBreachPunisher(&lookout.PunisherConfig{
 		PublishTx: func(tx *wire.MsgTx, _ string) error {
 			publications <- tx
 			return nil
 		},
+		ReportJustice: func(id wtdb.SessionID,
+			report *wtdb.JusticeReport) error {
+
+			require.Equal(t, sessionInfo.ID, id)
+			reports = append(reports, report)
+
+			return nil
+		},
 	})
 
 	// Exact retribution on the offender. If no error is returned, we expect
@@ -459,6 +469,13 @@ func testJusticeDescriptor(t *testing.T, blobType blob.Type) {
 		t.Fatalf("punisher did not publish justice txn")
 	}
 
+	// The published justice transaction should have been reported.
+	require.Equal(t, []*wtdb.JusticeReport{{
+		SeqNum:      1,
+		BreachTxID:  breachTxn.TxHash(),
+		JusticeTxID: wtJusticeTxn.TxHash(),
+	}}, reports)
+
 	if isTaprootChannel {
 		revokeLeaf := txscript.NewBaseTapLeaf(toLocalScript)
 		outputKey := txscript.ComputeTaprootOutputKey(
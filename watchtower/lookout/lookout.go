@@ -228,6 +228,7 @@ func (l *Lookout) processEpoch(epoch *chainntnfs.BlockEpoch,
 		justiceDesc := &JusticeDescriptor{
 			BreachedCommitTx: commitTx,
 			SessionInfo:      match.SessionInfo,
+			SeqNum:           match.SeqNum,
 			JusticeKit:       justiceKit,
 		}
 		successes = append(successes, justiceDesc)
@@ -1,16 +1,70 @@
 package lookout
 
 import (
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 )
 
+// DefaultJusticeConfTarget is the default number of blocks within which the
+// tower aims to confirm its justice transactions.
+const DefaultJusticeConfTarget = 6
+
+// CPFPRequest describes a justice transaction whose fee rate is too low to
+// confirm in time, and the tower's reward output that can be spent to bump
+// it.
+type CPFPRequest struct {
+	// JusticeTx is the published justice transaction.
+	JusticeTx *wire.MsgTx
+
+	// RewardOutPoint is the outpoint of the tower's reward output in the
+	// justice transaction.
+	RewardOutPoint wire.OutPoint
+
+	// RewardOutput is the tower's reward output in the justice
+	// transaction.
+	RewardOutput *wire.TxOut
+
+	// Fee is the fee paid by the justice transaction.
+	Fee btcutil.Amount
+
+	// Weight is the weight of the justice transaction.
+	Weight lntypes.WeightUnit
+
+	// ConfTarget is the number of blocks within which the justice
+	// transaction should confirm.
+	ConfTarget uint32
+}
+
 // PunisherConfig houses the resources required by the Punisher.
 type PunisherConfig struct {
 	// PublishTx provides the ability to send a signed transaction to the
 	// network.
 	PublishTx func(*wire.MsgTx, string) error
 
+	// FeeEstimator is used to check whether the fee rate of a justice
+	// transaction is sufficient to confirm within ConfTarget blocks. If
+	// nil, justice transactions are never fee bumped.
+	FeeEstimator chainfee.Estimator
+
+	// ConfTarget is the number of blocks within which the tower aims to
+	// confirm its justice transactions.
+	ConfTarget uint32
+
+	// CPFP is called to bump the fee of a justice transaction by spending
+	// the tower's reward output, e.g. through the sweeper. If nil, justice
+	// transactions are never fee bumped.
+	CPFP func(*CPFPRequest) error
+
+	// ReportJustice is called after a justice transaction was published,
+	// allowing the tower to report it to the client owning the session.
+	// If nil, justice transactions aren't reported.
+	ReportJustice func(wtdb.SessionID, *wtdb.JusticeReport) error
+
 	// TODO(conner) add DB tracking and spend ntfn registration to see if
 	// ours confirmed or not
 }
@@ -52,8 +106,132 @@ func (p *BreachPunisher) Punish(desc *JusticeDescriptor, quit <-chan struct{}) e
 		return err
 	}
 
+	// The justice transaction was signed by the client with the fee rate
+	// negotiated at session creation, which may no longer be enough to
+	// confirm before the breacher can sweep their outputs. Since the
+	// client signed it with SIGHASH_ALL we can't replace it, so we'll
+	// attempt to bump it by spending our reward output instead. A failure
+	// to do so doesn't affect the published justice transaction.
+	if err := p.maybeBumpFee(desc, justiceTxn); err != nil {
+		log.Errorf("Unable to bump fee of justice txn=%s for "+
+			"client=%s: %v", justiceTxn.TxHash(),
+			desc.SessionInfo.ID, err)
+	}
+
+	// Record the justice transaction so that the client can learn about
+	// the breach, even if it was offline when it happened. A failure to do
+	// so doesn't affect the published justice transaction either.
+	if p.cfg.ReportJustice != nil {
+		err := p.cfg.ReportJustice(desc.SessionInfo.ID,
+			&wtdb.JusticeReport{
+				SeqNum:      desc.SeqNum,
+				BreachTxID:  desc.BreachedCommitTx.TxHash(),
+				JusticeTxID: justiceTxn.TxHash(),
+			},
+		)
+		if err != nil {
+			log.Errorf("Unable to record justice txn=%s for "+
+				"client=%s: %v", justiceTxn.TxHash(),
+				desc.SessionInfo.ID, err)
+		}
+	}
+
 	// TODO(conner): register for spend and remove from db after
 	// confirmation
 
 	return nil
 }
+
+// maybeBumpFee compares the fee rate of the given justice transaction against
+// the fee rate currently needed to confirm within the configured target, and
+// hands the tower's reward output to the CPFP hook if it is too low.
+func (p *BreachPunisher) maybeBumpFee(desc *JusticeDescriptor,
+	justiceTxn *wire.MsgTx) error {
+
+	if p.cfg.FeeEstimator == nil || p.cfg.CPFP == nil {
+		return nil
+	}
+
+	fee, err := justiceFee(desc.BreachedCommitTx, justiceTxn)
+	if err != nil {
+		return err
+	}
+
+	weight := lntypes.WeightUnit(
+		blockchain.GetTransactionWeight(btcutil.NewTx(justiceTxn)),
+	)
+	feeRate := chainfee.NewSatPerKWeight(fee, weight)
+
+	targetFeeRate, err := p.cfg.FeeEstimator.EstimateFeePerKW(
+		p.cfg.ConfTarget,
+	)
+	if err != nil {
+		return err
+	}
+
+	if feeRate >= targetFeeRate {
+		return nil
+	}
+
+	// Without a reward output, there's nothing for us to spend. This is
+	// the case for altruist sessions.
+	rewardPkScript := desc.SessionInfo.RewardAddress
+	rewardIndex := -1
+	if len(rewardPkScript) > 0 {
+		for i, txOut := range justiceTxn.TxOut {
+			if string(txOut.PkScript) == string(rewardPkScript) {
+				rewardIndex = i
+				break
+			}
+		}
+	}
+	if rewardIndex < 0 {
+		log.Warnf("Justice txn=%s for client=%s has fee rate %v below "+
+			"target %v, but no reward output to bump it with",
+			justiceTxn.TxHash(), desc.SessionInfo.ID, feeRate,
+			targetFeeRate)
+
+		return nil
+	}
+
+	log.Infof("Bumping fee of justice txn=%s for client=%s from %v to "+
+		"target %v using its reward output", justiceTxn.TxHash(),
+		desc.SessionInfo.ID, feeRate, targetFeeRate)
+
+	return p.cfg.CPFP(&CPFPRequest{
+		JusticeTx: justiceTxn,
+		RewardOutPoint: wire.OutPoint{
+			Hash:  justiceTxn.TxHash(),
+			Index: uint32(rewardIndex),
+		},
+		RewardOutput: justiceTxn.TxOut[rewardIndex],
+		Fee:          fee,
+		Weight:       weight,
+		ConfTarget:   p.cfg.ConfTarget,
+	})
+}
+
+// justiceFee returns the fee paid by a justice transaction that only spends
+// outputs of the breached commitment transaction.
+func justiceFee(breachTxn, justiceTxn *wire.MsgTx) (btcutil.Amount, error) {
+	breachTxID := breachTxn.TxHash()
+
+	var inputAmt int64
+	for _, txIn := range justiceTxn.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		if prevOut.Hash != breachTxID ||
+			int(prevOut.Index) >= len(breachTxn.TxOut) {
+
+			return 0, ErrOutputNotFound
+		}
+
+		inputAmt += breachTxn.TxOut[prevOut.Index].Value
+	}
+
+	var outputAmt int64
+	for _, txOut := range justiceTxn.TxOut {
+		outputAmt += txOut.Value
+	}
+
+	return btcutil.Amount(inputAmt - outputAmt), nil
+}
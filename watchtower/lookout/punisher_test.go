@@ -0,0 +1,109 @@
+package lookout
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPunisherFeeBump asserts that the punisher only requests a CPFP of a
+// justice transaction if its fee rate is below the target, and that the
+// request spends the tower's reward output.
+func TestPunisherFeeBump(t *testing.T) {
+	t.Parallel()
+
+	var (
+		sweepPkScript  = []byte{0x00, 0x14, 0x01}
+		rewardPkScript = []byte{0x00, 0x14, 0x02}
+	)
+
+	breachTxn := &wire.MsgTx{
+		Version: 2,
+		TxOut: []*wire.TxOut{
+			{Value: 100_000},
+			{Value: 50_000},
+		},
+	}
+	breachTxID := breachTxn.TxHash()
+
+	// The justice transaction spends both outputs of the breach
+	// transaction, leaving a fee of 1000 sats.
+	justiceTxn := &wire.MsgTx{
+		Version: 2,
+		TxIn: []*wire.TxIn{
+			{PreviousOutPoint: wire.OutPoint{Hash: breachTxID}},
+			{PreviousOutPoint: wire.OutPoint{
+				Hash:  breachTxID,
+				Index: 1,
+			}},
+		},
+		TxOut: []*wire.TxOut{
+			{Value: 139_000, PkScript: sweepPkScript},
+			{Value: 10_000, PkScript: rewardPkScript},
+		},
+	}
+
+	fee, err := justiceFee(breachTxn, justiceTxn)
+	require.NoError(t, err)
+	require.EqualValues(t, 1000, fee)
+
+	newDesc := func(rewardAddr []byte) *JusticeDescriptor {
+		return &JusticeDescriptor{
+			BreachedCommitTx: breachTxn,
+			SessionInfo: &wtdb.SessionInfo{
+				RewardAddress: rewardAddr,
+			},
+		}
+	}
+
+	var requests []*CPFPRequest
+	newPunisher := func(feeRate chainfee.SatPerKWeight) *BreachPunisher {
+		return NewBreachPunisher(&PunisherConfig{
+			FeeEstimator: chainfee.NewStaticEstimator(feeRate, 0),
+			ConfTarget:   DefaultJusticeConfTarget,
+			CPFP: func(req *CPFPRequest) error {
+				requests = append(requests, req)
+				return nil
+			},
+		})
+	}
+
+	// A justice transaction paying enough fees isn't bumped.
+	punisher := newPunisher(chainfee.FeePerKwFloor)
+	err = punisher.maybeBumpFee(newDesc(rewardPkScript), justiceTxn)
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	// If the fee rate is too low, but there's no reward output, there's
+	// nothing we can bump the fee with.
+	punisher = newPunisher(100 * chainfee.FeePerKwFloor)
+	err = punisher.maybeBumpFee(newDesc(nil), justiceTxn)
+	require.NoError(t, err)
+	require.Empty(t, requests)
+
+	// Otherwise the reward output is used to bump the fee.
+	err = punisher.maybeBumpFee(newDesc(rewardPkScript), justiceTxn)
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+
+	req := requests[0]
+	require.Equal(t, justiceTxn, req.JusticeTx)
+	require.Equal(t, wire.OutPoint{
+		Hash:  justiceTxn.TxHash(),
+		Index: 1,
+	}, req.RewardOutPoint)
+	require.Equal(t, justiceTxn.TxOut[1], req.RewardOutput)
+	require.Equal(t, btcutil.Amount(1000), req.Fee)
+	require.NotZero(t, req.Weight)
+	require.EqualValues(t, DefaultJusticeConfTarget, req.ConfTarget)
+
+	// A justice transaction spending anything but the breach transaction
+	// is rejected.
+	justiceTxn.TxIn[1].PreviousOutPoint.Index = 2
+	_, err = justiceFee(breachTxn, justiceTxn)
+	require.ErrorIs(t, err, ErrOutputNotFound)
+}
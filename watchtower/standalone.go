@@ -10,6 +10,7 @@ import (
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/watchtower/lookout"
 	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
 )
 
 // Standalone encapsulates the server-side functionality required by watchtower
@@ -56,7 +57,11 @@ func New(cfg *Config) (*Standalone, error) {
 	}
 
 	punisher := lookout.NewBreachPunisher(&lookout.PunisherConfig{
-		PublishTx: cfg.PublishTx,
+		PublishTx:     cfg.PublishTx,
+		FeeEstimator:  cfg.FeeEstimator,
+		ConfTarget:    cfg.JusticeConfTarget,
+		CPFP:          cfg.CPFP,
+		ReportJustice: cfg.DB.InsertJusticeReport,
 	})
 
 	// Initialize the lookout service with its required resources.
@@ -84,14 +89,17 @@ func New(cfg *Config) (*Standalone, error) {
 
 	// Initialize the server with its required resources.
 	server, err := wtserver.New(&wtserver.Config{
-		ChainHash:     cfg.ChainHash,
-		DB:            cfg.DB,
-		NodeKeyECDH:   cfg.NodeKeyECDH,
-		Listeners:     listeners,
-		ReadTimeout:   cfg.ReadTimeout,
-		WriteTimeout:  cfg.WriteTimeout,
-		NewAddress:    cfg.NewAddress,
-		DisableReward: true,
+		ChainHash:         cfg.ChainHash,
+		DB:                cfg.DB,
+		NodeKeyECDH:       cfg.NodeKeyECDH,
+		Listeners:         listeners,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		NewAddress:        cfg.NewAddress,
+		DisableReward:     true,
+		MaxSessionUpdates: cfg.MaxSessionUpdates,
+		MaxStorage:        cfg.MaxStorage,
+		AuthorizeSession:  cfg.AuthorizeSession,
 	})
 	if err != nil {
 		return nil, err
@@ -225,3 +233,23 @@ func (w *Standalone) ExternalIPs() []net.Addr {
 
 	return addrs
 }
+
+// Announcement returns the advertisement of the watchtower that can be added
+// to the node announcement of the node running it. The advertisement contains
+// the tower's external addresses, including its onion address once the tower
+// has been started, and returns nil if the tower has no external addresses.
+func (w *Standalone) Announcement() *wtwire.TowerAnnouncement {
+	addrs := w.ExternalIPs()
+	if len(addrs) == 0 {
+		return nil
+	}
+
+	return &wtwire.TowerAnnouncement{
+		ChainHash:         w.cfg.ChainHash,
+		TowerKey:          w.PubKey(),
+		Addresses:         addrs,
+		Features:          wtserver.LocalFeatures(),
+		MaxSessionUpdates: w.cfg.MaxSessionUpdates,
+		MaxStorage:        w.cfg.MaxStorage,
+	}
+}
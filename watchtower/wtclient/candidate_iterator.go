@@ -2,7 +2,9 @@ package wtclient
 
 import (
 	"container/list"
+	"errors"
 	"net"
+	"sort"
 	"sync"
 
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
@@ -193,4 +195,103 @@ func (t *towerListIterator) IsActive(tower wtdb.TowerID) bool {
 	return ok
 }
 
+// healthRankedIterator is a TowerCandidateIterator that returns the candidates
+// of each pass of the wrapped iterator ordered by their health. Live towers are
+// returned before dead ones, and amongst those, towers with a higher score are
+// returned first. This makes new sessions fail over to healthy towers, while
+// dead towers are still tried as a last resort.
+type healthRankedIterator struct {
+	TowerCandidateIterator
+
+	health *towerHealthTracker
+
+	mu sync.Mutex
+
+	// pass holds the candidates of the current pass that were taken from
+	// the wrapped iterator but not returned yet.
+	pass []*Tower
+}
+
+// Compile-time constraint to ensure *healthRankedIterator implements the
+// TowerCandidateIterator interface.
+var _ TowerCandidateIterator = (*healthRankedIterator)(nil)
+
+// newHealthRankedIterator wraps the given iterator so that its candidates are
+// ranked using the health recorded by the tracker.
+func newHealthRankedIterator(iter TowerCandidateIterator,
+	health *towerHealthTracker) *healthRankedIterator {
+
+	return &healthRankedIterator{
+		TowerCandidateIterator: iter,
+		health:                 health,
+	}
+}
+
+// Reset clears the iterator's state, making previously taken candidates
+// available as long as they remain in the set.
+//
+// NOTE: This is part of the TowerCandidateIterator interface.
+func (h *healthRankedIterator) Reset() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.pass = nil
+
+	return h.TowerCandidateIterator.Reset()
+}
+
+// Next returns the healthiest candidate of the current pass that wasn't
+// returned yet. If no more candidates are available,
+// ErrTowerCandidatesExhausted is returned.
+//
+// NOTE: This is part of the TowerCandidateIterator interface.
+func (h *healthRankedIterator) Next() (*Tower, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Take all remaining candidates of this pass from the wrapped
+	// iterator. Candidates added since the last call are picked up here.
+	for {
+		tower, err := h.TowerCandidateIterator.Next()
+		if errors.Is(err, ErrTowerCandidatesExhausted) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		h.pass = append(h.pass, tower)
+	}
+
+	// Drop any candidates that were removed in the meantime.
+	candidates := h.pass[:0]
+	for _, tower := range h.pass {
+		if h.TowerCandidateIterator.IsActive(tower.ID) {
+			candidates = append(candidates, tower)
+		}
+	}
+	h.pass = candidates
+
+	if len(h.pass) == 0 {
+		return nil, ErrTowerCandidatesExhausted
+	}
+
+	// The health of the towers may have changed since the last call, so
+	// we'll rank the candidates each time.
+	sort.SliceStable(h.pass, func(i, j int) bool {
+		iDead := h.health.isDead(h.pass[i].ID)
+		jDead := h.health.isDead(h.pass[j].ID)
+		if iDead != jDead {
+			return jDead
+		}
+
+		return h.health.score(h.pass[i].ID) >
+			h.health.score(h.pass[j].ID)
+	})
+
+	tower := h.pass[0]
+	h.pass = h.pass[1:]
+
+	return tower, nil
+}
+
 // TODO(conner): implement graph-backed candidate iterator for public towers.
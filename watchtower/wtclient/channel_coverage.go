@@ -0,0 +1,135 @@
+package wtclient
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+)
+
+// ChannelCoverage describes how well the revoked states of a channel are
+// protected by the client's towers.
+type ChannelCoverage struct {
+	// ChanID is the ID of the channel.
+	ChanID lnwire.ChannelID
+
+	// BackupPolicy holds the backup settings of the channel.
+	BackupPolicy wtdb.ChannelBackupPolicy
+
+	// MaxHeight is the highest commitment height that was handed to the
+	// client for backup, if any.
+	MaxHeight fn.Option[uint64]
+
+	// ProtectedRanges are the commitment heights that were acked by a
+	// tower, merged into sorted ranges. States that were handed to the
+	// client but aren't covered by these ranges are still pending or
+	// weren't eligible for backup.
+	ProtectedRanges []wtdb.HeightRange
+}
+
+// NumProtected returns the number of commitment heights that are protected.
+func (c *ChannelCoverage) NumProtected() uint64 {
+	var num uint64
+	for _, r := range c.ProtectedRanges {
+		num += r.End - r.Start + 1
+	}
+
+	return num
+}
+
+// SetChannelBackupPolicy sets the backup settings of a registered channel.
+// Excluding a channel stops the backups of its future states, and a minimum
+// sweep fee rate makes the states of the channel ineligible for backup under
+// sessions that pay less. States that were already queued are subject to the
+// new settings as well.
+func (m *Manager) SetChannelBackupPolicy(id lnwire.ChannelID,
+	policy wtdb.ChannelBackupPolicy) error {
+
+	m.backupMu.Lock()
+	defer m.backupMu.Unlock()
+
+	info, ok := m.chanInfos[id]
+	if !ok {
+		return ErrUnregisteredChannel
+	}
+
+	if err := m.cfg.DB.SetChannelBackupPolicy(id, &policy); err != nil {
+		return err
+	}
+
+	info.BackupPolicy = policy
+
+	log.Infof("Set backup policy of channel %s: excluded=%v, "+
+		"min_sweep_fee_rate=%v", id, policy.Excluded,
+		policy.MinSweepFeeRate)
+
+	return nil
+}
+
+// ChannelCoverage returns the backup settings and the protected commitment
+// heights of the given registered channels, or of all of them if none are
+// given. The channels are returned in the order of their IDs.
+func (m *Manager) ChannelCoverage(chanIDs ...lnwire.ChannelID) (
+	[]*ChannelCoverage, error) {
+
+	m.backupMu.Lock()
+	if len(chanIDs) == 0 {
+		for id := range m.chanInfos {
+			chanIDs = append(chanIDs, id)
+		}
+	}
+
+	coverages := make([]*ChannelCoverage, 0, len(chanIDs))
+	for _, id := range chanIDs {
+		info, ok := m.chanInfos[id]
+		if !ok {
+			m.backupMu.Unlock()
+
+			return nil, ErrUnregisteredChannel
+		}
+
+		coverages = append(coverages, &ChannelCoverage{
+			ChanID:       id,
+			BackupPolicy: info.BackupPolicy,
+			MaxHeight:    info.MaxHeight,
+		})
+	}
+	m.backupMu.Unlock()
+
+	sort.Slice(coverages, func(i, j int) bool {
+		return bytes.Compare(
+			coverages[i].ChanID[:], coverages[j].ChanID[:],
+		) < 0
+	})
+
+	for _, coverage := range coverages {
+		ranges, err := m.cfg.DB.FetchChannelAckedRanges(
+			coverage.ChanID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		coverage.ProtectedRanges = ranges
+	}
+
+	return coverages, nil
+}
+
+// getBackupPolicy returns the backup settings of the given channel. Channels
+// that aren't registered have the default settings.
+func (m *Manager) getBackupPolicy(
+	id lnwire.ChannelID) wtdb.ChannelBackupPolicy {
+
+	m.backupMu.Lock()
+	defer m.backupMu.Unlock()
+
+	info, ok := m.chanInfos[id]
+	if !ok {
+		return wtdb.ChannelBackupPolicy{}
+	}
+
+	return info.BackupPolicy
+}
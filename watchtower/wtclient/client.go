@@ -88,6 +88,10 @@ type RegisteredTower struct {
 	// ActiveSessionCandidate determines whether the watchtower is currently
 	// being considered for new sessions.
 	ActiveSessionCandidate bool
+
+	// Health is the health the client observed for the watchtower since
+	// startup.
+	Health *TowerHealth
 }
 
 // BreachRetributionBuilder is a function that can be used to construct a
@@ -172,6 +176,8 @@ type clientCfg struct {
 	Policy wtpolicy.Policy
 
 	getSweepScript func(lnwire.ChannelID) ([]byte, bool)
+
+	getBackupPolicy func(lnwire.ChannelID) wtdb.ChannelBackupPolicy
 }
 
 // client manages backing up revoked states for all states that fall under a
@@ -191,6 +197,8 @@ type client struct {
 	sessionQueue *sessionQueue
 	prevTask     *wtdb.BackupID
 
+	health *towerHealthTracker
+
 	statTicker *time.Ticker
 	stats      *clientStats
 
@@ -227,6 +235,7 @@ func newClient(cfg *clientCfg) (*client, error) {
 		log:               plog,
 		pipeline:          queue,
 		activeSessions:    newSessionQueueSet(),
+		health:            newTowerHealthTracker(cfg.MaxTowerFailures),
 		statTicker:        time.NewTicker(DefaultStatInterval),
 		stats:             new(clientStats),
 		newTowers:         make(chan *newTowerMsg),
@@ -264,7 +273,9 @@ func newClient(cfg *clientCfg) (*client, error) {
 		return nil, err
 	}
 
-	c.candidateTowers = candidateTowers
+	// New sessions are negotiated with the healthiest towers first, so
+	// that we fail over to other towers if one becomes unresponsive.
+	c.candidateTowers = newHealthRankedIterator(candidateTowers, c.health)
 	c.candidateSessions = candidateSessions
 
 	c.negotiator = newSessionNegotiator(&NegotiatorConfig{
@@ -430,6 +441,11 @@ func (c *client) start() error {
 	c.wg.Add(1)
 	go c.backupDispatcher()
 
+	if c.cfg.HealthCheckInterval > 0 {
+		c.wg.Add(1)
+		go c.healthProber()
+	}
+
 	c.log.Infof("Watchtower client started successfully")
 
 	return nil
@@ -551,9 +567,11 @@ func (c *client) stopAndRemoveSession(id wtdb.SessionID, final bool) error {
 	return c.activeSessions.StopAndRemove(id, final)
 }
 
-// deleteSessionFromTower dials the tower that we created the session with and
-// attempts to send the tower the DeleteSession message.
-func (c *client) deleteSessionFromTower(sess *wtdb.ClientSession) error {
+// loadClientSession returns the ClientSession of the given DB session, along
+// with the tower it was created with.
+func (c *client) loadClientSession(
+	sess *wtdb.ClientSession) (*ClientSession, error) {
+
 	// First, we check if we have already loaded this tower in our
 	// candidate towers iterator.
 	tower, err := c.candidateTowers.GetTower(sess.TowerID)
@@ -561,31 +579,153 @@ func (c *client) deleteSessionFromTower(sess *wtdb.ClientSession) error {
 		// If not, then we attempt to load it from the DB.
 		dbTower, err := c.cfg.DB.LoadTowerByID(sess.TowerID)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		tower, err = NewTowerFromDBTower(dbTower)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else if err != nil {
+		return nil, err
+	}
+
+	return NewClientSessionFromDBSession(sess, tower, c.cfg.SecretKeyRing)
+}
+
+// fetchJusticeReports dials the tower that we created the session with and
+// requests the justice transactions it published for the session. If the
+// tower doesn't report justice transactions, no reports are returned.
+func (c *client) fetchJusticeReports(sess *wtdb.ClientSession) (
+	[]*wtdb.ClientJusticeReport, error) {
+
+	session, err := c.loadClientSession(sess)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, remoteFeatures, err := c.connectToTower(
+		session.SessionKeyECDH, session.Tower,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if !remoteFeatures.HasFeature(wtwire.JusticeReportsOptional) {
+		return nil, nil
+	}
+
+	// Send GetJusticeReports to tower.
+	err = c.sendMessage(conn, &wtwire.GetJusticeReports{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Receive JusticeReports from tower.
+	remoteMsg, err := c.readMessage(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, ok := remoteMsg.(*wtwire.JusticeReports)
+	if !ok {
+		return nil, fmt.Errorf("watchtower %s responded with %T to "+
+			"GetJusticeReports", conn.RemoteAddr(), remoteMsg)
+	}
+
+	if reply.Code != wtwire.CodeOK {
+		return nil, fmt.Errorf("received error code %v in "+
+			"JusticeReports when attempting to fetch the "+
+			"justice reports of session %s", reply.Code, sess.ID)
+	}
+
+	// The tower closes the connection after replying. Since it rejects
+	// concurrent connections for the same session, we wait for it to do
+	// so before the session is used to dial the tower again.
+	err = conn.SetReadDeadline(time.Now().Add(c.cfg.ReadTimeout))
+	if err == nil {
+		_, _ = conn.ReadNextMessage()
+	}
+
+	reports := make([]*wtdb.ClientJusticeReport, 0, len(reply.Reports))
+	for _, r := range reply.Reports {
+		reports = append(reports, &wtdb.ClientJusticeReport{
+			JusticeReport: wtdb.JusticeReport{
+				SeqNum:      r.SeqNum,
+				BreachTxID:  r.BreachTxID,
+				JusticeTxID: r.JusticeTxID,
+			},
+			TowerPubKey: session.Tower.IdentityKey,
+			SessionID:   sess.ID,
+		})
+	}
+
+	return reports, nil
+}
+
+// deleteSessionFromTower dials the tower that we created the session with and
+// attempts to send the tower the DeleteSession message.
+func (c *client) deleteSessionFromTower(sess *wtdb.ClientSession) error {
+	session, err := c.loadClientSession(sess)
+	if err != nil {
 		return err
 	}
 
-	session, err := NewClientSessionFromDBSession(
-		sess, tower, c.cfg.SecretKeyRing,
+	conn, _, err := c.connectToTower(
+		session.SessionKeyECDH, session.Tower,
 	)
 	if err != nil {
 		return err
 	}
+	defer conn.Close()
+
+	// Send DeleteSession to tower.
+	err = c.sendMessage(conn, &wtwire.DeleteSession{})
+	if err != nil {
+		return err
+	}
+
+	// Receive DeleteSessionReply from tower.
+	remoteMsg, err := c.readMessage(conn)
+	if err != nil {
+		return err
+	}
+
+	deleteSessionReply, ok := remoteMsg.(*wtwire.DeleteSessionReply)
+	if !ok {
+		return fmt.Errorf("watchtower %s responded with %T to "+
+			"DeleteSession", conn.RemoteAddr(), remoteMsg)
+	}
+
+	switch deleteSessionReply.Code {
+	case wtwire.CodeOK, wtwire.DeleteSessionCodeNotFound:
+		return nil
+	default:
+		return fmt.Errorf("received error code %v in "+
+			"DeleteSessionReply when attempting to delete "+
+			"session from tower", deleteSessionReply.Code)
+	}
+}
+
+// connectToTower dials the tower at any of its addresses using the given key
+// and exchanges Init messages with it, returning the connection along with the
+// features the tower signaled. The caller is responsible for closing the
+// returned connection.
+func (c *client) connectToTower(localKey keychain.SingleKeyECDH,
+	tower *Tower) (wtserver.Peer, *lnwire.FeatureVector, error) {
 
 	localInit := wtwire.NewInitMessage(
-		lnwire.NewRawFeatureVector(wtwire.AltruistSessionsRequired),
+		lnwire.NewRawFeatureVector(
+			wtwire.AltruistSessionsRequired,
+			wtwire.JusticeReportsOptional,
+		),
 		c.cfg.ChainHash,
 	)
 
 	var (
 		conn wtserver.Peer
+		err  error
 
 		// addrIterator is a copy of the tower's address iterator.
 		// We use this copy so that iterating through the addresses does
@@ -595,12 +735,10 @@ func (c *client) deleteSessionFromTower(sess *wtdb.ClientSession) error {
 	)
 	// Attempt to dial the tower with its available addresses.
 	for {
-		conn, err = c.dial(
-			session.SessionKeyECDH, &lnwire.NetAddress{
-				IdentityKey: tower.IdentityKey,
-				Address:     towerAddr,
-			},
-		)
+		conn, err = c.dial(localKey, &lnwire.NetAddress{
+			IdentityKey: tower.IdentityKey,
+			Address:     towerAddr,
+		})
 		if err != nil {
 			// If there are more addrs available, immediately try
 			// those.
@@ -614,65 +752,47 @@ func (c *client) deleteSessionFromTower(sess *wtdb.ClientSession) error {
 			// exit.
 			addrIterator.Reset()
 
-			return fmt.Errorf("failed to dial tower(%x) at any "+
-				"available addresses",
+			return nil, nil, fmt.Errorf("failed to dial "+
+				"tower(%x) at any available addresses",
 				tower.IdentityKey.SerializeCompressed())
 		}
 
 		break
 	}
-	defer conn.Close()
 
 	// Send Init to tower.
 	err = c.sendMessage(conn, localInit)
 	if err != nil {
-		return err
+		conn.Close()
+		return nil, nil, err
 	}
 
 	// Receive Init from tower.
 	remoteMsg, err := c.readMessage(conn)
 	if err != nil {
-		return err
+		conn.Close()
+		return nil, nil, err
 	}
 
 	remoteInit, ok := remoteMsg.(*wtwire.Init)
 	if !ok {
-		return fmt.Errorf("watchtower %s responded with %T to Init",
-			towerAddr, remoteMsg)
+		conn.Close()
+		return nil, nil, fmt.Errorf("watchtower %s responded with "+
+			"%T to Init", towerAddr, remoteMsg)
 	}
 
 	// Validate Init.
 	err = localInit.CheckRemoteInit(remoteInit, wtwire.FeatureNames)
 	if err != nil {
-		return err
+		conn.Close()
+		return nil, nil, err
 	}
 
-	// Send DeleteSession to tower.
-	err = c.sendMessage(conn, &wtwire.DeleteSession{})
-	if err != nil {
-		return err
-	}
-
-	// Receive DeleteSessionReply from tower.
-	remoteMsg, err = c.readMessage(conn)
-	if err != nil {
-		return err
-	}
-
-	deleteSessionReply, ok := remoteMsg.(*wtwire.DeleteSessionReply)
-	if !ok {
-		return fmt.Errorf("watchtower %s responded with %T to "+
-			"DeleteSession", towerAddr, remoteMsg)
-	}
+	remoteFeatures := lnwire.NewFeatureVector(
+		remoteInit.ConnFeatures, wtwire.FeatureNames,
+	)
 
-	switch deleteSessionReply.Code {
-	case wtwire.CodeOK, wtwire.DeleteSessionCodeNotFound:
-		return nil
-	default:
-		return fmt.Errorf("received error code %v in "+
-			"DeleteSessionReply when attempting to delete "+
-			"session from tower", deleteSessionReply.Code)
-	}
+	return conn, remoteFeatures, nil
 }
 
 // backupDispatcher processes events coming from the taskPipeline and is
@@ -737,6 +857,11 @@ func (c *client) backupDispatcher() {
 			case msg := <-c.terminateSessions:
 				msg.errChan <- c.handleTerminateSession(msg)
 
+			// Some towers died or came back, so we'll update the
+			// set of sessions we can use.
+			case <-c.health.statusChange:
+				c.handleTowerHealthChanges()
+
 			case <-c.quit:
 				return
 			}
@@ -830,6 +955,12 @@ func (c *client) backupDispatcher() {
 			case msg := <-c.terminateSessions:
 				msg.errChan <- c.handleTerminateSession(msg)
 
+			// Some towers died or came back. If the tower of the
+			// active session queue died, we'll fail over to a
+			// session with another tower.
+			case <-c.health.statusChange:
+				c.handleTowerHealthChanges()
+
 			case <-c.quit:
 				return
 			}
@@ -850,6 +981,30 @@ func (c *client) processTask(task *wtdb.BackupID) {
 		log.Infof("not processing task for unregistered channel: %s",
 			task.ChanID)
 
+		c.prevTask = nil
+
+		return
+	}
+
+	chanPolicy := c.cfg.getBackupPolicy(task.ChanID)
+	if chanPolicy.Excluded {
+		c.log.Infof("Not processing %v of excluded channel", task)
+
+		c.prevTask = nil
+
+		return
+	}
+
+	// The sweep fee rate of a justice transaction is fixed by the policy
+	// of the session it's backed up to, so a channel that requires a
+	// higher fee rate can't be backed up under this client's sessions.
+	if chanPolicy.MinSweepFeeRate > c.cfg.Policy.SweepFeeRate {
+		c.log.Infof("Ignoring %v, sweep fee rate %v is below the "+
+			"channel's minimum of %v", task,
+			c.cfg.Policy.SweepFeeRate, chanPolicy.MinSweepFeeRate)
+
+		c.taskRejected(task, sessionQueueAvailable)
+
 		return
 	}
 
@@ -1061,6 +1216,7 @@ func (c *client) newSessionQueue(s *ClientSession,
 		Log:                    c.log,
 		BuildBreachRetribution: c.cfg.BuildBreachRetribution,
 		TaskPipeline:           c.pipeline,
+		Health:                 c.health,
 	}, updates)
 }
 
@@ -1181,6 +1337,7 @@ func (c *client) handleDeactivateTower(msg *deactivateTowerMsg) error {
 	if err != nil {
 		return err
 	}
+	c.health.removeTower(msg.id)
 
 	pubKey := msg.pubKey.SerializeCompressed()
 	sessions, err := c.cfg.DB.ListClientSessions(&msg.id)
@@ -1245,7 +1402,12 @@ func (c *client) addTower(tower *Tower) error {
 func (c *client) handleNewTower(tower *Tower) error {
 	c.candidateTowers.AddCandidate(tower)
 
-	// Include all of its corresponding sessions to our set of candidates.
+	return c.addSessionCandidates(tower)
+}
+
+// addSessionCandidates includes all usable sessions with the given tower in
+// our set of candidate sessions.
+func (c *client) addSessionCandidates(tower *Tower) error {
 	sessions, err := getClientSessions(
 		c.cfg.DB, c.cfg.SecretKeyRing, &tower.ID,
 		wtdb.WithPreEvalFilterFn(c.genSessionFilter(true)),
@@ -1309,6 +1471,7 @@ func (c *client) handleStaleTower(msg *staleTowerMsg) error {
 
 	// Otherwise, the tower should no longer be used for future session
 	// negotiations and backups.
+	c.health.removeTower(msg.id)
 
 	pubKey := msg.pubKey.SerializeCompressed()
 	sessions, err := c.cfg.DB.ListClientSessions(&msg.id)
@@ -1341,6 +1504,201 @@ func (c *client) handleStaleTower(msg *staleTowerMsg) error {
 	return nil
 }
 
+// healthProber periodically probes the candidate towers, so that towers that
+// became unresponsive are detected, and towers that came back are used again,
+// even while we aren't sending any backups to them.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (c *client) healthProber() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.probeTowers()
+
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// probeTowers probes each of the active towers that are candidates for new
+// sessions.
+func (c *client) probeTowers() {
+	towers, err := c.cfg.DB.ListTowers(func(tower *wtdb.Tower) bool {
+		return tower.Status == wtdb.TowerStatusActive
+	})
+	if err != nil {
+		c.log.Errorf("Unable to list towers to probe: %v", err)
+		return
+	}
+
+	for _, dbTower := range towers {
+		select {
+		case <-c.quit:
+			return
+		default:
+		}
+
+		tower, err := c.candidateTowers.GetTower(dbTower.ID)
+		if err != nil {
+			continue
+		}
+
+		c.probeTower(tower)
+	}
+}
+
+// probeTower dials the given tower and exchanges Init messages with it,
+// recording the outcome with the health tracker. A fresh key is used for each
+// probe so that probes can't be linked to any of our sessions.
+func (c *client) probeTower(tower *Tower) {
+	towerPub := tower.IdentityKey.SerializeCompressed()
+
+	privKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		c.log.Errorf("Unable to generate probe key: %v", err)
+		return
+	}
+
+	start := time.Now()
+	conn, _, err := c.connectToTower(
+		&keychain.PrivKeyECDH{PrivKey: privKey}, tower,
+	)
+	if err != nil {
+		c.log.Debugf("Health probe of tower=%x failed: %v", towerPub,
+			err)
+
+		c.health.recordFailure(tower.ID)
+
+		return
+	}
+	conn.Close()
+
+	c.health.recordSuccess(tower.ID, time.Since(start))
+}
+
+// handleTowerHealthChanges handles the towers that died or came back since it
+// was last called.
+func (c *client) handleTowerHealthChanges() {
+	for id, dead := range c.health.statusChanges() {
+		// Towers that are no longer candidates don't have any sessions
+		// in use, so there's nothing to do for them.
+		tower, err := c.candidateTowers.GetTower(id)
+		if err != nil {
+			continue
+		}
+
+		if dead {
+			err = c.handleDeadTower(tower)
+		} else {
+			err = c.handleRecoveredTower(tower)
+		}
+		if err != nil {
+			c.log.Errorf("Unable to handle health change of "+
+				"tower=%x: %v",
+				tower.IdentityKey.SerializeCompressed(), err)
+		}
+	}
+}
+
+// handleDeadTower stops using the sessions with the given tower for new
+// backups, and re-queues the backups that were sent to it according to the
+// configured DeadTowerPolicy. The tower is still considered for new sessions,
+// but only once all other towers have been tried.
+func (c *client) handleDeadTower(tower *Tower) error {
+	c.log.Warnf("Tower=%x is unresponsive, failing over to other towers "+
+		"with dead tower policy %v",
+		tower.IdentityKey.SerializeCompressed(), c.cfg.DeadTowerPolicy)
+
+	// If our active session queue is with the dead tower, we'll proceed to
+	// use a session with another tower.
+	if c.sessionQueue != nil && c.sessionQueue.tower.ID == tower.ID {
+		c.sessionQueue = nil
+	}
+
+	sessions, err := c.cfg.DB.ListClientSessions(
+		&tower.ID, wtdb.WithPreEvalFilterFn(c.genSessionFilter(true)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for id := range sessions {
+		delete(c.candidateSessions, id)
+
+		if err := c.rebackupSession(id); err != nil {
+			return fmt.Errorf("unable to re-back up session %s: "+
+				"%w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// rebackupSession re-queues the backups of a session with a dead tower
+// according to the configured DeadTowerPolicy. Any session whose backups are
+// re-queued is terminated, so that it isn't used again.
+func (c *client) rebackupSession(id wtdb.SessionID) error {
+	switch c.cfg.DeadTowerPolicy {
+	// The backups stay with the dead tower.
+	case DeadTowerRebackupNone:
+		return nil
+
+	// Only sessions with backups that the tower hasn't acked yet have to
+	// be re-queued. Those sessions are always active.
+	case DeadTowerRebackupUnacked:
+		sq, ok := c.activeSessions.Get(id)
+		if !ok || sq.backlog() == 0 {
+			return nil
+		}
+
+		// Stopping the session for the final time replays its un-acked
+		// updates onto the task pipeline.
+		err := c.activeSessions.StopAndRemove(id, true)
+		if err != nil {
+			return err
+		}
+
+	// All backups of the session are re-queued, including the ones that
+	// the tower acked.
+	case DeadTowerRebackupAll:
+		err := c.activeSessions.StopAndRemove(id, true)
+		if err != nil {
+			return err
+		}
+
+		backupIDs, err := c.cfg.DB.FetchAckedUpdates(&id)
+		if err != nil {
+			return err
+		}
+
+		for i := range backupIDs {
+			err := c.pipeline.QueueBackupID(&backupIDs[i])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	c.log.Infof("Re-queued backups of session=%s with dead tower", id)
+
+	return c.cfg.DB.TerminateSession(id)
+}
+
+// handleRecoveredTower makes the sessions with a tower that came back
+// available for new backups again.
+func (c *client) handleRecoveredTower(tower *Tower) error {
+	c.log.Infof("Tower=%x is responsive again",
+		tower.IdentityKey.SerializeCompressed())
+
+	return c.addSessionCandidates(tower)
+}
+
 // registeredTowers retrieves the list of watchtowers registered with the
 // client.
 func (c *client) registeredTowers(towers []*wtdb.Tower,
@@ -1376,6 +1734,7 @@ func (c *client) registeredTowers(towers []*wtdb.Tower,
 			Tower:                  tower,
 			Sessions:               towerSessions[tower.ID],
 			ActiveSessionCandidate: isActive,
+			Health:                 c.health.health(tower.ID),
 		})
 	}
 
@@ -1398,6 +1757,7 @@ func (c *client) lookupTower(tower *wtdb.Tower,
 		Tower:                  tower,
 		Sessions:               towerSessions,
 		ActiveSessionCandidate: c.candidateTowers.IsActive(tower.ID),
+		Health:                 c.health.health(tower.ID),
 	}, nil
 }
 
@@ -1360,6 +1360,49 @@ var clientTests = []clientTest{
 			h.server.waitForUpdates(hints, waitTime)
 		},
 	},
+	{
+		// Asserts that the client falls back to sending its state
+		// updates one by one to a tower that doesn't support batched
+		// updates, and that it continues the same session with
+		// batches once the tower does.
+		name: "tower without batched updates",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy:   defaultTxPolicy,
+				MaxUpdates: 20,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				numUpdates = 10
+				chanID     = 0
+			)
+
+			hints := h.advanceChannelN(chanID, numUpdates)
+
+			// Restart the server such that it doesn't signal
+			// support for batched updates.
+			h.server.restart(func(cfg *wtserver.Config) {
+				cfg.NoBatchedUpdates = true
+			})
+
+			// Back up the first half of the states, which are sent
+			// to the tower one by one.
+			h.backupStates(chanID, 0, numUpdates/2, nil)
+			h.server.waitForUpdates(hints[:numUpdates/2], waitTime)
+
+			// Now, let the tower signal support for batched updates
+			// again, and back up the rest of the states.
+			h.server.restart(func(cfg *wtserver.Config) {
+				cfg.NoBatchedUpdates = false
+			})
+
+			h.backupStates(chanID, numUpdates/2, numUpdates, nil)
+			h.server.waitForUpdates(hints, waitTime)
+		},
+	},
 	{
 		// Asserts that the client is able to send state updates to the
 		// tower for a full range of channel values, assuming the sweep
@@ -1660,6 +1703,93 @@ var clientTests = []clientTest{
 			h.server.waitForUpdates(hints, waitTime)
 		},
 	},
+	{
+		// Asserts that the client doesn't back up the states of
+		// excluded channels or of channels that require a higher sweep
+		// fee rate than the client's sessions pay, and that the
+		// coverage of the channel reports the protected heights.
+		name: "per-channel backup policy",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy:   defaultTxPolicy,
+				MaxUpdates: 20,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				numUpdates = 15
+				chanID     = 0
+			)
+
+			hints := h.advanceChannelN(chanID, numUpdates)
+			id := chanIDFromInt(chanID)
+
+			// The states of an excluded channel are ignored.
+			err := h.clientMgr.SetChannelBackupPolicy(
+				id, wtdb.ChannelBackupPolicy{Excluded: true},
+			)
+			require.NoError(h.t, err)
+			h.backupStates(chanID, 0, 5, nil)
+
+			// The states of a channel that requires a higher
+			// sweep fee rate are ineligible.
+			err = h.clientMgr.SetChannelBackupPolicy(
+				id, wtdb.ChannelBackupPolicy{
+					MinSweepFeeRate: 2 *
+						defaultTxPolicy.SweepFeeRate,
+				},
+			)
+			require.NoError(h.t, err)
+			h.backupStates(chanID, 5, 10, nil)
+
+			// The policy is applied once the client processes the
+			// queued states, so wait for them to be rejected
+			// before relaxing it again.
+			err = wait.Predicate(func() bool {
+				stats := h.clientMgr.Stats()
+
+				return stats.NumTasksIneligible == 5
+			}, waitTime)
+			require.NoError(h.t, err)
+
+			// With the default policy, states are backed up again.
+			err = h.clientMgr.SetChannelBackupPolicy(
+				id, wtdb.ChannelBackupPolicy{},
+			)
+			require.NoError(h.t, err)
+			h.backupStates(chanID, 10, numUpdates, nil)
+
+			h.server.waitForUpdates(hints[10:], waitTime)
+
+			matches, err := h.server.db.QueryMatches(hints[:10])
+			require.NoError(h.t, err)
+			require.Empty(h.t, matches)
+
+			// Only the last states are reported as protected once
+			// the tower acked them.
+			err = wait.Predicate(func() bool {
+				coverages, err := h.clientMgr.ChannelCoverage(id)
+				require.NoError(h.t, err)
+				require.Len(h.t, coverages, 1)
+
+				ranges := coverages[0].ProtectedRanges
+
+				return len(ranges) == 1 &&
+					ranges[0] == wtdb.HeightRange{
+						Start: 10, End: numUpdates - 1,
+					}
+			}, waitTime)
+			require.NoError(h.t, err)
+
+			// Unknown channels have no coverage.
+			_, err = h.clientMgr.ChannelCoverage(chanIDFromInt(99))
+			require.ErrorIs(
+				h.t, err, wtclient.ErrUnregisteredChannel,
+			)
+		},
+	},
 	{
 		// Asserts that the client can continue making backups to a
 		// tower that's been re-added after it's been removed.
@@ -2083,6 +2213,93 @@ var clientTests = []clientTest{
 			require.NoError(h.t, err)
 		},
 	},
+	{
+		// Assert that the justice transactions a tower published for a
+		// session are fetched and reported before the session is
+		// deleted from the tower.
+		name: "justice reports fetched before session deletion",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy:   defaultTxPolicy,
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			const numUpdates = 5
+
+			h.sendUpdatesOn = true
+
+			// Exhaust a session with the updates of channel 0.
+			hints := h.advanceChannelN(0, numUpdates)
+			h.backupStates(0, 0, numUpdates, nil)
+			h.server.waitForUpdates(hints, waitTime)
+
+			sessionIDs := h.relevantSessions(0)
+			require.Len(h.t, sessionIDs, 1)
+			sessionID := sessionIDs[0]
+
+			// Let the tower record a justice transaction for one
+			// of the session's updates.
+			report := wtdb.JusticeReport{
+				SeqNum:      2,
+				BreachTxID:  [32]byte{1},
+				JusticeTxID: [32]byte{2},
+			}
+			err := h.server.db.InsertJusticeReport(
+				sessionID, &report,
+			)
+			require.NoError(h.t, err)
+
+			sub, err := h.clientMgr.SubscribeJusticeReports()
+			require.NoError(h.t, err)
+			defer sub.Cancel()
+
+			// Closing the channel makes the session closable, and
+			// mining past the close range deletes it.
+			h.closeChannel(0, 1)
+			err = wait.Predicate(func() bool {
+				return h.isSessionClosable(sessionID)
+			}, waitTime)
+			require.NoError(h.t, err)
+
+			h.mine(3)
+
+			expReport := &wtdb.ClientJusticeReport{
+				JusticeReport: report,
+				TowerPubKey:   h.server.addr.IdentityKey,
+				SessionID:     sessionID,
+			}
+
+			select {
+			case update := <-sub.Updates():
+				require.Equal(h.t, expReport, update)
+
+			case <-time.After(waitTime):
+				h.t.Fatalf("justice report not received")
+			}
+
+			// The report is kept after the session was deleted
+			// from the tower.
+			err = wait.NoError(func() error {
+				_, err := h.server.db.GetSessionInfo(&sessionID)
+				if !errors.Is(err, wtdb.ErrSessionNotFound) {
+					return fmt.Errorf("session not deleted")
+				}
+
+				return nil
+			}, waitTime)
+			require.NoError(h.t, err)
+
+			reports, err := h.clientMgr.JusticeReports()
+			require.NoError(h.t, err)
+			require.Equal(
+				h.t, []*wtdb.ClientJusticeReport{expReport},
+				reports,
+			)
+		},
+	},
 	{
 		// Demonstrate that the client is able to recover after
 		// deleting its database by skipping through key indices until
@@ -2318,9 +2535,12 @@ var clientTests = []clientTest{
 			h.server.waitForUpdates(hints[:numUpdates/2], waitTime)
 
 			// Now stop the server and restart it with the
-			// NoAckUpdates set to true.
+			// NoAckUpdates set to true. The tower doesn't signal
+			// support for batched updates, so that the client
+			// sends the remaining updates one by one.
 			h.server.restart(func(cfg *wtserver.Config) {
 				cfg.NoAckUpdates = true
+				cfg.NoBatchedUpdates = true
 			})
 
 			// Back up the remaining tasks. This will bind the
@@ -2402,9 +2622,12 @@ var clientTests = []clientTest{
 			h.server.waitForUpdates(hints[:numUpdates/2], waitTime)
 
 			// Now stop the server and restart it with the
-			// NoAckUpdates set to true.
+			// NoAckUpdates set to true. The tower doesn't signal
+			// support for batched updates, so that the client
+			// sends the remaining updates one by one.
 			h.server.restart(func(cfg *wtserver.Config) {
 				cfg.NoAckUpdates = true
+				cfg.NoBatchedUpdates = true
 			})
 
 			// Back up the remaining tasks. This will bind the
@@ -2463,6 +2686,93 @@ var clientTests = []clientTest{
 			server2.waitForUpdates(hints[numUpdates/2:], waitTime)
 		},
 	},
+	{
+		// Assert that a client is able to remove a tower if there are
+		// persisted un-acked batched updates. The client commits all
+		// updates of a batch before sending it, so all the updates
+		// that were sent to the tower are committed.
+		name: "can remove tower with un-acked batched updates",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy:   defaultTxPolicy,
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				numUpdates = 5
+				chanID     = 0
+			)
+
+			// Generate numUpdates retributions and back a few of
+			// them up to the main tower.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates/2, nil)
+
+			// Wait for all these updates to be populated in the
+			// server's database.
+			h.server.waitForUpdates(hints[:numUpdates/2], waitTime)
+
+			// Now stop the server and restart it with the
+			// NoAckUpdates set to true. The tower still signals
+			// support for batched updates.
+			h.server.restart(func(cfg *wtserver.Config) {
+				cfg.NoAckUpdates = true
+			})
+
+			// Back up the remaining tasks. The client sends them
+			// to the tower in batches and commits every update of
+			// a batch before sending it.
+			h.backupStates(chanID, numUpdates/2, numUpdates, nil)
+
+			tower, err := h.clientDB.LoadTower(
+				h.server.addr.IdentityKey,
+			)
+			require.NoError(h.t, err)
+
+			// Wait till all the remaining updates have been
+			// persisted as committed updates.
+			err = wait.Predicate(func() bool {
+				var numCommittedUpdates int
+				countUpdates := func(_ *wtdb.ClientSession,
+					update *wtdb.CommittedUpdate) {
+
+					numCommittedUpdates++
+				}
+
+				_, err := h.clientDB.ListClientSessions(
+					&tower.ID, wtdb.WithPerCommittedUpdate(
+						countUpdates,
+					),
+				)
+				require.NoError(h.t, err)
+
+				return numCommittedUpdates ==
+					numUpdates-numUpdates/2
+
+			}, waitTime)
+			require.NoError(h.t, err)
+
+			// Now remove the tower.
+			err = h.clientMgr.RemoveTower(
+				h.server.addr.IdentityKey, nil,
+			)
+			require.NoError(h.t, err)
+
+			// Add a new tower.
+			server2 := newServerHarness(
+				h.t, h.net, towerAddr2Str, nil,
+			)
+			server2.start()
+			h.addTower(server2.addr)
+
+			// Now we assert that all the committed updates are
+			// backed up to the new tower.
+			server2.waitForUpdates(hints[numUpdates/2:], waitTime)
+		},
+	},
 	{
 		// Previously we would not load a session into memory if its
 		// seq num was equal to it's max-updates. This meant that we
@@ -2894,6 +3204,69 @@ var clientTests = []clientTest{
 			require.EqualValues(h.t, 2, totalUpdates)
 		},
 	},
+	{
+		// Assert that the client fails over to a healthy tower once
+		// its tower is considered dead, and that the updates the dead
+		// tower didn't ack are backed up to the new tower without the
+		// dead tower having to be removed.
+		name: "fail over from dead tower",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy:   defaultTxPolicy,
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				numUpdates = 5
+				chanID     = 0
+			)
+
+			// Restart the client so that it considers a tower dead
+			// after a single failure and backs up the un-acked
+			// updates of a dead tower to another tower.
+			require.NoError(h.t, h.clientMgr.Stop())
+			h.clientCfg.MaxTowerFailures = 1
+			h.clientCfg.DeadTowerPolicy =
+				wtclient.DeadTowerRebackupUnacked
+			h.startClient()
+			h.registerChannel(chanID)
+
+			// Back up a few states to the first tower.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates/2, nil)
+			h.server.waitForUpdates(hints[:numUpdates/2], waitTime)
+
+			// Add a second tower and stop the first one.
+			server2 := newServerHarness(
+				h.t, h.net, towerAddr2Str, nil,
+			)
+			server2.start()
+			h.addTower(server2.addr)
+
+			h.server.stop()
+
+			// Back up the remaining states. The session with the
+			// first tower fails to deliver them, after which the
+			// tower is considered dead and the states are backed
+			// up to the second tower instead.
+			h.backupStates(chanID, numUpdates/2, numUpdates, nil)
+			server2.waitForUpdates(
+				hints[numUpdates/2:numUpdates], waitTime,
+			)
+
+			// The first tower is reported as dead.
+			towers, err := h.clientMgr.LookupTower(
+				h.server.addr.IdentityKey,
+			)
+			require.NoError(h.t, err)
+			for _, tower := range towers {
+				require.True(h.t, tower.Health.Dead)
+			}
+		},
+	},
 }
 
 // TestClient executes the client test suite, asserting the ability to backup
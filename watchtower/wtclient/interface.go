@@ -85,6 +85,10 @@ type DB interface {
 	// successfully backed up using the given session.
 	NumAckedUpdates(id *wtdb.SessionID) (uint64, error)
 
+	// FetchAckedUpdates returns the backups that have been acked by the
+	// tower of the given session.
+	FetchAckedUpdates(id *wtdb.SessionID) ([]wtdb.BackupID, error)
+
 	// FetchChanInfos loads a mapping from all registered channels to
 	// their wtdb.ChannelInfo. Only the channels that have not yet been
 	// marked as closed will be loaded.
@@ -117,6 +121,17 @@ type DB interface {
 	// the client's active policy.
 	RegisterChannel(lnwire.ChannelID, []byte) error
 
+	// SetChannelBackupPolicy persists the backup settings of a registered
+	// channel.
+	SetChannelBackupPolicy(chanID lnwire.ChannelID,
+		policy *wtdb.ChannelBackupPolicy) error
+
+	// FetchChannelAckedRanges returns the commitment heights of the given
+	// channel that were acked by a tower in any session, merged into
+	// sorted ranges.
+	FetchChannelAckedRanges(chanID lnwire.ChannelID) ([]wtdb.HeightRange,
+		error)
+
 	// MarkBackupIneligible records that the state identified by the
 	// (channel id, commit height) tuple was ineligible for being backed up
 	// under the current policy. This state can be retried later under a
@@ -148,6 +163,18 @@ type DB interface {
 	// that this tower's sessions won't be loaded and used for backups.
 	// CreateTower can be used to reactivate the tower again.
 	DeactivateTower(pubKey *btcec.PublicKey) error
+
+	// DBStats returns the storage statistics of the database, such as the
+	// number of stored sessions and the size of the backup backlog.
+	DBStats() (*wtdb.ClientDBStats, error)
+
+	// AddJusticeReport persists a justice report received from a tower.
+	// The report is kept after its session is deleted.
+	AddJusticeReport(report *wtdb.ClientJusticeReport) error
+
+	// ListJusticeReports returns all justice reports received from the
+	// client's towers.
+	ListJusticeReports() ([]*wtdb.ClientJusticeReport, error)
 }
 
 // AuthDialer connects to a remote node using an authenticated transport, such
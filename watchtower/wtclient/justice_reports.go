@@ -0,0 +1,51 @@
+package wtclient
+
+import (
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+)
+
+// JusticeReports returns the justice transactions the client's towers reported
+// to have published, which indicate that one of our channels was breached.
+func (m *Manager) JusticeReports() ([]*wtdb.ClientJusticeReport, error) {
+	return m.cfg.DB.ListJusticeReports()
+}
+
+// SubscribeJusticeReports returns a subscription that receives a
+// *wtdb.ClientJusticeReport for each justice transaction a tower reports after
+// the subscription was created.
+func (m *Manager) SubscribeJusticeReports() (*subscribe.Client, error) {
+	return m.justiceReportServer.Subscribe()
+}
+
+// collectJusticeReports fetches the justice transactions the tower published
+// for the given session, persists them and notifies the subscribers. Towers
+// only keep the reports as long as the session, so this must be done before
+// the session is deleted from the tower.
+func (m *Manager) collectJusticeReports(c *client,
+	sess *wtdb.ClientSession) error {
+
+	reports, err := c.fetchJusticeReports(sess)
+	if err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		if err := m.cfg.DB.AddJusticeReport(report); err != nil {
+			return err
+		}
+
+		log.Warnf("Tower %x published justice txn=%v for breach "+
+			"txn=%v of session %s",
+			report.TowerPubKey.SerializeCompressed(),
+			report.JusticeTxID, report.BreachTxID, sess.ID)
+
+		err := m.justiceReportServer.SendUpdate(report)
+		if err != nil {
+			log.Errorf("Unable to send justice report "+
+				"notification: %v", err)
+		}
+	}
+
+	return nil
+}
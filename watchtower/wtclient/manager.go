@@ -50,6 +50,9 @@ type ClientManager interface {
 	// Stats returns the in-memory statistics of the client since startup.
 	Stats() ClientStats
 
+	// DBStats returns the storage statistics of the client database.
+	DBStats() (*wtdb.ClientDBStats, error)
+
 	// Policy returns the active client policy configuration.
 	Policy(blob.Type) (wtpolicy.Policy, error)
 
@@ -74,6 +77,26 @@ type ClientManager interface {
 	// successful unless the justice transaction would create dust outputs
 	// when trying to abide by the negotiated policy.
 	BackupState(chanID *lnwire.ChannelID, stateNum uint64) error
+
+	// SetChannelBackupPolicy sets the backup settings of a registered
+	// channel, which allow excluding it from backups or requiring a
+	// minimum sweep fee rate for its justice transactions.
+	SetChannelBackupPolicy(lnwire.ChannelID, wtdb.ChannelBackupPolicy) error
+
+	// ChannelCoverage returns the backup settings and the protected
+	// commitment heights of the given registered channels, or of all of
+	// them if none are given.
+	ChannelCoverage(chanIDs ...lnwire.ChannelID) ([]*ChannelCoverage,
+		error)
+
+	// JusticeReports returns the justice transactions the client's towers
+	// reported to have published.
+	JusticeReports() ([]*wtdb.ClientJusticeReport, error)
+
+	// SubscribeJusticeReports returns a subscription that receives a
+	// *wtdb.ClientJusticeReport for each newly reported justice
+	// transaction.
+	SubscribeJusticeReports() (*subscribe.Client, error)
 }
 
 // Config provides the client with access to the resources it requires to
@@ -154,6 +177,25 @@ type Config struct {
 	// MaxTasksInMemQueue is the maximum number of backup tasks that should
 	// be kept in-memory. Any more tasks will overflow to disk.
 	MaxTasksInMemQueue uint64
+
+	// HealthCheckInterval is the interval at which the candidate towers
+	// are probed to determine their health. If the value is zero, towers
+	// are only judged by the exchanges made to back up states.
+	HealthCheckInterval time.Duration
+
+	// MaxTowerFailures is the number of consecutive failed exchanges with
+	// a tower after which it is considered dead. New sessions are then
+	// negotiated with other towers. If the value is zero, the default
+	// will be used instead.
+	MaxTowerFailures uint32
+
+	// DeadTowerPolicy determines what happens to the backups that were
+	// sent to a tower once it is considered dead.
+	DeadTowerPolicy DeadTowerPolicy
+
+	// TowerDiscovery, if set, lets the client add towers that are
+	// advertised in node announcements.
+	TowerDiscovery *TowerDiscoveryConfig
 }
 
 // Manager manages the various tower clients that are active. A client is
@@ -174,6 +216,10 @@ type Manager struct {
 
 	closableSessionQueue *sessionCloseMinHeap
 
+	// justiceReportServer notifies subscribers of the justice transactions
+	// reported by the towers.
+	justiceReportServer *subscribe.Server
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -196,6 +242,11 @@ func NewManager(config *Config, policies ...wtpolicy.Policy) (*Manager, error) {
 		cfg.WriteTimeout = DefaultWriteTimeout
 	}
 
+	// Set the max tower failures to the default if none was provided.
+	if cfg.MaxTowerFailures == 0 {
+		cfg.MaxTowerFailures = DefaultMaxTowerFailures
+	}
+
 	chanInfos, err := cfg.DB.FetchChanInfos()
 	if err != nil {
 		return nil, err
@@ -207,6 +258,7 @@ func NewManager(config *Config, policies ...wtpolicy.Policy) (*Manager, error) {
 		chanBlobType:         make(map[lnwire.ChannelID]blob.Type),
 		chanInfos:            chanInfos,
 		closableSessionQueue: newSessionCloseMinHeap(),
+		justiceReportServer:  subscribe.NewServer(),
 		quit:                 make(chan struct{}),
 	}
 
@@ -236,9 +288,10 @@ func (m *Manager) newClient(policy wtpolicy.Policy) error {
 	}
 
 	cfg := &clientCfg{
-		Config:         m.cfg,
-		Policy:         policy,
-		getSweepScript: m.getSweepScript,
+		Config:          m.cfg,
+		Policy:          policy,
+		getSweepScript:  m.getSweepScript,
+		getBackupPolicy: m.getBackupPolicy,
 	}
 
 	client, err := newClient(cfg)
@@ -255,6 +308,13 @@ func (m *Manager) newClient(policy wtpolicy.Policy) error {
 func (m *Manager) Start() error {
 	var returnErr error
 	m.started.Do(func() {
+		err := m.justiceReportServer.Start()
+		if err != nil {
+			returnErr = err
+
+			return
+		}
+
 		chanSub, err := m.cfg.SubscribeChannelEvents()
 		if err != nil {
 			returnErr = err
@@ -320,6 +380,11 @@ func (m *Manager) Start() error {
 		m.wg.Add(1)
 		go m.handleClosableSessions(blockEvents)
 
+		if m.cfg.TowerDiscovery != nil {
+			m.wg.Add(1)
+			go m.discoverTowers()
+		}
+
 		m.clientsMu.Lock()
 		defer m.clientsMu.Unlock()
 
@@ -349,6 +414,10 @@ func (m *Manager) Stop() error {
 				returnErr = err
 			}
 		}
+
+		if err := m.justiceReportServer.Stop(); err != nil {
+			returnErr = err
+		}
 	})
 
 	return returnErr
@@ -526,6 +595,12 @@ func (m *Manager) Stats() ClientStats {
 	return resp
 }
 
+// DBStats returns the storage statistics of the client database, which all
+// clients managed by the Manager share.
+func (m *Manager) DBStats() (*wtdb.ClientDBStats, error) {
+	return m.cfg.DB.DBStats()
+}
+
 // RegisteredTowers retrieves the list of watchtowers being used by the various
 // clients.
 func (m *Manager) RegisteredTowers(opts ...wtdb.ClientSessionListOption) (
@@ -671,6 +746,16 @@ func (m *Manager) BackupState(chanID *lnwire.ChannelID, stateNum uint64) error {
 		return ErrUnregisteredChannel
 	}
 
+	// Ignore backups of channels the user excluded from being backed up.
+	if info.BackupPolicy.Excluded {
+		m.backupMu.Unlock()
+
+		log.Debugf("Ignoring backup for excluded chanid=%v at "+
+			"height=%d", chanID, stateNum)
+
+		return nil
+	}
+
 	// Ignore backups that have already been presented to the client.
 	var duplicate bool
 	info.MaxHeight.WhenSome(func(maxHeight uint64) {
@@ -937,6 +1022,18 @@ func (m *Manager) handleClosableSessions(
 					continue
 				}
 
+				// Before the tower forgets about the session,
+				// learn whether it had to exact justice for
+				// any of its updates. This is best effort, a
+				// tower that already deleted the session can't
+				// report anything.
+				err = m.collectJusticeReports(client, sess)
+				if err != nil {
+					log.Errorf("Unable to collect justice "+
+						"reports of session %s: %v",
+						sess.ID, err)
+				}
+
 				err = client.deleteSessionFromTower(sess)
 				if err != nil {
 					log.Errorf("error deleting "+
@@ -533,6 +533,10 @@ func (n *sessionNegotiator) tryAddress(sessionKey keychain.SingleKeyECDH,
 		return fmt.Errorf("tower rejected sweep fee rate: %v",
 			policy.SweepFeeRate)
 
+	case wtwire.CreateSessionCodeRejectUnauthorized:
+		return errors.New("tower didn't authorize the session, it " +
+			"may require sessions to be paid for")
+
 	default:
 		return fmt.Errorf("received unhandled error code: %v",
 			createSessionReply.Code)
@@ -11,6 +11,7 @@ import (
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 	"github.com/lightningnetwork/lnd/watchtower/wtserver"
 	"github.com/lightningnetwork/lnd/watchtower/wtwire"
@@ -33,6 +34,11 @@ const (
 	sessionQueueShuttingDown
 )
 
+// maxStateUpdateBatchSize is the maximum number of updates sent to a tower in
+// a single StateUpdateBatch, which bounds the time the tower takes to apply a
+// batch before replying.
+const maxStateUpdateBatchSize = 100
+
 // sessionQueueConfig bundles the resources required by the sessionQueue to
 // perform its duties. All entries MUST be non-nil.
 type sessionQueueConfig struct {
@@ -73,6 +79,10 @@ type sessionQueueConfig struct {
 	// DB provides access to the client's stable storage.
 	DB DB
 
+	// Health records the outcome of the exchanges with the session's
+	// tower and the number of updates the tower hasn't acked yet.
+	Health *towerHealthTracker
+
 	// MinBackoff defines the initial backoff applied by the session
 	// queue before reconnecting to the tower after a failed or partially
 	// successful batch is sent. Subsequent backoff durations will grow
@@ -124,7 +134,10 @@ func newSessionQueue(cfg *sessionQueueConfig,
 	updates []wtdb.CommittedUpdate) *sessionQueue {
 
 	localInit := wtwire.NewInitMessage(
-		lnwire.NewRawFeatureVector(wtwire.AltruistSessionsRequired),
+		lnwire.NewRawFeatureVector(
+			wtwire.AltruistSessionsRequired,
+			wtwire.BatchedUpdatesOptional,
+		),
 		cfg.ChainHash,
 	)
 
@@ -154,6 +167,10 @@ func newSessionQueue(cfg *sessionQueueConfig,
 // backups.
 func (q *sessionQueue) Start() {
 	q.started.Do(func() {
+		q.queueCond.L.Lock()
+		q.reportBacklog()
+		q.queueCond.L.Unlock()
+
 		q.wg.Add(1)
 		go q.sessionManager()
 	})
@@ -245,6 +262,9 @@ func (q *sessionQueue) Stop(final bool) error {
 		}
 		q.queueCond.L.Unlock()
 
+		// The remaining tasks are no longer queued for the tower.
+		q.cfg.Health.setBacklog(q.tower.ID, *q.ID(), 0)
+
 		q.log.Debugf("SessionQueue(%s) stopped", q.ID())
 	})
 
@@ -321,6 +341,7 @@ func (q *sessionQueue) AcceptTask(task *backupTask) (sessionQueueStatus, bool) {
 	// The sweep and reward outputs satisfy the session's policy, queue the
 	// task for final signing and delivery.
 	q.pendingQueue.PushBack(task)
+	q.reportBacklog()
 
 	// Finally, compute the session's *new* reserve status. This will be
 	// used by the client to determine if it can continue using this session
@@ -402,11 +423,7 @@ func (q *sessionQueue) drainBackups() {
 			q.log.Errorf("SessionQueue(%s) unable to dial tower "+
 				"at any available Addresses: %v", q.ID(), err)
 
-			q.increaseBackoff()
-			select {
-			case <-time.After(q.retryBackoff):
-			case <-q.quit:
-			}
+			q.backOffAfterFailure()
 			return
 		}
 
@@ -414,15 +431,34 @@ func (q *sessionQueue) drainBackups() {
 	}
 	defer conn.Close()
 
-	// Begin draining the queue of pending state updates. Before the first
-	// update is sent, we will precede it with an Init message. If the first
-	// is successful, subsequent updates can be streamed without sending an
-	// Init.
-	for sendInit := true; ; sendInit = false {
+	// Before the first update is sent, we will exchange Init messages with
+	// the tower to establish that it supports the features we require.
+	remoteInit, err := q.initConnection(conn)
+	if err != nil {
+		q.log.Errorf("SessionQueue(%s) unable to initialize "+
+			"connection to tower: %v", q.ID(), err)
+
+		q.backOffAfterFailure()
+		return
+	}
+
+	// If the tower understands batched updates, we'll send all pending
+	// updates in as few round trips as possible.
+	remoteFeatures := lnwire.NewFeatureVector(
+		remoteInit.ConnFeatures, wtwire.FeatureNames,
+	)
+	if remoteFeatures.HasFeature(wtwire.BatchedUpdatesOptional) {
+		q.drainBackupBatches(conn)
+		return
+	}
+
+	// Otherwise, begin draining the queue of pending state updates one by
+	// one, streaming them to the tower over the same connection.
+	for {
 		// Generate the next state update to upload to the tower. This
 		// method will first proceed in dequeuing committed updates
 		// before attempting to dequeue any pending updates.
-		stateUpdate, isPending, backupID, err := q.nextStateUpdate()
+		stateUpdate, isPending, backupID, err := q.nextStateUpdate(0)
 		if err != nil {
 			q.log.Errorf("SessionQueue(%v) unable to get next "+
 				"state update: %v", q.ID(), err)
@@ -430,19 +466,18 @@ func (q *sessionQueue) drainBackups() {
 		}
 
 		// Now, send the state update to the tower and wait for a reply.
-		err = q.sendStateUpdate(conn, stateUpdate, sendInit, isPending)
+		start := time.Now()
+		err = q.sendStateUpdate(conn, stateUpdate, isPending)
 		if err != nil {
 			q.log.Errorf("SessionQueue(%s) unable to send state "+
 				"update: %v", q.ID(), err)
 
-			q.increaseBackoff()
-			select {
-			case <-time.After(q.retryBackoff):
-			case <-q.quit:
-			}
+			q.backOffAfterFailure()
 			return
 		}
 
+		q.cfg.Health.recordSuccess(q.tower.ID, time.Since(start))
+
 		q.log.Infof("SessionQueue(%s) uploaded %v seqnum=%d",
 			q.ID(), backupID, stateUpdate.SeqNum)
 
@@ -466,15 +501,130 @@ func (q *sessionQueue) drainBackups() {
 	}
 }
 
-// nextStateUpdate returns the next wtwire.StateUpdate to upload to the tower.
-// If any committed updates are present, this method will reconstruct the state
-// update from the committed update using the current last applied value found
-// in the database. Otherwise, it will select the next pending update, craft the
-// payload, and commit an update before returning the state update to send. The
-// boolean value in the response is true if the state update is taken from the
-// pending queue, allowing the caller to remove the update from either the
-// commit or pending queue if the update is successfully acked.
-func (q *sessionQueue) nextStateUpdate() (*wtwire.StateUpdate, bool,
+// drainBackupBatches attempts to send all pending updates in the queue to a
+// tower that understands batched updates. The updates are sent in
+// StateUpdateBatch messages, each of which is acked by the tower with a single
+// reply.
+func (q *sessionQueue) drainBackupBatches(conn wtserver.Peer) {
+	maxBatchSize, err := q.maxBatchSize()
+	if err != nil {
+		q.log.Errorf("SessionQueue(%v) unable to determine batch "+
+			"size: %v", q.ID(), err)
+		return
+	}
+
+	for {
+		// Generate the next batch of state updates to upload to the
+		// tower, starting with any committed updates.
+		batch, isPending, err := q.nextStateUpdateBatch(maxBatchSize)
+		if err != nil {
+			q.log.Errorf("SessionQueue(%v) unable to get next "+
+				"state update batch: %v", q.ID(), err)
+			return
+		}
+
+		start := time.Now()
+		err = q.sendStateUpdateBatch(conn, batch, isPending)
+		if err != nil {
+			q.log.Errorf("SessionQueue(%s) unable to send state "+
+				"update batch: %v", q.ID(), err)
+
+			q.backOffAfterFailure()
+			return
+		}
+
+		q.cfg.Health.recordSuccess(q.tower.ID, time.Since(start))
+
+		q.log.Infof("SessionQueue(%s) uploaded batch of %d updates "+
+			"seqnum=%d-%d", q.ID(), len(batch.Updates),
+			batch.Updates[0].SeqNum,
+			batch.Updates[len(batch.Updates)-1].SeqNum)
+
+		// If the batch contained the last queued update, we'll exit
+		// and continue once more tasks are added to the queue.
+		if batch.IsComplete == 1 {
+			q.resetBackoff()
+			return
+		}
+
+		select {
+		case <-q.quit:
+			return
+		default:
+		}
+	}
+}
+
+// maxBatchSize returns the maximum number of updates to send in a single
+// StateUpdateBatch, which is bounded by the size of the encrypted blobs of the
+// session.
+func (q *sessionQueue) maxBatchSize() (int, error) {
+	blobType := q.cfg.ClientSession.Policy.BlobType
+	commitType, err := blobType.CommitmentType(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	kit, err := commitType.EmptyJusticeKit()
+	if err != nil {
+		return 0, err
+	}
+
+	maxSize := wtwire.MaxBatchedStateUpdates(blob.Size(kit))
+
+	return min(maxSize, maxStateUpdateBatchSize), nil
+}
+
+// nextStateUpdateBatch returns the next wtwire.StateUpdateBatch to upload to
+// the tower, holding at most maxSize updates. The updates are taken from the
+// front of the queue in the same way nextStateUpdate does. The returned slice
+// indicates for each update of the batch whether it was taken from the pending
+// queue.
+func (q *sessionQueue) nextStateUpdateBatch(maxSize int) (
+	*wtwire.StateUpdateBatch, []bool, error) {
+
+	var (
+		batch     wtwire.StateUpdateBatch
+		isPending []bool
+	)
+	for offset := 0; offset < maxSize; offset++ {
+		update, pending, _, err := q.nextStateUpdate(offset)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		batch.LastApplied = update.LastApplied
+		batch.Updates = append(batch.Updates, wtwire.BatchedStateUpdate{
+			SeqNum:        update.SeqNum,
+			Hint:          update.Hint,
+			EncryptedBlob: update.EncryptedBlob,
+		})
+		isPending = append(isPending, pending)
+
+		// Stop at the last queued update, and let the tower know it
+		// can release the connection after replying.
+		if update.IsComplete == 1 {
+			batch.IsComplete = 1
+			break
+		}
+	}
+
+	return &batch, isPending, nil
+}
+
+// nextStateUpdate returns the wtwire.StateUpdate at the given offset into the
+// queue to upload to the tower, where any committed updates precede the
+// pending updates. If the offset points at a committed update, this method
+// will reconstruct the state update from the committed update using the
+// current last applied value found in the database. Otherwise, it will select
+// the pending update, craft the payload, and commit an update before returning
+// the state update to send. The boolean value in the response is true if the
+// state update is taken from the pending queue, allowing the caller to remove
+// the update from either the commit or pending queue if the update is
+// successfully acked.
+//
+// NOTE: The offset MUST point at an update in the queue.
+func (q *sessionQueue) nextStateUpdate(offset int) (*wtwire.StateUpdate, bool,
 	wtdb.BackupID, error) {
 
 	var (
@@ -485,43 +635,41 @@ func (q *sessionQueue) nextStateUpdate() (*wtwire.StateUpdate, bool,
 	)
 
 	q.queueCond.L.Lock()
+	numCommitted := q.commitQueue.Len()
+
+	// If this is the last item in the queue, we will use the IsComplete
+	// flag in the StateUpdate to signal that the tower can release the
+	// connection after replying to free up resources.
+	isLast = offset == numCommitted+q.pendingQueue.Len()-1
+
 	switch {
 
-	// If the commit queue is non-empty, parse the next committed update.
-	case q.commitQueue.Len() > 0:
-		next := q.commitQueue.Front()
+	// If the offset points into the commit queue, parse the committed
+	// update.
+	case offset < numCommitted:
+		next := listElement(q.commitQueue, offset)
 
 		update = next.Value.(wtdb.CommittedUpdate)
 		seqNum = update.SeqNum
-
-		// If this is the last item in the commit queue and no items
-		// exist in the pending queue, we will use the IsComplete flag
-		// in the StateUpdate to signal that the tower can release the
-		// connection after replying to free up resources.
-		isLast = q.commitQueue.Len() == 1 && q.pendingQueue.Len() == 0
 		q.queueCond.L.Unlock()
 
 		q.log.Debugf("SessionQueue(%s) reprocessing committed state "+
 			"update for %v seqnum=%d",
 			q.ID(), update.BackupID, seqNum)
 
-	// Otherwise, craft and commit the next update from the pending queue.
+	// Otherwise, craft and commit the update from the pending queue.
 	default:
 		isPending = true
 
-		// Determine the current sequence number to apply for this
-		// pending update.
-		seqNum = q.seqNum + 1
+		// Determine the sequence number to apply for this pending
+		// update, which follows those of the pending updates in front
+		// of it.
+		pendingOffset := offset - numCommitted
+		seqNum = q.seqNum + 1 + uint16(pendingOffset)
 
-		// Obtain the next task from the queue.
-		next := q.pendingQueue.Front()
+		// Obtain the task from the queue.
+		next := listElement(q.pendingQueue, pendingOffset)
 		task := next.Value.(*backupTask)
-
-		// If this is the last item in the pending queue, we will use
-		// the IsComplete flag in the StateUpdate to signal that the
-		// tower can release the connection after replying to free up
-		// resources.
-		isLast = q.pendingQueue.Len() == 1
 		q.queueCond.L.Unlock()
 
 		hint, encBlob, err := task.craftSessionPayload(q.cfg.Signer)
@@ -580,49 +728,54 @@ func (q *sessionQueue) nextStateUpdate() (*wtwire.StateUpdate, bool,
 	return stateUpdate, isPending, update.BackupID, nil
 }
 
-// sendStateUpdate sends a wtwire.StateUpdate to the watchtower and processes
-// the ACK before returning. If sendInit is true, this method will first send
-// the localInit message and verify that the tower supports our required feature
-// bits. And error is returned if any part of the send fails. The boolean return
-// variable indicates whether we should back off before attempting to send the
-// next state update.
-func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
-	stateUpdate *wtwire.StateUpdate, sendInit, isPending bool) error {
+// initConnection sends the localInit message to the tower, and verifies that
+// the tower's Init supports our required feature bits. The tower's Init is
+// returned, such that the caller can inspect the optional features it
+// supports.
+func (q *sessionQueue) initConnection(conn wtserver.Peer) (*wtwire.Init,
+	error) {
 
 	towerAddr := &lnwire.NetAddress{
 		IdentityKey: conn.RemotePub(),
 		Address:     conn.RemoteAddr(),
 	}
 
-	// If this is the first message being sent to the tower, we must send an
-	// Init message to establish that server supports the features we
-	// require.
-	if sendInit {
-		// Send Init to tower.
-		err := q.cfg.SendMessage(conn, q.localInit)
-		if err != nil {
-			return err
-		}
+	// Send Init to tower.
+	err := q.cfg.SendMessage(conn, q.localInit)
+	if err != nil {
+		return nil, err
+	}
 
-		// Receive Init from tower.
-		remoteMsg, err := q.cfg.ReadMessage(conn)
-		if err != nil {
-			return err
-		}
+	// Receive Init from tower.
+	remoteMsg, err := q.cfg.ReadMessage(conn)
+	if err != nil {
+		return nil, err
+	}
 
-		remoteInit, ok := remoteMsg.(*wtwire.Init)
-		if !ok {
-			return fmt.Errorf("watchtower %s responded with %T "+
-				"to Init", towerAddr, remoteMsg)
-		}
+	remoteInit, ok := remoteMsg.(*wtwire.Init)
+	if !ok {
+		return nil, fmt.Errorf("watchtower %s responded with %T to "+
+			"Init", towerAddr, remoteMsg)
+	}
 
-		// Validate Init.
-		err = q.localInit.CheckRemoteInit(
-			remoteInit, wtwire.FeatureNames,
-		)
-		if err != nil {
-			return err
-		}
+	// Validate Init.
+	err = q.localInit.CheckRemoteInit(remoteInit, wtwire.FeatureNames)
+	if err != nil {
+		return nil, err
+	}
+
+	return remoteInit, nil
+}
+
+// sendStateUpdate sends a wtwire.StateUpdate to the watchtower and processes
+// the ACK before returning. And error is returned if any part of the send
+// fails.
+func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
+	stateUpdate *wtwire.StateUpdate, isPending bool) error {
+
+	towerAddr := &lnwire.NetAddress{
+		IdentityKey: conn.RemotePub(),
+		Address:     conn.RemoteAddr(),
 	}
 
 	// Send StateUpdate to tower.
@@ -660,28 +813,102 @@ func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
 		return err
 	}
 
-	lastApplied := stateUpdateReply.LastApplied
-	err = q.cfg.DB.AckUpdate(q.ID(), stateUpdate.SeqNum, lastApplied)
+	return q.ackUpdate(
+		stateUpdate.SeqNum, stateUpdateReply.LastApplied, isPending,
+	)
+}
+
+// sendStateUpdateBatch sends a wtwire.StateUpdateBatch to the watchtower and
+// processes the single ACK for all of its updates before returning. Since the
+// tower applies the updates of a batch in order, the updates it applied are
+// acked even if it rejected a later one. An error is returned if any part of
+// the send fails or not all updates were applied.
+func (q *sessionQueue) sendStateUpdateBatch(conn wtserver.Peer,
+	batch *wtwire.StateUpdateBatch, isPending []bool) error {
+
+	towerAddr := &lnwire.NetAddress{
+		IdentityKey: conn.RemotePub(),
+		Address:     conn.RemoteAddr(),
+	}
+
+	err := q.cfg.SendMessage(conn, batch)
+	if err != nil {
+		return err
+	}
+
+	remoteMsg, err := q.cfg.ReadMessage(conn)
+	if err != nil {
+		return err
+	}
+
+	reply, ok := remoteMsg.(*wtwire.StateUpdateBatchReply)
+	if !ok {
+		return fmt.Errorf("watchtower %s responded with %T to "+
+			"StateUpdateBatch", towerAddr, remoteMsg)
+	}
+
+	for i, update := range batch.Updates {
+		if update.SeqNum > reply.LastApplied {
+			break
+		}
+
+		err := q.ackUpdate(
+			update.SeqNum, reply.LastApplied, isPending[i],
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	firstSeqNum := batch.Updates[0].SeqNum
+	lastSeqNum := batch.Updates[len(batch.Updates)-1].SeqNum
+
+	switch {
+	case reply.Code != wtwire.CodeOK:
+		err := fmt.Errorf("received error code %v in "+
+			"StateUpdateBatchReply for seqnum=%d-%d, last "+
+			"applied=%d", reply.Code, firstSeqNum, lastSeqNum,
+			reply.LastApplied)
+		q.log.Warnf("SessionQueue(%s) unable to upload state update "+
+			"batch to tower=%s: %v", q.ID(), towerAddr, err)
+		return err
+
+	// A tower that accepted the batch must have applied all of its
+	// updates.
+	case reply.LastApplied < lastSeqNum:
+		return fmt.Errorf("tower accepted batch for seqnum=%d-%d, "+
+			"but only applied up to seqnum=%d", firstSeqNum,
+			lastSeqNum, reply.LastApplied)
+	}
+
+	return nil
+}
+
+// ackUpdate records the tower's ack of the update with the given sequence
+// number, which MUST be the first update in the queue, and removes it from the
+// queue. The boolean indicates whether the update was taken from the pending
+// queue.
+func (q *sessionQueue) ackUpdate(seqNum, lastApplied uint16,
+	isPending bool) error {
+
+	err := q.cfg.DB.AckUpdate(q.ID(), seqNum, lastApplied)
 	switch {
 	case err == wtdb.ErrUnallocatedLastApplied:
 		// TODO(conner): borked watchtower
-		err = fmt.Errorf("unable to ack seqnum=%d: %w",
-			stateUpdate.SeqNum, err)
+		err = fmt.Errorf("unable to ack seqnum=%d: %w", seqNum, err)
 		q.log.Errorf("SessionQueue(%v) failed to ack update: %v",
 			q.ID(), err)
 		return err
 
 	case err == wtdb.ErrLastAppliedReversion:
 		// TODO(conner): borked watchtower
-		err = fmt.Errorf("unable to ack seqnum=%d: %w",
-			stateUpdate.SeqNum, err)
+		err = fmt.Errorf("unable to ack seqnum=%d: %w", seqNum, err)
 		q.log.Errorf("SessionQueue(%s) failed to ack update: %v",
 			q.ID(), err)
 		return err
 
 	case err != nil:
-		err = fmt.Errorf("unable to ack seqnum=%d: %w",
-			stateUpdate.SeqNum, err)
+		err = fmt.Errorf("unable to ack seqnum=%d: %w", seqNum, err)
 		q.log.Errorf("SessionQueue(%s) failed to ack update: %v",
 			q.ID(), err)
 		return err
@@ -702,6 +929,7 @@ func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
 		// update had already been committed.
 		q.commitQueue.Remove(q.commitQueue.Front())
 	}
+	q.reportBacklog()
 	q.queueCond.L.Unlock()
 
 	return nil
@@ -725,6 +953,36 @@ func (q *sessionQueue) status() sessionQueueStatus {
 
 }
 
+// backlog returns the number of updates in the queue that the tower hasn't
+// acked yet.
+func (q *sessionQueue) backlog() int {
+	q.queueCond.L.Lock()
+	defer q.queueCond.L.Unlock()
+
+	return q.commitQueue.Len() + q.pendingQueue.Len()
+}
+
+// reportBacklog reports the number of updates that the tower hasn't acked yet
+// to the health tracker.
+//
+// NOTE: This method MUST be called with queueCond's exclusive lock held.
+func (q *sessionQueue) reportBacklog() {
+	backlog := uint32(q.commitQueue.Len() + q.pendingQueue.Len())
+	q.cfg.Health.setBacklog(q.tower.ID, *q.ID(), backlog)
+}
+
+// backOffAfterFailure records a failed exchange with the tower, and waits for
+// the increased connection backoff before returning.
+func (q *sessionQueue) backOffAfterFailure() {
+	q.cfg.Health.recordFailure(q.tower.ID)
+
+	q.increaseBackoff()
+	select {
+	case <-time.After(q.retryBackoff):
+	case <-q.quit:
+	}
+}
+
 // resetBackoff returns the connection backoff the minimum configured backoff.
 func (q *sessionQueue) resetBackoff() {
 	q.retryBackoff = q.cfg.MinBackoff
@@ -739,6 +997,16 @@ func (q *sessionQueue) increaseBackoff() {
 	}
 }
 
+// listElement returns the element at the given index of the list.
+func listElement(l *list.List, i int) *list.Element {
+	e := l.Front()
+	for ; i > 0; i-- {
+		e = e.Next()
+	}
+
+	return e
+}
+
 // sessionQueueSet maintains a mapping of SessionIDs to their corresponding
 // sessionQueue.
 type sessionQueueSet struct {
@@ -0,0 +1,252 @@
+package wtclient
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+)
+
+const (
+	// DefaultTowerDiscoveryInterval is the default interval at which the
+	// client looks for advertised towers.
+	DefaultTowerDiscoveryInterval = time.Hour
+
+	// DefaultMaxDiscoveredTowers is the default number of active towers
+	// up to which the client adds advertised towers.
+	DefaultMaxDiscoveredTowers = 3
+)
+
+// TowerDiscoveryPolicy determines which of the advertised towers the client
+// selects.
+type TowerDiscoveryPolicy struct {
+	// MinStorage is the minimum number of bytes a tower must reserve for
+	// the sessions of its clients. Towers that don't limit their storage
+	// always pass.
+	MinStorage uint64
+
+	// AllowOnion indicates whether the client is able to reach towers at
+	// their onion addresses.
+	AllowOnion bool
+
+	// TorOnly restricts the client to towers that can be reached at an
+	// onion address. Only the onion addresses of those towers are used.
+	TorOnly bool
+
+	// MaxTowers is the number of active towers up to which the client
+	// adds advertised towers.
+	MaxTowers int
+}
+
+// TowerDiscoveryConfig holds the configuration of the discovery of towers
+// that are advertised in node announcements.
+type TowerDiscoveryConfig struct {
+	// FetchTowerAnns returns the tower advertisements of all known nodes.
+	FetchTowerAnns func() ([]*wtwire.TowerAnnouncement, error)
+
+	// Policy determines which of the advertised towers are selected.
+	Policy TowerDiscoveryPolicy
+
+	// Interval is the interval at which the advertised towers are
+	// fetched. If the value is zero, DefaultTowerDiscoveryInterval is
+	// used.
+	Interval time.Duration
+}
+
+// SelectTowers returns the advertised towers that watch the given chain, offer
+// altruist sessions with at least maxUpdates updates and match the policy.
+// The addresses of the returned advertisements are restricted to those the
+// client can reach. Towers that reserve more storage for their clients are
+// returned first.
+func SelectTowers(anns []*wtwire.TowerAnnouncement, chainHash chainhash.Hash,
+	maxUpdates uint16,
+	policy TowerDiscoveryPolicy) []*wtwire.TowerAnnouncement {
+
+	var selected []*wtwire.TowerAnnouncement
+	for _, ann := range anns {
+		if ann.ChainHash != chainHash || ann.TowerKey == nil {
+			continue
+		}
+
+		features := lnwire.NewFeatureVector(
+			ann.Features, wtwire.FeatureNames,
+		)
+		if !features.HasFeature(wtwire.AltruistSessionsOptional) {
+			continue
+		}
+
+		if ann.MaxSessionUpdates != 0 &&
+			ann.MaxSessionUpdates < maxUpdates {
+
+			continue
+		}
+
+		if ann.MaxStorage != 0 && ann.MaxStorage < policy.MinStorage {
+			continue
+		}
+
+		addrs := reachableTowerAddrs(ann.Addresses, policy)
+		if len(addrs) == 0 {
+			continue
+		}
+
+		selectedAnn := *ann
+		selectedAnn.Addresses = addrs
+		selected = append(selected, &selectedAnn)
+	}
+
+	// Prefer the towers with the most storage, where towers that don't
+	// limit their storage come first. Ties are broken by the tower key so
+	// that the selection is stable.
+	storage := func(ann *wtwire.TowerAnnouncement) uint64 {
+		if ann.MaxStorage == 0 {
+			return ^uint64(0)
+		}
+
+		return ann.MaxStorage
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		si, sj := storage(selected[i]), storage(selected[j])
+		if si != sj {
+			return si > sj
+		}
+
+		return bytes.Compare(
+			selected[i].TowerKey.SerializeCompressed(),
+			selected[j].TowerKey.SerializeCompressed(),
+		) < 0
+	})
+
+	return selected
+}
+
+// reachableTowerAddrs returns the addresses the client can reach according to
+// the given policy.
+func reachableTowerAddrs(addrs []net.Addr,
+	policy TowerDiscoveryPolicy) []net.Addr {
+
+	var reachable []net.Addr
+	for _, addr := range addrs {
+		switch addr.(type) {
+		case *tor.OnionAddr:
+			if !policy.AllowOnion && !policy.TorOnly {
+				continue
+			}
+
+		case *net.TCPAddr:
+			if policy.TorOnly {
+				continue
+			}
+
+		default:
+			continue
+		}
+
+		reachable = append(reachable, addr)
+	}
+
+	return reachable
+}
+
+// discoverTowers periodically adds advertised towers until the client has the
+// configured number of active towers.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (m *Manager) discoverTowers() {
+	defer m.wg.Done()
+
+	interval := m.cfg.TowerDiscovery.Interval
+	if interval == 0 {
+		interval = DefaultTowerDiscoveryInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.addDiscoveredTowers(); err != nil {
+			log.Errorf("Unable to add discovered towers: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// addDiscoveredTowers adds advertised towers that the client doesn't know yet
+// until it has the configured number of active towers. Towers that are known,
+// including those that were deactivated, are never added by discovery.
+func (m *Manager) addDiscoveredTowers() error {
+	policy := m.cfg.TowerDiscovery.Policy
+
+	activeTowers, err := m.cfg.DB.ListTowers(func(t *wtdb.Tower) bool {
+		return t.Status == wtdb.TowerStatusActive
+	})
+	if err != nil {
+		return err
+	}
+
+	numActive := len(activeTowers)
+	if numActive >= policy.MaxTowers {
+		return nil
+	}
+
+	anns, err := m.cfg.TowerDiscovery.FetchTowerAnns()
+	if err != nil {
+		return err
+	}
+
+	// A tower is used by all clients, so it must accept the largest
+	// session any of them negotiates.
+	var maxUpdates uint16
+	m.clientsMu.Lock()
+	for _, client := range m.clients {
+		if client.policy().MaxUpdates > maxUpdates {
+			maxUpdates = client.policy().MaxUpdates
+		}
+	}
+	m.clientsMu.Unlock()
+
+	selected := SelectTowers(anns, m.cfg.ChainHash, maxUpdates, policy)
+	for _, ann := range selected {
+		if numActive >= policy.MaxTowers {
+			break
+		}
+
+		_, err := m.cfg.DB.LoadTower(ann.TowerKey)
+		switch {
+		case err == nil:
+			continue
+
+		case !errors.Is(err, wtdb.ErrTowerNotFound):
+			return err
+		}
+
+		for _, addr := range ann.Addresses {
+			err := m.AddTower(&lnwire.NetAddress{
+				IdentityKey: ann.TowerKey,
+				Address:     addr,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		log.Infof("Added discovered tower %x at %v",
+			ann.TowerKey.SerializeCompressed(), ann.Addresses)
+
+		numActive++
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package wtclient
+
+import (
+	"net"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	discoveryTCPAddr = &net.TCPAddr{IP: net.IP{1, 2, 3, 4}, Port: 9911}
+
+	discoveryOnionAddr = &tor.OnionAddr{
+		OnionService: "abcdefghijklmnop.onion",
+		Port:         9911,
+	}
+)
+
+// newDiscoveryAnn returns an advertisement of an altruist tower on testnet
+// with the given storage that is reachable at the given addresses.
+func newDiscoveryAnn(t *testing.T, maxStorage uint64,
+	addrs ...net.Addr) *wtwire.TowerAnnouncement {
+
+	t.Helper()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &wtwire.TowerAnnouncement{
+		ChainHash: *chaincfg.TestNet3Params.GenesisHash,
+		TowerKey:  priv.PubKey(),
+		Addresses: addrs,
+		Features: lnwire.NewRawFeatureVector(
+			wtwire.AltruistSessionsOptional,
+		),
+		MaxSessionUpdates: 1024,
+		MaxStorage:        maxStorage,
+	}
+}
+
+// TestSelectTowers asserts that only the advertised towers that match the
+// client's chain, session size and policy are selected, in order of the
+// storage they reserve.
+func TestSelectTowers(t *testing.T) {
+	t.Parallel()
+
+	chainHash := *chaincfg.TestNet3Params.GenesisHash
+
+	small := newDiscoveryAnn(t, 1000, discoveryTCPAddr)
+	large := newDiscoveryAnn(t, 5000, discoveryTCPAddr)
+	unlimited := newDiscoveryAnn(t, 0, discoveryTCPAddr)
+	both := newDiscoveryAnn(t, 0, discoveryTCPAddr, discoveryOnionAddr)
+	onion := newDiscoveryAnn(t, 0, discoveryOnionAddr)
+
+	otherChain := newDiscoveryAnn(t, 0, discoveryTCPAddr)
+	otherChain.ChainHash = *chaincfg.MainNetParams.GenesisHash
+
+	noAltruist := newDiscoveryAnn(t, 0, discoveryTCPAddr)
+	noAltruist.Features = lnwire.NewRawFeatureVector()
+
+	smallSessions := newDiscoveryAnn(t, 0, discoveryTCPAddr)
+	smallSessions.MaxSessionUpdates = 10
+
+	anns := []*wtwire.TowerAnnouncement{
+		small, large, otherChain, noAltruist, smallSessions, unlimited,
+	}
+
+	// Only towers on our chain that offer altruist sessions large enough
+	// are selected, those with the most storage first.
+	selected := SelectTowers(anns, chainHash, 1024, TowerDiscoveryPolicy{})
+	require.Equal(
+		t, []*wtwire.TowerAnnouncement{unlimited, large, small},
+		selected,
+	)
+
+	// Towers that reserve too little storage are skipped.
+	selected = SelectTowers(anns, chainHash, 1024, TowerDiscoveryPolicy{
+		MinStorage: 2000,
+	})
+	require.Equal(
+		t, []*wtwire.TowerAnnouncement{unlimited, large}, selected,
+	)
+
+	// Without Tor, onion addresses are dropped, and towers only reachable
+	// over Tor are skipped.
+	selected = SelectTowers(
+		[]*wtwire.TowerAnnouncement{both, onion}, chainHash, 1024,
+		TowerDiscoveryPolicy{},
+	)
+	require.Len(t, selected, 1)
+	require.Equal(t, both.TowerKey, selected[0].TowerKey)
+	require.Equal(t, []net.Addr{discoveryTCPAddr}, selected[0].Addresses)
+
+	// With Tor, all addresses are used.
+	selected = SelectTowers(
+		[]*wtwire.TowerAnnouncement{both, onion}, chainHash, 1024,
+		TowerDiscoveryPolicy{AllowOnion: true},
+	)
+	require.Len(t, selected, 2)
+
+	// Restricted to Tor, only the onion addresses are used.
+	selected = SelectTowers(
+		[]*wtwire.TowerAnnouncement{both, onion, large}, chainHash,
+		1024, TowerDiscoveryPolicy{AllowOnion: true, TorOnly: true},
+	)
+	require.Len(t, selected, 2)
+	for _, ann := range selected {
+		require.Equal(t, []net.Addr{discoveryOnionAddr}, ann.Addresses)
+	}
+
+	// The advertisements that were passed in are left untouched.
+	require.Len(t, both.Addresses, 2)
+}
@@ -0,0 +1,343 @@
+package wtclient
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+)
+
+const (
+	// DefaultHealthCheckInterval is the default interval at which the
+	// client probes its candidate towers.
+	DefaultHealthCheckInterval = 10 * time.Minute
+
+	// DefaultMaxTowerFailures is the default number of consecutive failed
+	// exchanges with a tower after which it is considered dead.
+	DefaultMaxTowerFailures = 5
+
+	// latencyWeight is the weight given to a newly measured round trip
+	// when updating the moving average of a tower's latency.
+	latencyWeight = 0.25
+
+	// backlogWeight is the penalty a single un-acked update adds to the
+	// score of a tower, expressed in seconds of latency. A backlog of 100
+	// updates is thus penalized like a tower that takes an extra second
+	// to respond.
+	backlogWeight = 0.01
+)
+
+// DeadTowerPolicy determines what happens to the backups that were sent to
+// a tower once it is considered dead.
+type DeadTowerPolicy uint8
+
+const (
+	// DeadTowerRebackupNone leaves the sessions with a dead tower as they
+	// are. Any updates that haven't been acked yet are delivered once the
+	// tower comes back.
+	DeadTowerRebackupNone DeadTowerPolicy = iota
+
+	// DeadTowerRebackupUnacked re-queues the updates that the dead tower
+	// hasn't acked yet so that they are backed up to another tower. The
+	// sessions with pending updates are terminated.
+	DeadTowerRebackupUnacked
+
+	// DeadTowerRebackupAll re-queues all updates that were sent to the
+	// dead tower, acked or not, so that they are backed up to another
+	// tower. All sessions with the tower are terminated.
+	DeadTowerRebackupAll
+)
+
+// String returns a human-readable name of the policy.
+func (p DeadTowerPolicy) String() string {
+	switch p {
+	case DeadTowerRebackupNone:
+		return "none"
+
+	case DeadTowerRebackupUnacked:
+		return "unacked"
+
+	case DeadTowerRebackupAll:
+		return "all"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(p))
+	}
+}
+
+// ParseDeadTowerPolicy parses the name of a DeadTowerPolicy.
+func ParseDeadTowerPolicy(name string) (DeadTowerPolicy, error) {
+	switch name {
+	case "none":
+		return DeadTowerRebackupNone, nil
+
+	case "unacked":
+		return DeadTowerRebackupUnacked, nil
+
+	case "all":
+		return DeadTowerRebackupAll, nil
+
+	default:
+		return 0, fmt.Errorf("unknown dead tower policy %q, must be "+
+			"one of none, unacked or all", name)
+	}
+}
+
+// TowerHealth is a snapshot of the health the client observed for a tower.
+type TowerHealth struct {
+	// ConsecutiveFailures is the number of exchanges with the tower that
+	// failed since the last successful one.
+	ConsecutiveFailures uint32
+
+	// LastSuccess is the time of the last successful exchange with the
+	// tower. It is the zero time if there was none since startup.
+	LastSuccess time.Time
+
+	// LastFailure is the time of the last failed exchange with the tower.
+	// It is the zero time if there was none since startup.
+	LastFailure time.Time
+
+	// Latency is the moving average of the time the tower takes to
+	// respond to us.
+	Latency time.Duration
+
+	// Backlog is the number of updates queued for the tower that it
+	// hasn't acked yet.
+	Backlog uint32
+
+	// Score ranks the tower amongst the other towers. It ranges from 0 to
+	// 1 and is higher for towers that respond faster, have a smaller
+	// backlog and fail less.
+	Score float64
+
+	// Dead is true if the tower failed too many exchanges in a row. Dead
+	// towers are only used for new sessions if no other tower is
+	// available.
+	Dead bool
+}
+
+// towerHealth holds the observed health of a single tower.
+type towerHealth struct {
+	failures    uint32
+	lastSuccess time.Time
+	lastFailure time.Time
+	latency     time.Duration
+	dead        bool
+
+	// backlogs holds the number of un-acked updates of each of the
+	// tower's session queues.
+	backlogs map[wtdb.SessionID]uint32
+}
+
+// backlog returns the total number of un-acked updates queued for the tower.
+func (h *towerHealth) backlog() uint32 {
+	var backlog uint32
+	for _, n := range h.backlogs {
+		backlog += n
+	}
+
+	return backlog
+}
+
+// score returns the score of the tower as described by TowerHealth.
+func (h *towerHealth) score() float64 {
+	penalty := h.latency.Seconds() + float64(h.failures) +
+		float64(h.backlog())*backlogWeight
+
+	return 1 / (1 + penalty)
+}
+
+// towerHealthTracker keeps track of the health of the towers the client
+// exchanges messages with. Exchanges are reported by the session queues as
+// they upload updates, and by the client as it probes its candidate towers.
+type towerHealthTracker struct {
+	mu sync.Mutex
+
+	// maxFailures is the number of consecutive failures after which a
+	// tower is considered dead.
+	maxFailures uint32
+
+	towers map[wtdb.TowerID]*towerHealth
+
+	// changed holds the towers that died or came back since the last call
+	// to statusChanges, mapped to whether they are dead now.
+	changed map[wtdb.TowerID]bool
+
+	// statusChange is signaled whenever a tower dies or comes back.
+	statusChange chan struct{}
+}
+
+// newTowerHealthTracker creates a new towerHealthTracker that considers a
+// tower dead after the given number of consecutive failures.
+func newTowerHealthTracker(maxFailures uint32) *towerHealthTracker {
+	return &towerHealthTracker{
+		maxFailures:  maxFailures,
+		towers:       make(map[wtdb.TowerID]*towerHealth),
+		changed:      make(map[wtdb.TowerID]bool),
+		statusChange: make(chan struct{}, 1),
+	}
+}
+
+// get returns the health of the given tower, creating it if needed.
+//
+// NOTE: This method MUST be called with the tracker's mutex held.
+func (t *towerHealthTracker) get(id wtdb.TowerID) *towerHealth {
+	health, ok := t.towers[id]
+	if !ok {
+		health = &towerHealth{
+			backlogs: make(map[wtdb.SessionID]uint32),
+		}
+		t.towers[id] = health
+	}
+
+	return health
+}
+
+// setDead updates whether the tower is dead and records the change.
+//
+// NOTE: This method MUST be called with the tracker's mutex held.
+func (t *towerHealthTracker) setDead(id wtdb.TowerID, health *towerHealth,
+	dead bool) {
+
+	if health.dead == dead {
+		return
+	}
+	health.dead = dead
+
+	// If the tower changed back before the last change was consumed,
+	// there's nothing to act upon.
+	if _, ok := t.changed[id]; ok {
+		delete(t.changed, id)
+		return
+	}
+	t.changed[id] = dead
+
+	select {
+	case t.statusChange <- struct{}{}:
+	default:
+	}
+}
+
+// recordSuccess records a successful exchange with the tower that took the
+// given round trip time.
+func (t *towerHealthTracker) recordSuccess(id wtdb.TowerID,
+	rtt time.Duration) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health := t.get(id)
+	health.failures = 0
+	health.lastSuccess = time.Now()
+
+	if health.latency == 0 {
+		health.latency = rtt
+	} else {
+		health.latency = time.Duration(
+			latencyWeight*float64(rtt) +
+				(1-latencyWeight)*float64(health.latency),
+		)
+	}
+
+	t.setDead(id, health, false)
+}
+
+// recordFailure records a failed exchange with the tower.
+func (t *towerHealthTracker) recordFailure(id wtdb.TowerID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health := t.get(id)
+	health.failures++
+	health.lastFailure = time.Now()
+
+	if t.maxFailures > 0 && health.failures >= t.maxFailures {
+		t.setDead(id, health, true)
+	}
+}
+
+// setBacklog records the number of un-acked updates of the given session
+// with the tower.
+func (t *towerHealthTracker) setBacklog(id wtdb.TowerID,
+	session wtdb.SessionID, backlog uint32) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if backlog == 0 {
+		if health, ok := t.towers[id]; ok {
+			delete(health.backlogs, session)
+		}
+
+		return
+	}
+	t.get(id).backlogs[session] = backlog
+}
+
+// isDead returns true if the given tower is considered dead.
+func (t *towerHealthTracker) isDead(id wtdb.TowerID) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, ok := t.towers[id]
+
+	return ok && health.dead
+}
+
+// score returns the score of the given tower. Towers that we haven't
+// exchanged any messages with yet have a perfect score, so that new towers
+// are tried out.
+func (t *towerHealthTracker) score(id wtdb.TowerID) float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, ok := t.towers[id]
+	if !ok {
+		return 1
+	}
+
+	return health.score()
+}
+
+// health returns a snapshot of the health of the given tower.
+func (t *towerHealthTracker) health(id wtdb.TowerID) *TowerHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, ok := t.towers[id]
+	if !ok {
+		return &TowerHealth{Score: 1}
+	}
+
+	return &TowerHealth{
+		ConsecutiveFailures: health.failures,
+		LastSuccess:         health.lastSuccess,
+		LastFailure:         health.lastFailure,
+		Latency:             health.latency,
+		Backlog:             health.backlog(),
+		Score:               health.score(),
+		Dead:                health.dead,
+	}
+}
+
+// statusChanges returns the towers that died or came back since the last call,
+// mapped to whether they are dead now.
+func (t *towerHealthTracker) statusChanges() map[wtdb.TowerID]bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	changed := t.changed
+	t.changed = make(map[wtdb.TowerID]bool)
+
+	return changed
+}
+
+// removeTower forgets the health of the given tower.
+func (t *towerHealthTracker) removeTower(id wtdb.TowerID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.towers, id)
+	delete(t.changed, id)
+}
@@ -0,0 +1,149 @@
+package wtclient
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTowerHealthTracker asserts that the tracker declares towers dead after
+// the configured number of consecutive failures, and alive again after a
+// successful exchange.
+func TestTowerHealthTracker(t *testing.T) {
+	t.Parallel()
+
+	const maxFailures = 3
+
+	tracker := newTowerHealthTracker(maxFailures)
+	tower := wtdb.TowerID(1)
+
+	// A tower we haven't heard of yet has a perfect score.
+	require.False(t, tracker.isDead(tower))
+	require.EqualValues(t, 1, tracker.score(tower))
+
+	// The tower isn't dead until it failed maxFailures times in a row.
+	for i := 0; i < maxFailures-1; i++ {
+		tracker.recordFailure(tower)
+	}
+	require.False(t, tracker.isDead(tower))
+	require.Empty(t, tracker.statusChanges())
+
+	tracker.recordFailure(tower)
+	require.True(t, tracker.isDead(tower))
+	require.True(t, tracker.health(tower).Dead)
+	require.EqualValues(
+		t, maxFailures, tracker.health(tower).ConsecutiveFailures,
+	)
+
+	// The status change must be signaled and reported once.
+	select {
+	case <-tracker.statusChange:
+	default:
+		t.Fatalf("expected status change to be signaled")
+	}
+	require.Equal(
+		t, map[wtdb.TowerID]bool{tower: true}, tracker.statusChanges(),
+	)
+	require.Empty(t, tracker.statusChanges())
+
+	// A single success brings the tower back.
+	tracker.recordSuccess(tower, time.Second)
+	require.False(t, tracker.isDead(tower))
+	require.Equal(
+		t, map[wtdb.TowerID]bool{tower: false}, tracker.statusChanges(),
+	)
+
+	// If a tower dies and comes back before the change is consumed, there
+	// is nothing to report.
+	for i := 0; i < maxFailures; i++ {
+		tracker.recordFailure(tower)
+	}
+	tracker.recordSuccess(tower, time.Second)
+	require.Empty(t, tracker.statusChanges())
+
+	// Once removed, the tower is forgotten.
+	tracker.removeTower(tower)
+	require.EqualValues(t, 1, tracker.score(tower))
+}
+
+// TestTowerHealthScore asserts that the score of a tower decreases with its
+// latency and backlog.
+func TestTowerHealthScore(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTowerHealthTracker(DefaultMaxTowerFailures)
+
+	fast := wtdb.TowerID(1)
+	slow := wtdb.TowerID(2)
+
+	tracker.recordSuccess(fast, 100*time.Millisecond)
+	tracker.recordSuccess(slow, 2*time.Second)
+	require.Greater(t, tracker.score(fast), tracker.score(slow))
+
+	// The latency is a moving average of the measured round trips.
+	tracker.recordSuccess(slow, 6*time.Second)
+	require.Equal(t, 3*time.Second, tracker.health(slow).Latency)
+
+	// A large enough backlog makes the fast tower rank below the slow one.
+	session1 := wtdb.SessionID{1}
+	session2 := wtdb.SessionID{2}
+	tracker.setBacklog(fast, session1, 300)
+	tracker.setBacklog(fast, session2, 100)
+	require.EqualValues(t, 400, tracker.health(fast).Backlog)
+	require.Less(t, tracker.score(fast), tracker.score(slow))
+
+	// Clearing the backlog of a session only removes that session's
+	// updates.
+	tracker.setBacklog(fast, session1, 0)
+	require.EqualValues(t, 100, tracker.health(fast).Backlog)
+}
+
+// TestHealthRankedIterator asserts that the healthRankedIterator returns the
+// candidates of each pass ordered by their health.
+func TestHealthRankedIterator(t *testing.T) {
+	t.Parallel()
+
+	tracker := newTowerHealthTracker(1)
+
+	tower1 := randTower(t)
+	tower2 := randTower(t)
+	tower3 := randTower(t)
+
+	iter := newHealthRankedIterator(
+		newTowerListIterator(tower1, tower2, tower3), tracker,
+	)
+
+	// The first tower is dead and the second one is slower than the
+	// third.
+	tracker.recordFailure(tower1.ID)
+	tracker.recordSuccess(tower2.ID, 2*time.Second)
+	tracker.recordSuccess(tower3.ID, time.Second)
+
+	assertNextCandidate(t, iter, tower3)
+	assertNextCandidate(t, iter, tower2)
+	assertNextCandidate(t, iter, tower1)
+
+	_, err := iter.Next()
+	require.ErrorIs(t, err, ErrTowerCandidatesExhausted)
+
+	// If the health changes in the middle of a pass, the remaining
+	// candidates are ranked by their new health.
+	require.NoError(t, iter.Reset())
+	assertNextCandidate(t, iter, tower3)
+
+	tracker.recordSuccess(tower1.ID, 100*time.Millisecond)
+	assertNextCandidate(t, iter, tower1)
+
+	// Candidates that are removed are no longer returned, while new ones
+	// are picked up during the pass.
+	require.NoError(t, iter.RemoveCandidate(tower2.ID, nil))
+
+	tower4 := randTower(t)
+	iter.AddCandidate(tower4)
+	assertNextCandidate(t, iter, tower4)
+
+	_, err = iter.Next()
+	require.ErrorIs(t, err, ErrTowerCandidatesExhausted)
+}
@@ -2,8 +2,10 @@ package wtdb
 
 import (
 	"io"
+	"sort"
 
 	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -25,6 +27,75 @@ type ChannelInfo struct {
 	// a commitment height of zero is valid, and we need a way of knowing if
 	// we have seen a new height yet or not.
 	MaxHeight fn.Option[uint64]
+
+	// BackupPolicy holds the backup settings the user chose for this
+	// channel.
+	BackupPolicy ChannelBackupPolicy
+}
+
+// ChannelBackupPolicy holds the backup settings of a single channel.
+type ChannelBackupPolicy struct {
+	// Excluded indicates that the states of the channel should not be
+	// backed up.
+	Excluded bool
+
+	// MinSweepFeeRate is the minimum fee rate the justice transactions of
+	// the channel must pay. States are only backed up to sessions whose
+	// sweep fee rate is at least this high. If zero, the channel is backed
+	// up under any session.
+	MinSweepFeeRate chainfee.SatPerKWeight
+}
+
+// Encode writes the ChannelBackupPolicy to the passed io.Writer.
+func (p *ChannelBackupPolicy) Encode(w io.Writer) error {
+	return WriteElements(w, p.Excluded, uint64(p.MinSweepFeeRate))
+}
+
+// Decode reads a ChannelBackupPolicy from the passed io.Reader.
+func (p *ChannelBackupPolicy) Decode(r io.Reader) error {
+	var minSweepFeeRate uint64
+	if err := ReadElements(r, &p.Excluded, &minSweepFeeRate); err != nil {
+		return err
+	}
+	p.MinSweepFeeRate = chainfee.SatPerKWeight(minSweepFeeRate)
+
+	return nil
+}
+
+// HeightRange is an inclusive range of commitment heights.
+type HeightRange struct {
+	// Start is the first commitment height of the range.
+	Start uint64
+
+	// End is the last commitment height of the range.
+	End uint64
+}
+
+// mergeHeightRanges sorts the given ranges and merges those that overlap or
+// are adjacent.
+func mergeHeightRanges(ranges []HeightRange) []HeightRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+
+	merged := []HeightRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start > last.End+1 {
+			merged = append(merged, r)
+			continue
+		}
+
+		if r.End > last.End {
+			last.End = r.End
+		}
+	}
+
+	return merged
 }
 
 // ClientChanSummary tracks channel-specific information. A new
@@ -27,6 +27,7 @@ var (
 	// 		=> cChanSessions => db-session-id -> 1
 	// 		=> cChanClosedHeight -> block-height
 	// 		=> cChanMaxCommitmentHeight -> commitment-height
+	// 		=> cChanBackupPolicy -> encoded ChannelBackupPolicy
 	cChanDetailsBkt = []byte("client-channel-detail-bucket")
 
 	// cChanSessions is a sub-bucket of cChanDetailsBkt which stores:
@@ -54,6 +55,11 @@ var (
 		"client-channel-max-commitment-height",
 	)
 
+	// cChanBackupPolicy is a key used in the cChanDetailsBkt to store the
+	// encoded ChannelBackupPolicy of the channel. If there is no value for
+	// this key, the channel is backed up under the client's policy.
+	cChanBackupPolicy = []byte("client-channel-backup-policy")
+
 	// cSessionBkt is a top-level bucket storing:
 	//   session-id => cSessionBody -> encoded ClientSessionBody
 	// 		=> cSessionDBID -> db-assigned-id
@@ -110,6 +116,11 @@ var (
 	// 	db-session-id -> last-channel-close-height
 	cClosableSessionsBkt = []byte("client-closable-sessions-bucket")
 
+	// cJusticeReportsBkt is a top-level bucket storing the justice
+	// transactions reported by the client's towers:
+	// 	session-id || seqnum -> encoded ClientJusticeReport
+	cJusticeReportsBkt = []byte("client-justice-reports-bucket")
+
 	// cTaskQueue is a top-level bucket where the disk queue may store its
 	// content.
 	cTaskQueue = []byte("client-task-queue")
@@ -305,6 +316,7 @@ func initClientDBBuckets(tx kvdb.RwTx) error {
 		cChanIDIndexBkt,
 		cSessionIDIndexBkt,
 		cClosableSessionsBkt,
+		cJusticeReportsBkt,
 	}
 
 	for _, bucket := range buckets {
@@ -1345,6 +1357,68 @@ func (c *ClientDB) NumAckedUpdates(id *SessionID) (uint64, error) {
 	return numAcked, nil
 }
 
+// FetchAckedUpdates returns the backups that have been acked by the tower of
+// the given session. Rogue updates, which are updates for channels that have
+// since been closed, are not included.
+func (c *ClientDB) FetchAckedUpdates(id *SessionID) ([]BackupID, error) {
+	var backupIDs []BackupID
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(cSessionBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		chanIDIndexBkt := tx.ReadBucket(cChanIDIndexBkt)
+		if chanIDIndexBkt == nil {
+			return ErrUninitializedDB
+		}
+
+		sessionBkt := sessions.NestedReadBucket(id[:])
+		if sessionBkt == nil {
+			return ErrClientSessionNotFound
+		}
+
+		sessionAckRanges := sessionBkt.NestedReadBucket(
+			cSessionAckRangeIndex,
+		)
+		if sessionAckRanges == nil {
+			return nil
+		}
+
+		// Iterate over the channel ID's in the sessionAckRanges
+		// bucket and expand each of the channel's ranges into the
+		// commit heights it covers.
+		return sessionAckRanges.ForEach(func(dbChanID, _ []byte) error {
+			chanIDBytes := chanIDIndexBkt.Get(dbChanID)
+			var chanID lnwire.ChannelID
+			copy(chanID[:], chanIDBytes)
+
+			index, err := c.getRangeIndex(tx, *id, chanID)
+			if err != nil {
+				return err
+			}
+
+			for start, end := range index.GetAllRanges() {
+				for height := start; height <= end; height++ {
+					backupIDs = append(backupIDs, BackupID{
+						ChanID:       chanID,
+						CommitHeight: height,
+					})
+				}
+			}
+
+			return nil
+		})
+	}, func() {
+		backupIDs = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backupIDs, nil
+}
+
 // FetchChanInfos loads a mapping from all registered channels to their
 // ChannelInfo. Only the channels that have not yet been marked as closed will
 // be loaded.
@@ -1391,6 +1465,16 @@ func (c *ClientDB) FetchChanInfos() (ChannelInfos, error) {
 				info.MaxHeight = fn.Some(height)
 			}
 
+			policyBytes := chanDetails.Get(cChanBackupPolicy)
+			if len(policyBytes) != 0 {
+				err := info.BackupPolicy.Decode(
+					bytes.NewReader(policyBytes),
+				)
+				if err != nil {
+					return err
+				}
+			}
+
 			infos[chanID] = info
 
 			return nil
@@ -1469,6 +1553,123 @@ func (c *ClientDB) RegisterChannel(chanID lnwire.ChannelID,
 	}, func() {})
 }
 
+// SetChannelBackupPolicy persists the backup settings of a registered channel.
+// ErrChannelNotRegistered is returned if the channel is unknown or has been
+// closed.
+func (c *ClientDB) SetChannelBackupPolicy(chanID lnwire.ChannelID,
+	policy *ChannelBackupPolicy) error {
+
+	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		chanDetailsBkt := tx.ReadWriteBucket(cChanDetailsBkt)
+		if chanDetailsBkt == nil {
+			return ErrUninitializedDB
+		}
+
+		chanDetails := chanDetailsBkt.NestedReadWriteBucket(chanID[:])
+		if chanDetails == nil {
+			return ErrChannelNotRegistered
+		}
+
+		if len(chanDetails.Get(cChanClosedHeight)) > 0 {
+			return ErrChannelNotRegistered
+		}
+
+		var b bytes.Buffer
+		if err := policy.Encode(&b); err != nil {
+			return err
+		}
+
+		return chanDetails.Put(cChanBackupPolicy, b.Bytes())
+	}, func() {})
+}
+
+// FetchChannelAckedRanges returns the commitment heights of the given channel
+// that were acked by a tower in any session, merged into sorted ranges.
+func (c *ClientDB) FetchChannelAckedRanges(chanID lnwire.ChannelID) (
+	[]HeightRange, error) {
+
+	var ranges []HeightRange
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		sessionsBkt := tx.ReadBucket(cSessionBkt)
+		if sessionsBkt == nil {
+			return ErrUninitializedDB
+		}
+
+		chanDetailsBkt := tx.ReadBucket(cChanDetailsBkt)
+		if chanDetailsBkt == nil {
+			return ErrUninitializedDB
+		}
+
+		sessIDIndexBkt := tx.ReadBucket(cSessionIDIndexBkt)
+		if sessIDIndexBkt == nil {
+			return ErrUninitializedDB
+		}
+
+		_, dbChanIDBytes, err := getDBChanID(chanDetailsBkt, chanID)
+		if err != nil {
+			return err
+		}
+
+		chanDetails := chanDetailsBkt.NestedReadBucket(chanID[:])
+		chanSessIDsBkt := chanDetails.NestedReadBucket(cChanSessions)
+		if chanSessIDsBkt == nil {
+			return nil
+		}
+
+		return chanSessIDsBkt.ForEach(func(sessDBID, _ []byte) error {
+			sessDBIDInt, err := readBigSize(sessDBID)
+			if err != nil {
+				return err
+			}
+
+			sID, err := getRealSessionID(
+				sessIDIndexBkt, sessDBIDInt,
+			)
+			if err != nil {
+				return err
+			}
+
+			sessionBkt := sessionsBkt.NestedReadBucket(sID[:])
+			if sessionBkt == nil {
+				return ErrClientSessionNotFound
+			}
+
+			ackRanges := sessionBkt.NestedReadBucket(
+				cSessionAckRangeIndex,
+			)
+			if ackRanges == nil {
+				return nil
+			}
+
+			chanRanges := ackRanges.NestedReadBucket(dbChanIDBytes)
+			if chanRanges == nil {
+				return nil
+			}
+
+			index, err := readRangeIndex(chanRanges)
+			if err != nil {
+				return err
+			}
+
+			for start, end := range index.GetAllRanges() {
+				ranges = append(ranges, HeightRange{
+					Start: start,
+					End:   end,
+				})
+			}
+
+			return nil
+		})
+	}, func() {
+		ranges = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeHeightRanges(ranges), nil
+}
+
 // MarkBackupIneligible records that the state identified by the (channel id,
 // commit height) tuple was ineligible for being backed up under the current
 // policy. This state can be retried later under a different policy.
@@ -1518,6 +1719,59 @@ func (c *ClientDB) ListClosableSessions() (map[SessionID]uint32, error) {
 	return sessions, nil
 }
 
+// AddJusticeReport persists a justice report received from a tower. A report
+// for the same state update of the session replaces the existing one.
+func (c *ClientDB) AddJusticeReport(report *ClientJusticeReport) error {
+	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		reports := tx.ReadWriteBucket(cJusticeReportsBkt)
+		if reports == nil {
+			return ErrUninitializedDB
+		}
+
+		var b bytes.Buffer
+		if err := report.Encode(&b); err != nil {
+			return err
+		}
+
+		var key [SessionIDSize + 2]byte
+		copy(key[:], report.SessionID[:])
+		byteOrder.PutUint16(key[SessionIDSize:], report.SeqNum)
+
+		return reports.Put(key[:], b.Bytes())
+	}, func() {})
+}
+
+// ListJusticeReports returns all justice reports received from the client's
+// towers, in the order of their session IDs and sequence numbers.
+func (c *ClientDB) ListJusticeReports() ([]*ClientJusticeReport, error) {
+	var reports []*ClientJusticeReport
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		reportsBkt := tx.ReadBucket(cJusticeReportsBkt)
+		if reportsBkt == nil {
+			return ErrUninitializedDB
+		}
+
+		return reportsBkt.ForEach(func(_, v []byte) error {
+			var report ClientJusticeReport
+			err := report.Decode(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			reports = append(reports, &report)
+
+			return nil
+		})
+	}, func() {
+		reports = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
 // DeleteSession can be called when a session should be deleted from the DB.
 // All references to the session will also be deleted from the DB. Note that a
 // session will only be deleted if was previously marked as closable.
@@ -2373,6 +2627,162 @@ func (c *ClientDB) DeleteCommittedUpdates(id *SessionID) error {
 	}, func() {})
 }
 
+// DBStats returns the storage statistics of the client database.
+func (c *ClientDB) DBStats() (*ClientDBStats, error) {
+	var stats *ClientDBStats
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		stats = &ClientDBStats{}
+
+		sessions := tx.ReadBucket(cSessionBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		closableBkt := tx.ReadBucket(cClosableSessionsBkt)
+		if closableBkt == nil {
+			return ErrUninitializedDB
+		}
+
+		err := closableBkt.ForEach(func(_, _ []byte) error {
+			stats.NumClosableSessions++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		err = sessions.ForEach(func(id, _ []byte) error {
+			sessionBkt := sessions.NestedReadBucket(id)
+			if sessionBkt == nil {
+				return nil
+			}
+
+			stats.NumSessions++
+
+			return addSessionStats(sessionBkt, stats)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, blobType := range queueBlobTypes {
+			namespace, err := blobType.Identifier()
+			if err != nil {
+				return err
+			}
+
+			err = addQueueStats(tx, []byte(namespace), stats)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {
+		stats = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// queueBlobTypes are the blob types a client keeps a backup queue for. The
+// queues are namespaced by the identifiers of the blob types.
+var queueBlobTypes = []blob.Type{
+	blob.TypeAltruistCommit,
+	blob.TypeAltruistAnchorCommit,
+	blob.TypeRewardCommit,
+	blob.TypeAltruistTaprootCommit,
+}
+
+// addSessionStats adds the committed and acked updates of the given session
+// bucket to the stats.
+func addSessionStats(sessionBkt kvdb.RBucket, stats *ClientDBStats) error {
+	commits := sessionBkt.NestedReadBucket(cSessionCommits)
+	if commits != nil {
+		err := commits.ForEach(func(_, v []byte) error {
+			stats.NumCommittedUpdates++
+			stats.CommittedBytes += uint64(len(v))
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	ackRanges := sessionBkt.NestedReadBucket(cSessionAckRangeIndex)
+	if ackRanges == nil {
+		return nil
+	}
+
+	return ackRanges.ForEach(func(chanDBID, _ []byte) error {
+		rangesBkt := ackRanges.NestedReadBucket(chanDBID)
+		if rangesBkt == nil {
+			return nil
+		}
+
+		return rangesBkt.ForEach(func(k, v []byte) error {
+			start, err := readBigSize(k)
+			if err != nil {
+				return err
+			}
+
+			end, err := readBigSize(v)
+			if err != nil {
+				return err
+			}
+
+			stats.NumAckedRanges++
+			stats.NumAckedUpdates += end - start + 1
+
+			return nil
+		})
+	})
+}
+
+// addQueueStats adds the items of the disk queue with the given namespace to
+// the stats.
+func addQueueStats(tx kvdb.RTx, namespace []byte,
+	stats *ClientDBStats) error {
+
+	namespacedBkt := tx.ReadBucket(namespace)
+	if namespacedBkt == nil {
+		return nil
+	}
+
+	tasksBkt := namespacedBkt.NestedReadBucket(cTaskQueue)
+	if tasksBkt == nil {
+		return nil
+	}
+
+	for _, queueName := range [][]byte{queueHeadBkt, queueMainBkt} {
+		queueBkt := tasksBkt.NestedReadBucket(queueName)
+		if queueBkt == nil {
+			continue
+		}
+
+		items := queueBkt.NestedReadBucket(itemsBkt)
+		if items == nil {
+			continue
+		}
+
+		err := items.ForEach(func(_, v []byte) error {
+			stats.NumQueuedBackups++
+			stats.QueuedBytes += uint64(len(v))
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // putChannelToSessionMapping adds the given session ID to a channel's
 // cChanSessions bucket.
 func putChannelToSessionMapping(chanDetails kvdb.RwBucket,
@@ -316,6 +316,17 @@ func (h *clientDBHarness) numAcked(id *wtdb.SessionID, expErr error) uint64 {
 	return numAcked
 }
 
+func (h *clientDBHarness) fetchAckedUpdates(id *wtdb.SessionID,
+	expErr error) []wtdb.BackupID {
+
+	h.t.Helper()
+
+	backupIDs, err := h.db.FetchAckedUpdates(id)
+	require.ErrorIs(h.t, err, expErr)
+
+	return backupIDs
+}
+
 // testCreateClientSession asserts various conditions regarding the creation of
 // a new ClientSession. The test asserts:
 //   - client sessions can only be created if a session key index is reserved.
@@ -949,6 +960,276 @@ func testMaxCommitmentHeights(h *clientDBHarness) {
 	})
 }
 
+// testFetchAckedUpdates asserts that FetchAckedUpdates returns exactly the
+// backups that were acked for a session.
+func testFetchAckedUpdates(h *clientDBHarness) {
+	const maxUpdates = 5
+	t := h.t
+
+	tower := h.newTower()
+
+	// Fetching the acked updates of an unknown session should fail.
+	session := h.randSession(t, tower.ID, maxUpdates)
+	h.fetchAckedUpdates(&session.ID, wtdb.ErrClientSessionNotFound)
+
+	// A new session has no acked updates.
+	h.insertSession(session, nil)
+	require.Empty(t, h.fetchAckedUpdates(&session.ID, nil))
+
+	chanID1 := randChannelID(t)
+	chanID2 := randChannelID(t)
+	h.registerChan(chanID1, nil, nil)
+	h.registerChan(chanID2, nil, nil)
+
+	// Ack a few updates for two channels, leaving a gap in the commit
+	// heights of the first one.
+	backups := []wtdb.BackupID{
+		{ChanID: chanID1, CommitHeight: 1},
+		{ChanID: chanID1, CommitHeight: 2},
+		{ChanID: chanID1, CommitHeight: 5},
+		{ChanID: chanID2, CommitHeight: 3},
+	}
+	for i, backup := range backups {
+		update := randCommittedUpdateForChanWithHeight(
+			t, backup.ChanID, uint16(i+1), backup.CommitHeight,
+		)
+		lastApplied := h.commitUpdate(&session.ID, update, nil)
+		h.ackUpdate(&session.ID, uint16(i+1), lastApplied, nil)
+	}
+
+	// Committed but un-acked updates must not be returned.
+	update := randCommittedUpdateForChanWithHeight(
+		t, chanID2, uint16(len(backups)+1), 4,
+	)
+	h.commitUpdate(&session.ID, update, nil)
+
+	require.ElementsMatch(t, backups, h.fetchAckedUpdates(&session.ID, nil))
+}
+
+// testChannelBackupPolicy asserts that the backup settings of a channel are
+// persisted and only accepted for open registered channels.
+func testChannelBackupPolicy(h *clientDBHarness) {
+	t := h.t
+
+	policy := &wtdb.ChannelBackupPolicy{
+		Excluded:        true,
+		MinSweepFeeRate: 2500,
+	}
+
+	// Setting the policy of an unknown channel should fail.
+	chanID := randChannelID(t)
+	err := h.db.SetChannelBackupPolicy(chanID, policy)
+	require.ErrorIs(t, err, wtdb.ErrChannelNotRegistered)
+
+	// A newly registered channel has the default policy.
+	h.registerChan(chanID, nil, nil)
+	infos := h.fetchChanInfos()
+	require.Equal(t, wtdb.ChannelBackupPolicy{}, infos[chanID].BackupPolicy)
+
+	// Once set, the policy is loaded with the channel.
+	require.NoError(t, h.db.SetChannelBackupPolicy(chanID, policy))
+	infos = h.fetchChanInfos()
+	require.Equal(t, *policy, infos[chanID].BackupPolicy)
+
+	// The policy can be reset again.
+	policy = &wtdb.ChannelBackupPolicy{}
+	require.NoError(t, h.db.SetChannelBackupPolicy(chanID, policy))
+	infos = h.fetchChanInfos()
+	require.Equal(t, *policy, infos[chanID].BackupPolicy)
+
+	// The policy of a closed channel can't be changed. The channel needs a
+	// session for its details to be kept after the close.
+	tower := h.newTower()
+	session := h.randSession(t, tower.ID, 5)
+	h.insertSession(session, nil)
+
+	update := randCommittedUpdateForChanWithHeight(t, chanID, 1, 1)
+	lastApplied := h.commitUpdate(&session.ID, update, nil)
+	h.ackUpdate(&session.ID, 1, lastApplied, nil)
+
+	h.markChannelClosed(chanID, 100, nil)
+	err = h.db.SetChannelBackupPolicy(chanID, policy)
+	require.ErrorIs(t, err, wtdb.ErrChannelNotRegistered)
+}
+
+// testClientJusticeReports asserts that justice reports are listed in the order of
+// their sessions and sequence numbers, and that a report for the same state
+// update replaces the existing one.
+func testClientJusticeReports(h *clientDBHarness) {
+	t := h.t
+
+	reports, err := h.db.ListJusticeReports()
+	require.NoError(t, err)
+	require.Empty(t, reports)
+
+	tower := h.newTower()
+
+	report := func(id wtdb.SessionID, seqNum uint16,
+		txid byte) *wtdb.ClientJusticeReport {
+
+		return &wtdb.ClientJusticeReport{
+			JusticeReport: wtdb.JusticeReport{
+				SeqNum:      seqNum,
+				BreachTxID:  [32]byte{txid},
+				JusticeTxID: [32]byte{txid, 1},
+			},
+			TowerPubKey: tower.IdentityKey,
+			SessionID:   id,
+		}
+	}
+
+	report1 := report(*id(0), 3, 1)
+	report2 := report(*id(0), 1, 2)
+	report3 := report(*id(1), 1, 3)
+	for _, r := range []*wtdb.ClientJusticeReport{
+		report3, report1, report2,
+	} {
+		require.NoError(t, h.db.AddJusticeReport(r))
+	}
+
+	reports, err = h.db.ListJusticeReports()
+	require.NoError(t, err)
+	require.Equal(
+		t, []*wtdb.ClientJusticeReport{report2, report1, report3},
+		reports,
+	)
+
+	// Adding a report for the same state update replaces it.
+	report1 = report(*id(0), 3, 4)
+	require.NoError(t, h.db.AddJusticeReport(report1))
+
+	reports, err = h.db.ListJusticeReports()
+	require.NoError(t, err)
+	require.Equal(
+		t, []*wtdb.ClientJusticeReport{report2, report1, report3},
+		reports,
+	)
+}
+
+// testFetchChannelAckedRanges asserts that the heights of a channel acked in
+// different sessions are merged into sorted ranges.
+func testFetchChannelAckedRanges(h *clientDBHarness) {
+	const maxUpdates = 5
+	t := h.t
+
+	// Fetching the ranges of an unknown channel should fail.
+	chanID1 := randChannelID(t)
+	_, err := h.db.FetchChannelAckedRanges(chanID1)
+	require.ErrorIs(t, err, wtdb.ErrChannelNotRegistered)
+
+	// A channel without acked updates has no ranges.
+	chanID2 := randChannelID(t)
+	h.registerChan(chanID1, nil, nil)
+	h.registerChan(chanID2, nil, nil)
+
+	ranges, err := h.db.FetchChannelAckedRanges(chanID1)
+	require.NoError(t, err)
+	require.Empty(t, ranges)
+
+	tower := h.newTower()
+	// Each session needs its own key index reservation, so a session is
+	// only created once the previous one was inserted.
+	session1 := h.randSession(t, tower.ID, maxUpdates)
+	h.insertSession(session1, nil)
+	session2 := h.randSession(t, tower.ID, maxUpdates)
+	h.insertSession(session2, nil)
+
+	ack := func(session *wtdb.ClientSession, seqNum uint16,
+		chanID lnwire.ChannelID, height uint64) {
+
+		update := randCommittedUpdateForChanWithHeight(
+			t, chanID, seqNum, height,
+		)
+		lastApplied := h.commitUpdate(&session.ID, update, nil)
+		h.ackUpdate(&session.ID, seqNum, lastApplied, nil)
+	}
+
+	// The first session acks heights 1, 2 and 6 of the first channel, the
+	// second one heights 3 and 8, which are adjacent to or apart from the
+	// ranges of the first session.
+	ack(session1, 1, chanID1, 1)
+	ack(session1, 2, chanID1, 2)
+	ack(session1, 3, chanID1, 6)
+	ack(session1, 4, chanID2, 4)
+	ack(session2, 1, chanID1, 3)
+	ack(session2, 2, chanID1, 8)
+
+	// A committed update that isn't acked yet isn't protected.
+	update := randCommittedUpdateForChanWithHeight(t, chanID1, 3, 7)
+	h.commitUpdate(&session2.ID, update, nil)
+
+	ranges, err = h.db.FetchChannelAckedRanges(chanID1)
+	require.NoError(t, err)
+	require.Equal(t, []wtdb.HeightRange{
+		{Start: 1, End: 3},
+		{Start: 6, End: 6},
+		{Start: 8, End: 8},
+	}, ranges)
+
+	ranges, err = h.db.FetchChannelAckedRanges(chanID2)
+	require.NoError(t, err)
+	require.Equal(t, []wtdb.HeightRange{{Start: 4, End: 4}}, ranges)
+}
+
+// testDBStats asserts that DBStats reports the sessions, updates and queued
+// backups stored in the database.
+func testDBStats(h *clientDBHarness) {
+	const maxUpdates = 5
+	t := h.t
+
+	stats, err := h.db.DBStats()
+	require.NoError(t, err)
+	require.Equal(t, &wtdb.ClientDBStats{}, stats)
+
+	tower := h.newTower()
+	session := h.randSession(t, tower.ID, maxUpdates)
+	h.insertSession(session, nil)
+
+	chanID := randChannelID(t)
+	h.registerChan(chanID, nil, nil)
+
+	// Ack three consecutive updates, so that they are stored as a single
+	// range, and leave a fourth one un-acked.
+	var committedBytes uint64
+	for i := uint16(1); i <= 4; i++ {
+		update := randCommittedUpdateForChanWithHeight(
+			t, chanID, i, uint64(i),
+		)
+		lastApplied := h.commitUpdate(&session.ID, update, nil)
+
+		if i == 4 {
+			committedBytes = uint64(
+				len(update.Hint) + len(update.EncryptedBlob),
+			)
+			break
+		}
+
+		h.ackUpdate(&session.ID, i, lastApplied, nil)
+	}
+
+	namespace, err := blobType.Identifier()
+	require.NoError(t, err)
+
+	queue := h.db.GetDBQueue([]byte(namespace))
+	require.NoError(t, queue.Push(&wtdb.BackupID{ChanID: chanID}))
+
+	stats, err = h.db.DBStats()
+	require.NoError(t, err)
+	require.EqualValues(t, 1, stats.NumSessions)
+	require.Zero(t, stats.NumClosableSessions)
+	require.EqualValues(t, 1, stats.NumCommittedUpdates)
+	require.EqualValues(t, 3, stats.NumAckedUpdates)
+	require.EqualValues(t, 1, stats.NumAckedRanges)
+	require.EqualValues(t, 1, stats.NumQueuedBackups)
+
+	// The kv store also keeps the sequence number and the encoding
+	// overhead of a committed update, so its size is only a lower bound.
+	require.GreaterOrEqual(t, stats.CommittedBytes, committedBytes)
+	require.Equal(
+		t, stats.CommittedBytes+stats.QueuedBytes, stats.DataSize(),
+	)
+}
+
 // testMarkChannelClosed asserts the behaviour of MarkChannelClosed.
 func testMarkChannelClosed(h *clientDBHarness) {
 	tower := h.newTower()
@@ -1275,6 +1556,10 @@ func TestClientDB(t *testing.T) {
 				return db
 			},
 		},
+		{
+			name: "sql clientdb",
+			init: newSQLClientDB,
+		},
 	}
 
 	tests := []struct {
@@ -1321,6 +1606,10 @@ func TestClientDB(t *testing.T) {
 			name: "max commitment heights",
 			run:  testMaxCommitmentHeights,
 		},
+		{
+			name: "fetch acked updates",
+			run:  testFetchAckedUpdates,
+		},
 		{
 			name: "test tower status change",
 			run:  testTowerStatusChange,
@@ -1329,6 +1618,22 @@ func TestClientDB(t *testing.T) {
 			name: "terminate session",
 			run:  testTerminateSession,
 		},
+		{
+			name: "db stats",
+			run:  testDBStats,
+		},
+		{
+			name: "channel backup policy",
+			run:  testChannelBackupPolicy,
+		},
+		{
+			name: "fetch channel acked ranges",
+			run:  testFetchChannelAckedRanges,
+		},
+		{
+			name: "justice reports",
+			run:  testClientJusticeReports,
+		},
 	}
 
 	for _, database := range dbs {
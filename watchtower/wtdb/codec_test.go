@@ -153,6 +153,10 @@ func TestCodec(tt *testing.T) {
 			obj2 = &wtdb.Tower{}
 		case *wtdb.ClientChanSummary:
 			obj2 = &wtdb.ClientChanSummary{}
+		case *wtdb.JusticeReport:
+			obj2 = &wtdb.JusticeReport{}
+		case *wtdb.ClientJusticeReport:
+			obj2 = &wtdb.ClientJusticeReport{}
 		default:
 			t.Fatalf("unknown type: %T", obj)
 			return false
@@ -182,6 +186,20 @@ func TestCodec(tt *testing.T) {
 				Status:      wtdb.TowerStatus(r.Uint32()),
 			}
 
+			v[0] = reflect.ValueOf(obj)
+		},
+		"ClientJusticeReport": func(v []reflect.Value, r *rand.Rand) {
+			pk, err := randPubKey()
+			require.NoError(t, err)
+
+			obj := wtdb.ClientJusticeReport{
+				TowerPubKey: pk,
+			}
+			obj.SeqNum = uint16(r.Uint32())
+			_, _ = r.Read(obj.BreachTxID[:])
+			_, _ = r.Read(obj.JusticeTxID[:])
+			_, _ = r.Read(obj.SessionID[:])
+
 			v[0] = reflect.ValueOf(obj)
 		},
 	}
@@ -232,6 +250,18 @@ func TestCodec(tt *testing.T) {
 				return mainScenario(&obj)
 			},
 		},
+		{
+			name: "JusticeReport",
+			scenario: func(obj wtdb.JusticeReport) bool {
+				return mainScenario(&obj)
+			},
+		},
+		{
+			name: "ClientJusticeReport",
+			scenario: func(obj wtdb.ClientJusticeReport) bool {
+				return mainScenario(&obj)
+			},
+		},
 	}
 
 	for _, test := range tests {
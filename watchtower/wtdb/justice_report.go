@@ -0,0 +1,81 @@
+package wtdb
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// JusticeReport records that a tower published a justice transaction for a
+// state update of one of its clients' sessions.
+type JusticeReport struct {
+	// SeqNum is the sequence number of the state update within the
+	// session that was used to construct the justice transaction.
+	SeqNum uint16
+
+	// BreachTxID is the txid of the revoked commitment transaction that
+	// was broadcast.
+	BreachTxID chainhash.Hash
+
+	// JusticeTxID is the txid of the justice transaction that was
+	// published in response.
+	JusticeTxID chainhash.Hash
+}
+
+// Encode serializes the justice report into the provided io.Writer.
+func (r *JusticeReport) Encode(w io.Writer) error {
+	return WriteElements(w,
+		r.SeqNum,
+		r.BreachTxID,
+		r.JusticeTxID,
+	)
+}
+
+// Decode deserializes the target justice report from the provided io.Reader.
+func (r *JusticeReport) Decode(reader io.Reader) error {
+	return ReadElements(reader,
+		&r.SeqNum,
+		&r.BreachTxID,
+		&r.JusticeTxID,
+	)
+}
+
+// ClientJusticeReport is a justice report that a client received from one of
+// its towers.
+type ClientJusticeReport struct {
+	JusticeReport
+
+	// TowerPubKey is the identity key of the tower that published the
+	// justice transaction.
+	TowerPubKey *btcec.PublicKey
+
+	// SessionID is the ID of the session holding the state update that was
+	// used to construct the justice transaction.
+	SessionID SessionID
+}
+
+// Encode serializes the client justice report into the provided io.Writer.
+func (r *ClientJusticeReport) Encode(w io.Writer) error {
+	if err := r.JusticeReport.Encode(w); err != nil {
+		return err
+	}
+
+	return WriteElements(w,
+		r.TowerPubKey,
+		r.SessionID,
+	)
+}
+
+// Decode deserializes the target client justice report from the provided
+// io.Reader.
+func (r *ClientJusticeReport) Decode(reader io.Reader) error {
+	if err := r.JusticeReport.Decode(reader); err != nil {
+		return err
+	}
+
+	return ReadElements(reader,
+		&r.TowerPubKey,
+		&r.SessionID,
+	)
+}
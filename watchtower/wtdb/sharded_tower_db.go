@@ -0,0 +1,535 @@
+package wtdb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+)
+
+var (
+	// shardKey is a static key in the metadataBkt of each shard of a
+	// ShardedTowerDB. It stores the index of the shard followed by the
+	// total number of shards, each as a uint32.
+	shardKey = []byte("shard")
+
+	// shardMigratedKey is a static key in the metadataBkt of the first
+	// shard of a ShardedTowerDB. It is set once a tower database was
+	// migrated into the shards.
+	shardMigratedKey = []byte("migrated")
+
+	// ErrNoShards is returned when a sharded tower database is created
+	// without any shards.
+	ErrNoShards = errors.New("sharded tower db needs at least one shard")
+
+	// ErrShardMismatch is returned when a shard was previously used at a
+	// different position or with a different number of shards. Since the
+	// sessions are assigned to the shards by their id, the shards can't
+	// be reordered, added or removed once they're in use.
+	ErrShardMismatch = errors.New("shard was used with a different shard " +
+		"configuration")
+)
+
+// ShardedTowerDB spreads the sessions of the tower's clients over several
+// TowerDBs, such that a single logical tower can store more sessions than fit
+// on one machine. Each session and all of its state updates and justice
+// reports live on the shard that is determined by the session id. The
+// lookout tip is stored in the first shard.
+type ShardedTowerDB struct {
+	shards []*TowerDB
+
+	// quotaMtx serializes the insertion of sessions under a storage
+	// quota, as the quota spans the reservations of all shards.
+	quotaMtx sync.Mutex
+}
+
+// NewShardedTowerDB creates a tower database from the given shards. The shards
+// must always be passed in the same order, as each of them records its
+// position on first use and ErrShardMismatch is returned if it changes.
+func NewShardedTowerDB(shards ...*TowerDB) (*ShardedTowerDB, error) {
+	if len(shards) == 0 {
+		return nil, ErrNoShards
+	}
+
+	var expShard [8]byte
+	byteOrder.PutUint32(expShard[4:], uint32(len(shards)))
+
+	for i, shard := range shards {
+		byteOrder.PutUint32(expShard[:4], uint32(i))
+
+		err := kvdb.Update(shard.db, func(tx kvdb.RwTx) error {
+			metadata := tx.ReadWriteBucket(metadataBkt)
+			if metadata == nil {
+				return ErrUninitializedDB
+			}
+
+			shardBytes := metadata.Get(shardKey)
+			switch {
+			case shardBytes == nil:
+				return metadata.Put(shardKey, expShard[:])
+
+			case !bytes.Equal(shardBytes, expShard[:]):
+				return ErrShardMismatch
+			}
+
+			return nil
+		}, func() {})
+		if err != nil {
+			return nil, fmt.Errorf("unable to init shard %d: %w", i,
+				err)
+		}
+	}
+
+	return &ShardedTowerDB{
+		shards: shards,
+	}, nil
+}
+
+// shardFor returns the shard that stores the session with the given id.
+func (s *ShardedTowerDB) shardFor(id *SessionID) *TowerDB {
+	// The session ids are public keys chosen by the clients, so they're
+	// hashed to spread them evenly over the shards. Sessions can't be
+	// grouped by client instead, as clients derive a fresh key for each
+	// session, which is the only identity the tower learns of them.
+	h := sha256.Sum256(id[:])
+	idx := byteOrder.Uint64(h[:8]) % uint64(len(s.shards))
+
+	return s.shards[idx]
+}
+
+// Close closes all shards.
+func (s *ShardedTowerDB) Close() error {
+	var firstErr error
+	for _, shard := range s.shards {
+		if err := shard.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// GetSessionInfo retrieves the session for the passed session id. An error is
+// returned if the session could not be found.
+func (s *ShardedTowerDB) GetSessionInfo(id *SessionID) (*SessionInfo, error) {
+	return s.shardFor(id).GetSessionInfo(id)
+}
+
+// InsertSessionInfo records a negotiated session in the tower database. An
+// error is returned if the session already exists.
+func (s *ShardedTowerDB) InsertSessionInfo(session *SessionInfo) error {
+	return s.shardFor(&session.ID).InsertSessionInfo(session)
+}
+
+// InsertSessionInfoWithQuota records a negotiated session in the tower
+// database if the bytes reserved for the encrypted blobs of the sessions of
+// all shards don't exceed maxReserved afterwards. A maxReserved of zero means
+// no limit.
+func (s *ShardedTowerDB) InsertSessionInfoWithQuota(session *SessionInfo,
+	maxReserved uint64) error {
+
+	shard := s.shardFor(&session.ID)
+	if maxReserved == 0 {
+		return shard.InsertSessionInfoWithQuota(session, 0)
+	}
+
+	// The reservations of the other shards can't change while we insert
+	// the session, so the shard of the session may use what they leave of
+	// the quota. Deleted sessions only release reservations, so deletions
+	// don't need to be serialized.
+	s.quotaMtx.Lock()
+	defer s.quotaMtx.Unlock()
+
+	var otherReserved uint64
+	for _, other := range s.shards {
+		if other == shard {
+			continue
+		}
+
+		reserved, err := other.ReservedBytes()
+		if err != nil {
+			return err
+		}
+		otherReserved += reserved
+	}
+
+	if otherReserved >= maxReserved {
+		return ErrStorageQuotaExceeded
+	}
+
+	return shard.InsertSessionInfoWithQuota(
+		session, maxReserved-otherReserved,
+	)
+}
+
+// ReservedBytes returns the number of bytes reserved for the encrypted blobs
+// of the sessions of all shards.
+func (s *ShardedTowerDB) ReservedBytes() (uint64, error) {
+	var reserved uint64
+	for _, shard := range s.shards {
+		shardReserved, err := shard.ReservedBytes()
+		if err != nil {
+			return 0, err
+		}
+		reserved += shardReserved
+	}
+
+	return reserved, nil
+}
+
+// InsertStateUpdate stores an update sent by the client after validating that
+// the update is well-formed in the context of other updates sent for the same
+// session.
+func (s *ShardedTowerDB) InsertStateUpdate(update *SessionStateUpdate) (uint16,
+	error) {
+
+	return s.shardFor(&update.ID).InsertStateUpdate(update)
+}
+
+// DeleteSession removes all data associated with a particular session id from
+// the tower's database.
+func (s *ShardedTowerDB) DeleteSession(target SessionID) error {
+	return s.shardFor(&target).DeleteSession(target)
+}
+
+// InsertJusticeReport records that the tower published a justice transaction
+// for a state update of the given session.
+func (s *ShardedTowerDB) InsertJusticeReport(id SessionID,
+	report *JusticeReport) error {
+
+	return s.shardFor(&id).InsertJusticeReport(id, report)
+}
+
+// FetchJusticeReports returns the justice transactions the tower published
+// for the state updates of the given session.
+func (s *ShardedTowerDB) FetchJusticeReports(id SessionID) ([]JusticeReport,
+	error) {
+
+	return s.shardFor(&id).FetchJusticeReports(id)
+}
+
+// StorageStats returns a summary of the storage used by the sessions of the
+// tower's clients across all shards.
+func (s *ShardedTowerDB) StorageStats() (*StorageStats, error) {
+	var stats StorageStats
+	for _, shard := range s.shards {
+		shardStats, err := shard.StorageStats()
+		if err != nil {
+			return nil, err
+		}
+
+		stats.NumSessions += shardStats.NumSessions
+		stats.NumUpdates += shardStats.NumUpdates
+		stats.BlobBytes += shardStats.BlobBytes
+		stats.ReservedBlobBytes += shardStats.ReservedBlobBytes
+	}
+
+	return &stats, nil
+}
+
+// QueryMatches searches all shards for state updates that match the passed
+// breachHints.
+func (s *ShardedTowerDB) QueryMatches(breachHints []blob.BreachHint) ([]Match,
+	error) {
+
+	var matches []Match
+	for _, shard := range s.shards {
+		shardMatches, err := shard.QueryMatches(breachHints)
+		if err != nil {
+			return nil, err
+		}
+
+		matches = append(matches, shardMatches...)
+	}
+
+	return matches, nil
+}
+
+// SetLookoutTip stores the provided epoch as the latest lookout tip epoch in
+// the first shard.
+func (s *ShardedTowerDB) SetLookoutTip(epoch *chainntnfs.BlockEpoch) error {
+	return s.shards[0].SetLookoutTip(epoch)
+}
+
+// GetLookoutTip retrieves the current lookout tip block epoch from the first
+// shard.
+func (s *ShardedTowerDB) GetLookoutTip() (*chainntnfs.BlockEpoch, error) {
+	return s.shards[0].GetLookoutTip()
+}
+
+// towerSessionData holds the raw records of a single session of a tower
+// database.
+type towerSessionData struct {
+	// session is the encoded session info.
+	session []byte
+
+	// updates maps the hints of the session's state updates to the
+	// encoded updates.
+	updates map[blob.BreachHint][]byte
+
+	// justiceReports maps the encoded sequence numbers of the session's
+	// justice reports to the encoded reports.
+	justiceReports map[string][]byte
+}
+
+// MigrateFromTowerDB copies the sessions, state updates, justice reports and
+// lookout tip of the given tower database into the shards. The sessions are
+// copied one at a time, so that towers with many sessions can be migrated
+// without loading them into memory at once. The migration is only done once,
+// any later call is a no-op. The source database itself is not modified.
+func (s *ShardedTowerDB) MigrateFromTowerDB(src *TowerDB) error {
+	var migrated bool
+	err := kvdb.View(s.shards[0].db, func(tx kvdb.RTx) error {
+		metadata := tx.ReadBucket(metadataBkt)
+		if metadata == nil {
+			return ErrUninitializedDB
+		}
+
+		migrated = metadata.Get(shardMigratedKey) != nil
+
+		return nil
+	}, func() {
+		migrated = false
+	})
+	if err != nil {
+		return err
+	}
+
+	if migrated {
+		return nil
+	}
+
+	ids, err := src.listSessionIDs()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Migrating %d tower sessions into %d shards", len(ids),
+		len(s.shards))
+
+	for _, id := range ids {
+		data, err := src.fetchSessionData(&id)
+		if err != nil {
+			return fmt.Errorf("unable to read session %s: %w", id,
+				err)
+		}
+
+		err = s.shardFor(&id).putSessionData(&id, data)
+		if err != nil {
+			return fmt.Errorf("unable to write session %s: %w", id,
+				err)
+		}
+	}
+
+	epoch, err := src.GetLookoutTip()
+	if err != nil {
+		return err
+	}
+
+	if epoch != nil {
+		if err := s.SetLookoutTip(epoch); err != nil {
+			return err
+		}
+	}
+
+	return kvdb.Update(s.shards[0].db, func(tx kvdb.RwTx) error {
+		metadata := tx.ReadWriteBucket(metadataBkt)
+		if metadata == nil {
+			return ErrUninitializedDB
+		}
+
+		return metadata.Put(shardMigratedKey, []byte{1})
+	}, func() {})
+}
+
+// listSessionIDs returns the ids of all sessions in the tower database.
+func (t *TowerDB) listSessionIDs() ([]SessionID, error) {
+	var ids []SessionID
+	err := kvdb.View(t.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		return sessions.ForEach(func(k, _ []byte) error {
+			var id SessionID
+			copy(id[:], k)
+			ids = append(ids, id)
+
+			return nil
+		})
+	}, func() {
+		ids = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// fetchSessionData reads the raw records of the given session.
+func (t *TowerDB) fetchSessionData(id *SessionID) (*towerSessionData, error) {
+	var data *towerSessionData
+	err := kvdb.View(t.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		updates := tx.ReadBucket(updatesBkt)
+		if updates == nil {
+			return ErrUninitializedDB
+		}
+
+		updateIndex := tx.ReadBucket(updateIndexBkt)
+		if updateIndex == nil {
+			return ErrUninitializedDB
+		}
+
+		justiceReports := tx.ReadBucket(justiceReportsBkt)
+		if justiceReports == nil {
+			return ErrUninitializedDB
+		}
+
+		sessionBytes := sessions.Get(id[:])
+		if sessionBytes == nil {
+			return ErrSessionNotFound
+		}
+		data.session = append([]byte(nil), sessionBytes...)
+
+		hints, err := getHintsForSession(updateIndex, id)
+		if err != nil {
+			return err
+		}
+
+		for _, hint := range hints {
+			updatesForHint := updates.NestedReadBucket(hint[:])
+			if updatesForHint == nil {
+				continue
+			}
+
+			update := updatesForHint.Get(id[:])
+			if update == nil {
+				continue
+			}
+
+			data.updates[hint] = append([]byte(nil), update...)
+		}
+
+		sessionReports := justiceReports.NestedReadBucket(id[:])
+		if sessionReports == nil {
+			return nil
+		}
+
+		return sessionReports.ForEach(func(k, v []byte) error {
+			data.justiceReports[string(k)] = append(
+				[]byte(nil), v...,
+			)
+
+			return nil
+		})
+	}, func() {
+		data = &towerSessionData{
+			updates:        make(map[blob.BreachHint][]byte),
+			justiceReports: make(map[string][]byte),
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// putSessionData writes the raw records of the given session, replacing any
+// existing ones. This allows a partially completed migration to be resumed.
+func (t *TowerDB) putSessionData(id *SessionID,
+	data *towerSessionData) error {
+
+	return kvdb.Update(t.db, func(tx kvdb.RwTx) error {
+		sessions := tx.ReadWriteBucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		updates := tx.ReadWriteBucket(updatesBkt)
+		if updates == nil {
+			return ErrUninitializedDB
+		}
+
+		updateIndex := tx.ReadWriteBucket(updateIndexBkt)
+		if updateIndex == nil {
+			return ErrUninitializedDB
+		}
+
+		justiceReports := tx.ReadWriteBucket(justiceReportsBkt)
+		if justiceReports == nil {
+			return ErrUninitializedDB
+		}
+
+		metadata := tx.ReadWriteBucket(metadataBkt)
+		if metadata == nil {
+			return ErrUninitializedDB
+		}
+
+		err := sessions.Put(id[:], data.session)
+		if err != nil {
+			return err
+		}
+
+		// The raw session bypasses the reserved bytes counter, so it
+		// is removed to be computed from the sessions again.
+		err = metadata.Delete(reservedBytesKey)
+		if err != nil {
+			return err
+		}
+
+		err = touchSessionHintBkt(updateIndex, id)
+		if err != nil {
+			return err
+		}
+
+		for hint, update := range data.updates {
+			hints, err := updates.CreateBucketIfNotExists(hint[:])
+			if err != nil {
+				return err
+			}
+
+			err = hints.Put(id[:], update)
+			if err != nil {
+				return err
+			}
+
+			err = putHintForSession(updateIndex, id, hint)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(data.justiceReports) == 0 {
+			return nil
+		}
+
+		sessionReports, err := justiceReports.CreateBucketIfNotExists(
+			id[:],
+		)
+		if err != nil {
+			return err
+		}
+
+		for seqNum, report := range data.justiceReports {
+			err := sessionReports.Put([]byte(seqNum), report)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+}
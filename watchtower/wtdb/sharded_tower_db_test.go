@@ -0,0 +1,192 @@
+package wtdb_test
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
+	"github.com/stretchr/testify/require"
+)
+
+// openTestTowerDB opens a bolt tower database in the given directory.
+func openTestTowerDB(t *testing.T, dir string) *wtdb.TowerDB {
+	t.Helper()
+
+	dbCfg := &kvdb.BoltConfig{DBTimeout: kvdb.DefaultDBTimeout}
+	bdb, err := wtdb.NewBoltBackendCreator(
+		true, dir, "watchtower.db",
+	)(dbCfg)
+	require.NoError(t, err)
+
+	db, err := wtdb.OpenTowerDB(bdb)
+	require.NoError(t, err)
+
+	return db
+}
+
+// openTestShards opens a bolt tower database in each of the given directories
+// and combines them into a sharded tower database.
+func openTestShards(t *testing.T, dirs ...string) (*wtdb.ShardedTowerDB,
+	error) {
+
+	t.Helper()
+
+	shards := make([]*wtdb.TowerDB, 0, len(dirs))
+	for _, dir := range dirs {
+		shards = append(shards, openTestTowerDB(t, dir))
+	}
+
+	db, err := wtdb.NewShardedTowerDB(shards...)
+	if err != nil {
+		for _, shard := range shards {
+			require.NoError(t, shard.Close())
+		}
+
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// TestShardedTowerDBMismatch asserts that the shards of a sharded tower
+// database can't be reordered, added or removed once they are in use.
+func TestShardedTowerDBMismatch(t *testing.T) {
+	t.Parallel()
+
+	_, err := wtdb.NewShardedTowerDB()
+	require.ErrorIs(t, err, wtdb.ErrNoShards)
+
+	dir1, dir2, dir3 := t.TempDir(), t.TempDir(), t.TempDir()
+
+	db, err := openTestShards(t, dir1, dir2)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// Reopening the shards in the same order succeeds.
+	db, err = openTestShards(t, dir1, dir2)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	// Swapping, adding or removing shards fails.
+	_, err = openTestShards(t, dir2, dir1)
+	require.ErrorIs(t, err, wtdb.ErrShardMismatch)
+
+	_, err = openTestShards(t, dir1, dir2, dir3)
+	require.ErrorIs(t, err, wtdb.ErrShardMismatch)
+
+	_, err = openTestShards(t, dir1)
+	require.ErrorIs(t, err, wtdb.ErrShardMismatch)
+}
+
+// TestShardedTowerDBMigration asserts that the sessions, state updates,
+// justice reports and lookout tip of a tower database are copied into the
+// shards, and that the migration is only done once.
+func TestShardedTowerDBMigration(t *testing.T) {
+	t.Parallel()
+
+	src := openTestTowerDB(t, t.TempDir())
+	t.Cleanup(func() {
+		require.NoError(t, src.Close())
+	})
+
+	newSession := func(i int) *wtdb.SessionInfo {
+		return &wtdb.SessionInfo{
+			ID: *id(i),
+			Policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType: blob.TypeAltruistAnchorCommit,
+					SweepFeeRate: wtpolicy.
+						DefaultSweepFeeRate,
+				},
+				MaxUpdates: 10,
+			},
+			RewardAddress: []byte{},
+		}
+	}
+
+	// Populate the source database with sessions that have a few state
+	// updates each.
+	const numSessions = 10
+	for i := 0; i < numSessions; i++ {
+		require.NoError(t, src.InsertSessionInfo(newSession(i)))
+
+		for seqNum := 1; seqNum <= 3; seqNum++ {
+			update := updateFromInt(id(i), seqNum, 0)
+			_, err := src.InsertStateUpdate(update)
+			require.NoError(t, err)
+		}
+	}
+
+	report := &wtdb.JusticeReport{
+		SeqNum:      2,
+		BreachTxID:  [32]byte{1},
+		JusticeTxID: [32]byte{2},
+	}
+	require.NoError(t, src.InsertJusticeReport(*id(3), report))
+	require.NoError(t, src.SetLookoutTip(epochFromInt(100)))
+
+	dst, err := openTestShards(t, t.TempDir(), t.TempDir(), t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, dst.Close())
+	})
+
+	require.NoError(t, dst.MigrateFromTowerDB(src))
+
+	// All sessions and their updates can be found in the shards.
+	for i := 0; i < numSessions; i++ {
+		expSession, err := src.GetSessionInfo(id(i))
+		require.NoError(t, err)
+
+		session, err := dst.GetSessionInfo(id(i))
+		require.NoError(t, err)
+		require.Equal(t, expSession, session)
+
+		for seqNum := 1; seqNum <= 3; seqNum++ {
+			update := updateFromInt(id(i), seqNum, 0)
+
+			matches, err := dst.QueryMatches(
+				[]blob.BreachHint{update.Hint},
+			)
+			require.NoError(t, err)
+			require.Len(t, matches, 1)
+			require.Equal(t, update.EncryptedBlob,
+				matches[0].EncryptedBlob)
+		}
+	}
+
+	expStats, err := src.StorageStats()
+	require.NoError(t, err)
+
+	stats, err := dst.StorageStats()
+	require.NoError(t, err)
+	require.Equal(t, expStats, stats)
+
+	reports, err := dst.FetchJusticeReports(*id(3))
+	require.NoError(t, err)
+	require.Equal(t, []wtdb.JusticeReport{*report}, reports)
+
+	epoch, err := dst.GetLookoutTip()
+	require.NoError(t, err)
+	require.Equal(t, epochFromInt(100), epoch)
+
+	// The migrated sessions can be deleted, which also removes their
+	// updates.
+	require.NoError(t, dst.DeleteSession(*id(0)))
+
+	matches, err := dst.QueryMatches(
+		[]blob.BreachHint{updateFromInt(id(0), 1, 0).Hint},
+	)
+	require.NoError(t, err)
+	require.Empty(t, matches)
+
+	// Sessions added to the source database after the migration aren't
+	// copied by another migration.
+	require.NoError(t, src.InsertSessionInfo(newSession(numSessions)))
+	require.NoError(t, dst.MigrateFromTowerDB(src))
+
+	_, err = dst.GetSessionInfo(id(numSessions))
+	require.ErrorIs(t, err, wtdb.ErrSessionNotFound)
+}
@@ -0,0 +1,2195 @@
+package wtdb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/sqldb"
+	"github.com/lightningnetwork/lnd/sqldb/sqlc"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+)
+
+const (
+	// sqlStateSessionKeyIndex is the name of the wtclient_state row that
+	// holds the last reserved session key index.
+	sqlStateSessionKeyIndex = "session_key_index"
+
+	// sqlStateKVMigrated is the name of the wtclient_state row that is set
+	// to 1 once the legacy kv store was migrated to the SQL store.
+	sqlStateKVMigrated = "kv_migrated"
+)
+
+// SQLClientQueries is an interface that defines the set of operations that can
+// be executed against the watchtower client SQL database.
+type SQLClientQueries interface { //nolint:interfacebloat
+	GetWtclientState(ctx context.Context, name string) (int64, error)
+
+	UpdateWtclientState(ctx context.Context,
+		arg sqlc.UpdateWtclientStateParams) error
+
+	// Tower specific methods.
+	InsertWtclientTower(ctx context.Context,
+		arg sqlc.InsertWtclientTowerParams) (int64, error)
+
+	UpdateWtclientTower(ctx context.Context,
+		arg sqlc.UpdateWtclientTowerParams) error
+
+	GetWtclientTowerByID(ctx context.Context, id int64) (
+		sqlc.WtclientTower, error)
+
+	GetWtclientTowerByPubKey(ctx context.Context, pubKey []byte) (
+		sqlc.WtclientTower, error)
+
+	ListWtclientTowers(ctx context.Context) ([]sqlc.WtclientTower, error)
+
+	DeleteWtclientTower(ctx context.Context, id int64) error
+
+	GetWtclientSessionKeyReservation(ctx context.Context,
+		arg sqlc.GetWtclientSessionKeyReservationParams) (int64, error)
+
+	UpsertWtclientSessionKeyReservation(ctx context.Context,
+		arg sqlc.UpsertWtclientSessionKeyReservationParams) error
+
+	DeleteWtclientSessionKeyReservation(ctx context.Context,
+		arg sqlc.DeleteWtclientSessionKeyReservationParams) error
+
+	// Session specific methods.
+	InsertWtclientSession(ctx context.Context,
+		arg sqlc.InsertWtclientSessionParams) (int64, error)
+
+	GetWtclientSession(ctx context.Context, sessionID []byte) (
+		sqlc.WtclientSession, error)
+
+	GetWtclientSessionByID(ctx context.Context, id int64) (
+		sqlc.WtclientSession, error)
+
+	ListWtclientSessions(ctx context.Context) ([]sqlc.WtclientSession,
+		error)
+
+	ListWtclientTowerSessions(ctx context.Context, towerID int64) (
+		[]sqlc.WtclientSession, error)
+
+	ListWtclientClosableSessions(ctx context.Context) (
+		[]sqlc.ListWtclientClosableSessionsRow, error)
+
+	UpdateWtclientSessionSeqNum(ctx context.Context,
+		arg sqlc.UpdateWtclientSessionSeqNumParams) error
+
+	UpdateWtclientSessionLastApplied(ctx context.Context,
+		arg sqlc.UpdateWtclientSessionLastAppliedParams) error
+
+	UpdateWtclientSessionStatus(ctx context.Context,
+		arg sqlc.UpdateWtclientSessionStatusParams) error
+
+	IncrementWtclientSessionRogueCount(ctx context.Context, id int64) (
+		int64, error)
+
+	UpdateWtclientSessionClosable(ctx context.Context,
+		arg sqlc.UpdateWtclientSessionClosableParams) error
+
+	DeleteWtclientSession(ctx context.Context, id int64) error
+
+	CountWtclientSessions(ctx context.Context) (int64, error)
+
+	CountWtclientClosableSessions(ctx context.Context) (int64, error)
+
+	// Committed update specific methods.
+	InsertWtclientCommittedUpdate(ctx context.Context,
+		arg sqlc.InsertWtclientCommittedUpdateParams) error
+
+	GetWtclientCommittedUpdate(ctx context.Context,
+		arg sqlc.GetWtclientCommittedUpdateParams) (
+		sqlc.WtclientCommittedUpdate, error)
+
+	ListWtclientCommittedUpdates(ctx context.Context, sessionID int64) (
+		[]sqlc.WtclientCommittedUpdate, error)
+
+	DeleteWtclientCommittedUpdate(ctx context.Context,
+		arg sqlc.DeleteWtclientCommittedUpdateParams) error
+
+	DeleteWtclientCommittedUpdates(ctx context.Context,
+		sessionID int64) error
+
+	CountWtclientCommittedUpdates(ctx context.Context) (int64, error)
+
+	SumWtclientCommittedUpdateBytes(ctx context.Context) (int64, error)
+
+	// Channel specific methods.
+	InsertWtclientChannel(ctx context.Context,
+		arg sqlc.InsertWtclientChannelParams) (int64, error)
+
+	GetWtclientChannel(ctx context.Context, chanID []byte) (
+		sqlc.WtclientChannel, error)
+
+	ListWtclientOpenChannels(ctx context.Context) ([]sqlc.WtclientChannel,
+		error)
+
+	UpdateWtclientChannelMaxHeight(ctx context.Context,
+		arg sqlc.UpdateWtclientChannelMaxHeightParams) error
+
+	UpdateWtclientChannelBackupPolicy(ctx context.Context,
+		arg sqlc.UpdateWtclientChannelBackupPolicyParams) error
+
+	UpdateWtclientChannelClosed(ctx context.Context,
+		arg sqlc.UpdateWtclientChannelClosedParams) error
+
+	DeleteWtclientChannel(ctx context.Context, id int64) error
+
+	DeleteWtclientChannelIfUnused(ctx context.Context, id int64) error
+
+	ListWtclientChannelSessions(ctx context.Context, channelID int64) (
+		[]int64, error)
+
+	ListWtclientSessionChannels(ctx context.Context, sessionID int64) (
+		[]int64, error)
+
+	CountWtclientSessionOpenChannels(ctx context.Context,
+		sessionID int64) (int64, error)
+
+	// Acked range specific methods.
+	InsertWtclientAckedRange(ctx context.Context,
+		arg sqlc.InsertWtclientAckedRangeParams) error
+
+	GetWtclientAckedRangeContaining(ctx context.Context,
+		arg sqlc.GetWtclientAckedRangeContainingParams) (
+		sqlc.WtclientAckedRange, error)
+
+	GetWtclientAckedRangeByStart(ctx context.Context,
+		arg sqlc.GetWtclientAckedRangeByStartParams) (
+		sqlc.WtclientAckedRange, error)
+
+	GetWtclientAckedRangeByEnd(ctx context.Context,
+		arg sqlc.GetWtclientAckedRangeByEndParams) (
+		sqlc.WtclientAckedRange, error)
+
+	UpdateWtclientAckedRangeEnd(ctx context.Context,
+		arg sqlc.UpdateWtclientAckedRangeEndParams) error
+
+	DeleteWtclientAckedRange(ctx context.Context,
+		arg sqlc.DeleteWtclientAckedRangeParams) error
+
+	ListWtclientChannelAckedRanges(ctx context.Context, channelID int64) (
+		[]sqlc.ListWtclientChannelAckedRangesRow, error)
+
+	ListWtclientSessionAckedRanges(ctx context.Context, sessionID int64) (
+		[]sqlc.ListWtclientSessionAckedRangesRow, error)
+
+	SumWtclientSessionAckedUpdates(ctx context.Context,
+		sessionID int64) (int64, error)
+
+	CountWtclientAckedRanges(ctx context.Context) (int64, error)
+
+	SumWtclientAckedUpdates(ctx context.Context) (int64, error)
+
+	// Queue specific methods.
+	InsertWtclientQueueItem(ctx context.Context,
+		arg sqlc.InsertWtclientQueueItemParams) error
+
+	GetWtclientQueueBounds(ctx context.Context, namespace []byte) (
+		sqlc.GetWtclientQueueBoundsRow, error)
+
+	ListWtclientQueueItems(ctx context.Context,
+		arg sqlc.ListWtclientQueueItemsParams) (
+		[]sqlc.ListWtclientQueueItemsRow, error)
+
+	DeleteWtclientQueueItem(ctx context.Context,
+		arg sqlc.DeleteWtclientQueueItemParams) error
+
+	CountWtclientQueueItems(ctx context.Context) (int64, error)
+
+	SumWtclientQueueItemBytes(ctx context.Context) (int64, error)
+
+	// Justice report specific methods.
+	UpsertWtclientJusticeReport(ctx context.Context,
+		arg sqlc.UpsertWtclientJusticeReportParams) error
+
+	ListWtclientJusticeReports(ctx context.Context) (
+		[]sqlc.WtclientJusticeReport, error)
+}
+
+// SQLClientQueriesTxOptions defines the set of db txn options the
+// SQLClientQueries understands.
+type SQLClientQueriesTxOptions struct {
+	// readOnly governs if a read only transaction is needed or not.
+	readOnly bool
+}
+
+// ReadOnly returns true if the transaction should be read only.
+//
+// NOTE: This implements the TxOptions.
+func (a *SQLClientQueriesTxOptions) ReadOnly() bool {
+	return a.readOnly
+}
+
+// NewSQLClientQueryReadTx creates a new read transaction option set.
+func NewSQLClientQueryReadTx() SQLClientQueriesTxOptions {
+	return SQLClientQueriesTxOptions{
+		readOnly: true,
+	}
+}
+
+// BatchedSQLClientQueries is a version of the SQLClientQueries that's capable
+// of batched database operations.
+type BatchedSQLClientQueries interface {
+	SQLClientQueries
+
+	sqldb.BatchedTx[SQLClientQueries]
+}
+
+// SQLClientDB is a native SQL implementation of the watchtower client
+// database. Unlike the kv ClientDB, which keeps a bucket entry for every acked
+// update, consecutive acked commitment heights of a channel are stored as a
+// single range, and the ranges of a session are pruned together with the
+// session once it is deleted.
+type SQLClientDB struct {
+	db BatchedSQLClientQueries
+}
+
+// NewSQLClientDB creates a new SQLClientDB instance given an open
+// BatchedSQLClientQueries storage backend.
+func NewSQLClientDB(db BatchedSQLClientQueries) *SQLClientDB {
+	return &SQLClientDB{
+		db: db,
+	}
+}
+
+// update executes the given function in a read-write transaction.
+func (s *SQLClientDB) update(f func(ctx context.Context,
+	db SQLClientQueries) error, reset func()) error {
+
+	ctx := context.Background()
+
+	var writeTxOpts SQLClientQueriesTxOptions
+	return s.db.ExecTx(ctx, &writeTxOpts, func(db SQLClientQueries) error {
+		return f(ctx, db)
+	}, reset)
+}
+
+// view executes the given function in a read-only transaction.
+func (s *SQLClientDB) view(f func(ctx context.Context,
+	db SQLClientQueries) error, reset func()) error {
+
+	ctx := context.Background()
+
+	readTxOpts := NewSQLClientQueryReadTx()
+	return s.db.ExecTx(ctx, &readTxOpts, func(db SQLClientQueries) error {
+		return f(ctx, db)
+	}, reset)
+}
+
+// CreateTower initialize an address record used to communicate with a
+// watchtower. Each Tower is assigned a unique ID, that is used to amortize
+// storage costs of the public key when used by multiple sessions. If the tower
+// already exists, the address is appended to the list of all addresses used to
+// that tower previously and its corresponding sessions are marked as active.
+func (s *SQLClientDB) CreateTower(lnAddr *lnwire.NetAddress) (*Tower, error) {
+	pubKey := lnAddr.IdentityKey.SerializeCompressed()
+
+	var tower *Tower
+	err := s.update(func(ctx context.Context, db SQLClientQueries) error {
+		row, err := db.GetWtclientTowerByPubKey(ctx, pubKey)
+		switch {
+		// No such tower exists, so we insert a new one.
+		case errors.Is(err, sql.ErrNoRows):
+			tower = &Tower{
+				IdentityKey: lnAddr.IdentityKey,
+				Addresses:   []net.Addr{lnAddr.Address},
+				Status:      TowerStatusActive,
+			}
+
+			return insertSQLTower(ctx, db, tower)
+
+		case err != nil:
+			return err
+		}
+
+		tower, err = unmarshalSQLTower(row)
+		if err != nil {
+			return err
+		}
+
+		// Set its status to active and add the new address to the
+		// existing tower. If the address is a duplicate, this will
+		// result in no change.
+		tower.Status = TowerStatusActive
+		tower.AddAddress(lnAddr.Address)
+
+		return putSQLTower(ctx, db, tower)
+	}, func() {
+		tower = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tower, nil
+}
+
+// RemoveTower modifies a tower's record within the database. If an address is
+// provided, then _only_ the address record should be removed from the tower's
+// persisted state. Otherwise, we'll attempt to mark the tower as inactive. If
+// any of its sessions has unacked updates, then ErrTowerUnackedUpdates is
+// returned. If the tower doesn't have any sessions at all, it'll be completely
+// removed from the database.
+//
+// NOTE: An error is not returned if the tower doesn't exist.
+func (s *SQLClientDB) RemoveTower(pubKey *btcec.PublicKey,
+	addr net.Addr) error {
+
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		// Don't return an error if the watchtower doesn't exist to act
+		// as a NOP.
+		row, err := db.GetWtclientTowerByPubKey(
+			ctx, pubKey.SerializeCompressed(),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		tower, err := unmarshalSQLTower(row)
+		if err != nil {
+			return err
+		}
+
+		// If an address is provided, then we should _only_ remove the
+		// address record from the database.
+		if addr != nil {
+			// Towers should always have at least one address saved.
+			tower.RemoveAddress(addr)
+			if len(tower.Addresses) == 0 {
+				return ErrLastTowerAddr
+			}
+
+			return putSQLTower(ctx, db, tower)
+		}
+
+		sessions, err := db.ListWtclientTowerSessions(ctx, row.ID)
+		if err != nil {
+			return err
+		}
+
+		// If it doesn't have any, we can completely remove it from the
+		// database.
+		if len(sessions) == 0 {
+			return db.DeleteWtclientTower(ctx, row.ID)
+		}
+
+		// We'll do a check to ensure that the tower's sessions don't
+		// have any pending back-ups.
+		for _, session := range sessions {
+			updates, err := db.ListWtclientCommittedUpdates(
+				ctx, session.ID,
+			)
+			if err != nil {
+				return err
+			}
+
+			if len(updates) > 0 {
+				return ErrTowerUnackedUpdates
+			}
+		}
+
+		// Otherwise, we mark the tower as inactive.
+		tower.Status = TowerStatusInactive
+
+		return putSQLTower(ctx, db, tower)
+	}, func() {})
+}
+
+// DeactivateTower sets the given tower's status to inactive. This means that
+// this tower's sessions won't be loaded and used for backups. CreateTower can
+// be used to reactivate the tower again.
+func (s *SQLClientDB) DeactivateTower(pubKey *btcec.PublicKey) error {
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		row, err := db.GetWtclientTowerByPubKey(
+			ctx, pubKey.SerializeCompressed(),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTowerNotFound
+		} else if err != nil {
+			return err
+		}
+
+		// If the tower already has the desired status, then we can exit
+		// here.
+		if TowerStatus(row.Status) == TowerStatusInactive {
+			return nil
+		}
+
+		return db.UpdateWtclientTower(ctx, sqlc.UpdateWtclientTowerParams{
+			Addresses: row.Addresses,
+			Status:    int16(TowerStatusInactive),
+			ID:        row.ID,
+		})
+	}, func() {})
+}
+
+// LoadTowerByID retrieves a tower by its tower ID.
+func (s *SQLClientDB) LoadTowerByID(towerID TowerID) (*Tower, error) {
+	var tower *Tower
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		row, err := db.GetWtclientTowerByID(ctx, int64(towerID))
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTowerNotFound
+		} else if err != nil {
+			return err
+		}
+
+		tower, err = unmarshalSQLTower(row)
+
+		return err
+	}, func() {
+		tower = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tower, nil
+}
+
+// LoadTower retrieves a tower by its public key.
+func (s *SQLClientDB) LoadTower(pubKey *btcec.PublicKey) (*Tower, error) {
+	var tower *Tower
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		row, err := db.GetWtclientTowerByPubKey(
+			ctx, pubKey.SerializeCompressed(),
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTowerNotFound
+		} else if err != nil {
+			return err
+		}
+
+		tower, err = unmarshalSQLTower(row)
+
+		return err
+	}, func() {
+		tower = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tower, nil
+}
+
+// ListTowers retrieves the list of towers available within the database. The
+// filter function may be set in order to filter out the towers to be returned.
+func (s *SQLClientDB) ListTowers(filter TowerFilterFn) ([]*Tower, error) {
+	var towers []*Tower
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		rows, err := db.ListWtclientTowers(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			tower, err := unmarshalSQLTower(row)
+			if err != nil {
+				return err
+			}
+
+			if filter != nil && !filter(tower) {
+				continue
+			}
+
+			towers = append(towers, tower)
+		}
+
+		return nil
+	}, func() {
+		towers = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return towers, nil
+}
+
+// NextSessionKeyIndex reserves a new session key derivation index for a
+// particular tower id and blob type. The index is reserved for that (tower,
+// blob type) pair until CreateClientSession is invoked for that tower and
+// index, at which point a new index for that tower can be reserved. Multiple
+// calls to this method before CreateClientSession is invoked should return the
+// same index unless forceNext is true.
+func (s *SQLClientDB) NextSessionKeyIndex(towerID TowerID, blobType blob.Type,
+	forceNext bool) (uint32, error) {
+
+	var index uint32
+	err := s.update(func(ctx context.Context, db SQLClientQueries) error {
+		// Check if a key has already been reserved for this tower and
+		// blob type. If so, we'll return the index directly.
+		if !forceNext {
+			reserved, err := db.GetWtclientSessionKeyReservation(
+				ctx, sqlc.GetWtclientSessionKeyReservationParams{
+					TowerID:  int64(towerID),
+					BlobType: int32(blobType),
+				},
+			)
+			switch {
+			case err == nil:
+				index = uint32(reserved)
+				return nil
+
+			case !errors.Is(err, sql.ErrNoRows):
+				return err
+			}
+		}
+
+		// By default, we use the next available index. But if
+		// forceNext is true, then it is assumed that some data loss
+		// occurred and so the index is incremented by a jump of 1000
+		// so that we can arrive at a brand new key index quicker.
+		current, err := db.GetWtclientState(
+			ctx, sqlStateSessionKeyIndex,
+		)
+		if err != nil {
+			return err
+		}
+
+		nextIndex := current + 1
+		if forceNext {
+			nextIndex = current + 1000
+		}
+
+		// As a sanity check, assert that the index is still in the
+		// valid range of unhardened pubkeys.
+		if nextIndex > math.MaxInt32 {
+			return fmt.Errorf("exhausted session key indexes")
+		}
+
+		err = db.UpdateWtclientState(ctx, sqlc.UpdateWtclientStateParams{
+			Value: nextIndex,
+			Name:  sqlStateSessionKeyIndex,
+		})
+		if err != nil {
+			return err
+		}
+
+		index = uint32(nextIndex)
+
+		return db.UpsertWtclientSessionKeyReservation(
+			ctx, sqlc.UpsertWtclientSessionKeyReservationParams{
+				TowerID:  int64(towerID),
+				BlobType: int32(blobType),
+				KeyIndex: nextIndex,
+			},
+		)
+	}, func() {
+		index = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return index, nil
+}
+
+// CreateClientSession records a newly negotiated client session in the set of
+// active sessions. The session can be identified by its SessionID.
+func (s *SQLClientDB) CreateClientSession(session *ClientSession) error {
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		// Check that a client session with this session id doesn't
+		// already exist.
+		_, err := db.GetWtclientSession(ctx, session.ID[:])
+		switch {
+		case err == nil:
+			return ErrClientSessionAlreadyExists
+
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		// Ensure that a tower with the given ID actually exists in the
+		// DB.
+		towerID := int64(session.TowerID)
+		_, err = db.GetWtclientTowerByID(ctx, towerID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTowerNotFound
+		} else if err != nil {
+			return err
+		}
+
+		// Check that this tower has a reserved key index, and that it
+		// matches the key index of the inserted session.
+		blobType := int32(session.Policy.BlobType)
+		index, err := db.GetWtclientSessionKeyReservation(
+			ctx, sqlc.GetWtclientSessionKeyReservationParams{
+				TowerID:  towerID,
+				BlobType: blobType,
+			},
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoReservedKeyIndex
+		} else if err != nil {
+			return err
+		}
+
+		if uint32(index) != session.KeyIndex {
+			return ErrIncorrectKeyIndex
+		}
+
+		// Remove the key index reservation.
+		err = db.DeleteWtclientSessionKeyReservation(
+			ctx, sqlc.DeleteWtclientSessionKeyReservationParams{
+				TowerID:  towerID,
+				BlobType: blobType,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = insertSQLSession(ctx, db, session, 0, fn.None[uint32]())
+
+		return err
+	}, func() {})
+}
+
+// GetClientSession loads the ClientSession with the given ID from the DB.
+func (s *SQLClientDB) GetClientSession(id SessionID,
+	opts ...ClientSessionListOption) (*ClientSession, error) {
+
+	var session *ClientSession
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		row, err := getSQLSession(ctx, db, id)
+		if err != nil {
+			return err
+		}
+
+		session, err = evalSQLSession(ctx, db, row, opts...)
+
+		return err
+	}, func() {
+		session = nil
+	})
+
+	return session, err
+}
+
+// ListClientSessions returns the set of all client sessions known to the db. An
+// optional tower ID can be used to filter out any client sessions in the
+// response that do not correspond to this tower.
+func (s *SQLClientDB) ListClientSessions(id *TowerID,
+	opts ...ClientSessionListOption) (map[SessionID]*ClientSession, error) {
+
+	var clientSessions map[SessionID]*ClientSession
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		clientSessions = make(map[SessionID]*ClientSession)
+
+		var (
+			rows []sqlc.WtclientSession
+			err  error
+		)
+		if id == nil {
+			rows, err = db.ListWtclientSessions(ctx)
+		} else {
+			_, err = db.GetWtclientTowerByID(ctx, int64(*id))
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrTowerNotFound
+			} else if err != nil {
+				return err
+			}
+
+			rows, err = db.ListWtclientTowerSessions(ctx, int64(*id))
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			session, err := evalSQLSession(ctx, db, row, opts...)
+			if errors.Is(err, ErrSessionFailedFilterFn) {
+				continue
+			} else if err != nil {
+				return err
+			}
+
+			clientSessions[session.ID] = session
+		}
+
+		return nil
+	}, func() {
+		clientSessions = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clientSessions, nil
+}
+
+// FetchSessionCommittedUpdates retrieves the current set of un-acked updates
+// of the given session.
+func (s *SQLClientDB) FetchSessionCommittedUpdates(id *SessionID) (
+	[]CommittedUpdate, error) {
+
+	var committedUpdates []CommittedUpdate
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		row, err := getSQLSession(ctx, db, *id)
+		if err != nil {
+			return err
+		}
+
+		rows, err := db.ListWtclientCommittedUpdates(ctx, row.ID)
+		if err != nil {
+			return err
+		}
+
+		committedUpdates = make([]CommittedUpdate, 0, len(rows))
+		for _, update := range rows {
+			committedUpdates = append(
+				committedUpdates,
+				*unmarshalSQLCommittedUpdate(update),
+			)
+		}
+
+		return nil
+	}, func() {
+		committedUpdates = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return committedUpdates, nil
+}
+
+// IsAcked returns true if the given backup has been backed up using the given
+// session.
+func (s *SQLClientDB) IsAcked(id *SessionID, backupID *BackupID) (bool,
+	error) {
+
+	var isAcked bool
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		session, err := db.GetWtclientSession(ctx, id[:])
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		channel, err := db.GetWtclientChannel(ctx, backupID.ChanID[:])
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		_, err = db.GetWtclientAckedRangeContaining(
+			ctx, sqlc.GetWtclientAckedRangeContainingParams{
+				SessionID: session.ID,
+				ChannelID: channel.ID,
+				Height:    int64(backupID.CommitHeight),
+			},
+		)
+		switch {
+		case err == nil:
+			isAcked = true
+
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		return nil
+	}, func() {
+		isAcked = false
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return isAcked, nil
+}
+
+// NumAckedUpdates returns the number of backups that have been successfully
+// backed up using the given session.
+func (s *SQLClientDB) NumAckedUpdates(id *SessionID) (uint64, error) {
+	var numAcked uint64
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		session, err := db.GetWtclientSession(ctx, id[:])
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		acked, err := db.SumWtclientSessionAckedUpdates(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		numAcked = uint64(acked) + uint64(session.RogueUpdateCount)
+
+		return nil
+	}, func() {
+		numAcked = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numAcked, nil
+}
+
+// FetchAckedUpdates returns the backups that have been acked by the tower of
+// the given session. Rogue updates, which are updates for channels that have
+// since been closed, are not included.
+func (s *SQLClientDB) FetchAckedUpdates(id *SessionID) ([]BackupID, error) {
+	var backupIDs []BackupID
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		session, err := getSQLSession(ctx, db, *id)
+		if err != nil {
+			return err
+		}
+
+		ranges, err := db.ListWtclientSessionAckedRanges(
+			ctx, session.ID,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range ranges {
+			var chanID lnwire.ChannelID
+			copy(chanID[:], r.ChanID)
+
+			for h := r.StartHeight; h <= r.EndHeight; h++ {
+				backupIDs = append(backupIDs, BackupID{
+					ChanID:       chanID,
+					CommitHeight: uint64(h),
+				})
+			}
+		}
+
+		return nil
+	}, func() {
+		backupIDs = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return backupIDs, nil
+}
+
+// FetchChanInfos loads a mapping from all registered channels to their
+// ChannelInfo. Only the channels that have not yet been marked as closed will
+// be loaded.
+func (s *SQLClientDB) FetchChanInfos() (ChannelInfos, error) {
+	infos := make(ChannelInfos)
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		rows, err := db.ListWtclientOpenChannels(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			var chanID lnwire.ChannelID
+			copy(chanID[:], row.ChanID)
+
+			info := &ChannelInfo{
+				ClientChanSummary: ClientChanSummary{
+					SweepPkScript: row.SweepPkScript,
+				},
+			}
+			if row.MaxCommitHeight.Valid {
+				info.MaxHeight = fn.Some(
+					uint64(row.MaxCommitHeight.Int64),
+				)
+			}
+			info.BackupPolicy = ChannelBackupPolicy{
+				Excluded: row.BackupExcluded,
+				MinSweepFeeRate: chainfee.SatPerKWeight(
+					row.MinSweepFeeRate,
+				),
+			}
+
+			infos[chanID] = info
+		}
+
+		return nil
+	}, func() {
+		infos = make(ChannelInfos)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return infos, nil
+}
+
+// RegisterChannel registers a channel for use within the client database. For
+// now, all that is stored in the channel summary is the sweep pkscript that
+// we'd like any tower sweeps to pay into.
+func (s *SQLClientDB) RegisterChannel(chanID lnwire.ChannelID,
+	sweepPkScript []byte) error {
+
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		_, err := db.GetWtclientChannel(ctx, chanID[:])
+		switch {
+		case err == nil:
+			return ErrChannelAlreadyRegistered
+
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		_, err = db.InsertWtclientChannel(
+			ctx, sqlc.InsertWtclientChannelParams{
+				ChanID:        chanID[:],
+				SweepPkScript: sweepPkScript,
+			},
+		)
+
+		return err
+	}, func() {})
+}
+
+// SetChannelBackupPolicy persists the backup settings of a registered channel.
+// ErrChannelNotRegistered is returned if the channel is unknown or has been
+// closed.
+func (s *SQLClientDB) SetChannelBackupPolicy(chanID lnwire.ChannelID,
+	policy *ChannelBackupPolicy) error {
+
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		channel, err := db.GetWtclientChannel(ctx, chanID[:])
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrChannelNotRegistered
+		} else if err != nil {
+			return err
+		}
+
+		if channel.ClosedHeight.Valid {
+			return ErrChannelNotRegistered
+		}
+
+		return db.UpdateWtclientChannelBackupPolicy(
+			ctx, sqlc.UpdateWtclientChannelBackupPolicyParams{
+				BackupExcluded:  policy.Excluded,
+				MinSweepFeeRate: int64(policy.MinSweepFeeRate),
+				ID:              channel.ID,
+			},
+		)
+	}, func() {})
+}
+
+// FetchChannelAckedRanges returns the commitment heights of the given channel
+// that were acked by a tower in any session, merged into sorted ranges.
+func (s *SQLClientDB) FetchChannelAckedRanges(chanID lnwire.ChannelID) (
+	[]HeightRange, error) {
+
+	var ranges []HeightRange
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		channel, err := db.GetWtclientChannel(ctx, chanID[:])
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrChannelNotRegistered
+		} else if err != nil {
+			return err
+		}
+
+		rows, err := db.ListWtclientChannelAckedRanges(ctx, channel.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			ranges = append(ranges, HeightRange{
+				Start: uint64(row.StartHeight),
+				End:   uint64(row.EndHeight),
+			})
+		}
+
+		return nil
+	}, func() {
+		ranges = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeHeightRanges(ranges), nil
+}
+
+// MarkBackupIneligible records that the state identified by the (channel id,
+// commit height) tuple was ineligible for being backed up under the current
+// policy. This state can be retried later under a different policy.
+func (s *SQLClientDB) MarkBackupIneligible(_ lnwire.ChannelID, _ uint64) error {
+	return nil
+}
+
+// ListClosableSessions fetches and returns the IDs for all sessions marked as
+// closable.
+func (s *SQLClientDB) ListClosableSessions() (map[SessionID]uint32, error) {
+	sessions := make(map[SessionID]uint32)
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		rows, err := db.ListWtclientClosableSessions(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			var id SessionID
+			copy(id[:], row.SessionID)
+
+			sessions[id] = uint32(row.ClosableHeight.Int64)
+		}
+
+		return nil
+	}, func() {
+		sessions = make(map[SessionID]uint32)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// DeleteSession can be called when a session should be deleted from the DB.
+// The session's committed updates and acked ranges are deleted with it, as are
+// the details of any channel that no other session has acked updates for. Note
+// that a session will only be deleted if was previously marked as closable.
+func (s *SQLClientDB) DeleteSession(id SessionID) error {
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		// If the session does not exist, then it has already been
+		// deleted and so our work is done.
+		session, err := db.GetWtclientSession(ctx, id[:])
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if !session.ClosableHeight.Valid {
+			return ErrSessionNotClosable
+		}
+
+		// Collect the channels the session has acked updates for
+		// before the session's ranges are removed.
+		channels, err := db.ListWtclientSessionChannels(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		err = db.DeleteWtclientSession(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		// Delete the details of the channels that no remaining session
+		// has acked updates for.
+		for _, channelID := range channels {
+			err := db.DeleteWtclientChannelIfUnused(ctx, channelID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+}
+
+// MarkChannelClosed will mark a registered channel as closed by setting its
+// closed-height as the given block height. It returns a list of session IDs for
+// sessions that are now considered closable due to the close of this channel.
+// The details for this channel will be deleted from the DB if there are no more
+// sessions in the DB that contain updates for this channel.
+func (s *SQLClientDB) MarkChannelClosed(chanID lnwire.ChannelID,
+	blockHeight uint32) ([]SessionID, error) {
+
+	var closableSessions []SessionID
+	err := s.update(func(ctx context.Context, db SQLClientQueries) error {
+		channel, err := db.GetWtclientChannel(ctx, chanID[:])
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrChannelNotRegistered
+		} else if err != nil {
+			return err
+		}
+
+		// If there are no sessions for this channel, the channel
+		// details can be deleted.
+		sessionIDs, err := db.ListWtclientChannelSessions(
+			ctx, channel.ID,
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(sessionIDs) == 0 {
+			return db.DeleteWtclientChannel(ctx, channel.ID)
+		}
+
+		// Otherwise, mark the channel as closed.
+		err = db.UpdateWtclientChannelClosed(
+			ctx, sqlc.UpdateWtclientChannelClosedParams{
+				ClosedHeight: sqldb.SQLInt64(blockHeight),
+				ID:           channel.ID,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Now iterate through all the sessions of the channel to check
+		// if any of them are closable.
+		for _, sessionID := range sessionIDs {
+			session, err := db.GetWtclientSessionByID(
+				ctx, sessionID,
+			)
+			if err != nil {
+				return err
+			}
+
+			isClosable, err := isSQLSessionClosable(
+				ctx, db, session,
+			)
+			if err != nil {
+				return err
+			}
+
+			if !isClosable {
+				continue
+			}
+
+			// Mark the session as closable at the block height
+			// that this last channel was closed in. This will be
+			// used in future to determine when we should delete
+			// the session.
+			err = db.UpdateWtclientSessionClosable(
+				ctx, sqlc.UpdateWtclientSessionClosableParams{
+					ClosableHeight: sqldb.SQLInt64(
+						blockHeight,
+					),
+					ID: session.ID,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			var id SessionID
+			copy(id[:], session.SessionID)
+			closableSessions = append(closableSessions, id)
+		}
+
+		return nil
+	}, func() {
+		closableSessions = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return closableSessions, nil
+}
+
+// isSQLSessionClosable returns true if a session is considered closable. A
+// session is considered closable only if all the following points are true:
+//  1. It has no un-acked updates.
+//  2. It is exhausted (ie it can't accept any more updates) OR it has been
+//     marked as terminal.
+//  3. All the channels that it has acked updates for are closed.
+func isSQLSessionClosable(ctx context.Context, db SQLClientQueries,
+	session sqlc.WtclientSession) (bool, error) {
+
+	// If the session has any un-acked updates, then it is not yet
+	// closable.
+	updates, err := db.ListWtclientCommittedUpdates(ctx, session.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(updates) > 0 {
+		return false, nil
+	}
+
+	body, err := unmarshalSQLSession(session)
+	if err != nil {
+		return false, err
+	}
+
+	// If the session is not yet exhausted, and it is not yet in a terminal
+	// state then it is not yet closable.
+	isTerminal := body.Status == CSessionTerminal
+	if !isTerminal && body.SeqNum < body.Policy.MaxUpdates {
+		return false, nil
+	}
+
+	// A session that only has rogue updates is closable.
+	maxUpdates := int64(body.Policy.MaxUpdates)
+	if session.RogueUpdateCount == maxUpdates {
+		return true, nil
+	}
+
+	channels, err := db.ListWtclientSessionChannels(ctx, session.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if len(channels) == 0 {
+		if isTerminal {
+			return true, nil
+		}
+
+		// If the session has no acked-updates, and it is not in a
+		// terminal state then something is wrong since the above check
+		// ensures that this session has been exhausted meaning that it
+		// should have MaxUpdates acked updates.
+		return false, fmt.Errorf("no acked-updates found for "+
+			"exhausted session %x", session.SessionID)
+	}
+
+	// If any of the channels that the session has acked-updates for are
+	// not closed, then the session is not yet closable.
+	numOpen, err := db.CountWtclientSessionOpenChannels(ctx, session.ID)
+	if err != nil {
+		return false, err
+	}
+
+	return numOpen == 0, nil
+}
+
+// CommitUpdate persists the CommittedUpdate provided in the slot for (session,
+// seqNum). This allows the client to retransmit this update on startup.
+func (s *SQLClientDB) CommitUpdate(id *SessionID,
+	update *CommittedUpdate) (uint16, error) {
+
+	var lastApplied uint16
+	err := s.update(func(ctx context.Context, db SQLClientQueries) error {
+		session, err := getSQLSession(ctx, db, *id)
+		if err != nil {
+			return err
+		}
+
+		// Check to see if a committed update already exists for this
+		// sequence number.
+		dbUpdate, err := db.GetWtclientCommittedUpdate(
+			ctx, sqlc.GetWtclientCommittedUpdateParams{
+				SessionID: session.ID,
+				SeqNum:    int32(update.SeqNum),
+			},
+		)
+		switch {
+		case err == nil:
+			// If an existing committed update has a different
+			// hint, we'll reject this newer update.
+			if !bytes.Equal(dbUpdate.Hint, update.Hint[:]) {
+				return ErrUpdateAlreadyCommitted
+			}
+
+			// Otherwise, capture the last applied value and
+			// succeed.
+			lastApplied = uint16(session.TowerLastApplied)
+
+			return nil
+
+		case !errors.Is(err, sql.ErrNoRows):
+			return err
+		}
+
+		// There's no committed update for this sequence number, ensure
+		// that we are committing the next unallocated one.
+		if update.SeqNum != uint16(session.SeqNum)+1 {
+			return ErrCommitUnorderedUpdate
+		}
+
+		// Increment the session's sequence number.
+		err = db.UpdateWtclientSessionSeqNum(
+			ctx, sqlc.UpdateWtclientSessionSeqNumParams{
+				SeqNum: int32(update.SeqNum),
+				ID:     session.ID,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Store the committed update under the requested sequence
+		// number.
+		err = db.InsertWtclientCommittedUpdate(
+			ctx, sqlc.InsertWtclientCommittedUpdateParams{
+				SessionID:     session.ID,
+				SeqNum:        int32(update.SeqNum),
+				ChanID:        update.BackupID.ChanID[:],
+				CommitHeight:  int64(update.BackupID.CommitHeight),
+				Hint:          update.Hint[:],
+				EncryptedBlob: update.EncryptedBlob,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Update the channel's max commitment height if needed.
+		err = updateSQLMaxCommitHeight(ctx, db, update.BackupID)
+		if err != nil {
+			return err
+		}
+
+		// Finally, capture the session's last applied value so it can
+		// be sent in the next state update to the tower.
+		lastApplied = uint16(session.TowerLastApplied)
+
+		return nil
+	}, func() {
+		lastApplied = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return lastApplied, nil
+}
+
+// AckUpdate persists an acknowledgment for a given (session, seqnum) pair. This
+// removes the update from the set of committed updates, and validates the
+// lastApplied value returned from the tower.
+func (s *SQLClientDB) AckUpdate(id *SessionID, seqNum uint16,
+	lastApplied uint16) error {
+
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		session, err := getSQLSession(ctx, db, *id)
+		if err != nil {
+			return err
+		}
+
+		// If the tower has acked a sequence number beyond our highest
+		// sequence number, fail.
+		if lastApplied > uint16(session.SeqNum) {
+			return ErrUnallocatedLastApplied
+		}
+
+		// If the tower acked with a lower sequence number than it gave
+		// us prior, fail.
+		if lastApplied < uint16(session.TowerLastApplied) {
+			return ErrLastAppliedReversion
+		}
+
+		err = db.UpdateWtclientSessionLastApplied(
+			ctx, sqlc.UpdateWtclientSessionLastAppliedParams{
+				TowerLastApplied: int32(lastApplied),
+				ID:               session.ID,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// Assert that a committed update exists for this sequence
+		// number, and remove it.
+		updateParams := sqlc.GetWtclientCommittedUpdateParams{
+			SessionID: session.ID,
+			SeqNum:    int32(seqNum),
+		}
+		update, err := db.GetWtclientCommittedUpdate(ctx, updateParams)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCommittedUpdateNotFound
+		} else if err != nil {
+			return err
+		}
+
+		err = db.DeleteWtclientCommittedUpdate(
+			ctx, sqlc.DeleteWtclientCommittedUpdateParams{
+				SessionID: session.ID,
+				SeqNum:    int32(seqNum),
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		// There is a chance that the channel corresponding to this
+		// update has been closed and that the details for this channel
+		// no longer exist in the tower client DB. In that case, we
+		// consider this a rogue update and all we do is make sure to
+		// keep track of the number of rogue updates for this session.
+		channel, err := db.GetWtclientChannel(ctx, update.ChanID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ackSQLRogueUpdate(ctx, db, session)
+		} else if err != nil {
+			return err
+		}
+
+		return addSQLAckedHeight(
+			ctx, db, session.ID, channel.ID, update.CommitHeight,
+		)
+	}, func() {})
+}
+
+// ackSQLRogueUpdate increments the rogue update count of the given session. In
+// the rare chance that the session only has rogue updates, the session is
+// marked as closable once the count reaches the session's MaxUpdates.
+func ackSQLRogueUpdate(ctx context.Context, db SQLClientQueries,
+	session sqlc.WtclientSession) error {
+
+	rogueCount, err := db.IncrementWtclientSessionRogueCount(ctx, session.ID)
+	if err != nil {
+		return err
+	}
+
+	body, err := unmarshalSQLSession(session)
+	if err != nil {
+		return err
+	}
+
+	if rogueCount != int64(body.Policy.MaxUpdates) {
+		return nil
+	}
+
+	// Before we mark the session as closable, we do a sanity check to
+	// ensure that this session has no acked ranges.
+	channels, err := db.ListWtclientSessionChannels(ctx, session.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(channels) != 0 {
+		return fmt.Errorf("session(%x) has acked ranges but has a "+
+			"rogue count indicating saturation", session.SessionID)
+	}
+
+	return db.UpdateWtclientSessionClosable(
+		ctx, sqlc.UpdateWtclientSessionClosableParams{
+			ClosableHeight: sqldb.SQLInt64(0),
+			ID:             session.ID,
+		},
+	)
+}
+
+// addSQLAckedHeight records the given commitment height of a channel as acked
+// by the given session. The height is merged with the ranges directly below
+// and above it, so that a session that acks the states of a channel in order
+// only ever has a single range for it.
+func addSQLAckedHeight(ctx context.Context, db SQLClientQueries, sessionID,
+	channelID, height int64) error {
+
+	// If the height is already part of a range, there is nothing to do.
+	_, err := db.GetWtclientAckedRangeContaining(
+		ctx, sqlc.GetWtclientAckedRangeContainingParams{
+			SessionID: sessionID,
+			ChannelID: channelID,
+			Height:    height,
+		},
+	)
+	switch {
+	case err == nil:
+		return nil
+
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+
+	// Look up the ranges that end right below and start right above the
+	// new height.
+	below, err := db.GetWtclientAckedRangeByEnd(
+		ctx, sqlc.GetWtclientAckedRangeByEndParams{
+			SessionID: sessionID,
+			ChannelID: channelID,
+			EndHeight: height - 1,
+		},
+	)
+	hasBelow := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	above, err := db.GetWtclientAckedRangeByStart(
+		ctx, sqlc.GetWtclientAckedRangeByStartParams{
+			SessionID:   sessionID,
+			ChannelID:   channelID,
+			StartHeight: height + 1,
+		},
+	)
+	hasAbove := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	// The range above is either merged into the range below or replaced
+	// by a range starting at the new height, so it can be removed in both
+	// cases.
+	end := height
+	if hasAbove {
+		end = above.EndHeight
+
+		err := db.DeleteWtclientAckedRange(
+			ctx, sqlc.DeleteWtclientAckedRangeParams{
+				SessionID:   sessionID,
+				ChannelID:   channelID,
+				StartHeight: above.StartHeight,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if hasBelow {
+		return db.UpdateWtclientAckedRangeEnd(
+			ctx, sqlc.UpdateWtclientAckedRangeEndParams{
+				EndHeight:   end,
+				SessionID:   sessionID,
+				ChannelID:   channelID,
+				StartHeight: below.StartHeight,
+			},
+		)
+	}
+
+	return db.InsertWtclientAckedRange(
+		ctx, sqlc.InsertWtclientAckedRangeParams{
+			SessionID:   sessionID,
+			ChannelID:   channelID,
+			StartHeight: height,
+			EndHeight:   end,
+		},
+	)
+}
+
+// GetDBQueue returns a BackupID Queue instance under the given namespace.
+func (s *SQLClientDB) GetDBQueue(namespace []byte) Queue[*BackupID] {
+	return &sqlBackupQueue{
+		db:        s,
+		namespace: namespace,
+	}
+}
+
+// TerminateSession sets the given session's status to CSessionTerminal meaning
+// that it will not be usable again. An error will be returned if the given
+// session still has un-acked updates that should be attended to.
+func (s *SQLClientDB) TerminateSession(id SessionID) error {
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		session, err := getSQLSession(ctx, db, id)
+		if err != nil {
+			return err
+		}
+
+		// If there are any un-acked updates for this session then we
+		// don't allow the change of status as these updates must first
+		// be dealt with somehow.
+		updates, err := db.ListWtclientCommittedUpdates(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(updates) > 0 {
+			return ErrSessionHasUnackedUpdates
+		}
+
+		return db.UpdateWtclientSessionStatus(
+			ctx, sqlc.UpdateWtclientSessionStatusParams{
+				Status: int16(CSessionTerminal),
+				ID:     session.ID,
+			},
+		)
+	}, func() {})
+}
+
+// DeleteCommittedUpdates deletes all the committed updates for the given
+// session.
+func (s *SQLClientDB) DeleteCommittedUpdates(id *SessionID) error {
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		session, err := db.GetWtclientSession(ctx, id[:])
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("session %s not found", id)
+		} else if err != nil {
+			return err
+		}
+
+		updates, err := db.ListWtclientCommittedUpdates(ctx, session.ID)
+		if err != nil {
+			return err
+		}
+
+		// If there are no un-acked committed updates, there is nothing
+		// left to do.
+		if len(updates) == 0 {
+			return nil
+		}
+
+		// Once we delete a committed update from the session, the
+		// SeqNum of the session will be incorrect and so the session
+		// should be marked as terminal.
+		err = db.UpdateWtclientSessionStatus(
+			ctx, sqlc.UpdateWtclientSessionStatusParams{
+				Status: int16(CSessionTerminal),
+				ID:     session.ID,
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		return db.DeleteWtclientCommittedUpdates(ctx, session.ID)
+	}, func() {})
+}
+
+// DBStats returns the storage statistics of the client database.
+func (s *SQLClientDB) DBStats() (*ClientDBStats, error) {
+	var stats *ClientDBStats
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		numSessions, err := db.CountWtclientSessions(ctx)
+		if err != nil {
+			return err
+		}
+
+		numClosable, err := db.CountWtclientClosableSessions(ctx)
+		if err != nil {
+			return err
+		}
+
+		numCommitted, err := db.CountWtclientCommittedUpdates(ctx)
+		if err != nil {
+			return err
+		}
+
+		committedBytes, err := db.SumWtclientCommittedUpdateBytes(ctx)
+		if err != nil {
+			return err
+		}
+
+		numQueued, err := db.CountWtclientQueueItems(ctx)
+		if err != nil {
+			return err
+		}
+
+		queuedBytes, err := db.SumWtclientQueueItemBytes(ctx)
+		if err != nil {
+			return err
+		}
+
+		numAcked, err := db.SumWtclientAckedUpdates(ctx)
+		if err != nil {
+			return err
+		}
+
+		numRanges, err := db.CountWtclientAckedRanges(ctx)
+		if err != nil {
+			return err
+		}
+
+		stats = &ClientDBStats{
+			NumSessions:         uint64(numSessions),
+			NumClosableSessions: uint64(numClosable),
+			NumCommittedUpdates: uint64(numCommitted),
+			CommittedBytes:      uint64(committedBytes),
+			NumQueuedBackups:    uint64(numQueued),
+			QueuedBytes:         uint64(queuedBytes),
+			NumAckedUpdates:     uint64(numAcked),
+			NumAckedRanges:      uint64(numRanges),
+		}
+
+		return nil
+	}, func() {
+		stats = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// AddJusticeReport persists a justice report received from a tower. A report
+// for the same state update of the session replaces the existing one.
+func (s *SQLClientDB) AddJusticeReport(report *ClientJusticeReport) error {
+	towerPubKey := report.TowerPubKey.SerializeCompressed()
+
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		return db.UpsertWtclientJusticeReport(
+			ctx, sqlc.UpsertWtclientJusticeReportParams{
+				SessionID:   report.SessionID[:],
+				SeqNum:      int32(report.SeqNum),
+				TowerPubKey: towerPubKey,
+				BreachTxid:  report.BreachTxID[:],
+				JusticeTxid: report.JusticeTxID[:],
+			},
+		)
+	}, func() {})
+}
+
+// ListJusticeReports returns all justice reports received from the client's
+// towers, in the order of their session IDs and sequence numbers.
+func (s *SQLClientDB) ListJusticeReports() ([]*ClientJusticeReport, error) {
+	var reports []*ClientJusticeReport
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		rows, err := db.ListWtclientJusticeReports(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			towerPubKey, err := btcec.ParsePubKey(row.TowerPubKey)
+			if err != nil {
+				return err
+			}
+
+			report := &ClientJusticeReport{
+				TowerPubKey: towerPubKey,
+			}
+			report.SeqNum = uint16(row.SeqNum)
+			copy(report.SessionID[:], row.SessionID)
+			copy(report.BreachTxID[:], row.BreachTxid)
+			copy(report.JusticeTxID[:], row.JusticeTxid)
+
+			reports = append(reports, report)
+		}
+
+		return nil
+	}, func() {
+		reports = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// getSQLSession loads the session with the given ID, or returns
+// ErrClientSessionNotFound if it doesn't exist.
+func getSQLSession(ctx context.Context, db SQLClientQueries,
+	id SessionID) (sqlc.WtclientSession, error) {
+
+	session, err := db.GetWtclientSession(ctx, id[:])
+	if errors.Is(err, sql.ErrNoRows) {
+		return session, ErrClientSessionNotFound
+	}
+
+	return session, err
+}
+
+// evalSQLSession converts the given session row into a ClientSession and
+// passes it through the call-backs and filters of the given list options. If
+// the session doesn't pass the filters, ErrSessionFailedFilterFn is returned.
+func evalSQLSession(ctx context.Context, db SQLClientQueries,
+	row sqlc.WtclientSession, opts ...ClientSessionListOption) (
+	*ClientSession, error) {
+
+	cfg := NewClientSessionCfg()
+	for _, o := range opts {
+		o(cfg)
+	}
+
+	session, err := unmarshalSQLSession(row)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.PreEvaluateFilterFn != nil && !cfg.PreEvaluateFilterFn(session) {
+		return nil, ErrSessionFailedFilterFn
+	}
+
+	// Pass the session's committed (un-acked) updates through the call-back
+	// if one is provided.
+	updates, err := db.ListWtclientCommittedUpdates(ctx, row.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.PerCommittedUpdate != nil {
+		for _, update := range updates {
+			cfg.PerCommittedUpdate(
+				session, unmarshalSQLCommittedUpdate(update),
+			)
+		}
+	}
+
+	// Pass the session's acked updates through the call-backs if they are
+	// provided.
+	if cfg.PerRogueUpdateCount != nil {
+		cfg.PerRogueUpdateCount(session, uint16(row.RogueUpdateCount))
+	}
+
+	if cfg.PerMaxHeight != nil || cfg.PerNumAckedUpdates != nil {
+		err := evalSQLSessionAcks(ctx, db, row.ID, session, cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.PostEvaluateFilterFn != nil &&
+		!cfg.PostEvaluateFilterFn(session, uint16(len(updates))) {
+
+		return nil, ErrSessionFailedFilterFn
+	}
+
+	return session, nil
+}
+
+// evalSQLSessionAcks passes the max acked height and the number of acked
+// updates of each of the session's channels to the call-backs of the given
+// config.
+func evalSQLSessionAcks(ctx context.Context, db SQLClientQueries,
+	sessionID int64, session *ClientSession,
+	cfg *ClientSessionListCfg) error {
+
+	ranges, err := db.ListWtclientSessionAckedRanges(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// The ranges are ordered by channel, so the ranges of a channel can
+	// be summed up until the next channel starts.
+	type chanAcks struct {
+		chanID    lnwire.ChannelID
+		maxHeight uint64
+		numAcked  uint64
+	}
+
+	var acks []*chanAcks
+	for _, r := range ranges {
+		var chanID lnwire.ChannelID
+		copy(chanID[:], r.ChanID)
+
+		if len(acks) == 0 || acks[len(acks)-1].chanID != chanID {
+			acks = append(acks, &chanAcks{chanID: chanID})
+		}
+
+		current := acks[len(acks)-1]
+		current.numAcked += uint64(r.EndHeight - r.StartHeight + 1)
+		if uint64(r.EndHeight) > current.maxHeight {
+			current.maxHeight = uint64(r.EndHeight)
+		}
+	}
+
+	for _, a := range acks {
+		if cfg.PerMaxHeight != nil {
+			cfg.PerMaxHeight(session, a.chanID, a.maxHeight)
+		}
+
+		if cfg.PerNumAckedUpdates != nil {
+			cfg.PerNumAckedUpdates(
+				session, a.chanID, uint16(a.numAcked),
+			)
+		}
+	}
+
+	return nil
+}
+
+// insertSQLSession inserts the given session with the given rogue update count
+// and closable height, and returns its db-assigned id.
+func insertSQLSession(ctx context.Context, db SQLClientQueries,
+	session *ClientSession, rogueCount uint64,
+	closableHeight fn.Option[uint32]) (int64, error) {
+
+	var policy bytes.Buffer
+	err := WriteElement(&policy, session.Policy)
+	if err != nil {
+		return 0, err
+	}
+
+	var dbClosableHeight sql.NullInt64
+	closableHeight.WhenSome(func(height uint32) {
+		dbClosableHeight = sqldb.SQLInt64(height)
+	})
+
+	return db.InsertWtclientSession(ctx, sqlc.InsertWtclientSessionParams{
+		SessionID:        session.ID[:],
+		TowerID:          int64(session.TowerID),
+		SeqNum:           int32(session.SeqNum),
+		TowerLastApplied: int32(session.TowerLastApplied),
+		KeyIndex:         int64(session.KeyIndex),
+		Status:           int16(session.Status),
+		Policy:           policy.Bytes(),
+		RewardPkScript:   session.RewardPkScript,
+		RogueUpdateCount: int64(rogueCount),
+		ClosableHeight:   dbClosableHeight,
+	})
+}
+
+// unmarshalSQLSession converts a session row into a ClientSession.
+func unmarshalSQLSession(row sqlc.WtclientSession) (*ClientSession, error) {
+	session := &ClientSession{
+		ClientSessionBody: ClientSessionBody{
+			SeqNum:           uint16(row.SeqNum),
+			TowerLastApplied: uint16(row.TowerLastApplied),
+			TowerID:          TowerID(row.TowerID),
+			KeyIndex:         uint32(row.KeyIndex),
+			Status:           CSessionStatus(row.Status),
+			RewardPkScript:   row.RewardPkScript,
+		},
+	}
+	copy(session.ID[:], row.SessionID)
+
+	err := ReadElement(bytes.NewReader(row.Policy), &session.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// unmarshalSQLCommittedUpdate converts a committed update row into a
+// CommittedUpdate.
+func unmarshalSQLCommittedUpdate(
+	row sqlc.WtclientCommittedUpdate) *CommittedUpdate {
+
+	update := &CommittedUpdate{
+		SeqNum: uint16(row.SeqNum),
+		CommittedUpdateBody: CommittedUpdateBody{
+			BackupID: BackupID{
+				CommitHeight: uint64(row.CommitHeight),
+			},
+			EncryptedBlob: row.EncryptedBlob,
+		},
+	}
+	copy(update.BackupID.ChanID[:], row.ChanID)
+	copy(update.Hint[:], row.Hint)
+
+	return update
+}
+
+// insertSQLTower inserts the given tower and sets its ID to the db-assigned
+// one.
+func insertSQLTower(ctx context.Context, db SQLClientQueries,
+	tower *Tower) error {
+
+	var addrs bytes.Buffer
+	err := WriteElement(&addrs, tower.Addresses)
+	if err != nil {
+		return err
+	}
+
+	id, err := db.InsertWtclientTower(ctx, sqlc.InsertWtclientTowerParams{
+		PubKey:    tower.IdentityKey.SerializeCompressed(),
+		Addresses: addrs.Bytes(),
+		Status:    int16(tower.Status),
+	})
+	if err != nil {
+		return err
+	}
+
+	tower.ID = TowerID(id)
+
+	return nil
+}
+
+// putSQLTower stores the addresses and status of an existing tower.
+func putSQLTower(ctx context.Context, db SQLClientQueries, tower *Tower) error {
+	var addrs bytes.Buffer
+	err := WriteElement(&addrs, tower.Addresses)
+	if err != nil {
+		return err
+	}
+
+	return db.UpdateWtclientTower(ctx, sqlc.UpdateWtclientTowerParams{
+		Addresses: addrs.Bytes(),
+		Status:    int16(tower.Status),
+		ID:        int64(tower.ID),
+	})
+}
+
+// unmarshalSQLTower converts a tower row into a Tower.
+func unmarshalSQLTower(row sqlc.WtclientTower) (*Tower, error) {
+	pubKey, err := btcec.ParsePubKey(row.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	tower := &Tower{
+		ID:          TowerID(row.ID),
+		IdentityKey: pubKey,
+		Status:      TowerStatus(row.Status),
+	}
+
+	err = ReadElement(bytes.NewReader(row.Addresses), &tower.Addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	return tower, nil
+}
+
+// updateSQLMaxCommitHeight raises the max commitment height of the channel of
+// the given backup to the backup's height. Nothing is done if the channel is
+// not registered or already has a higher max height.
+func updateSQLMaxCommitHeight(ctx context.Context, db SQLClientQueries,
+	backupID BackupID) error {
+
+	return db.UpdateWtclientChannelMaxHeight(
+		ctx, sqlc.UpdateWtclientChannelMaxHeightParams{
+			Height: sqldb.SQLInt64(backupID.CommitHeight),
+			ChanID: backupID.ChanID[:],
+		},
+	)
+}
+
+// sqlBackupQueue is a SQL implementation of the Queue interface for the
+// BackupIDs of a client. Items are ordered by their queue index: pushing to
+// the tail allocates an index above the current highest one, and pushing to
+// the head allocates indexes below the current lowest one.
+type sqlBackupQueue struct {
+	db        *SQLClientDB
+	namespace []byte
+}
+
+// A compile-time check to ensure that sqlBackupQueue implements the Queue
+// interface.
+var _ Queue[*BackupID] = (*sqlBackupQueue)(nil)
+
+// Len returns the number of tasks in the queue.
+//
+// NOTE: This is part of the Queue interface.
+func (q *sqlBackupQueue) Len() (uint64, error) {
+	var numItems uint64
+	err := q.db.view(func(ctx context.Context, db SQLClientQueries) error {
+		bounds, err := db.GetWtclientQueueBounds(ctx, q.namespace)
+		if err != nil {
+			return err
+		}
+
+		numItems = uint64(bounds.NumItems)
+
+		return nil
+	}, func() {
+		numItems = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numItems, nil
+}
+
+// Push pushes new BackupIDs to the tail of the queue.
+//
+// NOTE: This is part of the Queue interface.
+func (q *sqlBackupQueue) Push(items ...*BackupID) error {
+	return q.db.update(func(ctx context.Context, db SQLClientQueries) error {
+		bounds, err := db.GetWtclientQueueBounds(ctx, q.namespace)
+		if err != nil {
+			return err
+		}
+
+		nextIndex := bounds.MaxIndex + 1
+		if bounds.NumItems == 0 {
+			nextIndex = 0
+		}
+
+		for i, item := range items {
+			err := q.insert(ctx, db, nextIndex+int64(i), item)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+}
+
+// PushHead pushes new BackupIDs to the head of the queue, keeping the order in
+// which they were given.
+//
+// NOTE: This is part of the Queue interface.
+func (q *sqlBackupQueue) PushHead(items ...*BackupID) error {
+	return q.db.update(func(ctx context.Context, db SQLClientQueries) error {
+		bounds, err := db.GetWtclientQueueBounds(ctx, q.namespace)
+		if err != nil {
+			return err
+		}
+
+		firstIndex := bounds.MinIndex - int64(len(items))
+		if bounds.NumItems == 0 {
+			firstIndex = 0
+		}
+
+		for i, item := range items {
+			err := q.insert(ctx, db, firstIndex+int64(i), item)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+}
+
+// PopUpTo attempts to pop up to n items from the head of the queue. If the
+// queue is empty, then ErrEmptyQueue is returned.
+//
+// NOTE: This is part of the Queue interface.
+func (q *sqlBackupQueue) PopUpTo(n int) ([]*BackupID, error) {
+	var items []*BackupID
+	err := q.db.update(func(ctx context.Context, db SQLClientQueries) error {
+		rows, err := db.ListWtclientQueueItems(
+			ctx, sqlc.ListWtclientQueueItemsParams{
+				Namespace: q.namespace,
+				Limit:     int32(n),
+			},
+		)
+		if err != nil {
+			return err
+		}
+
+		if len(rows) == 0 {
+			return ErrEmptyQueue
+		}
+
+		items = make([]*BackupID, 0, len(rows))
+		for _, row := range rows {
+			var item BackupID
+			err := item.Decode(bytes.NewReader(row.Item))
+			if err != nil {
+				return err
+			}
+
+			err = db.DeleteWtclientQueueItem(
+				ctx, sqlc.DeleteWtclientQueueItemParams{
+					Namespace:  q.namespace,
+					QueueIndex: row.QueueIndex,
+				},
+			)
+			if err != nil {
+				return err
+			}
+
+			items = append(items, &item)
+		}
+
+		return nil
+	}, func() {
+		items = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// insert stores the given item under the given queue index, and raises the
+// max commitment height of the item's channel if needed.
+func (q *sqlBackupQueue) insert(ctx context.Context, db SQLClientQueries,
+	index int64, item *BackupID) error {
+
+	err := updateSQLMaxCommitHeight(ctx, db, *item)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := item.Encode(&b); err != nil {
+		return err
+	}
+
+	return db.InsertWtclientQueueItem(ctx, sqlc.InsertWtclientQueueItemParams{
+		Namespace:  q.namespace,
+		QueueIndex: index,
+		Item:       b.Bytes(),
+	})
+}
@@ -0,0 +1,168 @@
+package wtdb_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/sqldb"
+	"github.com/lightningnetwork/lnd/watchtower/wtclient"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/stretchr/testify/require"
+)
+
+// A compile-time check to ensure that SQLClientDB implements the wtclient.DB
+// interface.
+var _ wtclient.DB = (*wtdb.SQLClientDB)(nil)
+
+// newSQLClientDB is a clientDBInit that creates a SQLClientDB.
+func newSQLClientDB(t *testing.T) wtclient.DB {
+	return newSQLClientStore(t)
+}
+
+// newSQLClientStore creates a SQLClientDB backed by a fresh sqlite database.
+func newSQLClientStore(t *testing.T) *wtdb.SQLClientDB {
+	db := sqldb.NewTestSqliteDB(t).BaseDB
+
+	executor := sqldb.NewTransactionExecutor(
+		db, func(tx *sql.Tx) wtdb.SQLClientQueries {
+			return db.WithTx(tx)
+		},
+	)
+
+	return wtdb.NewSQLClientDB(executor)
+}
+
+// TestSQLClientDBMigrateFromKV asserts that the towers, sessions, channels,
+// queued backups and justice reports of a kv client database are carried over
+// to the SQL store.
+func TestSQLClientDBMigrateFromKV(t *testing.T) {
+	t.Parallel()
+
+	const maxUpdates = 4
+
+	bdb, err := wtdb.NewBoltBackendCreator(
+		true, t.TempDir(), "wtclient.db",
+	)(&kvdb.BoltConfig{DBTimeout: kvdb.DefaultDBTimeout})
+	require.NoError(t, err)
+
+	kvDB, err := wtdb.OpenClientDB(bdb)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		kvDB.Close()
+	})
+
+	// Populate the kv store with a tower, a session that has both acked
+	// and committed updates, a closed channel and a queued backup.
+	h := newClientDBHarness(t, func(*testing.T) wtclient.DB {
+		return kvDB
+	})
+	tower := h.newTower()
+	session := h.randSession(t, tower.ID, maxUpdates)
+	h.insertSession(session, nil)
+
+	chanID1 := randChannelID(t)
+	chanID2 := randChannelID(t)
+	h.registerChan(chanID1, []byte{1}, nil)
+	h.registerChan(chanID2, []byte{2}, nil)
+
+	for i := uint16(1); i <= 3; i++ {
+		update := randCommittedUpdateForChanWithHeight(
+			t, chanID1, i, uint64(i),
+		)
+		lastApplied := h.commitUpdate(&session.ID, update, nil)
+		h.ackUpdate(&session.ID, i, lastApplied, nil)
+	}
+
+	committed := randCommittedUpdateForChanWithHeight(t, chanID2, 4, 7)
+	h.commitUpdate(&session.ID, committed, nil)
+	h.markChannelClosed(chanID1, 100, nil)
+
+	namespace, err := blobType.Identifier()
+	require.NoError(t, err)
+
+	queued := []*wtdb.BackupID{
+		{ChanID: chanID2, CommitHeight: 8},
+		{ChanID: chanID2, CommitHeight: 9},
+	}
+	queue := kvDB.GetDBQueue([]byte(namespace))
+	require.NoError(t, queue.Push(queued[1]))
+	require.NoError(t, queue.PushHead(queued[0]))
+
+	report := &wtdb.ClientJusticeReport{
+		JusticeReport: wtdb.JusticeReport{
+			SeqNum:      2,
+			BreachTxID:  [32]byte{1},
+			JusticeTxID: [32]byte{2},
+		},
+		TowerPubKey: tower.IdentityKey,
+		SessionID:   session.ID,
+	}
+	require.NoError(t, kvDB.AddJusticeReport(report))
+
+	// Migrate the kv store, and do so a second time to ensure that the
+	// migration is only applied once.
+	sqlDB := newSQLClientStore(t)
+	require.NoError(t, sqlDB.MigrateFromKV(kvDB))
+	require.NoError(t, sqlDB.MigrateFromKV(kvDB))
+
+	towers, err := sqlDB.ListTowers(nil)
+	require.NoError(t, err)
+	require.Len(t, towers, 1)
+	require.Equal(t, tower.IdentityKey, towers[0].IdentityKey)
+	require.Equal(t, tower.Addresses, towers[0].Addresses)
+
+	dbSession, err := sqlDB.GetClientSession(session.ID)
+	require.NoError(t, err)
+	require.Equal(t, towers[0].ID, dbSession.TowerID)
+	require.Equal(t, session.Policy, dbSession.Policy)
+	require.EqualValues(t, 4, dbSession.SeqNum)
+
+	acked, err := sqlDB.FetchAckedUpdates(&session.ID)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []wtdb.BackupID{
+		{ChanID: chanID1, CommitHeight: 1},
+		{ChanID: chanID1, CommitHeight: 2},
+		{ChanID: chanID1, CommitHeight: 3},
+	}, acked)
+
+	updates, err := sqlDB.FetchSessionCommittedUpdates(&session.ID)
+	require.NoError(t, err)
+	require.Equal(t, []wtdb.CommittedUpdate{*committed}, updates)
+
+	// Only the open channel is loaded, with the max height of the queued
+	// backups.
+	infos, err := sqlDB.FetchChanInfos()
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	require.Equal(t, []byte{2}, []byte(infos[chanID2].SweepPkScript))
+	require.Equal(t, uint64(9), infos[chanID2].MaxHeight.UnwrapOr(0))
+
+	items, err := sqlDB.GetDBQueue([]byte(namespace)).PopUpTo(10)
+	require.NoError(t, err)
+	require.Equal(t, queued, items)
+
+	reports, err := sqlDB.ListJusticeReports()
+	require.NoError(t, err)
+	require.Equal(t, []*wtdb.ClientJusticeReport{report}, reports)
+
+	// Once the committed update is acked, the session is exhausted, and
+	// closing the second channel makes it closable.
+	require.NoError(t, sqlDB.AckUpdate(&session.ID, 4, 4))
+	closable, err := sqlDB.MarkChannelClosed(chanID2, 101)
+	require.NoError(t, err)
+	require.Equal(t, []wtdb.SessionID{session.ID}, closable)
+
+	// Deleting the session also prunes its acked updates and the details
+	// of both channels.
+	require.NoError(t, sqlDB.DeleteSession(session.ID))
+
+	stats, err := sqlDB.DBStats()
+	require.NoError(t, err)
+	require.Zero(t, stats.NumSessions)
+	require.Zero(t, stats.NumAckedUpdates)
+	require.Zero(t, stats.NumAckedRanges)
+
+	_, err = sqlDB.MarkChannelClosed(chanID1, 102)
+	require.ErrorIs(t, err, wtdb.ErrChannelNotRegistered)
+}
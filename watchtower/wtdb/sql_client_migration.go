@@ -0,0 +1,581 @@
+package wtdb
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/fn"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/sqldb"
+	"github.com/lightningnetwork/lnd/sqldb/sqlc"
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+)
+
+// kvKeyReservation is a session key index that the kv store has reserved for
+// a tower and blob type.
+type kvKeyReservation struct {
+	towerID  TowerID
+	blobType blob.Type
+	index    uint32
+}
+
+// kvChannel holds the details the kv store keeps for a registered channel.
+type kvChannel struct {
+	chanID       lnwire.ChannelID
+	summary      *ClientChanSummary
+	maxHeight    fn.Option[uint64]
+	closedHeight fn.Option[uint32]
+	policy       ChannelBackupPolicy
+}
+
+// kvSession holds a session of the kv store along with its updates.
+type kvSession struct {
+	session        *ClientSession
+	rogueCount     uint64
+	committed      []CommittedUpdate
+	closableHeight fn.Option[uint32]
+
+	// ackedRanges maps the channels the session has acked updates for to
+	// the start and end heights of the acked ranges.
+	ackedRanges map[lnwire.ChannelID]map[uint64]uint64
+}
+
+// kvClientData is a snapshot of the contents of a kv client database.
+type kvClientData struct {
+	towers       []*Tower
+	keyIndex     uint64
+	reservations []kvKeyReservation
+	channels     []kvChannel
+	sessions     []*kvSession
+
+	// queues maps a queue namespace to its encoded items, ordered from
+	// head to tail.
+	queues map[string][][]byte
+
+	justiceReports []*ClientJusticeReport
+}
+
+// MigrateFromKV copies the towers, sessions, channels, queued backups and
+// justice reports of the given kv client database into the SQL store. The
+// migration is only done once, any later call is a no-op. The kv database
+// itself is not modified.
+func (s *SQLClientDB) MigrateFromKV(kvDB *ClientDB) error {
+	var migrated bool
+	err := s.view(func(ctx context.Context, db SQLClientQueries) error {
+		value, err := db.GetWtclientState(ctx, sqlStateKVMigrated)
+		if err != nil {
+			return err
+		}
+
+		migrated = value != 0
+
+		return nil
+	}, func() {
+		migrated = false
+	})
+	if err != nil {
+		return err
+	}
+
+	if migrated {
+		return nil
+	}
+
+	data, err := kvDB.fetchClientData()
+	if err != nil {
+		return fmt.Errorf("unable to read kv client db: %w", err)
+	}
+
+	return s.update(func(ctx context.Context, db SQLClientQueries) error {
+		err := insertKVClientData(ctx, db, data)
+		if err != nil {
+			return err
+		}
+
+		return db.UpdateWtclientState(ctx, sqlc.UpdateWtclientStateParams{
+			Value: 1,
+			Name:  sqlStateKVMigrated,
+		})
+	}, func() {})
+}
+
+// insertKVClientData inserts a snapshot of a kv client database into the SQL
+// store.
+func insertKVClientData(ctx context.Context, db SQLClientQueries,
+	data *kvClientData) error {
+
+	// The SQL store assigns new tower IDs, so we keep track of the ID each
+	// of the kv towers ends up with.
+	towerIDs := make(map[TowerID]TowerID, len(data.towers))
+	for _, tower := range data.towers {
+		kvID := tower.ID
+		err := insertSQLTower(ctx, db, tower)
+		if err != nil {
+			return err
+		}
+
+		towerIDs[kvID] = tower.ID
+	}
+
+	err := db.UpdateWtclientState(ctx, sqlc.UpdateWtclientStateParams{
+		Value: int64(data.keyIndex),
+		Name:  sqlStateSessionKeyIndex,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range data.reservations {
+		err := db.UpsertWtclientSessionKeyReservation(
+			ctx, sqlc.UpsertWtclientSessionKeyReservationParams{
+				TowerID:  int64(towerIDs[r.towerID]),
+				BlobType: int32(r.blobType),
+				KeyIndex: int64(r.index),
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	channelIDs := make(map[lnwire.ChannelID]int64, len(data.channels))
+	for _, c := range data.channels {
+		params := sqlc.InsertWtclientChannelParams{
+			ChanID:        c.chanID[:],
+			SweepPkScript: c.summary.SweepPkScript,
+		}
+		c.maxHeight.WhenSome(func(height uint64) {
+			params.MaxCommitHeight = sqldb.SQLInt64(height)
+		})
+		c.closedHeight.WhenSome(func(height uint32) {
+			params.ClosedHeight = sqldb.SQLInt64(height)
+		})
+
+		id, err := db.InsertWtclientChannel(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		if c.policy != (ChannelBackupPolicy{}) {
+			err := db.UpdateWtclientChannelBackupPolicy(
+				ctx, sqlc.UpdateWtclientChannelBackupPolicyParams{
+					BackupExcluded: c.policy.Excluded,
+					MinSweepFeeRate: int64(
+						c.policy.MinSweepFeeRate,
+					),
+					ID: id,
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		channelIDs[c.chanID] = id
+	}
+
+	for _, s := range data.sessions {
+		towerID, ok := towerIDs[s.session.TowerID]
+		if !ok {
+			return fmt.Errorf("tower %d of session %s not found",
+				s.session.TowerID, s.session.ID)
+		}
+		s.session.TowerID = towerID
+
+		sessionID, err := insertSQLSession(
+			ctx, db, s.session, s.rogueCount, s.closableHeight,
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, update := range s.committed {
+			err := db.InsertWtclientCommittedUpdate(
+				ctx, sqlc.InsertWtclientCommittedUpdateParams{
+					SessionID: sessionID,
+					SeqNum:    int32(update.SeqNum),
+					ChanID:    update.BackupID.ChanID[:],
+					CommitHeight: int64(
+						update.BackupID.CommitHeight,
+					),
+					Hint:          update.Hint[:],
+					EncryptedBlob: update.EncryptedBlob,
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		for chanID, ranges := range s.ackedRanges {
+			channelID, ok := channelIDs[chanID]
+			if !ok {
+				return fmt.Errorf("channel %s of session %s "+
+					"not found", chanID, s.session.ID)
+			}
+
+			for start, end := range ranges {
+				err := db.InsertWtclientAckedRange(
+					ctx, sqlc.InsertWtclientAckedRangeParams{
+						SessionID:   sessionID,
+						ChannelID:   channelID,
+						StartHeight: int64(start),
+						EndHeight:   int64(end),
+					},
+				)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for namespace, items := range data.queues {
+		for i, item := range items {
+			err := db.InsertWtclientQueueItem(
+				ctx, sqlc.InsertWtclientQueueItemParams{
+					Namespace:  []byte(namespace),
+					QueueIndex: int64(i),
+					Item:       item,
+				},
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, r := range data.justiceReports {
+		towerPubKey := r.TowerPubKey.SerializeCompressed()
+		err := db.UpsertWtclientJusticeReport(
+			ctx, sqlc.UpsertWtclientJusticeReportParams{
+				SessionID:   r.SessionID[:],
+				SeqNum:      int32(r.SeqNum),
+				TowerPubKey: towerPubKey,
+				BreachTxid:  r.BreachTxID[:],
+				JusticeTxid: r.JusticeTxID[:],
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchClientData reads a snapshot of the full contents of the database. It
+// must not be called while the database is being written to.
+func (c *ClientDB) fetchClientData() (*kvClientData, error) {
+	towers, err := c.ListTowers(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	rogueCounts := make(map[SessionID]uint64)
+	sessions, err := c.ListClientSessions(
+		nil, WithPerRogueUpdateCount(
+			func(s *ClientSession, count uint16) {
+				rogueCounts[s.ID] = uint64(count)
+			},
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	closable, err := c.ListClosableSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	justiceReports, err := c.ListJusticeReports()
+	if err != nil {
+		return nil, err
+	}
+
+	data := &kvClientData{
+		towers:         towers,
+		queues:         make(map[string][][]byte),
+		justiceReports: justiceReports,
+	}
+
+	sessionData := make(map[SessionID]*kvSession, len(sessions))
+	for id, session := range sessions {
+		committed, err := c.FetchSessionCommittedUpdates(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		s := &kvSession{
+			session:     session,
+			rogueCount:  rogueCounts[id],
+			committed:   committed,
+			ackedRanges: make(map[lnwire.ChannelID]map[uint64]uint64),
+		}
+		if height, ok := closable[id]; ok {
+			s.closableHeight = fn.Some(height)
+		}
+
+		sessionData[id] = s
+		data.sessions = append(data.sessions, s)
+	}
+
+	// The sequence of a bucket, which holds the last reserved session key
+	// index, is only exposed for writable buckets. So the remaining data
+	// is read in a write transaction, without modifying it.
+	err = kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		err := fetchKVKeyIndexes(tx, towers, data)
+		if err != nil {
+			return err
+		}
+
+		err = fetchKVChannels(tx, data)
+		if err != nil {
+			return err
+		}
+
+		err = fetchKVAckedRanges(tx, sessionData)
+		if err != nil {
+			return err
+		}
+
+		return fetchKVQueues(tx, data)
+	}, func() {
+		data.reservations = nil
+		data.channels = nil
+		data.queues = make(map[string][][]byte)
+		for _, s := range sessionData {
+			s.ackedRanges = make(
+				map[lnwire.ChannelID]map[uint64]uint64,
+			)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// fetchKVKeyIndexes reads the last reserved session key index and the key
+// index reservations of the given towers.
+func fetchKVKeyIndexes(tx kvdb.RwTx, towers []*Tower,
+	data *kvClientData) error {
+
+	keyIndexes := tx.ReadWriteBucket(cSessionKeyIndexBkt)
+	if keyIndexes == nil {
+		return ErrUninitializedDB
+	}
+
+	data.keyIndex = keyIndexes.Sequence()
+
+	for _, tower := range towers {
+		for _, blobType := range queueBlobTypes {
+			keyBytes := createSessionKeyIndexKey(tower.ID, blobType)
+
+			// As in getSessionKeyIndex, altruist commit sessions
+			// may still use the legacy key format.
+			indexBytes := keyIndexes.Get(keyBytes)
+			if indexBytes == nil &&
+				blobType == blob.TypeAltruistCommit {
+
+				indexBytes = keyIndexes.Get(tower.ID.Bytes())
+			}
+
+			if len(indexBytes) != 4 {
+				continue
+			}
+
+			data.reservations = append(
+				data.reservations, kvKeyReservation{
+					towerID:  tower.ID,
+					blobType: blobType,
+					index:    byteOrder.Uint32(indexBytes),
+				},
+			)
+		}
+	}
+
+	return nil
+}
+
+// fetchKVChannels reads the details of all registered channels.
+func fetchKVChannels(tx kvdb.RTx, data *kvClientData) error {
+	chanDetailsBkt := tx.ReadBucket(cChanDetailsBkt)
+	if chanDetailsBkt == nil {
+		return ErrUninitializedDB
+	}
+
+	return chanDetailsBkt.ForEach(func(k, _ []byte) error {
+		chanDetails := chanDetailsBkt.NestedReadBucket(k)
+		if chanDetails == nil {
+			return ErrCorruptChanDetails
+		}
+
+		summary, err := getChanSummary(chanDetails)
+		if err != nil {
+			return err
+		}
+
+		channel := kvChannel{
+			summary: summary,
+		}
+		copy(channel.chanID[:], k)
+
+		maxHeightBytes := chanDetails.Get(cChanMaxCommitmentHeight)
+		if len(maxHeightBytes) != 0 {
+			height, err := readBigSize(maxHeightBytes)
+			if err != nil {
+				return err
+			}
+
+			channel.maxHeight = fn.Some(height)
+		}
+
+		policyBytes := chanDetails.Get(cChanBackupPolicy)
+		if len(policyBytes) != 0 {
+			err := channel.policy.Decode(bytes.NewReader(policyBytes))
+			if err != nil {
+				return err
+			}
+		}
+
+		closedHeightBytes := chanDetails.Get(cChanClosedHeight)
+		if len(closedHeightBytes) == 4 {
+			channel.closedHeight = fn.Some(
+				byteOrder.Uint32(closedHeightBytes),
+			)
+		}
+
+		data.channels = append(data.channels, channel)
+
+		return nil
+	})
+}
+
+// fetchKVAckedRanges reads the acked ranges of the given sessions.
+func fetchKVAckedRanges(tx kvdb.RTx,
+	sessions map[SessionID]*kvSession) error {
+
+	sessionsBkt := tx.ReadBucket(cSessionBkt)
+	if sessionsBkt == nil {
+		return ErrUninitializedDB
+	}
+
+	chanIDIndexBkt := tx.ReadBucket(cChanIDIndexBkt)
+	if chanIDIndexBkt == nil {
+		return ErrUninitializedDB
+	}
+
+	for id, s := range sessions {
+		sessionBkt := sessionsBkt.NestedReadBucket(id[:])
+		if sessionBkt == nil {
+			return ErrClientSessionNotFound
+		}
+
+		ackRanges := sessionBkt.NestedReadBucket(cSessionAckRangeIndex)
+		if ackRanges == nil {
+			continue
+		}
+
+		err := ackRanges.ForEach(func(dbChanID, _ []byte) error {
+			rangesBkt := ackRanges.NestedReadBucket(dbChanID)
+			if rangesBkt == nil {
+				return nil
+			}
+
+			chanIDBytes := chanIDIndexBkt.Get(dbChanID)
+			if len(chanIDBytes) != 32 { //nolint:gomnd
+				return fmt.Errorf("channel ID not found")
+			}
+
+			var chanID lnwire.ChannelID
+			copy(chanID[:], chanIDBytes)
+
+			ranges := make(map[uint64]uint64)
+			err := rangesBkt.ForEach(func(k, v []byte) error {
+				start, err := readBigSize(k)
+				if err != nil {
+					return err
+				}
+
+				end, err := readBigSize(v)
+				if err != nil {
+					return err
+				}
+
+				ranges[start] = end
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			s.ackedRanges[chanID] = ranges
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchKVQueues reads the items of the backup queues of all blob types, head
+// queue items first.
+func fetchKVQueues(tx kvdb.RTx, data *kvClientData) error {
+	for _, blobType := range queueBlobTypes {
+		namespace, err := blobType.Identifier()
+		if err != nil {
+			return err
+		}
+
+		namespacedBkt := tx.ReadBucket([]byte(namespace))
+		if namespacedBkt == nil {
+			continue
+		}
+
+		tasksBkt := namespacedBkt.NestedReadBucket(cTaskQueue)
+		if tasksBkt == nil {
+			continue
+		}
+
+		var items [][]byte
+		for _, queueName := range [][]byte{queueHeadBkt, queueMainBkt} {
+			queueBkt := tasksBkt.NestedReadBucket(queueName)
+			if queueBkt == nil {
+				continue
+			}
+
+			itemsBucket := queueBkt.NestedReadBucket(itemsBkt)
+			if itemsBucket == nil {
+				continue
+			}
+
+			// Items are keyed by their BigSize encoded index, so
+			// iterating over the keys yields them in queue order.
+			err := itemsBucket.ForEach(func(_, v []byte) error {
+				item := make([]byte, len(v))
+				copy(item, v)
+				items = append(items, item)
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(items) > 0 {
+			data.queues[namespace] = items
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package wtdb
+
+import (
+	"github.com/lightningnetwork/lnd/watchtower/blob"
+)
+
+// StorageStats summarizes the storage a tower uses for the sessions of its
+// clients.
+type StorageStats struct {
+	// NumSessions is the number of sessions the tower stores.
+	NumSessions uint64
+
+	// NumUpdates is the number of state updates the tower stores.
+	NumUpdates uint64
+
+	// BlobBytes is the size of the encrypted blobs of all stored state
+	// updates.
+	BlobBytes uint64
+
+	// ReservedBlobBytes is the size the encrypted blobs would have if
+	// every session used all of its negotiated updates.
+	ReservedBlobBytes uint64
+}
+
+// AddSession adds the storage of the given session to the stats.
+func (s *StorageStats) AddSession(session *SessionInfo) error {
+	blobSize, err := session.BlobSize()
+	if err != nil {
+		return err
+	}
+
+	s.NumSessions++
+	s.NumUpdates += uint64(session.LastApplied)
+	s.BlobBytes += uint64(session.LastApplied) * blobSize
+	s.ReservedBlobBytes += uint64(session.Policy.MaxUpdates) * blobSize
+
+	return nil
+}
+
+// ReservedBytes returns the size of the encrypted blobs of the session if it
+// used all of its negotiated updates.
+func (s *SessionInfo) ReservedBytes() (uint64, error) {
+	blobSize, err := s.BlobSize()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(s.Policy.MaxUpdates) * blobSize, nil
+}
+
+// BlobSize returns the size of the encrypted blob of each state update of the
+// session, which follows from the session's blob type.
+func (s *SessionInfo) BlobSize() (uint64, error) {
+	commitType, err := s.Policy.BlobType.CommitmentType(nil)
+	if err != nil {
+		return 0, err
+	}
+
+	kit, err := commitType.EmptyJusticeKit()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(blob.Size(kit)), nil
+}
+
+// ClientDBStats summarizes the storage the client database uses for its
+// sessions and pending backups.
+type ClientDBStats struct {
+	// NumSessions is the number of sessions the client stores.
+	NumSessions uint64
+
+	// NumClosableSessions is the number of sessions that are waiting to be
+	// deleted.
+	NumClosableSessions uint64
+
+	// NumCommittedUpdates is the number of updates that have been
+	// committed to a session but not yet acked by its tower.
+	NumCommittedUpdates uint64
+
+	// CommittedBytes is the size of the hints and encrypted blobs of all
+	// committed updates.
+	CommittedBytes uint64
+
+	// NumQueuedBackups is the number of backups that are waiting to be
+	// assigned to a session.
+	NumQueuedBackups uint64
+
+	// QueuedBytes is the size of the encoded backup IDs of all queued
+	// backups.
+	QueuedBytes uint64
+
+	// NumAckedUpdates is the number of updates that have been acked by a
+	// tower, excluding rogue updates.
+	NumAckedUpdates uint64
+
+	// NumAckedRanges is the number of ranges the acked updates are stored
+	// as.
+	NumAckedRanges uint64
+}
+
+// DataSize returns the size of the stored committed updates and queued
+// backups, which make up the client's backlog.
+func (s *ClientDBStats) DataSize() uint64 {
+	return s.CommittedBytes + s.QueuedBytes
+}
@@ -34,10 +34,24 @@ var (
 	//   lookoutTipKey -> block epoch
 	lookoutTipBkt = []byte("lookout-tip-bucket")
 
+	// justiceReportsBkt is a bucket containing the justice transactions
+	// the tower published, in nested buckets per session:
+	//
+	// justice-reports-bucket
+	// 	-> session id
+	// 		-> seqnum -> encoded JusticeReport
+	justiceReportsBkt = []byte("justice-reports-bucket")
+
 	// lookoutTipKey is a static key used to retrieve lookout tip's block
 	// epoch from the lookoutTipBkt.
 	lookoutTipKey = []byte("lookout-tip")
 
+	// reservedBytesKey is a static key in the metadataBkt that stores the
+	// number of bytes reserved for the encrypted blobs of all sessions as
+	// a uint64. It is updated in the same transaction as the sessions, so
+	// the storage quota can be enforced without iterating all sessions.
+	reservedBytesKey = []byte("reserved-bytes")
+
 	// ErrNoSessionHintIndex signals that an active session does not have an
 	// initialized index for tracking its own state updates.
 	ErrNoSessionHintIndex = errors.New("session hint index missing")
@@ -45,6 +59,11 @@ var (
 	// ErrInvalidBlobSize indicates that the encrypted blob provided by the
 	// client is not valid according to the blob type of the session.
 	ErrInvalidBlobSize = errors.New("invalid blob size")
+
+	// ErrStorageQuotaExceeded signals that a session can't be inserted as
+	// reserving the storage for all of its updates would exceed the
+	// tower's storage quota.
+	ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
 )
 
 // TowerDB is single database providing a persistent storage engine for the
@@ -98,6 +117,7 @@ func initTowerDBBuckets(tx kvdb.RwTx) error {
 		updateIndexBkt,
 		updatesBkt,
 		lookoutTipBkt,
+		justiceReportsBkt,
 	}
 
 	for _, bucket := range buckets {
@@ -167,12 +187,29 @@ func (t *TowerDB) GetSessionInfo(id *SessionID) (*SessionInfo, error) {
 // InsertSessionInfo records a negotiated session in the tower database. An
 // error is returned if the session already exists.
 func (t *TowerDB) InsertSessionInfo(session *SessionInfo) error {
+	return t.InsertSessionInfoWithQuota(session, 0)
+}
+
+// InsertSessionInfoWithQuota records a negotiated session in the tower
+// database, like InsertSessionInfo, but only if the bytes reserved for the
+// encrypted blobs of all sessions don't exceed maxReserved afterwards. If the
+// session replaces an unused session, the reservation of the latter is
+// released. ErrStorageQuotaExceeded is returned if the session doesn't fit. A
+// maxReserved of zero means no limit.
+func (t *TowerDB) InsertSessionInfoWithQuota(session *SessionInfo,
+	maxReserved uint64) error {
+
 	return kvdb.Update(t.db, func(tx kvdb.RwTx) error {
 		sessions := tx.ReadWriteBucket(sessionsBkt)
 		if sessions == nil {
 			return ErrUninitializedDB
 		}
 
+		metadata := tx.ReadWriteBucket(metadataBkt)
+		if metadata == nil {
+			return ErrUninitializedDB
+		}
+
 		updateIndex := tx.ReadWriteBucket(updateIndexBkt)
 		if updateIndex == nil {
 			return ErrUninitializedDB
@@ -196,6 +233,36 @@ func (t *TowerDB) InsertSessionInfo(session *SessionInfo) error {
 			return err
 		}
 
+		// Reserve the storage for all updates of the session, releasing
+		// the reservation of the unused session it replaces.
+		reserved, err := getReservedBytes(metadata, sessions)
+		if err != nil {
+			return err
+		}
+
+		if dbSession != nil {
+			dbReserved, err := dbSession.ReservedBytes()
+			if err != nil {
+				return err
+			}
+			reserved -= dbReserved
+		}
+
+		sessionReserved, err := session.ReservedBytes()
+		if err != nil {
+			return err
+		}
+		reserved += sessionReserved
+
+		if maxReserved != 0 && reserved > maxReserved {
+			return ErrStorageQuotaExceeded
+		}
+
+		err = putReservedBytes(metadata, reserved)
+		if err != nil {
+			return err
+		}
+
 		err = putSession(sessions, session)
 		if err != nil {
 			return err
@@ -328,8 +395,31 @@ func (t *TowerDB) DeleteSession(target SessionID) error {
 			return ErrUninitializedDB
 		}
 
+		metadata := tx.ReadWriteBucket(metadataBkt)
+		if metadata == nil {
+			return ErrUninitializedDB
+		}
+
 		// Fail if the session doesn't exit.
-		_, err := getSession(sessions, target[:])
+		session, err := getSession(sessions, target[:])
+		if err != nil {
+			return err
+		}
+
+		// Release the storage reserved for the session before removing
+		// it, such that the reservation is still accounted for if the
+		// counter has to be computed first.
+		reserved, err := getReservedBytes(metadata, sessions)
+		if err != nil {
+			return err
+		}
+
+		sessionReserved, err := session.ReservedBytes()
+		if err != nil {
+			return err
+		}
+
+		err = putReservedBytes(metadata, reserved-sessionReserved)
 		if err != nil {
 			return err
 		}
@@ -387,12 +477,172 @@ func (t *TowerDB) DeleteSession(target SessionID) error {
 			}
 		}
 
+		// Remove the justice reports of the session, if any.
+		justiceReports := tx.ReadWriteBucket(justiceReportsBkt)
+		if justiceReports == nil {
+			return ErrUninitializedDB
+		}
+
+		if justiceReports.NestedReadWriteBucket(target[:]) != nil {
+			err := justiceReports.DeleteNestedBucket(target[:])
+			if err != nil {
+				return err
+			}
+		}
+
 		// Finally, remove this session from the update index, which
 		// also removes any of the indexed hints beneath it.
 		return removeSessionHintBkt(updateIndex, &target)
 	}, func() {})
 }
 
+// InsertJusticeReport records that the tower published a justice transaction
+// for a state update of the given session, such that it can be reported to the
+// client. A report for the same state update replaces the existing one.
+func (t *TowerDB) InsertJusticeReport(id SessionID,
+	report *JusticeReport) error {
+
+	return kvdb.Update(t.db, func(tx kvdb.RwTx) error {
+		sessions := tx.ReadBucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		justiceReports := tx.ReadWriteBucket(justiceReportsBkt)
+		if justiceReports == nil {
+			return ErrUninitializedDB
+		}
+
+		// Fail if the session doesn't exist.
+		_, err := getSession(sessions, id[:])
+		if err != nil {
+			return err
+		}
+
+		sessionReports, err := justiceReports.CreateBucketIfNotExists(
+			id[:],
+		)
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := report.Encode(&b); err != nil {
+			return err
+		}
+
+		var seqNum [2]byte
+		byteOrder.PutUint16(seqNum[:], report.SeqNum)
+
+		return sessionReports.Put(seqNum[:], b.Bytes())
+	}, func() {})
+}
+
+// FetchJusticeReports returns the justice transactions the tower published
+// for the state updates of the given session, in the order of their sequence
+// numbers.
+func (t *TowerDB) FetchJusticeReports(id SessionID) ([]JusticeReport, error) {
+	var reports []JusticeReport
+	err := kvdb.View(t.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		justiceReports := tx.ReadBucket(justiceReportsBkt)
+		if justiceReports == nil {
+			return ErrUninitializedDB
+		}
+
+		// Fail if the session doesn't exist.
+		_, err := getSession(sessions, id[:])
+		if err != nil {
+			return err
+		}
+
+		sessionReports := justiceReports.NestedReadBucket(id[:])
+		if sessionReports == nil {
+			return nil
+		}
+
+		return sessionReports.ForEach(func(_, v []byte) error {
+			var report JusticeReport
+			err := report.Decode(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			reports = append(reports, report)
+
+			return nil
+		})
+	}, func() {
+		reports = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// StorageStats returns a summary of the storage used by the sessions of the
+// tower's clients.
+func (t *TowerDB) StorageStats() (*StorageStats, error) {
+	var stats *StorageStats
+	err := kvdb.View(t.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		return sessions.ForEach(func(id, _ []byte) error {
+			session, err := getSession(sessions, id)
+			if err != nil {
+				return err
+			}
+
+			return stats.AddSession(session)
+		})
+	}, func() {
+		stats = &StorageStats{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// ReservedBytes returns the number of bytes reserved for the encrypted blobs
+// of all sessions, as if every session used all of its negotiated updates.
+func (t *TowerDB) ReservedBytes() (uint64, error) {
+	var reserved uint64
+	err := kvdb.View(t.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(sessionsBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		metadata := tx.ReadBucket(metadataBkt)
+		if metadata == nil {
+			return ErrUninitializedDB
+		}
+
+		var err error
+		reserved, err = getReservedBytes(metadata, sessions)
+
+		return err
+	}, func() {
+		reserved = 0
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return reserved, nil
+}
+
 // QueryMatches searches against all known state updates for any that match the
 // passed breachHints. More than one Match will be returned for a given hint if
 // they exist in the database.
@@ -533,6 +783,40 @@ func getSession(sessions kvdb.RBucket, id []byte) (*SessionInfo, error) {
 	return &session, nil
 }
 
+// getReservedBytes returns the number of bytes reserved for the encrypted
+// blobs of all sessions. Databases that were created before the counter was
+// stored don't have it yet, in which case it is computed from the sessions.
+func getReservedBytes(metadata, sessions kvdb.RBucket) (uint64, error) {
+	reservedBytes := metadata.Get(reservedBytesKey)
+	if reservedBytes != nil {
+		return byteOrder.Uint64(reservedBytes), nil
+	}
+
+	var stats StorageStats
+	err := sessions.ForEach(func(id, _ []byte) error {
+		session, err := getSession(sessions, id)
+		if err != nil {
+			return err
+		}
+
+		return stats.AddSession(session)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return stats.ReservedBlobBytes, nil
+}
+
+// putReservedBytes stores the number of bytes reserved for the encrypted blobs
+// of all sessions.
+func putReservedBytes(metadata kvdb.RwBucket, reserved uint64) error {
+	var reservedBytes [8]byte
+	byteOrder.PutUint64(reservedBytes[:], reserved)
+
+	return metadata.Put(reservedBytesKey, reservedBytes[:])
+}
+
 // putSession stores the session info in the sessions bucket identified by its
 // session id. An error is returned if a serialization error occurs.
 func putSession(sessions kvdb.RwBucket, session *SessionInfo) error {
@@ -50,7 +50,7 @@ func newTowerDBHarness(t *testing.T, init dbInit) *towerDBHarness {
 func (h *towerDBHarness) insertSession(s *wtdb.SessionInfo, expErr error) {
 	h.t.Helper()
 
-	err := h.db.InsertSessionInfo(s)
+	err := h.db.InsertSessionInfoWithQuota(s, 0)
 	require.ErrorIs(h.t, err, expErr)
 }
 
@@ -159,6 +159,174 @@ func testInsertSession(h *towerDBHarness) {
 	h.insertSession(session, wtdb.ErrSessionAlreadyExists)
 }
 
+// testStorageStats asserts that the storage stats account for the stored
+// sessions and their updates.
+func testStorageStats(h *towerDBHarness) {
+	stats, err := h.db.StorageStats()
+	require.NoError(h.t, err)
+	require.Equal(h.t, &wtdb.StorageStats{}, stats)
+
+	policy := wtpolicy.Policy{
+		TxPolicy: wtpolicy.TxPolicy{
+			BlobType:     blob.TypeAltruistCommit,
+			SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+		},
+		MaxUpdates: 10,
+	}
+	blobSize := uint64(len(testBlob))
+	for i := 0; i < 2; i++ {
+		h.insertSession(&wtdb.SessionInfo{
+			ID:     *id(i),
+			Policy: policy,
+		}, nil)
+	}
+
+	for seqNum := 1; seqNum <= 3; seqNum++ {
+		h.insertUpdate(&wtdb.SessionStateUpdate{
+			ID:            *id(0),
+			SeqNum:        uint16(seqNum),
+			Hint:          blob.BreachHint{byte(seqNum)},
+			EncryptedBlob: testBlob,
+		}, nil)
+	}
+
+	stats, err = h.db.StorageStats()
+	require.NoError(h.t, err)
+	require.Equal(h.t, &wtdb.StorageStats{
+		NumSessions:       2,
+		NumUpdates:        3,
+		BlobBytes:         3 * blobSize,
+		ReservedBlobBytes: 20 * blobSize,
+	}, stats)
+
+	// Deleting a session releases its storage.
+	h.deleteSession(*id(0), nil)
+
+	stats, err = h.db.StorageStats()
+	require.NoError(h.t, err)
+	require.Equal(h.t, &wtdb.StorageStats{
+		NumSessions:       1,
+		ReservedBlobBytes: 10 * blobSize,
+	}, stats)
+}
+
+// testStorageQuota asserts that sessions are only inserted as long as the
+// storage reserved for all sessions stays within the quota, and that the
+// reservations of replaced and deleted sessions are released.
+func testStorageQuota(h *towerDBHarness) {
+	policy := wtpolicy.Policy{
+		TxPolicy: wtpolicy.TxPolicy{
+			BlobType:     blob.TypeAltruistCommit,
+			SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+		},
+		MaxUpdates: 10,
+	}
+	blobSize := uint64(len(testBlob))
+	quota := 25 * blobSize
+
+	insert := func(i int, maxUpdates uint16, expErr error) {
+		h.t.Helper()
+
+		session := &wtdb.SessionInfo{
+			ID:     *id(i),
+			Policy: policy,
+		}
+		session.Policy.MaxUpdates = maxUpdates
+
+		err := h.db.InsertSessionInfoWithQuota(session, quota)
+		require.ErrorIs(h.t, err, expErr)
+	}
+
+	assertReserved := func(expReserved uint64) {
+		h.t.Helper()
+
+		reserved, err := h.db.ReservedBytes()
+		require.NoError(h.t, err)
+		require.Equal(h.t, expReserved, reserved)
+	}
+
+	assertReserved(0)
+
+	// Two sessions fit into the quota, a third one doesn't.
+	insert(0, 10, nil)
+	insert(1, 10, nil)
+	insert(2, 10, wtdb.ErrStorageQuotaExceeded)
+	assertReserved(20 * blobSize)
+
+	// Replacing an unused session only reserves the difference.
+	insert(1, 15, nil)
+	assertReserved(25 * blobSize)
+
+	// Deleting a session releases its reservation, so the third session
+	// fits now.
+	h.deleteSession(*id(0), nil)
+	assertReserved(15 * blobSize)
+
+	insert(2, 10, nil)
+	assertReserved(25 * blobSize)
+}
+
+// testJusticeReports asserts that the justice reports of a session can be
+// inserted and fetched, and that they are removed along with the session.
+func testJusticeReports(h *towerDBHarness) {
+	report := func(seqNum uint16) *wtdb.JusticeReport {
+		return &wtdb.JusticeReport{
+			SeqNum:      seqNum,
+			BreachTxID:  [32]byte{byte(seqNum)},
+			JusticeTxID: [32]byte{byte(seqNum), 1},
+		}
+	}
+
+	// Reports can't be inserted or fetched for unknown sessions.
+	err := h.db.InsertJusticeReport(*id(0), report(1))
+	require.ErrorIs(h.t, err, wtdb.ErrSessionNotFound)
+
+	_, err = h.db.FetchJusticeReports(*id(0))
+	require.ErrorIs(h.t, err, wtdb.ErrSessionNotFound)
+
+	for i := 0; i < 2; i++ {
+		h.insertSession(&wtdb.SessionInfo{
+			ID: *id(i),
+			Policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType: blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.
+						DefaultSweepFeeRate,
+				},
+				MaxUpdates: 10,
+			},
+			RewardAddress: []byte{},
+		}, nil)
+	}
+
+	// A new session has no reports.
+	reports, err := h.db.FetchJusticeReports(*id(0))
+	require.NoError(h.t, err)
+	require.Empty(h.t, reports)
+
+	// The reports are returned in the order of their sequence numbers,
+	// and only for their own session.
+	require.NoError(h.t, h.db.InsertJusticeReport(*id(0), report(5)))
+	require.NoError(h.t, h.db.InsertJusticeReport(*id(0), report(2)))
+	require.NoError(h.t, h.db.InsertJusticeReport(*id(1), report(3)))
+
+	reports, err = h.db.FetchJusticeReports(*id(0))
+	require.NoError(h.t, err)
+	require.Equal(
+		h.t, []wtdb.JusticeReport{*report(2), *report(5)}, reports,
+	)
+
+	// Deleting a session removes its reports, but not those of others.
+	h.deleteSession(*id(0), nil)
+
+	_, err = h.db.FetchJusticeReports(*id(0))
+	require.ErrorIs(h.t, err, wtdb.ErrSessionNotFound)
+
+	reports, err = h.db.FetchJusticeReports(*id(1))
+	require.NoError(h.t, err)
+	require.Equal(h.t, []wtdb.JusticeReport{*report(3)}, reports)
+}
+
 // testMultipleMatches asserts that if multiple sessions insert state updates
 // with the same breach hint that all will be returned from QueryMatches.
 func testMultipleMatches(h *towerDBHarness) {
@@ -636,6 +804,22 @@ func TestTowerDB(t *testing.T) {
 				return db
 			},
 		},
+		{
+			name: "sharded boltdb",
+			init: func(t *testing.T) watchtower.DB {
+				db, err := openTestShards(
+					t, t.TempDir(), t.TempDir(),
+					t.TempDir(),
+				)
+				require.NoError(t, err)
+
+				t.Cleanup(func() {
+					db.Close()
+				})
+
+				return db
+			},
+		},
 		{
 			name: "mock",
 			init: func(t *testing.T) watchtower.DB {
@@ -704,6 +888,18 @@ func TestTowerDB(t *testing.T) {
 			name: "lookout tip",
 			run:  testLookoutTip,
 		},
+		{
+			name: "storage stats",
+			run:  testStorageStats,
+		},
+		{
+			name: "storage quota",
+			run:  testStorageQuota,
+		},
+		{
+			name: "justice reports",
+			run:  testJusticeReports,
+		},
 	}
 
 	for _, database := range dbs {
@@ -1,6 +1,7 @@
 package wtmock
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/lightningnetwork/lnd/chainntnfs"
@@ -14,6 +15,8 @@ type TowerDB struct {
 	lastEpoch *chainntnfs.BlockEpoch
 	sessions  map[wtdb.SessionID]*wtdb.SessionInfo
 	blobs     map[blob.BreachHint]map[wtdb.SessionID]*wtdb.SessionStateUpdate
+
+	justiceReports map[wtdb.SessionID]map[uint16]wtdb.JusticeReport
 }
 
 // NewTowerDB initializes a fresh mock TowerDB.
@@ -21,6 +24,9 @@ func NewTowerDB() *TowerDB {
 	return &TowerDB{
 		sessions: make(map[wtdb.SessionID]*wtdb.SessionInfo),
 		blobs:    make(map[blob.BreachHint]map[wtdb.SessionID]*wtdb.SessionStateUpdate),
+		justiceReports: make(
+			map[wtdb.SessionID]map[uint16]wtdb.JusticeReport,
+		),
 	}
 }
 
@@ -83,6 +89,15 @@ func (db *TowerDB) GetSessionInfo(id *wtdb.SessionID) (*wtdb.SessionInfo, error)
 // InsertSessionInfo records a negotiated session in the tower database. An
 // error is returned if the session already exists.
 func (db *TowerDB) InsertSessionInfo(info *wtdb.SessionInfo) error {
+	return db.InsertSessionInfoWithQuota(info, 0)
+}
+
+// InsertSessionInfoWithQuota records a negotiated session in the tower
+// database if the bytes reserved for the encrypted blobs of all sessions don't
+// exceed maxReserved afterwards. A maxReserved of zero means no limit.
+func (db *TowerDB) InsertSessionInfoWithQuota(info *wtdb.SessionInfo,
+	maxReserved uint64) error {
+
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
@@ -96,6 +111,30 @@ func (db *TowerDB) InsertSessionInfo(info *wtdb.SessionInfo) error {
 		return err
 	}
 
+	if maxReserved != 0 {
+		var reserved uint64
+		for id, session := range db.sessions {
+			if id == info.ID {
+				continue
+			}
+
+			sessionReserved, err := session.ReservedBytes()
+			if err != nil {
+				return err
+			}
+			reserved += sessionReserved
+		}
+
+		infoReserved, err := info.ReservedBytes()
+		if err != nil {
+			return err
+		}
+
+		if reserved+infoReserved > maxReserved {
+			return wtdb.ErrStorageQuotaExceeded
+		}
+	}
+
 	db.sessions[info.ID] = info
 
 	return nil
@@ -112,8 +151,9 @@ func (db *TowerDB) DeleteSession(target wtdb.SessionID) error {
 		return wtdb.ErrSessionNotFound
 	}
 
-	// Remove the target session.
+	// Remove the target session and its justice reports.
 	delete(db.sessions, target)
+	delete(db.justiceReports, target)
 
 	// Remove the state updates for any blobs stored under the target
 	// session identifier.
@@ -130,6 +170,80 @@ func (db *TowerDB) DeleteSession(target wtdb.SessionID) error {
 	return nil
 }
 
+// InsertJusticeReport records that the tower published a justice transaction
+// for a state update of the given session. A report for the same state update
+// replaces the existing one.
+func (db *TowerDB) InsertJusticeReport(id wtdb.SessionID,
+	report *wtdb.JusticeReport) error {
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.sessions[id]; !ok {
+		return wtdb.ErrSessionNotFound
+	}
+
+	reports, ok := db.justiceReports[id]
+	if !ok {
+		reports = make(map[uint16]wtdb.JusticeReport)
+		db.justiceReports[id] = reports
+	}
+	reports[report.SeqNum] = *report
+
+	return nil
+}
+
+// FetchJusticeReports returns the justice transactions the tower published
+// for the state updates of the given session, in the order of their sequence
+// numbers.
+func (db *TowerDB) FetchJusticeReports(
+	id wtdb.SessionID) ([]wtdb.JusticeReport, error) {
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.sessions[id]; !ok {
+		return nil, wtdb.ErrSessionNotFound
+	}
+
+	var reports []wtdb.JusticeReport
+	for _, report := range db.justiceReports[id] {
+		reports = append(reports, report)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].SeqNum < reports[j].SeqNum
+	})
+
+	return reports, nil
+}
+
+// StorageStats returns a summary of the storage used by the sessions of the
+// tower's clients.
+func (db *TowerDB) StorageStats() (*wtdb.StorageStats, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	stats := &wtdb.StorageStats{}
+	for _, info := range db.sessions {
+		if err := stats.AddSession(info); err != nil {
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+// ReservedBytes returns the number of bytes reserved for the encrypted blobs
+// of all sessions.
+func (db *TowerDB) ReservedBytes() (uint64, error) {
+	stats, err := db.StorageStats()
+	if err != nil {
+		return 0, err
+	}
+
+	return stats.ReservedBlobBytes, nil
+}
+
 // QueryMatches searches against all known state updates for any that match the
 // passed breachHints. More than one Match will be returned for a given hint if
 // they exist in the database.
@@ -1,6 +1,9 @@
 package wtserver
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
@@ -65,11 +68,66 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 		)
 	}
 
+	// Reject sessions that exceed the per session quota of updates.
+	maxUpdates := s.cfg.MaxSessionUpdates
+	if maxUpdates != 0 && req.MaxUpdates > maxUpdates {
+		log.Debugf("Rejecting CreateSession from %s, max updates %d "+
+			"exceed limit of %d", id, req.MaxUpdates, maxUpdates)
+		return s.replyCreateSession(
+			peer, id, wtwire.CreateSessionCodeRejectMaxUpdates, 0,
+			nil,
+		)
+	}
+
+	// Assemble the session info using the agreed upon parameters and
+	// session id. The reward address is added once the session passed all
+	// checks.
+	info := wtdb.SessionInfo{
+		ID: *id,
+		Policy: wtpolicy.Policy{
+			TxPolicy: wtpolicy.TxPolicy{
+				BlobType:     req.BlobType,
+				RewardBase:   req.RewardBase,
+				RewardRate:   req.RewardRate,
+				SweepFeeRate: req.SweepFeeRate,
+			},
+			MaxUpdates: req.MaxUpdates,
+		},
+	}
+
+	// Make sure the tower has room to store all updates of the session
+	// before it is authorized. As the blob type is supported, its blob
+	// size is known. The quota is enforced again when the session is
+	// inserted, as other sessions may be created in the meantime.
+	if s.cfg.MaxStorage != 0 {
+		code, err := s.checkStorageQuota(existingInfo, &info)
+		if err != nil {
+			log.Debugf("Rejecting CreateSession from %s: %v", id,
+				err)
+			return s.replyCreateSession(peer, id, code, 0, nil)
+		}
+	}
+
+	// Finally, the operator may require the session to be authorized,
+	// e.g. by a payment.
+	if s.cfg.AuthorizeSession != nil {
+		err := s.cfg.AuthorizeSession(id, &info.Policy)
+		if err != nil {
+			log.Debugf("Rejecting CreateSession from %s, session "+
+				"not authorized: %v", id, err)
+			return s.replyCreateSession(
+				peer, id,
+				wtwire.CreateSessionCodeRejectUnauthorized, 0,
+				nil,
+			)
+		}
+	}
+
 	// Now that we've established that this session does not exist in the
-	// database, retrieve the sweep address that will be given to the
-	// client. This address is to be included by the client when signing
-	// sweep transactions destined for this tower, if its negotiated output
-	// is not dust.
+	// database and can be accepted, retrieve the sweep address that will
+	// be given to the client. This address is to be included by the client
+	// when signing sweep transactions destined for this tower, if its
+	// negotiated output is not dust.
 	var rewardScript []byte
 	if req.BlobType.Has(blob.FlagReward) {
 		rewardAddress, err := s.cfg.NewAddress()
@@ -93,28 +151,21 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 		}
 	}
 
-	// TODO(conner): create invoice for upfront payment
-
-	// Assemble the session info using the agreed upon parameters, reward
-	// address, and session id.
-	info := wtdb.SessionInfo{
-		ID: *id,
-		Policy: wtpolicy.Policy{
-			TxPolicy: wtpolicy.TxPolicy{
-				BlobType:     req.BlobType,
-				RewardBase:   req.RewardBase,
-				RewardRate:   req.RewardRate,
-				SweepFeeRate: req.SweepFeeRate,
-			},
-			MaxUpdates: req.MaxUpdates,
-		},
-		RewardAddress: rewardScript,
-	}
+	info.RewardAddress = rewardScript
 
 	// Insert the session info into the watchtower's database. If
-	// successful, the session will now be ready for use.
-	err = s.cfg.DB.InsertSessionInfo(&info)
-	if err != nil {
+	// successful, the session will now be ready for use. The storage of
+	// the session is reserved in the same transaction, so concurrent
+	// sessions can't exceed the storage quota together.
+	err = s.cfg.DB.InsertSessionInfoWithQuota(&info, s.cfg.MaxStorage)
+	switch {
+	case errors.Is(err, wtdb.ErrStorageQuotaExceeded):
+		log.Debugf("Rejecting CreateSession from %s: %v", id, err)
+		return s.replyCreateSession(
+			peer, id, wtwire.CodeTemporaryFailure, 0, nil,
+		)
+
+	case err != nil:
 		log.Errorf("Unable to create session for %s: %v", id, err)
 		return s.replyCreateSession(
 			peer, id, wtwire.CodeTemporaryFailure, 0, nil,
@@ -128,6 +179,42 @@ func (s *Server) handleCreateSession(peer Peer, id *wtdb.SessionID,
 	)
 }
 
+// checkStorageQuota checks that reserving the storage for all updates of the
+// new session doesn't exceed the tower's storage quota. If the session
+// replaces an unused session, the storage of the latter is released. On
+// failure, the code to reply with is returned.
+func (s *Server) checkStorageQuota(existingInfo,
+	info *wtdb.SessionInfo) (wtwire.ErrorCode, error) {
+
+	reserved, err := s.cfg.DB.ReservedBytes()
+	if err != nil {
+		return wtwire.CodeTemporaryFailure, err
+	}
+
+	if existingInfo != nil {
+		existingReserved, err := existingInfo.ReservedBytes()
+		if err != nil {
+			return wtwire.CodeTemporaryFailure, err
+		}
+		reserved -= existingReserved
+	}
+
+	infoReserved, err := info.ReservedBytes()
+	if err != nil {
+		return wtwire.CodeTemporaryFailure, err
+	}
+	reserved += infoReserved
+
+	// The quota may free up once sessions are deleted, so the failure is
+	// only temporary.
+	if reserved > s.cfg.MaxStorage {
+		return wtwire.CodeTemporaryFailure, fmt.Errorf("storage "+
+			"quota of %d bytes exceeded", s.cfg.MaxStorage)
+	}
+
+	return wtwire.CodeOK, nil
+}
+
 // replyCreateSession sends a response to a CreateSession from a client. If the
 // status code in the reply is OK, the error from the write will be bubbled up.
 // Otherwise, this method returns a connection error to ensure we don't continue
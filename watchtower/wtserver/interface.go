@@ -7,6 +7,7 @@ import (
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 )
 
 // Interface represents a simple, listen-only service that accepts watchtower
@@ -50,10 +51,17 @@ type Peer interface {
 // DB provides the server access to session creation and retrieval, as well as
 // persisting state updates sent by clients.
 type DB interface {
-	// InsertSessionInfo saves a newly agreed-upon session from a client.
-	// This method should fail if a session with the same session id already
-	// exists.
-	InsertSessionInfo(*wtdb.SessionInfo) error
+	// InsertSessionInfoWithQuota saves a newly agreed-upon session from a
+	// client if the bytes reserved for the encrypted blobs of all sessions
+	// don't exceed the given quota afterwards, failing with
+	// wtdb.ErrStorageQuotaExceeded otherwise. A quota of zero means no
+	// limit. This method should fail if a session with the same session id
+	// already exists.
+	InsertSessionInfoWithQuota(*wtdb.SessionInfo, uint64) error
+
+	// ReservedBytes returns the number of bytes reserved for the encrypted
+	// blobs of all sessions.
+	ReservedBytes() (uint64, error)
 
 	// GetSessionInfo retrieves the SessionInfo associated with the session
 	// id, if it exists.
@@ -67,4 +75,18 @@ type DB interface {
 	// DeleteSession removes all data associated with a particular session
 	// id from the tower's database.
 	DeleteSession(wtdb.SessionID) error
+
+	// StorageStats returns a summary of the storage used by the sessions
+	// of the tower's clients.
+	StorageStats() (*wtdb.StorageStats, error)
+
+	// FetchJusticeReports returns the justice transactions the tower
+	// published for the state updates of the given session.
+	FetchJusticeReports(wtdb.SessionID) ([]wtdb.JusticeReport, error)
 }
+
+// SessionAuthorizer decides whether a client may create the session with the
+// given id and policy. Returning an error rejects the session. This allows a
+// tower operator to only accept sessions that were paid for, e.g. by checking
+// that an invoice tied to the session id was settled.
+type SessionAuthorizer func(id *wtdb.SessionID, policy *wtpolicy.Policy) error
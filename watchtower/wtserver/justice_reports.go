@@ -0,0 +1,67 @@
+package wtserver
+
+import (
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+)
+
+// handleGetJusticeReports processes a GetJusticeReports request for a client
+// with given SessionID, replying with the justice transactions the tower
+// published for the session. The id is assumed to have been previously
+// authenticated by the brontide connection.
+func (s *Server) handleGetJusticeReports(peer Peer, id *wtdb.SessionID) error {
+	var (
+		failCode wtwire.ErrorCode
+		reports  []wtwire.JusticeReport
+	)
+
+	dbReports, err := s.cfg.DB.FetchJusticeReports(*id)
+	switch {
+	case err == nil:
+		failCode = wtwire.CodeOK
+
+		// Only the first reports that fit in a single message are
+		// returned, a session with that many breaches is faulty
+		// anyway.
+		if len(dbReports) > wtwire.MaxJusticeReports {
+			dbReports = dbReports[:wtwire.MaxJusticeReports]
+		}
+
+		reports = make([]wtwire.JusticeReport, 0, len(dbReports))
+		for _, report := range dbReports {
+			reports = append(reports, wtwire.JusticeReport{
+				SeqNum:      report.SeqNum,
+				BreachTxID:  report.BreachTxID,
+				JusticeTxID: report.JusticeTxID,
+			})
+		}
+
+	case err == wtdb.ErrSessionNotFound:
+		failCode = wtwire.CodePermanentFailure
+
+	default:
+		failCode = wtwire.CodeTemporaryFailure
+	}
+
+	msg := &wtwire.JusticeReports{
+		Code:    failCode,
+		Reports: reports,
+	}
+
+	err = s.sendMessage(peer, msg)
+	if err != nil {
+		log.Errorf("Unable to send JusticeReports to %s", id)
+	}
+
+	// Return the write error if the request succeeded.
+	if failCode == wtwire.CodeOK {
+		return err
+	}
+
+	// Otherwise the request failed, return a connection failure to
+	// disconnect the client.
+	return &connFailure{
+		ID:   *id,
+		Code: failCode,
+	}
+}
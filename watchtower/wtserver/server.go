@@ -64,9 +64,27 @@ type Config struct {
 	// should only be used for testing.
 	NoAckUpdates bool
 
+	// NoBatchedUpdates causes the server to not signal support for
+	// batched state updates, this should only be used for testing.
+	NoBatchedUpdates bool
+
 	// DisableReward causes the server to reject any session creation
 	// attempts that request rewards.
 	DisableReward bool
+
+	// MaxSessionUpdates is the maximum number of state updates a client
+	// may negotiate for a single session. Zero means no limit.
+	MaxSessionUpdates uint16
+
+	// MaxStorage is the maximum number of bytes of encrypted blobs the
+	// server reserves for the sessions of its clients. A new session is
+	// rejected if storing all of its updates would exceed it. Zero means
+	// no limit.
+	MaxStorage uint64
+
+	// AuthorizeSession, if set, is consulted before a new session is
+	// created, and may reject it, e.g. because it wasn't paid for.
+	AuthorizeSession SessionAuthorizer
 }
 
 // Server houses the state required to handle watchtower peers. It's primary job
@@ -91,17 +109,26 @@ type Server struct {
 	quit chan struct{}
 }
 
+// LocalFeatures returns the features the server signals in its Init message.
+func LocalFeatures() *lnwire.RawFeatureVector {
+	return lnwire.NewRawFeatureVector(
+		wtwire.AltruistSessionsOptional,
+		wtwire.AnchorCommitOptional,
+		wtwire.TaprootCommitOptional,
+		wtwire.BatchedUpdatesOptional,
+		wtwire.JusticeReportsOptional,
+	)
+}
+
 // New creates a new server to handle watchtower clients. The server will accept
 // clients connecting to the listener addresses, and allows them to open
 // sessions and send state updates.
 func New(cfg *Config) (*Server, error) {
-	localInit := wtwire.NewInitMessage(
-		lnwire.NewRawFeatureVector(
-			wtwire.AltruistSessionsOptional,
-			wtwire.AnchorCommitOptional,
-		),
-		cfg.ChainHash,
-	)
+	features := LocalFeatures()
+	if cfg.NoBatchedUpdates {
+		features.Unset(wtwire.BatchedUpdatesOptional)
+	}
+	localInit := wtwire.NewInitMessage(features, cfg.ChainHash)
 
 	s := &Server{
 		cfg:       cfg,
@@ -205,6 +232,7 @@ func (s *Server) peerHandler() {
 // client may either send:
 //   - a single CreateSession message.
 //   - a series of StateUpdate messages.
+//   - a series of StateUpdateBatch messages.
 //
 // This method uses the server's peer map to ensure at most one peer using the
 // same session id can enter the main event loop. The connection will be
@@ -284,6 +312,20 @@ func (s *Server) handleClient(peer Peer) {
 				"from %s: %v", id, err)
 		}
 
+	case *wtwire.StateUpdateBatch:
+		err = s.handleStateUpdateBatches(peer, &id, msg)
+		if err != nil {
+			log.Errorf("Unable to handle StateUpdateBatch "+
+				"from %s: %v", id, err)
+		}
+
+	case *wtwire.GetJusticeReports:
+		err = s.handleGetJusticeReports(peer, &id)
+		if err != nil {
+			log.Errorf("Unable to handle GetJusticeReports "+
+				"from %s: %v", id, err)
+		}
+
 	default:
 		log.Errorf("Received unsupported message type: %T "+
 			"from %s", nextMsg, id)
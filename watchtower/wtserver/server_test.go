@@ -2,6 +2,7 @@ package wtserver_test
 
 import (
 	"bytes"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 	"github.com/lightningnetwork/lnd/watchtower/wtmock"
+	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 	"github.com/lightningnetwork/lnd/watchtower/wtserver"
 	"github.com/lightningnetwork/lnd/watchtower/wtwire"
 	"github.com/stretchr/testify/require"
@@ -162,6 +164,30 @@ type createSessionTestCase struct {
 }
 
 var createSessionTests = []createSessionTestCase{
+	{
+		name: "duplicate session create altruist taproot commit",
+		initMsg: wtwire.NewInitMessage(
+			lnwire.NewRawFeatureVector(
+				wtwire.TaprootCommitRequired,
+			),
+			testnetChainHash,
+		),
+		createMsg: &wtwire.CreateSession{
+			BlobType:     blob.TypeAltruistTaprootCommit,
+			MaxUpdates:   1000,
+			RewardBase:   0,
+			RewardRate:   0,
+			SweepFeeRate: 10000,
+		},
+		expReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CodeOK,
+			Data: []byte{},
+		},
+		expDupReply: &wtwire.CreateSessionReply{
+			Code: wtwire.CodeOK,
+			Data: []byte{},
+		},
+	},
 	{
 		name: "duplicate session create altruist anchor commit",
 		initMsg: wtwire.NewInitMessage(
@@ -622,6 +648,82 @@ var stateUpdateTests = []stateUpdateTestCase{
 	},
 }
 
+// TestServerSessionQuotas asserts that the server rejects sessions that exceed
+// its quotas or that weren't authorized by the operator.
+func TestServerSessionQuotas(t *testing.T) {
+	t.Parallel()
+
+	const timeoutDuration = 500 * time.Millisecond
+
+	unauthorizedPub := randPubKey(t)
+	unauthorizedID := wtdb.NewSessionIDFromPubKey(unauthorizedPub)
+	authorize := func(id *wtdb.SessionID, _ *wtpolicy.Policy) error {
+		if *id == unauthorizedID {
+			return errors.New("session not paid for")
+		}
+
+		return nil
+	}
+
+	// The storage quota leaves room for a single session of 1000 updates.
+	s, err := wtserver.New(&wtserver.Config{
+		DB:           wtmock.NewTowerDB(),
+		ReadTimeout:  timeoutDuration,
+		WriteTimeout: timeoutDuration,
+		NewAddress: func() (btcutil.Address, error) {
+			return addr, nil
+		},
+		ChainHash:         testnetChainHash,
+		MaxSessionUpdates: 1000,
+		MaxStorage:        1500 * uint64(len(testBlob)),
+		AuthorizeSession:  authorize,
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.Start())
+	t.Cleanup(func() {
+		require.NoError(t, s.Stop())
+	})
+
+	initMsg := wtwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(), testnetChainHash,
+	)
+	createSession := func(peerPub *btcec.PublicKey,
+		maxUpdates uint16) wtwire.ErrorCode {
+
+		peer := wtmock.NewMockPeer(randPubKey(t), peerPub, nil, 0)
+		connect(t, s, peer, initMsg, timeoutDuration)
+
+		sendMsg(t, &wtwire.CreateSession{
+			BlobType:     blob.TypeAltruistCommit,
+			MaxUpdates:   maxUpdates,
+			SweepFeeRate: 10000,
+		}, peer, timeoutDuration)
+
+		reply := recvReply(
+			t, "MsgCreateSessionReply", peer, timeoutDuration,
+		).(*wtwire.CreateSessionReply)
+		assertConnClosed(t, peer, 2*timeoutDuration)
+
+		return reply.Code
+	}
+
+	// A session with more updates than allowed is rejected.
+	code := createSession(randPubKey(t), 1001)
+	require.Equal(t, wtwire.CreateSessionCodeRejectMaxUpdates, code)
+
+	// So is a session that the operator didn't authorize.
+	code = createSession(unauthorizedPub, 1000)
+	require.Equal(t, wtwire.CreateSessionCodeRejectUnauthorized, code)
+
+	// The first session fits into the storage quota, but there's no room
+	// left for the second one.
+	code = createSession(randPubKey(t), 1000)
+	require.Equal(t, wtwire.CodeOK, code)
+
+	code = createSession(randPubKey(t), 1000)
+	require.Equal(t, wtwire.CodeTemporaryFailure, code)
+}
+
 // TestServerStateUpdates tests the behavior of the server in response to
 // watchtower clients sending StateUpdate messages, after having already
 // established an open session. The test asserts that the server responds
@@ -703,6 +805,200 @@ func testServerStateUpdates(t *testing.T, test stateUpdateTestCase) {
 	assertConnClosed(t, peer, 2*timeoutDuration)
 }
 
+// batchedUpdates returns the state updates with the given sequence numbers as
+// they are sent within a StateUpdateBatch.
+func batchedUpdates(seqNums ...uint16) []wtwire.BatchedStateUpdate {
+	updates := make([]wtwire.BatchedStateUpdate, 0, len(seqNums))
+	for _, seqNum := range seqNums {
+		updates = append(updates, wtwire.BatchedStateUpdate{
+			SeqNum:        seqNum,
+			EncryptedBlob: testBlob,
+		})
+	}
+
+	return updates
+}
+
+type stateUpdateBatchTestCase struct {
+	name       string
+	maxUpdates uint16
+	batches    []*wtwire.StateUpdateBatch
+	replies    []*wtwire.StateUpdateBatchReply
+}
+
+var stateUpdateBatchTests = []stateUpdateBatchTestCase{
+	// A whole batch is acked with a single reply.
+	{
+		name:       "batch accepted",
+		maxUpdates: 4,
+		batches: []*wtwire.StateUpdateBatch{
+			{
+				LastApplied: 0,
+				IsComplete:  1,
+				Updates:     batchedUpdates(1, 2, 3),
+			},
+		},
+		replies: []*wtwire.StateUpdateBatchReply{
+			{Code: wtwire.CodeOK, LastApplied: 3},
+		},
+	},
+	// Several batches can be streamed over the same connection.
+	{
+		name:       "streamed batches",
+		maxUpdates: 4,
+		batches: []*wtwire.StateUpdateBatch{
+			{
+				LastApplied: 0,
+				Updates:     batchedUpdates(1, 2),
+			},
+			{
+				LastApplied: 2,
+				IsComplete:  1,
+				Updates:     batchedUpdates(3, 4),
+			},
+		},
+		replies: []*wtwire.StateUpdateBatchReply{
+			{Code: wtwire.CodeOK, LastApplied: 2},
+			{Code: wtwire.CodeOK, LastApplied: 4},
+		},
+	},
+	// The updates preceding a rejected update are still applied, and the
+	// client can continue after them.
+	{
+		name:       "partially applied batch",
+		maxUpdates: 4,
+		batches: []*wtwire.StateUpdateBatch{
+			{
+				LastApplied: 0,
+				Updates:     batchedUpdates(1, 2, 4),
+			},
+			nil,
+			{
+				LastApplied: 2,
+				IsComplete:  1,
+				Updates:     batchedUpdates(3, 4),
+			},
+		},
+		replies: []*wtwire.StateUpdateBatchReply{
+			{
+				Code:        wtwire.StateUpdateCodeSeqNumOutOfOrder,
+				LastApplied: 2,
+			},
+			nil,
+			{Code: wtwire.CodeOK, LastApplied: 4},
+		},
+	},
+	// Updates that were already applied are skipped, such that a client
+	// can resend a batch for which it didn't receive the reply.
+	{
+		name:       "resend applied batch",
+		maxUpdates: 4,
+		batches: []*wtwire.StateUpdateBatch{
+			{
+				LastApplied: 0,
+				Updates:     batchedUpdates(1, 2),
+			},
+			{
+				LastApplied: 0,
+				IsComplete:  1,
+				Updates:     batchedUpdates(1, 2, 3),
+			},
+		},
+		replies: []*wtwire.StateUpdateBatchReply{
+			{Code: wtwire.CodeOK, LastApplied: 2},
+			{Code: wtwire.CodeOK, LastApplied: 3},
+		},
+	},
+	// Updates beyond the session's capacity are rejected.
+	{
+		name:       "max updates exceeded",
+		maxUpdates: 2,
+		batches: []*wtwire.StateUpdateBatch{
+			{
+				LastApplied: 0,
+				Updates:     batchedUpdates(1, 2, 3),
+			},
+		},
+		replies: []*wtwire.StateUpdateBatchReply{
+			{
+				Code: wtwire.
+					StateUpdateCodeMaxUpdatesExceeded,
+				LastApplied: 2,
+			},
+		},
+	},
+}
+
+// TestServerStateUpdateBatches tests the server's handling of StateUpdateBatch
+// messages, asserting that each batch is answered with a single reply that
+// acks the updates that were applied.
+func TestServerStateUpdateBatches(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range stateUpdateBatchTests {
+		t.Run(test.name, func(t *testing.T) {
+			testServerStateUpdateBatches(t, test)
+		})
+	}
+}
+
+func testServerStateUpdateBatches(t *testing.T,
+	test stateUpdateBatchTestCase) {
+
+	const timeoutDuration = 100 * time.Millisecond
+
+	s := initServer(t, nil, timeoutDuration)
+
+	initMsg := wtwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(wtwire.BatchedUpdatesRequired),
+		testnetChainHash,
+	)
+
+	// Create a new client and register a session.
+	localPub := randPubKey(t)
+	peerPub := randPubKey(t)
+	peer := wtmock.NewMockPeer(localPub, peerPub, nil, 0)
+	connect(t, s, peer, initMsg, timeoutDuration)
+
+	sendMsg(t, &wtwire.CreateSession{
+		BlobType:     blob.TypeAltruistCommit,
+		MaxUpdates:   test.maxUpdates,
+		SweepFeeRate: 10000,
+	}, peer, timeoutDuration)
+	createReply := recvReply(
+		t, "MsgCreateSessionReply", peer, timeoutDuration,
+	).(*wtwire.CreateSessionReply)
+	require.Equal(t, wtwire.CodeOK, createReply.Code)
+
+	assertConnClosed(t, peer, 2*timeoutDuration)
+
+	peer = wtmock.NewMockPeer(localPub, peerPub, nil, 0)
+	connect(t, s, peer, initMsg, timeoutDuration)
+
+	for i, batch := range test.batches {
+		// A nil batch signals that we should wait for the prior
+		// connection to die, before reconnecting with the same session
+		// identifier.
+		if batch == nil {
+			assertConnClosed(t, peer, 2*timeoutDuration)
+
+			peer = wtmock.NewMockPeer(localPub, peerPub, nil, 0)
+			connect(t, s, peer, initMsg, timeoutDuration)
+
+			continue
+		}
+
+		sendMsg(t, batch, peer, timeoutDuration)
+		reply := recvReply(
+			t, "MsgStateUpdateBatchReply", peer, timeoutDuration,
+		).(*wtwire.StateUpdateBatchReply)
+		require.Equal(t, test.replies[i], reply, "batch %d", i)
+	}
+
+	// Check that the final connection is properly cleaned up by the server.
+	assertConnClosed(t, peer, 2*timeoutDuration)
+}
+
 // TestServerDeleteSession asserts the response to a DeleteSession request, and
 // checking that the proper error is returned when the session doesn't exist and
 // that a successful deletion does not disrupt other sessions.
@@ -832,6 +1128,79 @@ func TestServerDeleteSession(t *testing.T) {
 	}
 }
 
+// TestServerJusticeReports asserts that a client can fetch the justice
+// transactions the tower published for its session, and that the request fails
+// for unknown sessions.
+func TestServerJusticeReports(t *testing.T) {
+	t.Parallel()
+
+	const timeoutDuration = 100 * time.Millisecond
+
+	db := wtmock.NewTowerDB()
+	s := initServer(t, db, timeoutDuration)
+
+	localPub := randPubKey(t)
+	peerPub := randPubKey(t)
+	id := wtdb.NewSessionIDFromPubKey(peerPub)
+
+	initMsg := wtwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(wtwire.JusticeReportsRequired),
+		testnetChainHash,
+	)
+
+	// getReports requests the reports of the peer's session on a new
+	// connection.
+	getReports := func() *wtwire.JusticeReports {
+		t.Helper()
+
+		peer := wtmock.NewMockPeer(localPub, peerPub, nil, 0)
+		connect(t, s, peer, initMsg, timeoutDuration)
+		sendMsg(t, &wtwire.GetJusticeReports{}, peer, timeoutDuration)
+		reply := recvReply(
+			t, "MsgJusticeReports", peer, timeoutDuration,
+		).(*wtwire.JusticeReports)
+		assertConnClosed(t, peer, 2*timeoutDuration)
+
+		return reply
+	}
+
+	// The session doesn't exist yet, so the request fails.
+	reply := getReports()
+	require.Equal(t, wtwire.CodePermanentFailure, reply.Code)
+	require.Empty(t, reply.Reports)
+
+	peer := wtmock.NewMockPeer(localPub, peerPub, nil, 0)
+	connect(t, s, peer, initMsg, timeoutDuration)
+	sendMsg(t, &wtwire.CreateSession{
+		BlobType:     blob.TypeAltruistCommit,
+		MaxUpdates:   1000,
+		SweepFeeRate: 10000,
+	}, peer, timeoutDuration)
+	recvReply(t, "MsgCreateSessionReply", peer, timeoutDuration)
+	assertConnClosed(t, peer, 2*timeoutDuration)
+
+	// No justice transactions were published for the new session.
+	reply = getReports()
+	require.Equal(t, wtwire.CodeOK, reply.Code)
+	require.Empty(t, reply.Reports)
+
+	// Once the tower recorded a justice transaction, it is reported.
+	report := &wtdb.JusticeReport{
+		SeqNum:      3,
+		BreachTxID:  [32]byte{1},
+		JusticeTxID: [32]byte{2},
+	}
+	require.NoError(t, db.InsertJusticeReport(id, report))
+
+	reply = getReports()
+	require.Equal(t, wtwire.CodeOK, reply.Code)
+	require.Equal(t, []wtwire.JusticeReport{{
+		SeqNum:      report.SeqNum,
+		BreachTxID:  report.BreachTxID,
+		JusticeTxID: report.JusticeTxID,
+	}}, reply.Reports)
+}
+
 func connect(t *testing.T, s wtserver.Interface, peer *wtmock.MockPeer,
 	initMsg *wtwire.Init, timeout time.Duration) {
 
@@ -903,11 +1272,21 @@ func recvReply(t *testing.T, name string, peer *wtmock.MockPeer,
 			t.Fatalf("expected %s reply message, "+
 				"got %T", name, msg)
 		}
+	case "MsgStateUpdateBatchReply":
+		if _, ok := msg.(*wtwire.StateUpdateBatchReply); !ok {
+			t.Fatalf("expected %s reply message, "+
+				"got %T", name, msg)
+		}
 	case "MsgDeleteSessionReply":
 		if _, ok := msg.(*wtwire.DeleteSessionReply); !ok {
 			t.Fatalf("expected %s reply message, "+
 				"got %T", name, msg)
 		}
+	case "MsgJusticeReports":
+		if _, ok := msg.(*wtwire.JusticeReports); !ok {
+			t.Fatalf("expected %s reply message, "+
+				"got %T", name, msg)
+		}
 	}
 
 	return msg
@@ -67,12 +67,6 @@ func (s *Server) handleStateUpdates(peer Peer, id *wtdb.SessionID,
 func (s *Server) handleStateUpdate(peer Peer, id *wtdb.SessionID,
 	update *wtwire.StateUpdate) error {
 
-	var (
-		lastApplied uint16
-		failCode    wtwire.ErrorCode
-		err         error
-	)
-
 	sessionUpdate := wtdb.SessionStateUpdate{
 		ID:            *id,
 		Hint:          update.Hint,
@@ -81,49 +75,186 @@ func (s *Server) handleStateUpdate(peer Peer, id *wtdb.SessionID,
 		EncryptedBlob: update.EncryptedBlob,
 	}
 
-	lastApplied, err = s.cfg.DB.InsertStateUpdate(&sessionUpdate)
-	switch {
-	case err == nil:
+	lastApplied, err := s.cfg.DB.InsertStateUpdate(&sessionUpdate)
+	if err == nil {
 		log.Debugf("State update %d accepted for %s",
 			update.SeqNum, id)
+	}
+	failCode := stateUpdateCode(err)
+
+	if s.cfg.NoAckUpdates {
+		return &connFailure{
+			ID:   *id,
+			Code: failCode,
+		}
+	}
+
+	return s.replyStateUpdate(
+		peer, id, failCode, lastApplied,
+	)
+}
+
+// handleStateUpdateBatches processes a stream of StateUpdateBatch requests from
+// the client. The provided batch should be the first such batch read,
+// subsequent batches will be consumed if the peer does not signal IsComplete
+// on a particular batch.
+func (s *Server) handleStateUpdateBatches(peer Peer, id *wtdb.SessionID,
+	batch *wtwire.StateUpdateBatch) error {
+
+	var curBatch = batch
+	for {
+		// If this is not the first batch, read the next batch from the
+		// peer.
+		if curBatch == nil {
+			nextMsg, err := s.readMessage(peer)
+			if err != nil {
+				return err
+			}
+
+			var ok bool
+			curBatch, ok = nextMsg.(*wtwire.StateUpdateBatch)
+			if !ok {
+				return fmt.Errorf("client sent %T after "+
+					"StateUpdateBatch", nextMsg)
+			}
+		}
+
+		// Try to accept the updates of the batch from the client.
+		err := s.handleStateUpdateBatch(peer, id, curBatch)
+		if err != nil {
+			return err
+		}
+
+		// If the client signals that this is last batch, we can
+		// disconnect the client.
+		if curBatch.IsComplete == 1 {
+			return nil
+		}
+
+		curBatch = nil
+
+		select {
+		case <-s.quit:
+			return ErrServerExiting
+		default:
+		}
+	}
+}
+
+// handleStateUpdateBatch processes a StateUpdateBatch message request from a
+// client. The updates of the batch are inserted into the db in order, stopping
+// at the first update that is rejected. A single StateUpdateBatchReply is then
+// sent back, carrying the code of the rejected update, if any, and the
+// sequence number of the last update that was accepted.
+func (s *Server) handleStateUpdateBatch(peer Peer, id *wtdb.SessionID,
+	batch *wtwire.StateUpdateBatch) error {
+
+	// Start out from the last update the tower applied for this session.
+	// Updates of the batch preceding it are skipped if the client didn't
+	// learn that they were applied, which happens if the reply to a
+	// previous batch was lost, such that the client can simply resend it.
+	var (
+		lastApplied uint16
+		failCode    = wtwire.CodeOK
+	)
+	session, err := s.cfg.DB.GetSessionInfo(id)
+	if err != nil {
+		failCode = stateUpdateCode(err)
+	} else {
+		lastApplied = session.LastApplied
+	}
+	towerLastApplied := lastApplied
+
+	for _, update := range batch.Updates {
+		if failCode != wtwire.CodeOK {
+			break
+		}
+
+		if update.SeqNum < towerLastApplied &&
+			update.SeqNum > batch.LastApplied {
+
+			continue
+		}
+
+		sessionUpdate := wtdb.SessionStateUpdate{
+			ID:            *id,
+			Hint:          update.Hint,
+			SeqNum:        update.SeqNum,
+			LastApplied:   batch.LastApplied,
+			EncryptedBlob: update.EncryptedBlob,
+		}
+
+		applied, err := s.cfg.DB.InsertStateUpdate(&sessionUpdate)
+		failCode = stateUpdateCode(err)
+		if failCode == wtwire.CodeOK {
+			lastApplied = applied
+		}
+	}
+
+	log.Debugf("State update batch of %d updates for %s handled: "+
+		"code=%v, last_applied=%d", len(batch.Updates), id, failCode,
+		lastApplied)
+
+	if s.cfg.NoAckUpdates {
+		return &connFailure{
+			ID:   *id,
+			Code: failCode,
+		}
+	}
+
+	msg := &wtwire.StateUpdateBatchReply{
+		Code:        failCode,
+		LastApplied: lastApplied,
+	}
+
+	err = s.sendMessage(peer, msg)
+	if err != nil {
+		log.Errorf("unable to send StateUpdateBatchReply to %s", id)
+	}
 
-		failCode = wtwire.CodeOK
+	// Return the write error if the request succeeded.
+	if failCode == wtwire.CodeOK {
+		return err
+	}
+
+	// Otherwise the request failed, return a connection failure to
+	// disconnect the client.
+	return &connFailure{
+		ID:   *id,
+		Code: failCode,
+	}
+}
+
+// stateUpdateCode maps the error returned when inserting a state update to the
+// StateUpdateCode that is sent to the client.
+func stateUpdateCode(err error) wtwire.StateUpdateCode {
+	switch {
+	case err == nil:
+		return wtwire.CodeOK
 
 	// Return a permanent failure if a client tries to send an update for
 	// which we have no session.
 	case err == wtdb.ErrSessionNotFound:
-		failCode = wtwire.CodePermanentFailure
+		return wtwire.CodePermanentFailure
 
 	case err == wtdb.ErrSeqNumAlreadyApplied:
-		failCode = wtwire.CodePermanentFailure
-
 		// TODO(conner): remove session state for protocol
 		// violation. Could also double as clean up method for
 		// session-related state.
+		return wtwire.CodePermanentFailure
 
 	case err == wtdb.ErrLastAppliedReversion:
-		failCode = wtwire.StateUpdateCodeClientBehind
+		return wtwire.StateUpdateCodeClientBehind
 
 	case err == wtdb.ErrSessionConsumed:
-		failCode = wtwire.StateUpdateCodeMaxUpdatesExceeded
+		return wtwire.StateUpdateCodeMaxUpdatesExceeded
 
 	case err == wtdb.ErrUpdateOutOfOrder:
-		failCode = wtwire.StateUpdateCodeSeqNumOutOfOrder
+		return wtwire.StateUpdateCodeSeqNumOutOfOrder
 
 	default:
-		failCode = wtwire.CodeTemporaryFailure
+		return wtwire.CodeTemporaryFailure
 	}
-
-	if s.cfg.NoAckUpdates {
-		return &connFailure{
-			ID:   *id,
-			Code: failCode,
-		}
-	}
-
-	return s.replyStateUpdate(
-		peer, id, failCode, lastApplied,
-	)
 }
 
 // replyStateUpdate sends a response to a StateUpdate from a client. If the
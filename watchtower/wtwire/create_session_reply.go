@@ -29,6 +29,11 @@ const (
 	// CreateSessionCodeRejectBlobType is returned when the tower does not
 	// support the proposed blob type.
 	CreateSessionCodeRejectBlobType CreateSessionCode = 64
+
+	// CreateSessionCodeRejectUnauthorized is returned when the tower's
+	// operator didn't authorize the session, e.g. because the tower
+	// requires sessions to be paid for.
+	CreateSessionCodeRejectUnauthorized CreateSessionCode = 65
 )
 
 // MaxCreateSessionReplyDataLength is the maximum size of the Data payload
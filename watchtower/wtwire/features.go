@@ -11,6 +11,10 @@ var FeatureNames = map[lnwire.FeatureBit]string{
 	AnchorCommitOptional:     "anchor-commit",
 	TaprootCommitRequired:    "taproot-commit",
 	TaprootCommitOptional:    "taproot-commit",
+	BatchedUpdatesRequired:   "batched-updates",
+	BatchedUpdatesOptional:   "batched-updates",
+	JusticeReportsRequired:   "justice-reports",
+	JusticeReportsOptional:   "justice-reports",
 }
 
 const (
@@ -41,4 +45,22 @@ const (
 	// TaprootCommitOptional specifies that the advertising tower allows the
 	// remote party to negotiate sessions for protecting taproot channels.
 	TaprootCommitOptional lnwire.FeatureBit = 5
+
+	// BatchedUpdatesRequired specifies that the advertising node requires
+	// the remote party to understand StateUpdateBatch messages.
+	BatchedUpdatesRequired lnwire.FeatureBit = 6
+
+	// BatchedUpdatesOptional specifies that the advertising node
+	// understands StateUpdateBatch messages, allowing the remote party to
+	// send many state updates with a single round trip.
+	BatchedUpdatesOptional lnwire.FeatureBit = 7
+
+	// JusticeReportsRequired specifies that the advertising node requires
+	// the remote party to understand GetJusticeReports messages.
+	JusticeReportsRequired lnwire.FeatureBit = 8
+
+	// JusticeReportsOptional specifies that the advertising tower records
+	// the justice transactions it publishes, allowing the remote party to
+	// request them with a GetJusticeReports message.
+	JusticeReportsOptional lnwire.FeatureBit = 9
 )
@@ -174,3 +174,63 @@ func FuzzStateUpdate(f *testing.F) {
 		harness(t, data, &emptyMsg)
 	})
 }
+
+func FuzzStateUpdateBatchReply(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgStateUpdateBatchReply.
+		data = prefixWithMsgType(data, MsgStateUpdateBatchReply)
+
+		// Create an empty message so that the FuzzHarness func can
+		// check if the max payload constraint is violated.
+		emptyMsg := StateUpdateBatchReply{}
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data, &emptyMsg)
+	})
+}
+
+func FuzzStateUpdateBatch(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgStateUpdateBatch.
+		data = prefixWithMsgType(data, MsgStateUpdateBatch)
+
+		// Create an empty message so that the FuzzHarness func can
+		// check if the max payload constraint is violated.
+		emptyMsg := StateUpdateBatch{}
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data, &emptyMsg)
+	})
+}
+
+func FuzzGetJusticeReports(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgGetJusticeReports.
+		data = prefixWithMsgType(data, MsgGetJusticeReports)
+
+		// Create an empty message so that the FuzzHarness func can
+		// check if the max payload constraint is violated.
+		emptyMsg := GetJusticeReports{}
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data, &emptyMsg)
+	})
+}
+
+func FuzzJusticeReports(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data []byte) {
+		// Prefix with MsgJusticeReports.
+		data = prefixWithMsgType(data, MsgJusticeReports)
+
+		// Create an empty message so that the FuzzHarness func can
+		// check if the max payload constraint is violated.
+		emptyMsg := JusticeReports{}
+
+		// Pass the message into our general fuzz harness for wire
+		// messages!
+		harness(t, data, &emptyMsg)
+	})
+}
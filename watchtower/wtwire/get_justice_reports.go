@@ -0,0 +1,45 @@
+package wtwire
+
+import "io"
+
+// GetJusticeReports is sent from the client to the tower to request the
+// justice transactions the tower published for the session used to
+// authenticate the brontide connection. It may only be sent to towers that
+// signal the justice-reports feature bit.
+type GetJusticeReports struct{}
+
+// Compile-time constraint to ensure GetJusticeReports implements the
+// wtwire.Message interface.
+var _ Message = (*GetJusticeReports)(nil)
+
+// Decode deserializes a serialized GetJusticeReports message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the wtwire.Message interface.
+func (m *GetJusticeReports) Decode(r io.Reader, pver uint32) error {
+	return nil
+}
+
+// Encode serializes the target GetJusticeReports message into the passed
+// io.Writer observing the specified protocol version.
+//
+// This is part of the wtwire.Message interface.
+func (m *GetJusticeReports) Encode(w io.Writer, pver uint32) error {
+	return nil
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the wtwire.Message interface.
+func (m *GetJusticeReports) MsgType() MessageType {
+	return MsgGetJusticeReports
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// GetJusticeReports message observing the specified protocol version.
+//
+// This is part of the wtwire.Message interface.
+func (m *GetJusticeReports) MaxPayloadLength(uint32) uint32 {
+	return 0
+}
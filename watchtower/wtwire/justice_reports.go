@@ -0,0 +1,125 @@
+package wtwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// justiceReportSize is the number of bytes an encoded JusticeReport takes up.
+const justiceReportSize = 2 + 32 + 32
+
+// MaxJusticeReports is the maximum number of reports that fit in a single
+// JusticeReports message, next to its code and the number of reports.
+const MaxJusticeReports = (MaxMessagePayload - 2 - 2) / justiceReportSize
+
+// JusticeReport describes a justice transaction the tower published in
+// response to a breach of one of the client's channels.
+type JusticeReport struct {
+	// SeqNum is the sequence number of the state update that allowed the
+	// tower to exact justice.
+	SeqNum uint16
+
+	// BreachTxID is the txid of the revoked commitment transaction that
+	// was broadcast.
+	BreachTxID chainhash.Hash
+
+	// JusticeTxID is the txid of the justice transaction the tower
+	// published to sweep the breached outputs.
+	JusticeTxID chainhash.Hash
+}
+
+// JusticeReports is a message sent from watchtower to client in response to a
+// GetJusticeReports message.
+type JusticeReports struct {
+	// Code will be non-zero if the watchtower was unable to fetch the
+	// reports of the session.
+	Code ErrorCode
+
+	// Reports are the justice transactions the tower published for the
+	// session, in the order of their sequence numbers. At most
+	// MaxJusticeReports are returned.
+	Reports []JusticeReport
+}
+
+// A compile time check to ensure JusticeReports implements the wtwire.Message
+// interface.
+var _ Message = (*JusticeReports)(nil)
+
+// Decode deserializes a serialized JusticeReports message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the wtwire.Message interface.
+func (m *JusticeReports) Decode(r io.Reader, pver uint32) error {
+	var numReports uint16
+	err := ReadElements(r,
+		&m.Code,
+		&numReports,
+	)
+	if err != nil {
+		return err
+	}
+
+	// The reports are appended one by one rather than allocated upfront,
+	// such that a bogus count can't make us allocate more than the
+	// message actually contains.
+	m.Reports = make([]JusticeReport, 0)
+	for i := uint16(0); i < numReports; i++ {
+		var report JusticeReport
+		err := ReadElements(r,
+			&report.SeqNum,
+			&report.BreachTxID,
+			&report.JusticeTxID,
+		)
+		if err != nil {
+			return err
+		}
+
+		m.Reports = append(m.Reports, report)
+	}
+
+	return nil
+}
+
+// Encode serializes the target JusticeReports into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the wtwire.Message interface.
+func (m *JusticeReports) Encode(w io.Writer, pver uint32) error {
+	err := WriteElements(w,
+		m.Code,
+		uint16(len(m.Reports)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, report := range m.Reports {
+		err := WriteElements(w,
+			report.SeqNum,
+			report.BreachTxID,
+			report.JusticeTxID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the wtwire.Message interface.
+func (m *JusticeReports) MsgType() MessageType {
+	return MsgJusticeReports
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// JusticeReports complete message observing the specified protocol version.
+//
+// This is part of the wtwire.Message interface.
+func (m *JusticeReports) MaxPayloadLength(uint32) uint32 {
+	return MaxMessagePayload
+}
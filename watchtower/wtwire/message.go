@@ -45,6 +45,20 @@ const (
 	// MsgDeleteSessionReply identifies an encoded DeleteSessionReply
 	// message.
 	MsgDeleteSessionReply MessageType = 607
+
+	// MsgStateUpdateBatch identifies an encoded StateUpdateBatch message.
+	MsgStateUpdateBatch MessageType = 608
+
+	// MsgStateUpdateBatchReply identifies an encoded StateUpdateBatchReply
+	// message.
+	MsgStateUpdateBatchReply MessageType = 609
+
+	// MsgGetJusticeReports identifies an encoded GetJusticeReports
+	// message.
+	MsgGetJusticeReports MessageType = 610
+
+	// MsgJusticeReports identifies an encoded JusticeReports message.
+	MsgJusticeReports MessageType = 611
 )
 
 // String returns a human readable description of the message type.
@@ -64,6 +78,14 @@ func (m MessageType) String() string {
 		return "MsgDeleteSession"
 	case MsgDeleteSessionReply:
 		return "MsgDeleteSessionReply"
+	case MsgStateUpdateBatch:
+		return "MsgStateUpdateBatch"
+	case MsgStateUpdateBatchReply:
+		return "MsgStateUpdateBatchReply"
+	case MsgGetJusticeReports:
+		return "MsgGetJusticeReports"
+	case MsgJusticeReports:
+		return "MsgJusticeReports"
 	case MsgError:
 		return "Error"
 	default:
@@ -117,6 +139,14 @@ func makeEmptyMessage(msgType MessageType) (Message, error) {
 		msg = &DeleteSession{}
 	case MsgDeleteSessionReply:
 		msg = &DeleteSessionReply{}
+	case MsgStateUpdateBatch:
+		msg = &StateUpdateBatch{}
+	case MsgStateUpdateBatchReply:
+		msg = &StateUpdateBatchReply{}
+	case MsgGetJusticeReports:
+		msg = &GetJusticeReports{}
+	case MsgJusticeReports:
+		msg = &JusticeReports{}
 	case MsgError:
 		msg = &Error{}
 	default:
@@ -0,0 +1,142 @@
+package wtwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// stateUpdateBatchHeaderSize is the number of bytes an encoded
+// StateUpdateBatch takes up before its first update, i.e. its LastApplied,
+// IsComplete and the number of updates.
+const stateUpdateBatchHeaderSize = 2 + 1 + 2
+
+// BatchedStateUpdate is a single encrypted state update within a
+// StateUpdateBatch.
+type BatchedStateUpdate struct {
+	// SeqNum is the sequence number of the update within the session. The
+	// updates of a batch must carry consecutive sequence numbers.
+	SeqNum uint16
+
+	// Hint is the 16-byte prefix of the revoked commitment transaction ID
+	// for which the encrypted blob can exact justice.
+	Hint [16]byte
+
+	// EncryptedBlob is the serialized ciphertext containing all necessary
+	// information to sweep the commitment transaction corresponding to the
+	// Hint, encrypted as described for StateUpdate.
+	EncryptedBlob []byte
+}
+
+// StateUpdateBatch transmits a batch of encrypted state updates from the
+// client to the watchtower, which are acknowledged with a single
+// StateUpdateBatchReply. Batches may only be sent to towers that signal the
+// batched-updates feature bit.
+type StateUpdateBatch struct {
+	// LastApplied echos the LastApplied value returned from watchtower,
+	// allowing the tower to detect faulty clients.
+	LastApplied uint16
+
+	// IsComplete is 1 if the watchtower should close the connection after
+	// responding, and 0 otherwise.
+	IsComplete uint8
+
+	// Updates are the state updates of the batch, in the order of their
+	// sequence numbers.
+	Updates []BatchedStateUpdate
+}
+
+// A compile time check to ensure StateUpdateBatch implements the
+// wtwire.Message interface.
+var _ Message = (*StateUpdateBatch)(nil)
+
+// Decode deserializes a serialized StateUpdateBatch message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the wtwire.Message interface.
+func (m *StateUpdateBatch) Decode(r io.Reader, pver uint32) error {
+	var numUpdates uint16
+	err := ReadElements(r,
+		&m.LastApplied,
+		&m.IsComplete,
+		&numUpdates,
+	)
+	if err != nil {
+		return err
+	}
+
+	// The updates are appended one by one rather than allocated upfront,
+	// such that a bogus count can't make us allocate more than the
+	// message actually contains.
+	m.Updates = make([]BatchedStateUpdate, 0)
+	for i := uint16(0); i < numUpdates; i++ {
+		var update BatchedStateUpdate
+		err := ReadElements(r,
+			&update.SeqNum,
+			&update.Hint,
+			&update.EncryptedBlob,
+		)
+		if err != nil {
+			return err
+		}
+
+		m.Updates = append(m.Updates, update)
+	}
+
+	return nil
+}
+
+// Encode serializes the target StateUpdateBatch into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the wtwire.Message interface.
+func (m *StateUpdateBatch) Encode(w io.Writer, pver uint32) error {
+	err := WriteElements(w,
+		m.LastApplied,
+		m.IsComplete,
+		uint16(len(m.Updates)),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, update := range m.Updates {
+		err := WriteElements(w,
+			update.SeqNum,
+			update.Hint,
+			update.EncryptedBlob,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the wtwire.Message interface.
+func (m *StateUpdateBatch) MsgType() MessageType {
+	return MsgStateUpdateBatch
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// StateUpdateBatch complete message observing the specified protocol version.
+//
+// This is part of the wtwire.Message interface.
+func (m *StateUpdateBatch) MaxPayloadLength(uint32) uint32 {
+	return MaxMessagePayload
+}
+
+// MaxBatchedStateUpdates returns the maximum number of state updates carrying
+// encrypted blobs of the given size that fit in a single StateUpdateBatch.
+func MaxBatchedStateUpdates(blobSize int) int {
+	// Each update consists of its sequence number, hint and the length
+	// prefixed encrypted blob.
+	updateSize := 2 + 16 + wire.VarIntSerializeSize(uint64(blobSize)) +
+		blobSize
+
+	return (MaxMessagePayload - stateUpdateBatchHeaderSize) / updateSize
+}
@@ -0,0 +1,61 @@
+package wtwire
+
+import "io"
+
+// StateUpdateBatchReply is a message sent from watchtower to client in
+// response to a StateUpdateBatch message, and acknowledges all of its updates
+// at once.
+type StateUpdateBatchReply struct {
+	// Code will be non-zero if the watchtower rejected one of the updates
+	// of the batch. The updates are applied in order, so those preceding
+	// the rejected update were still accepted.
+	Code StateUpdateCode
+
+	// LastApplied returns the sequence number of the last accepted update
+	// known to the watchtower. If the batch was accepted, this value
+	// should be the sequence number of the last update of the batch.
+	LastApplied uint16
+}
+
+// A compile time check to ensure StateUpdateBatchReply implements the
+// wtwire.Message interface.
+var _ Message = (*StateUpdateBatchReply)(nil)
+
+// Decode deserializes a serialized StateUpdateBatchReply message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the wtwire.Message interface.
+func (t *StateUpdateBatchReply) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		&t.Code,
+		&t.LastApplied,
+	)
+}
+
+// Encode serializes the target StateUpdateBatchReply into the passed
+// io.Writer observing the protocol version specified.
+//
+// This is part of the wtwire.Message interface.
+func (t *StateUpdateBatchReply) Encode(w io.Writer, pver uint32) error {
+	return WriteElements(w,
+		t.Code,
+		t.LastApplied,
+	)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the wtwire.Message interface.
+func (t *StateUpdateBatchReply) MsgType() MessageType {
+	return MsgStateUpdateBatchReply
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// StateUpdateBatchReply complete message observing the specified protocol
+// version.
+//
+// This is part of the wtwire.Message interface.
+func (t *StateUpdateBatchReply) MaxPayloadLength(uint32) uint32 {
+	return 4
+}
@@ -0,0 +1,46 @@
+package wtwire_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMaxBatchedStateUpdates asserts that a StateUpdateBatch holding the
+// maximum number of updates can be written, and that a larger one can't.
+func TestMaxBatchedStateUpdates(t *testing.T) {
+	t.Parallel()
+
+	for _, blobSize := range []int{100, 300, 1000} {
+		maxUpdates := wtwire.MaxBatchedStateUpdates(blobSize)
+		require.Positive(t, maxUpdates)
+
+		batch := &wtwire.StateUpdateBatch{}
+		for i := 0; i <= maxUpdates; i++ {
+			batch.Updates = append(
+				batch.Updates, wtwire.BatchedStateUpdate{
+					SeqNum:        uint16(i + 1),
+					EncryptedBlob: make([]byte, blobSize),
+				},
+			)
+		}
+
+		// One update too many exceeds the maximum payload.
+		var b bytes.Buffer
+		_, err := wtwire.WriteMessage(&b, batch, 0)
+		require.Error(t, err)
+
+		// Without it, the batch is written and read back.
+		batch.Updates = batch.Updates[:maxUpdates]
+
+		b.Reset()
+		_, err = wtwire.WriteMessage(&b, batch, 0)
+		require.NoError(t, err)
+
+		msg, err := wtwire.ReadMessage(&b, 0)
+		require.NoError(t, err)
+		require.Equal(t, batch, msg)
+	}
+}
@@ -0,0 +1,205 @@
+package wtwire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// TowerAnnRecordType is the TLV type under which a node advertises its
+	// watchtower in the extra opaque data of its node announcement. The
+	// type is odd, so nodes that don't understand it ignore it.
+	TowerAnnRecordType tlv.Type = 65539
+
+	// The types of the records within a tower advertisement.
+	towerAnnChainHashType         tlv.Type = 0
+	towerAnnTowerKeyType          tlv.Type = 2
+	towerAnnAddressesType         tlv.Type = 4
+	towerAnnFeaturesType          tlv.Type = 6
+	towerAnnMaxSessionUpdatesType tlv.Type = 8
+	towerAnnMaxStorageType        tlv.Type = 10
+)
+
+// ErrIncompleteTowerAnn is returned when a tower advertisement lacks the
+// tower's key or addresses.
+var ErrIncompleteTowerAnn = errors.New("tower advertisement is missing the " +
+	"tower key or addresses")
+
+// TowerAnnouncement advertises a node's watchtower to the network, so that
+// clients can discover it without any manual configuration.
+type TowerAnnouncement struct {
+	// ChainHash is the genesis hash of the chain the tower is watching.
+	ChainHash chainhash.Hash
+
+	// TowerKey is the public key of the tower, which differs from the
+	// key of the node that advertises it.
+	TowerKey *btcec.PublicKey
+
+	// Addresses are the addresses at which clients can reach the tower.
+	Addresses []net.Addr
+
+	// Features are the features the tower signals in its Init message.
+	Features *lnwire.RawFeatureVector
+
+	// MaxSessionUpdates is the maximum number of updates the tower
+	// accepts for a single session, zero meaning no limit.
+	MaxSessionUpdates uint16
+
+	// MaxStorage is the maximum number of bytes the tower reserves for the
+	// sessions of its clients, zero meaning no limit.
+	MaxStorage uint64
+}
+
+// Encode serializes the tower advertisement as a TLV stream.
+func (a *TowerAnnouncement) Encode(w io.Writer) error {
+	if a.TowerKey == nil || len(a.Addresses) == 0 {
+		return ErrIncompleteTowerAnn
+	}
+
+	var addrs bytes.Buffer
+	if err := lnwire.WriteNetAddrs(&addrs, a.Addresses); err != nil {
+		return err
+	}
+
+	features := a.Features
+	if features == nil {
+		features = lnwire.NewRawFeatureVector()
+	}
+
+	var featureBytes bytes.Buffer
+	if err := features.Encode(&featureBytes); err != nil {
+		return err
+	}
+
+	chainHash := [32]byte(a.ChainHash)
+	addrBytes := addrs.Bytes()
+	rawFeatures := featureBytes.Bytes()
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(towerAnnChainHashType, &chainHash),
+		tlv.MakePrimitiveRecord(towerAnnTowerKeyType, &a.TowerKey),
+		tlv.MakePrimitiveRecord(towerAnnAddressesType, &addrBytes),
+		tlv.MakePrimitiveRecord(towerAnnFeaturesType, &rawFeatures),
+		tlv.MakePrimitiveRecord(
+			towerAnnMaxSessionUpdatesType, &a.MaxSessionUpdates,
+		),
+		tlv.MakePrimitiveRecord(
+			towerAnnMaxStorageType, &a.MaxStorage,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	return stream.Encode(w)
+}
+
+// Decode deserializes a tower advertisement from a TLV stream.
+func (a *TowerAnnouncement) Decode(r io.Reader) error {
+	var (
+		chainHash   [32]byte
+		towerKey    *btcec.PublicKey
+		addrBytes   []byte
+		rawFeatures []byte
+	)
+
+	stream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(towerAnnChainHashType, &chainHash),
+		tlv.MakePrimitiveRecord(towerAnnTowerKeyType, &towerKey),
+		tlv.MakePrimitiveRecord(towerAnnAddressesType, &addrBytes),
+		tlv.MakePrimitiveRecord(towerAnnFeaturesType, &rawFeatures),
+		tlv.MakePrimitiveRecord(
+			towerAnnMaxSessionUpdatesType, &a.MaxSessionUpdates,
+		),
+		tlv.MakePrimitiveRecord(
+			towerAnnMaxStorageType, &a.MaxStorage,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := stream.DecodeWithParsedTypesP2P(r); err != nil {
+		return err
+	}
+
+	var addrs []net.Addr
+	err = lnwire.ReadElement(bytes.NewReader(addrBytes), &addrs)
+	if err != nil {
+		return err
+	}
+
+	if towerKey == nil || len(addrs) == 0 {
+		return ErrIncompleteTowerAnn
+	}
+
+	features := lnwire.NewRawFeatureVector()
+	err = features.Decode(bytes.NewReader(rawFeatures))
+	if err != nil {
+		return err
+	}
+
+	a.ChainHash = chainHash
+	a.TowerKey = towerKey
+	a.Addresses = addrs
+	a.Features = features
+
+	return nil
+}
+
+// SetTowerAnn adds the given tower advertisement to the extra opaque data of
+// a node announcement, replacing any existing one. Any other records of the
+// extra opaque data are kept. If ann is nil, only the existing advertisement
+// is removed.
+func SetTowerAnn(extra *lnwire.ExtraOpaqueData, ann *TowerAnnouncement) error {
+	tlvMap, err := extra.ExtractRecords()
+	if err != nil {
+		return err
+	}
+	delete(tlvMap, TowerAnnRecordType)
+
+	if ann != nil {
+		var b bytes.Buffer
+		if err := ann.Encode(&b); err != nil {
+			return err
+		}
+
+		tlvMap[TowerAnnRecordType] = b.Bytes()
+	}
+
+	records := lnwire.TlvMapToRecords(tlvMap)
+
+	return extra.PackRecords(lnwire.RecordsAsProducers(records)...)
+}
+
+// ParseTowerAnn extracts the tower advertisement from the extra opaque data of
+// a node announcement. If the node doesn't advertise a tower, nil is returned.
+func ParseTowerAnn(extra lnwire.ExtraOpaqueData) (*TowerAnnouncement, error) {
+	if len(extra) == 0 {
+		return nil, nil
+	}
+
+	tlvMap, err := extra.ExtractRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	annBytes, ok := tlvMap[TowerAnnRecordType]
+	if !ok {
+		return nil, nil
+	}
+
+	var ann TowerAnnouncement
+	if err := ann.Decode(bytes.NewReader(annBytes)); err != nil {
+		return nil, err
+	}
+
+	return &ann, nil
+}
@@ -0,0 +1,107 @@
+package wtwire_test
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/lightningnetwork/lnd/tor"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTowerAnn returns a tower advertisement with a fresh tower key.
+func newTestTowerAnn(t *testing.T) *wtwire.TowerAnnouncement {
+	t.Helper()
+
+	priv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return &wtwire.TowerAnnouncement{
+		ChainHash: testnetChainHash,
+		TowerKey:  priv.PubKey(),
+		Addresses: []net.Addr{
+			&net.TCPAddr{IP: net.IP{1, 2, 3, 4}, Port: 9911},
+			&tor.OnionAddr{
+				OnionService: "abcdefghijklmnop.onion",
+				Port:         9911,
+			},
+		},
+		Features: lnwire.NewRawFeatureVector(
+			wtwire.AltruistSessionsOptional,
+		),
+		MaxSessionUpdates: 1024,
+		MaxStorage:        1 << 30,
+	}
+}
+
+// TestTowerAnnEncodeDecode asserts that a tower advertisement survives an
+// encoding round trip, and that incomplete advertisements are rejected.
+func TestTowerAnnEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	ann := newTestTowerAnn(t)
+
+	var b bytes.Buffer
+	require.NoError(t, ann.Encode(&b))
+
+	var decoded wtwire.TowerAnnouncement
+	require.NoError(t, decoded.Decode(&b))
+	require.Equal(t, ann, &decoded)
+
+	incomplete := *ann
+	incomplete.Addresses = nil
+	require.ErrorIs(
+		t, incomplete.Encode(&b), wtwire.ErrIncompleteTowerAnn,
+	)
+}
+
+// TestSetTowerAnn asserts that a tower advertisement can be added to, replaced
+// in and removed from the extra opaque data of a node announcement without
+// touching its other records.
+func TestSetTowerAnn(t *testing.T) {
+	t.Parallel()
+
+	// Without any extra data, there is no advertisement.
+	var extra lnwire.ExtraOpaqueData
+	ann, err := wtwire.ParseTowerAnn(extra)
+	require.NoError(t, err)
+	require.Nil(t, ann)
+
+	const otherType tlv.Type = 65541
+	otherValue := []byte{1, 2, 3}
+	records := lnwire.TlvMapToRecords(tlv.TypeMap{otherType: otherValue})
+	require.NoError(
+		t, extra.PackRecords(lnwire.RecordsAsProducers(records)...),
+	)
+
+	// The advertisement is added next to the other record.
+	ann1 := newTestTowerAnn(t)
+	require.NoError(t, wtwire.SetTowerAnn(&extra, ann1))
+
+	parsed, err := wtwire.ParseTowerAnn(extra)
+	require.NoError(t, err)
+	require.Equal(t, ann1, parsed)
+
+	// Setting another advertisement replaces the first one.
+	ann2 := newTestTowerAnn(t)
+	require.NoError(t, wtwire.SetTowerAnn(&extra, ann2))
+
+	parsed, err = wtwire.ParseTowerAnn(extra)
+	require.NoError(t, err)
+	require.Equal(t, ann2, parsed)
+
+	// Removing the advertisement keeps the other record.
+	require.NoError(t, wtwire.SetTowerAnn(&extra, nil))
+
+	parsed, err = wtwire.ParseTowerAnn(extra)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+
+	tlvMap, err := extra.ExtractRecords()
+	require.NoError(t, err)
+	require.Equal(t, tlv.TypeMap{otherType: otherValue}, tlvMap)
+}
@@ -138,6 +138,30 @@ func TestWatchtowerWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: wtwire.MsgStateUpdateBatch,
+			scenario: func(m wtwire.StateUpdateBatch) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: wtwire.MsgStateUpdateBatchReply,
+			scenario: func(m wtwire.StateUpdateBatchReply) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: wtwire.MsgGetJusticeReports,
+			scenario: func(m wtwire.GetJusticeReports) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: wtwire.MsgJusticeReports,
+			scenario: func(m wtwire.JusticeReports) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: wtwire.MsgError,
 			scenario: func(m wtwire.Error) bool {